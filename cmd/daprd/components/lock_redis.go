@@ -14,10 +14,10 @@ limitations under the License.
 package components
 
 import (
-	"github.com/dapr/components-contrib/lock/redis"
 	lockLoader "github.com/dapr/dapr/pkg/components/lock"
+	"github.com/dapr/dapr/pkg/components/lock/redis"
 )
 
 func init() {
-	lockLoader.DefaultRegistry.RegisterComponent(redis.NewStandaloneRedisLock, "redis")
+	lockLoader.DefaultRegistry.RegisterComponent(redis.NewFencingRedisLock, "redis")
 }
@@ -25,6 +25,7 @@ import (
 
 	bindingsLoader "github.com/dapr/dapr/pkg/components/bindings"
 	configurationLoader "github.com/dapr/dapr/pkg/components/configuration"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
 	lockLoader "github.com/dapr/dapr/pkg/components/lock"
 	httpMiddlewareLoader "github.com/dapr/dapr/pkg/components/middleware/http"
 	nrLoader "github.com/dapr/dapr/pkg/components/nameresolution"
@@ -33,6 +34,7 @@ import (
 	stateLoader "github.com/dapr/dapr/pkg/components/state"
 
 	"github.com/dapr/dapr/pkg/runtime"
+	"github.com/dapr/dapr/pkg/runtime/workflow"
 	"github.com/dapr/kit/logger"
 )
 
@@ -54,6 +56,7 @@ func main() {
 	stateLoader.DefaultRegistry.Logger = logContrib
 	configurationLoader.DefaultRegistry.Logger = logContrib
 	lockLoader.DefaultRegistry.Logger = logContrib
+	cryptoLoader.DefaultRegistry.Logger = logContrib
 	pubsubLoader.DefaultRegistry.Logger = logContrib
 	nrLoader.DefaultRegistry.Logger = logContrib
 	bindingsLoader.DefaultRegistry.Logger = logContrib
@@ -64,10 +67,12 @@ func main() {
 		runtime.WithStates(stateLoader.DefaultRegistry),
 		runtime.WithConfigurations(configurationLoader.DefaultRegistry),
 		runtime.WithLocks(lockLoader.DefaultRegistry),
+		runtime.WithCryptoProviders(cryptoLoader.DefaultRegistry),
 		runtime.WithPubSubs(pubsubLoader.DefaultRegistry),
 		runtime.WithNameResolutions(nrLoader.DefaultRegistry),
 		runtime.WithBindings(bindingsLoader.DefaultRegistry),
 		runtime.WithHTTPMiddlewares(httpMiddlewareLoader.DefaultRegistry),
+		runtime.WithWorkflows(workflow.DefaultRegistry),
 	)
 	if err != nil {
 		log.Fatalf("fatal error from runtime: %s", err)
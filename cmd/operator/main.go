@@ -22,6 +22,7 @@ import (
 
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/backup"
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/metrics"
 	"github.com/dapr/dapr/pkg/operator"
@@ -36,7 +37,19 @@ var (
 	certChainPath           string
 	watchInterval           string
 	maxPodRestartsPerMinute int
+	watchdogDryRun          bool
 	disableLeaderElection   bool
+	backupInterval          time.Duration
+	backupKeyFile           string
+	backupTargets           string
+	restartOnChanges        bool
+	restartWindow           string
+	gcInterval              time.Duration
+	gcSecretGracePeriod     time.Duration
+	gcDryRun                bool
+	workloadsAPIPort        int
+	sharedNamespace         string
+	additionalTypes         string
 )
 
 //nolint:gosec
@@ -52,6 +65,13 @@ const (
 
 	// defaultMaxPodRestartsPerMinute is the default value for max-pod-restarts-per-minute.
 	defaultMaxPodRestartsPerMinute = 20
+
+	// defaultBackupTargets is the default value for backup-targets: the admin APIs of the placement and Sentry services,
+	// served with mTLS on the admin ports of their services in the Helm chart.
+	defaultBackupTargets = "https://dapr-placement-server:8081,https://dapr-sentry:8081"
+
+	// defaultGCSecretGracePeriod is the default value for gc-secret-grace-period.
+	defaultGCSecretGracePeriod = 24 * time.Hour
 )
 
 func main() {
@@ -64,6 +84,12 @@ func main() {
 		WatchdogEnabled:           false,
 		WatchdogInterval:          0,
 		WatchdogMaxRestartsPerMin: maxPodRestartsPerMinute,
+		WatchdogDryRun:            watchdogDryRun,
+		GCInterval:                gcInterval,
+		GCSecretGracePeriod:       gcSecretGracePeriod,
+		GCDryRun:                  gcDryRun,
+		WorkloadsAPIPort:          workloadsAPIPort,
+		SharedComponentsNamespace: sharedNamespace,
 	}
 
 	switch strings.ToLower(watchInterval) {
@@ -83,10 +109,45 @@ func main() {
 		}
 	}
 
+	if backupInterval > 0 {
+		if backupKeyFile == "" {
+			log.Fatal("backup-key-file is required when backup-interval is set")
+		}
+		key, err := backup.LoadKey(backupKeyFile)
+		if err != nil {
+			log.Fatalf("failed to load backup key: %s", err)
+		}
+		operatorOpts.BackupInterval = backupInterval
+		operatorOpts.BackupKey = key
+		for _, target := range strings.Split(backupTargets, ",") {
+			if target = strings.TrimSpace(target); target != "" {
+				operatorOpts.BackupTargets = append(operatorOpts.BackupTargets, target)
+			}
+		}
+	}
+
+	if restartOnChanges {
+		operatorOpts.RestartEnabled = true
+		if restartWindow != "" {
+			window, err := operator.ParseMaintenanceWindow(restartWindow)
+			if err != nil {
+				log.Fatalf("invalid value for restart-maintenance-window: %s", err)
+			}
+			operatorOpts.RestartWindow = window
+		}
+	}
+
+	var additionalComponentTypes []string
+	for _, t := range strings.Split(additionalTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			additionalComponentTypes = append(additionalComponentTypes, t)
+		}
+	}
+
 	ctx := signals.Context()
 
 	go operator.NewOperator(operatorOpts).Run(ctx)
-	go operator.RunWebhooks(ctx, !disableLeaderElection)
+	go operator.RunWebhooks(ctx, !disableLeaderElection, additionalComponentTypes)
 
 	<-ctx.Done() // Wait for SIGTERM and SIGINT.
 }
@@ -112,6 +173,18 @@ func init() {
 
 	flag.StringVar(&watchInterval, "watch-interval", defaultWatchInterval, "Interval for polling pods' state, e.g. '2m'. Set to '0' to disable, or 'once' to only run once when the operator starts")
 	flag.IntVar(&maxPodRestartsPerMinute, "max-pod-restarts-per-minute", defaultMaxPodRestartsPerMinute, "Maximum number of pods in an invalid state that can be restarted per minute")
+	flag.BoolVar(&watchdogDryRun, "watchdog-dry-run", false, "Report pods in an invalid state with events and metrics, without restarting them")
+	flag.DurationVar(&backupInterval, "backup-interval", 0, "Interval for backing up the state of the placement and Sentry services in a secret, e.g. '1h'. Set to '0' to disable")
+	flag.StringVar(&backupKeyFile, "backup-key-file", "", "Path to the file with the base64 encoded key used to encrypt backups")
+	flag.StringVar(&backupTargets, "backup-targets", defaultBackupTargets, "Comma separated list of the admin API addresses of the services to back up")
+	flag.BoolVar(&restartOnChanges, "restart-on-breaking-changes", false, "Restart the deployments with the Dapr sidecar after changes of Configurations and Components that can't be hot-reloaded")
+	flag.StringVar(&restartWindow, "restart-maintenance-window", "", "Daily window in UTC in which the deployments can be restarted, e.g. '22:00-04:00'. Empty to restart them at any time")
+	flag.DurationVar(&gcInterval, "gc-interval", 0, "Interval for removing the orphaned Dapr services and the expired secrets of the control plane, e.g. '1h'. Set to '0' to disable")
+	flag.DurationVar(&gcSecretGracePeriod, "gc-secret-grace-period", defaultGCSecretGracePeriod, "Time after the expiration of their certificates before the secrets of the control plane are removed")
+	flag.BoolVar(&gcDryRun, "gc-dry-run", false, "Report the orphaned Dapr services and the expired secrets with events and metrics, without removing them")
+	flag.IntVar(&workloadsAPIPort, "workloads-api-port", 0, "HTTP port of the read-only API listing the workloads with Dapr annotations. Set to '0' to disable")
+	flag.StringVar(&sharedNamespace, "shared-components-namespace", "", "Namespace of the components shared with the sidecars of all the namespaces. Empty to only send the sidecars the components of their own namespace")
+	flag.StringVar(&additionalTypes, "additional-component-types", "", "Comma separated list of the types of the components not built into daprd, such as pluggable components, accepted by the validation webhook, e.g. 'state.my-store'")
 	flag.BoolVar(&disableLeaderElection, "disable-leader-election", false, "Disable leader election for operator")
 
 	flag.Parse()
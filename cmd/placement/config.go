@@ -15,12 +15,15 @@ package main
 
 import (
 	"flag"
+	"os"
 	"strings"
 
 	"github.com/dapr/kit/logger"
 
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/metrics"
+	"github.com/dapr/dapr/pkg/placement"
+	"github.com/dapr/dapr/pkg/placement/lease"
 	"github.com/dapr/dapr/pkg/placement/raft"
 )
 
@@ -28,17 +31,31 @@ import (
 const (
 	defaultCredentialsPath   = "/var/run/dapr/credentials"
 	defaultHealthzPort       = 8080
+	defaultAdminPort         = 8081
 	defaultPlacementPort     = 50005
 	defaultReplicationFactor = 100
+
+	// Placement state backends.
+	backendRaft  = "raft"
+	backendLease = "lease"
 )
 
 type config struct {
+	// Backend storing the placement state: raft, or lease for small Kubernetes clusters
+	backend string
+
 	// Raft protocol configurations
 	raftID           string
 	raftPeerString   string
 	raftPeers        []raft.PeerInfo
 	raftInMemEnabled bool
 	raftLogStorePath string
+	raftLogStore     string
+	raftStorage      raft.StorageOptions
+
+	// Kubernetes lease backend configurations
+	leaseNamespace  string
+	leaseMaxMembers int
 
 	// Placement server configurations
 	placementPort int
@@ -46,8 +63,18 @@ type config struct {
 	certChainPath string
 	tlsEnabled    bool
 
+	// Admin server configurations, to back up and restore the raft state
+	adminPort     int
+	backupKeyFile string
+
 	replicationFactor int
 
+	// Federation configurations, to place actors on the hosts of other clusters
+	clusterID            string
+	federationPeerString string
+	federationPeers      []string
+	federationLocality   string
+
 	// Log and metrics configurations
 	loggerOptions   logger.Options
 	metricsExporter metrics.Exporter
@@ -56,27 +83,49 @@ type config struct {
 func newConfig() *config {
 	// Default configuration
 	cfg := config{
+		backend: backendRaft,
+
 		raftID:           "dapr-placement-0",
 		raftPeerString:   "dapr-placement-0=127.0.0.1:8201",
 		raftPeers:        []raft.PeerInfo{},
 		raftInMemEnabled: true,
 		raftLogStorePath: "",
+		raftStorage:      raft.DefaultStorageOptions(""),
+
+		leaseNamespace:  os.Getenv("NAMESPACE"),
+		leaseMaxMembers: lease.DefaultMaxMembers,
 
 		placementPort: defaultPlacementPort,
 		healthzPort:   defaultHealthzPort,
 		certChainPath: defaultCredentialsPath,
 		tlsEnabled:    false,
+		adminPort:     defaultAdminPort,
+
+		federationLocality: placement.FederationLocalityAny,
 	}
 
+	flag.StringVar(&cfg.backend, "backend", cfg.backend, "Backend storing the placement state: 'raft', or 'lease' to elect the leader with a Kubernetes Lease and store the state in a ConfigMap, for small clusters")
 	flag.StringVar(&cfg.raftID, "id", cfg.raftID, "Placement server ID.")
 	flag.StringVar(&cfg.raftPeerString, "initial-cluster", cfg.raftPeerString, "raft cluster peers")
 	flag.BoolVar(&cfg.raftInMemEnabled, "inmem-store-enabled", cfg.raftInMemEnabled, "Enable in-memory log and snapshot store unless --raft-logstore-path is set")
 	flag.StringVar(&cfg.raftLogStorePath, "raft-logstore-path", cfg.raftLogStorePath, "raft log store path.")
+	flag.StringVar(&cfg.raftLogStore, "raft-log-store", cfg.raftLogStore, "Store of the raft logs: 'inmem', or 'boltdb' to store them on disk. If empty, 'inmem' unless --inmem-store-enabled is false or --raft-logstore-path is set")
+	flag.IntVar(&cfg.raftStorage.SnapshotsRetained, "raft-snapshots-retained", cfg.raftStorage.SnapshotsRetained, "Number of raft snapshots kept on disk")
+	flag.DurationVar(&cfg.raftStorage.SnapshotInterval, "raft-snapshot-interval", cfg.raftStorage.SnapshotInterval, "Interval of the checks for the raft snapshots")
+	flag.Uint64Var(&cfg.raftStorage.SnapshotThreshold, "raft-snapshot-threshold", cfg.raftStorage.SnapshotThreshold, "Number of raft logs written since the last snapshot for a new snapshot to be taken")
+	flag.Uint64Var(&cfg.raftStorage.TrailingLogs, "raft-trailing-logs", cfg.raftStorage.TrailingLogs, "Number of raft logs kept after a snapshot, so that the followers can catch up without the snapshot")
+	flag.StringVar(&cfg.leaseNamespace, "lease-namespace", cfg.leaseNamespace, "Namespace of the Lease and of the ConfigMap used by the lease backend")
+	flag.IntVar(&cfg.leaseMaxMembers, "lease-max-members", cfg.leaseMaxMembers, "Maximum number of actor hosts in the placement table with the lease backend")
 	flag.IntVar(&cfg.placementPort, "port", cfg.placementPort, "sets the gRPC port for the placement service")
 	flag.IntVar(&cfg.healthzPort, "healthz-port", cfg.healthzPort, "sets the HTTP port for the healthz server")
 	flag.StringVar(&cfg.certChainPath, "certchain", cfg.certChainPath, "Path to the credentials directory holding the cert chain")
 	flag.BoolVar(&cfg.tlsEnabled, "tls-enabled", cfg.tlsEnabled, "Should TLS be enabled for the placement gRPC server")
+	flag.IntVar(&cfg.adminPort, "admin-port", cfg.adminPort, "sets the HTTPS port for the backup and restore admin APIs, served with mTLS")
+	flag.StringVar(&cfg.backupKeyFile, "backup-key-file", cfg.backupKeyFile, "Path to the file with the base64 encoded key used to encrypt backups. The admin APIs are disabled if it is not set")
 	flag.IntVar(&cfg.replicationFactor, "replicationFactor", defaultReplicationFactor, "sets the replication factor for actor distribution on vnodes")
+	flag.StringVar(&cfg.clusterID, "cluster-id", cfg.clusterID, "ID of the cluster of the placement service, required for the federation")
+	flag.StringVar(&cfg.federationPeerString, "federation-peers", cfg.federationPeerString, "Comma separated addresses of the placement services of the federated clusters. The federation is disabled if empty")
+	flag.StringVar(&cfg.federationLocality, "federation-locality", cfg.federationLocality, "Routing preference for the hosts of the federated clusters: 'any', or 'local' to prefer the hosts of the local cluster. The placement tables always include the hosts of all the clusters")
 
 	flag.StringVar(&credentials.RootCertFilename, "issuer-ca-filename", credentials.RootCertFilename, "Certificate Authority certificate filename")
 	flag.StringVar(&credentials.IssuerCertFilename, "issuer-certificate-filename", credentials.IssuerCertFilename, "Issuer certificate filename")
@@ -91,9 +140,11 @@ func newConfig() *config {
 	flag.Parse()
 
 	cfg.raftPeers = parsePeersFromFlag(cfg.raftPeerString)
+	cfg.federationPeers = parseFederationPeersFromFlag(cfg.federationPeerString)
 	if cfg.raftLogStorePath != "" {
 		cfg.raftInMemEnabled = false
 	}
+	cfg.raftStorage.LogStore = raftLogStore(cfg.raftLogStore, cfg.raftInMemEnabled)
 
 	return &cfg
 }
@@ -116,3 +167,24 @@ func parsePeersFromFlag(val string) []raft.PeerInfo {
 
 	return peers
 }
+
+func parseFederationPeersFromFlag(val string) []string {
+	peers := []string{}
+	for _, addr := range strings.Split(val, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			peers = append(peers, addr)
+		}
+	}
+	return peers
+}
+
+// raftLogStore returns the store of the raft logs, which defaults to the in-memory store when it's enabled.
+func raftLogStore(logStore string, inMemEnabled bool) string {
+	if logStore != "" {
+		return logStore
+	}
+	if inMemEnabled {
+		return raft.LogStoreInMem
+	}
+	return raft.LogStoreBoltDB
+}
@@ -54,3 +54,14 @@ func TestParsePeersFromFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFederationPeersFromFlag(t *testing.T) {
+	assert.Equal(t, []string{}, parseFederationPeersFromFlag(""))
+	assert.Equal(t, []string{"placement.west:50005", "placement.east:50005"}, parseFederationPeersFromFlag("placement.west:50005, placement.east:50005,"))
+}
+
+func TestRaftLogStore(t *testing.T) {
+	assert.Equal(t, raft.LogStoreInMem, raftLogStore("", true))
+	assert.Equal(t, raft.LogStoreBoltDB, raftLogStore("", false))
+	assert.Equal(t, raft.LogStoreBoltDB, raftLogStore(raft.LogStoreBoltDB, true))
+}
@@ -23,14 +23,17 @@ import (
 
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/backup"
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/fswatcher"
 	"github.com/dapr/dapr/pkg/health"
 	"github.com/dapr/dapr/pkg/placement"
 	"github.com/dapr/dapr/pkg/placement/hashing"
+	"github.com/dapr/dapr/pkg/placement/lease"
 	"github.com/dapr/dapr/pkg/placement/monitoring"
 	"github.com/dapr/dapr/pkg/placement/raft"
 	"github.com/dapr/dapr/pkg/version"
+	"github.com/dapr/dapr/utils"
 )
 
 var log = logger.NewLogger("dapr.placement")
@@ -57,31 +60,84 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Start Raft cluster.
-	raftServer := raft.New(cfg.raftID, cfg.raftInMemEnabled, cfg.raftPeers, cfg.raftLogStorePath)
-	if raftServer == nil {
-		log.Fatal("failed to create raft server.")
-	}
+	// Start the backend storing the placement state.
+	var (
+		backend         placement.Backend
+		shutdownBackend func()
+	)
+	switch cfg.backend {
+	case backendRaft:
+		raftServer := raft.NewWithStorage(cfg.raftID, cfg.raftPeers, cfg.raftLogStorePath, cfg.raftStorage)
+		if raftServer == nil {
+			log.Fatal("failed to create raft server.")
+		}
+
+		if err := raftServer.StartRaft(nil); err != nil {
+			log.Fatalf("failed to start Raft Server: %v", err)
+		}
+		backend, shutdownBackend = raftServer, raftServer.Shutdown
+
+	case backendLease:
+		leaseServer := lease.New(utils.GetKubeClient(), lease.Options{
+			Identity:   cfg.raftID,
+			Namespace:  cfg.leaseNamespace,
+			MaxMembers: cfg.leaseMaxMembers,
+		})
+		if err := leaseServer.Start(); err != nil {
+			log.Fatalf("failed to start the lease backend: %v", err)
+		}
+		backend, shutdownBackend = leaseServer, leaseServer.Shutdown
 
-	if err := raftServer.StartRaft(nil); err != nil {
-		log.Fatalf("failed to start Raft Server: %v", err)
+	default:
+		log.Fatalf("invalid placement backend: %s", cfg.backend)
 	}
 
 	// Start Placement gRPC server.
 	hashing.SetReplicationFactor(cfg.replicationFactor)
-	apiServer := placement.NewPlacementService(raftServer)
-	var certChain *credentials.CertChain
+	apiServer := placement.NewPlacementService(backend)
+	var (
+		certChain *credentials.CertChain
+		certs     *credentials.CertChainReloader
+	)
 	if cfg.tlsEnabled {
 		certChain = loadCertChains(cfg.certChainPath)
+		var err error
+		certs, err = credentials.NewCertChainReloader(credentials.NewTLSCredentials(cfg.certChainPath), certChain)
+		if err != nil {
+			log.Fatalf("failed to load tls certificates: %s", err)
+		}
+		// Reload the certs when they are rotated
+		go func() {
+			if err := certs.Watch(context.Background()); err != nil {
+				log.Errorf("error watching tls certificates, rotated certificates will not be reloaded: %s", err)
+			}
+		}()
+	}
+
+	if len(cfg.federationPeers) > 0 {
+		err := apiServer.EnableFederation(placement.FederationOptions{
+			ClusterID: cfg.clusterID,
+			Peers:     cfg.federationPeers,
+			Locality:  cfg.federationLocality,
+			CertChain: certChain,
+		})
+		if err != nil {
+			log.Fatalf("failed to enable the federation: %s", err)
+		}
 	}
 
 	go apiServer.MonitorLeadership()
-	go apiServer.Run(strconv.Itoa(cfg.placementPort), certChain)
+	go apiServer.Run(strconv.Itoa(cfg.placementPort), certs)
 	log.Infof("placement service started on port %d", cfg.placementPort)
 
 	// Start Healthz endpoint.
 	go startHealthzServer(cfg.healthzPort)
 
+	// Start the backup and restore admin APIs.
+	if cfg.backupKeyFile != "" {
+		go startAdminServer(cfg.adminPort, cfg.backupKeyFile, backend, certs)
+	}
+
 	// Relay incoming process signal to exit placement gracefully
 	signalCh := make(chan os.Signal, 10)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
@@ -93,7 +149,7 @@ func main() {
 	gracefulExitCh := make(chan struct{})
 	go func() {
 		apiServer.Shutdown()
-		raftServer.Shutdown()
+		shutdownBackend()
 		close(gracefulExitCh)
 	}()
 
@@ -117,6 +173,18 @@ func startHealthzServer(healthzPort int) {
 	}
 }
 
+func startAdminServer(adminPort int, keyFile string, backend placement.Backend, certs *credentials.CertChainReloader) {
+	key, err := backup.LoadKey(keyFile)
+	if err != nil {
+		log.Fatalf("failed to load backup key: %s", err)
+	}
+
+	adminServer := backup.NewServer(placement.NewBackupSource(backend), key, log)
+	if err := adminServer.Run(context.Background(), adminPort, certs); err != nil {
+		log.Fatalf("failed to start admin server: %s", err)
+	}
+}
+
 func loadCertChains(certChainPath string) *credentials.CertChain {
 	tlsCreds := credentials.NewTLSCredentials(certChainPath)
 
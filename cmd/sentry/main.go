@@ -23,11 +23,13 @@ import (
 
 	"k8s.io/client-go/util/homedir"
 
+	"github.com/dapr/dapr/pkg/backup"
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/fswatcher"
 	"github.com/dapr/dapr/pkg/health"
 	"github.com/dapr/dapr/pkg/metrics"
 	"github.com/dapr/dapr/pkg/sentry"
+	sentryCA "github.com/dapr/dapr/pkg/sentry/ca"
 	"github.com/dapr/dapr/pkg/sentry/config"
 	"github.com/dapr/dapr/pkg/sentry/monitoring"
 	"github.com/dapr/dapr/pkg/signals"
@@ -45,6 +47,7 @@ const (
 	defaultDaprSystemConfigName = "daprsystem"
 
 	healthzPort = 8080
+	adminPort   = 8081
 )
 
 func main() {
@@ -54,6 +57,9 @@ func main() {
 	flag.StringVar(&credentials.IssuerCertFilename, "issuer-certificate-filename", credentials.IssuerCertFilename, "Issuer certificate filename")
 	flag.StringVar(&credentials.IssuerKeyFilename, "issuer-key-filename", credentials.IssuerKeyFilename, "Issuer private key filename")
 	trustDomain := flag.String("trust-domain", "localhost", "The CA trust domain")
+	backupKeyFile := flag.String("backup-key-file", "", "Path to the file with the base64 encoded key used to encrypt backups. The admin APIs are disabled if it is not set")
+	certExpiryWarningDays := flag.Int("cert-expiry-warning-days", int(config.DefaultCertExpiryWarningThreshold.Hours()/24), "Number of days before the expiration of the root or issuer certificate when warnings start being emitted")
+	rootRotationWindow := flag.Duration("root-rotation-window", config.DefaultRootRotationWindow, "Time during which the previous root certificate is still trusted after the root certificate changes. Set to 0 to stop trusting it immediately")
 
 	loggerOptions := logger.DefaultOptions()
 	loggerOptions.AttachCmdFlags(flag.StringVar, flag.BoolVar)
@@ -107,6 +113,10 @@ func main() {
 	config.IssuerKeyPath = issuerKeyPath
 	config.RootCertPath = rootCertPath
 	config.TrustDomain = *trustDomain
+	if *certExpiryWarningDays > 0 {
+		config.CertExpiryWarningThreshold = time.Duration(*certExpiryWarningDays) * 24 * time.Hour
+	}
+	config.RootRotationWindow = *rootRotationWindow
 
 	watchDir := filepath.Dir(config.IssuerCertPath)
 
@@ -155,6 +165,36 @@ func main() {
 		log.Fatalf("failed to restart sentry server: %s", err)
 	}
 
+	// Start the backup and restore admin APIs in background, once the issuer credentials exist
+	if *backupKeyFile != "" {
+		key, keyErr := backup.LoadKey(*backupKeyFile)
+		if keyErr != nil {
+			log.Fatalf("failed to load backup key: %s", keyErr)
+		}
+		// The admin APIs are served with mTLS with the issuer credentials, like the other control plane services
+		issuerCreds := credentials.NewTLSCredentials(*credsPath)
+		chain, chainErr := credentials.LoadFromDisk(rootCertPath, issuerCertPath, issuerKeyPath)
+		if chainErr != nil {
+			log.Fatalf("failed to load the issuer credentials for the admin server: %s", chainErr)
+		}
+		certs, certsErr := credentials.NewCertChainReloader(issuerCreds, chain)
+		if certsErr != nil {
+			log.Fatalf("failed to load the issuer credentials for the admin server: %s", certsErr)
+		}
+
+		go func() {
+			if innerErr := certs.Watch(runCtx); innerErr != nil {
+				log.Errorf("error watching the issuer credentials, rotated certificates will not be reloaded by the admin server: %s", innerErr)
+			}
+		}()
+		go func() {
+			adminServer := backup.NewServer(sentryCA.NewBackupSource(config), key, log)
+			if innerErr := adminServer.Run(runCtx, adminPort, certs); innerErr != nil {
+				log.Fatalf("failed to start admin server: %s", innerErr)
+			}
+		}()
+	}
+
 	// Watch for changes in the watchDir
 	// This also blocks until runCtx is canceled
 	fswatcher.Watch(runCtx, watchDir, issuerEvent)
@@ -164,6 +164,7 @@ func parseSpiffeID(spiffeID string) (*config.SpiffeID, error) {
 	}
 
 	// The SPIFFE Id will be of the format: spiffe://<trust-domain/ns/<namespace>/<app-id>
+	// Sentry only accepts the SPIFFE ID templates that keep the namespace and the app id at these positions.
 	parts := strings.Split(spiffeID, "/")
 	if len(parts) < 6 {
 		return nil, fmt.Errorf("input spiffe id: %s is invalid", spiffeID)
@@ -350,6 +351,7 @@ func IsOperationAllowedByAccessControlPolicy(spiffeID *config.SpiffeID, srcAppID
 
 	if operationPolicy != nil {
 		// Operation prefix and postfix match. Now check the operation specific policy
+		// Without an HTTP verb, the default action of the app or the global one applies.
 		if appProtocol == config.HTTPProtocol {
 			if httpVerb != commonv1pb.HTTPExtension_NONE {
 				verbAction, found := operationPolicy.VerbAction[httpVerb.String()]
@@ -363,9 +365,6 @@ func IsOperationAllowedByAccessControlPolicy(spiffeID *config.SpiffeID, srcAppID
 						action = verbAction
 					}
 				}
-			} else {
-				// No matching verb found in the operation specific policies.
-				action = appPolicy.DefaultAction
 			}
 		} else if appProtocol == config.GRPCProtocol {
 			// No http verb match is needed.
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/proto/common/v1"
@@ -380,6 +381,15 @@ func TestSpiffeID(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("test parse spiffe id from a custom template", func(t *testing.T) {
+		spiffeID := "spiffe://mydomain/cluster-a/mynamespace/myappid/dapr"
+		id, err := parseSpiffeID(spiffeID)
+		assert.Equal(t, "mydomain", id.TrustDomain)
+		assert.Equal(t, "mynamespace", id.Namespace)
+		assert.Equal(t, "myappid", id.AppID)
+		assert.Nil(t, err)
+	})
+
 	t.Run("test parse invalid spiffe id", func(t *testing.T) {
 		spiffeID := "abcd"
 		_, err := parseSpiffeID(spiffeID)
@@ -571,6 +581,37 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 		assert.False(t, isAllowed)
 	})
 
+	t.Run("test when http verb is not specified and the app has no default action", func(t *testing.T) {
+		accessControlList, err := ParseAccessControlSpec(config.AccessControlSpec{
+			DefaultAction: config.AllowAccess,
+			TrustDomain:   "public",
+			AppPolicies: []config.AppPolicySpec{
+				{
+					AppName:     app1,
+					TrustDomain: "public",
+					Namespace:   "ns1",
+					AppOperationActions: []config.AppOperation{
+						{
+							Action:    config.DenyAccess,
+							HTTPVerb:  []string{"POST"},
+							Operation: "/op1",
+						},
+					},
+				},
+			},
+		}, config.HTTPProtocol)
+		require.NoError(t, err)
+		spiffeID := config.SpiffeID{
+			TrustDomain: "public",
+			Namespace:   "ns1",
+			AppID:       app1,
+		}
+		isAllowed, actionPolicy := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "op1", common.HTTPExtension_NONE, config.HTTPProtocol, accessControlList)
+		// Action = Global default action
+		assert.True(t, isAllowed)
+		assert.Equal(t, config.ActionPolicyGlobal, actionPolicy)
+	})
+
 	t.Run("test when matching operation post fix is specified in policy spec", func(t *testing.T) {
 		srcAppID := app2
 		accessControlList, _ := initializeAccessControlList(config.HTTPProtocol)
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dapr/dapr/pkg/config"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+)
+
+// crossNamespaceAllApps is the app ID of the rules allowing all the apps of a namespace.
+const crossNamespaceAllApps = "*"
+
+// CrossNamespacePolicy is an in-memory copy of the cross-namespace invocation policy for fast lookup.
+type CrossNamespacePolicy struct {
+	namespace   string
+	trustDomain string
+	// The callees allowed for each caller. The key is appID||namespace||trustDomain.
+	allowed map[string]crossNamespaceCallees
+}
+
+type crossNamespaceCallees struct {
+	all     bool
+	callees map[string]struct{}
+}
+
+// ParseCrossNamespaceSpec creates an in-memory copy of the cross-namespace invocation policy of the apps of a namespace
+// in the trust domain. It returns nil if no policy is specified, in which case the calls from other namespaces are not
+// restricted.
+func ParseCrossNamespaceSpec(spec *config.CrossNamespaceSpec, namespace, trustDomain string) (*CrossNamespacePolicy, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	policy := &CrossNamespacePolicy{
+		namespace:   namespace,
+		trustDomain: trustDomain,
+		allowed:     make(map[string]crossNamespaceCallees, len(spec.AllowedCallers)),
+	}
+	for _, caller := range spec.AllowedCallers {
+		if caller.Namespace == "" {
+			return nil, errors.New("invalid cross-namespace policy: missing namespace of an allowed caller")
+		}
+		appID := caller.AppID
+		if appID == "" {
+			appID = crossNamespaceAllApps
+		}
+
+		key := getKeyForCrossNamespaceCaller(appID, caller.Namespace, caller.TrustDomain)
+		allowed := policy.allowed[key]
+		if len(caller.Callees) == 0 {
+			allowed.all = true
+		}
+		for _, callee := range caller.Callees {
+			if allowed.callees == nil {
+				allowed.callees = make(map[string]struct{}, len(caller.Callees))
+			}
+			allowed.callees[callee] = struct{}{}
+		}
+		policy.allowed[key] = allowed
+	}
+	return policy, nil
+}
+
+// IsCallAllowed returns true if the caller can invoke the callee app.
+// The calls from the same namespace of the same trust domain are always allowed, while the calls from the namespace of
+// the same name in another trust domain, such as another cluster, must be allowed by the rules.
+func (p *CrossNamespacePolicy) IsCallAllowed(caller *config.SpiffeID, calleeAppID string) bool {
+	if caller == nil {
+		return false
+	}
+	if p.isLocal(caller) {
+		return true
+	}
+
+	for _, appID := range []string{caller.AppID, crossNamespaceAllApps} {
+		// The rules without a trust domain allow the callers of all the trust domains.
+		for _, trustDomain := range []string{caller.TrustDomain, ""} {
+			allowed, ok := p.allowed[getKeyForCrossNamespaceCaller(appID, caller.Namespace, trustDomain)]
+			if !ok {
+				continue
+			}
+			if allowed.all {
+				return true
+			}
+			if _, ok = allowed.callees[calleeAppID]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ApplyCrossNamespacePolicy checks that the caller identified by its certificate can invoke the callee app.
+// The caller must be identified, so the calls are denied when mTLS is disabled.
+func ApplyCrossNamespacePolicy(ctx context.Context, calleeAppID string, policy *CrossNamespacePolicy) (bool, string) {
+	if policy == nil {
+		return true, ""
+	}
+
+	spiffeID, err := GetAndParseSpiffeID(ctx)
+	if err != nil {
+		errMessage := fmt.Sprintf("cross-namespace policy has denied access: the caller could not be identified: %s", err)
+		log.Debugf(errMessage)
+		return false, errMessage
+	}
+
+	allowed := policy.IsCallAllowed(spiffeID, calleeAppID)
+	if !policy.isLocal(spiffeID) {
+		diag.DefaultMonitoring.CrossBoundaryRequestReceived(spiffeID.TrustDomain, spiffeID.Namespace, allowed)
+	}
+	if !allowed {
+		errMessage := fmt.Sprintf("cross-namespace policy has denied access to appid: %s from appid: %s namespace: %s trust domain: %s", calleeAppID, spiffeID.AppID, spiffeID.Namespace, spiffeID.TrustDomain)
+		log.Debugf(errMessage)
+		return false, errMessage
+	}
+	return true, ""
+}
+
+func (p *CrossNamespacePolicy) isLocal(caller *config.SpiffeID) bool {
+	return caller.Namespace == p.namespace && caller.TrustDomain == p.trustDomain
+}
+
+func getKeyForCrossNamespaceCaller(appID, namespace, trustDomain string) string {
+	return getKeyForAppID(appID, namespace) + "||" + trustDomain
+}
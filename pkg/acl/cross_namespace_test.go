@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestCrossNamespacePolicy(t *testing.T) {
+	t.Run("no policy", func(t *testing.T) {
+		policy, err := ParseCrossNamespaceSpec(nil, "payments", "public")
+		require.NoError(t, err)
+		assert.Nil(t, policy)
+
+		allowed, _ := ApplyCrossNamespacePolicy(context.Background(), app1, policy)
+		assert.True(t, allowed)
+	})
+
+	t.Run("missing namespace", func(t *testing.T) {
+		_, err := ParseCrossNamespaceSpec(&config.CrossNamespaceSpec{
+			AllowedCallers: []config.CrossNamespaceCaller{{AppID: app1}},
+		}, "payments", "public")
+		assert.Error(t, err)
+	})
+
+	policy, err := ParseCrossNamespaceSpec(&config.CrossNamespaceSpec{
+		AllowedCallers: []config.CrossNamespaceCaller{
+			{Namespace: "frontend", AppID: "web", Callees: []string{app1}},
+			{Namespace: "frontend", AppID: "web", Callees: []string{app2}},
+			{Namespace: "monitoring"},
+			{Namespace: "batch", TrustDomain: "us-west"},
+			{Namespace: "payments", TrustDomain: "eu-west", AppID: "settlement"},
+		},
+	}, "payments", "public")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name    string
+		caller  *config.SpiffeID
+		callee  string
+		allowed bool
+	}{{
+		name:    "same namespace",
+		caller:  &config.SpiffeID{TrustDomain: "public", Namespace: "payments", AppID: app3},
+		callee:  app1,
+		allowed: true,
+	}, {
+		name:    "same namespace of another trust domain",
+		caller:  &config.SpiffeID{TrustDomain: "us-west", Namespace: "payments", AppID: app3},
+		callee:  app1,
+		allowed: false,
+	}, {
+		name:    "allowed caller of the same namespace of another trust domain",
+		caller:  &config.SpiffeID{TrustDomain: "eu-west", Namespace: "payments", AppID: "settlement"},
+		callee:  app1,
+		allowed: true,
+	}, {
+		name:    "allowed callee",
+		caller:  &config.SpiffeID{Namespace: "frontend", AppID: "web"},
+		callee:  app2,
+		allowed: true,
+	}, {
+		name:    "callee not allowed",
+		caller:  &config.SpiffeID{Namespace: "frontend", AppID: "web"},
+		callee:  app3,
+		allowed: false,
+	}, {
+		name:    "caller not allowed",
+		caller:  &config.SpiffeID{Namespace: "frontend", AppID: "admin"},
+		callee:  app1,
+		allowed: false,
+	}, {
+		name:    "all the apps of the namespace",
+		caller:  &config.SpiffeID{Namespace: "monitoring", AppID: "prober"},
+		callee:  app3,
+		allowed: true,
+	}, {
+		name:    "unknown namespace",
+		caller:  &config.SpiffeID{Namespace: "sandbox", AppID: "web"},
+		callee:  app1,
+		allowed: false,
+	}, {
+		name:    "any trust domain",
+		caller:  &config.SpiffeID{TrustDomain: "us-west", Namespace: "monitoring", AppID: "prober"},
+		callee:  app3,
+		allowed: true,
+	}, {
+		name:    "allowed trust domain",
+		caller:  &config.SpiffeID{TrustDomain: "us-west", Namespace: "batch", AppID: "job"},
+		callee:  app1,
+		allowed: true,
+	}, {
+		name:    "trust domain not allowed",
+		caller:  &config.SpiffeID{TrustDomain: "public", Namespace: "batch", AppID: "job"},
+		callee:  app1,
+		allowed: false,
+	}, {
+		name:    "unidentified caller",
+		callee:  app1,
+		allowed: false,
+	}}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.allowed, policy.IsCallAllowed(tc.caller, tc.callee))
+		})
+	}
+
+	t.Run("calls without a certificate are denied", func(t *testing.T) {
+		allowed, msg := ApplyCrossNamespacePolicy(context.Background(), app1, policy)
+		assert.False(t, allowed)
+		assert.Contains(t, msg, "the caller could not be identified")
+	})
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acl
+
+import (
+	"errors"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+// OutboundPolicy is an in-memory copy of the policy of the invocations to other namespaces and clusters for fast lookup.
+type OutboundPolicy struct {
+	namespace string
+	// The allowed callees. The key is appID||namespace||cluster.
+	allowed map[string]struct{}
+}
+
+// ParseOutboundSpec creates an in-memory copy of the outbound invocation policy of the apps of a namespace.
+// It returns nil if no policy is specified, in which case the calls to other namespaces and clusters are not restricted.
+func ParseOutboundSpec(spec *config.OutboundSpec, namespace string) (*OutboundPolicy, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	policy := &OutboundPolicy{
+		namespace: namespace,
+		allowed:   make(map[string]struct{}, len(spec.AllowedCallees)),
+	}
+	for _, callee := range spec.AllowedCallees {
+		if callee.Namespace == "" {
+			return nil, errors.New("invalid outbound policy: missing namespace of an allowed callee")
+		}
+		appID := callee.AppID
+		if appID == "" {
+			appID = crossNamespaceAllApps
+		}
+		policy.allowed[getKeyForOutboundCallee(appID, callee.Namespace, callee.Cluster)] = struct{}{}
+	}
+	return policy, nil
+}
+
+// IsCallAllowed returns true if the app of the namespace and cluster can be invoked.
+// The cluster is empty for the local cluster, whose apps of the same namespace can always be invoked.
+func (p *OutboundPolicy) IsCallAllowed(appID, namespace, cluster string) bool {
+	if namespace == p.namespace && cluster == "" {
+		return true
+	}
+
+	for _, id := range []string{appID, crossNamespaceAllApps} {
+		if _, ok := p.allowed[getKeyForOutboundCallee(id, namespace, cluster)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func getKeyForOutboundCallee(appID, namespace, cluster string) string {
+	return getKeyForAppID(appID, namespace) + "||" + cluster
+}
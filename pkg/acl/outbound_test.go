@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestOutboundPolicy(t *testing.T) {
+	t.Run("no policy", func(t *testing.T) {
+		policy, err := ParseOutboundSpec(nil, "frontend")
+		require.NoError(t, err)
+		assert.Nil(t, policy)
+	})
+
+	t.Run("missing namespace", func(t *testing.T) {
+		_, err := ParseOutboundSpec(&config.OutboundSpec{
+			AllowedCallees: []config.OutboundCallee{{AppID: app1}},
+		}, "frontend")
+		assert.Error(t, err)
+	})
+
+	policy, err := ParseOutboundSpec(&config.OutboundSpec{
+		AllowedCallees: []config.OutboundCallee{
+			{Namespace: "payments", AppID: app1},
+			{Namespace: "monitoring"},
+			{Namespace: "frontend", Cluster: "us-west"},
+		},
+	}, "frontend")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name      string
+		appID     string
+		namespace string
+		cluster   string
+		allowed   bool
+	}{{
+		name:      "same namespace",
+		appID:     app3,
+		namespace: "frontend",
+		allowed:   true,
+	}, {
+		name:      "allowed callee",
+		appID:     app1,
+		namespace: "payments",
+		allowed:   true,
+	}, {
+		name:      "callee not allowed",
+		appID:     app2,
+		namespace: "payments",
+		allowed:   false,
+	}, {
+		name:      "all the apps of the namespace",
+		appID:     app2,
+		namespace: "monitoring",
+		allowed:   true,
+	}, {
+		name:      "allowed cluster",
+		appID:     app2,
+		namespace: "frontend",
+		cluster:   "us-west",
+		allowed:   true,
+	}, {
+		name:      "namespace not allowed in the cluster",
+		appID:     app1,
+		namespace: "payments",
+		cluster:   "us-west",
+		allowed:   false,
+	}, {
+		name:      "unknown cluster",
+		appID:     app1,
+		namespace: "frontend",
+		cluster:   "eu-central",
+		allowed:   false,
+	}}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.allowed, policy.IsCallAllowed(tc.appID, tc.namespace, tc.cluster))
+		})
+	}
+}
@@ -18,9 +18,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"math"
 	nethttp "net/http"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -49,6 +51,7 @@ import (
 	"github.com/dapr/dapr/pkg/health"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	"github.com/dapr/dapr/pkg/modes"
+	"github.com/dapr/dapr/pkg/placement/hashing"
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	"github.com/dapr/dapr/pkg/resiliency"
@@ -72,6 +75,7 @@ type Actors interface {
 	Stop()
 	GetState(ctx context.Context, req *GetStateRequest) (*StateResponse, error)
 	TransactionalStateOperation(ctx context.Context, req *TransactionalRequest) error
+	MultiActorTransactionalStateOperation(ctx context.Context, req *MultiActorTransactionalRequest) error
 	GetReminder(ctx context.Context, req *GetReminderRequest) (*Reminder, error)
 	CreateReminder(ctx context.Context, req *CreateReminderRequest) error
 	DeleteReminder(ctx context.Context, req *DeleteReminderRequest) error
@@ -80,6 +84,8 @@ type Actors interface {
 	DeleteTimer(ctx context.Context, req *DeleteTimerRequest) error
 	IsActorHosted(ctx context.Context, req *ActorHostedRequest) bool
 	GetActiveActorsCount(ctx context.Context) []ActiveActorsCount
+	SimulatePlacementChange(ctx context.Context, req *PlacementSimulationRequest) []PlacementSimulation
+	GetPlacementStatus(ctx context.Context) *PlacementStatus
 }
 
 type actorsRuntime struct {
@@ -139,6 +145,9 @@ const (
 
 var ErrDaprResponseHeader = errors.New("error indicated via actor header response")
 
+// ErrActorsNotColocated is returned for a multi-actor transaction whose actors aren't all hosted by this host.
+var ErrActorsNotColocated = errors.New("actors of the transaction are not hosted by the same host")
+
 // NewActors create a new actors runtime with given config.
 func NewActors(
 	stateStore state.Store,
@@ -190,6 +199,14 @@ func (a *actorsRuntime) Init() error {
 		return errors.New("actors: couldn't connect to placement service: address is empty")
 	}
 
+	// The internal actors are placed and reminded like the actors of the app
+	for actorType := range a.config.InternalActors {
+		if !IsInternalActorType(actorType) {
+			return errors.Errorf("actors: invalid internal actor type %s: must start with %s", actorType, InternalActorTypePrefix)
+		}
+		a.config.HostedActorTypes = append(a.config.HostedActorTypes, actorType)
+	}
+
 	if len(a.config.HostedActorTypes) > 0 {
 		if a.store == nil {
 			// If we have hosted actors and no store, we can't initialize the actor runtime
@@ -215,6 +232,13 @@ func (a *actorsRuntime) Init() error {
 		a.config.AppID, hostname, a.config.HostedActorTypes,
 		appHealthFn,
 		afterTableUpdateFn)
+	if tlsConfig := a.config.PlacementTLS; tlsConfig != nil {
+		a.placement.SetTLSConfig(&internal.TLSConfig{
+			RootCA:     tlsConfig.RootCA,
+			ServerName: tlsConfig.ServerName,
+			Token:      tlsConfig.Token,
+		})
+	}
 
 	go a.placement.Start()
 	a.startDeactivationTicker(a.config)
@@ -235,7 +259,8 @@ func (a *actorsRuntime) Init() error {
 }
 
 func (a *actorsRuntime) startAppHealthCheck(opts ...health.Option) {
-	if len(a.config.HostedActorTypes) == 0 {
+	// The health of the app doesn't matter to the internal actors
+	if len(a.config.HostedActorTypes) == len(a.config.InternalActors) {
 		return
 	}
 
@@ -256,28 +281,36 @@ func decomposeCompositeKey(compositeKey string) []string {
 }
 
 func (a *actorsRuntime) deactivateActor(actorType, actorID string) error {
-	req := invokev1.NewInvokeMethodRequest(fmt.Sprintf("actors/%s/%s", actorType, actorID))
-	req.WithHTTPExtension(nethttp.MethodDelete, "")
-	req.WithRawData(nil, invokev1.JSONContentType)
-
 	// TODO Propagate context.
 	ctx := context.Background()
-	resp, err := a.appChannel.InvokeMethod(ctx, req)
-	if err != nil {
-		diag.DefaultMonitoring.ActorDeactivationFailed(actorType, "invoke")
-		return err
-	}
+	if internal, ok := a.config.InternalActors[actorType]; ok {
+		if err := internal.DeactivateActor(ctx, actorID); err != nil {
+			diag.DefaultMonitoring.ActorDeactivationFailed(actorType, "invoke")
+			return err
+		}
+	} else {
+		req := invokev1.NewInvokeMethodRequest(fmt.Sprintf("actors/%s/%s", actorType, actorID))
+		req.WithHTTPExtension(nethttp.MethodDelete, "")
+		req.WithRawData(nil, invokev1.JSONContentType)
 
-	if resp.Status().Code != nethttp.StatusOK {
-		diag.DefaultMonitoring.ActorDeactivationFailed(actorType, fmt.Sprintf("status_code_%d", resp.Status().Code))
-		_, body := resp.RawData()
-		return errors.Errorf("error from actor service: %s", string(body))
+		resp, err := a.appChannel.InvokeMethod(ctx, req)
+		if err != nil {
+			diag.DefaultMonitoring.ActorDeactivationFailed(actorType, "invoke")
+			return err
+		}
+
+		if resp.Status().Code != nethttp.StatusOK {
+			diag.DefaultMonitoring.ActorDeactivationFailed(actorType, fmt.Sprintf("status_code_%d", resp.Status().Code))
+			_, body := resp.RawData()
+			return errors.Errorf("error from actor service: %s", string(body))
+		}
 	}
 
 	actorKey := constructCompositeKey(actorType, actorID)
 	a.actorsTable.Delete(actorKey)
 	diag.DefaultMonitoring.ActorDeactivated(actorType)
 	log.Debugf("deactivated actor type=%s, id=%s\n", actorType, actorID)
+	a.publishLifecycleEvent(LifecycleEventDeactivated, actorType, actorID, "")
 
 	return nil
 }
@@ -437,7 +470,11 @@ func (a *actorsRuntime) getOrCreateActor(actorType, actorID string) *actor {
 	// call newActor, but this is trivial.
 	val, ok := a.actorsTable.Load(key)
 	if !ok {
-		val, _ = a.actorsTable.LoadOrStore(key, newActor(actorType, actorID, a.config.GetReentrancyForType(actorType).MaxStackDepth))
+		var loaded bool
+		val, loaded = a.actorsTable.LoadOrStore(key, newActor(actorType, actorID, a.config.GetReentrancyForType(actorType).MaxStackDepth))
+		if !loaded {
+			a.publishLifecycleEvent(LifecycleEventActivated, actorType, actorID, "")
+		}
 	}
 
 	return val.(*actor)
@@ -450,16 +487,27 @@ func (a *actorsRuntime) callLocalActor(ctx context.Context, req *invokev1.Invoke
 
 	// Reentrancy to determine how we lock.
 	var reentrancyID *string
-	if a.config.GetReentrancyForType(act.actorType).Enabled {
-		if headerValue, ok := req.Metadata()["Dapr-Reentrancy-Id"]; ok {
+	if reentrancy := a.config.GetReentrancyForType(act.actorType); reentrancy.Enabled {
+		if headerValue, ok := req.Metadata()[reentrancyIDHeader]; ok {
 			reentrancyID = &headerValue.GetValues()[0]
 		} else {
 			reentrancyHeader := fasthttp.RequestHeader{}
 			uuid := uuid.New().String()
-			reentrancyHeader.Add("Dapr-Reentrancy-Id", uuid)
+			reentrancyHeader.Add(reentrancyIDHeader, uuid)
 			req.AddHeaders(&reentrancyHeader)
 			reentrancyID = &uuid
 		}
+
+		// Pass the depth of the call in the chain to the app, so that it can guard against runaway recursions.
+		depth, maxDepth, err := reentrancyDepth(req, *reentrancy.MaxStackDepth)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if depth > maxDepth {
+			return nil, status.Error(codes.ResourceExhausted, ErrMaxStackDepthExceeded.Error())
+		}
+		setReentrancyMetadata(req, reentrancyDepthHeader, depth)
+		setReentrancyMetadata(req, reentrancyMaxDepthHeader, maxDepth)
 	}
 
 	err := act.lock(reentrancyID)
@@ -468,6 +516,10 @@ func (a *actorsRuntime) callLocalActor(ctx context.Context, req *invokev1.Invoke
 	}
 	defer act.unlock()
 
+	if internal, ok := a.config.InternalActors[act.actorType]; ok {
+		return callInternalActor(ctx, internal, req)
+	}
+
 	// Replace method to actors method.
 	originalMethod := req.Message().Method
 	req.Message().Method = fmt.Sprintf("actors/%s/%s/method/%s", actorTypeID.GetActorType(), actorTypeID.GetActorId(), req.Message().Method)
@@ -575,19 +627,75 @@ func (a *actorsRuntime) TransactionalStateOperation(ctx context.Context, req *Tr
 	if a.store == nil || a.transactionalStore == nil {
 		return errors.New("actors: state store does not exist or incorrectly configured. Have you set the - name: actorStateStore value: \"true\" in your state store component file?")
 	}
-	operations := []state.TransactionalStateOperation{}
 	partitionKey := constructCompositeKey(a.config.AppID, req.ActorType, req.ActorID)
 	metadata := map[string]string{metadataPartitionKey: partitionKey}
 
-	for _, o := range req.Operations {
+	operations, err := a.actorStateOperations(req.ActorType, req.ActorID, req.Operations)
+	if err != nil {
+		return err
+	}
+
+	policy := a.resiliency.ComponentOutboundPolicy(ctx, a.storeName, resiliency.Statestore)
+	return policy(func(ctx context.Context) error {
+		return a.transactionalStore.Multi(&state.TransactionalStateRequest{
+			Operations: operations,
+			Metadata:   metadata,
+		})
+	})
+}
+
+// MultiActorTransactionalStateOperation performs the operations of several actors of the same type in a single transaction.
+// All the actors must be hosted by this host according to the placement table.
+func (a *actorsRuntime) MultiActorTransactionalStateOperation(ctx context.Context, req *MultiActorTransactionalRequest) error {
+	if a.store == nil || a.transactionalStore == nil {
+		return errors.New("actors: state store does not exist or incorrectly configured. Have you set the - name: actorStateStore value: \"true\" in your state store component file?")
+	}
+	if len(req.Actors) == 0 {
+		return errors.New("no actor in the transaction")
+	}
+
+	a.placement.WaitUntilPlacementTableIsReady()
+
+	operations := []state.TransactionalStateOperation{}
+	for _, actor := range req.Actors {
+		if actor.ActorID == "" {
+			return errors.New("missing actor id in the transaction")
+		}
+		targetActorAddress, _ := a.placement.LookupActor(req.ActorType, actor.ActorID)
+		if targetActorAddress == "" || !a.isActorLocal(targetActorAddress, a.config.HostAddress, a.config.Port) {
+			return errors.Wrapf(ErrActorsNotColocated, "actor %s of type %s is not hosted by this host", actor.ActorID, req.ActorType)
+		}
+
+		actorOperations, err := a.actorStateOperations(req.ActorType, actor.ActorID, actor.Operations)
+		if err != nil {
+			return err
+		}
+		operations = append(operations, actorOperations...)
+	}
+
+	policy := a.resiliency.ComponentOutboundPolicy(ctx, a.storeName, resiliency.Statestore)
+	return policy(func(ctx context.Context) error {
+		return a.transactionalStore.Multi(&state.TransactionalStateRequest{
+			Operations: operations,
+		})
+	})
+}
+
+// actorStateOperations returns the state store operations of the actor operations.
+func (a *actorsRuntime) actorStateOperations(actorType, actorID string, ops []TransactionalOperation) ([]state.TransactionalStateOperation, error) {
+	operations := []state.TransactionalStateOperation{}
+	partitionKey := constructCompositeKey(a.config.AppID, actorType, actorID)
+	metadata := map[string]string{metadataPartitionKey: partitionKey}
+
+	for _, o := range ops {
 		switch o.Operation {
 		case Upsert:
 			var upsert TransactionalUpsert
 			err := mapstructure.Decode(o.Request, &upsert)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			key := a.constructActorStateKey(req.ActorType, req.ActorID, upsert.Key)
+			key := a.constructActorStateKey(actorType, actorID, upsert.Key)
 			operations = append(operations, state.TransactionalStateOperation{
 				Request: state.SetRequest{
 					Key:      key,
@@ -600,10 +708,10 @@ func (a *actorsRuntime) TransactionalStateOperation(ctx context.Context, req *Tr
 			var delete TransactionalDelete
 			err := mapstructure.Decode(o.Request, &delete)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
-			key := a.constructActorStateKey(req.ActorType, req.ActorID, delete.Key)
+			key := a.constructActorStateKey(actorType, actorID, delete.Key)
 			operations = append(operations, state.TransactionalStateOperation{
 				Request: state.DeleteRequest{
 					Key:      key,
@@ -612,17 +720,10 @@ func (a *actorsRuntime) TransactionalStateOperation(ctx context.Context, req *Tr
 				Operation: state.Delete,
 			})
 		default:
-			return errors.Errorf("operation type %s not supported", o.Operation)
+			return nil, errors.Errorf("operation type %s not supported", o.Operation)
 		}
 	}
-
-	policy := a.resiliency.ComponentOutboundPolicy(ctx, a.storeName, resiliency.Statestore)
-	return policy(func(ctx context.Context) error {
-		return a.transactionalStore.Multi(&state.TransactionalStateRequest{
-			Operations: operations,
-			Metadata:   metadata,
-		})
-	})
+	return operations, nil
 }
 
 func (a *actorsRuntime) IsActorHosted(ctx context.Context, req *ActorHostedRequest) bool {
@@ -653,6 +754,7 @@ func (a *actorsRuntime) constructActorStateKey(actorType, actorID, key string) s
 func (a *actorsRuntime) drainRebalancedActors() {
 	// visit all currently active actors.
 	var wg sync.WaitGroup
+	var drained atomic.Int32
 
 	a.actorsTable.Range(func(key interface{}, value interface{}) bool {
 		wg.Add(1)
@@ -698,6 +800,8 @@ func (a *actorsRuntime) drainRebalancedActors() {
 				a.actorsTable.Delete(key)
 
 				diag.DefaultMonitoring.ActorRebalanced(actorType)
+				a.publishLifecycleEvent(LifecycleEventRebalanced, actorType, actorID, "")
+				drained.Inc()
 
 				for {
 					// wait until actor is not busy, then deactivate
@@ -716,6 +820,9 @@ func (a *actorsRuntime) drainRebalancedActors() {
 	})
 
 	wg.Wait()
+	if n := drained.Load(); n > 0 {
+		log.Infof("drained %d actors rebalanced to other hosts", n)
+	}
 }
 
 func (a *actorsRuntime) evaluateReminders() {
@@ -995,10 +1102,17 @@ func (a *actorsRuntime) executeReminder(reminder *Reminder) error {
 	req.WithRawData(b, invokev1.JSONContentType)
 
 	policy := a.resiliency.ActorPreLockPolicy(context.Background(), reminder.ActorType, reminder.ActorID)
-	return policy(func(ctx context.Context) error {
+	err = policy(func(ctx context.Context) error {
 		_, err := a.callLocalActor(ctx, req)
 		return err
 	})
+	if err != nil {
+		diag.DefaultMonitoring.ActorReminderFailed(reminder.ActorType)
+		a.publishLifecycleEvent(LifecycleEventReminderFailed, reminder.ActorType, reminder.ActorID, reminder.Name)
+		return err
+	}
+	a.publishLifecycleEvent(LifecycleEventReminderFired, reminder.ActorType, reminder.ActorID, reminder.Name)
+	return nil
 }
 
 func (a *actorsRuntime) reminderRequiresUpdate(req *CreateReminderRequest, reminder *Reminder) bool {
@@ -2044,6 +2158,73 @@ func (a *actorsRuntime) GetActiveActorsCount(ctx context.Context) []ActiveActors
 	return activeActorsCount
 }
 
+// SimulatePlacementChange reports which actor ranges would move if the given hosts joined or left the placement tables.
+func (a *actorsRuntime) SimulatePlacementChange(ctx context.Context, req *PlacementSimulationRequest) []PlacementSimulation {
+	if a.placement == nil {
+		return []PlacementSimulation{}
+	}
+
+	changes := a.placement.SimulateChange(req.Added, req.Removed)
+	activations := map[string]int{}
+	affected := map[string]int{}
+	a.actorsTable.Range(func(key, value interface{}) bool {
+		actorType, actorID := a.getActorTypeAndIDFromKey(key.(string))
+		activations[actorType]++
+		if change, ok := changes[actorType]; ok {
+			current, _ := change.Current.Get(actorID)
+			simulated, _ := change.Simulated.Get(actorID)
+			if current != simulated {
+				affected[actorType]++
+			}
+		}
+		return true
+	})
+
+	simulations := make([]PlacementSimulation, 0, len(changes))
+	for actorType, change := range changes {
+		ranges := change.Current.MovedRanges(change.Simulated)
+		if ranges == nil {
+			ranges = []hashing.MovedRange{}
+		}
+		fraction := hashing.RingFraction(ranges)
+		hosts := len(change.Current.Hosts())
+		simulations = append(simulations, PlacementSimulation{
+			ActorType:                    actorType,
+			MovedRanges:                  ranges,
+			MovedFraction:                fraction,
+			AffectedLocalActivations:     affected[actorType],
+			EstimatedAffectedActivations: int(math.Round(fraction * float64(activations[actorType]*hosts))),
+		})
+	}
+	sort.Slice(simulations, func(i, j int) bool {
+		return simulations[i].ActorType < simulations[j].ActorType
+	})
+
+	return simulations
+}
+
+// GetPlacementStatus returns the state of the connection to placement service, or nil if this sidecar doesn't connect to it.
+func (a *actorsRuntime) GetPlacementStatus(ctx context.Context) *PlacementStatus {
+	if a.placement == nil {
+		return nil
+	}
+
+	status := a.placement.Status()
+	res := &PlacementStatus{
+		Connected:    status.Connected,
+		Server:       status.Server,
+		TableVersion: status.TableVersion,
+		TableBlocked: status.TableBlocked,
+	}
+	if !status.LastTableUpdate.IsZero() {
+		res.LastTableUpdate = &status.LastTableUpdate
+	}
+	if !status.LastDisconnect.IsZero() {
+		res.LastDisconnect = &status.LastDisconnect
+	}
+	return res
+}
+
 // Stop closes all network connections and resources used in actor runtime.
 func (a *actorsRuntime) Stop() {
 	if a.placement != nil {
@@ -195,6 +195,20 @@ func (_m *MockActors) TransactionalStateOperation(ctx context.Context, req *Tran
 	return r0
 }
 
+// MultiActorTransactionalStateOperation provides a mock function with given fields: req
+func (_m *MockActors) MultiActorTransactionalStateOperation(ctx context.Context, req *MultiActorTransactionalRequest) error {
+	ret := _m.Called(req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*MultiActorTransactionalRequest) error); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetReminder provides a mock function with given fields: req
 func (_m *MockActors) GetReminder(ctx context.Context, req *GetReminderRequest) (*Reminder, error) {
 	ret := _m.Called(req)
@@ -233,6 +247,38 @@ func (_m *MockActors) GetActiveActorsCount(ctx context.Context) []ActiveActorsCo
 	}
 }
 
+// SimulatePlacementChange provides a mock function with given fields: req
+func (_m *MockActors) SimulatePlacementChange(ctx context.Context, req *PlacementSimulationRequest) []PlacementSimulation {
+	ret := _m.Called(req)
+
+	var r0 []PlacementSimulation
+	if rf, ok := ret.Get(0).(func(*PlacementSimulationRequest) []PlacementSimulation); ok {
+		r0 = rf(req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]PlacementSimulation)
+		}
+	}
+
+	return r0
+}
+
+// GetPlacementStatus provides a mock function with given fields:
+func (_m *MockActors) GetPlacementStatus(ctx context.Context) *PlacementStatus {
+	ret := _m.Called()
+
+	var r0 *PlacementStatus
+	if rf, ok := ret.Get(0).(func() *PlacementStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*PlacementStatus)
+		}
+	}
+
+	return r0
+}
+
 type FailingActors struct {
 	Failure daprt.Failure
 }
@@ -260,6 +306,10 @@ func (f *FailingActors) TransactionalStateOperation(ctx context.Context, req *Tr
 	return nil
 }
 
+func (f *FailingActors) MultiActorTransactionalStateOperation(ctx context.Context, req *MultiActorTransactionalRequest) error {
+	return nil
+}
+
 func (f *FailingActors) GetReminder(ctx context.Context, req *GetReminderRequest) (*Reminder, error) {
 	return nil, nil
 }
@@ -291,3 +341,11 @@ func (f *FailingActors) IsActorHosted(ctx context.Context, req *ActorHostedReque
 func (f *FailingActors) GetActiveActorsCount(ctx context.Context) []ActiveActorsCount {
 	return []ActiveActorsCount{}
 }
+
+func (f *FailingActors) SimulatePlacementChange(ctx context.Context, req *PlacementSimulationRequest) []PlacementSimulation {
+	return []PlacementSimulation{}
+}
+
+func (f *FailingActors) GetPlacementStatus(ctx context.Context) *PlacementStatus {
+	return nil
+}
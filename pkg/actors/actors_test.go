@@ -31,6 +31,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/dapr/pkg/actors/internal"
 	"github.com/dapr/dapr/pkg/apis/resiliency/v1alpha1"
 	"github.com/dapr/dapr/pkg/channel"
 	"github.com/dapr/dapr/pkg/config"
@@ -119,6 +120,7 @@ type reentrantAppChannel struct {
 	channel.AppChannel
 	nextCall []*invokev1.InvokeMethodRequest
 	callLog  []string
+	depthLog []string
 	a        *actorsRuntime
 }
 
@@ -128,15 +130,20 @@ func (r *reentrantAppChannel) GetBaseAddress() string {
 
 func (r *reentrantAppChannel) InvokeMethod(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
 	r.callLog = append(r.callLog, fmt.Sprintf("Entering %s", req.Message().Method))
+	if val, ok := req.Metadata()[reentrancyDepthHeader]; ok {
+		r.depthLog = append(r.depthLog, fmt.Sprintf("%s/%s", val.Values[0], req.Metadata()[reentrancyMaxDepthHeader].Values[0]))
+	}
 	if len(r.nextCall) > 0 {
 		nextReq := r.nextCall[0]
 		r.nextCall = r.nextCall[1:]
 
-		if val, ok := req.Metadata()["Dapr-Reentrancy-Id"]; ok {
-			header := fasthttp.RequestHeader{}
-			header.Add("Dapr-Reentrancy-Id", val.Values[0])
-			nextReq.AddHeaders(&header)
+		header := fasthttp.RequestHeader{}
+		for _, key := range []string{reentrancyIDHeader, reentrancyDepthHeader, reentrancyMaxDepthHeader} {
+			if val, ok := req.Metadata()[key]; ok {
+				header.Add(key, val.Values[0])
+			}
 		}
+		nextReq.AddHeaders(&header)
 		_, err := r.a.callLocalActor(context.Background(), nextReq)
 		if err != nil {
 			return nil, err
@@ -1813,6 +1820,90 @@ func TestGetOrCreateActor(t *testing.T) {
 	})
 }
 
+func TestLifecycleEvents(t *testing.T) {
+	events := make(chan LifecycleEvent, 10)
+	config := NewConfig("", TestAppID, []string{"placement:5050"}, 0, "", config.ApplicationConfig{})
+	config.HostAddress = "10.0.0.1"
+	config.LifecycleEventPublisher = func(event LifecycleEvent) error {
+		events <- event
+		return nil
+	}
+	testActorRuntime := (&runtimeBuilder{config: &config}).buildActorRuntime()
+
+	receive := func(t *testing.T) LifecycleEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "lifecycle event not published")
+			return LifecycleEvent{}
+		}
+	}
+
+	t.Run("activated", func(t *testing.T) {
+		testActorRuntime.getOrCreateActor("cat", "id-1")
+		event := receive(t)
+		assert.Equal(t, LifecycleEventActivated, event.Type)
+		assert.Equal(t, "cat", event.ActorType)
+		assert.Equal(t, "id-1", event.ActorID)
+		assert.Equal(t, TestAppID, event.AppID)
+		assert.Equal(t, "10.0.0.1", event.HostAddress)
+
+		// The actor is already active.
+		testActorRuntime.getOrCreateActor("cat", "id-1")
+		assert.Empty(t, events)
+	})
+
+	t.Run("deactivated", func(t *testing.T) {
+		require.NoError(t, testActorRuntime.deactivateActor("cat", "id-1"))
+		event := receive(t)
+		assert.Equal(t, LifecycleEventDeactivated, event.Type)
+		assert.Equal(t, "id-1", event.ActorID)
+	})
+
+	t.Run("reminder fired", func(t *testing.T) {
+		err := testActorRuntime.executeReminder(&Reminder{ActorType: "cat", ActorID: "id-2", Name: "reminder1"})
+		require.NoError(t, err)
+		// Invoking the reminder activates the actor too, the events are published concurrently.
+		received := map[LifecycleEventType]LifecycleEvent{}
+		for i := 0; i < 2; i++ {
+			event := receive(t)
+			received[event.Type] = event
+		}
+		assert.Contains(t, received, LifecycleEventActivated)
+		assert.Equal(t, "reminder1", received[LifecycleEventReminderFired].ReminderName)
+	})
+
+	t.Run("reminder failed", func(t *testing.T) {
+		act := testActorRuntime.getOrCreateActor("cat", "id-3")
+		assert.Equal(t, LifecycleEventActivated, receive(t).Type)
+		// The actor can't be locked once it is disposed.
+		require.NoError(t, act.lock(nil))
+		act.channel()
+		act.unlock()
+
+		err := testActorRuntime.executeReminder(&Reminder{ActorType: "cat", ActorID: "id-3", Name: "reminder2"})
+		require.Error(t, err)
+		event := receive(t)
+		assert.Equal(t, LifecycleEventReminderFailed, event.Type)
+		assert.Equal(t, "reminder2", event.ReminderName)
+	})
+}
+
+func TestGetPlacementStatus(t *testing.T) {
+	testActorRuntime := newTestActorsRuntime()
+	assert.Nil(t, testActorRuntime.GetPlacementStatus(context.Background()))
+
+	testActorRuntime.placement = internal.NewActorPlacement(
+		[]string{}, nil, TestAppID, "127.0.0.1:1000", []string{"cat"},
+		func() bool { return true }, func() {})
+	status := testActorRuntime.GetPlacementStatus(context.Background())
+	require.NotNil(t, status)
+	assert.False(t, status.Connected)
+	assert.Nil(t, status.LastTableUpdate)
+	assert.Nil(t, status.LastDisconnect)
+}
+
 func TestActiveActorsCount(t *testing.T) {
 	ctx := context.Background()
 	t.Run("Actors Count", func(t *testing.T) {
@@ -1838,6 +1929,74 @@ func TestActiveActorsCount(t *testing.T) {
 	})
 }
 
+func TestMultiActorTransactionalState(t *testing.T) {
+	ctx := context.Background()
+	upsert := []TransactionalOperation{{
+		Operation: Upsert,
+		Request:   TransactionalUpsert{Key: "key1", Value: "fakeData"},
+	}}
+
+	t.Run("no actor", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntime()
+		err := testActorRuntime.MultiActorTransactionalStateOperation(ctx, &MultiActorTransactionalRequest{ActorType: "cat"})
+		assert.Error(t, err)
+	})
+
+	t.Run("actors not hosted by this host", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntime()
+		testActorRuntime.placement = internal.NewActorPlacement(
+			[]string{}, nil, TestAppID, "127.0.0.1:1000", []string{"cat"},
+			func() bool { return true }, func() {})
+
+		err := testActorRuntime.MultiActorTransactionalStateOperation(ctx, &MultiActorTransactionalRequest{
+			ActorType: "cat",
+			Actors: []ActorTransactionalOperations{
+				{ActorID: "1", Operations: upsert},
+				{ActorID: "2", Operations: upsert},
+			},
+		})
+		assert.ErrorIs(t, err, ErrActorsNotColocated)
+	})
+
+	t.Run("without state store", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntimeWithoutStore()
+		err := testActorRuntime.MultiActorTransactionalStateOperation(ctx, &MultiActorTransactionalRequest{
+			ActorType: "cat",
+			Actors:    []ActorTransactionalOperations{{ActorID: "1", Operations: upsert}},
+		})
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrActorsNotColocated)
+	})
+}
+
+func TestActorStateOperations(t *testing.T) {
+	testActorRuntime := newTestActorsRuntime()
+	operations, err := testActorRuntime.actorStateOperations("cat", "1", []TransactionalOperation{
+		{Operation: Upsert, Request: TransactionalUpsert{Key: "key1", Value: "fakeData"}},
+		{Operation: Delete, Request: TransactionalDelete{Key: "key2"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, state.Upsert, operations[0].Operation)
+	assert.Equal(t, TestAppID+"||cat||1||key1", operations[0].Request.(state.SetRequest).Key)
+	assert.Equal(t, TestAppID+"||cat||1", operations[0].Request.(state.SetRequest).Metadata[metadataPartitionKey])
+	assert.Equal(t, state.Delete, operations[1].Operation)
+	assert.Equal(t, TestAppID+"||cat||1||key2", operations[1].Request.(state.DeleteRequest).Key)
+
+	_, err = testActorRuntime.actorStateOperations("cat", "1", []TransactionalOperation{{Operation: "unknown"}})
+	assert.Error(t, err)
+}
+
+func TestSimulatePlacementChange(t *testing.T) {
+	testActorRuntime := newTestActorsRuntime()
+	fakeCallAndActivateActor(testActorRuntime, "cat", "abcd")
+
+	simulations := testActorRuntime.SimulatePlacementChange(context.Background(), &PlacementSimulationRequest{
+		Added: []string{"127.0.0.1:50002"},
+	})
+	assert.Equal(t, []PlacementSimulation{}, simulations)
+}
+
 func TestActorsAppHealthCheck(t *testing.T) {
 	testActorRuntime := newTestActorsRuntime()
 	testActorRuntime.config.HostedActorTypes = []string{"actor1"}
@@ -2162,6 +2321,64 @@ func TestReentrancyStackLimit(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestReentrancyDepth(t *testing.T) {
+	newRuntime := func(nextCalls ...*invokev1.InvokeMethodRequest) (*actorsRuntime, *reentrantAppChannel) {
+		stackDepth := 4
+		appConfig := DefaultAppConfig
+		appConfig.Reentrancy = config.ReentrancyConfig{Enabled: true, MaxStackDepth: &stackDepth}
+		reentrantConfig := NewConfig("", TestAppID, []string{"placement:5050"}, 0, "", appConfig)
+		reentrantAppChannel := new(reentrantAppChannel)
+		reentrantAppChannel.nextCall = nextCalls
+		builder := runtimeBuilder{
+			appChannel:  reentrantAppChannel,
+			config:      &reentrantConfig,
+			featureSpec: []config.FeatureSpec{{Name: "Actor.Reentrancy", Enabled: true}},
+		}
+		testActorRuntime := builder.buildActorRuntime()
+		reentrantAppChannel.a = testActorRuntime
+		return testActorRuntime, reentrantAppChannel
+	}
+
+	t.Run("depth passed to the app across actors", func(t *testing.T) {
+		testActorRuntime, reentrantAppChannel := newRuntime(
+			invokev1.NewInvokeMethodRequest("second").WithActor("other", "1"),
+			invokev1.NewInvokeMethodRequest("third").WithActor("reentrant", "1"),
+		)
+		_, err := testActorRuntime.callLocalActor(context.Background(), invokev1.NewInvokeMethodRequest("first").WithActor("reentrant", "1"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1/4", "2/4", "3/4"}, reentrantAppChannel.depthLog)
+	})
+
+	t.Run("max depth of the chain", func(t *testing.T) {
+		testActorRuntime, reentrantAppChannel := newRuntime(
+			invokev1.NewInvokeMethodRequest("second").WithActor("other", "1"),
+			invokev1.NewInvokeMethodRequest("third").WithActor("reentrant", "1"),
+		)
+		req := invokev1.NewInvokeMethodRequest("first").WithActor("reentrant", "1").
+			WithMetadata(map[string][]string{reentrancyMaxDepthHeader: {"2"}})
+		_, err := testActorRuntime.callLocalActor(context.Background(), req)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, []string{"1/2", "2/2"}, reentrantAppChannel.depthLog)
+	})
+
+	t.Run("requested max depth above the configuration", func(t *testing.T) {
+		testActorRuntime, reentrantAppChannel := newRuntime()
+		req := invokev1.NewInvokeMethodRequest("first").WithActor("reentrant", "1").
+			WithMetadata(map[string][]string{reentrancyMaxDepthHeader: {"100"}})
+		_, err := testActorRuntime.callLocalActor(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1/4"}, reentrantAppChannel.depthLog)
+	})
+
+	t.Run("invalid depth", func(t *testing.T) {
+		testActorRuntime, _ := newRuntime()
+		req := invokev1.NewInvokeMethodRequest("first").WithActor("reentrant", "1").
+			WithMetadata(map[string][]string{reentrancyDepthHeader: {"-1"}})
+		_, err := testActorRuntime.callLocalActor(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
 func TestReentrancyPerActor(t *testing.T) {
 	req := invokev1.NewInvokeMethodRequest("first").WithActor("reentrantActor", "1")
 	req2 := invokev1.NewInvokeMethodRequest("second").WithActor("reentrantActor", "1")
@@ -35,6 +35,23 @@ type Config struct {
 	Reentrancy                    daprAppConfig.ReentrancyConfig
 	RemindersStoragePartitions    int
 	EntityConfigs                 map[string]EntityConfig
+	// LifecycleEventPublisher publishes the actor lifecycle events, if set.
+	LifecycleEventPublisher LifecycleEventPublisher
+	// PlacementTLS is the TLS configuration of a placement service which isn't verified with the trust bundle, if set.
+	PlacementTLS *PlacementTLSConfig
+	// InternalActors are the actors hosted by the runtime instead of the app, by actor type.
+	InternalActors map[string]InternalActor
+}
+
+// PlacementTLSConfig is the configuration of the connection to a placement service over TLS with its own CA,
+// such as a managed placement service outside of the cluster.
+type PlacementTLSConfig struct {
+	// RootCA is the PEM bundle of the CAs of the placement service. The system CAs are used if empty.
+	RootCA []byte
+	// ServerName is the name sent with SNI and verified in the certificate of the placement service.
+	ServerName string
+	// Token is sent as bearer token to the placement service, if set.
+	Token string
 }
 
 // Remap of app_config.EntityConfig but with more useful types for actors.go.
@@ -64,6 +64,8 @@ type ActorPlacement struct {
 
 	// clientCert is the workload certificate to connect placement.
 	clientCert *daprCredentials.CertChain
+	// tlsConfig is the TLS configuration of a placement service which isn't verified with the trust bundle, if set.
+	tlsConfig *TLSConfig
 
 	// clientLock is the lock for client conn and stream.
 	clientLock *sync.RWMutex
@@ -96,6 +98,15 @@ type ActorPlacement struct {
 	// such as draining actors and resetting reminders.
 	afterTableUpdateFn func()
 
+	// statusLock is the lock for the connection and table events reported by Status.
+	statusLock *sync.RWMutex
+	// connectedServer is the target of the last established connection to placement.
+	connectedServer string
+	// lastTableUpdate is the time the placement tables were last updated.
+	lastTableUpdate time.Time
+	// lastDisconnect is the time the stream to placement was last closed.
+	lastDisconnect time.Time
+
 	// shutdown is the flag when runtime is being shutdown.
 	shutdown atomic.Bool
 	// shutdownConnLoop is the wait group to wait until all connection loop are done
@@ -139,11 +150,25 @@ func NewActorPlacement(
 
 		operationUpdateLock: &sync.Mutex{},
 		tableIsBlocked:      atomic.NewBool(false),
+		statusLock:          &sync.RWMutex{},
 		appHealthFn:         appHealthFn,
 		afterTableUpdateFn:  afterTableUpdateFn,
 	}
 }
 
+// SetTLSConfig sets the TLS configuration used to connect to a placement service
+// which isn't verified with the trust bundle. It must be called before Start.
+func (p *ActorPlacement) SetTLSConfig(config *TLSConfig) {
+	p.tlsConfig = config
+}
+
+func (p *ActorPlacement) dialOptions() ([]grpc.DialOption, error) {
+	if p.tlsConfig != nil {
+		return p.tlsConfig.dialOptions(p.clientCert)
+	}
+	return daprCredentials.GetClientOptions(p.clientCert, security.TLSServerName)
+}
+
 // Start connects placement service to register to membership and send heartbeat
 // to report the current member status periodically.
 func (p *ActorPlacement) Start() {
@@ -307,6 +332,10 @@ func (p *ActorPlacement) closeStream() {
 		if p.clientStream != nil {
 			p.clientStream.CloseSend()
 			p.clientStream = nil
+
+			if !p.shutdown.Load() {
+				p.recordDisconnect()
+			}
 		}
 
 		if p.clientConn != nil {
@@ -338,7 +367,7 @@ func (p *ActorPlacement) establishStreamConn() (v1pb.Placement_ReportDaprStatusC
 
 		log.Debugf("try to connect to placement service: %s", serverAddr)
 
-		opts, err := daprCredentials.GetClientOptions(p.clientCert, security.TLSServerName)
+		opts, err := p.dialOptions()
 		if err != nil {
 			log.Errorf("failed to establish TLS credentials for actor placement service: %s", err)
 			return nil, nil
@@ -375,6 +404,9 @@ func (p *ActorPlacement) establishStreamConn() (v1pb.Placement_ReportDaprStatusC
 		}
 
 		log.Debugf("established connection to placement service at %s", conn.Target())
+		p.statusLock.Lock()
+		p.connectedServer = conn.Target()
+		p.statusLock.Unlock()
 		return stream, conn
 	}
 
@@ -450,12 +482,65 @@ func (p *ActorPlacement) updatePlacements(in *v1pb.PlacementTables) {
 		return
 	}
 
+	p.statusLock.Lock()
+	p.lastTableUpdate = time.Now().UTC()
+	p.statusLock.Unlock()
+	diag.DefaultMonitoring.ActorPlacementTableUpdated()
+
 	// May call LookupActor inside, so should not do this with placementTableLock locked.
 	p.afterTableUpdateFn()
 
 	log.Infof("placement tables updated, version: %s", in.GetVersion())
 }
 
+func (p *ActorPlacement) recordDisconnect() {
+	p.statusLock.Lock()
+	p.lastDisconnect = time.Now().UTC()
+	server := p.connectedServer
+	p.statusLock.Unlock()
+
+	diag.DefaultMonitoring.ActorPlacementDisconnected()
+	log.Warnf("disconnected from placement service %s", server)
+}
+
+// PlacementStatus is the state of the connection to placement and of the placement tables.
+type PlacementStatus struct {
+	Connected bool
+	// Server is the placement service of the last established connection.
+	Server string
+	// TableVersion is the version of the placement tables, empty until the first update.
+	TableVersion string
+	// TableBlocked is true while the placement tables are locked for an update.
+	TableBlocked    bool
+	LastTableUpdate time.Time
+	LastDisconnect  time.Time
+}
+
+// Status returns the state of the connection to placement and of the placement tables.
+func (p *ActorPlacement) Status() PlacementStatus {
+	p.streamConnectedCond.L.Lock()
+	connected := p.streamConnAlive
+	p.streamConnectedCond.L.Unlock()
+
+	version := ""
+	p.placementTableLock.RLock()
+	if p.placementTables != nil {
+		version = p.placementTables.Version
+	}
+	p.placementTableLock.RUnlock()
+
+	p.statusLock.RLock()
+	defer p.statusLock.RUnlock()
+	return PlacementStatus{
+		Connected:       connected,
+		Server:          p.connectedServer,
+		TableVersion:    version,
+		TableBlocked:    p.tableIsBlocked.Load(),
+		LastTableUpdate: p.lastTableUpdate,
+		LastDisconnect:  p.lastDisconnect,
+	}
+}
+
 // WaitUntilPlacementTableIsReady waits until placement table is until table lock is unlocked.
 func (p *ActorPlacement) WaitUntilPlacementTableIsReady() {
 	if p.tableIsBlocked.Load() {
@@ -482,3 +567,29 @@ func (p *ActorPlacement) LookupActor(actorType, actorID string) (string, string)
 	}
 	return host.Name, host.AppID
 }
+
+// PlacementChange is the placement table of an actor type and the table it would have after a membership change.
+type PlacementChange struct {
+	Current   *hashing.Consistent
+	Simulated *hashing.Consistent
+}
+
+// SimulateChange returns the placement tables of the actor types as they would be with the given hosts
+// added and removed, leaving the current placement tables unchanged.
+func (p *ActorPlacement) SimulateChange(added, removed []string) map[string]PlacementChange {
+	p.placementTableLock.RLock()
+	defer p.placementTableLock.RUnlock()
+
+	changes := map[string]PlacementChange{}
+	if p.placementTables == nil {
+		return changes
+	}
+
+	for actorType, t := range p.placementTables.Entries {
+		changes[actorType] = PlacementChange{
+			Current:   t,
+			Simulated: t.Simulate(added, removed),
+		}
+	}
+	return changes
+}
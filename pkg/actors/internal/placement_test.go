@@ -142,6 +142,45 @@ func TestAppHealthyStatus(t *testing.T) {
 	cleanup()
 }
 
+func TestStatus(t *testing.T) {
+	address, testSrv, cleanup := newTestServer()
+	testSrv.setLeader(true)
+
+	appHealthFunc := func() bool { return true }
+	noopTableUpdateFunc := func() {}
+	testPlacement := NewActorPlacement(
+		[]string{address}, nil, "testAppID", "127.0.0.1:1000", []string{"actorOne", "actorTwo"},
+		appHealthFunc, noopTableUpdateFunc)
+
+	status := testPlacement.Status()
+	assert.False(t, status.Connected)
+	assert.Empty(t, status.TableVersion)
+
+	testPlacement.Start()
+	defer testPlacement.Stop()
+
+	testPlacement.onPlacementOrder(&placementv1pb.PlacementOrder{
+		Operation: "update",
+		Tables: &placementv1pb.PlacementTables{
+			Version: "1",
+			Entries: map[string]*placementv1pb.PlacementTable{},
+		},
+	})
+
+	status = testPlacement.Status()
+	assert.True(t, status.Connected)
+	assert.Equal(t, address, status.Server)
+	assert.Equal(t, "1", status.TableVersion)
+	assert.False(t, status.TableBlocked)
+	assert.False(t, status.LastTableUpdate.IsZero())
+	assert.True(t, status.LastDisconnect.IsZero())
+
+	cleanup()
+	assert.Eventually(t, func() bool {
+		return !testPlacement.Status().LastDisconnect.IsZero()
+	}, time.Second*5, time.Millisecond*10)
+}
+
 func TestOnPlacementOrder(t *testing.T) {
 	tableUpdateCount := 0
 	appHealthFunc := func() bool { return true }
@@ -315,6 +354,8 @@ func newTestServer() (string, *testServer, func()) {
 }
 
 type testServer struct {
+	placementv1pb.UnimplementedPlacementServer
+
 	isLeader           atomic.Bool
 	lastHost           *placementv1pb.Host
 	recvCount          atomic.Int32
@@ -346,3 +387,29 @@ func (s *testServer) ReportDaprStatus(srv placementv1pb.Placement_ReportDaprStat
 func (s *testServer) setLeader(leader bool) {
 	s.isLeader.Store(leader)
 }
+
+func TestSimulateChange(t *testing.T) {
+	appHealthFunc := func() bool { return true }
+	tableUpdateFunc := func() {}
+	testPlacement := NewActorPlacement(
+		[]string{}, nil,
+		"testAppID", "127.0.0.1:1000",
+		[]string{"actorOne"},
+		appHealthFunc, tableUpdateFunc)
+
+	assert.Empty(t, testPlacement.SimulateChange([]string{"127.0.0.1:2000"}, nil))
+
+	hashing.SetReplicationFactor(10)
+	actorOneHashing := hashing.NewConsistentHash()
+	actorOneHashing.Add(testPlacement.runtimeHostName, testPlacement.appID, 0)
+	testPlacement.placementTables = &hashing.ConsistentHashTables{
+		Version: "1",
+		Entries: map[string]*hashing.Consistent{"actorOne": actorOneHashing},
+	}
+
+	changes := testPlacement.SimulateChange([]string{"127.0.0.1:2000"}, nil)
+	assert.Len(t, changes, 1)
+	assert.Same(t, actorOneHashing, changes["actorOne"].Current)
+	assert.Len(t, changes["actorOne"].Current.Hosts(), 1)
+	assert.Len(t, changes["actorOne"].Simulated.Hosts(), 2)
+}
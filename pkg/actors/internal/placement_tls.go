@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	daprCredentials "github.com/dapr/dapr/pkg/credentials"
+)
+
+// TLSConfig is the configuration of the connection to a placement service which isn't verified with the trust bundle of Dapr,
+// such as a managed placement service outside of the cluster.
+type TLSConfig struct {
+	// RootCA is the PEM bundle of the CAs of the placement service. The system CAs are used if empty.
+	RootCA []byte
+	// ServerName is the name sent with SNI and verified in the certificate of the placement service.
+	// The host of the placement address is used if empty.
+	ServerName string
+	// Token is sent as bearer token in the authorization metadata of the stream, if set.
+	Token string
+}
+
+// dialOptions returns the options to connect to the placement service with TLS.
+// The workload certificate is presented as client certificate, if any.
+func (c *TLSConfig) dialOptions(clientCert *daprCredentials.CertChain) ([]grpc.DialOption, error) {
+	var rootCAs *x509.CertPool
+	if len(c.RootCA) > 0 {
+		rootCAs = x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(c.RootCA) {
+			return nil, errors.New("failed to append PEM root cert of placement to x509 CertPool")
+		}
+	} else {
+		var err error
+		if rootCAs, err = x509.SystemCertPool(); err != nil {
+			return nil, errors.Wrap(err, "failed to load the system CAs")
+		}
+	}
+
+	config := &tls.Config{
+		RootCAs:    rootCAs,
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if clientCert != nil {
+		cert, err := tls.X509KeyPair(clientCert.Cert, clientCert.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create tls config from cert and key")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(config))}
+	if c.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials(c.Token)))
+	}
+	return opts, nil
+}
+
+// tokenCredentials sends the token as bearer token, only over secure connections.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
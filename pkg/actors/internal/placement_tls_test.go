@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	placementv1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
+)
+
+// newTestCertificate returns a self-signed certificate of the server name and its PEM encoded certificate.
+func newTestCertificate(t *testing.T, serverName string) (tls.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: serverName},
+		DNSNames:              []string{serverName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestPlacementStream_TLS(t *testing.T) {
+	cert, certPEM := newTestCertificate(t, "placement.example.com")
+
+	var token atomic.String
+	port, _ := freeport.GetFreePort()
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if md, ok := metadata.FromIncomingContext(ss.Context()); ok && len(md.Get("authorization")) > 0 {
+				token.Store(md.Get("authorization")[0])
+			}
+			return handler(srv, ss)
+		}))
+	srv := &testServer{}
+	srv.setLeader(true)
+	placementv1pb.RegisterPlacementServer(server, srv)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	testPlacement := NewActorPlacement(
+		[]string{address}, nil, "testAppID", "127.0.0.1:1000", []string{"actorOne"},
+		func() bool { return true }, func() {})
+	testPlacement.SetTLSConfig(&TLSConfig{
+		RootCA:     certPEM,
+		ServerName: "placement.example.com",
+		Token:      "secret",
+	})
+
+	testPlacement.Start()
+	defer testPlacement.Stop()
+
+	assert.Eventually(t, func() bool {
+		return srv.recvCount.Load() >= 1
+	}, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, "Bearer secret", token.Load())
+}
+
+func TestTLSConfigDialOptions(t *testing.T) {
+	t.Run("invalid CA bundle", func(t *testing.T) {
+		_, err := (&TLSConfig{RootCA: []byte("not a certificate")}).dialOptions(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("system CAs", func(t *testing.T) {
+		opts, err := (&TLSConfig{ServerName: "placement.example.com"}).dialOptions(nil)
+		require.NoError(t, err)
+		assert.Len(t, opts, 1)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		opts, err := (&TLSConfig{ServerName: "placement.example.com", Token: "secret"}).dialOptions(nil)
+		require.NoError(t, err)
+		assert.Len(t, opts, 2)
+
+		md, err := tokenCredentials("secret").GetRequestMetadata(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"authorization": "Bearer secret"}, md)
+	})
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actors
+
+import (
+	"context"
+	nethttp "net/http"
+	"strings"
+
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+)
+
+// InternalActorTypePrefix is the prefix of the types of the actors hosted by the runtime instead of the app.
+// The actors of these types can't be invoked with the actor APIs.
+const InternalActorTypePrefix = "dapr.internal."
+
+// InternalActor is an actor hosted by the runtime instead of the app, such as a workflow instance.
+// Its actors are placed like the actors of the app, so each of them is active on a single host at a time,
+// and the calls to an actor are serialized.
+type InternalActor interface {
+	// InvokeMethod invokes a method of the actor. The reminders are invoked with the method remind/<name>.
+	InvokeMethod(ctx context.Context, actorID, method string, data []byte) ([]byte, error)
+	// DeactivateActor is called when the actor is deactivated, after it was idle or moved to another host.
+	DeactivateActor(ctx context.Context, actorID string) error
+}
+
+// IsInternalActorType returns true if the actor type is reserved for the actors hosted by the runtime.
+func IsInternalActorType(actorType string) bool {
+	return strings.HasPrefix(actorType, InternalActorTypePrefix)
+}
+
+// callInternalActor invokes the method of the request on the internal actor.
+// It must be called with the lock of the actor held.
+func callInternalActor(ctx context.Context, internal InternalActor, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	_, data := req.RawData()
+	respData, err := internal.InvokeMethod(ctx, req.Actor().GetActorId(), req.Message().Method, data)
+	if err != nil {
+		return nil, err
+	}
+	return invokev1.NewInvokeMethodResponse(nethttp.StatusOK, "OK", nil).
+		WithRawData(respData, invokev1.JSONContentType), nil
+}
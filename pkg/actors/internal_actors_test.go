@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+)
+
+type fakeInternalActor struct {
+	calls       []string
+	deactivated []string
+}
+
+func (f *fakeInternalActor) InvokeMethod(ctx context.Context, actorID, method string, data []byte) ([]byte, error) {
+	f.calls = append(f.calls, actorID+"/"+method+"/"+string(data))
+	return []byte(`"ok"`), nil
+}
+
+func (f *fakeInternalActor) DeactivateActor(ctx context.Context, actorID string) error {
+	f.deactivated = append(f.deactivated, actorID)
+	return nil
+}
+
+func TestInternalActors(t *testing.T) {
+	const internalType = InternalActorTypePrefix + "test"
+
+	t.Run("calls are sent to the internal actor instead of the app", func(t *testing.T) {
+		appChannel := new(mockAppChannel)
+		testActorsRuntime := newTestActorsRuntimeWithMock(appChannel)
+		internal := &fakeInternalActor{}
+		testActorsRuntime.config.InternalActors = map[string]InternalActor{internalType: internal}
+
+		req := invokev1.NewInvokeMethodRequest("start").
+			WithActor(internalType, "i1").
+			WithRawData([]byte("input"), invokev1.JSONContentType)
+		resp, err := testActorsRuntime.callLocalActor(context.Background(), req)
+		require.NoError(t, err)
+
+		_, data := resp.RawData()
+		assert.Equal(t, `"ok"`, string(data))
+		assert.Equal(t, []string{"i1/start/input"}, internal.calls)
+	})
+
+	t.Run("internal actors are deactivated without the app", func(t *testing.T) {
+		appChannel := new(mockAppChannel)
+		testActorsRuntime := newTestActorsRuntimeWithMock(appChannel)
+		internal := &fakeInternalActor{}
+		testActorsRuntime.config.InternalActors = map[string]InternalActor{internalType: internal}
+		fakeCallAndActivateActor(testActorsRuntime, internalType, "i1")
+
+		require.NoError(t, testActorsRuntime.deactivateActor(internalType, "i1"))
+		assert.Equal(t, []string{"i1"}, internal.deactivated)
+		_, exists := testActorsRuntime.actorsTable.Load(constructCompositeKey(internalType, "i1"))
+		assert.False(t, exists)
+	})
+
+	t.Run("internal actor types must have the internal prefix", func(t *testing.T) {
+		testActorsRuntime := newTestActorsRuntime()
+		testActorsRuntime.config.InternalActors = map[string]InternalActor{"test": &fakeInternalActor{}}
+
+		assert.ErrorContains(t, testActorsRuntime.Init(), "invalid internal actor type")
+	})
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actors
+
+import (
+	"time"
+)
+
+// LifecycleEventType is the type of an actor lifecycle event.
+type LifecycleEventType string
+
+const (
+	// LifecycleEventActivated is emitted when an actor is activated in this host.
+	LifecycleEventActivated LifecycleEventType = "activated"
+	// LifecycleEventDeactivated is emitted when an actor is deactivated, because it was idle or rebalanced.
+	LifecycleEventDeactivated LifecycleEventType = "deactivated"
+	// LifecycleEventRebalanced is emitted when an actor is moved to another host after a placement table update.
+	LifecycleEventRebalanced LifecycleEventType = "rebalanced"
+	// LifecycleEventReminderFired is emitted when a reminder of an actor was executed.
+	LifecycleEventReminderFired LifecycleEventType = "reminderFired"
+	// LifecycleEventReminderFailed is emitted when a reminder of an actor failed to execute.
+	LifecycleEventReminderFailed LifecycleEventType = "reminderFailed"
+)
+
+// LifecycleEvent is an actor lifecycle event.
+type LifecycleEvent struct {
+	Type         LifecycleEventType `json:"type"`
+	ActorType    string             `json:"actorType"`
+	ActorID      string             `json:"actorId"`
+	ReminderName string             `json:"reminderName,omitempty"`
+	AppID        string             `json:"appId"`
+	HostAddress  string             `json:"hostAddress"`
+	Time         time.Time          `json:"time"`
+}
+
+// LifecycleEventPublisher publishes the actor lifecycle events.
+type LifecycleEventPublisher func(event LifecycleEvent) error
+
+// publishLifecycleEvent publishes an actor lifecycle event in the background, if a publisher is configured.
+// Events are best effort: they don't slow down or fail the actor operations.
+func (a *actorsRuntime) publishLifecycleEvent(eventType LifecycleEventType, actorType, actorID, reminderName string) {
+	publish := a.config.LifecycleEventPublisher
+	if publish == nil {
+		return
+	}
+
+	event := LifecycleEvent{
+		Type:         eventType,
+		ActorType:    actorType,
+		ActorID:      actorID,
+		ReminderName: reminderName,
+		AppID:        a.config.AppID,
+		HostAddress:  a.config.HostAddress,
+		Time:         time.Now().UTC(),
+	}
+	go func() {
+		if err := publish(event); err != nil {
+			log.Debugf("failed to publish actor %s event for actor type=%s, id=%s: %s", eventType, actorType, actorID, err)
+		}
+	}()
+}
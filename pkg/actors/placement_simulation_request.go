@@ -0,0 +1,37 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actors
+
+import "github.com/dapr/dapr/pkg/placement/hashing"
+
+// PlacementSimulationRequest is the request object for simulating a membership change of the placement tables.
+// The hosts are the addresses of the sidecars, as registered in the placement tables.
+type PlacementSimulationRequest struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// PlacementSimulation reports the impact of a membership change on the placement of an actor type.
+type PlacementSimulation struct {
+	ActorType string `json:"actorType"`
+	// MovedRanges are the ranges of the hash ring which would be owned by another host.
+	MovedRanges []hashing.MovedRange `json:"movedRanges"`
+	// MovedFraction is the fraction of the hash ring which would be owned by another host.
+	MovedFraction float64 `json:"movedFraction"`
+	// AffectedLocalActivations is the number of actors activated in this sidecar which would move to another host.
+	AffectedLocalActivations int `json:"affectedLocalActivations"`
+	// EstimatedAffectedActivations is the number of actors of the whole cluster which would be deactivated,
+	// assuming all the hosts have as many activations as this sidecar.
+	EstimatedAffectedActivations int `json:"estimatedAffectedActivations"`
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actors
+
+import "time"
+
+// PlacementStatus is the state of the connection of this sidecar to placement service and of its placement tables.
+type PlacementStatus struct {
+	Connected bool `json:"connected"`
+	// Server is the placement service of the last established connection.
+	Server string `json:"server,omitempty"`
+	// TableVersion is the version of the placement tables, empty until the first update.
+	TableVersion string `json:"tableVersion,omitempty"`
+	// TableBlocked is true while the placement tables are locked for an update.
+	TableBlocked    bool       `json:"tableBlocked"`
+	LastTableUpdate *time.Time `json:"lastTableUpdate,omitempty"`
+	LastDisconnect  *time.Time `json:"lastDisconnect,omitempty"`
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actors
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
+)
+
+const (
+	// reentrancyIDHeader identifies the chain of reentrant calls.
+	reentrancyIDHeader = "Dapr-Reentrancy-Id"
+	// reentrancyDepthHeader is the depth of the call in the chain, 1 for the call starting the chain.
+	// Apps forward it with the reentrancy ID so that the depth is counted across the actors of the chain.
+	reentrancyDepthHeader = "Dapr-Reentrancy-Depth"
+	// reentrancyMaxDepthHeader is the maximum depth of the chain. A call can set it lower than the
+	// maximum stack depth of the configuration, for the rest of the chain.
+	reentrancyMaxDepthHeader = "Dapr-Reentrancy-Max-Depth"
+)
+
+// ErrInvalidReentrancyDepth is returned for a call with an invalid reentrancy depth or maximum depth.
+var ErrInvalidReentrancyDepth = errors.New("invalid reentrancy depth")
+
+// reentrancyDepth returns the depth of the reentrant call in its chain and the maximum depth of the chain,
+// which is the lowest of the maximum stack depth of the configuration and the one requested by the call.
+func reentrancyDepth(req *invokev1.InvokeMethodRequest, maxStackDepth int) (depth int, maxDepth int, err error) {
+	callerDepth, err := reentrancyMetadataInt(req, reentrancyDepthHeader)
+	if err != nil {
+		return 0, 0, err
+	}
+	depth = callerDepth + 1
+
+	maxDepth = maxStackDepth
+	requested, err := reentrancyMetadataInt(req, reentrancyMaxDepthHeader)
+	if err != nil {
+		return 0, 0, err
+	}
+	if requested > 0 && requested < maxDepth {
+		maxDepth = requested
+	}
+
+	return depth, maxDepth, nil
+}
+
+// reentrancyMetadataInt returns the non-negative integer of the metadata key of the request, 0 if it's missing.
+func reentrancyMetadataInt(req *invokev1.InvokeMethodRequest, key string) (int, error) {
+	val, ok := req.Metadata()[key]
+	if !ok || len(val.GetValues()) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(val.GetValues()[0])
+	if err != nil || n < 0 {
+		return 0, errors.Wrapf(ErrInvalidReentrancyDepth, "%s must be a non-negative integer, got %q", key, val.GetValues()[0])
+	}
+	return n, nil
+}
+
+// setReentrancyMetadata sets the metadata key of the request, overwriting the value sent by the caller.
+func setReentrancyMetadata(req *invokev1.InvokeMethodRequest, key string, val int) {
+	r := req.Proto()
+	if r.Metadata == nil {
+		r.Metadata = map[string]*internalv1pb.ListStringValue{}
+	}
+	r.Metadata[key] = &internalv1pb.ListStringValue{Values: []string{strconv.Itoa(val)}}
+}
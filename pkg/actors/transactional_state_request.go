@@ -29,6 +29,19 @@ type TransactionalRequest struct {
 	ActorID    string
 }
 
+// MultiActorTransactionalRequest describes the stateful operations of several actors of the same type,
+// hosted by the same host, that are performed in a single transaction.
+type MultiActorTransactionalRequest struct {
+	Actors    []ActorTransactionalOperations `json:"actors"`
+	ActorType string
+}
+
+// ActorTransactionalOperations are the operations of an actor participating in a multi-actor transaction.
+type ActorTransactionalOperations struct {
+	ActorID    string                   `json:"actorId"`
+	Operations []TransactionalOperation `json:"operations"`
+}
+
 // TransactionalOperation is the request object for a state operation participating in a transaction.
 type TransactionalOperation struct {
 	Operation OperationType `json:"operation"`
@@ -38,6 +38,8 @@ type ConfigurationSpec struct {
 	// +optional
 	HTTPPipelineSpec PipelineSpec `json:"httpPipeline,omitempty"`
 	// +optional
+	AppHTTPPipelineSpec PipelineSpec `json:"appHttpPipeline,omitempty"`
+	// +optional
 	TracingSpec TracingSpec `json:"tracing,omitempty"`
 	// +kubebuilder:default={enabled:true}
 	MetricSpec MetricSpec `json:"metric,omitempty"`
@@ -55,11 +57,157 @@ type ConfigurationSpec struct {
 	APISpec APISpec `json:"api,omitempty"`
 	// +optional
 	ComponentsSpec ComponentsSpec `json:"components,omitempty"`
+	// +optional
+	GRPCProxySpec GRPCProxySpec `json:"grpcProxy,omitempty"`
+	// +optional
+	ActorsSpec ActorsSpec `json:"actors,omitempty"`
+	// +optional
+	CORSSpec CORSSpec `json:"cors,omitempty"`
+	// +optional
+	AuditSpec AuditSpec `json:"audit,omitempty"`
+	// +optional
+	ProxySpec ProxySpec `json:"proxy,omitempty"`
+	// +optional
+	CompressionSpec CompressionSpec `json:"compression,omitempty"`
+	// +optional
+	ProfilingSpec ProfilingSpec `json:"profiling,omitempty"`
+	// +optional
+	LoggingSpec LoggingSpec `json:"logging,omitempty"`
+}
+
+// LoggingSpec configures the loggers of the sidecar.
+type LoggingSpec struct {
+	// Levels overrides the log level of the loggers by scope, such as dapr.runtime.actor or dapr.contrib.pubsub.kafka
+	// +optional
+	Levels map[string]string `json:"levels,omitempty"`
+}
+
+// ProfilingSpec exposes the pprof endpoints, including the capture of execution traces, on the public port.
+type ProfilingSpec struct {
+	// Enabled serves /debug/pprof on the public port to the callers with the Dapr API token
+	// +optional
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Maximum duration of the CPU profiles and of the execution traces, 30s by default
+	// +optional
+	MaxDuration string `json:"maxDuration,omitempty" yaml:"maxDuration,omitempty"`
+}
+
+// CompressionSpec configures the compression of the HTTP bodies of the Dapr HTTP API and of the app channel.
+type CompressionSpec struct {
+	// +optional
+	API CompressionPolicy `json:"api,omitempty"`
+	// +optional
+	AppChannel CompressionPolicy `json:"appChannel,omitempty"`
+}
+
+// CompressionPolicy enables the compression with a list of encodings.
+type CompressionPolicy struct {
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// +optional
+	Encodings []string `json:"encodings,omitempty"`
+	// +optional
+	MinSize int `json:"minSize,omitempty"`
+}
+
+// ProxySpec configures the proxies of the outbound HTTP(S) requests.
+type ProxySpec struct {
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+	// +optional
+	Auth ProxyAuthSpec `json:"auth,omitempty"`
+	// +optional
+	Components []ComponentProxySpec `json:"components,omitempty"`
+}
+
+// ComponentProxySpec overrides the proxies of the requests a component sends to its hosts.
+type ComponentProxySpec struct {
+	Name  string   `json:"name"`
+	Hosts []string `json:"hosts"`
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+}
+
+// ProxyAuthSpec references the secret holding the credentials of the proxies.
+type ProxyAuthSpec struct {
+	// +optional
+	SecretStore string `json:"secretStore,omitempty"`
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// AuditSpec configures the audit log of the calls to the Dapr APIs.
+type AuditSpec struct {
+	Enabled bool `json:"enabled"`
+	// +optional
+	Output string `json:"output,omitempty"`
+	// +optional
+	Headers []string `json:"headers,omitempty"`
+	// +optional
+	RedactedHeaders []string `json:"redactedHeaders,omitempty"`
+	// +optional
+	PayloadHash bool `json:"payloadHash,omitempty"`
+}
+
+// CORSSpec is the CORS policy of the Dapr HTTP API server.
+type CORSSpec struct {
+	CORSPolicy `json:",inline"`
+	// +optional
+	Routes []CORSRoutePolicy `json:"routes,omitempty"`
+}
+
+// CORSPolicy defines the cross-origin requests allowed.
+type CORSPolicy struct {
+	// +optional
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	// +optional
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+	// +optional
+	AllowedHeaders []string `json:"allowedHeaders,omitempty"`
+	// +optional
+	ExposedHeaders []string `json:"exposedHeaders,omitempty"`
+	// +optional
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
+	// +optional
+	MaxAge int `json:"maxAge,omitempty"`
+}
+
+// CORSRoutePolicy is the CORS policy of the requests whose path starts with Path.
+type CORSRoutePolicy struct {
+	Path       string `json:"path"`
+	CORSPolicy `json:",inline"`
 }
 
 // APISpec describes the configuration for Dapr APIs.
 type APISpec struct {
 	Allowed []APIAccessRule `json:"allowed,omitempty"`
+	// +optional
+	Denied []APIAccessRule `json:"denied,omitempty"`
+	// +optional
+	EnableAlpha bool `json:"enableAlpha,omitempty"`
+	// +optional
+	HTTPRouteLimits []HTTPRouteLimit `json:"httpRouteLimits,omitempty"`
+}
+
+// HTTPRouteLimit describes the maximum request body size and the timeout of the HTTP APIs whose route starts with the name.
+type HTTPRouteLimit struct {
+	Name string `json:"name"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	MaxRequestBodySize int `json:"maxRequestBodySize,omitempty"`
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
 }
 
 // APIAccessRule describes an access rule for allowing a Dapr API to be enabled and accessible by an app.
@@ -75,11 +223,42 @@ type NameResolutionSpec struct {
 	Component     string       `json:"component"`
 	Version       string       `json:"version"`
 	Configuration DynamicValue `json:"configuration"`
+	// +optional
+	Clusters []ClusterSpec `json:"clusters,omitempty"`
+	// +optional
+	LoadBalancing *LoadBalancingSpec `json:"loadBalancing,omitempty"`
+}
+
+// LoadBalancingSpec configures the balancing of the service invocations between the instances of the apps.
+type LoadBalancingSpec struct {
+	// +optional
+	Policy string `json:"policy,omitempty"`
+	// +optional
+	LocalZone string `json:"localZone,omitempty"`
+	// +optional
+	Zones []ZoneSpec `json:"zones,omitempty"`
+}
+
+// ZoneSpec is a zone of the instances of the apps, identified by the subnets of their addresses.
+type ZoneSpec struct {
+	Name  string   `json:"name"`
+	CIDRs []string `json:"cidrs"`
+}
+
+// ClusterSpec is a remote cluster whose apps can be invoked.
+type ClusterSpec struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
 }
 
 // SecretsSpec is the spec for secrets configuration.
 type SecretsSpec struct {
-	Scopes []SecretsScope `json:"scopes"`
+	// +optional
+	Scopes []SecretsScope `json:"scopes,omitempty"`
+	// +optional
+	DisableBuiltinK8sSecretStore bool `json:"disableBuiltinK8sSecretStore,omitempty"`
+	// +optional
+	ResolutionChain []string `json:"resolutionChain,omitempty"`
 }
 
 // SecretsScope defines the scope for secrets.
@@ -112,6 +291,60 @@ type MTLSSpec struct {
 	WorkloadCertTTL string `json:"workloadCertTTL"`
 	// +optional
 	AllowedClockSkew string `json:"allowedClockSkew"`
+	// Additional token validators to use.
+	// When Dapr is running in Kubernetes mode, this is in addition to the built-in "kubernetes" validator.
+	// In self-hosted mode, enabling a custom validator will disable the built-in "insecure" validator.
+	// +optional
+	TokenValidators []ValidatorSpec `json:"tokenValidators,omitempty"`
+	// DNS names that the sidecars of each app ID can request in addition to the default ones on their workload certificates.
+	// +optional
+	AllowedDNSNames []AllowedDNSNamesSpec `json:"allowedDNSNames,omitempty"`
+	// Template of the SPIFFE IDs of the workload certificates, with the placeholders {trustDomain}, {namespace} and {appID}.
+	// Defaults to "spiffe://{trustDomain}/ns/{namespace}/{appID}". The access control policies read the namespace and the
+	// app ID from the third and fourth segments, so only the "ns" segment can be changed and more segments appended.
+	// +optional
+	SPIFFEIDTemplate string `json:"spiffeIDTemplate,omitempty"`
+	// Rate limits of the certificate signing requests of each app ID and namespace.
+	// +optional
+	CSRRateLimit *CSRRateLimitSpec `json:"csrRateLimit,omitempty"`
+}
+
+// AllowedDNSNamesSpec defines the DNS names that the sidecars of apps of a namespace can request on their workload certificates.
+type AllowedDNSNamesSpec struct {
+	// Namespace of the sidecars.
+	Namespace string `json:"namespace"`
+	// App IDs of the sidecars.
+	AppIDs []string `json:"appIDs"`
+	// DNS names that the sidecars can request. A name starting with "*." allows all the names with one more label,
+	// e.g. "*.example.com" allows "orders.example.com".
+	Names []string `json:"names"`
+}
+
+// CSRRateLimitSpec defines the rate limits of the certificate signing requests received by sentry.
+type CSRRateLimitSpec struct {
+	// Rate of the requests of each verified requester ID in a namespace, unlimited if 0.
+	// +optional
+	AppIDRequestsPerMinute int `json:"appIDRequestsPerMinute,omitempty"`
+	// Rate of the requests of each namespace, unlimited if 0.
+	// +optional
+	NamespaceRequestsPerMinute int `json:"namespaceRequestsPerMinute,omitempty"`
+	// Number of requests accepted at once, the rate per minute by default.
+	// +optional
+	Burst int `json:"burst,omitempty"`
+	// "warn" to only report the requests above the limits, or "reject" to reject them. Defaults to "warn".
+	// +kubebuilder:validation:Enum={"warn","reject"}
+	// +optional
+	Enforcement string `json:"enforcement,omitempty"`
+}
+
+// ValidatorSpec contains additional token validators to use.
+type ValidatorSpec struct {
+	// Name of the validator
+	// +kubebuilder:validation:Enum={"jwks"}
+	Name string `json:"name"`
+	// Options for the validator, if any
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
 }
 
 // SelectorSpec selects target services to which the handler is to be applied.
@@ -134,6 +367,10 @@ type TracingSpec struct {
 	Zipkin ZipkinSpec `json:"zipkin"`
 	// +optional
 	Otel OtelSpec `json:"otel"`
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// +optional
+	Propagators []string `json:"propagators,omitempty"`
 }
 
 // OtelSpec defines Otel exporter configurations.
@@ -141,6 +378,22 @@ type OtelSpec struct {
 	Protocol        string `json:"protocol" yaml:"protocol"`
 	EndpointAddress string `json:"endpointAddress" yaml:"endpointAddress"`
 	IsSecure        bool   `json:"isSecure" yaml:"isSecure"`
+	// +optional
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// +optional
+	TLS *OtelTLSSpec `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// OtelTLSSpec defines the TLS configuration of the connection to the Otel collector.
+type OtelTLSSpec struct {
+	// +optional
+	CAFile string `json:"caFile,omitempty" yaml:"caFile,omitempty"`
+	// +optional
+	CertFile string `json:"certFile,omitempty" yaml:"certFile,omitempty"`
+	// +optional
+	KeyFile string `json:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
 }
 
 // ZipkinSpec defines Zipkin trace configurations.
@@ -151,6 +404,35 @@ type ZipkinSpec struct {
 // MetricSpec defines metrics configuration.
 type MetricSpec struct {
 	Enabled bool `json:"enabled"`
+	// +optional
+	HTTP *MetricHTTP `json:"http,omitempty"`
+	// +optional
+	Otel *OtelSpec `json:"otel,omitempty"`
+	// +optional
+	Rules []MetricsRule `json:"rules,omitempty"`
+	// +optional
+	PayloadSchema bool `json:"payloadSchema,omitempty"`
+}
+
+// MetricsRule defines the rules applied to the labels of a metric.
+type MetricsRule struct {
+	Name   string        `json:"name"`
+	Labels []MetricLabel `json:"labels"`
+}
+
+// MetricLabel defines the rule applied to a label of a metric.
+type MetricLabel struct {
+	Name string `json:"name"`
+	// +optional
+	Regex map[string]string `json:"regex,omitempty"`
+	// +optional
+	Drop bool `json:"drop,omitempty"`
+}
+
+// MetricHTTP defines configuration for the HTTP metrics.
+type MetricHTTP struct {
+	// +optional
+	PathTemplates []string `json:"pathTemplates,omitempty"`
 }
 
 // AppPolicySpec defines the policy data structure for each app.
@@ -182,6 +464,42 @@ type AccessControlSpec struct {
 	TrustDomain string `json:"trustDomain" yaml:"trustDomain"`
 	// +optional
 	AppPolicies []AppPolicySpec `json:"policies" yaml:"policies"`
+	// +optional
+	CrossNamespace *CrossNamespaceSpec `json:"crossNamespace,omitempty" yaml:"crossNamespace,omitempty"`
+	// +optional
+	Outbound *OutboundSpec `json:"outbound,omitempty" yaml:"outbound,omitempty"`
+}
+
+// CrossNamespaceSpec is the policy of the service invocations from the apps of other namespaces.
+type CrossNamespaceSpec struct {
+	// +optional
+	AllowedCallers []CrossNamespaceCaller `json:"allowedCallers,omitempty" yaml:"allowedCallers,omitempty"`
+}
+
+// CrossNamespaceCaller is an app of another namespace allowed to invoke the apps of this namespace.
+type CrossNamespaceCaller struct {
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// +optional
+	AppID string `json:"appId,omitempty" yaml:"appId,omitempty"`
+	// +optional
+	Callees []string `json:"callees,omitempty" yaml:"callees,omitempty"`
+	// +optional
+	TrustDomain string `json:"trustDomain,omitempty" yaml:"trustDomain,omitempty"`
+}
+
+// OutboundSpec is the policy of the service invocations to the apps of other namespaces and clusters.
+type OutboundSpec struct {
+	// +optional
+	AllowedCallees []OutboundCallee `json:"allowedCallees,omitempty" yaml:"allowedCallees,omitempty"`
+}
+
+// OutboundCallee is an app of another namespace or cluster the apps are allowed to invoke.
+type OutboundCallee struct {
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// +optional
+	AppID string `json:"appId,omitempty" yaml:"appId,omitempty"`
+	// +optional
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
 }
 
 // FeatureSpec defines the features that are enabled/disabled.
@@ -195,6 +513,77 @@ type ComponentsSpec struct {
 	// Denylist of component types that cannot be instantiated
 	// +optional
 	Deny []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+	// Default initialization timeout of the components that don't set one
+	// +optional
+	InitTimeout string `json:"initTimeout,omitempty" yaml:"initTimeout,omitempty"`
+	// Initialize the components of different categories concurrently at startup, after the secret stores
+	// +optional
+	ParallelInit bool `json:"parallelInit,omitempty" yaml:"parallelInit,omitempty"`
+	// Names of the optional components, initialized without blocking the readiness of the sidecar and marked as degraded if they fail
+	// +optional
+	Optional []string `json:"optional,omitempty" yaml:"optional,omitempty"`
+	// Retries of the initialization of the components whose errors are ignored and of the optional components
+	// +optional
+	InitRetry ComponentInitRetrySpec `json:"initRetry,omitempty" yaml:"initRetry,omitempty"`
+}
+
+// ComponentInitRetrySpec configures the retries with exponential backoff of the failed initializations of the components.
+type ComponentInitRetrySpec struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Delay before the first retry, 1s by default
+	// +optional
+	InitialInterval string `json:"initialInterval,omitempty" yaml:"initialInterval,omitempty"`
+	// Maximum delay between the retries, 1m by default
+	// +optional
+	MaxInterval string `json:"maxInterval,omitempty" yaml:"maxInterval,omitempty"`
+	// Maximum number of retries; 0 means no limit
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+}
+
+// GRPCProxySpec describes the configuration for the gRPC proxy.
+type GRPCProxySpec struct {
+	// Policy for the metadata forwarded with proxied calls
+	// +optional
+	Metadata GRPCProxyMetadataSpec `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// Maximum size in bytes of the metadata of a proxied call; 0 means no limit
+	// +optional
+	MaxMetadataSize int `json:"maxMetadataSize,omitempty" yaml:"maxMetadataSize,omitempty"`
+}
+
+// GRPCProxyMetadataSpec controls which gRPC metadata keys are forwarded, stripped, or renamed by the gRPC proxy.
+type GRPCProxyMetadataSpec struct {
+	// Allowlist of metadata keys that are forwarded; if empty, all keys that are not denied are forwarded
+	// +optional
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	// Denylist of metadata keys that are stripped
+	// +optional
+	Deny []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+	// Metadata keys that are forwarded with a different name
+	// +optional
+	Rename []GRPCProxyMetadataRename `json:"rename,omitempty" yaml:"rename,omitempty"`
+}
+
+// GRPCProxyMetadataRename renames a metadata key forwarded by the gRPC proxy.
+type GRPCProxyMetadataRename struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// ActorsSpec describes the configuration for the actors runtime.
+type ActorsSpec struct {
+	// Publishing of the actor lifecycle events
+	// +optional
+	LifecycleEvents ActorLifecycleEventsSpec `json:"lifecycleEvents,omitempty" yaml:"lifecycleEvents,omitempty"`
+}
+
+// ActorLifecycleEventsSpec configures the topic where the actor lifecycle events are published.
+type ActorLifecycleEventsSpec struct {
+	// Name of the pubsub component; events are not published if empty
+	PubsubName string `json:"pubsubName" yaml:"pubsubName"`
+	// Topic where the events are published
+	// +optional
+	Topic string `json:"topic,omitempty" yaml:"topic,omitempty"`
 }
 
 // +kubebuilder:object:root=true
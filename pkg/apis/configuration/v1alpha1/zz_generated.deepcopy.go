@@ -48,6 +48,16 @@ func (in *APISpec) DeepCopyInto(out *APISpec) {
 		*out = make([]APIAccessRule, len(*in))
 		copy(*out, *in)
 	}
+	if in.Denied != nil {
+		in, out := &in.Denied, &out.Denied
+		*out = make([]APIAccessRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.HTTPRouteLimits != nil {
+		in, out := &in.HTTPRouteLimits, &out.HTTPRouteLimits
+		*out = make([]HTTPRouteLimit, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APISpec.
@@ -70,6 +80,16 @@ func (in *AccessControlSpec) DeepCopyInto(out *AccessControlSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CrossNamespace != nil {
+		in, out := &in.CrossNamespace, &out.CrossNamespace
+		*out = new(CrossNamespaceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Outbound != nil {
+		in, out := &in.Outbound, &out.Outbound
+		*out = new(OutboundSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessControlSpec.
@@ -82,6 +102,62 @@ func (in *AccessControlSpec) DeepCopy() *AccessControlSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActorLifecycleEventsSpec) DeepCopyInto(out *ActorLifecycleEventsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActorLifecycleEventsSpec.
+func (in *ActorLifecycleEventsSpec) DeepCopy() *ActorLifecycleEventsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActorLifecycleEventsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActorsSpec) DeepCopyInto(out *ActorsSpec) {
+	*out = *in
+	out.LifecycleEvents = in.LifecycleEvents
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActorsSpec.
+func (in *ActorsSpec) DeepCopy() *ActorsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActorsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedDNSNamesSpec) DeepCopyInto(out *AllowedDNSNamesSpec) {
+	*out = *in
+	if in.AppIDs != nil {
+		in, out := &in.AppIDs, &out.AppIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedDNSNamesSpec.
+func (in *AllowedDNSNamesSpec) DeepCopy() *AllowedDNSNamesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedDNSNamesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppOperationAction) DeepCopyInto(out *AppOperationAction) {
 	*out = *in
@@ -124,6 +200,170 @@ func (in *AppPolicySpec) DeepCopy() *AppPolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSpec) DeepCopyInto(out *AuditSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RedactedHeaders != nil {
+		in, out := &in.RedactedHeaders, &out.RedactedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSpec.
+func (in *AuditSpec) DeepCopy() *AuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CORSPolicy) DeepCopyInto(out *CORSPolicy) {
+	*out = *in
+	if in.AllowedOrigins != nil {
+		in, out := &in.AllowedOrigins, &out.AllowedOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedMethods != nil {
+		in, out := &in.AllowedMethods, &out.AllowedMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedHeaders != nil {
+		in, out := &in.AllowedHeaders, &out.AllowedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExposedHeaders != nil {
+		in, out := &in.ExposedHeaders, &out.ExposedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CORSPolicy.
+func (in *CORSPolicy) DeepCopy() *CORSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CORSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CORSRoutePolicy) DeepCopyInto(out *CORSRoutePolicy) {
+	*out = *in
+	in.CORSPolicy.DeepCopyInto(&out.CORSPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CORSRoutePolicy.
+func (in *CORSRoutePolicy) DeepCopy() *CORSRoutePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CORSRoutePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CORSSpec) DeepCopyInto(out *CORSSpec) {
+	*out = *in
+	in.CORSPolicy.DeepCopyInto(&out.CORSPolicy)
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]CORSRoutePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CORSSpec.
+func (in *CORSSpec) DeepCopy() *CORSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CORSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSRRateLimitSpec) DeepCopyInto(out *CSRRateLimitSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSRRateLimitSpec.
+func (in *CSRRateLimitSpec) DeepCopy() *CSRRateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CSRRateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
+func (in *ClusterSpec) DeepCopy() *ClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentInitRetrySpec) DeepCopyInto(out *ComponentInitRetrySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentInitRetrySpec.
+func (in *ComponentInitRetrySpec) DeepCopy() *ComponentInitRetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentInitRetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentProxySpec) DeepCopyInto(out *ComponentProxySpec) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentProxySpec.
+func (in *ComponentProxySpec) DeepCopy() *ComponentProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentsSpec) DeepCopyInto(out *ComponentsSpec) {
 	*out = *in
@@ -132,6 +372,12 @@ func (in *ComponentsSpec) DeepCopyInto(out *ComponentsSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.InitRetry = in.InitRetry
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentsSpec.
@@ -144,6 +390,43 @@ func (in *ComponentsSpec) DeepCopy() *ComponentsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompressionPolicy) DeepCopyInto(out *CompressionPolicy) {
+	*out = *in
+	if in.Encodings != nil {
+		in, out := &in.Encodings, &out.Encodings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompressionPolicy.
+func (in *CompressionPolicy) DeepCopy() *CompressionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CompressionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompressionSpec) DeepCopyInto(out *CompressionSpec) {
+	*out = *in
+	in.API.DeepCopyInto(&out.API)
+	in.AppChannel.DeepCopyInto(&out.AppChannel)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompressionSpec.
+func (in *CompressionSpec) DeepCopy() *CompressionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompressionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Configuration) DeepCopyInto(out *Configuration) {
 	*out = *in
@@ -206,9 +489,10 @@ func (in *ConfigurationList) DeepCopyObject() runtime.Object {
 func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	*out = *in
 	in.HTTPPipelineSpec.DeepCopyInto(&out.HTTPPipelineSpec)
-	out.TracingSpec = in.TracingSpec
-	out.MetricSpec = in.MetricSpec
-	out.MTLSSpec = in.MTLSSpec
+	in.AppHTTPPipelineSpec.DeepCopyInto(&out.AppHTTPPipelineSpec)
+	in.TracingSpec.DeepCopyInto(&out.TracingSpec)
+	in.MetricSpec.DeepCopyInto(&out.MetricSpec)
+	in.MTLSSpec.DeepCopyInto(&out.MTLSSpec)
 	in.Secrets.DeepCopyInto(&out.Secrets)
 	in.AccessControlSpec.DeepCopyInto(&out.AccessControlSpec)
 	in.NameResolutionSpec.DeepCopyInto(&out.NameResolutionSpec)
@@ -219,6 +503,14 @@ func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	}
 	in.APISpec.DeepCopyInto(&out.APISpec)
 	in.ComponentsSpec.DeepCopyInto(&out.ComponentsSpec)
+	in.GRPCProxySpec.DeepCopyInto(&out.GRPCProxySpec)
+	out.ActorsSpec = in.ActorsSpec
+	in.CORSSpec.DeepCopyInto(&out.CORSSpec)
+	in.AuditSpec.DeepCopyInto(&out.AuditSpec)
+	in.ProxySpec.DeepCopyInto(&out.ProxySpec)
+	in.CompressionSpec.DeepCopyInto(&out.CompressionSpec)
+	out.ProfilingSpec = in.ProfilingSpec
+	in.LoggingSpec.DeepCopyInto(&out.LoggingSpec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
@@ -231,6 +523,48 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrossNamespaceCaller) DeepCopyInto(out *CrossNamespaceCaller) {
+	*out = *in
+	if in.Callees != nil {
+		in, out := &in.Callees, &out.Callees
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossNamespaceCaller.
+func (in *CrossNamespaceCaller) DeepCopy() *CrossNamespaceCaller {
+	if in == nil {
+		return nil
+	}
+	out := new(CrossNamespaceCaller)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrossNamespaceSpec) DeepCopyInto(out *CrossNamespaceSpec) {
+	*out = *in
+	if in.AllowedCallers != nil {
+		in, out := &in.AllowedCallers, &out.AllowedCallers
+		*out = make([]CrossNamespaceCaller, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossNamespaceSpec.
+func (in *CrossNamespaceSpec) DeepCopy() *CrossNamespaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrossNamespaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicValue) DeepCopyInto(out *DynamicValue) {
 	*out = *in
@@ -262,6 +596,82 @@ func (in *FeatureSpec) DeepCopy() *FeatureSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCProxyMetadataRename) DeepCopyInto(out *GRPCProxyMetadataRename) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCProxyMetadataRename.
+func (in *GRPCProxyMetadataRename) DeepCopy() *GRPCProxyMetadataRename {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCProxyMetadataRename)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCProxyMetadataSpec) DeepCopyInto(out *GRPCProxyMetadataSpec) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rename != nil {
+		in, out := &in.Rename, &out.Rename
+		*out = make([]GRPCProxyMetadataRename, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCProxyMetadataSpec.
+func (in *GRPCProxyMetadataSpec) DeepCopy() *GRPCProxyMetadataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCProxyMetadataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCProxySpec) DeepCopyInto(out *GRPCProxySpec) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCProxySpec.
+func (in *GRPCProxySpec) DeepCopy() *GRPCProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteLimit) DeepCopyInto(out *HTTPRouteLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteLimit.
+func (in *HTTPRouteLimit) DeepCopy() *HTTPRouteLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HandlerSpec) DeepCopyInto(out *HandlerSpec) {
 	*out = *in
@@ -278,9 +688,72 @@ func (in *HandlerSpec) DeepCopy() *HandlerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancingSpec) DeepCopyInto(out *LoadBalancingSpec) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]ZoneSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancingSpec.
+func (in *LoadBalancingSpec) DeepCopy() *LoadBalancingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingSpec) DeepCopyInto(out *LoggingSpec) {
+	*out = *in
+	if in.Levels != nil {
+		in, out := &in.Levels, &out.Levels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingSpec.
+func (in *LoggingSpec) DeepCopy() *LoggingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MTLSSpec) DeepCopyInto(out *MTLSSpec) {
 	*out = *in
+	if in.TokenValidators != nil {
+		in, out := &in.TokenValidators, &out.TokenValidators
+		*out = make([]ValidatorSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllowedDNSNames != nil {
+		in, out := &in.AllowedDNSNames, &out.AllowedDNSNames
+		*out = make([]AllowedDNSNamesSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CSRRateLimit != nil {
+		in, out := &in.CSRRateLimit, &out.CSRRateLimit
+		*out = new(CSRRateLimitSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MTLSSpec.
@@ -293,9 +766,68 @@ func (in *MTLSSpec) DeepCopy() *MTLSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricHTTP) DeepCopyInto(out *MetricHTTP) {
+	*out = *in
+	if in.PathTemplates != nil {
+		in, out := &in.PathTemplates, &out.PathTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricHTTP.
+func (in *MetricHTTP) DeepCopy() *MetricHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricLabel) DeepCopyInto(out *MetricLabel) {
+	*out = *in
+	if in.Regex != nil {
+		in, out := &in.Regex, &out.Regex
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricLabel.
+func (in *MetricLabel) DeepCopy() *MetricLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
 	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(MetricHTTP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Otel != nil {
+		in, out := &in.Otel, &out.Otel
+		*out = new(OtelSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]MetricsRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricSpec.
@@ -308,10 +840,42 @@ func (in *MetricSpec) DeepCopy() *MetricSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsRule) DeepCopyInto(out *MetricsRule) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]MetricLabel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsRule.
+func (in *MetricsRule) DeepCopy() *MetricsRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NameResolutionSpec) DeepCopyInto(out *NameResolutionSpec) {
 	*out = *in
 	in.Configuration.DeepCopyInto(&out.Configuration)
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancing != nil {
+		in, out := &in.LoadBalancing, &out.LoadBalancing
+		*out = new(LoadBalancingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NameResolutionSpec.
@@ -327,6 +891,18 @@ func (in *NameResolutionSpec) DeepCopy() *NameResolutionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OtelSpec) DeepCopyInto(out *OtelSpec) {
 	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(OtelTLSSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtelSpec.
@@ -339,6 +915,56 @@ func (in *OtelSpec) DeepCopy() *OtelSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtelTLSSpec) DeepCopyInto(out *OtelTLSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtelTLSSpec.
+func (in *OtelTLSSpec) DeepCopy() *OtelTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OtelTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutboundCallee) DeepCopyInto(out *OutboundCallee) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutboundCallee.
+func (in *OutboundCallee) DeepCopy() *OutboundCallee {
+	if in == nil {
+		return nil
+	}
+	out := new(OutboundCallee)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutboundSpec) DeepCopyInto(out *OutboundSpec) {
+	*out = *in
+	if in.AllowedCallees != nil {
+		in, out := &in.AllowedCallees, &out.AllowedCallees
+		*out = make([]OutboundCallee, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutboundSpec.
+func (in *OutboundSpec) DeepCopy() *OutboundSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutboundSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 	*out = *in
@@ -361,6 +987,64 @@ func (in *PipelineSpec) DeepCopy() *PipelineSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfilingSpec) DeepCopyInto(out *ProfilingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfilingSpec.
+func (in *ProfilingSpec) DeepCopy() *ProfilingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfilingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyAuthSpec) DeepCopyInto(out *ProxyAuthSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyAuthSpec.
+func (in *ProxyAuthSpec) DeepCopy() *ProxyAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Auth = in.Auth
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentProxySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretsScope) DeepCopyInto(out *SecretsScope) {
 	*out = *in
@@ -396,6 +1080,11 @@ func (in *SecretsSpec) DeepCopyInto(out *SecretsSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResolutionChain != nil {
+		in, out := &in.ResolutionChain, &out.ResolutionChain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsSpec.
@@ -447,7 +1136,19 @@ func (in *SelectorSpec) DeepCopy() *SelectorSpec {
 func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
 	*out = *in
 	out.Zipkin = in.Zipkin
-	out.Otel = in.Otel
+	in.Otel.DeepCopyInto(&out.Otel)
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Propagators != nil {
+		in, out := &in.Propagators, &out.Propagators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingSpec.
@@ -460,6 +1161,28 @@ func (in *TracingSpec) DeepCopy() *TracingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidatorSpec) DeepCopyInto(out *ValidatorSpec) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidatorSpec.
+func (in *ValidatorSpec) DeepCopy() *ValidatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ZipkinSpec) DeepCopyInto(out *ZipkinSpec) {
 	*out = *in
@@ -474,3 +1197,23 @@ func (in *ZipkinSpec) DeepCopy() *ZipkinSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneSpec) DeepCopyInto(out *ZoneSpec) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneSpec.
+func (in *ZoneSpec) DeepCopy() *ZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
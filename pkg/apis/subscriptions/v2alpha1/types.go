@@ -61,6 +61,8 @@ type Routes struct {
 // a message to a specific path.
 type Rule struct {
 	// The optional CEL expression used to match the event.
+	// The expression can use the cloud event as `event` and
+	// the metadata of the message as `metadata`.
 	// If the match is not specified, then the route is considered
 	// the default. The rules are tested in the order specified,
 	// so they should be define from most-to-least specific.
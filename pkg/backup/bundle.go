@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup exports and imports the state of the Dapr control plane in encrypted bundles.
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BundleVersion is the version of the format of the bundles.
+	BundleVersion = 1
+
+	// Size of the AES-256 key used to encrypt the bundles.
+	keySize = 32
+)
+
+// Bundle contains the state of the control plane services.
+type Bundle struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Placement contains a snapshot of the placement raft state.
+	Placement []byte `json:"placement,omitempty"`
+	// Sentry contains the trust anchors and the issuer credentials of Sentry.
+	Sentry *IssuerMaterial `json:"sentry,omitempty"`
+}
+
+// IssuerMaterial contains the PEM encoded trust anchors and issuer credentials of Sentry.
+type IssuerMaterial struct {
+	RootCert   []byte `json:"rootCert"`
+	IssuerCert []byte `json:"issuerCert"`
+	IssuerKey  []byte `json:"issuerKey"`
+}
+
+// NewBundle returns a new empty bundle.
+func NewBundle() *Bundle {
+	return &Bundle{
+		Version:   BundleVersion,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Merge copies the parts of the other bundle that are set into the bundle.
+func (b *Bundle) Merge(other *Bundle) {
+	if other.Placement != nil {
+		b.Placement = other.Placement
+	}
+	if other.Sentry != nil {
+		b.Sentry = other.Sentry
+	}
+}
+
+// Seal encrypts the bundle with AES-256-GCM.
+func Seal(b *Bundle, key []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts a bundle encrypted with Seal.
+func Open(data []byte, key []byte) (*Bundle, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("invalid bundle: too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt bundle")
+	}
+
+	var b Bundle
+	if err = json.Unmarshal(plaintext, &b); err != nil {
+		return nil, errors.Wrap(err, "invalid bundle")
+	}
+	if b.Version != BundleVersion {
+		return nil, errors.Errorf("unsupported bundle version %d", b.Version)
+	}
+	return &b, nil
+}
+
+// LoadKey reads the base64 encoded 32 bytes key used to encrypt the bundles from a file.
+func LoadKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, errors.Wrap(err, "backup key must be base64 encoded")
+	}
+	if len(key) != keySize {
+		return nil, errors.Errorf("backup key must be %d bytes long, got %d", keySize, len(key))
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, errors.Errorf("backup key must be %d bytes long, got %d", keySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = bytes.Repeat([]byte{1}, keySize)
+
+func TestSealAndOpen(t *testing.T) {
+	b := NewBundle()
+	b.Placement = []byte("placement")
+	b.Sentry = &IssuerMaterial{
+		RootCert:   []byte("root"),
+		IssuerCert: []byte("cert"),
+		IssuerKey:  []byte("key"),
+	}
+
+	data, err := Seal(b, testKey)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "placement")
+
+	t.Run("open with the key", func(t *testing.T) {
+		opened, err := Open(data, testKey)
+		require.NoError(t, err)
+		assert.Equal(t, b.Placement, opened.Placement)
+		assert.Equal(t, b.Sentry, opened.Sentry)
+		assert.True(t, b.CreatedAt.Equal(opened.CreatedAt))
+	})
+
+	t.Run("open with another key", func(t *testing.T) {
+		_, err := Open(data, bytes.Repeat([]byte{2}, keySize))
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered bundle", func(t *testing.T) {
+		tampered := append([]byte{}, data...)
+		tampered[len(tampered)-1] ^= 1
+		_, err := Open(tampered, testKey)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		_, err := Seal(b, []byte("short"))
+		assert.Error(t, err)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	b := NewBundle()
+	b.Merge(&Bundle{Placement: []byte("placement")})
+	b.Merge(&Bundle{Sentry: &IssuerMaterial{RootCert: []byte("root")}})
+
+	assert.Equal(t, []byte("placement"), b.Placement)
+	assert.Equal(t, []byte("root"), b.Sentry.RootCert)
+}
+
+func TestLoadKey(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid key", func(t *testing.T) {
+		path := filepath.Join(dir, "valid")
+		require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(testKey)+"\n"), 0o600))
+
+		key, err := LoadKey(path)
+		require.NoError(t, err)
+		assert.Equal(t, testKey, key)
+	})
+
+	t.Run("not base64", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid")
+		require.NoError(t, os.WriteFile(path, []byte("not base64!"), 0o600))
+
+		_, err := LoadKey(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong size", func(t *testing.T) {
+		path := filepath.Join(dir, "short")
+		require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0o600))
+
+		_, err := LoadKey(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadKey(filepath.Join(dir, "missing"))
+		assert.Error(t, err)
+	})
+}
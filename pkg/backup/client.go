@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/runtime/security"
+)
+
+// NewClient returns the HTTP client of the admin APIs, authenticated with the cert chain of a control plane service.
+func NewClient(certChain *credentials.CertChain, timeout time.Duration) (*http.Client, error) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(certChain.RootCA) {
+		return nil, errors.New("failed to append PEM root cert to x509 CertPool")
+	}
+	config, err := credentials.TLSConfigFromCertAndKey(certChain.Cert, certChain.Key, security.TLSServerName, roots)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tls config from cert and key")
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: config},
+	}, nil
+}
+
+// Fetch exports the state of the service with the admin APIs at the address, and decrypts the bundle.
+func Fetch(ctx context.Context, client *http.Client, address string, key []byte) (*Bundle, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(address, "/")+BackupPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxBundleSize))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("backup from %s failed with status code %d: %s", address, res.StatusCode, bytes.TrimSpace(data))
+	}
+
+	return Open(data, key)
+}
+
+// Push imports an encrypted bundle in the service with the admin APIs at the address.
+func Push(ctx context.Context, client *http.Client, address string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(address, "/")+RestorePath, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.Errorf("restore to %s failed with status code %d: %s", address, res.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
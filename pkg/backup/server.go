@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/runtime/security"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// BackupPath is the path of the admin API that exports the state of a service in an encrypted bundle.
+	BackupPath = "/v1.0/backup"
+	// RestorePath is the path of the admin API that imports the state of a service from an encrypted bundle.
+	RestorePath = "/v1.0/restore"
+
+	// ContentType is the content type of the encrypted bundles.
+	ContentType = "application/octet-stream"
+
+	maxBundleSize = 64 << 20
+)
+
+// ErrNotInBundle is returned by Source.Restore when the bundle doesn't contain the state of the service.
+var ErrNotInBundle = errors.New("the bundle doesn't contain the state of the service")
+
+// Source is a control plane service whose state can be exported and imported.
+type Source interface {
+	// Backup adds the state of the service to the bundle.
+	Backup(b *Bundle) error
+	// Restore replaces the state of the service with the one in the bundle.
+	Restore(b *Bundle) error
+}
+
+// Server serves the admin APIs to export and import the state of a control plane service.
+type Server struct {
+	source Source
+	key    []byte
+	log    logger.Logger
+}
+
+// NewServer returns a new admin server for the source, with the key used to encrypt the bundles.
+func NewServer(source Source, key []byte, log logger.Logger) *Server {
+	return &Server{
+		source: source,
+		key:    key,
+		log:    log,
+	}
+}
+
+// Handler returns the handler of the admin APIs,
+// which are only served to the control plane services authenticated with mTLS.
+func (s *Server) Handler() http.Handler {
+	router := http.NewServeMux()
+	router.HandleFunc(BackupPath, s.onBackup)
+	router.HandleFunc(RestorePath, s.onRestore)
+	return s.authorize(router)
+}
+
+// Run starts a net/http server with the admin APIs, serving the cert chain of the certs with mTLS.
+// This method blocks until the context is canceled.
+func (s *Server) Run(ctx context.Context, port int, certs *credentials.CertChainReloader) error {
+	if certs == nil {
+		return errors.New("the admin APIs require mTLS")
+	}
+
+	//nolint:gosec
+	srv := &http.Server{
+		Addr:      fmt.Sprintf(":%d", port),
+		Handler:   s.Handler(),
+		TLSConfig: certs.TLSConfig(),
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.log.Info("Admin server is shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			s.log.Errorf("Error while shutting down admin server: %v", err)
+		}
+	}()
+
+	s.log.Infof("Admin server is listening on %s", srv.Addr)
+
+	// The certificates are served by the TLS config
+	err := srv.ListenAndServeTLS("", "")
+	if err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// authorize rejects the callers that aren't control plane services:
+// the backup bundle contains the issuer key, so the sidecars must not be able to export it with their workload certificate.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 ||
+			!credentials.IsControlPlaneCertificate(r.TLS.VerifiedChains[0][0], security.TLSServerName) {
+			s.log.Warnf("rejected admin API call from %s: the caller isn't a control plane service", r.RemoteAddr)
+			http.Error(w, "the admin APIs are only available to the control plane services", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) onBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	b := NewBundle()
+	if err := s.source.Backup(b); err != nil {
+		s.log.Errorf("failed to back up state: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := Seal(b, s.key)
+	if err != nil {
+		s.log.Errorf("failed to encrypt backup bundle: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.log.Info("state exported to a backup bundle")
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (s *Server) onRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBundleSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The bundle is authenticated, so only the holders of the key can restore it
+	b, err := Open(data, s.key)
+	if err != nil {
+		s.log.Warnf("rejected backup bundle: %s", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.source.Restore(b)
+	if errors.Is(err, ErrNotInBundle) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		s.log.Errorf("failed to restore state: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.log.Infof("state restored from a backup bundle created at %s", b.CreatedAt)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/runtime/security"
+	daprt "github.com/dapr/dapr/pkg/testing"
+	"github.com/dapr/kit/logger"
+)
+
+type fakeSource struct {
+	placement []byte
+}
+
+func (s *fakeSource) Backup(b *Bundle) error {
+	b.Placement = s.placement
+	return nil
+}
+
+func (s *fakeSource) Restore(b *Bundle) error {
+	if b.Placement == nil {
+		return ErrNotInBundle
+	}
+	s.placement = b.Placement
+	return nil
+}
+
+// newTestServer starts the admin APIs with mTLS, and returns the clients of a control plane service and of a sidecar.
+func newTestServer(t *testing.T, source Source) (srv *httptest.Server, client, workloadClient *http.Client) {
+	t.Helper()
+
+	controlPlane, workload := daprt.GenerateCertChains(t, security.TLSServerName, "spiffe://cluster.local/ns/default/app")
+	certs, err := credentials.NewCertChainReloader(credentials.NewTLSCredentials(t.TempDir()), controlPlane)
+	require.NoError(t, err)
+
+	srv = httptest.NewUnstartedServer(NewServer(source, testKey, logger.NewLogger("dapr.test")).Handler())
+	srv.TLS = certs.TLSConfig()
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	client, err = NewClient(controlPlane, time.Second)
+	require.NoError(t, err)
+	workloadClient, err = NewClient(workload, time.Second)
+	require.NoError(t, err)
+	return srv, client, workloadClient
+}
+
+func TestServer(t *testing.T) {
+	source := &fakeSource{placement: []byte("state")}
+	srv, client, workloadClient := newTestServer(t, source)
+
+	ctx := context.Background()
+
+	t.Run("the sidecars are not authorized", func(t *testing.T) {
+		_, err := Fetch(ctx, workloadClient, srv.URL, testKey)
+		assert.ErrorContains(t, err, "status code 403")
+
+		data, err := Seal(NewBundle(), testKey)
+		require.NoError(t, err)
+		assert.ErrorContains(t, Push(ctx, workloadClient, srv.URL, data), "status code 403")
+	})
+
+	t.Run("the callers without a client certificate are rejected", func(t *testing.T) {
+		//nolint:gosec
+		noCert := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+		_, err := Fetch(ctx, noCert, srv.URL, testKey)
+		assert.Error(t, err)
+	})
+
+	t.Run("mTLS is required", func(t *testing.T) {
+		assert.Error(t, NewServer(source, testKey, logger.NewLogger("dapr.test")).Run(ctx, 0, nil))
+	})
+
+	t.Run("backup", func(t *testing.T) {
+		b, err := Fetch(ctx, client, srv.URL, testKey)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("state"), b.Placement)
+	})
+
+	t.Run("backup with another key", func(t *testing.T) {
+		_, err := Fetch(ctx, client, srv.URL, bytes.Repeat([]byte{2}, keySize))
+		assert.Error(t, err)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		b := NewBundle()
+		b.Placement = []byte("restored")
+		data, err := Seal(b, testKey)
+		require.NoError(t, err)
+
+		require.NoError(t, Push(ctx, client, srv.URL, data))
+		assert.Equal(t, []byte("restored"), source.placement)
+	})
+
+	t.Run("restore bundle encrypted with another key", func(t *testing.T) {
+		b := NewBundle()
+		b.Placement = []byte("forged")
+		data, err := Seal(b, bytes.Repeat([]byte{2}, keySize))
+		require.NoError(t, err)
+
+		assert.Error(t, Push(ctx, client, srv.URL, data))
+		assert.Equal(t, []byte("restored"), source.placement)
+	})
+
+	t.Run("restore bundle without the state of the service", func(t *testing.T) {
+		data, err := Seal(NewBundle(), testKey)
+		require.NoError(t, err)
+
+		res, err := client.Post(srv.URL+RestorePath, ContentType, bytes.NewReader(data))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		res, err := client.Post(srv.URL+BackupPath, ContentType, nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	})
+}
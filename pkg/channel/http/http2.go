@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http2"
+)
+
+// HTTP2Mode is the use of HTTP/2 to talk to the app.
+type HTTP2Mode string
+
+const (
+	// HTTP2Disabled talks to the app with HTTP/1.1.
+	HTTP2Disabled HTTP2Mode = ""
+	// HTTP2Enabled talks to the app with HTTP/2, over cleartext (h2c) with prior knowledge or over TLS when app SSL is enabled.
+	HTTP2Enabled HTTP2Mode = "enabled"
+	// HTTP2Auto talks to the app with HTTP/2 if it supports it, and with HTTP/1.1 otherwise.
+	// The support is negotiated with ALPN over TLS, and detected with the HTTP/2 connection preface over cleartext.
+	HTTP2Auto HTTP2Mode = "auto"
+
+	http2DetectionTimeout = 5 * time.Second
+)
+
+// httpClient sends the requests to the app.
+type httpClient interface {
+	Do(req *fasthttp.Request, resp *fasthttp.Response) error
+}
+
+// SetHTTP2 sets the use of HTTP/2 to talk to the app, and returns true if HTTP/2 is used.
+// With HTTP2Auto, the support of HTTP/2 is detected once, so the app must be listening;
+// HTTP/1.1 is used if the detection fails.
+func (h *Channel) SetHTTP2(mode HTTP2Mode) (bool, error) {
+	var err error
+	enabled := mode == HTTP2Enabled
+	if mode == HTTP2Auto {
+		enabled, err = supportsHTTP2(h.dial, h.baseAddress, h.sslEnabled)
+	}
+	if enabled {
+		h.client = newHTTP2Client(h.dial, h.sslEnabled, h.maxResponseBodySize)
+	}
+	return enabled, err
+}
+
+// http2Client sends the fasthttp requests to the app over HTTP/2.
+type http2Client struct {
+	client              *nethttp.Client
+	maxResponseBodySize int
+}
+
+//nolint:gosec
+func newHTTP2Client(dial fasthttp.DialFunc, sslEnabled bool, maxResponseBodySize int) *http2Client {
+	return &http2Client{
+		client: &nethttp.Client{
+			Transport: &http2.Transport{
+				AllowHTTP:       !sslEnabled,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+				DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+					conn, err := dial(addr)
+					if err != nil || !sslEnabled {
+						return conn, err
+					}
+					tlsConn := tls.Client(conn, cfg)
+					if err = tlsConn.Handshake(); err != nil {
+						conn.Close()
+						return nil, err
+					}
+					return tlsConn, nil
+				},
+			},
+			// Redirects are returned to the caller, like with HTTP/1.1.
+			CheckRedirect: func(*nethttp.Request, []*nethttp.Request) error {
+				return nethttp.ErrUseLastResponse
+			},
+		},
+		maxResponseBodySize: maxResponseBodySize * 1024 * 1024,
+	}
+}
+
+func (c *http2Client) Do(req *fasthttp.Request, resp *fasthttp.Response) error {
+	httpReq, err := toHTTPRequest(req)
+	if err != nil {
+		return err
+	}
+
+	// The informational responses are recorded like over HTTP/1.1.
+	var interim []string
+	httpReq = httpReq.WithContext(httptrace.WithClientTrace(httpReq.Context(), &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			// 100 Continue is only meaningful between the app and Dapr.
+			if code != fasthttp.StatusContinue {
+				interim = append(interim, strconv.Itoa(code)+" "+url.Values(header).Encode())
+			}
+			return nil
+		},
+	}))
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	var body io.Reader = httpResp.Body
+	if c.maxResponseBodySize > 0 {
+		body = io.LimitReader(httpResp.Body, int64(c.maxResponseBodySize)+1)
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if c.maxResponseBodySize > 0 && len(b) > c.maxResponseBodySize {
+		return fasthttp.ErrBodyTooLarge
+	}
+
+	resp.Reset()
+	resp.SetStatusCode(httpResp.StatusCode)
+	for key, values := range httpResp.Header {
+		for _, value := range values {
+			resp.Header.Add(key, value)
+		}
+	}
+	resp.SetBody(b)
+	// The trailers are set once the body is read.
+	for key, values := range httpResp.Trailer {
+		if len(values) == 0 || resp.Header.AddTrailer(key) != nil {
+			continue
+		}
+		resp.Header.Set(key, values[0])
+	}
+	for _, val := range interim {
+		resp.Header.Add(interimResponseHeader, val)
+	}
+	return nil
+}
+
+// toHTTPRequest converts the fasthttp request to a net/http request, with its trailers.
+func toHTTPRequest(req *fasthttp.Request) (*nethttp.Request, error) {
+	httpReq, err := nethttp.NewRequest(string(req.Header.Method()), req.URI().String(), bytes.NewReader(req.Body()))
+	if err != nil {
+		return nil, err
+	}
+
+	declared := map[string]struct{}{}
+	req.Header.VisitAllTrailer(func(value []byte) {
+		declared[textproto.CanonicalMIMEHeaderKey(string(value))] = struct{}{}
+	})
+	req.Header.VisitAll(func(k, v []byte) {
+		key := textproto.CanonicalMIMEHeaderKey(string(k))
+		if _, ok := declared[key]; ok {
+			if httpReq.Trailer == nil {
+				httpReq.Trailer = nethttp.Header{}
+			}
+			httpReq.Trailer.Add(key, string(v))
+			return
+		}
+		switch key {
+		case fasthttp.HeaderHost, fasthttp.HeaderContentLength, fasthttp.HeaderTrailer, fasthttp.HeaderConnection, fasthttp.HeaderTransferEncoding:
+			return
+		}
+		httpReq.Header.Add(key, string(v))
+	})
+	if host := req.Header.Host(); len(host) > 0 {
+		httpReq.Host = string(host)
+	}
+	if httpReq.Trailer != nil {
+		// The trailers are only sent after a body of unknown length.
+		httpReq.ContentLength = -1
+	}
+	return httpReq, nil
+}
+
+// supportsHTTP2 returns true if the app supports HTTP/2.
+// Over TLS, HTTP/2 is negotiated with ALPN. Over cleartext, the app must answer the HTTP/2 connection preface with its settings.
+func supportsHTTP2(dial fasthttp.DialFunc, baseAddress string, sslEnabled bool) (bool, error) {
+	u, err := url.Parse(baseAddress)
+	if err != nil {
+		return false, err
+	}
+	conn, err := dial(u.Host)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(http2DetectionTimeout)); err != nil {
+		return false, err
+	}
+
+	if sslEnabled {
+		//nolint:gosec
+		tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true, NextProtos: []string{http2.NextProtoTLS, "http/1.1"}})
+		if err = tlsConn.Handshake(); err != nil {
+			return false, err
+		}
+		return tlsConn.ConnectionState().NegotiatedProtocol == http2.NextProtoTLS, nil
+	}
+
+	if _, err = io.WriteString(conn, http2.ClientPreface); err != nil {
+		return false, err
+	}
+	framer := http2.NewFramer(conn, conn)
+	if err = framer.WriteSettings(); err != nil {
+		return false, err
+	}
+	// An HTTP/1.1 server answers with an error response, which is not a valid frame.
+	frame, err := framer.ReadFrame()
+	if err != nil {
+		//nolint:nilerr
+		return false, nil
+	}
+	_, ok := frame.(*http2.SettingsFrame)
+	return ok, nil
+}
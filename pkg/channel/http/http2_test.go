@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+)
+
+type testProtoHandler struct{}
+
+func (t *testProtoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	io.ReadAll(r.Body)
+	w.Write([]byte(r.Proto))
+}
+
+func newTestHTTP2Channel(url string) *Channel {
+	return &Channel{baseAddress: url, client: &fasthttp.Client{Dial: dialInterimConn}, dial: fasthttp.Dial}
+}
+
+func TestSetHTTP2(t *testing.T) {
+	ctx := context.Background()
+	h2cServer := httptest.NewServer(h2c.NewHandler(&testProtoHandler{}, &http2.Server{}))
+	defer h2cServer.Close()
+	http1Server := httptest.NewServer(&testProtoHandler{})
+	defer http1Server.Close()
+
+	invoke := func(t *testing.T, c *Channel) string {
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithHTTPExtension(http.MethodPost, "")
+		req.WithRawData([]byte("request"), "text/plain")
+		response, err := c.InvokeMethod(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, int32(200), response.Status().Code)
+		_, body := response.RawData()
+		return string(body)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		c := newTestHTTP2Channel(h2cServer.URL)
+		enabled, err := c.SetHTTP2(HTTP2Disabled)
+		require.NoError(t, err)
+		assert.False(t, enabled)
+		assert.Equal(t, "HTTP/1.1", invoke(t, c))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		c := newTestHTTP2Channel(h2cServer.URL)
+		enabled, err := c.SetHTTP2(HTTP2Enabled)
+		require.NoError(t, err)
+		assert.True(t, enabled)
+		assert.Equal(t, "HTTP/2.0", invoke(t, c))
+	})
+
+	t.Run("auto with an h2c app", func(t *testing.T) {
+		c := newTestHTTP2Channel(h2cServer.URL)
+		enabled, err := c.SetHTTP2(HTTP2Auto)
+		require.NoError(t, err)
+		assert.True(t, enabled)
+		assert.Equal(t, "HTTP/2.0", invoke(t, c))
+	})
+
+	t.Run("auto with an HTTP/1.1 app", func(t *testing.T) {
+		c := newTestHTTP2Channel(http1Server.URL)
+		enabled, err := c.SetHTTP2(HTTP2Auto)
+		require.NoError(t, err)
+		assert.False(t, enabled)
+		assert.Equal(t, "HTTP/1.1", invoke(t, c))
+	})
+
+	t.Run("auto with a TLS app", func(t *testing.T) {
+		tlsServer := httptest.NewUnstartedServer(&testProtoHandler{})
+		tlsServer.EnableHTTP2 = true
+		tlsServer.StartTLS()
+		defer tlsServer.Close()
+
+		c := newTestHTTP2Channel(tlsServer.URL)
+		c.sslEnabled = true
+		enabled, err := c.SetHTTP2(HTTP2Auto)
+		require.NoError(t, err)
+		assert.True(t, enabled)
+		assert.Equal(t, "HTTP/2.0", invoke(t, c))
+	})
+
+	t.Run("auto without app", func(t *testing.T) {
+		c := newTestHTTP2Channel("http://127.0.0.1:1")
+		enabled, err := c.SetHTTP2(HTTP2Auto)
+		assert.Error(t, err)
+		assert.False(t, enabled)
+	})
+}
+
+func TestHTTP2WithTrailersAndInterimResponses(t *testing.T) {
+	testServer := httptest.NewServer(h2c.NewHandler(&testTrailersHandler{}, &http2.Server{}))
+	defer testServer.Close()
+	c := newTestHTTP2Channel(testServer.URL)
+	_, err := c.SetHTTP2(HTTP2Enabled)
+	require.NoError(t, err)
+
+	req := invokev1.NewInvokeMethodRequest("method")
+	req.WithHTTPExtension(http.MethodPost, "")
+	req.WithRawData([]byte("request"), "text/plain")
+	req.WithTrailers(map[string][]string{"X-Checksum": {"abc"}})
+
+	response, err := c.InvokeMethod(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(200), response.Status().Code)
+	_, body := response.RawData()
+	assert.Equal(t, "body", string(body))
+
+	assert.Equal(t, []string{"abc"}, response.Trailers()["X-Checksum"].GetValues())
+	assert.NotContains(t, response.Headers(), "X-Checksum")
+
+	interim := response.InterimResponses()
+	if assert.Len(t, interim, 1) {
+		assert.Equal(t, int32(http.StatusEarlyHints), interim[0].Code)
+		assert.Equal(t, []string{"</style.css>; rel=preload"}, interim[0].Headers["Link"].GetValues())
+	}
+}
+
+func TestHTTP2MaxResponseBodySize(t *testing.T) {
+	testServer := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 2*1024*1024))
+	}), &http2.Server{}))
+	defer testServer.Close()
+	c := newTestHTTP2Channel(testServer.URL)
+	c.maxResponseBodySize = 1
+	_, err := c.SetHTTP2(HTTP2Enabled)
+	require.NoError(t, err)
+
+	req := invokev1.NewInvokeMethodRequest("method")
+	req.WithHTTPExtension(http.MethodGet, "")
+	_, err = c.InvokeMethod(context.Background(), req)
+	assert.Error(t, err)
+}
@@ -14,10 +14,12 @@ limitations under the License.
 package http
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -30,11 +32,13 @@ import (
 
 	"github.com/dapr/dapr/pkg/apphealth"
 	"github.com/dapr/dapr/pkg/channel"
+	"github.com/dapr/dapr/pkg/compression"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"github.com/dapr/dapr/pkg/messages"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	httpMiddleware "github.com/dapr/dapr/pkg/middleware/http"
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	auth "github.com/dapr/dapr/pkg/runtime/security"
@@ -51,7 +55,9 @@ const (
 
 // Channel is an HTTP implementation of an AppChannel.
 type Channel struct {
-	client              *fasthttp.Client
+	client              httpClient
+	dial                fasthttp.DialFunc
+	sslEnabled          bool
 	baseAddress         string
 	ch                  chan struct{}
 	tracingSpec         config.TracingSpec
@@ -59,6 +65,8 @@ type Channel struct {
 	maxResponseBodySize int
 	appHealthCheckPath  string
 	appHealth           *apphealth.AppHealth
+	pipeline            httpMiddleware.Pipeline
+	compression         *compression.Policy
 }
 
 // CreateLocalChannel creates an HTTP AppChannel
@@ -70,14 +78,17 @@ func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEn
 		scheme = httpsScheme
 	}
 
+	client := &fasthttp.Client{
+		MaxConnsPerHost:           1000000,
+		MaxIdemponentCallAttempts: 0,
+		MaxResponseBodySize:       maxRequestBodySize * 1024 * 1024,
+		ReadBufferSize:            readBufferSize * 1024,
+		DisablePathNormalizing:    true,
+	}
 	c := &Channel{
-		client: &fasthttp.Client{
-			MaxConnsPerHost:           1000000,
-			MaxIdemponentCallAttempts: 0,
-			MaxResponseBodySize:       maxRequestBodySize * 1024 * 1024,
-			ReadBufferSize:            readBufferSize * 1024,
-			DisablePathNormalizing:    true,
-		},
+		client:              client,
+		dial:                fasthttp.Dial,
+		sslEnabled:          sslEnabled,
 		baseAddress:         fmt.Sprintf("%s://%s:%d", scheme, channel.DefaultChannelAddress, port),
 		tracingSpec:         spec,
 		appHeaderToken:      auth.GetAppToken(),
@@ -85,7 +96,9 @@ func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEn
 	}
 
 	if sslEnabled {
-		c.client.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+		client.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	} else {
+		client.Dial = dialInterimConn
 	}
 
 	if maxConcurrency > 0 {
@@ -95,6 +108,29 @@ func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEn
 	return c, nil
 }
 
+// CreateLocalChannelOverUnixDomainSocket creates an HTTP AppChannel connected to the app listening on the Unix Domain Socket.
+func CreateLocalChannelOverUnixDomainSocket(socket string, maxConcurrency int, spec config.TracingSpec, maxRequestBodySize int, readBufferSize int) (channel.AppChannel, error) {
+	ch, err := CreateLocalChannel(0, maxConcurrency, spec, false, maxRequestBodySize, readBufferSize)
+	if err != nil {
+		return nil, err
+	}
+
+	c := ch.(*Channel)
+	// The address only sets the host of the requests, the connections are made to the socket.
+	c.baseAddress = fmt.Sprintf("%s://%s", httpScheme, channel.DefaultChannelAddress)
+	c.dial = func(string) (net.Conn, error) {
+		return net.Dial("unix", socket)
+	}
+	c.client.(*fasthttp.Client).Dial = func(addr string) (net.Conn, error) {
+		conn, err := c.dial(addr)
+		if err != nil {
+			return nil, err
+		}
+		return newInterimConn(conn), nil
+	}
+	return c, nil
+}
+
 // GetBaseAddress returns the application base address.
 func (h *Channel) GetBaseAddress() string {
 	return h.baseAddress
@@ -176,6 +212,16 @@ func (h *Channel) SetAppHealthCheckPath(path string) {
 	h.appHealthCheckPath = "/" + strings.TrimPrefix(path, "/")
 }
 
+// SetPipeline sets the middleware pipeline applied to the requests sent to the app and to their responses.
+func (h *Channel) SetPipeline(pipeline httpMiddleware.Pipeline) {
+	h.pipeline = pipeline
+}
+
+// SetCompression asks the app for responses compressed with the encodings of the policy, which are decompressed.
+func (h *Channel) SetCompression(policy *compression.Policy) {
+	h.compression = policy
+}
+
 // SetAppHealth sets the apphealth.AppHealth object.
 func (h *Channel) SetAppHealth(ah *apphealth.AppHealth) {
 	h.appHealth = ah
@@ -237,7 +283,12 @@ func (h *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 	// Send request to user application
 	resp := fasthttp.AcquireResponse()
 
-	err := h.client.Do(channelReq, resp)
+	var err error
+	if len(h.pipeline.Handlers) > 0 {
+		err = h.doWithPipeline(channelReq, resp)
+	} else {
+		err = h.client.Do(channelReq, resp)
+	}
 	defer func() {
 		fasthttp.ReleaseRequest(channelReq)
 		fasthttp.ReleaseResponse(resp)
@@ -249,6 +300,11 @@ func (h *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 		diag.DefaultHTTPMonitoring.ClientRequestCompleted(ctx, verb, req.Message().GetMethod(), strconv.Itoa(nethttp.StatusInternalServerError), int64(resp.Header.ContentLength()), elapsedMs)
 		return nil, err
 	}
+	if h.compression != nil {
+		if err = h.decompressResponse(resp); err != nil {
+			return nil, err
+		}
+	}
 
 	rsp := h.parseChannelResponse(req, resp)
 	diag.DefaultHTTPMonitoring.ClientRequestCompleted(ctx, verb, req.Message().GetMethod(), strconv.Itoa(int(rsp.Status().Code)), int64(resp.Header.ContentLength()), elapsedMs)
@@ -256,6 +312,28 @@ func (h *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 	return rsp, nil
 }
 
+// doWithPipeline sends the request to the app through the middleware pipeline, which can transform the request,
+// the response or answer the request itself.
+func (h *Channel) doWithPipeline(req *fasthttp.Request, resp *fasthttp.Response) error {
+	// The middleware work on the body bytes, the body is streamed again when it's followed by trailers.
+	streamed := req.IsBodyStream()
+	req.Body()
+
+	ctx := &fasthttp.RequestCtx{}
+	req.CopyTo(&ctx.Request)
+
+	var err error
+	h.pipeline.Apply(func(ctx *fasthttp.RequestCtx) {
+		if streamed {
+			ctx.Request.SetBodyStream(bytes.NewReader(append([]byte(nil), ctx.Request.Body()...)), -1)
+		}
+		err = h.client.Do(&ctx.Request, &ctx.Response)
+	})(ctx)
+
+	ctx.Response.CopyTo(resp)
+	return err
+}
+
 func (h *Channel) constructRequest(ctx context.Context, req *invokev1.InvokeMethodRequest) *fasthttp.Request {
 	channelReq := fasthttp.AcquireRequest()
 
@@ -283,19 +361,47 @@ func (h *Channel) constructRequest(ctx context.Context, req *invokev1.InvokeMeth
 	if ts != "" {
 		channelReq.Header.Set("tracestate", ts)
 	}
+	// The app may be instrumented with other propagation formats, like during a migration.
+	diag.SpanContextToHTTPHeaders(span.SpanContext(), channelReq.Header.Set)
 
 	if h.appHeaderToken != "" {
 		channelReq.Header.Set(auth.APITokenHeader, h.appHeaderToken)
 	}
 
+	if h.compression != nil {
+		channelReq.Header.Set(fasthttp.HeaderAcceptEncoding, h.compression.AcceptEncoding())
+	}
+
 	// Set Content body and types
 	contentType, body := req.RawData()
 	channelReq.Header.SetContentType(contentType)
-	channelReq.SetBody(body)
+
+	// Trailers are only sent after a chunked body.
+	if invokev1.InternalMetadataToHTTPTrailers(req.Trailers(), &channelReq.Header) {
+		channelReq.SetBodyStream(bytes.NewReader(body), -1)
+	} else {
+		channelReq.SetBody(body)
+	}
 
 	return channelReq
 }
 
+// decompressResponse replaces the body of a compressed response with the decompressed body.
+// The responses with an unsupported encoding are left as they are.
+func (h *Channel) decompressResponse(resp *fasthttp.Response) error {
+	encoding := string(resp.Header.Peek(fasthttp.HeaderContentEncoding))
+	if encoding == "" || !compression.IsSupported(encoding) {
+		return nil
+	}
+	body, err := compression.Decompress(encoding, resp.Body(), h.maxResponseBodySize*1024*1024)
+	if err != nil {
+		return fmt.Errorf("failed to decompress the response of the app: %w", err)
+	}
+	resp.SetBody(body)
+	resp.Header.Del(fasthttp.HeaderContentEncoding)
+	return nil
+}
+
 func (h *Channel) parseChannelResponse(req *invokev1.InvokeMethodRequest, resp *fasthttp.Response) *invokev1.InvokeMethodResponse {
 	var statusCode int
 	var contentType string
@@ -312,6 +418,9 @@ func (h *Channel) parseChannelResponse(req *invokev1.InvokeMethodRequest, resp *
 
 	// Convert status code
 	rsp := invokev1.NewInvokeMethodResponse(int32(statusCode), "", nil)
+	for _, interim := range parseInterimResponses(&resp.Header) {
+		rsp.WithInterimResponse(int32(interim.code), interim.headers)
+	}
 	rsp.WithFastHTTPHeaders(&resp.Header).WithRawData(body, contentType)
 
 	return rsp
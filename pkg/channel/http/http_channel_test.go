@@ -17,18 +17,24 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 	"go.uber.org/atomic"
 
+	"github.com/dapr/dapr/pkg/compression"
 	"github.com/dapr/dapr/pkg/config"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	httpMiddleware "github.com/dapr/dapr/pkg/middleware/http"
 )
 
 // testConcurrencyHandler is used for testing max concurrency.
@@ -249,6 +255,62 @@ func TestInvokeMethodMaxConcurrency(t *testing.T) {
 	})
 }
 
+// testTrailersHandler sends an early hints response, and a response with the request trailer as trailer.
+type testTrailersHandler struct{}
+
+func (t *testTrailersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	io.ReadAll(r.Body)
+	w.Header().Set("Link", "</style.css>; rel=preload")
+	w.WriteHeader(http.StatusEarlyHints)
+
+	w.Header().Del("Link")
+	w.Header().Set("Trailer", "X-Checksum")
+	io.WriteString(w, "body")
+	w.Header().Set("X-Checksum", r.Trailer.Get("X-Checksum"))
+}
+
+func TestInvokeWithTrailersAndInterimResponses(t *testing.T) {
+	ctx := context.Background()
+	testServer := httptest.NewServer(&testTrailersHandler{})
+	defer testServer.Close()
+	c := Channel{baseAddress: testServer.URL, client: &fasthttp.Client{Dial: dialInterimConn}}
+
+	req := invokev1.NewInvokeMethodRequest("method")
+	req.WithHTTPExtension(http.MethodPost, "")
+	req.WithRawData([]byte("request"), "text/plain")
+	req.WithTrailers(map[string][]string{"X-Checksum": {"abc"}})
+
+	for i := 0; i < 2; i++ {
+		// The second request reuses the connection.
+		response, err := c.InvokeMethod(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(200), response.Status().Code)
+		_, body := response.RawData()
+		assert.Equal(t, "body", string(body))
+
+		assert.Equal(t, []string{"abc"}, response.Trailers()["X-Checksum"].GetValues())
+		assert.NotContains(t, response.Headers(), "X-Checksum")
+		assert.NotContains(t, response.Headers(), interimResponseHeader)
+
+		interim := response.InterimResponses()
+		if assert.Len(t, interim, 1) {
+			assert.Equal(t, int32(http.StatusEarlyHints), interim[0].Code)
+			assert.Equal(t, []string{"</style.css>; rel=preload"}, interim[0].Headers["Link"].GetValues())
+		}
+	}
+}
+
+func TestInterimStatusCode(t *testing.T) {
+	code, ok := interimStatusCode([]byte("HTTP/1.1 103 Early Hints\r\n"))
+	assert.True(t, ok)
+	assert.Equal(t, 103, code)
+
+	for _, line := range []string{"HTTP/1.1 200 OK\r\n", "HTTP/1.1 101 Switching Protocols\r\n", "HTTP/1.1 1\r\n"} {
+		_, ok = interimStatusCode([]byte(line))
+		assert.False(t, ok, line)
+	}
+}
+
 func TestInvokeWithHeaders(t *testing.T) {
 	ctx := context.Background()
 	testServer := httptest.NewServer(&testHandlerHeaders{})
@@ -278,6 +340,78 @@ func TestInvokeWithHeaders(t *testing.T) {
 	testServer.Close()
 }
 
+func TestInvokeWithPipeline(t *testing.T) {
+	ctx := context.Background()
+	testServer := httptest.NewServer(&testHandlerHeaders{})
+	defer testServer.Close()
+
+	pipeline := httpMiddleware.Pipeline{
+		Handlers: []httpMiddleware.Middleware{
+			func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+				return func(ctx *fasthttp.RequestCtx) {
+					ctx.Request.Header.Set("H1", "pipeline")
+					next(ctx)
+					ctx.Response.Header.Set("X-Pipeline", "true")
+				}
+			},
+		},
+	}
+
+	t.Run("request and response are transformed", func(t *testing.T) {
+		c := Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}}
+		c.SetPipeline(pipeline)
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithMetadata(map[string][]string{"H1": {"v1"}})
+		req.WithHTTPExtension(http.MethodPost, "")
+
+		response, err := c.InvokeMethod(ctx, req)
+		assert.NoError(t, err)
+		_, body := response.RawData()
+
+		actual := map[string]string{}
+		json.Unmarshal(body, &actual)
+		assert.Equal(t, "pipeline", actual["H1"])
+		assert.Equal(t, []string{"true"}, response.Headers()["X-Pipeline"].GetValues())
+	})
+
+	t.Run("middleware answers the request", func(t *testing.T) {
+		c := Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}}
+		c.SetPipeline(httpMiddleware.Pipeline{
+			Handlers: []httpMiddleware.Middleware{
+				func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+					return func(ctx *fasthttp.RequestCtx) {
+						ctx.Error("rate limited", fasthttp.StatusTooManyRequests)
+					}
+				},
+			},
+		})
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithHTTPExtension(http.MethodPost, "")
+
+		response, err := c.InvokeMethod(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(http.StatusTooManyRequests), response.Status().Code)
+	})
+
+	t.Run("request body followed by trailers", func(t *testing.T) {
+		trailersServer := httptest.NewServer(&testTrailersHandler{})
+		defer trailersServer.Close()
+		c := Channel{baseAddress: trailersServer.URL, client: &fasthttp.Client{Dial: dialInterimConn}}
+		c.SetPipeline(pipeline)
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithHTTPExtension(http.MethodPost, "")
+		req.WithRawData([]byte("request"), "text/plain")
+		req.WithTrailers(map[string][]string{"X-Checksum": {"abc"}})
+
+		response, err := c.InvokeMethod(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"abc"}, response.Trailers()["X-Checksum"].GetValues())
+	})
+}
+
 func TestContentType(t *testing.T) {
 	ctx := context.Background()
 
@@ -415,6 +549,38 @@ func TestAppToken(t *testing.T) {
 	})
 }
 
+func TestCompression(t *testing.T) {
+	body := strings.Repeat("compressed response ", 100)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Accept-Encoding")
+		w.Header().Set("X-Accept-Encoding", encoding)
+		if encoding == "" {
+			io.WriteString(w, body)
+			return
+		}
+		compressed, err := compression.Compress(compression.Gzip, []byte(body))
+		require.NoError(t, err)
+		w.Header().Set("Content-Encoding", compression.Gzip)
+		w.Write(compressed)
+	}))
+	defer testServer.Close()
+
+	policy, err := compression.NewPolicy(config.CompressionPolicy{Enabled: true, Encodings: []string{"zstd", "gzip"}})
+	require.NoError(t, err)
+	c := Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}, maxResponseBodySize: 4}
+	c.SetCompression(policy)
+
+	req := invokev1.NewInvokeMethodRequest("method")
+	req.WithHTTPExtension(http.MethodGet, "")
+	response, err := c.InvokeMethod(context.Background(), req)
+	require.NoError(t, err)
+
+	_, data := response.RawData()
+	assert.Equal(t, body, string(data))
+	assert.Equal(t, "zstd, gzip", response.Headers()["X-Accept-Encoding"].GetValues()[0])
+	assert.NotContains(t, response.Headers(), "Content-Encoding")
+}
+
 func TestCreateChannel(t *testing.T) {
 	t.Run("ssl scheme", func(t *testing.T) {
 		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, true, 4, 4)
@@ -462,3 +628,32 @@ func TestHealthProbe(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, success)
 }
+
+func TestUnixDomainSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.socket")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	h := &testStatusCodeHandler{}
+	testServer := httptest.NewUnstartedServer(h)
+	testServer.Listener = listener
+	testServer.Start()
+	defer testServer.Close()
+
+	ch, err := CreateLocalChannelOverUnixDomainSocket(socket, 0, config.TracingSpec{}, 4, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1", ch.GetBaseAddress())
+
+	t.Run("invoke method", func(t *testing.T) {
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithHTTPExtension(http.MethodPost, "")
+		resp, err := ch.InvokeMethod(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int32(200), resp.Status().Code)
+	})
+
+	t.Run("health probe", func(t *testing.T) {
+		success, err := ch.HealthProbe(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, success)
+	})
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// interimResponseHeader is added to the final response for every informational response that preceded it.
+// Its value is the status code followed by the URL encoded headers, for example "103 Link=%3C%2Fstyle.css%3E".
+const interimResponseHeader = "Dapr-Interim-Response"
+
+// dialInterimConn dials the app with a connection that records the informational (1xx) responses.
+// fasthttp only understands 100 Continue and would consider any other 1xx response as final.
+func dialInterimConn(addr string) (net.Conn, error) {
+	conn, err := fasthttp.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	return newInterimConn(conn), nil
+}
+
+// interimConn removes the informational responses from the stream read by fasthttp and records them
+// as interimResponseHeader headers of the final response.
+// Requests are not pipelined, so a response starts with the first read after a request is written.
+type interimConn struct {
+	net.Conn
+	br            *bufio.Reader
+	pending       []byte
+	responseStart bool
+}
+
+func newInterimConn(conn net.Conn) *interimConn {
+	return &interimConn{Conn: conn, br: bufio.NewReader(conn)}
+}
+
+func (c *interimConn) Write(b []byte) (int, error) {
+	c.responseStart = true
+	return c.Conn.Write(b)
+}
+
+func (c *interimConn) Read(b []byte) (int, error) {
+	if c.responseStart {
+		c.responseStart = false
+		if err := c.readInterimResponses(); err != nil {
+			return 0, err
+		}
+	}
+	if len(c.pending) > 0 {
+		n := copy(b, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	return c.br.Read(b)
+}
+
+// readInterimResponses consumes the informational responses and prepares the status line of the final response,
+// followed by the headers recording the informational responses.
+func (c *interimConn) readInterimResponses() error {
+	var interim []string
+	for {
+		line, err := c.br.ReadBytes('\n')
+		if err != nil {
+			if len(line) == 0 {
+				return err
+			}
+			// Let fasthttp handle the incomplete response.
+			c.pending = line
+			return nil
+		}
+
+		code, ok := interimStatusCode(line)
+		if !ok {
+			c.pending = line
+			for _, val := range interim {
+				c.pending = append(c.pending, interimResponseHeader+": "+val+"\r\n"...)
+			}
+			return nil
+		}
+
+		headers := url.Values{}
+		for {
+			headerLine, err := c.br.ReadBytes('\n')
+			if err != nil {
+				return err
+			}
+			headerLine = bytes.TrimRight(headerLine, "\r\n")
+			if len(headerLine) == 0 {
+				break
+			}
+			if key, value, found := strings.Cut(string(headerLine), ":"); found {
+				headers.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+			}
+		}
+		// 100 Continue is only meaningful between the app and Dapr.
+		if code != fasthttp.StatusContinue {
+			interim = append(interim, strconv.Itoa(code)+" "+headers.Encode())
+		}
+	}
+}
+
+// interimStatusCode returns the status code of an informational response status line.
+// 101 Switching Protocols is a final response.
+func interimStatusCode(line []byte) (int, bool) {
+	if len(line) < 12 || !bytes.HasPrefix(line, []byte("HTTP/1.")) || line[9] != '1' {
+		return 0, false
+	}
+	code, err := strconv.Atoi(string(line[9:12]))
+	if err != nil || code == fasthttp.StatusSwitchingProtocols {
+		return 0, false
+	}
+	return code, true
+}
+
+// parseInterimResponses removes the interimResponseHeader headers from the response and returns their values.
+func parseInterimResponses(header *fasthttp.ResponseHeader) []interimResponse {
+	var res []interimResponse
+	header.VisitAll(func(key, value []byte) {
+		if !strings.EqualFold(string(key), interimResponseHeader) {
+			return
+		}
+		codeStr, query, _ := strings.Cut(string(value), " ")
+		code, err := strconv.Atoi(codeStr)
+		if err != nil {
+			return
+		}
+		headers, _ := url.ParseQuery(query)
+		res = append(res, interimResponse{code: code, headers: headers})
+	})
+	header.Del(interimResponseHeader)
+	return res
+}
+
+type interimResponse struct {
+	code    int
+	headers map[string][]string
+}
@@ -92,12 +92,12 @@ func (b *Registry) getInputBinding(name, version string) (func() bindings.InputB
 	versionLower := strings.ToLower(version)
 	bindingFn, ok := b.inputBindings[nameLower+"/"+versionLower]
 	if ok {
-		return b.wrapInputBindingFn(bindingFn), true
+		return b.wrapInputBindingFn(bindingFn, nameLower), true
 	}
 	if components.IsInitialVersion(versionLower) {
 		bindingFn, ok = b.inputBindings[nameLower]
 		if ok {
-			return b.wrapInputBindingFn(bindingFn), true
+			return b.wrapInputBindingFn(bindingFn, nameLower), true
 		}
 	}
 	return nil, false
@@ -108,26 +108,26 @@ func (b *Registry) getOutputBinding(name, version string) (func() bindings.Outpu
 	versionLower := strings.ToLower(version)
 	bindingFn, ok := b.outputBindings[nameLower+"/"+versionLower]
 	if ok {
-		return b.wrapOutputBindingFn(bindingFn), true
+		return b.wrapOutputBindingFn(bindingFn, nameLower), true
 	}
 	if components.IsInitialVersion(versionLower) {
 		bindingFn, ok = b.outputBindings[nameLower]
 		if ok {
-			return b.wrapOutputBindingFn(bindingFn), true
+			return b.wrapOutputBindingFn(bindingFn, nameLower), true
 		}
 	}
 	return nil, false
 }
 
-func (b *Registry) wrapInputBindingFn(componentFactory func(logger.Logger) bindings.InputBinding) func() bindings.InputBinding {
+func (b *Registry) wrapInputBindingFn(componentFactory func(logger.Logger) bindings.InputBinding, name string) func() bindings.InputBinding {
 	return func() bindings.InputBinding {
-		return componentFactory(b.Logger)
+		return componentFactory(components.Logger(b.Logger, name))
 	}
 }
 
-func (b *Registry) wrapOutputBindingFn(componentFactory func(logger.Logger) bindings.OutputBinding) func() bindings.OutputBinding {
+func (b *Registry) wrapOutputBindingFn(componentFactory func(logger.Logger) bindings.OutputBinding, name string) func() bindings.OutputBinding {
 	return func() bindings.OutputBinding {
-		return componentFactory(b.Logger)
+		return componentFactory(components.Logger(b.Logger, name))
 	}
 }
 
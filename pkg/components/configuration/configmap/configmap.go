@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configmap contains the configuration store with the items of a Kubernetes ConfigMap,
+// which are reloaded when the ConfigMap changes.
+package configmap
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/dapr/components-contrib/configuration"
+	"github.com/dapr/dapr/pkg/components/configuration/itemstore"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// configMapKey is the metadata with the name of the ConfigMap.
+	configMapKey = "configMapName"
+	// namespaceKey is the metadata with the namespace of the ConfigMap, the namespace of the sidecar by default.
+	namespaceKey = "namespace"
+	// kubeconfigPathKey is the metadata with the path of the kubeconfig file used out of the cluster.
+	kubeconfigPathKey = "kubeconfigPath"
+
+	// rewatchInterval is the interval between the watches of the ConfigMap when they end.
+	rewatchInterval = time.Second * 5
+)
+
+// Store is the configuration store with the items of a ConfigMap, where each key of the data is an item
+// whose version is the resource version of the ConfigMap that changed it.
+type Store struct {
+	*itemstore.Store
+	logger    logger.Logger
+	client    kubernetes.Interface
+	name      string
+	namespace string
+	cancel    context.CancelFunc
+}
+
+// NewConfigMapStore creates the Kubernetes ConfigMap configuration store.
+func NewConfigMapStore(logger logger.Logger) configuration.Store {
+	return &Store{Store: itemstore.New(logger), logger: logger}
+}
+
+// Init loads the items of the ConfigMap and starts watching it.
+func (s *Store) Init(metadata configuration.Metadata) error {
+	s.name = metadata.Properties[configMapKey]
+	if s.name == "" {
+		return errors.Errorf("metadata property %s is required", configMapKey)
+	}
+	s.namespace = metadata.Properties[namespaceKey]
+	if s.namespace == "" {
+		s.namespace = os.Getenv("NAMESPACE")
+	}
+	if s.namespace == "" {
+		s.namespace = metav1.NamespaceDefault
+	}
+
+	if s.client == nil {
+		client, err := newClient(metadata.Properties[kubeconfigPathKey])
+		if err != nil {
+			return err
+		}
+		s.client = client
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	resourceVersion, err := s.load(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	go s.watch(ctx, resourceVersion)
+	return nil
+}
+
+// Close stops watching the ConfigMap.
+func (s *Store) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+func newClient(kubeconfigPath string) (kubernetes.Interface, error) {
+	var (
+		conf *rest.Config
+		err  error
+	)
+	if kubeconfigPath != "" {
+		conf, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	} else {
+		conf, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the Kubernetes config")
+	}
+	return kubernetes.NewForConfig(conf)
+}
+
+// load gets the ConfigMap and returns its resource version, from which it is watched.
+func (s *Store) load(ctx context.Context) (string, error) {
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the ConfigMap %s/%s", s.namespace, s.name)
+	}
+	s.Update(itemsOf(configMap))
+	return configMap.ResourceVersion, nil
+}
+
+func (s *Store) watch(ctx context.Context, resourceVersion string) {
+	for {
+		w, err := s.client.CoreV1().ConfigMaps(s.namespace).Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", s.name).String(),
+			ResourceVersion: resourceVersion,
+		})
+		if err == nil {
+			resourceVersion = s.handleEvents(ctx, w, resourceVersion)
+		} else {
+			s.logger.Errorf("failed to watch the ConfigMap %s/%s: %s", s.namespace, s.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(rewatchInterval):
+		}
+		// The changes missed between the watches are loaded before watching again.
+		if v, err := s.load(ctx); err == nil {
+			resourceVersion = v
+		} else {
+			s.logger.Errorf("failed to reload the configuration: %s", err)
+		}
+	}
+}
+
+// handleEvents updates the items on the events of the watch until it ends, and returns the last resource version.
+func (s *Store) handleEvents(ctx context.Context, w watch.Interface, resourceVersion string) string {
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return resourceVersion
+			}
+			configMap, ok := event.Object.(*corev1.ConfigMap)
+			if !ok {
+				continue
+			}
+			resourceVersion = configMap.ResourceVersion
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				s.Update(itemsOf(configMap))
+			case watch.Deleted:
+				s.Update(map[string]*configuration.Item{})
+			}
+		}
+	}
+}
+
+func itemsOf(configMap *corev1.ConfigMap) map[string]*configuration.Item {
+	items := make(map[string]*configuration.Item, len(configMap.Data)+len(configMap.BinaryData))
+	for k, v := range configMap.Data {
+		items[k] = &configuration.Item{Value: v, Version: configMap.ResourceVersion}
+	}
+	for k, v := range configMap.BinaryData {
+		items[k] = &configuration.Item{Value: string(v), Version: configMap.ResourceVersion}
+	}
+	return items
+}
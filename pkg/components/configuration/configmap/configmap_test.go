@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configmap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dapr/components-contrib/configuration"
+	contribMetadata "github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/kit/logger"
+)
+
+func newTestStore(objects ...*corev1.ConfigMap) (*Store, *fake.Clientset) {
+	client := fake.NewSimpleClientset()
+	for _, o := range objects {
+		client.Tracker().Add(o)
+	}
+	s := NewConfigMapStore(logger.NewLogger("test")).(*Store)
+	s.client = client
+	return s, client
+}
+
+func testMetadata() configuration.Metadata {
+	return configuration.Metadata{Base: contribMetadata.Base{Properties: map[string]string{
+		configMapKey: "app-config",
+		namespaceKey: "ns1",
+	}}}
+}
+
+func TestInit(t *testing.T) {
+	t.Run("items of the config map", func(t *testing.T) {
+		s, _ := newTestStore(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "ns1", ResourceVersion: "10"},
+			Data:       map[string]string{"greeting": "hello"},
+			BinaryData: map[string][]byte{"logo": []byte("png")},
+		})
+		require.NoError(t, s.Init(testMetadata()))
+		defer s.Close()
+
+		resp, err := s.Get(context.Background(), &configuration.GetRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]*configuration.Item{
+			"greeting": {Value: "hello", Version: "10"},
+			"logo":     {Value: "png", Version: "10"},
+		}, resp.Items)
+	})
+
+	t.Run("missing config map", func(t *testing.T) {
+		s, _ := newTestStore()
+		assert.Error(t, s.Init(testMetadata()))
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		s, _ := newTestStore()
+		assert.Error(t, s.Init(configuration.Metadata{}))
+	})
+}
+
+func TestWatch(t *testing.T) {
+	s, client := newTestStore(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "ns1", ResourceVersion: "10"},
+		Data:       map[string]string{"greeting": "hello", "retries": "3"},
+	})
+	require.NoError(t, s.Init(testMetadata()))
+	defer s.Close()
+
+	events := make(chan *configuration.UpdateEvent, 1)
+	_, err := s.Subscribe(context.Background(), &configuration.SubscribeRequest{Keys: []string{"greeting"}},
+		func(ctx context.Context, e *configuration.UpdateEvent) error {
+			events <- e
+			return nil
+		})
+	require.NoError(t, err)
+
+	// The config map is updated until the watch, started in the background, sees it.
+	timeout := time.After(time.Second * 10)
+	for {
+		_, err = client.CoreV1().ConfigMaps("ns1").Update(context.Background(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "ns1", ResourceVersion: "11"},
+			Data:       map[string]string{"greeting": "hi", "retries": "3"},
+		}, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		select {
+		case e := <-events:
+			assert.Equal(t, map[string]*configuration.Item{"greeting": {Value: "hi", Version: "11"}}, e.Items)
+			return
+		case <-time.After(time.Millisecond * 200):
+		case <-timeout:
+			assert.Fail(t, "no update event")
+			return
+		}
+	}
+}
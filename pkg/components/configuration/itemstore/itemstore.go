@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package itemstore contains the in-memory items of the built-in configuration stores, which load all their items
+// from a source such as a file or a ConfigMap, and notify the subscribers of the items changed by each load.
+package itemstore
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/configuration"
+	"github.com/dapr/kit/logger"
+)
+
+type subscription struct {
+	keys    []string
+	handler configuration.UpdateHandler
+}
+
+// Store serves the items of the last load, and implements the Get, Subscribe and Unsubscribe methods of the configuration stores.
+type Store struct {
+	logger        logger.Logger
+	lock          sync.RWMutex
+	items         map[string]*configuration.Item
+	revision      int
+	subscriptions map[string]subscription
+}
+
+// New creates a store without items.
+func New(logger logger.Logger) *Store {
+	return &Store{
+		logger:        logger,
+		items:         map[string]*configuration.Item{},
+		subscriptions: map[string]subscription{},
+	}
+}
+
+// Get returns the items of the keys that exist, or all the items if no key is requested.
+func (s *Store) Get(ctx context.Context, req *configuration.GetRequest) (*configuration.GetResponse, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	items := make(map[string]*configuration.Item, len(req.Keys))
+	if len(req.Keys) == 0 {
+		for k, item := range s.items {
+			items[k] = copyItem(item)
+		}
+	}
+	for _, k := range req.Keys {
+		if item, ok := s.items[k]; ok {
+			items[k] = copyItem(item)
+		}
+	}
+	return &configuration.GetResponse{Items: items}, nil
+}
+
+// Subscribe calls the handler with the changed items of the keys, or of all the keys if none is requested.
+func (s *Store) Subscribe(ctx context.Context, req *configuration.SubscribeRequest, handler configuration.UpdateHandler) (string, error) {
+	id := uuid.New().String()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.subscriptions[id] = subscription{keys: req.Keys, handler: handler}
+	return id, nil
+}
+
+func (s *Store) Unsubscribe(ctx context.Context, req *configuration.UnsubscribeRequest) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.subscriptions[req.ID]; !ok {
+		return errors.Errorf("subscription with id %s does not exist", req.ID)
+	}
+	delete(s.subscriptions, req.ID)
+	return nil
+}
+
+// Update replaces the items with the loaded ones, and notifies the subscribers of the changed items.
+// The removed items are notified with an empty value. The changed items without a version get the revision of the update.
+func (s *Store) Update(loaded map[string]*configuration.Item) {
+	s.lock.Lock()
+	changed := map[string]*configuration.Item{}
+	for k, item := range loaded {
+		if old, ok := s.items[k]; ok && old.Value == item.Value && reflect.DeepEqual(old.Metadata, item.Metadata) {
+			loaded[k] = old
+			continue
+		}
+		changed[k] = item
+	}
+	for k := range s.items {
+		if _, ok := loaded[k]; !ok {
+			changed[k] = &configuration.Item{}
+		}
+	}
+	if len(changed) == 0 {
+		s.lock.Unlock()
+		return
+	}
+
+	s.revision++
+	for _, item := range changed {
+		if item.Version == "" {
+			item.Version = strconv.Itoa(s.revision)
+		}
+	}
+	s.items = loaded
+
+	// The handlers are called without the lock, as they may get the items.
+	events := map[string]subscription{}
+	for id, sub := range s.subscriptions {
+		events[id] = sub
+	}
+	s.lock.Unlock()
+
+	for id, sub := range events {
+		items := map[string]*configuration.Item{}
+		if len(sub.keys) == 0 {
+			for k, item := range changed {
+				items[k] = copyItem(item)
+			}
+		}
+		for _, k := range sub.keys {
+			if item, ok := changed[k]; ok {
+				items[k] = copyItem(item)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		if err := sub.handler(context.Background(), &configuration.UpdateEvent{ID: id, Items: items}); err != nil {
+			s.logger.Errorf("failed to notify the configuration subscription %s: %s", id, err)
+		}
+	}
+}
+
+func copyItem(item *configuration.Item) *configuration.Item {
+	c := *item
+	if item.Metadata != nil {
+		c.Metadata = make(map[string]string, len(item.Metadata))
+		for k, v := range item.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	return &c
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package itemstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/configuration"
+	"github.com/dapr/kit/logger"
+)
+
+func TestGet(t *testing.T) {
+	s := New(logger.NewLogger("test"))
+	s.Update(map[string]*configuration.Item{
+		"a": {Value: "1"},
+		"b": {Value: "2", Version: "v2"},
+	})
+
+	resp, err := s.Get(context.Background(), &configuration.GetRequest{Keys: []string{"a", "c"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*configuration.Item{"a": {Value: "1", Version: "1"}}, resp.Items)
+
+	resp, err = s.Get(context.Background(), &configuration.GetRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*configuration.Item{
+		"a": {Value: "1", Version: "1"},
+		"b": {Value: "2", Version: "v2"},
+	}, resp.Items)
+}
+
+func TestSubscribe(t *testing.T) {
+	s := New(logger.NewLogger("test"))
+	s.Update(map[string]*configuration.Item{"a": {Value: "1"}, "b": {Value: "2"}})
+
+	var events []*configuration.UpdateEvent
+	handler := func(ctx context.Context, e *configuration.UpdateEvent) error {
+		events = append(events, e)
+		return nil
+	}
+	id, err := s.Subscribe(context.Background(), &configuration.SubscribeRequest{Keys: []string{"a"}}, handler)
+	require.NoError(t, err)
+
+	t.Run("changed subscribed item", func(t *testing.T) {
+		events = nil
+		s.Update(map[string]*configuration.Item{"a": {Value: "10"}, "b": {Value: "2"}})
+		require.Len(t, events, 1)
+		assert.Equal(t, id, events[0].ID)
+		assert.Equal(t, map[string]*configuration.Item{"a": {Value: "10", Version: "2"}}, events[0].Items)
+	})
+
+	t.Run("changed other item", func(t *testing.T) {
+		events = nil
+		s.Update(map[string]*configuration.Item{"a": {Value: "10"}, "b": {Value: "20"}})
+		assert.Empty(t, events)
+	})
+
+	t.Run("removed item", func(t *testing.T) {
+		events = nil
+		s.Update(map[string]*configuration.Item{"b": {Value: "20"}})
+		require.Len(t, events, 1)
+		assert.Equal(t, map[string]*configuration.Item{"a": {Version: "4"}}, events[0].Items)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		require.NoError(t, s.Unsubscribe(context.Background(), &configuration.UnsubscribeRequest{ID: id}))
+		events = nil
+		s.Update(map[string]*configuration.Item{"a": {Value: "1"}})
+		assert.Empty(t, events)
+		assert.Error(t, s.Unsubscribe(context.Background(), &configuration.UnsubscribeRequest{ID: id}))
+	})
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package localfile contains the configuration store with the items of a local file or directory,
+// which are reloaded when the files change.
+package localfile
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/configuration"
+	"github.com/dapr/dapr/pkg/components/configuration/itemstore"
+	"github.com/dapr/dapr/pkg/fswatcher"
+	"github.com/dapr/kit/logger"
+)
+
+// pathKey is the metadata with the path of the file or directory.
+const pathKey = "path"
+
+// Store is the configuration store with the items of a local file or directory:
+//   - a YAML or JSON file maps the keys to their values, or to objects with a value and metadata;
+//   - in a directory, such as a mounted ConfigMap, each file is an item named after the file, with the content of the file as value.
+type Store struct {
+	*itemstore.Store
+	logger logger.Logger
+	path   string
+	cancel context.CancelFunc
+}
+
+// NewLocalFileStore creates the local file configuration store.
+func NewLocalFileStore(logger logger.Logger) configuration.Store {
+	return &Store{Store: itemstore.New(logger), logger: logger}
+}
+
+// Init loads the items and starts watching the files.
+func (s *Store) Init(metadata configuration.Metadata) error {
+	s.path = metadata.Properties[pathKey]
+	if s.path == "" {
+		return errors.Errorf("metadata property %s is required", pathKey)
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the configuration")
+	}
+	if err = s.load(); err != nil {
+		return err
+	}
+
+	// The directory of a file is watched, as the files are often replaced rather than written.
+	dir := s.path
+	if !info.IsDir() {
+		dir = filepath.Dir(s.path)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	eventCh := make(chan struct{})
+	go func() {
+		if err := fswatcher.Watch(ctx, dir, eventCh); err != nil && ctx.Err() == nil {
+			s.logger.Errorf("failed to watch the configuration %s: %s", s.path, err)
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case <-eventCh:
+				if err := s.load(); err != nil {
+					s.logger.Errorf("failed to reload the configuration %s: %s", s.path, err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops watching the files.
+func (s *Store) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+func (s *Store) load() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the configuration")
+	}
+
+	var items map[string]*configuration.Item
+	if info.IsDir() {
+		items, err = readDir(s.path)
+	} else {
+		items, err = readFile(s.path)
+	}
+	if err != nil {
+		return err
+	}
+	s.Update(items)
+	return nil
+}
+
+func readDir(dir string) (map[string]*configuration.Item, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the configuration")
+	}
+
+	items := make(map[string]*configuration.Item, len(entries))
+	for _, e := range entries {
+		// The hidden files include the data directories of the mounted ConfigMaps.
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		// The items of the mounted ConfigMaps are symbolic links.
+		info, err := os.Stat(filepath.Join(dir, e.Name()))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		value, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the configuration item %s", e.Name())
+		}
+		items[e.Name()] = &configuration.Item{Value: string(value)}
+	}
+	return items, nil
+}
+
+func readFile(path string) (map[string]*configuration.Item, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the configuration")
+	}
+
+	var values map[string]json.RawMessage
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		return nil, errors.Wrapf(err, "invalid configuration %s", path)
+	}
+
+	items := make(map[string]*configuration.Item, len(values))
+	for k, raw := range values {
+		item := &configuration.Item{}
+		var value string
+		switch {
+		case json.Unmarshal(raw, &value) == nil:
+			item.Value = value
+		case strings.HasPrefix(strings.TrimSpace(string(raw)), "{"):
+			if err = json.Unmarshal(raw, item); err != nil {
+				return nil, errors.Wrapf(err, "invalid configuration item %s", k)
+			}
+		default:
+			// The numbers and booleans are kept as written.
+			item.Value = string(raw)
+		}
+		items[k] = item
+	}
+	return items, nil
+}
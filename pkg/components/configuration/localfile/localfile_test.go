@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/configuration"
+	contribMetadata "github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/kit/logger"
+)
+
+func initStore(t *testing.T, path string) *Store {
+	s := NewLocalFileStore(logger.NewLogger("test")).(*Store)
+	require.NoError(t, s.Init(configuration.Metadata{Base: contribMetadata.Base{Properties: map[string]string{pathKey: path}}}))
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func getAll(t *testing.T, s *Store) map[string]*configuration.Item {
+	resp, err := s.Get(context.Background(), &configuration.GetRequest{})
+	require.NoError(t, err)
+	return resp.Items
+}
+
+func TestInit(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+greeting: hello
+retries: 3
+feature:
+  value: "on"
+  metadata:
+    owner: team1
+`), 0o600))
+
+		s := initStore(t, path)
+		assert.Equal(t, map[string]*configuration.Item{
+			"greeting": {Value: "hello", Version: "1"},
+			"retries":  {Value: "3", Version: "1"},
+			"feature":  {Value: "on", Version: "1", Metadata: map[string]string{"owner": "team1"}},
+		}, getAll(t, s))
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "greeting"), []byte("hello"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), []byte("ignored"), 0o600))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "..data"), 0o700))
+
+		s := initStore(t, dir)
+		assert.Equal(t, map[string]*configuration.Item{"greeting": {Value: "hello", Version: "1"}}, getAll(t, s))
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		s := NewLocalFileStore(logger.NewLogger("test"))
+		assert.Error(t, s.Init(configuration.Metadata{}))
+		assert.Error(t, s.Init(configuration.Metadata{Base: contribMetadata.Base{Properties: map[string]string{pathKey: "/missing"}}}))
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("- a list"), 0o600))
+		s := NewLocalFileStore(logger.NewLogger("test"))
+		assert.Error(t, s.Init(configuration.Metadata{Base: contribMetadata.Base{Properties: map[string]string{pathKey: path}}}))
+	})
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("greeting: hello"), 0o600))
+	s := initStore(t, path)
+
+	events := make(chan *configuration.UpdateEvent, 1)
+	_, err := s.Subscribe(context.Background(), &configuration.SubscribeRequest{Keys: []string{"greeting"}},
+		func(ctx context.Context, e *configuration.UpdateEvent) error {
+			events <- e
+			return nil
+		})
+	require.NoError(t, err)
+
+	// The file is written until the watcher, started in the background, sees it.
+	timeout := time.After(time.Second * 10)
+	for {
+		require.NoError(t, os.WriteFile(path, []byte("greeting: hi"), 0o600))
+		select {
+		case e := <-events:
+			assert.Equal(t, map[string]*configuration.Item{"greeting": {Value: "hi", Version: "2"}}, e.Items)
+			return
+		case <-time.After(time.Millisecond * 200):
+		case <-timeout:
+			assert.Fail(t, "no update event")
+			return
+		}
+	}
+}
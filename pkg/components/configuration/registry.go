@@ -61,20 +61,20 @@ func (s *Registry) getConfigurationStore(name, version string) (func() configura
 	versionLower := strings.ToLower(version)
 	configurationStoreFn, ok := s.configurationStores[nameLower+"/"+versionLower]
 	if ok {
-		return s.wrapFn(configurationStoreFn), true
+		return s.wrapFn(configurationStoreFn, nameLower), true
 	}
 	if components.IsInitialVersion(versionLower) {
 		configurationStoreFn, ok = s.configurationStores[nameLower]
 		if ok {
-			return s.wrapFn(configurationStoreFn), true
+			return s.wrapFn(configurationStoreFn, nameLower), true
 		}
 	}
 	return nil, false
 }
 
-func (s *Registry) wrapFn(componentFactory func(logger.Logger) configuration.Store) func() configuration.Store {
+func (s *Registry) wrapFn(componentFactory func(logger.Logger) configuration.Store, name string) func() configuration.Store {
 	return func() configuration.Store {
-		return componentFactory(s.Logger)
+		return componentFactory(components.Logger(s.Logger, name))
 	}
 }
 
@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package azurekeyvault contains a crypto component that performs the operations in Azure Key Vault, so the keys never leave the vault.
+package azurekeyvault
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/components/crypto"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	vaultNameKey         = "vaultName"
+	vaultDNSSuffixKey    = "vaultDNSSuffix"
+	azureTenantIDKey     = "azureTenantId"
+	azureClientIDKey     = "azureClientId"
+	azureClientSecretKey = "azureClientSecret"
+
+	defaultVaultDNSSuffix = "vault.azure.net"
+	apiVersion            = "7.3"
+	moduleName            = "dapr-crypto-azurekeyvault"
+	moduleVersion         = "v0.1.0"
+)
+
+var base64URL = base64.RawURLEncoding
+
+// keyVaultCrypto is a crypto component backed by Azure Key Vault.
+// Key names are the names of the keys in the vault, optionally followed by a slash and the version of the key.
+type keyVaultCrypto struct {
+	vaultURL string
+	pipeline runtime.Pipeline
+	logger   logger.Logger
+}
+
+// keyOperationRequest is the body of the key operations of the Key Vault REST API.
+type keyOperationRequest struct {
+	Algorithm string `json:"alg"`
+	Value     string `json:"value"`
+	Digest    string `json:"digest,omitempty"`
+}
+
+// keyOperationResult is the response of the key operations of the Key Vault REST API.
+type keyOperationResult struct {
+	Value string `json:"value"`
+}
+
+// keyVerifyResult is the response of the verify operation of the Key Vault REST API.
+type keyVerifyResult struct {
+	Value bool `json:"value"`
+}
+
+// NewAzureKeyVaultCrypto returns a new Azure Key Vault crypto component.
+func NewAzureKeyVaultCrypto(logger logger.Logger) crypto.SubtleCrypto {
+	return &keyVaultCrypto{logger: logger}
+}
+
+func (k *keyVaultCrypto) Init(metadata crypto.Metadata) error {
+	vaultName := metadata.Properties[vaultNameKey]
+	if vaultName == "" {
+		return errors.Errorf("metadata property %s is required", vaultNameKey)
+	}
+	dnsSuffix := metadata.Properties[vaultDNSSuffixKey]
+	if dnsSuffix == "" {
+		dnsSuffix = defaultVaultDNSSuffix
+	}
+	k.vaultURL = fmt.Sprintf("https://%s.%s", vaultName, dnsSuffix)
+
+	var (
+		cred azcore.TokenCredential
+		err  error
+	)
+	tenantID, clientID, clientSecret := metadata.Properties[azureTenantIDKey], metadata.Properties[azureClientIDKey], metadata.Properties[azureClientSecretKey]
+	if tenantID != "" && clientID != "" && clientSecret != "" {
+		cred, err = azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	} else {
+		cred, err = azidentity.NewDefaultAzureCredential(nil)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get the Azure credentials")
+	}
+
+	scope := "https://" + dnsSuffix + "/.default"
+	k.pipeline = runtime.NewPipeline(moduleName, moduleVersion, runtime.PipelineOptions{
+		PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(cred, []string{scope}, nil)},
+	}, nil)
+	return nil
+}
+
+func (k *keyVaultCrypto) WrapKey(ctx context.Context, key []byte, algorithm string, keyName string) ([]byte, error) {
+	return k.keyOperation(ctx, "wrapkey", keyName, keyOperationRequest{
+		Algorithm: algorithm,
+		Value:     base64URL.EncodeToString(key),
+	})
+}
+
+func (k *keyVaultCrypto) UnwrapKey(ctx context.Context, wrappedKey []byte, algorithm string, keyName string) ([]byte, error) {
+	return k.keyOperation(ctx, "unwrapkey", keyName, keyOperationRequest{
+		Algorithm: algorithm,
+		Value:     base64URL.EncodeToString(wrappedKey),
+	})
+}
+
+func (k *keyVaultCrypto) Sign(ctx context.Context, digest []byte, algorithm string, keyName string) ([]byte, error) {
+	return k.keyOperation(ctx, "sign", keyName, keyOperationRequest{
+		Algorithm: algorithm,
+		Value:     base64URL.EncodeToString(digest),
+	})
+}
+
+func (k *keyVaultCrypto) Verify(ctx context.Context, digest []byte, signature []byte, algorithm string, keyName string) (bool, error) {
+	var res keyVerifyResult
+	err := k.do(ctx, "verify", keyName, keyOperationRequest{
+		Algorithm: algorithm,
+		Digest:    base64URL.EncodeToString(digest),
+		Value:     base64URL.EncodeToString(signature),
+	}, &res)
+	if err != nil {
+		return false, err
+	}
+	return res.Value, nil
+}
+
+func (k *keyVaultCrypto) keyOperation(ctx context.Context, operation string, keyName string, body keyOperationRequest) ([]byte, error) {
+	var res keyOperationResult
+	if err := k.do(ctx, operation, keyName, body, &res); err != nil {
+		return nil, err
+	}
+	return base64URL.DecodeString(strings.TrimRight(res.Value, "="))
+}
+
+func (k *keyVaultCrypto) do(ctx context.Context, operation string, keyName string, body keyOperationRequest, res interface{}) error {
+	name, version, _ := strings.Cut(keyName, "/")
+	if name == "" {
+		return errors.New("key name is required")
+	}
+	endpoint := k.vaultURL + "/keys/" + url.PathEscape(name)
+	if version != "" {
+		endpoint += "/" + url.PathEscape(version)
+	}
+	endpoint += "/" + operation + "?api-version=" + apiVersion
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, endpoint)
+	if err != nil {
+		return err
+	}
+	if err = runtime.MarshalAsJSON(req, body); err != nil {
+		return err
+	}
+
+	resp, err := k.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return runtime.NewResponseError(resp)
+	}
+	return runtime.UnmarshalAsJSON(resp, res)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crypto contains the components of the cryptography building block, and the envelope encryption of streams.
+package crypto
+
+import (
+	"context"
+
+	contribMetadata "github.com/dapr/components-contrib/metadata"
+)
+
+// Metadata contains the metadata of a crypto component.
+type Metadata struct {
+	contribMetadata.Base `json:",inline"`
+}
+
+// SubtleCrypto is the interface of the crypto components.
+// The keys never leave the components: the runtime only asks them to wrap and unwrap the data encryption keys,
+// and to sign and verify digests.
+// Algorithms use the names of JWA (RFC 7518), for example RSA-OAEP-256, A256KW, RS256 or ES256.
+type SubtleCrypto interface {
+	// Init initializes the component.
+	Init(metadata Metadata) error
+	// WrapKey encrypts the key with the key named keyName.
+	WrapKey(ctx context.Context, key []byte, algorithm string, keyName string) ([]byte, error)
+	// UnwrapKey decrypts a key wrapped with WrapKey.
+	UnwrapKey(ctx context.Context, wrappedKey []byte, algorithm string, keyName string) ([]byte, error)
+	// Sign signs the digest with the key named keyName.
+	Sign(ctx context.Context, digest []byte, algorithm string, keyName string) ([]byte, error)
+	// Verify checks the signature of the digest with the key named keyName.
+	Verify(ctx context.Context, digest []byte, signature []byte, algorithm string, keyName string) (bool, error)
+}
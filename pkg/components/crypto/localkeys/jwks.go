@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkeys
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/dapr/dapr/pkg/components/crypto"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	jwksKey     = "jwks"
+	jwksPathKey = "jwksPath"
+)
+
+// JWKS is a crypto component with the keys of a JSON Web Key Set, named after their key ID.
+// The key set is in the "jwks" metadata property, or in the file at the "jwksPath" metadata property.
+type JWKS struct {
+	keySet
+	logger logger.Logger
+}
+
+// NewJWKS returns a new JWKS crypto component.
+func NewJWKS(logger logger.Logger) crypto.SubtleCrypto {
+	return &JWKS{logger: logger}
+}
+
+func (j *JWKS) Init(metadata crypto.Metadata) error {
+	data := []byte(metadata.Properties[jwksKey])
+	if len(data) == 0 {
+		path := metadata.Properties[jwksPathKey]
+		if path == "" {
+			return errors.Errorf("metadata property %s or %s is required", jwksKey, jwksPathKey)
+		}
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the key set")
+		}
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return errors.Wrap(err, "invalid key set")
+	}
+
+	j.keys = make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.KeyID == "" {
+			return errors.New("keys of the key set must have a key ID")
+		}
+		j.keys[k.KeyID] = k.Key
+	}
+	j.logger.Debugf("loaded %d keys from the key set", len(j.keys))
+	return nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package localkeys contains the crypto components that perform the operations in daprd, with keys from the component metadata.
+package localkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/subtle"
+	"hash"
+	"math/big"
+
+	"github.com/pkg/errors"
+	josecipher "gopkg.in/square/go-jose.v2/cipher"
+)
+
+// ErrKeyNotFound is returned when the component doesn't have the key.
+var ErrKeyNotFound = errors.New("key not found")
+
+// keySet performs the crypto operations with keys in memory.
+// Keys are *rsa.PrivateKey, *rsa.PublicKey, *ecdsa.PrivateKey, *ecdsa.PublicKey or []byte for symmetric keys.
+type keySet struct {
+	keys map[string]interface{}
+}
+
+func (s *keySet) getKey(keyName string) (interface{}, error) {
+	key, ok := s.keys[keyName]
+	if !ok {
+		return nil, errors.Wrapf(ErrKeyNotFound, "key %s", keyName)
+	}
+	return key, nil
+}
+
+func (s *keySet) WrapKey(ctx context.Context, key []byte, algorithm string, keyName string) ([]byte, error) {
+	k, err := s.getKey(keyName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch algorithm {
+	case "A128KW", "A192KW", "A256KW":
+		block, err := newKeyWrapCipher(k, algorithm)
+		if err != nil {
+			return nil, err
+		}
+		return josecipher.KeyWrap(block, key)
+	case "RSA-OAEP", "RSA-OAEP-256":
+		pub, err := rsaPublicKey(k)
+		if err != nil {
+			return nil, err
+		}
+		return rsa.EncryptOAEP(oaepHash(algorithm), rand.Reader, pub, key, nil)
+	default:
+		return nil, errors.Errorf("unsupported key wrap algorithm %s", algorithm)
+	}
+}
+
+func (s *keySet) UnwrapKey(ctx context.Context, wrappedKey []byte, algorithm string, keyName string) ([]byte, error) {
+	k, err := s.getKey(keyName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch algorithm {
+	case "A128KW", "A192KW", "A256KW":
+		block, err := newKeyWrapCipher(k, algorithm)
+		if err != nil {
+			return nil, err
+		}
+		return josecipher.KeyUnwrap(block, wrappedKey)
+	case "RSA-OAEP", "RSA-OAEP-256":
+		priv, ok := k.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.Errorf("key %s is not a RSA private key", keyName)
+		}
+		return rsa.DecryptOAEP(oaepHash(algorithm), rand.Reader, priv, wrappedKey, nil)
+	default:
+		return nil, errors.Errorf("unsupported key wrap algorithm %s", algorithm)
+	}
+}
+
+func (s *keySet) Sign(ctx context.Context, digest []byte, algorithm string, keyName string) ([]byte, error) {
+	k, err := s.getKey(keyName)
+	if err != nil {
+		return nil, err
+	}
+	h, err := signatureHash(algorithm, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	switch algorithm[:2] {
+	case "RS", "PS":
+		priv, ok := k.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.Errorf("key %s is not a RSA private key", keyName)
+		}
+		if algorithm[0] == 'P' {
+			return rsa.SignPSS(rand.Reader, priv, h, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.SignPKCS1v15(rand.Reader, priv, h, digest)
+	case "ES":
+		priv, ok := k.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.Errorf("key %s is not a ECDSA private key", keyName)
+		}
+		r, sig, err := ecdsa.Sign(rand.Reader, priv, digest)
+		if err != nil {
+			return nil, err
+		}
+		// Signatures are the concatenation of r and s, as in JWS
+		size := (priv.Curve.Params().BitSize + 7) / 8
+		out := make([]byte, 2*size)
+		r.FillBytes(out[:size])
+		sig.FillBytes(out[size:])
+		return out, nil
+	default:
+		secret, ok := k.([]byte)
+		if !ok {
+			return nil, errors.Errorf("key %s is not a symmetric key", keyName)
+		}
+		mac := hmac.New(h.New, secret)
+		mac.Write(digest)
+		return mac.Sum(nil), nil
+	}
+}
+
+func (s *keySet) Verify(ctx context.Context, digest []byte, signature []byte, algorithm string, keyName string) (bool, error) {
+	k, err := s.getKey(keyName)
+	if err != nil {
+		return false, err
+	}
+	h, err := signatureHash(algorithm, digest)
+	if err != nil {
+		return false, err
+	}
+
+	switch algorithm[:2] {
+	case "RS", "PS":
+		pub, err := rsaPublicKey(k)
+		if err != nil {
+			return false, err
+		}
+		if algorithm[0] == 'P' {
+			return rsa.VerifyPSS(pub, h, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}) == nil, nil
+		}
+		return rsa.VerifyPKCS1v15(pub, h, digest, signature) == nil, nil
+	case "ES":
+		var pub *ecdsa.PublicKey
+		switch key := k.(type) {
+		case *ecdsa.PrivateKey:
+			pub = &key.PublicKey
+		case *ecdsa.PublicKey:
+			pub = key
+		default:
+			return false, errors.Errorf("key %s is not a ECDSA key", keyName)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false, nil
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		sig := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(pub, digest, r, sig), nil
+	default:
+		secret, ok := k.([]byte)
+		if !ok {
+			return false, errors.Errorf("key %s is not a symmetric key", keyName)
+		}
+		mac := hmac.New(h.New, secret)
+		mac.Write(digest)
+		return subtle.ConstantTimeCompare(mac.Sum(nil), signature) == 1, nil
+	}
+}
+
+// signatureHash returns the hash function of the signature algorithm, and checks the size of the digest.
+func signatureHash(algorithm string, digest []byte) (crypto.Hash, error) {
+	var h crypto.Hash
+	switch algorithm {
+	case "RS256", "PS256", "ES256", "HS256":
+		h = crypto.SHA256
+	case "RS384", "PS384", "ES384", "HS384":
+		h = crypto.SHA384
+	case "RS512", "PS512", "ES512", "HS512":
+		h = crypto.SHA512
+	default:
+		return 0, errors.Errorf("unsupported signature algorithm %s", algorithm)
+	}
+	if len(digest) != h.Size() {
+		return 0, errors.Errorf("digest must be %d bytes long for algorithm %s", h.Size(), algorithm)
+	}
+	return h, nil
+}
+
+func newKeyWrapCipher(k interface{}, algorithm string) (cipher.Block, error) {
+	secret, ok := k.([]byte)
+	if !ok {
+		return nil, errors.New("AES key wrap requires a symmetric key")
+	}
+	var size int
+	switch algorithm {
+	case "A128KW":
+		size = 16
+	case "A192KW":
+		size = 24
+	case "A256KW":
+		size = 32
+	}
+	if len(secret) != size {
+		return nil, errors.Errorf("algorithm %s requires a key of %d bytes", algorithm, size)
+	}
+	return aes.NewCipher(secret)
+}
+
+func rsaPublicKey(k interface{}) (*rsa.PublicKey, error) {
+	switch key := k.(type) {
+	case *rsa.PrivateKey:
+		return &key.PublicKey, nil
+	case *rsa.PublicKey:
+		return key, nil
+	default:
+		return nil, errors.New("key is not a RSA key")
+	}
+}
+
+func oaepHash(algorithm string) hash.Hash {
+	if algorithm == "RSA-OAEP" {
+		return sha1.New() //nolint:gosec
+	}
+	return sha256.New()
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkeys
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	contribMetadata "github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/dapr/pkg/components/crypto"
+	"github.com/dapr/kit/logger"
+)
+
+var testLogger = logger.NewLogger("crypto.localkeys.test")
+
+func newMetadata(properties map[string]string) crypto.Metadata {
+	return crypto.Metadata{Base: contribMetadata.Base{Properties: properties}}
+}
+
+func newTestRawKeys(t *testing.T) (crypto.SubtleCrypto, *rsa.PrivateKey) {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	c := NewRawKeys(testLogger)
+	err = c.Init(newMetadata(map[string]string{
+		"rsa":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})),
+		"rsapub": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})),
+		"ec":     string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER})),
+		"aes":    base64.StdEncoding.EncodeToString(make([]byte, 32)),
+	}))
+	require.NoError(t, err)
+	return c, rsaKey
+}
+
+func TestRawKeysInit(t *testing.T) {
+	t.Run("no keys", func(t *testing.T) {
+		err := NewRawKeys(testLogger).Init(newMetadata(nil))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		err := NewRawKeys(testLogger).Init(newMetadata(map[string]string{"key": "not a key!"}))
+		assert.Error(t, err)
+	})
+}
+
+func TestWrapUnwrapKey(t *testing.T) {
+	c, _ := newTestRawKeys(t)
+	ctx := context.Background()
+	dataKey := make([]byte, 32)
+	_, err := rand.Read(dataKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		algorithm string
+		wrapKey   string
+		unwrapKey string
+	}{
+		{"A256KW", "aes", "aes"},
+		{"RSA-OAEP", "rsa", "rsa"},
+		{"RSA-OAEP-256", "rsapub", "rsa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			wrapped, err := c.WrapKey(ctx, dataKey, tt.algorithm, tt.wrapKey)
+			require.NoError(t, err)
+			assert.NotEqual(t, dataKey, wrapped)
+
+			unwrapped, err := c.UnwrapKey(ctx, wrapped, tt.algorithm, tt.unwrapKey)
+			require.NoError(t, err)
+			assert.Equal(t, dataKey, unwrapped)
+		})
+	}
+
+	t.Run("wrong key size", func(t *testing.T) {
+		_, err := c.WrapKey(ctx, dataKey, "A128KW", "aes")
+		assert.Error(t, err)
+	})
+
+	t.Run("unwrap with a public key", func(t *testing.T) {
+		wrapped, err := c.WrapKey(ctx, dataKey, "RSA-OAEP", "rsapub")
+		require.NoError(t, err)
+		_, err = c.UnwrapKey(ctx, wrapped, "RSA-OAEP", "rsapub")
+		assert.Error(t, err)
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		_, err := c.WrapKey(ctx, dataKey, "A256KW", "nope")
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		_, err := c.WrapKey(ctx, dataKey, "dir", "aes")
+		assert.Error(t, err)
+	})
+}
+
+func TestSignVerify(t *testing.T) {
+	c, _ := newTestRawKeys(t)
+	ctx := context.Background()
+	digest := sha256.Sum256([]byte("hello world"))
+
+	tests := []struct {
+		algorithm string
+		signKey   string
+		verifyKey string
+	}{
+		{"RS256", "rsa", "rsapub"},
+		{"PS256", "rsa", "rsa"},
+		{"ES256", "ec", "ec"},
+		{"HS256", "aes", "aes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			signature, err := c.Sign(ctx, digest[:], tt.algorithm, tt.signKey)
+			require.NoError(t, err)
+
+			valid, err := c.Verify(ctx, digest[:], signature, tt.algorithm, tt.verifyKey)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			signature[0] ^= 1
+			valid, err = c.Verify(ctx, digest[:], signature, tt.algorithm, tt.verifyKey)
+			require.NoError(t, err)
+			assert.False(t, valid)
+		})
+	}
+
+	t.Run("wrong digest size", func(t *testing.T) {
+		_, err := c.Sign(ctx, digest[:16], "RS256", "rsa")
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong key type", func(t *testing.T) {
+		_, err := c.Sign(ctx, digest[:], "ES256", "rsa")
+		assert.Error(t, err)
+	})
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	set, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: rsaKey, KeyID: "mykey", Algorithm: "RS256"},
+	}})
+	require.NoError(t, err)
+
+	t.Run("missing key set", func(t *testing.T) {
+		err := NewJWKS(testLogger).Init(newMetadata(nil))
+		assert.Error(t, err)
+	})
+
+	t.Run("keys by key ID", func(t *testing.T) {
+		c := NewJWKS(testLogger)
+		require.NoError(t, c.Init(newMetadata(map[string]string{"jwks": string(set)})))
+
+		digest := sha256.Sum256([]byte("hello world"))
+		signature, err := c.Sign(context.Background(), digest[:], "RS256", "mykey")
+		require.NoError(t, err)
+		assert.NoError(t, rsa.VerifyPKCS1v15(&rsaKey.PublicKey, stdcrypto.SHA256, digest[:], signature))
+	})
+}
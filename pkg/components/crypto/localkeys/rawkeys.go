@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkeys
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/components/crypto"
+	"github.com/dapr/kit/logger"
+)
+
+// RawKeys is a crypto component with the keys in the metadata properties, named after the properties.
+// Values are PEM encoded private or public keys, or base64 encoded symmetric keys.
+// Keys can be read from secret stores with secret references in the metadata of the component.
+type RawKeys struct {
+	keySet
+	logger logger.Logger
+}
+
+// NewRawKeys returns a new raw keys crypto component.
+func NewRawKeys(logger logger.Logger) crypto.SubtleCrypto {
+	return &RawKeys{logger: logger}
+}
+
+func (r *RawKeys) Init(metadata crypto.Metadata) error {
+	if len(metadata.Properties) == 0 {
+		return errors.New("at least one key is required")
+	}
+
+	r.keys = make(map[string]interface{}, len(metadata.Properties))
+	for name, value := range metadata.Properties {
+		key, err := parseRawKey(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid key %s", name)
+		}
+		r.keys[name] = key
+	}
+	r.logger.Debugf("loaded %d keys", len(r.keys))
+	return nil
+}
+
+func parseRawKey(value string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, errors.New("key must be PEM encoded, or a base64 encoded symmetric key")
+		}
+		return key, nil
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, errors.Errorf("unsupported PEM block type %s", block.Type)
+	}
+}
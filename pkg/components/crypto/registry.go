@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/components"
+	"github.com/dapr/kit/logger"
+)
+
+type Registry struct {
+	Logger    logger.Logger
+	providers map[string]func(logger.Logger) SubtleCrypto
+}
+
+// DefaultRegistry is the singleton with the registry.
+var DefaultRegistry *Registry
+
+func init() {
+	DefaultRegistry = NewRegistry()
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]func(logger.Logger) SubtleCrypto),
+	}
+}
+
+func (r *Registry) RegisterComponent(componentFactory func(logger.Logger) SubtleCrypto, names ...string) {
+	for _, name := range names {
+		r.providers[createFullName(name)] = componentFactory
+	}
+}
+
+func (r *Registry) Create(name, version string) (SubtleCrypto, error) {
+	if method, ok := r.getProvider(name, version); ok {
+		return method(), nil
+	}
+	return nil, errors.Errorf("couldn't find crypto provider %s/%s", name, version)
+}
+
+func (r *Registry) getProvider(name, version string) (func() SubtleCrypto, bool) {
+	nameLower := strings.ToLower(name)
+	versionLower := strings.ToLower(version)
+	factoryMethod, ok := r.providers[nameLower+"/"+versionLower]
+	if ok {
+		return r.wrapFn(factoryMethod, nameLower), true
+	}
+	if components.IsInitialVersion(versionLower) {
+		factoryMethod, ok = r.providers[nameLower]
+		if ok {
+			return r.wrapFn(factoryMethod, nameLower), true
+		}
+	}
+	return nil, false
+}
+
+func (r *Registry) wrapFn(componentFactory func(logger.Logger) SubtleCrypto, name string) func() SubtleCrypto {
+	return func() SubtleCrypto {
+		return componentFactory(components.Logger(r.Logger, name))
+	}
+}
+
+func createFullName(name string) string {
+	return strings.ToLower("crypto." + name)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	compName   = "mock"
+	compNameV2 = "mock/v2"
+	fullName   = "crypto." + compName
+)
+
+func TestNewRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterComponent(func(_ logger.Logger) SubtleCrypto {
+		return nil
+	}, compName)
+	r.RegisterComponent(func(_ logger.Logger) SubtleCrypto {
+		return nil
+	}, compNameV2)
+	if _, err := r.Create(fullName, "v1"); err != nil {
+		t.Fatalf("create mock provider failed: %v", err)
+	}
+	if _, err := r.Create(fullName, "v2"); err != nil {
+		t.Fatalf("create mock provider failed: %v", err)
+	}
+	if _, err := r.Create("not exists", "v1"); !strings.Contains(err.Error(), "couldn't find crypto provider") {
+		t.Fatalf("create mock provider failed: %v", err)
+	}
+}
+
+func TestAliasing(t *testing.T) {
+	const alias = "my-alias"
+	r := NewRegistry()
+	r.RegisterComponent(func(_ logger.Logger) SubtleCrypto {
+		return nil
+	}, "", alias)
+	_, err := r.Create("crypto."+alias, "")
+	assert.Nil(t, err)
+}
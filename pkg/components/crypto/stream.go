@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+/*
+Encrypted streams use envelope encryption: the data is encrypted with a random data encryption key,
+which is wrapped with a key of the crypto component and stored in the header of the stream.
+
+The format of the encrypted streams is:
+
+	dapr.io/enc/v1\n
+	<header as JSON>\n
+	<segment 0>...<segment N>
+
+The plaintext is split in segments of SegmentSize bytes (the last one can be shorter, or empty),
+and each segment is encrypted with AES-256-GCM, with the header as additional data.
+The nonce of a segment is the nonce prefix of the header, followed by the index of the segment
+and a byte set to 1 for the last segment, so that segments can't be reordered and streams can't be truncated.
+*/
+
+const (
+	// SegmentSize is the size of the plaintext segments of the encrypted streams.
+	SegmentSize = 64 << 10
+
+	streamMagic     = "dapr.io/enc/v1\n"
+	dataKeySize     = 32
+	noncePrefixSize = 7
+	maxHeaderSize   = 16 << 10
+)
+
+// ErrInvalidStream is returned when decrypting a stream that is not an encrypted stream, or was altered.
+var ErrInvalidStream = errors.New("invalid encrypted stream")
+
+// EncryptOptions contains the options to encrypt a stream.
+type EncryptOptions struct {
+	// Name of the key of the component used to wrap the data encryption key.
+	KeyName string
+	// Algorithm used to wrap the data encryption key.
+	KeyWrapAlgorithm string
+}
+
+// streamHeader is the header of an encrypted stream.
+type streamHeader struct {
+	KeyName          string `json:"k"`
+	KeyWrapAlgorithm string `json:"kw"`
+	WrappedKey       []byte `json:"wk"`
+	SegmentSize      int    `json:"cs"`
+	NoncePrefix      []byte `json:"np"`
+}
+
+// Encrypt encrypts the data read from in, and writes the encrypted stream to out.
+func Encrypt(ctx context.Context, provider SubtleCrypto, out io.Writer, in io.Reader, opts EncryptOptions) error {
+	if opts.KeyName == "" {
+		return errors.New("key name is required")
+	}
+	if opts.KeyWrapAlgorithm == "" {
+		return errors.New("key wrap algorithm is required")
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(rand.Reader, noncePrefix); err != nil {
+		return err
+	}
+
+	wrappedKey, err := provider.WrapKey(ctx, dataKey, opts.KeyWrapAlgorithm, opts.KeyName)
+	if err != nil {
+		return errors.Wrap(err, "failed to wrap the data encryption key")
+	}
+
+	header, err := json.Marshal(streamHeader{
+		KeyName:          opts.KeyName,
+		KeyWrapAlgorithm: opts.KeyWrapAlgorithm,
+		WrappedKey:       wrappedKey,
+		SegmentSize:      SegmentSize,
+		NoncePrefix:      noncePrefix,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(out, streamMagic); err != nil {
+		return err
+	}
+	if _, err = out.Write(append(header, '\n')); err != nil {
+		return err
+	}
+
+	aead, err := newStreamAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReaderSize(in, SegmentSize)
+	plaintext := make([]byte, SegmentSize)
+	ciphertext := make([]byte, 0, SegmentSize+aead.Overhead())
+	for i := uint32(0); ; i++ {
+		n, last, err := readSegment(r, plaintext)
+		if err != nil {
+			return err
+		}
+		ciphertext = aead.Seal(ciphertext[:0], segmentNonce(noncePrefix, i, last), plaintext[:n], header)
+		if _, err = out.Write(ciphertext); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		if i == ^uint32(0) {
+			return errors.New("stream is too long")
+		}
+	}
+}
+
+// Decrypt decrypts an encrypted stream read from in, and writes the plaintext to out.
+// The plaintext of a segment is only written after the segment has been authenticated,
+// but the plaintext of the first segments may have been written when an error is returned.
+func Decrypt(ctx context.Context, provider SubtleCrypto, out io.Writer, in io.Reader) error {
+	r := bufio.NewReaderSize(in, maxHeaderSize)
+
+	magic := make([]byte, len(streamMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != streamMagic {
+		return ErrInvalidStream
+	}
+	header, err := r.ReadSlice('\n')
+	if err != nil {
+		return ErrInvalidStream
+	}
+	header = append([]byte(nil), header[:len(header)-1]...)
+
+	var h streamHeader
+	if err = json.Unmarshal(header, &h); err != nil {
+		return ErrInvalidStream
+	}
+	if h.SegmentSize <= 0 || h.SegmentSize > SegmentSize || len(h.NoncePrefix) != noncePrefixSize || h.KeyName == "" {
+		return ErrInvalidStream
+	}
+
+	dataKey, err := provider.UnwrapKey(ctx, h.WrappedKey, h.KeyWrapAlgorithm, h.KeyName)
+	if err != nil {
+		return errors.Wrap(err, "failed to unwrap the data encryption key")
+	}
+	aead, err := newStreamAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	ciphertext := make([]byte, h.SegmentSize+aead.Overhead())
+	plaintext := make([]byte, 0, h.SegmentSize)
+	for i := uint32(0); ; i++ {
+		n, last, err := readSegment(r, ciphertext)
+		if err != nil {
+			return err
+		}
+		plaintext, err = aead.Open(plaintext[:0], segmentNonce(h.NoncePrefix, i, last), ciphertext[:n], header)
+		if err != nil {
+			return ErrInvalidStream
+		}
+		if _, err = out.Write(plaintext); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		if i == ^uint32(0) {
+			return ErrInvalidStream
+		}
+	}
+}
+
+// readSegment fills buf with the next segment, and reports whether it is the last one.
+func readSegment(r *bufio.Reader, buf []byte) (n int, last bool, err error) {
+	n, err = io.ReadFull(r, buf)
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return n, true, nil
+	case err != nil:
+		return n, false, err
+	}
+
+	// The segment is full: it is the last one if there is no more data
+	if _, err = r.Peek(1); errors.Is(err, io.EOF) {
+		return n, true, nil
+	} else if err != nil {
+		return n, false, err
+	}
+	return n, false, nil
+}
+
+func segmentNonce(prefix []byte, i uint32, last bool) []byte {
+	nonce := make([]byte, noncePrefixSize+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], i)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+func newStreamAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != dataKeySize {
+		return nil, errors.New("invalid data encryption key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xorCrypto wraps keys by xoring them with a fixed byte, which is enough to test the envelope.
+type xorCrypto struct{}
+
+func (xorCrypto) Init(metadata Metadata) error { return nil }
+
+func (xorCrypto) WrapKey(ctx context.Context, key []byte, algorithm string, keyName string) ([]byte, error) {
+	if keyName != "mykey" {
+		return nil, errors.New("key not found")
+	}
+	out := make([]byte, len(key))
+	for i := range key {
+		out[i] = key[i] ^ 0x5a
+	}
+	return out, nil
+}
+
+func (c xorCrypto) UnwrapKey(ctx context.Context, wrappedKey []byte, algorithm string, keyName string) ([]byte, error) {
+	return c.WrapKey(ctx, wrappedKey, algorithm, keyName)
+}
+
+func (xorCrypto) Sign(ctx context.Context, digest []byte, algorithm string, keyName string) ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+func (xorCrypto) Verify(ctx context.Context, digest []byte, signature []byte, algorithm string, keyName string) (bool, error) {
+	return false, errors.New("not supported")
+}
+
+func encryptForTest(t *testing.T, plaintext []byte) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	err := Encrypt(context.Background(), xorCrypto{}, &out, bytes.NewReader(plaintext), EncryptOptions{
+		KeyName:          "mykey",
+		KeyWrapAlgorithm: "xor",
+	})
+	require.NoError(t, err)
+	return out.Bytes()
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	sizes := map[string]int{
+		"empty":           0,
+		"small":           42,
+		"one segment":     SegmentSize,
+		"multi segments":  3*SegmentSize + 17,
+		"exact multiple":  2 * SegmentSize,
+		"one byte longer": SegmentSize + 1,
+	}
+
+	for name, size := range sizes {
+		t.Run(name, func(t *testing.T) {
+			plaintext := make([]byte, size)
+			_, err := rand.Read(plaintext)
+			require.NoError(t, err)
+
+			ciphertext := encryptForTest(t, plaintext)
+			assert.True(t, bytes.HasPrefix(ciphertext, []byte(streamMagic)))
+
+			var out bytes.Buffer
+			err = Decrypt(context.Background(), xorCrypto{}, &out, bytes.NewReader(ciphertext))
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(plaintext, out.Bytes()))
+		})
+	}
+}
+
+func TestEncryptMissingOptions(t *testing.T) {
+	var out bytes.Buffer
+	err := Encrypt(context.Background(), xorCrypto{}, &out, bytes.NewReader([]byte("hello")), EncryptOptions{KeyName: "mykey"})
+	assert.Error(t, err)
+	err = Encrypt(context.Background(), xorCrypto{}, &out, bytes.NewReader([]byte("hello")), EncryptOptions{KeyWrapAlgorithm: "xor"})
+	assert.Error(t, err)
+	assert.Zero(t, out.Len())
+}
+
+func TestDecryptInvalidStream(t *testing.T) {
+	plaintext := make([]byte, 2*SegmentSize+100)
+	_, err := rand.Read(plaintext)
+	require.NoError(t, err)
+	ciphertext := encryptForTest(t, plaintext)
+
+	decrypt := func(data []byte) error {
+		var out bytes.Buffer
+		return Decrypt(context.Background(), xorCrypto{}, &out, bytes.NewReader(data))
+	}
+
+	t.Run("not encrypted", func(t *testing.T) {
+		assert.ErrorIs(t, decrypt([]byte("hello world")), ErrInvalidStream)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		data := append([]byte(nil), ciphertext...)
+		data[len(data)-SegmentSize] ^= 1
+		assert.ErrorIs(t, decrypt(data), ErrInvalidStream)
+	})
+
+	t.Run("truncated at a segment boundary", func(t *testing.T) {
+		headerEnd := bytes.IndexByte(ciphertext[len(streamMagic):], '\n') + len(streamMagic) + 1
+		// Drop the last segment: the second segment is not marked as the last one
+		data := ciphertext[:headerEnd+2*(SegmentSize+16)]
+		assert.ErrorIs(t, decrypt(data), ErrInvalidStream)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		assert.ErrorIs(t, decrypt(ciphertext[:len(ciphertext)-1]), ErrInvalidStream)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		data := bytes.Replace(ciphertext, []byte(`"k":"mykey"`), []byte(`"k":"other"`), 1)
+		err := decrypt(data)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidStream)
+	})
+}
@@ -0,0 +1,45 @@
+package lock
+
+import (
+	"github.com/dapr/components-contrib/lock"
+)
+
+// FencingStore is implemented by the lock stores that return a fencing token when a lock is acquired.
+// Fencing tokens increase every time the lock of a resource is acquired, so the resources protected by the lock
+// can reject the writes of owners whose lock expired in the meantime.
+type FencingStore interface {
+	TryLockWithFencingToken(req *lock.TryLockRequest) (*TryLockResponse, error)
+}
+
+// TryLockResponse is the response of TryLock, with the fencing token of the lock.
+type TryLockResponse struct {
+	lock.TryLockResponse
+	// FencingToken is 0 if the lock was not acquired or the store doesn't support fencing tokens.
+	FencingToken int64 `json:"fencingToken,omitempty"`
+}
+
+// TryLock tries to acquire the lock, with a fencing token if the store supports them.
+// The response is never nil when the error is nil.
+func TryLock(store lock.Store, req *lock.TryLockRequest) (*TryLockResponse, error) {
+	var resp *TryLockResponse
+	if fs, ok := store.(FencingStore); ok {
+		var err error
+		resp, err = fs.TryLockWithFencingToken(req)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		compResp, err := store.TryLock(req)
+		if err != nil {
+			return nil, err
+		}
+		if compResp != nil {
+			resp = &TryLockResponse{TryLockResponse: *compResp}
+		}
+	}
+
+	if resp == nil {
+		resp = &TryLockResponse{}
+	}
+	return resp, nil
+}
@@ -0,0 +1,56 @@
+package lock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/lock"
+)
+
+type plainStore struct{}
+
+func (plainStore) InitLockStore(metadata lock.Metadata) error { return nil }
+
+func (plainStore) TryLock(req *lock.TryLockRequest) (*lock.TryLockResponse, error) {
+	return &lock.TryLockResponse{Success: true}, nil
+}
+
+func (plainStore) Unlock(req *lock.UnlockRequest) (*lock.UnlockResponse, error) {
+	return &lock.UnlockResponse{}, nil
+}
+
+type fencingStore struct {
+	plainStore
+	token int64
+}
+
+func (s *fencingStore) TryLockWithFencingToken(req *lock.TryLockRequest) (*TryLockResponse, error) {
+	s.token++
+	return &TryLockResponse{
+		TryLockResponse: lock.TryLockResponse{Success: true},
+		FencingToken:    s.token,
+	}, nil
+}
+
+func TestTryLock(t *testing.T) {
+	req := &lock.TryLockRequest{ResourceID: "resource", LockOwner: "owner", ExpiryInSeconds: 10}
+
+	t.Run("store without fencing tokens", func(t *testing.T) {
+		resp, err := TryLock(plainStore{}, req)
+		require.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.Zero(t, resp.FencingToken)
+	})
+
+	t.Run("store with fencing tokens", func(t *testing.T) {
+		store := &fencingStore{}
+		first, err := TryLock(store, req)
+		require.NoError(t, err)
+		second, err := TryLock(store, req)
+		require.NoError(t, err)
+		assert.True(t, first.Success)
+		assert.Greater(t, second.FencingToken, first.FencingToken)
+	})
+}
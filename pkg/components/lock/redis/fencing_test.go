@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/lock"
+	lockLoader "github.com/dapr/dapr/pkg/components/lock"
+)
+
+// fakeClient runs the script of TryLockWithFencingToken on in-memory locks.
+type fakeClient struct {
+	redis.UniversalClient
+
+	locks  map[string]string
+	tokens map[string]int64
+	err    error
+}
+
+func (c *fakeClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	if c.err != nil {
+		return redis.NewCmdResult(nil, c.err)
+	}
+	if script != tryLockScript {
+		return redis.NewCmdResult(nil, errors.New("unexpected script"))
+	}
+	if _, ok := c.locks[keys[0]]; ok {
+		return redis.NewCmdResult(int64(0), nil)
+	}
+	c.locks[keys[0]] = args[0].(string)
+	c.tokens[keys[1]]++
+	return redis.NewCmdResult(c.tokens[keys[1]], nil)
+}
+
+func (c *fakeClient) Close() error {
+	return nil
+}
+
+func TestFencingRedisLock(t *testing.T) {
+	client := &fakeClient{
+		locks:  map[string]string{},
+		tokens: map[string]int64{},
+	}
+	store := &FencingRedisLock{client: client}
+	req := &lock.TryLockRequest{ResourceID: "resource", LockOwner: "owner1", ExpiryInSeconds: 10}
+
+	first, err := lockLoader.TryLock(store, req)
+	require.NoError(t, err)
+	assert.True(t, first.Success)
+	assert.Equal(t, int64(1), first.FencingToken)
+
+	t.Run("lock held by another owner", func(t *testing.T) {
+		resp, err := lockLoader.TryLock(store, &lock.TryLockRequest{ResourceID: "resource", LockOwner: "owner2", ExpiryInSeconds: 10})
+		require.NoError(t, err)
+		assert.False(t, resp.Success)
+		assert.Zero(t, resp.FencingToken)
+	})
+
+	t.Run("token increases when the lock is acquired again", func(t *testing.T) {
+		// The lock expired or was released
+		delete(client.locks, "resource")
+
+		resp, err := lockLoader.TryLock(store, &lock.TryLockRequest{ResourceID: "resource", LockOwner: "owner2", ExpiryInSeconds: 10})
+		require.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.Equal(t, int64(2), resp.FencingToken)
+		assert.Equal(t, "owner2", client.locks["resource"])
+	})
+
+	t.Run("tokens are per resource", func(t *testing.T) {
+		resp, err := lockLoader.TryLock(store, &lock.TryLockRequest{ResourceID: "other", LockOwner: "owner1", ExpiryInSeconds: 10})
+		require.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.Equal(t, int64(1), resp.FencingToken)
+	})
+
+	t.Run("redis error", func(t *testing.T) {
+		client.err = errors.New("connection refused")
+		defer func() { client.err = nil }()
+
+		_, err := lockLoader.TryLock(store, req)
+		require.Error(t, err)
+		resp, err := store.TryLock(req)
+		require.Error(t, err)
+		assert.False(t, resp.Success)
+	})
+
+	t.Run("not initialized", func(t *testing.T) {
+		_, err := (&FencingRedisLock{}).TryLockWithFencingToken(req)
+		require.Error(t, err)
+	})
+}
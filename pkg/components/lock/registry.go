@@ -46,20 +46,20 @@ func (r *Registry) getStore(name, version string) (func() lock.Store, bool) {
 	versionLower := strings.ToLower(version)
 	factoryMethod, ok := r.stores[nameLower+"/"+versionLower]
 	if ok {
-		return r.wrapFn(factoryMethod), true
+		return r.wrapFn(factoryMethod, nameLower), true
 	}
 	if components.IsInitialVersion(versionLower) {
 		factoryMethod, ok = r.stores[nameLower]
 		if ok {
-			return r.wrapFn(factoryMethod), true
+			return r.wrapFn(factoryMethod, nameLower), true
 		}
 	}
 	return nil, false
 }
 
-func (r *Registry) wrapFn(componentFactory func(logger.Logger) lock.Store) func() lock.Store {
+func (r *Registry) wrapFn(componentFactory func(logger.Logger) lock.Store, name string) func() lock.Store {
 	return func() lock.Store {
-		return componentFactory(r.Logger)
+		return componentFactory(components.Logger(r.Logger, name))
 	}
 }
 
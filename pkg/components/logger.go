@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"github.com/dapr/kit/logger"
+
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+)
+
+// ContribLoggerScope is the scope of the logger shared by the components.
+const ContribLoggerScope = "dapr.contrib"
+
+var contribLogger = logger.NewLogger(ContribLoggerScope)
+
+// Logger returns the logger of a component of the type, such as pubsub.kafka.
+// The components share the contrib logger, unless the log level of the dapr.contrib.<type> scope is overridden, so
+// the logs of a type of components can be set apart from the others. The other loggers are returned as is.
+func Logger(l logger.Logger, componentType string) logger.Logger {
+	if l != contribLogger {
+		return l
+	}
+	scope := ContribLoggerScope + "." + componentType
+	if !diag.DefaultRuntimeLogging.HasScopeLevel(scope) {
+		return l
+	}
+	return logger.NewLogger(scope)
+}
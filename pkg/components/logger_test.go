@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/kit/logger"
+
+	"github.com/dapr/dapr/pkg/components"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+)
+
+func TestLogger(t *testing.T) {
+	contrib := logger.NewLogger(components.ContribLoggerScope)
+	require.NoError(t, diag.DefaultRuntimeLogging.SetScopeLevels(map[string]string{"dapr.contrib.pubsub.kafka": "debug"}))
+	defer diag.DefaultRuntimeLogging.SetScopeLevels(nil)
+
+	t.Run("type with a log level", func(t *testing.T) {
+		assert.Equal(t, logger.NewLogger("dapr.contrib.pubsub.kafka"), components.Logger(contrib, "pubsub.kafka"))
+	})
+
+	t.Run("type without a log level", func(t *testing.T) {
+		assert.Equal(t, contrib, components.Logger(contrib, "pubsub.redis"))
+	})
+
+	t.Run("other logger", func(t *testing.T) {
+		l := logger.NewLogger("dapr.test")
+		assert.Equal(t, l, components.Logger(l, "pubsub.kafka"))
+	})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dnssrv contains the name resolver looking up the addresses of the apps in DNS SRV records,
+// for the self-hosted and VM deployments where mDNS and the Kubernetes DNS are unavailable.
+package dnssrv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	nr "github.com/dapr/components-contrib/nameresolution"
+	"github.com/dapr/kit/config"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// DefaultRecordName is the name of the SRV records of the apps, resolved with the search domains of the host.
+	DefaultRecordName = "_dapr._tcp.{appID}"
+	// DefaultTimeout is the timeout of the lookups.
+	DefaultTimeout = 5 * time.Second
+
+	appIDPlaceholder     = "{appID}"
+	namespacePlaceholder = "{namespace}"
+)
+
+// configSpec is the configuration of the resolver in the nameResolution spec of the Configuration.
+type configSpec struct {
+	// RecordName is the template of the name of the SRV records, where {appID} and {namespace} are replaced
+	// with the app ID and the namespace of the app.
+	RecordName string `json:"recordName"`
+	// DNSServer is the address of the DNS server, the servers of the host are used if empty.
+	DNSServer string `json:"dnsServer"`
+	// Timeout of the lookups, as a duration string.
+	Timeout string `json:"timeout"`
+}
+
+type resolver struct {
+	logger     logger.Logger
+	recordName string
+	timeout    time.Duration
+	lookupSRV  func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// NewResolver creates the DNS SRV name resolver.
+func NewResolver(logger logger.Logger) nr.Resolver {
+	return &resolver{logger: logger}
+}
+
+// Init parses the configuration of the resolver.
+func (r *resolver) Init(metadata nr.Metadata) error {
+	spec, err := parseConfig(metadata.Configuration)
+	if err != nil {
+		return err
+	}
+
+	r.recordName = spec.RecordName
+	if r.recordName == "" {
+		r.recordName = DefaultRecordName
+	}
+	if !strings.Contains(r.recordName, appIDPlaceholder) {
+		return errors.Errorf("invalid dns srv record name %s: missing %s", r.recordName, appIDPlaceholder)
+	}
+
+	r.timeout = DefaultTimeout
+	if spec.Timeout != "" {
+		r.timeout, err = time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return errors.Wrap(err, "invalid dns srv timeout")
+		}
+	}
+
+	netResolver := net.DefaultResolver
+	if spec.DNSServer != "" {
+		server := spec.DNSServer
+		if _, _, err = net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		netResolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+	r.lookupSRV = netResolver.LookupSRV
+
+	r.logger.Infof("DNS SRV name resolution looking up %s", r.recordName)
+	return nil
+}
+
+// ResolveID returns the address of the target of the SRV record of the app with the lowest priority.
+// The records of the same priority are picked at random, proportionally to their weight.
+func (r *resolver) ResolveID(req nr.ResolveRequest) (string, error) {
+	name := strings.NewReplacer(appIDPlaceholder, req.ID, namespacePlaceholder, req.Namespace).Replace(r.recordName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	// The name is looked up directly, without the _service._proto prefix.
+	_, records, err := r.lookupSRV(ctx, "", "", name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to look up the dns srv records %s", name)
+	}
+	if len(records) == 0 {
+		return "", errors.Errorf("no dns srv records %s", name)
+	}
+
+	// The records are sorted by priority and randomized by weight.
+	record := records[0]
+	return net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))), nil
+}
+
+func parseConfig(rawConfig interface{}) (configSpec, error) {
+	var result configSpec
+	if rawConfig == nil {
+		return result, nil
+	}
+	rawConfig, err := config.Normalize(rawConfig)
+	if err != nil {
+		return result, err
+	}
+
+	data, err := json.Marshal(rawConfig)
+	if err != nil {
+		return result, errors.Wrap(err, "error serializing dns srv configuration")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&result); err != nil {
+		return result, errors.Wrap(err, "error parsing dns srv configuration")
+	}
+	return result, nil
+}
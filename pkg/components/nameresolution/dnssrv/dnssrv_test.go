@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnssrv
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nr "github.com/dapr/components-contrib/nameresolution"
+	"github.com/dapr/kit/logger"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("default configuration", func(t *testing.T) {
+		r := NewResolver(logger.NewLogger("test")).(*resolver)
+		require.NoError(t, r.Init(nr.Metadata{}))
+		assert.Equal(t, DefaultRecordName, r.recordName)
+		assert.Equal(t, DefaultTimeout, r.timeout)
+	})
+
+	t.Run("configuration", func(t *testing.T) {
+		r := NewResolver(logger.NewLogger("test")).(*resolver)
+		require.NoError(t, r.Init(nr.Metadata{Configuration: map[interface{}]interface{}{
+			"recordName": "_dapr._tcp.{appID}.{namespace}.example.com",
+			"dnsServer":  "10.0.0.2",
+			"timeout":    "1s",
+		}}))
+		assert.Equal(t, "_dapr._tcp.{appID}.{namespace}.example.com", r.recordName)
+		assert.Equal(t, time.Second, r.timeout)
+	})
+
+	t.Run("record name without app id", func(t *testing.T) {
+		r := NewResolver(logger.NewLogger("test")).(*resolver)
+		assert.Error(t, r.Init(nr.Metadata{Configuration: map[string]interface{}{"recordName": "_dapr._tcp.example.com"}}))
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		r := NewResolver(logger.NewLogger("test")).(*resolver)
+		assert.Error(t, r.Init(nr.Metadata{Configuration: map[string]interface{}{"timeout": "soon"}}))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		r := NewResolver(logger.NewLogger("test")).(*resolver)
+		assert.Error(t, r.Init(nr.Metadata{Configuration: map[string]interface{}{"domain": "example.com"}}))
+	})
+}
+
+func TestResolveID(t *testing.T) {
+	r := NewResolver(logger.NewLogger("test")).(*resolver)
+	require.NoError(t, r.Init(nr.Metadata{Configuration: map[string]interface{}{
+		"recordName": "_dapr._tcp.{appID}.{namespace}.example.com",
+	}}))
+
+	var lookedUp string
+	records := map[string][]*net.SRV{
+		"_dapr._tcp.app1.default.example.com": {
+			{Target: "host1.example.com.", Port: 50002, Priority: 10},
+			{Target: "host2.example.com.", Port: 50003, Priority: 20},
+		},
+		"_dapr._tcp.app2.default.example.com": {},
+	}
+	r.lookupSRV = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		lookedUp = name
+		srvs, ok := records[name]
+		if !ok {
+			return "", nil, errors.New("no such host")
+		}
+		return name, srvs, nil
+	}
+
+	t.Run("first record", func(t *testing.T) {
+		address, err := r.ResolveID(nr.ResolveRequest{ID: "app1", Namespace: "default"})
+		require.NoError(t, err)
+		assert.Equal(t, "_dapr._tcp.app1.default.example.com", lookedUp)
+		assert.Equal(t, "host1.example.com:50002", address)
+	})
+
+	t.Run("no records", func(t *testing.T) {
+		_, err := r.ResolveID(nr.ResolveRequest{ID: "app2", Namespace: "default"})
+		assert.Error(t, err)
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		_, err := r.ResolveID(nr.ResolveRequest{ID: "app3", Namespace: "default"})
+		assert.ErrorContains(t, err, "no such host")
+	})
+}
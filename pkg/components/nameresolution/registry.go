@@ -71,20 +71,20 @@ func (s *Registry) getResolver(name, version string) (func() nr.Resolver, bool)
 	versionLower := strings.ToLower(version)
 	resolverFn, ok := s.resolvers[nameLower+"/"+versionLower]
 	if ok {
-		return s.wrapFn(resolverFn), true
+		return s.wrapFn(resolverFn, nameLower), true
 	}
 	if components.IsInitialVersion(versionLower) {
 		resolverFn, ok = s.resolvers[nameLower]
 		if ok {
-			return s.wrapFn(resolverFn), true
+			return s.wrapFn(resolverFn, nameLower), true
 		}
 	}
 	return nil, false
 }
 
-func (s *Registry) wrapFn(componentFactory FactoryMethod) func() nr.Resolver {
+func (s *Registry) wrapFn(componentFactory FactoryMethod, name string) func() nr.Resolver {
 	return func() nr.Resolver {
-		return componentFactory(s.Logger)
+		return componentFactory(components.Logger(s.Logger, name))
 	}
 }
 
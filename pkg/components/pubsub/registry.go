@@ -62,20 +62,20 @@ func (p *Registry) getPubSub(name, version string) (func() pubsub.PubSub, bool)
 	versionLower := strings.ToLower(version)
 	pubSubFn, ok := p.messageBuses[nameLower+"/"+versionLower]
 	if ok {
-		return p.wrapFn(pubSubFn), true
+		return p.wrapFn(pubSubFn, nameLower), true
 	}
 	if components.IsInitialVersion(versionLower) {
 		pubSubFn, ok = p.messageBuses[nameLower]
 		if ok {
-			return p.wrapFn(pubSubFn), true
+			return p.wrapFn(pubSubFn, nameLower), true
 		}
 	}
 	return nil, false
 }
 
-func (p *Registry) wrapFn(componentFactory func(logger.Logger) pubsub.PubSub) func() pubsub.PubSub {
+func (p *Registry) wrapFn(componentFactory func(logger.Logger) pubsub.PubSub, name string) func() pubsub.PubSub {
 	return func() pubsub.PubSub {
-		return componentFactory(p.Logger)
+		return componentFactory(components.Logger(p.Logger, name))
 	}
 }
 
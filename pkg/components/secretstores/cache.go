@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstores
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/secretstores"
+)
+
+// CacheTTLKey is the metadata property of secret store components with the time to live of the secrets cached by daprd.
+// Secrets are not cached when it is not set.
+const CacheTTLKey = "cacheTTL"
+
+// GetCacheTTL returns the time to live of the cached secrets from the properties of a secret store component.
+func GetCacheTTL(properties map[string]string) (time.Duration, error) {
+	val := strings.TrimSpace(properties[CacheTTLKey])
+	if val == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid value for metadata property %s", CacheTTLKey)
+	}
+	if ttl < 0 {
+		return 0, errors.Errorf("metadata property %s must not be negative", CacheTTLKey)
+	}
+	return ttl, nil
+}
+
+type cacheEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// cachedSecretStore is a secret store that caches the responses of another secret store.
+// Errors are not cached.
+type cachedSecretStore struct {
+	secretstores.SecretStore
+	ttl   time.Duration
+	clock func() time.Time
+
+	lock    sync.RWMutex
+	secrets map[string]cacheEntry[secretstores.GetSecretResponse]
+	bulk    map[string]cacheEntry[secretstores.BulkGetSecretResponse]
+}
+
+// NewCachedSecretStore returns a secret store that caches the secrets returned by the store for the ttl.
+func NewCachedSecretStore(store secretstores.SecretStore, ttl time.Duration) secretstores.SecretStore {
+	return &cachedSecretStore{
+		SecretStore: store,
+		ttl:         ttl,
+		clock:       time.Now,
+		secrets:     map[string]cacheEntry[secretstores.GetSecretResponse]{},
+		bulk:        map[string]cacheEntry[secretstores.BulkGetSecretResponse]{},
+	}
+}
+
+func (c *cachedSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	key := cacheKey(req.Name, req.Metadata)
+	if res, ok := getCached(c, c.secrets, key); ok {
+		return res, nil
+	}
+
+	res, err := c.SecretStore.GetSecret(req)
+	if err != nil {
+		return res, err
+	}
+	setCached(c, c.secrets, key, res)
+	return res, nil
+}
+
+func (c *cachedSecretStore) BulkGetSecret(req secretstores.BulkGetSecretRequest) (secretstores.BulkGetSecretResponse, error) {
+	key := cacheKey("", req.Metadata)
+	if res, ok := getCached(c, c.bulk, key); ok {
+		return res, nil
+	}
+
+	res, err := c.SecretStore.BulkGetSecret(req)
+	if err != nil {
+		return res, err
+	}
+	setCached(c, c.bulk, key, res)
+	return res, nil
+}
+
+// Ping forwards the health checks to the secret store.
+func (c *cachedSecretStore) Ping() error {
+	return secretstores.Ping(c.SecretStore)
+}
+
+func getCached[T any](c *cachedSecretStore, cache map[string]cacheEntry[T], key string) (T, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	entry, ok := cache[key]
+	if !ok || !c.clock().Before(entry.expires) {
+		var zero T
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func setCached[T any](c *cachedSecretStore, cache map[string]cacheEntry[T], key string, value T) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock()
+	// Drop the expired entries, so secrets that are not requested anymore don't stay in memory
+	for k, entry := range cache {
+		if !now.Before(entry.expires) {
+			delete(cache, k)
+		}
+	}
+	cache[key] = cacheEntry[T]{value: value, expires: now.Add(c.ttl)}
+}
+
+// cacheKey returns the key of a request in the cache: the responses depend on the metadata of the requests.
+func cacheKey(name string, metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteString("\x00")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(metadata[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstores
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/secretstores"
+)
+
+// countingSecretStore counts the calls to the secret store.
+type countingSecretStore struct {
+	calls int
+	err   error
+}
+
+func (s *countingSecretStore) Init(metadata secretstores.Metadata) error {
+	return nil
+}
+
+func (s *countingSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return secretstores.GetSecretResponse{}, s.err
+	}
+	return secretstores.GetSecretResponse{Data: map[string]string{req.Name: req.Metadata["version"]}}, nil
+}
+
+func (s *countingSecretStore) BulkGetSecret(req secretstores.BulkGetSecretRequest) (secretstores.BulkGetSecretResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return secretstores.BulkGetSecretResponse{}, s.err
+	}
+	return secretstores.BulkGetSecretResponse{Data: map[string]map[string]string{"key": {"key": "value"}}}, nil
+}
+
+func TestGetCacheTTL(t *testing.T) {
+	ttl, err := GetCacheTTL(map[string]string{})
+	assert.NoError(t, err)
+	assert.Zero(t, ttl)
+
+	ttl, err = GetCacheTTL(map[string]string{CacheTTLKey: "5m"})
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, ttl)
+
+	_, err = GetCacheTTL(map[string]string{CacheTTLKey: "five minutes"})
+	assert.Error(t, err)
+
+	_, err = GetCacheTTL(map[string]string{CacheTTLKey: "-1s"})
+	assert.Error(t, err)
+}
+
+func TestCachedSecretStore(t *testing.T) {
+	now := time.Now()
+	store := &countingSecretStore{}
+	cached := NewCachedSecretStore(store, time.Minute).(*cachedSecretStore)
+	cached.clock = func() time.Time { return now }
+
+	t.Run("secrets are cached until they expire", func(t *testing.T) {
+		req := secretstores.GetSecretRequest{Name: "key", Metadata: map[string]string{"version": "1"}}
+		res, err := cached.GetSecret(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", res.Data["key"])
+
+		_, err = cached.GetSecret(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, store.calls)
+
+		now = now.Add(time.Minute)
+		_, err = cached.GetSecret(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, store.calls)
+	})
+
+	t.Run("metadata is part of the cache key", func(t *testing.T) {
+		store.calls = 0
+		res, err := cached.GetSecret(secretstores.GetSecretRequest{Name: "key", Metadata: map[string]string{"version": "2"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "2", res.Data["key"])
+		assert.Equal(t, 1, store.calls)
+	})
+
+	t.Run("bulk responses are cached", func(t *testing.T) {
+		store.calls = 0
+		for i := 0; i < 3; i++ {
+			res, err := cached.BulkGetSecret(secretstores.BulkGetSecretRequest{})
+			assert.NoError(t, err)
+			assert.Equal(t, "value", res.Data["key"]["key"])
+		}
+		assert.Equal(t, 1, store.calls)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		store.calls = 0
+		store.err = errors.New("unavailable")
+		req := secretstores.GetSecretRequest{Name: "other"}
+		_, err := cached.GetSecret(req)
+		assert.Error(t, err)
+
+		store.err = nil
+		_, err = cached.GetSecret(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, store.calls)
+	})
+}
@@ -67,20 +67,20 @@ func (s *Registry) getSecretStore(name, version string) (func() secretstores.Sec
 	versionLower := strings.ToLower(version)
 	secretStoreFn, ok := s.secretStores[nameLower+"/"+versionLower]
 	if ok {
-		return s.wrapFn(secretStoreFn), true
+		return s.wrapFn(secretStoreFn, nameLower), true
 	}
 	if components.IsInitialVersion(versionLower) {
 		secretStoreFn, ok = s.secretStores[nameLower]
 		if ok {
-			return s.wrapFn(secretStoreFn), true
+			return s.wrapFn(secretStoreFn, nameLower), true
 		}
 	}
 	return nil, false
 }
 
-func (s *Registry) wrapFn(componentFactory func(logger.Logger) secretstores.SecretStore) func() secretstores.SecretStore {
+func (s *Registry) wrapFn(componentFactory func(logger.Logger) secretstores.SecretStore, name string) func() secretstores.SecretStore {
 	return func() secretstores.SecretStore {
-		return componentFactory(s.Logger)
+		return componentFactory(components.Logger(s.Logger, name))
 	}
 }
 
@@ -45,9 +45,38 @@ func NewStandaloneComponents(configuration config.StandaloneConfig) *StandaloneC
 	}
 }
 
-// LoadComponents loads dapr components from a given directory.
+// LoadComponents loads dapr components from the resources directories.
+// A component defined in several directories is loaded from the last one, so app-specific components can override shared ones.
 func (s *StandaloneComponents) LoadComponents() ([]componentsV1alpha1.Component, error) {
-	files, err := os.ReadDir(s.config.ComponentsPath)
+	list := []componentsV1alpha1.Component{}
+	// Index of the components in the list, and the directories they were loaded from
+	indexes := map[string]int{}
+	dirs := map[string]string{}
+
+	for _, dir := range s.config.ResourcesPath {
+		components, err := s.loadComponentsFromDir(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, comp := range components {
+			if i, ok := indexes[comp.Name]; ok {
+				log.Infof("component %s in %s overrides the one in %s", comp.Name, dir, dirs[comp.Name])
+				list[i] = comp
+			} else {
+				indexes[comp.Name] = len(list)
+				list = append(list, comp)
+			}
+			dirs[comp.Name] = dir
+		}
+	}
+
+	return list, nil
+}
+
+// loadComponentsFromDir loads dapr components from a given directory.
+func (s *StandaloneComponents) loadComponentsFromDir(dir string) ([]componentsV1alpha1.Component, error) {
+	files, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +89,7 @@ func (s *StandaloneComponents) LoadComponents() ([]componentsV1alpha1.Component,
 				log.Warnf("A non-YAML component file %s was detected, it will not be loaded", file.Name())
 				continue
 			}
-			components := s.loadComponentsFromFile(file.Name())
+			components := s.loadComponentsFromFile(dir, file.Name())
 			if len(components) > 0 {
 				list = append(list, components...)
 			}
@@ -70,11 +99,11 @@ func (s *StandaloneComponents) LoadComponents() ([]componentsV1alpha1.Component,
 	return list, nil
 }
 
-func (s *StandaloneComponents) loadComponentsFromFile(filename string) []componentsV1alpha1.Component {
+func (s *StandaloneComponents) loadComponentsFromFile(dir, filename string) []componentsV1alpha1.Component {
 	var errors []error
 
 	components := []componentsV1alpha1.Component{}
-	path := filepath.Join(s.config.ComponentsPath, filename)
+	path := filepath.Join(dir, filename)
 
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -20,7 +20,7 @@ func writeTempConfig(path, content string) error {
 func TestLoadComponentsFromFile(t *testing.T) {
 	request := &StandaloneComponents{
 		config: config.StandaloneConfig{
-			ComponentsPath: configPrefix,
+			ResourcesPath: []string{configPrefix},
 		},
 	}
 	t.Run("valid yaml content", func(t *testing.T) {
@@ -41,7 +41,7 @@ spec:
 		err := writeTempConfig(filename, yaml)
 		// defer os.Remove(filename)
 		assert.Nil(t, err)
-		components := request.loadComponentsFromFile(filename)
+		components := request.loadComponentsFromFile(configPrefix, filename)
 		assert.Len(t, components, 1)
 	})
 
@@ -56,14 +56,14 @@ name: statestore`
 		err := writeTempConfig(filename, yaml)
 		defer os.Remove(filename)
 		assert.Nil(t, err)
-		components := request.loadComponentsFromFile(filename)
+		components := request.loadComponentsFromFile(configPrefix, filename)
 		assert.Len(t, components, 0)
 	})
 
 	t.Run("load components file not exist", func(t *testing.T) {
 		filename := "test-component-no-exist.yaml"
 
-		components := request.loadComponentsFromFile(filename)
+		components := request.loadComponentsFromFile(configPrefix, filename)
 		assert.Len(t, components, 0)
 	})
 }
@@ -71,7 +71,7 @@ name: statestore`
 func TestStandaloneDecodeValidYaml(t *testing.T) {
 	request := &StandaloneComponents{
 		config: config.StandaloneConfig{
-			ComponentsPath: "test_component_path",
+			ResourcesPath: []string{"test_component_path"},
 		},
 	}
 	yaml := `
@@ -110,7 +110,7 @@ spec:
 func TestStandaloneDecodeInvalidComponent(t *testing.T) {
 	request := &StandaloneComponents{
 		config: config.StandaloneConfig{
-			ComponentsPath: "test_component_path",
+			ResourcesPath: []string{"test_component_path"},
 		},
 	}
 	yaml := `
@@ -141,7 +141,7 @@ spec:
 func TestStandaloneDecodeUnsuspectingFile(t *testing.T) {
 	request := &StandaloneComponents{
 		config: config.StandaloneConfig{
-			ComponentsPath: "test_component_path",
+			ResourcesPath: []string{"test_component_path"},
 		},
 	}
 
@@ -153,7 +153,7 @@ func TestStandaloneDecodeUnsuspectingFile(t *testing.T) {
 func TestStandaloneDecodeInvalidYaml(t *testing.T) {
 	request := &StandaloneComponents{
 		config: config.StandaloneConfig{
-			ComponentsPath: "test_component_path",
+			ResourcesPath: []string{"test_component_path"},
 		},
 	}
 	yaml := `
@@ -170,7 +170,7 @@ name: statestore`
 func TestStandaloneDecodeValidMultiYaml(t *testing.T) {
 	request := &StandaloneComponents{
 		config: config.StandaloneConfig{
-			ComponentsPath: "test_component_path",
+			ResourcesPath: []string{"test_component_path"},
 		},
 	}
 	yaml := `
@@ -217,7 +217,7 @@ spec:
 func TestStandaloneDecodeInValidDocInMultiYaml(t *testing.T) {
 	request := &StandaloneComponents{
 		config: config.StandaloneConfig{
-			ComponentsPath: "test_component_path",
+			ResourcesPath: []string{"test_component_path"},
 		},
 	}
 	yaml := `
@@ -267,3 +267,41 @@ spec:
 	assert.Equal(t, "prop3", components[1].Spec.Metadata[0].Name)
 	assert.Equal(t, "value3", components[1].Spec.Metadata[0].Value.String())
 }
+
+func TestLoadComponentsFromResourcesPaths(t *testing.T) {
+	component := func(name, componentType string) string {
+		return `
+apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: ` + name + `
+spec:
+  type: ` + componentType + `
+`
+	}
+
+	shared := t.TempDir()
+	app := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(shared, "statestore.yaml"), []byte(component("statestore", "state.redis")), fs.FileMode(0o644)))
+	assert.NoError(t, os.WriteFile(filepath.Join(shared, "pubsub.yaml"), []byte(component("pubsub", "pubsub.redis")), fs.FileMode(0o644)))
+	assert.NoError(t, os.WriteFile(filepath.Join(app, "statestore.yaml"), []byte(component("statestore", "state.in-memory")), fs.FileMode(0o644)))
+
+	t.Run("the components of the later directories take precedence", func(t *testing.T) {
+		loader := NewStandaloneComponents(config.StandaloneConfig{ResourcesPath: []string{shared, app}})
+		components, err := loader.LoadComponents()
+		assert.NoError(t, err)
+		if assert.Len(t, components, 2) {
+			types := map[string]string{}
+			for _, c := range components {
+				types[c.Name] = c.Spec.Type
+			}
+			assert.Equal(t, map[string]string{"statestore": "state.in-memory", "pubsub": "pubsub.redis"}, types)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		loader := NewStandaloneComponents(config.StandaloneConfig{ResourcesPath: []string{shared, filepath.Join(app, "missing")}})
+		_, err := loader.LoadComponents()
+		assert.Error(t, err)
+	})
+}
@@ -62,20 +62,20 @@ func (s *Registry) getStateStore(name, version string) (func() state.Store, bool
 	versionLower := strings.ToLower(version)
 	stateStoreFn, ok := s.stateStores[nameLower+"/"+versionLower]
 	if ok {
-		return s.wrapFn(stateStoreFn), true
+		return s.wrapFn(stateStoreFn, nameLower), true
 	}
 	if components.IsInitialVersion(versionLower) {
 		stateStoreFn, ok = s.stateStores[nameLower]
 		if ok {
-			return s.wrapFn(stateStoreFn), true
+			return s.wrapFn(stateStoreFn, nameLower), true
 		}
 	}
 	return nil, false
 }
 
-func (s *Registry) wrapFn(componentFactory func(logger.Logger) state.Store) func() state.Store {
+func (s *Registry) wrapFn(componentFactory func(logger.Logger) state.Store, name string) func() state.Store {
 	return func() state.Store {
-		return componentFactory(s.Logger)
+		return componentFactory(components.Logger(s.Logger, name))
 	}
 }
 
@@ -0,0 +1,290 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package router contains the state store routing the requests of a logical state store to the state stores
+// of the prefixes of the keys, such as the hot keys to Redis and the cold keys to PostgreSQL.
+//
+// The requests on a single key are sent to its state store. The bulk and transactional requests are split by state store,
+// and the requests of each state store keep their semantics, including the transactions. The requests spanning several
+// state stores are not atomic: the state stores are called one after the other in the order of their first key, and when
+// one fails the changes of the state stores called before it are kept and reported in a PartialFailureError.
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/state"
+	stateLoader "github.com/dapr/dapr/pkg/components/state"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// ComponentType is the type of the router components.
+	ComponentType = "state.router"
+
+	// routesKey is the metadata with the comma separated list of prefix=store routes.
+	routesKey = "routes"
+	// defaultStoreKey is the metadata with the state store of the keys matching no route.
+	defaultStoreKey = "defaultStore"
+)
+
+// Route sends the keys starting with the prefix to the state store.
+type Route struct {
+	Prefix string
+	Store  string
+}
+
+// Config is the config of the router in the metadata of the component.
+type Config struct {
+	// Routes sorted from the longest prefix, which takes precedence, to the shortest.
+	Routes       []Route
+	DefaultStore string
+}
+
+// ParseConfig parses the routes and the default state store of the metadata of the component.
+func ParseConfig(metadata map[string]string) (Config, error) {
+	config := Config{DefaultStore: metadata[defaultStoreKey]}
+	if config.DefaultStore == "" {
+		return config, errors.Errorf("invalid state router: missing %s", defaultStoreKey)
+	}
+
+	for _, r := range strings.Split(metadata[routesKey], ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		prefix, store, ok := strings.Cut(r, "=")
+		prefix, store = strings.TrimSpace(prefix), strings.TrimSpace(store)
+		if !ok || prefix == "" || store == "" {
+			return config, errors.Errorf("invalid state router route %s: expected prefix=store", r)
+		}
+		config.Routes = append(config.Routes, Route{Prefix: prefix, Store: store})
+	}
+	sort.SliceStable(config.Routes, func(i, j int) bool {
+		return len(config.Routes[i].Prefix) > len(config.Routes[j].Prefix)
+	})
+	return config, nil
+}
+
+// Stores returns the names of the state stores the router sends requests to.
+func (c Config) Stores() []string {
+	stores := []string{c.DefaultStore}
+	for _, r := range c.Routes {
+		found := false
+		for _, s := range stores {
+			found = found || s == r.Store
+		}
+		if !found {
+			stores = append(stores, r.Store)
+		}
+	}
+	return stores
+}
+
+// storeOf returns the name of the state store of the key, as modified by the key prefix strategy of the router.
+func (c Config) storeOf(key string) string {
+	key = stateLoader.GetOriginalStateKey(key)
+	for _, r := range c.Routes {
+		if strings.HasPrefix(key, r.Prefix) {
+			return r.Store
+		}
+	}
+	return c.DefaultStore
+}
+
+// PartialFailureError is returned when a request spanning several state stores fails after changing some of them.
+type PartialFailureError struct {
+	// Committed are the state stores whose changes are kept.
+	Committed []string
+	// Failed is the state store whose request failed.
+	Failed string
+	Err    error
+}
+
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("state store %s failed after the changes of state stores %s: %s", e.Failed, strings.Join(e.Committed, ", "), e.Err)
+}
+
+func (e *PartialFailureError) Unwrap() error {
+	return e.Err
+}
+
+type router struct {
+	logger   logger.Logger
+	getStore func(name string) (state.Store, bool)
+	config   Config
+	stores   map[string]state.Store
+	features []state.Feature
+}
+
+// NewRouter creates the state router, which gets the state stores it routes to with getStore when it is initialized.
+func NewRouter(logger logger.Logger, getStore func(name string) (state.Store, bool)) state.Store {
+	return &router{logger: logger, getStore: getStore}
+}
+
+// Init parses the routes and gets their state stores. The features are the ones of all the state stores.
+func (r *router) Init(metadata state.Metadata) error {
+	config, err := ParseConfig(metadata.Properties)
+	if err != nil {
+		return err
+	}
+
+	r.config = config
+	r.stores = map[string]state.Store{}
+	r.features = nil
+	for i, name := range config.Stores() {
+		store, ok := r.getStore(name)
+		if !ok {
+			return errors.Errorf("invalid state router: state store %s not found", name)
+		}
+		if _, ok = store.(*router); ok {
+			return errors.Errorf("invalid state router: state store %s is a router", name)
+		}
+		r.stores[name] = store
+
+		if i == 0 {
+			r.features = append(r.features, store.Features()...)
+			continue
+		}
+		features := r.features[:0]
+		for _, f := range r.features {
+			if f.IsPresent(store.Features()) {
+				features = append(features, f)
+			}
+		}
+		r.features = features
+	}
+
+	r.logger.Infof("state router routing %d prefixes, and the other keys to state store %s", len(config.Routes), config.DefaultStore)
+	return nil
+}
+
+func (r *router) Features() []state.Feature {
+	return r.features
+}
+
+// Ping pings all the state stores.
+func (r *router) Ping() error {
+	for _, name := range r.config.Stores() {
+		if err := state.Ping(r.stores[name]); err != nil {
+			return errors.Wrapf(err, "state store %s", name)
+		}
+	}
+	return nil
+}
+
+func (r *router) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	return r.stores[r.config.storeOf(req.Key)].Get(req)
+}
+
+func (r *router) Set(req *state.SetRequest) error {
+	return r.stores[r.config.storeOf(req.Key)].Set(req)
+}
+
+func (r *router) Delete(req *state.DeleteRequest) error {
+	return r.stores[r.config.storeOf(req.Key)].Delete(req)
+}
+
+// BulkGet is not supported, so that the keys are got one by one from their state stores.
+func (r *router) BulkGet(req []state.GetRequest) (bool, []state.BulkGetResponse, error) {
+	return false, nil, nil
+}
+
+func (r *router) BulkSet(req []state.SetRequest) error {
+	groups := map[string][]state.SetRequest{}
+	order := r.groupKeys(len(req), func(i int) string { return req[i].Key }, func(store string, i int) {
+		groups[store] = append(groups[store], req[i])
+	})
+	return r.forEachStore(order, func(store string) error {
+		return r.stores[store].BulkSet(groups[store])
+	})
+}
+
+func (r *router) BulkDelete(req []state.DeleteRequest) error {
+	groups := map[string][]state.DeleteRequest{}
+	order := r.groupKeys(len(req), func(i int) string { return req[i].Key }, func(store string, i int) {
+		groups[store] = append(groups[store], req[i])
+	})
+	return r.forEachStore(order, func(store string) error {
+		return r.stores[store].BulkDelete(groups[store])
+	})
+}
+
+// Multi executes the operations of each state store in a transaction of the state store.
+// All the state stores must be transactional.
+func (r *router) Multi(request *state.TransactionalStateRequest) error {
+	groups := map[string][]state.TransactionalStateOperation{}
+	var keyErr error
+	order := r.groupKeys(len(request.Operations), func(i int) string {
+		k, ok := request.Operations[i].Request.(interface{ GetKey() string })
+		if !ok {
+			keyErr = errors.Errorf("invalid state router transaction: operation %s without key", request.Operations[i].Operation)
+			return ""
+		}
+		return k.GetKey()
+	}, func(store string, i int) {
+		groups[store] = append(groups[store], request.Operations[i])
+	})
+	if keyErr != nil {
+		return keyErr
+	}
+
+	transactional := make(map[string]state.TransactionalStore, len(order))
+	for _, store := range order {
+		t, ok := r.stores[store].(state.TransactionalStore)
+		if !ok || !state.FeatureTransactional.IsPresent(r.stores[store].Features()) {
+			return errors.Errorf("state store %s doesn't support transactions", store)
+		}
+		transactional[store] = t
+	}
+
+	return r.forEachStore(order, func(store string) error {
+		return transactional[store].Multi(&state.TransactionalStateRequest{
+			Operations: groups[store],
+			Metadata:   request.Metadata,
+		})
+	})
+}
+
+// groupKeys adds the n requests to the group of the state store of their key,
+// and returns the state stores in the order of their first request.
+func (r *router) groupKeys(n int, key func(i int) string, add func(store string, i int)) []string {
+	var order []string
+	seen := map[string]struct{}{}
+	for i := 0; i < n; i++ {
+		store := r.config.storeOf(key(i))
+		if _, ok := seen[store]; !ok {
+			seen[store] = struct{}{}
+			order = append(order, store)
+		}
+		add(store, i)
+	}
+	return order
+}
+
+// forEachStore calls fn for the state stores in order, and stops at the first error.
+func (r *router) forEachStore(order []string, fn func(store string) error) error {
+	for i, store := range order {
+		if err := fn(store); err != nil {
+			if i == 0 {
+				return err
+			}
+			return &PartialFailureError{Committed: order[:i], Failed: store, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	contribMetadata "github.com/dapr/components-contrib/metadata"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+// memoryStore is a store recording the keys of its requests.
+type memoryStore struct {
+	state.DefaultBulkStore
+	features []state.Feature
+	fail     bool
+	keys     []string
+	multis   int
+}
+
+func newMemoryStore(features ...state.Feature) *memoryStore {
+	s := &memoryStore{features: features}
+	s.DefaultBulkStore = state.NewDefaultBulkStore(s)
+	return s
+}
+
+func (s *memoryStore) Init(state.Metadata) error { return nil }
+
+func (s *memoryStore) Features() []state.Feature { return s.features }
+
+func (s *memoryStore) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	s.keys = append(s.keys, req.Key)
+	return &state.GetResponse{Data: []byte(req.Key)}, nil
+}
+
+func (s *memoryStore) Set(req *state.SetRequest) error {
+	if s.fail {
+		return errors.New("set failed")
+	}
+	s.keys = append(s.keys, req.Key)
+	return nil
+}
+
+func (s *memoryStore) Delete(req *state.DeleteRequest) error {
+	s.keys = append(s.keys, req.Key)
+	return nil
+}
+
+func (s *memoryStore) Multi(req *state.TransactionalStateRequest) error {
+	if s.fail {
+		return errors.New("transaction failed")
+	}
+	s.multis++
+	for _, o := range req.Operations {
+		s.keys = append(s.keys, o.Request.(interface{ GetKey() string }).GetKey())
+	}
+	return nil
+}
+
+func newTestRouter(t *testing.T, stores map[string]*memoryStore, metadata map[string]string) *router {
+	r := NewRouter(logger.NewLogger("test"), func(name string) (state.Store, bool) {
+		s, ok := stores[name]
+		return s, ok
+	}).(*router)
+	require.NoError(t, r.Init(state.Metadata{Base: contribMetadata.Base{Properties: metadata}}))
+	return r
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Run("routes from the longest prefix", func(t *testing.T) {
+		config, err := ParseConfig(map[string]string{
+			routesKey:       "hot-=redis, hot-orders-=redis2,session-=redis",
+			defaultStoreKey: "postgres",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []Route{{"hot-orders-", "redis2"}, {"session-", "redis"}, {"hot-", "redis"}}, config.Routes)
+		assert.Equal(t, []string{"postgres", "redis2", "redis"}, config.Stores())
+		assert.Equal(t, "redis2", config.storeOf("app1||hot-orders-1"))
+		assert.Equal(t, "redis", config.storeOf("hot-1"))
+		assert.Equal(t, "postgres", config.storeOf("app1||cold-1"))
+	})
+
+	t.Run("missing default store", func(t *testing.T) {
+		_, err := ParseConfig(map[string]string{routesKey: "hot-=redis"})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid route", func(t *testing.T) {
+		_, err := ParseConfig(map[string]string{routesKey: "hot-", defaultStoreKey: "postgres"})
+		assert.Error(t, err)
+	})
+}
+
+func TestInit(t *testing.T) {
+	stores := map[string]*memoryStore{
+		"redis":    newMemoryStore(state.FeatureETag, state.FeatureTransactional),
+		"postgres": newMemoryStore(state.FeatureTransactional, state.FeatureQueryAPI),
+	}
+
+	t.Run("features of all the stores", func(t *testing.T) {
+		r := newTestRouter(t, stores, map[string]string{routesKey: "hot-=redis", defaultStoreKey: "postgres"})
+		assert.Equal(t, []state.Feature{state.FeatureTransactional}, r.Features())
+	})
+
+	t.Run("missing store", func(t *testing.T) {
+		r := NewRouter(logger.NewLogger("test"), func(name string) (state.Store, bool) {
+			return nil, false
+		})
+		err := r.Init(state.Metadata{Base: contribMetadata.Base{Properties: map[string]string{defaultStoreKey: "postgres"}}})
+		assert.Error(t, err)
+	})
+}
+
+func TestRouting(t *testing.T) {
+	redis := newMemoryStore(state.FeatureTransactional)
+	postgres := newMemoryStore(state.FeatureTransactional)
+	r := newTestRouter(t, map[string]*memoryStore{"redis": redis, "postgres": postgres}, map[string]string{
+		routesKey:       "hot-=redis",
+		defaultStoreKey: "postgres",
+	})
+
+	resp, err := r.Get(&state.GetRequest{Key: "app1||hot-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "app1||hot-1", string(resp.Data))
+	require.NoError(t, r.Set(&state.SetRequest{Key: "app1||cold-1"}))
+	require.NoError(t, r.Delete(&state.DeleteRequest{Key: "app1||hot-2"}))
+	require.NoError(t, r.BulkSet([]state.SetRequest{{Key: "app1||hot-3"}, {Key: "app1||cold-2"}, {Key: "app1||hot-4"}}))
+	require.NoError(t, r.BulkDelete([]state.DeleteRequest{{Key: "app1||cold-3"}}))
+
+	assert.Equal(t, []string{"app1||hot-1", "app1||hot-2", "app1||hot-3", "app1||hot-4"}, redis.keys)
+	assert.Equal(t, []string{"app1||cold-1", "app1||cold-2", "app1||cold-3"}, postgres.keys)
+}
+
+func TestMulti(t *testing.T) {
+	request := &state.TransactionalStateRequest{
+		Operations: []state.TransactionalStateOperation{
+			{Operation: state.Upsert, Request: state.SetRequest{Key: "app1||cold-1"}},
+			{Operation: state.Upsert, Request: state.SetRequest{Key: "app1||hot-1"}},
+			{Operation: state.Delete, Request: state.DeleteRequest{Key: "app1||cold-2"}},
+		},
+	}
+	metadata := map[string]string{routesKey: "hot-=redis", defaultStoreKey: "postgres"}
+
+	t.Run("a transaction in each store", func(t *testing.T) {
+		redis := newMemoryStore(state.FeatureTransactional)
+		postgres := newMemoryStore(state.FeatureTransactional)
+		r := newTestRouter(t, map[string]*memoryStore{"redis": redis, "postgres": postgres}, metadata)
+
+		require.NoError(t, r.Multi(request))
+		assert.Equal(t, 1, postgres.multis)
+		assert.Equal(t, []string{"app1||cold-1", "app1||cold-2"}, postgres.keys)
+		assert.Equal(t, 1, redis.multis)
+		assert.Equal(t, []string{"app1||hot-1"}, redis.keys)
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		redis := newMemoryStore(state.FeatureTransactional)
+		redis.fail = true
+		postgres := newMemoryStore(state.FeatureTransactional)
+		r := newTestRouter(t, map[string]*memoryStore{"redis": redis, "postgres": postgres}, metadata)
+
+		err := r.Multi(request)
+		var partialErr *PartialFailureError
+		require.ErrorAs(t, err, &partialErr)
+		assert.Equal(t, []string{"postgres"}, partialErr.Committed)
+		assert.Equal(t, "redis", partialErr.Failed)
+		assert.Equal(t, 1, postgres.multis)
+	})
+
+	t.Run("failure of the first store", func(t *testing.T) {
+		redis := newMemoryStore(state.FeatureTransactional)
+		postgres := newMemoryStore(state.FeatureTransactional)
+		postgres.fail = true
+		r := newTestRouter(t, map[string]*memoryStore{"redis": redis, "postgres": postgres}, metadata)
+
+		err := r.Multi(request)
+		assert.EqualError(t, err, "transaction failed")
+		assert.Equal(t, 0, redis.multis)
+	})
+
+	t.Run("non transactional store", func(t *testing.T) {
+		redis := newMemoryStore()
+		postgres := newMemoryStore(state.FeatureTransactional)
+		r := newTestRouter(t, map[string]*memoryStore{"redis": redis, "postgres": postgres}, metadata)
+
+		assert.Error(t, r.Multi(request))
+		assert.Equal(t, 0, postgres.multis)
+	})
+}
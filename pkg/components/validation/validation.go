@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation validates the credentials of the components when they are initialized,
+// and classifies the initialization errors by their cause.
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dapr/components-contrib/health"
+)
+
+// Timeout is the maximum duration of the credentials validation of a component.
+const Timeout = 10 * time.Second
+
+// ErrorClass is the cause of a failed component initialization.
+type ErrorClass string
+
+const (
+	// ClassCredentials is used when the provider rejected the credentials.
+	ClassCredentials ErrorClass = "CREDENTIALS"
+	// ClassPermissions is used when the credentials are valid but aren't allowed to perform the operation.
+	ClassPermissions ErrorClass = "PERMISSIONS"
+	// ClassNetwork is used when the provider could not be reached.
+	ClassNetwork ErrorClass = "NETWORK"
+	// ClassUnknown is used when the cause of the error could not be determined.
+	ClassUnknown ErrorClass = "UNKNOWN"
+)
+
+// hint returns the action suggested to fix an error of the class.
+func (c ErrorClass) hint() string {
+	switch c {
+	case ClassCredentials:
+		return "the provider rejected the credentials: check the authentication metadata of the component and the secrets it references"
+	case ClassPermissions:
+		return "the credentials are not allowed to access the resource: check the roles or policies granted to them"
+	case ClassNetwork:
+		return "the provider could not be reached: check the host and port, the DNS resolution and the network policies"
+	default:
+		return "initialization failed"
+	}
+}
+
+// CredentialsValidator is implemented by the components that can check their credentials without performing any other operation.
+// ValidateCredentials is called after the component is initialized.
+type CredentialsValidator interface {
+	ValidateCredentials(ctx context.Context) error
+}
+
+// Error is an initialization error of a component, classified by its cause.
+type Error struct {
+	Component string
+	Class     ErrorClass
+	Err       error
+}
+
+func (e *Error) Error() string {
+	// There is nothing to suggest when the cause is unknown, so the error of the component is returned as is
+	if e.Class == ClassUnknown {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("component %s: %s: %v", e.Component, e.Class.hint(), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap classifies the error returned by the component in an Error.
+func Wrap(component string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var validationErr *Error
+	if errors.As(err, &validationErr) {
+		return err
+	}
+	return &Error{
+		Component: component,
+		Class:     Classify(err),
+		Err:       err,
+	}
+}
+
+// Validate validates the credentials of the component if it implements CredentialsValidator.
+// Otherwise the components implementing health.Pinger, such as the Redis, MongoDB and Azure Cosmos DB components, are
+// pinged, since their pings are authenticated with the credentials of the component.
+func Validate(ctx context.Context, name string, component any) error {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	switch c := component.(type) {
+	case CredentialsValidator:
+		return Wrap(name, c.ValidateCredentials(ctx))
+	case health.Pinger:
+		return Wrap(name, ping(ctx, c))
+	default:
+		return nil
+	}
+}
+
+// ping pings the component until the context is done, as the pings are not canceled by a context.
+func ping(ctx context.Context, pinger health.Pinger) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- pinger.Ping()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var (
+	permissionsPattern = regexp.MustCompile(`\b403\b|forbidden|permission denied|access denied|accessdenied|not authorized to perform|insufficient permission|authorizationfailed`)
+	credentialsPattern = regexp.MustCompile(`\b401\b|unauthorized|unauthenticated|authentication failed|auth failed|invalid credentials|invalid password|invalid token|token (has )?expired|invalid api key|invalid access key|signaturedoesnotmatch|authentication required|noauth|wrongpass|invalid username-password`)
+	networkPattern     = regexp.MustCompile(`connection refused|no such host|i/o timeout|connection reset|network is unreachable|no route to host|deadline exceeded|tls handshake timeout`)
+)
+
+// Classify returns the cause of the error returned by a component.
+// The well-known error types are used first, then the messages of the providers are matched against common patterns.
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	var validationErr *Error
+	if errors.As(err, &validationErr) {
+		return validationErr.Class
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unauthenticated:
+			return ClassCredentials
+		case codes.PermissionDenied:
+			return ClassPermissions
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return ClassNetwork
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case permissionsPattern.MatchString(msg):
+		return ClassPermissions
+	case credentialsPattern.MatchString(msg):
+		return ClassCredentials
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) || networkPattern.MatchString(msg) {
+		return ClassNetwork
+	}
+
+	return ClassUnknown
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type validatingComponent struct {
+	err error
+}
+
+func (c *validatingComponent) ValidateCredentials(ctx context.Context) error {
+	return c.err
+}
+
+type pingingComponent struct {
+	err     error
+	blockCh chan struct{}
+}
+
+func (c *pingingComponent) Ping() error {
+	if c.blockCh != nil {
+		<-c.blockCh
+	}
+	return c.err
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected ErrorClass
+	}{
+		{errors.New("NOAUTH Authentication required"), ClassCredentials},
+		{errors.New("WRONGPASS invalid username-password pair or user is disabled"), ClassCredentials},
+		{errors.New("kafka: client has run out of available brokers to talk to: SASL authentication failed"), ClassCredentials},
+		{errors.New("unexpected status code 401"), ClassCredentials},
+		{errors.New("amqp: Unauthorized access"), ClassCredentials},
+		{errors.New("AccessDenied: User is not authorized to perform: sqs:GetQueueUrl"), ClassPermissions},
+		{errors.New("status code 403: Forbidden"), ClassPermissions},
+		{status.Error(codes.Unauthenticated, "bad token"), ClassCredentials},
+		{status.Error(codes.PermissionDenied, "denied"), ClassPermissions},
+		{status.Error(codes.Unavailable, "down"), ClassNetwork},
+		{fmt.Errorf("connect: %w", &net.DNSError{Err: "no such host", Name: "broker"}), ClassNetwork},
+		{errors.New("dial tcp 10.0.0.1:5672: connect: connection refused"), ClassNetwork},
+		{context.DeadlineExceeded, ClassNetwork},
+		{errors.New("missing required metadata: host"), ClassUnknown},
+		{&Error{Component: "c", Class: ClassPermissions, Err: errors.New("x")}, ClassPermissions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, Classify(tt.err))
+		})
+	}
+	assert.Equal(t, ErrorClass(""), Classify(nil))
+}
+
+func TestWrap(t *testing.T) {
+	assert.NoError(t, Wrap("pubsub", nil))
+
+	inner := errors.New("status code 401")
+	err := Wrap("pubsub", inner)
+	var validationErr *Error
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "pubsub", validationErr.Component)
+	assert.Equal(t, ClassCredentials, validationErr.Class)
+	assert.ErrorIs(t, err, inner)
+	assert.Contains(t, err.Error(), "check the authentication metadata")
+
+	// Errors are not wrapped twice
+	assert.Equal(t, err, Wrap("pubsub", err))
+
+	// The message of the unclassified errors is unchanged
+	assert.EqualError(t, Wrap("pubsub", errors.New("missing required metadata: host")), "missing required metadata: host")
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(context.Background(), "binding", struct{}{}))
+	assert.NoError(t, Validate(context.Background(), "binding", &validatingComponent{}))
+
+	err := Validate(context.Background(), "binding", &validatingComponent{err: errors.New("permission denied")})
+	assert.Equal(t, ClassPermissions, Classify(err))
+	assert.Contains(t, err.Error(), "component binding")
+
+	t.Run("pinged component", func(t *testing.T) {
+		assert.NoError(t, Validate(context.Background(), "statestore", &pingingComponent{}))
+
+		err := Validate(context.Background(), "statestore", &pingingComponent{err: errors.New("NOAUTH Authentication required")})
+		assert.Equal(t, ClassCredentials, Classify(err))
+		assert.Contains(t, err.Error(), "component statestore")
+	})
+
+	t.Run("ping not returning", func(t *testing.T) {
+		blockCh := make(chan struct{})
+		defer close(blockCh)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := Validate(ctx, "statestore", &pingingComponent{blockCh: blockCh})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package compression contains the content encodings of the HTTP bodies compressed by the runtime.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const (
+	// Zstd is the Zstandard content encoding.
+	Zstd = "zstd"
+	// Brotli is the Brotli content encoding.
+	Brotli = "br"
+	// Gzip is the gzip content encoding.
+	Gzip = "gzip"
+
+	// DefaultMinSize is the default minimum size in bytes of the compressed bodies.
+	// The smaller bodies are sent as they are, since their compression saves little.
+	DefaultMinSize = 1024
+)
+
+// DefaultEncodings are the supported encodings in the default order of preference.
+var DefaultEncodings = []string{Zstd, Brotli, Gzip}
+
+// ErrTooLarge is returned when a decompressed body exceeds the maximum size.
+var ErrTooLarge = errors.New("decompressed body exceeds the maximum size")
+
+// zstdEncoder is safe for concurrent use with EncodeAll.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// Policy is a compression policy of the configuration whose encodings were validated.
+type Policy struct {
+	Encodings []string
+	MinSize   int
+}
+
+// NewPolicy returns the policy of the configuration, nil if the compression is disabled.
+func NewPolicy(spec config.CompressionPolicy) (*Policy, error) {
+	if !spec.Enabled {
+		return nil, nil
+	}
+
+	p := &Policy{Encodings: DefaultEncodings, MinSize: spec.MinSize}
+	if len(spec.Encodings) > 0 {
+		p.Encodings = make([]string, len(spec.Encodings))
+		for i, e := range spec.Encodings {
+			if !IsSupported(e) {
+				return nil, errors.Errorf("unsupported compression encoding %q", e)
+			}
+			p.Encodings[i] = strings.ToLower(e)
+		}
+	}
+	if p.MinSize <= 0 {
+		p.MinSize = DefaultMinSize
+	}
+	return p, nil
+}
+
+// AcceptEncoding returns the value of the Accept-Encoding header of the requests with the encodings of the policy.
+func (p *Policy) AcceptEncoding() string {
+	return strings.Join(p.Encodings, ", ")
+}
+
+// Negotiate returns the preferred encoding of the policy accepted by the Accept-Encoding header, or an empty string.
+// The encodings with the highest quality value are preferred, then the first encodings of the policy.
+func (p *Policy) Negotiate(acceptEncoding string) string {
+	var (
+		best    string
+		bestQ   float64
+		wildQ   = -1.0
+		qValues = map[string]float64{}
+	)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, q := parseQValue(part)
+		if name == "*" {
+			wildQ = q
+		} else if name != "" {
+			qValues[name] = q
+		}
+	}
+
+	for _, e := range p.Encodings {
+		q, ok := qValues[e]
+		if !ok {
+			if wildQ < 0 {
+				continue
+			}
+			q = wildQ
+		}
+		if q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best
+}
+
+func parseQValue(part string) (string, float64) {
+	name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+	name = strings.ToLower(strings.TrimSpace(name))
+	q := 1.0
+	for _, param := range strings.Split(params, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok && strings.TrimSpace(k) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+	return name, q
+}
+
+// IsSupported returns true if the content encoding is supported.
+func IsSupported(encoding string) bool {
+	switch strings.ToLower(encoding) {
+	case Zstd, Brotli, Gzip:
+		return true
+	default:
+		return false
+	}
+}
+
+// Compress returns the body compressed with the encoding.
+func Compress(encoding string, body []byte) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case Zstd:
+		return zstdEncoder.EncodeAll(body, make([]byte, 0, len(body)/2)), nil
+	case Brotli:
+		var buf bytes.Buffer
+		w := brotli.NewWriterLevel(&buf, brotli.DefaultCompression)
+		if _, err := w.Write(body); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Gzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(body); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.Errorf("unsupported compression encoding %q", encoding)
+	}
+}
+
+// Decompress returns the body decompressed with the encoding.
+// It returns ErrTooLarge without reading the whole body if the decompressed body exceeds maxSize bytes.
+func Decompress(encoding string, body []byte, maxSize int) ([]byte, error) {
+	var r io.Reader
+	switch strings.ToLower(encoding) {
+	case Zstd:
+		zr, err := zstd.NewReader(bytes.NewReader(body), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	case Brotli:
+		r = brotli.NewReader(bytes.NewReader(body))
+	case Gzip:
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	default:
+		return nil, errors.Errorf("unsupported compression encoding %q", encoding)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestNewPolicy(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		p, err := NewPolicy(config.CompressionPolicy{Encodings: []string{Gzip}})
+		require.NoError(t, err)
+		assert.Nil(t, p)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		p, err := NewPolicy(config.CompressionPolicy{Enabled: true})
+		require.NoError(t, err)
+		assert.Equal(t, DefaultEncodings, p.Encodings)
+		assert.Equal(t, DefaultMinSize, p.MinSize)
+		assert.Equal(t, "zstd, br, gzip", p.AcceptEncoding())
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		_, err := NewPolicy(config.CompressionPolicy{Enabled: true, Encodings: []string{"deflate"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestNegotiate(t *testing.T) {
+	p := &Policy{Encodings: []string{Zstd, Brotli, Gzip}}
+	tests := map[string]string{
+		"":                      "",
+		"identity":              "",
+		"gzip, deflate, br":     Brotli,
+		"GZIP":                  Gzip,
+		"gzip;q=1.0, br;q=0.5":  Gzip,
+		"zstd;q=0, gzip;q=0.1":  Gzip,
+		"*":                     Zstd,
+		"*;q=0.5, zstd;q=0":     Brotli,
+		"deflate, compress;q=1": "",
+	}
+	for accept, expected := range tests {
+		assert.Equal(t, expected, p.Negotiate(accept), accept)
+	}
+}
+
+func TestCompressDecompress(t *testing.T) {
+	body := []byte(strings.Repeat("dapr ", 1000))
+	for _, encoding := range DefaultEncodings {
+		t.Run(encoding, func(t *testing.T) {
+			compressed, err := Compress(encoding, body)
+			require.NoError(t, err)
+			assert.Less(t, len(compressed), len(body))
+
+			decompressed, err := Decompress(encoding, compressed, len(body))
+			require.NoError(t, err)
+			assert.Equal(t, body, decompressed)
+
+			_, err = Decompress(encoding, compressed, len(body)-1)
+			assert.ErrorIs(t, err, ErrTooLarge)
+		})
+	}
+
+	t.Run("invalid body", func(t *testing.T) {
+		_, err := Decompress(Gzip, body, len(body))
+		assert.Error(t, err)
+	})
+}
@@ -15,7 +15,11 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"io"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
@@ -27,12 +31,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	env "github.com/dapr/dapr/pkg/config/env"
 	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
 )
 
 const (
 	operatorCallTimeout          = time.Second * 5
 	operatorMaxRetries           = 100
+	configURLTimeout             = time.Second * 10
+	maxConfigURLSize             = 4 << 20
 	AllowAccess                  = "allow"
 	DenyAccess                   = "deny"
 	DefaultTrustDomain           = "public"
@@ -84,20 +91,179 @@ type AccessControlListOperationAction struct {
 }
 
 type ConfigurationSpec struct {
-	HTTPPipelineSpec   PipelineSpec       `json:"httpPipeline,omitempty" yaml:"httpPipeline,omitempty"`
-	TracingSpec        TracingSpec        `json:"tracing,omitempty" yaml:"tracing,omitempty"`
-	MTLSSpec           MTLSSpec           `json:"mtls,omitempty" yaml:"mtls,omitempty"`
-	MetricSpec         MetricSpec         `json:"metric,omitempty" yaml:"metric,omitempty"`
-	Secrets            SecretsSpec        `json:"secrets,omitempty" yaml:"secrets,omitempty"`
-	AccessControlSpec  AccessControlSpec  `json:"accessControl,omitempty" yaml:"accessControl,omitempty"`
-	NameResolutionSpec NameResolutionSpec `json:"nameResolution,omitempty" yaml:"nameResolution,omitempty"`
-	Features           []FeatureSpec      `json:"features,omitempty" yaml:"features,omitempty"`
-	APISpec            APISpec            `json:"api,omitempty" yaml:"api,omitempty"`
-	ComponentsSpec     ComponentsSpec     `json:"components,omitempty" yaml:"components,omitempty"`
+	HTTPPipelineSpec    PipelineSpec       `json:"httpPipeline,omitempty" yaml:"httpPipeline,omitempty"`
+	AppHTTPPipelineSpec PipelineSpec       `json:"appHttpPipeline,omitempty" yaml:"appHttpPipeline,omitempty"`
+	TracingSpec         TracingSpec        `json:"tracing,omitempty" yaml:"tracing,omitempty"`
+	MTLSSpec            MTLSSpec           `json:"mtls,omitempty" yaml:"mtls,omitempty"`
+	MetricSpec          MetricSpec         `json:"metric,omitempty" yaml:"metric,omitempty"`
+	Secrets             SecretsSpec        `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	AccessControlSpec   AccessControlSpec  `json:"accessControl,omitempty" yaml:"accessControl,omitempty"`
+	NameResolutionSpec  NameResolutionSpec `json:"nameResolution,omitempty" yaml:"nameResolution,omitempty"`
+	Features            []FeatureSpec      `json:"features,omitempty" yaml:"features,omitempty"`
+	APISpec             APISpec            `json:"api,omitempty" yaml:"api,omitempty"`
+	ComponentsSpec      ComponentsSpec     `json:"components,omitempty" yaml:"components,omitempty"`
+	GRPCProxySpec       GRPCProxySpec      `json:"grpcProxy,omitempty" yaml:"grpcProxy,omitempty"`
+	ActorsSpec          ActorsSpec         `json:"actors,omitempty" yaml:"actors,omitempty"`
+	CORSSpec            CORSSpec           `json:"cors,omitempty" yaml:"cors,omitempty"`
+	AuditSpec           AuditSpec          `json:"audit,omitempty" yaml:"audit,omitempty"`
+	ProxySpec           ProxySpec          `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	CompressionSpec     CompressionSpec    `json:"compression,omitempty" yaml:"compression,omitempty"`
+	ProfilingSpec       ProfilingSpec      `json:"profiling,omitempty" yaml:"profiling,omitempty"`
+	LoggingSpec         LoggingSpec        `json:"logging,omitempty" yaml:"logging,omitempty"`
+}
+
+// LoggingSpec configures the loggers of the sidecar.
+type LoggingSpec struct {
+	// Levels overrides the log level of the loggers by scope, such as dapr.runtime.actor or dapr.contrib.pubsub.kafka,
+	// which keep it when the log level of the sidecar changes
+	Levels map[string]string `json:"levels,omitempty" yaml:"levels,omitempty"`
+}
+
+// ProfilingSpec exposes the pprof endpoints, including the capture of execution traces, on the public port.
+type ProfilingSpec struct {
+	// Enabled serves /debug/pprof on the public port to the callers with the Dapr API token
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Maximum duration of the CPU profiles and of the execution traces, 30s by default
+	MaxDuration string `json:"maxDuration,omitempty" yaml:"maxDuration,omitempty"`
+}
+
+// CompressionSpec configures the compression of the HTTP bodies exchanged with the callers of the Dapr HTTP API and with the app.
+type CompressionSpec struct {
+	// API compresses the responses of the Dapr HTTP API with the encoding negotiated with the Accept-Encoding header
+	// of the requests, and decompresses the requests with a Content-Encoding header.
+	API CompressionPolicy `json:"api,omitempty" yaml:"api,omitempty"`
+	// AppChannel asks the app for compressed responses with an Accept-Encoding header, and decompresses them.
+	AppChannel CompressionPolicy `json:"appChannel,omitempty" yaml:"appChannel,omitempty"`
+}
+
+// CompressionPolicy enables the compression with a list of encodings.
+type CompressionPolicy struct {
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Encodings are the supported encodings in the order of preference, among "zstd", "br" and "gzip". All of them if empty.
+	Encodings []string `json:"encodings,omitempty" yaml:"encodings,omitempty"`
+	// MinSize is the minimum size in bytes of the compressed responses, 1024 if 0.
+	MinSize int `json:"minSize,omitempty" yaml:"minSize,omitempty"`
+}
+
+// ProxySpec configures the proxies the outbound HTTP(S) requests of the runtime and of the components go through,
+// for the environments where the egress traffic is only allowed via a proxy.
+type ProxySpec struct {
+	// HTTPProxy is the URL of the proxy of the plain HTTP requests. The http, https and socks5 schemes are supported.
+	HTTPProxy string `json:"httpProxy,omitempty" yaml:"httpProxy,omitempty"`
+	// HTTPSProxy is the URL of the proxy of the HTTPS requests. The http, https and socks5 schemes are supported.
+	HTTPSProxy string `json:"httpsProxy,omitempty" yaml:"httpsProxy,omitempty"`
+	// NoProxy are the hosts reached without a proxy: host names, domains starting with a dot, IP addresses or CIDRs,
+	// optionally with a port. The loopback addresses are never proxied.
+	NoProxy []string `json:"noProxy,omitempty" yaml:"noProxy,omitempty"`
+	// Auth are the credentials sent to the proxies whose URL has none.
+	Auth ProxyAuthSpec `json:"auth,omitempty" yaml:"auth,omitempty"`
+	// Components override the proxies of the requests of components to their hosts.
+	Components []ComponentProxySpec `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// ComponentProxySpec overrides the proxies of the requests a component sends to its hosts.
+// The requests are matched by host, as the clients of the components share the default HTTP transport.
+type ComponentProxySpec struct {
+	// Name is the name of the component.
+	Name string `json:"name" yaml:"name"`
+	// Hosts are the hosts the component sends requests to, with the syntax of NoProxy.
+	Hosts []string `json:"hosts" yaml:"hosts"`
+	// HTTPProxy is the URL of the proxy of the plain HTTP requests to the hosts.
+	HTTPProxy string `json:"httpProxy,omitempty" yaml:"httpProxy,omitempty"`
+	// HTTPSProxy is the URL of the proxy of the HTTPS requests to the hosts.
+	// The requests to the hosts are sent without a proxy when both proxies are empty.
+	HTTPSProxy string `json:"httpsProxy,omitempty" yaml:"httpsProxy,omitempty"`
+}
+
+// ProxyAuthSpec references the secret holding the credentials of the proxies.
+type ProxyAuthSpec struct {
+	// SecretStore is the name of the secret store, the built-in Kubernetes secret store when empty.
+	SecretStore string `json:"secretStore,omitempty" yaml:"secretStore,omitempty"`
+	// SecretName is the name of the secret. The proxies get no credentials when empty.
+	SecretName string `json:"secretName,omitempty" yaml:"secretName,omitempty"`
+	// UsernameKey is the key of the username in the secret, "username" when empty.
+	UsernameKey string `json:"usernameKey,omitempty" yaml:"usernameKey,omitempty"`
+	// PasswordKey is the key of the password in the secret, "password" when empty.
+	PasswordKey string `json:"passwordKey,omitempty" yaml:"passwordKey,omitempty"`
+}
+
+// AuditSpec configures the audit log, which records every call to the Dapr APIs for compliance purposes.
+// It is separate from the API logging enabled with the enable-api-logging flag.
+type AuditSpec struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Output is the path of the file the audit records are appended to. They are written to stdout when empty.
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+	// Headers are the request headers, or gRPC metadata keys, recorded with the calls.
+	Headers []string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// RedactedHeaders are the recorded headers whose value is replaced. The headers carrying credentials are always redacted.
+	RedactedHeaders []string `json:"redactedHeaders,omitempty" yaml:"redactedHeaders,omitempty"`
+	// PayloadHash records the SHA-256 hash of the request payloads. The payloads themselves are never recorded.
+	PayloadHash bool `json:"payloadHash,omitempty" yaml:"payloadHash,omitempty"`
+}
+
+// CORSSpec is the CORS policy of the Dapr HTTP API server. It replaces the allowed origins set with the
+// allowed-origins flag when configured.
+type CORSSpec struct {
+	CORSPolicy `json:",inline" yaml:",inline"`
+	// Routes override the policy for the requests whose path starts with the route path.
+	// The route with the longest matching path applies.
+	Routes []CORSRoutePolicy `json:"routes,omitempty" yaml:"routes,omitempty"`
+}
+
+// CORSPolicy defines the cross-origin requests allowed. All the origins are allowed when AllowedOrigins is empty.
+type CORSPolicy struct {
+	AllowedOrigins []string `json:"allowedOrigins,omitempty" yaml:"allowedOrigins,omitempty"`
+	AllowedMethods []string `json:"allowedMethods,omitempty" yaml:"allowedMethods,omitempty"`
+	AllowedHeaders []string `json:"allowedHeaders,omitempty" yaml:"allowedHeaders,omitempty"`
+	ExposedHeaders []string `json:"exposedHeaders,omitempty" yaml:"exposedHeaders,omitempty"`
+	// AllowCredentials requires an explicit list of AllowedOrigins, without the wildcard origin.
+	AllowCredentials bool `json:"allowCredentials,omitempty" yaml:"allowCredentials,omitempty"`
+	// MaxAge is the number of seconds the result of a preflight request can be cached.
+	MaxAge int `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+}
+
+// CORSRoutePolicy is the CORS policy of the requests whose path starts with Path.
+type CORSRoutePolicy struct {
+	Path       string `json:"path" yaml:"path"`
+	CORSPolicy `json:",inline" yaml:",inline"`
+}
+
+// IsEmpty returns true if none of the policy fields is set.
+func (p CORSPolicy) IsEmpty() bool {
+	return len(p.AllowedOrigins) == 0 && len(p.AllowedMethods) == 0 && len(p.AllowedHeaders) == 0 &&
+		len(p.ExposedHeaders) == 0 && !p.AllowCredentials && p.MaxAge == 0
+}
+
+// Validate returns an error if the credentials are allowed without an explicit list of origins, which would let any
+// website make credentialed requests to the Dapr API.
+func (p CORSPolicy) Validate() error {
+	if !p.AllowCredentials {
+		return nil
+	}
+	if len(p.AllowedOrigins) == 0 {
+		return errors.New("allowCredentials requires an explicit list of allowedOrigins")
+	}
+	for _, origin := range p.AllowedOrigins {
+		if origin == "*" {
+			return errors.New("allowCredentials can't be used with the wildcard origin")
+		}
+	}
+	return nil
+}
+
+// IsEnabled returns true if a CORS policy is configured.
+func (c CORSSpec) IsEnabled() bool {
+	return !c.CORSPolicy.IsEmpty() || len(c.Routes) > 0
 }
 
 type SecretsSpec struct {
 	Scopes []SecretsScope `json:"scopes"`
+	// DisableBuiltinK8sSecretStore disables the built-in Kubernetes secret store for all the apps using the configuration,
+	// regardless of their annotations.
+	DisableBuiltinK8sSecretStore bool `json:"disableBuiltinK8sSecretStore,omitempty" yaml:"disableBuiltinK8sSecretStore,omitempty"`
+	// ResolutionChain are the secret stores resolving the secret references of the components naming no secret store,
+	// in order: a secret is got from the first store having it.
+	ResolutionChain []string `json:"resolutionChain,omitempty" yaml:"resolutionChain,omitempty"`
 }
 
 // SecretsScope defines the scope for secrets.
@@ -115,6 +281,24 @@ type PipelineSpec struct {
 // APISpec describes the configuration for Dapr APIs.
 type APISpec struct {
 	Allowed []APIAccessRule `json:"allowed,omitempty"`
+	// Denied APIs are disabled even when they are allowed.
+	Denied []APIAccessRule `json:"denied,omitempty"`
+	// EnableAlpha enables the alpha APIs. Without it, only the alpha APIs in the allow list are enabled.
+	EnableAlpha bool `json:"enableAlpha,omitempty" yaml:"enableAlpha,omitempty"`
+	// HTTPRouteLimits override the maximum request body size and the timeout of groups of HTTP APIs.
+	HTTPRouteLimits []HTTPRouteLimit `json:"httpRouteLimits,omitempty" yaml:"httpRouteLimits,omitempty"`
+}
+
+// HTTPRouteLimit describes the limits of the HTTP APIs whose route starts with the name, e.g. "state", "invoke" or "bindings".
+// When several limits match an API, the one with the longest name applies.
+type HTTPRouteLimit struct {
+	Name string `json:"name" yaml:"name"`
+	// Version of the APIs, all the versions if empty.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// MaxRequestBodySize is the maximum size of the request body in MB, the size of --dapr-http-max-request-size if 0.
+	MaxRequestBodySize int `json:"maxRequestBodySize,omitempty" yaml:"maxRequestBodySize,omitempty"`
+	// Timeout is the maximum duration of the handling of the requests, e.g. "30s". Unlimited if empty.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
 // APIAccessRule describes an access rule for allowing a Dapr API to be enabled and accessible by an app.
@@ -145,6 +329,11 @@ type TracingSpec struct {
 	Stdout       bool       `json:"stdout" yaml:"stdout"`
 	Zipkin       ZipkinSpec `json:"zipkin" yaml:"zipkin"`
 	Otel         OtelSpec   `json:"otel" yaml:"otel"`
+	// Attributes are static attributes, such as the team or the environment, added to all the spans emitted by Dapr.
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+	// Propagators are the trace context propagation formats accepted and emitted by Dapr: w3c, b3, b3multi and jaeger.
+	// W3C trace context is always used in addition to them.
+	Propagators []string `json:"propagators,omitempty" yaml:"propagators,omitempty"`
 }
 
 // ZipkinSpec defines Zipkin exporter configurations.
@@ -157,11 +346,97 @@ type OtelSpec struct {
 	Protocol        string `json:"protocol" yaml:"protocol"`
 	EndpointAddress string `json:"endpointAddress" yaml:"endpointAddress"`
 	IsSecure        bool   `json:"isSecure" yaml:"isSecure"`
+	// Headers are sent with every export request, for example to authenticate with the collector.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// TLS configures the secure connection to the collector. It is used only when IsSecure is true.
+	TLS *OtelTLSSpec `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// OtelTLSSpec defines the TLS configuration of the connection to the Otel collector.
+type OtelTLSSpec struct {
+	// CAFile is the path to the PEM encoded CA certificates used to verify the collector. Defaults to the system roots.
+	CAFile string `json:"caFile,omitempty" yaml:"caFile,omitempty"`
+	// CertFile and KeyFile are the paths to the PEM encoded client certificate and key, for mutual TLS.
+	CertFile           string `json:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile            string `json:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+}
+
+// GetTLSConfig returns the TLS configuration of the connection to the collector.
+// It returns nil when the connection isn't secure, or when the default TLS configuration must be used.
+func (o OtelSpec) GetTLSConfig() (*tls.Config, error) {
+	if !o.IsSecure || o.TLS == nil {
+		return nil, nil
+	}
+
+	//nolint:gosec
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.TLS.InsecureSkipVerify,
+	}
+	if o.TLS.CAFile != "" {
+		ca, err := os.ReadFile(o.TLS.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the CA certificates of the Otel collector")
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.Errorf("no valid certificate found in %s", o.TLS.CAFile)
+		}
+	}
+	if o.TLS.CertFile != "" || o.TLS.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.TLS.CertFile, o.TLS.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the client certificate for the Otel collector")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
 }
 
 // MetricSpec configuration for metrics.
 type MetricSpec struct {
-	Enabled bool `json:"enabled" yaml:"enabled"`
+	Enabled bool        `json:"enabled" yaml:"enabled"`
+	HTTP    *MetricHTTP `json:"http,omitempty" yaml:"http,omitempty"`
+	// Otel configures the export of the metrics to an OpenTelemetry collector, in addition to the Prometheus endpoint.
+	Otel *OtelSpec `json:"otel,omitempty" yaml:"otel,omitempty"`
+	// Rules transform the labels of the metrics before they are recorded, to limit their cardinality.
+	Rules []MetricsRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// PayloadSchema records the schema of the payloads of the pub/sub messages and of the bindings in the schema label
+	// of the payload size metrics. It's disabled by default, as the schemas are set by the producers of the payloads.
+	PayloadSchema bool `json:"payloadSchema,omitempty" yaml:"payloadSchema,omitempty"`
+}
+
+// MetricsRule defines the rules applied to the labels of a metric.
+// The name is either the name exported to Prometheus, like "dapr_http_server_request_count", or the name of the measure.
+type MetricsRule struct {
+	Name   string        `json:"name" yaml:"name"`
+	Labels []MetricLabel `json:"labels" yaml:"labels"`
+}
+
+// MetricLabel defines the rule applied to a label of a metric.
+type MetricLabel struct {
+	Name string `json:"name" yaml:"name"`
+	// Regex maps the replacement values to regular expressions: the values of the label matching an expression are replaced.
+	// The expressions are evaluated in the order of the replacement values, and the first match wins.
+	Regex map[string]string `json:"regex,omitempty" yaml:"regex,omitempty"`
+	// Drop removes the label from the metric.
+	Drop bool `json:"drop,omitempty" yaml:"drop,omitempty"`
+}
+
+// MetricHTTP defines configuration for the HTTP metrics.
+type MetricHTTP struct {
+	// PathTemplates are route templates like "/orders/{id}", which the paths of the requests are collapsed to
+	// in the HTTP metrics and the API logs. Paths that don't match any template have the segments that look like IDs collapsed.
+	PathTemplates []string `json:"pathTemplates,omitempty" yaml:"pathTemplates,omitempty"`
+}
+
+// GetPathTemplates returns the route templates of the HTTP metrics.
+func (m MetricSpec) GetPathTemplates() []string {
+	if m.HTTP == nil {
+		return nil
+	}
+	return m.HTTP.PathTemplates
 }
 
 // AppPolicySpec defines the policy data structure for each app.
@@ -185,18 +460,133 @@ type AccessControlSpec struct {
 	DefaultAction string          `json:"defaultAction" yaml:"defaultAction"`
 	TrustDomain   string          `json:"trustDomain" yaml:"trustDomain"`
 	AppPolicies   []AppPolicySpec `json:"policies" yaml:"policies"`
+	// CrossNamespace restricts the service invocations from the apps of other namespaces. Unrestricted if nil.
+	CrossNamespace *CrossNamespaceSpec `json:"crossNamespace,omitempty" yaml:"crossNamespace,omitempty"`
+	// Outbound restricts the service invocations to the apps of other namespaces and clusters. Unrestricted if nil.
+	Outbound *OutboundSpec `json:"outbound,omitempty" yaml:"outbound,omitempty"`
+}
+
+// CrossNamespaceSpec is the policy of the service invocations from the apps of other namespaces.
+// The calls from other namespaces, and from the namespace of the same name in other trust domains, are denied unless
+// their caller is allowed.
+type CrossNamespaceSpec struct {
+	AllowedCallers []CrossNamespaceCaller `json:"allowedCallers,omitempty" yaml:"allowedCallers,omitempty"`
+}
+
+// CrossNamespaceCaller is an app of another namespace allowed to invoke the apps of this namespace.
+type CrossNamespaceCaller struct {
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// AppID of the caller, or all the apps of the namespace if empty.
+	AppID string `json:"appId,omitempty" yaml:"appId,omitempty"`
+	// Callees are the IDs of the apps the caller can invoke, or all the apps using the configuration if empty.
+	Callees []string `json:"callees,omitempty" yaml:"callees,omitempty"`
+	// TrustDomain of the caller, or any trust domain if empty.
+	TrustDomain string `json:"trustDomain,omitempty" yaml:"trustDomain,omitempty"`
+}
+
+// OutboundSpec is the policy of the service invocations to the apps of other namespaces and clusters.
+// The calls to other namespaces and clusters are denied unless their callee is allowed.
+type OutboundSpec struct {
+	AllowedCallees []OutboundCallee `json:"allowedCallees,omitempty" yaml:"allowedCallees,omitempty"`
+}
+
+// OutboundCallee is an app of another namespace or cluster the apps using the configuration are allowed to invoke.
+type OutboundCallee struct {
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// AppID of the callee, or all the apps of the namespace if empty.
+	AppID string `json:"appId,omitempty" yaml:"appId,omitempty"`
+	// Cluster of the callee, or the local cluster if empty.
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
 }
 
 type NameResolutionSpec struct {
 	Component     string      `json:"component" yaml:"component"`
 	Version       string      `json:"version" yaml:"version"`
 	Configuration interface{} `json:"configuration" yaml:"configuration"`
+	// Clusters are the remote clusters whose apps are invoked with the app-id.namespace.cluster targets.
+	Clusters []ClusterSpec `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	// LoadBalancing is the policy of the balancing of the service invocations between the instances of the apps.
+	LoadBalancing *LoadBalancingSpec `json:"loadBalancing,omitempty" yaml:"loadBalancing,omitempty"`
+}
+
+// Load balancing policies of the service invocations.
+const (
+	RoundRobinLoadBalancing   = "roundRobin"
+	LeastRequestLoadBalancing = "leastRequest"
+	ZoneAwareLoadBalancing    = "zoneAware"
+)
+
+// LoadBalancingSpec configures the balancing of the service invocations between the instances of the apps.
+type LoadBalancingSpec struct {
+	// Policy is roundRobin (the default), leastRequest or zoneAware.
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+	// LocalZone is the zone of the sidecar for the zoneAware policy. If empty, it is the zone of the host address.
+	LocalZone string `json:"localZone,omitempty" yaml:"localZone,omitempty"`
+	// Zones are the zones of the instances for the zoneAware policy, which prefers the instances of the local zone.
+	Zones []ZoneSpec `json:"zones,omitempty" yaml:"zones,omitempty"`
+}
+
+// ZoneSpec is a zone of the instances of the apps, identified by the subnets of their addresses.
+type ZoneSpec struct {
+	Name  string   `json:"name" yaml:"name"`
+	CIDRs []string `json:"cidrs" yaml:"cidrs"`
+}
+
+// ClusterSpec is a remote cluster whose apps can be invoked.
+type ClusterSpec struct {
+	Name string `json:"name" yaml:"name"`
+	// Address of the internal gRPC endpoint of the apps of the cluster, where {appID} and {namespace} are replaced
+	// with the app ID and the namespace of the callee; for example "{appID}-dapr.{namespace}.svc.clusterset.local:50002".
+	Address string `json:"address" yaml:"address"`
 }
 
 type MTLSSpec struct {
-	Enabled          bool   `json:"enabled" yaml:"enabled"`
-	WorkloadCertTTL  string `json:"workloadCertTTL" yaml:"workloadCertTTL"`
-	AllowedClockSkew string `json:"allowedClockSkew" yaml:"allowedClockSkew"`
+	Enabled          bool            `json:"enabled" yaml:"enabled"`
+	WorkloadCertTTL  string          `json:"workloadCertTTL" yaml:"workloadCertTTL"`
+	AllowedClockSkew string          `json:"allowedClockSkew" yaml:"allowedClockSkew"`
+	TokenValidators  []ValidatorSpec `json:"tokenValidators,omitempty" yaml:"tokenValidators,omitempty"`
+	// AllowedDNSNames are the DNS names that the sidecars of each app ID can request on their workload certificates.
+	AllowedDNSNames []AllowedDNSNamesSpec `json:"allowedDNSNames,omitempty" yaml:"allowedDNSNames,omitempty"`
+	// SPIFFEIDTemplate is the template of the SPIFFE IDs of the workload certificates.
+	SPIFFEIDTemplate string `json:"spiffeIDTemplate,omitempty" yaml:"spiffeIDTemplate,omitempty"`
+	// CSRRateLimit limits the certificate signing requests of each app ID and namespace.
+	CSRRateLimit *CSRRateLimitSpec `json:"csrRateLimit,omitempty" yaml:"csrRateLimit,omitempty"`
+}
+
+// AllowedDNSNamesSpec defines the DNS names that the sidecars of apps of a namespace can request on their workload certificates.
+type AllowedDNSNamesSpec struct {
+	// Namespace is the namespace of the sidecars.
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// AppIDs are the app IDs of the sidecars.
+	AppIDs []string `json:"appIDs" yaml:"appIDs"`
+	// Names are the patterns of the DNS names. A name starting with "*." allows all the names with one more label.
+	Names []string `json:"names" yaml:"names"`
+}
+
+// Enforcements of the CSR rate limits.
+const (
+	CSRRateLimitWarn   = "warn"
+	CSRRateLimitReject = "reject"
+)
+
+// CSRRateLimitSpec defines the rate limits of the certificate signing requests received by sentry.
+type CSRRateLimitSpec struct {
+	// AppIDRequestsPerMinute is the rate of the requests of each verified requester ID in a namespace, unlimited if 0.
+	AppIDRequestsPerMinute int `json:"appIDRequestsPerMinute,omitempty" yaml:"appIDRequestsPerMinute,omitempty"`
+	// NamespaceRequestsPerMinute is the rate of the requests of each namespace, unlimited if 0.
+	NamespaceRequestsPerMinute int `json:"namespaceRequestsPerMinute,omitempty" yaml:"namespaceRequestsPerMinute,omitempty"`
+	// Burst is the number of requests accepted at once, the rate per minute by default.
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
+	// Enforcement is "warn" to only report the requests above the limits, or "reject" to reject them. Defaults to "warn".
+	Enforcement string `json:"enforcement,omitempty" yaml:"enforcement,omitempty"`
+}
+
+// ValidatorSpec contains additional token validators to use.
+type ValidatorSpec struct {
+	// Name of the validator
+	Name string `json:"name" yaml:"name"`
+	// Options for the validator, if any
+	Options map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
 }
 
 // SpiffeID represents the separated fields in a spiffe id.
@@ -216,6 +606,64 @@ type FeatureSpec struct {
 type ComponentsSpec struct {
 	// Denylist of component types that cannot be instantiated
 	Deny []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+	// Default initialization timeout of the components that don't set one
+	InitTimeout string `json:"initTimeout,omitempty" yaml:"initTimeout,omitempty"`
+	// Initialize the components of different categories concurrently at startup, after the secret stores
+	ParallelInit bool `json:"parallelInit,omitempty" yaml:"parallelInit,omitempty"`
+	// Names of the optional components, initialized without blocking the readiness of the sidecar and marked as degraded if they fail
+	Optional []string `json:"optional,omitempty" yaml:"optional,omitempty"`
+	// Retries of the initialization of the components whose errors are ignored and of the optional components
+	InitRetry ComponentInitRetrySpec `json:"initRetry,omitempty" yaml:"initRetry,omitempty"`
+}
+
+// ComponentInitRetrySpec configures the retries with exponential backoff of the failed initializations of the components.
+// The sidecar exits once the retries of a component that is neither optional nor ignoring its errors are exhausted.
+type ComponentInitRetrySpec struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Delay before the first retry, 1s by default
+	InitialInterval string `json:"initialInterval,omitempty" yaml:"initialInterval,omitempty"`
+	// Maximum delay between the retries, 1m by default
+	MaxInterval string `json:"maxInterval,omitempty" yaml:"maxInterval,omitempty"`
+	// Maximum number of retries; 0 means no limit
+	MaxRetries int `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+}
+
+// GRPCProxySpec describes the configuration for the gRPC proxy.
+type GRPCProxySpec struct {
+	// Policy for the metadata forwarded with proxied calls
+	Metadata GRPCProxyMetadataSpec `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// Maximum size in bytes of the metadata of a proxied call; 0 means no limit
+	MaxMetadataSize int `json:"maxMetadataSize,omitempty" yaml:"maxMetadataSize,omitempty"`
+}
+
+// GRPCProxyMetadataSpec controls which gRPC metadata keys are forwarded, stripped, or renamed by the gRPC proxy.
+type GRPCProxyMetadataSpec struct {
+	// Allowlist of metadata keys that are forwarded; if empty, all keys that are not denied are forwarded
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	// Denylist of metadata keys that are stripped
+	Deny []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+	// Metadata keys that are forwarded with a different name
+	Rename []GRPCProxyMetadataRename `json:"rename,omitempty" yaml:"rename,omitempty"`
+}
+
+// GRPCProxyMetadataRename renames a metadata key forwarded by the gRPC proxy.
+type GRPCProxyMetadataRename struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// ActorsSpec describes the configuration for the actors runtime.
+type ActorsSpec struct {
+	// Publishing of the actor lifecycle events
+	LifecycleEvents ActorLifecycleEventsSpec `json:"lifecycleEvents,omitempty" yaml:"lifecycleEvents,omitempty"`
+}
+
+// ActorLifecycleEventsSpec configures the topic where the actor lifecycle events are published.
+type ActorLifecycleEventsSpec struct {
+	// Name of the pubsub component; events are not published if empty
+	PubsubName string `json:"pubsubName" yaml:"pubsubName"`
+	// Topic where the events are published
+	Topic string `json:"topic,omitempty" yaml:"topic,omitempty"`
 }
 
 // LoadDefaultConfiguration returns the default config.
@@ -239,21 +687,29 @@ func LoadDefaultConfiguration() *Configuration {
 	}
 }
 
-// LoadStandaloneConfiguration gets the path to a config file and loads it into a configuration.
+// LoadStandaloneConfiguration gets the path to a config file, or an HTTP(S) URL, and loads it into a configuration.
+// The requests to the URLs send the value of the DAPR_CONFIG_AUTHORIZATION environment variable as Authorization header,
+// which is only sent to HTTP URLs if DAPR_CONFIG_AUTHORIZATION_INSECURE is "true".
 func LoadStandaloneConfiguration(config string) (*Configuration, string, error) {
-	_, err := os.Stat(config)
-	if err != nil {
-		return nil, "", err
-	}
+	var b []byte
+	var err error
+	if strings.HasPrefix(config, "http://") || strings.HasPrefix(config, "https://") {
+		// The environment variables aren't expanded in the configuration of a remote server, which could otherwise
+		// read the secrets of the environment of the sidecar, such as its API token.
+		b, err = fetchConfiguration(config)
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		b, err = os.ReadFile(config)
+		if err != nil {
+			return nil, "", err
+		}
 
-	b, err := os.ReadFile(config)
-	if err != nil {
-		return nil, "", err
+		// Parse environment variables from yaml
+		b = []byte(os.ExpandEnv(string(b)))
 	}
 
-	// Parse environment variables from yaml
-	b = []byte(os.ExpandEnv(string(b)))
-
 	conf := LoadDefaultConfiguration()
 	err = yaml.Unmarshal(b, conf)
 	if err != nil {
@@ -263,12 +719,50 @@ func LoadStandaloneConfiguration(config string) (*Configuration, string, error)
 	if err != nil {
 		return nil, string(b), err
 	}
+	err = validateCORSConfiguration(conf)
+	if err != nil {
+		return nil, string(b), err
+	}
 
 	noDefaultContentTypeValue = IsFeatureEnabled(conf.Spec.Features, NoDefaultContentType)
 
 	return conf, string(b), nil
 }
 
+// fetchConfiguration gets the configuration at the URL, such as a configuration distributed to VM fleets by a central server.
+func fetchConfiguration(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), configURLTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid configuration url")
+	}
+	if auth := os.Getenv(env.ConfigAuthorization); auth != "" {
+		if req.URL.Scheme != "https" && !strings.EqualFold(os.Getenv(env.ConfigAuthorizationInsecure), "true") {
+			return nil, errors.Errorf("refusing to send the %s header over %s; use an https url or set %s to true", env.ConfigAuthorization, req.URL.Scheme, env.ConfigAuthorizationInsecure)
+		}
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the configuration")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get the configuration: status code %d", resp.StatusCode)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxConfigURLSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the configuration")
+	}
+	if len(b) > maxConfigURLSize {
+		return nil, errors.Errorf("configuration exceeds the maximum size of %d bytes", maxConfigURLSize)
+	}
+	return b, nil
+}
+
 // LoadKubernetesConfiguration gets configuration from the Kubernetes operator with a given name.
 func LoadKubernetesConfiguration(config, namespace string, podName string, operatorClient operatorv1pb.OperatorClient) (*Configuration, error) {
 	resp, err := operatorClient.GetConfiguration(context.Background(), &operatorv1pb.GetConfigurationRequest{
@@ -292,12 +786,128 @@ func LoadKubernetesConfiguration(config, namespace string, podName string, opera
 	if err != nil {
 		return nil, err
 	}
+	err = validateCORSConfiguration(conf)
+	if err != nil {
+		return nil, err
+	}
 
 	noDefaultContentTypeValue = IsFeatureEnabled(conf.Spec.Features, NoDefaultContentType)
 
 	return conf, nil
 }
 
+// SplitConfigurationNames returns the names of the comma separated list of configurations.
+func SplitConfigurationNames(configs string) []string {
+	var names []string
+	for _, name := range strings.Split(configs, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// MergeConfigurations merges the configurations of a sidecar, such as a platform-wide baseline followed by the configuration of a team.
+// The first configuration provides all the sections, and the next ones override its tracing, metrics, features and log
+// levels in order:
+//   - the tracing fields set by a configuration replace the previous ones, the attributes are merged by name;
+//   - the metrics are disabled by any configuration disabling them, the HTTP and Otel settings set by a configuration
+//     replace the previous ones, and the rules are merged by metric name;
+//   - the features are merged by name;
+//   - the log levels are merged by scope.
+func MergeConfigurations(configs ...*Configuration) *Configuration {
+	if len(configs) == 0 {
+		return LoadDefaultConfiguration()
+	}
+
+	merged := configs[0]
+	for _, conf := range configs[1:] {
+		mergeTracingSpec(&merged.Spec.TracingSpec, conf.Spec.TracingSpec)
+		mergeMetricSpec(&merged.Spec.MetricSpec, conf.Spec.MetricSpec)
+		for _, feature := range conf.Spec.Features {
+			found := false
+			for i := range merged.Spec.Features {
+				if merged.Spec.Features[i].Name == feature.Name {
+					merged.Spec.Features[i] = feature
+					found = true
+				}
+			}
+			if !found {
+				merged.Spec.Features = append(merged.Spec.Features, feature)
+			}
+		}
+		for scope, level := range conf.Spec.LoggingSpec.Levels {
+			if merged.Spec.LoggingSpec.Levels == nil {
+				merged.Spec.LoggingSpec.Levels = map[string]string{}
+			}
+			merged.Spec.LoggingSpec.Levels[scope] = level
+		}
+	}
+
+	noDefaultContentTypeValue = IsFeatureEnabled(merged.Spec.Features, NoDefaultContentType)
+
+	return merged
+}
+
+func mergeTracingSpec(merged *TracingSpec, tracing TracingSpec) {
+	if tracing.SamplingRate != "" {
+		merged.SamplingRate = tracing.SamplingRate
+	}
+	if tracing.Stdout {
+		merged.Stdout = true
+	}
+	if tracing.Zipkin.EndpointAddress != "" {
+		merged.Zipkin = tracing.Zipkin
+	}
+	if tracing.Otel.EndpointAddress != "" {
+		merged.Otel = tracing.Otel
+	}
+	if len(tracing.Attributes) > 0 && merged.Attributes == nil {
+		merged.Attributes = make(map[string]string, len(tracing.Attributes))
+	}
+	for k, v := range tracing.Attributes {
+		merged.Attributes[k] = v
+	}
+	if len(tracing.Propagators) > 0 {
+		merged.Propagators = tracing.Propagators
+	}
+}
+
+func mergeMetricSpec(merged *MetricSpec, metrics MetricSpec) {
+	merged.Enabled = merged.Enabled && metrics.Enabled
+	if metrics.HTTP != nil {
+		merged.HTTP = metrics.HTTP
+	}
+	if metrics.Otel != nil {
+		merged.Otel = metrics.Otel
+	}
+	for _, rule := range metrics.Rules {
+		found := false
+		for i := range merged.Rules {
+			if merged.Rules[i].Name == rule.Name {
+				merged.Rules[i] = rule
+				found = true
+			}
+		}
+		if !found {
+			merged.Rules = append(merged.Rules, rule)
+		}
+	}
+}
+
+// validateCORSConfiguration returns an error if a policy of the CORS configuration is invalid.
+func validateCORSConfiguration(conf *Configuration) error {
+	if err := conf.Spec.CORSSpec.CORSPolicy.Validate(); err != nil {
+		return errors.Wrap(err, "invalid cors policy")
+	}
+	for _, route := range conf.Spec.CORSSpec.Routes {
+		if err := route.CORSPolicy.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid cors policy of route %s", route.Path)
+		}
+	}
+	return nil
+}
+
 // Validate the secrets configuration and sort to the allowed and denied lists if present.
 func sortAndValidateSecretsConfiguration(conf *Configuration) error {
 	scopes := conf.Spec.Secrets.Scopes
@@ -319,6 +929,17 @@ func sortAndValidateSecretsConfiguration(conf *Configuration) error {
 		sort.Strings(scope.DeniedSecrets)
 	}
 
+	chain := sets.NewString()
+	for _, store := range conf.Spec.Secrets.ResolutionChain {
+		if store == "" {
+			return errors.New("empty secret store name in secrets resolution chain")
+		}
+		if chain.Has(store) {
+			return errors.Errorf("%q secret store is repeated in secrets resolution chain", store)
+		}
+		chain.Insert(store)
+	}
+
 	return nil
 }
 
@@ -353,13 +974,16 @@ func containsKey(s []string, key string) bool {
 	return index < len(s) && s[index] == key
 }
 
+// IsFeatureEnabled returns the state of the feature in the features of the configuration,
+// or the default state of its feature gate when the configuration doesn't set it.
 func IsFeatureEnabled(features []FeatureSpec, target Feature) bool {
 	for _, feature := range features {
 		if feature.Name == target {
 			return feature.Enabled
 		}
 	}
-	return false
+	gate, _ := getFeatureGate(target)
+	return gate.Default
 }
 
 // GetNoDefaultContentType returns the value of the noDefaultContentType flag.
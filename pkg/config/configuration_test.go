@@ -14,11 +14,15 @@ limitations under the License.
 package config
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"reflect"
 	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadStandaloneConfiguration(t *testing.T) {
@@ -64,6 +68,56 @@ func TestLoadStandaloneConfiguration(t *testing.T) {
 		assert.NotNil(t, config, "Config not loaded as expected")
 		assert.Equal(t, "keepitsecret", config.Spec.Secrets.Scopes[0].AllowedSecrets[0])
 	})
+
+	t.Run("Configuration URL", func(t *testing.T) {
+		b, err := os.ReadFile("./testdata/config.yaml")
+		require.NoError(t, err)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write(b)
+		}))
+		defer server.Close()
+
+		_, _, err = LoadStandaloneConfiguration(server.URL + "/config.yaml")
+		assert.Error(t, err)
+
+		t.Setenv("DAPR_CONFIG_AUTHORIZATION", "Bearer token1")
+		_, _, err = LoadStandaloneConfiguration(server.URL + "/config.yaml")
+		assert.ErrorContains(t, err, "refusing to send the DAPR_CONFIG_AUTHORIZATION header over http")
+
+		t.Setenv("DAPR_CONFIG_AUTHORIZATION_INSECURE", "true")
+		config, content, err := LoadStandaloneConfiguration(server.URL + "/config.yaml")
+		require.NoError(t, err)
+		assert.NotNil(t, config)
+		assert.Equal(t, string(b), content)
+	})
+
+	t.Run("Environment variables aren't parsed in the configuration URL", func(t *testing.T) {
+		b, err := os.ReadFile("./testdata/env_variables_config.yaml")
+		require.NoError(t, err)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(b)
+		}))
+		defer server.Close()
+
+		t.Setenv("DAPR_SECRET", "keepitsecret")
+		config, _, err := LoadStandaloneConfiguration(server.URL + "/config.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "${DAPR_SECRET}", config.Spec.Secrets.Scopes[0].AllowedSecrets[0])
+	})
+
+	t.Run("Configuration URL exceeding the maximum size", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(make([]byte, maxConfigURLSize+1))
+		}))
+		defer server.Close()
+
+		_, _, err := LoadStandaloneConfiguration(server.URL + "/config.yaml")
+		assert.ErrorContains(t, err, "maximum size")
+	})
 }
 
 func TestLoadStandaloneConfigurationKindName(t *testing.T) {
@@ -103,6 +157,142 @@ func TestMetricSpecForStandAlone(t *testing.T) {
 	}
 }
 
+func TestMetricPathTemplatesForStandAlone(t *testing.T) {
+	t.Run("path templates are loaded", func(t *testing.T) {
+		config, _, err := LoadStandaloneConfiguration("./testdata/metric_path_templates.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/orders/{id}", "/users/{name}/orders"}, config.Spec.MetricSpec.GetPathTemplates())
+	})
+
+	t.Run("no path templates by default", func(t *testing.T) {
+		config, _, err := LoadStandaloneConfiguration("./testdata/config.yaml")
+		assert.NoError(t, err)
+		assert.Nil(t, config.Spec.MetricSpec.GetPathTemplates())
+	})
+}
+
+func TestMetricRulesForStandAlone(t *testing.T) {
+	config, _, err := LoadStandaloneConfiguration("./testdata/metric_rules.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []MetricsRule{{
+		Name: "dapr_http_server_request_count",
+		Labels: []MetricLabel{{
+			Name:  "path",
+			Regex: map[string]string{"/orders/{id}": "^/orders/[^/]+$"},
+		}, {
+			Name: "method",
+			Drop: true,
+		}},
+	}}, config.Spec.MetricSpec.Rules)
+}
+
+func TestAuditSpecForStandAlone(t *testing.T) {
+	config, _, err := LoadStandaloneConfiguration("./testdata/audit_config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, AuditSpec{
+		Enabled:         true,
+		Output:          "/var/log/dapr/audit.log",
+		Headers:         []string{"x-request-id", "x-user-email"},
+		RedactedHeaders: []string{"x-user-email"},
+		PayloadHash:     true,
+	}, config.Spec.AuditSpec)
+}
+
+func TestProxySpecForStandAlone(t *testing.T) {
+	config, _, err := LoadStandaloneConfiguration("./testdata/proxy_config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, ProxySpec{
+		HTTPProxy:  "http://proxy.corp.example.com:3128",
+		HTTPSProxy: "socks5://proxy.corp.example.com:1080",
+		NoProxy:    []string{".svc.cluster.local", "10.0.0.0/8"},
+		Auth: ProxyAuthSpec{
+			SecretStore: "vault",
+			SecretName:  "proxy-credentials",
+			UsernameKey: "user",
+		},
+	}, config.Spec.ProxySpec)
+}
+
+func TestCrossNamespaceSpecForStandAlone(t *testing.T) {
+	config, _, err := LoadStandaloneConfiguration("./testdata/cross_namespace_config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, &CrossNamespaceSpec{
+		AllowedCallers: []CrossNamespaceCaller{
+			{Namespace: "frontend", AppID: "web", Callees: []string{"orders"}},
+			{Namespace: "monitoring"},
+			{Namespace: "frontend", TrustDomain: "us-west"},
+		},
+	}, config.Spec.AccessControlSpec.CrossNamespace)
+	assert.Equal(t, &OutboundSpec{
+		AllowedCallees: []OutboundCallee{
+			{Namespace: "payments", AppID: "checkout"},
+			{Namespace: "default", Cluster: "us-west"},
+		},
+	}, config.Spec.AccessControlSpec.Outbound)
+	assert.Equal(t, []ClusterSpec{
+		{Name: "us-west", Address: "{appID}-dapr.{namespace}.svc.us-west.local:50002"},
+	}, config.Spec.NameResolutionSpec.Clusters)
+
+	config, _, err = LoadStandaloneConfiguration("./testdata/config.yaml")
+	require.NoError(t, err)
+	assert.Nil(t, config.Spec.AccessControlSpec.CrossNamespace)
+	assert.Nil(t, config.Spec.AccessControlSpec.Outbound)
+}
+
+func TestAPISpecForStandAlone(t *testing.T) {
+	config, _, err := LoadStandaloneConfiguration("./testdata/api_config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, APISpec{
+		Allowed:     []APIAccessRule{{Name: "state", Version: "v1.0", Protocol: "http"}},
+		Denied:      []APIAccessRule{{Name: "secrets", Version: "v1", Protocol: "grpc"}},
+		EnableAlpha: true,
+	}, config.Spec.APISpec)
+}
+
+func TestOtelSpecForStandAlone(t *testing.T) {
+	config, _, err := LoadStandaloneConfiguration("./testdata/otel_config.yaml")
+	require.NoError(t, err)
+
+	t.Run("tracing exporter", func(t *testing.T) {
+		otel := config.Spec.TracingSpec.Otel
+		assert.Equal(t, "collector:4317", otel.EndpointAddress)
+		assert.Equal(t, map[string]string{"api-key": "secret"}, otel.Headers)
+		require.NotNil(t, otel.TLS)
+		// The CA file doesn't exist
+		_, err := otel.GetTLSConfig()
+		assert.Error(t, err)
+	})
+
+	t.Run("span attributes", func(t *testing.T) {
+		assert.Equal(t, map[string]string{"team": "payments", "environment": "staging"}, config.Spec.TracingSpec.Attributes)
+	})
+
+	t.Run("propagators", func(t *testing.T) {
+		assert.Equal(t, []string{"b3", "w3c"}, config.Spec.TracingSpec.Propagators)
+	})
+
+	t.Run("metrics exporter", func(t *testing.T) {
+		otel := config.Spec.MetricSpec.Otel
+		require.NotNil(t, otel)
+		assert.Equal(t, "collector:4318", otel.EndpointAddress)
+		assert.Equal(t, "http", otel.Protocol)
+		tlsConfig, err := otel.GetTLSConfig()
+		assert.NoError(t, err)
+		assert.Nil(t, tlsConfig)
+	})
+
+	t.Run("default TLS configuration", func(t *testing.T) {
+		tlsConfig, err := OtelSpec{IsSecure: true}.GetTLSConfig()
+		assert.NoError(t, err)
+		assert.Nil(t, tlsConfig)
+
+		tlsConfig, err = OtelSpec{IsSecure: true, TLS: &OtelTLSSpec{InsecureSkipVerify: true}}.GetTLSConfig()
+		assert.NoError(t, err)
+		require.NotNil(t, tlsConfig)
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+	})
+}
+
 func TestComponentsSpecForStandAlone(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -125,6 +315,75 @@ func TestComponentsSpecForStandAlone(t *testing.T) {
 	}
 }
 
+func TestCORSSpecForStandAlone(t *testing.T) {
+	t.Run("cors policy is loaded", func(t *testing.T) {
+		config, _, err := LoadStandaloneConfiguration("./testdata/cors_config.yaml")
+		assert.NoError(t, err)
+		cors := config.Spec.CORSSpec
+		assert.True(t, cors.IsEnabled())
+		assert.Equal(t, []string{"https://example.com"}, cors.AllowedOrigins)
+		assert.Equal(t, []string{"GET", "POST"}, cors.AllowedMethods)
+		assert.True(t, cors.AllowCredentials)
+		assert.Equal(t, 600, cors.MaxAge)
+		assert.Equal(t, []CORSRoutePolicy{
+			{Path: "/v1.0/invoke/", CORSPolicy: CORSPolicy{AllowedOrigins: []string{"https://invoke.example.com"}}},
+		}, cors.Routes)
+	})
+
+	t.Run("no cors policy by default", func(t *testing.T) {
+		config, _, err := LoadStandaloneConfiguration("./testdata/config.yaml")
+		assert.NoError(t, err)
+		assert.False(t, config.Spec.CORSSpec.IsEnabled())
+	})
+
+	t.Run("credentials without allowed origins are rejected", func(t *testing.T) {
+		_, _, err := LoadStandaloneConfiguration("./testdata/cors_credentials_any_origin_config.yaml")
+		assert.ErrorContains(t, err, "allowCredentials requires an explicit list of allowedOrigins")
+	})
+}
+
+func TestValidateCORSConfiguration(t *testing.T) {
+	testCases := []struct {
+		name   string
+		spec   CORSSpec
+		errMsg string
+	}{
+		{
+			name: "credentials with allowed origins",
+			spec: CORSSpec{CORSPolicy: CORSPolicy{AllowedOrigins: []string{"https://example.com"}, AllowCredentials: true}},
+		},
+		{
+			name: "all origins without credentials",
+			spec: CORSSpec{CORSPolicy: CORSPolicy{AllowedMethods: []string{"GET"}}},
+		},
+		{
+			name:   "credentials with the wildcard origin",
+			spec:   CORSSpec{CORSPolicy: CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}},
+			errMsg: "wildcard origin",
+		},
+		{
+			name: "credentials without allowed origins in a route",
+			spec: CORSSpec{Routes: []CORSRoutePolicy{
+				{Path: "/v1.0/invoke/", CORSPolicy: CORSPolicy{AllowCredentials: true}},
+			}},
+			errMsg: "invalid cors policy of route /v1.0/invoke/",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conf := LoadDefaultConfiguration()
+			conf.Spec.CORSSpec = tc.spec
+			err := validateCORSConfiguration(conf)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+}
+
 func TestSortAndValidateSecretsConfigration(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -220,6 +479,28 @@ func TestSortAndValidateSecretsConfigration(t *testing.T) {
 			},
 			errorExpected: false,
 		},
+		{
+			name: "resolution chain",
+			config: Configuration{
+				Spec: ConfigurationSpec{
+					Secrets: SecretsSpec{
+						ResolutionChain: []string{"vault", "kubernetes"},
+					},
+				},
+			},
+			errorExpected: false,
+		},
+		{
+			name: "repeated store in resolution chain",
+			config: Configuration{
+				Spec: ConfigurationSpec{
+					Secrets: SecretsSpec{
+						ResolutionChain: []string{"vault", "kubernetes", "vault"},
+					},
+				},
+			},
+			errorExpected: true,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -341,6 +622,58 @@ func TestFeatureEnabled(t *testing.T) {
 	})
 }
 
+func TestSplitConfigurationNames(t *testing.T) {
+	assert.Equal(t, []string{"config1"}, SplitConfigurationNames("config1"))
+	assert.Equal(t, []string{"baseline", "team1"}, SplitConfigurationNames(" baseline, team1,"))
+	assert.Empty(t, SplitConfigurationNames(""))
+}
+
+func TestMergeConfigurations(t *testing.T) {
+	baseline := LoadDefaultConfiguration()
+	baseline.Spec.TracingSpec.SamplingRate = "0.1"
+	baseline.Spec.TracingSpec.Zipkin.EndpointAddress = "http://zipkin:9411/api/v2/spans"
+	baseline.Spec.TracingSpec.Attributes = map[string]string{"environment": "production", "team": "platform"}
+	baseline.Spec.MetricSpec.Rules = []MetricsRule{{Name: "dapr_http_server_request_count", Labels: []MetricLabel{{Name: "path", Drop: true}}}}
+	baseline.Spec.Features = []FeatureSpec{{Name: Resiliency, Enabled: true}, {Name: "Test.Feature", Enabled: true}}
+	baseline.Spec.AccessControlSpec.DefaultAction = DenyAccess
+	baseline.Spec.LoggingSpec.Levels = map[string]string{"dapr.runtime.actor": "debug", "dapr.contrib.pubsub.kafka": "warn"}
+
+	team := LoadDefaultConfiguration()
+	team.Spec.TracingSpec.SamplingRate = "1"
+	team.Spec.TracingSpec.Attributes = map[string]string{"team": "payments"}
+	team.Spec.MetricSpec.HTTP = &MetricHTTP{PathTemplates: []string{"/orders/{id}"}}
+	team.Spec.MetricSpec.Rules = []MetricsRule{{Name: "dapr_grpc_io_server_completed_rpcs"}}
+	team.Spec.Features = []FeatureSpec{{Name: "Test.Feature", Enabled: false}, {Name: NoDefaultContentType, Enabled: true}}
+	team.Spec.AccessControlSpec.DefaultAction = AllowAccess
+	team.Spec.LoggingSpec.Levels = map[string]string{"dapr.contrib.pubsub.kafka": "debug"}
+
+	defer SetNoDefaultContentType(false)
+	merged := MergeConfigurations(baseline, team)
+
+	assert.Equal(t, "1", merged.Spec.TracingSpec.SamplingRate)
+	assert.Equal(t, "http://zipkin:9411/api/v2/spans", merged.Spec.TracingSpec.Zipkin.EndpointAddress)
+	assert.Equal(t, map[string]string{"environment": "production", "team": "payments"}, merged.Spec.TracingSpec.Attributes)
+	assert.True(t, merged.Spec.MetricSpec.Enabled)
+	assert.Equal(t, []string{"/orders/{id}"}, merged.Spec.MetricSpec.GetPathTemplates())
+	assert.Len(t, merged.Spec.MetricSpec.Rules, 2)
+	assert.Equal(t, []FeatureSpec{
+		{Name: Resiliency, Enabled: true},
+		{Name: "Test.Feature", Enabled: false},
+		{Name: NoDefaultContentType, Enabled: true},
+	}, merged.Spec.Features)
+	assert.True(t, GetNoDefaultContentType())
+	assert.Equal(t, map[string]string{"dapr.runtime.actor": "debug", "dapr.contrib.pubsub.kafka": "debug"}, merged.Spec.LoggingSpec.Levels)
+	// The other sections are the ones of the first configuration.
+	assert.Equal(t, DenyAccess, merged.Spec.AccessControlSpec.DefaultAction)
+
+	t.Run("metrics disabled by any configuration", func(t *testing.T) {
+		disabled := LoadDefaultConfiguration()
+		disabled.Spec.MetricSpec.Enabled = false
+		merged := MergeConfigurations(LoadDefaultConfiguration(), disabled, LoadDefaultConfiguration())
+		assert.False(t, merged.Spec.MetricSpec.Enabled)
+	})
+}
+
 func TestFeatureSpecForStandAlone(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -17,4 +17,8 @@ const (
 	AppPort string = "APP_PORT"
 	// AppID is the ID of the application.
 	AppID string = "APP_ID"
+	// ConfigAuthorization is the Authorization header of the requests to the configurations at HTTP(S) URLs.
+	ConfigAuthorization string = "DAPR_CONFIG_AUTHORIZATION"
+	// ConfigAuthorizationInsecure allows sending the Authorization header to the configurations at HTTP URLs when "true".
+	ConfigAuthorizationInsecure string = "DAPR_CONFIG_AUTHORIZATION_INSECURE"
 )
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AlphaAPIs enables all the alpha APIs, like the enableAlpha field of the API spec.
+const AlphaAPIs Feature = "AlphaAPIs"
+
+// FeatureGate is a preview feature, enabled or disabled per sidecar in the features of the configuration.
+type FeatureGate struct {
+	Name Feature
+	// Default is the state of the feature when the configuration doesn't set it.
+	Default     bool
+	Description string
+}
+
+// FeatureStatus is the state of a feature in a configuration.
+type FeatureStatus struct {
+	Name    Feature
+	Enabled bool
+	Default bool
+	// Registered is false for the features of the configuration without a feature gate.
+	Registered bool
+}
+
+var (
+	featureGatesLock sync.RWMutex
+	featureGates     = map[Feature]FeatureGate{}
+)
+
+func init() {
+	RegisterFeatureGate(FeatureGate{Name: Resiliency, Description: "Resiliency policies of the calls to the apps, the components and the actors"})
+	RegisterFeatureGate(FeatureGate{Name: NoDefaultContentType, Description: "No default content type of the invoked requests without one"})
+	RegisterFeatureGate(FeatureGate{Name: AppHealthCheck, Description: "Health checks of the app"})
+	RegisterFeatureGate(FeatureGate{Name: AlphaAPIs, Description: "All the alpha APIs"})
+}
+
+// RegisterFeatureGate registers a feature gate. It panics if a feature gate with the same name is registered already.
+func RegisterFeatureGate(gate FeatureGate) {
+	featureGatesLock.Lock()
+	defer featureGatesLock.Unlock()
+
+	if _, ok := featureGates[gate.Name]; ok {
+		panic(fmt.Sprintf("feature gate %s registered twice", gate.Name))
+	}
+	featureGates[gate.Name] = gate
+}
+
+// GetAPISpec returns the API spec, with all the alpha APIs enabled when the AlphaAPIs feature is enabled.
+func (c ConfigurationSpec) GetAPISpec() APISpec {
+	spec := c.APISpec
+	spec.EnableAlpha = spec.EnableAlpha || IsFeatureEnabled(c.Features, AlphaAPIs)
+	return spec
+}
+
+// GetFeatureGates returns the registered feature gates, sorted by name.
+func GetFeatureGates() []FeatureGate {
+	featureGatesLock.RLock()
+	defer featureGatesLock.RUnlock()
+
+	gates := make([]FeatureGate, 0, len(featureGates))
+	for _, gate := range featureGates {
+		gates = append(gates, gate)
+	}
+	sort.Slice(gates, func(i, j int) bool {
+		return gates[i].Name < gates[j].Name
+	})
+	return gates
+}
+
+func getFeatureGate(name Feature) (FeatureGate, bool) {
+	featureGatesLock.RLock()
+	defer featureGatesLock.RUnlock()
+
+	gate, ok := featureGates[name]
+	return gate, ok
+}
+
+// GetFeatureStatuses returns the state of the registered features, followed by the features of the configuration
+// without a feature gate, sorted by name.
+func GetFeatureStatuses(features []FeatureSpec) []FeatureStatus {
+	gates := GetFeatureGates()
+	statuses := make([]FeatureStatus, 0, len(gates))
+	for _, gate := range gates {
+		statuses = append(statuses, FeatureStatus{
+			Name:       gate.Name,
+			Enabled:    IsFeatureEnabled(features, gate.Name),
+			Default:    gate.Default,
+			Registered: true,
+		})
+	}
+
+	var unregistered []FeatureStatus
+	seen := map[Feature]struct{}{}
+	for _, feature := range features {
+		if _, ok := seen[feature.Name]; ok {
+			continue
+		}
+		seen[feature.Name] = struct{}{}
+		if _, ok := getFeatureGate(feature.Name); !ok {
+			unregistered = append(unregistered, FeatureStatus{Name: feature.Name, Enabled: feature.Enabled})
+		}
+	}
+	sort.Slice(unregistered, func(i, j int) bool {
+		return unregistered[i].Name < unregistered[j].Name
+	})
+	return append(statuses, unregistered...)
+}
+
+// GetEnabledFeatures returns the names of the enabled features, sorted by name.
+func GetEnabledFeatures(features []FeatureSpec) []string {
+	var enabled []string
+	for _, status := range GetFeatureStatuses(features) {
+		if status.Enabled {
+			enabled = append(enabled, string(status.Name))
+		}
+	}
+	sort.Strings(enabled)
+	return enabled
+}
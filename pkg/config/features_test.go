@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureGates(t *testing.T) {
+	gate := FeatureGate{Name: "Test.DefaultOn", Default: true}
+	RegisterFeatureGate(gate)
+	defer func() {
+		featureGatesLock.Lock()
+		delete(featureGates, gate.Name)
+		featureGatesLock.Unlock()
+	}()
+
+	t.Run("registered twice", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterFeatureGate(gate) })
+	})
+
+	t.Run("default state", func(t *testing.T) {
+		assert.True(t, IsFeatureEnabled(nil, gate.Name))
+		assert.False(t, IsFeatureEnabled([]FeatureSpec{{Name: gate.Name, Enabled: false}}, gate.Name))
+		assert.False(t, IsFeatureEnabled(nil, Resiliency))
+		assert.False(t, IsFeatureEnabled(nil, "Test.Missing"))
+	})
+
+	t.Run("statuses", func(t *testing.T) {
+		features := []FeatureSpec{{Name: Resiliency, Enabled: true}, {Name: "Test.Unknown", Enabled: true}}
+		statuses := GetFeatureStatuses(features)
+		assert.Contains(t, statuses, FeatureStatus{Name: Resiliency, Enabled: true, Registered: true})
+		assert.Contains(t, statuses, FeatureStatus{Name: gate.Name, Enabled: true, Default: true, Registered: true})
+		assert.Contains(t, statuses, FeatureStatus{Name: AppHealthCheck, Registered: true})
+		assert.Equal(t, FeatureStatus{Name: "Test.Unknown", Enabled: true}, statuses[len(statuses)-1])
+
+		assert.Equal(t, []string{string(Resiliency), string(gate.Name), "Test.Unknown"}, GetEnabledFeatures(features))
+	})
+}
+
+func TestGetAPISpec(t *testing.T) {
+	spec := ConfigurationSpec{}
+	assert.False(t, spec.GetAPISpec().EnableAlpha)
+
+	spec.Features = []FeatureSpec{{Name: AlphaAPIs, Enabled: true}}
+	assert.True(t, spec.GetAPISpec().EnableAlpha)
+	assert.False(t, spec.APISpec.EnableAlpha)
+
+	spec = ConfigurationSpec{APISpec: APISpec{EnableAlpha: true}}
+	assert.True(t, spec.GetAPISpec().EnableAlpha)
+}
@@ -15,5 +15,7 @@ package config
 
 // StandaloneConfig is the configuration for standalone mode.
 type StandaloneConfig struct {
-	ComponentsPath string
+	// ResourcesPath are the directories of the resources, such as the components and the subscriptions.
+	// When resources with the same name are defined in several directories, the ones in the last directory take precedence.
+	ResourcesPath []string
 }
@@ -0,0 +1,127 @@
+package credentials
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/dapr/dapr/pkg/fswatcher"
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.credentials")
+
+// CertChainReloader holds the cert chain of a server and reloads it when the credentials change on disk,
+// so that the rotated issuer and root certs are served without restarting the server.
+type CertChainReloader struct {
+	creds TLSCredentials
+	lock  sync.RWMutex
+	chain *CertChain
+	cert  *tls.Certificate
+	roots *x509.CertPool
+}
+
+// NewCertChainReloader returns a new CertChainReloader serving the cert chain loaded from the credentials.
+func NewCertChainReloader(creds TLSCredentials, chain *CertChain) (*CertChainReloader, error) {
+	r := &CertChainReloader{creds: creds}
+	if err := r.set(chain); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CertChain returns the current cert chain.
+func (r *CertChainReloader) CertChain() *CertChain {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.chain
+}
+
+// Reload loads the cert chain from disk; the current cert chain is kept if the new one is invalid.
+func (r *CertChainReloader) Reload() error {
+	chain, err := LoadFromDisk(r.creds.RootCertPath(), r.creds.CertPath(), r.creds.KeyPath())
+	if err != nil {
+		return errors.Wrap(err, "error loading cert chain from disk")
+	}
+	return r.set(chain)
+}
+
+// Watch reloads the cert chain when the credentials directory changes.
+// This is a blocking method that should be run in its own goroutine.
+func (r *CertChainReloader) Watch(ctx context.Context) error {
+	fsevent := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fswatcher.Watch(ctx, r.creds.Path(), fsevent)
+	}()
+
+	for {
+		select {
+		case <-fsevent:
+			if err := r.Reload(); err != nil {
+				log.Warnf("tls certificates changed on disk but could not be reloaded: %s", err)
+				continue
+			}
+			log.Info("tls certificates reloaded successfully")
+		case err := <-errCh:
+			return err
+		}
+	}
+}
+
+// ServerOptions returns the gRPC server options serving the current cert chain,
+// and verifying the client certificates with its current root certs.
+func (r *CertChainReloader) ServerOptions() []grpc.ServerOption {
+	//nolint:gosec
+	config := &tls.Config{
+		// Require cert verification
+		ClientAuth:         tls.RequireAndVerifyClientCert,
+		GetConfigForClient: r.config,
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(config))}
+}
+
+// TLSConfig returns the TLS config of a net/http server serving the current cert chain,
+// and verifying the client certificates with its current root certs.
+func (r *CertChainReloader) TLSConfig() *tls.Config {
+	//nolint:gosec
+	return &tls.Config{
+		ClientAuth:         tls.RequireAndVerifyClientCert,
+		GetConfigForClient: r.config,
+	}
+}
+
+// config returns the TLS config of a connection with the current cert chain.
+func (r *CertChainReloader) config(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	//nolint:gosec
+	return &tls.Config{
+		ClientCAs:    r.roots,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		Certificates: []tls.Certificate{*r.cert},
+	}, nil
+}
+
+func (r *CertChainReloader) set(chain *CertChain) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(chain.RootCA) {
+		return errors.New("failed to append PEM root cert to x509 CertPool")
+	}
+	cert, err := tls.X509KeyPair(chain.Cert, chain.Key)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.chain = chain
+	r.cert = &cert
+	r.roots = roots
+	return nil
+}
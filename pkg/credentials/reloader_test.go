@@ -0,0 +1,59 @@
+package credentials
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestCertChain(t *testing.T, dir string, rootCA, cert, key string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, RootCertFilename), []byte(rootCA), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, IssuerCertFilename), []byte(cert), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, IssuerKeyFilename), []byte(key), 0o600))
+}
+
+func TestCertChainReloader(t *testing.T) {
+	chain := &CertChain{
+		RootCA: []byte(TestCACert),
+		Cert:   []byte(TestCert),
+		Key:    []byte(TestKey),
+	}
+
+	t.Run("invalid certs", func(t *testing.T) {
+		_, err := NewCertChainReloader(NewTLSCredentials(t.TempDir()), &CertChain{})
+		assert.Error(t, err)
+	})
+
+	t.Run("server options", func(t *testing.T) {
+		r, err := NewCertChainReloader(NewTLSCredentials(t.TempDir()), chain)
+		require.NoError(t, err)
+		assert.Len(t, r.ServerOptions(), 1)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		dir := t.TempDir()
+		r, err := NewCertChainReloader(NewTLSCredentials(dir), chain)
+		require.NoError(t, err)
+
+		// The current cert chain is kept when the files are missing or invalid
+		assert.Error(t, r.Reload())
+		writeTestCertChain(t, dir, TestCACert, TestCert, "not a key")
+		assert.Error(t, r.Reload())
+		assert.Equal(t, chain, r.CertChain())
+
+		rotated := TestCACert + "\n" + TestCACert
+		writeTestCertChain(t, dir, rotated, TestCert, TestKey)
+		require.NoError(t, r.Reload())
+		assert.Equal(t, []byte(rotated), r.CertChain().RootCA)
+
+		config, err := r.config(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+		assert.Len(t, config.Certificates, 1)
+		assert.NotNil(t, config.ClientCAs)
+	})
+}
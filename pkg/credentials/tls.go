@@ -22,3 +22,9 @@ func TLSConfigFromCertAndKey(certPem, keyPem []byte, serverName string, rootCA *
 
 	return config, nil
 }
+
+// IsControlPlaneCertificate returns true if the certificate is the one of a control plane service,
+// which is valid for the server name and has no SPIFFE ID, unlike the workload certificates of the sidecars.
+func IsControlPlaneCertificate(cert *x509.Certificate, serverName string) bool {
+	return len(cert.URIs) == 0 && cert.VerifyHostname(serverName) == nil
+}
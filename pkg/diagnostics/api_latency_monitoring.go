@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"strconv"
+
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+var targetAppIDKey = tag.MustNewKey("target_app_id")
+
+// fineLatencyDistribution has narrower buckets than defaultLatencyDistribution below one second, where most of the
+// calls to the components and to the apps fall.
+var fineLatencyDistribution = view.Distribution(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 12, 14, 16, 18, 20, 25, 30, 35, 40, 45, 50, 60, 70, 80, 90, 100, 120, 140, 160, 180, 200, 250, 300, 350, 400, 450, 500, 600, 700, 800, 900, 1000, 1500, 2000, 3000, 5000, 10000, 30000)
+
+// apiLatencyMetrics holds the latency histograms of the Dapr APIs per state store, pub/sub component and target app.
+// The samples keep the span context of the traced calls as exemplars, so a latency spike links to the traces.
+type apiLatencyMetrics struct {
+	stateLatency             *stats.Float64Measure
+	publishLatency           *stats.Float64Measure
+	serviceInvocationLatency *stats.Float64Measure
+
+	appID   string
+	enabled bool
+}
+
+func newAPILatencyMetrics() *apiLatencyMetrics {
+	return &apiLatencyMetrics{
+		stateLatency: stats.Float64(
+			"api/state/latencies",
+			"The latency of the state operations of the Dapr API, per state store.",
+			stats.UnitMilliseconds),
+		publishLatency: stats.Float64(
+			"api/publish/latencies",
+			"The latency of the messages published with the Dapr API, per pub/sub component.",
+			stats.UnitMilliseconds),
+		serviceInvocationLatency: stats.Float64(
+			"api/service_invocation/latencies",
+			"The latency of the service invocations of the Dapr API, per target app.",
+			stats.UnitMilliseconds),
+	}
+}
+
+// Init registers the API latency metrics views.
+func (a *apiLatencyMetrics) Init(appID string) error {
+	a.appID = appID
+	a.enabled = true
+
+	return view.Register(
+		diagUtils.NewMeasureView(a.stateLatency, []tag.Key{appIDKey, componentKey, operationKey, successKey}, fineLatencyDistribution),
+		diagUtils.NewMeasureView(a.publishLatency, []tag.Key{appIDKey, componentKey, successKey}, fineLatencyDistribution),
+		diagUtils.NewMeasureView(a.serviceInvocationLatency, []tag.Key{appIDKey, targetAppIDKey, successKey}, fineLatencyDistribution),
+	)
+}
+
+// StateLatency records the latency of a state operation on a state store.
+func (a *apiLatencyMetrics) StateLatency(ctx context.Context, store, operation string, success bool, elapsed float64) {
+	if a.enabled {
+		a.record(ctx, a.stateLatency, elapsed, appIDKey, a.appID, componentKey, store, operationKey, operation, successKey, strconv.FormatBool(success))
+	}
+}
+
+// PublishLatency records the latency of a message published to a pub/sub component.
+func (a *apiLatencyMetrics) PublishLatency(ctx context.Context, pubsub string, success bool, elapsed float64) {
+	if a.enabled {
+		a.record(ctx, a.publishLatency, elapsed, appIDKey, a.appID, componentKey, pubsub, successKey, strconv.FormatBool(success))
+	}
+}
+
+// ServiceInvocationLatency records the latency of a service invocation of the target app.
+func (a *apiLatencyMetrics) ServiceInvocationLatency(ctx context.Context, targetAppID string, success bool, elapsed float64) {
+	if a.enabled {
+		a.record(ctx, a.serviceInvocationLatency, elapsed, appIDKey, a.appID, targetAppIDKey, targetAppID, successKey, strconv.FormatBool(success))
+	}
+}
+
+func (a *apiLatencyMetrics) record(ctx context.Context, measure *stats.Float64Measure, elapsed float64, tags ...interface{}) {
+	stats.RecordWithOptions(
+		ctx,
+		stats.WithTags(diagUtils.WithTags(measure.Name(), tags...)...),
+		stats.WithMeasurements(measure.M(elapsed)),
+		stats.WithAttachments(exemplarAttachments(ctx)))
+}
+
+// exemplarAttachments returns the span context of the call, to be recorded as exemplar of the sample.
+// It returns nil when the call isn't sampled, since its trace isn't exported.
+func exemplarAttachments(ctx context.Context) metricdata.Attachments {
+	span := diagUtils.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+	sc := span.SpanContext()
+	if !sc.IsValid() || !sc.IsSampled() {
+		return nil
+	}
+	return metricdata.Attachments{metricdata.AttachmentKeySpanContext: sc}
+}
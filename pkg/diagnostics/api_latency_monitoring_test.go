@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestAPILatencyMetrics() *apiLatencyMetrics {
+	a := newAPILatencyMetrics()
+	a.Init("test")
+
+	return a
+}
+
+// exemplars returns the span contexts recorded as exemplars in the rows of the view.
+func exemplars(t *testing.T, viewName string) []trace.SpanContext {
+	rows, err := view.RetrieveData(viewName)
+	require.NoError(t, err)
+	scs := []trace.SpanContext{}
+	for _, row := range rows {
+		data, ok := row.Data.(*view.DistributionData)
+		require.True(t, ok)
+		for _, e := range data.ExemplarsPerBucket {
+			if e != nil {
+				scs = append(scs, e.Attachments[metricdata.AttachmentKeySpanContext].(trace.SpanContext))
+			}
+		}
+	}
+	return scs
+}
+
+func TestAPILatency(t *testing.T) {
+	sampled := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	notSampled := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{7, 8, 9},
+		SpanID:  trace.SpanID{10, 11, 12},
+	})
+
+	t.Run("record state latency with exemplar", func(t *testing.T) {
+		a := newTestAPILatencyMetrics()
+
+		a.StateLatency(trace.ContextWithSpanContext(context.Background(), sampled), "mystore", Get, true, 12)
+
+		viewData, _ := view.RetrieveData("api/state/latencies")
+		v := view.Find("api/state/latencies")
+		allTagsPresent(t, v, viewData[0].Tags)
+		assert.Contains(t, exemplars(t, "api/state/latencies"), sampled)
+	})
+
+	t.Run("record publish latency without exemplar of unsampled trace", func(t *testing.T) {
+		a := newTestAPILatencyMetrics()
+
+		a.PublishLatency(trace.ContextWithSpanContext(context.Background(), notSampled), "mypubsub", true, 5)
+
+		viewData, _ := view.RetrieveData("api/publish/latencies")
+		v := view.Find("api/publish/latencies")
+		allTagsPresent(t, v, viewData[0].Tags)
+		assert.NotContains(t, exemplars(t, "api/publish/latencies"), notSampled)
+	})
+
+	t.Run("record service invocation latency", func(t *testing.T) {
+		a := newTestAPILatencyMetrics()
+
+		a.ServiceInvocationLatency(context.Background(), "targetapp", false, 300)
+
+		viewData, _ := view.RetrieveData("api/service_invocation/latencies")
+		v := view.Find("api/service_invocation/latencies")
+		allTagsPresent(t, v, viewData[0].Tags)
+		assert.Contains(t, viewData[0].Tags, tag.Tag{Key: targetAppIDKey, Value: "targetapp"})
+	})
+}
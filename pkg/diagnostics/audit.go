@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const (
+	// AuditProtocolHTTP is the protocol of the calls to the Dapr HTTP API.
+	AuditProtocolHTTP = "http"
+	// AuditProtocolGRPC is the protocol of the calls to the Dapr gRPC APIs.
+	AuditProtocolGRPC = "grpc"
+
+	auditRedactedValue = "[REDACTED]"
+)
+
+// auditCredentialsHeaders are redacted even when they aren't listed in the redacted headers.
+var auditCredentialsHeaders = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"dapr-api-token",
+}
+
+// AuditRecord is a call to a Dapr API recorded in the audit log.
+// It never contains the payloads of the calls, nor the keys of the resources they target.
+type AuditRecord struct {
+	Time  time.Time `json:"time"`
+	AppID string    `json:"app_id"`
+	// CallerAppID is the ID of the app that called the API. It is empty if the caller could not be identified.
+	CallerAppID string `json:"caller_app_id,omitempty"`
+	Protocol    string `json:"protocol"`
+	// Method is the gRPC method, or the HTTP method followed by the route template.
+	Method string `json:"method"`
+	// Target is the name of the component, the app or the actor type the call was made to.
+	Target     string            `json:"target,omitempty"`
+	Result     string            `json:"result"`
+	DurationMs float64           `json:"duration_ms"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	// PayloadHash is the hex encoded SHA-256 hash of the request payload.
+	PayloadHash string `json:"payload_sha256,omitempty"`
+}
+
+// Auditor writes the audit records of the calls to the Dapr APIs as JSON lines.
+type Auditor struct {
+	appID       string
+	headers     []string
+	redacted    map[string]struct{}
+	payloadHash bool
+
+	lock   sync.Mutex
+	out    io.Writer
+	closer io.Closer
+}
+
+// NewAuditor returns an Auditor writing to the output of the audit spec.
+func NewAuditor(appID string, spec config.AuditSpec) (*Auditor, error) {
+	if spec.Output == "" {
+		return newAuditor(appID, spec, os.Stdout, nil), nil
+	}
+
+	f, err := os.OpenFile(spec.Output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the audit log %s: %w", spec.Output, err)
+	}
+	return newAuditor(appID, spec, f, f), nil
+}
+
+func newAuditor(appID string, spec config.AuditSpec, out io.Writer, closer io.Closer) *Auditor {
+	a := &Auditor{
+		appID:       appID,
+		headers:     make([]string, 0, len(spec.Headers)),
+		redacted:    make(map[string]struct{}, len(spec.RedactedHeaders)+len(auditCredentialsHeaders)),
+		payloadHash: spec.PayloadHash,
+		out:         out,
+		closer:      closer,
+	}
+	// Headers and gRPC metadata keys are case-insensitive
+	for _, h := range spec.Headers {
+		a.headers = append(a.headers, strings.ToLower(h))
+	}
+	for _, h := range auditCredentialsHeaders {
+		a.redacted[h] = struct{}{}
+	}
+	for _, h := range spec.RedactedHeaders {
+		a.redacted[strings.ToLower(h)] = struct{}{}
+	}
+	return a
+}
+
+// Headers returns the recorded headers of a request, with the values of the redacted ones replaced.
+// get returns the value of a header of the request, or an empty string if it isn't set.
+func (a *Auditor) Headers(get func(name string) string) map[string]string {
+	var headers map[string]string
+	for _, name := range a.headers {
+		value := get(name)
+		if value == "" {
+			continue
+		}
+		if _, ok := a.redacted[name]; ok {
+			value = auditRedactedValue
+		}
+		if headers == nil {
+			headers = make(map[string]string, len(a.headers))
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
+// PayloadHash returns the hash of the request payload, or an empty string if the hashes aren't recorded.
+func (a *Auditor) PayloadHash(payload []byte) string {
+	if !a.payloadHash || len(payload) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordsPayloadHash returns true if the hashes of the request payloads are recorded.
+func (a *Auditor) RecordsPayloadHash() bool {
+	return a.payloadHash
+}
+
+// Record writes the audit record of a call.
+func (a *Auditor) Record(record AuditRecord) {
+	record.AppID = a.appID
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		log.Errorf("failed to serialize the audit record of %s: %s", record.Method, err)
+		return
+	}
+	b = append(b, '\n')
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if _, err = a.out.Write(b); err != nil {
+		log.Errorf("failed to write the audit record of %s: %s", record.Method, err)
+	}
+}
+
+// Close closes the output of the audit log.
+func (a *Auditor) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.closer.Close()
+}
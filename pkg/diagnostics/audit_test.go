@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestAuditor(t *testing.T) {
+	spec := config.AuditSpec{
+		Enabled:         true,
+		Headers:         []string{"X-Request-ID", "Authorization", "X-User-Email"},
+		RedactedHeaders: []string{"x-user-email"},
+		PayloadHash:     true,
+	}
+
+	t.Run("headers are recorded and redacted", func(t *testing.T) {
+		a := newAuditor("myapp", spec, &bytes.Buffer{}, nil)
+		request := map[string]string{
+			"x-request-id":  "42",
+			"authorization": "Bearer secret",
+			"x-user-email":  "jane@example.com",
+			"x-other":       "value",
+		}
+		headers := a.Headers(func(name string) string { return request[name] })
+		assert.Equal(t, map[string]string{
+			"x-request-id":  "42",
+			"authorization": auditRedactedValue,
+			"x-user-email":  auditRedactedValue,
+		}, headers)
+
+		assert.Nil(t, a.Headers(func(string) string { return "" }))
+	})
+
+	t.Run("payloads are hashed", func(t *testing.T) {
+		a := newAuditor("myapp", spec, &bytes.Buffer{}, nil)
+		assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", a.PayloadHash([]byte("hello")))
+		assert.Empty(t, a.PayloadHash(nil))
+
+		a = newAuditor("myapp", config.AuditSpec{Enabled: true}, &bytes.Buffer{}, nil)
+		assert.False(t, a.RecordsPayloadHash())
+		assert.Empty(t, a.PayloadHash([]byte("hello")))
+	})
+
+	t.Run("records are written as JSON lines", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		a := newAuditor("myapp", spec, buf, nil)
+		a.Record(AuditRecord{
+			CallerAppID: "myapp",
+			Protocol:    AuditProtocolHTTP,
+			Method:      "POST /v1.0/state/{storeName}",
+			Target:      "statestore",
+			Result:      "204",
+		})
+		a.Record(AuditRecord{
+			Protocol: AuditProtocolGRPC,
+			Method:   "/dapr.proto.runtime.v1.Dapr/GetState",
+			Result:   "OK",
+		})
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		var record AuditRecord
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+		assert.Equal(t, "myapp", record.AppID)
+		assert.Equal(t, "statestore", record.Target)
+		assert.Equal(t, "204", record.Result)
+		assert.False(t, record.Time.IsZero())
+		assert.NotContains(t, lines[1], "caller_app_id")
+	})
+
+	t.Run("records are appended to the output file", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "audit.log")
+		for i := 0; i < 2; i++ {
+			a, err := NewAuditor("myapp", config.AuditSpec{Enabled: true, Output: output})
+			require.NoError(t, err)
+			a.Record(AuditRecord{Method: "/dapr.proto.runtime.v1.Dapr/GetState", Result: "OK"})
+			require.NoError(t, a.Close())
+		}
+
+		b, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Len(t, strings.Split(strings.TrimSpace(string(b)), "\n"), 2)
+	})
+}
@@ -16,8 +16,18 @@ var (
 	processStatusKey = tag.MustNewKey("process_status")
 	successKey       = tag.MustNewKey("success")
 	topicKey         = tag.MustNewKey("topic")
+	pipelineKey      = tag.MustNewKey("pipeline")
+	shortCircuitKey  = tag.MustNewKey("short_circuited")
+	schemaKey        = tag.MustNewKey("schema")
 )
 
+// PayloadSchemaMetadataKey is the metadata key of the binding requests and events identifying the schema of their payload.
+// It is named after the dataschema attribute of the cloudevents, which identifies the schema of the pub/sub messages.
+const PayloadSchemaMetadataKey = "dataschema"
+
+// MalformedPayloadSchema is the schema recorded for the pub/sub messages that aren't valid cloudevents.
+const MalformedPayloadSchema = "malformed"
+
 const (
 	Delete                   = "delete"
 	Get                      = "get"
@@ -30,6 +40,9 @@ const (
 	BulkDelete               = "bulk_delete"
 )
 
+// pendingAggregation is shared by the registrations of the view, as the views are compared with their aggregation.
+var pendingAggregation = view.LastValue()
+
 // componentMetrics holds dapr runtime metrics for components.
 type componentMetrics struct {
 	pubsubIngressCount   *stats.Int64Measure
@@ -37,11 +50,19 @@ type componentMetrics struct {
 	pubsubEgressCount    *stats.Int64Measure
 	pubsubEgressLatency  *stats.Float64Measure
 
+	pubsubIngressPayloadSize *stats.Int64Measure
+	pubsubEgressPayloadSize  *stats.Int64Measure
+
+	pubsubIngressPending *stats.Int64Measure
+
 	inputBindingCount    *stats.Int64Measure
 	inputBindingLatency  *stats.Float64Measure
 	outputBindingCount   *stats.Int64Measure
 	outputBindingLatency *stats.Float64Measure
 
+	inputBindingPayloadSize  *stats.Int64Measure
+	outputBindingPayloadSize *stats.Int64Measure
+
 	stateCount   *stats.Int64Measure
 	stateLatency *stats.Float64Measure
 
@@ -51,9 +72,15 @@ type componentMetrics struct {
 	secretCount   *stats.Int64Measure
 	secretLatency *stats.Float64Measure
 
+	middlewareCount   *stats.Int64Measure
+	middlewareLatency *stats.Float64Measure
+
 	appID     string
 	enabled   bool
 	namespace string
+	// payloadSchema enables the schema label of the payload size metrics.
+	// Its values are set by the producers of the payloads, so they have an unbounded cardinality.
+	payloadSchema bool
 }
 
 // newComponentMetrics returns a componentMetrics instance with default stats.
@@ -75,6 +102,18 @@ func newComponentMetrics() *componentMetrics {
 			"component/pubsub_egress/latencies",
 			"The latency of the response from the pub/sub component.",
 			stats.UnitMilliseconds),
+		pubsubIngressPayloadSize: stats.Int64(
+			"component/pubsub_ingress/payload_size",
+			"The size of the incoming messages arriving from the pub/sub component.",
+			stats.UnitBytes),
+		pubsubEgressPayloadSize: stats.Int64(
+			"component/pubsub_egress/payload_size",
+			"The size of the outgoing messages published to the pub/sub component.",
+			stats.UnitBytes),
+		pubsubIngressPending: stats.Int64(
+			"component/pubsub_ingress/pending",
+			"The number of incoming messages of the subscription being processed by the app.",
+			stats.UnitDimensionless),
 		inputBindingCount: stats.Int64(
 			"component/input_binding/count",
 			"The number of incoming events arriving from the input binding component.",
@@ -91,6 +130,14 @@ func newComponentMetrics() *componentMetrics {
 			"component/output_binding/latencies",
 			"The latency of the response from the output binding component.",
 			stats.UnitMilliseconds),
+		inputBindingPayloadSize: stats.Int64(
+			"component/input_binding/payload_size",
+			"The size of the incoming events arriving from the input binding component.",
+			stats.UnitBytes),
+		outputBindingPayloadSize: stats.Int64(
+			"component/output_binding/payload_size",
+			"The size of the data sent to the output binding component.",
+			stats.UnitBytes),
 		stateCount: stats.Int64(
 			"component/state/count",
 			"The number of operations performed on the state component.",
@@ -115,6 +162,14 @@ func newComponentMetrics() *componentMetrics {
 			"component/secret/latencies",
 			"The latency of the response from the secret component.",
 			stats.UnitMilliseconds),
+		middlewareCount: stats.Int64(
+			"component/middleware/count",
+			"The number of requests processed by the middleware component.",
+			stats.UnitDimensionless),
+		middlewareLatency: stats.Float64(
+			"component/middleware/latencies",
+			"The time spent in the middleware component, excluding the rest of the pipeline.",
+			stats.UnitMilliseconds),
 	}
 }
 
@@ -129,16 +184,23 @@ func (c *componentMetrics) Init(appID, namespace string) error {
 		diagUtils.NewMeasureView(c.pubsubIngressCount, []tag.Key{appIDKey, componentKey, namespaceKey, processStatusKey, topicKey}, view.Count()),
 		diagUtils.NewMeasureView(c.pubsubEgressLatency, []tag.Key{appIDKey, componentKey, namespaceKey, successKey, topicKey}, defaultLatencyDistribution),
 		diagUtils.NewMeasureView(c.pubsubEgressCount, []tag.Key{appIDKey, componentKey, namespaceKey, successKey, topicKey}, view.Count()),
+		diagUtils.NewMeasureView(c.pubsubIngressPayloadSize, []tag.Key{appIDKey, componentKey, namespaceKey, topicKey, schemaKey}, defaultSizeDistribution),
+		diagUtils.NewMeasureView(c.pubsubEgressPayloadSize, []tag.Key{appIDKey, componentKey, namespaceKey, topicKey, schemaKey}, defaultSizeDistribution),
+		diagUtils.NewMeasureView(c.pubsubIngressPending, []tag.Key{appIDKey, componentKey, namespaceKey, topicKey}, pendingAggregation),
 		diagUtils.NewMeasureView(c.inputBindingLatency, []tag.Key{appIDKey, componentKey, namespaceKey, successKey}, defaultLatencyDistribution),
 		diagUtils.NewMeasureView(c.inputBindingCount, []tag.Key{appIDKey, componentKey, namespaceKey, successKey}, view.Count()),
 		diagUtils.NewMeasureView(c.outputBindingLatency, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, successKey}, defaultLatencyDistribution),
 		diagUtils.NewMeasureView(c.outputBindingCount, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, successKey}, view.Count()),
+		diagUtils.NewMeasureView(c.inputBindingPayloadSize, []tag.Key{appIDKey, componentKey, namespaceKey, schemaKey}, defaultSizeDistribution),
+		diagUtils.NewMeasureView(c.outputBindingPayloadSize, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, schemaKey}, defaultSizeDistribution),
 		diagUtils.NewMeasureView(c.stateLatency, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, successKey}, defaultLatencyDistribution),
 		diagUtils.NewMeasureView(c.stateCount, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, successKey}, view.Count()),
 		diagUtils.NewMeasureView(c.configurationLatency, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, successKey}, defaultLatencyDistribution),
 		diagUtils.NewMeasureView(c.configurationCount, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, successKey}, view.Count()),
 		diagUtils.NewMeasureView(c.secretLatency, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, successKey}, defaultLatencyDistribution),
 		diagUtils.NewMeasureView(c.secretCount, []tag.Key{appIDKey, componentKey, namespaceKey, operationKey, successKey}, view.Count()),
+		diagUtils.NewMeasureView(c.middlewareLatency, []tag.Key{appIDKey, componentKey, namespaceKey, pipelineKey, shortCircuitKey}, defaultLatencyDistribution),
+		diagUtils.NewMeasureView(c.middlewareCount, []tag.Key{appIDKey, componentKey, namespaceKey, pipelineKey, shortCircuitKey}, view.Count()),
 	)
 }
 
@@ -147,47 +209,81 @@ func (c *componentMetrics) PubsubIngressEvent(ctx context.Context, component, pr
 	if c.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, processStatusKey, processStatus, topicKey, topic),
+			diagUtils.WithTags(c.pubsubIngressCount.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, processStatusKey, processStatus, topicKey, topic),
 			c.pubsubIngressCount.M(1))
 
 		if elapsed > 0 {
 			stats.RecordWithTags(
 				ctx,
-				diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, processStatusKey, processStatus, topicKey, topic),
+				diagUtils.WithTags(c.pubsubIngressLatency.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, processStatusKey, processStatus, topicKey, topic),
 				c.pubsubIngressLatency.M(elapsed))
 		}
 	}
 }
 
+// PubsubIngressPending records the number of pending deliveries of a subscription to the app.
+func (c *componentMetrics) PubsubIngressPending(ctx context.Context, component, topic string, pending int64) {
+	if c.enabled {
+		stats.RecordWithTags(
+			ctx,
+			diagUtils.WithTags(c.pubsubIngressPending.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, topicKey, topic),
+			c.pubsubIngressPending.M(pending))
+	}
+}
+
 // PubsubEgressEvent records the metris for a pub/sub egress event.
 func (c *componentMetrics) PubsubEgressEvent(ctx context.Context, component, topic string, success bool, elapsed float64) {
 	if c.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, successKey, fmt.Sprintf("%v", success), topicKey, topic),
+			diagUtils.WithTags(c.pubsubEgressCount.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, successKey, fmt.Sprintf("%v", success), topicKey, topic),
 			c.pubsubEgressCount.M(1))
 
 		if elapsed > 0 {
 			stats.RecordWithTags(
 				ctx,
-				diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, successKey, fmt.Sprintf("%v", success), topicKey, topic),
+				diagUtils.WithTags(c.pubsubEgressLatency.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, successKey, fmt.Sprintf("%v", success), topicKey, topic),
 				c.pubsubEgressLatency.M(elapsed))
 		}
 	}
 }
 
+// PubsubIngressPayload records the size of a message arriving from a pub/sub component.
+// The schema is the identifier of the schema of the message data. It isn't recorded when empty, or when the schemas aren't enabled.
+func (c *componentMetrics) PubsubIngressPayload(ctx context.Context, component, topic, schema string, size int) {
+	if c.enabled {
+		schema = c.payloadSchemaTag(schema)
+		stats.RecordWithTags(
+			ctx,
+			diagUtils.WithTags(c.pubsubIngressPayloadSize.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, topicKey, topic, schemaKey, schema),
+			c.pubsubIngressPayloadSize.M(int64(size)))
+	}
+}
+
+// PubsubEgressPayload records the size of a message published to a pub/sub component.
+// The schema is the identifier of the schema of the message data. It isn't recorded when empty, or when the schemas aren't enabled.
+func (c *componentMetrics) PubsubEgressPayload(ctx context.Context, component, topic, schema string, size int) {
+	if c.enabled {
+		schema = c.payloadSchemaTag(schema)
+		stats.RecordWithTags(
+			ctx,
+			diagUtils.WithTags(c.pubsubEgressPayloadSize.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, topicKey, topic, schemaKey, schema),
+			c.pubsubEgressPayloadSize.M(int64(size)))
+	}
+}
+
 // InputBindingEvent records the metrics for an input binding event.
 func (c *componentMetrics) InputBindingEvent(ctx context.Context, component string, success bool, elapsed float64) {
 	if c.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, successKey, fmt.Sprintf("%v", success)),
+			diagUtils.WithTags(c.inputBindingCount.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, successKey, fmt.Sprintf("%v", success)),
 			c.inputBindingCount.M(1))
 
 		if elapsed > 0 {
 			stats.RecordWithTags(
 				ctx,
-				diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, successKey, fmt.Sprintf("%v", success)),
+				diagUtils.WithTags(c.inputBindingLatency.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, successKey, fmt.Sprintf("%v", success)),
 				c.inputBindingLatency.M(elapsed))
 		}
 	}
@@ -198,30 +294,63 @@ func (c *componentMetrics) OutputBindingEvent(ctx context.Context, component, op
 	if c.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
+			diagUtils.WithTags(c.outputBindingCount.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
 			c.outputBindingCount.M(1))
 
 		if elapsed > 0 {
 			stats.RecordWithTags(
 				ctx,
-				diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
+				diagUtils.WithTags(c.outputBindingLatency.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
 				c.outputBindingLatency.M(elapsed))
 		}
 	}
 }
 
+// InputBindingPayload records the size of an event arriving from an input binding component.
+// The schema is the identifier of the schema of the event data. It isn't recorded when empty, or when the schemas aren't enabled.
+func (c *componentMetrics) InputBindingPayload(ctx context.Context, component, schema string, size int) {
+	if c.enabled {
+		schema = c.payloadSchemaTag(schema)
+		stats.RecordWithTags(
+			ctx,
+			diagUtils.WithTags(c.inputBindingPayloadSize.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, schemaKey, schema),
+			c.inputBindingPayloadSize.M(int64(size)))
+	}
+}
+
+// OutputBindingPayload records the size of the data sent to an output binding component.
+// The schema is the identifier of the schema of the data. It isn't recorded when empty, or when the schemas aren't enabled.
+func (c *componentMetrics) OutputBindingPayload(ctx context.Context, component, operation, schema string, size int) {
+	if c.enabled {
+		schema = c.payloadSchemaTag(schema)
+		stats.RecordWithTags(
+			ctx,
+			diagUtils.WithTags(c.outputBindingPayloadSize.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, schemaKey, schema),
+			c.outputBindingPayloadSize.M(int64(size)))
+	}
+}
+
+// payloadSchemaTag returns the value of the schema label of the payload size metrics: the schema, or "" to not
+// record the label when the schemas aren't enabled.
+func (c *componentMetrics) payloadSchemaTag(schema string) string {
+	if !c.payloadSchema {
+		return ""
+	}
+	return schema
+}
+
 // StateInvoked records the metrics for a state event.
 func (c *componentMetrics) StateInvoked(ctx context.Context, component, operation string, success bool, elapsed float64) {
 	if c.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
+			diagUtils.WithTags(c.stateCount.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
 			c.stateCount.M(1))
 
 		if elapsed > 0 {
 			stats.RecordWithTags(
 				ctx,
-				diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
+				diagUtils.WithTags(c.stateLatency.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
 				c.stateLatency.M(elapsed))
 		}
 	}
@@ -232,13 +361,13 @@ func (c *componentMetrics) ConfigurationInvoked(ctx context.Context, component,
 	if c.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
+			diagUtils.WithTags(c.configurationCount.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
 			c.configurationCount.M(1))
 
 		if elapsed > 0 {
 			stats.RecordWithTags(
 				ctx,
-				diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
+				diagUtils.WithTags(c.configurationLatency.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
 				c.configurationLatency.M(elapsed))
 		}
 	}
@@ -249,18 +378,35 @@ func (c *componentMetrics) SecretInvoked(ctx context.Context, component, operati
 	if c.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
+			diagUtils.WithTags(c.secretCount.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
 			c.secretCount.M(1))
 
 		if elapsed > 0 {
 			stats.RecordWithTags(
 				ctx,
-				diagUtils.WithTags(appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
+				diagUtils.WithTags(c.secretLatency.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, operationKey, operation, successKey, fmt.Sprintf("%v", success)),
 				c.secretLatency.M(elapsed))
 		}
 	}
 }
 
+// MiddlewareInvoked records the metrics for a request processed by a middleware component of a pipeline.
+func (c *componentMetrics) MiddlewareInvoked(ctx context.Context, component, pipeline string, shortCircuited bool, elapsed float64) {
+	if c.enabled {
+		stats.RecordWithTags(
+			ctx,
+			diagUtils.WithTags(c.middlewareCount.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, pipelineKey, pipeline, shortCircuitKey, fmt.Sprintf("%v", shortCircuited)),
+			c.middlewareCount.M(1))
+
+		if elapsed > 0 {
+			stats.RecordWithTags(
+				ctx,
+				diagUtils.WithTags(c.middlewareLatency.Name(), appIDKey, c.appID, componentKey, component, namespaceKey, c.namespace, pipelineKey, pipeline, shortCircuitKey, fmt.Sprintf("%v", shortCircuited)),
+				c.middlewareLatency.M(elapsed))
+		}
+	}
+}
+
 func ElapsedSince(start time.Time) float64 {
 	return float64(time.Since(start) / time.Millisecond)
 }
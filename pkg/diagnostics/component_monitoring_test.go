@@ -16,6 +16,7 @@ const (
 
 func componentsMetrics() *componentMetrics {
 	c := newComponentMetrics()
+	c.payloadSchema = true
 	c.Init("test", "default")
 
 	return c
@@ -81,6 +82,46 @@ func TestPubSub(t *testing.T) {
 
 		assert.Equal(t, float64(1), viewData[0].Data.(*view.DistributionData).Min)
 	})
+
+	t.Run("record ingress payload size", func(t *testing.T) {
+		c := componentsMetrics()
+
+		c.PubsubIngressPayload(context.Background(), componentName, "A", "https://example.com/order.json", 2048)
+
+		viewData, _ := view.RetrieveData("component/pubsub_ingress/payload_size")
+		v := view.Find("component/pubsub_ingress/payload_size")
+
+		allTagsPresent(t, v, viewData[0].Tags)
+
+		assert.Equal(t, float64(2048), viewData[0].Data.(*view.DistributionData).Max)
+	})
+
+	t.Run("record ingress pending deliveries", func(t *testing.T) {
+		c := componentsMetrics()
+
+		c.PubsubIngressPending(context.Background(), componentName, "A", 3)
+		c.PubsubIngressPending(context.Background(), componentName, "A", 2)
+
+		viewData, _ := view.RetrieveData("component/pubsub_ingress/pending")
+		v := view.Find("component/pubsub_ingress/pending")
+
+		allTagsPresent(t, v, viewData[0].Tags)
+
+		assert.Equal(t, float64(2), viewData[0].Data.(*view.LastValueData).Value)
+	})
+
+	t.Run("record egress payload size", func(t *testing.T) {
+		c := componentsMetrics()
+
+		c.PubsubEgressPayload(context.Background(), componentName, "A", "https://example.com/order.json", 1024)
+
+		viewData, _ := view.RetrieveData("component/pubsub_egress/payload_size")
+		v := view.Find("component/pubsub_egress/payload_size")
+
+		allTagsPresent(t, v, viewData[0].Tags)
+
+		assert.Equal(t, float64(1024), viewData[0].Data.(*view.DistributionData).Max)
+	})
 }
 
 func TestBindings(t *testing.T) {
@@ -131,6 +172,48 @@ func TestBindings(t *testing.T) {
 
 		assert.Equal(t, float64(1), viewData[0].Data.(*view.DistributionData).Min)
 	})
+
+	t.Run("record input binding payload size", func(t *testing.T) {
+		c := componentsMetrics()
+
+		c.InputBindingPayload(context.Background(), componentName, "v1", 512)
+
+		viewData, _ := view.RetrieveData("component/input_binding/payload_size")
+		v := view.Find("component/input_binding/payload_size")
+
+		allTagsPresent(t, v, viewData[0].Tags)
+
+		assert.Equal(t, float64(512), viewData[0].Data.(*view.DistributionData).Max)
+	})
+
+	t.Run("schema isn't recorded unless enabled", func(t *testing.T) {
+		c := newComponentMetrics()
+		c.Init("test", "default")
+
+		c.InputBindingPayload(context.Background(), "noschema", "v1", 256)
+
+		viewData, _ := view.RetrieveData("component/input_binding/payload_size")
+		found := false
+		for _, row := range viewData {
+			for _, tag := range row.Tags {
+				if tag.Key.Name() == componentKey.Name() && tag.Value == "noschema" {
+					found = true
+					assert.Len(t, row.Tags, 3)
+				}
+			}
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("record output binding payload size without schema", func(t *testing.T) {
+		c := componentsMetrics()
+
+		c.OutputBindingPayload(context.Background(), componentName, "create", "", 512)
+
+		viewData, _ := view.RetrieveData("component/output_binding/payload_size")
+		assert.Len(t, viewData[0].Tags, 4)
+		assert.Equal(t, float64(512), viewData[0].Data.(*view.DistributionData).Max)
+	})
 }
 
 func TestState(t *testing.T) {
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// daprDebugComponentSpanAttributeKey is set on the spans of the requests to a component with detailed tracing.
+	daprDebugComponentSpanAttributeKey = "dapr.debug_component"
+
+	payloadSpanEventName          = "dapr.payload"
+	requestPayloadSpanAttribute   = "dapr.request_payload"
+	responsePayloadSpanAttribute  = "dapr.response_payload"
+	payloadTruncatedSpanAttribute = "dapr.payload_truncated"
+
+	// DefaultMaxPayloadSize is the default maximum size of the payloads captured in span events.
+	DefaultMaxPayloadSize = 1024
+	// MaxComponentTracingDuration is the maximum duration of the detailed tracing of a component.
+	MaxComponentTracingDuration = time.Hour
+)
+
+// DefaultComponentTracing holds the components with detailed tracing enabled.
+var DefaultComponentTracing = NewComponentTracing()
+
+// ComponentTracingOptions contains the options of the detailed tracing of a component.
+type ComponentTracingOptions struct {
+	// Whether the payloads of the requests and responses are captured in span events.
+	CapturePayloads bool `json:"capturePayloads"`
+	// Maximum size of the captured payloads: longer payloads are truncated.
+	MaxPayloadSize int `json:"maxPayloadSize"`
+	// Time at which the detailed tracing of the component is disabled.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ComponentTracing holds the components with detailed tracing enabled for a bounded duration.
+// All the requests to these components are sampled, regardless of the sampling rate.
+type ComponentTracing struct {
+	lock       sync.RWMutex
+	components map[string]ComponentTracingOptions
+	clock      func() time.Time
+}
+
+// NewComponentTracing returns a new ComponentTracing.
+func NewComponentTracing() *ComponentTracing {
+	return &ComponentTracing{
+		components: map[string]ComponentTracingOptions{},
+		clock:      time.Now,
+	}
+}
+
+// Enable enables the detailed tracing of the component for the duration, and returns the applied options.
+func (c *ComponentTracing) Enable(component string, duration time.Duration, capturePayloads bool, maxPayloadSize int) ComponentTracingOptions {
+	if duration > MaxComponentTracingDuration {
+		duration = MaxComponentTracingDuration
+	}
+	if maxPayloadSize <= 0 {
+		maxPayloadSize = DefaultMaxPayloadSize
+	}
+	opts := ComponentTracingOptions{
+		CapturePayloads: capturePayloads,
+		MaxPayloadSize:  maxPayloadSize,
+		ExpiresAt:       c.clock().Add(duration),
+	}
+
+	c.lock.Lock()
+	c.components[component] = opts
+	c.lock.Unlock()
+	return opts
+}
+
+// Disable disables the detailed tracing of the component.
+func (c *ComponentTracing) Disable(component string) {
+	c.lock.Lock()
+	delete(c.components, component)
+	c.lock.Unlock()
+}
+
+// Get returns the options of the detailed tracing of the component, if it is enabled.
+func (c *ComponentTracing) Get(component string) (ComponentTracingOptions, bool) {
+	if component == "" {
+		return ComponentTracingOptions{}, false
+	}
+
+	c.lock.RLock()
+	opts, ok := c.components[component]
+	c.lock.RUnlock()
+	if !ok || !c.clock().Before(opts.ExpiresAt) {
+		return ComponentTracingOptions{}, false
+	}
+	return opts, true
+}
+
+// List returns the components with detailed tracing enabled, and removes the expired ones.
+func (c *ComponentTracing) List() map[string]ComponentTracingOptions {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock()
+	res := make(map[string]ComponentTracingOptions, len(c.components))
+	for name, opts := range c.components {
+		if !now.Before(opts.ExpiresAt) {
+			delete(c.components, name)
+			continue
+		}
+		res[name] = opts
+	}
+	return res
+}
+
+// componentTracingSpanOptions returns the span start options for a request to the component,
+// so the span is sampled when the detailed tracing of the component is enabled.
+func componentTracingSpanOptions(component string) (ComponentTracingOptions, []trace.SpanStartOption, bool) {
+	opts, ok := DefaultComponentTracing.Get(component)
+	if !ok {
+		return opts, nil, false
+	}
+	return opts, []trace.SpanStartOption{trace.WithAttributes(attribute.String(daprDebugComponentSpanAttributeKey, component))}, true
+}
+
+// addPayloadSpanEvent adds the payloads of a request to a component in a span event, truncated to the maximum size.
+func addPayloadSpanEvent(span trace.Span, opts ComponentTracingOptions, request, response []byte) {
+	if !opts.CapturePayloads {
+		return
+	}
+
+	truncated := false
+	truncate := func(b []byte) string {
+		if len(b) > opts.MaxPayloadSize {
+			truncated = true
+			b = b[:opts.MaxPayloadSize]
+		}
+		return string(b)
+	}
+	span.AddEvent(payloadSpanEventName, trace.WithAttributes(
+		attribute.String(requestPayloadSpanAttribute, truncate(request)),
+		attribute.String(responsePayloadSpanAttribute, truncate(response)),
+		attribute.Bool(payloadTruncatedSpanAttribute, truncated),
+	))
+}
+
+// componentTracingSampler samples the spans of the requests to the components with detailed tracing enabled,
+// and delegates the decision to the sampler for the other spans.
+type componentTracingSampler struct {
+	sdktrace.Sampler
+}
+
+// NewComponentTracingSampler returns a sampler that samples the spans of the components with detailed tracing enabled.
+func NewComponentTracingSampler(sampler sdktrace.Sampler) sdktrace.Sampler {
+	return componentTracingSampler{Sampler: sampler}
+}
+
+func (s componentTracingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, attr := range p.Attributes {
+		if attr.Key == daprDebugComponentSpanAttributeKey {
+			return sdktrace.SamplingResult{
+				Decision:   sdktrace.RecordAndSample,
+				Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+			}
+		}
+	}
+	return s.Sampler.ShouldSample(p)
+}
+
+func (s componentTracingSampler) Description() string {
+	return "ComponentTracing{" + s.Sampler.Description() + "}"
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+)
+
+func TestComponentTracing(t *testing.T) {
+	now := time.Now()
+	c := NewComponentTracing()
+	c.clock = func() time.Time { return now }
+
+	t.Run("not enabled", func(t *testing.T) {
+		_, ok := c.Get("statestore")
+		assert.False(t, ok)
+		_, ok = c.Get("")
+		assert.False(t, ok)
+	})
+
+	t.Run("enable", func(t *testing.T) {
+		opts := c.Enable("statestore", time.Minute, true, 0)
+		assert.True(t, opts.CapturePayloads)
+		assert.Equal(t, DefaultMaxPayloadSize, opts.MaxPayloadSize)
+		assert.Equal(t, now.Add(time.Minute), opts.ExpiresAt)
+
+		got, ok := c.Get("statestore")
+		require.True(t, ok)
+		assert.Equal(t, opts, got)
+	})
+
+	t.Run("duration is capped", func(t *testing.T) {
+		opts := c.Enable("pubsub", 24*time.Hour, false, 10)
+		assert.Equal(t, now.Add(MaxComponentTracingDuration), opts.ExpiresAt)
+		assert.Equal(t, 10, opts.MaxPayloadSize)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		list := c.List()
+		assert.Len(t, list, 2)
+		assert.Contains(t, list, "statestore")
+		assert.Contains(t, list, "pubsub")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		_, ok := c.Get("statestore")
+		assert.False(t, ok)
+
+		list := c.List()
+		assert.Len(t, list, 1)
+		assert.Contains(t, list, "pubsub")
+		assert.NotContains(t, c.components, "statestore")
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		c.Disable("pubsub")
+		_, ok := c.Get("pubsub")
+		assert.False(t, ok)
+		assert.Empty(t, c.List())
+	})
+}
+
+func TestComponentTracingSampler(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(NewComponentTracingSampler(sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(sr),
+	)
+	tr := tp.Tracer("test")
+
+	t.Run("component with detailed tracing is sampled", func(t *testing.T) {
+		DefaultComponentTracing.Enable("statestore", time.Minute, true, 4)
+		defer DefaultComponentTracing.Disable("statestore")
+
+		opts, spanOpts, ok := componentTracingSpanOptions("statestore")
+		require.True(t, ok)
+		_, span := tr.Start(context.Background(), "test", spanOpts...)
+		assert.True(t, span.SpanContext().IsSampled())
+		addPayloadSpanEvent(span, opts, []byte("request"), []byte("res"))
+		span.End()
+
+		spans := sr.Ended()
+		require.Len(t, spans, 1)
+		assert.Contains(t, spans[0].Attributes(), attribute.String(daprDebugComponentSpanAttributeKey, "statestore"))
+		require.Len(t, spans[0].Events(), 1)
+		event := spans[0].Events()[0]
+		assert.Equal(t, payloadSpanEventName, event.Name)
+		assert.ElementsMatch(t, []attribute.KeyValue{
+			attribute.String(requestPayloadSpanAttribute, "requ"),
+			attribute.String(responsePayloadSpanAttribute, "res"),
+			attribute.Bool(payloadTruncatedSpanAttribute, true),
+		}, event.Attributes)
+	})
+
+	t.Run("other components delegate to the sampler", func(t *testing.T) {
+		_, spanOpts, ok := componentTracingSpanOptions("pubsub")
+		assert.False(t, ok)
+		_, span := tr.Start(context.Background(), "test", spanOpts...)
+		assert.False(t, span.SpanContext().IsSampled())
+		span.End()
+	})
+
+	t.Run("payloads are not captured", func(t *testing.T) {
+		DefaultComponentTracing.Enable("bindings", time.Minute, false, 0)
+		defer DefaultComponentTracing.Disable("bindings")
+
+		opts, spanOpts, ok := componentTracingSpanOptions("bindings")
+		require.True(t, ok)
+		_, span := tr.Start(context.Background(), "test", spanOpts...)
+		addPayloadSpanEvent(span, opts, []byte("request"), nil)
+		span.End()
+
+		spans := sr.Ended()
+		assert.True(t, spans[len(spans)-1].SpanContext().IsSampled())
+		assert.Empty(t, spans[len(spans)-1].Events())
+	})
+}
+
+func TestComponentNameFromHTTPPath(t *testing.T) {
+	tests := map[string]string{
+		"/v1.0/state/statestore/key":          "statestore",
+		"/v1.0/state/statestore":              "statestore",
+		"/v1.0/publish/pubsub/topic":          "pubsub",
+		"/v1.0/bindings/kafka":                "kafka",
+		"/v1.0/secrets/vault/secret":          "vault",
+		"/v1.0/invoke/app/method/hello":       "",
+		"/v1.0/actors/actortype/id/method/do": "",
+		"/v1.0/healthz":                       "",
+	}
+	for path, expected := range tests {
+		assert.Equal(t, expected, componentNameFromHTTPPath(path), path)
+	}
+}
+
+func TestComponentNameFromGRPCRequest(t *testing.T) {
+	assert.Equal(t, "kafka", componentNameFromGRPCRequest(&runtimev1pb.InvokeBindingRequest{Name: "kafka"}))
+	assert.Equal(t, "statestore", componentNameFromGRPCRequest(&runtimev1pb.GetStateRequest{StoreName: "statestore"}))
+	assert.Equal(t, "pubsub", componentNameFromGRPCRequest(&runtimev1pb.PublishEventRequest{PubsubName: "pubsub"}))
+	assert.Equal(t, "", componentNameFromGRPCRequest(&runtimev1pb.InvokeServiceRequest{Id: "app"}))
+}
+
+func TestSecretsRequestsAreDetected(t *testing.T) {
+	assert.True(t, isSecretsHTTPPath("/v1.0/secrets/vault/secret"))
+	assert.True(t, isSecretsHTTPPath("/v1.0/secrets/vault/bulk"))
+	assert.False(t, isSecretsHTTPPath("/v1.0/state/statestore/secrets"))
+	assert.False(t, isSecretsHTTPPath("/v1.0"))
+
+	assert.True(t, isSecretsGRPCRequest(&runtimev1pb.GetSecretRequest{StoreName: "vault"}))
+	assert.True(t, isSecretsGRPCRequest(&runtimev1pb.GetBulkSecretRequest{StoreName: "vault"}))
+	assert.False(t, isSecretsGRPCRequest(&runtimev1pb.GetStateRequest{StoreName: "statestore"}))
+}
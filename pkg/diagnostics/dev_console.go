@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DevConsoleKindAPI is the kind of the calls to the Dapr APIs.
+	DevConsoleKindAPI = "api"
+	// DevConsoleKindInvocation is the kind of the service invocations received from the other sidecars.
+	DevConsoleKindInvocation = "invocation"
+	// DevConsoleKindActor is the kind of the actor invocations.
+	DevConsoleKindActor = "actor"
+	// DevConsoleKindPubSub is the kind of the pub/sub messages delivered to the app.
+	DevConsoleKindPubSub = "pubsub"
+
+	// devConsoleBufferSize is the number of events buffered for each subscriber.
+	// The events are dropped for the subscribers that don't keep up.
+	devConsoleBufferSize = 256
+)
+
+// DevConsoleEvent is an event of the developer console.
+type DevConsoleEvent struct {
+	Time     time.Time `json:"time"`
+	AppID    string    `json:"app_id"`
+	Kind     string    `json:"kind"`
+	Protocol string    `json:"protocol"`
+	// Method is the gRPC method, the HTTP method followed by the route template, or the route of the app a message is delivered to.
+	Method string `json:"method"`
+	// Target is the name of the component, the app or the actor type of the event.
+	Target     string  `json:"target,omitempty"`
+	Result     string  `json:"result"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// DevConsole broadcasts the live feed of the API calls, pub/sub deliveries and actor invocations of the sidecar
+// to the developer console. It's meant for the developers, not for production.
+type DevConsole struct {
+	appID string
+
+	lock        sync.RWMutex
+	subscribers map[chan DevConsoleEvent]struct{}
+}
+
+// NewDevConsole returns a DevConsole with no subscribers.
+func NewDevConsole(appID string) *DevConsole {
+	return &DevConsole{
+		appID:       appID,
+		subscribers: map[chan DevConsoleEvent]struct{}{},
+	}
+}
+
+// Record sends the event to the subscribers without blocking.
+func (c *DevConsole) Record(event DevConsoleEvent) {
+	event.AppID = c.appID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for ch := range c.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the channel of the events recorded from now on, and the function to unsubscribe.
+func (c *DevConsole) Subscribe() (<-chan DevConsoleEvent, func()) {
+	ch := make(chan DevConsoleEvent, devConsoleBufferSize)
+	c.lock.Lock()
+	c.subscribers[ch] = struct{}{}
+	c.lock.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			c.lock.Lock()
+			delete(c.subscribers, ch)
+			c.lock.Unlock()
+		})
+	}
+}
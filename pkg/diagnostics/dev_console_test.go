@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevConsole(t *testing.T) {
+	t.Run("events are broadcast to the subscribers", func(t *testing.T) {
+		c := NewDevConsole("myapp")
+		ch1, unsubscribe1 := c.Subscribe()
+		ch2, unsubscribe2 := c.Subscribe()
+		defer unsubscribe2()
+
+		c.Record(DevConsoleEvent{Kind: DevConsoleKindAPI, Method: "GET /v1.0/state/{storeName}/{key}", Result: "200"})
+		for _, ch := range []<-chan DevConsoleEvent{ch1, ch2} {
+			event := <-ch
+			assert.Equal(t, "myapp", event.AppID)
+			assert.Equal(t, DevConsoleKindAPI, event.Kind)
+			assert.False(t, event.Time.IsZero())
+		}
+
+		unsubscribe1()
+		unsubscribe1()
+		c.Record(DevConsoleEvent{Kind: DevConsoleKindPubSub})
+		assert.Len(t, ch1, 0)
+		assert.Len(t, ch2, 1)
+	})
+
+	t.Run("slow subscribers don't block", func(t *testing.T) {
+		c := NewDevConsole("myapp")
+		ch, unsubscribe := c.Subscribe()
+		defer unsubscribe()
+
+		for i := 0; i < devConsoleBufferSize+10; i++ {
+			c.Record(DevConsoleEvent{Kind: DevConsoleKindActor})
+		}
+		assert.Len(t, ch, devConsoleBufferSize)
+	})
+}
@@ -134,7 +134,7 @@ func (g *grpcMetrics) ServerRequestReceived(ctx context.Context, method string,
 	if g.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyServerMethod, method),
+			diagUtils.WithTags(g.serverReceivedBytes.Name(), appIDKey, g.appID, KeyServerMethod, method),
 			g.serverReceivedBytes.M(contentSize))
 	}
 
@@ -146,15 +146,15 @@ func (g *grpcMetrics) ServerRequestSent(ctx context.Context, method, status stri
 		elapsed := float64(time.Since(start) / time.Millisecond)
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyServerMethod, method, KeyServerStatus, status),
+			diagUtils.WithTags(g.serverCompletedRpcs.Name(), appIDKey, g.appID, KeyServerMethod, method, KeyServerStatus, status),
 			g.serverCompletedRpcs.M(1))
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyServerMethod, method),
+			diagUtils.WithTags(g.serverSentBytes.Name(), appIDKey, g.appID, KeyServerMethod, method),
 			g.serverSentBytes.M(contentSize))
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyServerMethod, method, KeyServerStatus, status),
+			diagUtils.WithTags(g.serverLatency.Name(), appIDKey, g.appID, KeyServerMethod, method, KeyServerStatus, status),
 			g.serverLatency.M(elapsed))
 	}
 }
@@ -163,7 +163,7 @@ func (g *grpcMetrics) ClientRequestSent(ctx context.Context, method string, cont
 	if g.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyClientMethod, method),
+			diagUtils.WithTags(g.clientSentBytes.Name(), appIDKey, g.appID, KeyClientMethod, method),
 			g.clientSentBytes.M(contentSize))
 	}
 
@@ -175,22 +175,22 @@ func (g *grpcMetrics) ClientRequestReceived(ctx context.Context, method, status
 		elapsed := float64(time.Since(start) / time.Millisecond)
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyClientMethod, method, KeyClientStatus, status),
+			diagUtils.WithTags(g.clientCompletedRpcs.Name(), appIDKey, g.appID, KeyClientMethod, method, KeyClientStatus, status),
 			g.clientCompletedRpcs.M(1))
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyClientMethod, method, KeyClientStatus, status),
+			diagUtils.WithTags(g.clientRoundtripLatency.Name(), appIDKey, g.appID, KeyClientMethod, method, KeyClientStatus, status),
 			g.clientRoundtripLatency.M(elapsed))
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyClientMethod, method),
+			diagUtils.WithTags(g.clientReceivedBytes.Name(), appIDKey, g.appID, KeyClientMethod, method),
 			g.clientReceivedBytes.M(contentSize))
 	}
 }
 
 func (g *grpcMetrics) AppHealthProbeStarted(ctx context.Context) time.Time {
 	if g.enabled {
-		stats.RecordWithTags(ctx, diagUtils.WithTags(appIDKey, g.appID))
+		stats.RecordWithTags(ctx, diagUtils.WithTags("", appIDKey, g.appID))
 	}
 
 	return time.Now()
@@ -201,11 +201,11 @@ func (g *grpcMetrics) AppHealthProbeCompleted(ctx context.Context, status string
 		elapsed := float64(time.Since(start) / time.Millisecond)
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyClientStatus, status),
+			diagUtils.WithTags(g.healthProbeCompletedCount.Name(), appIDKey, g.appID, KeyClientStatus, status),
 			g.healthProbeCompletedCount.M(1))
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, g.appID, KeyClientStatus, status),
+			diagUtils.WithTags(g.healthProbeRoundripLatency.Name(), appIDKey, g.appID, KeyClientStatus, status),
 			g.healthProbeRoundripLatency.M(elapsed))
 	}
 }
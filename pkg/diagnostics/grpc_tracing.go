@@ -25,6 +25,8 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/dapr/dapr/pkg/config"
 	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
@@ -58,8 +60,10 @@ func GRPCTraceUnaryServerInterceptor(appID string, spec config.TracingSpec) grpc
 			spanKind = trace.WithSpanKind(trace.SpanKindClient)
 		}
 
+		tracingOpts, startOpts, debugComponent := componentTracingSpanOptions(componentNameFromGRPCRequest(req))
+
 		ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
-		ctx, span = tracer.Start(ctx, info.FullMethod, spanKind)
+		ctx, span = tracer.Start(ctx, info.FullMethod, append(startOpts, spanKind)...)
 
 		isSampled := span.SpanContext().IsSampled()
 
@@ -71,6 +75,11 @@ func GRPCTraceUnaryServerInterceptor(appID string, spec config.TracingSpec) grpc
 
 		resp, err := handler(ctx, req)
 
+		// The secrets are never captured in the spans
+		if debugComponent && !isSecretsGRPCRequest(req) {
+			addPayloadSpanEvent(span, tracingOpts, marshalPayload(req), marshalPayload(resp))
+		}
+
 		if isSampled {
 			// Populates dapr- prefixed header first
 			for key, value := range reqSpanAttr {
@@ -219,32 +228,75 @@ func SpanContextFromIncomingGRPCMetadata(ctx context.Context) (trace.SpanContext
 		// as grpc-trace-bin is not yet there in OpenTelemetry unlike OpenCensus , tracking issue https://github.com/open-telemetry/opentelemetry-specification/issues/639
 		// and grpc-dotnet client adheres to OpenTelemetry Spec which only supports http based traceparent header in gRPC path
 		// TODO : Remove this workaround fix once grpc-dotnet supports grpc-trace-bin header. Tracking issue https://github.com/dapr/dapr/issues/1827
-		traceContext = md[traceparentHeader]
-		if len(traceContext) > 0 {
-			sc, ok = SpanContextFromW3CString(traceContext[0])
-			if ok && len(md[tracestateHeader]) > 0 {
-				ts := TraceStateFromW3CString(md[tracestateHeader][0])
-				sc.WithTraceState(*ts)
+		// The other configured propagation formats are checked too, for the apps instrumented with them.
+		sc, ok = extractSpanContext(func(name string) string {
+			if v := md[name]; len(v) > 0 {
+				return v[0]
 			}
-		}
+			return ""
+		})
 	}
 	return sc, ok
 }
 
-// SpanContextToGRPCMetadata appends binary serialized SpanContext to the outgoing GRPC context.
+// SpanContextToGRPCMetadata appends binary serialized SpanContext to the outgoing GRPC context,
+// followed by the metadata of the other configured propagation formats.
 func SpanContextToGRPCMetadata(ctx context.Context, spanContext trace.SpanContext) context.Context {
 	traceContextBinary := diagUtils.BinaryFromSpanContext(spanContext)
 	if len(traceContextBinary) == 0 {
 		return ctx
 	}
 
-	return metadata.AppendToOutgoingContext(ctx, grpcTraceContextKey, string(traceContextBinary))
+	kv := []string{grpcTraceContextKey, string(traceContextBinary)}
+	injectSpanContext(spanContext, func(name, value string) {
+		kv = append(kv, name, value)
+	}, false)
+	return metadata.AppendToOutgoingContext(ctx, kv...)
 }
 
 func isInternalCalls(method string) bool {
 	return strings.HasPrefix(method, "/dapr.proto.internals.")
 }
 
+// componentNameFromGRPCRequest returns the name of the component of a Dapr API request.
+func componentNameFromGRPCRequest(req interface{}) string {
+	switch r := req.(type) {
+	case *runtimev1pb.InvokeBindingRequest:
+		return r.GetName()
+	case interface{ GetStoreName() string }:
+		return r.GetStoreName()
+	case interface{ GetPubsubName() string }:
+		return r.GetPubsubName()
+	case interface{ GetComponentName() string }:
+		return r.GetComponentName()
+	default:
+		return ""
+	}
+}
+
+// isSecretsGRPCRequest returns true for the requests of the secrets API, whose payloads must never be captured.
+func isSecretsGRPCRequest(req interface{}) bool {
+	switch req.(type) {
+	case *runtimev1pb.GetSecretRequest, *runtimev1pb.GetBulkSecretRequest:
+		return true
+	default:
+		return false
+	}
+}
+
+// marshalPayload returns the JSON representation of a gRPC message, to capture it in span events.
+func marshalPayload(msg interface{}) []byte {
+	m, ok := msg.(proto.Message)
+	if !ok || m == nil {
+		return nil
+	}
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
 // spanAttributesMapFromGRPC builds the span trace attributes map for gRPC calls based on given parameters as per open-telemetry specs.
 func spanAttributesMapFromGRPC(appID string, req interface{}, rpcMethod string) map[string]string {
 	// RPC Span Attribute reference https://github.com/open-telemetry/opentelemetry-specification/blob/master/specification/trace/semantic_conventions/rpc.md
@@ -60,6 +60,8 @@ type httpMetrics struct {
 
 	appID   string
 	enabled bool
+
+	pathTemplater *diagUtils.PathTemplater
 }
 
 func newHTTPMetrics() *httpMetrics {
@@ -121,10 +123,10 @@ func (h *httpMetrics) ServerRequestReceived(ctx context.Context, method, path st
 	if h.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, h.appID, httpPathKey, path, httpMethodKey, method),
+			diagUtils.WithTags(h.serverRequestCount.Name(), appIDKey, h.appID, httpPathKey, path, httpMethodKey, method),
 			h.serverRequestCount.M(1))
 		stats.RecordWithTags(
-			ctx, diagUtils.WithTags(appIDKey, h.appID),
+			ctx, diagUtils.WithTags(h.serverRequestBytes.Name(), appIDKey, h.appID),
 			h.serverRequestBytes.M(contentSize))
 	}
 }
@@ -133,14 +135,14 @@ func (h *httpMetrics) ServerRequestCompleted(ctx context.Context, method, path,
 	if h.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, h.appID, httpPathKey, path, httpMethodKey, method, httpStatusCodeKey, status),
+			diagUtils.WithTags(h.serverResponseCount.Name(), appIDKey, h.appID, httpPathKey, path, httpMethodKey, method, httpStatusCodeKey, status),
 			h.serverResponseCount.M(1))
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, h.appID, httpPathKey, path, httpMethodKey, method, httpStatusCodeKey, status),
+			diagUtils.WithTags(h.serverLatency.Name(), appIDKey, h.appID, httpPathKey, path, httpMethodKey, method, httpStatusCodeKey, status),
 			h.serverLatency.M(elapsed))
 		stats.RecordWithTags(
-			ctx, diagUtils.WithTags(appIDKey, h.appID),
+			ctx, diagUtils.WithTags(h.serverResponseBytes.Name(), appIDKey, h.appID),
 			h.serverResponseBytes.M(contentSize))
 	}
 }
@@ -149,7 +151,7 @@ func (h *httpMetrics) ClientRequestStarted(ctx context.Context, method, path str
 	if h.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method),
+			diagUtils.WithTags(h.clientSentBytes.Name(), appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method),
 			h.clientSentBytes.M(contentSize))
 	}
 }
@@ -158,21 +160,21 @@ func (h *httpMetrics) ClientRequestCompleted(ctx context.Context, method, path,
 	if h.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method, httpStatusCodeKey, status),
+			diagUtils.WithTags(h.clientCompletedCount.Name(), appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method, httpStatusCodeKey, status),
 			h.clientCompletedCount.M(1))
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method, httpStatusCodeKey, status),
+			diagUtils.WithTags(h.clientRoundtripLatency.Name(), appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method, httpStatusCodeKey, status),
 			h.clientRoundtripLatency.M(elapsed))
 		stats.RecordWithTags(
-			ctx, diagUtils.WithTags(appIDKey, h.appID),
+			ctx, diagUtils.WithTags(h.clientReceivedBytes.Name(), appIDKey, h.appID),
 			h.clientReceivedBytes.M(contentSize))
 	}
 }
 
 func (h *httpMetrics) AppHealthProbeStarted(ctx context.Context) {
 	if h.enabled {
-		stats.RecordWithTags(ctx, diagUtils.WithTags(appIDKey, h.appID))
+		stats.RecordWithTags(ctx, diagUtils.WithTags("", appIDKey, h.appID))
 	}
 }
 
@@ -180,11 +182,11 @@ func (h *httpMetrics) AppHealthProbeCompleted(ctx context.Context, status string
 	if h.enabled {
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, h.appID, httpStatusCodeKey, status),
+			diagUtils.WithTags(h.healthProbeCompletedCount.Name(), appIDKey, h.appID, httpStatusCodeKey, status),
 			h.healthProbeCompletedCount.M(1))
 		stats.RecordWithTags(
 			ctx,
-			diagUtils.WithTags(appIDKey, h.appID, httpStatusCodeKey, status),
+			diagUtils.WithTags(h.healthProbeRoundripLatency.Name(), appIDKey, h.appID, httpStatusCodeKey, status),
 			h.healthProbeRoundripLatency.M(elapsed))
 	}
 }
@@ -235,6 +237,7 @@ func (h *httpMetrics) FastHTTPMiddleware(next fasthttp.RequestHandler) fasthttp.
 
 // convertPathToMetricLabel removes the variant parameters in URL path for low cardinality label space
 // For example, it removes {keys} param from /v1/state/statestore/{keys}.
+// The paths of the app and of the invoked methods are collapsed by the path templater, e.g. /orders/123 to /orders/{id}.
 func (h *httpMetrics) convertPathToMetricLabel(path string) string {
 	if path == "" {
 		return path
@@ -249,7 +252,7 @@ func (h *httpMetrics) convertPathToMetricLabel(path string) string {
 	parsedPath := strings.SplitN(p, "/", 6)
 
 	if len(parsedPath) < 3 {
-		return path
+		return h.pathTemplater.Template(path)
 	}
 
 	// Replace actor id with {id} for appcallback url - 'actors/DemoActor/1/method/method1'
@@ -259,6 +262,13 @@ func (h *httpMetrics) convertPathToMetricLabel(path string) string {
 	}
 
 	switch parsedPath[1] {
+	case "invoke":
+		if len(parsedPath) < 5 {
+			return path
+		}
+		// Template the method of the app in /v1/invoke/app/method/orders/123
+		return "/" + strings.Join(parsedPath[0:4], "/") + "/" + strings.TrimPrefix(h.pathTemplater.Template("/"+strings.Join(parsedPath[4:], "/")), "/")
+
 	case "state", "secrets":
 		// state api: Concat 3 items(v1, state, statestore) in /v1/state/statestore/key
 		// secrets api: Concat 3 items(v1, secrets, keyvault) in /v1/secrets/keyvault/name
@@ -274,5 +284,5 @@ func (h *httpMetrics) convertPathToMetricLabel(path string) string {
 		return "/" + strings.Join(parsedPath[0:5], "/")
 	}
 
-	return path
+	return h.pathTemplater.Template(path)
 }
@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 	"go.opencensus.io/stats/view"
+
+	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
 )
 
 func TestFastHTTPMiddleware(t *testing.T) {
@@ -109,10 +111,17 @@ func TestConvertPathToMethodName(t *testing.T) {
 		{"actors/DemoActor/1/method/method1", "actors/DemoActor/{id}/method/method1"},
 		{"actors/DemoActor/1/method/timer/timer1", "actors/DemoActor/{id}/method/timer/timer1"},
 		{"actors/DemoActor/1/method/remind/reminder1", "actors/DemoActor/{id}/method/remind/reminder1"},
+		{"/v1/invoke/myapp/method/orders/123", "/v1/invoke/myapp/method/orders/{id}"},
+		{"/v1/invoke/myapp/method/orders/123/items/shoes", "/v1/invoke/myapp/method/orders/{order}/items/{item}"},
+		{"/v1/invoke/myapp/method/health", "/v1/invoke/myapp/method/health"},
+		{"/v1/invoke/myapp", "/v1/invoke/myapp"},
+		{"orders/123", "orders/{id}"},
+		{"/orders/123/items/shoes", "/orders/{order}/items/{item}"},
 		{"", ""},
 	}
 
 	testHTTP := newHTTPMetrics()
+	testHTTP.pathTemplater = diagUtils.NewPathTemplater([]string{"/orders/{order}/items/{item}"})
 	for _, tt := range convertTests {
 		t.Run(tt.in, func(t *testing.T) {
 			lowCardinalityName := testHTTP.convertPathToMetricLabel(tt.in)
@@ -48,9 +48,15 @@ func HTTPTraceMiddleware(next fasthttp.RequestHandler, appID string, spec config
 			return
 		}
 
-		ctx, span := startTracingClientSpanFromHTTPContext(ctx, path, spec)
+		tracingOpts, startOpts, debugComponent := componentTracingSpanOptions(componentNameFromHTTPPath(path))
+		ctx, span := startTracingClientSpanFromHTTPContext(ctx, path, spec, startOpts...)
 		next(ctx)
 
+		// Streamed response bodies can't be read without consuming them, and the secrets are never captured in the spans
+		if debugComponent && !ctx.Response.IsBodyStream() && !isSecretsHTTPPath(path) {
+			addPayloadSpanEvent(span, tracingOpts, ctx.Request.Body(), ctx.Response.Body())
+		}
+
 		// Add span attributes only if it is sampled, which reduced the perf impact.
 		if span.SpanContext().IsSampled() {
 			AddAttributesToSpan(span, userDefinedHTTPHeaders(ctx))
@@ -89,27 +95,21 @@ func userDefinedHTTPHeaders(reqCtx *fasthttp.RequestCtx) map[string]string {
 	return m
 }
 
-func startTracingClientSpanFromHTTPContext(ctx *fasthttp.RequestCtx, spanName string, spec config.TracingSpec) (*fasthttp.RequestCtx, trace.Span) {
+func startTracingClientSpanFromHTTPContext(ctx *fasthttp.RequestCtx, spanName string, spec config.TracingSpec, opts ...trace.SpanStartOption) (*fasthttp.RequestCtx, trace.Span) {
 	sc, _ := SpanContextFromRequest(&ctx.Request)
 	netCtx := trace.ContextWithRemoteSpanContext(ctx, sc)
 	kindOption := trace.WithSpanKind(trace.SpanKindClient)
-	_, span := tracer.Start(netCtx, spanName, kindOption)
+	_, span := tracer.Start(netCtx, spanName, append(opts, kindOption)...)
 	diagUtils.SpanToFastHTTPContext(ctx, span)
 	return ctx, span
 }
 
-// SpanContextFromRequest extracts a span context from incoming requests.
+// SpanContextFromRequest extracts a span context from incoming requests, with the configured propagation formats.
 func SpanContextFromRequest(req *fasthttp.Request) (sc trace.SpanContext, ok bool) {
-	h, ok := getRequestHeader(req, traceparentHeader)
-	if !ok {
-		return trace.SpanContext{}, false
-	}
-	sc, ok = SpanContextFromW3CString(h)
-	if ok {
-		ts := tracestateFromRequest(req)
-		sc = sc.WithTraceState(*ts)
-	}
-	return sc, ok
+	return extractSpanContext(func(name string) string {
+		h, _ := getRequestHeader(req, name)
+		return h
+	})
 }
 
 func isHealthzRequest(name string) bool {
@@ -149,20 +149,14 @@ func getRequestHeader(req *fasthttp.Request, name string) (string, bool) {
 	return s, true
 }
 
-func tracestateFromRequest(req *fasthttp.Request) *trace.TraceState {
-	h, _ := getRequestHeader(req, tracestateHeader)
-	return TraceStateFromW3CString(h)
-}
-
-// SpanContextToHTTPHeaders adds the spancontext in traceparent and tracestate headers.
+// SpanContextToHTTPHeaders adds the spancontext in traceparent and tracestate headers,
+// and in the headers of the other configured propagation formats.
 func SpanContextToHTTPHeaders(sc trace.SpanContext, setHeader func(string, string)) {
 	// if sc is empty context, no ops.
 	if sc.Equal(trace.SpanContext{}) {
 		return
 	}
-	h := SpanContextToW3CString(sc)
-	setHeader(traceparentHeader, h)
-	tracestateToHeader(sc, setHeader)
+	injectSpanContext(sc, setHeader, true)
 }
 
 func tracestateToHeader(sc trace.SpanContext, setHeader func(string, string)) {
@@ -195,6 +189,28 @@ func getAPIComponent(apiPath string) (string, string) {
 	return tokens[1], tokens[2]
 }
 
+// componentNameFromHTTPPath returns the name of the component of a Dapr API request, before the request is routed.
+// example : apiPath /v1.0/state/statestore/key
+func componentNameFromHTTPPath(apiPath string) string {
+	tokens := strings.SplitN(apiPath, "/", 5)
+	if len(tokens) < 4 {
+		return ""
+	}
+	switch tokens[2] {
+	case "invoke", "actors":
+		// These APIs don't target components
+		return ""
+	default:
+		return tokens[3]
+	}
+}
+
+// isSecretsHTTPPath returns true for the requests of the secrets API, whose payloads must never be captured.
+func isSecretsHTTPPath(apiPath string) bool {
+	tokens := strings.SplitN(apiPath, "/", 4)
+	return len(tokens) >= 3 && tokens[2] == "secrets"
+}
+
 func spanAttributesMapFromHTTPContext(ctx *fasthttp.RequestCtx) map[string]string {
 	// Span Attribute reference https://github.com/open-telemetry/opentelemetry-specification/tree/master/specification/trace/semantic_conventions
 	path := string(ctx.Request.URI().Path())
@@ -18,6 +18,9 @@ import (
 
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+
+	"github.com/dapr/dapr/pkg/config"
+	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
 )
 
 // appIDKey is a tag key for App ID.
@@ -37,10 +40,21 @@ var (
 	DefaultComponentMonitoring = newComponentMetrics()
 	// DefaultResiliencyMonitoring holds resiliency specific metrics.
 	DefaultResiliencyMonitoring = newResiliencyMetrics()
+	// DefaultWorkflowMonitoring holds workflow engine specific metrics.
+	DefaultWorkflowMonitoring = newWorkflowMetrics()
+	// DefaultAPILatencyMonitoring holds the latency histograms of the Dapr APIs.
+	DefaultAPILatencyMonitoring = newAPILatencyMetrics()
 )
 
 // InitMetrics initializes metrics.
-func InitMetrics(appID, namespace string) error {
+// The path templates are the route templates used to collapse the paths in the HTTP metrics,
+// and the rules transform the labels of the metrics to limit their cardinality.
+// The schemas of the payloads are recorded in the component metrics only if payloadSchema is true.
+func InitMetrics(appID, namespace string, pathTemplates []string, rules []config.MetricsRule, payloadSchema bool) error {
+	if err := diagUtils.CreateRulesMap(rules); err != nil {
+		return err
+	}
+
 	if err := DefaultMonitoring.Init(appID); err != nil {
 		return err
 	}
@@ -49,10 +63,12 @@ func InitMetrics(appID, namespace string) error {
 		return err
 	}
 
+	DefaultHTTPMonitoring.pathTemplater = diagUtils.NewPathTemplater(pathTemplates)
 	if err := DefaultHTTPMonitoring.Init(appID); err != nil {
 		return err
 	}
 
+	DefaultComponentMonitoring.payloadSchema = payloadSchema
 	if err := DefaultComponentMonitoring.Init(appID, namespace); err != nil {
 		return err
 	}
@@ -61,6 +77,14 @@ func InitMetrics(appID, namespace string) error {
 		return err
 	}
 
+	if err := DefaultWorkflowMonitoring.Init(appID); err != nil {
+		return err
+	}
+
+	if err := DefaultAPILatencyMonitoring.Init(appID); err != nil {
+		return err
+	}
+
 	// Set reporting period of views
 	view.SetReportingPeriod(DefaultReportingPeriod)
 
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+const (
+	middlewareNameSpanAttributeKey           = "dapr.middleware.name"
+	middlewareTypeSpanAttributeKey           = "dapr.middleware.type"
+	middlewarePipelineSpanAttributeKey       = "dapr.middleware.pipeline"
+	middlewareShortCircuitedSpanAttributeKey = "dapr.middleware.short_circuited"
+	middlewareStatusCodeSpanAttributeKey     = "dapr.middleware.status_code"
+)
+
+// middlewareCall tracks the execution of a middleware for a request.
+type middlewareCall struct {
+	calledNext bool
+	nextTime   time.Duration
+}
+
+// InstrumentHTTPMiddleware wraps a middleware of an HTTP pipeline to create a span and record metrics for each request it processes.
+// The metrics record the time spent in the middleware itself, without the rest of the pipeline, and whether the middleware
+// short-circuited the pipeline by responding without calling the next handler.
+func InstrumentHTTPMiddleware(name, middlewareType, pipeline string, middleware func(fasthttp.RequestHandler) fasthttp.RequestHandler) func(fasthttp.RequestHandler) fasthttp.RequestHandler {
+	callKey := fmt.Sprintf("dapr-middleware-call-%s-%s", pipeline, name)
+	spanName := "middleware/" + name
+
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		handler := middleware(func(ctx *fasthttp.RequestCtx) {
+			call, _ := ctx.UserValue(callKey).(*middlewareCall)
+			if call == nil {
+				next(ctx)
+				return
+			}
+			call.calledNext = true
+			start := time.Now()
+			next(ctx)
+			call.nextTime += time.Since(start)
+		})
+
+		return func(ctx *fasthttp.RequestCtx) {
+			parent := diagUtils.SpanFromContext(ctx)
+			_, span := tracer.Start(trace.ContextWithSpan(ctx, parent), spanName, trace.WithSpanKind(trace.SpanKindInternal))
+
+			call := &middlewareCall{}
+			ctx.SetUserValue(callKey, call)
+			start := time.Now()
+			handler(ctx)
+			elapsed := time.Since(start) - call.nextTime
+			ctx.SetUserValue(callKey, nil)
+
+			shortCircuited := !call.calledNext
+			if span.SpanContext().IsSampled() {
+				span.SetAttributes(
+					attribute.String(middlewareNameSpanAttributeKey, name),
+					attribute.String(middlewareTypeSpanAttributeKey, middlewareType),
+					attribute.String(middlewarePipelineSpanAttributeKey, pipeline),
+					attribute.Bool(middlewareShortCircuitedSpanAttributeKey, shortCircuited),
+				)
+				if shortCircuited {
+					span.SetAttributes(attribute.Int(middlewareStatusCodeSpanAttributeKey, ctx.Response.StatusCode()))
+					UpdateSpanStatusFromHTTPStatus(span, ctx.Response.StatusCode())
+				}
+			}
+			span.End()
+
+			DefaultComponentMonitoring.MiddlewareInvoked(ctx, name, pipeline, shortCircuited, float64(elapsed)/float64(time.Millisecond))
+		}
+	}
+}
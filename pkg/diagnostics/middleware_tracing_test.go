@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumentHTTPMiddleware(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	// The global tracer is bound to the first registered provider
+	defaultTracer := tracer
+	tracer = tp.Tracer(tracerName)
+	defer func() { tracer = defaultTracer }()
+
+	require.NoError(t, DefaultComponentMonitoring.Init("test", "default"))
+
+	passThrough := InstrumentHTTPMiddleware("uppercase", "middleware.http.uppercase", "http", func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			next(ctx)
+		}
+	})
+	deny := InstrumentHTTPMiddleware("opa", "middleware.http.opa", "http", func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			ctx.Response.SetStatusCode(fasthttp.StatusForbidden)
+		}
+	})
+
+	appCalled := false
+	app := func(ctx *fasthttp.RequestCtx) {
+		appCalled = true
+	}
+
+	t.Run("middleware calling the next handler", func(t *testing.T) {
+		handler := passThrough(app)
+		handler(&fasthttp.RequestCtx{})
+		assert.True(t, appCalled)
+
+		spans := sr.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "middleware/uppercase", spans[0].Name())
+		assert.Contains(t, spans[0].Attributes(), attribute.String(middlewareTypeSpanAttributeKey, "middleware.http.uppercase"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Bool(middlewareShortCircuitedSpanAttributeKey, false))
+
+		rows, err := view.RetrieveData("component/middleware/count")
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		allTagsPresent(t, view.Find("component/middleware/count"), rows[0].Tags)
+	})
+
+	t.Run("middleware short-circuiting the pipeline", func(t *testing.T) {
+		appCalled = false
+		handler := passThrough(deny(app))
+		ctx := &fasthttp.RequestCtx{}
+		handler(ctx)
+		assert.False(t, appCalled)
+		assert.Equal(t, fasthttp.StatusForbidden, ctx.Response.StatusCode())
+
+		spans := sr.Ended()
+		require.Len(t, spans, 3)
+		// The spans end in reverse order of the pipeline
+		assert.Equal(t, "middleware/opa", spans[1].Name())
+		assert.Contains(t, spans[1].Attributes(), attribute.Bool(middlewareShortCircuitedSpanAttributeKey, true))
+		assert.Contains(t, spans[1].Attributes(), attribute.Int(middlewareStatusCodeSpanAttributeKey, fasthttp.StatusForbidden))
+		assert.Equal(t, "middleware/uppercase", spans[2].Name())
+		assert.Contains(t, spans[2].Attributes(), attribute.Bool(middlewareShortCircuitedSpanAttributeKey, false))
+	})
+}
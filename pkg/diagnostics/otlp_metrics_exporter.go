@@ -0,0 +1,368 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricexport"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.runtime.diagnostics")
+
+const (
+	// OTLPProtocolGRPC is the protocol of the OTLP exporter sending the metrics with gRPC.
+	OTLPProtocolGRPC = "grpc"
+	// OTLPProtocolHTTP is the protocol of the OTLP exporter sending the metrics as protobuf over HTTP.
+	OTLPProtocolHTTP = "http"
+
+	otlpMetricsURLPath   = "/v1/metrics"
+	otlpExportTimeout    = 10 * time.Second
+	otlpScopeName        = "dapr"
+	serviceNameAttribute = "service.name"
+)
+
+// OTLPMetricsExporterOptions defines the configurations of the OTLP metrics exporter.
+type OTLPMetricsExporterOptions struct {
+	// AppID is reported as the service name of the metrics.
+	AppID string
+	// Protocol is either OTLPProtocolGRPC or OTLPProtocolHTTP.
+	Protocol string
+	// Endpoint is the host and port of the collector.
+	Endpoint string
+	// Insecure disables TLS.
+	Insecure bool
+	// TLSConfig is the TLS configuration of the connection, nil to use the default one.
+	TLSConfig *tls.Config
+	// Headers are sent with every export request.
+	Headers map[string]string
+	// Interval is the period between two exports. Defaults to DefaultReportingPeriod.
+	Interval time.Duration
+}
+
+// OTLPMetricsExporter periodically exports the metrics of the sidecar to an OpenTelemetry collector with the OTLP protocol.
+type OTLPMetricsExporter struct {
+	client   otlpMetricsClient
+	resource *resourcepb.Resource
+	reader   *metricexport.IntervalReader
+}
+
+// otlpMetricsClient sends the metrics to the collector.
+type otlpMetricsClient interface {
+	export(ctx context.Context, req *colmetricpb.ExportMetricsServiceRequest) error
+	close() error
+}
+
+// NewOTLPMetricsExporter returns an exporter sending the metrics to the collector configured in the options.
+// The exporter must be started with Start.
+func NewOTLPMetricsExporter(opts OTLPMetricsExporterOptions) (*OTLPMetricsExporter, error) {
+	if opts.Endpoint == "" {
+		return nil, fmt.Errorf("the endpoint of the Otel collector is required")
+	}
+
+	var (
+		client otlpMetricsClient
+		err    error
+	)
+	switch opts.Protocol {
+	case OTLPProtocolGRPC:
+		client, err = newOTLPMetricsGRPCClient(opts)
+	case OTLPProtocolHTTP:
+		client = newOTLPMetricsHTTPClient(opts)
+	default:
+		return nil, fmt.Errorf("invalid protocol %v provided for Otel endpoint", opts.Protocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e := &OTLPMetricsExporter{
+		client: client,
+		resource: &resourcepb.Resource{
+			Attributes: []*commonpb.KeyValue{stringKeyValue(serviceNameAttribute, opts.AppID)},
+		},
+	}
+	e.reader, err = metricexport.NewIntervalReader(metricexport.NewReader(), e)
+	if err != nil {
+		client.close()
+		return nil, err
+	}
+	e.reader.ReportingInterval = opts.Interval
+	if e.reader.ReportingInterval == 0 {
+		e.reader.ReportingInterval = DefaultReportingPeriod
+	}
+	return e, nil
+}
+
+// Start starts exporting the metrics periodically.
+func (e *OTLPMetricsExporter) Start() error {
+	return e.reader.Start()
+}
+
+// Close exports the pending metrics and stops the exporter.
+func (e *OTLPMetricsExporter) Close() error {
+	e.reader.Stop()
+	return e.client.close()
+}
+
+// ExportMetrics exports the metrics to the collector. It implements metricexport.Exporter.
+func (e *OTLPMetricsExporter) ExportMetrics(ctx context.Context, data []*metricdata.Metric) error {
+	metrics := make([]*metricpb.Metric, 0, len(data))
+	for _, m := range data {
+		if converted := convertMetric(m); converted != nil {
+			metrics = append(metrics, converted)
+		}
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, otlpExportTimeout)
+	defer cancel()
+	err := e.client.export(ctx, &colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{{
+			Resource: e.resource,
+			ScopeMetrics: []*metricpb.ScopeMetrics{{
+				Scope:   &commonpb.InstrumentationScope{Name: otlpScopeName},
+				Metrics: metrics,
+			}},
+		}},
+	})
+	if err != nil {
+		log.Warnf("failed to export the metrics to the Otel collector: %v", err)
+	}
+	return err
+}
+
+// convertMetric converts an OpenCensus metric to the OTLP format. It returns nil for the unsupported metric types.
+func convertMetric(m *metricdata.Metric) *metricpb.Metric {
+	res := &metricpb.Metric{
+		Name:        m.Descriptor.Name,
+		Description: m.Descriptor.Description,
+		Unit:        string(m.Descriptor.Unit),
+	}
+
+	switch m.Descriptor.Type {
+	case metricdata.TypeGaugeInt64, metricdata.TypeGaugeFloat64:
+		res.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{
+			DataPoints: convertNumberPoints(m),
+		}}
+	case metricdata.TypeCumulativeInt64, metricdata.TypeCumulativeFloat64:
+		res.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+			DataPoints:             convertNumberPoints(m),
+			AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			IsMonotonic:            true,
+		}}
+	case metricdata.TypeCumulativeDistribution, metricdata.TypeGaugeDistribution:
+		res.Data = &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+			DataPoints:             convertHistogramPoints(m),
+			AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+		}}
+	default:
+		return nil
+	}
+	return res
+}
+
+func convertNumberPoints(m *metricdata.Metric) []*metricpb.NumberDataPoint {
+	res := []*metricpb.NumberDataPoint{}
+	for _, ts := range m.TimeSeries {
+		attrs := convertLabels(m.Descriptor.LabelKeys, ts.LabelValues)
+		for _, p := range ts.Points {
+			dp := &metricpb.NumberDataPoint{
+				Attributes:        attrs,
+				StartTimeUnixNano: unixNano(ts.StartTime),
+				TimeUnixNano:      unixNano(p.Time),
+			}
+			switch v := p.Value.(type) {
+			case int64:
+				dp.Value = &metricpb.NumberDataPoint_AsInt{AsInt: v}
+			case float64:
+				dp.Value = &metricpb.NumberDataPoint_AsDouble{AsDouble: v}
+			default:
+				continue
+			}
+			res = append(res, dp)
+		}
+	}
+	return res
+}
+
+func convertHistogramPoints(m *metricdata.Metric) []*metricpb.HistogramDataPoint {
+	res := []*metricpb.HistogramDataPoint{}
+	for _, ts := range m.TimeSeries {
+		attrs := convertLabels(m.Descriptor.LabelKeys, ts.LabelValues)
+		for _, p := range ts.Points {
+			dist, ok := p.Value.(*metricdata.Distribution)
+			if !ok {
+				continue
+			}
+			sum := dist.Sum
+			dp := &metricpb.HistogramDataPoint{
+				Attributes:        attrs,
+				StartTimeUnixNano: unixNano(ts.StartTime),
+				TimeUnixNano:      unixNano(p.Time),
+				Count:             uint64(dist.Count),
+				Sum:               &sum,
+				BucketCounts:      make([]uint64, len(dist.Buckets)),
+			}
+			for i, b := range dist.Buckets {
+				dp.BucketCounts[i] = uint64(b.Count)
+			}
+			if dist.BucketOptions != nil {
+				dp.ExplicitBounds = dist.BucketOptions.Bounds
+			}
+			res = append(res, dp)
+		}
+	}
+	return res
+}
+
+func convertLabels(keys []metricdata.LabelKey, values []metricdata.LabelValue) []*commonpb.KeyValue {
+	res := make([]*commonpb.KeyValue, 0, len(keys))
+	for i, k := range keys {
+		if i >= len(values) || !values[i].Present {
+			continue
+		}
+		res = append(res, stringKeyValue(k.Key, values[i].Value))
+	}
+	return res
+}
+
+func stringKeyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func unixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+// otlpMetricsGRPCClient sends the metrics with the gRPC MetricsService.
+type otlpMetricsGRPCClient struct {
+	conn    *grpc.ClientConn
+	client  colmetricpb.MetricsServiceClient
+	headers metadata.MD
+}
+
+func newOTLPMetricsGRPCClient(opts OTLPMetricsExporterOptions) (*otlpMetricsGRPCClient, error) {
+	creds := insecure.NewCredentials()
+	if !opts.Insecure {
+		tlsConfig := opts.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.Dial(opts.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Otel collector: %w", err)
+	}
+	return &otlpMetricsGRPCClient{
+		conn:    conn,
+		client:  colmetricpb.NewMetricsServiceClient(conn),
+		headers: metadata.New(opts.Headers),
+	}, nil
+}
+
+func (c *otlpMetricsGRPCClient) export(ctx context.Context, req *colmetricpb.ExportMetricsServiceRequest) error {
+	ctx = metadata.NewOutgoingContext(ctx, c.headers)
+	_, err := c.client.Export(ctx, req)
+	return err
+}
+
+func (c *otlpMetricsGRPCClient) close() error {
+	return c.conn.Close()
+}
+
+// otlpMetricsHTTPClient sends the metrics as protobuf over HTTP.
+type otlpMetricsHTTPClient struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newOTLPMetricsHTTPClient(opts OTLPMetricsExporterOptions) *otlpMetricsHTTPClient {
+	scheme := "https"
+	if opts.Insecure {
+		scheme = "http"
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !opts.Insecure && opts.TLSConfig != nil {
+		transport.TLSClientConfig = opts.TLSConfig
+	}
+	return &otlpMetricsHTTPClient{
+		url:     scheme + "://" + opts.Endpoint + otlpMetricsURLPath,
+		headers: opts.Headers,
+		client:  &http.Client{Transport: transport},
+	}
+}
+
+func (c *otlpMetricsHTTPClient) export(ctx context.Context, req *colmetricpb.ExportMetricsServiceRequest) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range c.headers {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection is reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("the Otel collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *otlpMetricsHTTPClient) close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
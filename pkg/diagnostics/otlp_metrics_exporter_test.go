@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/metric/metricdata"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func testMetrics(now time.Time) []*metricdata.Metric {
+	return []*metricdata.Metric{{
+		Descriptor: metricdata.Descriptor{
+			Name:      "runtime/service_invocation/req_sent_total",
+			Type:      metricdata.TypeCumulativeInt64,
+			LabelKeys: []metricdata.LabelKey{{Key: "app_id"}, {Key: "destination_app_id"}},
+		},
+		TimeSeries: []*metricdata.TimeSeries{{
+			LabelValues: []metricdata.LabelValue{metricdata.NewLabelValue("fakeID"), {}},
+			Points:      []metricdata.Point{metricdata.NewInt64Point(now, 3)},
+			StartTime:   now.Add(-time.Minute),
+		}},
+	}, {
+		Descriptor: metricdata.Descriptor{
+			Name: "http/server/latency",
+			Unit: metricdata.UnitMilliseconds,
+			Type: metricdata.TypeCumulativeDistribution,
+		},
+		TimeSeries: []*metricdata.TimeSeries{{
+			Points: []metricdata.Point{metricdata.NewDistributionPoint(now, &metricdata.Distribution{
+				Count:         3,
+				Sum:           12,
+				BucketOptions: &metricdata.BucketOptions{Bounds: []float64{1, 5}},
+				Buckets:       []metricdata.Bucket{{Count: 0}, {Count: 2}, {Count: 1}},
+			})},
+		}},
+	}, {
+		Descriptor: metricdata.Descriptor{
+			Name: "unsupported",
+			Type: metricdata.TypeSummary,
+		},
+	}}
+}
+
+func assertExportedMetrics(t *testing.T, now time.Time, req *colmetricpb.ExportMetricsServiceRequest) {
+	t.Helper()
+
+	require.Len(t, req.ResourceMetrics, 1)
+	assert.Equal(t, "service.name", req.ResourceMetrics[0].Resource.Attributes[0].Key)
+	assert.Equal(t, "fakeID", req.ResourceMetrics[0].Resource.Attributes[0].Value.GetStringValue())
+	require.Len(t, req.ResourceMetrics[0].ScopeMetrics, 1)
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+
+	sum := metrics[0].GetSum()
+	require.NotNil(t, sum)
+	assert.True(t, sum.IsMonotonic)
+	assert.Equal(t, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.AggregationTemporality)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(3), sum.DataPoints[0].GetAsInt())
+	assert.Equal(t, uint64(now.UnixNano()), sum.DataPoints[0].TimeUnixNano)
+	// Labels without a value are omitted
+	require.Len(t, sum.DataPoints[0].Attributes, 1)
+	assert.Equal(t, "app_id", sum.DataPoints[0].Attributes[0].Key)
+
+	assert.Equal(t, "ms", metrics[1].Unit)
+	histogram := metrics[1].GetHistogram()
+	require.NotNil(t, histogram)
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(3), histogram.DataPoints[0].Count)
+	assert.Equal(t, 12.0, histogram.DataPoints[0].GetSum())
+	assert.Equal(t, []uint64{0, 2, 1}, histogram.DataPoints[0].BucketCounts)
+	assert.Equal(t, []float64{1, 5}, histogram.DataPoints[0].ExplicitBounds)
+}
+
+type fakeMetricsService struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+
+	requests chan *colmetricpb.ExportMetricsServiceRequest
+	headers  chan metadata.MD
+}
+
+func (s *fakeMetricsService) Export(ctx context.Context, req *colmetricpb.ExportMetricsServiceRequest) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.headers <- md
+	s.requests <- req
+	return &colmetricpb.ExportMetricsServiceResponse{}, nil
+}
+
+func TestOTLPMetricsExporter(t *testing.T) {
+	now := time.Now()
+
+	t.Run("http", func(t *testing.T) {
+		var (
+			received *colmetricpb.ExportMetricsServiceRequest
+			header   http.Header
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/metrics", r.URL.Path)
+			header = r.Header
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			received = &colmetricpb.ExportMetricsServiceRequest{}
+			require.NoError(t, proto.Unmarshal(body, received))
+		}))
+		defer server.Close()
+
+		exporter, err := NewOTLPMetricsExporter(OTLPMetricsExporterOptions{
+			AppID:    "fakeID",
+			Protocol: OTLPProtocolHTTP,
+			Endpoint: strings.TrimPrefix(server.URL, "http://"),
+			Insecure: true,
+			Headers:  map[string]string{"api-key": "secret"},
+		})
+		require.NoError(t, err)
+		defer exporter.Close()
+
+		require.NoError(t, exporter.ExportMetrics(context.Background(), testMetrics(now)))
+		require.NotNil(t, received)
+		assertExportedMetrics(t, now, received)
+		assert.Equal(t, "secret", header.Get("api-key"))
+		assert.Equal(t, "application/x-protobuf", header.Get("Content-Type"))
+	})
+
+	t.Run("http error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		exporter, err := NewOTLPMetricsExporter(OTLPMetricsExporterOptions{
+			Protocol: OTLPProtocolHTTP,
+			Endpoint: strings.TrimPrefix(server.URL, "http://"),
+			Insecure: true,
+		})
+		require.NoError(t, err)
+		defer exporter.Close()
+
+		assert.Error(t, exporter.ExportMetrics(context.Background(), testMetrics(now)))
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		service := &fakeMetricsService{
+			requests: make(chan *colmetricpb.ExportMetricsServiceRequest, 1),
+			headers:  make(chan metadata.MD, 1),
+		}
+		server := grpc.NewServer()
+		colmetricpb.RegisterMetricsServiceServer(server, service)
+		go server.Serve(lis)
+		defer server.Stop()
+
+		exporter, err := NewOTLPMetricsExporter(OTLPMetricsExporterOptions{
+			AppID:    "fakeID",
+			Protocol: OTLPProtocolGRPC,
+			Endpoint: lis.Addr().String(),
+			Insecure: true,
+			Headers:  map[string]string{"api-key": "secret"},
+		})
+		require.NoError(t, err)
+		defer exporter.Close()
+
+		require.NoError(t, exporter.ExportMetrics(context.Background(), testMetrics(now)))
+		assertExportedMetrics(t, now, <-service.requests)
+		assert.Equal(t, []string{"secret"}, (<-service.headers).Get("api-key"))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewOTLPMetricsExporter(OTLPMetricsExporterOptions{Protocol: OTLPProtocolHTTP})
+		assert.Error(t, err)
+		_, err = NewOTLPMetricsExporter(OTLPMetricsExporterOptions{Protocol: "tcp", Endpoint: "localhost:4317"})
+		assert.EqualError(t, err, "invalid protocol tcp provided for Otel endpoint")
+	})
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// PropagatorW3C is the W3C trace context propagation format, with the traceparent and tracestate headers.
+	PropagatorW3C = "w3c"
+	// PropagatorB3 is the B3 single header propagation format.
+	PropagatorB3 = "b3"
+	// PropagatorB3Multi is the B3 multiple headers propagation format.
+	PropagatorB3Multi = "b3multi"
+	// PropagatorJaeger is the Jaeger propagation format, with the uber-trace-id header.
+	PropagatorJaeger = "jaeger"
+
+	b3Header             = "b3"
+	b3TraceIDHeader      = "x-b3-traceid"
+	b3SpanIDHeader       = "x-b3-spanid"
+	b3SampledHeader      = "x-b3-sampled"
+	b3FlagsHeader        = "x-b3-flags"
+	jaegerTraceHeader    = "uber-trace-id"
+	jaegerSampledFlagBit = 1
+)
+
+// propagator extracts and injects span contexts in the headers of a request.
+type propagator interface {
+	extract(get func(name string) string) (trace.SpanContext, bool)
+	inject(sc trace.SpanContext, set func(name, value string))
+}
+
+var propagatorsByName = map[string]propagator{
+	PropagatorW3C:     w3cPropagator{},
+	PropagatorB3:      b3Propagator{},
+	PropagatorB3Multi: b3MultiPropagator{},
+	PropagatorJaeger:  jaegerPropagator{},
+}
+
+var (
+	propagatorsLock sync.RWMutex
+	// propagators are the configured propagation formats, in the order the incoming requests are checked.
+	propagators = []propagator{w3cPropagator{}}
+)
+
+// SetTracePropagators sets the propagation formats accepted and emitted by the runtime.
+// The incoming span contexts are extracted with the first format found in the request, in the order of the names,
+// and the outgoing span contexts are injected with all the formats.
+// W3C trace context is always accepted and emitted, as it is used between the sidecars.
+func SetTracePropagators(names []string) error {
+	configured := make([]propagator, 0, len(names)+1)
+	w3c := false
+	for _, name := range names {
+		p, ok := propagatorsByName[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("invalid trace propagator %s", name)
+		}
+		if _, ok = p.(w3cPropagator); ok {
+			w3c = true
+		}
+		configured = append(configured, p)
+	}
+	if !w3c {
+		configured = append(configured, w3cPropagator{})
+	}
+
+	propagatorsLock.Lock()
+	propagators = configured
+	propagatorsLock.Unlock()
+	return nil
+}
+
+func getTracePropagators() []propagator {
+	propagatorsLock.RLock()
+	defer propagatorsLock.RUnlock()
+	return propagators
+}
+
+// extractSpanContext extracts the span context of a request with the first configured format found in its headers.
+func extractSpanContext(get func(name string) string) (trace.SpanContext, bool) {
+	for _, p := range getTracePropagators() {
+		if sc, ok := p.extract(get); ok {
+			return sc, true
+		}
+	}
+	return trace.SpanContext{}, false
+}
+
+// injectSpanContext injects the span context with all the configured formats, optionally excluding W3C trace context.
+func injectSpanContext(sc trace.SpanContext, set func(name, value string), includeW3C bool) {
+	for _, p := range getTracePropagators() {
+		if _, ok := p.(w3cPropagator); ok && !includeW3C {
+			continue
+		}
+		p.inject(sc, set)
+	}
+}
+
+type w3cPropagator struct{}
+
+func (w3cPropagator) extract(get func(name string) string) (trace.SpanContext, bool) {
+	sc, ok := SpanContextFromW3CString(get(traceparentHeader))
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+	if ts := TraceStateFromW3CString(get(tracestateHeader)); ts != nil {
+		sc = sc.WithTraceState(*ts)
+	}
+	return sc, true
+}
+
+func (w3cPropagator) inject(sc trace.SpanContext, set func(name, value string)) {
+	set(traceparentHeader, SpanContextToW3CString(sc))
+	tracestateToHeader(sc, set)
+}
+
+// b3Propagator uses the single b3 header: {TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}.
+type b3Propagator struct{}
+
+func (b3Propagator) extract(get func(name string) string) (trace.SpanContext, bool) {
+	sections := strings.Split(get(b3Header), "-")
+	if len(sections) < 2 || len(sections) > 4 {
+		return trace.SpanContext{}, false
+	}
+	sampled := ""
+	if len(sections) > 2 {
+		sampled = sections[2]
+	}
+	return newRemoteSpanContext(sections[0], sections[1], sampled == "1" || sampled == "d")
+}
+
+func (b3Propagator) inject(sc trace.SpanContext, set func(name, value string)) {
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	set(b3Header, sc.TraceID().String()+"-"+sc.SpanID().String()+"-"+sampled)
+}
+
+// b3MultiPropagator uses the X-B3-TraceId, X-B3-SpanId and X-B3-Sampled headers.
+type b3MultiPropagator struct{}
+
+func (b3MultiPropagator) extract(get func(name string) string) (trace.SpanContext, bool) {
+	sampled := get(b3SampledHeader) == "1" || strings.EqualFold(get(b3SampledHeader), "true") || get(b3FlagsHeader) == "1"
+	return newRemoteSpanContext(get(b3TraceIDHeader), get(b3SpanIDHeader), sampled)
+}
+
+func (b3MultiPropagator) inject(sc trace.SpanContext, set func(name, value string)) {
+	set(b3TraceIDHeader, sc.TraceID().String())
+	set(b3SpanIDHeader, sc.SpanID().String())
+	if sc.IsSampled() {
+		set(b3SampledHeader, "1")
+	} else {
+		set(b3SampledHeader, "0")
+	}
+}
+
+// jaegerPropagator uses the uber-trace-id header: {trace-id}:{span-id}:{parent-span-id}:{flags}.
+type jaegerPropagator struct{}
+
+func (jaegerPropagator) extract(get func(name string) string) (trace.SpanContext, bool) {
+	sections := strings.Split(get(jaegerTraceHeader), ":")
+	if len(sections) != 4 {
+		return trace.SpanContext{}, false
+	}
+	flags, err := strconv.ParseUint(sections[3], 16, 8)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	return newRemoteSpanContext(sections[0], sections[1], flags&jaegerSampledFlagBit != 0)
+}
+
+func (jaegerPropagator) inject(sc trace.SpanContext, set func(name, value string)) {
+	flags := 0
+	if sc.IsSampled() {
+		flags = jaegerSampledFlagBit
+	}
+	set(jaegerTraceHeader, fmt.Sprintf("%s:%s:0:%x", sc.TraceID(), sc.SpanID(), flags))
+}
+
+// newRemoteSpanContext returns the span context of hex encoded IDs, which may be shorter than their full length like the 64-bit trace IDs.
+func newRemoteSpanContext(traceID, spanID string, sampled bool) (trace.SpanContext, bool) {
+	if traceID == "" || len(traceID) > 32 || spanID == "" || len(spanID) > 16 {
+		return trace.SpanContext{}, false
+	}
+	tid, err := trace.TraceIDFromHex(strings.Repeat("0", 32-len(traceID)) + strings.ToLower(traceID))
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	sid, err := trace.SpanIDFromHex(strings.Repeat("0", 16-len(spanID)) + strings.ToLower(spanID))
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	var flags trace.TraceFlags
+	if sampled {
+		flags = trace.FlagsSampled
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	return sc, sc.IsValid()
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestSetTracePropagators(t *testing.T) {
+	defer SetTracePropagators(nil)
+
+	assert.Error(t, SetTracePropagators([]string{"b3", "xray"}))
+
+	require.NoError(t, SetTracePropagators([]string{"Jaeger", "b3multi"}))
+	assert.Equal(t, []propagator{jaegerPropagator{}, b3MultiPropagator{}, w3cPropagator{}}, getTracePropagators())
+
+	require.NoError(t, SetTracePropagators([]string{"w3c", "b3"}))
+	assert.Equal(t, []propagator{w3cPropagator{}, b3Propagator{}}, getTracePropagators())
+
+	require.NoError(t, SetTracePropagators(nil))
+	assert.Equal(t, []propagator{w3cPropagator{}}, getTracePropagators())
+}
+
+func TestPropagators(t *testing.T) {
+	tid, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	sid, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+
+	testcases := []struct {
+		name       string
+		propagator propagator
+		headers    map[string]string
+	}{{
+		name:       "b3 single header",
+		propagator: b3Propagator{},
+		headers:    map[string]string{"b3": "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"},
+	}, {
+		name:       "b3 multiple headers",
+		propagator: b3MultiPropagator{},
+		headers: map[string]string{
+			"x-b3-traceid": "4bf92f3577b34da6a3ce929d0e0e4736",
+			"x-b3-spanid":  "00f067aa0ba902b7",
+			"x-b3-sampled": "1",
+		},
+	}, {
+		name:       "jaeger",
+		propagator: jaegerPropagator{},
+		headers:    map[string]string{"uber-trace-id": "4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"},
+	}}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := map[string]string{}
+			tc.propagator.inject(sc, func(name, value string) { headers[name] = value })
+			assert.Equal(t, tc.headers, headers)
+
+			extracted, ok := tc.propagator.extract(func(name string) string { return tc.headers[name] })
+			assert.True(t, ok)
+			assert.Equal(t, sc, extracted)
+
+			_, ok = tc.propagator.extract(func(string) string { return "" })
+			assert.False(t, ok)
+		})
+	}
+
+	t.Run("64-bit trace IDs are padded", func(t *testing.T) {
+		extracted, ok := b3Propagator{}.extract(func(string) string { return "a3ce929d0e0e4736-00f067aa0ba902b7" })
+		require.True(t, ok)
+		assert.Equal(t, "0000000000000000a3ce929d0e0e4736", extracted.TraceID().String())
+		assert.False(t, extracted.IsSampled())
+	})
+
+	t.Run("invalid IDs are rejected", func(t *testing.T) {
+		_, ok := jaegerPropagator{}.extract(func(string) string { return "xyz:00f067aa0ba902b7:0:1" })
+		assert.False(t, ok)
+		_, ok = jaegerPropagator{}.extract(func(string) string { return "0:0:0:1" })
+		assert.False(t, ok)
+	})
+}
+
+func TestPropagatorsHeaders(t *testing.T) {
+	require.NoError(t, SetTracePropagators([]string{"b3", "jaeger"}))
+	defer SetTracePropagators(nil)
+
+	t.Run("the span context is extracted from the configured headers", func(t *testing.T) {
+		req := &fasthttp.Request{}
+		req.Header.Set("uber-trace-id", "4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1")
+		sc, ok := SpanContextFromRequest(req)
+		require.True(t, ok)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+
+		md := metadata.Pairs("b3", "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1")
+		sc, ok = SpanContextFromIncomingGRPCMetadata(metadata.NewIncomingContext(context.Background(), md))
+		require.True(t, ok)
+		assert.Equal(t, "00f067aa0ba902b7", sc.SpanID().String())
+	})
+
+	t.Run("the span context is injected in all the configured headers", func(t *testing.T) {
+		tid, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		sid, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: tid, SpanID: sid, TraceFlags: trace.FlagsSampled})
+
+		headers := map[string]string{}
+		SpanContextToHTTPHeaders(sc, func(name, value string) { headers[name] = value })
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", headers["traceparent"])
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1", headers["b3"])
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1", headers["uber-trace-id"])
+
+		md, _ := metadata.FromOutgoingContext(SpanContextToGRPCMetadata(context.Background(), sc))
+		assert.Len(t, md[grpcTraceContextKey], 1)
+		assert.Equal(t, []string{"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"}, md["b3"])
+		assert.Empty(t, md["traceparent"])
+	})
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dapr/kit/logger"
+)
+
+// MaxRuntimeLoggingDuration is the maximum duration of a change of the logging before it's reverted.
+const MaxRuntimeLoggingDuration = 24 * time.Hour
+
+// DefaultRuntimeLogging holds the logging options of the sidecar changed at runtime.
+var DefaultRuntimeLogging = NewRuntimeLogging()
+
+// RuntimeLoggingStatus is the logging of the sidecar.
+type RuntimeLoggingStatus struct {
+	// Log level of the loggers.
+	Level string `json:"level"`
+	// Whether the API calls are logged.
+	APILogging bool `json:"apiLogging"`
+	// Time at which the logging is reverted to the startup options, if the change has a TTL.
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+	// Log levels of the scopes that override the log level of the sidecar.
+	ScopeLevels map[string]string `json:"scopeLevels,omitempty"`
+}
+
+// RuntimeLogging changes the log level and the logging of the API calls without restarting the sidecar.
+// The changes can be reverted to the startup options after a TTL.
+type RuntimeLogging struct {
+	lock sync.RWMutex
+
+	options     logger.Options
+	apiLogging  bool
+	apiLoggers  []logger.Logger
+	scopeLevels map[string]string
+
+	level      string
+	apiEnabled *bool
+	revert     *time.Timer
+	revertAt   *time.Time
+	clock      func() time.Time
+}
+
+// NewRuntimeLogging returns a new RuntimeLogging with the default logger options.
+func NewRuntimeLogging() *RuntimeLogging {
+	options := logger.DefaultOptions()
+	return &RuntimeLogging{
+		options: options,
+		level:   options.OutputLevel,
+		clock:   time.Now,
+	}
+}
+
+// Init sets the startup options of the logging, to which the changes are reverted.
+func (r *RuntimeLogging) Init(options logger.Options, apiLogging bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.options = options
+	r.apiLogging = apiLogging
+	r.level = options.OutputLevel
+}
+
+// SetScopeLevels overrides the log level of the loggers of the scopes, such as dapr.runtime.actor.
+// The scopes keep their log level when the log level of the sidecar changes.
+func (r *RuntimeLogging) SetScopeLevels(levels map[string]string) error {
+	opts := logger.DefaultOptions()
+	for scope, level := range levels {
+		if err := opts.SetOutputLevel(level); err != nil {
+			return fmt.Errorf("invalid log level of scope %s: %w", scope, err)
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.scopeLevels = make(map[string]string, len(levels))
+	for scope, level := range levels {
+		r.scopeLevels[scope] = strings.ToLower(level)
+		// Registers the loggers not created yet, so they get the options of the sidecar too
+		logger.NewLogger(scope)
+	}
+	return r.applyLevel(r.level)
+}
+
+// HasScopeLevel returns whether the log level of the scope is overridden.
+func (r *RuntimeLogging) HasScopeLevel(scope string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	_, ok := r.scopeLevels[scope]
+	return ok
+}
+
+// AddAPILogger adds a logger of the API calls, which is kept at the info level when the log level changes.
+func (r *RuntimeLogging) AddAPILogger(l logger.Logger) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, existing := range r.apiLoggers {
+		if existing == l {
+			return
+		}
+	}
+	r.apiLoggers = append(r.apiLoggers, l)
+}
+
+// APILoggingEnabled returns whether the API calls are logged, given whether the API logging is enabled in the
+// configuration of the server.
+func (r *RuntimeLogging) APILoggingEnabled(configured bool) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if r.apiEnabled != nil {
+		return *r.apiEnabled
+	}
+	return configured
+}
+
+// Status returns the current logging of the sidecar.
+func (r *RuntimeLogging) Status() RuntimeLoggingStatus {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.status()
+}
+
+func (r *RuntimeLogging) status() RuntimeLoggingStatus {
+	s := RuntimeLoggingStatus{
+		Level:      r.level,
+		APILogging: r.apiLogging,
+		RevertAt:   r.revertAt,
+	}
+	if len(r.scopeLevels) > 0 {
+		s.ScopeLevels = make(map[string]string, len(r.scopeLevels))
+		for scope, level := range r.scopeLevels {
+			s.ScopeLevels[scope] = level
+		}
+	}
+	if r.apiEnabled != nil {
+		s.APILogging = *r.apiEnabled
+	}
+	return s
+}
+
+// Set changes the log level, if not empty, and the logging of the API calls, if not nil.
+// With a positive TTL, capped at MaxRuntimeLoggingDuration, the logging is reverted to the startup options after it;
+// otherwise the change is kept. A change cancels the pending revert of the previous one, while an empty change
+// returns the current logging.
+func (r *RuntimeLogging) Set(level string, apiLogging *bool, ttl time.Duration) (RuntimeLoggingStatus, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if level == "" && apiLogging == nil && ttl <= 0 {
+		return r.status(), nil
+	}
+
+	if level != "" {
+		if err := r.applyLevel(level); err != nil {
+			return r.status(), err
+		}
+	}
+	if apiLogging != nil {
+		enabled := *apiLogging
+		r.apiEnabled = &enabled
+	}
+
+	if r.revert != nil {
+		r.revert.Stop()
+		r.revert = nil
+		r.revertAt = nil
+	}
+	if ttl > 0 {
+		if ttl > MaxRuntimeLoggingDuration {
+			ttl = MaxRuntimeLoggingDuration
+		}
+		revertAt := r.clock().Add(ttl)
+		r.revertAt = &revertAt
+
+		var t *time.Timer
+		t = time.AfterFunc(ttl, func() {
+			r.lock.Lock()
+			defer r.lock.Unlock()
+
+			// A later change replaced this one.
+			if r.revert != t {
+				return
+			}
+			r.reset()
+			log.Infof("log level reverted to: %s", r.level)
+		})
+		r.revert = t
+	}
+
+	return r.status(), nil
+}
+
+// Reset reverts the logging to the startup options and cancels the pending revert.
+func (r *RuntimeLogging) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.revert != nil {
+		r.revert.Stop()
+	}
+	r.reset()
+}
+
+func (r *RuntimeLogging) reset() {
+	if err := r.applyLevel(r.options.OutputLevel); err != nil {
+		log.Warnf("failed to revert the log level to %s: %s", r.options.OutputLevel, err)
+	}
+	r.apiEnabled = nil
+	r.revert = nil
+	r.revertAt = nil
+}
+
+func (r *RuntimeLogging) applyLevel(level string) error {
+	opts := r.options
+	if err := opts.SetOutputLevel(level); err != nil {
+		return err
+	}
+	if err := logger.ApplyOptionsToLoggers(&opts); err != nil {
+		return err
+	}
+	for _, l := range r.apiLoggers {
+		l.SetOutputLevel(logger.InfoLevel)
+	}
+	for scope, scopeLevel := range r.scopeLevels {
+		logger.NewLogger(scope).SetOutputLevel(logger.LogLevel(scopeLevel))
+	}
+	r.level = opts.OutputLevel
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/kit/logger"
+)
+
+// levelLogger records the output level of the logger.
+type levelLogger struct {
+	logger.Logger
+	level logger.LogLevel
+}
+
+func (l *levelLogger) SetOutputLevel(level logger.LogLevel) {
+	l.level = level
+	l.Logger.SetOutputLevel(level)
+}
+
+func TestRuntimeLogging(t *testing.T) {
+	apiLogger := &levelLogger{Logger: logger.NewLogger("dapr.test.runtime-logging-api")}
+
+	r := NewRuntimeLogging()
+	r.Init(logger.DefaultOptions(), false)
+	r.AddAPILogger(apiLogger)
+	defer r.Reset()
+
+	enabled := true
+
+	t.Run("startup options", func(t *testing.T) {
+		s := r.Status()
+		assert.Equal(t, "info", s.Level)
+		assert.False(t, s.APILogging)
+		assert.Nil(t, s.RevertAt)
+		assert.False(t, r.APILoggingEnabled(false))
+		assert.True(t, r.APILoggingEnabled(true))
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		_, err := r.Set("verbose", nil, 0)
+		assert.Error(t, err)
+		assert.Equal(t, "info", r.Status().Level)
+	})
+
+	t.Run("set level and API logging", func(t *testing.T) {
+		s, err := r.Set("debug", &enabled, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "debug", s.Level)
+		assert.True(t, s.APILogging)
+		assert.Nil(t, s.RevertAt)
+		assert.Equal(t, logger.InfoLevel, apiLogger.level)
+		assert.True(t, r.APILoggingEnabled(false))
+	})
+
+	t.Run("API logger is kept at info", func(t *testing.T) {
+		_, err := r.Set("error", nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "error", r.Status().Level)
+		assert.Equal(t, logger.InfoLevel, apiLogger.level)
+		assert.True(t, r.APILoggingEnabled(false))
+	})
+
+	t.Run("revert after TTL", func(t *testing.T) {
+		s, err := r.Set("debug", &enabled, 50*time.Millisecond)
+		require.NoError(t, err)
+		require.NotNil(t, s.RevertAt)
+
+		assert.Eventually(t, func() bool {
+			return r.Status().Level == "info"
+		}, 5*time.Second, 10*time.Millisecond)
+		s = r.Status()
+		assert.False(t, s.APILogging)
+		assert.Nil(t, s.RevertAt)
+	})
+
+	t.Run("a change cancels the pending revert", func(t *testing.T) {
+		_, err := r.Set("debug", nil, 50*time.Millisecond)
+		require.NoError(t, err)
+		s, err := r.Set("warn", nil, 0)
+		require.NoError(t, err)
+		assert.Nil(t, s.RevertAt)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, "warn", r.Status().Level)
+	})
+
+	t.Run("TTL is capped", func(t *testing.T) {
+		now := time.Now()
+		r.clock = func() time.Time { return now }
+		s, err := r.Set("", nil, 48*time.Hour)
+		require.NoError(t, err)
+		require.NotNil(t, s.RevertAt)
+		assert.Equal(t, now.Add(MaxRuntimeLoggingDuration), *s.RevertAt)
+	})
+
+	t.Run("empty change keeps the pending revert", func(t *testing.T) {
+		s, err := r.Set("", nil, 0)
+		require.NoError(t, err)
+		assert.NotNil(t, s.RevertAt)
+	})
+
+	t.Run("scope levels", func(t *testing.T) {
+		assert.Error(t, r.SetScopeLevels(map[string]string{"dapr.test.runtime-logging-scope": "verbose"}))
+		assert.False(t, r.HasScopeLevel("dapr.test.runtime-logging-scope"))
+
+		require.NoError(t, r.SetScopeLevels(map[string]string{"dapr.test.runtime-logging-scope": "DEBUG"}))
+		assert.True(t, r.HasScopeLevel("dapr.test.runtime-logging-scope"))
+		assert.Equal(t, map[string]string{"dapr.test.runtime-logging-scope": "debug"}, r.Status().ScopeLevels)
+
+		// The scopes keep their log level when the log level changes
+		s, err := r.Set("error", nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "error", s.Level)
+		assert.Equal(t, map[string]string{"dapr.test.runtime-logging-scope": "debug"}, s.ScopeLevels)
+
+		require.NoError(t, r.SetScopeLevels(nil))
+		assert.False(t, r.HasScopeLevel("dapr.test.runtime-logging-scope"))
+		assert.Nil(t, r.Status().ScopeLevels)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		r.Reset()
+		s := r.Status()
+		assert.Equal(t, "info", s.Level)
+		assert.Nil(t, s.RevertAt)
+	})
+}
@@ -21,6 +21,11 @@ var (
 	policyActionKey   = tag.MustNewKey("policyAction")
 	resiliencyNameKey = tag.MustNewKey("name")
 	policyKey         = tag.MustNewKey("policy")
+	attributeKey      = tag.MustNewKey("key")
+	resourceKindKey   = tag.MustNewKey("kind")
+	apiProtocolKey    = tag.MustNewKey("protocol")
+	apiKey            = tag.MustNewKey("api")
+	clusterKey        = tag.MustNewKey("cluster")
 )
 
 // serviceMetrics holds dapr runtime metric monitoring methods.
@@ -44,6 +49,9 @@ type serviceMetrics struct {
 	actorDeactivationTotal       *stats.Int64Measure
 	actorDeactivationFailedTotal *stats.Int64Measure
 	actorPendingCalls            *stats.Int64Measure
+	actorPlacementDisconnected   *stats.Int64Measure
+	actorPlacementTableUpdated   *stats.Int64Measure
+	actorReminderFailedTotal     *stats.Int64Measure
 
 	// Access Control Lists for Service Invocation metrics
 	appPolicyActionAllowed    *stats.Int64Measure
@@ -51,6 +59,19 @@ type serviceMetrics struct {
 	appPolicyActionBlocked    *stats.Int64Measure
 	globalPolicyActionBlocked *stats.Int64Measure
 
+	// Metadata attributes set by the app
+	metadataAttribute *stats.Int64Measure
+
+	// Resources that are out of date compared to the operator
+	driftedResources *stats.Int64Measure
+
+	// Calls to the deprecated APIs
+	deprecatedAPICalls *stats.Int64Measure
+
+	// Service invocations to and from other namespaces and clusters
+	crossBoundaryRequestsSent     *stats.Int64Measure
+	crossBoundaryRequestsReceived *stats.Int64Measure
+
 	appID   string
 	ctx     context.Context
 	enabled bool
@@ -120,6 +141,18 @@ func newServiceMetrics() *serviceMetrics {
 			"runtime/actor/pending_actor_calls",
 			"The number of pending actor calls waiting to acquire the per-actor lock.",
 			stats.UnitDimensionless),
+		actorPlacementDisconnected: stats.Int64(
+			"runtime/actor/placement_disconnected_total",
+			"The number of the disconnections from placement service.",
+			stats.UnitDimensionless),
+		actorPlacementTableUpdated: stats.Int64(
+			"runtime/actor/placement_table_updated_total",
+			"The number of the applied placement table updates.",
+			stats.UnitDimensionless),
+		actorReminderFailedTotal: stats.Int64(
+			"runtime/actor/reminder_failed_total",
+			"The number of the reminders which failed to fire.",
+			stats.UnitDimensionless),
 
 		// Access Control Lists for service invocation
 		appPolicyActionAllowed: stats.Int64(
@@ -139,6 +172,34 @@ func newServiceMetrics() *serviceMetrics {
 			"The number of requests blocked by the global action specified in the access control policy.",
 			stats.UnitDimensionless),
 
+		// Metadata attributes
+		metadataAttribute: stats.Int64(
+			"runtime/metadata/attribute",
+			"The metadata attributes set by the app, by key: 1 while the attribute is set, 0 once it expired.",
+			stats.UnitDimensionless),
+
+		// Drift
+		driftedResources: stats.Int64(
+			"runtime/drift/resources",
+			"The number of components and configurations loaded by the sidecar that differ from the current ones in the operator.",
+			stats.UnitDimensionless),
+
+		// Deprecated APIs
+		deprecatedAPICalls: stats.Int64(
+			"runtime/api/deprecated_calls_total",
+			"The number of calls to the deprecated Dapr APIs.",
+			stats.UnitDimensionless),
+
+		// Cross-boundary service invocation
+		crossBoundaryRequestsSent: stats.Int64(
+			"runtime/service_invocation/cross_boundary_req_sent_total",
+			"The number of service invocations to the apps of other namespaces and clusters.",
+			stats.UnitDimensionless),
+		crossBoundaryRequestsReceived: stats.Int64(
+			"runtime/service_invocation/cross_boundary_req_recv_total",
+			"The number of service invocations from the apps of other namespaces, checked by the cross-namespace policy.",
+			stats.UnitDimensionless),
+
 		// TODO: use the correct context for each request
 		ctx:     context.Background(),
 		enabled: false,
@@ -166,18 +227,30 @@ func (s *serviceMetrics) Init(appID string) error {
 		diagUtils.NewMeasureView(s.actorDeactivationTotal, []tag.Key{appIDKey, actorTypeKey}, view.Count()),
 		diagUtils.NewMeasureView(s.actorDeactivationFailedTotal, []tag.Key{appIDKey, actorTypeKey}, view.Count()),
 		diagUtils.NewMeasureView(s.actorPendingCalls, []tag.Key{appIDKey, actorTypeKey}, view.Count()),
+		diagUtils.NewMeasureView(s.actorPlacementDisconnected, []tag.Key{appIDKey}, view.Count()),
+		diagUtils.NewMeasureView(s.actorPlacementTableUpdated, []tag.Key{appIDKey}, view.Count()),
+		diagUtils.NewMeasureView(s.actorReminderFailedTotal, []tag.Key{appIDKey, actorTypeKey}, view.Count()),
 
 		diagUtils.NewMeasureView(s.appPolicyActionAllowed, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.Count()),
 		diagUtils.NewMeasureView(s.globalPolicyActionAllowed, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.Count()),
 		diagUtils.NewMeasureView(s.appPolicyActionBlocked, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.Count()),
 		diagUtils.NewMeasureView(s.globalPolicyActionBlocked, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.Count()),
+
+		diagUtils.NewMeasureView(s.metadataAttribute, []tag.Key{appIDKey, attributeKey}, view.Sum()),
+
+		diagUtils.NewMeasureView(s.driftedResources, []tag.Key{appIDKey, resourceKindKey}, view.Sum()),
+
+		diagUtils.NewMeasureView(s.deprecatedAPICalls, []tag.Key{appIDKey, apiProtocolKey, apiKey}, view.Count()),
+
+		diagUtils.NewMeasureView(s.crossBoundaryRequestsSent, []tag.Key{appIDKey, namespaceKey, clusterKey, policyActionKey}, view.Count()),
+		diagUtils.NewMeasureView(s.crossBoundaryRequestsReceived, []tag.Key{appIDKey, trustDomainKey, namespaceKey, policyActionKey}, view.Count()),
 	)
 }
 
 // ComponentLoaded records metric when component is loaded successfully.
 func (s *serviceMetrics) ComponentLoaded() {
 	if s.enabled {
-		stats.RecordWithTags(s.ctx, diagUtils.WithTags(appIDKey, s.appID), s.componentLoaded.M(1))
+		stats.RecordWithTags(s.ctx, diagUtils.WithTags(s.componentLoaded.Name(), appIDKey, s.appID), s.componentLoaded.M(1))
 	}
 }
 
@@ -186,7 +259,7 @@ func (s *serviceMetrics) ComponentInitialized(component string) {
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(appIDKey, s.appID, componentKey, component),
+			diagUtils.WithTags(s.componentInitCompleted.Name(), appIDKey, s.appID, componentKey, component),
 			s.componentInitCompleted.M(1))
 	}
 }
@@ -196,7 +269,7 @@ func (s *serviceMetrics) ComponentInitFailed(component string, reason string) {
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(appIDKey, s.appID, componentKey, component, failReasonKey, reason),
+			diagUtils.WithTags(s.componentInitFailed.Name(), appIDKey, s.appID, componentKey, component, failReasonKey, reason),
 			s.componentInitFailed.M(1))
 	}
 }
@@ -204,7 +277,7 @@ func (s *serviceMetrics) ComponentInitFailed(component string, reason string) {
 // MTLSInitCompleted records metric when component is initialized.
 func (s *serviceMetrics) MTLSInitCompleted() {
 	if s.enabled {
-		stats.RecordWithTags(s.ctx, diagUtils.WithTags(appIDKey, s.appID), s.mtlsInitCompleted.M(1))
+		stats.RecordWithTags(s.ctx, diagUtils.WithTags(s.mtlsInitCompleted.Name(), appIDKey, s.appID), s.mtlsInitCompleted.M(1))
 	}
 }
 
@@ -212,7 +285,7 @@ func (s *serviceMetrics) MTLSInitCompleted() {
 func (s *serviceMetrics) MTLSInitFailed(reason string) {
 	if s.enabled {
 		stats.RecordWithTags(
-			s.ctx, diagUtils.WithTags(appIDKey, s.appID, failReasonKey, reason),
+			s.ctx, diagUtils.WithTags(s.mtlsInitFailed.Name(), appIDKey, s.appID, failReasonKey, reason),
 			s.mtlsInitFailed.M(1))
 	}
 }
@@ -220,7 +293,7 @@ func (s *serviceMetrics) MTLSInitFailed(reason string) {
 // MTLSWorkLoadCertRotationCompleted records metric when workload certificate rotation is succeeded.
 func (s *serviceMetrics) MTLSWorkLoadCertRotationCompleted() {
 	if s.enabled {
-		stats.RecordWithTags(s.ctx, diagUtils.WithTags(appIDKey, s.appID), s.mtlsWorkloadCertRotated.M(1))
+		stats.RecordWithTags(s.ctx, diagUtils.WithTags(s.mtlsWorkloadCertRotated.Name(), appIDKey, s.appID), s.mtlsWorkloadCertRotated.M(1))
 	}
 }
 
@@ -228,7 +301,7 @@ func (s *serviceMetrics) MTLSWorkLoadCertRotationCompleted() {
 func (s *serviceMetrics) MTLSWorkLoadCertRotationFailed(reason string) {
 	if s.enabled {
 		stats.RecordWithTags(
-			s.ctx, diagUtils.WithTags(appIDKey, s.appID, failReasonKey, reason),
+			s.ctx, diagUtils.WithTags(s.mtlsWorkloadCertRotatedFailed.Name(), appIDKey, s.appID, failReasonKey, reason),
 			s.mtlsWorkloadCertRotatedFailed.M(1))
 	}
 }
@@ -237,7 +310,7 @@ func (s *serviceMetrics) MTLSWorkLoadCertRotationFailed(reason string) {
 func (s *serviceMetrics) ActorStatusReported(operation string) {
 	if s.enabled {
 		stats.RecordWithTags(
-			s.ctx, diagUtils.WithTags(appIDKey, s.appID, operationKey, operation),
+			s.ctx, diagUtils.WithTags(s.actorStatusReportTotal.Name(), appIDKey, s.appID, operationKey, operation),
 			s.actorStatusReportTotal.M(1))
 	}
 }
@@ -246,7 +319,7 @@ func (s *serviceMetrics) ActorStatusReported(operation string) {
 func (s *serviceMetrics) ActorStatusReportFailed(operation string, reason string) {
 	if s.enabled {
 		stats.RecordWithTags(
-			s.ctx, diagUtils.WithTags(appIDKey, s.appID, operationKey, operation, failReasonKey, reason),
+			s.ctx, diagUtils.WithTags(s.actorStatusReportFailedTotal.Name(), appIDKey, s.appID, operationKey, operation, failReasonKey, reason),
 			s.actorStatusReportFailedTotal.M(1))
 	}
 }
@@ -255,7 +328,7 @@ func (s *serviceMetrics) ActorStatusReportFailed(operation string, reason string
 func (s *serviceMetrics) ActorPlacementTableOperationReceived(operation string) {
 	if s.enabled {
 		stats.RecordWithTags(
-			s.ctx, diagUtils.WithTags(appIDKey, s.appID, operationKey, operation),
+			s.ctx, diagUtils.WithTags(s.actorTableOperationRecvTotal.Name(), appIDKey, s.appID, operationKey, operation),
 			s.actorTableOperationRecvTotal.M(1))
 	}
 }
@@ -265,7 +338,7 @@ func (s *serviceMetrics) ActorRebalanced(actorType string) {
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(appIDKey, s.appID, actorTypeKey, actorType),
+			diagUtils.WithTags(s.actorRebalancedTotal.Name(), appIDKey, s.appID, actorTypeKey, actorType),
 			s.actorRebalancedTotal.M(1))
 	}
 }
@@ -275,7 +348,7 @@ func (s *serviceMetrics) ActorDeactivated(actorType string) {
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(appIDKey, s.appID, actorTypeKey, actorType),
+			diagUtils.WithTags(s.actorDeactivationTotal.Name(), appIDKey, s.appID, actorTypeKey, actorType),
 			s.actorDeactivationTotal.M(1))
 	}
 }
@@ -285,7 +358,7 @@ func (s *serviceMetrics) ActorDeactivationFailed(actorType, reason string) {
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(appIDKey, s.appID, actorTypeKey, actorType, failReasonKey, reason),
+			diagUtils.WithTags(s.actorDeactivationFailedTotal.Name(), appIDKey, s.appID, actorTypeKey, actorType, failReasonKey, reason),
 			s.actorDeactivationFailedTotal.M(1))
 	}
 }
@@ -295,17 +368,47 @@ func (s *serviceMetrics) ReportActorPendingCalls(actorType string, pendingLocks
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(appIDKey, s.appID, actorTypeKey, actorType),
+			diagUtils.WithTags(s.actorPendingCalls.Name(), appIDKey, s.appID, actorTypeKey, actorType),
 			s.actorPendingCalls.M(int64(pendingLocks)))
 	}
 }
 
+// ActorPlacementDisconnected records metric when the stream to placement service is closed.
+func (s *serviceMetrics) ActorPlacementDisconnected() {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.actorPlacementDisconnected.Name(), appIDKey, s.appID),
+			s.actorPlacementDisconnected.M(1))
+	}
+}
+
+// ActorPlacementTableUpdated records metric when a new version of the placement tables is applied.
+func (s *serviceMetrics) ActorPlacementTableUpdated() {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.actorPlacementTableUpdated.Name(), appIDKey, s.appID),
+			s.actorPlacementTableUpdated.M(1))
+	}
+}
+
+// ActorReminderFailed records metric when a reminder fails to fire.
+func (s *serviceMetrics) ActorReminderFailed(actorType string) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.actorReminderFailedTotal.Name(), appIDKey, s.appID, actorTypeKey, actorType),
+			s.actorReminderFailedTotal.M(1))
+	}
+}
+
 // RequestAllowedByAppAction records the requests allowed due to a match with the action specified in the access control policy for the app.
 func (s *serviceMetrics) RequestAllowedByAppAction(appID, trustDomain, namespace, operation, httpverb string, policyAction bool) {
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(
+			diagUtils.WithTags(s.appPolicyActionAllowed.Name(),
 				appIDKey, appID,
 				trustDomainKey, trustDomain,
 				namespaceKey, namespace,
@@ -321,7 +424,7 @@ func (s *serviceMetrics) RequestBlockedByAppAction(appID, trustDomain, namespace
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(
+			diagUtils.WithTags(s.appPolicyActionBlocked.Name(),
 				appIDKey, appID,
 				trustDomainKey, trustDomain,
 				namespaceKey, namespace,
@@ -337,7 +440,7 @@ func (s *serviceMetrics) RequestAllowedByGlobalAction(appID, trustDomain, namesp
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(
+			diagUtils.WithTags(s.globalPolicyActionAllowed.Name(),
 				appIDKey, appID,
 				trustDomainKey, trustDomain,
 				namespaceKey, namespace,
@@ -353,7 +456,7 @@ func (s *serviceMetrics) RequestBlockedByGlobalAction(appID, trustDomain, namesp
 	if s.enabled {
 		stats.RecordWithTags(
 			s.ctx,
-			diagUtils.WithTags(
+			diagUtils.WithTags(s.globalPolicyActionBlocked.Name(),
 				appIDKey, appID,
 				trustDomainKey, trustDomain,
 				namespaceKey, namespace,
@@ -363,3 +466,72 @@ func (s *serviceMetrics) RequestBlockedByGlobalAction(appID, trustDomain, namesp
 			s.globalPolicyActionBlocked.M(1))
 	}
 }
+
+// MetadataAttributeSet records the metadata attribute set by the app.
+// The values of the attributes are not recorded, since they're unbounded.
+func (s *serviceMetrics) MetadataAttributeSet(key string) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.metadataAttribute.Name(), appIDKey, s.appID, attributeKey, key),
+			s.metadataAttribute.M(1))
+	}
+}
+
+// MetadataAttributeRemoved records the expiration of a metadata attribute.
+func (s *serviceMetrics) MetadataAttributeRemoved(key string) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.metadataAttribute.Name(), appIDKey, s.appID, attributeKey, key),
+			s.metadataAttribute.M(-1))
+	}
+}
+
+// DriftedResourcesChanged records the change of the number of resources of the kind that are out of date.
+func (s *serviceMetrics) DriftedResourcesChanged(kind string, delta int) {
+	if s.enabled && delta != 0 {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.driftedResources.Name(), appIDKey, s.appID, resourceKindKey, kind),
+			s.driftedResources.M(int64(delta)))
+	}
+}
+
+// DeprecatedAPICalled records a call to a deprecated API.
+func (s *serviceMetrics) DeprecatedAPICalled(protocol, api string) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.deprecatedAPICalls.Name(), appIDKey, s.appID, apiProtocolKey, protocol, apiKey, api),
+			s.deprecatedAPICalls.M(1))
+	}
+}
+
+// CrossBoundaryRequestSent records a service invocation to an app of another namespace or cluster, allowed or not by the outbound policy.
+func (s *serviceMetrics) CrossBoundaryRequestSent(namespace, cluster string, allowed bool) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.crossBoundaryRequestsSent.Name(),
+				appIDKey, s.appID,
+				namespaceKey, namespace,
+				clusterKey, cluster,
+				policyActionKey, allowed),
+			s.crossBoundaryRequestsSent.M(1))
+	}
+}
+
+// CrossBoundaryRequestReceived records a service invocation from an app of another namespace, allowed or not by the cross-namespace policy.
+func (s *serviceMetrics) CrossBoundaryRequestReceived(trustDomain, namespace string, allowed bool) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diagUtils.WithTags(s.crossBoundaryRequestsReceived.Name(),
+				appIDKey, s.appID,
+				trustDomainKey, trustDomain,
+				namespaceKey, namespace,
+				policyActionKey, allowed),
+			s.crossBoundaryRequestsReceived.M(1))
+	}
+}
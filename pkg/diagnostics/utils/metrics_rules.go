@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+// metricsNamespace is the prefix of the metric names in the Prometheus exporter.
+const metricsNamespace = "dapr_"
+
+var (
+	metricsRulesLock sync.RWMutex
+	metricsRules     map[string]metricRules
+)
+
+// metricRules are the rules applied to the labels of a metric, by label name.
+type metricRules map[string]labelRule
+
+// labelRule replaces the values of a label matching a regular expression, or drops the label.
+type labelRule struct {
+	drop         bool
+	replacements []labelReplacement
+}
+
+type labelReplacement struct {
+	regex *regexp.Regexp
+	value string
+}
+
+// normalizeMetricName returns the name of the metric in the Prometheus format, without the namespace.
+// The rules can be configured with either the name of the measure, like "runtime/actor/timers",
+// or the name exported to Prometheus, like "dapr_runtime_actor_timers".
+func normalizeMetricName(name string) string {
+	return strings.TrimPrefix(strings.ReplaceAll(name, "/", "_"), metricsNamespace)
+}
+
+// CreateRulesMap compiles the rules transforming the labels of the metrics, which replace the previous ones.
+// The rules are applied by WithTags before the metrics are recorded, to limit the cardinality of their labels.
+func CreateRulesMap(rules []config.MetricsRule) error {
+	res := make(map[string]metricRules, len(rules))
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return fmt.Errorf("the name of the metric is required in the metrics rules")
+		}
+		name := normalizeMetricName(rule.Name)
+		if _, ok := res[name]; ok {
+			return fmt.Errorf("duplicate rules for metric %s", rule.Name)
+		}
+
+		labels := make(metricRules, len(rule.Labels))
+		for _, label := range rule.Labels {
+			if label.Name == "" {
+				return fmt.Errorf("the name of the label is required in the rules of metric %s", rule.Name)
+			}
+
+			lr := labelRule{drop: label.Drop}
+			// Sort the replacements, so that they are evaluated in a predictable order
+			values := make([]string, 0, len(label.Regex))
+			for value := range label.Regex {
+				values = append(values, value)
+			}
+			sort.Strings(values)
+			for _, value := range values {
+				regex, err := regexp.Compile(label.Regex[value])
+				if err != nil {
+					return fmt.Errorf("invalid regular expression for label %s of metric %s: %w", label.Name, rule.Name, err)
+				}
+				lr.replacements = append(lr.replacements, labelReplacement{regex: regex, value: value})
+			}
+			labels[label.Name] = lr
+		}
+		res[name] = labels
+	}
+
+	metricsRulesLock.Lock()
+	metricsRules = res
+	metricsRulesLock.Unlock()
+	return nil
+}
+
+func getMetricRules(name string) metricRules {
+	if name == "" {
+		return nil
+	}
+
+	metricsRulesLock.RLock()
+	defer metricsRulesLock.RUnlock()
+	if len(metricsRules) == 0 {
+		return nil
+	}
+	return metricsRules[normalizeMetricName(name)]
+}
+
+// apply returns the value of the label after the rules are applied. An empty value drops the label.
+func (r metricRules) apply(label, value string) string {
+	rule, ok := r[label]
+	if !ok {
+		return value
+	}
+	if rule.drop {
+		return ""
+	}
+	for _, replacement := range rule.replacements {
+		if replacement.regex.MatchString(value) {
+			return replacement.value
+		}
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/tag"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+var (
+	pathKey   = tag.MustNewKey("path")
+	methodKey = tag.MustNewKey("method")
+	appKey    = tag.MustNewKey("app_id")
+)
+
+func tagValues(t *testing.T, mutators []tag.Mutator) map[string]string {
+	t.Helper()
+
+	ctx, err := tag.New(context.Background(), mutators...)
+	require.NoError(t, err)
+	res := map[string]string{}
+	for _, key := range []tag.Key{pathKey, methodKey, appKey} {
+		if value, ok := tag.FromContext(ctx).Value(key); ok {
+			res[key.Name()] = value
+		}
+	}
+	return res
+}
+
+func TestMetricsRules(t *testing.T) {
+	defer CreateRulesMap(nil)
+
+	require.NoError(t, CreateRulesMap([]config.MetricsRule{{
+		Name: "dapr_http_server_request_count",
+		Labels: []config.MetricLabel{{
+			Name: "path",
+			Regex: map[string]string{
+				"/orders/{id}":                  "^/orders/[^/]+$",
+				"/v1.0/actors/myactor/{id}/...": "^/v1.0/actors/myactor/",
+			},
+		}, {
+			Name: "method",
+			Drop: true,
+		}},
+	}}))
+
+	t.Run("the label values matching a rule are replaced", func(t *testing.T) {
+		values := tagValues(t, WithTags("http/server/request_count", appKey, "myapp", pathKey, "/orders/123", methodKey, "GET"))
+		assert.Equal(t, map[string]string{"app_id": "myapp", "path": "/orders/{id}"}, values)
+
+		values = tagValues(t, WithTags("http/server/request_count", pathKey, "/v1.0/actors/myactor/42/method/do"))
+		assert.Equal(t, "/v1.0/actors/myactor/{id}/...", values["path"])
+	})
+
+	t.Run("the label values not matching any rule are kept", func(t *testing.T) {
+		values := tagValues(t, WithTags("http/server/request_count", pathKey, "/users/123"))
+		assert.Equal(t, "/users/123", values["path"])
+	})
+
+	t.Run("the rules only apply to their metric", func(t *testing.T) {
+		values := tagValues(t, WithTags("http/server/latency", pathKey, "/orders/123", methodKey, "GET"))
+		assert.Equal(t, map[string]string{"path": "/orders/123", "method": "GET"}, values)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		assert.Error(t, CreateRulesMap([]config.MetricsRule{{Name: ""}}))
+		assert.Error(t, CreateRulesMap([]config.MetricsRule{{
+			Name:   "http/server/request_count",
+			Labels: []config.MetricLabel{{Name: "path", Regex: map[string]string{"x": "("}}},
+		}}))
+		assert.Error(t, CreateRulesMap([]config.MetricsRule{
+			{Name: "http/server/request_count"},
+			{Name: "dapr_http_server_request_count"},
+		}))
+
+		// The previous rules are kept
+		values := tagValues(t, WithTags("http/server/request_count", pathKey, "/orders/123"))
+		assert.Equal(t, "/orders/{id}", values["path"])
+	})
+}
@@ -33,9 +33,11 @@ func NewMeasureView(measure stats.Measure, keys []tag.Key, aggregation *view.Agg
 }
 
 // WithTags converts tag key and value pairs to tag.Mutator array.
-// WithTags(key1, value1, key2, value2) returns
+// WithTags(name, key1, value1, key2, value2) returns
 // []tag.Mutator{tag.Upsert(key1, value1), tag.Upsert(key2, value2)}.
-func WithTags(opts ...interface{}) []tag.Mutator {
+// The values are transformed by the rules configured for the labels of the metric with the given name.
+func WithTags(name string, opts ...interface{}) []tag.Mutator {
+	rules := getMetricRules(name)
 	tagMutators := []tag.Mutator{}
 	for i := 0; i < len(opts)-1; i += 2 {
 		key, ok := opts[i].(tag.Key)
@@ -46,6 +48,9 @@ func WithTags(opts ...interface{}) []tag.Mutator {
 		if !ok {
 			break
 		}
+		if rules != nil {
+			value = rules.apply(key.Name(), value)
+		}
 		// skip if value is empty
 		if value != "" {
 			tagMutators = append(tagMutators, tag.Upsert(key, value))
@@ -23,14 +23,14 @@ import (
 func TestWithTags(t *testing.T) {
 	t.Run("one tag", func(t *testing.T) {
 		appKey := tag.MustNewKey("app_id")
-		mutators := WithTags(appKey, "test")
+		mutators := WithTags("test", appKey, "test")
 		assert.Equal(t, 1, len(mutators))
 	})
 
 	t.Run("two tags", func(t *testing.T) {
 		appKey := tag.MustNewKey("app_id")
 		operationKey := tag.MustNewKey("operation")
-		mutators := WithTags(appKey, "test", operationKey, "op")
+		mutators := WithTags("test", appKey, "test", operationKey, "op")
 		assert.Equal(t, 2, len(mutators))
 	})
 
@@ -38,14 +38,14 @@ func TestWithTags(t *testing.T) {
 		appKey := tag.MustNewKey("app_id")
 		operationKey := tag.MustNewKey("operation")
 		methodKey := tag.MustNewKey("method")
-		mutators := WithTags(appKey, "test", operationKey, "op", methodKey, "method")
+		mutators := WithTags("test", appKey, "test", operationKey, "op", methodKey, "method")
 		assert.Equal(t, 3, len(mutators))
 	})
 
 	t.Run("two tags with wrong value type", func(t *testing.T) {
 		appKey := tag.MustNewKey("app_id")
 		operationKey := tag.MustNewKey("operation")
-		mutators := WithTags(appKey, "test", operationKey, 1)
+		mutators := WithTags("test", appKey, "test", operationKey, 1)
 		assert.Equal(t, 1, len(mutators))
 	})
 
@@ -53,7 +53,7 @@ func TestWithTags(t *testing.T) {
 		appKey := tag.MustNewKey("app_id")
 		operationKey := tag.MustNewKey("operation")
 		methodKey := tag.MustNewKey("method")
-		mutators := WithTags(appKey, "", operationKey, "op", methodKey, "method")
+		mutators := WithTags("test", appKey, "", operationKey, "op", methodKey, "method")
 		assert.Equal(t, 2, len(mutators))
 	})
 }
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+)
+
+// idPlaceholder replaces the segments of the paths that look like IDs.
+const idPlaceholder = "{id}"
+
+// PathTemplater collapses the variable segments of URL paths, so they can be used as low cardinality metric labels.
+// For example, /orders/123 becomes /orders/{id}.
+type PathTemplater struct {
+	templates [][]string
+}
+
+// NewPathTemplater returns a PathTemplater with route templates like "/orders/{id}/items/{item}".
+// The segments of the templates in braces match any segment of the paths.
+func NewPathTemplater(templates []string) *PathTemplater {
+	t := &PathTemplater{
+		templates: make([][]string, 0, len(templates)),
+	}
+	for _, tmpl := range templates {
+		tmpl = strings.Trim(tmpl, "/")
+		if tmpl == "" {
+			continue
+		}
+		t.templates = append(t.templates, strings.Split(tmpl, "/"))
+	}
+	return t
+}
+
+// Template returns the first route template that matches the path.
+// If none matches, it returns the path with the segments that look like IDs, such as numbers and UUIDs, replaced by {id}.
+// The query string is removed, and the leading slash of the path is kept.
+func (t *PathTemplater) Template(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	prefix := ""
+	if strings.HasPrefix(path, "/") {
+		prefix = "/"
+	}
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return path
+	}
+	segments := strings.Split(trimmed, "/")
+
+	if t != nil {
+		for _, tmpl := range t.templates {
+			if matchTemplate(tmpl, segments) {
+				return prefix + strings.Join(tmpl, "/")
+			}
+		}
+	}
+
+	changed := false
+	for i, s := range segments {
+		if isID(s) {
+			segments[i] = idPlaceholder
+			changed = true
+		}
+	}
+	if !changed {
+		return path
+	}
+	return prefix + strings.Join(segments, "/")
+}
+
+func matchTemplate(tmpl, segments []string) bool {
+	if len(tmpl) != len(segments) {
+		return false
+	}
+	for i, s := range tmpl {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if s != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isID reports whether the path segment looks like an ID: a number, a UUID, a long hexadecimal string,
+// or a long string with digits.
+func isID(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	digits, hex := 0, 0
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+			hex++
+		case (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F'):
+			hex++
+		}
+	}
+
+	switch {
+	case digits == len(s):
+		return true
+	case isUUID(s):
+		return true
+	case hex == len(s) && len(s) >= 16:
+		return true
+	case digits > 0 && len(s) >= 16:
+		return true
+	}
+	return false
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathTemplater(t *testing.T) {
+	templater := NewPathTemplater([]string{"/orders/{orderID}/items/{item}", "users/{name}/", ""})
+
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"", ""},
+		{"/", "/"},
+		{"/orders", "/orders"},
+		{"/orders/123", "/orders/{id}"},
+		{"orders/123", "orders/{id}"},
+		{"/orders/123?expand=true", "/orders/{id}"},
+		{"/orders/123/items/shoes", "/orders/{orderID}/items/{item}"},
+		{"/users/alice", "/users/{name}"},
+		{"/users/alice/orders", "/users/alice/orders"},
+		{"/orders/f47ac10b-58cc-4372-a567-0e02b2c3d479", "/orders/{id}"},
+		{"/orders/5f8d0d55b54764421b7156c3", "/orders/{id}"},
+		{"/orders/ORD2022ABCDEFGHIJK", "/orders/{id}"},
+		{"/v1.0/state/store", "/v1.0/state/store"},
+		{"/orders/deadbeef", "/orders/deadbeef"},
+		{"/orders/order-1", "/orders/order-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.out, templater.Template(tt.in))
+		})
+	}
+
+	t.Run("nil templater uses the heuristics only", func(t *testing.T) {
+		var nilTemplater *PathTemplater
+		assert.Equal(t, "/users/alice", nilTemplater.Template("/users/alice"))
+		assert.Equal(t, "/orders/{id}", nilTemplater.Template("/orders/42"))
+	})
+}
@@ -0,0 +1,117 @@
+package diagnostics
+
+import (
+	"context"
+	"strconv"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+var (
+	workflowNameKey   = tag.MustNewKey("workflow")
+	workflowStatusKey = tag.MustNewKey("status")
+	activityNameKey   = tag.MustNewKey("activity")
+)
+
+// workflowMetrics holds dapr runtime metrics for the workflow engine.
+type workflowMetrics struct {
+	workflowStartedCount   *stats.Int64Measure
+	workflowCompletedCount *stats.Int64Measure
+	workflowLatency        *stats.Float64Measure
+	activityCount          *stats.Int64Measure
+	activityLatency        *stats.Float64Measure
+
+	appID   string
+	ctx     context.Context
+	enabled bool
+}
+
+func newWorkflowMetrics() *workflowMetrics {
+	return &workflowMetrics{
+		workflowStartedCount: stats.Int64(
+			"workflow/started/count",
+			"The number of workflow instances started.",
+			stats.UnitDimensionless),
+		workflowCompletedCount: stats.Int64(
+			"workflow/completed/count",
+			"The number of workflow instances that reached a final status.",
+			stats.UnitDimensionless),
+		workflowLatency: stats.Float64(
+			"workflow/completed/latencies",
+			"The time between the start of the workflow instances and their final status.",
+			stats.UnitMilliseconds),
+		activityCount: stats.Int64(
+			"workflow/activity/count",
+			"The number of activities executed by workflow instances.",
+			stats.UnitDimensionless),
+		activityLatency: stats.Float64(
+			"workflow/activity/latencies",
+			"The latency of the activities executed by workflow instances.",
+			stats.UnitMilliseconds),
+
+		ctx:     context.Background(),
+		enabled: false,
+	}
+}
+
+// Init registers the workflow metrics views.
+func (w *workflowMetrics) Init(appID string) error {
+	w.appID = appID
+	w.enabled = true
+
+	return view.Register(
+		diagUtils.NewMeasureView(w.workflowStartedCount, []tag.Key{appIDKey, workflowNameKey}, view.Count()),
+		diagUtils.NewMeasureView(w.workflowCompletedCount, []tag.Key{appIDKey, workflowNameKey, workflowStatusKey}, view.Count()),
+		diagUtils.NewMeasureView(w.workflowLatency, []tag.Key{appIDKey, workflowNameKey, workflowStatusKey}, defaultLatencyDistribution),
+		diagUtils.NewMeasureView(w.activityCount, []tag.Key{appIDKey, workflowNameKey, activityNameKey, successKey}, view.Count()),
+		diagUtils.NewMeasureView(w.activityLatency, []tag.Key{appIDKey, workflowNameKey, activityNameKey, successKey}, defaultLatencyDistribution),
+	)
+}
+
+// WorkflowStarted records metric when a workflow instance is started.
+func (w *workflowMetrics) WorkflowStarted(workflow string) {
+	if w.enabled {
+		stats.RecordWithTags(
+			w.ctx,
+			diagUtils.WithTags(w.workflowStartedCount.Name(), appIDKey, w.appID, workflowNameKey, workflow),
+			w.workflowStartedCount.M(1))
+	}
+}
+
+// WorkflowCompleted records metric when a workflow instance reaches a final status.
+func (w *workflowMetrics) WorkflowCompleted(workflow, status string, elapsed float64) {
+	if w.enabled {
+		stats.RecordWithTags(
+			w.ctx,
+			diagUtils.WithTags(w.workflowCompletedCount.Name(), appIDKey, w.appID, workflowNameKey, workflow, workflowStatusKey, status),
+			w.workflowCompletedCount.M(1))
+
+		if elapsed > 0 {
+			stats.RecordWithTags(
+				w.ctx,
+				diagUtils.WithTags(w.workflowLatency.Name(), appIDKey, w.appID, workflowNameKey, workflow, workflowStatusKey, status),
+				w.workflowLatency.M(elapsed))
+		}
+	}
+}
+
+// ActivityExecuted records metric when an activity of a workflow instance is executed.
+func (w *workflowMetrics) ActivityExecuted(workflow, activity string, success bool, elapsed float64) {
+	if w.enabled {
+		stats.RecordWithTags(
+			w.ctx,
+			diagUtils.WithTags(w.activityCount.Name(), appIDKey, w.appID, workflowNameKey, workflow, activityNameKey, activity, successKey, strconv.FormatBool(success)),
+			w.activityCount.M(1))
+
+		if elapsed > 0 {
+			stats.RecordWithTags(
+				w.ctx,
+				diagUtils.WithTags(w.activityLatency.Name(), appIDKey, w.appID, workflowNameKey, workflow, activityNameKey, activity, successKey, strconv.FormatBool(success)),
+				w.activityLatency.M(elapsed))
+		}
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package embedded runs the Dapr runtime in the process of a Go application.
+// The app and the runtime communicate over in-memory gRPC connections, with the same building block APIs as daprd.
+package embedded
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dapr/dapr/pkg/acl"
+	bindingsLoader "github.com/dapr/dapr/pkg/components/bindings"
+	configurationLoader "github.com/dapr/dapr/pkg/components/configuration"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
+	lockLoader "github.com/dapr/dapr/pkg/components/lock"
+	httpMiddlewareLoader "github.com/dapr/dapr/pkg/components/middleware/http"
+	nrLoader "github.com/dapr/dapr/pkg/components/nameresolution"
+	pubsubLoader "github.com/dapr/dapr/pkg/components/pubsub"
+	secretstoresLoader "github.com/dapr/dapr/pkg/components/secretstores"
+	stateLoader "github.com/dapr/dapr/pkg/components/state"
+	daprGlobalConfig "github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/modes"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	resiliencyConfig "github.com/dapr/dapr/pkg/resiliency"
+	"github.com/dapr/dapr/pkg/runtime"
+	"github.com/dapr/kit/logger"
+)
+
+// Size of the buffers of the in-memory connections.
+const bufferSize = 1024 * 1024
+
+var log = logger.NewLogger("dapr.embedded")
+
+// Options contains the options for an embedded runtime.
+type Options struct {
+	// ID of the app.
+	AppID string
+	// Path of the components directory. If empty, components will not be loaded.
+	ComponentsPath string
+	// Path of the configuration file. If empty, the default configuration is used.
+	ConfigPath string
+	// Server of the app callbacks invoked by the runtime, for service invocation, pubsub and input bindings.
+	// If nil, the runtime does not call the app.
+	App runtimev1pb.AppCallbackServer
+	// Ports for the HTTP API, the gRPC API and the Dapr to Dapr gRPC API. Free ports are picked if 0.
+	HTTPPort         int
+	APIGRPCPort      int
+	InternalGRPCPort int
+	// Addresses of the placement service, to enable actors.
+	PlacementAddresses []string
+	// Maximum number of concurrent calls to the app. Unlimited if 0.
+	MaxConcurrency int
+	// Duration to wait for outstanding operations on Close.
+	GracefulShutdownDuration time.Duration
+}
+
+// Runtime is a Dapr runtime embedded in the app.
+type Runtime struct {
+	opts        Options
+	rt          *runtime.DaprRuntime
+	apiListener *bufconn.Listener
+	appListener *bufconn.Listener
+	appServer   *grpc.Server
+	appConn     *grpc.ClientConn
+	conn        *grpc.ClientConn
+	client      runtimev1pb.DaprClient
+	closeOnce   sync.Once
+}
+
+// New returns a new embedded runtime in standalone mode.
+func New(opts Options) (*Runtime, error) {
+	if opts.AppID == "" {
+		return nil, errors.New("app id is required")
+	}
+
+	if err := assignFreePorts(&opts.HTTPPort, &opts.APIGRPCPort, &opts.InternalGRPCPort); err != nil {
+		return nil, errors.Wrap(err, "failed to get free ports")
+	}
+
+	var resourcesPath []string
+	if opts.ComponentsPath != "" {
+		resourcesPath = []string{opts.ComponentsPath}
+	}
+
+	runtimeConfig := runtime.NewRuntimeConfig(runtime.NewRuntimeConfigOpts{
+		ID:                       opts.AppID,
+		PlacementAddresses:       opts.PlacementAddresses,
+		GlobalConfig:             opts.ConfigPath,
+		ResourcesPath:            resourcesPath,
+		AppProtocol:              string(runtime.GRPCProtocol),
+		Mode:                     string(modes.StandaloneMode),
+		HTTPPort:                 opts.HTTPPort,
+		InternalGRPCPort:         opts.InternalGRPCPort,
+		APIGRPCPort:              opts.APIGRPCPort,
+		APIListenAddresses:       []string{runtime.DefaultAPIListenAddress},
+		ProfilePort:              runtime.DefaultProfilePort,
+		MaxConcurrency:           opts.MaxConcurrency,
+		MaxRequestBodySize:       runtime.DefaultMaxRequestBodySize,
+		ReadBufferSize:           runtime.DefaultReadBufferSize,
+		GracefulShutdownDuration: opts.GracefulShutdownDuration,
+	})
+
+	globalConfig := daprGlobalConfig.LoadDefaultConfiguration()
+	if opts.ConfigPath != "" {
+		var err error
+		globalConfig, _, err = daprGlobalConfig.LoadStandaloneConfiguration(opts.ConfigPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading configuration")
+		}
+	}
+
+	var resiliencyProvider resiliencyConfig.Provider = &resiliencyConfig.NoOp{}
+	if daprGlobalConfig.IsFeatureEnabled(globalConfig.Spec.Features, daprGlobalConfig.Resiliency) {
+		resiliencyConfigs := resiliencyConfig.LoadStandaloneResiliency(log, opts.AppID, opts.ComponentsPath)
+		resiliencyProvider = resiliencyConfig.FromConfigurations(log, resiliencyConfigs...)
+	}
+
+	accessControlList, err := acl.ParseAccessControlSpec(globalConfig.Spec.AccessControlSpec, string(runtimeConfig.ApplicationProtocol))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runtime{
+		opts: opts,
+		rt:   runtime.NewDaprRuntime(runtimeConfig, globalConfig, accessControlList, resiliencyProvider),
+	}, nil
+}
+
+// Start initializes the runtime with the components of the default registries, and connects it to the app.
+// The options override the default registries.
+func (r *Runtime) Start(opts ...runtime.Option) error {
+	r.apiListener = bufconn.Listen(bufferSize)
+	conn, err := dialBufconn(r.apiListener)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+	r.client = runtimev1pb.NewDaprClient(conn)
+
+	runtimeOpts := []runtime.Option{
+		runtime.WithSecretStores(secretstoresLoader.DefaultRegistry),
+		runtime.WithStates(stateLoader.DefaultRegistry),
+		runtime.WithConfigurations(configurationLoader.DefaultRegistry),
+		runtime.WithLocks(lockLoader.DefaultRegistry),
+		runtime.WithCryptoProviders(cryptoLoader.DefaultRegistry),
+		runtime.WithPubSubs(pubsubLoader.DefaultRegistry),
+		runtime.WithNameResolutions(nrLoader.DefaultRegistry),
+		runtime.WithBindings(bindingsLoader.DefaultRegistry),
+		runtime.WithHTTPMiddlewares(httpMiddlewareLoader.DefaultRegistry),
+		runtime.WithAPIListener(r.apiListener),
+	}
+
+	if r.opts.App != nil {
+		r.appListener = bufconn.Listen(bufferSize)
+		r.appServer = grpc.NewServer()
+		runtimev1pb.RegisterAppCallbackServer(r.appServer, r.opts.App)
+		go func() {
+			if serveErr := r.appServer.Serve(r.appListener); serveErr != nil {
+				log.Errorf("app callback server error: %s", serveErr)
+			}
+		}()
+
+		r.appConn, err = dialBufconn(r.appListener)
+		if err != nil {
+			return err
+		}
+		runtimeOpts = append(runtimeOpts, runtime.WithAppConnection(r.appConn))
+	}
+
+	return r.rt.Run(append(runtimeOpts, opts...)...)
+}
+
+// Client returns a client of the Dapr gRPC API of the runtime, over an in-memory connection.
+// It can only be used after Start.
+func (r *Runtime) Client() runtimev1pb.DaprClient {
+	return r.client
+}
+
+// HTTPPort returns the port of the Dapr HTTP API.
+func (r *Runtime) HTTPPort() int {
+	return r.opts.HTTPPort
+}
+
+// Close gracefully stops the runtime and closes the connections to the app.
+func (r *Runtime) Close() error {
+	r.closeOnce.Do(func() {
+		r.rt.Shutdown(r.opts.GracefulShutdownDuration)
+		if r.conn != nil {
+			r.conn.Close()
+		}
+		if r.appConn != nil {
+			r.appConn.Close()
+		}
+		if r.appServer != nil {
+			r.appServer.Stop()
+		}
+	})
+	return nil
+}
+
+func dialBufconn(l *bufconn.Listener) (*grpc.ClientConn, error) {
+	return grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return l.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
+
+func assignFreePorts(ports ...*int) error {
+	for _, p := range ports {
+		if *p != 0 {
+			continue
+		}
+		port, err := freeport.GetFreePort()
+		if err != nil {
+			return err
+		}
+		*p = port
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package embedded
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	inmemory "github.com/dapr/components-contrib/state/in-memory"
+	stateLoader "github.com/dapr/dapr/pkg/components/state"
+	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/dapr/pkg/runtime"
+)
+
+const stateComponent = `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.in-memory
+  version: v1
+`
+
+type echoApp struct {
+	runtimev1pb.UnimplementedAppCallbackServer
+}
+
+func (echoApp) OnInvoke(ctx context.Context, in *commonv1pb.InvokeRequest) (*commonv1pb.InvokeResponse, error) {
+	return &commonv1pb.InvokeResponse{
+		Data:        &anypb.Any{Value: append([]byte(in.Method+":"), in.GetData().GetValue()...)},
+		ContentType: "text/plain",
+	}, nil
+}
+
+func TestNew(t *testing.T) {
+	t.Run("app id is required", func(t *testing.T) {
+		_, err := New(Options{})
+		assert.Error(t, err)
+	})
+
+	t.Run("free ports are assigned", func(t *testing.T) {
+		r, err := New(Options{AppID: "myapp", HTTPPort: 1234})
+		require.NoError(t, err)
+		assert.Equal(t, 1234, r.HTTPPort())
+		assert.NotZero(t, r.opts.APIGRPCPort)
+		assert.NotZero(t, r.opts.InternalGRPCPort)
+	})
+}
+
+func TestEmbeddedRuntime(t *testing.T) {
+	componentsPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(componentsPath, "statestore.yaml"), []byte(stateComponent), 0o600))
+
+	states := stateLoader.NewRegistry()
+	states.RegisterComponent(inmemory.NewInMemoryStateStore, "in-memory")
+
+	r, err := New(Options{
+		AppID:          "myapp",
+		ComponentsPath: componentsPath,
+		App:            echoApp{},
+	})
+	require.NoError(t, err)
+	require.NoError(t, r.Start(runtime.WithStates(states)))
+	defer r.Close()
+
+	client := r.Client()
+	ctx := context.Background()
+
+	t.Run("state", func(t *testing.T) {
+		_, err := client.SaveState(ctx, &runtimev1pb.SaveStateRequest{
+			StoreName: "statestore",
+			States: []*commonv1pb.StateItem{
+				{Key: "key1", Value: []byte("value1"), Metadata: map[string]string{"ttlInSeconds": "60"}},
+			},
+		})
+		require.NoError(t, err)
+
+		resp, err := client.GetState(ctx, &runtimev1pb.GetStateRequest{
+			StoreName: "statestore",
+			Key:       "key1",
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.Data)
+	})
+
+	t.Run("invoke the app", func(t *testing.T) {
+		resp, err := client.InvokeService(ctx, &runtimev1pb.InvokeServiceRequest{
+			Id: "myapp",
+			Message: &commonv1pb.InvokeRequest{
+				Method: "echo",
+				Data:   &anypb.Any{Value: []byte("hello")},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("echo:hello"), resp.GetData().GetValue())
+	})
+}
@@ -20,10 +20,12 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/dapr/components-contrib/lock"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
 	lockLoader "github.com/dapr/dapr/pkg/components/lock"
 	"github.com/dapr/dapr/pkg/version"
 
@@ -34,6 +36,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/dapr/components-contrib/bindings"
 	"github.com/dapr/components-contrib/contenttype"
@@ -59,12 +62,17 @@ import (
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	"github.com/dapr/dapr/pkg/resiliency"
 	"github.com/dapr/dapr/pkg/resiliency/breaker"
+	runtimeConfiguration "github.com/dapr/dapr/pkg/runtime/configuration"
+	"github.com/dapr/dapr/pkg/runtime/meta"
 	runtimePubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	"github.com/dapr/dapr/pkg/runtime/workflow"
+	"github.com/dapr/dapr/pkg/statemerge"
 )
 
 const (
 	daprHTTPStatusHeader  = "dapr-http-status"
 	daprRuntimeVersionKey = "daprRuntimeVersion"
+	baggageMetadata       = "baggage"
 )
 
 // API is the gRPC interface for the Dapr gRPC API. It implements both the internal and external proto definitions.
@@ -104,12 +112,32 @@ type API interface {
 	InvokeActor(ctx context.Context, in *runtimev1pb.InvokeActorRequest) (*runtimev1pb.InvokeActorResponse, error)
 	TryLockAlpha1(ctx context.Context, in *runtimev1pb.TryLockRequest) (*runtimev1pb.TryLockResponse, error)
 	UnlockAlpha1(ctx context.Context, in *runtimev1pb.UnlockRequest) (*runtimev1pb.UnlockResponse, error)
+	SetWorkflowEngine(engine *workflow.Engine)
+	SetRuntimeMetadataFn(getRuntimeMetadataFn func() meta.Info)
+	SetMetadataAttributes(attributes *meta.AttributeStore)
+	SetCrossNamespacePolicy(policy *acl.CrossNamespacePolicy)
+	StartWorkflowAlpha1(ctx context.Context, in *runtimev1pb.StartWorkflowRequest) (*runtimev1pb.StartWorkflowResponse, error)
+	GetWorkflowAlpha1(ctx context.Context, in *runtimev1pb.GetWorkflowRequest) (*runtimev1pb.GetWorkflowResponse, error)
+	TerminateWorkflowAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*emptypb.Empty, error)
+	RaiseEventWorkflowAlpha1(ctx context.Context, in *runtimev1pb.RaiseEventWorkflowRequest) (*emptypb.Empty, error)
+	PauseWorkflowAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*emptypb.Empty, error)
+	ResumeWorkflowAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*emptypb.Empty, error)
+	PurgeWorkflowAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*emptypb.Empty, error)
+	GetWorkflowPendingAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*runtimev1pb.GetWorkflowPendingResponse, error)
+	CancelWorkflowTimerAlpha1(ctx context.Context, in *runtimev1pb.WorkflowTimerRequest) (*emptypb.Empty, error)
+	RescheduleWorkflowTimerAlpha1(ctx context.Context, in *runtimev1pb.RescheduleWorkflowTimerRequest) (*emptypb.Empty, error)
+	EncryptAlpha1(stream runtimev1pb.Dapr_EncryptAlpha1Server) error
+	DecryptAlpha1(stream runtimev1pb.Dapr_DecryptAlpha1Server) error
+	SignAlpha1(ctx context.Context, in *runtimev1pb.SignRequest) (*runtimev1pb.SignResponse, error)
+	VerifyAlpha1(ctx context.Context, in *runtimev1pb.VerifyRequest) (*runtimev1pb.VerifyResponse, error)
 	// Gets metadata of the sidecar
 	GetMetadata(ctx context.Context, in *emptypb.Empty) (*runtimev1pb.GetMetadataResponse, error)
 	// Sets value in extended metadata of the sidecar
 	SetMetadata(ctx context.Context, in *runtimev1pb.SetMetadataRequest) (*emptypb.Empty, error)
 	// Shutdown the sidecar
 	Shutdown(ctx context.Context, in *emptypb.Empty) (*emptypb.Empty, error)
+	// Sets the log level of the sidecar and toggles the logging of the API calls
+	SetLogLevelAlpha1(ctx context.Context, in *runtimev1pb.SetLogLevelRequest) (*runtimev1pb.SetLogLevelResponse, error)
 }
 
 type api struct {
@@ -125,16 +153,20 @@ type api struct {
 	configurationSubscribe     map[string]chan struct{} // store map[storeName||key1,key2] -> stopChan
 	configurationSubscribeLock sync.Mutex
 	lockStores                 map[string]lock.Store
+	cryptoProviders            map[string]cryptoLoader.SubtleCrypto
+	workflowEngine             *workflow.Engine
 	pubsubAdapter              runtimePubsub.Adapter
 	id                         string
 	sendToOutputBindingFn      func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error)
 	tracingSpec                config.TracingSpec
 	accessControlList          *config.AccessControlList
+	crossNamespacePolicy       *acl.CrossNamespacePolicy
 	appProtocol                string
-	extendedMetadata           sync.Map
+	extendedMetadata           *meta.AttributeStore
 	shutdown                   func()
 	getComponentsFn            func() []componentsV1alpha.Component
 	getComponentsCapabilitesFn func() map[string][]string
+	getRuntimeMetadataFn       func() meta.Info
 	daprRunTimeVersion         string
 }
 
@@ -172,13 +204,14 @@ func (a *api) TryLockAlpha1(ctx context.Context, req *runtimev1pb.TryLockRequest
 		return &runtimev1pb.TryLockResponse{}, err
 	}
 	// 4. delegate to the component
-	compResp, err := store.TryLock(compReq)
+	compResp, err := lockLoader.TryLock(store, compReq)
 	if err != nil {
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.TryLockResponse{}, err
 	}
 	// 5. convert response
-	resp := TryLockResponseToGrpcResponse(compResp)
+	resp := TryLockResponseToGrpcResponse(&compResp.TryLockResponse)
+	resp.FencingToken = compResp.FencingToken
 	return resp, nil
 }
 
@@ -222,6 +255,401 @@ func (a *api) UnlockAlpha1(ctx context.Context, req *runtimev1pb.UnlockRequest)
 	return resp, nil
 }
 
+func (a *api) SetWorkflowEngine(engine *workflow.Engine) {
+	a.workflowEngine = engine
+}
+
+func (a *api) validateWorkflowRequest(workflowName, instanceID string) error {
+	if a.workflowEngine == nil {
+		return status.Error(codes.FailedPrecondition, messages.ErrWorkflowEngineNotConfigured)
+	}
+	if workflowName == "" {
+		return status.Error(codes.InvalidArgument, messages.ErrWorkflowNameMissing)
+	}
+	if instanceID == "" {
+		return status.Error(codes.InvalidArgument, messages.ErrWorkflowInstanceIDMissing)
+	}
+	return nil
+}
+
+// getWorkflowInstance returns the state of an existing workflow instance, and checks that it is an instance of the workflow.
+func (a *api) getWorkflowInstance(ctx context.Context, workflowName, instanceID string) (*workflow.State, error) {
+	if err := a.validateWorkflowRequest(workflowName, instanceID); err != nil {
+		return nil, err
+	}
+
+	state, err := a.workflowEngine.Get(ctx, instanceID)
+	if err != nil {
+		return nil, status.Errorf(workflowErrorCode(err), messages.ErrWorkflowGet, instanceID, err)
+	}
+	if state.WorkflowName != workflowName {
+		return nil, status.Errorf(codes.InvalidArgument, messages.ErrWorkflowNameMismatch, instanceID, state.WorkflowName)
+	}
+	return state, nil
+}
+
+// workflowErrorCode returns the gRPC status code for an error of the workflow engine.
+func workflowErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, workflow.ErrWorkflowNotFound), errors.Is(err, workflow.ErrInstanceNotFound),
+		errors.Is(err, workflow.ErrTimerNotFound):
+		return codes.NotFound
+	case errors.Is(err, workflow.ErrInstanceExists):
+		return codes.AlreadyExists
+	case errors.Is(err, workflow.ErrInstanceCompleted), errors.Is(err, workflow.ErrInstanceNotComplete),
+		errors.Is(err, workflow.ErrInstanceNotPaused):
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+func (a *api) StartWorkflowAlpha1(ctx context.Context, in *runtimev1pb.StartWorkflowRequest) (*runtimev1pb.StartWorkflowResponse, error) {
+	if err := a.validateWorkflowRequest(in.WorkflowName, in.InstanceId); err != nil {
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.StartWorkflowResponse{}, err
+	}
+
+	if err := a.workflowEngine.Start(ctx, in.WorkflowName, in.InstanceId, in.Input); err != nil {
+		err = status.Errorf(workflowErrorCode(err), messages.ErrWorkflowStart, in.WorkflowName, in.InstanceId, err)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.StartWorkflowResponse{}, err
+	}
+	return &runtimev1pb.StartWorkflowResponse{InstanceId: in.InstanceId}, nil
+}
+
+func (a *api) GetWorkflowAlpha1(ctx context.Context, in *runtimev1pb.GetWorkflowRequest) (*runtimev1pb.GetWorkflowResponse, error) {
+	state, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId)
+	if err != nil {
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.GetWorkflowResponse{}, err
+	}
+
+	return &runtimev1pb.GetWorkflowResponse{
+		InstanceId:     state.InstanceID,
+		WorkflowName:   state.WorkflowName,
+		RuntimeStatus:  string(state.Status),
+		CreatedAt:      timestamppb.New(state.CreatedAt),
+		LastUpdatedAt:  timestamppb.New(state.LastUpdatedAt),
+		Input:          state.Input,
+		Output:         state.Output,
+		CustomStatus:   state.CustomStatus,
+		FailureMessage: state.FailureMessage,
+	}, nil
+}
+
+func (a *api) TerminateWorkflowAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*emptypb.Empty, error) {
+	if _, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId); err != nil {
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+
+	if err := a.workflowEngine.Terminate(ctx, in.InstanceId); err != nil {
+		err = status.Errorf(workflowErrorCode(err), messages.ErrWorkflowTerminate, in.InstanceId, err)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *api) RaiseEventWorkflowAlpha1(ctx context.Context, in *runtimev1pb.RaiseEventWorkflowRequest) (*emptypb.Empty, error) {
+	if in.EventName == "" {
+		err := status.Error(codes.InvalidArgument, messages.ErrWorkflowEventNameMissing)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	if _, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId); err != nil {
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+
+	if err := a.workflowEngine.RaiseEvent(ctx, in.InstanceId, in.EventName, in.EventData); err != nil {
+		err = status.Errorf(workflowErrorCode(err), messages.ErrWorkflowRaiseEvent, in.EventName, in.InstanceId, err)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *api) PauseWorkflowAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*emptypb.Empty, error) {
+	if _, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId); err != nil {
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+
+	if err := a.workflowEngine.Pause(ctx, in.InstanceId); err != nil {
+		err = status.Errorf(workflowErrorCode(err), messages.ErrWorkflowPause, in.InstanceId, err)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *api) ResumeWorkflowAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*emptypb.Empty, error) {
+	if _, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId); err != nil {
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+
+	if err := a.workflowEngine.Resume(ctx, in.InstanceId); err != nil {
+		err = status.Errorf(workflowErrorCode(err), messages.ErrWorkflowResume, in.InstanceId, err)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *api) PurgeWorkflowAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*emptypb.Empty, error) {
+	if _, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId); err != nil {
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+
+	if err := a.workflowEngine.Purge(ctx, in.InstanceId); err != nil {
+		err = status.Errorf(workflowErrorCode(err), messages.ErrWorkflowPurge, in.InstanceId, err)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *api) GetWorkflowPendingAlpha1(ctx context.Context, in *runtimev1pb.WorkflowInstanceRequest) (*runtimev1pb.GetWorkflowPendingResponse, error) {
+	state, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId)
+	if err != nil {
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.GetWorkflowPendingResponse{}, err
+	}
+
+	pending := state.Pending()
+	resp := &runtimev1pb.GetWorkflowPendingResponse{
+		WaitingForEvent: pending.WaitingForEvent,
+		Events:          make([]*runtimev1pb.WorkflowPendingEvent, len(pending.Events)),
+		Timers:          make([]*runtimev1pb.WorkflowTimer, len(pending.Timers)),
+	}
+	for i, ev := range pending.Events {
+		resp.Events[i] = &runtimev1pb.WorkflowPendingEvent{
+			Name:     ev.Name,
+			Data:     ev.Data,
+			RaisedAt: timestamppb.New(ev.Timestamp),
+		}
+	}
+	for i, t := range pending.Timers {
+		resp.Timers[i] = &runtimev1pb.WorkflowTimer{
+			Id:        t.ID,
+			Name:      t.Name,
+			FireAt:    timestamppb.New(t.FireAt),
+			CreatedAt: timestamppb.New(t.CreatedAt),
+			Canceled:  t.Canceled,
+		}
+	}
+	return resp, nil
+}
+
+func (a *api) CancelWorkflowTimerAlpha1(ctx context.Context, in *runtimev1pb.WorkflowTimerRequest) (*emptypb.Empty, error) {
+	if in.TimerId == "" {
+		err := status.Error(codes.InvalidArgument, messages.ErrWorkflowTimerIDMissing)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	if _, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId); err != nil {
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+
+	if err := a.workflowEngine.CancelTimer(ctx, in.InstanceId, in.TimerId); err != nil {
+		err = status.Errorf(workflowErrorCode(err), messages.ErrWorkflowCancelTimer, in.TimerId, in.InstanceId, err)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *api) RescheduleWorkflowTimerAlpha1(ctx context.Context, in *runtimev1pb.RescheduleWorkflowTimerRequest) (*emptypb.Empty, error) {
+	if in.TimerId == "" {
+		err := status.Error(codes.InvalidArgument, messages.ErrWorkflowTimerIDMissing)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	if in.FireAt == nil {
+		err := status.Error(codes.InvalidArgument, messages.ErrWorkflowTimerFireAtMissing)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	if _, err := a.getWorkflowInstance(ctx, in.WorkflowName, in.InstanceId); err != nil {
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+
+	if err := a.workflowEngine.RescheduleTimer(ctx, in.InstanceId, in.TimerId, in.FireAt.AsTime()); err != nil {
+		err = status.Errorf(workflowErrorCode(err), messages.ErrWorkflowRescheduleTimer, in.TimerId, in.InstanceId, err)
+		apiServerLogger.Debug(err)
+		return &emptypb.Empty{}, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *api) getCryptoProvider(name string) (cryptoLoader.SubtleCrypto, error) {
+	if len(a.cryptoProviders) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, messages.ErrCryptoProvidersNotConfigured)
+	}
+	provider, ok := a.cryptoProviders[name]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, messages.ErrCryptoProviderNotFound, name)
+	}
+	return provider, nil
+}
+
+// cryptoStreamReader reads the data of the messages received from a crypto stream.
+type cryptoStreamReader struct {
+	recv func() ([]byte, error)
+	buf  []byte
+}
+
+func (r *cryptoStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		data, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// cryptoStreamWriter sends the data written to it in messages of a crypto stream.
+type cryptoStreamWriter func(data []byte) error
+
+func (w cryptoStreamWriter) Write(p []byte) (int, error) {
+	// The data is copied because the buffer is reused by the caller
+	if err := w(append([]byte(nil), p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (a *api) EncryptAlpha1(stream runtimev1pb.Dapr_EncryptAlpha1Server) error {
+	first, err := stream.Recv()
+	if err != nil {
+		apiServerLogger.Debug(err)
+		return err
+	}
+	opts := first.GetOptions()
+	if opts == nil {
+		err = status.Error(codes.InvalidArgument, messages.ErrCryptoMissingOptions)
+		apiServerLogger.Debug(err)
+		return err
+	}
+	provider, err := a.getCryptoProvider(opts.ComponentName)
+	if err != nil {
+		apiServerLogger.Debug(err)
+		return err
+	}
+
+	in := &cryptoStreamReader{
+		buf: first.Data,
+		recv: func() ([]byte, error) {
+			req, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return req.Data, nil
+		},
+	}
+	out := cryptoStreamWriter(func(data []byte) error {
+		return stream.Send(&runtimev1pb.EncryptResponse{Data: data})
+	})
+
+	err = cryptoLoader.Encrypt(stream.Context(), provider, out, in, cryptoLoader.EncryptOptions{
+		KeyName:          opts.KeyName,
+		KeyWrapAlgorithm: opts.KeyWrapAlgorithm,
+	})
+	if err != nil {
+		err = status.Errorf(codes.Internal, messages.ErrCryptoEncrypt, opts.ComponentName, err)
+		apiServerLogger.Debug(err)
+		return err
+	}
+	return nil
+}
+
+func (a *api) DecryptAlpha1(stream runtimev1pb.Dapr_DecryptAlpha1Server) error {
+	first, err := stream.Recv()
+	if err != nil {
+		apiServerLogger.Debug(err)
+		return err
+	}
+	opts := first.GetOptions()
+	if opts == nil {
+		err = status.Error(codes.InvalidArgument, messages.ErrCryptoMissingOptions)
+		apiServerLogger.Debug(err)
+		return err
+	}
+	provider, err := a.getCryptoProvider(opts.ComponentName)
+	if err != nil {
+		apiServerLogger.Debug(err)
+		return err
+	}
+
+	in := &cryptoStreamReader{
+		buf: first.Data,
+		recv: func() ([]byte, error) {
+			req, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return req.Data, nil
+		},
+	}
+	out := cryptoStreamWriter(func(data []byte) error {
+		return stream.Send(&runtimev1pb.DecryptResponse{Data: data})
+	})
+
+	err = cryptoLoader.Decrypt(stream.Context(), provider, out, in)
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, cryptoLoader.ErrInvalidStream) {
+			code = codes.InvalidArgument
+		}
+		err = status.Errorf(code, messages.ErrCryptoDecrypt, opts.ComponentName, err)
+		apiServerLogger.Debug(err)
+		return err
+	}
+	return nil
+}
+
+func (a *api) SignAlpha1(ctx context.Context, in *runtimev1pb.SignRequest) (*runtimev1pb.SignResponse, error) {
+	provider, err := a.getCryptoProvider(in.ComponentName)
+	if err != nil {
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.SignResponse{}, err
+	}
+
+	signature, err := provider.Sign(ctx, in.Digest, in.Algorithm, in.KeyName)
+	if err != nil {
+		err = status.Errorf(codes.Internal, messages.ErrCryptoSign, in.ComponentName, err)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.SignResponse{}, err
+	}
+	return &runtimev1pb.SignResponse{Signature: signature}, nil
+}
+
+func (a *api) VerifyAlpha1(ctx context.Context, in *runtimev1pb.VerifyRequest) (*runtimev1pb.VerifyResponse, error) {
+	provider, err := a.getCryptoProvider(in.ComponentName)
+	if err != nil {
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.VerifyResponse{}, err
+	}
+
+	valid, err := provider.Verify(ctx, in.Digest, in.Signature, in.Algorithm, in.KeyName)
+	if err != nil {
+		err = status.Errorf(codes.Internal, messages.ErrCryptoVerify, in.ComponentName, err)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.VerifyResponse{}, err
+	}
+	return &runtimev1pb.VerifyResponse{Valid: valid}, nil
+}
+
 func newInternalErrorUnlockResponse() *runtimev1pb.UnlockResponse {
 	return &runtimev1pb.UnlockResponse{
 		Status: runtimev1pb.UnlockResponse_INTERNAL_ERROR, //nolint:nosnakecase
@@ -276,6 +704,7 @@ func NewAPI(
 	secretsConfiguration map[string]config.SecretsScope,
 	configurationStores map[string]configuration.Store,
 	lockStores map[string]lock.Store,
+	cryptoProviders map[string]cryptoLoader.SubtleCrypto,
 	pubsubAdapter runtimePubsub.Adapter,
 	directMessaging messaging.DirectMessaging,
 	actor actors.Actors,
@@ -306,6 +735,7 @@ func NewAPI(
 		configurationStores:        configurationStores,
 		configurationSubscribe:     make(map[string]chan struct{}),
 		lockStores:                 lockStores,
+		cryptoProviders:            cryptoProviders,
 		secretsConfiguration:       secretsConfiguration,
 		sendToOutputBindingFn:      sendToOutputBindingFn,
 		tracingSpec:                tracingSpec,
@@ -315,6 +745,7 @@ func NewAPI(
 		getComponentsFn:            getComponentsFn,
 		getComponentsCapabilitesFn: getComponentsCapabilitiesFn,
 		daprRunTimeVersion:         version.Version(),
+		extendedMetadata:           meta.NewAttributeStore(),
 	}
 }
 
@@ -329,6 +760,10 @@ func (a *api) CallLocal(ctx context.Context, in *internalv1pb.InternalInvokeRequ
 		return nil, status.Errorf(codes.InvalidArgument, messages.ErrInternalInvokeRequest, err.Error())
 	}
 
+	if callAllowed, errMsg := acl.ApplyCrossNamespacePolicy(ctx, a.id, a.crossNamespacePolicy); !callAllowed {
+		return nil, status.Error(codes.PermissionDenied, errMsg)
+	}
+
 	if a.accessControlList != nil {
 		// An access control policy has been specified for the app. Apply the policies.
 		operation := req.Message().Method
@@ -362,6 +797,10 @@ func (a *api) CallActor(ctx context.Context, in *internalv1pb.InternalInvokeRequ
 		return nil, status.Errorf(codes.InvalidArgument, messages.ErrInternalInvokeRequest, err.Error())
 	}
 
+	if callAllowed, errMsg := acl.ApplyCrossNamespacePolicy(ctx, a.id, a.crossNamespacePolicy); !callAllowed {
+		return nil, status.Error(codes.PermissionDenied, errMsg)
+	}
+
 	// We don't do resiliency here as it is handled in the API layer. See InvokeActor().
 	resp, err := a.actor.Call(ctx, req)
 	if err != nil {
@@ -423,6 +862,7 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 	}
 
 	data := body
+	var schema string
 
 	if !rawPayload {
 		envelope, err := runtimePubsub.NewCloudEvent(&runtimePubsub.CloudEvent{
@@ -432,6 +872,7 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 			Data:            body,
 			TraceID:         corID,
 			TraceState:      traceState,
+			Baggage:         baggageFromContext(ctx),
 			Pubsub:          in.PubsubName,
 		})
 		if err != nil {
@@ -442,6 +883,8 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 
 		features := thepubsub.Features()
 		pubsub.ApplyMetadata(envelope, features, in.Metadata)
+		runtimePubsub.ApplyCloudEventOverrides(envelope, in.Metadata)
+		schema = runtimePubsub.DataSchema(envelope)
 
 		data, err = json.Marshal(envelope)
 		if err != nil {
@@ -463,6 +906,8 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 	elapsed := diag.ElapsedSince(start)
 
 	diag.DefaultComponentMonitoring.PubsubEgressEvent(context.Background(), pubsubName, topic, err == nil, elapsed)
+	diag.DefaultAPILatencyMonitoring.PublishLatency(ctx, pubsubName, err == nil, elapsed)
+	diag.DefaultComponentMonitoring.PubsubEgressPayload(context.Background(), pubsubName, topic, schema, len(data))
 
 	if err != nil {
 		nerr := status.Errorf(codes.Internal, messages.ErrPubsubPublishMessage, topic, pubsubName, err.Error())
@@ -480,6 +925,12 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 	return &emptypb.Empty{}, nil
 }
 
+// baggageFromContext returns the W3C baggage of the incoming request, or an empty string if it isn't set.
+func baggageFromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return strings.Join(md.Get(baggageMetadata), ",")
+}
+
 func (a *api) InvokeService(ctx context.Context, in *runtimev1pb.InvokeServiceRequest) (*commonv1pb.InvokeResponse, error) {
 	req := invokev1.FromInvokeRequestMessage(in.GetMessage())
 
@@ -516,9 +967,12 @@ func (a *api) InvokeService(ctx context.Context, in *runtimev1pb.InvokeServiceRe
 			respError = invokev1.ErrorFromHTTPResponseCode(int(resp.Status().Code), string(errorMessage))
 			// Populate http status code to header
 			headerMD.Set(daprHTTPStatusHeader, strconv.Itoa(int(resp.Status().Code)))
+			// Forward the HTTP trailers of the app as gRPC trailers
+			if len(resp.Trailers()) > 0 {
+				grpc.SetTrailer(ctx, invokev1.InternalMetadataToGrpcMetadata(ctx, resp.Trailers(), true))
+			}
 		} else {
 			respError = invokev1.ErrorFromInternalStatus(resp.Status())
-			// ignore trailer if appchannel uses HTTP
 			grpc.SetTrailer(ctx, invokev1.InternalMetadataToGrpcMetadata(ctx, resp.Trailers(), false))
 		}
 
@@ -549,6 +1003,7 @@ func (a *api) InvokeBinding(ctx context.Context, in *runtimev1pb.InvokeBindingRe
 	elapsed := diag.ElapsedSince(start)
 
 	diag.DefaultComponentMonitoring.OutputBindingEvent(context.Background(), in.Name, in.Operation, err == nil, elapsed)
+	diag.DefaultComponentMonitoring.OutputBindingPayload(context.Background(), in.Name, in.Operation, in.Metadata[diag.PayloadSchemaMetadataKey], len(req.Data))
 
 	if err != nil {
 		err = status.Errorf(codes.Internal, messages.ErrInvokeOutputBinding, in.Name, err.Error())
@@ -708,6 +1163,7 @@ func (a *api) GetState(ctx context.Context, in *runtimev1pb.GetStateRequest) (*r
 	elapsed := diag.ElapsedSince(start)
 
 	diag.DefaultComponentMonitoring.StateInvoked(ctx, in.StoreName, diag.Get, err == nil, elapsed)
+	diag.DefaultAPILatencyMonitoring.StateLatency(ctx, in.StoreName, diag.Get, err == nil, elapsed)
 
 	if err != nil {
 		err = status.Errorf(codes.Internal, messages.ErrStateGet, in.Key, in.StoreName, err.Error())
@@ -786,11 +1242,12 @@ func (a *api) SaveState(ctx context.Context, in *runtimev1pb.SaveStateRequest) (
 	start := time.Now()
 	policy := a.resiliency.ComponentOutboundPolicy(ctx, in.StoreName, resiliency.Statestore)
 	err = policy(func(ctx context.Context) error {
-		return store.BulkSet(reqs)
+		return statemerge.BulkSet(ctx, store, a.appChannel, in.StoreName, reqs, store.BulkSet)
 	})
 	elapsed := diag.ElapsedSince(start)
 
 	diag.DefaultComponentMonitoring.StateInvoked(ctx, in.StoreName, diag.Set, err == nil, elapsed)
+	diag.DefaultAPILatencyMonitoring.StateLatency(ctx, in.StoreName, diag.Set, err == nil, elapsed)
 
 	if err != nil {
 		err = a.stateErrorResponse(err, messages.ErrStateSave, in.StoreName, err.Error())
@@ -1055,6 +1512,9 @@ func (a *api) GetBulkSecret(ctx context.Context, in *runtimev1pb.GetBulkSecretRe
 
 	filteredSecrets := map[string]map[string]string{}
 	for key, v := range getResponse.Data {
+		if !strings.HasPrefix(key, in.Prefix) {
+			continue
+		}
 		if a.isSecretAllowed(secretStoreName, key) {
 			filteredSecrets[key] = v
 		} else {
@@ -1422,6 +1882,11 @@ func (a *api) InvokeActor(ctx context.Context, in *runtimev1pb.InvokeActorReques
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.InvokeActorResponse{}, err
 	}
+	if actors.IsInternalActorType(in.ActorType) {
+		err := status.Errorf(codes.InvalidArgument, messages.ErrActorTypeInternal, in.ActorType)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.InvokeActorResponse{}, err
+	}
 
 	req := invokev1.NewInvokeMethodRequest(in.Method)
 	req.WithActor(in.ActorType, in.ActorId)
@@ -1472,13 +1937,37 @@ func (a *api) SetActorRuntime(actor actors.Actors) {
 	a.actor = actor
 }
 
+func (a *api) SetRuntimeMetadataFn(getRuntimeMetadataFn func() meta.Info) {
+	a.getRuntimeMetadataFn = getRuntimeMetadataFn
+}
+
+func (a *api) SetMetadataAttributes(attributes *meta.AttributeStore) {
+	a.extendedMetadata = attributes
+}
+
+// SetCrossNamespacePolicy sets the policy of the service invocations from the apps of other namespaces.
+func (a *api) SetCrossNamespacePolicy(policy *acl.CrossNamespacePolicy) {
+	a.crossNamespacePolicy = policy
+}
+
 func (a *api) GetMetadata(ctx context.Context, in *emptypb.Empty) (*runtimev1pb.GetMetadataResponse, error) {
 	extendedMetadata := make(map[string]string)
-	// Copy synchronously so it can be serialized to JSON.
-	a.extendedMetadata.Range(func(key, value interface{}) bool {
-		extendedMetadata[key.(string)] = value.(string)
-		return true
-	})
+	var metadataAttributes []*runtimev1pb.MetadataAttribute
+	for _, attr := range a.extendedMetadata.List() {
+		extendedMetadata[attr.Key] = attr.Value
+		if len(attr.Labels) == 0 && attr.ExpiresAt.IsZero() {
+			continue
+		}
+		mtdAttr := &runtimev1pb.MetadataAttribute{
+			Key:    attr.Key,
+			Value:  attr.Value,
+			Labels: attr.Labels,
+		}
+		if !attr.ExpiresAt.IsZero() {
+			mtdAttr.ExpireTime = timestamppb.New(attr.ExpiresAt)
+		}
+		metadataAttributes = append(metadataAttributes, mtdAttr)
+	}
 	extendedMetadata[daprRuntimeVersionKey] = a.daprRunTimeVersion
 
 	activeActorsCount := []*runtimev1pb.ActiveActorsCount{}
@@ -1509,11 +1998,101 @@ func (a *api) GetMetadata(ctx context.Context, in *emptypb.Empty) (*runtimev1pb.
 		ExtendedMetadata:     extendedMetadata,
 		RegisteredComponents: registeredComponents,
 		ActiveActorsCount:    activeActorsCount,
+		Attributes:           metadataAttributes,
+	}
+	if a.getRuntimeMetadataFn != nil {
+		addRuntimeMetadata(response, a.getRuntimeMetadataFn())
 	}
 
 	return response, nil
 }
 
+// addRuntimeMetadata adds the status of the components, the subscriptions, the actor types, the app connection, the enabled features
+// and the drift of the loaded resources to the response.
+// Components that failed to initialize are listed with the registered ones.
+func addRuntimeMetadata(response *runtimev1pb.GetMetadataResponse, info meta.Info) {
+	for _, status := range info.ComponentStatuses {
+		found := false
+		for _, comp := range response.RegisteredComponents {
+			if comp.Name == status.Name {
+				comp.Status = status.Status
+				comp.LastError = status.LastError
+				comp.LastErrorClass = string(status.ErrorClass)
+				found = true
+				break
+			}
+		}
+		if !found && status.Status != meta.ComponentStatusInitialized {
+			response.RegisteredComponents = append(response.RegisteredComponents, &runtimev1pb.RegisteredComponents{
+				Name:           status.Name,
+				Type:           status.Type,
+				Version:        status.Version,
+				Capabilities:   []string{},
+				Status:         status.Status,
+				LastError:      status.LastError,
+				LastErrorClass: string(status.ErrorClass),
+			})
+		}
+	}
+
+	for _, s := range info.Subscriptions {
+		sub := &runtimev1pb.PubsubSubscription{
+			PubsubName:      s.PubsubName,
+			Topic:           s.Topic,
+			Metadata:        s.Metadata,
+			DeadLetterTopic: s.DeadLetterTopic,
+		}
+		for _, r := range s.Rules {
+			rule := &runtimev1pb.PubsubSubscriptionRule{Path: r.Path}
+			if r.Match != nil {
+				rule.Match = fmt.Sprint(r.Match)
+			}
+			sub.Rules = append(sub.Rules, rule)
+		}
+		response.Subscriptions = append(response.Subscriptions, sub)
+	}
+
+	response.ActorTypes = info.ActorTypes
+	response.EnabledFeatures = info.EnabledFeatures
+
+	response.AppConnectionProperties = &runtimev1pb.AppConnectionProperties{
+		Port:           int32(info.AppConnection.Port),
+		Protocol:       info.AppConnection.Protocol,
+		MaxConcurrency: int32(info.AppConnection.MaxConcurrency),
+	}
+	if health := info.AppConnection.Health; health != nil {
+		response.AppConnectionProperties.Health = &runtimev1pb.AppConnectionHealthProperties{
+			HealthCheckPath:     health.HealthCheckPath,
+			HealthProbeInterval: health.HealthProbeInterval.String(),
+			HealthProbeTimeout:  health.HealthProbeTimeout.String(),
+			HealthThreshold:     health.HealthThreshold,
+			Status:              health.Status,
+		}
+	}
+
+	if drift := info.Drift; drift != nil {
+		response.Drift = &runtimev1pb.MetadataDrift{
+			Detected:      drift.Detected(),
+			Components:    drift.Components,
+			Configuration: drift.Configuration,
+			LastCheckTime: timestamppb.New(drift.LastChecked),
+		}
+	}
+
+	if crossNamespace := info.CrossNamespace; crossNamespace != nil {
+		response.CrossNamespacePolicy = &runtimev1pb.MetadataCrossNamespacePolicy{
+			AllowedCallers: make([]*runtimev1pb.MetadataCrossNamespaceCaller, len(crossNamespace.AllowedCallers)),
+		}
+		for i, caller := range crossNamespace.AllowedCallers {
+			response.CrossNamespacePolicy.AllowedCallers[i] = &runtimev1pb.MetadataCrossNamespaceCaller{
+				Namespace: caller.Namespace,
+				AppId:     caller.AppID,
+				Callees:   caller.Callees,
+			}
+		}
+	}
+}
+
 func getOrDefaultCapabilities(dict map[string][]string, key string) []string {
 	if val, ok := dict[key]; ok {
 		return val
@@ -1521,9 +2100,14 @@ func getOrDefaultCapabilities(dict map[string][]string, key string) []string {
 	return make([]string, 0)
 }
 
-// SetMetadata Sets value in extended metadata of the sidecar.
+// SetMetadata sets an attribute in the extended metadata of the sidecar, with an optional TTL and labels.
 func (a *api) SetMetadata(ctx context.Context, in *runtimev1pb.SetMetadataRequest) (*emptypb.Empty, error) {
-	a.extendedMetadata.Store(in.Key, in.Value)
+	if in.TtlInSeconds < 0 {
+		return &emptypb.Empty{}, status.Errorf(codes.InvalidArgument, messages.ErrMetadataAttributeSet, in.Key, "the TTL must not be negative")
+	}
+	if err := a.extendedMetadata.Set(in.Key, in.Value, time.Duration(in.TtlInSeconds)*time.Second, in.Labels); err != nil {
+		return &emptypb.Empty{}, status.Errorf(codes.InvalidArgument, messages.ErrMetadataAttributeSet, in.Key, err)
+	}
 	return &emptypb.Empty{}, nil
 }
 
@@ -1536,6 +2120,30 @@ func (a *api) Shutdown(ctx context.Context, in *emptypb.Empty) (*emptypb.Empty,
 	return &emptypb.Empty{}, nil
 }
 
+// SetLogLevelAlpha1 changes the log level and the logging of the API calls of the sidecar, without restarting it.
+func (a *api) SetLogLevelAlpha1(ctx context.Context, in *runtimev1pb.SetLogLevelRequest) (*runtimev1pb.SetLogLevelResponse, error) {
+	ttl := time.Duration(in.TtlInSeconds) * time.Second
+	if in.TtlInSeconds < 0 || ttl > diag.MaxRuntimeLoggingDuration {
+		return &runtimev1pb.SetLogLevelResponse{}, status.Errorf(codes.InvalidArgument, messages.ErrLogLevelTTL, ttl, diag.MaxRuntimeLoggingDuration)
+	}
+	s, err := diag.DefaultRuntimeLogging.Set(in.Level, in.ApiLogging, ttl)
+	if err != nil {
+		return &runtimev1pb.SetLogLevelResponse{}, status.Errorf(codes.InvalidArgument, messages.ErrLogLevelInvalid, err)
+	}
+	if in.Level != "" || in.ApiLogging != nil {
+		apiServerLogger.Infof("log level set to: %s, API logging enabled: %t", s.Level, s.APILogging)
+	}
+
+	res := &runtimev1pb.SetLogLevelResponse{
+		Level:      s.Level,
+		ApiLogging: s.APILogging,
+	}
+	if s.RevertAt != nil {
+		res.RevertTime = timestamppb.New(*s.RevertAt)
+	}
+	return res, nil
+}
+
 func stringValueOrEmpty(value *string) string {
 	if value == nil {
 		return ""
@@ -1637,6 +2245,13 @@ func (a *api) SubscribeConfigurationAlpha1(request *runtimev1pb.SubscribeConfigu
 
 	subscribeKeys := make([]string, 0)
 
+	resyncInterval, metadata, err := runtimeConfiguration.SubscribeRequestOptions(request.GetMetadata())
+	if err != nil {
+		err = status.Errorf(codes.InvalidArgument, messages.ErrConfigurationResyncInterval, err)
+		apiServerLogger.Debug(err)
+		return err
+	}
+
 	// TODO(@halspang) provide a switch to use just resiliency or this.
 	newCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1646,7 +2261,7 @@ func (a *api) SubscribeConfigurationAlpha1(request *runtimev1pb.SubscribeConfigu
 		getConfigurationReq := &runtimev1pb.GetConfigurationRequest{
 			StoreName: request.StoreName,
 			Keys:      []string{},
-			Metadata:  request.GetMetadata(),
+			Metadata:  metadata,
 		}
 		resp, err2 := a.GetConfigurationAlpha1(newCtx, getConfigurationReq)
 		if err2 != nil {
@@ -1665,11 +2280,6 @@ func (a *api) SubscribeConfigurationAlpha1(request *runtimev1pb.SubscribeConfigu
 		subscribeKeys = append(subscribeKeys, request.Keys...)
 	}
 
-	req := &configuration.SubscribeRequest{
-		Keys:     subscribeKeys,
-		Metadata: request.GetMetadata(),
-	}
-
 	handler := &configurationEventHandler{
 		api:          a,
 		storeName:    request.StoreName,
@@ -1679,9 +2289,16 @@ func (a *api) SubscribeConfigurationAlpha1(request *runtimev1pb.SubscribeConfigu
 	// TODO(@laurence) deal with failed subscription and retires
 	start := time.Now()
 	policy := a.resiliency.ComponentOutboundPolicy(newCtx, request.StoreName, resiliency.Configuration)
-	var id string
+	var sub *runtimeConfiguration.Subscription
 	err = policy(func(ctx context.Context) (rErr error) {
-		id, rErr = store.Subscribe(ctx, req, handler.updateEventHandler)
+		sub, rErr = runtimeConfiguration.Subscribe(ctx, runtimeConfiguration.SubscribeOptions{
+			Store:          store,
+			StoreName:      request.StoreName,
+			Keys:           subscribeKeys,
+			Metadata:       metadata,
+			ResyncInterval: resyncInterval,
+			Handler:        handler.updateEventHandler,
+		})
 		return rErr
 	})
 	elapsed := diag.ElapsedSince(start)
@@ -1689,15 +2306,29 @@ func (a *api) SubscribeConfigurationAlpha1(request *runtimev1pb.SubscribeConfigu
 	diag.DefaultComponentMonitoring.ConfigurationInvoked(context.Background(), request.StoreName, diag.ConfigurationSubscribe, err == nil, elapsed)
 
 	if err != nil {
-		err = status.Errorf(codes.InvalidArgument, messages.ErrConfigurationSubscribe, req.Keys, request.StoreName, err.Error())
+		err = status.Errorf(codes.InvalidArgument, messages.ErrConfigurationSubscribe, subscribeKeys, request.StoreName, err.Error())
 		apiServerLogger.Debug(err)
 		return err
 	}
+	id := sub.ID()
 	stop := make(chan struct{})
 	a.configurationSubscribeLock.Lock()
 	a.configurationSubscribe[id] = stop
 	a.configurationSubscribeLock.Unlock()
-	<-stop
+
+	select {
+	case <-stop:
+		// Unsubscribed from the store by UnsubscribeConfigurationAlpha1
+		sub.Stop()
+	case <-configurationServer.Context().Done():
+		// The app closed the stream
+		a.configurationSubscribeLock.Lock()
+		delete(a.configurationSubscribe, id)
+		a.configurationSubscribeLock.Unlock()
+		if err := sub.Close(context.Background()); err != nil {
+			apiServerLogger.Debugf("error unsubscribing configuration subscription %s: %s", id, err)
+		}
+	}
 	return nil
 }
 
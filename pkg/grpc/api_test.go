@@ -14,7 +14,11 @@ limitations under the License.
 package grpc
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -33,6 +37,7 @@ import (
 	"github.com/phayes/freeport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace"
 	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
@@ -43,16 +48,22 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/dapr/components-contrib/bindings"
 	"github.com/dapr/components-contrib/configuration"
+	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/pubsub"
 	"github.com/dapr/components-contrib/secretstores"
 	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/dapr/pkg/acl"
 	"github.com/dapr/dapr/pkg/actors"
 	componentsV1alpha "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	"github.com/dapr/dapr/pkg/apis/resiliency/v1alpha1"
 	channelt "github.com/dapr/dapr/pkg/channel/testing"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
+	"github.com/dapr/dapr/pkg/components/crypto/localkeys"
+	lockLoader "github.com/dapr/dapr/pkg/components/lock"
 	stateLoader "github.com/dapr/dapr/pkg/components/state"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
@@ -64,7 +75,9 @@ import (
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	"github.com/dapr/dapr/pkg/resiliency"
+	"github.com/dapr/dapr/pkg/runtime/meta"
 	runtimePubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	"github.com/dapr/dapr/pkg/runtime/workflow"
 	daprt "github.com/dapr/dapr/pkg/testing"
 	testtrace "github.com/dapr/dapr/pkg/testing/trace"
 	"github.com/dapr/kit/logger"
@@ -324,6 +337,31 @@ func TestCallActorWithTracing(t *testing.T) {
 	assert.NotEmpty(t, resp.GetMessage(), "failed to generate trace context with actor call")
 }
 
+func TestCallActorCrossNamespacePolicy(t *testing.T) {
+	port, _ := freeport.GetFreePort()
+
+	policy, err := acl.ParseCrossNamespaceSpec(&config.CrossNamespaceSpec{}, "default", "public")
+	require.NoError(t, err)
+	mockActors := new(actors.MockActors)
+	fakeAPI := &api{
+		id:                   "fakeAPI",
+		actor:                mockActors,
+		crossNamespacePolicy: policy,
+	}
+	server := startInternalServer(port, fakeAPI)
+	defer server.Stop()
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+
+	client := internalv1pb.NewServiceInvocationClient(clientConn)
+	request := invokev1.NewInvokeMethodRequest("method")
+	request.WithActor("test-actor", "actor-1")
+
+	_, err = client.CallActor(context.Background(), request.Proto())
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	mockActors.AssertNotCalled(t, "Call", mock.Anything, mock.Anything)
+}
+
 func TestCallRemoteAppWithTracing(t *testing.T) {
 	port, _ := freeport.GetFreePort()
 
@@ -403,6 +441,30 @@ func TestCallLocal(t *testing.T) {
 		_, err := client.CallLocal(context.Background(), request)
 		assert.Equal(t, codes.Internal, status.Code(err))
 	})
+
+	t.Run("cross-namespace policy denies unidentified callers", func(t *testing.T) {
+		port, _ := freeport.GetFreePort()
+
+		policy, err := acl.ParseCrossNamespaceSpec(&config.CrossNamespaceSpec{}, "default", "public")
+		require.NoError(t, err)
+		mockAppChannel := new(channelt.MockAppChannel)
+		fakeAPI := &api{
+			id:                   "fakeAPI",
+			appChannel:           mockAppChannel,
+			crossNamespacePolicy: policy,
+		}
+		server := startInternalServer(port, fakeAPI)
+		defer server.Stop()
+		clientConn := createTestClient(port)
+		defer clientConn.Close()
+
+		client := internalv1pb.NewServiceInvocationClient(clientConn)
+		request := invokev1.NewInvokeMethodRequest("method").Proto()
+
+		_, err = client.CallLocal(context.Background(), request)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockAppChannel.AssertNotCalled(t, "InvokeMethod", mock.Anything, mock.Anything)
+	})
 }
 
 func mustMarshalAny(msg proto.Message) *anypb.Any {
@@ -937,6 +999,22 @@ func TestGetBulkSecret(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Prefix filter", func(t *testing.T) {
+		resp, err := client.GetBulkSecret(context.Background(), &runtimev1pb.GetBulkSecretRequest{
+			StoreName: "store1",
+			Prefix:    "good",
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, resp.Data, goodKey)
+
+		resp, err = client.GetBulkSecret(context.Background(), &runtimev1pb.GetBulkSecretRequest{
+			StoreName: "store1",
+			Prefix:    "other",
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Data)
+	})
 }
 
 func TestGetStateWhenStoreNotConfigured(t *testing.T) {
@@ -1263,6 +1341,8 @@ func TestGetConfiguration(t *testing.T) {
 func TestSubscribeConfiguration(t *testing.T) {
 	fakeConfigurationStore := &daprt.MockConfigurationStore{}
 	var tempReq *configuration.SubscribeRequest
+	// Invoked when the app closes the stream
+	fakeConfigurationStore.On("Unsubscribe", mock.Anything, mock.Anything).Return(nil)
 	fakeConfigurationStore.On("Subscribe",
 		mock.AnythingOfType("*context.cancelCtx"),
 		mock.MatchedBy(func(req *configuration.SubscribeRequest) bool {
@@ -1421,6 +1501,8 @@ func TestUnSubscribeConfiguration(t *testing.T) {
 		mock.MatchedBy(func(req *configuration.UnsubscribeRequest) bool {
 			return true
 		})).Return(nil)
+	// Invoked when the app closes the stream
+	fakeConfigurationStore.On("Unsubscribe", mock.Anything, mock.Anything).Return(nil)
 	fakeConfigurationStore.On("Subscribe",
 		mock.AnythingOfType("*context.cancelCtx"),
 		mock.MatchedBy(func(req *configuration.SubscribeRequest) bool {
@@ -1777,9 +1859,12 @@ func TestDeleteState(t *testing.T) {
 func TestPublishTopic(t *testing.T) {
 	port, _ := freeport.GetFreePort()
 
+	var published []byte
 	srv := &api{
 		pubsubAdapter: &daprt.MockPubSubAdapter{
 			PublishFn: func(req *pubsub.PublishRequest) error {
+				published = req.Data
+
 				if req.Topic == "error-topic" {
 					return errors.New("error when publish")
 				}
@@ -1838,6 +1923,19 @@ func TestPublishTopic(t *testing.T) {
 		Topic:      "err-not-allowed",
 	})
 	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	t.Run("the baggage is propagated in the cloudevent", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "baggage", "team=payments")
+		_, err := client.PublishEvent(ctx, &runtimev1pb.PublishEventRequest{
+			PubsubName: "pubsub",
+			Topic:      "topic",
+			Data:       []byte("hello"),
+		})
+		require.NoError(t, err)
+		var ce map[string]interface{}
+		require.NoError(t, json.Unmarshal(published, &ce))
+		assert.Equal(t, "team=payments", runtimePubsub.Baggage(ce))
+	})
 }
 
 func TestShutdownEndpoints(t *testing.T) {
@@ -2058,7 +2156,8 @@ func TestGetMetadata(t *testing.T) {
 			return capsMap
 		},
 	}
-	fakeAPI.extendedMetadata.Store("testKey", "testValue")
+	fakeAPI.extendedMetadata = meta.NewAttributeStore()
+	fakeAPI.extendedMetadata.Set("testKey", "testValue", 0, nil)
 	server := startDaprAPIServer(port, fakeAPI, "")
 	defer server.Stop()
 
@@ -2079,12 +2178,89 @@ func TestGetMetadata(t *testing.T) {
 	assert.Equal(t, response.GetActiveActorsCount()[0].Count, int32(10))
 }
 
+func TestGetMetadataWithRuntimeInfo(t *testing.T) {
+	fakeComponent := componentsV1alpha.Component{}
+	fakeComponent.Name = "testComponent"
+
+	fakeAPI := &api{
+		id:               "fakeAPI",
+		extendedMetadata: meta.NewAttributeStore(),
+		getComponentsFn: func() []componentsV1alpha.Component {
+			return []componentsV1alpha.Component{fakeComponent}
+		},
+		getComponentsCapabilitesFn: func() map[string][]string {
+			return map[string][]string{}
+		},
+		getRuntimeMetadataFn: func() meta.Info {
+			return meta.Info{
+				ComponentStatuses: []meta.ComponentStatus{
+					{Name: "failedComponent", Type: "state.fake", Version: "v1", Status: meta.ComponentStatusFailed, LastError: "init error"},
+					{Name: "testComponent", Status: meta.ComponentStatusInitialized},
+				},
+				Subscriptions: []runtimePubsub.Subscription{
+					{PubsubName: "pubsub", Topic: "topic1", Rules: []*runtimePubsub.Rule{{Path: "/orders"}}},
+				},
+				ActorTypes: []string{"abcd"},
+				AppConnection: meta.AppConnection{
+					Port:           50001,
+					Protocol:       "grpc",
+					MaxConcurrency: 10,
+				},
+				EnabledFeatures: []string{"Resiliency"},
+				Drift: &meta.Drift{
+					Components:  []string{"testComponent"},
+					LastChecked: time.Unix(1000, 0),
+				},
+				CrossNamespace: &config.CrossNamespaceSpec{
+					AllowedCallers: []config.CrossNamespaceCaller{{Namespace: "frontend", Callees: []string{"orders"}}},
+				},
+			}
+		},
+	}
+
+	response, err := fakeAPI.GetMetadata(context.Background(), &emptypb.Empty{})
+	assert.NoError(t, err)
+	assert.Len(t, response.RegisteredComponents, 2)
+	assert.Equal(t, "testComponent", response.RegisteredComponents[0].Name)
+	assert.Equal(t, meta.ComponentStatusInitialized, response.RegisteredComponents[0].Status)
+	assert.Equal(t, "failedComponent", response.RegisteredComponents[1].Name)
+	assert.Equal(t, meta.ComponentStatusFailed, response.RegisteredComponents[1].Status)
+	assert.Equal(t, "init error", response.RegisteredComponents[1].LastError)
+	assert.Len(t, response.Subscriptions, 1)
+	assert.Equal(t, "topic1", response.Subscriptions[0].Topic)
+	assert.Equal(t, "/orders", response.Subscriptions[0].Rules[0].Path)
+	assert.Equal(t, []string{"abcd"}, response.ActorTypes)
+	assert.Equal(t, int32(50001), response.AppConnectionProperties.Port)
+	assert.Equal(t, "grpc", response.AppConnectionProperties.Protocol)
+	assert.Equal(t, int32(10), response.AppConnectionProperties.MaxConcurrency)
+	assert.Nil(t, response.AppConnectionProperties.Health)
+	assert.Equal(t, []string{"Resiliency"}, response.EnabledFeatures)
+	if assert.NotNil(t, response.Drift) {
+		assert.True(t, response.Drift.Detected)
+		assert.Equal(t, []string{"testComponent"}, response.Drift.Components)
+		assert.False(t, response.Drift.Configuration)
+		assert.Equal(t, int64(1000), response.Drift.LastCheckTime.GetSeconds())
+	}
+	if assert.NotNil(t, response.CrossNamespacePolicy) && assert.Len(t, response.CrossNamespacePolicy.AllowedCallers, 1) {
+		assert.Equal(t, "frontend", response.CrossNamespacePolicy.AllowedCallers[0].Namespace)
+		assert.Empty(t, response.CrossNamespacePolicy.AllowedCallers[0].AppId)
+		assert.Equal(t, []string{"orders"}, response.CrossNamespacePolicy.AllowedCallers[0].Callees)
+	}
+}
+
 func TestSetMetadata(t *testing.T) {
 	port, _ := freeport.GetFreePort()
 	fakeComponent := componentsV1alpha.Component{}
 	fakeComponent.Name = "testComponent"
 	fakeAPI := &api{
-		id: "fakeAPI",
+		id:               "fakeAPI",
+		extendedMetadata: meta.NewAttributeStore(),
+		getComponentsFn: func() []componentsV1alpha.Component {
+			return nil
+		},
+		getComponentsCapabilitesFn: func() map[string][]string {
+			return nil
+		},
 	}
 	server := startDaprAPIServer(port, fakeAPI, "")
 	defer server.Stop()
@@ -2101,14 +2277,83 @@ func TestSetMetadata(t *testing.T) {
 	assert.NoError(t, err, "Expected no error")
 	temp := make(map[string]string)
 
-	// Copy synchronously so it can be serialized to JSON.
-	fakeAPI.extendedMetadata.Range(func(key, value interface{}) bool {
-		temp[key.(string)] = value.(string)
-		return true
-	})
+	for _, attr := range fakeAPI.extendedMetadata.List() {
+		temp[attr.Key] = attr.Value
+	}
 
 	assert.Contains(t, temp, "testKey")
 	assert.Equal(t, temp["testKey"], "testValue")
+
+	t.Run("attribute with TTL and labels", func(t *testing.T) {
+		_, err := client.SetMetadata(context.Background(), &runtimev1pb.SetMetadataRequest{
+			Key:          "build",
+			Value:        "abc123",
+			TtlInSeconds: 60,
+			Labels:       map[string]string{"source": "ci"},
+		})
+		assert.NoError(t, err)
+
+		response, err := client.GetMetadata(context.Background(), &emptypb.Empty{})
+		assert.NoError(t, err)
+		assert.Equal(t, "abc123", response.ExtendedMetadata["build"])
+		if assert.Len(t, response.Attributes, 1) {
+			assert.Equal(t, "build", response.Attributes[0].Key)
+			assert.Equal(t, map[string]string{"source": "ci"}, response.Attributes[0].Labels)
+			assert.NotNil(t, response.Attributes[0].ExpireTime)
+		}
+	})
+
+	t.Run("negative TTL", func(t *testing.T) {
+		_, err := client.SetMetadata(context.Background(), &runtimev1pb.SetMetadataRequest{
+			Key:          "build",
+			Value:        "abc123",
+			TtlInSeconds: -1,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestSetLogLevel(t *testing.T) {
+	port, _ := freeport.GetFreePort()
+	server := startDaprAPIServer(port, &api{id: "fakeAPI"}, "")
+	defer server.Stop()
+	defer diag.DefaultRuntimeLogging.Reset()
+
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+
+	client := runtimev1pb.NewDaprClient(clientConn)
+
+	t.Run("invalid level", func(t *testing.T) {
+		_, err := client.SetLogLevelAlpha1(context.Background(), &runtimev1pb.SetLogLevelRequest{Level: "verbose"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("negative TTL", func(t *testing.T) {
+		_, err := client.SetLogLevelAlpha1(context.Background(), &runtimev1pb.SetLogLevelRequest{Level: "debug", TtlInSeconds: -1})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("set with TTL", func(t *testing.T) {
+		apiLogging := true
+		res, err := client.SetLogLevelAlpha1(context.Background(), &runtimev1pb.SetLogLevelRequest{
+			Level:        "debug",
+			ApiLogging:   &apiLogging,
+			TtlInSeconds: 600,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "debug", res.Level)
+		assert.True(t, res.ApiLogging)
+		assert.NotNil(t, res.RevertTime)
+	})
+
+	t.Run("empty request returns the current level", func(t *testing.T) {
+		res, err := client.SetLogLevelAlpha1(context.Background(), &runtimev1pb.SetLogLevelRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, "debug", res.Level)
+		assert.True(t, res.ApiLogging)
+		assert.NotNil(t, res.RevertTime)
+	})
 }
 
 func TestStateStoreErrors(t *testing.T) {
@@ -3133,7 +3378,7 @@ func TestUnlockResponseToGrpcResponse(t *testing.T) {
 
 func TestTryLock(t *testing.T) {
 	t.Run("error when lock store not configured", func(t *testing.T) {
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 		req := &runtimev1pb.TryLockRequest{
 			StoreName: "abc",
 		}
@@ -3146,7 +3391,7 @@ func TestTryLock(t *testing.T) {
 		defer ctl.Finish()
 
 		mockLockStore := daprt.NewMockStore(ctl)
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 		req := &runtimev1pb.TryLockRequest{
 			StoreName: "abc",
 		}
@@ -3159,7 +3404,7 @@ func TestTryLock(t *testing.T) {
 		defer ctl.Finish()
 
 		mockLockStore := daprt.NewMockStore(ctl)
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"abc": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"abc": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 		req := &runtimev1pb.TryLockRequest{
 			StoreName:  "abc",
 			ResourceId: "resource",
@@ -3173,7 +3418,7 @@ func TestTryLock(t *testing.T) {
 		defer ctl.Finish()
 
 		mockLockStore := daprt.NewMockStore(ctl)
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"abc": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"abc": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 
 		req := &runtimev1pb.TryLockRequest{
 			StoreName:  "abc",
@@ -3189,7 +3434,7 @@ func TestTryLock(t *testing.T) {
 		defer ctl.Finish()
 
 		mockLockStore := daprt.NewMockStore(ctl)
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 
 		req := &runtimev1pb.TryLockRequest{
 			StoreName:       "abc",
@@ -3215,7 +3460,7 @@ func TestTryLock(t *testing.T) {
 				Success: true,
 			}, nil
 		})
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 		req := &runtimev1pb.TryLockRequest{
 			StoreName:       "mock",
 			ResourceId:      "resource",
@@ -3225,12 +3470,54 @@ func TestTryLock(t *testing.T) {
 		resp, err := api.TryLockAlpha1(context.Background(), req)
 		assert.Nil(t, err)
 		assert.Equal(t, true, resp.Success)
+		assert.Zero(t, resp.FencingToken)
 	})
+
+	t.Run("Success with fencing token", func(t *testing.T) {
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": &mockFencingLockStore{}}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		req := &runtimev1pb.TryLockRequest{
+			StoreName:       "mock",
+			ResourceId:      "resource",
+			LockOwner:       "owner",
+			ExpiryInSeconds: 1,
+		}
+		first, err := api.TryLockAlpha1(context.Background(), req)
+		assert.Nil(t, err)
+		second, err := api.TryLockAlpha1(context.Background(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, true, second.Success)
+		assert.Equal(t, int64(1), first.FencingToken)
+		assert.Equal(t, int64(2), second.FencingToken)
+	})
+}
+
+type mockFencingLockStore struct {
+	token int64
+}
+
+func (m *mockFencingLockStore) InitLockStore(metadata lock.Metadata) error {
+	return nil
+}
+
+func (m *mockFencingLockStore) TryLock(req *lock.TryLockRequest) (*lock.TryLockResponse, error) {
+	return nil, errors.New("TryLockWithFencingToken must be used")
+}
+
+func (m *mockFencingLockStore) TryLockWithFencingToken(req *lock.TryLockRequest) (*lockLoader.TryLockResponse, error) {
+	m.token++
+	return &lockLoader.TryLockResponse{
+		TryLockResponse: lock.TryLockResponse{Success: true},
+		FencingToken:    m.token,
+	}, nil
+}
+
+func (m *mockFencingLockStore) Unlock(req *lock.UnlockRequest) (*lock.UnlockResponse, error) {
+	return &lock.UnlockResponse{}, nil
 }
 
 func TestUnlock(t *testing.T) {
 	t.Run("error when lock store not configured", func(t *testing.T) {
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 
 		req := &runtimev1pb.UnlockRequest{
 			StoreName: "abc",
@@ -3244,7 +3531,7 @@ func TestUnlock(t *testing.T) {
 		defer ctl.Finish()
 
 		mockLockStore := daprt.NewMockStore(ctl)
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 
 		req := &runtimev1pb.UnlockRequest{
 			StoreName: "abc",
@@ -3258,7 +3545,7 @@ func TestUnlock(t *testing.T) {
 		defer ctl.Finish()
 
 		mockLockStore := daprt.NewMockStore(ctl)
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 		req := &runtimev1pb.UnlockRequest{
 			StoreName:  "abc",
 			ResourceId: "resource",
@@ -3272,7 +3559,7 @@ func TestUnlock(t *testing.T) {
 		defer ctl.Finish()
 
 		mockLockStore := daprt.NewMockStore(ctl)
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 
 		req := &runtimev1pb.UnlockRequest{
 			StoreName:  "abc",
@@ -3296,7 +3583,7 @@ func TestUnlock(t *testing.T) {
 				Status: lock.Success,
 			}, nil
 		})
-		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, map[string]lock.Store{"mock": mockLockStore}, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
 		req := &runtimev1pb.UnlockRequest{
 			StoreName:  "mock",
 			ResourceId: "resource",
@@ -3307,3 +3594,282 @@ func TestUnlock(t *testing.T) {
 		assert.Equal(t, runtimev1pb.UnlockResponse_SUCCESS, resp.Status) //nolint:nosnakecase
 	})
 }
+
+func TestWorkflowAPIs(t *testing.T) {
+	registry := workflow.NewRegistry()
+	registry.RegisterWorkflow("approval", func(ctx *workflow.Context) (any, error) {
+		var approver string
+		if err := ctx.WaitForEvent("approve", &approver); err != nil {
+			return nil, err
+		}
+		return approver, nil
+	})
+	registry.RegisterWorkflow("deadline", func(ctx *workflow.Context) (any, error) {
+		if err := ctx.CreateTimer("deadline", time.Hour); err != nil {
+			return nil, err
+		}
+		return "fired", nil
+	})
+
+	t.Run("error when workflow engine not configured", func(t *testing.T) {
+		api := NewAPI("", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+		_, err := api.StartWorkflowAlpha1(context.Background(), &runtimev1pb.StartWorkflowRequest{
+			WorkflowName: "approval",
+			InstanceId:   "1",
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	api := NewAPI("", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, config.TracingSpec{}, nil, "", nil, nil, nil)
+	engine := workflow.NewEngine(registry, "default", "myapp")
+	workflow.NewMockActors().AddHost(engine)
+	defer engine.Close()
+	api.SetWorkflowEngine(engine)
+	ctx := context.Background()
+
+	t.Run("InvalidArgument: instance id empty", func(t *testing.T) {
+		_, err := api.StartWorkflowAlpha1(ctx, &runtimev1pb.StartWorkflowRequest{WorkflowName: "approval"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("NotFound: workflow not registered", func(t *testing.T) {
+		_, err := api.StartWorkflowAlpha1(ctx, &runtimev1pb.StartWorkflowRequest{WorkflowName: "unknown", InstanceId: "1"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("start, raise event and purge", func(t *testing.T) {
+		resp, err := api.StartWorkflowAlpha1(ctx, &runtimev1pb.StartWorkflowRequest{
+			WorkflowName: "approval",
+			InstanceId:   "order-1",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "order-1", resp.InstanceId)
+
+		_, err = api.StartWorkflowAlpha1(ctx, &runtimev1pb.StartWorkflowRequest{WorkflowName: "approval", InstanceId: "order-1"})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+		_, err = api.GetWorkflowAlpha1(ctx, &runtimev1pb.GetWorkflowRequest{WorkflowName: "other", InstanceId: "order-1"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = api.PurgeWorkflowAlpha1(ctx, &runtimev1pb.WorkflowInstanceRequest{WorkflowName: "approval", InstanceId: "order-1"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = api.RaiseEventWorkflowAlpha1(ctx, &runtimev1pb.RaiseEventWorkflowRequest{
+			WorkflowName: "approval",
+			InstanceId:   "order-1",
+			EventName:    "approve",
+			EventData:    []byte(`"alice"`),
+		})
+		assert.NoError(t, err)
+
+		var state *runtimev1pb.GetWorkflowResponse
+		assert.Eventually(t, func() bool {
+			state, err = api.GetWorkflowAlpha1(ctx, &runtimev1pb.GetWorkflowRequest{WorkflowName: "approval", InstanceId: "order-1"})
+			return err == nil && state.RuntimeStatus == string(workflow.StatusCompleted)
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []byte(`"alice"`), state.Output)
+
+		_, err = api.PurgeWorkflowAlpha1(ctx, &runtimev1pb.WorkflowInstanceRequest{WorkflowName: "approval", InstanceId: "order-1"})
+		assert.NoError(t, err)
+
+		_, err = api.GetWorkflowAlpha1(ctx, &runtimev1pb.GetWorkflowRequest{WorkflowName: "approval", InstanceId: "order-1"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("pause, resume and terminate", func(t *testing.T) {
+		req := &runtimev1pb.WorkflowInstanceRequest{WorkflowName: "approval", InstanceId: "order-2"}
+		_, err := api.StartWorkflowAlpha1(ctx, &runtimev1pb.StartWorkflowRequest{WorkflowName: "approval", InstanceId: "order-2"})
+		assert.NoError(t, err)
+
+		_, err = api.ResumeWorkflowAlpha1(ctx, req)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = api.PauseWorkflowAlpha1(ctx, req)
+		assert.NoError(t, err)
+		_, err = api.ResumeWorkflowAlpha1(ctx, req)
+		assert.NoError(t, err)
+
+		_, err = api.TerminateWorkflowAlpha1(ctx, req)
+		assert.NoError(t, err)
+		state, err := api.GetWorkflowAlpha1(ctx, &runtimev1pb.GetWorkflowRequest{WorkflowName: "approval", InstanceId: "order-2"})
+		assert.NoError(t, err)
+		assert.Equal(t, string(workflow.StatusTerminated), state.RuntimeStatus)
+	})
+
+	t.Run("pending events", func(t *testing.T) {
+		req := &runtimev1pb.WorkflowInstanceRequest{WorkflowName: "approval", InstanceId: "order-3"}
+		_, err := api.StartWorkflowAlpha1(ctx, &runtimev1pb.StartWorkflowRequest{WorkflowName: "approval", InstanceId: "order-3"})
+		assert.NoError(t, err)
+		_, err = api.PauseWorkflowAlpha1(ctx, req)
+		assert.NoError(t, err)
+		_, err = api.RaiseEventWorkflowAlpha1(ctx, &runtimev1pb.RaiseEventWorkflowRequest{
+			WorkflowName: "approval",
+			InstanceId:   "order-3",
+			EventName:    "approve",
+			EventData:    []byte(`"alice"`),
+		})
+		assert.NoError(t, err)
+
+		pending, err := api.GetWorkflowPendingAlpha1(ctx, req)
+		assert.NoError(t, err)
+		assert.Len(t, pending.Events, 1)
+		assert.Equal(t, "approve", pending.Events[0].Name)
+		assert.Equal(t, []byte(`"alice"`), pending.Events[0].Data)
+		assert.Empty(t, pending.Timers)
+	})
+
+	t.Run("cancel and reschedule timers", func(t *testing.T) {
+		req := &runtimev1pb.WorkflowInstanceRequest{WorkflowName: "deadline", InstanceId: "order-4"}
+		_, err := api.StartWorkflowAlpha1(ctx, &runtimev1pb.StartWorkflowRequest{WorkflowName: "deadline", InstanceId: "order-4"})
+		assert.NoError(t, err)
+
+		var pending *runtimev1pb.GetWorkflowPendingResponse
+		assert.Eventually(t, func() bool {
+			pending, err = api.GetWorkflowPendingAlpha1(ctx, req)
+			return err == nil && len(pending.Timers) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		timerID := pending.Timers[0].Id
+		assert.Equal(t, "deadline", pending.Timers[0].Name)
+
+		_, err = api.CancelWorkflowTimerAlpha1(ctx, &runtimev1pb.WorkflowTimerRequest{WorkflowName: "deadline", InstanceId: "order-4"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = api.CancelWorkflowTimerAlpha1(ctx, &runtimev1pb.WorkflowTimerRequest{WorkflowName: "deadline", InstanceId: "order-4", TimerId: "unknown"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		_, err = api.RescheduleWorkflowTimerAlpha1(ctx, &runtimev1pb.RescheduleWorkflowTimerRequest{WorkflowName: "deadline", InstanceId: "order-4", TimerId: timerID})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = api.RescheduleWorkflowTimerAlpha1(ctx, &runtimev1pb.RescheduleWorkflowTimerRequest{
+			WorkflowName: "deadline",
+			InstanceId:   "order-4",
+			TimerId:      timerID,
+			FireAt:       timestamppb.Now(),
+		})
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			state, err := api.GetWorkflowAlpha1(ctx, &runtimev1pb.GetWorkflowRequest{WorkflowName: "deadline", InstanceId: "order-4"})
+			return err == nil && state.RuntimeStatus == string(workflow.StatusCompleted)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		_, err = api.CancelWorkflowTimerAlpha1(ctx, &runtimev1pb.WorkflowTimerRequest{WorkflowName: "deadline", InstanceId: "order-4", TimerId: timerID})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestCryptoAPIs(t *testing.T) {
+	provider := localkeys.NewRawKeys(logger.NewLogger("crypto.test"))
+	err := provider.Init(cryptoLoader.Metadata{Base: contribMetadata.Base{Properties: map[string]string{
+		"mykey": base64.StdEncoding.EncodeToString(make([]byte, 32)),
+	}}})
+	assert.NoError(t, err)
+
+	fakeAPI := &api{
+		id:              "fakeAPI",
+		cryptoProviders: map[string]cryptoLoader.SubtleCrypto{"mycrypto": provider},
+	}
+	port, _ := freeport.GetFreePort()
+	server := startDaprAPIServer(port, fakeAPI, "")
+	defer server.Stop()
+
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+	client := runtimev1pb.NewDaprClient(clientConn)
+
+	plaintext := bytes.Repeat([]byte("hello world "), 10000)
+
+	encrypt := func(t *testing.T, opts *runtimev1pb.EncryptRequestOptions) ([]byte, error) {
+		stream, err := client.EncryptAlpha1(context.Background())
+		assert.NoError(t, err)
+		assert.NoError(t, stream.Send(&runtimev1pb.EncryptRequest{Options: opts, Data: plaintext[:100]}))
+		assert.NoError(t, stream.Send(&runtimev1pb.EncryptRequest{Data: plaintext[100:]}))
+		assert.NoError(t, stream.CloseSend())
+
+		var out []byte
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return out, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res.Data...)
+		}
+	}
+
+	decrypt := func(t *testing.T, componentName string, data []byte) ([]byte, error) {
+		stream, err := client.DecryptAlpha1(context.Background())
+		assert.NoError(t, err)
+		assert.NoError(t, stream.Send(&runtimev1pb.DecryptRequest{
+			Options: &runtimev1pb.DecryptRequestOptions{ComponentName: componentName},
+			Data:    data,
+		}))
+		assert.NoError(t, stream.CloseSend())
+
+		var out []byte
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return out, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res.Data...)
+		}
+	}
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		ciphertext, err := encrypt(t, &runtimev1pb.EncryptRequestOptions{
+			ComponentName:    "mycrypto",
+			KeyName:          "mykey",
+			KeyWrapAlgorithm: "A256KW",
+		})
+		assert.NoError(t, err)
+		assert.NotContains(t, string(ciphertext), "hello world")
+
+		decrypted, err := decrypt(t, "mycrypto", ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("InvalidArgument: missing options", func(t *testing.T) {
+		_, err := encrypt(t, nil)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("InvalidArgument: provider not found", func(t *testing.T) {
+		_, err := encrypt(t, &runtimev1pb.EncryptRequestOptions{ComponentName: "nope"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("InvalidArgument: invalid encrypted stream", func(t *testing.T) {
+		_, err := decrypt(t, "mycrypto", []byte("not encrypted"))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("sign and verify", func(t *testing.T) {
+		digest := sha256.Sum256(plaintext)
+		signed, err := fakeAPI.SignAlpha1(context.Background(), &runtimev1pb.SignRequest{
+			ComponentName: "mycrypto",
+			KeyName:       "mykey",
+			Algorithm:     "HS256",
+			Digest:        digest[:],
+		})
+		assert.NoError(t, err)
+
+		verified, err := fakeAPI.VerifyAlpha1(context.Background(), &runtimev1pb.VerifyRequest{
+			ComponentName: "mycrypto",
+			KeyName:       "mykey",
+			Algorithm:     "HS256",
+			Digest:        digest[:],
+			Signature:     signed.Signature,
+		})
+		assert.NoError(t, err)
+		assert.True(t, verified.Valid)
+	})
+
+	t.Run("FailedPrecondition: crypto providers not configured", func(t *testing.T) {
+		_, err := (&api{}).SignAlpha1(context.Background(), &runtimev1pb.SignRequest{ComponentName: "mycrypto"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
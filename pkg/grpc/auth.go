@@ -12,6 +12,11 @@ import (
 
 func setAPIAuthenticationMiddlewareUnary(apiToken, authHeader string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// The health checks are made by the kubelet, which doesn't have the token
+		if info.FullMethod == healthCheckMethod {
+			return handler(ctx, req)
+		}
+
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
 			err := v1.ErrorFromHTTPResponseCode(http.StatusUnauthorized, "missing metadata in request")
@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/serviceconfig"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const (
+	// leastRequestBalancerName is the name of the balancer sending the requests to the instance with the fewest requests in flight.
+	leastRequestBalancerName = "dapr_least_request"
+	// zoneAwareBalancerName is the name of the balancer preferring the instances of the local zone.
+	zoneAwareBalancerName = "dapr_zone_aware"
+)
+
+func init() {
+	balancer.Register(&leastRequestBuilder{})
+	balancer.Register(&zoneAwareBuilder{})
+}
+
+// getServiceConfig returns the gRPC service config of the connections to the other sidecars with the load balancing policy.
+func getServiceConfig(spec *config.LoadBalancingSpec, hostAddress string) (string, error) {
+	if spec == nil {
+		return grpcServiceConfig, nil
+	}
+
+	switch spec.Policy {
+	case "", config.RoundRobinLoadBalancing:
+		return grpcServiceConfig, nil
+	case config.LeastRequestLoadBalancing:
+		return fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, leastRequestBalancerName), nil
+	case config.ZoneAwareLoadBalancing:
+		zoneConfig := zoneAwareConfig{LocalZone: spec.LocalZone, Zones: spec.Zones}
+		zones, err := zoneConfig.parseZones()
+		if err != nil {
+			return "", err
+		}
+		if zoneConfig.LocalZone == "" {
+			zoneConfig.LocalZone = zones.zoneOf(hostAddress)
+			if zoneConfig.LocalZone == "" {
+				return "", errors.Errorf("invalid zone aware load balancing: the host address %s is in no zone", hostAddress)
+			}
+		}
+		b, err := json.Marshal(map[string]interface{}{
+			"loadBalancingConfig": []interface{}{
+				map[string]interface{}{zoneAwareBalancerName: zoneConfig},
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", errors.Errorf("invalid load balancing policy %s", spec.Policy)
+	}
+}
+
+// leastRequestBuilder builds the least request balancers, each with its own picker builder.
+type leastRequestBuilder struct{}
+
+func (*leastRequestBuilder) Name() string {
+	return leastRequestBalancerName
+}
+
+func (*leastRequestBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	return base.NewBalancerBuilder(leastRequestBalancerName, &leastRequestPickerBuilder{}, base.Config{HealthCheck: true}).Build(cc, opts)
+}
+
+// leastRequestPickerBuilder builds the pickers of the least request balancer of a connection.
+// The number of requests in flight of the instances is kept across the pickers.
+type leastRequestPickerBuilder struct {
+	lock     sync.Mutex
+	inflight map[balancer.SubConn]*int64
+}
+
+func (b *leastRequestPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	inflight := make(map[balancer.SubConn]*int64, len(info.ReadySCs))
+	subConns := make([]balancer.SubConn, 0, len(info.ReadySCs))
+	for sc := range info.ReadySCs {
+		count, ok := b.inflight[sc]
+		if !ok {
+			count = new(int64)
+		}
+		inflight[sc] = count
+		subConns = append(subConns, sc)
+	}
+	b.inflight = inflight
+	return &leastRequestPicker{subConns: subConns, inflight: inflight}
+}
+
+// leastRequestPicker picks the instance with the fewest requests in flight of two distinct instances picked at random,
+// which avoids sending all the requests to the same instance while it is the least loaded one.
+type leastRequestPicker struct {
+	subConns []balancer.SubConn
+	inflight map[balancer.SubConn]*int64
+}
+
+func (p *leastRequestPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	//nolint:gosec
+	i := rand.Intn(len(p.subConns))
+	sc := p.subConns[i]
+	if len(p.subConns) > 1 {
+		//nolint:gosec
+		j := rand.Intn(len(p.subConns) - 1)
+		if j >= i {
+			j++
+		}
+		if other := p.subConns[j]; atomic.LoadInt64(p.inflight[other]) < atomic.LoadInt64(p.inflight[sc]) {
+			sc = other
+		}
+	}
+
+	count := p.inflight[sc]
+	atomic.AddInt64(count, 1)
+	return balancer.PickResult{
+		SubConn: sc,
+		Done: func(balancer.DoneInfo) {
+			atomic.AddInt64(count, -1)
+		},
+	}, nil
+}
+
+// zoneAwareConfig is the config of the zone aware balancer.
+type zoneAwareConfig struct {
+	serviceconfig.LoadBalancingConfig `json:"-"`
+
+	LocalZone string            `json:"localZone"`
+	Zones     []config.ZoneSpec `json:"zones"`
+}
+
+type zoneSubnets map[string][]*net.IPNet
+
+func (c *zoneAwareConfig) parseZones() (zoneSubnets, error) {
+	zones := make(zoneSubnets, len(c.Zones))
+	for _, zone := range c.Zones {
+		if zone.Name == "" {
+			return nil, errors.New("invalid zone aware load balancing: missing name of a zone")
+		}
+		for _, cidr := range zone.CIDRs {
+			_, subnet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid zone aware load balancing: zone %s", zone.Name)
+			}
+			zones[zone.Name] = append(zones[zone.Name], subnet)
+		}
+	}
+	return zones, nil
+}
+
+// zoneOf returns the zone of the address, an IP optionally with a port, or an empty string if it is in no zone.
+func (z zoneSubnets) zoneOf(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	for zone, subnets := range z {
+		for _, subnet := range subnets {
+			if subnet.Contains(ip) {
+				return zone
+			}
+		}
+	}
+	return ""
+}
+
+// zoneAwareBuilder builds the zone aware balancers, which balance the requests between the instances of the local zone
+// in a round robin way, and between all the instances when none of the local zone are ready.
+type zoneAwareBuilder struct{}
+
+func (*zoneAwareBuilder) Name() string {
+	return zoneAwareBalancerName
+}
+
+func (*zoneAwareBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	pickerBuilder := &zoneAwarePickerBuilder{}
+	return &zoneAwareBalancer{
+		Balancer:      base.NewBalancerBuilder(zoneAwareBalancerName, pickerBuilder, base.Config{HealthCheck: true}).Build(cc, opts),
+		pickerBuilder: pickerBuilder,
+	}
+}
+
+func (*zoneAwareBuilder) ParseConfig(js json.RawMessage) (serviceconfig.LoadBalancingConfig, error) {
+	var cfg zoneAwareConfig
+	if err := json.Unmarshal(js, &cfg); err != nil {
+		return nil, errors.Wrap(err, "invalid zone aware load balancing config")
+	}
+	zones, err := cfg.parseZones()
+	if err != nil {
+		return nil, err
+	}
+	return &parsedZoneAwareConfig{localZone: cfg.LocalZone, zones: zones}, nil
+}
+
+type parsedZoneAwareConfig struct {
+	serviceconfig.LoadBalancingConfig
+
+	localZone string
+	zones     zoneSubnets
+}
+
+// zoneAwareBalancer passes its config to its picker builder before the base balancer builds the pickers.
+type zoneAwareBalancer struct {
+	balancer.Balancer
+	pickerBuilder *zoneAwarePickerBuilder
+}
+
+func (b *zoneAwareBalancer) UpdateClientConnState(state balancer.ClientConnState) error {
+	if cfg, ok := state.BalancerConfig.(*parsedZoneAwareConfig); ok {
+		b.pickerBuilder.setConfig(cfg)
+	}
+	return b.Balancer.UpdateClientConnState(state)
+}
+
+type zoneAwarePickerBuilder struct {
+	lock sync.Mutex
+	cfg  *parsedZoneAwareConfig
+}
+
+func (b *zoneAwarePickerBuilder) setConfig(cfg *parsedZoneAwareConfig) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.cfg = cfg
+}
+
+func (b *zoneAwarePickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	b.lock.Lock()
+	cfg := b.cfg
+	b.lock.Unlock()
+
+	all := make([]balancer.SubConn, 0, len(info.ReadySCs))
+	local := make([]balancer.SubConn, 0, len(info.ReadySCs))
+	for sc, scInfo := range info.ReadySCs {
+		all = append(all, sc)
+		if cfg != nil && cfg.zones.zoneOf(scInfo.Address.Addr) == cfg.localZone {
+			local = append(local, sc)
+		}
+	}
+	if len(local) == 0 {
+		local = all
+	}
+	//nolint:gosec
+	return &roundRobinPicker{subConns: local, next: uint32(rand.Intn(len(local)))}
+}
+
+// roundRobinPicker picks the instances in turn.
+type roundRobinPicker struct {
+	subConns []balancer.SubConn
+	next     uint32
+}
+
+func (p *roundRobinPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	next := atomic.AddUint32(&p.next, 1)
+	return balancer.PickResult{SubConn: p.subConns[next%uint32(len(p.subConns))]}, nil
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/modes"
+)
+
+type fakeSubConn struct {
+	balancer.SubConn
+	name string
+}
+
+func testZones() []config.ZoneSpec {
+	return []config.ZoneSpec{
+		{Name: "zone-a", CIDRs: []string{"10.0.0.0/16"}},
+		{Name: "zone-b", CIDRs: []string{"10.1.0.0/16", "10.2.0.0/16"}},
+	}
+}
+
+func TestGetServiceConfig(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		serviceConfig, err := getServiceConfig(nil, "10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, grpcServiceConfig, serviceConfig)
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		serviceConfig, err := getServiceConfig(&config.LoadBalancingSpec{Policy: config.RoundRobinLoadBalancing}, "10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, grpcServiceConfig, serviceConfig)
+	})
+
+	t.Run("least request", func(t *testing.T) {
+		serviceConfig, err := getServiceConfig(&config.LoadBalancingSpec{Policy: config.LeastRequestLoadBalancing}, "10.0.0.1")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"loadBalancingConfig":[{"dapr_least_request":{}}]}`, serviceConfig)
+	})
+
+	t.Run("zone aware with the zone of the host", func(t *testing.T) {
+		serviceConfig, err := getServiceConfig(&config.LoadBalancingSpec{Policy: config.ZoneAwareLoadBalancing, Zones: testZones()}, "10.2.0.1")
+		require.NoError(t, err)
+
+		var parsed struct {
+			LoadBalancingConfig []map[string]json.RawMessage `json:"loadBalancingConfig"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(serviceConfig), &parsed))
+		require.Len(t, parsed.LoadBalancingConfig, 1)
+		lbConfig, err := (&zoneAwareBuilder{}).ParseConfig(parsed.LoadBalancingConfig[0][zoneAwareBalancerName])
+		require.NoError(t, err)
+		assert.Equal(t, "zone-b", lbConfig.(*parsedZoneAwareConfig).localZone)
+	})
+
+	t.Run("zone aware with a local zone", func(t *testing.T) {
+		serviceConfig, err := getServiceConfig(&config.LoadBalancingSpec{Policy: config.ZoneAwareLoadBalancing, LocalZone: "zone-a", Zones: testZones()}, "192.168.0.1")
+		require.NoError(t, err)
+		assert.Contains(t, serviceConfig, `"localZone":"zone-a"`)
+	})
+
+	t.Run("zone aware with the host in no zone", func(t *testing.T) {
+		_, err := getServiceConfig(&config.LoadBalancingSpec{Policy: config.ZoneAwareLoadBalancing, Zones: testZones()}, "192.168.0.1")
+		assert.Error(t, err)
+	})
+
+	t.Run("zone aware with an invalid cidr", func(t *testing.T) {
+		_, err := getServiceConfig(&config.LoadBalancingSpec{
+			Policy:    config.ZoneAwareLoadBalancing,
+			LocalZone: "zone-a",
+			Zones:     []config.ZoneSpec{{Name: "zone-a", CIDRs: []string{"10.0.0.0"}}},
+		}, "10.0.0.1")
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := getServiceConfig(&config.LoadBalancingSpec{Policy: "random"}, "10.0.0.1")
+		assert.Error(t, err)
+	})
+}
+
+func TestSetLoadBalancing(t *testing.T) {
+	m := NewGRPCManager(modes.StandaloneMode)
+	require.NoError(t, m.SetLoadBalancing(&config.LoadBalancingSpec{Policy: config.LeastRequestLoadBalancing}, "10.0.0.1"))
+	assert.Contains(t, m.serviceConfig, leastRequestBalancerName)
+
+	assert.Error(t, m.SetLoadBalancing(&config.LoadBalancingSpec{Policy: "random"}, "10.0.0.1"))
+	assert.Contains(t, m.serviceConfig, leastRequestBalancerName)
+}
+
+func TestZoneOf(t *testing.T) {
+	zoneConfig := zoneAwareConfig{Zones: testZones()}
+	zones, err := zoneConfig.parseZones()
+	require.NoError(t, err)
+
+	assert.Equal(t, "zone-a", zones.zoneOf("10.0.1.1"))
+	assert.Equal(t, "zone-b", zones.zoneOf("10.1.1.1:50002"))
+	assert.Equal(t, "", zones.zoneOf("192.168.0.1:50002"))
+	assert.Equal(t, "", zones.zoneOf("app1-dapr.default.svc.cluster.local:50002"))
+}
+
+func TestLeastRequestPicker(t *testing.T) {
+	sc1 := &fakeSubConn{name: "sc1"}
+	sc2 := &fakeSubConn{name: "sc2"}
+	pickerBuilder := &leastRequestPickerBuilder{}
+
+	t.Run("no ready instance", func(t *testing.T) {
+		_, err := pickerBuilder.Build(base.PickerBuildInfo{}).Pick(balancer.PickInfo{})
+		assert.Equal(t, balancer.ErrNoSubConnAvailable, err)
+	})
+
+	picker := pickerBuilder.Build(base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{
+		sc1: {Address: resolver.Address{Addr: "10.0.0.1:50002"}},
+		sc2: {Address: resolver.Address{Addr: "10.0.0.2:50002"}},
+	}})
+
+	first, err := picker.Pick(balancer.PickInfo{})
+	require.NoError(t, err)
+	// The other instance has fewer requests in flight until the first request is done.
+	for i := 0; i < 20; i++ {
+		result, err := picker.Pick(balancer.PickInfo{})
+		require.NoError(t, err)
+		assert.NotEqual(t, first.SubConn, result.SubConn)
+		result.Done(balancer.DoneInfo{})
+	}
+
+	// The requests in flight are kept by the next pickers.
+	picker = pickerBuilder.Build(base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{
+		sc1: {Address: resolver.Address{Addr: "10.0.0.1:50002"}},
+		sc2: {Address: resolver.Address{Addr: "10.0.0.2:50002"}},
+	}})
+	result, err := picker.Pick(balancer.PickInfo{})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.SubConn, result.SubConn)
+	result.Done(balancer.DoneInfo{})
+
+	first.Done(balancer.DoneInfo{})
+	assert.Equal(t, int64(0), *pickerBuilder.inflight[sc1])
+	assert.Equal(t, int64(0), *pickerBuilder.inflight[sc2])
+}
+
+func TestZoneAwarePicker(t *testing.T) {
+	zoneConfig := zoneAwareConfig{Zones: testZones()}
+	zones, err := zoneConfig.parseZones()
+	require.NoError(t, err)
+	pickerBuilder := &zoneAwarePickerBuilder{}
+	pickerBuilder.setConfig(&parsedZoneAwareConfig{localZone: "zone-a", zones: zones})
+
+	local1 := &fakeSubConn{name: "local1"}
+	local2 := &fakeSubConn{name: "local2"}
+	remote := &fakeSubConn{name: "remote"}
+
+	t.Run("local zone instances", func(t *testing.T) {
+		picker := pickerBuilder.Build(base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{
+			local1: {Address: resolver.Address{Addr: "10.0.0.1:50002"}},
+			local2: {Address: resolver.Address{Addr: "10.0.0.2:50002"}},
+			remote: {Address: resolver.Address{Addr: "10.1.0.1:50002"}},
+		}})
+
+		picked := map[balancer.SubConn]int{}
+		for i := 0; i < 10; i++ {
+			result, err := picker.Pick(balancer.PickInfo{})
+			require.NoError(t, err)
+			picked[result.SubConn]++
+		}
+		assert.Equal(t, map[balancer.SubConn]int{local1: 5, local2: 5}, picked)
+	})
+
+	t.Run("no local zone instance", func(t *testing.T) {
+		picker := pickerBuilder.Build(base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{
+			remote: {Address: resolver.Address{Addr: "10.1.0.1:50002"}},
+		}})
+		result, err := picker.Pick(balancer.PickInfo{})
+		require.NoError(t, err)
+		assert.Equal(t, remote, result.SubConn)
+	})
+}
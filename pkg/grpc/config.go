@@ -13,6 +13,13 @@ limitations under the License.
 
 package grpc
 
+import (
+	"net"
+	"time"
+
+	"google.golang.org/grpc/health"
+)
+
 // ServerConfig is the config object for a grpc server.
 type ServerConfig struct {
 	AppID              string
@@ -25,6 +32,30 @@ type ServerConfig struct {
 	UnixDomainSocket   string
 	ReadBufferSize     int
 	EnableAPILogging   bool
+	// Listeners the API server serves on in addition to the configured ports, such as in-memory listeners for apps that embed Dapr.
+	Listeners []net.Listener
+	// Health is the gRPC health service registered on the server, reporting the readiness of the sidecar.
+	Health *health.Server
+	// Connection is the config of the connections accepted by the internal server.
+	Connection ConnectionConfig
+}
+
+// ConnectionConfig is the config of the gRPC connections between the sidecars.
+type ConnectionConfig struct {
+	// MaxConnectionAge is the age after which the internal server closes the connections, so that the clients reconnect to the new instances.
+	// The default age is used if 0.
+	MaxConnectionAge time.Duration
+	// KeepaliveTime is the interval of the keepalive pings of the connections without activity, which keep them open through the NAT gateways.
+	// The connections are not pinged if 0.
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is the time to wait for the acknowledgement of a keepalive ping before closing the connection.
+	KeepaliveTimeout time.Duration
+	// ConnectionsPerTarget is the number of connections to each sidecar, between which the calls are spread. One connection if 0.
+	ConnectionsPerTarget int
+	// MaxConcurrentStreams is the maximum number of concurrent streams of each connection accepted by the internal server. No limit if 0.
+	MaxConcurrentStreams uint32
+	// IdleTimeout is the time after which the pooled connections without calls are closed. They are kept open if 0.
+	IdleTimeout time.Duration
 }
 
 // NewServerConfig returns a new grpc server config.
@@ -6,6 +6,9 @@ import (
 	"github.com/dapr/dapr/pkg/modes"
 )
 
+// UnixDomainSocketScheme is the prefix of the addresses of the Unix domain sockets, such as unix:/tmp/app.socket.
+const UnixDomainSocketScheme = "unix:"
+
 // GetDialAddressPrefix returns a dial prefix for a gRPC client connections
 // For a given DaprMode.
 func GetDialAddressPrefix(mode modes.DaprMode) string {
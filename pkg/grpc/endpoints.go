@@ -16,10 +16,14 @@ package grpc
 import (
 	"context"
 	"net/http"
+	"strings"
+	"sync"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/dapr/dapr/pkg/config"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
 	v1 "github.com/dapr/dapr/pkg/messaging/v1"
 )
 
@@ -73,15 +77,87 @@ var endpoints = map[string][]string{
 	"unlock.v1alpha1": {
 		"/dapr.proto.runtime.v1.Dapr/UnlockAlpha1",
 	},
+	"workflows.v1alpha1": {
+		"/dapr.proto.runtime.v1.Dapr/StartWorkflowAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/GetWorkflowAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/TerminateWorkflowAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/RaiseEventWorkflowAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/PauseWorkflowAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/ResumeWorkflowAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/PurgeWorkflowAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/GetWorkflowPendingAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/CancelWorkflowTimerAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/RescheduleWorkflowTimerAlpha1",
+	},
+	"crypto.v1alpha1": {
+		"/dapr.proto.runtime.v1.Dapr/EncryptAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/DecryptAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/SignAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/VerifyAlpha1",
+	},
 	"shutdown.v1": {
 		"/dapr.proto.runtime.v1.Dapr/Shutdown",
 	},
+	"logging.v1alpha1": {
+		"/dapr.proto.runtime.v1.Dapr/SetLogLevelAlpha1",
+	},
+}
+
+// deprecatedEndpoints are the methods of the deprecated APIs.
+var deprecatedEndpoints = map[string]struct{}{
+	"/dapr.proto.runtime.v1.Dapr/RenameActorReminder": {},
+}
+
+const (
+	protocol = "grpc"
+
+	// deprecationHeader is the header of the responses of the deprecated APIs.
+	deprecationHeader = "deprecation"
+
+	// healthCheckMethod is the method of the gRPC health service, which is always allowed like the healthz HTTP endpoint.
+	healthCheckMethod = "/grpc.health.v1.Health/Check"
+
+	daprServiceMethodPrefix = "/dapr.proto.runtime.v1.Dapr/"
+)
+
+func setAPIEndpointsMiddlewareUnary(allowedRules, deniedRules []config.APIAccessRule) grpc.UnaryServerInterceptor {
+	allowed := endpointsForRules(allowedRules)
+	denied := endpointsForRules(deniedRules)
+
+	// Passthrough if no gRPC rules
+	if len(allowed) == 0 && len(denied) == 0 {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(ctx, req)
+		}
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !endpointAllowed(info.FullMethod, allowed, denied) {
+			return nil, v1.ErrorFromHTTPResponseCode(http.StatusNotImplemented, "requested endpoint is not available")
+		}
+
+		return handler(ctx, req)
+	}
 }
 
-const protocol = "grpc"
+// setAPIEndpointsMiddlewareStream applies the API access rules to the streaming Dapr APIs.
+// The calls proxied to the apps are not restricted.
+func setAPIEndpointsMiddlewareStream(allowedRules, deniedRules []config.APIAccessRule) grpc.StreamServerInterceptor {
+	allowed := endpointsForRules(allowedRules)
+	denied := endpointsForRules(deniedRules)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, daprServiceMethodPrefix) && !endpointAllowed(info.FullMethod, allowed, denied) {
+			return v1.ErrorFromHTTPResponseCode(http.StatusNotImplemented, "requested endpoint is not available")
+		}
+
+		return handler(srv, ss)
+	}
+}
 
-func setAPIEndpointsMiddlewareUnary(rules []config.APIAccessRule) grpc.UnaryServerInterceptor {
-	allowed := map[string]struct{}{}
+// endpointsForRules returns the gRPC methods of the APIs matching the gRPC rules.
+func endpointsForRules(rules []config.APIAccessRule) map[string]struct{} {
+	methods := map[string]struct{}{}
 
 	for _, rule := range rules {
 		if rule.Protocol != protocol {
@@ -90,24 +166,87 @@ func setAPIEndpointsMiddlewareUnary(rules []config.APIAccessRule) grpc.UnaryServ
 
 		if list, ok := endpoints[rule.Name+"."+rule.Version]; ok {
 			for _, method := range list {
-				allowed[method] = struct{}{}
+				methods[method] = struct{}{}
 			}
 		}
 	}
 
-	// Passthrough if no gRPC rules
+	return methods
+}
+
+// endpointAllowed returns true if the method isn't denied, and is allowed when an allow list is set.
+// The denied APIs take precedence over the allowed ones.
+func endpointAllowed(method string, allowed, denied map[string]struct{}) bool {
+	if method == healthCheckMethod {
+		return true
+	}
+	if _, ok := denied[method]; ok {
+		return false
+	}
 	if len(allowed) == 0 {
-		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-			return handler(ctx, req)
-		}
+		return true
 	}
+	_, ok := allowed[method]
+	return ok
+}
+
+// setAPIMaturityMiddlewareUnary disables the alpha APIs which aren't enabled in the configuration,
+// and warns the callers of the deprecated APIs.
+func setAPIMaturityMiddlewareUnary(spec config.APISpec) grpc.UnaryServerInterceptor {
+	disabled := disabledAlphaEndpoints(spec)
+	var warned sync.Map
 
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		_, ok := allowed[info.FullMethod]
-		if !ok {
-			return nil, v1.ErrorFromHTTPResponseCode(http.StatusNotImplemented, "requested endpoint is not available")
+		if _, ok := disabled[info.FullMethod]; ok {
+			return nil, v1.ErrorFromHTTPResponseCode(http.StatusNotImplemented, "requested endpoint is not available: the alpha APIs are disabled")
+		}
+		if _, ok := deprecatedEndpoints[info.FullMethod]; ok {
+			deprecatedEndpointCalled(info.FullMethod, &warned)
+			_ = grpc.SetHeader(ctx, metadata.Pairs(deprecationHeader, "true"))
 		}
 
 		return handler(ctx, req)
 	}
 }
+
+// setAPIMaturityMiddlewareStream disables the streaming alpha APIs which aren't enabled in the configuration.
+func setAPIMaturityMiddlewareStream(spec config.APISpec) grpc.StreamServerInterceptor {
+	disabled := disabledAlphaEndpoints(spec)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := disabled[info.FullMethod]; ok {
+			return v1.ErrorFromHTTPResponseCode(http.StatusNotImplemented, "requested endpoint is not available: the alpha APIs are disabled")
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// disabledAlphaEndpoints returns the methods of the alpha APIs, except the ones in the allow list.
+// It returns no methods when all the alpha APIs are enabled.
+func disabledAlphaEndpoints(spec config.APISpec) map[string]struct{} {
+	disabled := map[string]struct{}{}
+	if spec.EnableAlpha {
+		return disabled
+	}
+
+	allowed := endpointsForRules(spec.Allowed)
+	for api, methods := range endpoints {
+		if !strings.Contains(api, "alpha") {
+			continue
+		}
+		for _, method := range methods {
+			if _, ok := allowed[method]; !ok {
+				disabled[method] = struct{}{}
+			}
+		}
+	}
+	return disabled
+}
+
+func deprecatedEndpointCalled(method string, warned *sync.Map) {
+	if _, loaded := warned.LoadOrStore(method, struct{}{}); !loaded {
+		apiServerLogger.Warnf("the deprecated API %s was called and will be removed in a future release", method)
+	}
+	diag.DefaultMonitoring.DeprecatedAPICalled(protocol, method)
+}
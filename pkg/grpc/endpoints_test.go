@@ -17,6 +17,7 @@ package grpc
 import (
 	"context"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,7 +63,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["state.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -92,7 +93,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["state.v1alpha1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -122,7 +123,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["publish.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -152,7 +153,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["actors.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -182,7 +183,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["bindings.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -212,7 +213,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["secrets.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -242,7 +243,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["metadata.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -272,7 +273,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["shutdown.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -302,7 +303,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, e := range endpoints["invoke.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -324,7 +325,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 	})
 
 	t.Run("no rules, all endpoints are allowed", func(t *testing.T) {
-		f := setAPIEndpointsMiddlewareUnary(nil)
+		f := setAPIEndpointsMiddlewareUnary(nil, nil)
 
 		for _, e := range endpoints["invoke.v1"] {
 			_, err := f(nil, nil, &grpc.UnaryServerInfo{
@@ -352,7 +353,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := setAPIEndpointsMiddlewareUnary(a, nil)
 
 		for _, v := range endpoints {
 			for _, e := range v {
@@ -363,4 +364,151 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("secrets.v1 endpoints denied", func(t *testing.T) {
+		d := []config.APIAccessRule{
+			{
+				Name:     "secrets",
+				Version:  "v1",
+				Protocol: "grpc",
+			},
+		}
+
+		f := setAPIEndpointsMiddlewareUnary(nil, d)
+
+		for k, v := range endpoints {
+			for _, e := range v {
+				_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					FullMethod: e,
+				}, h)
+				if k == "secrets.v1" {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+		}
+
+		_, err := f(nil, nil, &grpc.UnaryServerInfo{FullMethod: healthCheckMethod}, h)
+		assert.NoError(t, err)
+	})
+
+	t.Run("denied endpoints take precedence over allowed ones", func(t *testing.T) {
+		a := []config.APIAccessRule{
+			{
+				Name:     "state",
+				Version:  "v1",
+				Protocol: "grpc",
+			},
+		}
+
+		f := setAPIEndpointsMiddlewareUnary(a, a)
+
+		for _, v := range endpoints {
+			for _, e := range v {
+				_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					FullMethod: e,
+				}, h)
+				assert.Error(t, err)
+			}
+		}
+	})
+}
+
+func TestSetAPIEndpointsMiddlewareStream(t *testing.T) {
+	h := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+
+	d := []config.APIAccessRule{
+		{
+			Name:     "actors",
+			Version:  "v1",
+			Protocol: "grpc",
+		},
+	}
+
+	f := setAPIEndpointsMiddlewareStream(nil, d)
+
+	for k, v := range endpoints {
+		for _, e := range v {
+			err := f(nil, nil, &grpc.StreamServerInfo{
+				FullMethod: e,
+			}, h)
+			if k == "actors.v1" {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		}
+	}
+
+	t.Run("proxied calls are not restricted", func(t *testing.T) {
+		f := setAPIEndpointsMiddlewareStream(d, nil)
+
+		err := f(nil, nil, &grpc.StreamServerInfo{FullMethod: "/myapp.Service/Method"}, h)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSetAPIMaturityMiddlewareUnary(t *testing.T) {
+	h := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	call := func(f grpc.UnaryServerInterceptor, method string) error {
+		_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, h)
+		return err
+	}
+
+	t.Run("alpha APIs disabled by default", func(t *testing.T) {
+		f := setAPIMaturityMiddlewareUnary(config.APISpec{})
+
+		for k, v := range endpoints {
+			for _, e := range v {
+				if strings.Contains(k, "alpha") {
+					assert.Error(t, call(f, e))
+				} else {
+					assert.NoError(t, call(f, e))
+				}
+			}
+		}
+	})
+
+	t.Run("alpha APIs enabled", func(t *testing.T) {
+		f := setAPIMaturityMiddlewareUnary(config.APISpec{EnableAlpha: true})
+
+		for _, v := range endpoints {
+			for _, e := range v {
+				assert.NoError(t, call(f, e))
+			}
+		}
+	})
+
+	t.Run("alpha API in the allow list", func(t *testing.T) {
+		f := setAPIMaturityMiddlewareUnary(config.APISpec{
+			Allowed: []config.APIAccessRule{
+				{
+					Name:     "lock",
+					Version:  "v1alpha1",
+					Protocol: "grpc",
+				},
+			},
+		})
+
+		assert.NoError(t, call(f, "/dapr.proto.runtime.v1.Dapr/TryLockAlpha1"))
+		assert.Error(t, call(f, "/dapr.proto.runtime.v1.Dapr/UnlockAlpha1"))
+	})
+}
+
+func TestSetAPIMaturityMiddlewareStream(t *testing.T) {
+	h := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+
+	f := setAPIMaturityMiddlewareStream(config.APISpec{})
+	err := f(nil, nil, &grpc.StreamServerInfo{FullMethod: "/dapr.proto.runtime.v1.Dapr/SubscribeConfigurationAlpha1"}, h)
+	assert.Error(t, err)
+
+	err = f(nil, nil, &grpc.StreamServerInfo{FullMethod: "/myapp.Service/Method"}, h)
+	assert.NoError(t, err)
 }
@@ -18,13 +18,16 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/dapr/dapr/pkg/channel"
 	grpcChannel "github.com/dapr/dapr/pkg/channel/grpc"
@@ -54,6 +57,8 @@ type Manager struct {
 	connectionPool *connectionPool
 	auth           security.Authenticator
 	mode           modes.DaprMode
+	serviceConfig  string
+	connection     ConnectionConfig
 }
 
 // NewGRPCManager returns a new grpc manager.
@@ -62,6 +67,44 @@ func NewGRPCManager(mode modes.DaprMode) *Manager {
 		lock:           &sync.RWMutex{},
 		connectionPool: newConnectionPool(),
 		mode:           mode,
+		serviceConfig:  grpcServiceConfig,
+	}
+}
+
+// SetLoadBalancing sets the policy of the balancing of the requests between the instances of the apps
+// resolved from the same address. The host address is used to find the local zone of the zoneAware policy.
+func (g *Manager) SetLoadBalancing(spec *config.LoadBalancingSpec, hostAddress string) error {
+	serviceConfig, err := getServiceConfig(spec, hostAddress)
+	if err != nil {
+		return err
+	}
+	g.serviceConfig = serviceConfig
+	return nil
+}
+
+// SetConnectionConfig sets the config of the connections to the other sidecars.
+func (g *Manager) SetConnectionConfig(connection ConnectionConfig) {
+	g.connection = connection
+}
+
+// CloseIdleConnections closes the pooled connections without calls for the idle timeout of the connection config,
+// until the context is canceled. It returns immediately if there is no idle timeout.
+func (g *Manager) CloseIdleConnections(ctx context.Context) {
+	if g.connection.IdleTimeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(g.connection.IdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			g.lock.Lock()
+			g.connectionPool.CloseIdle(now.Add(-g.connection.IdleTimeout))
+			g.lock.Unlock()
+		}
 	}
 }
 
@@ -82,6 +125,30 @@ func (g *Manager) CreateLocalChannel(port, maxConcurrency int, spec config.Traci
 	return ch, nil
 }
 
+// CreateLocalChannelOverUnixDomainSocket creates a new gRPC AppChannel connected to the app listening on the Unix Domain Socket.
+func (g *Manager) CreateLocalChannelOverUnixDomainSocket(socket string, maxConcurrency int, spec config.TracingSpec, maxRequestBodySize int, readBufferSize int) (channel.AppChannel, error) {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	if diag.DefaultGRPCMonitoring.IsEnabled() {
+		opts = append(opts, grpc.WithUnaryInterceptor(diag.DefaultGRPCMonitoring.UnaryClientInterceptor()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), dialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, UnixDomainSocketScheme+socket, opts...)
+	if err != nil {
+		return nil, errors.Errorf("error establishing connection to app grpc on socket %s: %s", socket, err)
+	}
+	return g.CreateLocalChannelWithConnection(conn, maxConcurrency, spec, maxRequestBodySize, readBufferSize), nil
+}
+
+// CreateLocalChannelWithConnection creates a new gRPC AppChannel over an existing connection to the app, such as an in-memory connection.
+func (g *Manager) CreateLocalChannelWithConnection(conn *grpc.ClientConn, maxConcurrency int, spec config.TracingSpec, maxRequestBodySize int, readBufferSize int) channel.AppChannel {
+	g.AppClient = conn
+	return grpcChannel.CreateLocalChannel(0, maxConcurrency, conn, spec, maxRequestBodySize, readBufferSize)
+}
+
 // GetGRPCConnection returns a new grpc connection for a given address and inits one if doesn't exist.
 func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, namespace string, skipTLS, recreateIfExists, sslEnabled bool, customOpts ...grpc.DialOption) (*grpc.ClientConn, func(), error) {
 	releaseFactory := func(conn *grpc.ClientConn) func() {
@@ -90,10 +157,16 @@ func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, nam
 		}
 	}
 
+	// The connections to the app skip TLS, while the ones to the other sidecars use the connection config.
+	connectionsPerTarget := 1
+	if !skipTLS && g.connection.ConnectionsPerTarget > 1 {
+		connectionsPerTarget = g.connection.ConnectionsPerTarget
+	}
+
 	// share pooled connection
 	if !recreateIfExists {
 		g.lock.RLock()
-		if conn, ok := g.connectionPool.Share(address); ok {
+		if conn, ok := g.connectionPool.Share(address, connectionsPerTarget); ok {
 			g.lock.RUnlock()
 
 			teardown := releaseFactory(conn)
@@ -103,7 +176,7 @@ func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, nam
 
 		g.lock.RLock()
 		// read the value once again, as a concurrent writer could create it
-		if conn, ok := g.connectionPool.Share(address); ok {
+		if conn, ok := g.connectionPool.Share(address, connectionsPerTarget); ok {
 			g.lock.RUnlock()
 
 			teardown := releaseFactory(conn)
@@ -113,13 +186,21 @@ func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, nam
 	}
 
 	opts := []grpc.DialOption{
-		grpc.WithDefaultServiceConfig(grpcServiceConfig),
+		grpc.WithDefaultServiceConfig(g.serviceConfig),
 	}
 
 	if diag.DefaultGRPCMonitoring.IsEnabled() {
 		opts = append(opts, grpc.WithUnaryInterceptor(diag.DefaultGRPCMonitoring.UnaryClientInterceptor()))
 	}
 
+	if !skipTLS && g.connection.KeepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                g.connection.KeepaliveTime,
+			Timeout:             g.connection.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
 	transportCredentialsAdded := false
 	if !skipTLS && g.auth != nil {
 		signedCert := g.auth.GetCurrentSignedCert()
@@ -147,6 +228,10 @@ func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, nam
 	defer cancel()
 
 	dialPrefix := GetDialAddressPrefix(g.mode)
+	// The addresses of the Unix domain sockets have their own scheme.
+	if strings.HasPrefix(address, UnixDomainSocketScheme) {
+		dialPrefix = ""
+	}
 	if sslEnabled {
 		//nolint:gosec
 		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
@@ -168,50 +253,71 @@ func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, nam
 	teardown := releaseFactory(conn)
 	g.lock.Lock()
 	defer g.lock.Unlock()
-	g.connectionPool.Register(address, conn)
+	g.connectionPool.Register(address, conn, connectionsPerTarget)
 
 	return conn, teardown, nil
 }
 
 type connectionPool struct {
-	pool           map[string]*grpc.ClientConn
+	pool           map[string]*targetConnections
 	referenceCount map[*grpc.ClientConn]int
+	lastUsed       map[*grpc.ClientConn]time.Time
 	referenceLock  *sync.RWMutex
 }
 
+// targetConnections are the pooled connections to an address, shared in turn.
+type targetConnections struct {
+	conns []*grpc.ClientConn
+	next  uint32
+}
+
 func newConnectionPool() *connectionPool {
 	return &connectionPool{
-		pool:           map[string]*grpc.ClientConn{},
+		pool:           map[string]*targetConnections{},
 		referenceCount: map[*grpc.ClientConn]int{},
+		lastUsed:       map[*grpc.ClientConn]time.Time{},
 		referenceLock:  &sync.RWMutex{},
 	}
 }
 
-func (p *connectionPool) Register(address string, conn *grpc.ClientConn) {
-	if oldConn, ok := p.pool[address]; ok {
+// Register adds the connection to the pool of the address.
+// When the pool has the max number of connections of the address, the connection replaces the oldest one.
+func (p *connectionPool) Register(address string, conn *grpc.ClientConn, maxConns int) {
+	target, ok := p.pool[address]
+	if !ok {
+		target = &targetConnections{}
+		p.pool[address] = target
+	}
+	if len(target.conns) >= maxConns {
+		oldConn := target.conns[0]
+		target.conns = append(target.conns[:0:0], target.conns[1:]...)
 		// oldConn is not used by pool anymore
 		p.Release(oldConn)
 	}
+	target.conns = append(target.conns, conn)
 
-	p.pool[address] = conn
 	// conn is used by caller and pool
 	// NOTE: pool should also increment referenceCount not to close the pooled connection
 
 	p.referenceLock.Lock()
 	defer p.referenceLock.Unlock()
 	p.referenceCount[conn] = 2
+	p.lastUsed[conn] = time.Now()
 }
 
-func (p *connectionPool) Share(address string) (*grpc.ClientConn, bool) {
-	conn, ok := p.pool[address]
-	if !ok {
+// Share returns one of the connections of the address in turn, unless the pool has fewer than the max number of connections of the address.
+func (p *connectionPool) Share(address string, maxConns int) (*grpc.ClientConn, bool) {
+	target, ok := p.pool[address]
+	if !ok || len(target.conns) < maxConns {
 		return nil, false
 	}
+	conn := target.conns[(atomic.AddUint32(&target.next, 1)-1)%uint32(len(target.conns))]
 
 	p.referenceLock.Lock()
 	defer p.referenceLock.Unlock()
 
 	p.referenceCount[conn]++
+	p.lastUsed[conn] = time.Now()
 	return conn, true
 }
 
@@ -219,6 +325,10 @@ func (p *connectionPool) Release(conn *grpc.ClientConn) {
 	p.referenceLock.Lock()
 	defer p.referenceLock.Unlock()
 
+	p.release(conn)
+}
+
+func (p *connectionPool) release(conn *grpc.ClientConn) {
 	if _, ok := p.referenceCount[conn]; !ok {
 		return
 	}
@@ -228,5 +338,29 @@ func (p *connectionPool) Release(conn *grpc.ClientConn) {
 	// for concurrent use, connection is closed after all callers release it
 	if p.referenceCount[conn] <= 0 {
 		conn.Close()
+		delete(p.referenceCount, conn)
+		delete(p.lastUsed, conn)
+	}
+}
+
+// CloseIdle removes from the pool and closes the connections used by no caller since the given time.
+func (p *connectionPool) CloseIdle(since time.Time) {
+	p.referenceLock.Lock()
+	defer p.referenceLock.Unlock()
+
+	for address, target := range p.pool {
+		conns := target.conns[:0:0]
+		for _, conn := range target.conns {
+			if p.referenceCount[conn] == 1 && p.lastUsed[conn].Before(since) {
+				p.release(conn)
+				continue
+			}
+			conns = append(conns, conn)
+		}
+		if len(conns) == 0 {
+			delete(p.pool, address)
+			continue
+		}
+		target.conns = conns
 	}
 }
@@ -17,11 +17,17 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"net"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 
+	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/modes"
 	"github.com/dapr/dapr/pkg/runtime/security"
 )
@@ -112,6 +118,93 @@ func TestGetGRPCConnection(t *testing.T) {
 		assert.NoError(t, err)
 		teardown()
 	})
+
+	t.Run("Connection over a Unix domain socket in Kubernetes mode", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "app.socket")
+		listener, err := net.Listen("unix", socket)
+		require.NoError(t, err)
+		server := grpc.NewServer()
+		go server.Serve(listener)
+		defer server.Stop()
+
+		m := NewGRPCManager(modes.KubernetesMode)
+		conn, teardown, err := m.GetGRPCConnection(context.TODO(), UnixDomainSocketScheme+socket, "", "", true, true, false)
+		require.NoError(t, err)
+		defer teardown()
+
+		conn.Connect()
+		assert.Eventually(t, func() bool {
+			return conn.GetState() == connectivity.Ready
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestGetGRPCConnectionsPerTarget(t *testing.T) {
+	m := NewGRPCManager(modes.StandaloneMode)
+	m.SetConnectionConfig(ConnectionConfig{ConnectionsPerTarget: 2, KeepaliveTime: time.Minute, KeepaliveTimeout: time.Second})
+	address := "127.0.0.1:55555"
+
+	conn1, teardown1, err := m.GetGRPCConnection(context.TODO(), address, "", "", false, false, false)
+	require.NoError(t, err)
+	defer teardown1()
+	conn2, teardown2, err := m.GetGRPCConnection(context.TODO(), address, "", "", false, false, false)
+	require.NoError(t, err)
+	defer teardown2()
+	assert.NotSame(t, conn1, conn2, "a connection is created until the pool has the connections per target")
+
+	conn3, teardown3, err := m.GetGRPCConnection(context.TODO(), address, "", "", false, false, false)
+	require.NoError(t, err)
+	defer teardown3()
+	conn4, teardown4, err := m.GetGRPCConnection(context.TODO(), address, "", "", false, false, false)
+	require.NoError(t, err)
+	defer teardown4()
+	assert.NotSame(t, conn3, conn4, "pooled connections are shared in turn")
+	assert.True(t, conn3 == conn1 || conn3 == conn2)
+	assert.True(t, conn4 == conn1 || conn4 == conn2)
+
+	t.Run("connections to the app skip the connection config", func(t *testing.T) {
+		appConn1, appTeardown1, err := m.GetGRPCConnection(context.TODO(), "127.0.0.1:55556", "", "", true, false, false)
+		require.NoError(t, err)
+		defer appTeardown1()
+		appConn2, appTeardown2, err := m.GetGRPCConnection(context.TODO(), "127.0.0.1:55556", "", "", true, false, false)
+		require.NoError(t, err)
+		defer appTeardown2()
+		assert.Same(t, appConn1, appConn2)
+	})
+}
+
+func TestConnectionPoolCloseIdle(t *testing.T) {
+	m := NewGRPCManager(modes.StandaloneMode)
+	inUse, teardownInUse, err := m.GetGRPCConnection(context.TODO(), "127.0.0.1:55555", "", "", true, false, false)
+	require.NoError(t, err)
+	defer teardownInUse()
+	idle, teardownIdle, err := m.GetGRPCConnection(context.TODO(), "127.0.0.1:55556", "", "", true, false, false)
+	require.NoError(t, err)
+	teardownIdle()
+
+	m.connectionPool.CloseIdle(time.Now().Add(time.Second))
+	assert.Equal(t, connectivity.Shutdown, idle.GetState(), "idle connection must be closed")
+	assert.NotEqual(t, connectivity.Shutdown, inUse.GetState(), "connection in use must not be closed")
+
+	conn, teardown, err := m.GetGRPCConnection(context.TODO(), "127.0.0.1:55556", "", "", true, false, false)
+	require.NoError(t, err)
+	defer teardown()
+	assert.NotSame(t, idle, conn, "closed idle connection must not be shared")
+}
+
+func TestCloseIdleConnections(t *testing.T) {
+	m := NewGRPCManager(modes.StandaloneMode)
+	m.SetConnectionConfig(ConnectionConfig{IdleTimeout: 20 * time.Millisecond})
+	conn, teardown, err := m.GetGRPCConnection(context.TODO(), "127.0.0.1:55555", "", "", false, false, false)
+	require.NoError(t, err)
+	teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.CloseIdleConnections(ctx)
+	assert.Eventually(t, func() bool {
+		return conn.GetState() == connectivity.Shutdown
+	}, 5*time.Second, 10*time.Millisecond)
 }
 
 func TestSetAuthenticator(t *testing.T) {
@@ -121,3 +214,24 @@ func TestSetAuthenticator(t *testing.T) {
 
 	assert.Equal(t, a, m.auth)
 }
+
+func TestCreateLocalChannelOverUnixDomainSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.socket")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	go server.Serve(listener)
+	defer server.Stop()
+
+	m := NewGRPCManager(modes.KubernetesMode)
+	ch, err := m.CreateLocalChannelOverUnixDomainSocket(socket, 0, config.TracingSpec{}, 4, 4)
+	require.NoError(t, err)
+	assert.NotNil(t, ch)
+
+	conn := m.AppClient.(*grpc.ClientConn)
+	defer conn.Close()
+	conn.Connect()
+	assert.Eventually(t, func() bool {
+		return conn.GetState() == connectivity.Ready
+	}, 5*time.Second, 10*time.Millisecond)
+}
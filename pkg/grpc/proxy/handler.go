@@ -26,6 +26,12 @@ var clientStreamDescForProxying = &grpc.StreamDesc{
 	ClientStreams: true,
 }
 
+func init() {
+	// The proxy codec replaces the default proto codec of gRPC, so it must be registered after it.
+	// This package imports gRPC, while the codec package may be initialized before the default codec.
+	codec.Register()
+}
+
 // RegisterService sets up a proxy handler for a particular gRPC service and method.
 // The behaviour is the same as if you were registering a handler method, e.g. from a codegenerated pb.go file.
 //
@@ -110,14 +116,17 @@ func (s *handler) handler(srv interface{}, serverStream grpc.ServerStream) error
 	}
 
 	cErr := policy(func(ctx context.Context) (rErr error) {
-		// We require that the director's returned context inherits from the serverStream.Context().
-		outgoingCtx, backendConn, teardown, err := s.director(serverStream.Context(), fullMethodName)
+		// We require that the director's returned context inherits from the context of the policy, which inherits from
+		// the serverStream.Context(): the deadline of the caller and the timeout of the policy are propagated to the
+		// backend, and the backend call is canceled when the caller cancels.
+		outgoingCtx, backendConn, teardown, err := s.director(ctx, fullMethodName)
 		defer teardown()
 		if err != nil {
 			return err
 		}
 
 		clientCtx, clientCancel := context.WithCancel(outgoingCtx)
+		defer clientCancel()
 
 		// TODO(mwitkow): Add a `forwarded` header to metadata, https://en.wikipedia.org/wiki/X-Forwarded-For.
 		clientStream, err := grpc.NewClientStream(clientCtx, clientStreamDescForProxying, backendConn, fullMethodName, grpc.CallContentSubtype((&codec.Proxy{}).Name()))
@@ -139,13 +148,16 @@ func (s *handler) handler(srv interface{}, serverStream grpc.ServerStream) error
 					// the clientStream>serverStream may continue pumping though.
 					clientStream.CloseSend()
 					continue
-				} else {
-					// however, we may have gotten a receive error (stream disconnected, a read error etc) in which case we need
-					// to cancel the clientStream to the backend, let all of its goroutines be freed up by the CancelFunc and
-					// exit with an error to the stack
-					clientCancel()
-					return status.Errorf(codes.Internal, "failed proxying s2c: %v", s2cErr)
 				}
+				// however, we may have gotten a receive error (stream disconnected, a read error etc) in which case we need
+				// to cancel the clientStream to the backend, let all of its goroutines be freed up by the CancelFunc and
+				// exit with an error to the stack
+				clientCancel()
+				if code := status.Code(s2cErr); code == codes.Canceled || code == codes.DeadlineExceeded {
+					// The caller canceled the call or its deadline expired.
+					return s2cErr
+				}
+				return status.Errorf(codes.Internal, "failed proxying s2c: %v", s2cErr)
 			case c2sErr := <-c2sErrChan:
 				// This happens when the clientStream has nothing else to offer (io.EOF), returned a gRPC error. In those two
 				// cases we may have received Trailers as part of the call. In case of other errors (stream closed) the trailers
@@ -174,8 +186,15 @@ func (s *handler) forwardClientToServer(src grpc.ClientStream, dst grpc.ServerSt
 		localHeaders := syncMapValue.(bool)
 		for i := 0; ; i++ {
 			if err := src.RecvMsg(f); err != nil {
+				if !localHeaders {
+					// The backend ended the call without sending any message, e.g. with an error or an empty server stream:
+					// its headers are sent to the caller with the status.
+					if md, hErr := src.Header(); hErr == nil {
+						dst.SetHeader(md)
+					}
+				}
 				ret <- err // this can be io.EOF which is happy case
-				break
+				return
 			}
 			// In the case of retries, don't resend the headers.
 			if i == 0 && !localHeaders {
@@ -184,16 +203,19 @@ func (s *handler) forwardClientToServer(src grpc.ClientStream, dst grpc.ServerSt
 				// This is the only place to do it nicely.
 				md, err := src.Header()
 				if err != nil {
-					break
+					ret <- err
+					return
 				}
 				if err := dst.SendHeader(md); err != nil {
-					break
+					ret <- err
+					return
 				}
 				localHeaders = true
 				s.headersSent.Store(requestID, true)
 			}
 			if err := dst.SendMsg(f); err != nil {
-				break
+				ret <- err
+				return
 			}
 		}
 	}()
@@ -203,25 +225,26 @@ func (s *handler) forwardClientToServer(src grpc.ClientStream, dst grpc.ServerSt
 func (s *handler) forwardServerToClient(src grpc.ServerStream, dst grpc.ClientStream, requestID string) chan error {
 	ret := make(chan error, 1)
 	go func() {
-		f := &codec.Frame{}
 		syncMapValue, _ := s.bufferedCalls.Load(requestID)
 		bufferedFrames := syncMapValue.([]interface{})
 		for _, msg := range bufferedFrames {
 			if err := dst.SendMsg(msg); err != nil {
-				ret <- err
 				return
 			}
 		}
 		for i := 0; ; i++ {
+			// Each message gets its own frame, since the frames of client streams are buffered to be replayed on retries.
+			f := &codec.Frame{}
 			if err := src.RecvMsg(f); err != nil {
 				s.bufferedCalls.Store(requestID, bufferedFrames)
 				ret <- err // this can be io.EOF which is happy case
-				break
+				return
 			}
 			bufferedFrames = append(bufferedFrames, f)
 			if err := dst.SendMsg(f); err != nil {
+				// The backend ended the call: its status is received by forwardClientToServer.
 				s.bufferedCalls.Store(requestID, bufferedFrames)
-				break
+				return
 			}
 		}
 	}()
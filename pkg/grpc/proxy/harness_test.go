@@ -0,0 +1,188 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/dapr/dapr/pkg/grpc/proxy/testservice"
+	"github.com/dapr/dapr/pkg/resiliency"
+)
+
+const harnessMethod = "/dapr.proxy.Harness/Call"
+
+var harnessStreamDesc = &grpc.StreamDesc{
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+// proxyHarness runs a backend with a raw stream handler and a transparent proxy in front of it, so the tests can
+// observe end-to-end what the backend receives from the proxy and what the caller receives back.
+type proxyHarness struct {
+	conn *grpc.ClientConn
+}
+
+func newProxyHarness(t *testing.T, backend grpc.StreamHandler) *proxyHarness {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	backendServer := grpc.NewServer(grpc.UnknownServiceHandler(backend))
+	go backendServer.Serve(backendListener)
+	t.Cleanup(backendServer.Stop)
+
+	backendConn, err := grpc.Dial(backendListener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { backendConn.Close() })
+
+	director := func(ctx context.Context, fullName string) (context.Context, *grpc.ClientConn, func(), error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		return metadata.NewOutgoingContext(ctx, md.Copy()), backendConn, func() {}, nil
+	}
+	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	proxyServer := grpc.NewServer(
+		grpc.UnknownServiceHandler(TransparentHandler(director, resiliency.New(nil), func(string) (bool, error) { return true, nil })),
+	)
+	go proxyServer.Serve(proxyListener)
+	t.Cleanup(proxyServer.Stop)
+
+	conn, err := grpc.Dial(proxyListener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return &proxyHarness{conn: conn}
+}
+
+// call opens a stream to the backend through the proxy.
+func (h *proxyHarness) call(t *testing.T, ctx context.Context) grpc.ClientStream {
+	stream, err := h.conn.NewStream(ctx, harnessStreamDesc, harnessMethod)
+	require.NoError(t, err)
+	return stream
+}
+
+func TestProxyClientStreaming(t *testing.T) {
+	h := newProxyHarness(t, func(srv interface{}, stream grpc.ServerStream) error {
+		values := []string{}
+		for {
+			ping := &pb.PingRequest{}
+			err := stream.RecvMsg(ping)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			values = append(values, ping.Value)
+		}
+		stream.SetHeader(metadata.Pairs(serverHeaderMdKey, "header"))
+		stream.SetTrailer(metadata.Pairs(serverTrailerMdKey, "trailer"))
+		return stream.SendMsg(&pb.PingResponse{Value: strings.Join(values, ","), Counter: int32(len(values))})
+	})
+
+	stream := h.call(t, context.Background())
+	for _, v := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, stream.SendMsg(&pb.PingRequest{Value: v}))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	resp := &pb.PingResponse{}
+	require.NoError(t, stream.RecvMsg(resp))
+	assert.Equal(t, "a,b,c,d", resp.Value)
+	assert.Equal(t, int32(4), resp.Counter)
+	assert.Equal(t, io.EOF, stream.RecvMsg(&pb.PingResponse{}))
+
+	header, err := stream.Header()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"header"}, header.Get(serverHeaderMdKey))
+	assert.Equal(t, []string{"trailer"}, stream.Trailer().Get(serverTrailerMdKey))
+}
+
+func TestProxyCancellation(t *testing.T) {
+	canceled := make(chan error, 1)
+	h := newProxyHarness(t, func(srv interface{}, stream grpc.ServerStream) error {
+		if err := stream.SendMsg(&pb.PingResponse{Value: "first"}); err != nil {
+			return err
+		}
+		<-stream.Context().Done()
+		canceled <- stream.Context().Err()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := h.call(t, ctx)
+	require.NoError(t, stream.CloseSend())
+	require.NoError(t, stream.RecvMsg(&pb.PingResponse{}))
+	cancel()
+
+	select {
+	case err := <-canceled:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the cancellation wasn't propagated to the backend")
+	}
+	assert.Equal(t, codes.Canceled, status.Code(stream.RecvMsg(&pb.PingResponse{})))
+}
+
+func TestProxyDeadline(t *testing.T) {
+	deadlines := make(chan time.Time, 1)
+	h := newProxyHarness(t, func(srv interface{}, stream grpc.ServerStream) error {
+		deadline, _ := stream.Context().Deadline()
+		deadlines <- deadline
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	})
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	stream := h.call(t, ctx)
+	require.NoError(t, stream.CloseSend())
+	err := stream.RecvMsg(&pb.PingResponse{})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	backendDeadline := <-deadlines
+	require.False(t, backendDeadline.IsZero(), "the deadline wasn't propagated to the backend")
+	assert.WithinDuration(t, deadline, backendDeadline, 100*time.Millisecond)
+}
+
+func TestProxyErrorWithHeadersAndTrailers(t *testing.T) {
+	h := newProxyHarness(t, func(srv interface{}, stream grpc.ServerStream) error {
+		stream.SetHeader(metadata.Pairs(serverHeaderMdKey, "header"))
+		stream.SetTrailer(metadata.Pairs(serverTrailerMdKey, "trailer"))
+		return status.Error(codes.NotFound, "not found")
+	})
+
+	stream := h.call(t, context.Background())
+	require.NoError(t, stream.CloseSend())
+	err := stream.RecvMsg(&pb.PingResponse{})
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "not found", st.Message())
+
+	header, err := stream.Header()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"header"}, header.Get(serverHeaderMdKey))
+	assert.Equal(t, []string{"trailer"}, stream.Trailer().Get(serverTrailerMdKey))
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,10 +27,15 @@ import (
 	"github.com/pkg/errors"
 	grpcGo "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	healthv1pb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/acl"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
@@ -40,11 +46,15 @@ import (
 )
 
 const (
-	certWatchInterval              = time.Second * 3
-	renewWhenPercentagePassed      = 70
-	apiServer                      = "apiServer"
-	internalServer                 = "internalServer"
-	defaultMaxConnectionAgeSeconds = 30
+	certWatchInterval         = time.Second * 3
+	renewWhenPercentagePassed = 70
+	apiServer                 = "apiServer"
+	internalServer            = "internalServer"
+	healthServer              = "healthServer"
+	minKeepaliveTime          = time.Second * 10
+
+	// DefaultMaxConnectionAge is the default age after which the internal server closes the connections.
+	DefaultMaxConnectionAge = time.Second * 30
 )
 
 // Server is an interface for the dapr gRPC server.
@@ -54,13 +64,14 @@ type Server interface {
 }
 
 type server struct {
-	api                API
-	config             ServerConfig
-	tracingSpec        config.TracingSpec
-	metricSpec         config.MetricSpec
-	authenticator      auth.Authenticator
-	servers            []*grpcGo.Server
-	renewMutex         *sync.Mutex
+	api           API
+	config        ServerConfig
+	tracingSpec   config.TracingSpec
+	metricSpec    config.MetricSpec
+	authenticator auth.Authenticator
+	servers       []*grpcGo.Server
+	// renewMutex guards the workload certificate, which is read by the TLS handshakes while it is renewed.
+	renewMutex         *sync.RWMutex
 	signedCert         *auth.SignedCertificate
 	tlsCert            tls.Certificate
 	signedCertDuration time.Duration
@@ -71,17 +82,21 @@ type server struct {
 	authToken          string
 	apiSpec            config.APISpec
 	proxy              messaging.Proxy
+	auditor            *diag.Auditor
+	devConsole         *diag.DevConsole
 }
 
 var (
 	apiServerLogger      = logger.NewLogger("dapr.runtime.grpc.api")
 	apiServerInfoLogger  = logger.NewLogger("dapr.runtime.grpc.api-info")
 	internalServerLogger = logger.NewLogger("dapr.runtime.grpc.internal")
+	healthServerLogger   = logger.NewLogger("dapr.runtime.grpc.health")
 )
 
 // NewAPIServer returns a new user facing gRPC API server.
-func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, apiSpec config.APISpec, proxy messaging.Proxy) Server {
+func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, apiSpec config.APISpec, proxy messaging.Proxy, auditor *diag.Auditor, devConsole *diag.DevConsole) Server {
 	apiServerInfoLogger.SetOutputLevel(logger.LogLevel("info"))
+	diag.DefaultRuntimeLogging.AddAPILogger(apiServerInfoLogger)
 	return &server{
 		api:         api,
 		config:      config,
@@ -93,27 +108,44 @@ func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec,
 		authToken:   auth.GetAPIToken(),
 		apiSpec:     apiSpec,
 		proxy:       proxy,
+		auditor:     auditor,
+		devConsole:  devConsole,
 	}
 }
 
 // NewInternalServer returns a new gRPC server for Dapr to Dapr communications.
-func NewInternalServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, authenticator auth.Authenticator, proxy messaging.Proxy) Server {
+func NewInternalServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, authenticator auth.Authenticator, proxy messaging.Proxy, auditor *diag.Auditor, devConsole *diag.DevConsole) Server {
 	return &server{
 		api:              api,
 		config:           config,
 		tracingSpec:      tracingSpec,
 		metricSpec:       metricSpec,
 		authenticator:    authenticator,
-		renewMutex:       &sync.Mutex{},
+		renewMutex:       &sync.RWMutex{},
 		kind:             internalServer,
 		logger:           internalServerLogger,
-		maxConnectionAge: getDefaultMaxAgeDuration(),
+		maxConnectionAge: getMaxConnectionAge(config.Connection),
 		proxy:            proxy,
+		auditor:          auditor,
+		devConsole:       devConsole,
 	}
 }
 
-func getDefaultMaxAgeDuration() *time.Duration {
-	d := time.Second * defaultMaxConnectionAgeSeconds
+// NewHealthServer returns a new gRPC server serving only the health service of the config.
+// It uses neither TLS nor authentication, so that it can be probed by the kubelet.
+func NewHealthServer(config ServerConfig) Server {
+	return &server{
+		config: config,
+		kind:   healthServer,
+		logger: healthServerLogger,
+	}
+}
+
+func getMaxConnectionAge(connection ConnectionConfig) *time.Duration {
+	d := connection.MaxConnectionAge
+	if d <= 0 {
+		d = DefaultMaxConnectionAge
+	}
 	return &d
 }
 
@@ -138,6 +170,10 @@ func (s *server) StartNonBlocking() error {
 		}
 	}
 
+	if s.kind == apiServer {
+		listeners = append(listeners, s.config.Listeners...)
+	}
+
 	if len(listeners) == 0 {
 		return errors.Errorf("could not listen on any endpoint")
 	}
@@ -156,6 +192,9 @@ func (s *server) StartNonBlocking() error {
 		} else if s.kind == apiServer {
 			runtimev1pb.RegisterDaprServer(server, s.api)
 		}
+		if s.config.Health != nil {
+			healthv1pb.RegisterHealthServer(server, s.config.Health)
+		}
 
 		go func(server *grpcGo.Server, l net.Listener) {
 			if err := server.Serve(l); err != nil {
@@ -188,9 +227,11 @@ func (s *server) generateWorkloadCert() error {
 		return errors.Wrap(err, "error creating x509 Key Pair")
 	}
 
+	s.renewMutex.Lock()
 	s.signedCert = signedCert
 	s.tlsCert = tlsCert
 	s.signedCertDuration = signedCert.Expiry.Sub(time.Now().UTC())
+	s.renewMutex.Unlock()
 	return nil
 }
 
@@ -199,9 +240,15 @@ func (s *server) getMiddlewareOptions() []grpcGo.ServerOption {
 	intr := []grpcGo.UnaryServerInterceptor{}
 	intrStream := []grpcGo.StreamServerInterceptor{}
 
-	if len(s.apiSpec.Allowed) > 0 {
+	if len(s.apiSpec.Allowed) > 0 || len(s.apiSpec.Denied) > 0 {
 		s.logger.Info("enabled API access list on gRPC server")
-		intr = append(intr, setAPIEndpointsMiddlewareUnary(s.apiSpec.Allowed))
+		intr = append(intr, setAPIEndpointsMiddlewareUnary(s.apiSpec.Allowed, s.apiSpec.Denied))
+		intrStream = append(intrStream, setAPIEndpointsMiddlewareStream(s.apiSpec.Allowed, s.apiSpec.Denied))
+	}
+
+	if s.kind == apiServer {
+		intr = append(intr, setAPIMaturityMiddlewareUnary(s.apiSpec))
+		intrStream = append(intrStream, setAPIMaturityMiddlewareStream(s.apiSpec))
 	}
 
 	if s.authToken != "" {
@@ -223,9 +270,15 @@ func (s *server) getMiddlewareOptions() []grpcGo.ServerOption {
 		intr = append(intr, diag.DefaultGRPCMonitoring.UnaryServerInterceptor())
 	}
 
-	enableAPILogging := s.config.EnableAPILogging
-	if enableAPILogging {
-		intr = append(intr, s.getGRPCAPILoggingInfo())
+	intr = append(intr, s.getGRPCAPILoggingInfo())
+
+	if s.devConsole != nil {
+		intr = append([]grpcGo.UnaryServerInterceptor{s.getGRPCDevConsoleInterceptor()}, intr...)
+	}
+
+	if s.auditor != nil {
+		s.logger.Info("enabled gRPC audit middleware")
+		intr = append([]grpcGo.UnaryServerInterceptor{s.getGRPCAuditInterceptor()}, intr...)
 	}
 
 	chain := grpcMiddleware.ChainUnaryServer(
@@ -236,7 +289,7 @@ func (s *server) getMiddlewareOptions() []grpcGo.ServerOption {
 		grpcGo.UnaryInterceptor(chain),
 	)
 
-	if s.proxy != nil {
+	if len(intrStream) > 0 {
 		chainStream := grpcMiddleware.ChainStreamServer(
 			intrStream...,
 		)
@@ -250,7 +303,18 @@ func (s *server) getMiddlewareOptions() []grpcGo.ServerOption {
 func (s *server) getGRPCServer() (*grpcGo.Server, error) {
 	opts := s.getMiddlewareOptions()
 	if s.maxConnectionAge != nil {
-		opts = append(opts, grpcGo.KeepaliveParams(keepalive.ServerParameters{MaxConnectionAge: *s.maxConnectionAge}))
+		opts = append(opts, grpcGo.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionAge: *s.maxConnectionAge,
+			Time:             s.config.Connection.KeepaliveTime,
+			Timeout:          s.config.Connection.KeepaliveTimeout,
+		}))
+	}
+	if s.kind == internalServer {
+		// Accept the keepalive pings of the other sidecars, which may be configured with shorter intervals than this one.
+		opts = append(opts, grpcGo.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: minKeepaliveTime, PermitWithoutStream: true}))
+		if s.config.Connection.MaxConcurrentStreams > 0 {
+			opts = append(opts, grpcGo.MaxConcurrentStreams(s.config.Connection.MaxConcurrentStreams))
+		}
 	}
 
 	if s.authenticator != nil {
@@ -261,11 +325,10 @@ func (s *server) getGRPCServer() (*grpcGo.Server, error) {
 
 		//nolint:gosec
 		tlsConfig := tls.Config{
-			ClientCAs:  s.signedCert.TrustChain,
 			ClientAuth: tls.RequireAndVerifyClientCert,
-			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
-				return &s.tlsCert, nil
-			},
+			// The client CAs follow the trust chain of the renewed certificate,
+			// so that the roots added during a rotation are trusted without restarting the server.
+			GetConfigForClient: s.tlsConfigForClient,
 		}
 		ta := credentials.NewTLS(&tlsConfig)
 
@@ -282,26 +345,40 @@ func (s *server) getGRPCServer() (*grpcGo.Server, error) {
 	return grpcGo.NewServer(opts...), nil
 }
 
+// tlsConfigForClient returns the TLS config of a connection with the current workload certificate.
+func (s *server) tlsConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	s.renewMutex.RLock()
+	defer s.renewMutex.RUnlock()
+	//nolint:gosec
+	return &tls.Config{
+		ClientCAs:    s.signedCert.TrustChain,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		Certificates: []tls.Certificate{s.tlsCert},
+	}, nil
+}
+
 func (s *server) startWorkloadCertRotation() {
+	s.renewMutex.RLock()
 	s.logger.Infof("starting workload cert expiry watcher. current cert expires on: %s", s.signedCert.Expiry.String())
+	s.renewMutex.RUnlock()
 
 	ticker := time.NewTicker(certWatchInterval)
 
 	for range ticker.C {
-		s.renewMutex.Lock()
+		s.renewMutex.RLock()
 		renew := shouldRenewCert(s.signedCert.Expiry, s.signedCertDuration)
+		s.renewMutex.RUnlock()
 		if renew {
 			s.logger.Info("renewing certificate: requesting new cert and restarting gRPC server")
 
+			// The certificate is requested without holding the lock, so that the handshakes aren't blocked meanwhile
 			err := s.generateWorkloadCert()
 			if err != nil {
 				s.logger.Errorf("error starting server: %s", err)
-				s.renewMutex.Unlock()
 				continue
 			}
 			diag.DefaultMonitoring.MTLSWorkLoadCertRotationCompleted()
 		}
-		s.renewMutex.Unlock()
 	}
 }
 
@@ -316,9 +393,110 @@ func shouldRenewCert(certExpiryDate time.Time, certDuration time.Duration) bool
 
 func (s *server) getGRPCAPILoggingInfo() grpcGo.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpcGo.UnaryServerInfo, handler grpcGo.UnaryHandler) (interface{}, error) {
-		if s.infoLogger != nil && info != nil {
+		// The API logging can be toggled at runtime
+		if s.infoLogger != nil && info != nil && diag.DefaultRuntimeLogging.APILoggingEnabled(s.config.EnableAPILogging) {
 			s.infoLogger.Info("gRPC API Called: ", info.FullMethod)
 		}
 		return handler(ctx, req)
 	}
 }
+
+// getGRPCAuditInterceptor records the unary calls in the audit log.
+// The calls rejected by the other interceptors are recorded too, so it comes first in the chain.
+func (s *server) getGRPCAuditInterceptor() grpcGo.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpcGo.UnaryServerInfo, handler grpcGo.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		record := diag.AuditRecord{
+			Time:        start,
+			CallerAppID: s.auditCallerAppID(ctx),
+			Protocol:    diag.AuditProtocolGRPC,
+			Method:      info.FullMethod,
+			Target:      auditTarget(req),
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		record.Headers = s.auditor.Headers(func(name string) string {
+			if v := md.Get(name); len(v) > 0 {
+				return v[0]
+			}
+			return ""
+		})
+		if msg, ok := req.(proto.Message); ok && s.auditor.RecordsPayloadHash() {
+			if payload, err := proto.Marshal(msg); err == nil {
+				record.PayloadHash = s.auditor.PayloadHash(payload)
+			}
+		}
+
+		resp, err := handler(ctx, req)
+
+		record.Result = status.Code(err).String()
+		record.DurationMs = float64(time.Since(start)) / float64(time.Millisecond)
+		s.auditor.Record(record)
+		return resp, err
+	}
+}
+
+// getGRPCDevConsoleInterceptor records the unary calls in the developer console.
+func (s *server) getGRPCDevConsoleInterceptor() grpcGo.UnaryServerInterceptor {
+	kind := diag.DevConsoleKindAPI
+	if s.kind == internalServer {
+		kind = diag.DevConsoleKindInvocation
+	}
+	return func(ctx context.Context, req interface{}, info *grpcGo.UnaryServerInfo, handler grpcGo.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		event := diag.DevConsoleEvent{
+			Time:       start,
+			Kind:       kind,
+			Protocol:   diag.AuditProtocolGRPC,
+			Method:     info.FullMethod,
+			Target:     auditTarget(req),
+			Result:     status.Code(err).String(),
+			DurationMs: float64(time.Since(start)) / float64(time.Millisecond),
+		}
+		if strings.HasSuffix(info.FullMethod, "/InvokeActor") || strings.HasSuffix(info.FullMethod, "/CallActor") {
+			event.Kind = diag.DevConsoleKindActor
+		}
+		s.devConsole.Record(event)
+		return resp, err
+	}
+}
+
+// auditCallerAppID returns the ID of the app calling the server.
+// The user facing APIs are called by the app of the sidecar, while the internal API is called by the other sidecars,
+// which are identified by their certificate when mTLS is enabled.
+func (s *server) auditCallerAppID(ctx context.Context) string {
+	if s.kind == apiServer {
+		return s.config.AppID
+	}
+	id, err := acl.GetAndParseSpiffeID(ctx)
+	if err != nil || id == nil {
+		return ""
+	}
+	return id.AppID
+}
+
+// auditTarget returns the name of the component, the app or the actor type a request is made to.
+// The keys and the IDs of the resources are not recorded, as they can contain personal data.
+func auditTarget(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetActorType() string }:
+		return r.GetActorType()
+	case interface{ GetStoreName() string }:
+		return r.GetStoreName()
+	case interface{ GetPubsubName() string }:
+		return r.GetPubsubName()
+	case interface{ GetWorkflowName() string }:
+		return r.GetWorkflowName()
+	case interface{ GetComponentName() string }:
+		return r.GetComponentName()
+	case *runtimev1pb.InvokeServiceRequest:
+		return r.GetId()
+	case *runtimev1pb.InvokeBindingRequest:
+		return r.GetName()
+	case *internalv1pb.InternalInvokeRequest:
+		return r.GetActor().GetActorType()
+	}
+	return ""
+}
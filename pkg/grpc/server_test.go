@@ -1,7 +1,12 @@
 package grpc
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -9,10 +14,22 @@ import (
 	"github.com/phayes/freeport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	grpcGo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthv1pb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/dapr/kit/logger"
 
 	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/credentials"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/dapr/pkg/runtime/security"
 	dapr_testing "github.com/dapr/dapr/pkg/testing"
 )
 
@@ -35,6 +52,56 @@ func TestCertRenewal(t *testing.T) {
 	})
 }
 
+// rotatingAuthenticator signs a workload certificate expiring later at each request.
+type rotatingAuthenticator struct {
+	authenticatorMock
+	chain *credentials.CertChain
+	lock  sync.Mutex
+	count int
+}
+
+func (a *rotatingAuthenticator) CreateSignedWorkloadCert(id, namespace, trustDomain string) (*security.SignedCertificate, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.count++
+	return &security.SignedCertificate{
+		WorkloadCert:  a.chain.Cert,
+		PrivateKeyPem: a.chain.Key,
+		Expiry:        time.Now().Add(time.Duration(a.count) * time.Hour),
+		TrustChain:    x509.NewCertPool(),
+	}, nil
+}
+
+func TestWorkloadCertRenewal(t *testing.T) {
+	_, workload := dapr_testing.GenerateCertChains(t, security.TLSServerName, "spiffe://cluster.local/ns/default/myapp")
+	s := &server{
+		authenticator: &rotatingAuthenticator{chain: workload},
+		renewMutex:    &sync.RWMutex{},
+		logger:        logger.NewLogger("dapr.runtime.grpc.test"),
+	}
+	require.NoError(t, s.generateWorkloadCert())
+
+	// The handshakes read the certificate while it is renewed
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			assert.NoError(t, s.generateWorkloadCert())
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			config, err := s.tlsConfigForClient(nil)
+			assert.NoError(t, err)
+			assert.Len(t, config.Certificates, 1)
+			assert.NotNil(t, config.ClientCAs)
+		}
+	}()
+	wg.Wait()
+}
+
 func TestGetMiddlewareOptions(t *testing.T) {
 	t.Run("should enable unary interceptor if tracing and metrics are enabled", func(t *testing.T) {
 		fakeServer := &server{
@@ -42,7 +109,7 @@ func TestGetMiddlewareOptions(t *testing.T) {
 			tracingSpec: config.TracingSpec{
 				SamplingRate: "1",
 			},
-			renewMutex: &sync.Mutex{},
+			renewMutex: &sync.RWMutex{},
 			logger:     logger.NewLogger("dapr.runtime.grpc.test"),
 		}
 
@@ -57,7 +124,7 @@ func TestGetMiddlewareOptions(t *testing.T) {
 			tracingSpec: config.TracingSpec{
 				SamplingRate: "0",
 			},
-			renewMutex: &sync.Mutex{},
+			renewMutex: &sync.RWMutex{},
 			logger:     logger.NewLogger("dapr.runtime.grpc.test"),
 		}
 
@@ -72,7 +139,7 @@ func TestGetMiddlewareOptions(t *testing.T) {
 			tracingSpec: config.TracingSpec{
 				SamplingRate: "0",
 			},
-			renewMutex: &sync.Mutex{},
+			renewMutex: &sync.RWMutex{},
 			logger:     logger.NewLogger("dapr.runtime.grpc.test"),
 			apiSpec: config.APISpec{
 				Allowed: []config.APIAccessRule{
@@ -86,8 +153,88 @@ func TestGetMiddlewareOptions(t *testing.T) {
 
 		serverOption := fakeServer.getMiddlewareOptions()
 
-		assert.Equal(t, 1, len(serverOption))
+		assert.Equal(t, 2, len(serverOption))
+	})
+}
+
+func TestGRPCAuditInterceptor(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := diag.NewAuditor("myapp", config.AuditSpec{
+		Enabled:     true,
+		Output:      output,
+		Headers:     []string{"dapr-api-token", "x-request-id"},
+		PayloadHash: true,
+	})
+	require.NoError(t, err)
+
+	fakeServer := &server{
+		config:  ServerConfig{AppID: "myapp"},
+		kind:    apiServer,
+		auditor: auditor,
+	}
+	interceptor := fakeServer.getGRPCAuditInterceptor()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("dapr-api-token", "secret", "x-request-id", "42"))
+	info := &grpcGo.UnaryServerInfo{FullMethod: "/dapr.proto.runtime.v1.Dapr/GetState"}
+	req := &runtimev1pb.GetStateRequest{StoreName: "statestore", Key: "jane@example.com"}
+
+	_, err = interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
 	})
+	assert.Error(t, err)
+	require.NoError(t, auditor.Close())
+
+	b, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "jane@example.com")
+	assert.NotContains(t, string(b), "secret")
+
+	var record diag.AuditRecord
+	require.NoError(t, json.Unmarshal(b, &record))
+	assert.Equal(t, "myapp", record.CallerAppID)
+	assert.Equal(t, diag.AuditProtocolGRPC, record.Protocol)
+	assert.Equal(t, info.FullMethod, record.Method)
+	assert.Equal(t, "statestore", record.Target)
+	assert.Equal(t, "NotFound", record.Result)
+	assert.Equal(t, map[string]string{"dapr-api-token": "[REDACTED]", "x-request-id": "42"}, record.Headers)
+	assert.Len(t, record.PayloadHash, 64)
+}
+
+func TestGRPCDevConsoleInterceptor(t *testing.T) {
+	devConsole := diag.NewDevConsole("myapp")
+	events, unsubscribe := devConsole.Subscribe()
+	defer unsubscribe()
+
+	fakeServer := &server{kind: internalServer, devConsole: devConsole}
+	interceptor := fakeServer.getGRPCDevConsoleInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	info := &grpcGo.UnaryServerInfo{FullMethod: "/dapr.proto.internals.v1.ServiceInvocation/CallActor"}
+	req := &internalv1pb.InternalInvokeRequest{Actor: &internalv1pb.Actor{ActorType: "myactor", ActorId: "1"}}
+	_, err := interceptor(context.Background(), req, info, handler)
+	require.NoError(t, err)
+	event := <-events
+	assert.Equal(t, diag.DevConsoleKindActor, event.Kind)
+	assert.Equal(t, info.FullMethod, event.Method)
+	assert.Equal(t, "myactor", event.Target)
+	assert.Equal(t, "OK", event.Result)
+
+	info = &grpcGo.UnaryServerInfo{FullMethod: "/dapr.proto.internals.v1.ServiceInvocation/CallLocal"}
+	_, err = interceptor(context.Background(), &internalv1pb.InternalInvokeRequest{}, info, handler)
+	require.NoError(t, err)
+	event = <-events
+	assert.Equal(t, diag.DevConsoleKindInvocation, event.Kind)
+}
+
+func TestAuditTarget(t *testing.T) {
+	assert.Equal(t, "myactor", auditTarget(&runtimev1pb.InvokeActorRequest{ActorType: "myactor", ActorId: "1"}))
+	assert.Equal(t, "pubsub", auditTarget(&runtimev1pb.PublishEventRequest{PubsubName: "pubsub", Topic: "orders"}))
+	assert.Equal(t, "otherapp", auditTarget(&runtimev1pb.InvokeServiceRequest{Id: "otherapp"}))
+	assert.Equal(t, "binding", auditTarget(&runtimev1pb.InvokeBindingRequest{Name: "binding"}))
+	assert.Equal(t, "myactor", auditTarget(&internalv1pb.InternalInvokeRequest{Actor: &internalv1pb.Actor{ActorType: "myactor"}}))
+	assert.Empty(t, auditTarget(&internalv1pb.InternalInvokeRequest{}))
+	assert.Empty(t, auditTarget(nil))
 }
 
 func TestClose(t *testing.T) {
@@ -96,7 +243,7 @@ func TestClose(t *testing.T) {
 		require.NoError(t, err)
 		serverConfig := NewServerConfig("test", "127.0.0.1", port, []string{"127.0.0.1"}, "test", "test", 4, "", 4, true)
 		a := &api{}
-		server := NewAPIServer(a, serverConfig, config.TracingSpec{}, config.MetricSpec{}, config.APISpec{}, nil)
+		server := NewAPIServer(a, serverConfig, config.TracingSpec{}, config.MetricSpec{}, config.APISpec{}, nil, nil, nil)
 		require.NoError(t, server.StartNonBlocking())
 		dapr_testing.WaitForListeningAddress(t, 5*time.Second, fmt.Sprintf("127.0.0.1:%d", port))
 		assert.NoError(t, server.Close())
@@ -107,9 +254,59 @@ func TestClose(t *testing.T) {
 		require.NoError(t, err)
 		serverConfig := NewServerConfig("test", "127.0.0.1", port, []string{"127.0.0.1"}, "test", "test", 4, "", 4, false)
 		a := &api{}
-		server := NewAPIServer(a, serverConfig, config.TracingSpec{}, config.MetricSpec{}, config.APISpec{}, nil)
+		server := NewAPIServer(a, serverConfig, config.TracingSpec{}, config.MetricSpec{}, config.APISpec{}, nil, nil, nil)
 		require.NoError(t, server.StartNonBlocking())
 		dapr_testing.WaitForListeningAddress(t, 5*time.Second, fmt.Sprintf("127.0.0.1:%d", port))
 		assert.NoError(t, server.Close())
 	})
 }
+
+func TestHealthService(t *testing.T) {
+	check := func(t *testing.T, port int) (healthv1pb.HealthCheckResponse_ServingStatus, error) {
+		t.Helper()
+		dapr_testing.WaitForListeningAddress(t, 5*time.Second, fmt.Sprintf("127.0.0.1:%d", port))
+		conn, err := grpcGo.Dial(fmt.Sprintf("127.0.0.1:%d", port), grpcGo.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+		resp, err := healthv1pb.NewHealthClient(conn).Check(context.Background(), &healthv1pb.HealthCheckRequest{})
+		return resp.GetStatus(), err
+	}
+
+	h := health.NewServer()
+	h.SetServingStatus("", healthv1pb.HealthCheckResponse_NOT_SERVING)
+
+	t.Run("health server", func(t *testing.T) {
+		port, err := freeport.GetFreePort()
+		require.NoError(t, err)
+		serverConfig := NewServerConfig("test", "127.0.0.1", port, []string{"127.0.0.1"}, "test", "test", 4, "", 4, false)
+		serverConfig.Health = h
+		server := NewHealthServer(serverConfig)
+		require.NoError(t, server.StartNonBlocking())
+		defer server.Close()
+
+		status, err := check(t, port)
+		require.NoError(t, err)
+		assert.Equal(t, healthv1pb.HealthCheckResponse_NOT_SERVING, status)
+
+		h.SetServingStatus("", healthv1pb.HealthCheckResponse_SERVING)
+		status, err = check(t, port)
+		require.NoError(t, err)
+		assert.Equal(t, healthv1pb.HealthCheckResponse_SERVING, status)
+	})
+
+	t.Run("API server with token authentication and access list", func(t *testing.T) {
+		port, err := freeport.GetFreePort()
+		require.NoError(t, err)
+		serverConfig := NewServerConfig("test", "127.0.0.1", port, []string{"127.0.0.1"}, "test", "test", 4, "", 4, false)
+		serverConfig.Health = h
+		apiSpec := config.APISpec{Allowed: []config.APIAccessRule{{Name: "state", Version: "v1", Protocol: "grpc"}}}
+		daprServer := NewAPIServer(&api{}, serverConfig, config.TracingSpec{}, config.MetricSpec{}, apiSpec, nil, nil, nil)
+		daprServer.(*server).authToken = "token"
+		require.NoError(t, daprServer.StartNonBlocking())
+		defer daprServer.Close()
+
+		status, err := check(t, port)
+		require.NoError(t, err)
+		assert.Equal(t, healthv1pb.HealthCheckResponse_SERVING, status)
+	})
+}
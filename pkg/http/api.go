@@ -14,6 +14,8 @@ limitations under the License.
 package http
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -21,7 +23,6 @@ import (
 	nethttp "net/http"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/fasthttp/router"
@@ -35,6 +36,7 @@ import (
 	"github.com/dapr/components-contrib/bindings"
 	"github.com/dapr/components-contrib/configuration"
 	"github.com/dapr/components-contrib/lock"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
 	lockLoader "github.com/dapr/dapr/pkg/components/lock"
 	"github.com/dapr/dapr/pkg/version"
 
@@ -57,7 +59,11 @@ import (
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	"github.com/dapr/dapr/pkg/resiliency"
 	"github.com/dapr/dapr/pkg/resiliency/breaker"
+	runtimeConfiguration "github.com/dapr/dapr/pkg/runtime/configuration"
+	"github.com/dapr/dapr/pkg/runtime/meta"
 	runtimePubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	"github.com/dapr/dapr/pkg/runtime/workflow"
+	"github.com/dapr/dapr/pkg/statemerge"
 )
 
 // API returns a list of HTTP endpoints for Dapr.
@@ -65,10 +71,23 @@ type API interface {
 	APIEndpoints() []Endpoint
 	PublicEndpoints() []Endpoint
 	MarkStatusAsReady()
+	MarkStatusAsNotReady()
 	MarkStatusAsOutboundReady()
 	SetAppChannel(appChannel channel.AppChannel)
 	SetDirectMessaging(directMessaging messaging.DirectMessaging)
 	SetActorRuntime(actor actors.Actors)
+	SetWorkflowEngine(engine *workflow.Engine)
+	SetRuntimeMetadataFn(getRuntimeMetadataFn func() meta.Info)
+	SetMetadataAttributes(attributes *meta.AttributeStore)
+	SetSubscriptionScalingFn(getSubscriptionScalingFn func() []runtimePubsub.ScalingSignals)
+	SetInputBindingPauser(pauser InputBindingPauser)
+}
+
+// InputBindingPauser is implemented by the runtime to let operators stop reading from input bindings without deleting them.
+type InputBindingPauser interface {
+	PauseInputBinding(name string) error
+	ResumeInputBinding(name string) error
+	PausedInputBindings() []string
 }
 
 type api struct {
@@ -80,36 +99,123 @@ type api struct {
 	resiliency                 resiliency.Provider
 	stateStores                map[string]state.Store
 	lockStores                 map[string]lock.Store
+	cryptoProviders            map[string]cryptoLoader.SubtleCrypto
 	configurationStores        map[string]configuration.Store
 	configurationSubscribe     map[string]chan struct{}
 	transactionalStateStores   map[string]state.TransactionalStore
 	secretStores               map[string]secretstores.SecretStore
 	secretsConfiguration       map[string]config.SecretsScope
 	actor                      actors.Actors
+	workflowEngine             *workflow.Engine
 	pubsubAdapter              runtimePubsub.Adapter
 	sendToOutputBindingFn      func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error)
 	id                         string
-	extendedMetadata           sync.Map
+	extendedMetadata           *meta.AttributeStore
 	readyStatus                bool
 	outboundReadyStatus        bool
 	tracingSpec                config.TracingSpec
 	shutdown                   func()
 	getComponentsCapabilitesFn func() map[string][]string
+	getRuntimeMetadataFn       func() meta.Info
+	getSubscriptionScalingFn   func() []runtimePubsub.ScalingSignals
+	inputBindingPauser         InputBindingPauser
 	daprRunTimeVersion         string
 }
 
 type registeredComponent struct {
-	Name         string   `json:"name"`
-	Type         string   `json:"type"`
-	Version      string   `json:"version"`
-	Capabilities []string `json:"capabilities"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Version        string   `json:"version"`
+	Capabilities   []string `json:"capabilities"`
+	Status         string   `json:"status,omitempty"`
+	LastError      string   `json:"lastError,omitempty"`
+	LastErrorClass string   `json:"lastErrorClass,omitempty"`
+}
+
+type metadataSubscription struct {
+	PubsubName      string                     `json:"pubsubname"`
+	Topic           string                     `json:"topic"`
+	DeadLetterTopic string                     `json:"deadLetterTopic,omitempty"`
+	Metadata        map[string]string          `json:"metadata,omitempty"`
+	Rules           []metadataSubscriptionRule `json:"rules,omitempty"`
+}
+
+type metadataSubscriptionRule struct {
+	Match string `json:"match,omitempty"`
+	Path  string `json:"path"`
+}
+
+type metadataAppConnection struct {
+	Port           int                          `json:"port,omitempty"`
+	Protocol       string                       `json:"protocol,omitempty"`
+	MaxConcurrency int                          `json:"maxConcurrency,omitempty"`
+	Health         *metadataAppConnectionHealth `json:"health,omitempty"`
+}
+
+type metadataAppConnectionHealth struct {
+	HealthCheckPath     string `json:"healthCheckPath,omitempty"`
+	HealthProbeInterval string `json:"healthProbeInterval,omitempty"`
+	HealthProbeTimeout  string `json:"healthProbeTimeout,omitempty"`
+	HealthThreshold     int32  `json:"healthThreshold,omitempty"`
+	Status              string `json:"status,omitempty"`
 }
 
 type metadata struct {
-	ID                   string                     `json:"id"`
-	ActiveActorsCount    []actors.ActiveActorsCount `json:"actors"`
-	Extended             map[string]string          `json:"extended"`
-	RegisteredComponents []registeredComponent      `json:"components"`
+	ID                   string                             `json:"id"`
+	ActiveActorsCount    []actors.ActiveActorsCount         `json:"actors"`
+	Placement            *actors.PlacementStatus            `json:"placement,omitempty"`
+	Extended             map[string]string                  `json:"extended"`
+	RegisteredComponents []registeredComponent              `json:"components"`
+	PausedSubscriptions  []runtimePubsub.PausedSubscription `json:"pausedSubscriptions,omitempty"`
+	PausedInputBindings  []string                           `json:"pausedInputBindings,omitempty"`
+	Subscriptions        []metadataSubscription             `json:"subscriptions,omitempty"`
+	ActorTypes           []string                           `json:"actorTypes,omitempty"`
+	AppConnection        *metadataAppConnection             `json:"appConnectionProperties,omitempty"`
+	EnabledFeatures      []string                           `json:"enabledFeatures,omitempty"`
+	Features             []metadataFeature                  `json:"features,omitempty"`
+	Attributes           []metadataAttribute                `json:"attributes,omitempty"`
+	Drift                *metadataDrift                     `json:"drift,omitempty"`
+	CrossNamespacePolicy *metadataCrossNamespacePolicy      `json:"crossNamespacePolicy,omitempty"`
+}
+
+type metadataFeature struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Default bool   `json:"default"`
+	// Registered is false for the features of the configuration without a feature gate.
+	Registered bool `json:"registered"`
+}
+
+type metadataCrossNamespacePolicy struct {
+	AllowedCallers []metadataCrossNamespaceCaller `json:"allowedCallers"`
+}
+
+type metadataCrossNamespaceCaller struct {
+	Namespace string   `json:"namespace"`
+	AppID     string   `json:"appId,omitempty"`
+	Callees   []string `json:"callees,omitempty"`
+}
+
+type metadataDrift struct {
+	Detected      bool      `json:"detected"`
+	Components    []string  `json:"components,omitempty"`
+	Configuration bool      `json:"configuration"`
+	LastChecked   time.Time `json:"lastChecked"`
+}
+
+type subscriptionScaling struct {
+	PubsubName        string  `json:"pubsubname"`
+	Topic             string  `json:"topic"`
+	PendingDeliveries int64   `json:"pendingDeliveries"`
+	ProcessingRate    float64 `json:"processingRate"`
+	Lag               *int64  `json:"lag,omitempty"`
+}
+
+type metadataAttribute struct {
+	Key       string            `json:"key"`
+	Value     string            `json:"value"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty"`
 }
 
 const (
@@ -130,10 +236,25 @@ const (
 	consistencyParam         = "consistency"
 	concurrencyParam         = "concurrency"
 	pubsubnameparam          = "pubsubname"
+	durationParam            = "duration"
+	workflowNameParam        = "workflowName"
+	workflowInstanceIDParam  = "instanceID"
+	workflowEventNameParam   = "eventName"
+	workflowTimerIDParam     = "timerID"
+	secretPrefixParam        = "prefix"
+	daprKeyNameHeader        = "dapr-key-name"
+	daprKeyWrapAlgHeader     = "dapr-key-wrap-algorithm"
 	traceparentHeader        = "traceparent"
 	tracestateHeader         = "tracestate"
+	baggageHeader            = "baggage"
 	daprAppID                = "dapr-app-id"
 	daprRuntimeVersionKey    = "daprRuntimeVersion"
+	metadataTTLParam         = "ttlInSeconds"
+	metadataLabelPrefix      = "label."
+
+	// Size of the buffer of updates for a configuration stream, and interval of the keep-alive messages.
+	configurationStreamBuffer    = 16
+	configurationStreamKeepAlive = 15 * time.Second
 )
 
 // NewAPI returns a new API.
@@ -145,6 +266,7 @@ func NewAPI(
 	resiliency resiliency.Provider,
 	stateStores map[string]state.Store,
 	lockStores map[string]lock.Store,
+	cryptoProviders map[string]cryptoLoader.SubtleCrypto,
 	secretStores map[string]secretstores.SecretStore,
 	secretsConfiguration map[string]config.SecretsScope,
 	configurationStores map[string]configuration.Store,
@@ -168,6 +290,7 @@ func NewAPI(
 		directMessaging:            directMessaging,
 		stateStores:                stateStores,
 		lockStores:                 lockStores,
+		cryptoProviders:            cryptoProviders,
 		transactionalStateStores:   transactionalStateStores,
 		secretStores:               secretStores,
 		secretsConfiguration:       secretsConfiguration,
@@ -181,6 +304,7 @@ func NewAPI(
 		shutdown:                   shutdown,
 		getComponentsCapabilitesFn: getComponentsCapabilitiesFn,
 		daprRunTimeVersion:         version.Version(),
+		extendedMetadata:           meta.NewAttributeStore(),
 	}
 
 	metadataEndpoints := api.constructMetadataEndpoints()
@@ -189,14 +313,21 @@ func NewAPI(
 	api.endpoints = append(api.endpoints, api.constructStateEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructSecretEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructPubSubEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructSubscriptionEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructActorEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructDirectMessagingEndpoints()...)
 	api.endpoints = append(api.endpoints, metadataEndpoints...)
 	api.endpoints = append(api.endpoints, api.constructShutdownEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructBindingsEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructInputBindingEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructConfigurationEndpoints()...)
 	api.endpoints = append(api.endpoints, healthEndpoints...)
 	api.endpoints = append(api.endpoints, api.constructDistributedLockEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructWorkflowEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructCryptoEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructComponentTracingEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructLoggingEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructResiliencyEndpoints()...)
 
 	api.publicEndpoints = append(api.publicEndpoints, metadataEndpoints...)
 	api.publicEndpoints = append(api.publicEndpoints, healthEndpoints...)
@@ -219,6 +350,11 @@ func (a *api) MarkStatusAsReady() {
 	a.readyStatus = true
 }
 
+// MarkStatusAsNotReady marks dapr as not ready, so that no more traffic is sent to it while it shuts down.
+func (a *api) MarkStatusAsNotReady() {
+	a.readyStatus = false
+}
+
 // MarkStatusAsOutboundReady marks the ready status of dapr for outbound traffic.
 func (a *api) MarkStatusAsOutboundReady() {
 	a.outboundReadyStatus = true
@@ -262,6 +398,18 @@ func (a *api) constructStateEndpoints() []Endpoint {
 			Version: apiVersionV1alpha1,
 			Handler: a.onQueryState,
 		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "state/{storeName}/bulk/set",
+			Version: apiVersionV1alpha1,
+			Handler: a.onBulkSetState,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "state/{storeName}/bulk/delete",
+			Version: apiVersionV1alpha1,
+			Handler: a.onBulkDeleteState,
+		},
 	}
 }
 
@@ -293,6 +441,29 @@ func (a *api) constructPubSubEndpoints() []Endpoint {
 	}
 }
 
+func (a *api) constructSubscriptionEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "subscriptions/{pubsubname}/{topic}/pause",
+			Version: apiVersionV1alpha1,
+			Handler: a.onPauseSubscription,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "subscriptions/{pubsubname}/{topic}/resume",
+			Version: apiVersionV1alpha1,
+			Handler: a.onResumeSubscription,
+		},
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "subscriptions/scaling",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetSubscriptionScaling,
+		},
+	}
+}
+
 func (a *api) constructBindingsEndpoints() []Endpoint {
 	return []Endpoint{
 		{
@@ -304,6 +475,23 @@ func (a *api) constructBindingsEndpoints() []Endpoint {
 	}
 }
 
+func (a *api) constructInputBindingEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "bindings/{name}/pause",
+			Version: apiVersionV1alpha1,
+			Handler: a.onPauseInputBinding,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "bindings/{name}/resume",
+			Version: apiVersionV1alpha1,
+			Handler: a.onResumeInputBinding,
+		},
+	}
+}
+
 func (a *api) constructDirectMessagingEndpoints() []Endpoint {
 	return []Endpoint{
 		{
@@ -368,10 +556,23 @@ func (a *api) constructActorEndpoints() []Endpoint {
 			Handler: a.onGetActorReminder,
 		},
 		{
-			Methods: []string{fasthttp.MethodPatch},
-			Route:   "actors/{actorType}/{actorId}/reminders/{name}",
-			Version: apiVersionV1,
-			Handler: a.onRenameActorReminder,
+			Methods:    []string{fasthttp.MethodPatch},
+			Route:      "actors/{actorType}/{actorId}/reminders/{name}",
+			Version:    apiVersionV1,
+			Deprecated: true,
+			Handler:    a.onRenameActorReminder,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "actors/placement/simulate",
+			Version: apiVersionV1alpha1,
+			Handler: a.onSimulateActorPlacement,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "actors/{actorType}/state",
+			Version: apiVersionV1alpha1,
+			Handler: a.onMultiActorStateTransaction,
 		},
 	}
 }
@@ -404,6 +605,57 @@ func (a *api) constructShutdownEndpoints() []Endpoint {
 	}
 }
 
+func (a *api) constructComponentTracingEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "tracing/components",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetComponentTracing,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "tracing/components/{name}",
+			Version: apiVersionV1alpha1,
+			Handler: a.onEnableComponentTracing,
+		},
+		{
+			Methods: []string{fasthttp.MethodDelete},
+			Route:   "tracing/components/{name}",
+			Version: apiVersionV1alpha1,
+			Handler: a.onDisableComponentTracing,
+		},
+	}
+}
+
+func (a *api) constructLoggingEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "logging/level",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetLogLevel,
+		},
+		{
+			Methods: []string{fasthttp.MethodPut},
+			Route:   "logging/level",
+			Version: apiVersionV1alpha1,
+			Handler: a.onSetLogLevel,
+		},
+	}
+}
+
+func (a *api) constructResiliencyEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "resiliency",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetResiliency,
+		},
+	}
+}
+
 func (a *api) constructHealthzEndpoints() []Endpoint {
 	return []Endpoint{
 		{
@@ -435,6 +687,12 @@ func (a *api) constructConfigurationEndpoints() []Endpoint {
 			Version: apiVersionV1alpha1,
 			Handler: a.onSubscribeConfiguration,
 		},
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "configuration/{storeName}/subscribe/stream",
+			Version: apiVersionV1alpha1,
+			Handler: a.onSubscribeConfigurationStream,
+		},
 		{
 			Methods: []string{fasthttp.MethodGet},
 			Route:   "configuration/{storeName}/{configurationSubscribeID}/unsubscribe",
@@ -461,6 +719,100 @@ func (a *api) constructDistributedLockEndpoints() []Endpoint {
 	}
 }
 
+func (a *api) constructWorkflowEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "workflows/{workflowName}/{instanceID}/start",
+			Version: apiVersionV1alpha1,
+			Handler: a.onStartWorkflow,
+		},
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "workflows/{workflowName}/{instanceID}",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetWorkflow,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "workflows/{workflowName}/{instanceID}/terminate",
+			Version: apiVersionV1alpha1,
+			Handler: a.onTerminateWorkflow,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "workflows/{workflowName}/{instanceID}/raiseEvent/{eventName}",
+			Version: apiVersionV1alpha1,
+			Handler: a.onRaiseEventWorkflow,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "workflows/{workflowName}/{instanceID}/pause",
+			Version: apiVersionV1alpha1,
+			Handler: a.onPauseWorkflow,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "workflows/{workflowName}/{instanceID}/resume",
+			Version: apiVersionV1alpha1,
+			Handler: a.onResumeWorkflow,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "workflows/{workflowName}/{instanceID}/purge",
+			Version: apiVersionV1alpha1,
+			Handler: a.onPurgeWorkflow,
+		},
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "workflows/{workflowName}/{instanceID}/pending",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetWorkflowPending,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "workflows/{workflowName}/{instanceID}/timers/{timerID}/cancel",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCancelWorkflowTimer,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "workflows/{workflowName}/{instanceID}/timers/{timerID}/reschedule",
+			Version: apiVersionV1alpha1,
+			Handler: a.onRescheduleWorkflowTimer,
+		},
+	}
+}
+
+func (a *api) constructCryptoEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "crypto/{name}/encrypt",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCryptoEncrypt,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "crypto/{name}/decrypt",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCryptoDecrypt,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "crypto/{name}/sign",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCryptoSign,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "crypto/{name}/verify",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCryptoVerify,
+		},
+	}
+}
+
 func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 	name := reqCtx.UserValue(nameParam).(string)
 	body := reqCtx.PostBody()
@@ -506,6 +858,7 @@ func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 	elapsed := diag.ElapsedSince(start)
 
 	diag.DefaultComponentMonitoring.OutputBindingEvent(context.Background(), name, req.Operation, err == nil, elapsed)
+	diag.DefaultComponentMonitoring.OutputBindingPayload(context.Background(), name, req.Operation, req.Metadata[diag.PayloadSchemaMetadataKey], len(b))
 
 	if err != nil {
 		msg := NewErrorResponse("ERR_INVOKE_OUTPUT_BINDING", fmt.Sprintf(messages.ErrInvokeOutputBinding, name, err))
@@ -521,6 +874,46 @@ func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+func (a *api) onPauseInputBinding(reqCtx *fasthttp.RequestCtx) {
+	if a.inputBindingPauser == nil {
+		msg := NewErrorResponse("ERR_INPUT_BINDINGS_NOT_AVAILABLE", messages.ErrInputBindingsNotAvailable)
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	name := reqCtx.UserValue(nameParam).(string)
+	err := a.inputBindingPauser.PauseInputBinding(name)
+	if err != nil {
+		msg := NewErrorResponse("ERR_PAUSE_INPUT_BINDING", fmt.Sprintf(messages.ErrPauseInputBinding, name, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onResumeInputBinding(reqCtx *fasthttp.RequestCtx) {
+	if a.inputBindingPauser == nil {
+		msg := NewErrorResponse("ERR_INPUT_BINDINGS_NOT_AVAILABLE", messages.ErrInputBindingsNotAvailable)
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	name := reqCtx.UserValue(nameParam).(string)
+	err := a.inputBindingPauser.ResumeInputBinding(name)
+	if err != nil {
+		msg := NewErrorResponse("ERR_RESUME_INPUT_BINDING", fmt.Sprintf(messages.ErrResumeInputBinding, name, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	respond(reqCtx, withEmpty())
+}
+
 func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
 	if err != nil {
@@ -734,6 +1127,7 @@ func (a *api) onGetState(reqCtx *fasthttp.RequestCtx) {
 	elapsed := diag.ElapsedSince(start)
 
 	diag.DefaultComponentMonitoring.StateInvoked(context.Background(), storeName, diag.Get, err == nil, elapsed)
+	diag.DefaultAPILatencyMonitoring.StateLatency(reqCtx, storeName, diag.Get, err == nil, elapsed)
 
 	if err != nil {
 		msg := NewErrorResponse("ERR_STATE_GET", fmt.Sprintf(messages.ErrStateGet, key, storeName, err.Error()))
@@ -840,7 +1234,7 @@ func (a *api) onLock(reqCtx *fasthttp.RequestCtx) {
 
 	policy := a.resiliency.ComponentOutboundPolicy(reqCtx, storeName, resiliency.Lock)
 
-	var resp *lock.TryLockResponse
+	var resp *lockLoader.TryLockResponse
 	req.ResourceID, err = lockLoader.GetModifiedLockKey(req.ResourceID, storeName, a.id)
 	if err != nil {
 		msg := NewErrorResponse("ERR_TRY_LOCK", err.Error())
@@ -850,7 +1244,7 @@ func (a *api) onLock(reqCtx *fasthttp.RequestCtx) {
 	}
 
 	err = policy(func(ctx context.Context) (rErr error) {
-		resp, rErr = store.TryLock(&req)
+		resp, rErr = lockLoader.TryLock(store, &req)
 		return rErr
 	})
 	if err != nil {
@@ -906,37 +1300,447 @@ func (a *api) onUnlock(reqCtx *fasthttp.RequestCtx) {
 	respond(reqCtx, withJSON(200, b))
 }
 
-func (a *api) onSubscribeConfiguration(reqCtx *fasthttp.RequestCtx) {
-	store, storeName, err := a.getConfigurationStoreWithRequestValidation(reqCtx)
+func (a *api) getCryptoProviderWithRequestValidation(reqCtx *fasthttp.RequestCtx) (cryptoLoader.SubtleCrypto, string, error) {
+	if len(a.cryptoProviders) == 0 {
+		msg := NewErrorResponse("ERR_CRYPTO_PROVIDERS_NOT_CONFIGURED", messages.ErrCryptoProvidersNotConfigured)
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		return nil, "", errors.New(msg.Message)
+	}
+
+	name := reqCtx.UserValue(nameParam).(string)
+	provider, ok := a.cryptoProviders[name]
+	if !ok {
+		msg := NewErrorResponse("ERR_CRYPTO_PROVIDER_NOT_FOUND", fmt.Sprintf(messages.ErrCryptoProviderNotFound, name))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		return nil, "", errors.New(msg.Message)
+	}
+	return provider, name, nil
+}
+
+// SignRequest is the request of the sign API.
+type SignRequest struct {
+	KeyName   string `json:"keyName"`
+	Algorithm string `json:"algorithm"`
+	Digest    []byte `json:"digest"`
+}
+
+// SignResponse is the response of the sign API.
+type SignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// VerifyRequest is the request of the verify API.
+type VerifyRequest struct {
+	KeyName   string `json:"keyName"`
+	Algorithm string `json:"algorithm"`
+	Digest    []byte `json:"digest"`
+	Signature []byte `json:"signature"`
+}
+
+// VerifyResponse is the response of the verify API.
+type VerifyResponse struct {
+	Valid bool `json:"valid"`
+}
+
+func (a *api) onCryptoEncrypt(reqCtx *fasthttp.RequestCtx) {
+	provider, name, err := a.getCryptoProviderWithRequestValidation(reqCtx)
 	if err != nil {
 		log.Debug(err)
 		return
 	}
 
-	metadata := getMetadataFromRequest(reqCtx)
-	subscribeKeys := make([]string, 0)
+	opts := cryptoLoader.EncryptOptions{
+		KeyName:          string(reqCtx.Request.Header.Peek(daprKeyNameHeader)),
+		KeyWrapAlgorithm: string(reqCtx.Request.Header.Peek(daprKeyWrapAlgHeader)),
+	}
+	if opts.KeyName == "" || opts.KeyWrapAlgorithm == "" {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, "headers "+daprKeyNameHeader+" and "+daprKeyWrapAlgHeader+" are required"))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
 
-	keys := make([]string, 0)
-	queryKeys := reqCtx.QueryArgs().PeekMulti(configurationKeyParam)
-	for _, queryKeyByte := range queryKeys {
-		keys = append(keys, string(queryKeyByte))
+	var out bytes.Buffer
+	err = cryptoLoader.Encrypt(reqCtx, provider, &out, bytes.NewReader(reqCtx.PostBody()), opts)
+	if err != nil {
+		msg := NewErrorResponse("ERR_CRYPTO", fmt.Sprintf(messages.ErrCryptoEncrypt, name, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
 	}
 
-	// empty list means subscribing to all configuration keys
-	if len(keys) == 0 {
-		getConfigurationReq := &configuration.GetRequest{
-			Keys:     []string{},
-			Metadata: metadata,
-		}
+	reqCtx.Response.Header.SetContentType("application/octet-stream")
+	respond(reqCtx, with(fasthttp.StatusOK, out.Bytes()))
+}
 
-		start := time.Now()
-		policy := a.resiliency.ComponentOutboundPolicy(reqCtx, storeName, resiliency.Configuration)
-		var getResponse *configuration.GetResponse
-		err = policy(func(ctx context.Context) (rErr error) {
-			getResponse, rErr = store.Get(ctx, getConfigurationReq)
-			return rErr
-		})
-		elapsed := diag.ElapsedSince(start)
+func (a *api) onCryptoDecrypt(reqCtx *fasthttp.RequestCtx) {
+	provider, name, err := a.getCryptoProviderWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = cryptoLoader.Decrypt(reqCtx, provider, &out, bytes.NewReader(reqCtx.PostBody()))
+	if err != nil {
+		code := fasthttp.StatusInternalServerError
+		if errors.Is(err, cryptoLoader.ErrInvalidStream) {
+			code = fasthttp.StatusBadRequest
+		}
+		msg := NewErrorResponse("ERR_CRYPTO", fmt.Sprintf(messages.ErrCryptoDecrypt, name, err))
+		respond(reqCtx, withError(code, msg))
+		log.Debug(msg)
+		return
+	}
+
+	reqCtx.Response.Header.SetContentType("application/octet-stream")
+	respond(reqCtx, with(fasthttp.StatusOK, out.Bytes()))
+}
+
+func (a *api) onCryptoSign(reqCtx *fasthttp.RequestCtx) {
+	provider, name, err := a.getCryptoProviderWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	var req SignRequest
+	if err = json.Unmarshal(reqCtx.PostBody(), &req); err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	signature, err := provider.Sign(reqCtx, req.Digest, req.Algorithm, req.KeyName)
+	if err != nil {
+		msg := NewErrorResponse("ERR_CRYPTO", fmt.Sprintf(messages.ErrCryptoSign, name, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	b, _ := json.Marshal(SignResponse{Signature: signature})
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onCryptoVerify(reqCtx *fasthttp.RequestCtx) {
+	provider, name, err := a.getCryptoProviderWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	var req VerifyRequest
+	if err = json.Unmarshal(reqCtx.PostBody(), &req); err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	valid, err := provider.Verify(reqCtx, req.Digest, req.Signature, req.Algorithm, req.KeyName)
+	if err != nil {
+		msg := NewErrorResponse("ERR_CRYPTO", fmt.Sprintf(messages.ErrCryptoVerify, name, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	b, _ := json.Marshal(VerifyResponse{Valid: valid})
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) getWorkflowWithRequestValidation(reqCtx *fasthttp.RequestCtx) (workflowName string, instanceID string, err error) {
+	if a.workflowEngine == nil {
+		msg := NewErrorResponse("ERR_WORKFLOW_ENGINE_NOT_CONFIGURED", messages.ErrWorkflowEngineNotConfigured)
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		return "", "", errors.New(msg.Message)
+	}
+
+	workflowName = reqCtx.UserValue(workflowNameParam).(string)
+	if workflowName == "" {
+		msg := NewErrorResponse("ERR_WORKFLOW_NAME_MISSING", messages.ErrWorkflowNameMissing)
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		return "", "", errors.New(msg.Message)
+	}
+	instanceID = reqCtx.UserValue(workflowInstanceIDParam).(string)
+	if instanceID == "" {
+		msg := NewErrorResponse("ERR_WORKFLOW_INSTANCE_ID_MISSING", messages.ErrWorkflowInstanceIDMissing)
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		return "", "", errors.New(msg.Message)
+	}
+	return workflowName, instanceID, nil
+}
+
+// getWorkflowInstanceWithRequestValidation validates the request on an existing workflow instance,
+// and checks that the instance belongs to the workflow in the path.
+func (a *api) getWorkflowInstanceWithRequestValidation(reqCtx *fasthttp.RequestCtx) (*workflow.State, error) {
+	workflowName, instanceID, err := a.getWorkflowWithRequestValidation(reqCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := a.workflowEngine.Get(reqCtx, instanceID)
+	if err != nil {
+		msg := NewErrorResponse("ERR_GET_WORKFLOW", fmt.Sprintf(messages.ErrWorkflowGet, instanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		return nil, errors.New(msg.Message)
+	}
+	if state.WorkflowName != workflowName {
+		msg := NewErrorResponse("ERR_WORKFLOW_NAME_MISMATCH", fmt.Sprintf(messages.ErrWorkflowNameMismatch, instanceID, state.WorkflowName))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		return nil, errors.New(msg.Message)
+	}
+	return state, nil
+}
+
+// workflowErrorStatusCode returns the HTTP status code for an error of the workflow engine.
+func workflowErrorStatusCode(err error) int {
+	switch {
+	case errors.Is(err, workflow.ErrWorkflowNotFound), errors.Is(err, workflow.ErrInstanceNotFound),
+		errors.Is(err, workflow.ErrTimerNotFound):
+		return fasthttp.StatusNotFound
+	case errors.Is(err, workflow.ErrInstanceExists), errors.Is(err, workflow.ErrInstanceCompleted),
+		errors.Is(err, workflow.ErrInstanceNotComplete), errors.Is(err, workflow.ErrInstanceNotPaused):
+		return fasthttp.StatusConflict
+	default:
+		return fasthttp.StatusInternalServerError
+	}
+}
+
+// Response of the start workflow API.
+type startWorkflowResponse struct {
+	InstanceID string `json:"instanceID"`
+}
+
+func (a *api) onStartWorkflow(reqCtx *fasthttp.RequestCtx) {
+	workflowName, instanceID, err := a.getWorkflowWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	err = a.workflowEngine.Start(reqCtx, workflowName, instanceID, reqCtx.PostBody())
+	if err != nil {
+		msg := NewErrorResponse("ERR_START_WORKFLOW", fmt.Sprintf(messages.ErrWorkflowStart, workflowName, instanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		log.Debug(msg)
+		return
+	}
+
+	b, _ := json.Marshal(startWorkflowResponse{InstanceID: instanceID})
+	respond(reqCtx, withJSON(fasthttp.StatusAccepted, b))
+}
+
+func (a *api) onGetWorkflow(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	b, _ := json.Marshal(state)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onTerminateWorkflow(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	if err = a.workflowEngine.Terminate(reqCtx, state.InstanceID); err != nil {
+		msg := NewErrorResponse("ERR_TERMINATE_WORKFLOW", fmt.Sprintf(messages.ErrWorkflowTerminate, state.InstanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onRaiseEventWorkflow(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	eventName := reqCtx.UserValue(workflowEventNameParam).(string)
+	if eventName == "" {
+		msg := NewErrorResponse("ERR_WORKFLOW_EVENT_NAME_MISSING", messages.ErrWorkflowEventNameMissing)
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	if err = a.workflowEngine.RaiseEvent(reqCtx, state.InstanceID, eventName, reqCtx.PostBody()); err != nil {
+		msg := NewErrorResponse("ERR_RAISE_EVENT_WORKFLOW", fmt.Sprintf(messages.ErrWorkflowRaiseEvent, eventName, state.InstanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onPauseWorkflow(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	if err = a.workflowEngine.Pause(reqCtx, state.InstanceID); err != nil {
+		msg := NewErrorResponse("ERR_PAUSE_WORKFLOW", fmt.Sprintf(messages.ErrWorkflowPause, state.InstanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onResumeWorkflow(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	if err = a.workflowEngine.Resume(reqCtx, state.InstanceID); err != nil {
+		msg := NewErrorResponse("ERR_RESUME_WORKFLOW", fmt.Sprintf(messages.ErrWorkflowResume, state.InstanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onPurgeWorkflow(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	if err = a.workflowEngine.Purge(reqCtx, state.InstanceID); err != nil {
+		msg := NewErrorResponse("ERR_PURGE_WORKFLOW", fmt.Sprintf(messages.ErrWorkflowPurge, state.InstanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onGetWorkflowPending(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	b, _ := json.Marshal(state.Pending())
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onCancelWorkflowTimer(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	timerID := reqCtx.UserValue(workflowTimerIDParam).(string)
+	if timerID == "" {
+		msg := NewErrorResponse("ERR_WORKFLOW_TIMER_ID_MISSING", messages.ErrWorkflowTimerIDMissing)
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	if err = a.workflowEngine.CancelTimer(reqCtx, state.InstanceID, timerID); err != nil {
+		msg := NewErrorResponse("ERR_CANCEL_WORKFLOW_TIMER", fmt.Sprintf(messages.ErrWorkflowCancelTimer, timerID, state.InstanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+// Request of the reschedule workflow timer API.
+type rescheduleWorkflowTimerRequest struct {
+	FireAt time.Time `json:"fireAt"`
+}
+
+func (a *api) onRescheduleWorkflowTimer(reqCtx *fasthttp.RequestCtx) {
+	state, err := a.getWorkflowInstanceWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	timerID := reqCtx.UserValue(workflowTimerIDParam).(string)
+	if timerID == "" {
+		msg := NewErrorResponse("ERR_WORKFLOW_TIMER_ID_MISSING", messages.ErrWorkflowTimerIDMissing)
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	var req rescheduleWorkflowTimerRequest
+	if err = json.Unmarshal(reqCtx.PostBody(), &req); err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+	if req.FireAt.IsZero() {
+		msg := NewErrorResponse("ERR_WORKFLOW_TIMER_FIRE_AT_MISSING", messages.ErrWorkflowTimerFireAtMissing)
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	if err = a.workflowEngine.RescheduleTimer(reqCtx, state.InstanceID, timerID, req.FireAt); err != nil {
+		msg := NewErrorResponse("ERR_RESCHEDULE_WORKFLOW_TIMER", fmt.Sprintf(messages.ErrWorkflowRescheduleTimer, timerID, state.InstanceID, err))
+		respond(reqCtx, withError(workflowErrorStatusCode(err), msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onSubscribeConfiguration(reqCtx *fasthttp.RequestCtx) {
+	store, storeName, err := a.getConfigurationStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	metadata := getMetadataFromRequest(reqCtx)
+	subscribeKeys := make([]string, 0)
+
+	keys := make([]string, 0)
+	queryKeys := reqCtx.QueryArgs().PeekMulti(configurationKeyParam)
+	for _, queryKeyByte := range queryKeys {
+		keys = append(keys, string(queryKeyByte))
+	}
+
+	// empty list means subscribing to all configuration keys
+	if len(keys) == 0 {
+		getConfigurationReq := &configuration.GetRequest{
+			Keys:     []string{},
+			Metadata: metadata,
+		}
+
+		start := time.Now()
+		policy := a.resiliency.ComponentOutboundPolicy(reqCtx, storeName, resiliency.Configuration)
+		var getResponse *configuration.GetResponse
+		err = policy(func(ctx context.Context) (rErr error) {
+			getResponse, rErr = store.Get(ctx, getConfigurationReq)
+			return rErr
+		})
+		elapsed := diag.ElapsedSince(start)
 		diag.DefaultComponentMonitoring.ConfigurationInvoked(context.Background(), storeName, diag.Get, err == nil, elapsed)
 
 		if err != nil {
@@ -991,6 +1795,132 @@ func (a *api) onSubscribeConfiguration(reqCtx *fasthttp.RequestCtx) {
 	respond(reqCtx, withJSON(fasthttp.StatusOK, respBytes))
 }
 
+// onSubscribeConfigurationStream subscribes to the configuration store and streams the updates to the app as Server-Sent Events.
+// The first event contains the ID of the subscription and the current value of the keys.
+// The subscription ends when the app closes the connection.
+func (a *api) onSubscribeConfigurationStream(reqCtx *fasthttp.RequestCtx) {
+	store, storeName, err := a.getConfigurationStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	keys := make([]string, 0)
+	queryKeys := reqCtx.QueryArgs().PeekMulti(configurationKeyParam)
+	for _, queryKeyByte := range queryKeys {
+		keys = append(keys, string(queryKeyByte))
+	}
+
+	resyncInterval, metadata, err := runtimeConfiguration.SubscribeRequestOptions(getMetadataFromRequest(reqCtx))
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrConfigurationResyncInterval, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	events := make(chan *configuration.UpdateEvent, configurationStreamBuffer)
+	done := make(chan struct{})
+
+	start := time.Now()
+	policy := a.resiliency.ComponentOutboundPolicy(reqCtx, storeName, resiliency.Configuration)
+	var sub *runtimeConfiguration.Subscription
+	err = policy(func(ctx context.Context) (rErr error) {
+		sub, rErr = runtimeConfiguration.Subscribe(ctx, runtimeConfiguration.SubscribeOptions{
+			Store:          store,
+			StoreName:      storeName,
+			Keys:           keys,
+			Metadata:       metadata,
+			ResyncInterval: resyncInterval,
+			Handler: func(ctx context.Context, e *configuration.UpdateEvent) error {
+				select {
+				case events <- e:
+				case <-done:
+				}
+				return nil
+			},
+		})
+		return rErr
+	})
+	elapsed := diag.ElapsedSince(start)
+
+	diag.DefaultComponentMonitoring.ConfigurationInvoked(context.Background(), storeName, diag.ConfigurationSubscribe, err == nil, elapsed)
+
+	if err != nil {
+		msg := NewErrorResponse("ERR_CONFIGURATION_SUBSCRIBE", fmt.Sprintf(messages.ErrConfigurationSubscribe, keys, storeName, err.Error()))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	// The first event has the current value of the keys, as the stores only push the changes
+	var items map[string]*configuration.Item
+	err = policy(func(ctx context.Context) (rErr error) {
+		items, rErr = sub.Load(ctx)
+		return rErr
+	})
+	if err != nil {
+		if closeErr := sub.Close(context.Background()); closeErr != nil {
+			log.Debugf("error unsubscribing configuration stream %s: %s", sub.ID(), closeErr)
+		}
+		msg := NewErrorResponse("ERR_CONFIGURATION_GET", fmt.Sprintf(messages.ErrConfigurationGet, keys, storeName, err.Error()))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	reqCtx.SetContentType("text/event-stream")
+	reqCtx.Response.Header.Set("Cache-Control", "no-cache")
+	reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer func() {
+			close(done)
+			if err := sub.Close(context.Background()); err != nil {
+				log.Debugf("error unsubscribing configuration stream %s: %s", sub.ID(), err)
+			}
+		}()
+
+		err := writeServerSentEvent(w, "subscribe", &configuration.UpdateEvent{
+			ID:    sub.ID(),
+			Items: items,
+		})
+		if err != nil {
+			return
+		}
+
+		keepAlive := time.NewTicker(configurationStreamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case e := <-events:
+				err = writeServerSentEvent(w, "update", e)
+			case <-keepAlive.C:
+				// Comment lines are ignored by clients, and allow detecting closed connections
+				_, err = w.WriteString(": keep-alive\n\n")
+				if err == nil {
+					err = w.Flush()
+				}
+			}
+			if err != nil {
+				log.Debugf("configuration stream %s closed: %s", sub.ID(), err)
+				return
+			}
+		}
+	})
+}
+
+func writeServerSentEvent(w *bufio.Writer, event string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
 func (a *api) onUnsubscribeConfiguration(reqCtx *fasthttp.RequestCtx) {
 	store, storeName, err := a.getConfigurationStoreWithRequestValidation(reqCtx)
 	if err != nil {
@@ -1227,8 +2157,12 @@ func (a *api) onBulkGetSecret(reqCtx *fasthttp.RequestCtx) {
 		return
 	}
 
+	prefix := string(reqCtx.QueryArgs().Peek(secretPrefixParam))
 	filteredSecrets := map[string]map[string]string{}
 	for key, v := range resp.Data {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
 		if a.isSecretAllowed(secretStoreName, key) {
 			filteredSecrets[key] = v
 		} else {
@@ -1316,11 +2250,12 @@ func (a *api) onPostState(reqCtx *fasthttp.RequestCtx) {
 	start := time.Now()
 	policy := a.resiliency.ComponentOutboundPolicy(reqCtx, storeName, resiliency.Statestore)
 	err = policy(func(ctx context.Context) error {
-		return store.BulkSet(reqs)
+		return statemerge.BulkSet(ctx, store, a.appChannel, storeName, reqs, store.BulkSet)
 	})
 	elapsed := diag.ElapsedSince(start)
 
 	diag.DefaultComponentMonitoring.StateInvoked(context.Background(), storeName, diag.Set, err == nil, elapsed)
+	diag.DefaultAPILatencyMonitoring.StateLatency(reqCtx, storeName, diag.Set, err == nil, elapsed)
 
 	if err != nil {
 		storeName := a.getStateStoreName(reqCtx)
@@ -1328,12 +2263,188 @@ func (a *api) onPostState(reqCtx *fasthttp.RequestCtx) {
 		statusCode, errMsg, resp := a.stateErrorResponse(err, "ERR_STATE_SAVE")
 		resp.Message = fmt.Sprintf(messages.ErrStateSave, storeName, errMsg)
 
-		respond(reqCtx, withError(statusCode, resp))
-		log.Debug(resp.Message)
-		return
+		respond(reqCtx, withError(statusCode, resp))
+		log.Debug(resp.Message)
+		return
+	}
+
+	respond(reqCtx, withEmpty())
+}
+
+// onBulkSetState saves the items one by one, each with its own etag and options, and returns the result of each item
+// instead of failing the whole request, so that the items with an etag mismatch can be retried by the client.
+func (a *api) onBulkSetState(reqCtx *fasthttp.RequestCtx) {
+	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	var req BulkSetRequest
+	err = json.Unmarshal(reqCtx.PostBody(), &req)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	// merge metadata from URL query parameters
+	metadata := getMetadataFromRequest(reqCtx)
+	if req.Metadata == nil {
+		req.Metadata = metadata
+	} else {
+		for k, v := range metadata {
+			req.Metadata[k] = v
+		}
+	}
+
+	start := time.Now()
+	policy := a.resiliency.ComponentOutboundPolicy(reqCtx, storeName, resiliency.Statestore)
+	bulkResp := make([]BulkStateItemResponse, len(req.Items))
+	limiter := concurrency.NewLimiter(req.Parallelism)
+	for i := range req.Items {
+		bulkResp[i].Key = req.Items[i].Key
+
+		fn := func(param interface{}) {
+			i := param.(int)
+			item := req.Items[i]
+			item.Metadata = mergeItemMetadata(item.Metadata, req.Metadata)
+
+			err := a.setBulkStateItem(policy, store, storeName, &item)
+			if err != nil {
+				log.Debugf("bulk set: error saving key %s: %s", bulkResp[i].Key, err)
+				bulkResp[i].ErrorCode, bulkResp[i].Error = bulkStateItemError(err, "ERR_STATE_SAVE")
+			}
+		}
+
+		limiter.Execute(fn, i)
+	}
+	limiter.Wait()
+	elapsed := diag.ElapsedSince(start)
+
+	diag.DefaultComponentMonitoring.StateInvoked(context.Background(), storeName, diag.Set, true, elapsed)
+
+	b, _ := json.Marshal(bulkResp)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) setBulkStateItem(policy resiliency.Runner, store state.Store, storeName string, item *state.SetRequest) error {
+	var err error
+	item.Key, err = stateLoader.GetModifiedStateKey(item.Key, storeName, a.id)
+	if err != nil {
+		return err
+	}
+
+	if encryption.EncryptedStateStore(storeName) {
+		data := []byte(fmt.Sprintf("%v", item.Value))
+		item.Value, err = encryption.TryEncryptValue(storeName, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return policy(func(ctx context.Context) error {
+		return statemerge.BulkSet(ctx, store, a.appChannel, storeName, []state.SetRequest{*item}, func(reqs []state.SetRequest) error {
+			for i := range reqs {
+				if err := store.Set(&reqs[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// onBulkDeleteState deletes the items one by one, each with its own etag and options, and returns the result of each item.
+func (a *api) onBulkDeleteState(reqCtx *fasthttp.RequestCtx) {
+	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	var req BulkDeleteRequest
+	err = json.Unmarshal(reqCtx.PostBody(), &req)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	// merge metadata from URL query parameters
+	metadata := getMetadataFromRequest(reqCtx)
+	if req.Metadata == nil {
+		req.Metadata = metadata
+	} else {
+		for k, v := range metadata {
+			req.Metadata[k] = v
+		}
+	}
+
+	start := time.Now()
+	policy := a.resiliency.ComponentOutboundPolicy(reqCtx, storeName, resiliency.Statestore)
+	bulkResp := make([]BulkStateItemResponse, len(req.Items))
+	limiter := concurrency.NewLimiter(req.Parallelism)
+	for i := range req.Items {
+		bulkResp[i].Key = req.Items[i].Key
+
+		fn := func(param interface{}) {
+			i := param.(int)
+			item := req.Items[i]
+			item.Metadata = mergeItemMetadata(item.Metadata, req.Metadata)
+
+			var err error
+			item.Key, err = stateLoader.GetModifiedStateKey(item.Key, storeName, a.id)
+			if err == nil {
+				err = policy(func(ctx context.Context) error {
+					return store.Delete(&item)
+				})
+			}
+			if err != nil {
+				log.Debugf("bulk delete: error deleting key %s: %s", bulkResp[i].Key, err)
+				bulkResp[i].ErrorCode, bulkResp[i].Error = bulkStateItemError(err, "ERR_STATE_DELETE")
+			}
+		}
+
+		limiter.Execute(fn, i)
+	}
+	limiter.Wait()
+	elapsed := diag.ElapsedSince(start)
+
+	diag.DefaultComponentMonitoring.StateInvoked(context.Background(), storeName, diag.Delete, true, elapsed)
+
+	b, _ := json.Marshal(bulkResp)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+// mergeItemMetadata returns the metadata of an item of a bulk request merged with the metadata of the request,
+// which takes precedence as for the save state requests.
+func mergeItemMetadata(itemMetadata, metadata map[string]string) map[string]string {
+	merged := make(map[string]string, len(itemMetadata)+len(metadata))
+	for k, v := range itemMetadata {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
 	}
+	return merged
+}
 
-	respond(reqCtx, withEmpty())
+// bulkStateItemError returns the error code and the error message of an item of a bulk state request,
+// distinguishing the etag mismatches and the invalid etags from the other errors.
+func bulkStateItemError(err error, errorCode string) (string, string) {
+	var etagErr *state.ETagError
+	if errors.As(err, &etagErr) {
+		switch etagErr.Kind() {
+		case state.ETagMismatch:
+			return "ERR_STATE_ETAG_MISMATCH", etagErr.Error()
+		case state.ETagInvalid:
+			return "ERR_STATE_ETAG_INVALID", etagErr.Error()
+		}
+	}
+	return errorCode, err.Error()
 }
 
 // stateErrorResponse takes a state store error and returns a corresponding status code, error message and modified user error.
@@ -1454,7 +2565,11 @@ func (a *api) onDirectMessage(reqCtx *fasthttp.RequestCtx) {
 		respond(reqCtx, withError(statusCode, msg))
 		return
 	}
-	respond(reqCtx, with(statusCode, body))
+	if resp == nil {
+		respond(reqCtx, with(statusCode, body))
+		return
+	}
+	respond(reqCtx, withInterimResponses(resp.InterimResponses()), with(statusCode, body), withTrailers(resp.Trailers()))
 }
 
 // findTargetID tries to find ID of the target service from the following three places:
@@ -1551,6 +2666,35 @@ func (a *api) onRenameActorReminder(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+func (a *api) onSimulateActorPlacement(reqCtx *fasthttp.RequestCtx) {
+	if a.actor == nil {
+		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	var req actors.PlacementSimulationRequest
+	err := json.Unmarshal(reqCtx.PostBody(), &req)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	simulations := a.actor.SimulatePlacementChange(reqCtx, &req)
+	b, err := json.Marshal(simulations)
+	if err != nil {
+		msg := NewErrorResponse("ERR_ACTOR_PLACEMENT_SIMULATION", fmt.Sprintf(messages.ErrActorPlacementSimulation, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
 func (a *api) onCreateActorTimer(reqCtx *fasthttp.RequestCtx) {
 	if a.actor == nil {
 		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
@@ -1663,6 +2807,38 @@ func (a *api) onActorStateTransaction(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+func (a *api) onMultiActorStateTransaction(reqCtx *fasthttp.RequestCtx) {
+	if a.actor == nil {
+		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	var req actors.MultiActorTransactionalRequest
+	err := json.Unmarshal(reqCtx.PostBody(), &req)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+	req.ActorType = reqCtx.UserValue(actorTypeParam).(string)
+
+	err = a.actor.MultiActorTransactionalStateOperation(reqCtx, &req)
+	if errors.Is(err, actors.ErrActorsNotColocated) {
+		msg := NewErrorResponse("ERR_ACTORS_NOT_COLOCATED", fmt.Sprintf(messages.ErrActorsNotColocated, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+	} else if err != nil {
+		msg := NewErrorResponse("ERR_ACTOR_STATE_TRANSACTION_SAVE", fmt.Sprintf(messages.ErrActorStateTransactionSave, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+	} else {
+		respond(reqCtx, withEmpty())
+	}
+}
+
 func (a *api) onGetActorReminder(reqCtx *fasthttp.RequestCtx) {
 	if a.actor == nil {
 		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
@@ -1738,6 +2914,13 @@ func (a *api) onDirectActorMessage(reqCtx *fasthttp.RequestCtx) {
 	method := reqCtx.UserValue(methodParam).(string)
 	body := reqCtx.PostBody()
 
+	if actors.IsInternalActorType(actorType) {
+		msg := NewErrorResponse("ERR_ACTOR_INVOKE_METHOD", fmt.Sprintf(messages.ErrActorTypeInternal, actorType))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
 	req := invokev1.NewInvokeMethodRequest(method)
 	req.WithActor(actorType, actorID)
 	req.WithHTTPExtension(verb, reqCtx.QueryArgs().String())
@@ -1829,16 +3012,30 @@ func (a *api) onGetActorState(reqCtx *fasthttp.RequestCtx) {
 func (a *api) onGetMetadata(reqCtx *fasthttp.RequestCtx) {
 	temp := make(map[string]string)
 
-	// Copy synchronously so it can be serialized to JSON.
-	a.extendedMetadata.Range(func(key, value interface{}) bool {
-		temp[key.(string)] = value.(string)
-
-		return true
-	})
+	attributes := a.extendedMetadata.List()
+	metadataAttributes := make([]metadataAttribute, 0, len(attributes))
+	for _, attr := range attributes {
+		temp[attr.Key] = attr.Value
+		if len(attr.Labels) == 0 && attr.ExpiresAt.IsZero() {
+			continue
+		}
+		mtdAttr := metadataAttribute{
+			Key:    attr.Key,
+			Value:  attr.Value,
+			Labels: attr.Labels,
+		}
+		if !attr.ExpiresAt.IsZero() {
+			expiresAt := attr.ExpiresAt.UTC()
+			mtdAttr.ExpiresAt = &expiresAt
+		}
+		metadataAttributes = append(metadataAttributes, mtdAttr)
+	}
 	temp[daprRuntimeVersionKey] = a.daprRunTimeVersion
 	activeActorsCount := []actors.ActiveActorsCount{}
+	var placement *actors.PlacementStatus
 	if a.actor != nil {
 		activeActorsCount = a.actor.GetActiveActorsCount(reqCtx)
+		placement = a.actor.GetPlacementStatus(reqCtx)
 	}
 	componentsCapabilties := a.getComponentsCapabilitesFn()
 	components := a.getComponentsFn()
@@ -1856,8 +3053,19 @@ func (a *api) onGetMetadata(reqCtx *fasthttp.RequestCtx) {
 	mtd := metadata{
 		ID:                   a.id,
 		ActiveActorsCount:    activeActorsCount,
+		Placement:            placement,
 		Extended:             temp,
 		RegisteredComponents: registeredComponents,
+		Attributes:           metadataAttributes,
+	}
+	if pauser, ok := a.pubsubAdapter.(runtimePubsub.SubscriptionPauser); ok {
+		mtd.PausedSubscriptions = pauser.PausedSubscriptions()
+	}
+	if a.inputBindingPauser != nil {
+		mtd.PausedInputBindings = a.inputBindingPauser.PausedInputBindings()
+	}
+	if a.getRuntimeMetadataFn != nil {
+		addRuntimeMetadata(&mtd, a.getRuntimeMetadataFn())
 	}
 
 	mtdBytes, err := json.Marshal(mtd)
@@ -1870,6 +3078,223 @@ func (a *api) onGetMetadata(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+// addRuntimeMetadata adds the status of the components, the subscriptions, the actor types, the app connection, the enabled features
+// and the drift of the loaded resources to the metadata.
+// Components that failed to initialize are listed with the registered ones.
+func addRuntimeMetadata(mtd *metadata, info meta.Info) {
+	for _, status := range info.ComponentStatuses {
+		found := false
+		for i := range mtd.RegisteredComponents {
+			if mtd.RegisteredComponents[i].Name == status.Name {
+				mtd.RegisteredComponents[i].Status = status.Status
+				mtd.RegisteredComponents[i].LastError = status.LastError
+				mtd.RegisteredComponents[i].LastErrorClass = string(status.ErrorClass)
+				found = true
+				break
+			}
+		}
+		if !found && status.Status != meta.ComponentStatusInitialized {
+			mtd.RegisteredComponents = append(mtd.RegisteredComponents, registeredComponent{
+				Name:           status.Name,
+				Type:           status.Type,
+				Version:        status.Version,
+				Capabilities:   []string{},
+				Status:         status.Status,
+				LastError:      status.LastError,
+				LastErrorClass: string(status.ErrorClass),
+			})
+		}
+	}
+
+	for _, s := range info.Subscriptions {
+		sub := metadataSubscription{
+			PubsubName:      s.PubsubName,
+			Topic:           s.Topic,
+			DeadLetterTopic: s.DeadLetterTopic,
+			Metadata:        s.Metadata,
+		}
+		for _, r := range s.Rules {
+			rule := metadataSubscriptionRule{Path: r.Path}
+			if r.Match != nil {
+				rule.Match = fmt.Sprint(r.Match)
+			}
+			sub.Rules = append(sub.Rules, rule)
+		}
+		mtd.Subscriptions = append(mtd.Subscriptions, sub)
+	}
+
+	mtd.ActorTypes = info.ActorTypes
+	mtd.EnabledFeatures = info.EnabledFeatures
+	for _, f := range info.Features {
+		mtd.Features = append(mtd.Features, metadataFeature{
+			Name:       string(f.Name),
+			Enabled:    f.Enabled,
+			Default:    f.Default,
+			Registered: f.Registered,
+		})
+	}
+
+	mtd.AppConnection = &metadataAppConnection{
+		Port:           info.AppConnection.Port,
+		Protocol:       info.AppConnection.Protocol,
+		MaxConcurrency: info.AppConnection.MaxConcurrency,
+	}
+	if health := info.AppConnection.Health; health != nil {
+		mtd.AppConnection.Health = &metadataAppConnectionHealth{
+			HealthCheckPath:     health.HealthCheckPath,
+			HealthProbeInterval: health.HealthProbeInterval.String(),
+			HealthProbeTimeout:  health.HealthProbeTimeout.String(),
+			HealthThreshold:     health.HealthThreshold,
+			Status:              health.Status,
+		}
+	}
+
+	if drift := info.Drift; drift != nil {
+		mtd.Drift = &metadataDrift{
+			Detected:      drift.Detected(),
+			Components:    drift.Components,
+			Configuration: drift.Configuration,
+			LastChecked:   drift.LastChecked,
+		}
+	}
+
+	if crossNamespace := info.CrossNamespace; crossNamespace != nil {
+		mtd.CrossNamespacePolicy = &metadataCrossNamespacePolicy{
+			AllowedCallers: make([]metadataCrossNamespaceCaller, len(crossNamespace.AllowedCallers)),
+		}
+		for i, caller := range crossNamespace.AllowedCallers {
+			mtd.CrossNamespacePolicy.AllowedCallers[i] = metadataCrossNamespaceCaller{
+				Namespace: caller.Namespace,
+				AppID:     caller.AppID,
+				Callees:   caller.Callees,
+			}
+		}
+	}
+}
+
+// EnableComponentTracingRequest is the request of the API that enables the detailed tracing of a component.
+type EnableComponentTracingRequest struct {
+	// Duration of the detailed tracing, such as "5m".
+	Duration string `json:"duration"`
+	// Whether the payloads of the requests are captured in span events.
+	CapturePayloads bool `json:"capturePayloads"`
+	// Maximum size of the captured payloads, in bytes.
+	MaxPayloadSize int `json:"maxPayloadSize"`
+}
+
+// onGetResiliency returns the effective resiliency policies of the targets and the state of the circuit breakers.
+func (a *api) onGetResiliency(reqCtx *fasthttp.RequestCtx) {
+	var res *resiliency.Description
+	if a.resiliency != nil {
+		res = a.resiliency.Describe()
+	} else {
+		res = (&resiliency.NoOp{}).Describe()
+	}
+	b, _ := json.Marshal(res)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onGetComponentTracing(reqCtx *fasthttp.RequestCtx) {
+	b, _ := json.Marshal(diag.DefaultComponentTracing.List())
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onEnableComponentTracing(reqCtx *fasthttp.RequestCtx) {
+	name := reqCtx.UserValue(nameParam).(string)
+	if !a.hasComponent(name) {
+		msg := NewErrorResponse("ERR_COMPONENT_NOT_FOUND", fmt.Sprintf(messages.ErrComponentTracingNotFound, name))
+		respond(reqCtx, withError(fasthttp.StatusNotFound, msg))
+		log.Debug(msg)
+		return
+	}
+
+	var req EnableComponentTracingRequest
+	if err := json.Unmarshal(reqCtx.PostBody(), &req); err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil || duration <= 0 || duration > diag.MaxComponentTracingDuration {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrComponentTracingInvalidDuration, req.Duration, diag.MaxComponentTracingDuration))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	opts := diag.DefaultComponentTracing.Enable(name, duration, req.CapturePayloads, req.MaxPayloadSize)
+	log.Infof("detailed tracing of component %s enabled until %s", name, opts.ExpiresAt.Format(time.RFC3339))
+	b, _ := json.Marshal(opts)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onDisableComponentTracing(reqCtx *fasthttp.RequestCtx) {
+	name := reqCtx.UserValue(nameParam).(string)
+	diag.DefaultComponentTracing.Disable(name)
+	log.Infof("detailed tracing of component %s disabled", name)
+	respond(reqCtx, withEmpty())
+}
+
+// SetLogLevelRequest is the request of the API that changes the logging of the sidecar at runtime.
+type SetLogLevelRequest struct {
+	// Log level: debug, info, warn, error or fatal. The log level is unchanged if empty.
+	Level string `json:"level"`
+	// Whether the API calls are logged. It is unchanged if unset.
+	APILogging *bool `json:"apiLogging"`
+	// Duration after which the logging is reverted to the startup options, such as "15m". It is kept if empty.
+	TTL string `json:"ttl"`
+}
+
+func (a *api) onGetLogLevel(reqCtx *fasthttp.RequestCtx) {
+	b, _ := json.Marshal(diag.DefaultRuntimeLogging.Status())
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onSetLogLevel(reqCtx *fasthttp.RequestCtx) {
+	var req SetLogLevelRequest
+	if err := json.Unmarshal(reqCtx.PostBody(), &req); err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 || ttl > diag.MaxRuntimeLoggingDuration {
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrLogLevelTTL, req.TTL, diag.MaxRuntimeLoggingDuration))
+			respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+			log.Debug(msg)
+			return
+		}
+	}
+
+	status, err := diag.DefaultRuntimeLogging.Set(req.Level, req.APILogging, ttl)
+	if err != nil {
+		msg := NewErrorResponse("ERR_LOG_LEVEL_INVALID", fmt.Sprintf(messages.ErrLogLevelInvalid, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+	log.Infof("log level set to: %s, API logging enabled: %t", status.Level, status.APILogging)
+	b, _ := json.Marshal(status)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) hasComponent(name string) bool {
+	if a.getComponentsFn == nil {
+		return false
+	}
+	for _, comp := range a.getComponentsFn() {
+		if comp.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func getOrDefaultCapabilites(dict map[string][]string, key string) []string {
 	if val, ok := dict[key]; ok {
 		return val
@@ -1877,10 +3302,40 @@ func getOrDefaultCapabilites(dict map[string][]string, key string) []string {
 	return make([]string, 0)
 }
 
+// onPutMetadata sets a metadata attribute; its value is the request body.
+// The attribute expires after the optional ttlInSeconds query parameter, and is labeled with the label.<name> query parameters.
 func (a *api) onPutMetadata(reqCtx *fasthttp.RequestCtx) {
 	key := fmt.Sprintf("%v", reqCtx.UserValue("key"))
 	body := reqCtx.PostBody()
-	a.extendedMetadata.Store(key, string(body))
+
+	var ttl time.Duration
+	if ttlParam := string(reqCtx.QueryArgs().Peek(metadataTTLParam)); ttlParam != "" {
+		ttlInSeconds, err := strconv.ParseInt(ttlParam, 10, 64)
+		if err != nil || ttlInSeconds < 0 {
+			msg := NewErrorResponse("ERR_METADATA_ATTRIBUTE_SET", fmt.Sprintf(messages.ErrMetadataAttributeSet, key, "invalid "+metadataTTLParam+": "+ttlParam))
+			respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+			log.Debug(msg)
+			return
+		}
+		ttl = time.Duration(ttlInSeconds) * time.Second
+	}
+
+	var labels map[string]string
+	reqCtx.QueryArgs().VisitAll(func(k []byte, v []byte) {
+		if label := string(k); strings.HasPrefix(label, metadataLabelPrefix) {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[strings.TrimPrefix(label, metadataLabelPrefix)] = string(v)
+		}
+	})
+
+	if err := a.extendedMetadata.Set(key, string(body), ttl, labels); err != nil {
+		msg := NewErrorResponse("ERR_METADATA_ATTRIBUTE_SET", fmt.Sprintf(messages.ErrMetadataAttributeSet, key, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
 	respond(reqCtx, withEmpty())
 }
 
@@ -1895,6 +3350,102 @@ func (a *api) onShutdown(reqCtx *fasthttp.RequestCtx) {
 	}()
 }
 
+// onGetSubscriptionScaling returns the signals of the subscriptions for the autoscalers such as KEDA.
+func (a *api) onGetSubscriptionScaling(reqCtx *fasthttp.RequestCtx) {
+	res := []subscriptionScaling{}
+	if a.getSubscriptionScalingFn != nil {
+		for _, s := range a.getSubscriptionScalingFn() {
+			res = append(res, subscriptionScaling{
+				PubsubName:        s.PubsubName,
+				Topic:             s.Topic,
+				PendingDeliveries: s.PendingDeliveries,
+				ProcessingRate:    s.ProcessingRate,
+				Lag:               s.Lag,
+			})
+		}
+	}
+
+	b, _ := json.Marshal(res)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onPauseSubscription(reqCtx *fasthttp.RequestCtx) {
+	pauser, pubsubName, topic, ok := a.getSubscriptionPauser(reqCtx)
+	if !ok {
+		return
+	}
+
+	duration, err := time.ParseDuration(string(reqCtx.QueryArgs().Peek(durationParam)))
+	if err != nil || duration <= 0 {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrPubsubPauseDuration, reqCtx.QueryArgs().Peek(durationParam)))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+
+		return
+	}
+
+	err = pauser.PauseSubscription(pubsubName, topic, duration)
+	if err != nil {
+		msg := NewErrorResponse("ERR_PUBSUB_PAUSE_SUBSCRIPTION", fmt.Sprintf(messages.ErrPubsubPauseSubscription, topic, pubsubName, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+
+		return
+	}
+
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onResumeSubscription(reqCtx *fasthttp.RequestCtx) {
+	pauser, pubsubName, topic, ok := a.getSubscriptionPauser(reqCtx)
+	if !ok {
+		return
+	}
+
+	err := pauser.ResumeSubscription(pubsubName, topic)
+	if err != nil {
+		msg := NewErrorResponse("ERR_PUBSUB_RESUME_SUBSCRIPTION", fmt.Sprintf(messages.ErrPubsubResumeSubscription, topic, pubsubName, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+
+		return
+	}
+
+	respond(reqCtx, withEmpty())
+}
+
+// getSubscriptionPauser validates the pubsub and topic of the request, responding with an error if they're not valid.
+func (a *api) getSubscriptionPauser(reqCtx *fasthttp.RequestCtx) (runtimePubsub.SubscriptionPauser, string, string, bool) {
+	pauser, ok := a.pubsubAdapter.(runtimePubsub.SubscriptionPauser)
+	if !ok {
+		msg := NewErrorResponse("ERR_PUBSUB_NOT_CONFIGURED", messages.ErrPubsubNotConfigured)
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+
+		return nil, "", "", false
+	}
+
+	pubsubName := reqCtx.UserValue(pubsubnameparam).(string)
+	if a.pubsubAdapter.GetPubSub(pubsubName) == nil {
+		msg := NewErrorResponse("ERR_PUBSUB_NOT_FOUND", fmt.Sprintf(messages.ErrPubsubNotFound, pubsubName))
+		respond(reqCtx, withError(fasthttp.StatusNotFound, msg))
+		log.Debug(msg)
+
+		return nil, "", "", false
+	}
+
+	topic := reqCtx.UserValue(topicParam).(string)
+	if topic == "" {
+		msg := NewErrorResponse("ERR_TOPIC_EMPTY", fmt.Sprintf(messages.ErrTopicEmpty, pubsubName))
+		respond(reqCtx, withError(fasthttp.StatusNotFound, msg))
+		log.Debug(msg)
+
+		return nil, "", "", false
+	}
+
+	return pauser, pubsubName, topic, true
+}
+
 func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
 	if a.pubsubAdapter == nil {
 		msg := NewErrorResponse("ERR_PUBSUB_NOT_CONFIGURED", messages.ErrPubsubNotConfigured)
@@ -1952,6 +3503,7 @@ func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
 	traceState := diag.TraceStateToW3CString(span.SpanContext())
 
 	data := body
+	var schema string
 
 	if !rawPayload {
 		envelope, err := runtimePubsub.NewCloudEvent(&runtimePubsub.CloudEvent{
@@ -1961,6 +3513,7 @@ func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
 			Data:            body,
 			TraceID:         corID,
 			TraceState:      traceState,
+			Baggage:         string(reqCtx.Request.Header.Peek(baggageHeader)),
 			Pubsub:          pubsubName,
 		})
 		if err != nil {
@@ -1974,6 +3527,8 @@ func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
 		features := thepubsub.Features()
 
 		pubsub.ApplyMetadata(envelope, features, metadata)
+		runtimePubsub.ApplyCloudEventOverrides(envelope, metadata)
+		schema = runtimePubsub.DataSchema(envelope)
 
 		data, err = json.Marshal(envelope)
 		if err != nil {
@@ -1997,6 +3552,8 @@ func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
 	elapsed := diag.ElapsedSince(start)
 
 	diag.DefaultComponentMonitoring.PubsubEgressEvent(context.Background(), pubsubName, topic, err == nil, elapsed)
+	diag.DefaultAPILatencyMonitoring.PublishLatency(reqCtx, pubsubName, err == nil, elapsed)
+	diag.DefaultComponentMonitoring.PubsubEgressPayload(context.Background(), pubsubName, topic, schema, len(data))
 
 	if err != nil {
 		status := fasthttp.StatusInternalServerError
@@ -2299,3 +3856,23 @@ func (a *api) SetDirectMessaging(directMessaging messaging.DirectMessaging) {
 func (a *api) SetActorRuntime(actor actors.Actors) {
 	a.actor = actor
 }
+
+func (a *api) SetWorkflowEngine(engine *workflow.Engine) {
+	a.workflowEngine = engine
+}
+
+func (a *api) SetRuntimeMetadataFn(getRuntimeMetadataFn func() meta.Info) {
+	a.getRuntimeMetadataFn = getRuntimeMetadataFn
+}
+
+func (a *api) SetMetadataAttributes(attributes *meta.AttributeStore) {
+	a.extendedMetadata = attributes
+}
+
+func (a *api) SetSubscriptionScalingFn(getSubscriptionScalingFn func() []runtimePubsub.ScalingSignals) {
+	a.getSubscriptionScalingFn = getSubscriptionScalingFn
+}
+
+func (a *api) SetInputBindingPauser(pauser InputBindingPauser) {
+	a.inputBindingPauser = pauser
+}
@@ -15,15 +15,20 @@ limitations under the License.
 package http
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	gohttp "net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"strconv"
 	"strings"
-	"sync"
 	"testing"
 	"time"
 
@@ -33,6 +38,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttputil"
 	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -44,6 +50,7 @@ import (
 	"github.com/dapr/components-contrib/bindings"
 	"github.com/dapr/components-contrib/configuration"
 	"github.com/dapr/components-contrib/lock"
+	contribMetadata "github.com/dapr/components-contrib/metadata"
 	"github.com/dapr/components-contrib/middleware"
 	"github.com/dapr/components-contrib/pubsub"
 	"github.com/dapr/components-contrib/secretstores"
@@ -52,14 +59,20 @@ import (
 	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	"github.com/dapr/dapr/pkg/apis/resiliency/v1alpha1"
 	"github.com/dapr/dapr/pkg/channel/http"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
+	"github.com/dapr/dapr/pkg/components/crypto/localkeys"
+	lockLoader "github.com/dapr/dapr/pkg/components/lock"
 	httpMiddlewareLoader "github.com/dapr/dapr/pkg/components/middleware/http"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	"github.com/dapr/dapr/pkg/encryption"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	httpMiddleware "github.com/dapr/dapr/pkg/middleware/http"
+	"github.com/dapr/dapr/pkg/placement/hashing"
 	"github.com/dapr/dapr/pkg/resiliency"
+	"github.com/dapr/dapr/pkg/runtime/meta"
 	runtimePubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	"github.com/dapr/dapr/pkg/runtime/workflow"
 	daprt "github.com/dapr/dapr/pkg/testing"
 	testtrace "github.com/dapr/dapr/pkg/testing/trace"
 	"github.com/dapr/kit/logger"
@@ -293,6 +306,137 @@ func TestPubSubEndpoints(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+// mockSubscriptionPauser is a pubsub adapter that records paused subscriptions.
+type mockSubscriptionPauser struct {
+	daprt.MockPubSubAdapter
+	paused map[string]time.Duration
+}
+
+func (m *mockSubscriptionPauser) PauseSubscription(pubsubName, topic string, duration time.Duration) error {
+	if topic == "notsubscribed" {
+		return errors.New("the subscription does not exist")
+	}
+	m.paused[pubsubName+"||"+topic] = duration
+	return nil
+}
+
+func (m *mockSubscriptionPauser) ResumeSubscription(pubsubName, topic string) error {
+	if _, ok := m.paused[pubsubName+"||"+topic]; !ok {
+		return errors.New("the subscription is not paused")
+	}
+	delete(m.paused, pubsubName+"||"+topic)
+	return nil
+}
+
+func (m *mockSubscriptionPauser) PausedSubscriptions() []runtimePubsub.PausedSubscription {
+	return nil
+}
+
+func TestPauseSubscriptionEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	pauser := &mockSubscriptionPauser{
+		MockPubSubAdapter: daprt.MockPubSubAdapter{
+			GetPubSubFn: func(pubsubName string) pubsub.PubSub {
+				if pubsubName == "errnotfound" {
+					return nil
+				}
+				return &daprt.MockPubSub{}
+			},
+		},
+		paused: map[string]time.Duration{},
+	}
+	testAPI := &api{
+		pubsubAdapter: pauser,
+	}
+	fakeServer.StartServer(testAPI.constructSubscriptionEndpoints())
+
+	t.Run("Pause and resume successfully - 204 No Content", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/subscriptions/pubsubname/topic/pause?duration=30s", apiVersionV1alpha1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Equal(t, 30*time.Second, pauser.paused["pubsubname||topic"])
+
+		apiPath = fmt.Sprintf("%s/subscriptions/pubsubname/topic/resume", apiVersionV1alpha1)
+		resp = fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Empty(t, pauser.paused)
+	})
+
+	t.Run("Pause with invalid duration - 400", func(t *testing.T) {
+		for _, q := range []string{"", "?duration=foo", "?duration=-1s"} {
+			apiPath := fmt.Sprintf("%s/subscriptions/pubsubname/topic/pause%s", apiVersionV1alpha1, q)
+			resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+			assert.Equal(t, 400, resp.StatusCode)
+			assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+		}
+	})
+
+	t.Run("Pause subscription that does not exist - 400", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/subscriptions/pubsubname/notsubscribed/pause?duration=30s", apiVersionV1alpha1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_PUBSUB_PAUSE_SUBSCRIPTION", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Resume subscription that is not paused - 400", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/subscriptions/pubsubname/topic/resume", apiVersionV1alpha1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_PUBSUB_RESUME_SUBSCRIPTION", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Pubsub not found - 404", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/subscriptions/errnotfound/topic/pause?duration=30s", apiVersionV1alpha1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_PUBSUB_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Pubsub not configured - 400", func(t *testing.T) {
+		testAPI.pubsubAdapter = nil
+		defer func() { testAPI.pubsubAdapter = pauser }()
+
+		apiPath := fmt.Sprintf("%s/subscriptions/pubsubname/topic/pause?duration=30s", apiVersionV1alpha1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_PUBSUB_NOT_CONFIGURED", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestSubscriptionScalingEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{}
+	fakeServer.StartServer(testAPI.constructSubscriptionEndpoints())
+	defer fakeServer.Shutdown()
+
+	apiPath := fmt.Sprintf("%s/subscriptions/scaling", apiVersionV1alpha1)
+
+	t.Run("no subscriptions", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "[]", string(resp.RawBody))
+	})
+
+	t.Run("subscriptions signals", func(t *testing.T) {
+		lag := int64(42)
+		testAPI.SetSubscriptionScalingFn(func() []runtimePubsub.ScalingSignals {
+			return []runtimePubsub.ScalingSignals{
+				{PubsubName: "kafka", Topic: "orders", PendingDeliveries: 3, ProcessingRate: 1.5, Lag: &lag},
+				{PubsubName: "redis", Topic: "payments"},
+			}
+		})
+
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.JSONEq(t, `[
+			{"pubsubname":"kafka","topic":"orders","pendingDeliveries":3,"processingRate":1.5,"lag":42},
+			{"pubsubname":"redis","topic":"payments","pendingDeliveries":0,"processingRate":0}
+		]`, string(resp.RawBody))
+	})
+}
+
 func TestShutdownEndpoints(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 
@@ -435,6 +579,80 @@ func TestV1OutputBindingsEndpoints(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+// mockInputBindingPauser records the paused input bindings.
+type mockInputBindingPauser struct {
+	paused map[string]bool
+}
+
+func (m *mockInputBindingPauser) PauseInputBinding(name string) error {
+	if name == "notfound" {
+		return errors.New("the input binding does not exist")
+	}
+	m.paused[name] = true
+	return nil
+}
+
+func (m *mockInputBindingPauser) ResumeInputBinding(name string) error {
+	if !m.paused[name] {
+		return errors.New("the input binding is not paused")
+	}
+	delete(m.paused, name)
+	return nil
+}
+
+func (m *mockInputBindingPauser) PausedInputBindings() []string {
+	res := make([]string, 0, len(m.paused))
+	for name := range m.paused {
+		res = append(res, name)
+	}
+	return res
+}
+
+func TestPauseInputBindingEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	pauser := &mockInputBindingPauser{paused: map[string]bool{}}
+	testAPI := &api{}
+	fakeServer.StartServer(testAPI.constructInputBindingEndpoints())
+	defer fakeServer.Shutdown()
+
+	t.Run("Input bindings not available - 500", func(t *testing.T) {
+		for _, action := range []string{"pause", "resume"} {
+			apiPath := fmt.Sprintf("%s/bindings/kafka/%s", apiVersionV1alpha1, action)
+			resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+			assert.Equal(t, 500, resp.StatusCode)
+			assert.Equal(t, "ERR_INPUT_BINDINGS_NOT_AVAILABLE", resp.ErrorBody["errorCode"])
+		}
+	})
+
+	testAPI.SetInputBindingPauser(pauser)
+
+	t.Run("Pause and resume successfully - 204 No Content", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/bindings/kafka/pause", apiVersionV1alpha1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.True(t, pauser.paused["kafka"])
+
+		apiPath = fmt.Sprintf("%s/bindings/kafka/resume", apiVersionV1alpha1)
+		resp = fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Empty(t, pauser.paused)
+	})
+
+	t.Run("Pause input binding that does not exist - 400", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/bindings/notfound/pause", apiVersionV1alpha1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_PAUSE_INPUT_BINDING", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Resume input binding that is not paused - 400", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/bindings/kafka/resume", apiVersionV1alpha1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_RESUME_INPUT_BINDING", resp.ErrorBody["errorCode"])
+	})
+}
+
 func TestV1OutputBindingsEndpointsWithTracer(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 	buffer := ""
@@ -799,6 +1017,53 @@ func TestV1DirectMessagingEndpoints(t *testing.T) {
 		assert.Equal(t, "ERR_DIRECT_INVOKE", resp.ErrorBody["errorCode"])
 	})
 
+	t.Run("Invoke direct messaging with trailers and interim responses - 200 OK", func(t *testing.T) {
+		fakeDirectMessageResponse := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		fakeDirectMessageResponse.WithRawData([]byte("fakeDirectMessageResponse"), "application/json")
+		fakeDirectMessageResponse.WithTrailers(map[string][]string{"X-Checksum": {"def"}})
+		fakeDirectMessageResponse.WithInterimResponse(gohttp.StatusEarlyHints, map[string][]string{"Link": {"</style.css>; rel=preload"}})
+
+		mockDirectMessaging.Calls = nil // reset call count
+		mockDirectMessaging.On("Invoke",
+			mock.MatchedBy(func(a context.Context) bool {
+				return true
+			}), mock.MatchedBy(func(b string) bool {
+				return b == "fakeAppID"
+			}), mock.MatchedBy(func(c *invokev1.InvokeMethodRequest) bool {
+				return c.Message().Method == "fakeTrailersMethod"
+			})).Return(fakeDirectMessageResponse, nil).Once()
+
+		var interimCodes []int
+		var interimLinks []string
+		trace := &httptrace.ClientTrace{
+			Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+				interimCodes = append(interimCodes, code)
+				interimLinks = append(interimLinks, header.Get("Link"))
+				return nil
+			},
+		}
+		r, _ := gohttp.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace),
+			gohttp.MethodPost, "http://localhost/v1.0/invoke/fakeAppID/method/fakeTrailersMethod", strings.NewReader("fakeData"))
+		// Send the body chunked, followed by the trailer
+		r.ContentLength = -1
+		r.Trailer = gohttp.Header{"X-Checksum": {"abc"}}
+
+		// act
+		res, err := fakeServer.client.Do(r)
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		// assert
+		mockDirectMessaging.AssertNumberOfCalls(t, "Invoke", 1)
+		invokedReq := mockDirectMessaging.Calls[0].Arguments.Get(2).(*invokev1.InvokeMethodRequest)
+		assert.Equal(t, []string{"abc"}, invokedReq.Trailers()["X-Checksum"].GetValues())
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, []byte("fakeDirectMessageResponse"), body)
+		assert.Equal(t, "def", res.Trailer.Get("X-Checksum"))
+		assert.Equal(t, []int{gohttp.StatusEarlyHints}, interimCodes)
+		assert.Equal(t, []string{"</style.css>; rel=preload"}, interimLinks)
+	})
 	fakeServer.Shutdown()
 }
 
@@ -1427,6 +1692,116 @@ func TestV1ActorEndpoints(t *testing.T) {
 		mockActors.AssertNumberOfCalls(t, "RenameReminder", 1)
 	})
 
+	t.Run("Placement Simulation - 200 OK", func(t *testing.T) {
+		apiPath := "v1.0-alpha1/actors/placement/simulate"
+
+		simulationRequest := actors.PlacementSimulationRequest{
+			Added:   []string{"10.0.0.4:50002"},
+			Removed: []string{"10.0.0.1:50002"},
+		}
+		simulations := []actors.PlacementSimulation{
+			{
+				ActorType: "fakeActorType",
+				MovedRanges: []hashing.MovedRange{
+					{Start: 10, End: 20, From: "10.0.0.1:50002", To: "10.0.0.4:50002"},
+				},
+				MovedFraction:                0.25,
+				AffectedLocalActivations:     3,
+				EstimatedAffectedActivations: 9,
+			},
+		}
+		mockActors := new(actors.MockActors)
+
+		mockActors.On("SimulatePlacementChange", &simulationRequest).Return(simulations)
+
+		testAPI.actor = mockActors
+
+		// act
+		inputBodyBytes, err := json.Marshal(simulationRequest)
+
+		assert.NoError(t, err)
+		resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+		expected, _ := json.Marshal(simulations)
+		assert.Equal(t, expected, resp.RawBody)
+		mockActors.AssertNumberOfCalls(t, "SimulatePlacementChange", 1)
+	})
+
+	t.Run("Placement Simulation - 400 for invalid JSON", func(t *testing.T) {
+		testAPI.actor = new(actors.MockActors)
+
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/actors/placement/simulate", []byte("{\"added\":"), nil)
+
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Multi-Actor Transaction - 204 No Content", func(t *testing.T) {
+		apiPath := "v1.0-alpha1/actors/fakeActorType/state"
+		actorOperations := []actors.ActorTransactionalOperations{
+			{
+				ActorID: "fakeActorID1",
+				Operations: []actors.TransactionalOperation{{
+					Operation: actors.Upsert,
+					Request:   map[string]interface{}{"key": "key1", "value": "fakeData"},
+				}},
+			},
+			{
+				ActorID: "fakeActorID2",
+				Operations: []actors.TransactionalOperation{{
+					Operation: actors.Delete,
+					Request:   map[string]interface{}{"key": "key1"},
+				}},
+			},
+		}
+		mockActors := new(actors.MockActors)
+		mockActors.On("MultiActorTransactionalStateOperation", &actors.MultiActorTransactionalRequest{
+			ActorType: "fakeActorType",
+			Actors:    actorOperations,
+		}).Return(nil)
+
+		testAPI.actor = mockActors
+
+		// act
+		inputBodyBytes, err := json.Marshal(map[string]interface{}{"actors": actorOperations})
+		assert.NoError(t, err)
+		resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+		// assert
+		assert.Equal(t, 204, resp.StatusCode)
+		mockActors.AssertNumberOfCalls(t, "MultiActorTransactionalStateOperation", 1)
+	})
+
+	t.Run("Multi-Actor Transaction - 400 when the actors are not colocated", func(t *testing.T) {
+		mockActors := new(actors.MockActors)
+		mockActors.On("MultiActorTransactionalStateOperation", mock.Anything).Return(errors.Wrap(actors.ErrActorsNotColocated, "actor fakeActorID2"))
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/actors/fakeActorType/state", []byte(`{"actors":[{"actorId":"fakeActorID2"}]}`), nil)
+
+		// assert
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_ACTORS_NOT_COLOCATED", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Multi-Actor Transaction - 500 when the transaction fails", func(t *testing.T) {
+		mockActors := new(actors.MockActors)
+		mockActors.On("MultiActorTransactionalStateOperation", mock.Anything).Return(errors.New("UPSTREAM_ERROR"))
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/actors/fakeActorType/state", []byte(`{"actors":[]}`), nil)
+
+		// assert
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_ACTOR_STATE_TRANSACTION_SAVE", resp.ErrorBody["errorCode"])
+	})
+
 	t.Run("Reminder Delete - 204 No Content", func(t *testing.T) {
 		apiPath := "v1.0/actors/fakeActorType/fakeActorID/reminders/reminder1"
 		reminderRequest := actors.DeleteReminderRequest{
@@ -1682,6 +2057,19 @@ func TestV1ActorEndpoints(t *testing.T) {
 		mockActors.AssertNumberOfCalls(t, "Call", 1)
 	})
 
+	t.Run("Direct Message - 400 for internal actor types", func(t *testing.T) {
+		mockActors := new(actors.MockActors)
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", "v1.0/actors/dapr.internal.default.myapp.workflow/i1/method/terminate", nil, nil)
+
+		// assert
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_ACTOR_INVOKE_METHOD", resp.ErrorBody["errorCode"])
+		mockActors.AssertNumberOfCalls(t, "Call", 0)
+	})
+
 	t.Run("Direct Message - 500 for actor call failure", func(t *testing.T) {
 		apiPath := "v1.0/actors/fakeActorType/fakeActorID/method/method1"
 		headerMetadata := map[string][]string{
@@ -1780,7 +2168,7 @@ func TestV1MetadataEndpoint(t *testing.T) {
 				},
 			}
 		},
-		extendedMetadata: sync.Map{},
+		extendedMetadata: meta.NewAttributeStore(),
 		getComponentsCapabilitesFn: func() map[string][]string {
 			capsMap := make(map[string][]string)
 			capsMap["MockComponent1Name"] = []string{"mock.feat.MockComponent1Name"}
@@ -1789,7 +2177,7 @@ func TestV1MetadataEndpoint(t *testing.T) {
 		},
 	}
 	// PutMetadata only stroes string(request body)
-	testAPI.extendedMetadata.Store("test", "value")
+	testAPI.extendedMetadata.Set("test", "value", 0, nil)
 
 	fakeServer.StartServer(testAPI.constructMetadataEndpoints())
 
@@ -1822,6 +2210,7 @@ func TestV1MetadataEndpoint(t *testing.T) {
 		mockActors := new(actors.MockActors)
 
 		mockActors.On("GetActiveActorsCount")
+		mockActors.On("GetPlacementStatus").Return(nil)
 
 		testAPI.id = "xyz"
 		testAPI.actor = mockActors
@@ -1834,6 +2223,153 @@ func TestV1MetadataEndpoint(t *testing.T) {
 		mockActors.AssertNumberOfCalls(t, "GetActiveActorsCount", 1)
 	})
 
+	t.Run("Metadata with runtime information - 200 OK", func(t *testing.T) {
+		apiPath := "v1.0/metadata"
+		mockActors := new(actors.MockActors)
+		mockActors.On("GetActiveActorsCount")
+		lastTableUpdate := time.Unix(2000, 0).UTC()
+		mockActors.On("GetPlacementStatus").Return(&actors.PlacementStatus{
+			Connected:       true,
+			Server:          "placement:50005",
+			TableVersion:    "3",
+			LastTableUpdate: &lastTableUpdate,
+		})
+
+		testAPI.actor = mockActors
+		testAPI.getRuntimeMetadataFn = func() meta.Info {
+			return meta.Info{
+				ComponentStatuses: []meta.ComponentStatus{
+					{Name: "MockComponent1Name", Type: "mock.component1Type", Version: "v1.0", Status: meta.ComponentStatusInitialized},
+					{Name: "MockComponent3Name", Type: "mock.component3Type", Version: "v1", Status: meta.ComponentStatusFailed, LastError: "init error"},
+				},
+				Subscriptions: []runtimePubsub.Subscription{
+					{
+						PubsubName:      "pubsub",
+						Topic:           "topic1",
+						DeadLetterTopic: "dead",
+						Rules:           []*runtimePubsub.Rule{{Path: "/orders"}},
+					},
+				},
+				ActorTypes: []string{"abcd", "xyz"},
+				AppConnection: meta.AppConnection{
+					Port:     3000,
+					Protocol: "http",
+					Health: &meta.AppHealth{
+						HealthCheckPath:     "/healthz",
+						HealthProbeInterval: 5 * time.Second,
+						HealthProbeTimeout:  500 * time.Millisecond,
+						HealthThreshold:     3,
+						Status:              meta.AppStatusHealthy,
+					},
+				},
+				EnabledFeatures: []string{"Resiliency"},
+				Features: []config.FeatureStatus{
+					{Name: config.Resiliency, Enabled: true, Registered: true},
+					{Name: "Feature1", Enabled: false},
+				},
+				Drift: &meta.Drift{
+					Configuration: true,
+					LastChecked:   time.Unix(1000, 0).UTC(),
+				},
+				CrossNamespace: &config.CrossNamespaceSpec{
+					AllowedCallers: []config.CrossNamespaceCaller{{Namespace: "frontend", AppID: "web"}},
+				},
+			}
+		}
+		defer func() {
+			testAPI.getRuntimeMetadataFn = nil
+		}()
+
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var res metadata
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &res))
+		assert.Len(t, res.RegisteredComponents, 3)
+		assert.Equal(t, meta.ComponentStatusInitialized, res.RegisteredComponents[0].Status)
+		assert.Equal(t, "", res.RegisteredComponents[1].Status)
+		assert.Equal(t, registeredComponent{
+			Name:         "MockComponent3Name",
+			Type:         "mock.component3Type",
+			Version:      "v1",
+			Capabilities: []string{},
+			Status:       meta.ComponentStatusFailed,
+			LastError:    "init error",
+		}, res.RegisteredComponents[2])
+		assert.Equal(t, []metadataSubscription{
+			{
+				PubsubName:      "pubsub",
+				Topic:           "topic1",
+				DeadLetterTopic: "dead",
+				Rules:           []metadataSubscriptionRule{{Path: "/orders"}},
+			},
+		}, res.Subscriptions)
+		assert.Equal(t, []string{"abcd", "xyz"}, res.ActorTypes)
+		assert.Equal(t, &metadataAppConnection{
+			Port:     3000,
+			Protocol: "http",
+			Health: &metadataAppConnectionHealth{
+				HealthCheckPath:     "/healthz",
+				HealthProbeInterval: "5s",
+				HealthProbeTimeout:  "500ms",
+				HealthThreshold:     3,
+				Status:              meta.AppStatusHealthy,
+			},
+		}, res.AppConnection)
+		assert.Equal(t, []string{"Resiliency"}, res.EnabledFeatures)
+		assert.Equal(t, []metadataFeature{
+			{Name: "Resiliency", Enabled: true, Registered: true},
+			{Name: "Feature1", Enabled: false},
+		}, res.Features)
+		assert.Equal(t, &metadataDrift{
+			Detected:      true,
+			Configuration: true,
+			LastChecked:   time.Unix(1000, 0).UTC(),
+		}, res.Drift)
+		assert.Equal(t, &metadataCrossNamespacePolicy{
+			AllowedCallers: []metadataCrossNamespaceCaller{{Namespace: "frontend", AppID: "web"}},
+		}, res.CrossNamespacePolicy)
+		assert.Equal(t, &actors.PlacementStatus{
+			Connected:       true,
+			Server:          "placement:50005",
+			TableVersion:    "3",
+			LastTableUpdate: &lastTableUpdate,
+		}, res.Placement)
+	})
+
+	t.Run("Put metadata with TTL and labels - 204 No Content", func(t *testing.T) {
+		mockActors := new(actors.MockActors)
+		mockActors.On("GetActiveActorsCount")
+		mockActors.On("GetPlacementStatus").Return(nil)
+		testAPI.actor = mockActors
+
+		resp := fakeServer.DoRequest("PUT", "v1.0/metadata/tenant", []byte("contoso"), map[string]string{
+			"ttlInSeconds": "60",
+			"label.team":   "billing",
+		})
+		assert.Equal(t, 204, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("GET", "v1.0/metadata", nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var res metadata
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &res))
+		assert.Equal(t, "contoso", res.Extended["tenant"])
+		if assert.Len(t, res.Attributes, 1) {
+			assert.Equal(t, "tenant", res.Attributes[0].Key)
+			assert.Equal(t, map[string]string{"team": "billing"}, res.Attributes[0].Labels)
+			assert.NotNil(t, res.Attributes[0].ExpiresAt)
+		}
+	})
+
+	t.Run("Put metadata with invalid TTL - 400 Bad Request", func(t *testing.T) {
+		resp := fakeServer.DoRequest("PUT", "v1.0/metadata/tenant", []byte("contoso"), map[string]string{
+			"ttlInSeconds": "abc",
+		})
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_METADATA_ATTRIBUTE_SET", resp.ErrorBody["errorCode"])
+	})
+
 	fakeServer.Shutdown()
 }
 
@@ -2037,6 +2573,18 @@ func TestAPIToken(t *testing.T) {
 		assert.Equal(t, 401, resp.StatusCode)
 	})
 
+	t.Run("Invoke direct messaging with the health route in the query and without token - 401", func(t *testing.T) {
+		apiPath := "v1.0/invoke/fakeDaprID/method/fakeMethod?x=/healthz"
+
+		mockDirectMessaging.Calls = nil // reset call count
+
+		// act
+		resp := fakeServer.DoRequestWithAPIToken("POST", apiPath, "", []byte("fakeData"))
+		// assert
+		mockDirectMessaging.AssertNumberOfCalls(t, "Invoke", 0)
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
 	t.Run("Invoke direct messaging token mismatch - 401", func(t *testing.T) {
 		apiPath := "v1.0/invoke/fakeDaprID/method/fakeMethod"
 		fakeData := []byte("fakeData")
@@ -2317,6 +2865,119 @@ func TestV1Alpha1ConfigurationUnsubscribe(t *testing.T) {
 	})
 }
 
+func TestV1Alpha1ConfigurationSubscribeStream(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+
+	var fakeConfigurationStore configuration.Store = &fakeConfigurationStore{}
+
+	storeName := "store1"
+
+	fakeConfigurationStores := map[string]configuration.Store{
+		storeName: fakeConfigurationStore,
+	}
+	testAPI := &api{
+		resiliency:          resiliency.New(nil),
+		configurationStores: fakeConfigurationStores,
+	}
+	fakeServer.StartServer(testAPI.constructConfigurationEndpoints())
+
+	t.Run("store not found", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0-alpha1/configuration/%s/subscribe/stream", "nonexistent")
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode, "Expected configuration store not found")
+		assert.Equal(t, "ERR_CONFIGURATION_STORE_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("invalid resync interval", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0-alpha1/configuration/%s/subscribe/stream?metadata.resyncInterval=foo", storeName)
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode, "Expected invalid resync interval")
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0-alpha1/configuration/%s/subscribe/stream?key=bad-key", storeName)
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 500, resp.StatusCode, "Expected configuration get error")
+		assert.Equal(t, "ERR_CONFIGURATION_GET", resp.ErrorBody["errorCode"])
+	})
+}
+
+func TestV1Alpha1ConfigurationSubscribeStreamEvents(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+
+	store := &fakeStreamingConfigurationStore{
+		handlers: make(chan configuration.UpdateHandler, 1),
+	}
+	testAPI := &api{
+		resiliency: resiliency.New(nil),
+		configurationStores: map[string]configuration.Store{
+			"store1": store,
+		},
+	}
+	fakeServer.StartServer(testAPI.constructConfigurationEndpoints())
+	defer fakeServer.Shutdown()
+
+	r, err := gohttp.NewRequest(gohttp.MethodGet, "http://localhost/v1.0-alpha1/configuration/store1/subscribe/stream?key=good-key1", nil)
+	require.NoError(t, err)
+	res, err := fakeServer.client.Do(r)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(res.Body)
+	readEvent := func() (string, configuration.UpdateEvent) {
+		t.Helper()
+
+		var (
+			name  string
+			event configuration.UpdateEvent
+		)
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				return name, event
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+			}
+		}
+	}
+
+	// The first event has the current value of the key
+	name, event := readEvent()
+	assert.Equal(t, "subscribe", name)
+	assert.Equal(t, "sub1", event.ID)
+	require.Contains(t, event.Items, "good-key1")
+	assert.Equal(t, "good-value1", event.Items["good-key1"].Value)
+	assert.Equal(t, "version1", event.Items["good-key1"].Version)
+
+	var handler configuration.UpdateHandler
+	select {
+	case handler = <-store.handlers:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the subscription")
+	}
+	require.NoError(t, handler(context.Background(), &configuration.UpdateEvent{
+		ID: "sub1",
+		Items: map[string]*configuration.Item{
+			"good-key1": {Value: "good-value2", Version: "version2"},
+		},
+	}))
+
+	name, event = readEvent()
+	assert.Equal(t, "update", name)
+	assert.Equal(t, "sub1", event.ID)
+	require.Contains(t, event.Items, "good-key1")
+	assert.Equal(t, "good-value2", event.Items["good-key1"].Value)
+	assert.Equal(t, "version2", event.Items["good-key1"].Version)
+}
+
 func TestV1Alpha1DistributedLock(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 
@@ -2485,6 +3146,395 @@ func TestV1Alpha1DistributedLock(t *testing.T) {
 	})
 }
 
+func TestV1Alpha1DistributedLockFencingToken(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		resiliency: resiliency.New(nil),
+		lockStores: map[string]lock.Store{
+			"store1": &fakeLockStore{},
+			"store2": &fakeFencingLockStore{},
+		},
+	}
+	fakeServer.StartServer(testAPI.constructDistributedLockEndpoints())
+
+	b, _ := json.Marshal(&lock.TryLockRequest{
+		ResourceID:      "1",
+		LockOwner:       "palpatine",
+		ExpiryInSeconds: 5,
+	})
+
+	t.Run("Lock store without fencing tokens", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/lock/store1", b, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		rspMap := resp.JSONBody.(map[string]interface{})
+		assert.Equal(t, true, rspMap["success"])
+		assert.NotContains(t, rspMap, "fencingToken")
+	})
+
+	t.Run("Lock store with fencing tokens", func(t *testing.T) {
+		for _, expected := range []float64{1, 2} {
+			resp := fakeServer.DoRequest("POST", "v1.0-alpha1/lock/store2", b, nil)
+			assert.Equal(t, 200, resp.StatusCode)
+
+			rspMap := resp.JSONBody.(map[string]interface{})
+			assert.Equal(t, true, rspMap["success"])
+			assert.Equal(t, expected, rspMap["fencingToken"])
+		}
+	})
+}
+
+func TestV1Alpha1Workflow(t *testing.T) {
+	registry := workflow.NewRegistry()
+	registry.RegisterWorkflow("approval", func(ctx *workflow.Context) (any, error) {
+		var approver string
+		if err := ctx.WaitForEvent("approve", &approver); err != nil {
+			return nil, err
+		}
+		return approver, nil
+	})
+	registry.RegisterWorkflow("deadline", func(ctx *workflow.Context) (any, error) {
+		if err := ctx.CreateTimer("deadline", time.Hour); err != nil {
+			return nil, err
+		}
+		return "fired", nil
+	})
+	engine := workflow.NewEngine(registry, "default", "myapp")
+	workflow.NewMockActors().AddHost(engine)
+	defer engine.Close()
+
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{}
+	fakeServer.StartServer(testAPI.constructWorkflowEndpoints())
+	defer fakeServer.Shutdown()
+
+	t.Run("Workflow engine not configured", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-1/start", nil, nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_WORKFLOW_ENGINE_NOT_CONFIGURED", resp.ErrorBody["errorCode"])
+	})
+
+	testAPI.workflowEngine = engine
+
+	t.Run("Workflow not registered", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/unknown/order-1/start", nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_START_WORKFLOW", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Start, raise event and purge", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-1/start", nil, nil)
+		assert.Equal(t, 202, resp.StatusCode)
+		assert.Equal(t, "order-1", resp.JSONBody.(map[string]interface{})["instanceID"])
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-1/start", nil, nil)
+		assert.Equal(t, 409, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("GET", "v1.0-alpha1/workflows/other/order-1", nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_WORKFLOW_NAME_MISMATCH", resp.ErrorBody["errorCode"])
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-1/purge", nil, nil)
+		assert.Equal(t, 409, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-1/raiseEvent/approve", []byte(`"alice"`), nil)
+		assert.Equal(t, 204, resp.StatusCode)
+
+		assert.Eventually(t, func() bool {
+			resp = fakeServer.DoRequest("GET", "v1.0-alpha1/workflows/approval/order-1", nil, nil)
+			return resp.StatusCode == 200 && resp.JSONBody.(map[string]interface{})["status"] == string(workflow.StatusCompleted)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-1/purge", nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("GET", "v1.0-alpha1/workflows/approval/order-1", nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("Pause, resume and terminate", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-2/start", nil, nil)
+		assert.Equal(t, 202, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-2/resume", nil, nil)
+		assert.Equal(t, 409, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-2/pause", nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-2/resume", nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-2/terminate", nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("GET", "v1.0-alpha1/workflows/approval/order-2", nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, string(workflow.StatusTerminated), resp.JSONBody.(map[string]interface{})["status"])
+	})
+
+	t.Run("Pending events", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-3/start", nil, nil)
+		assert.Equal(t, 202, resp.StatusCode)
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-3/pause", nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/approval/order-3/raiseEvent/approve", []byte(`"alice"`), nil)
+		assert.Equal(t, 204, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("GET", "v1.0-alpha1/workflows/approval/order-3/pending", nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		var pending workflow.Pending
+		require.NoError(t, json.Unmarshal(resp.RawBody, &pending))
+		require.Len(t, pending.Events, 1)
+		assert.Equal(t, "approve", pending.Events[0].Name)
+		assert.Empty(t, pending.Timers)
+	})
+
+	t.Run("Cancel and reschedule timers", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/deadline/order-4/start", nil, nil)
+		assert.Equal(t, 202, resp.StatusCode)
+
+		var pending workflow.Pending
+		assert.Eventually(t, func() bool {
+			resp = fakeServer.DoRequest("GET", "v1.0-alpha1/workflows/deadline/order-4/pending", nil, nil)
+			return resp.StatusCode == 200 && json.Unmarshal(resp.RawBody, &pending) == nil && len(pending.Timers) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		timerID := pending.Timers[0].ID
+		assert.Equal(t, "deadline", pending.Timers[0].Name)
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/deadline/order-4/timers/unknown/cancel", nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_CANCEL_WORKFLOW_TIMER", resp.ErrorBody["errorCode"])
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/deadline/order-4/timers/"+timerID+"/reschedule", []byte(`{}`), nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_WORKFLOW_TIMER_FIRE_AT_MISSING", resp.ErrorBody["errorCode"])
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/deadline/order-4/timers/"+timerID+"/reschedule", []byte(`{"fireAt":"2022-01-01T00:00:00Z"}`), nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Eventually(t, func() bool {
+			resp = fakeServer.DoRequest("GET", "v1.0-alpha1/workflows/deadline/order-4", nil, nil)
+			return resp.StatusCode == 200 && resp.JSONBody.(map[string]interface{})["status"] == string(workflow.StatusCompleted)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/workflows/deadline/order-4/timers/"+timerID+"/cancel", nil, nil)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
+
+func TestV1Alpha1Crypto(t *testing.T) {
+	provider := localkeys.NewRawKeys(logger.NewLogger("crypto.test"))
+	err := provider.Init(cryptoLoader.Metadata{Base: contribMetadata.Base{Properties: map[string]string{
+		"mykey": base64.StdEncoding.EncodeToString(make([]byte, 32)),
+	}}})
+	assert.NoError(t, err)
+
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{}
+	fakeServer.StartServer(testAPI.constructCryptoEndpoints())
+	defer fakeServer.Shutdown()
+
+	plaintext := []byte("hello world")
+
+	t.Run("Crypto providers not configured", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/mycrypto/encrypt", plaintext, nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_CRYPTO_PROVIDERS_NOT_CONFIGURED", resp.ErrorBody["errorCode"])
+	})
+
+	testAPI.cryptoProviders = map[string]cryptoLoader.SubtleCrypto{"mycrypto": provider}
+
+	t.Run("Crypto provider not found", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/nope/encrypt", plaintext, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_CRYPTO_PROVIDER_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Encrypt without key name", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/mycrypto/encrypt", plaintext, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Encrypt and decrypt", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/mycrypto/encrypt", plaintext, nil,
+			"dapr-key-name", "mykey", "dapr-key-wrap-algorithm", "A256KW")
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "application/octet-stream", resp.ContentType)
+		assert.NotContains(t, string(resp.RawBody), string(plaintext))
+
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/mycrypto/decrypt", resp.RawBody, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, plaintext, resp.RawBody)
+	})
+
+	t.Run("Decrypt invalid stream", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/mycrypto/decrypt", plaintext, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_CRYPTO", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Sign and verify", func(t *testing.T) {
+		digest := sha256.Sum256(plaintext)
+		body, _ := json.Marshal(SignRequest{KeyName: "mykey", Algorithm: "HS256", Digest: digest[:]})
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/mycrypto/sign", body, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		var signed SignResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &signed))
+
+		body, _ = json.Marshal(VerifyRequest{KeyName: "mykey", Algorithm: "HS256", Digest: digest[:], Signature: signed.Signature})
+		resp = fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/mycrypto/verify", body, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, true, resp.JSONBody.(map[string]interface{})["valid"])
+	})
+
+	t.Run("Sign malformed request", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/mycrypto/sign", []byte("{"), nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+}
+
+func TestV1Alpha1ComponentTracing(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		getComponentsFn: func() []componentsV1alpha1.Component {
+			return []componentsV1alpha1.Component{
+				{ObjectMeta: metaV1.ObjectMeta{Name: "statestore"}},
+			}
+		},
+	}
+	fakeServer.StartServer(testAPI.constructComponentTracingEndpoints())
+	defer fakeServer.Shutdown()
+	defer diag.DefaultComponentTracing.Disable("statestore")
+
+	t.Run("Component not found", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/tracing/components/nope", []byte(`{"duration":"1m"}`), nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_COMPONENT_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Invalid duration", func(t *testing.T) {
+		for _, body := range []string{`{"duration":"2h"}`, `{"duration":"-1m"}`, `{"duration":"nope"}`, `{}`} {
+			resp := fakeServer.DoRequest("POST", "v1.0-alpha1/tracing/components/statestore", []byte(body), nil)
+			assert.Equal(t, 400, resp.StatusCode, body)
+			assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"], body)
+		}
+	})
+
+	t.Run("Enable", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/tracing/components/statestore", []byte(`{"duration":"5m","capturePayloads":true}`), nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var opts diag.ComponentTracingOptions
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &opts))
+		assert.True(t, opts.CapturePayloads)
+		assert.Equal(t, diag.DefaultMaxPayloadSize, opts.MaxPayloadSize)
+		assert.True(t, opts.ExpiresAt.After(time.Now()))
+
+		_, ok := diag.DefaultComponentTracing.Get("statestore")
+		assert.True(t, ok)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", "v1.0-alpha1/tracing/components", nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var list map[string]diag.ComponentTracingOptions
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &list))
+		assert.Contains(t, list, "statestore")
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		resp := fakeServer.DoRequest("DELETE", "v1.0-alpha1/tracing/components/statestore", nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+
+		_, ok := diag.DefaultComponentTracing.Get("statestore")
+		assert.False(t, ok)
+	})
+}
+
+func TestV1Alpha1LogLevel(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{}
+	fakeServer.StartServer(testAPI.constructLoggingEndpoints())
+	defer fakeServer.Shutdown()
+	defer diag.DefaultRuntimeLogging.Reset()
+
+	t.Run("Invalid level", func(t *testing.T) {
+		resp := fakeServer.DoRequest("PUT", "v1.0-alpha1/logging/level", []byte(`{"level":"verbose"}`), nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_LOG_LEVEL_INVALID", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Invalid ttl", func(t *testing.T) {
+		for _, body := range []string{`{"level":"debug","ttl":"48h"}`, `{"level":"debug","ttl":"-1m"}`, `{"level":"debug","ttl":"nope"}`, `{`} {
+			resp := fakeServer.DoRequest("PUT", "v1.0-alpha1/logging/level", []byte(body), nil)
+			assert.Equal(t, 400, resp.StatusCode, body)
+			assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"], body)
+		}
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		resp := fakeServer.DoRequest("PUT", "v1.0-alpha1/logging/level", []byte(`{"level":"debug","apiLogging":true,"ttl":"10m"}`), nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var s diag.RuntimeLoggingStatus
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &s))
+		assert.Equal(t, "debug", s.Level)
+		assert.True(t, s.APILogging)
+		if assert.NotNil(t, s.RevertAt) {
+			assert.True(t, s.RevertAt.After(time.Now()))
+		}
+		assert.True(t, diag.DefaultRuntimeLogging.APILoggingEnabled(false))
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", "v1.0-alpha1/logging/level", nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var s diag.RuntimeLoggingStatus
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &s))
+		assert.Equal(t, "debug", s.Level)
+		assert.True(t, s.APILogging)
+	})
+}
+
+func TestV1Alpha1Resiliency(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{}
+	fakeServer.StartServer(testAPI.constructResiliencyEndpoints())
+	defer fakeServer.Shutdown()
+
+	t.Run("Resiliency not configured", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", "v1.0-alpha1/resiliency", nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.JSONEq(t, `{"targets":[],"circuitBreakers":[]}`, string(resp.RawBody))
+	})
+
+	t.Run("Policies and circuit breakers", func(t *testing.T) {
+		res := resiliency.FromConfigurations(logger.NewLogger("test.api.http.resiliency"), testResiliency)
+		res.EndpointPolicy(context.Background(), "circuitBreakerApp", "method")(func(ctx context.Context) error {
+			return nil
+		})
+		testAPI.resiliency = res
+
+		resp := fakeServer.DoRequest("GET", "v1.0-alpha1/resiliency", nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var d resiliency.Description
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &d))
+		assert.Contains(t, d.Targets, resiliency.TargetDescription{
+			Type: resiliency.Endpoint, Name: "circuitBreakerApp", Retry: "tenRetries", CircuitBreaker: "simpleCB",
+		})
+		if assert.Len(t, d.CircuitBreakers, 1) {
+			assert.Equal(t, "circuitBreakerApp", d.CircuitBreakers[0].Target)
+			assert.Equal(t, "method", d.CircuitBreakers[0].Instance)
+			assert.Equal(t, "closed", d.CircuitBreakers[0].State)
+			assert.Equal(t, uint32(1), d.CircuitBreakers[0].TotalSuccesses)
+		}
+	})
+}
+
 func buildHTTPPineline(spec config.PipelineSpec) httpMiddleware.Pipeline {
 	registry := httpMiddlewareLoader.NewRegistry()
 	registry.RegisterComponent(func(l logger.Logger) httpMiddlewareLoader.FactoryMethod {
@@ -3446,6 +4496,95 @@ const (
 	queryTestRequestSyntaxErr = `syntax error`
 )
 
+func TestV1BulkSetDeleteStateEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		stateStores: map[string]state.Store{"store1": fakeETagStateStore{}},
+		resiliency:  resiliency.New(nil),
+	}
+	fakeServer.StartServer(testAPI.constructStateEndpoints())
+	defer fakeServer.Shutdown()
+
+	t.Run("bulk set returns the result of each item", func(t *testing.T) {
+		request := BulkSetRequest{
+			Items: []state.SetRequest{
+				{Key: "good-key", Value: "v1", ETag: ptr.Of("1")},
+				{Key: "good-key", Value: "v2", ETag: ptr.Of("2")},
+				{Key: "good-key", Value: "v3", ETag: ptr.Of("not-a-number")},
+				{Key: "bad-key", Value: "v4"},
+			},
+		}
+		body, _ := json.Marshal(request)
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/state/store1/bulk/set", body, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var responses []BulkStateItemResponse
+		require.NoError(t, json.Unmarshal(resp.RawBody, &responses))
+		assert.Equal(t, []BulkStateItemResponse{
+			{Key: "good-key"},
+			{Key: "good-key", ErrorCode: "ERR_STATE_ETAG_MISMATCH", Error: "possible etag mismatch. error from state store: etag 2"},
+			{Key: "good-key", ErrorCode: "ERR_STATE_ETAG_INVALID", Error: "invalid etag value: etag not-a-number"},
+			{Key: "bad-key", ErrorCode: "ERR_STATE_SAVE", Error: "NOT FOUND"},
+		}, responses)
+	})
+
+	t.Run("bulk delete returns the result of each item", func(t *testing.T) {
+		request := BulkDeleteRequest{
+			Items: []state.DeleteRequest{
+				{Key: "good-key", ETag: ptr.Of("2")},
+				{Key: "good-key", ETag: ptr.Of("1")},
+			},
+			Parallelism: 1,
+		}
+		body, _ := json.Marshal(request)
+		resp := fakeServer.DoRequest("PUT", "v1.0-alpha1/state/store1/bulk/delete", body, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var responses []BulkStateItemResponse
+		require.NoError(t, json.Unmarshal(resp.RawBody, &responses))
+		assert.Equal(t, []BulkStateItemResponse{
+			{Key: "good-key", ErrorCode: "ERR_STATE_ETAG_MISMATCH", Error: "possible etag mismatch. error from state store: etag 2"},
+			{Key: "good-key"},
+		}, responses)
+	})
+
+	t.Run("malformed request", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/state/store1/bulk/set", []byte("[]"), nil)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("store not found", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/state/store2/bulk/delete", []byte("{}"), nil)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+// fakeETagStateStore accepts the requests on good-key with the etag 1 or without etag.
+type fakeETagStateStore struct {
+	fakeStateStore
+}
+
+func (c fakeETagStateStore) checkETag(key string, etag *string) error {
+	if key != "good-key" {
+		return errors.New("NOT FOUND")
+	}
+	if etag == nil || *etag == "1" {
+		return nil
+	}
+	if _, err := strconv.Atoi(*etag); err != nil {
+		return state.NewETagError(state.ETagInvalid, fmt.Errorf("etag %s", *etag))
+	}
+	return state.NewETagError(state.ETagMismatch, fmt.Errorf("etag %s", *etag))
+}
+
+func (c fakeETagStateStore) Set(req *state.SetRequest) error {
+	return c.checkETag(req.Key, req.ETag)
+}
+
+func (c fakeETagStateStore) Delete(req *state.DeleteRequest) error {
+	return c.checkETag(req.Key, req.ETag)
+}
+
 type fakeStateStore struct {
 	counter int
 }
@@ -3705,6 +4844,19 @@ func TestV1SecretEndpoints(t *testing.T) {
 		assert.Equal(t, 200, resp.StatusCode, "reading secrets should succeed")
 	})
 
+	t.Run("Get Bulk secret - prefix filter", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/secrets/%s/bulk", storeName)
+		// act
+		resp := fakeServer.DoRequest("GET", apiPath, nil, map[string]string{"prefix": "good"})
+		// assert
+		assert.Equal(t, 200, resp.StatusCode, "reading secrets should succeed")
+		assert.Contains(t, resp.JSONBody, "good-key")
+
+		resp = fakeServer.DoRequest("GET", apiPath, nil, map[string]string{"prefix": "other"})
+		assert.Equal(t, 200, resp.StatusCode, "reading secrets should succeed")
+		assert.Empty(t, resp.JSONBody)
+	})
+
 	t.Run("Get secret - retries on initial failure with resiliency", func(t *testing.T) {
 		apiPath := fmt.Sprintf("v1.0/secrets/%s/key", "failSecret")
 
@@ -3836,6 +4988,17 @@ func (c *fakeConfigurationStore) Unsubscribe(ctx context.Context, req *configura
 	return nil
 }
 
+// fakeStreamingConfigurationStore is a fakeConfigurationStore which hands out the handlers of the subscriptions.
+type fakeStreamingConfigurationStore struct {
+	fakeConfigurationStore
+	handlers chan configuration.UpdateHandler
+}
+
+func (c *fakeStreamingConfigurationStore) Subscribe(ctx context.Context, req *configuration.SubscribeRequest, handler configuration.UpdateHandler) (string, error) {
+	c.handlers <- handler
+	return "sub1", nil
+}
+
 type fakeLockStore struct{}
 
 func (l fakeLockStore) Ping() error {
@@ -3902,6 +5065,23 @@ func (l *fakeLockStore) Unlock(req *lock.UnlockRequest) (*lock.UnlockResponse, e
 	}, nil
 }
 
+type fakeFencingLockStore struct {
+	fakeLockStore
+	token int64
+}
+
+func (l *fakeFencingLockStore) TryLockWithFencingToken(req *lock.TryLockRequest) (*lockLoader.TryLockResponse, error) {
+	resp, err := l.TryLock(req)
+	if err != nil {
+		return nil, err
+	}
+	l.token++
+	return &lockLoader.TryLockResponse{
+		TryLockResponse: *resp,
+		FencingToken:    l.token,
+	}, nil
+}
+
 func TestV1HealthzEndpoint(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 
@@ -3926,6 +5106,14 @@ func TestV1HealthzEndpoint(t *testing.T) {
 		assert.Equal(t, 204, resp.StatusCode)
 	})
 
+	t.Run("Healthz - 500 while shutting down", func(t *testing.T) {
+		apiPath := "v1.0/healthz"
+		testAPI.MarkStatusAsNotReady()
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+
+		assert.Equal(t, 500, resp.StatusCode, "dapr not ready should return 500")
+	})
+
 	fakeServer.Shutdown()
 }
 
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/compression"
+	"github.com/dapr/dapr/pkg/messages"
+)
+
+var eventStreamContentType = []byte("text/event-stream")
+
+// useCompression decompresses the requests with a Content-Encoding header, and compresses the responses with the
+// encoding negotiated with the Accept-Encoding header of the requests.
+func (s *server) useCompression(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	policy, err := compression.NewPolicy(s.compressionSpec.API)
+	if err != nil {
+		log.Warnf("compression of the HTTP API is disabled: %s", err)
+		return next
+	}
+	if policy == nil {
+		return next
+	}
+
+	log.Infof("enabled compression of the HTTP API with the encodings %v", policy.Encodings)
+	maxSize := s.maxRequestBodySize() * 1024 * 1024
+	return func(ctx *fasthttp.RequestCtx) {
+		if !decompressRequest(ctx, maxSize) {
+			return
+		}
+		next(ctx)
+		compressResponse(ctx, policy)
+	}
+}
+
+// decompressRequest replaces the body of a compressed request with the decompressed body.
+// It responds with an error and returns false if the body can't be decompressed.
+func decompressRequest(ctx *fasthttp.RequestCtx, maxSize int) bool {
+	encoding := string(ctx.Request.Header.Peek(fasthttp.HeaderContentEncoding))
+	if encoding == "" || encoding == "identity" {
+		return true
+	}
+	if !compression.IsSupported(encoding) {
+		msg := NewErrorResponse("ERR_UNSUPPORTED_ENCODING", fmt.Sprintf(messages.ErrContentEncoding, encoding))
+		respond(ctx, withError(fasthttp.StatusUnsupportedMediaType, msg))
+		return false
+	}
+
+	body, err := compression.Decompress(encoding, ctx.Request.Body(), maxSize)
+	if errors.Is(err, compression.ErrTooLarge) {
+		msg := NewErrorResponse("ERR_REQUEST_TOO_LARGE", fmt.Sprintf(messages.ErrRequestDecompression, err))
+		respond(ctx, withError(fasthttp.StatusRequestEntityTooLarge, msg))
+		return false
+	} else if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrRequestDecompression, err))
+		respond(ctx, withError(fasthttp.StatusBadRequest, msg))
+		return false
+	}
+	ctx.Request.SetBody(body)
+	ctx.Request.Header.Del(fasthttp.HeaderContentEncoding)
+	return true
+}
+
+// compressResponse compresses the body of the response, unless it's streamed, already encoded or too small.
+func compressResponse(ctx *fasthttp.RequestCtx, policy *compression.Policy) {
+	resp := &ctx.Response
+	// The streamed bodies are left untouched, so they're still sent to the caller as they are read.
+	if resp.IsBodyStream() || len(resp.Header.Peek(fasthttp.HeaderContentEncoding)) > 0 ||
+		bytes.HasPrefix(resp.Header.ContentType(), eventStreamContentType) || ctx.IsHead() {
+		return
+	}
+	body := resp.Body()
+	if len(body) < policy.MinSize {
+		return
+	}
+
+	resp.Header.Add(fasthttp.HeaderVary, fasthttp.HeaderAcceptEncoding)
+	encoding := policy.Negotiate(string(ctx.Request.Header.Peek(fasthttp.HeaderAcceptEncoding)))
+	if encoding == "" {
+		return
+	}
+	compressed, err := compression.Compress(encoding, body)
+	if err != nil {
+		log.Debugf("failed to compress the response with %s: %s", encoding, err)
+		return
+	}
+	if len(compressed) >= len(body) {
+		return
+	}
+	resp.SetBodyRaw(compressed)
+	resp.Header.Set(fasthttp.HeaderContentEncoding, encoding)
+}
@@ -13,6 +13,8 @@ limitations under the License.
 
 package http
 
+import "github.com/dapr/dapr/utils"
+
 // ServerConfig holds config values for an HTTP server.
 type ServerConfig struct {
 	AppID              string
@@ -20,6 +22,7 @@ type ServerConfig struct {
 	Port               int
 	APIListenAddresses []string
 	PublicPort         *int
+	PublicAllowlist    utils.IPAllowlist
 	ProfilePort        int
 	AllowedOrigins     string
 	EnableProfiling    bool
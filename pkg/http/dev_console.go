@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	routing "github.com/fasthttp/router"
+	"github.com/valyala/fasthttp"
+
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+const (
+	devConsoleRoute = "devconsole"
+
+	// devConsoleKeepAliveInterval is the interval of the comments sent to the clients of the developer console
+	// when there are no events, to detect the clients that disconnected.
+	devConsoleKeepAliveInterval = 15 * time.Second
+)
+
+var devConsolePath = "/" + apiVersionV1alpha1 + "/" + devConsoleRoute
+
+// devConsoleEndpoint streams the events of the developer console as server-sent events.
+// It's only installed when the developer console is enabled, regardless of the alpha APIs of the configuration.
+func (s *server) devConsoleEndpoint() Endpoint {
+	return Endpoint{
+		Methods: []string{fasthttp.MethodGet},
+		Route:   devConsoleRoute,
+		Version: apiVersionV1alpha1,
+		Handler: s.onDevConsole,
+	}
+}
+
+func (s *server) onDevConsole(reqCtx *fasthttp.RequestCtx) {
+	events, unsubscribe := s.devConsole.Subscribe()
+
+	reqCtx.SetContentType("text/event-stream")
+	reqCtx.Response.Header.Set("Cache-Control", "no-cache")
+	reqCtx.Response.Header.Set("Connection", "keep-alive")
+	reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		keepAlive := time.NewTicker(devConsoleKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		// Send the headers right away so that the clients know they're connected.
+		if _, err := w.WriteString(": connected\n\n"); err != nil || w.Flush() != nil {
+			return
+		}
+		for {
+			select {
+			case event := <-events:
+				b, err := json.Marshal(event)
+				if err != nil {
+					log.Debugf("failed to serialize the developer console event of %s: %s", event.Method, err)
+					continue
+				}
+				if _, err = w.WriteString("data: " + string(b) + "\n\n"); err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				if _, err := w.WriteString(": keep-alive\n\n"); err != nil {
+					return
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+}
+
+// useDevConsole records the calls to the Dapr HTTP API in the developer console.
+func (s *server) useDevConsole(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	pathTemplater := diagUtils.NewPathTemplater(s.metricSpec.GetPathTemplates())
+	return func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Path()) == devConsolePath {
+			next(ctx)
+			return
+		}
+
+		start := time.Now()
+		method := string(ctx.Method())
+
+		next(ctx)
+
+		route, ok := ctx.UserValue(routing.MatchedRoutePathParam).(string)
+		if !ok {
+			route = pathTemplater.Template(string(ctx.Path()))
+		}
+		kind := diag.DevConsoleKindAPI
+		if strings.Contains(route, "/actors/{actorType}/{actorId}/method/") {
+			kind = diag.DevConsoleKindActor
+		}
+		var target string
+		for _, param := range auditTargetParams {
+			if v, ok := ctx.UserValue(param).(string); ok && v != "" {
+				target = v
+				break
+			}
+		}
+		s.devConsole.Record(diag.DevConsoleEvent{
+			Time:       start,
+			Kind:       kind,
+			Protocol:   protocol,
+			Method:     method + " " + route,
+			Target:     target,
+			Result:     strconv.Itoa(ctx.Response.StatusCode()),
+			DurationMs: float64(time.Since(start)) / float64(time.Millisecond),
+		})
+	}
+}
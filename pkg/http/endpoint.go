@@ -13,7 +13,14 @@ limitations under the License.
 
 package http
 
-import "github.com/valyala/fasthttp"
+import (
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// deprecationHeader is the header of the responses of the deprecated APIs.
+const deprecationHeader = "Deprecation"
 
 // Endpoint is a collection of route information for an Dapr API.
 //
@@ -27,5 +34,11 @@ type Endpoint struct {
 	Version           string
 	Alias             string
 	KeepParamUnescape bool // keep the param in path unescaped
+	Deprecated        bool // the responses have a deprecation header and the calls are counted
 	Handler           fasthttp.RequestHandler
 }
+
+// IsAlpha returns true if the endpoint is an alpha API, which is disabled unless enabled in the configuration.
+func (e Endpoint) IsAlpha() bool {
+	return strings.Contains(e.Version, "alpha")
+}
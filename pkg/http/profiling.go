@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/pprofhandler"
+
+	auth "github.com/dapr/dapr/pkg/runtime/security"
+)
+
+const (
+	profilingPathPrefix         = "/debug/pprof"
+	defaultProfilingMaxDuration = 30 * time.Second
+)
+
+// profilingMaxDuration returns the maximum duration of the profiles and traces captured on the public port.
+func (s *server) profilingMaxDuration() time.Duration {
+	if s.profilingSpec.MaxDuration == "" {
+		return defaultProfilingMaxDuration
+	}
+	d, err := time.ParseDuration(s.profilingSpec.MaxDuration)
+	if err != nil || d <= 0 {
+		log.Warnf("ignoring the invalid maximum profiling duration %q", s.profilingSpec.MaxDuration)
+		return defaultProfilingMaxDuration
+	}
+	return d
+}
+
+// useProfiling serves the pprof endpoints, including /debug/pprof/trace for the execution traces, on the public port.
+// The callers must be in the allowlist of the public port and send the Dapr API token, so the endpoints are only
+// served when the API token is set.
+func (s *server) useProfiling(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if !s.profilingSpec.Enabled {
+		return next
+	}
+	token := auth.GetAPIToken()
+	if token == "" {
+		log.Warn("profiling on the public port is disabled: it requires the Dapr API token to be set")
+		return next
+	}
+	maxDuration := s.profilingMaxDuration()
+	allowlist := s.config.PublicAllowlist
+	log.Infof("enabled profiling on the public port, with a maximum duration of %s", maxDuration)
+
+	return func(ctx *fasthttp.RequestCtx) {
+		path := string(ctx.Path())
+		if path != profilingPathPrefix && !strings.HasPrefix(path, profilingPathPrefix+"/") {
+			next(ctx)
+			return
+		}
+		if !allowlist.Allows(ctx.RemoteAddr().String()) {
+			ctx.Error("forbidden", http.StatusForbidden)
+			return
+		}
+		v := ctx.Request.Header.Peek(auth.APITokenHeader)
+		if subtle.ConstantTimeCompare(v, []byte(token)) != 1 {
+			ctx.Error("invalid api token", http.StatusUnauthorized)
+			return
+		}
+		if seconds := ctx.QueryArgs().Peek("seconds"); len(seconds) > 0 {
+			n, err := strconv.ParseFloat(string(seconds), 64)
+			if err != nil || n <= 0 || time.Duration(n*float64(time.Second)) > maxDuration {
+				ctx.Error(fmt.Sprintf("invalid duration: the maximum is %s", maxDuration), http.StatusBadRequest)
+				return
+			}
+		}
+		pprofhandler.PprofHandler(ctx)
+	}
+}
@@ -13,6 +13,10 @@ limitations under the License.
 
 package http
 
+import (
+	"github.com/dapr/components-contrib/state"
+)
+
 // OutputBindingRequest is the request object to invoke an output binding.
 type OutputBindingRequest struct {
 	Metadata  map[string]string `json:"metadata"`
@@ -26,3 +30,17 @@ type BulkGetRequest struct {
 	Keys        []string          `json:"keys"`
 	Parallelism int               `json:"parallelism"`
 }
+
+// BulkSetRequest is the request object to save multiple items, each with its own etag and options, in a state store.
+type BulkSetRequest struct {
+	Metadata    map[string]string  `json:"metadata"`
+	Items       []state.SetRequest `json:"items"`
+	Parallelism int                `json:"parallelism"`
+}
+
+// BulkDeleteRequest is the request object to delete multiple items, each with its own etag and options, from a state store.
+type BulkDeleteRequest struct {
+	Metadata    map[string]string     `json:"metadata"`
+	Items       []state.DeleteRequest `json:"items"`
+	Parallelism int                   `json:"parallelism"`
+}
@@ -14,9 +14,15 @@ limitations under the License.
 package http
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/valyala/fasthttp"
+
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 )
 
 const (
@@ -34,6 +40,14 @@ type BulkGetResponse struct {
 	Error    string            `json:"error,omitempty"`
 }
 
+// BulkStateItemResponse is the result of an item of a state bulk set or delete operation.
+// The error code is ERR_STATE_ETAG_MISMATCH when the etag of the item doesn't match the stored one.
+type BulkStateItemResponse struct {
+	Key       string `json:"key"`
+	ErrorCode string `json:"errorCode,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
 // QueryResponse is the response object for querying state.
 type QueryResponse struct {
 	Results  []QueryItem       `json:"results"`
@@ -92,6 +106,47 @@ func withEmpty() option {
 	}
 }
 
+// withTrailers sends the body chunked, followed by the trailers. It must be applied after the body is set.
+func withTrailers(trailers invokev1.DaprInternalMetadata) option {
+	return func(ctx *fasthttp.RequestCtx) {
+		if invokev1.InternalMetadataToHTTPTrailers(trailers, &ctx.Response.Header) {
+			body := append([]byte(nil), ctx.Response.Body()...)
+			ctx.Response.SetBodyStream(bytes.NewReader(body), -1)
+		}
+	}
+}
+
+// withInterimResponses writes the informational (1xx) responses to the connection, ahead of the final response.
+func withInterimResponses(responses []*internalv1pb.InterimResponse) option {
+	return func(ctx *fasthttp.RequestCtx) {
+		// HTTP/1.0 clients don't understand informational responses.
+		if len(responses) == 0 || !ctx.Request.Header.IsHTTP11() {
+			return
+		}
+
+		var b bytes.Buffer
+		for _, r := range responses {
+			if r.Code <= fasthttp.StatusContinue || r.Code >= fasthttp.StatusOK || r.Code == fasthttp.StatusSwitchingProtocols {
+				continue
+			}
+			fmt.Fprintf(&b, "HTTP/1.1 %d %s\r\n", r.Code, fasthttp.StatusMessage(int(r.Code)))
+			for k, v := range r.Headers {
+				for _, val := range v.Values {
+					if !strings.ContainsAny(k+val, "\r\n") {
+						fmt.Fprintf(&b, "%s: %s\r\n", k, val)
+					}
+				}
+			}
+			b.WriteString("\r\n")
+		}
+		if b.Len() > 0 {
+			if _, err := ctx.Conn().Write(b.Bytes()); err != nil {
+				log.Debugf("failed to write the interim responses: %s", err)
+			}
+		}
+	}
+}
+
 // with sets a default application/json content type if content type is not present.
 func with(code int, obj []byte) option {
 	return func(ctx *fasthttp.RequestCtx) {
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	routing "github.com/fasthttp/router"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/messages"
+)
+
+// routeTimeoutParam is the user value of the requests holding the timeout of their route.
+const routeTimeoutParam = "dapr.routeTimeout"
+
+// maxRequestBodySize returns the maximum request body size in MB of the APIs: the size of the server,
+// or the largest size of the route limits. The smaller sizes are enforced by the handlers of the routes.
+func (s *server) maxRequestBodySize() int {
+	size := s.config.MaxRequestBodySize
+	for _, l := range s.apiSpec.HTTPRouteLimits {
+		if l.MaxRequestBodySize > size {
+			size = l.MaxRequestBodySize
+		}
+	}
+	return size
+}
+
+// routeLimit returns the route limit with the longest name matching the endpoint.
+func (s *server) routeLimit(e Endpoint) (config.HTTPRouteLimit, bool) {
+	var (
+		limit config.HTTPRouteLimit
+		found bool
+	)
+	for _, l := range s.apiSpec.HTTPRouteLimits {
+		if !strings.HasPrefix(e.Route, l.Name) || (l.Version != "" && l.Version != e.Version) {
+			continue
+		}
+		if !found || len(l.Name) > len(limit.Name) {
+			limit, found = l, true
+		}
+	}
+	return limit, found
+}
+
+// useRouteLimits enforces the maximum request body size of the route limit of the endpoint.
+// The timeouts are enforced by useRouteTimeouts, in front of all the other handlers.
+func (s *server) useRouteLimits(e Endpoint, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	limit, ok := s.routeLimit(e)
+
+	size := s.config.MaxRequestBodySize
+	if ok && limit.MaxRequestBodySize > 0 {
+		size = limit.MaxRequestBodySize
+	}
+	if size < s.maxRequestBodySize() {
+		next = requestBodySizeHandler(size, next)
+	}
+	return next
+}
+
+// routeTimeout returns the timeout of the route limit of the endpoint, or 0 if none.
+func (s *server) routeTimeout(e Endpoint, path string) time.Duration {
+	limit, ok := s.routeLimit(e)
+	if !ok || limit.Timeout == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(limit.Timeout)
+	if err != nil || timeout <= 0 {
+		log.Warnf("ignoring the invalid timeout %q of the route limit %s for %s", limit.Timeout, limit.Name, path)
+		return 0
+	}
+	return timeout
+}
+
+// useRouteTimeouts enforces the timeouts of the route limits of the endpoints.
+// It must wrap all the other handlers: a handler that times out keeps running with the request, which the server
+// doesn't reuse, so no other handler must use the request once the timeout response is sent.
+func (s *server) useRouteTimeouts(endpoints []Endpoint, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	// The handlers of the router record the timeout of the route matching the request.
+	var timeouts *routing.Router
+	register := func(e Endpoint, path string) {
+		timeout := s.routeTimeout(e, path)
+		if timeout <= 0 {
+			return
+		}
+		if timeouts == nil {
+			timeouts = routing.New()
+		}
+		for _, m := range e.Methods {
+			timeouts.Handle(m, path, func(ctx *fasthttp.RequestCtx) {
+				ctx.SetUserValue(routeTimeoutParam, timeout)
+			})
+		}
+	}
+	for _, e := range endpoints {
+		if !s.endpointAllowed(e) {
+			continue
+		}
+		register(e, fmt.Sprintf("/%s/%s", e.Version, e.Route))
+		if e.Alias != "" {
+			register(e, fmt.Sprintf("/%s", e.Alias))
+		}
+	}
+	if timeouts == nil {
+		return next
+	}
+
+	return func(ctx *fasthttp.RequestCtx) {
+		path := string(ctx.Request.URI().PathOriginal())
+		h, _ := timeouts.Lookup(string(ctx.Request.Header.Method()), path, nil)
+		if h == nil {
+			next(ctx)
+			return
+		}
+		h(ctx)
+		timeout, _ := ctx.UserValue(routeTimeoutParam).(time.Duration)
+		serveWithTimeout(ctx, timeout, next)
+	}
+}
+
+func requestBodySizeHandler(size int, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if l := len(ctx.Request.Body()); l > size*1024*1024 {
+			msg := NewErrorResponse("ERR_REQUEST_TOO_LARGE", fmt.Sprintf(messages.ErrRequestBodyTooLarge, l, size))
+			respond(ctx, withError(fasthttp.StatusRequestEntityTooLarge, msg))
+			return
+		}
+		next(ctx)
+	}
+}
+
+// serveWithTimeout responds with an error if the handler doesn't return within the timeout.
+// The handler isn't interrupted, but its response is discarded.
+func serveWithTimeout(ctx *fasthttp.RequestCtx, timeout time.Duration, next fasthttp.RequestHandler) {
+	done := make(chan struct{})
+	go func() {
+		next(ctx)
+		close(done)
+	}()
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-done:
+	case <-t.C:
+		b, _ := json.Marshal(NewErrorResponse("ERR_REQUEST_TIMEOUT", fmt.Sprintf(messages.ErrRequestTimeout, timeout)))
+		resp := &fasthttp.Response{}
+		resp.SetStatusCode(fasthttp.StatusGatewayTimeout)
+		resp.Header.SetContentType(jsonContentTypeHeader)
+		resp.SetBody(b)
+		// The context isn't reused by the server while the handler may still use it.
+		ctx.TimeoutErrorWithResponse(resp)
+	}
+}
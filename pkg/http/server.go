@@ -14,13 +14,18 @@ limitations under the License.
 package http
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	cors "github.com/AdhityaRamadhanus/fasthttpcors"
 	routing "github.com/fasthttp/router"
@@ -58,30 +63,46 @@ type server struct {
 	pipeline           httpMiddleware.Pipeline
 	api                API
 	apiSpec            config.APISpec
+	corsSpec           config.CORSSpec
+	compressionSpec    config.CompressionSpec
+	profilingSpec      config.ProfilingSpec
+	auditor            *diag.Auditor
+	devConsole         *diag.DevConsole
 	servers            []*fasthttp.Server
 	profilingListeners []net.Listener
 }
 
 // NewServerOpts are the options for NewServer.
 type NewServerOpts struct {
-	API         API
-	Config      ServerConfig
-	TracingSpec config.TracingSpec
-	MetricSpec  config.MetricSpec
-	Pipeline    httpMiddleware.Pipeline
-	APISpec     config.APISpec
+	API             API
+	Config          ServerConfig
+	TracingSpec     config.TracingSpec
+	MetricSpec      config.MetricSpec
+	Pipeline        httpMiddleware.Pipeline
+	APISpec         config.APISpec
+	CORSSpec        config.CORSSpec
+	CompressionSpec config.CompressionSpec
+	ProfilingSpec   config.ProfilingSpec
+	Auditor         *diag.Auditor
+	DevConsole      *diag.DevConsole
 }
 
 // NewServer returns a new HTTP server.
 func NewServer(opts NewServerOpts) Server {
 	infoLog.SetOutputLevel(logger.LogLevel("info"))
+	diag.DefaultRuntimeLogging.AddAPILogger(infoLog)
 	return &server{
-		api:         opts.API,
-		config:      opts.Config,
-		tracingSpec: opts.TracingSpec,
-		metricSpec:  opts.MetricSpec,
-		pipeline:    opts.Pipeline,
-		apiSpec:     opts.APISpec,
+		api:             opts.API,
+		config:          opts.Config,
+		tracingSpec:     opts.TracingSpec,
+		metricSpec:      opts.MetricSpec,
+		pipeline:        opts.Pipeline,
+		apiSpec:         opts.APISpec,
+		corsSpec:        opts.CORSSpec,
+		compressionSpec: opts.CompressionSpec,
+		profilingSpec:   opts.ProfilingSpec,
+		auditor:         opts.Auditor,
+		devConsole:      opts.DevConsole,
 	}
 }
 
@@ -89,17 +110,25 @@ func NewServer(opts NewServerOpts) Server {
 func (s *server) StartNonBlocking() error {
 	handler := useAPIAuthentication(
 		s.useCors(
-			s.useComponents(
-				s.useRouter())))
+			s.useCompression(
+				s.useComponents(
+					s.useRouter()))))
 
 	handler = s.useMetrics(handler)
 	handler = s.useTracing(handler)
 
-	enableAPILogging := s.config.EnableAPILogging
-	if enableAPILogging {
-		handler = s.apiLoggingInfo(handler)
+	handler = s.apiLoggingInfo(handler)
+
+	if s.devConsole != nil {
+		handler = s.useDevConsole(handler)
+	}
+
+	if s.auditor != nil {
+		handler = s.useAudit(handler)
 	}
 
+	handler = s.useRouteTimeouts(s.api.APIEndpoints(), handler)
+
 	var listeners []net.Listener
 	var profilingListeners []net.Listener
 	if s.config.UnixDomainSocket != "" {
@@ -128,7 +157,7 @@ func (s *server) StartNonBlocking() error {
 		// has a handle on the underlying listener.
 		customServer := &fasthttp.Server{
 			Handler:            handler,
-			MaxRequestBodySize: s.config.MaxRequestBodySize * 1024 * 1024,
+			MaxRequestBodySize: s.maxRequestBodySize() * 1024 * 1024,
 			ReadBufferSize:     s.config.ReadBufferSize * 1024,
 		}
 		s.servers = append(s.servers, customServer)
@@ -142,6 +171,9 @@ func (s *server) StartNonBlocking() error {
 
 	if s.config.PublicPort != nil {
 		publicHandler := s.usePublicRouter()
+		publicHandler = s.usePublicProtection(publicHandler)
+		// The profiling endpoints check the Dapr API token, before the protection removes the header.
+		publicHandler = s.useProfiling(publicHandler)
 		publicHandler = s.useMetrics(publicHandler)
 		publicHandler = s.useTracing(publicHandler)
 
@@ -226,9 +258,56 @@ func (s *server) useMetrics(next fasthttp.RequestHandler) fasthttp.RequestHandle
 }
 
 func (s *server) apiLoggingInfo(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	// Log the templated paths, so the logs can be aggregated by route
+	pathTemplater := diagUtils.NewPathTemplater(s.metricSpec.GetPathTemplates())
+	return func(ctx *fasthttp.RequestCtx) {
+		// The API logging can be toggled at runtime
+		if diag.DefaultRuntimeLogging.APILoggingEnabled(s.config.EnableAPILogging) {
+			infoLog.Infof("HTTP API Called: %s %s", ctx.Method(), pathTemplater.Template(string(ctx.Path())))
+		}
+		next(ctx)
+	}
+}
+
+// auditTargetParams are the route parameters naming the component, app or actor type targeted by the calls.
+// The keys and the IDs of the resources are not recorded, as they can contain personal data.
+var auditTargetParams = []string{"actorType", "storeName", "secretStoreName", "pubsubname", "workflowName", "id", "name"}
+
+func (s *server) useAudit(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	// The calls that don't match any route are recorded with the templated path
+	pathTemplater := diagUtils.NewPathTemplater(s.metricSpec.GetPathTemplates())
 	return func(ctx *fasthttp.RequestCtx) {
-		infoLog.Infof("HTTP API Called: %s %s", ctx.Method(), ctx.Path())
+		start := time.Now()
+		method := string(ctx.Method())
+		payloadHash := s.auditor.PayloadHash(ctx.Request.Body())
+		headers := s.auditor.Headers(func(name string) string {
+			return string(ctx.Request.Header.Peek(name))
+		})
+
 		next(ctx)
+
+		route, ok := ctx.UserValue(routing.MatchedRoutePathParam).(string)
+		if !ok {
+			route = pathTemplater.Template(string(ctx.Path()))
+		}
+		var target string
+		for _, param := range auditTargetParams {
+			if v, ok := ctx.UserValue(param).(string); ok && v != "" {
+				target = v
+				break
+			}
+		}
+		s.auditor.Record(diag.AuditRecord{
+			Time:        start,
+			CallerAppID: s.config.AppID,
+			Protocol:    diag.AuditProtocolHTTP,
+			Method:      method + " " + route,
+			Target:      target,
+			Result:      strconv.Itoa(ctx.Response.StatusCode()),
+			DurationMs:  float64(time.Since(start)) / float64(time.Millisecond),
+			Headers:     headers,
+			PayloadHash: payloadHash,
+		})
 	}
 }
 
@@ -251,6 +330,11 @@ func (s *server) useComponents(next fasthttp.RequestHandler) fasthttp.RequestHan
 }
 
 func (s *server) useCors(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if s.corsSpec.IsEnabled() {
+		log.Infof("enabled cors http middleware with the configuration policy")
+		return s.useCorsPolicy(next)
+	}
+
 	if s.config.AllowedOrigins == corsDapr.DefaultAllowedOrigins {
 		return next
 	}
@@ -261,6 +345,19 @@ func (s *server) useCors(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return corsHandler.CorsMiddleware(next)
 }
 
+// healthRoutes are the paths of the health endpoints, which are reachable without the api tokens for the probes.
+var healthRoutes = map[string]struct{}{
+	"/" + apiVersionV1 + "/healthz":          {},
+	"/" + apiVersionV1 + "/healthz/outbound": {},
+}
+
+// isHealthRoute returns whether the path is exactly the one of a health endpoint. The query string is not part of the
+// path, so it can't be used to skip the protection of the other endpoints.
+func isHealthRoute(path []byte) bool {
+	_, ok := healthRoutes[string(path)]
+	return ok
+}
+
 func useAPIAuthentication(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	token := auth.GetAPIToken()
 	if token == "" {
@@ -270,7 +367,7 @@ func useAPIAuthentication(next fasthttp.RequestHandler) fasthttp.RequestHandler
 
 	return func(ctx *fasthttp.RequestCtx) {
 		v := ctx.Request.Header.Peek(auth.APITokenHeader)
-		if auth.ExcludedRoute(string(ctx.Request.URI().FullURI())) || string(v) == token {
+		if isHealthRoute(ctx.Path()) || string(v) == token {
 			ctx.Request.Header.Del(auth.APITokenHeader)
 			next(ctx)
 		} else {
@@ -279,6 +376,37 @@ func useAPIAuthentication(next fasthttp.RequestHandler) fasthttp.RequestHandler
 	}
 }
 
+// usePublicProtection rejects the calls to the public port from the addresses outside of the allowlist, and the ones
+// without the public api token when it's set. The health endpoints stay reachable for the probes of the kubelet.
+func (s *server) usePublicProtection(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	token := auth.GetPublicAPIToken()
+	allowlist := s.config.PublicAllowlist
+	if token == "" && len(allowlist) == 0 {
+		return next
+	}
+	log.Info("enabled protection of the public http server")
+
+	return func(ctx *fasthttp.RequestCtx) {
+		if isHealthRoute(ctx.Path()) {
+			next(ctx)
+			return
+		}
+		if !allowlist.Allows(ctx.RemoteAddr().String()) {
+			ctx.Error("forbidden", http.StatusForbidden)
+			return
+		}
+		if token != "" {
+			v := ctx.Request.Header.Peek(auth.APITokenHeader)
+			if subtle.ConstantTimeCompare(v, []byte(token)) != 1 {
+				ctx.Error("invalid api token", http.StatusUnauthorized)
+				return
+			}
+			ctx.Request.Header.Del(auth.APITokenHeader)
+		}
+		next(ctx)
+	}
+}
+
 func (s *server) getCorsHandler(allowedOrigins []string) *cors.CorsHandler {
 	return cors.NewCorsHandler(cors.Options{
 		AllowedOrigins: allowedOrigins,
@@ -286,6 +414,56 @@ func (s *server) getCorsHandler(allowedOrigins []string) *cors.CorsHandler {
 	})
 }
 
+// useCorsPolicy applies the CORS policy of the configuration, or the policy of the route with the longest
+// path matching the request path.
+func (s *server) useCorsPolicy(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	type corsRoute struct {
+		path    string
+		handler fasthttp.RequestHandler
+	}
+	routes := make([]corsRoute, 0, len(s.corsSpec.Routes))
+	for _, r := range s.corsSpec.Routes {
+		routes = append(routes, corsRoute{path: r.Path, handler: getCorsPolicyHandler(r.CORSPolicy).CorsMiddleware(next)})
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].path) > len(routes[j].path)
+	})
+
+	// Requests outside of the routes aren't subject to CORS when only route policies are configured.
+	defaultHandler := next
+	if !s.corsSpec.CORSPolicy.IsEmpty() {
+		defaultHandler = getCorsPolicyHandler(s.corsSpec.CORSPolicy).CorsMiddleware(next)
+	}
+
+	return func(ctx *fasthttp.RequestCtx) {
+		path := string(ctx.Path())
+		for _, r := range routes {
+			if strings.HasPrefix(path, r.path) {
+				r.handler(ctx)
+				return
+			}
+		}
+		defaultHandler(ctx)
+	}
+}
+
+func getCorsPolicyHandler(policy config.CORSPolicy) *cors.CorsHandler {
+	// The configurations are validated when loaded; the credentials are never allowed for any origin
+	if err := policy.Validate(); err != nil {
+		log.Warnf("not allowing the credentials in the cors policy: %s", err)
+		policy.AllowCredentials = false
+	}
+	return cors.NewCorsHandler(cors.Options{
+		AllowedOrigins:   policy.AllowedOrigins,
+		AllowedMethods:   policy.AllowedMethods,
+		AllowedHeaders:   policy.AllowedHeaders,
+		ExposedHeaders:   policy.ExposedHeaders,
+		AllowCredentials: policy.AllowCredentials,
+		AllowMaxAge:      policy.MaxAge,
+		Debug:            false,
+	})
+}
+
 func (s *server) unescapeRequestParametersHandler(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 		parseError := false
@@ -316,6 +494,8 @@ func (s *server) unescapeRequestParametersHandler(next fasthttp.RequestHandler)
 
 func (s *server) getRouter(endpoints []Endpoint) *routing.Router {
 	router := routing.New()
+	// The audit and the developer console record the templates of the routes rather than the paths, which can contain personal data
+	router.SaveMatchedRoutePath = s.auditor != nil || s.devConsole != nil
 	parameterFinder, _ := regexp.Compile("/{.*}")
 	for _, e := range endpoints {
 		if !s.endpointAllowed(e) {
@@ -331,21 +511,55 @@ func (s *server) getRouter(endpoints []Endpoint) *routing.Router {
 		}
 	}
 
+	if s.devConsole != nil {
+		e := s.devConsoleEndpoint()
+		s.handle(e, parameterFinder, fmt.Sprintf("/%s/%s", e.Version, e.Route), router)
+	}
+
 	return router
 }
 
 func (s *server) handle(e Endpoint, parameterFinder *regexp.Regexp, path string, router *routing.Router) {
+	handler := e.Handler
+	if e.Deprecated {
+		handler = deprecatedHandler(path, handler)
+	}
+	handler = s.useRouteLimits(e, handler)
 	for _, m := range e.Methods {
 		pathIncludesParameters := parameterFinder.MatchString(path)
 		if pathIncludesParameters && !e.KeepParamUnescape {
-			router.Handle(m, path, s.unescapeRequestParametersHandler(e.Handler))
+			router.Handle(m, path, s.unescapeRequestParametersHandler(handler))
 		} else {
-			router.Handle(m, path, e.Handler)
+			router.Handle(m, path, handler)
 		}
 	}
 }
 
+// deprecatedHandler warns the callers of a deprecated API with a response header and counts the calls.
+func deprecatedHandler(path string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	var warnOnce sync.Once
+	return func(ctx *fasthttp.RequestCtx) {
+		warnOnce.Do(func() {
+			log.Warnf("the deprecated API %s was called and will be removed in a future release", path)
+		})
+		diag.DefaultMonitoring.DeprecatedAPICalled(protocol, path)
+		ctx.Response.Header.Set(deprecationHeader, "true")
+		next(ctx)
+	}
+}
+
 func (s *server) endpointAllowed(endpoint Endpoint) bool {
+	if endpoint.Route == "healthz" {
+		return true
+	}
+
+	// Denied APIs take precedence over the allowed ones.
+	for _, rule := range s.apiSpec.Denied {
+		if rule.Protocol == protocol && endpointMatchesRule(endpoint, rule) {
+			return false
+		}
+	}
+
 	var httpRules []config.APIAccessRule
 
 	for _, rule := range s.apiSpec.Allowed {
@@ -354,14 +568,19 @@ func (s *server) endpointAllowed(endpoint Endpoint) bool {
 		}
 	}
 	if len(httpRules) == 0 {
-		return true
+		// The alpha APIs must be enabled explicitly, either in the allow list or all together.
+		return !endpoint.IsAlpha() || s.apiSpec.EnableAlpha
 	}
 
 	for _, rule := range httpRules {
-		if (strings.Index(endpoint.Route, rule.Name) == 0 && endpoint.Version == rule.Version) || endpoint.Route == "healthz" {
+		if endpointMatchesRule(endpoint, rule) {
 			return true
 		}
 	}
 
 	return false
 }
+
+func endpointMatchesRule(endpoint Endpoint, rule config.APIAccessRule) bool {
+	return strings.Index(endpoint.Route, rule.Name) == 0 && endpoint.Version == rule.Version
+}
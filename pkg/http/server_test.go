@@ -15,7 +15,13 @@ limitations under the License.
 package http
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -26,11 +32,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
 
+	"github.com/dapr/dapr/pkg/compression"
 	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/cors"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
 	httpMiddleware "github.com/dapr/dapr/pkg/middleware/http"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 	dapr_testing "github.com/dapr/dapr/pkg/testing"
+	"github.com/dapr/dapr/utils"
 )
 
 type mockHost struct {
@@ -447,6 +458,286 @@ func TestAllowedAPISpec(t *testing.T) {
 	})
 }
 
+func TestDeniedAPISpec(t *testing.T) {
+	a := &api{}
+
+	t.Run("secrets denied", func(t *testing.T) {
+		s := server{
+			apiSpec: config.APISpec{
+				Denied: []config.APIAccessRule{
+					{
+						Name:     "secrets",
+						Version:  "v1.0",
+						Protocol: "http",
+					},
+				},
+				EnableAlpha: true,
+			},
+		}
+
+		for _, e := range a.constructSecretEndpoints() {
+			assert.False(t, s.endpointAllowed(e))
+		}
+
+		allOtherEndpoints := []Endpoint{}
+		allOtherEndpoints = append(allOtherEndpoints, a.constructActorEndpoints()...)
+		allOtherEndpoints = append(allOtherEndpoints, a.constructStateEndpoints()...)
+		allOtherEndpoints = append(allOtherEndpoints, a.constructHealthzEndpoints()...)
+
+		for _, e := range allOtherEndpoints {
+			assert.True(t, s.endpointAllowed(e))
+		}
+	})
+
+	t.Run("denied APIs take precedence over allowed ones", func(t *testing.T) {
+		rules := []config.APIAccessRule{
+			{
+				Name:     "state",
+				Version:  "v1.0",
+				Protocol: "http",
+			},
+		}
+		s := server{
+			apiSpec: config.APISpec{
+				Allowed: rules,
+				Denied:  rules,
+			},
+		}
+
+		for _, e := range a.constructStateEndpoints() {
+			if e.Version == "v1.0" {
+				assert.False(t, s.endpointAllowed(e))
+			}
+		}
+		for _, e := range a.constructHealthzEndpoints() {
+			if e.Route == healthzEndpoint {
+				assert.True(t, s.endpointAllowed(e))
+			}
+		}
+	})
+}
+
+func TestAlphaAPISpec(t *testing.T) {
+	a := &api{}
+	endpoints := a.constructStateEndpoints()
+
+	t.Run("alpha APIs disabled by default", func(t *testing.T) {
+		s := server{}
+		for _, e := range endpoints {
+			assert.Equal(t, e.Version != apiVersionV1alpha1, s.endpointAllowed(e), e.Route)
+		}
+	})
+
+	t.Run("alpha APIs enabled", func(t *testing.T) {
+		s := server{
+			apiSpec: config.APISpec{EnableAlpha: true},
+		}
+		for _, e := range endpoints {
+			assert.True(t, s.endpointAllowed(e))
+		}
+	})
+
+	t.Run("alpha API in the allow list", func(t *testing.T) {
+		s := server{
+			apiSpec: config.APISpec{
+				Allowed: []config.APIAccessRule{
+					{
+						Name:     "state",
+						Version:  apiVersionV1alpha1,
+						Protocol: "http",
+					},
+				},
+			},
+		}
+		for _, e := range endpoints {
+			assert.Equal(t, e.Version == apiVersionV1alpha1, s.endpointAllowed(e), e.Route)
+		}
+	})
+}
+
+func TestDeprecatedEndpoint(t *testing.T) {
+	s := newServer()
+	router := s.getRouter([]Endpoint{
+		{
+			Methods:    []string{fasthttp.MethodGet},
+			Route:      "old",
+			Version:    apiVersionV1,
+			Deprecated: true,
+			Handler:    func(ctx *fasthttp.RequestCtx) {},
+		},
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "new",
+			Version: apiVersionV1,
+			Handler: func(ctx *fasthttp.RequestCtx) {},
+		},
+	})
+
+	r := &fasthttp.RequestCtx{}
+	r.Request.Header.SetMethod(fasthttp.MethodGet)
+	r.Request.SetRequestURI("/v1.0/old")
+	router.Handler(r)
+	assert.Equal(t, "true", string(r.Response.Header.Peek(deprecationHeader)))
+
+	r = &fasthttp.RequestCtx{}
+	r.Request.Header.SetMethod(fasthttp.MethodGet)
+	r.Request.SetRequestURI("/v1.0/new")
+	router.Handler(r)
+	assert.Empty(t, r.Response.Header.Peek(deprecationHeader))
+}
+
+func TestRouteLimits(t *testing.T) {
+	s := newServer()
+	s.config.MaxRequestBodySize = 1
+	s.apiSpec = config.APISpec{
+		HTTPRouteLimits: []config.HTTPRouteLimit{
+			{Name: "bindings", MaxRequestBodySize: 8},
+			{Name: "bindings/slow", Version: apiVersionV1, Timeout: "50ms"},
+			{Name: "state", Timeout: "invalid"},
+		},
+	}
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Path()) == "/v1.0/bindings/slow" {
+			time.Sleep(time.Second)
+		}
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	endpoints := []Endpoint{
+		{Methods: []string{fasthttp.MethodPost}, Route: "bindings/{name}", Version: apiVersionV1, Handler: handler},
+		{Methods: []string{fasthttp.MethodPost}, Route: "bindings/slow", Version: apiVersionV1, Handler: handler},
+		{Methods: []string{fasthttp.MethodPost}, Route: "state/{name}", Version: apiVersionV1, Handler: handler},
+	}
+	router := s.getRouter(endpoints)
+	assert.Equal(t, 8, s.maxRequestBodySize())
+
+	do := func(path string, size int) *fasthttp.RequestCtx {
+		r := &fasthttp.RequestCtx{}
+		r.Request.Header.SetMethod(fasthttp.MethodPost)
+		r.Request.SetRequestURI(path)
+		r.Request.SetBody(make([]byte, size))
+		router.Handler(r)
+		return r
+	}
+
+	t.Run("body size of the route", func(t *testing.T) {
+		assert.Equal(t, fasthttp.StatusOK, do("/v1.0/bindings/files", 4<<20).Response.StatusCode())
+	})
+
+	t.Run("default body size", func(t *testing.T) {
+		r := do("/v1.0/state/store", 2<<20)
+		assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, r.Response.StatusCode())
+		assert.Contains(t, string(r.Response.Body()), "ERR_REQUEST_TOO_LARGE")
+		assert.Equal(t, fasthttp.StatusOK, do("/v1.0/state/store", 1<<20).Response.StatusCode())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ln := fasthttputil.NewInmemoryListener()
+		defer ln.Close()
+		// The middlewares keep using the request after the handler returns, which happens after the timeout.
+		middleware := func(ctx *fasthttp.RequestCtx) {
+			router.Handler(ctx)
+			ctx.Response.Header.Set("X-Middleware", "true")
+		}
+		go fasthttp.Serve(ln, s.useRouteTimeouts(endpoints, middleware))
+		client := &fasthttp.Client{Dial: func(addr string) (net.Conn, error) { return ln.Dial() }}
+
+		do := func(path string) (*fasthttp.Response, time.Duration) {
+			req := fasthttp.AcquireRequest()
+			defer fasthttp.ReleaseRequest(req)
+			resp := &fasthttp.Response{}
+			req.Header.SetMethod(fasthttp.MethodPost)
+			req.SetRequestURI("http://localhost" + path)
+
+			start := time.Now()
+			require.NoError(t, client.Do(req, resp))
+			return resp, time.Since(start)
+		}
+
+		resp, elapsed := do("/v1.0/bindings/slow")
+		assert.Less(t, elapsed, time.Second)
+		assert.Equal(t, fasthttp.StatusGatewayTimeout, resp.StatusCode())
+		assert.Contains(t, string(resp.Body()), "ERR_REQUEST_TIMEOUT")
+		assert.Empty(t, resp.Header.Peek("X-Middleware"))
+
+		// The routes without a timeout are served as is.
+		resp, _ = do("/v1.0/bindings/files")
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, "true", string(resp.Header.Peek("X-Middleware")))
+	})
+}
+
+func TestCompressionHandler(t *testing.T) {
+	large := strings.Repeat("state ", 1000)
+	s := newServer()
+	s.config.MaxRequestBodySize = 1
+	s.compressionSpec = config.CompressionSpec{
+		API: config.CompressionPolicy{Enabled: true, Encodings: []string{compression.Gzip, compression.Zstd}},
+	}
+	h := s.useCompression(func(ctx *fasthttp.RequestCtx) {
+		switch string(ctx.Path()) {
+		case "/small":
+			ctx.SetBodyString("small")
+		case "/stream":
+			ctx.SetBodyStream(strings.NewReader(large), len(large))
+		default:
+			// Echoes the decompressed request
+			ctx.SetBody(ctx.Request.Body())
+		}
+	})
+
+	do := func(path, acceptEncoding, contentEncoding string, body []byte) *fasthttp.RequestCtx {
+		r := &fasthttp.RequestCtx{}
+		r.Request.Header.SetMethod(fasthttp.MethodPost)
+		r.Request.SetRequestURI(path)
+		r.Request.Header.Set(fasthttp.HeaderAcceptEncoding, acceptEncoding)
+		if contentEncoding != "" {
+			r.Request.Header.Set(fasthttp.HeaderContentEncoding, contentEncoding)
+		}
+		r.Request.SetBody(body)
+		h(r)
+		return r
+	}
+
+	t.Run("compressed request and response", func(t *testing.T) {
+		compressed, err := compression.Compress(compression.Zstd, []byte(large))
+		require.NoError(t, err)
+		r := do("/echo", "br, zstd", compression.Zstd, compressed)
+
+		assert.Equal(t, fasthttp.StatusOK, r.Response.StatusCode())
+		assert.Equal(t, compression.Zstd, string(r.Response.Header.Peek(fasthttp.HeaderContentEncoding)))
+		assert.Equal(t, fasthttp.HeaderAcceptEncoding, string(r.Response.Header.Peek(fasthttp.HeaderVary)))
+		body, err := compression.Decompress(compression.Zstd, r.Response.Body(), len(large))
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	})
+
+	t.Run("no accepted encoding", func(t *testing.T) {
+		r := do("/echo", "br", "", []byte(large))
+		assert.Empty(t, r.Response.Header.Peek(fasthttp.HeaderContentEncoding))
+		assert.Equal(t, large, string(r.Response.Body()))
+	})
+
+	t.Run("small and streamed responses", func(t *testing.T) {
+		r := do("/small", "gzip", "", nil)
+		assert.Empty(t, r.Response.Header.Peek(fasthttp.HeaderContentEncoding))
+		r = do("/stream", "gzip", "", nil)
+		assert.Empty(t, r.Response.Header.Peek(fasthttp.HeaderContentEncoding))
+		assert.True(t, r.Response.IsBodyStream())
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		r := do("/echo", "", "deflate", []byte("data"))
+		assert.Equal(t, fasthttp.StatusUnsupportedMediaType, r.Response.StatusCode())
+		r = do("/echo", "", compression.Gzip, []byte("data"))
+		assert.Equal(t, fasthttp.StatusBadRequest, r.Response.StatusCode())
+
+		bomb, err := compression.Compress(compression.Gzip, make([]byte, 2<<20))
+		require.NoError(t, err)
+		r = do("/echo", "", compression.Gzip, bomb)
+		assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, r.Response.StatusCode())
+	})
+}
+
 func TestCorsHandler(t *testing.T) {
 	t.Run("with default cors, middleware not enabled", func(t *testing.T) {
 		srv := newServer()
@@ -476,6 +767,108 @@ func TestCorsHandler(t *testing.T) {
 		h(r)
 		assert.True(t, mh.hasCORS)
 	})
+
+	t.Run("with configuration policy, policy replaces allowed origins", func(t *testing.T) {
+		srv := newServer()
+		srv.config.AllowedOrigins = "http://test.com"
+		srv.corsSpec = config.CORSSpec{
+			CORSPolicy: config.CORSPolicy{
+				AllowedOrigins:   []string{"http://policy.com"},
+				ExposedHeaders:   []string{"X-Custom"},
+				AllowCredentials: true,
+			},
+		}
+
+		mh := mockHost{}
+		h := srv.useCors(mh.mockHandler())
+		r := &fasthttp.RequestCtx{
+			Request: fasthttp.Request{},
+		}
+		r.Request.Header.Set("Origin", "http://test.com")
+		h(r)
+		assert.False(t, mh.hasCORS)
+
+		r = &fasthttp.RequestCtx{
+			Request: fasthttp.Request{},
+		}
+		r.Request.Header.Set("Origin", "http://policy.com")
+		h(r)
+		assert.True(t, mh.hasCORS)
+		assert.Equal(t, "X-Custom", string(r.Response.Header.Peek("Access-Control-Expose-Headers")))
+		assert.Equal(t, "true", string(r.Response.Header.Peek("Access-Control-Allow-Credentials")))
+	})
+
+	t.Run("with configuration policy, credentials never allowed for any origin", func(t *testing.T) {
+		srv := newServer()
+		srv.corsSpec = config.CORSSpec{
+			CORSPolicy: config.CORSPolicy{
+				AllowCredentials: true,
+			},
+		}
+
+		mh := mockHost{}
+		h := srv.useCors(mh.mockHandler())
+		r := &fasthttp.RequestCtx{
+			Request: fasthttp.Request{},
+		}
+		r.Request.Header.Set("Origin", "http://evil.com")
+		h(r)
+		assert.Empty(t, string(r.Response.Header.Peek("Access-Control-Allow-Credentials")))
+	})
+
+	t.Run("with configuration policy, preflight request", func(t *testing.T) {
+		srv := newServer()
+		srv.corsSpec = config.CORSSpec{
+			CORSPolicy: config.CORSPolicy{
+				AllowedOrigins: []string{"http://policy.com"},
+				AllowedMethods: []string{fasthttp.MethodPut},
+				AllowedHeaders: []string{"Content-Type"},
+				MaxAge:         600,
+			},
+		}
+
+		mh := mockHost{}
+		h := srv.useCors(mh.mockHandler())
+		r := &fasthttp.RequestCtx{
+			Request: fasthttp.Request{},
+		}
+		r.Request.Header.SetMethod(fasthttp.MethodOptions)
+		r.Request.Header.Set("Origin", "http://policy.com")
+		r.Request.Header.Set("Access-Control-Request-Method", fasthttp.MethodPut)
+		r.Request.Header.Set("Access-Control-Request-Headers", "Content-Type")
+		h(r)
+		assert.Equal(t, "http://policy.com", string(r.Response.Header.Peek("Access-Control-Allow-Origin")))
+		assert.Equal(t, fasthttp.MethodPut, string(r.Response.Header.Peek("Access-Control-Allow-Methods")))
+		assert.Equal(t, "600", string(r.Response.Header.Peek("Access-Control-Max-Age")))
+	})
+
+	t.Run("with configuration policy, route overrides", func(t *testing.T) {
+		srv := newServer()
+		srv.corsSpec = config.CORSSpec{
+			Routes: []config.CORSRoutePolicy{
+				{Path: "/v1.0/invoke/", CORSPolicy: config.CORSPolicy{AllowedOrigins: []string{"http://invoke.com"}}},
+				{Path: "/v1.0/invoke/app1/", CORSPolicy: config.CORSPolicy{AllowedOrigins: []string{"http://app1.com"}}},
+			},
+		}
+
+		mh := mockHost{}
+		h := srv.useCors(mh.mockHandler())
+		serve := func(path, origin string) bool {
+			r := &fasthttp.RequestCtx{
+				Request: fasthttp.Request{},
+			}
+			r.Request.SetRequestURI(path)
+			r.Request.Header.Set("Origin", origin)
+			h(r)
+			return mh.hasCORS
+		}
+
+		assert.True(t, serve("/v1.0/invoke/app2/method/a", "http://invoke.com"))
+		assert.False(t, serve("/v1.0/invoke/app1/method/a", "http://invoke.com"))
+		assert.True(t, serve("/v1.0/invoke/app1/method/a", "http://app1.com"))
+		// No policy applies outside of the routes
+		assert.False(t, serve("/v1.0/state/store", "http://invoke.com"))
+	})
 }
 
 func TestUnescapeRequestParametersHandler(t *testing.T) {
@@ -657,6 +1050,210 @@ func TestAliasRoute(t *testing.T) {
 	})
 }
 
+func TestAudit(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := diag.NewAuditor("myapp", config.AuditSpec{
+		Enabled:     true,
+		Output:      output,
+		Headers:     []string{"dapr-api-token", "x-request-id"},
+		PayloadHash: true,
+	})
+	require.NoError(t, err)
+
+	s := &server{
+		config:  ServerConfig{AppID: "myapp"},
+		auditor: auditor,
+	}
+	handler := s.useAudit(s.getRouter([]Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "state/{storeName}/{key}",
+			Version: apiVersionV1,
+			Handler: func(ctx *fasthttp.RequestCtx) {
+				ctx.SetStatusCode(fasthttp.StatusNoContent)
+			},
+		},
+	}).Handler)
+
+	for _, path := range []string{"/v1.0/state/statestore/jane@example.com", "/v1.0/unknown/123"} {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+		ctx.Request.Header.Set("dapr-api-token", "secret")
+		ctx.Request.Header.Set("x-request-id", "42")
+		ctx.Request.SetRequestURI(path)
+		ctx.Request.SetBodyString("hello")
+		handler(ctx)
+	}
+	require.NoError(t, auditor.Close())
+
+	b, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "jane@example.com")
+	assert.NotContains(t, string(b), "secret")
+	assert.NotContains(t, string(b), "hello")
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 2)
+
+	var record diag.AuditRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "myapp", record.CallerAppID)
+	assert.Equal(t, "POST /v1.0/state/{storeName}/{key}", record.Method)
+	assert.Equal(t, "statestore", record.Target)
+	assert.Equal(t, "204", record.Result)
+	assert.Equal(t, map[string]string{"dapr-api-token": "[REDACTED]", "x-request-id": "42"}, record.Headers)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", record.PayloadHash)
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "POST /v1.0/unknown/{id}", record.Method)
+	assert.Equal(t, "404", record.Result)
+}
+
+func TestDevConsole(t *testing.T) {
+	devConsole := diag.NewDevConsole("myapp")
+	s := &server{devConsole: devConsole}
+	handler := s.useDevConsole(s.getRouter([]Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "actors/{actorType}/{actorId}/method/{method}",
+			Version: apiVersionV1,
+			Handler: func(ctx *fasthttp.RequestCtx) {
+				ctx.SetStatusCode(fasthttp.StatusOK)
+			},
+		},
+	}).Handler)
+
+	// The endpoint is installed even though the alpha APIs aren't enabled.
+	stream := &fasthttp.RequestCtx{}
+	stream.Request.Header.SetMethod(fasthttp.MethodGet)
+	stream.Request.SetRequestURI("/v1.0-alpha1/devconsole")
+	handler(stream)
+	assert.Equal(t, fasthttp.StatusOK, stream.Response.StatusCode())
+	assert.Equal(t, "text/event-stream", string(stream.Response.Header.ContentType()))
+
+	r, w := io.Pipe()
+	go func() {
+		bw := bufio.NewWriter(w)
+		_ = stream.Response.Write(bw)
+		w.Close()
+	}()
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		if lines.Text() == ": connected" {
+			break
+		}
+	}
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("/v1.0/actors/myactor/1/method/foo")
+	handler(ctx)
+
+	var event diag.DevConsoleEvent
+	for lines.Scan() {
+		if strings.HasPrefix(lines.Text(), "data: ") {
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines.Text(), "data: ")), &event))
+			break
+		}
+	}
+	r.Close()
+	assert.Equal(t, "myapp", event.AppID)
+	assert.Equal(t, diag.DevConsoleKindActor, event.Kind)
+	assert.Equal(t, "POST /v1.0/actors/{actorType}/{actorId}/method/{method}", event.Method)
+	assert.Equal(t, "myactor", event.Target)
+	assert.Equal(t, "200", event.Result)
+}
+
+func TestPublicProtection(t *testing.T) {
+	allowlist, err := utils.ParseIPAllowlist("10.0.0.0/8")
+	require.NoError(t, err)
+	t.Setenv(auth.PublicAPITokenEnvVar, "secret")
+
+	s := &server{config: ServerConfig{PublicAllowlist: allowlist}}
+	handler := s.usePublicProtection(func(ctx *fasthttp.RequestCtx) {
+		assert.Empty(t, ctx.Request.Header.Peek(auth.APITokenHeader))
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	})
+
+	call := func(path, remoteIP, token string) int {
+		req := fasthttp.Request{}
+		req.SetRequestURI(path)
+		if token != "" {
+			req.Header.Set(auth.APITokenHeader, token)
+		}
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Init(&req, &net.TCPAddr{IP: net.ParseIP(remoteIP), Port: 1234}, nil)
+		handler(ctx)
+		return ctx.Response.StatusCode()
+	}
+
+	assert.Equal(t, fasthttp.StatusOK, call("/v1.0/metadata", "10.1.2.3", "secret"))
+	assert.Equal(t, fasthttp.StatusUnauthorized, call("/v1.0/metadata", "10.1.2.3", "other"))
+	assert.Equal(t, fasthttp.StatusUnauthorized, call("/v1.0/metadata", "10.1.2.3", ""))
+	assert.Equal(t, fasthttp.StatusForbidden, call("/v1.0/metadata", "203.0.113.1", "secret"))
+	// The health endpoints are reachable for the probes of the kubelet.
+	assert.Equal(t, fasthttp.StatusOK, call("/v1.0/healthz", "203.0.113.1", ""))
+	assert.Equal(t, fasthttp.StatusOK, call("/v1.0/healthz/outbound", "203.0.113.1", ""))
+	// Only the paths of the health endpoints are exempted.
+	assert.Equal(t, fasthttp.StatusForbidden, call("/v1.0/metadata?x=/healthz", "203.0.113.1", ""))
+	assert.Equal(t, fasthttp.StatusUnauthorized, call("/v1.0/metadata?x=/healthz", "10.1.2.3", ""))
+	assert.Equal(t, fasthttp.StatusForbidden, call("/v1.0/healthz/../metadata", "203.0.113.1", ""))
+}
+
+func TestProfiling(t *testing.T) {
+	next := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusTeapot)
+	}
+	call := func(handler fasthttp.RequestHandler, uri, remoteIP, token string) *fasthttp.Response {
+		req := fasthttp.Request{}
+		req.SetRequestURI(uri)
+		if token != "" {
+			req.Header.Set(auth.APITokenHeader, token)
+		}
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Init(&req, &net.TCPAddr{IP: net.ParseIP(remoteIP), Port: 1234}, nil)
+		handler(ctx)
+		return &ctx.Response
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv(auth.APITokenEnvVar, "secret")
+		s := &server{}
+		resp := call(s.useProfiling(next), "/debug/pprof/", "10.1.2.3", "secret")
+		assert.Equal(t, fasthttp.StatusTeapot, resp.StatusCode())
+	})
+
+	t.Run("enabled without api token", func(t *testing.T) {
+		t.Setenv(auth.APITokenEnvVar, "")
+		s := &server{profilingSpec: config.ProfilingSpec{Enabled: true}}
+		resp := call(s.useProfiling(next), "/debug/pprof/", "10.1.2.3", "")
+		assert.Equal(t, fasthttp.StatusTeapot, resp.StatusCode())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv(auth.APITokenEnvVar, "secret")
+		allowlist, err := utils.ParseIPAllowlist("10.0.0.0/8")
+		require.NoError(t, err)
+		s := &server{
+			config:        ServerConfig{PublicAllowlist: allowlist},
+			profilingSpec: config.ProfilingSpec{Enabled: true, MaxDuration: "10s"},
+		}
+		handler := s.useProfiling(next)
+
+		resp := call(handler, "/debug/pprof/cmdline", "10.1.2.3", "secret")
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.NotEmpty(t, resp.Body())
+
+		assert.Equal(t, fasthttp.StatusUnauthorized, call(handler, "/debug/pprof/cmdline", "10.1.2.3", "other").StatusCode())
+		assert.Equal(t, fasthttp.StatusUnauthorized, call(handler, "/debug/pprof/", "10.1.2.3", "").StatusCode())
+		assert.Equal(t, fasthttp.StatusForbidden, call(handler, "/debug/pprof/", "203.0.113.1", "secret").StatusCode())
+		assert.Equal(t, fasthttp.StatusBadRequest, call(handler, "/debug/pprof/trace?seconds=60", "10.1.2.3", "secret").StatusCode())
+		assert.Equal(t, fasthttp.StatusBadRequest, call(handler, "/debug/pprof/profile?seconds=abc", "10.1.2.3", "secret").StatusCode())
+		// The other routes of the public port are untouched.
+		assert.Equal(t, fasthttp.StatusTeapot, call(handler, "/v1.0/healthz", "203.0.113.1", "").StatusCode())
+		assert.Equal(t, fasthttp.StatusTeapot, call(handler, "/debug/pprofx", "10.1.2.3", "").StatusCode())
+	})
+}
+
 func TestClose(t *testing.T) {
 	t.Run("test close with api logging enabled", func(t *testing.T) {
 		port, err := freeport.GetFreePort()
@@ -14,7 +14,10 @@ limitations under the License.
 package injector
 
 import (
+	"encoding/json"
+
 	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
 
 	"github.com/dapr/dapr/utils"
 )
@@ -29,6 +32,33 @@ type Config struct {
 	KubeClusterDomain           string `envconfig:"KUBE_CLUSTER_DOMAIN"`
 	AllowedServiceAccounts      string `envconfig:"ALLOWED_SERVICE_ACCOUNTS"`
 	IgnoreEntrypointTolerations string `envconfig:"IGNORE_ENTRYPOINT_TOLERATIONS"`
+	SidecarProfiles             string `envconfig:"SIDECAR_PROFILES"`
+	ExcludedContainers          string `envconfig:"EXCLUDED_CONTAINERS"`
+}
+
+// SidecarProfile is a named preset of sidecar resources and tuning settings, selected with the
+// dapr.io/sidecar-profile annotation. The annotations set on the pod take precedence over the profile.
+type SidecarProfile struct {
+	CPULimit           string `json:"cpuLimit,omitempty"`
+	MemoryLimit        string `json:"memoryLimit,omitempty"`
+	CPURequest         string `json:"cpuRequest,omitempty"`
+	MemoryRequest      string `json:"memoryRequest,omitempty"`
+	GoMemLimit         string `json:"goMemLimit,omitempty"`
+	AppMaxConcurrency  string `json:"appMaxConcurrency,omitempty"`
+	HTTPMaxRequestSize string `json:"httpMaxRequestSize,omitempty"`
+	HTTPReadBufferSize string `json:"httpReadBufferSize,omitempty"`
+}
+
+// GetSidecarProfiles returns the sidecar profiles by name, parsed from the SidecarProfiles JSON object.
+func (c Config) GetSidecarProfiles() (map[string]SidecarProfile, error) {
+	profiles := map[string]SidecarProfile{}
+	if c.SidecarProfiles == "" {
+		return profiles, nil
+	}
+	if err := json.Unmarshal([]byte(c.SidecarProfiles), &profiles); err != nil {
+		return nil, errors.Wrap(err, "failed to parse sidecar profiles")
+	}
+	return profiles, nil
 }
 
 // NewConfigWithDefaults returns a Config object with default values already
@@ -49,6 +79,10 @@ func GetConfig() (Config, error) {
 		return c, err
 	}
 
+	if _, err = c.GetSidecarProfiles(); err != nil {
+		return c, err
+	}
+
 	if c.KubeClusterDomain == "" {
 		// auto-detect KubeClusterDomain from resolv.conf file
 		clusterDomain, err := utils.GetKubeClusterDomain()
@@ -15,6 +15,7 @@ func TestGetInjectorConfig(t *testing.T) {
 		t.Setenv("NAMESPACE", "test-namespace")
 		t.Setenv("KUBE_CLUSTER_DOMAIN", "cluster.local")
 		t.Setenv("ALLOWED_SERVICE_ACCOUNTS", "test-service-account1:test1,test-service-account2:test2")
+		t.Setenv("EXCLUDED_CONTAINERS", "istio-proxy,vault-agent")
 
 		cfg, err := GetConfig()
 		assert.Nil(t, err)
@@ -25,6 +26,7 @@ func TestGetInjectorConfig(t *testing.T) {
 		assert.Equal(t, "test-namespace", cfg.Namespace)
 		assert.Equal(t, "cluster.local", cfg.KubeClusterDomain)
 		assert.Equal(t, "test-service-account1:test1,test-service-account2:test2", cfg.AllowedServiceAccounts)
+		assert.Equal(t, "istio-proxy,vault-agent", cfg.ExcludedContainers)
 	})
 
 	t.Run("not set kube cluster domain env", func(t *testing.T) {
@@ -44,4 +46,31 @@ func TestGetInjectorConfig(t *testing.T) {
 		assert.Equal(t, "test-namespace", cfg.Namespace)
 		assert.NotEqual(t, "", cfg.KubeClusterDomain)
 	})
+
+	t.Run("with sidecar profiles", func(t *testing.T) {
+		t.Setenv("TLS_CERT_FILE", "test-cert-file")
+		t.Setenv("TLS_KEY_FILE", "test-key-file")
+		t.Setenv("SIDECAR_IMAGE", "daprd-test-image")
+		t.Setenv("NAMESPACE", "test-namespace")
+		t.Setenv("KUBE_CLUSTER_DOMAIN", "cluster.local")
+		t.Setenv("SIDECAR_PROFILES", `{"small":{"cpuLimit":"500m","goMemLimit":"200MiB"}}`)
+
+		cfg, err := GetConfig()
+		assert.Nil(t, err)
+		profiles, err := cfg.GetSidecarProfiles()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]SidecarProfile{"small": {CPULimit: "500m", GoMemLimit: "200MiB"}}, profiles)
+	})
+
+	t.Run("invalid sidecar profiles", func(t *testing.T) {
+		t.Setenv("TLS_CERT_FILE", "test-cert-file")
+		t.Setenv("TLS_KEY_FILE", "test-key-file")
+		t.Setenv("SIDECAR_IMAGE", "daprd-test-image")
+		t.Setenv("NAMESPACE", "test-namespace")
+		t.Setenv("KUBE_CLUSTER_DOMAIN", "cluster.local")
+		t.Setenv("SIDECAR_PROFILES", "small")
+
+		_, err := GetConfig()
+		assert.Error(t, err)
+	})
 }
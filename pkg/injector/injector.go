@@ -61,12 +61,13 @@ type Injector interface {
 }
 
 type injector struct {
-	config       Config
-	deserializer runtime.Decoder
-	server       *http.Server
-	kubeClient   kubernetes.Interface
-	daprClient   scheme.Interface
-	authUIDs     []string
+	config          Config
+	deserializer    runtime.Decoder
+	server          *http.Server
+	kubeClient      kubernetes.Interface
+	daprClient      scheme.Interface
+	authUIDs        []string
+	sidecarProfiles map[string]SidecarProfile
 }
 
 // errorToAdmissionResponse is a helper function to create an AdmissionResponse
@@ -102,6 +103,11 @@ func getAppIDFromRequest(req *v1.AdmissionRequest) string {
 func NewInjector(authUIDs []string, config Config, daprClient scheme.Interface, kubeClient kubernetes.Interface) Injector {
 	mux := http.NewServeMux()
 
+	sidecarProfiles, err := config.GetSidecarProfiles()
+	if err != nil {
+		log.Errorf("ignoring the sidecar profiles: %s", err)
+	}
+
 	i := &injector{
 		config: config,
 		deserializer: serializer.NewCodecFactory(
@@ -112,9 +118,10 @@ func NewInjector(authUIDs []string, config Config, daprClient scheme.Interface,
 			Addr:    fmt.Sprintf(":%d", port),
 			Handler: mux,
 		},
-		kubeClient: kubeClient,
-		daprClient: daprClient,
-		authUIDs:   authUIDs,
+		kubeClient:      kubeClient,
+		daprClient:      daprClient,
+		authUIDs:        authUIDs,
+		sidecarProfiles: sidecarProfiles,
 	}
 
 	mux.HandleFunc("/mutate", i.handleRequest)
@@ -227,6 +234,7 @@ func (i *injector) handleRequest(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var patchOps []PatchOperation
+	var warnings []string
 	patchedSuccessfully := false
 
 	ar := v1.AdmissionReview{}
@@ -239,10 +247,13 @@ func (i *injector) handleRequest(w http.ResponseWriter, r *http.Request) {
 		} else if ar.Request.Kind.Kind != "Pod" {
 			log.Errorf("invalid kind for review: %s", ar.Kind)
 		} else {
-			patchOps, err = i.getPodPatchOperations(&ar, i.config.Namespace, i.config.SidecarImage, i.config.SidecarImagePullPolicy, i.kubeClient, i.daprClient)
+			patchOps, warnings, err = i.getPodPatchOperations(&ar, i.config.Namespace, i.config.SidecarImage, i.config.SidecarImagePullPolicy, i.kubeClient, i.daprClient)
 			if err == nil {
 				patchedSuccessfully = true
 			}
+			for _, warning := range warnings {
+				log.Warnf("Sidecar injector warning for pod %s/%s: %s", ar.Request.Namespace, ar.Request.Name, warning)
+			}
 		}
 	}
 
@@ -264,8 +275,9 @@ func (i *injector) handleRequest(w http.ResponseWriter, r *http.Request) {
 			admissionResponse = errorToAdmissionResponse(err)
 		} else {
 			admissionResponse = &v1.AdmissionResponse{
-				Allowed: true,
-				Patch:   patchBytes,
+				Allowed:  true,
+				Patch:    patchBytes,
+				Warnings: warnings,
 				PatchType: func() *v1.PatchType {
 					pt := v1.PatchTypeJSONPatch
 					return &pt
@@ -56,6 +56,10 @@ func TestGetConfig(t *testing.T) {
 	m := map[string]string{daprConfigKey: "config1"}
 	c := getConfig(m)
 	assert.Equal(t, "config1", c)
+
+	m = map[string]string{daprConfigKey: "baseline, team1,"}
+	c = getConfig(m)
+	assert.Equal(t, "baseline,team1", c)
 }
 
 func TestGetProfiling(t *testing.T) {
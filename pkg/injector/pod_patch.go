@@ -31,6 +31,7 @@ import (
 
 	scheme "github.com/dapr/dapr/pkg/client/clientset/versioned"
 	"github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/metrics"
 	auth "github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/dapr/dapr/pkg/sentry/certs"
 	"github.com/dapr/dapr/pkg/validation"
@@ -46,6 +47,7 @@ const (
 	daprAppProtocolKey                = "dapr.io/app-protocol"
 	appIDKey                          = "dapr.io/app-id"
 	daprEnableProfilingKey            = "dapr.io/enable-profiling"
+	daprProfilePortKey                = "dapr.io/profile-port"
 	daprLogLevel                      = "dapr.io/log-level"
 	daprAPITokenSecret                = "dapr.io/api-token-secret" /* #nosec */
 	daprAppTokenSecret                = "dapr.io/app-token-secret" /* #nosec */
@@ -56,6 +58,7 @@ const (
 	daprEnableDebugKey                = "dapr.io/enable-debug"
 	daprDebugPortKey                  = "dapr.io/debug-port"
 	daprEnvKey                        = "dapr.io/env"
+	daprSidecarProfileKey             = "dapr.io/sidecar-profile"
 	daprCPULimitKey                   = "dapr.io/sidecar-cpu-limit"
 	daprMemoryLimitKey                = "dapr.io/sidecar-memory-limit"
 	daprCPURequestKey                 = "dapr.io/sidecar-cpu-request"
@@ -69,28 +72,58 @@ const (
 	daprReadinessProbeTimeoutKey      = "dapr.io/sidecar-readiness-probe-timeout-seconds"
 	daprReadinessProbePeriodKey       = "dapr.io/sidecar-readiness-probe-period-seconds"
 	daprReadinessProbeThresholdKey    = "dapr.io/sidecar-readiness-probe-threshold"
+	daprGRPCProbesKey                 = "dapr.io/sidecar-grpc-probes"
 	daprImage                         = "dapr.io/sidecar-image"
 	daprAppSSLKey                     = "dapr.io/app-ssl"
 	daprMaxRequestBodySize            = "dapr.io/http-max-request-size"
 	daprReadBufferSize                = "dapr.io/http-read-buffer-size"
 	daprGracefulShutdownSeconds       = "dapr.io/graceful-shutdown-seconds"
+	daprAppDrainSeconds               = "dapr.io/app-drain-seconds"
+	daprAdjustTerminationGracePeriod  = "dapr.io/adjust-termination-grace-period"
 	daprEnableAPILogging              = "dapr.io/enable-api-logging"
 	daprUnixDomainSocketPath          = "dapr.io/unix-domain-socket-path"
+	daprAppUnixDomainSocket           = "dapr.io/app-unix-domain-socket"
+	daprAppHTTP2Detection             = "dapr.io/app-http2-detection"
 	daprVolumeMountsReadOnlyKey       = "dapr.io/volume-mounts"
 	daprVolumeMountsReadWriteKey      = "dapr.io/volume-mounts-rw"
+	daprInjectedContainers            = "dapr.io/injected-containers"
+	daprExcludedContainers            = "dapr.io/excluded-containers"
 	daprDisableBuiltinK8sSecretStore  = "dapr.io/disable-builtin-k8s-secret-store"
 	daprEnableAppHealthCheck          = "dapr.io/enable-app-health-check"
 	daprAppHealthCheckPath            = "dapr.io/app-health-check-path"
 	daprAppHealthProbeInterval        = "dapr.io/app-health-probe-interval"
 	daprAppHealthProbeTimeout         = "dapr.io/app-health-probe-timeout"
 	daprAppHealthThreshold            = "dapr.io/app-health-threshold"
+	daprAppStartupProbeMethod         = "dapr.io/app-startup-probe-method"
+	daprAppStartupProbeInterval       = "dapr.io/app-startup-probe-interval"
+	daprAppStartupProbeTimeout        = "dapr.io/app-startup-probe-timeout"
+	daprTraceAttributes               = "dapr.io/trace-attributes"
+	daprMetricsTokenSecret            = "dapr.io/metrics-token-secret" /* #nosec */
+	daprMetricsAllowedCIDRs           = "dapr.io/metrics-allowed-cidrs"
+	daprPublicTokenSecret             = "dapr.io/public-token-secret" /* #nosec */
+	daprPublicAllowedCIDRs            = "dapr.io/public-allowed-cidrs"
+	daprCertDNSNames                  = "dapr.io/sidecar-cert-dns-names"
+	daprInternalGRPCMaxConnectionAge  = "dapr.io/internal-grpc-max-connection-age"
+	daprInternalGRPCKeepaliveTime     = "dapr.io/internal-grpc-keepalive-time"
+	daprInternalGRPCKeepaliveTimeout  = "dapr.io/internal-grpc-keepalive-timeout"
+	daprInternalGRPCConnsPerTarget    = "dapr.io/internal-grpc-connections-per-target"
+	daprInternalGRPCMaxStreams        = "dapr.io/internal-grpc-max-concurrent-streams"
+	daprInternalGRPCIdleTimeout       = "dapr.io/internal-grpc-idle-timeout"
 	unixDomainSocketVolume            = "dapr-unix-domain-socket"
+	trustBundleVolumeName             = "dapr-trust-bundle"
+	trustBundleMountPath              = "/var/run/dapr/trust-bundle"
 	daprPlacementAddressesKey         = "dapr.io/placement-host-address"
+	daprPlacementTLSCAFile            = "dapr.io/placement-tls-ca-file"
+	daprPlacementTLSServerName        = "dapr.io/placement-tls-server-name"
+	daprPlacementTokenSecret          = "dapr.io/placement-token-secret" /* #nosec */
 	containersPath                    = "/spec/containers"
+	volumesPath                       = "/spec/volumes"
+	terminationGracePeriodPath        = "/spec/terminationGracePeriodSeconds"
 	sidecarHTTPPort                   = 3500
 	sidecarAPIGRPCPort                = 50001
 	sidecarInternalGRPCPort           = 50002
 	sidecarPublicPort                 = 3501
+	sidecarPublicGRPCPort             = 3502
 	userContainerDaprHTTPPortName     = "DAPR_HTTP_PORT"
 	userContainerDaprGRPCPortName     = "DAPR_GRPC_PORT"
 	apiAddress                        = "dapr-api"
@@ -104,6 +137,7 @@ const (
 	sidecarInternalGRPCPortName       = "dapr-internal"
 	sidecarMetricsPortName            = "dapr-metrics"
 	sidecarDebugPortName              = "dapr-debug"
+	sidecarProfilePortName            = "dapr-profile"
 	defaultLogLevel                   = "info"
 	defaultLogAsJSON                  = false
 	defaultAppSSL                     = false
@@ -113,6 +147,7 @@ const (
 	defaultMetricsPort                = 9090
 	defaultSidecarDebug               = false
 	defaultSidecarDebugPort           = 40000
+	defaultProfilePort                = 7777
 	defaultSidecarListenAddresses     = "[::1],127.0.0.1"
 	sidecarHealthzPath                = "healthz"
 	defaultHealthzProbeDelaySeconds   = 3
@@ -127,6 +162,10 @@ const (
 	defaultAppHealthProbeInterval     = 5   // in seconds
 	defaultAppHealthProbeTimeout      = 500 // in ms
 	defaultAppHealthThreshold         = 3
+	defaultAppStartupProbeInterval    = 1 // in seconds
+	defaultAppStartupProbeTimeout     = 0 // in seconds
+	defaultGracefulShutdownSeconds    = 5
+	defaultTerminationGracePeriod     = 30 // in seconds, as defaulted by Kubernetes
 )
 
 // sidecarContainerConfig contains the configuration for the sidecar container.
@@ -137,6 +176,7 @@ type sidecarContainerConfig struct {
 	certKey                     string
 	controlPlaneAddress         string
 	daprSidecarImage            string
+	goMemLimit                  string
 	identity                    string
 	ignoreEntrypointTolerations string
 	imagePullPolicy             string
@@ -148,17 +188,20 @@ type sidecarContainerConfig struct {
 	tokenVolumeMount            *corev1.VolumeMount
 	tolerations                 []corev1.Toleration
 	trustAnchors                string
+	trustBundleVolumeMount      *corev1.VolumeMount
 	volumeMounts                []corev1.VolumeMount
 }
 
+// getPodPatchOperations returns the patch operations injecting the sidecar in the pod of the admission review,
+// along with the warnings to return to the API client.
 func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 	namespace, image, imagePullPolicy string, kubeClient kubernetes.Interface, daprClient scheme.Interface,
-) ([]PatchOperation, error) {
+) ([]PatchOperation, []string, error) {
 	req := ar.Request
 	var pod corev1.Pod
 	if err := json.Unmarshal(req.Object.Raw, &pod); err != nil {
 		errors.Wrap(err, "could not unmarshal raw object")
-		return nil, err
+		return nil, nil, err
 	}
 
 	log.Infof(
@@ -174,13 +217,13 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 	)
 
 	if !isResourceDaprEnabled(pod.Annotations) || podContainsSidecarContainer(&pod) {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	appID := getAppID(pod)
 	err := validation.ValidateKubernetesAppID(appID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Keep DNS resolution outside of getSidecarContainer for unit testing.
@@ -193,15 +236,23 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 	var certKey string
 
 	trustAnchors, certChain, certKey = getTrustAnchorsAndCertChain(kubeClient, namespace)
+	podVolumes := pod.Spec.Volumes
+	trustBundleVolume, trustBundleVolumeMount := getTrustBundleVolume(kubeClient, req.Namespace)
 	socketVolumeMount := appendUnixDomainSocketVolume(&pod)
 
+	annotations, profile, err := applySidecarProfile(pod.Annotations, i.sidecarProfiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	cfg := sidecarContainerConfig{
 		appID:                       appID,
-		annotations:                 pod.Annotations,
+		annotations:                 annotations,
 		certChain:                   certChain,
 		certKey:                     certKey,
 		controlPlaneAddress:         apiSvcAddress,
 		daprSidecarImage:            image,
+		goMemLimit:                  profile.GoMemLimit,
 		identity:                    fmt.Sprintf("%s:%s", req.Namespace, pod.Spec.ServiceAccountName),
 		ignoreEntrypointTolerations: i.config.IgnoreEntrypointTolerations,
 		imagePullPolicy:             imagePullPolicy,
@@ -213,11 +264,17 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 		tokenVolumeMount:            getTokenVolumeMount(pod),
 		tolerations:                 pod.Spec.Tolerations,
 		trustAnchors:                trustAnchors,
+		trustBundleVolumeMount:      trustBundleVolumeMount,
 		volumeMounts:                getVolumeMounts(pod),
 	}
 	sidecarContainer, err := getSidecarContainer(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	gracePeriodPatchOps, warnings, err := getTerminationGracePeriodPatchOperations(pod, annotations)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	patchOps := []PatchOperation{}
@@ -229,8 +286,9 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 		path = containersPath
 		value = []corev1.Container{*sidecarContainer}
 	} else {
-		envPatchOps = addDaprEnvVarsToContainers(pod.Spec.Containers)
-		socketVolumePatchOps = addSocketVolumeToContainers(pod.Spec.Containers, socketVolumeMount)
+		filter := getContainerFilter(annotations, i.config.ExcludedContainers)
+		envPatchOps = addDaprEnvVarsToContainers(pod.Spec.Containers, filter)
+		socketVolumePatchOps = addSocketVolumeToContainers(pod.Spec.Containers, socketVolumeMount, filter)
 		path = "/spec/containers/-"
 		value = sidecarContainer
 	}
@@ -245,13 +303,70 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 	)
 	patchOps = append(patchOps, envPatchOps...)
 	patchOps = append(patchOps, socketVolumePatchOps...)
+	if trustBundleVolume != nil {
+		patchOps = append(patchOps, getVolumePatchOperation(podVolumes, *trustBundleVolume))
+	}
+	patchOps = append(patchOps, gracePeriodPatchOps...)
+
+	return patchOps, warnings, nil
+}
+
+// containerFilter selects the containers of the pod in which the Dapr environment variables and the unix domain socket
+// volume are added, so the other sidecars of the pod, such as istio-proxy or vault-agent, are left untouched.
+type containerFilter struct {
+	// The names of the containers to select, all the containers if empty.
+	injected map[string]struct{}
+	// The names of the containers to skip.
+	excluded map[string]struct{}
+}
+
+// getContainerFilter returns the filter of the containers set by the dapr.io/injected-containers and
+// dapr.io/excluded-containers annotations, and by the containers excluded in all the pods.
+func getContainerFilter(annotations map[string]string, excludedContainers string) containerFilter {
+	filter := containerFilter{
+		injected: parseContainerNames(getStringAnnotation(annotations, daprInjectedContainers)),
+		excluded: parseContainerNames(getStringAnnotation(annotations, daprExcludedContainers)),
+	}
+	for name := range parseContainerNames(excludedContainers) {
+		if filter.excluded == nil {
+			filter.excluded = map[string]struct{}{}
+		}
+		filter.excluded[name] = struct{}{}
+	}
+	return filter
+}
+
+// includes returns whether the container is selected: the containers both injected and excluded are skipped.
+func (f containerFilter) includes(name string) bool {
+	if _, ok := f.excluded[name]; ok {
+		return false
+	}
+	if len(f.injected) == 0 {
+		return true
+	}
+	_, ok := f.injected[name]
+	return ok
+}
 
-	return patchOps, nil
+// parseContainerNames parses a comma-separated list of container names.
+func parseContainerNames(s string) map[string]struct{} {
+	var names map[string]struct{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if names == nil {
+			names = map[string]struct{}{}
+		}
+		names[name] = struct{}{}
+	}
+	return names
 }
 
-// This function add Dapr environment variables to all the containers in any Dapr enabled pod.
+// This function add Dapr environment variables to the containers selected by the filter in any Dapr enabled pod.
 // The containers can be injected or user defined.
-func addDaprEnvVarsToContainers(containers []corev1.Container) []PatchOperation {
+func addDaprEnvVarsToContainers(containers []corev1.Container, filter containerFilter) []PatchOperation {
 	portEnv := []corev1.EnvVar{
 		{
 			Name:  userContainerDaprHTTPPortName,
@@ -264,6 +379,9 @@ func addDaprEnvVarsToContainers(containers []corev1.Container) []PatchOperation
 	}
 	envPatchOps := make([]PatchOperation, 0, len(containers))
 	for i, container := range containers {
+		if !filter.includes(container.Name) {
+			continue
+		}
 		path := fmt.Sprintf("%s/%d/env", containersPath, i)
 		patchOps := getEnvPatchOperations(container.Env, portEnv, path)
 		envPatchOps = append(envPatchOps, patchOps...)
@@ -305,19 +423,22 @@ LoopEnv:
 	return patchOps
 }
 
-// This function add Dapr unix domain socket volume to all the containers in any Dapr enabled pod.
-func addSocketVolumeToContainers(containers []corev1.Container, socketVolumeMount *corev1.VolumeMount) []PatchOperation {
+// This function add Dapr unix domain socket volume to the containers selected by the filter in any Dapr enabled pod.
+func addSocketVolumeToContainers(containers []corev1.Container, socketVolumeMount *corev1.VolumeMount, filter containerFilter) []PatchOperation {
 	if socketVolumeMount == nil {
 		return []PatchOperation{}
 	}
 
-	return addVolumeToContainers(containers, *socketVolumeMount)
+	return addVolumeToContainers(containers, *socketVolumeMount, filter)
 }
 
-func addVolumeToContainers(containers []corev1.Container, addMounts corev1.VolumeMount) []PatchOperation {
+func addVolumeToContainers(containers []corev1.Container, addMounts corev1.VolumeMount, filter containerFilter) []PatchOperation {
 	volumeMount := []corev1.VolumeMount{addMounts}
 	volumeMountPatchOps := make([]PatchOperation, 0, len(containers))
 	for i, container := range containers {
+		if !filter.includes(container.Name) {
+			continue
+		}
 		path := fmt.Sprintf("%s/%d/volumeMounts", containersPath, i)
 		patchOps := getVolumeMountPatchOperations(container.VolumeMounts, volumeMount, path)
 		volumeMountPatchOps = append(volumeMountPatchOps, patchOps...)
@@ -375,6 +496,48 @@ func getTrustAnchorsAndCertChain(kubeClient kubernetes.Interface, namespace stri
 	return string(rootCert), string(certChain), string(certKey)
 }
 
+// getTrustBundleVolume returns the volume and the volume mount for the trust bundle ConfigMap published by Sentry, if it exists in the namespace.
+func getTrustBundleVolume(kubeClient kubernetes.Interface, namespace string) (*corev1.Volume, *corev1.VolumeMount) {
+	_, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), certs.TrustBundleConfigMapName, metaV1.GetOptions{})
+	if err != nil {
+		log.Debugf("trust bundle ConfigMap not found in namespace %s, trust anchors will be embedded in the environment: %s", namespace, err)
+		return nil, nil
+	}
+
+	volume := &corev1.Volume{
+		Name: trustBundleVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: certs.TrustBundleConfigMapName,
+				},
+			},
+		},
+	}
+	mount := &corev1.VolumeMount{
+		Name:      trustBundleVolumeName,
+		MountPath: trustBundleMountPath,
+		ReadOnly:  true,
+	}
+	return volume, mount
+}
+
+// getVolumePatchOperation returns the patch operation to add a volume to a pod with the given volumes.
+func getVolumePatchOperation(volumes []corev1.Volume, volume corev1.Volume) PatchOperation {
+	if len(volumes) == 0 {
+		return PatchOperation{
+			Op:    "add",
+			Path:  volumesPath,
+			Value: []corev1.Volume{volume},
+		}
+	}
+	return PatchOperation{
+		Op:    "add",
+		Path:  volumesPath + "/-",
+		Value: volume,
+	}
+}
+
 func mTLSEnabled(daprClient scheme.Interface) bool {
 	resp, err := daprClient.ConfigurationV1alpha1().Configurations(metaV1.NamespaceAll).List(metaV1.ListOptions{})
 	if err != nil {
@@ -419,8 +582,16 @@ func getAppPort(annotations map[string]string) (int32, error) {
 	return getInt32Annotation(annotations, daprAppPortKey)
 }
 
+// getConfig returns the configuration of the sidecar, or the comma separated list of configurations merged by the sidecar.
 func getConfig(annotations map[string]string) string {
-	return getStringAnnotation(annotations, daprConfigKey)
+	names := strings.Split(getStringAnnotation(annotations, daprConfigKey), ",")
+	configs := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			configs = append(configs, name)
+		}
+	}
+	return strings.Join(configs, ",")
 }
 
 func getProtocol(annotations map[string]string) string {
@@ -443,6 +614,10 @@ func existPlacementAddressesAnnotation(annotations map[string]string) bool {
 	return existAnnotation(annotations, daprPlacementAddressesKey)
 }
 
+func getGRPCProbes(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprGRPCProbesKey, false)
+}
+
 func getEnableDebug(annotations map[string]string) bool {
 	return getBoolAnnotationOrDefault(annotations, daprEnableDebugKey, defaultSidecarDebug)
 }
@@ -467,10 +642,27 @@ func profilingEnabled(annotations map[string]string) bool {
 	return getBoolAnnotationOrDefault(annotations, daprEnableProfilingKey, false)
 }
 
+func getProfilePort(annotations map[string]string) int {
+	return int(getInt32AnnotationOrDefault(annotations, daprProfilePortKey, defaultProfilePort))
+}
+
 func appSSLEnabled(annotations map[string]string) bool {
 	return getBoolAnnotationOrDefault(annotations, daprAppSSLKey, defaultAppSSL)
 }
 
+func appHTTP2DetectionEnabled(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprAppHTTP2Detection, false)
+}
+
+func appUnixDomainSocketEnabled(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprAppUnixDomainSocket, false)
+}
+
+// getAppUnixDomainSocket returns the path of the socket the app listens on, in the unix domain socket directory shared with the app.
+func getAppUnixDomainSocket(socketVolumeMount *corev1.VolumeMount, appID string) string {
+	return path.Join(socketVolumeMount.MountPath, fmt.Sprintf("dapr-%s-app.socket", appID))
+}
+
 func getAPITokenSecret(annotations map[string]string) string {
 	return getStringAnnotationOrDefault(annotations, daprAPITokenSecret, "")
 }
@@ -495,6 +687,60 @@ func getGracefulShutdownSeconds(annotations map[string]string) (int32, error) {
 	return getInt32Annotation(annotations, daprGracefulShutdownSeconds)
 }
 
+func getAppDrainSeconds(annotations map[string]string) (int32, error) {
+	return getInt32Annotation(annotations, daprAppDrainSeconds)
+}
+
+func getAdjustTerminationGracePeriod(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprAdjustTerminationGracePeriod, false)
+}
+
+// getTerminationGracePeriodPatchOperations checks that the termination grace period of the pod covers the graceful
+// shutdown of the sidecar plus the drain time of the app. When it doesn't, the grace period is raised if the pod
+// opted in with the dapr.io/adjust-termination-grace-period annotation, otherwise a warning is returned.
+func getTerminationGracePeriodPatchOperations(pod corev1.Pod, annotations map[string]string) ([]PatchOperation, []string, error) {
+	appDrainSeconds, err := getAppDrainSeconds(annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+	if appDrainSeconds < 0 {
+		if existAnnotation(annotations, daprAppDrainSeconds) {
+			return nil, nil, errors.Errorf("%s must not be negative: %d", daprAppDrainSeconds, appDrainSeconds)
+		}
+		appDrainSeconds = 0
+	}
+
+	gracefulShutdownSeconds, _ := getGracefulShutdownSeconds(annotations)
+	if gracefulShutdownSeconds < 0 {
+		gracefulShutdownSeconds = defaultGracefulShutdownSeconds
+	}
+
+	required := int64(gracefulShutdownSeconds) + int64(appDrainSeconds)
+	gracePeriod := int64(defaultTerminationGracePeriod)
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	if gracePeriod >= required {
+		return nil, nil, nil
+	}
+
+	if getAdjustTerminationGracePeriod(annotations) {
+		return []PatchOperation{
+			{
+				Op:    "add",
+				Path:  terminationGracePeriodPath,
+				Value: required,
+			},
+		}, nil, nil
+	}
+
+	warning := fmt.Sprintf("the pod's terminationGracePeriodSeconds (%d) is shorter than the %ds needed by the Dapr sidecar "+
+		"to shut down gracefully (%s=%d plus %s=%d); set %s to \"true\" to raise it",
+		gracePeriod, required, daprGracefulShutdownSeconds, gracefulShutdownSeconds, daprAppDrainSeconds, appDrainSeconds,
+		daprAdjustTerminationGracePeriod)
+	return nil, []string{warning}, nil
+}
+
 func getUnixDomainSocketPath(annotations map[string]string) string {
 	return getStringAnnotationOrDefault(annotations, daprUnixDomainSocketPath, "")
 }
@@ -531,6 +777,49 @@ func getAppHealthThreshold(annotations map[string]string) int32 {
 	return getInt32AnnotationOrDefault(annotations, daprAppHealthThreshold, defaultAppHealthThreshold)
 }
 
+func getAppStartupProbeMethod(annotations map[string]string) string {
+	return getStringAnnotation(annotations, daprAppStartupProbeMethod)
+}
+
+func getAppStartupProbeInterval(annotations map[string]string) int32 {
+	return getInt32AnnotationOrDefault(annotations, daprAppStartupProbeInterval, defaultAppStartupProbeInterval)
+}
+
+func getAppStartupProbeTimeout(annotations map[string]string) int32 {
+	return getInt32AnnotationOrDefault(annotations, daprAppStartupProbeTimeout, defaultAppStartupProbeTimeout)
+}
+
+func getTraceAttributes(annotations map[string]string) string {
+	return getStringAnnotation(annotations, daprTraceAttributes)
+}
+
+func getMetricsAllowedCIDRs(annotations map[string]string) string {
+	return getStringAnnotation(annotations, daprMetricsAllowedCIDRs)
+}
+
+func getPublicAllowedCIDRs(annotations map[string]string) string {
+	return getStringAnnotation(annotations, daprPublicAllowedCIDRs)
+}
+
+func getCertDNSNames(annotations map[string]string) string {
+	return getStringAnnotation(annotations, daprCertDNSNames)
+}
+
+// getTokenSecretEnvVar returns the environment variable set to the token of the Kubernetes secret.
+func getTokenSecretEnvVar(name string, secret string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				Key: "token",
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secret,
+				},
+			},
+		},
+	}
+}
+
 func getBoolAnnotationOrDefault(annotations map[string]string, key string, defaultValue bool) bool {
 	enabled, ok := annotations[key]
 	if !ok {
@@ -539,6 +828,34 @@ func getBoolAnnotationOrDefault(annotations map[string]string, key string, defau
 	return utils.IsTruthy(enabled)
 }
 
+// internalGRPCFlags are the daprd flags of the internal gRPC connections set by the annotations.
+var internalGRPCFlags = []struct {
+	annotation string
+	flag       string
+}{
+	{daprInternalGRPCMaxConnectionAge, "--dapr-internal-grpc-max-connection-age"},
+	{daprInternalGRPCKeepaliveTime, "--dapr-internal-grpc-keepalive-time"},
+	{daprInternalGRPCKeepaliveTimeout, "--dapr-internal-grpc-keepalive-timeout"},
+	{daprInternalGRPCConnsPerTarget, "--dapr-internal-grpc-connections-per-target"},
+	{daprInternalGRPCMaxStreams, "--dapr-internal-grpc-max-concurrent-streams"},
+	{daprInternalGRPCIdleTimeout, "--dapr-internal-grpc-idle-timeout"},
+}
+
+func getInternalGRPCArgs(annotations map[string]string) []string {
+	var args []string
+	for _, f := range internalGRPCFlags {
+		value, err := getInt32Annotation(annotations, f.annotation)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		if value >= 0 {
+			args = append(args, f.flag, strconv.Itoa(int(value)))
+		}
+	}
+	return args
+}
+
 func getStringAnnotationOrDefault(annotations map[string]string, key, defaultValue string) string {
 	if val, ok := annotations[key]; ok && val != "" {
 		return val
@@ -588,6 +905,14 @@ func getProbeHTTPHandler(port int32, pathElements ...string) corev1.ProbeHandler
 	}
 }
 
+func getProbeGRPCHandler(port int32) corev1.ProbeHandler {
+	return corev1.ProbeHandler{
+		GRPC: &corev1.GRPCAction{
+			Port: port,
+		},
+	}
+}
+
 func formatProbePath(elements ...string) string {
 	pathStr := path.Join(elements...)
 	if !strings.HasPrefix(pathStr, "/") {
@@ -605,6 +930,47 @@ func appendQuantityToResourceList(quantity string, resourceName corev1.ResourceN
 	return &resourceList, nil
 }
 
+// applySidecarProfile returns the pod annotations completed with the settings of the sidecar profile selected
+// with the dapr.io/sidecar-profile annotation, and the profile. The annotations set on the pod take precedence.
+func applySidecarProfile(annotations map[string]string, profiles map[string]SidecarProfile) (map[string]string, SidecarProfile, error) {
+	name, ok := annotations[daprSidecarProfileKey]
+	if !ok || name == "" {
+		return annotations, SidecarProfile{}, nil
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, SidecarProfile{}, errors.Errorf("sidecar profile %s not found", name)
+	}
+
+	res := map[string]string{}
+	for key, value := range map[string]string{
+		daprCPULimitKey:          profile.CPULimit,
+		daprMemoryLimitKey:       profile.MemoryLimit,
+		daprCPURequestKey:        profile.CPURequest,
+		daprMemoryRequestKey:     profile.MemoryRequest,
+		daprAppMaxConcurrencyKey: profile.AppMaxConcurrency,
+		daprMaxRequestBodySize:   profile.HTTPMaxRequestSize,
+		daprReadBufferSize:       profile.HTTPReadBufferSize,
+	} {
+		if value != "" {
+			res[key] = value
+		}
+	}
+	for key, value := range annotations {
+		res[key] = value
+	}
+	return res, profile, nil
+}
+
+func containsEnvVar(envs []corev1.EnvVar, name string) bool {
+	for _, env := range envs {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func getResourceRequirements(annotations map[string]string) (*corev1.ResourceRequirements, error) {
 	r := corev1.ResourceRequirements{
 		Limits:   corev1.ResourceList{},
@@ -696,7 +1062,12 @@ func getSidecarContainer(cfg sidecarContainerConfig) (*corev1.Container, error)
 
 	pullPolicy := getPullPolicy(cfg.imagePullPolicy)
 
-	httpHandler := getProbeHTTPHandler(sidecarPublicPort, apiVersionV1, sidecarHealthzPath)
+	// The gRPC probes require Kubernetes 1.24+
+	probeHandler := getProbeHTTPHandler(sidecarPublicPort, apiVersionV1, sidecarHealthzPath)
+	grpcProbes := getGRPCProbes(cfg.annotations)
+	if grpcProbes {
+		probeHandler = getProbeGRPCHandler(sidecarPublicGRPCPort)
+	}
 
 	allowPrivilegeEscalation := false
 
@@ -775,6 +1146,49 @@ func getSidecarContainer(cfg sidecarContainerConfig) (*corev1.Container, error)
 		)
 	}
 
+	if method := getAppStartupProbeMethod(cfg.annotations); method != "" {
+		args = append(args,
+			"--app-startup-probe-method", method,
+			"--app-startup-probe-interval", strconv.Itoa(int(getAppStartupProbeInterval(cfg.annotations))),
+			"--app-startup-probe-timeout", strconv.Itoa(int(getAppStartupProbeTimeout(cfg.annotations))),
+		)
+	}
+
+	if grpcProbes {
+		args = append(args, "--dapr-public-grpc-port", strconv.Itoa(sidecarPublicGRPCPort))
+	}
+
+	if traceAttributes := getTraceAttributes(cfg.annotations); traceAttributes != "" {
+		args = append(args, "--trace-attributes", traceAttributes)
+	}
+
+	if cidrs := getMetricsAllowedCIDRs(cfg.annotations); cidrs != "" {
+		args = append(args, "--metrics-allowed-cidrs", cidrs)
+	}
+
+	if cidrs := getPublicAllowedCIDRs(cfg.annotations); cidrs != "" {
+		args = append(args, "--public-allowed-cidrs", cidrs)
+	}
+
+	if caFile := getStringAnnotation(cfg.annotations, daprPlacementTLSCAFile); caFile != "" {
+		args = append(args, "--placement-tls-ca-file", caFile)
+	}
+
+	if serverName := getStringAnnotation(cfg.annotations, daprPlacementTLSServerName); serverName != "" {
+		args = append(args, "--placement-tls-server-name", serverName)
+	}
+
+	args = append(args, getInternalGRPCArgs(cfg.annotations)...)
+
+	if profilingEnabled(cfg.annotations) {
+		profilePort := getProfilePort(cfg.annotations)
+		ports = append(ports, corev1.ContainerPort{
+			Name:          sidecarProfilePortName,
+			ContainerPort: int32(profilePort),
+		})
+		args = append(args, "--enable-profiling", "--profile-port", strconv.Itoa(profilePort))
+	}
+
 	debugEnabled := getEnableDebug(cfg.annotations)
 	debugPort := getDebugPort(cfg.annotations)
 	if debugEnabled {
@@ -825,14 +1239,14 @@ func getSidecarContainer(cfg sidecarContainerConfig) (*corev1.Container, error)
 			},
 		},
 		ReadinessProbe: &corev1.Probe{
-			ProbeHandler:        httpHandler,
+			ProbeHandler:        probeHandler,
 			InitialDelaySeconds: getInt32AnnotationOrDefault(cfg.annotations, daprReadinessProbeDelayKey, defaultHealthzProbeDelaySeconds),
 			TimeoutSeconds:      getInt32AnnotationOrDefault(cfg.annotations, daprReadinessProbeTimeoutKey, defaultHealthzProbeTimeoutSeconds),
 			PeriodSeconds:       getInt32AnnotationOrDefault(cfg.annotations, daprReadinessProbePeriodKey, defaultHealthzProbePeriodSeconds),
 			FailureThreshold:    getInt32AnnotationOrDefault(cfg.annotations, daprReadinessProbeThresholdKey, defaultHealthzProbeThreshold),
 		},
 		LivenessProbe: &corev1.Probe{
-			ProbeHandler:        httpHandler,
+			ProbeHandler:        probeHandler,
 			InitialDelaySeconds: getInt32AnnotationOrDefault(cfg.annotations, daprLivenessProbeDelayKey, defaultHealthzProbeDelaySeconds),
 			TimeoutSeconds:      getInt32AnnotationOrDefault(cfg.annotations, daprLivenessProbeTimeoutKey, defaultHealthzProbeTimeoutSeconds),
 			PeriodSeconds:       getInt32AnnotationOrDefault(cfg.annotations, daprLivenessProbePeriodKey, defaultHealthzProbePeriodSeconds),
@@ -854,6 +1268,13 @@ func getSidecarContainer(cfg sidecarContainerConfig) (*corev1.Container, error)
 
 	c.Env = append(c.Env, utils.ParseEnvString(cfg.annotations[daprEnvKey])...)
 
+	if cfg.goMemLimit != "" && !containsEnvVar(c.Env, "GOMEMLIMIT") {
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  "GOMEMLIMIT",
+			Value: cfg.goMemLimit,
+		})
+	}
+
 	// This is a special case that requires administrator privileges in Windows containers
 	// to install the certificates to the root store. If this environment variable is set,
 	// the container security context should be set to run as administrator.
@@ -883,14 +1304,21 @@ func getSidecarContainer(cfg sidecarContainerConfig) (*corev1.Container, error)
 		c.Args = append(c.Args, "--log-as-json")
 	}
 
-	if profilingEnabled(cfg.annotations) {
-		c.Args = append(c.Args, "--enable-profiling")
+	if cfg.trustBundleVolumeMount != nil {
+		// Read the trust anchors from the ConfigMap published by Sentry, which is kept in sync on rotation
+		c.VolumeMounts = append(c.VolumeMounts, *cfg.trustBundleVolumeMount)
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  certs.TrustAnchorsFileEnvVar,
+			Value: path.Join(cfg.trustBundleVolumeMount.MountPath, certs.TrustBundleConfigMapKey),
+		})
+	} else {
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  certs.TrustAnchorsEnvVar,
+			Value: cfg.trustAnchors,
+		})
 	}
 
-	c.Env = append(c.Env, corev1.EnvVar{
-		Name:  certs.TrustAnchorsEnvVar,
-		Value: cfg.trustAnchors,
-	},
+	c.Env = append(c.Env,
 		corev1.EnvVar{
 			Name:  certs.CertChainEnvVar,
 			Value: cfg.certChain,
@@ -904,6 +1332,13 @@ func getSidecarContainer(cfg sidecarContainerConfig) (*corev1.Container, error)
 			Value: cfg.identity,
 		})
 
+	if dnsNames := getCertDNSNames(cfg.annotations); dnsNames != "" {
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  certs.CertDNSNamesEnvVar,
+			Value: dnsNames,
+		})
+	}
+
 	if cfg.mtlsEnabled {
 		c.Args = append(c.Args, "--enable-mtls")
 	}
@@ -912,6 +1347,14 @@ func getSidecarContainer(cfg sidecarContainerConfig) (*corev1.Container, error)
 		c.Args = append(c.Args, "--app-ssl")
 	}
 
+	if appHTTP2DetectionEnabled(cfg.annotations) {
+		c.Args = append(c.Args, "--app-http2-detection")
+	}
+
+	if appUnixDomainSocketEnabled(cfg.annotations) && cfg.socketVolumeMount != nil {
+		c.Args = append(c.Args, "--app-unix-domain-socket", getAppUnixDomainSocket(cfg.socketVolumeMount, cfg.appID))
+	}
+
 	secret := getAPITokenSecret(cfg.annotations)
 	if secret != "" {
 		c.Env = append(c.Env, corev1.EnvVar{
@@ -942,6 +1385,18 @@ func getSidecarContainer(cfg sidecarContainerConfig) (*corev1.Container, error)
 		})
 	}
 
+	if secret := getStringAnnotation(cfg.annotations, daprMetricsTokenSecret); secret != "" {
+		c.Env = append(c.Env, getTokenSecretEnvVar(metrics.MetricsTokenEnvVar, secret))
+	}
+
+	if secret := getStringAnnotation(cfg.annotations, daprPublicTokenSecret); secret != "" {
+		c.Env = append(c.Env, getTokenSecretEnvVar(auth.PublicAPITokenEnvVar, secret))
+	}
+
+	if secret := getStringAnnotation(cfg.annotations, daprPlacementTokenSecret); secret != "" {
+		c.Env = append(c.Env, getTokenSecretEnvVar(auth.PlacementTokenEnvVar, secret))
+	}
+
 	resources, err := getResourceRequirements(cfg.annotations)
 	if err != nil {
 		log.Warnf("couldn't set container resource requirements: %s. using defaults", err)
@@ -17,13 +17,17 @@ package injector
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
-
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
 )
 
 const (
@@ -658,6 +662,153 @@ func TestGetSideCarContainer(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("app startup probe", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		assert.NotContains(t, container.Args, "--app-startup-probe-method")
+
+		annotations := map[string]string{
+			daprAppStartupProbeMethod:  "/ready",
+			daprAppStartupProbeTimeout: "120",
+		}
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: annotations})
+		args := strings.Join(container.Args, " ")
+		assert.Contains(t, args, "--app-startup-probe-method /ready")
+		assert.Contains(t, args, "--app-startup-probe-interval 1")
+		assert.Contains(t, args, "--app-startup-probe-timeout 120")
+	})
+
+	t.Run("internal gRPC connections", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		assert.NotContains(t, strings.Join(container.Args, " "), "--dapr-internal-grpc-keepalive-time")
+
+		annotations := map[string]string{
+			daprInternalGRPCKeepaliveTime:  "60",
+			daprInternalGRPCConnsPerTarget: "4",
+			daprInternalGRPCIdleTimeout:    "soon",
+		}
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: annotations})
+		args := strings.Join(container.Args, " ")
+		assert.Contains(t, args, "--dapr-internal-grpc-keepalive-time 60")
+		assert.Contains(t, args, "--dapr-internal-grpc-connections-per-target 4")
+		assert.NotContains(t, args, "--dapr-internal-grpc-idle-timeout")
+		assert.NotContains(t, args, "--dapr-internal-grpc-max-connection-age")
+	})
+
+	t.Run("profiling", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		assert.NotContains(t, container.Args, "--enable-profiling")
+		for _, p := range container.Ports {
+			assert.NotEqual(t, sidecarProfilePortName, p.Name)
+		}
+
+		annotations := map[string]string{
+			daprEnableProfilingKey: "true",
+			daprProfilePortKey:     "7070",
+		}
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: annotations})
+		assert.Contains(t, strings.Join(container.Args, " "), "--enable-profiling --profile-port 7070")
+		assert.Contains(t, container.Ports, corev1.ContainerPort{Name: sidecarProfilePortName, ContainerPort: 7070})
+
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{daprEnableProfilingKey: "true"}})
+		assert.Contains(t, strings.Join(container.Args, " "), "--enable-profiling --profile-port 7777")
+	})
+
+	t.Run("gRPC probes", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		assert.NotNil(t, container.ReadinessProbe.HTTPGet)
+		assert.NotContains(t, container.Args, "--dapr-public-grpc-port")
+
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{daprGRPCProbesKey: "true"}})
+		assert.Contains(t, strings.Join(container.Args, " "), "--dapr-public-grpc-port 3502")
+		expectedHandler := corev1.ProbeHandler{GRPC: &corev1.GRPCAction{Port: sidecarPublicGRPCPort}}
+		assert.Equal(t, expectedHandler, container.ReadinessProbe.ProbeHandler)
+		assert.Equal(t, expectedHandler, container.LivenessProbe.ProbeHandler)
+	})
+
+	t.Run("trace attributes", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		assert.NotContains(t, container.Args, "--trace-attributes")
+
+		annotations := map[string]string{
+			daprTraceAttributes: "team=payments,environment=staging",
+		}
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: annotations})
+		assert.Contains(t, strings.Join(container.Args, " "), "--trace-attributes team=payments,environment=staging")
+	})
+
+	t.Run("certificate dns names", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		for _, env := range container.Env {
+			assert.NotEqual(t, certs.CertDNSNamesEnvVar, env.Name)
+		}
+
+		annotations := map[string]string{
+			daprCertDNSNames: "orders.example.com,orders.contoso.com",
+		}
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: annotations})
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: certs.CertDNSNamesEnvVar, Value: "orders.example.com,orders.contoso.com"})
+	})
+
+	t.Run("app unix domain socket", func(t *testing.T) {
+		annotations := map[string]string{daprAppUnixDomainSocket: "true"}
+		container, _ := getSidecarContainer(sidecarContainerConfig{appID: "app1", annotations: annotations})
+		// The socket is only used when the unix domain socket directory is shared with the app
+		assert.NotContains(t, container.Args, "--app-unix-domain-socket")
+
+		socketVolumeMount := &corev1.VolumeMount{Name: unixDomainSocketVolume, MountPath: "/tmp/dapr"}
+		container, _ = getSidecarContainer(sidecarContainerConfig{appID: "app1", annotations: annotations, socketVolumeMount: socketVolumeMount})
+		assert.Contains(t, strings.Join(container.Args, " "), "--app-unix-domain-socket /tmp/dapr/dapr-app1-app.socket")
+	})
+
+	t.Run("app http2 detection", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		assert.NotContains(t, container.Args, "--app-http2-detection")
+
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{daprAppHTTP2Detection: "true"}})
+		assert.Contains(t, container.Args, "--app-http2-detection")
+	})
+
+	t.Run("metrics and public port protection", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		assert.NotContains(t, container.Args, "--metrics-allowed-cidrs")
+		assert.NotContains(t, container.Args, "--public-allowed-cidrs")
+		for _, env := range container.Env {
+			assert.NotContains(t, []string{"DAPR_METRICS_TOKEN", "DAPR_PUBLIC_API_TOKEN"}, env.Name)
+		}
+
+		annotations := map[string]string{
+			daprMetricsAllowedCIDRs: "10.0.0.0/8",
+			daprMetricsTokenSecret:  "metrics-secret",
+			daprPublicAllowedCIDRs:  "10.1.0.0/16,10.2.0.1",
+			daprPublicTokenSecret:   "public-secret",
+		}
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: annotations})
+		args := strings.Join(container.Args, " ")
+		assert.Contains(t, args, "--metrics-allowed-cidrs 10.0.0.0/8")
+		assert.Contains(t, args, "--public-allowed-cidrs 10.1.0.0/16,10.2.0.1")
+		assert.Contains(t, container.Env, getTokenSecretEnvVar("DAPR_METRICS_TOKEN", "metrics-secret"))
+		assert.Contains(t, container.Env, getTokenSecretEnvVar("DAPR_PUBLIC_API_TOKEN", "public-secret"))
+	})
+
+	t.Run("placement TLS", func(t *testing.T) {
+		container, _ := getSidecarContainer(sidecarContainerConfig{annotations: map[string]string{}})
+		assert.NotContains(t, container.Args, "--placement-tls-ca-file")
+		assert.NotContains(t, container.Args, "--placement-tls-server-name")
+
+		annotations := map[string]string{
+			daprPlacementAddressesKey:  "placement.example.com:443",
+			daprPlacementTLSCAFile:     "/etc/placement/ca.pem",
+			daprPlacementTLSServerName: "placement.example.com",
+			daprPlacementTokenSecret:   "placement-secret",
+		}
+		container, _ = getSidecarContainer(sidecarContainerConfig{annotations: annotations})
+		args := strings.Join(container.Args, " ")
+		assert.Contains(t, args, "--placement-host-address placement.example.com:443")
+		assert.Contains(t, args, "--placement-tls-ca-file /etc/placement/ca.pem")
+		assert.Contains(t, args, "--placement-tls-server-name placement.example.com")
+		assert.Contains(t, container.Env, getTokenSecretEnvVar("DAPR_PLACEMENT_TOKEN", "placement-secret"))
+	})
 }
 
 //nolint:forbidigo
@@ -810,7 +961,7 @@ func TestAddDaprEnvVarsToContainers(t *testing.T) {
 	for _, tc := range testCases {
 		tc := tc
 		t.Run(tc.testName, func(t *testing.T) {
-			patchEnv := addDaprEnvVarsToContainers([]corev1.Container{tc.mockContainer})
+			patchEnv := addDaprEnvVarsToContainers([]corev1.Container{tc.mockContainer}, containerFilter{})
 			fmt.Println(tc.testName)
 			assert.Equal(t, tc.expOpsLen, len(patchEnv))
 			assert.Equal(t, tc.expOps, patchEnv)
@@ -939,13 +1090,74 @@ func TestAddSocketVolumeToContainers(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.testName, func(t *testing.T) {
-			patchEnv := addSocketVolumeToContainers([]corev1.Container{tc.mockContainer}, tc.socketMount)
+			patchEnv := addSocketVolumeToContainers([]corev1.Container{tc.mockContainer}, tc.socketMount, containerFilter{})
 			assert.Equal(t, tc.expOpsLen, len(patchEnv))
 			assert.Equal(t, tc.expOps, patchEnv)
 		})
 	}
 }
 
+func TestContainerFilter(t *testing.T) {
+	containers := []corev1.Container{
+		{Name: "app"},
+		{Name: "worker"},
+		{Name: "istio-proxy"},
+		{Name: "vault-agent"},
+	}
+	socketMount := &corev1.VolumeMount{Name: "dapr-unix-domain-socket", MountPath: "/tmp"}
+
+	testCases := []struct {
+		testName           string
+		annotations        map[string]string
+		excludedContainers string
+		expPaths           []string
+	}{
+		{
+			testName: "all the containers by default",
+			expPaths: []string{"/spec/containers/0", "/spec/containers/1", "/spec/containers/2", "/spec/containers/3"},
+		},
+		{
+			testName:    "injected containers",
+			annotations: map[string]string{daprInjectedContainers: "app, worker"},
+			expPaths:    []string{"/spec/containers/0", "/spec/containers/1"},
+		},
+		{
+			testName:    "excluded containers",
+			annotations: map[string]string{daprExcludedContainers: "istio-proxy,vault-agent"},
+			expPaths:    []string{"/spec/containers/0", "/spec/containers/1"},
+		},
+		{
+			testName:    "excluded containers take precedence over injected containers",
+			annotations: map[string]string{daprInjectedContainers: "app,worker", daprExcludedContainers: "worker"},
+			expPaths:    []string{"/spec/containers/0"},
+		},
+		{
+			testName:           "containers excluded in all the pods",
+			annotations:        map[string]string{daprExcludedContainers: "vault-agent"},
+			excludedContainers: "istio-proxy",
+			expPaths:           []string{"/spec/containers/0", "/spec/containers/1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			filter := getContainerFilter(tc.annotations, tc.excludedContainers)
+
+			envPaths := []string{}
+			for _, op := range addDaprEnvVarsToContainers(containers, filter) {
+				envPaths = append(envPaths, strings.TrimSuffix(op.Path, "/env"))
+			}
+			assert.Equal(t, tc.expPaths, envPaths)
+
+			volumePaths := []string{}
+			for _, op := range addSocketVolumeToContainers(containers, socketMount, filter) {
+				volumePaths = append(volumePaths, strings.TrimSuffix(op.Path, "/volumeMounts"))
+			}
+			assert.Equal(t, tc.expPaths, volumePaths)
+		})
+	}
+}
+
 func TestAppendUnixDomainSocketVolume(t *testing.T) {
 	testCases := []struct {
 		testName        string
@@ -1106,3 +1318,204 @@ func TestGetVolumeMounts(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTrustBundleVolume(t *testing.T) {
+	t.Run("ConfigMap not found", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		volume, mount := getTrustBundleVolume(kubeClient, "ns1")
+		assert.Nil(t, volume)
+		assert.Nil(t, mount)
+	})
+
+	t.Run("ConfigMap exists", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metaV1.ObjectMeta{Name: certs.TrustBundleConfigMapName, Namespace: "ns1"},
+		})
+		volume, mount := getTrustBundleVolume(kubeClient, "ns1")
+		if assert.NotNil(t, volume) && assert.NotNil(t, mount) {
+			assert.Equal(t, certs.TrustBundleConfigMapName, volume.ConfigMap.Name)
+			assert.Equal(t, volume.Name, mount.Name)
+			assert.True(t, mount.ReadOnly)
+		}
+	})
+}
+
+func TestSidecarContainerTrustBundle(t *testing.T) {
+	hasEnv := func(c *corev1.Container, name string) (string, bool) {
+		for _, e := range c.Env {
+			if e.Name == name {
+				return e.Value, true
+			}
+		}
+		return "", false
+	}
+
+	t.Run("trust anchors embedded in the environment", func(t *testing.T) {
+		container, err := getSidecarContainer(sidecarContainerConfig{
+			annotations:  map[string]string{},
+			trustAnchors: "root",
+		})
+		assert.NoError(t, err)
+		val, ok := hasEnv(container, certs.TrustAnchorsEnvVar)
+		assert.True(t, ok)
+		assert.Equal(t, "root", val)
+		_, ok = hasEnv(container, certs.TrustAnchorsFileEnvVar)
+		assert.False(t, ok)
+	})
+
+	t.Run("trust anchors from the trust bundle ConfigMap", func(t *testing.T) {
+		container, err := getSidecarContainer(sidecarContainerConfig{
+			annotations:  map[string]string{},
+			trustAnchors: "root",
+			trustBundleVolumeMount: &corev1.VolumeMount{
+				Name:      trustBundleVolumeName,
+				MountPath: trustBundleMountPath,
+				ReadOnly:  true,
+			},
+		})
+		assert.NoError(t, err)
+		_, ok := hasEnv(container, certs.TrustAnchorsEnvVar)
+		assert.False(t, ok)
+		val, ok := hasEnv(container, certs.TrustAnchorsFileEnvVar)
+		assert.True(t, ok)
+		assert.Equal(t, trustBundleMountPath+"/"+certs.TrustBundleConfigMapKey, val)
+		assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: trustBundleVolumeName, MountPath: trustBundleMountPath, ReadOnly: true})
+	})
+}
+
+func TestApplySidecarProfile(t *testing.T) {
+	profiles := map[string]SidecarProfile{
+		"small": {
+			CPULimit:           "500m",
+			MemoryLimit:        "256Mi",
+			GoMemLimit:         "200MiB",
+			AppMaxConcurrency:  "10",
+			HTTPReadBufferSize: "8",
+		},
+	}
+
+	t.Run("no profile", func(t *testing.T) {
+		annotations := map[string]string{daprCPULimitKey: "1"}
+		res, profile, err := applySidecarProfile(annotations, profiles)
+		assert.NoError(t, err)
+		assert.Equal(t, annotations, res)
+		assert.Equal(t, SidecarProfile{}, profile)
+	})
+
+	t.Run("profile settings completed by the annotations", func(t *testing.T) {
+		res, profile, err := applySidecarProfile(map[string]string{
+			daprSidecarProfileKey: "small",
+			daprCPULimitKey:       "1",
+		}, profiles)
+		assert.NoError(t, err)
+		assert.Equal(t, "200MiB", profile.GoMemLimit)
+		assert.Equal(t, "1", res[daprCPULimitKey])
+		assert.Equal(t, "256Mi", res[daprMemoryLimitKey])
+		assert.Equal(t, "10", res[daprAppMaxConcurrencyKey])
+		assert.Equal(t, "8", res[daprReadBufferSize])
+		assert.NotContains(t, res, daprCPURequestKey)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, _, err := applySidecarProfile(map[string]string{daprSidecarProfileKey: "huge"}, profiles)
+		assert.Error(t, err)
+	})
+}
+
+func TestSidecarContainerGoMemLimit(t *testing.T) {
+	getGoMemLimit := func(c *corev1.Container) []string {
+		var values []string
+		for _, e := range c.Env {
+			if e.Name == "GOMEMLIMIT" {
+				values = append(values, e.Value)
+			}
+		}
+		return values
+	}
+
+	t.Run("set by the profile", func(t *testing.T) {
+		container, err := getSidecarContainer(sidecarContainerConfig{
+			annotations: map[string]string{},
+			goMemLimit:  "200MiB",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"200MiB"}, getGoMemLimit(container))
+	})
+
+	t.Run("env annotation takes precedence", func(t *testing.T) {
+		container, err := getSidecarContainer(sidecarContainerConfig{
+			annotations: map[string]string{daprEnvKey: "GOMEMLIMIT=100MiB"},
+			goMemLimit:  "200MiB",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"100MiB"}, getGoMemLimit(container))
+	})
+}
+
+func TestGetVolumePatchOperation(t *testing.T) {
+	volume := corev1.Volume{Name: "myvolume"}
+
+	op := getVolumePatchOperation(nil, volume)
+	assert.Equal(t, volumesPath, op.Path)
+	assert.Equal(t, []corev1.Volume{volume}, op.Value)
+
+	op = getVolumePatchOperation([]corev1.Volume{{Name: "existing"}}, volume)
+	assert.Equal(t, volumesPath+"/-", op.Path)
+	assert.Equal(t, volume, op.Value)
+}
+
+func TestGetTerminationGracePeriodPatchOperations(t *testing.T) {
+	podWithGracePeriod := func(seconds int64) corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{TerminationGracePeriodSeconds: &seconds}}
+	}
+
+	t.Run("default grace period covers the default shutdown", func(t *testing.T) {
+		ops, warnings, err := getTerminationGracePeriodPatchOperations(corev1.Pod{}, map[string]string{})
+		assert.NoError(t, err)
+		assert.Empty(t, ops)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("grace period covers the shutdown and the drain time", func(t *testing.T) {
+		ops, warnings, err := getTerminationGracePeriodPatchOperations(podWithGracePeriod(60), map[string]string{
+			daprGracefulShutdownSeconds: "40",
+			daprAppDrainSeconds:         "20",
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, ops)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("warning when the grace period is too short", func(t *testing.T) {
+		ops, warnings, err := getTerminationGracePeriodPatchOperations(corev1.Pod{}, map[string]string{
+			daprGracefulShutdownSeconds: "25",
+			daprAppDrainSeconds:         "10",
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, ops)
+		assert.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "terminationGracePeriodSeconds (30)")
+		assert.Contains(t, warnings[0], "35s")
+	})
+
+	t.Run("grace period raised when opted in", func(t *testing.T) {
+		ops, warnings, err := getTerminationGracePeriodPatchOperations(podWithGracePeriod(10), map[string]string{
+			daprAppDrainSeconds:              "15",
+			daprAdjustTerminationGracePeriod: "true",
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.Equal(t, []PatchOperation{
+			{Op: "add", Path: terminationGracePeriodPath, Value: int64(20)},
+		}, ops)
+	})
+
+	t.Run("invalid drain time", func(t *testing.T) {
+		for _, val := range []string{"invalid", "-5"} {
+			_, _, err := getTerminationGracePeriodPatchOperations(corev1.Pod{}, map[string]string{
+				daprAppDrainSeconds: val,
+			})
+			assert.Error(t, err, val)
+		}
+	})
+}
@@ -5,6 +5,10 @@ const (
 	ErrNotFound             = "method %q is not found"
 	ErrMalformedRequest     = "failed deserializing HTTP body: %s"
 	ErrMalformedRequestData = "can't serialize request data field: %s"
+	ErrRequestBodyTooLarge  = "request body of %d bytes exceeds the maximum size of %d MB of the API"
+	ErrRequestTimeout       = "request was not handled within the timeout of %s of the API"
+	ErrRequestDecompression = "failed decompressing the request body: %s"
+	ErrContentEncoding      = "unsupported content encoding %s"
 
 	// State.
 	ErrStateStoresNotConfigured = "state store is not configured"
@@ -20,7 +24,10 @@ const (
 	ErrStateTransaction           = "error while executing state transaction: %s"
 
 	// Binding.
-	ErrInvokeOutputBinding = "error when invoke output binding %s: %s"
+	ErrInvokeOutputBinding       = "error when invoke output binding %s: %s"
+	ErrInputBindingsNotAvailable = "input bindings are not available"
+	ErrPauseInputBinding         = "error when pausing input binding %s: %s"
+	ErrResumeInputBinding        = "error when resuming input binding %s: %s"
 
 	// PubSub.
 	ErrPubsubNotConfigured      = "no pubsub is configured"
@@ -31,6 +38,9 @@ const (
 	ErrPubsubPublishMessage     = "error when publish to topic %s in pubsub %s: %s"
 	ErrPubsubForbidden          = "topic %s is not allowed for app id %s"
 	ErrPubsubCloudEventCreation = "cannot create cloudevent: %s"
+	ErrPubsubPauseDuration      = "invalid pause duration: %q"
+	ErrPubsubPauseSubscription  = "error when pausing subscription to topic %s in pubsub %s: %s"
+	ErrPubsubResumeSubscription = "error when resuming subscription to topic %s in pubsub %s: %s"
 
 	// AppChannel.
 	ErrChannelNotFound       = "app channel is not initialized"
@@ -44,6 +54,7 @@ const (
 	ErrActorRuntimeNotFound      = "the state store is not configured to use the actor runtime. Have you set the - name: actorStateStore value: \"true\" in your state store component file?"
 	ErrActorInstanceMissing      = "actor instance is missing"
 	ErrActorInvoke               = "error invoke actor method: %s"
+	ErrActorTypeInternal         = "actor type %s is reserved for the actors hosted by dapr"
 	ErrActorReminderCreate       = "error creating actor reminder: %s"
 	ErrActorReminderRename       = "error rename actor reminder: %s"
 	ErrActorReminderGet          = "error getting actor reminder: %s"
@@ -52,6 +63,8 @@ const (
 	ErrActorTimerDelete          = "error deleting actor timer: %s"
 	ErrActorStateGet             = "error getting actor state: %s"
 	ErrActorStateTransactionSave = "error saving actor transaction state: %s"
+	ErrActorPlacementSimulation  = "error simulating actor placement change: %s"
+	ErrActorsNotColocated        = "error saving multi-actor transaction state: %s"
 
 	// Secret.
 	ErrSecretStoreNotConfigured = "secret store is not configured"
@@ -67,7 +80,16 @@ const (
 	ErrDirectInvokeNotReady = "invoke API is not ready"
 
 	// Metadata.
-	ErrMetadataGet = "failed deserializing metadata: %s"
+	ErrMetadataGet          = "failed deserializing metadata: %s"
+	ErrMetadataAttributeSet = "failed setting metadata attribute %s: %s"
+
+	// Component tracing.
+	ErrComponentTracingNotFound        = "component %s not found"
+	ErrComponentTracingInvalidDuration = "invalid duration %q: must be a positive duration of at most %s"
+
+	// Runtime logging.
+	ErrLogLevelInvalid = "invalid log level: %s"
+	ErrLogLevelTTL     = "invalid ttl %q: must be a positive duration of at most %s"
 
 	// Healthz.
 	ErrHealthNotReady = "dapr is not ready"
@@ -78,6 +100,7 @@ const (
 	ErrConfigurationGet                 = "fail to get %s from Configuration store %s: %s"
 	ErrConfigurationSubscribe           = "fail to subscribe %s from Configuration store %s: %s"
 	ErrConfigurationUnsubscribe         = "fail to unsubscribe to configuration request %s: %s"
+	ErrConfigurationResyncInterval      = "invalid value for metadata resyncInterval: %s"
 
 	//	Lock
 	ErrLockStoresNotConfigured    = "lock store is not configured"
@@ -85,4 +108,31 @@ const (
 	ErrLockOwnerEmpty             = "LockOwner is empty in lock store %s"
 	ErrExpiryInSecondsNotPositive = "ExpiryInSeconds is not positive in lock store %s"
 	ErrLockStoreNotFound          = "lock store %s not found"
+
+	// Crypto
+	ErrCryptoProvidersNotConfigured = "crypto providers are not configured"
+	ErrCryptoProviderNotFound       = "crypto provider %s not found"
+	ErrCryptoMissingOptions         = "the first message of the stream must contain the options"
+	ErrCryptoEncrypt                = "failed to encrypt with crypto provider %s: %s"
+	ErrCryptoDecrypt                = "failed to decrypt with crypto provider %s: %s"
+	ErrCryptoSign                   = "failed to sign with crypto provider %s: %s"
+	ErrCryptoVerify                 = "failed to verify with crypto provider %s: %s"
+
+	// Workflow
+	ErrWorkflowEngineNotConfigured = "the workflow engine is not configured: actors must be enabled to use workflows"
+	ErrWorkflowNameMissing         = "workflow name is empty"
+	ErrWorkflowInstanceIDMissing   = "workflow instance id is empty"
+	ErrWorkflowEventNameMissing    = "workflow event name is empty"
+	ErrWorkflowNameMismatch        = "workflow instance %s is an instance of workflow %s"
+	ErrWorkflowStart               = "error starting workflow %s instance %s: %s"
+	ErrWorkflowGet                 = "error getting workflow instance %s: %s"
+	ErrWorkflowTerminate           = "error terminating workflow instance %s: %s"
+	ErrWorkflowRaiseEvent          = "error raising event %s on workflow instance %s: %s"
+	ErrWorkflowPause               = "error pausing workflow instance %s: %s"
+	ErrWorkflowResume              = "error resuming workflow instance %s: %s"
+	ErrWorkflowPurge               = "error purging workflow instance %s: %s"
+	ErrWorkflowTimerIDMissing      = "workflow timer id is empty"
+	ErrWorkflowTimerFireAtMissing  = "workflow timer fire time is empty"
+	ErrWorkflowCancelTimer         = "error canceling timer %s of workflow instance %s: %s"
+	ErrWorkflowRescheduleTimer     = "error rescheduling timer %s of workflow instance %s: %s"
 )
@@ -29,7 +29,9 @@ import (
 	nr "github.com/dapr/components-contrib/nameresolution"
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/acl"
 	"github.com/dapr/dapr/pkg/channel"
+	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"github.com/dapr/dapr/pkg/modes"
@@ -43,6 +45,12 @@ import (
 
 var log = logger.NewLogger("dapr.runtime.direct_messaging")
 
+// Placeholders of the addresses of the remote clusters.
+const (
+	clusterAppIDPlaceholder     = "{appID}"
+	clusterNamespacePlaceholder = "{namespace}"
+)
+
 // messageClientConnection is the function type to connect to the other
 // applications to send the message using service invocation.
 type messageClientConnection func(ctx context.Context, address, id string, namespace string, skipTLS, recreateIfExists, enableSSL bool, customOpts ...grpc.DialOption) (*grpc.ClientConn, func(), error)
@@ -67,11 +75,14 @@ type directMessaging struct {
 	readBufferSize      int
 	resiliency          resiliency.Provider
 	isResiliencyEnabled bool
+	outboundPolicy      *acl.OutboundPolicy
+	clusters            map[string]string
 }
 
 type remoteApp struct {
 	id        string
 	namespace string
+	cluster   string
 	address   string
 }
 
@@ -89,6 +100,8 @@ type NewDirectMessagingOpts struct {
 	ReadBufferSize      int
 	Resiliency          resiliency.Provider
 	IsResiliencyEnabled bool
+	OutboundPolicy      *acl.OutboundPolicy
+	Clusters            []config.ClusterSpec
 }
 
 // NewDirectMessaging returns a new direct messaging api.
@@ -109,9 +122,14 @@ func NewDirectMessaging(opts NewDirectMessagingOpts) DirectMessaging {
 		readBufferSize:      opts.ReadBufferSize,
 		resiliency:          opts.Resiliency,
 		isResiliencyEnabled: opts.IsResiliencyEnabled,
+		outboundPolicy:      opts.OutboundPolicy,
+		clusters:            make(map[string]string, len(opts.Clusters)),
 		hostAddress:         hAddr,
 		hostName:            hName,
 	}
+	for _, cluster := range opts.Clusters {
+		dm.clusters[cluster.Name] = cluster.Address
+	}
 
 	if dm.proxy != nil {
 		dm.proxy.SetRemoteAppFn(dm.getRemoteApp)
@@ -122,30 +140,43 @@ func NewDirectMessaging(opts NewDirectMessagingOpts) DirectMessaging {
 }
 
 // Invoke takes a message requests and invokes an app, either local or remote.
-func (d *directMessaging) Invoke(ctx context.Context, targetAppID string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+func (d *directMessaging) Invoke(ctx context.Context, targetAppID string, req *invokev1.InvokeMethodRequest) (resp *invokev1.InvokeMethodResponse, err error) {
 	app, err := d.getRemoteApp(targetAppID)
 	if err != nil {
 		return nil, err
 	}
 
-	if app.id == d.appID && app.namespace == d.namespace {
+	start := time.Now()
+	defer func() {
+		diag.DefaultAPILatencyMonitoring.ServiceInvocationLatency(ctx, targetAppID, err == nil, diag.ElapsedSince(start))
+	}()
+
+	if d.isLocalApp(app) {
 		return d.invokeLocal(ctx, req)
 	}
 	return d.invokeWithRetry(ctx, retry.DefaultLinearRetryCount, retry.DefaultLinearBackoffInterval, app, d.invokeRemote, req)
 }
 
-// requestAppIDAndNamespace takes an app id and returns the app id, namespace and error.
-func (d *directMessaging) requestAppIDAndNamespace(targetAppID string) (string, string, error) {
+// requestAppIDNamespaceAndCluster takes an app id and returns the app id, namespace, cluster and error.
+// The target is app-id, app-id.namespace or app-id.namespace.cluster; the cluster is empty for the local cluster.
+func (d *directMessaging) requestAppIDNamespaceAndCluster(targetAppID string) (string, string, string, error) {
 	items := strings.Split(targetAppID, ".")
-	if len(items) == 1 {
-		return targetAppID, d.namespace, nil
-	} else if len(items) == 2 {
-		return items[0], items[1], nil
-	} else {
-		return "", "", errors.Errorf("invalid app id %s", targetAppID)
+	switch len(items) {
+	case 1:
+		return targetAppID, d.namespace, "", nil
+	case 2:
+		return items[0], items[1], "", nil
+	case 3:
+		return items[0], items[1], items[2], nil
+	default:
+		return "", "", "", errors.Errorf("invalid app id %s", targetAppID)
 	}
 }
 
+func (d *directMessaging) isLocalApp(app remoteApp) bool {
+	return app.id == d.appID && app.namespace == d.namespace && app.cluster == ""
+}
+
 // invokeWithRetry will call a remote endpoint for the specified number of retries and will only retry in the case of transient failures
 // TODO: check why https://github.com/grpc-ecosystem/go-grpc-middleware/blob/master/retry/examples_test.go doesn't recover the connection when target
 // Server shuts down.
@@ -300,20 +331,43 @@ func (d *directMessaging) addForwardedHeadersToMetadata(req *invokev1.InvokeMeth
 }
 
 func (d *directMessaging) getRemoteApp(appID string) (remoteApp, error) {
-	id, namespace, err := d.requestAppIDAndNamespace(appID)
+	id, namespace, cluster, err := d.requestAppIDNamespaceAndCluster(appID)
 	if err != nil {
 		return remoteApp{}, err
 	}
+	app := remoteApp{namespace: namespace, id: id, cluster: cluster}
+
+	if namespace != d.namespace || cluster != "" {
+		allowed := d.outboundPolicy == nil || d.outboundPolicy.IsCallAllowed(id, namespace, cluster)
+		diag.DefaultMonitoring.CrossBoundaryRequestSent(namespace, cluster, allowed)
+		if !allowed {
+			return remoteApp{}, status.Errorf(codes.PermissionDenied, "outbound policy has denied access to appid: %s namespace: %s cluster: %s", id, namespace, cluster)
+		}
+	}
+
+	if cluster != "" {
+		// The apps of the remote clusters are reached through the address of their cluster
+		address, ok := d.clusters[cluster]
+		if !ok {
+			return remoteApp{}, errors.Errorf("cannot resolve app %s: unknown cluster %s", appID, cluster)
+		}
+		app.address = strings.NewReplacer(clusterAppIDPlaceholder, id, clusterNamespacePlaceholder, namespace).Replace(address)
+		return app, nil
+	}
+
+	if d.resolver == nil {
+		// The local app is invoked through the app channel and doesn't need to be resolved
+		if d.isLocalApp(app) {
+			return app, nil
+		}
+		return remoteApp{}, errors.Errorf("cannot resolve app %s: name resolution not initialized", appID)
+	}
 
 	request := nr.ResolveRequest{ID: id, Namespace: namespace, Port: d.grpcPort}
-	address, err := d.resolver.ResolveID(request)
+	app.address, err = d.resolver.ResolveID(request)
 	if err != nil {
 		return remoteApp{}, err
 	}
 
-	return remoteApp{
-		namespace: namespace,
-		id:        id,
-		address:   address,
-	}, nil
+	return app, nil
 }
@@ -17,8 +17,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/dapr/dapr/pkg/acl"
+	"github.com/dapr/dapr/pkg/config"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 )
 
@@ -93,10 +98,11 @@ func TestKubernetesNamespace(t *testing.T) {
 		appID := "app1"
 
 		dm := newDirectMessaging()
-		id, ns, err := dm.requestAppIDAndNamespace(appID)
+		id, ns, cluster, err := dm.requestAppIDNamespaceAndCluster(appID)
 
 		assert.NoError(t, err)
 		assert.Empty(t, ns)
+		assert.Empty(t, cluster)
 		assert.Equal(t, appID, id)
 	})
 
@@ -104,19 +110,114 @@ func TestKubernetesNamespace(t *testing.T) {
 		appID := "app1.ns1"
 
 		dm := newDirectMessaging()
-		id, ns, err := dm.requestAppIDAndNamespace(appID)
+		id, ns, cluster, err := dm.requestAppIDNamespaceAndCluster(appID)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "ns1", ns)
+		assert.Empty(t, cluster)
+		assert.Equal(t, "app1", id)
+	})
+
+	t.Run("with cluster", func(t *testing.T) {
+		appID := "app1.ns1.cluster1"
+
+		dm := newDirectMessaging()
+		id, ns, cluster, err := dm.requestAppIDNamespaceAndCluster(appID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ns1", ns)
+		assert.Equal(t, "cluster1", cluster)
 		assert.Equal(t, "app1", id)
 	})
 
 	t.Run("invalid namespace", func(t *testing.T) {
-		appID := "app1.ns1.ns2"
+		appID := "app1.ns1.cluster1.ns2"
 
 		dm := newDirectMessaging()
-		_, _, err := dm.requestAppIDAndNamespace(appID)
+		_, _, _, err := dm.requestAppIDNamespaceAndCluster(appID)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetRemoteAppWithoutResolver(t *testing.T) {
+	dm := newDirectMessaging()
+	dm.appID = "app1"
+
+	t.Run("local app", func(t *testing.T) {
+		app, err := dm.getRemoteApp("app1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "app1", app.id)
+		assert.Empty(t, app.address)
+	})
+
+	t.Run("remote app", func(t *testing.T) {
+		_, err := dm.getRemoteApp("app2")
 
 		assert.Error(t, err)
 	})
 }
+
+func TestGetRemoteAppAcrossBoundaries(t *testing.T) {
+	outboundPolicy, err := acl.ParseOutboundSpec(&config.OutboundSpec{
+		AllowedCallees: []config.OutboundCallee{
+			{Namespace: "ns2", AppID: "app2"},
+			{Namespace: "ns1", Cluster: "cluster1"},
+		},
+	}, "ns1")
+	require.NoError(t, err)
+
+	dm := NewDirectMessaging(NewDirectMessagingOpts{
+		AppID:          "app1",
+		Namespace:      "ns1",
+		OutboundPolicy: outboundPolicy,
+		Clusters: []config.ClusterSpec{
+			{Name: "cluster1", Address: "{appID}-dapr.{namespace}.svc.cluster1.local:50002"},
+		},
+	}).(*directMessaging)
+
+	t.Run("app of a remote cluster", func(t *testing.T) {
+		app, err := dm.getRemoteApp("app2.ns1.cluster1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "app2", app.id)
+		assert.Equal(t, "ns1", app.namespace)
+		assert.Equal(t, "cluster1", app.cluster)
+		assert.Equal(t, "app2-dapr.ns1.svc.cluster1.local:50002", app.address)
+	})
+
+	t.Run("same app id in a remote cluster", func(t *testing.T) {
+		app, err := dm.getRemoteApp("app1.ns1.cluster1")
+
+		require.NoError(t, err)
+		assert.False(t, dm.isLocalApp(app))
+	})
+
+	t.Run("unknown cluster", func(t *testing.T) {
+		policy, err := acl.ParseOutboundSpec(&config.OutboundSpec{
+			AllowedCallees: []config.OutboundCallee{{Namespace: "ns1", Cluster: "cluster2"}},
+		}, "ns1")
+		require.NoError(t, err)
+		dm := NewDirectMessaging(NewDirectMessagingOpts{AppID: "app1", Namespace: "ns1", OutboundPolicy: policy}).(*directMessaging)
+
+		_, err = dm.getRemoteApp("app2.ns1.cluster2")
+
+		assert.ErrorContains(t, err, "unknown cluster cluster2")
+	})
+
+	t.Run("denied by the outbound policy", func(t *testing.T) {
+		for _, target := range []string{"app3.ns2", "app2.ns2.cluster1", "app2.ns3"} {
+			_, err := dm.getRemoteApp(target)
+
+			assert.Equal(t, codes.PermissionDenied, status.Code(err), target)
+		}
+	})
+
+	t.Run("allowed by the outbound policy", func(t *testing.T) {
+		// The app is allowed, but it can't be resolved without name resolution
+		_, err := dm.getRemoteApp("app2.ns2")
+
+		assert.ErrorContains(t, err, "name resolution not initialized")
+	})
+}
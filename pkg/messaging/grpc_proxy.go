@@ -37,6 +37,9 @@ type Proxy interface {
 	Handler() grpc.StreamHandler
 	SetRemoteAppFn(func(string) (remoteApp, error))
 	SetTelemetryFn(func(context.Context) context.Context)
+	SetMetadataPolicy(spec config.GRPCProxyMetadataSpec)
+	SetMaxMetadataSize(size int)
+	SetCrossNamespacePolicy(policy *acl.CrossNamespacePolicy)
 }
 
 type proxy struct {
@@ -49,6 +52,9 @@ type proxy struct {
 	acl               *config.AccessControlList
 	sslEnabled        bool
 	resiliency        resiliency.Provider
+	metadataPolicy    *metadataPolicy
+	maxMetadataSize   int
+	crossNamespace    *acl.CrossNamespacePolicy
 }
 
 // NewProxy returns a new proxy.
@@ -77,7 +83,11 @@ func (p *proxy) intercept(ctx context.Context, fullName string) (context.Context
 		return ctx, nil, func() {}, errors.Errorf("failed to proxy request: required metadata %s not found", diagnostics.GRPCProxyAppIDKey)
 	}
 
-	outCtx := metadata.NewOutgoingContext(ctx, md.Copy())
+	if err := checkMetadataSize(md, p.maxMetadataSize); err != nil {
+		return ctx, nil, func() {}, status.Errorf(codes.ResourceExhausted, "failed to proxy request: %s", err)
+	}
+
+	outCtx := metadata.NewOutgoingContext(ctx, p.metadataPolicy.apply(md))
 	appID := v[0]
 
 	if p.remoteAppFn == nil {
@@ -91,6 +101,9 @@ func (p *proxy) intercept(ctx context.Context, fullName string) (context.Context
 
 	if isLocal {
 		// proxy locally to the app
+		if ok, authError := acl.ApplyCrossNamespacePolicy(ctx, p.appID, p.crossNamespace); !ok {
+			return ctx, nil, func() {}, status.Error(codes.PermissionDenied, authError)
+		}
 		if p.acl != nil {
 			ok, authError := acl.ApplyAccessControlPolicies(ctx, fullName, common.HTTPExtension_NONE, config.GRPCProtocol, p.acl) //nolint:nosnakecase
 			if !ok {
@@ -120,6 +133,16 @@ func (p *proxy) SetTelemetryFn(spanFn func(context.Context) context.Context) {
 	p.telemetryFn = spanFn
 }
 
+// SetMetadataPolicy sets the policy controlling which metadata keys are forwarded with proxied calls.
+func (p *proxy) SetMetadataPolicy(spec config.GRPCProxyMetadataSpec) {
+	p.metadataPolicy = newMetadataPolicy(spec)
+}
+
+// SetMaxMetadataSize sets the maximum size in bytes of the metadata of proxied calls. A size of 0 means no limit.
+func (p *proxy) SetMaxMetadataSize(size int) {
+	p.maxMetadataSize = size
+}
+
 // Expose the functionality to detect if apps are local or not.
 func (p *proxy) IsLocal(appID string) (bool, error) {
 	_, isLocal, err := p.isLocalInternal(appID)
@@ -135,5 +158,10 @@ func (p *proxy) isLocalInternal(appID string) (remoteApp, bool, error) {
 	if err != nil {
 		return remoteApp{}, false, err
 	}
-	return target, target.id == p.appID, nil
+	return target, target.id == p.appID && target.cluster == "", nil
+}
+
+// SetCrossNamespacePolicy sets the policy controlling which callers from other namespaces can call the local app.
+func (p *proxy) SetCrossNamespacePolicy(policy *acl.CrossNamespacePolicy) {
+	p.crossNamespace = policy
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/diagnostics"
+)
+
+// metadataPolicy filters and renames the metadata keys forwarded by the gRPC proxy.
+type metadataPolicy struct {
+	allow  map[string]struct{}
+	deny   map[string]struct{}
+	rename map[string]string
+}
+
+// newMetadataPolicy returns the policy for the given spec, or nil if the spec is empty.
+func newMetadataPolicy(spec config.GRPCProxyMetadataSpec) *metadataPolicy {
+	if len(spec.Allow) == 0 && len(spec.Deny) == 0 && len(spec.Rename) == 0 {
+		return nil
+	}
+
+	p := &metadataPolicy{
+		deny:   make(map[string]struct{}, len(spec.Deny)),
+		rename: make(map[string]string, len(spec.Rename)),
+	}
+	if len(spec.Allow) > 0 {
+		p.allow = make(map[string]struct{}, len(spec.Allow))
+		for _, k := range spec.Allow {
+			p.allow[strings.ToLower(k)] = struct{}{}
+		}
+	}
+	for _, k := range spec.Deny {
+		p.deny[strings.ToLower(k)] = struct{}{}
+	}
+	for _, r := range spec.Rename {
+		if r.From == "" || r.To == "" {
+			continue
+		}
+		p.rename[strings.ToLower(r.From)] = strings.ToLower(r.To)
+	}
+	return p
+}
+
+// apply returns a copy of md with the policy applied.
+// The key containing the target app ID is used for routing and is always forwarded.
+func (p *metadataPolicy) apply(md metadata.MD) metadata.MD {
+	if p == nil {
+		return md.Copy()
+	}
+
+	out := make(metadata.MD, len(md))
+	for k, v := range md {
+		if k != diagnostics.GRPCProxyAppIDKey {
+			if _, ok := p.deny[k]; ok {
+				continue
+			}
+			if p.allow != nil {
+				if _, ok := p.allow[k]; !ok {
+					continue
+				}
+			}
+			if to, ok := p.rename[k]; ok {
+				k = to
+			}
+		}
+		out[k] = append(out[k], v...)
+	}
+	return out
+}
+
+// metadataSize returns the size of md as counted by HTTP/2 for the header list: the length of each key and value,
+// plus an overhead of 32 bytes for each entry.
+func metadataSize(md metadata.MD) int {
+	size := 0
+	for k, vs := range md {
+		for _, v := range vs {
+			size += len(k) + len(v) + 32
+		}
+	}
+	return size
+}
+
+// checkMetadataSize returns an error if md is larger than maxSize bytes. A maxSize of 0 means no limit.
+func checkMetadataSize(md metadata.MD, maxSize int) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	if size := metadataSize(md); size > maxSize {
+		return fmt.Errorf("metadata size %d exceeds the limit of %d bytes", size, maxSize)
+	}
+	return nil
+}
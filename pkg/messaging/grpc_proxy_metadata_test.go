@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/diagnostics"
+)
+
+func TestMetadataPolicy(t *testing.T) {
+	md := metadata.Pairs(
+		diagnostics.GRPCProxyAppIDKey, "b",
+		"authorization", "secret",
+		"x-tenant", "t1",
+		"x-legacy-id", "42",
+	)
+
+	t.Run("empty policy forwards everything", func(t *testing.T) {
+		p := newMetadataPolicy(config.GRPCProxyMetadataSpec{})
+		assert.Nil(t, p)
+
+		out := p.apply(md)
+		assert.Equal(t, md, out)
+
+		// The result is a copy
+		out.Set("x-tenant", "t2")
+		assert.Equal(t, []string{"t1"}, md.Get("x-tenant"))
+	})
+
+	t.Run("denied keys are stripped", func(t *testing.T) {
+		p := newMetadataPolicy(config.GRPCProxyMetadataSpec{
+			Deny: []string{"Authorization"},
+		})
+
+		out := p.apply(md)
+		assert.Empty(t, out.Get("authorization"))
+		assert.Equal(t, []string{"t1"}, out.Get("x-tenant"))
+		assert.Equal(t, []string{"42"}, out.Get("x-legacy-id"))
+	})
+
+	t.Run("only allowed keys are forwarded", func(t *testing.T) {
+		p := newMetadataPolicy(config.GRPCProxyMetadataSpec{
+			Allow: []string{"x-tenant"},
+		})
+
+		out := p.apply(md)
+		assert.Len(t, out, 2)
+		assert.Equal(t, []string{"t1"}, out.Get("x-tenant"))
+		assert.Equal(t, []string{"b"}, out.Get(diagnostics.GRPCProxyAppIDKey))
+	})
+
+	t.Run("app ID key cannot be denied", func(t *testing.T) {
+		p := newMetadataPolicy(config.GRPCProxyMetadataSpec{
+			Deny:   []string{diagnostics.GRPCProxyAppIDKey},
+			Rename: []config.GRPCProxyMetadataRename{{From: diagnostics.GRPCProxyAppIDKey, To: "x-app"}},
+		})
+
+		out := p.apply(md)
+		assert.Equal(t, []string{"b"}, out.Get(diagnostics.GRPCProxyAppIDKey))
+		assert.Empty(t, out.Get("x-app"))
+	})
+
+	t.Run("keys are renamed", func(t *testing.T) {
+		p := newMetadataPolicy(config.GRPCProxyMetadataSpec{
+			Allow:  []string{"x-legacy-id"},
+			Rename: []config.GRPCProxyMetadataRename{{From: "x-legacy-id", To: "X-Request-ID"}},
+		})
+
+		out := p.apply(md)
+		assert.Empty(t, out.Get("x-legacy-id"))
+		assert.Equal(t, []string{"42"}, out.Get("x-request-id"))
+	})
+}
@@ -15,13 +15,18 @@ package messaging
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
+	"github.com/dapr/dapr/pkg/acl"
 	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/diagnostics"
 	"github.com/dapr/dapr/pkg/resiliency"
@@ -186,6 +191,64 @@ func TestIntercept(t *testing.T) {
 		assert.Equal(t, "b", md["a"][0])
 	})
 
+	t.Run("metadata policy applied", func(t *testing.T) {
+		p := NewProxy(connectionFn, "a", "a:123", 50005, nil, false, resiliency.New(nil))
+		p.SetTelemetryFn(func(ctx context.Context) context.Context {
+			return ctx
+		})
+		p.SetMetadataPolicy(config.GRPCProxyMetadataSpec{
+			Deny: []string{"authorization"},
+		})
+
+		p.SetRemoteAppFn(func(s string) (remoteApp, error) {
+			return remoteApp{
+				id: "b",
+			}, nil
+		})
+
+		ctx := metadata.NewIncomingContext(context.TODO(), metadata.MD{
+			diagnostics.GRPCProxyAppIDKey: []string{"b"},
+			"authorization":               []string{"secret"},
+			"x-tenant":                    []string{"t1"},
+		})
+		proxy := p.(*proxy)
+		ctx, _, teardown, err := proxy.intercept(ctx, "/test")
+		defer teardown()
+
+		assert.NoError(t, err)
+
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.Empty(t, md.Get("authorization"))
+		assert.Equal(t, []string{"t1"}, md.Get("x-tenant"))
+		assert.Equal(t, []string{"b"}, md.Get(diagnostics.GRPCProxyAppIDKey))
+	})
+
+	t.Run("metadata size limit applied", func(t *testing.T) {
+		p := NewProxy(connectionFn, "a", "a:123", 50005, nil, false, resiliency.New(nil))
+		p.SetTelemetryFn(func(ctx context.Context) context.Context {
+			return ctx
+		})
+		p.SetMaxMetadataSize(100)
+		p.SetRemoteAppFn(func(s string) (remoteApp, error) {
+			return remoteApp{
+				id: "b",
+			}, nil
+		})
+		proxy := p.(*proxy)
+
+		ctx := metadata.NewIncomingContext(context.TODO(), metadata.MD{diagnostics.GRPCProxyAppIDKey: []string{"b"}})
+		_, _, teardown, err := proxy.intercept(ctx, "/test")
+		defer teardown()
+		assert.NoError(t, err)
+
+		ctx = metadata.NewIncomingContext(context.TODO(), metadata.MD{
+			diagnostics.GRPCProxyAppIDKey: []string{"b"},
+			"x-large":                     []string{strings.Repeat("a", 100)},
+		})
+		_, _, _, err = proxy.intercept(ctx, "/test")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
 	t.Run("access policies applied", func(t *testing.T) {
 		acl := &config.AccessControlList{
 			DefaultAction: "deny",
@@ -214,6 +277,58 @@ func TestIntercept(t *testing.T) {
 		assert.Nil(t, conn)
 	})
 
+	t.Run("cross-namespace policy applied", func(t *testing.T) {
+		policy, err := acl.ParseCrossNamespaceSpec(&config.CrossNamespaceSpec{}, "default", "public")
+		require.NoError(t, err)
+
+		p := NewProxy(connectionFn, "a", "a:123", 50005, nil, false, resiliency.New(nil))
+		p.SetRemoteAppFn(func(s string) (remoteApp, error) {
+			return remoteApp{
+				id:      "a",
+				address: "a:123",
+			}, nil
+		})
+		p.SetTelemetryFn(func(ctx context.Context) context.Context {
+			return ctx
+		})
+		p.SetCrossNamespacePolicy(policy)
+
+		ctx := metadata.NewIncomingContext(context.TODO(), metadata.MD{diagnostics.GRPCProxyAppIDKey: []string{"a"}})
+		proxy := p.(*proxy)
+
+		_, conn, teardown, err := proxy.intercept(ctx, "/test")
+		defer teardown()
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Nil(t, conn)
+	})
+
+	t.Run("cross-namespace policy not applied to remote calls", func(t *testing.T) {
+		policy, err := acl.ParseCrossNamespaceSpec(&config.CrossNamespaceSpec{}, "default", "public")
+		require.NoError(t, err)
+
+		p := NewProxy(connectionFn, "a", "a:123", 50005, nil, false, resiliency.New(nil))
+		p.SetRemoteAppFn(func(s string) (remoteApp, error) {
+			return remoteApp{
+				id:      "b",
+				address: "b:123",
+			}, nil
+		})
+		p.SetTelemetryFn(func(ctx context.Context) context.Context {
+			return ctx
+		})
+		p.SetCrossNamespacePolicy(policy)
+
+		ctx := metadata.NewIncomingContext(context.TODO(), metadata.MD{diagnostics.GRPCProxyAppIDKey: []string{"b"}})
+		proxy := p.(*proxy)
+
+		_, conn, teardown, err := proxy.intercept(ctx, "/test")
+		defer teardown()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, conn)
+	})
+
 	t.Run("SetRemoteAppFn never called", func(t *testing.T) {
 		p := NewProxy(connectionFn, "a", "a:123", 50005, nil, false, resiliency.New(nil))
 		p.SetTelemetryFn(func(ctx context.Context) context.Context {
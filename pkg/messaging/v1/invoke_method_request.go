@@ -80,13 +80,19 @@ func (imr *InvokeMethodRequest) WithMetadata(md map[string][]string) *InvokeMeth
 	return imr
 }
 
-// WithFastHTTPHeaders sets fasthttp request headers.
+// WithFastHTTPHeaders sets fasthttp request headers and trailers.
 func (imr *InvokeMethodRequest) WithFastHTTPHeaders(header *fasthttp.RequestHeader) *InvokeMethodRequest {
-	md := map[string][]string{}
-	header.VisitAll(func(key []byte, value []byte) {
-		md[string(key)] = []string{string(value)}
-	})
+	md, trailers := fastHTTPHeadersAndTrailers(header)
 	imr.r.Metadata = MetadataToInternalMetadata(md)
+	if len(trailers) > 0 {
+		imr.r.Trailers = MetadataToInternalMetadata(trailers)
+	}
+	return imr
+}
+
+// WithTrailers sets the HTTP request trailers.
+func (imr *InvokeMethodRequest) WithTrailers(trailers map[string][]string) *InvokeMethodRequest {
+	imr.r.Trailers = MetadataToInternalMetadata(trailers)
 	return imr
 }
 
@@ -153,6 +159,11 @@ func (imr *InvokeMethodRequest) Metadata() DaprInternalMetadata {
 	return imr.r.GetMetadata()
 }
 
+// Trailers gets the HTTP request trailers of InvokeMethodRequest.
+func (imr *InvokeMethodRequest) Trailers() DaprInternalMetadata {
+	return imr.r.GetTrailers()
+}
+
 // Proto returns InternalInvokeRequest Proto object.
 func (imr *InvokeMethodRequest) Proto() *internalv1pb.InternalInvokeRequest {
 	return imr.r
@@ -102,6 +102,21 @@ func TestMetadata(t *testing.T) {
 		assert.Equal(t, "Value2", mheader["Header2"].GetValues()[0])
 		assert.Equal(t, "Value3", mheader["Header3"].GetValues()[0])
 	})
+
+	t.Run("HTTP trailers", func(t *testing.T) {
+		req := fasthttp.AcquireRequest()
+		req.Header.Set("Header1", "Value1")
+		req.Header.SetTrailer("Trailer1")
+		req.Header.Set("Trailer1", "Value2")
+
+		re := NewInvokeMethodRequest("test_method")
+		re.WithFastHTTPHeaders(&req.Header)
+
+		assert.Equal(t, "Value1", re.Metadata()["Header1"].GetValues()[0])
+		assert.NotContains(t, re.Metadata(), "Trailer1")
+		assert.NotContains(t, re.Metadata(), "Trailer")
+		assert.Equal(t, "Value2", re.Trailers()["Trailer1"].GetValues()[0])
+	})
 }
 
 func TestData(t *testing.T) {
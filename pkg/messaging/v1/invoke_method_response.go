@@ -76,16 +76,21 @@ func (imr *InvokeMethodResponse) WithHeaders(headers metadata.MD) *InvokeMethodR
 	return imr
 }
 
-// WithFastHTTPHeaders populates fasthttp response header to gRPC header metadata.
+// WithFastHTTPHeaders populates fasthttp response header and trailers to gRPC header and trailer metadata.
 func (imr *InvokeMethodResponse) WithFastHTTPHeaders(header *fasthttp.ResponseHeader) *InvokeMethodResponse {
-	md := DaprInternalMetadata{}
-	header.VisitAll(func(key []byte, value []byte) {
-		md[string(key)] = &internalv1pb.ListStringValue{
-			Values: []string{string(value)},
+	headers, trailers := fastHTTPHeadersAndTrailers(header)
+	toInternalMetadata := func(values map[string][]string) DaprInternalMetadata {
+		md := DaprInternalMetadata{}
+		for k, v := range values {
+			md[k] = &internalv1pb.ListStringValue{Values: v}
 		}
-	})
-	if len(md) > 0 {
-		imr.r.Headers = md
+		return md
+	}
+	if len(headers) > 0 {
+		imr.r.Headers = toInternalMetadata(headers)
+	}
+	if len(trailers) > 0 {
+		imr.r.Trailers = toInternalMetadata(trailers)
 	}
 	return imr
 }
@@ -96,6 +101,15 @@ func (imr *InvokeMethodResponse) WithTrailers(trailer metadata.MD) *InvokeMethod
 	return imr
 }
 
+// WithInterimResponse adds an informational (1xx) response received before the final response.
+func (imr *InvokeMethodResponse) WithInterimResponse(code int32, headers map[string][]string) *InvokeMethodResponse {
+	imr.r.InterimResponses = append(imr.r.InterimResponses, &internalv1pb.InterimResponse{
+		Code:    code,
+		Headers: MetadataToInternalMetadata(headers),
+	})
+	return imr
+}
+
 // Status gets Response status.
 func (imr *InvokeMethodResponse) Status() *internalv1pb.Status {
 	return imr.r.GetStatus()
@@ -123,6 +137,11 @@ func (imr *InvokeMethodResponse) Trailers() DaprInternalMetadata {
 	return imr.r.Trailers
 }
 
+// InterimResponses gets the informational (1xx) responses received before the final response.
+func (imr *InvokeMethodResponse) InterimResponses() []*internalv1pb.InterimResponse {
+	return imr.r.GetInterimResponses()
+}
+
 // Message returns message field in InvokeMethodResponse.
 func (imr *InvokeMethodResponse) Message() *commonv1pb.InvokeResponse {
 	return imr.r.Message
@@ -156,6 +156,21 @@ func TestResponseHeader(t *testing.T) {
 		assert.Equal(t, "Value2", mheader["Header2"].GetValues()[0])
 		assert.Equal(t, "Value3", mheader["Header3"].GetValues()[0])
 	})
+
+	t.Run("HTTP trailers", func(t *testing.T) {
+		resp := fasthttp.AcquireResponse()
+		resp.Header.Set("Header1", "Value1")
+		resp.Header.SetTrailer("Trailer1")
+		resp.Header.Set("Trailer1", "Value2")
+
+		re := NewInvokeMethodResponse(0, "OK", nil)
+		re.WithFastHTTPHeaders(&resp.Header)
+
+		assert.Equal(t, "Value1", re.Headers()["Header1"].GetValues()[0])
+		assert.NotContains(t, re.Headers(), "Trailer1")
+		assert.NotContains(t, re.Headers(), "Trailer")
+		assert.Equal(t, "Value2", re.Trailers()["Trailer1"].GetValues()[0])
+	})
 }
 
 func TestResponseTrailer(t *testing.T) {
@@ -173,6 +188,16 @@ func TestResponseTrailer(t *testing.T) {
 	assert.Equal(t, "val4", mheader["test2"].GetValues()[1])
 }
 
+func TestResponseInterimResponses(t *testing.T) {
+	resp := NewInvokeMethodResponse(200, "OK", nil)
+	resp.WithInterimResponse(103, map[string][]string{"Link": {"</style.css>; rel=preload"}})
+
+	interim := resp.InterimResponses()
+	assert.Len(t, interim, 1)
+	assert.Equal(t, int32(103), interim[0].GetCode())
+	assert.Equal(t, "</style.css>; rel=preload", interim[0].GetHeaders()["Link"].GetValues()[0])
+}
+
 func TestIsHTTPResponse(t *testing.T) {
 	t.Run("gRPC response status", func(t *testing.T) {
 		grpcResp := NewInvokeMethodResponse(int32(codes.OK), "OK", nil)
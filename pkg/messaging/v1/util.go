@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/valyala/fasthttp"
 	"go.opentelemetry.io/otel/trace"
 	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	spb "google.golang.org/genproto/googleapis/rpc/status"
@@ -240,6 +241,60 @@ func InternalMetadataToHTTPHeader(ctx context.Context, internalMD DaprInternalMe
 	}
 }
 
+// fastHTTPHeader is implemented by the fasthttp request and response headers.
+type fastHTTPHeader interface {
+	VisitAll(f func(key, value []byte))
+	VisitAllTrailer(f func(value []byte))
+}
+
+// fastHTTPHeadersAndTrailers returns the headers and the trailers declared by the Trailer header.
+// The Trailer header itself is not returned, since it is rebuilt from the trailers.
+func fastHTTPHeadersAndTrailers(header fastHTTPHeader) (headers map[string][]string, trailers map[string][]string) {
+	declared := map[string]struct{}{}
+	header.VisitAllTrailer(func(value []byte) {
+		declared[strings.ToLower(string(value))] = struct{}{}
+	})
+
+	headers = map[string][]string{}
+	trailers = map[string][]string{}
+	header.VisitAll(func(key []byte, value []byte) {
+		k := string(key)
+		if _, ok := declared[strings.ToLower(k)]; ok {
+			trailers[k] = []string{string(value)}
+			return
+		}
+		if len(declared) > 0 && strings.EqualFold(k, fasthttp.HeaderTrailer) {
+			return
+		}
+		headers[k] = []string{string(value)}
+	})
+	return headers, trailers
+}
+
+// fastHTTPTrailerSetter is implemented by the fasthttp request and response headers.
+type fastHTTPTrailerSetter interface {
+	AddTrailer(trailer string) error
+	Set(key, value string)
+}
+
+// InternalMetadataToHTTPTrailers declares the trailers in the HTTP header and sets their values.
+// Trailers forbidden by RFC 7230, such as Content-Length, are dropped.
+// It returns false when no trailer is set; otherwise the body must be sent chunked.
+func InternalMetadataToHTTPTrailers(internalMD DaprInternalMetadata, header fastHTTPTrailerSetter) bool {
+	set := false
+	for k, listVal := range internalMD {
+		if len(listVal.Values) == 0 || strings.HasSuffix(strings.ToLower(k), gRPCBinaryMetadataSuffix) {
+			continue
+		}
+		if err := header.AddTrailer(k); err != nil {
+			continue
+		}
+		header.Set(k, listVal.Values[0])
+		set = true
+	}
+	return set
+}
+
 // HTTPStatusFromCode converts a gRPC error code into the corresponding HTTP response status.
 // https://github.com/grpc-ecosystem/grpc-gateway/blob/master/runtime/errors.go#L15
 // See: https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
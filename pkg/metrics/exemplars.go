@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"strings"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricproducer"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	exemplarTraceIDLabel = "trace_id"
+	exemplarSpanIDLabel  = "span_id"
+)
+
+// exemplarGatherer adds the exemplars of the OpenCensus histograms to the buckets of the histograms gathered from the
+// Prometheus registry, since the OpenCensus exporter drops them.
+// The exemplars are the span contexts recorded with the samples, exposed with the trace_id and span_id labels.
+type exemplarGatherer struct {
+	namespace string
+	gatherer  prom.Gatherer
+}
+
+func newExemplarGatherer(namespace string, gatherer prom.Gatherer) prom.Gatherer {
+	return &exemplarGatherer{
+		namespace: namespace,
+		gatherer:  gatherer,
+	}
+}
+
+// Gather implements prom.Gatherer.
+func (g *exemplarGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+	if len(families) == 0 {
+		return families, err
+	}
+	exemplars := g.readExemplars()
+	if len(exemplars) == 0 {
+		return families, err
+	}
+
+	for _, family := range families {
+		if family.GetType() != dto.MetricType_HISTOGRAM {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string, len(m.GetLabel()))
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			bucketExemplars, ok := exemplars[seriesKey(family.GetName(), labels)]
+			if !ok {
+				continue
+			}
+			for _, b := range m.GetHistogram().GetBucket() {
+				if e, ok := bucketExemplars[b.GetUpperBound()]; ok {
+					b.Exemplar = e
+				}
+			}
+		}
+	}
+	return families, err
+}
+
+// readExemplars returns the exemplars of the OpenCensus histograms, by series and by upper bound of their bucket.
+// The exemplars of the overflow buckets are dropped, as the +Inf buckets aren't gathered.
+func (g *exemplarGatherer) readExemplars() map[string]map[float64]*dto.Exemplar {
+	exemplars := map[string]map[float64]*dto.Exemplar{}
+	for _, producer := range metricproducer.GlobalManager().GetAll() {
+		for _, metric := range producer.Read() {
+			if metric.Descriptor.Type != metricdata.TypeCumulativeDistribution {
+				continue
+			}
+			name := sanitize(metric.Descriptor.Name)
+			if g.namespace != "" {
+				name = g.namespace + "_" + name
+			}
+
+			for _, ts := range metric.TimeSeries {
+				labels := make(map[string]string, len(metric.Descriptor.LabelKeys))
+				for i, k := range metric.Descriptor.LabelKeys {
+					if i < len(ts.LabelValues) {
+						labels[sanitize(k.Key)] = ts.LabelValues[i].Value
+					}
+				}
+				key := seriesKey(name, labels)
+
+				for _, point := range ts.Points {
+					dist, ok := point.Value.(*metricdata.Distribution)
+					if !ok || dist.BucketOptions == nil {
+						continue
+					}
+					for i, bound := range dist.BucketOptions.Bounds {
+						if i >= len(dist.Buckets) {
+							break
+						}
+						e := toPromExemplar(dist.Buckets[i].Exemplar)
+						if e == nil {
+							continue
+						}
+						if exemplars[key] == nil {
+							exemplars[key] = map[float64]*dto.Exemplar{}
+						}
+						exemplars[key][bound] = e
+					}
+				}
+			}
+		}
+	}
+	return exemplars
+}
+
+// toPromExemplar converts an exemplar with a span context, or returns nil.
+func toPromExemplar(e *metricdata.Exemplar) *dto.Exemplar {
+	if e == nil {
+		return nil
+	}
+	sc, ok := e.Attachments[metricdata.AttachmentKeySpanContext].(trace.SpanContext)
+	if !ok || !sc.IsValid() {
+		return nil
+	}
+	return &dto.Exemplar{
+		Label: []*dto.LabelPair{
+			{Name: proto.String(exemplarTraceIDLabel), Value: proto.String(sc.TraceID().String())},
+			{Name: proto.String(exemplarSpanIDLabel), Value: proto.String(sc.SpanID().String())},
+		},
+		Value:     proto.Float64(e.Value),
+		Timestamp: timestamppb.New(e.Timestamp),
+	}
+}
+
+// seriesKey identifies a series by the name of its metric and its labels.
+func seriesKey(name string, labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// sanitize converts the names of the OpenCensus views and tags to Prometheus names, like the OpenCensus exporter.
+func sanitize(s string) string {
+	if s == "" {
+		return s
+	}
+	if len(s) > 100 {
+		s = s[:100]
+	}
+	b := []byte(s)
+	for i, c := range b {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			b[i] = '_'
+		}
+	}
+	s = string(b)
+	if s[0] >= '0' && s[0] <= '9' {
+		s = "_" + s
+	}
+	if s[0] == '_' {
+		s = "key" + s
+	}
+	return s
+}
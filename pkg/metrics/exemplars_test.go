@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ocprom "contrib.go.opencensus.io/exporter/prometheus"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExemplarGatherer(t *testing.T) {
+	measure := stats.Float64("test/exemplar/latencies", "The latency.", stats.UnitMilliseconds)
+	key := tag.MustNewKey("component")
+	v := &view.View{
+		Name:        measure.Name(),
+		Measure:     measure,
+		TagKeys:     []tag.Key{key},
+		Aggregation: view.Distribution(10, 100),
+	}
+	require.NoError(t, view.Register(v))
+	defer view.Unregister(v)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	require.NoError(t, stats.RecordWithOptions(context.Background(),
+		stats.WithTags(tag.Upsert(key, "mystore")),
+		stats.WithMeasurements(measure.M(42)),
+		stats.WithAttachments(metricdata.Attachments{metricdata.AttachmentKeySpanContext: sc})))
+	require.NoError(t, stats.RecordWithOptions(context.Background(),
+		stats.WithTags(tag.Upsert(key, "mystore")),
+		stats.WithMeasurements(measure.M(5))))
+
+	registry := prom.NewRegistry()
+	_, err := ocprom.NewExporter(ocprom.Options{Namespace: "dapr", Registry: registry})
+	require.NoError(t, err)
+	gatherer := newExemplarGatherer("dapr", registry)
+
+	t.Run("exemplars are added to the buckets", func(t *testing.T) {
+		families, err := gatherer.Gather()
+		require.NoError(t, err)
+
+		var found bool
+		for _, family := range families {
+			if family.GetName() != "dapr_test_exemplar_latencies" {
+				continue
+			}
+			found = true
+			require.Len(t, family.GetMetric(), 1)
+			buckets := family.GetMetric()[0].GetHistogram().GetBucket()
+			require.Len(t, buckets, 2)
+			assert.Nil(t, buckets[0].GetExemplar())
+			e := buckets[1].GetExemplar()
+			require.NotNil(t, e)
+			assert.Equal(t, 42.0, e.GetValue())
+			require.Len(t, e.GetLabel(), 2)
+			assert.Equal(t, sc.TraceID().String(), e.GetLabel()[0].GetValue())
+			assert.Equal(t, sc.SpanID().String(), e.GetLabel()[1].GetValue())
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("exemplars are served in the OpenMetrics format", func(t *testing.T) {
+		server := httptest.NewServer(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `# {trace_id="`+sc.TraceID().String()+`",span_id="`+sc.SpanID().String()+`"} 42`)
+	})
+}
+
+func TestSanitize(t *testing.T) {
+	assert.Equal(t, "component_state_latencies", sanitize("component/state/latencies"))
+	assert.Equal(t, "app_id", sanitize("app_id"))
+	assert.Equal(t, "key_1abc", sanitize("1abc"))
+	assert.Equal(t, "key_abc", sanitize("_abc"))
+}
@@ -3,12 +3,16 @@ package metrics
 import (
 	"fmt"
 	"net/http"
+	"os"
 
 	ocprom "contrib.go.opencensus.io/exporter/prometheus"
 	"github.com/pkg/errors"
 	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/dapr/kit/logger"
+
+	"github.com/dapr/dapr/utils"
 )
 
 const (
@@ -87,10 +91,24 @@ func (m *promMetricsExporter) startMetricServer() error {
 		return errors.New("exporter was not initialized")
 	}
 
+	allowlist, err := utils.ParseIPAllowlist(m.options.AllowedCIDRs)
+	if err != nil {
+		return errors.Errorf("invalid metrics-allowed-cidrs: %v", err)
+	}
+	var handler http.Handler = m.ocExporter
+	if m.options.Exemplars {
+		handler = promhttp.HandlerFor(newExemplarGatherer(m.namespace, prom.DefaultGatherer), promhttp.HandlerOpts{EnableOpenMetrics: true})
+		m.exporter.logger.Info("metrics are served with the trace exemplars to the OpenMetrics scrapers")
+	}
+	handler = protectHandler(handler, allowlist, os.Getenv(MetricsTokenEnvVar))
+	if len(allowlist) > 0 {
+		m.exporter.logger.Infof("metrics can be scraped from %s", m.options.AllowedCIDRs)
+	}
+
 	m.exporter.logger.Infof("metrics server started on %s%s", addr, defaultMetricsPath)
 	go func() {
 		mux := http.NewServeMux()
-		mux.Handle(defaultMetricsPath, m.ocExporter)
+		mux.Handle(defaultMetricsPath, handler)
 
 		if err := http.ListenAndServe(addr, mux); err != nil {
 			m.exporter.logger.Fatalf("failed to start metrics server: %v", err)
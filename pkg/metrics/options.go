@@ -28,6 +28,13 @@ type Options struct {
 	MetricsEnabled bool
 
 	Port string
+
+	// AllowedCIDRs is the comma separated list of CIDRs the metrics can be scraped from, any address when empty.
+	AllowedCIDRs string
+
+	// Exemplars serves the metrics in the OpenMetrics format to the scrapers requesting it, with the trace exemplars
+	// of the histograms. The names of the counters get the _total suffix in this format.
+	Exemplars bool
 }
 
 func defaultMetricOptions() *Options {
@@ -63,6 +70,16 @@ func (o *Options) AttachCmdFlags(
 		"enable-metrics",
 		defaultMetricsEnabled,
 		"Enable prometheus metric")
+	stringVar(
+		&o.AllowedCIDRs,
+		"metrics-allowed-cidrs",
+		"",
+		"Comma separated list of CIDRs the metrics can be scraped from; any address if empty")
+	boolVar(
+		&o.Exemplars,
+		"metrics-exemplars",
+		false,
+		"Serve the metrics in the OpenMetrics format with the trace exemplars of the histograms, to the scrapers requesting it")
 }
 
 // AttachCmdFlag attaches single metrics option to command flags.
@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/dapr/dapr/utils"
+)
+
+// MetricsTokenEnvVar is the environment variable of the bearer token the scrapers of the metrics must send.
+const MetricsTokenEnvVar = "DAPR_METRICS_TOKEN" /* #nosec */
+
+// protectHandler rejects the scrapes from the addresses outside of the allowlist, and the ones without
+// the token when it's not empty, so that the metrics of a process can't be scraped by any pod of the cluster.
+func protectHandler(next http.Handler, allowlist utils.IPAllowlist, token string) http.Handler {
+	if len(allowlist) == 0 && token == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowlist.Allows(r.RemoteAddr) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if token != "" {
+			authorization := r.Header.Get("Authorization")
+			if !strings.HasPrefix(authorization, "Bearer ") ||
+				subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authorization, "Bearer ")), []byte(token)) != 1 {
+				http.Error(w, "invalid metrics token", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/utils"
+)
+
+func TestProtectHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	scrape := func(h http.Handler, remoteAddr, authorization string) int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	allowlist, err := utils.ParseIPAllowlist("10.0.0.0/8")
+	require.NoError(t, err)
+
+	t.Run("unprotected", func(t *testing.T) {
+		h := protectHandler(next, nil, "")
+		assert.Equal(t, http.StatusOK, scrape(h, "203.0.113.1:1234", ""))
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		h := protectHandler(next, allowlist, "")
+		assert.Equal(t, http.StatusOK, scrape(h, "10.1.2.3:1234", ""))
+		assert.Equal(t, http.StatusForbidden, scrape(h, "203.0.113.1:1234", ""))
+	})
+
+	t.Run("token", func(t *testing.T) {
+		h := protectHandler(next, allowlist, "secret")
+		assert.Equal(t, http.StatusOK, scrape(h, "10.1.2.3:1234", "Bearer secret"))
+		assert.Equal(t, http.StatusUnauthorized, scrape(h, "10.1.2.3:1234", "Bearer other"))
+		assert.Equal(t, http.StatusUnauthorized, scrape(h, "10.1.2.3:1234", "secret"))
+		assert.Equal(t, http.StatusForbidden, scrape(h, "203.0.113.1:1234", "Bearer secret"))
+	})
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routeralias
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fasthttp/router"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/kit/logger"
+)
+
+const routesKey = "routes"
+
+// paramRegexp matches the parameters of a route pattern, such as "{id}", "{id?}", "{id:[0-9]+}" or "{path:*}".
+var paramRegexp = regexp.MustCompile(`\{([^}:?]+)\??(?::[^}]*)?\}`)
+
+// Middleware is a HTTP middleware rewriting the path of the inbound requests matching an alias.
+//
+// The "routes" metadata is a JSON object mapping the route patterns to their targets, for example:
+//
+//	{"/v1.0/mall/{id}": "/v1.0/invoke/mall/method/orders?id="}
+//
+// The parameters of a pattern replace the same parameters in the target path, for example "{id}", and are
+// otherwise set as query parameters. The query parameters of the inbound request are preserved.
+type Middleware struct {
+	logger logger.Logger
+}
+
+// NewMiddleware returns a new router alias middleware.
+func NewMiddleware(logger logger.Logger) *Middleware {
+	return &Middleware{logger: logger}
+}
+
+// GetHandler returns the HTTP handler provided by the middleware.
+func (m *Middleware) GetHandler(metadata middleware.Metadata) (func(h fasthttp.RequestHandler) fasthttp.RequestHandler, error) {
+	routes, err := getRoutes(metadata.Properties)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse router alias metadata")
+	}
+
+	r := router.New()
+	for pattern, target := range routes {
+		if err = addRoute(r, pattern, m.rewrite(pattern, target)); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			if rewrite, _ := r.Lookup(string(ctx.Method()), string(ctx.Path()), ctx); rewrite != nil {
+				rewrite(ctx)
+			}
+			h(ctx)
+		}
+	}, nil
+}
+
+func getRoutes(properties map[string]string) (map[string]string, error) {
+	val, ok := properties[routesKey]
+	if !ok || val == "" {
+		return nil, errors.Errorf("missing %s", routesKey)
+	}
+	routes := map[string]string{}
+	if err := json.Unmarshal([]byte(val), &routes); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", routesKey)
+	}
+	return routes, nil
+}
+
+// addRoute adds the route to the router, which panics on invalid or conflicting patterns.
+func addRoute(r *router.Router, pattern string, handler fasthttp.RequestHandler) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("invalid route pattern %q: %v", pattern, rec)
+		}
+	}()
+	r.ANY(pattern, handler)
+	return nil
+}
+
+// rewrite returns the handler rewriting the request URI of the requests matching pattern to target.
+func (m *Middleware) rewrite(pattern, target string) fasthttp.RequestHandler {
+	var params []string
+	for _, match := range paramRegexp.FindAllStringSubmatch(pattern, -1) {
+		params = append(params, match[1])
+	}
+	targetPath, targetQuery, _ := strings.Cut(target, "?")
+
+	return func(ctx *fasthttp.RequestCtx) {
+		path := targetPath
+		args := fasthttp.AcquireArgs()
+		defer fasthttp.ReleaseArgs(args)
+		args.Parse(targetQuery)
+
+		for _, param := range params {
+			value, _ := ctx.UserValue(param).(string)
+			// The router set the parameters as user values, which mustn't leak to the next handlers.
+			ctx.RemoveUserValue(param)
+
+			placeholder := "{" + param + "}"
+			if strings.Contains(path, placeholder) {
+				path = strings.ReplaceAll(path, placeholder, value)
+			} else {
+				args.Set(param, value)
+			}
+		}
+		ctx.QueryArgs().VisitAll(func(key, value []byte) {
+			args.AddBytesKV(key, value)
+		})
+
+		uri := path
+		if args.Len() > 0 {
+			uri += "?" + args.String()
+		}
+		m.logger.Debugf("rewriting request URI %s to %s", ctx.RequestURI(), uri)
+		ctx.Request.SetRequestURI(uri)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routeralias
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/kit/logger"
+)
+
+func getHandler(routes string) (func(h fasthttp.RequestHandler) fasthttp.RequestHandler, error) {
+	metadata := middleware.Metadata{}
+	metadata.Properties = map[string]string{routesKey: routes}
+	return NewMiddleware(logger.NewLogger("routeralias.test")).GetHandler(metadata)
+}
+
+func serve(handler func(h fasthttp.RequestHandler) fasthttp.RequestHandler, uri string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI(uri)
+	handler(func(ctx *fasthttp.RequestCtx) {})(ctx)
+	return ctx
+}
+
+func TestRouterAlias(t *testing.T) {
+	handler, err := getHandler(`{
+		"/v1.0/mall/{id}": "/v1.0/invoke/mall/method/orders?id=",
+		"/v1.0/mall/{id}/items/{item}": "/v1.0/invoke/mall/method/orders/{id}/items",
+		"/v1.0/hello": "/v1.0/invoke/hello/method/hello?lang=en"
+	}`)
+	require.NoError(t, err)
+
+	t.Run("path parameter set as query parameter", func(t *testing.T) {
+		ctx := serve(handler, "/v1.0/mall/42")
+		assert.Equal(t, "/v1.0/invoke/mall/method/orders", string(ctx.Path()))
+		assert.Equal(t, "42", string(ctx.QueryArgs().Peek("id")))
+		assert.Nil(t, ctx.UserValue("id"))
+	})
+
+	t.Run("path parameters set in the target path", func(t *testing.T) {
+		ctx := serve(handler, "/v1.0/mall/42/items/7?limit=10")
+		assert.Equal(t, "/v1.0/invoke/mall/method/orders/42/items", string(ctx.Path()))
+		assert.Equal(t, "7", string(ctx.QueryArgs().Peek("item")))
+		assert.Equal(t, "10", string(ctx.QueryArgs().Peek("limit")))
+	})
+
+	t.Run("static route", func(t *testing.T) {
+		ctx := serve(handler, "/v1.0/hello?name=dapr")
+		assert.Equal(t, "/v1.0/invoke/hello/method/hello", string(ctx.Path()))
+		assert.Equal(t, "en", string(ctx.QueryArgs().Peek("lang")))
+		assert.Equal(t, "dapr", string(ctx.QueryArgs().Peek("name")))
+	})
+
+	t.Run("unmatched route", func(t *testing.T) {
+		ctx := serve(handler, "/v1.0/state/store?key=1")
+		assert.Equal(t, "/v1.0/state/store?key=1", string(ctx.RequestURI()))
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	})
+}
+
+func TestInvalidRoutes(t *testing.T) {
+	for _, routes := range []string{
+		"",
+		"not json",
+		`{"mall": "/v1.0/invoke/mall/method/orders"}`,
+		`{"/v1.0/mall/{id}": "/a", "/v1.0/mall/{name}": "/b"}`,
+	} {
+		_, err := getHandler(routes)
+		assert.Error(t, err, routes)
+	}
+}
@@ -0,0 +1,440 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/wasi"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	pathKey             = "path"
+	routesKey           = "routes"
+	configKey           = "config"
+	maxMemoryPagesKey   = "maxMemoryPages"
+	maxBodySizeKey      = "maxBodySize"
+	maxConcurrencyKey   = "maxConcurrency"
+	executionTimeoutKey = "executionTimeout"
+
+	// defaultMaxMemoryPages limits the memory of a module instance to 16MiB (a page is 64KiB).
+	defaultMaxMemoryPages = 256
+	// defaultMaxBodySize is the maximum size of the request and response bodies passed to the module.
+	defaultMaxBodySize = 1 << 20
+	// defaultExecutionTimeout is the maximum duration of a call of a handler of the module.
+	defaultExecutionTimeout = time.Second
+
+	// The functions exported by the module.
+	mallocFunc         = "malloc"
+	freeFunc           = "free"
+	handleRequestFunc  = "handle_request"
+	handleResponseFunc = "handle_response"
+)
+
+// Options are the options of the wasm middleware, parsed from the component metadata.
+type Options struct {
+	// Path is the path of the wasm module.
+	Path string
+	// Routes are the path prefixes the middleware applies to. The middleware applies to all the requests when empty.
+	Routes []string
+	// Config is passed as-is to the module with every request.
+	Config string
+	// MaxMemoryPages is the maximum number of 64KiB memory pages of a module instance.
+	MaxMemoryPages uint32
+	// MaxBodySize is the maximum size in bytes of the bodies passed to the module.
+	MaxBodySize int
+	// MaxConcurrency is the maximum number of module instances running at the same time. Zero means no limit.
+	MaxConcurrency int
+	// ExecutionTimeout is the maximum duration of a call of a handler of the module, including the wait for an instance.
+	ExecutionTimeout time.Duration
+}
+
+// Request is passed to the handle_request function of the module, encoded as JSON.
+type Request struct {
+	Route   string            `json:"route,omitempty"`
+	Config  string            `json:"config,omitempty"`
+	Method  string            `json:"method"`
+	URI     string            `json:"uri"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    []byte            `json:"body,omitempty"`
+}
+
+// Response is passed to the handle_response function of the module, encoded as JSON.
+type Response struct {
+	Route      string            `json:"route,omitempty"`
+	Config     string            `json:"config,omitempty"`
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       []byte            `json:"body,omitempty"`
+}
+
+// Result is returned by the module functions, encoded as JSON, to modify the request or the response.
+// When handle_request returns a status code, the request is answered with the result and not forwarded.
+type Result struct {
+	URI        string            `json:"uri,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       *[]byte           `json:"body,omitempty"`
+	StatusCode int               `json:"statusCode,omitempty"`
+}
+
+// Middleware is a HTTP middleware running request and response handlers implemented in a wasm module.
+//
+// The module must export "malloc" and "free" functions, as TinyGo does, and at least one of the handlers:
+//
+//	handle_request(ptr, len uint32) uint64
+//	handle_response(ptr, len uint32) uint64
+//
+// The handlers receive a JSON document and return the pointer (high 32 bits) and the length (low 32 bits) of
+// a JSON Result, or zero to leave the request or response unchanged.
+type Middleware struct {
+	logger logger.Logger
+}
+
+// NewMiddleware returns a new wasm middleware.
+func NewMiddleware(logger logger.Logger) *Middleware {
+	return &Middleware{logger: logger}
+}
+
+// GetHandler compiles the wasm module and returns the HTTP handler running it.
+func (m *Middleware) GetHandler(metadata middleware.Metadata) (func(h fasthttp.RequestHandler) fasthttp.RequestHandler, error) {
+	opts, err := getOptions(metadata.Properties)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse wasm metadata")
+	}
+	code, err := os.ReadFile(opts.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read wasm module")
+	}
+
+	g, err := newGuest(context.Background(), code, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			route, ok := opts.matchRoute(string(ctx.Path()))
+			if !ok {
+				h(ctx)
+				return
+			}
+
+			if len(ctx.Request.Body()) > opts.MaxBodySize {
+				ctx.Error("request body too large", fasthttp.StatusRequestEntityTooLarge)
+				return
+			}
+			if g.handleRequest {
+				res, err := g.call(handleRequestFunc, newRequest(ctx, route, opts.Config))
+				if err != nil {
+					m.logger.Errorf("wasm middleware failed handling the request: %s", err)
+					ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+					return
+				}
+				if res != nil {
+					res.applyToRequest(ctx)
+					if res.StatusCode != 0 {
+						res.applyToResponse(ctx)
+						return
+					}
+				}
+			}
+
+			h(ctx)
+
+			if !g.handleResponse || len(ctx.Response.Body()) > opts.MaxBodySize {
+				return
+			}
+			res, err := g.call(handleResponseFunc, newResponse(ctx, route, opts.Config))
+			if err != nil {
+				m.logger.Errorf("wasm middleware failed handling the response: %s", err)
+				ctx.Response.Reset()
+				ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+				return
+			}
+			if res != nil {
+				res.applyToResponse(ctx)
+			}
+		}
+	}, nil
+}
+
+func getOptions(properties map[string]string) (*Options, error) {
+	opts := &Options{
+		Path:             properties[pathKey],
+		Config:           properties[configKey],
+		MaxMemoryPages:   defaultMaxMemoryPages,
+		MaxBodySize:      defaultMaxBodySize,
+		ExecutionTimeout: defaultExecutionTimeout,
+	}
+	if opts.Path == "" {
+		return nil, errors.Errorf("missing %s", pathKey)
+	}
+	for _, route := range strings.Split(properties[routesKey], ",") {
+		if route = strings.TrimSpace(route); route != "" {
+			opts.Routes = append(opts.Routes, route)
+		}
+	}
+	if val := properties[maxMemoryPagesKey]; val != "" {
+		pages, err := strconv.ParseUint(val, 10, 32)
+		if err != nil || pages == 0 || pages > 65536 {
+			return nil, errors.Errorf("invalid %s %q: must be between 1 and 65536", maxMemoryPagesKey, val)
+		}
+		opts.MaxMemoryPages = uint32(pages)
+	}
+	if val := properties[maxBodySizeKey]; val != "" {
+		size, err := strconv.Atoi(val)
+		if err != nil || size < 0 {
+			return nil, errors.Errorf("invalid %s %q", maxBodySizeKey, val)
+		}
+		opts.MaxBodySize = size
+	}
+	if val := properties[maxConcurrencyKey]; val != "" {
+		concurrency, err := strconv.Atoi(val)
+		if err != nil || concurrency < 0 {
+			return nil, errors.Errorf("invalid %s %q", maxConcurrencyKey, val)
+		}
+		opts.MaxConcurrency = concurrency
+	}
+	if val := properties[executionTimeoutKey]; val != "" {
+		timeout, err := time.ParseDuration(val)
+		if err != nil || timeout <= 0 {
+			return nil, errors.Errorf("invalid %s %q: must be a positive duration", executionTimeoutKey, val)
+		}
+		opts.ExecutionTimeout = timeout
+	}
+	return opts, nil
+}
+
+// matchRoute returns the longest route matching the path.
+func (o *Options) matchRoute(path string) (string, bool) {
+	if len(o.Routes) == 0 {
+		return "", true
+	}
+	match, ok := "", false
+	for _, route := range o.Routes {
+		if strings.HasPrefix(path, route) && len(route) >= len(match) {
+			match, ok = route, true
+		}
+	}
+	return match, ok
+}
+
+func newRequest(ctx *fasthttp.RequestCtx, route, config string) Request {
+	req := Request{
+		Route:   route,
+		Config:  config,
+		Method:  string(ctx.Method()),
+		URI:     string(ctx.RequestURI()),
+		Headers: map[string]string{},
+		Body:    ctx.Request.Body(),
+	}
+	ctx.Request.Header.VisitAll(func(key, value []byte) {
+		req.Headers[string(key)] = string(value)
+	})
+	return req
+}
+
+func newResponse(ctx *fasthttp.RequestCtx, route, config string) Response {
+	resp := Response{
+		Route:      route,
+		Config:     config,
+		StatusCode: ctx.Response.StatusCode(),
+		Headers:    map[string]string{},
+		Body:       ctx.Response.Body(),
+	}
+	ctx.Response.Header.VisitAll(func(key, value []byte) {
+		resp.Headers[string(key)] = string(value)
+	})
+	return resp
+}
+
+func (r *Result) applyToRequest(ctx *fasthttp.RequestCtx) {
+	if r.StatusCode != 0 {
+		return
+	}
+	if r.URI != "" {
+		ctx.Request.SetRequestURI(r.URI)
+	}
+	for key, value := range r.Headers {
+		ctx.Request.Header.Set(key, value)
+	}
+	if r.Body != nil {
+		ctx.Request.SetBody(*r.Body)
+	}
+}
+
+func (r *Result) applyToResponse(ctx *fasthttp.RequestCtx) {
+	if r.StatusCode != 0 {
+		ctx.Response.SetStatusCode(r.StatusCode)
+	}
+	for key, value := range r.Headers {
+		ctx.Response.Header.Set(key, value)
+	}
+	if r.Body != nil {
+		ctx.Response.SetBody(*r.Body)
+	}
+}
+
+// guest is a compiled wasm module, instantiated for every call so that the requests don't share memory.
+type guest struct {
+	runtime        wazero.Runtime
+	compiled       *wazero.CompiledCode
+	handleRequest  bool
+	handleResponse bool
+	// sem limits the number of instances running at the same time, nil when unlimited.
+	sem     chan struct{}
+	timeout time.Duration
+	counter uint64
+}
+
+func newGuest(ctx context.Context, code []byte, opts *Options) (*guest, error) {
+	r := wazero.NewRuntimeWithConfig(wazero.NewRuntimeConfig().WithMemoryMaxPages(opts.MaxMemoryPages))
+
+	// TinyGo needs WASI to implement functions such as panic.
+	if _, err := wasi.InstantiateSnapshotPreview1(ctx, r); err != nil {
+		return nil, errors.Wrap(err, "failed to instantiate wasi")
+	}
+	compiled, err := r.CompileModule(ctx, code)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compile wasm module")
+	}
+
+	g := &guest{runtime: r, compiled: compiled, timeout: opts.ExecutionTimeout}
+	if opts.MaxConcurrency > 0 {
+		g.sem = make(chan struct{}, opts.MaxConcurrency)
+	}
+
+	// Instantiate the module once to validate its exports.
+	mod, err := g.instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mod.Close(ctx)
+	for _, name := range []string{mallocFunc, freeFunc} {
+		if mod.ExportedFunction(name) == nil {
+			return nil, errors.Errorf("wasm module does not export the %s function", name)
+		}
+	}
+	g.handleRequest = mod.ExportedFunction(handleRequestFunc) != nil
+	g.handleResponse = mod.ExportedFunction(handleResponseFunc) != nil
+	if !g.handleRequest && !g.handleResponse {
+		return nil, errors.Errorf("wasm module must export %s or %s", handleRequestFunc, handleResponseFunc)
+	}
+	return g, nil
+}
+
+func (g *guest) instantiate(ctx context.Context) (api.Module, error) {
+	name := fmt.Sprintf("guest-%d", atomic.AddUint64(&g.counter, 1))
+	mod, err := g.runtime.InstantiateModuleWithConfig(ctx, g.compiled, wazero.NewModuleConfig().WithName(name))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to instantiate wasm module")
+	}
+	return mod, nil
+}
+
+// call invokes a handler of the module with the JSON encoded input and decodes the result.
+// It returns a nil result when the handler doesn't make changes, and an error if it doesn't return before the timeout.
+func (g *guest) call(fn string, input interface{}) (*Result, error) {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(g.timeout)
+	defer timer.Stop()
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		case <-timer.C:
+			return nil, errors.Errorf("timed out after %s waiting for an instance of the wasm module", g.timeout)
+		}
+	}
+
+	type callResult struct {
+		res *Result
+		err error
+	}
+	// The runtime can't interrupt the module, so a call timing out keeps running in the background until it returns.
+	// It holds its instance until then, so the modules that don't return are limited by MaxConcurrency.
+	// As the compiled module can't be preempted either, it blocks the other goroutines when GOMAXPROCS is 1.
+	// The call doesn't use the request, which is reused by fasthttp once it is answered.
+	doneCh := make(chan callResult, 1)
+	go func() {
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		res, err := g.run(context.Background(), fn, b)
+		doneCh <- callResult{res: res, err: err}
+	}()
+
+	select {
+	case r := <-doneCh:
+		return r.res, r.err
+	case <-timer.C:
+		return nil, errors.Errorf("%s timed out after %s", fn, g.timeout)
+	}
+}
+
+// run instantiates the module and invokes the handler with the JSON input.
+func (g *guest) run(ctx context.Context, fn string, b []byte) (*Result, error) {
+	mod, err := g.instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mod.Close(ctx)
+
+	results, err := mod.ExportedFunction(mallocFunc).Call(ctx, uint64(len(b)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to allocate memory")
+	}
+	ptr := results[0]
+	defer mod.ExportedFunction(freeFunc).Call(ctx, ptr)
+
+	if !mod.Memory().Write(ctx, uint32(ptr), b) {
+		return nil, errors.Errorf("Memory.Write(%d, %d) out of range of memory size %d", ptr, len(b), mod.Memory().Size(ctx))
+	}
+	results, err = mod.ExportedFunction(fn).Call(ctx, ptr, uint64(len(b)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to call %s", fn)
+	}
+	if results[0] == 0 {
+		return nil, nil
+	}
+
+	// The result is still owned by the module, it is released when the instance is closed.
+	resPtr, resSize := uint32(results[0]>>32), uint32(results[0])
+	out, ok := mod.Memory().Read(ctx, resPtr, resSize)
+	if !ok {
+		return nil, errors.Errorf("Memory.Read(%d, %d) out of range of memory size %d", resPtr, resSize, mod.Memory().Size(ctx))
+	}
+	res := &Result{}
+	if err := json.Unmarshal(out, res); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the result of %s", fn)
+	}
+	return res, nil
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/kit/logger"
+)
+
+// guestModule returns a module in the text format whose handlers return the given results.
+// A handler is not exported when its result is empty, and returns zero when its result is "-".
+func guestModule(requestResult, responseResult string) string {
+	var sb strings.Builder
+	sb.WriteString(`(module (memory 1)
+	(func $malloc (param i32) (result i32) i32.const 1024)
+	(func $free (param i32))
+	(export "memory" (memory 0))
+	(export "malloc" (func $malloc))
+	(export "free" (func $free))
+`)
+	handler := func(name, result string) {
+		if result == "" {
+			return
+		}
+		fmt.Fprintf(&sb, "(func $%s (param i32 i32) (result i64)\n", name)
+		if result == "-" {
+			sb.WriteString("i64.const 0)\n")
+		} else {
+			// The module text format doesn't support data segments, so the handler stores the result itself.
+			for len(result)%8 != 0 {
+				result += " "
+			}
+			for i := 0; i < len(result); i += 8 {
+				fmt.Fprintf(&sb, "i32.const %d i64.const %d i64.store\n", i, int64(binary.LittleEndian.Uint64([]byte(result[i:i+8]))))
+			}
+			fmt.Fprintf(&sb, "i64.const %d)\n", len(result))
+		}
+		fmt.Fprintf(&sb, "(export %q (func $%s))\n", name, name)
+	}
+	handler(handleRequestFunc, requestResult)
+	handler(handleResponseFunc, responseResult)
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func getHandler(t *testing.T, module string, properties map[string]string) (func(h fasthttp.RequestHandler) fasthttp.RequestHandler, error) {
+	path := filepath.Join(t.TempDir(), "guest.wat")
+	require.NoError(t, os.WriteFile(path, []byte(module), 0o600))
+
+	metadata := middleware.Metadata{}
+	metadata.Properties = map[string]string{pathKey: path}
+	for k, v := range properties {
+		metadata.Properties[k] = v
+	}
+	return NewMiddleware(logger.NewLogger("wasm.test")).GetHandler(metadata)
+}
+
+func serve(handler func(h fasthttp.RequestHandler) fasthttp.RequestHandler, path string) (*fasthttp.RequestCtx, bool) {
+	called := false
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI(path)
+	handler(func(ctx *fasthttp.RequestCtx) {
+		called = true
+		ctx.Response.SetBodyString("app response")
+	})(ctx)
+	return ctx, called
+}
+
+func TestGetOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := getOptions(map[string]string{pathKey: "guest.wasm"})
+		require.NoError(t, err)
+		assert.Equal(t, &Options{Path: "guest.wasm", MaxMemoryPages: defaultMaxMemoryPages, MaxBodySize: defaultMaxBodySize, ExecutionTimeout: defaultExecutionTimeout}, opts)
+	})
+
+	t.Run("all options", func(t *testing.T) {
+		opts, err := getOptions(map[string]string{
+			pathKey:             "guest.wasm",
+			routesKey:           "/v1.0/invoke/, /v1.0/state/",
+			configKey:           "cfg",
+			maxMemoryPagesKey:   "16",
+			maxBodySizeKey:      "1024",
+			maxConcurrencyKey:   "4",
+			executionTimeoutKey: "250ms",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/v1.0/invoke/", "/v1.0/state/"}, opts.Routes)
+		assert.Equal(t, "cfg", opts.Config)
+		assert.Equal(t, uint32(16), opts.MaxMemoryPages)
+		assert.Equal(t, 1024, opts.MaxBodySize)
+		assert.Equal(t, 4, opts.MaxConcurrency)
+		assert.Equal(t, 250*time.Millisecond, opts.ExecutionTimeout)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, properties := range []map[string]string{
+			{},
+			{pathKey: "guest.wasm", maxMemoryPagesKey: "0"},
+			{pathKey: "guest.wasm", maxBodySizeKey: "-1"},
+			{pathKey: "guest.wasm", maxConcurrencyKey: "many"},
+			{pathKey: "guest.wasm", executionTimeoutKey: "0s"},
+			{pathKey: "guest.wasm", executionTimeoutKey: "1"},
+		} {
+			_, err := getOptions(properties)
+			assert.Error(t, err, properties)
+		}
+	})
+}
+
+func TestMatchRoute(t *testing.T) {
+	opts := &Options{Routes: []string{"/v1.0/invoke/", "/v1.0/invoke/app1/"}}
+	route, ok := opts.matchRoute("/v1.0/invoke/app1/method/a")
+	assert.True(t, ok)
+	assert.Equal(t, "/v1.0/invoke/app1/", route)
+
+	_, ok = opts.matchRoute("/v1.0/state/store")
+	assert.False(t, ok)
+
+	_, ok = (&Options{}).matchRoute("/v1.0/state/store")
+	assert.True(t, ok)
+}
+
+func TestWasmMiddleware(t *testing.T) {
+	t.Run("request handler rewrites the request", func(t *testing.T) {
+		handler, err := getHandler(t, guestModule(`{"uri":"/v1.0/rewritten","headers":{"X-Wasm":"1"}}`, ""), nil)
+		require.NoError(t, err)
+
+		ctx, called := serve(handler, "/v1.0/original")
+		assert.True(t, called)
+		assert.Equal(t, "/v1.0/rewritten", string(ctx.Path()))
+		assert.Equal(t, "1", string(ctx.Request.Header.Peek("X-Wasm")))
+	})
+
+	t.Run("request handler answers the request", func(t *testing.T) {
+		handler, err := getHandler(t, guestModule(`{"statusCode":403}`, ""), nil)
+		require.NoError(t, err)
+
+		ctx, called := serve(handler, "/v1.0/invoke/app1/method/a")
+		assert.False(t, called)
+		assert.Equal(t, fasthttp.StatusForbidden, ctx.Response.StatusCode())
+	})
+
+	t.Run("response handler modifies the response", func(t *testing.T) {
+		handler, err := getHandler(t, guestModule("-", `{"statusCode":202,"body":"d2FzbQ=="}`), nil)
+		require.NoError(t, err)
+
+		ctx, called := serve(handler, "/v1.0/invoke/app1/method/a")
+		assert.True(t, called)
+		assert.Equal(t, fasthttp.StatusAccepted, ctx.Response.StatusCode())
+		assert.Equal(t, "wasm", string(ctx.Response.Body()))
+	})
+
+	t.Run("routes not configured are not handled", func(t *testing.T) {
+		handler, err := getHandler(t, guestModule(`{"statusCode":403}`, ""), map[string]string{routesKey: "/v1.0/invoke/"})
+		require.NoError(t, err)
+
+		ctx, called := serve(handler, "/v1.0/state/store")
+		assert.True(t, called)
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+
+		_, called = serve(handler, "/v1.0/invoke/app1/method/a")
+		assert.False(t, called)
+	})
+
+	t.Run("request body too large", func(t *testing.T) {
+		handler, err := getHandler(t, guestModule("-", ""), map[string]string{maxBodySizeKey: "4"})
+		require.NoError(t, err)
+
+		called := false
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/v1.0/invoke/app1/method/a")
+		ctx.Request.SetBodyString("larger than 4 bytes")
+		handler(func(ctx *fasthttp.RequestCtx) { called = true })(ctx)
+		assert.False(t, called)
+		assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+	})
+
+	t.Run("request handler timing out", func(t *testing.T) {
+		if runtime.GOMAXPROCS(0) < 2 {
+			t.Skip("the compiled module can't be preempted, so the timeout needs another processor")
+		}
+
+		// The text format doesn't support loops, so the module is in the binary format.
+		// Its handler counts down from 500M with its first parameter before returning, for longer than the timeout.
+		module := string([]byte{
+			0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+			// types: (i32) -> i32, (i32) -> (), (i32, i32) -> i64
+			0x01, 0x10, 0x03, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x01, 0x7f, 0x00, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e,
+			// functions: malloc, free, handle_request
+			0x03, 0x04, 0x03, 0x00, 0x01, 0x02,
+			// memory of 1 page
+			0x05, 0x03, 0x01, 0x00, 0x01,
+			// exports
+			0x07, 0x2b, 0x04,
+			0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+			0x06, 'm', 'a', 'l', 'l', 'o', 'c', 0x00, 0x00,
+			0x04, 'f', 'r', 'e', 'e', 0x00, 0x01,
+			0x0e, 'h', 'a', 'n', 'd', 'l', 'e', '_', 'r', 'e', 'q', 'u', 'e', 's', 't', 0x00, 0x02,
+			// code
+			0x0a, 0x23, 0x03,
+			// malloc: i32.const 1024
+			0x05, 0x00, 0x41, 0x80, 0x08, 0x0b,
+			// free
+			0x02, 0x00, 0x0b,
+			// handle_request: i32.const 500000000 local.set 0 (loop local.get 0 i32.const 1 i32.sub local.tee 0 br_if 0) i64.const 0
+			0x18, 0x00, 0x41, 0x80, 0xca, 0xb5, 0xee, 0x01, 0x21, 0x00,
+			0x03, 0x40, 0x20, 0x00, 0x41, 0x01, 0x6b, 0x22, 0x00, 0x0d, 0x00, 0x0b,
+			0x42, 0x00, 0x0b,
+		})
+		handler, err := getHandler(t, module, map[string]string{executionTimeoutKey: "10ms", maxConcurrencyKey: "1"})
+		require.NoError(t, err)
+
+		start := time.Now()
+		ctx, called := serve(handler, "/v1.0/invoke/app1/method/a")
+		assert.False(t, called)
+		assert.Equal(t, fasthttp.StatusInternalServerError, ctx.Response.StatusCode())
+		assert.Less(t, time.Since(start), time.Second)
+
+		// The instance still running holds the only slot
+		ctx, called = serve(handler, "/v1.0/invoke/app1/method/a")
+		assert.False(t, called)
+		assert.Equal(t, fasthttp.StatusInternalServerError, ctx.Response.StatusCode())
+	})
+
+	t.Run("module without handlers", func(t *testing.T) {
+		_, err := getHandler(t, guestModule("", ""), nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("module exceeding the memory limit", func(t *testing.T) {
+		_, err := getHandler(t, strings.Replace(guestModule("-", ""), "(memory 1)", "(memory 2)", 1), map[string]string{maxMemoryPagesKey: "1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing module", func(t *testing.T) {
+		metadata := middleware.Metadata{}
+		metadata.Properties = map[string]string{pathKey: filepath.Join(t.TempDir(), "missing.wasm")}
+		_, err := NewMiddleware(logger.NewLogger("wasm.test")).GetHandler(metadata)
+		assert.Error(t, err)
+	})
+}
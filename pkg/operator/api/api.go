@@ -29,19 +29,25 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/dapr/kit/logger"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
+	"github.com/dapr/dapr/pkg/acl"
 	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
 	resiliencyapi "github.com/dapr/dapr/pkg/apis/resiliency/v1alpha1"
 	subscriptionsapiV2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+	"github.com/dapr/dapr/pkg/components/validation"
 	daprCredentials "github.com/dapr/dapr/pkg/credentials"
 	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
 )
@@ -52,41 +58,60 @@ const (
 	APIVersionV1alpha1    = "dapr.io/v1alpha1"
 	APIVersionV2alpha1    = "dapr.io/v2alpha1"
 	kubernetesSecretStore = "kubernetes"
+	componentStatusFailed = "FAILED"
+	// driftReason is the reason of the events emitted for the resources that are out of date in a sidecar.
+	driftReason = "ConfigurationDrift"
 )
 
 var log = logger.NewLogger("dapr.operator.api")
 
 // Server runs the Dapr API server for components and configurations.
 type Server interface {
-	Run(ctx context.Context, certChain *daprCredentials.CertChain, onReady func())
-	OnComponentUpdated(component *componentsapi.Component)
+	Run(ctx context.Context, certs *daprCredentials.CertChainReloader, onReady func())
+	OnComponentUpdated(component *componentsapi.Component, eventType operatorv1pb.ResourceEventType)
+	OnSubscriptionUpdated(subscription *subscriptionsapiV2alpha1.Subscription, eventType operatorv1pb.ResourceEventType)
+}
+
+type componentUpdateEvent struct {
+	component *componentsapi.Component
+	eventType operatorv1pb.ResourceEventType
+}
+
+type subscriptionUpdateEvent struct {
+	subscription *subscriptionsapiV2alpha1.Subscription
+	eventType    operatorv1pb.ResourceEventType
 }
 
 type apiServer struct {
 	operatorv1pb.UnimplementedOperatorServer
 	Client client.Client
+	// recorder emits the events of the components reported by the sidecars.
+	recorder record.EventRecorder
 	// notify all dapr runtime
-	connLock          sync.Mutex
-	allConnUpdateChan map[string]chan *componentsapi.Component
+	connLock             sync.Mutex
+	allConnUpdateChan    map[string]chan *componentUpdateEvent
+	allSubConnUpdateChan map[string]chan *subscriptionUpdateEvent
+	// sharedNamespace is the namespace of the components shared with the sidecars of all the namespaces, if any.
+	sharedNamespace string
 }
 
 // NewAPIServer returns a new API server.
-func NewAPIServer(client client.Client) Server {
+// The components of sharedNamespace are returned to the sidecars of all the namespaces, in addition to the components of their own namespace.
+func NewAPIServer(client client.Client, recorder record.EventRecorder, sharedNamespace string) Server {
 	return &apiServer{
-		Client:            client,
-		allConnUpdateChan: make(map[string]chan *componentsapi.Component),
+		Client:               client,
+		recorder:             recorder,
+		sharedNamespace:      sharedNamespace,
+		allConnUpdateChan:    make(map[string]chan *componentUpdateEvent),
+		allSubConnUpdateChan: make(map[string]chan *subscriptionUpdateEvent),
 	}
 }
 
 // Run starts a new gRPC server.
-func (a *apiServer) Run(ctx context.Context, certChain *daprCredentials.CertChain, onReady func()) {
+func (a *apiServer) Run(ctx context.Context, certs *daprCredentials.CertChainReloader, onReady func()) {
 	log.Infof("starting gRPC server on port %d", serverPort)
 
-	opts, err := daprCredentials.GetServerOptions(certChain)
-	if err != nil {
-		log.Fatalf("error creating gRPC options: %v", err)
-	}
-	s := grpc.NewServer(opts...)
+	s := grpc.NewServer(certs.ServerOptions()...)
 	operatorv1pb.RegisterOperatorServer(s, a)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%v", serverPort))
@@ -126,10 +151,24 @@ func (a *apiServer) Run(ctx context.Context, certChain *daprCredentials.CertChai
 	}
 }
 
-func (a *apiServer) OnComponentUpdated(component *componentsapi.Component) {
+func (a *apiServer) OnComponentUpdated(component *componentsapi.Component, eventType operatorv1pb.ResourceEventType) {
 	a.connLock.Lock()
 	for _, connUpdateChan := range a.allConnUpdateChan {
-		connUpdateChan <- component
+		connUpdateChan <- &componentUpdateEvent{
+			component: component,
+			eventType: eventType,
+		}
+	}
+	a.connLock.Unlock()
+}
+
+func (a *apiServer) OnSubscriptionUpdated(subscription *subscriptionsapiV2alpha1.Subscription, eventType operatorv1pb.ResourceEventType) {
+	a.connLock.Lock()
+	for _, connUpdateChan := range a.allSubConnUpdateChan {
+		connUpdateChan <- &subscriptionUpdateEvent{
+			subscription: subscription,
+			eventType:    eventType,
+		}
 	}
 	a.connLock.Unlock()
 }
@@ -151,19 +190,45 @@ func (a *apiServer) GetConfiguration(ctx context.Context, in *operatorv1pb.GetCo
 }
 
 // ListComponents returns a list of Dapr components.
+// The sidecars only get the components of their own namespace and of the shared namespace.
 func (a *apiServer) ListComponents(ctx context.Context, in *operatorv1pb.ListComponentsRequest) (*operatorv1pb.ListComponentResponse, error) {
+	if err := authorizeNamespace(ctx, in.Namespace); err != nil {
+		log.Warnf("denied the components of namespace %s to pod %s: %s", in.Namespace, in.PodName, err)
+		return nil, err
+	}
+
 	var components componentsapi.ComponentList
 	if err := a.Client.List(ctx, &components, &client.ListOptions{
 		Namespace: in.Namespace,
 	}); err != nil {
 		return nil, errors.Wrap(err, "error getting components")
 	}
+	items := components.Items
+	if a.sharedNamespace != "" && a.sharedNamespace != in.Namespace {
+		var shared componentsapi.ComponentList
+		if err := a.Client.List(ctx, &shared, &client.ListOptions{
+			Namespace: a.sharedNamespace,
+		}); err != nil {
+			return nil, errors.Wrap(err, "error getting shared components")
+		}
+		names := make(map[string]struct{}, len(items))
+		for _, c := range items {
+			names[c.Name] = struct{}{}
+		}
+		for _, c := range shared.Items {
+			// The components of the namespace of the sidecar take precedence over the shared ones.
+			if _, ok := names[c.Name]; !ok {
+				items = append(items, c)
+			}
+		}
+	}
+
 	resp := &operatorv1pb.ListComponentResponse{
 		Components: [][]byte{},
 	}
-	for i := range components.Items {
-		c := components.Items[i] // Make a copy since we will refer to this as a reference in this loop.
-		err := processComponentSecrets(&c, in.Namespace, a.Client)
+	for i := range items {
+		c := items[i] // Make a copy since we will refer to this as a reference in this loop.
+		err := a.prepareComponent(&c, in.Namespace)
 		if err != nil {
 			log.Warnf("error processing component %s secrets from pod %s/%s: %s", c.Name, in.Namespace, in.PodName, err)
 			return &operatorv1pb.ListComponentResponse{}, err
@@ -179,6 +244,149 @@ func (a *apiServer) ListComponents(ctx context.Context, in *operatorv1pb.ListCom
 	return resp, nil
 }
 
+// isComponentVisible returns true if the component can be sent to the sidecars of the namespace.
+func (a *apiServer) isComponentVisible(c *componentsapi.Component, namespace string) bool {
+	return c.Namespace == namespace || (a.sharedNamespace != "" && c.Namespace == a.sharedNamespace)
+}
+
+// isComponentShadowed returns true if the component is a shared one and the namespace has its own component of the same
+// name, which takes precedence over it, so the changes of the shared component must not be sent to the sidecars of the
+// namespace. The shared component is also considered shadowed if the component of the namespace can't be looked up.
+func (a *apiServer) isComponentShadowed(ctx context.Context, c *componentsapi.Component, namespace string) bool {
+	if c.Namespace == namespace {
+		return false
+	}
+	var local componentsapi.Component
+	err := a.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: c.Name}, &local)
+	if err == nil {
+		return true
+	}
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	log.Warnf("error getting component %s of namespace %s, skipping the update of shared component %s: %s", c.Name, namespace, c.Name, err)
+	return true
+}
+
+// getSharedComponent returns the shared component of the name, or nil if there is none for the sidecars of the namespace.
+func (a *apiServer) getSharedComponent(ctx context.Context, name, namespace string) *componentsapi.Component {
+	if a.sharedNamespace == "" || a.sharedNamespace == namespace {
+		return nil
+	}
+	var shared componentsapi.Component
+	err := a.Client.Get(ctx, types.NamespacedName{Namespace: a.sharedNamespace, Name: name}, &shared)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Warnf("error getting shared component %s: %s", name, err)
+		}
+		return nil
+	}
+	return &shared
+}
+
+// prepareComponent resolves the secrets of the component from its own namespace,
+// and moves the shared components to the namespace of the sidecar, which only loads the components of its namespace.
+func (a *apiServer) prepareComponent(c *componentsapi.Component, namespace string) error {
+	if err := processComponentSecrets(c, c.Namespace, a.Client); err != nil {
+		return err
+	}
+	c.Namespace = namespace
+	return nil
+}
+
+// authorizeNamespace returns an error if the identity of the mTLS certificate of the caller doesn't belong to the namespace.
+// The calls without mTLS are denied, as the callers can't be identified.
+func authorizeNamespace(ctx context.Context, namespace string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return status.Error(codes.PermissionDenied, "the caller must be authenticated with mTLS")
+	}
+	if _, ok = p.AuthInfo.(credentials.TLSInfo); !ok {
+		return status.Error(codes.PermissionDenied, "the caller must be authenticated with mTLS")
+	}
+
+	id, err := acl.GetAndParseSpiffeID(ctx)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "failed to get the identity of the caller: %s", err)
+	}
+	if id.Namespace != namespace {
+		return status.Errorf(codes.PermissionDenied, "caller from namespace %s is not allowed to access the resources of namespace %s", id.Namespace, namespace)
+	}
+	return nil
+}
+
+// ReportComponentStatus emits a Kubernetes event on the component that a sidecar failed to initialize.
+func (a *apiServer) ReportComponentStatus(ctx context.Context, in *operatorv1pb.ReportComponentStatusRequest) (*emptypb.Empty, error) {
+	if in.ComponentName == "" {
+		return nil, status.Error(codes.InvalidArgument, "component name is required")
+	}
+	if err := authorizeNamespace(ctx, in.Namespace); err != nil {
+		log.Warnf("denied the status report of component %s of namespace %s from pod %s: %s", in.ComponentName, in.Namespace, in.PodName, err)
+		return nil, err
+	}
+	if in.Status != componentStatusFailed || a.recorder == nil {
+		return &emptypb.Empty{}, nil
+	}
+
+	key := types.NamespacedName{Namespace: in.Namespace, Name: in.ComponentName}
+	var component componentsapi.Component
+	if err := a.Client.Get(ctx, key, &component); err != nil {
+		return nil, errors.Wrap(err, "error getting component")
+	}
+
+	a.recorder.Eventf(&component, corev1.EventTypeWarning, componentFailureReason(validation.ErrorClass(in.ErrorClass)),
+		"pod %s failed to initialize the component: %s", in.PodName, in.Error)
+	return &emptypb.Empty{}, nil
+}
+
+// ReportDrift emits a Kubernetes event on the components and the configuration that are out of date in a sidecar.
+func (a *apiServer) ReportDrift(ctx context.Context, in *operatorv1pb.ReportDriftRequest) (*emptypb.Empty, error) {
+	if err := authorizeNamespace(ctx, in.Namespace); err != nil {
+		log.Warnf("denied the drift report of namespace %s from pod %s: %s", in.Namespace, in.PodName, err)
+		return nil, err
+	}
+	if a.recorder == nil {
+		return &emptypb.Empty{}, nil
+	}
+
+	for _, name := range in.Components {
+		var component componentsapi.Component
+		if err := a.Client.Get(ctx, types.NamespacedName{Namespace: in.Namespace, Name: name}, &component); err != nil {
+			// Deleted components have no object to attach the event to
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrap(err, "error getting component")
+		}
+		a.recorder.Eventf(&component, corev1.EventTypeWarning, driftReason,
+			"pod %s is running an out of date version of the component", in.PodName)
+	}
+
+	if in.Configuration != "" {
+		var config configurationapi.Configuration
+		if err := a.Client.Get(ctx, types.NamespacedName{Namespace: in.Namespace, Name: in.Configuration}, &config); err != nil {
+			return nil, errors.Wrap(err, "error getting configuration")
+		}
+		a.recorder.Eventf(&config, corev1.EventTypeWarning, driftReason,
+			"pod %s is running an out of date version of the configuration", in.PodName)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// componentFailureReason returns the reason of the event emitted for an initialization error of the class.
+func componentFailureReason(class validation.ErrorClass) string {
+	switch class {
+	case validation.ClassCredentials:
+		return "InvalidCredentials"
+	case validation.ClassPermissions:
+		return "PermissionDenied"
+	case validation.ClassNetwork:
+		return "Unreachable"
+	default:
+		return "InitFailed"
+	}
+}
+
 func processComponentSecrets(component *componentsapi.Component, namespace string, kubeClient client.Client) error {
 	for i, m := range component.Spec.Metadata {
 		if m.SecretKeyRef.Name != "" && (component.Auth.SecretStore == kubernetesSecretStore || component.Auth.SecretStore == "") {
@@ -188,6 +396,10 @@ func processComponentSecrets(component *componentsapi.Component, namespace strin
 				Name:      m.SecretKeyRef.Name,
 				Namespace: namespace,
 			}, &secret)
+			// A component naming no secret store may get the secret from the other secret stores of the resolution chain of the sidecar.
+			if component.Auth.SecretStore == "" && apierrors.IsNotFound(err) {
+				continue
+			}
 			if err != nil {
 				return err
 			}
@@ -294,10 +506,15 @@ func (a *apiServer) ListResiliency(ctx context.Context, in *operatorv1pb.ListRes
 
 // ComponentUpdate updates Dapr sidecars whenever a component in the cluster is modified.
 func (a *apiServer) ComponentUpdate(in *operatorv1pb.ComponentUpdateRequest, srv operatorv1pb.Operator_ComponentUpdateServer) error { //nolint:nosnakecase
+	if err := authorizeNamespace(srv.Context(), in.Namespace); err != nil {
+		log.Warnf("denied the component updates of namespace %s to pod %s: %s", in.Namespace, in.PodName, err)
+		return err
+	}
+
 	log.Info("sidecar connected for component updates")
 	key := uuid.New().String()
 	a.connLock.Lock()
-	a.allConnUpdateChan[key] = make(chan *componentsapi.Component, 1)
+	a.allConnUpdateChan[key] = make(chan *componentUpdateEvent, 1)
 	updateChan := a.allConnUpdateChan[key]
 	a.connLock.Unlock()
 	defer func() {
@@ -306,16 +523,34 @@ func (a *apiServer) ComponentUpdate(in *operatorv1pb.ComponentUpdateRequest, srv
 		a.connLock.Unlock()
 	}()
 	chWrapper := initChanGracefully(updateChan)
-	updateComponentFunc := func(c *componentsapi.Component) {
-		if c.Namespace != in.Namespace {
+	updateComponentFunc := func(e *componentUpdateEvent) {
+		if !a.isComponentVisible(e.component, in.Namespace) {
 			return
 		}
-
-		err := processComponentSecrets(c, in.Namespace, a.Client)
-		if err != nil {
-			log.Warnf("error processing component %s secrets from pod %s/%s: %s", c.Name, in.Namespace, in.PodName, err)
+		if a.isComponentShadowed(srv.Context(), e.component, in.Namespace) {
 			return
 		}
+		// The same component is sent to the sidecars of all the namespaces
+		c := e.component.DeepCopy()
+		eventType := e.eventType
+
+		// The shared component of the same name takes over from the deleted component of the namespace
+		if eventType == operatorv1pb.ResourceEventType_DELETED && c.Namespace == in.Namespace {
+			if shared := a.getSharedComponent(srv.Context(), c.Name, in.Namespace); shared != nil {
+				c = shared
+				eventType = operatorv1pb.ResourceEventType_UPDATED
+			}
+		}
+
+		// The secrets referenced by a deleted component may be gone already, and are not needed to remove it
+		if eventType != operatorv1pb.ResourceEventType_DELETED {
+			err := processComponentSecrets(c, c.Namespace, a.Client)
+			if err != nil {
+				log.Warnf("error processing component %s secrets from pod %s/%s: %s", c.Name, in.Namespace, in.PodName, err)
+				return
+			}
+		}
+		c.Namespace = in.Namespace
 
 		b, err := json.Marshal(&c)
 		if err != nil {
@@ -324,6 +559,7 @@ func (a *apiServer) ComponentUpdate(in *operatorv1pb.ComponentUpdateRequest, srv
 		}
 		err = srv.Send(&operatorv1pb.ComponentUpdateEvent{
 			Component: b,
+			Type:      eventType,
 		})
 		if err != nil {
 			log.Warnf("error updating sidecar with component %s (%s) from pod %s/%s: %s", c.GetName(), c.Spec.Type, in.Namespace, in.PodName, err)
@@ -338,33 +574,84 @@ func (a *apiServer) ComponentUpdate(in *operatorv1pb.ComponentUpdateRequest, srv
 		select {
 		case <-srv.Context().Done():
 			return nil
-		case c, ok := <-updateChan:
+		case e, ok := <-updateChan:
+			if !ok {
+				return nil
+			}
+			go updateComponentFunc(e)
+		}
+	}
+}
+
+// SubscriptionUpdate updates Dapr sidecars whenever a pub/sub subscription in the cluster is created, modified or deleted.
+func (a *apiServer) SubscriptionUpdate(in *operatorv1pb.SubscriptionUpdateRequest, srv operatorv1pb.Operator_SubscriptionUpdateServer) error { //nolint:nosnakecase
+	log.Info("sidecar connected for subscription updates")
+	key := uuid.New().String()
+	a.connLock.Lock()
+	a.allSubConnUpdateChan[key] = make(chan *subscriptionUpdateEvent, 1)
+	updateChan := a.allSubConnUpdateChan[key]
+	a.connLock.Unlock()
+	defer func() {
+		a.connLock.Lock()
+		delete(a.allSubConnUpdateChan, key)
+		a.connLock.Unlock()
+	}()
+	chWrapper := initChanGracefully(updateChan)
+	updateSubscriptionFunc := func(e *subscriptionUpdateEvent) {
+		s := e.subscription
+		if s.Namespace != in.Namespace {
+			return
+		}
+
+		b, err := json.Marshal(s)
+		if err != nil {
+			log.Warnf("error serializing subscription %s for pod %s/%s: %s", s.GetName(), in.Namespace, in.PodName, err)
+			return
+		}
+		err = srv.Send(&operatorv1pb.SubscriptionUpdateEvent{
+			Subscription: b,
+			Type:         e.eventType,
+		})
+		if err != nil {
+			log.Warnf("error updating sidecar with subscription %s for pod %s/%s: %s", s.GetName(), in.Namespace, in.PodName, err)
+			if status.Code(err) == codes.Unavailable {
+				chWrapper.Close()
+			}
+			return
+		}
+		log.Infof("updated sidecar with subscription %s for pod %s/%s", s.GetName(), in.Namespace, in.PodName)
+	}
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case e, ok := <-updateChan:
 			if !ok {
 				return nil
 			}
-			go updateComponentFunc(c)
+			go updateSubscriptionFunc(e)
 		}
 	}
 }
 
 // chanGracefully control channel to close gracefully in multi-goroutines.
-type chanGracefully struct {
-	ch       chan *componentsapi.Component
+type chanGracefully[T any] struct {
+	ch       chan T
 	isClosed bool
 	sync.Mutex
 }
 
-func initChanGracefully(ch chan *componentsapi.Component) (
-	c *chanGracefully,
+func initChanGracefully[T any](ch chan T) (
+	c *chanGracefully[T],
 ) {
-	return &chanGracefully{
+	return &chanGracefully[T]{
 		ch:       ch,
 		isClosed: false,
 	}
 }
 
 // Close chan be closed non-reentrantly.
-func (c *chanGracefully) Close() {
+func (c *chanGracefully[T]) Close() {
 	c.Lock()
 	if !c.isClosed {
 		c.isClosed = true
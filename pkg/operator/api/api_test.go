@@ -15,20 +15,35 @@ package api
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
 	resiliencyapi "github.com/dapr/dapr/pkg/apis/resiliency/v1alpha1"
 	subscriptionsapiV2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
 	"github.com/dapr/dapr/pkg/client/clientset/versioned/scheme"
@@ -37,15 +52,56 @@ import (
 
 type mockComponentUpdateServer struct {
 	grpc.ServerStream
-	Calls int
+	ctx    context.Context
+	Calls  int
+	Events []*operatorv1pb.ComponentUpdateEvent
 }
 
-func (m *mockComponentUpdateServer) Send(*operatorv1pb.ComponentUpdateEvent) error {
+func (m *mockComponentUpdateServer) Send(e *operatorv1pb.ComponentUpdateEvent) error {
 	m.Calls++
+	m.Events = append(m.Events, e)
 	return nil
 }
 
 func (m *mockComponentUpdateServer) Context() context.Context {
+	return m.ctx
+}
+
+// newPeerContext returns the context of a call with a client certificate of the SPIFFE ID.
+func newPeerContext(t *testing.T, spiffeID string) context.Context {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id, err := url.Parse(spiffeID)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{id},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+		},
+	})
+}
+
+type mockSubscriptionUpdateServer struct {
+	grpc.ServerStream
+	Events []*operatorv1pb.SubscriptionUpdateEvent
+}
+
+func (m *mockSubscriptionUpdateServer) Send(e *operatorv1pb.SubscriptionUpdateEvent) error {
+	m.Events = append(m.Events, e)
+	return nil
+}
+
+func (m *mockSubscriptionUpdateServer) Context() context.Context {
 	return context.TODO()
 }
 
@@ -165,6 +221,39 @@ func TestProcessComponentSecrets(t *testing.T) {
 
 		assert.Equal(t, jsonEnc, c.Spec.Metadata[0].Value.Raw)
 	})
+
+	t.Run("secret ref doesn't exist, default kubernetes secret store, secret left to the sidecar", func(t *testing.T) {
+		c := componentsapi.Component{
+			Spec: componentsapi.ComponentSpec{
+				Metadata: []componentsapi.MetadataItem{
+					{
+						Name: "test1",
+						SecretKeyRef: componentsapi.SecretKeyRef{
+							Name: "secret1",
+							Key:  "key1",
+						},
+					},
+				},
+			},
+		}
+
+		s := runtime.NewScheme()
+		err := scheme.AddToScheme(s)
+		assert.NoError(t, err)
+
+		err = corev1.AddToScheme(s)
+		assert.NoError(t, err)
+
+		client := fake.NewClientBuilder().WithScheme(s).Build()
+
+		err = processComponentSecrets(&c, "default", client)
+		assert.NoError(t, err)
+		assert.Empty(t, c.Spec.Metadata[0].Value.Raw)
+
+		c.Auth.SecretStore = kubernetesSecretStore
+		err = processComponentSecrets(&c, "default", client)
+		assert.Error(t, err)
+	})
 }
 
 func TestChanGracefullyClose(t *testing.T) {
@@ -195,15 +284,18 @@ func TestComponentUpdate(t *testing.T) {
 		client := fake.NewClientBuilder().
 			WithScheme(s).Build()
 
-		mockSidecar := &mockComponentUpdateServer{}
-		api := NewAPIServer(client).(*apiServer)
+		mockSidecar := &mockComponentUpdateServer{ctx: newPeerContext(t, "spiffe://public/ns/ns2/myapp")}
+		api := NewAPIServer(client, nil, "").(*apiServer)
 
 		go func() {
 			// Send a component update, give sidecar time to register
 			time.Sleep(time.Millisecond * 500)
 
 			for _, connUpdateChan := range api.allConnUpdateChan {
-				connUpdateChan <- &c
+				connUpdateChan <- &componentUpdateEvent{
+					component: &c,
+					eventType: operatorv1pb.ResourceEventType_UPDATED,
+				}
 
 				// Give sidecar time to register update
 				time.Sleep(time.Millisecond * 500)
@@ -237,15 +329,18 @@ func TestComponentUpdate(t *testing.T) {
 		client := fake.NewClientBuilder().
 			WithScheme(s).Build()
 
-		mockSidecar := &mockComponentUpdateServer{}
-		api := NewAPIServer(client).(*apiServer)
+		mockSidecar := &mockComponentUpdateServer{ctx: newPeerContext(t, "spiffe://public/ns/ns1/myapp")}
+		api := NewAPIServer(client, nil, "").(*apiServer)
 
 		go func() {
 			// Send a component update, give sidecar time to register
 			time.Sleep(time.Millisecond * 500)
 
 			for _, connUpdateChan := range api.allConnUpdateChan {
-				connUpdateChan <- &c
+				connUpdateChan <- &componentUpdateEvent{
+					component: &c,
+					eventType: operatorv1pb.ResourceEventType_UPDATED,
+				}
 
 				// Give sidecar time to register update
 				time.Sleep(time.Millisecond * 500)
@@ -262,6 +357,227 @@ func TestComponentUpdate(t *testing.T) {
 	})
 }
 
+func TestSharedComponentUpdate(t *testing.T) {
+	s := runtime.NewScheme()
+	err := scheme.AddToScheme(s)
+	assert.NoError(t, err)
+
+	av, kind := componentsapi.SchemeGroupVersion.WithKind("Component").ToAPIVersionAndKind()
+	typeMeta := metav1.TypeMeta{
+		Kind:       kind,
+		APIVersion: av,
+	}
+	client := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(&componentsapi.Component{
+			TypeMeta:   typeMeta,
+			ObjectMeta: metav1.ObjectMeta{Name: "statestore", Namespace: "namespace-a"},
+			Spec:       componentsapi.ComponentSpec{Type: "state.redis"},
+		}).
+		Build()
+
+	for _, eventType := range []operatorv1pb.ResourceEventType{operatorv1pb.ResourceEventType_UPDATED, operatorv1pb.ResourceEventType_DELETED} {
+		t.Run(fmt.Sprintf("shared component shadowed by the namespace is skipped when %s", eventType), func(t *testing.T) {
+			mockSidecar := &mockComponentUpdateServer{ctx: newPeerContext(t, "spiffe://public/ns/namespace-a/myapp")}
+			api := NewAPIServer(client, nil, "shared").(*apiServer)
+
+			go func() {
+				// Send the updates, give sidecar time to register
+				time.Sleep(time.Millisecond * 500)
+
+				api.OnComponentUpdated(&componentsapi.Component{
+					ObjectMeta: metav1.ObjectMeta{Name: "statestore", Namespace: "shared"},
+				}, eventType)
+				api.OnComponentUpdated(&componentsapi.Component{
+					ObjectMeta: metav1.ObjectMeta{Name: "pubsub", Namespace: "shared"},
+				}, eventType)
+
+				// Give sidecar time to register update
+				time.Sleep(time.Millisecond * 500)
+				api.connLock.Lock()
+				for _, connUpdateChan := range api.allConnUpdateChan {
+					close(connUpdateChan)
+				}
+				api.connLock.Unlock()
+			}()
+
+			// Start sidecar update loop
+			api.ComponentUpdate(&operatorv1pb.ComponentUpdateRequest{
+				Namespace: "namespace-a",
+			}, mockSidecar)
+
+			// Only the shared pubsub component is sent
+			assert.Equal(t, 1, mockSidecar.Calls)
+		})
+	}
+
+	t.Run("shared component replaces the deleted component of the namespace", func(t *testing.T) {
+		client := fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(&componentsapi.Component{
+				TypeMeta:   typeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "statestore", Namespace: "shared"},
+				Spec:       componentsapi.ComponentSpec{Type: "state.postgresql"},
+			}).
+			Build()
+		mockSidecar := &mockComponentUpdateServer{ctx: newPeerContext(t, "spiffe://public/ns/namespace-a/myapp")}
+		api := NewAPIServer(client, nil, "shared").(*apiServer)
+
+		go func() {
+			// Send the deletion, give sidecar time to register
+			time.Sleep(time.Millisecond * 500)
+
+			api.OnComponentUpdated(&componentsapi.Component{
+				ObjectMeta: metav1.ObjectMeta{Name: "statestore", Namespace: "namespace-a"},
+				Spec:       componentsapi.ComponentSpec{Type: "state.redis"},
+			}, operatorv1pb.ResourceEventType_DELETED)
+
+			// Give sidecar time to register update
+			time.Sleep(time.Millisecond * 500)
+			api.connLock.Lock()
+			for _, connUpdateChan := range api.allConnUpdateChan {
+				close(connUpdateChan)
+			}
+			api.connLock.Unlock()
+		}()
+
+		// Start sidecar update loop
+		api.ComponentUpdate(&operatorv1pb.ComponentUpdateRequest{
+			Namespace: "namespace-a",
+		}, mockSidecar)
+
+		if assert.Len(t, mockSidecar.Events, 1) {
+			assert.Equal(t, operatorv1pb.ResourceEventType_UPDATED, mockSidecar.Events[0].Type)
+
+			var received componentsapi.Component
+			require.NoError(t, json.Unmarshal(mockSidecar.Events[0].Component, &received))
+			assert.Equal(t, "state.postgresql", received.Spec.Type)
+			assert.Equal(t, "namespace-a", received.Namespace)
+		}
+	})
+
+	t.Run("sidecar without mtls is denied", func(t *testing.T) {
+		api := NewAPIServer(client, nil, "shared").(*apiServer)
+
+		err := api.ComponentUpdate(&operatorv1pb.ComponentUpdateRequest{
+			Namespace: "namespace-a",
+		}, &mockComponentUpdateServer{ctx: context.Background()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestComponentDeleteUpdate(t *testing.T) {
+	t.Run("deleted component is sent without resolving its secrets", func(t *testing.T) {
+		c := componentsapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+			},
+			Spec: componentsapi.ComponentSpec{
+				Metadata: []componentsapi.MetadataItem{
+					{
+						Name: "test1",
+						SecretKeyRef: componentsapi.SecretKeyRef{
+							Name: "deleted-secret",
+							Key:  "key1",
+						},
+					},
+				},
+			},
+		}
+
+		s := runtime.NewScheme()
+		err := scheme.AddToScheme(s)
+		assert.NoError(t, err)
+
+		err = corev1.AddToScheme(s)
+		assert.NoError(t, err)
+
+		client := fake.NewClientBuilder().
+			WithScheme(s).Build()
+
+		mockSidecar := &mockComponentUpdateServer{ctx: newPeerContext(t, "spiffe://public/ns/ns1/myapp")}
+		api := NewAPIServer(client, nil, "").(*apiServer)
+
+		go func() {
+			// Send a component deletion, give sidecar time to register
+			time.Sleep(time.Millisecond * 500)
+
+			api.OnComponentUpdated(&c, operatorv1pb.ResourceEventType_DELETED)
+
+			// Give sidecar time to register update
+			time.Sleep(time.Millisecond * 500)
+			api.connLock.Lock()
+			for _, connUpdateChan := range api.allConnUpdateChan {
+				close(connUpdateChan)
+			}
+			api.connLock.Unlock()
+		}()
+
+		// Start sidecar update loop
+		api.ComponentUpdate(&operatorv1pb.ComponentUpdateRequest{
+			Namespace: "ns1",
+		}, mockSidecar)
+
+		assert.Equal(t, 1, mockSidecar.Calls)
+	})
+}
+
+func TestSubscriptionUpdate(t *testing.T) {
+	s := runtime.NewScheme()
+	err := scheme.AddToScheme(s)
+	assert.NoError(t, err)
+
+	client := fake.NewClientBuilder().
+		WithScheme(s).Build()
+
+	mockSidecar := &mockSubscriptionUpdateServer{}
+	api := NewAPIServer(client, nil, "").(*apiServer)
+
+	sub := func(namespace string) *subscriptionsapiV2alpha1.Subscription {
+		return &subscriptionsapiV2alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sub1",
+				Namespace: namespace,
+			},
+			Spec: subscriptionsapiV2alpha1.SubscriptionSpec{
+				Pubsubname: "pubsub",
+				Topic:      "topic1",
+			},
+		}
+	}
+
+	go func() {
+		// Send subscription updates, give sidecar time to register
+		time.Sleep(time.Millisecond * 500)
+
+		api.OnSubscriptionUpdated(sub("ns2"), operatorv1pb.ResourceEventType_CREATED)
+		api.OnSubscriptionUpdated(sub("ns1"), operatorv1pb.ResourceEventType_DELETED)
+
+		// Give sidecar time to register update
+		time.Sleep(time.Millisecond * 500)
+		api.connLock.Lock()
+		for _, connUpdateChan := range api.allSubConnUpdateChan {
+			close(connUpdateChan)
+		}
+		api.connLock.Unlock()
+	}()
+
+	// Start sidecar update loop
+	api.SubscriptionUpdate(&operatorv1pb.SubscriptionUpdateRequest{
+		Namespace: "ns1",
+	}, mockSidecar)
+
+	if assert.Len(t, mockSidecar.Events, 1) {
+		assert.Equal(t, operatorv1pb.ResourceEventType_DELETED, mockSidecar.Events[0].Type)
+
+		var received subscriptionsapiV2alpha1.Subscription
+		err = json.Unmarshal(mockSidecar.Events[0].Subscription, &received)
+		assert.NoError(t, err)
+		assert.Equal(t, "sub1", received.Name)
+		assert.Equal(t, "topic1", received.Spec.Topic)
+	}
+}
+
 func TestListsNamespaced(t *testing.T) {
 	t.Run("list components namespace scoping", func(t *testing.T) {
 		s := runtime.NewScheme()
@@ -293,9 +609,9 @@ func TestListsNamespaced(t *testing.T) {
 			}).
 			Build()
 
-		api := NewAPIServer(client).(*apiServer)
+		api := NewAPIServer(client, nil, "").(*apiServer)
 
-		res, err := api.ListComponents(context.TODO(), &operatorv1pb.ListComponentsRequest{
+		res, err := api.ListComponents(newPeerContext(t, "spiffe://public/ns/namespace-a/myapp"), &operatorv1pb.ListComponentsRequest{
 			PodName:   "foo",
 			Namespace: "namespace-a",
 		})
@@ -310,13 +626,90 @@ func TestListsNamespaced(t *testing.T) {
 		assert.Equal(t, "obj1", sub.Name)
 		assert.Equal(t, "namespace-a", sub.Namespace)
 
-		res, err = api.ListComponents(context.TODO(), &operatorv1pb.ListComponentsRequest{
+		res, err = api.ListComponents(newPeerContext(t, "spiffe://public/ns/namespace-c/myapp"), &operatorv1pb.ListComponentsRequest{
 			PodName:   "foo",
 			Namespace: "namespace-c",
 		})
 		assert.Nil(t, err)
 		assert.Equal(t, 0, len(res.GetComponents()))
 	})
+	t.Run("list components with shared namespace", func(t *testing.T) {
+		s := runtime.NewScheme()
+		err := scheme.AddToScheme(s)
+		assert.NoError(t, err)
+
+		av, kind := componentsapi.SchemeGroupVersion.WithKind("Component").ToAPIVersionAndKind()
+		typeMeta := metav1.TypeMeta{
+			Kind:       kind,
+			APIVersion: av,
+		}
+		client := fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(&componentsapi.Component{
+				TypeMeta:   typeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "statestore", Namespace: "namespace-a"},
+				Spec:       componentsapi.ComponentSpec{Type: "state.redis"},
+			}, &componentsapi.Component{
+				TypeMeta:   typeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "statestore", Namespace: "shared"},
+				Spec:       componentsapi.ComponentSpec{Type: "state.postgresql"},
+			}, &componentsapi.Component{
+				TypeMeta:   typeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "pubsub", Namespace: "shared"},
+			}, &componentsapi.Component{
+				TypeMeta:   typeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "private", Namespace: "namespace-b"},
+			}).
+			Build()
+
+		api := NewAPIServer(client, nil, "shared").(*apiServer)
+
+		res, err := api.ListComponents(newPeerContext(t, "spiffe://public/ns/namespace-a/myapp"), &operatorv1pb.ListComponentsRequest{
+			PodName:   "foo",
+			Namespace: "namespace-a",
+		})
+		require.NoError(t, err)
+		require.Len(t, res.GetComponents(), 2)
+
+		components := map[string]componentsapi.Component{}
+		for _, b := range res.GetComponents() {
+			var c componentsapi.Component
+			require.NoError(t, yaml.Unmarshal(b, &c))
+			components[c.Name] = c
+			// The sidecars only load the components of their namespace
+			assert.Equal(t, "namespace-a", c.Namespace)
+		}
+		assert.Equal(t, "state.redis", components["statestore"].Spec.Type)
+		assert.Contains(t, components, "pubsub")
+	})
+	t.Run("list components of another namespace denied", func(t *testing.T) {
+		s := runtime.NewScheme()
+		err := scheme.AddToScheme(s)
+		assert.NoError(t, err)
+
+		client := fake.NewClientBuilder().WithScheme(s).Build()
+		api := NewAPIServer(client, nil, "").(*apiServer)
+
+		ctx := newPeerContext(t, "spiffe://public/ns/namespace-a/myapp")
+		_, err = api.ListComponents(ctx, &operatorv1pb.ListComponentsRequest{
+			PodName:   "foo",
+			Namespace: "namespace-b",
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = api.ListComponents(ctx, &operatorv1pb.ListComponentsRequest{
+			PodName:   "foo",
+			Namespace: "namespace-a",
+		})
+		assert.NoError(t, err)
+
+		// The callers without mTLS can't be identified
+		_, err = api.ListComponents(context.Background(), &operatorv1pb.ListComponentsRequest{
+			PodName:   "foo",
+			Namespace: "namespace-a",
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
 	t.Run("list subscriptions namespace scoping", func(t *testing.T) {
 		s := runtime.NewScheme()
 		err := scheme.AddToScheme(s)
@@ -347,7 +740,7 @@ func TestListsNamespaced(t *testing.T) {
 			}).
 			Build()
 
-		api := NewAPIServer(client).(*apiServer)
+		api := NewAPIServer(client, nil, "").(*apiServer)
 
 		res, err := api.ListSubscriptionsV2(context.TODO(), &operatorv1pb.ListSubscriptionsRequest{
 			PodName:   "foo",
@@ -401,7 +794,7 @@ func TestListsNamespaced(t *testing.T) {
 			}).
 			Build()
 
-		api := NewAPIServer(client).(*apiServer)
+		api := NewAPIServer(client, nil, "").(*apiServer)
 
 		res, err := api.ListResiliency(context.TODO(), &operatorv1pb.ListResiliencyRequest{
 			Namespace: "namespace-a",
@@ -424,3 +817,142 @@ func TestListsNamespaced(t *testing.T) {
 		assert.Equal(t, 0, len(res.GetResiliencies()))
 	})
 }
+
+func TestReportComponentStatus(t *testing.T) {
+	s := runtime.NewScheme()
+	err := scheme.AddToScheme(s)
+	assert.NoError(t, err)
+
+	client := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(&componentsapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "statestore",
+				Namespace: "ns1",
+			},
+		}).
+		Build()
+
+	t.Run("failed component emits a warning event", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		api := NewAPIServer(client, recorder, "").(*apiServer)
+
+		_, err := api.ReportComponentStatus(newPeerContext(t, "spiffe://public/ns/ns1/myapp"), &operatorv1pb.ReportComponentStatusRequest{
+			ComponentName: "statestore",
+			Namespace:     "ns1",
+			PodName:       "pod1",
+			Status:        "FAILED",
+			Error:         "NOAUTH Authentication required",
+			ErrorClass:    "CREDENTIALS",
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, recorder.Events, 1) {
+			event := <-recorder.Events
+			assert.Contains(t, event, "Warning InvalidCredentials")
+			assert.Contains(t, event, "pod1")
+			assert.Contains(t, event, "NOAUTH Authentication required")
+		}
+	})
+
+	t.Run("initialized component doesn't emit any event", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		api := NewAPIServer(client, recorder, "").(*apiServer)
+
+		_, err := api.ReportComponentStatus(newPeerContext(t, "spiffe://public/ns/ns1/myapp"), &operatorv1pb.ReportComponentStatusRequest{
+			ComponentName: "statestore",
+			Namespace:     "ns1",
+			Status:        "INITIALIZED",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, recorder.Events, 0)
+	})
+
+	t.Run("report of another namespace denied", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		api := NewAPIServer(client, recorder, "").(*apiServer)
+
+		_, err := api.ReportComponentStatus(newPeerContext(t, "spiffe://public/ns/ns2/myapp"), &operatorv1pb.ReportComponentStatusRequest{
+			ComponentName: "statestore",
+			Namespace:     "ns1",
+			Status:        "FAILED",
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Len(t, recorder.Events, 0)
+	})
+
+	t.Run("unknown component", func(t *testing.T) {
+		api := NewAPIServer(client, record.NewFakeRecorder(10), "").(*apiServer)
+
+		_, err := api.ReportComponentStatus(newPeerContext(t, "spiffe://public/ns/ns1/myapp"), &operatorv1pb.ReportComponentStatusRequest{
+			ComponentName: "notfound",
+			Namespace:     "ns1",
+			Status:        "FAILED",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestReportDrift(t *testing.T) {
+	s := runtime.NewScheme()
+	err := scheme.AddToScheme(s)
+	assert.NoError(t, err)
+
+	client := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(&componentsapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "statestore",
+				Namespace: "ns1",
+			},
+		}, &configurationapi.Configuration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "appconfig",
+				Namespace: "ns1",
+			},
+		}).
+		Build()
+
+	t.Run("out of date resources emit warning events", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		api := NewAPIServer(client, recorder, "").(*apiServer)
+
+		_, err := api.ReportDrift(newPeerContext(t, "spiffe://public/ns/ns1/myapp"), &operatorv1pb.ReportDriftRequest{
+			Namespace: "ns1",
+			PodName:   "pod1",
+			// Deleted components are skipped
+			Components:    []string{"statestore", "deleted"},
+			Configuration: "appconfig",
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, recorder.Events, 2) {
+			event := <-recorder.Events
+			assert.Contains(t, event, "Warning ConfigurationDrift")
+			assert.Contains(t, event, "pod1")
+			assert.Contains(t, event, "component")
+			assert.Contains(t, <-recorder.Events, "configuration")
+		}
+	})
+
+	t.Run("report of another namespace denied", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		api := NewAPIServer(client, recorder, "").(*apiServer)
+
+		_, err := api.ReportDrift(newPeerContext(t, "spiffe://public/ns/ns2/myapp"), &operatorv1pb.ReportDriftRequest{
+			Namespace:     "ns1",
+			Components:    []string{"statestore"},
+			Configuration: "appconfig",
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Len(t, recorder.Events, 0)
+	})
+
+	t.Run("unknown configuration", func(t *testing.T) {
+		api := NewAPIServer(client, record.NewFakeRecorder(10), "").(*apiServer)
+
+		_, err := api.ReportDrift(newPeerContext(t, "spiffe://public/ns/ns1/myapp"), &operatorv1pb.ReportDriftRequest{
+			Namespace:     "ns1",
+			Configuration: "notfound",
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,136 @@
+package operator
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dapr/dapr/pkg/backup"
+	"github.com/dapr/dapr/pkg/credentials"
+)
+
+const (
+	// Name of the secret with the encrypted backup bundle of the control plane.
+	backupSecretName = "dapr-control-plane-backup"
+	// Key of the encrypted backup bundle in the secret.
+	backupSecretKey = "bundle"
+
+	backupRequestTimeout = 30 * time.Second
+)
+
+// BackupController is a controller that periodically exports the state of the placement and Sentry services
+// with their admin APIs, and stores it in an encrypted bundle in a secret in the namespace of the operator.
+// The bundle can be imported in the services with their restore admin API.
+// This controller only runs on the cluster's leader.
+type BackupController struct {
+	interval  time.Duration
+	key       []byte
+	targets   []string
+	namespace string
+
+	client client.Client
+	// credentials are the cert chain of the operator, used to authenticate with the admin APIs.
+	credentials credentials.TLSCredentials
+	// httpClient is the client of the admin APIs, created from the credentials for each backup if nil.
+	httpClient *http.Client
+}
+
+// NeedLeaderElection makes it so the controller runs on the leader node only.
+// Implements sigs.k8s.io/controller-runtime/pkg/manager.LeaderElectionRunnable .
+func (bc *BackupController) NeedLeaderElection() bool {
+	return true
+}
+
+// Start the controller. This method blocks until the context is canceled.
+// Implements sigs.k8s.io/controller-runtime/pkg/manager.Runnable .
+func (bc *BackupController) Start(ctx context.Context) error {
+	if bc.interval <= 0 || len(bc.targets) == 0 {
+		log.Infof("BackupController is not enabled")
+		return nil
+	}
+
+	log.Infof("BackupController worker started, backing up %v every %s", bc.targets, bc.interval)
+
+	t := time.NewTicker(bc.interval)
+	defer t.Stop()
+
+	for {
+		if err := bc.backup(ctx); err != nil {
+			log.Errorf("Failed to back up the control plane state. Error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Infof("BackupController worker stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+func (bc *BackupController) backup(ctx context.Context) error {
+	httpClient, err := bc.adminClient()
+	if err != nil {
+		return err
+	}
+
+	b := backup.NewBundle()
+	for _, target := range bc.targets {
+		fetchCtx, cancel := context.WithTimeout(ctx, backupRequestTimeout)
+		part, err := backup.Fetch(fetchCtx, httpClient, target, bc.key)
+		cancel()
+		if err != nil {
+			return err
+		}
+		b.Merge(part)
+	}
+
+	data, err := backup.Seal(b, bc.key)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	err = bc.client.Get(ctx, client.ObjectKey{Namespace: bc.namespace, Name: backupSecretName}, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      backupSecretName,
+				Namespace: bc.namespace,
+			},
+			Data: map[string][]byte{backupSecretKey: data},
+		}
+		err = bc.client.Create(ctx, secret)
+	} else if err == nil {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[backupSecretKey] = data
+		err = bc.client.Update(ctx, secret)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to store backup bundle")
+	}
+
+	log.Infof("BackupController stored the control plane state in secret %s/%s", bc.namespace, backupSecretName)
+	return nil
+}
+
+// adminClient returns the client of the admin APIs, authenticated with the current cert chain of the operator,
+// which is loaded from disk for each backup so that the rotated certificates are used.
+func (bc *BackupController) adminClient() (*http.Client, error) {
+	if bc.httpClient != nil {
+		return bc.httpClient, nil
+	}
+	chain, err := credentials.LoadFromDisk(bc.credentials.RootCertPath(), bc.credentials.CertPath(), bc.credentials.KeyPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the cert chain for the admin APIs")
+	}
+	return backup.NewClient(chain, backupRequestTimeout)
+}
@@ -0,0 +1,111 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/dapr/dapr/pkg/backup"
+	"github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/runtime/security"
+	daprt "github.com/dapr/dapr/pkg/testing"
+	"github.com/dapr/kit/logger"
+)
+
+type testBackupSource struct {
+	placement []byte
+	sentry    *backup.IssuerMaterial
+}
+
+func (s *testBackupSource) Backup(b *backup.Bundle) error {
+	b.Placement = s.placement
+	b.Sentry = s.sentry
+	return nil
+}
+
+func (s *testBackupSource) Restore(b *backup.Bundle) error {
+	return nil
+}
+
+func TestBackupController(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	log := logger.NewLogger("dapr.test")
+
+	controlPlane, _ := daprt.GenerateCertChains(t, security.TLSServerName, "spiffe://cluster.local/ns/default/app")
+	certs, err := credentials.NewCertChainReloader(credentials.NewTLSCredentials(t.TempDir()), controlPlane)
+	require.NoError(t, err)
+	newAdminServer := func(source backup.Source) *httptest.Server {
+		srv := httptest.NewUnstartedServer(backup.NewServer(source, key, log).Handler())
+		srv.TLS = certs.TLSConfig()
+		srv.StartTLS()
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	placementSrv := newAdminServer(&testBackupSource{placement: []byte("placement")})
+	sentrySrv := newAdminServer(&testBackupSource{sentry: &backup.IssuerMaterial{RootCert: []byte("root")}})
+
+	// The operator authenticates with the cert chain on disk
+	credsDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(credsDir, credentials.RootCertFilename), controlPlane.RootCA, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(credsDir, credentials.IssuerCertFilename), controlPlane.Cert, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(credsDir, credentials.IssuerKeyFilename), controlPlane.Key, 0o600))
+
+	bc := &BackupController{
+		key:         key,
+		targets:     []string{placementSrv.URL, sentrySrv.URL},
+		namespace:   "dapr-system",
+		client:      fake.NewClientBuilder().Build(),
+		credentials: credentials.NewTLSCredentials(credsDir),
+	}
+
+	getBundle := func(t *testing.T) *backup.Bundle {
+		secret := &corev1.Secret{}
+		require.NoError(t, bc.client.Get(context.Background(), client.ObjectKey{Namespace: "dapr-system", Name: backupSecretName}, secret))
+		b, err := backup.Open(secret.Data[backupSecretKey], key)
+		require.NoError(t, err)
+		return b
+	}
+
+	t.Run("creates the secret", func(t *testing.T) {
+		require.NoError(t, bc.backup(context.Background()))
+
+		b := getBundle(t)
+		assert.Equal(t, []byte("placement"), b.Placement)
+		assert.Equal(t, []byte("root"), b.Sentry.RootCert)
+	})
+
+	t.Run("updates the secret", func(t *testing.T) {
+		first := getBundle(t)
+		require.NoError(t, bc.backup(context.Background()))
+
+		b := getBundle(t)
+		assert.False(t, b.CreatedAt.Before(first.CreatedAt))
+		assert.Equal(t, []byte("placement"), b.Placement)
+	})
+
+	t.Run("fails if a target is unreachable", func(t *testing.T) {
+		failing := *bc
+		failing.targets = []string{placementSrv.URL, "https://127.0.0.1:1"}
+		assert.Error(t, failing.backup(context.Background()))
+	})
+
+	t.Run("fails without the cert chain", func(t *testing.T) {
+		failing := *bc
+		failing.credentials = credentials.NewTLSCredentials(t.TempDir())
+		assert.ErrorContains(t, failing.backup(context.Background()), "cert chain")
+	})
+}
+
+func TestBackupControllerDisabled(t *testing.T) {
+	bc := &BackupController{}
+	assert.NoError(t, bc.Start(context.Background()))
+}
@@ -0,0 +1,109 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// crdMigrationRetryInterval is the interval of the retries of the migration of the stored objects,
+// which fails until the conversion webhook is served.
+const crdMigrationRetryInterval = 10 * time.Second
+
+// migrateCRDs migrates the stored objects of the CRDs to their storage version, retrying until it succeeds.
+func migrateCRDs(ctx context.Context, conf *rest.Config, crdNames ...string) {
+	clientSet, err := apiextensionsclient.NewForConfig(conf)
+	if err != nil {
+		log.Errorf("Could not get API extension client: %v", err)
+
+		return
+	}
+	dynamicClient, err := dynamic.NewForConfig(conf)
+	if err != nil {
+		log.Errorf("Could not get dynamic client: %v", err)
+
+		return
+	}
+
+	crdClient := clientSet.ApiextensionsV1().CustomResourceDefinitions()
+	for _, crdName := range crdNames {
+		crdName := crdName
+		_ = wait.PollImmediateUntil(crdMigrationRetryInterval, func() (bool, error) {
+			if err := migrateStoredVersions(ctx, crdClient, dynamicClient, crdName); err != nil {
+				log.Warnf("Failed to migrate the stored objects of CRD %q, retrying: %v", crdName, err)
+
+				return false, nil
+			}
+
+			return true, nil
+		}, ctx.Done())
+	}
+}
+
+// migrateStoredVersions rewrites all the objects of a CRD, so that the API server stores them in the storage version,
+// then removes the other versions from the stored versions of the CRD.
+// Once the old versions are no longer stored, they can be removed from the CRD without losing objects.
+func migrateStoredVersions(ctx context.Context, crdClient apiextensionsv1client.CustomResourceDefinitionInterface, dynamicClient dynamic.Interface, crdName string) error {
+	crd, err := crdClient.Get(ctx, crdName, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("could not get CRD: %w", err)
+	}
+
+	storageVersion := crdStorageVersion(crd)
+	if storageVersion == "" {
+		return fmt.Errorf("CRD has no storage version")
+	}
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		log.Infof("Stored objects of CRD %q are up to date", crdName)
+
+		return nil
+	}
+
+	resource := dynamicClient.Resource(schema.GroupVersionResource{
+		Group:    crd.Spec.Group,
+		Version:  storageVersion,
+		Resource: crd.Spec.Names.Plural,
+	})
+	list, err := resource.List(ctx, v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("could not list objects: %w", err)
+	}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		// An update without changes is enough for the API server to store the object in the storage version.
+		_, err = resource.Namespace(obj.GetNamespace()).Update(ctx, obj, v1.UpdateOptions{})
+		// The objects updated or deleted concurrently are already stored in the storage version.
+		if err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("could not migrate %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+
+	crd.Status.StoredVersions = []string{storageVersion}
+	if _, err = crdClient.UpdateStatus(ctx, crd, v1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("could not update the stored versions: %w", err)
+	}
+
+	log.Infof("Successfully migrated %d objects of CRD %q to version %s", len(list.Items), crdName, storageVersion)
+
+	return nil
+}
+
+func crdStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,151 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newSubscriptionCRD(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "subscriptions.dapr.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "dapr.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "subscriptions", Kind: "Subscription"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v2alpha1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
+
+func newSubscription(name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("dapr.io/v2alpha1")
+	obj.SetKind("Subscription")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	return obj
+}
+
+func TestMigrateStoredVersions(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "dapr.io", Version: "v2alpha1", Resource: "subscriptions"}
+
+	t.Run("objects rewritten in the storage version", func(t *testing.T) {
+		crdClient := apiextensionsfake.NewSimpleClientset(newSubscriptionCRD("v1alpha1", "v2alpha1"))
+		dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr: "SubscriptionList"},
+			newSubscription("orders"), newSubscription("payments"))
+
+		crds := crdClient.ApiextensionsV1().CustomResourceDefinitions()
+		require.NoError(t, migrateStoredVersions(context.Background(), crds, dynamicClient, "subscriptions.dapr.io"))
+
+		updated := 0
+		for _, action := range dynamicClient.Actions() {
+			if action.GetVerb() == "update" {
+				updated++
+			}
+		}
+		assert.Equal(t, 2, updated)
+
+		crd, err := crds.Get(context.Background(), "subscriptions.dapr.io", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v2alpha1"}, crd.Status.StoredVersions)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		crdClient := apiextensionsfake.NewSimpleClientset(newSubscriptionCRD("v2alpha1"))
+		dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr: "SubscriptionList"})
+
+		crds := crdClient.ApiextensionsV1().CustomResourceDefinitions()
+		require.NoError(t, migrateStoredVersions(context.Background(), crds, dynamicClient, "subscriptions.dapr.io"))
+		assert.Empty(t, dynamicClient.Actions())
+	})
+
+	t.Run("stored versions kept when the objects can't be listed", func(t *testing.T) {
+		crdClient := apiextensionsfake.NewSimpleClientset(newSubscriptionCRD("v1alpha1", "v2alpha1"))
+		dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr: "SubscriptionList"})
+		dynamicClient.PrependReactor("list", "subscriptions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+
+		crds := crdClient.ApiextensionsV1().CustomResourceDefinitions()
+		assert.Error(t, migrateStoredVersions(context.Background(), crds, dynamicClient, "subscriptions.dapr.io"))
+
+		crd, err := crds.Get(context.Background(), "subscriptions.dapr.io", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1alpha1", "v2alpha1"}, crd.Status.StoredVersions)
+	})
+}
+
+func TestPatchCRDConversion(t *testing.T) {
+	caBundle := []byte("ca")
+
+	t.Run("conversion added to a CRD installed without one", func(t *testing.T) {
+		crdClient := apiextensionsfake.NewSimpleClientset(newSubscriptionCRD("v2alpha1"))
+		crds := crdClient.ApiextensionsV1().CustomResourceDefinitions()
+		require.NoError(t, patchCRDConversion(context.Background(), crds, "subscriptions.dapr.io", "dapr-system", caBundle))
+
+		crd, err := crds.Get(context.Background(), "subscriptions.dapr.io", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, crd.Spec.Conversion)
+		assert.Equal(t, apiextensionsv1.WebhookConverter, crd.Spec.Conversion.Strategy)
+		service := crd.Spec.Conversion.Webhook.ClientConfig.Service
+		assert.Equal(t, "dapr-system", service.Namespace)
+		assert.Equal(t, webhookServiceName, service.Name)
+		assert.Equal(t, conversionWebhookPath, *service.Path)
+		assert.Equal(t, caBundle, crd.Spec.Conversion.Webhook.ClientConfig.CABundle)
+		assert.Equal(t, []string{"v1"}, crd.Spec.Conversion.Webhook.ConversionReviewVersions)
+	})
+
+	t.Run("existing conversion updated", func(t *testing.T) {
+		path := "/custom"
+		crd := newSubscriptionCRD("v2alpha1")
+		crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+			Strategy: apiextensionsv1.WebhookConverter,
+			Webhook: &apiextensionsv1.WebhookConversion{
+				ClientConfig: &apiextensionsv1.WebhookClientConfig{
+					Service: &apiextensionsv1.ServiceReference{Namespace: "replaceme", Name: "my-webhook", Path: &path},
+				},
+				ConversionReviewVersions: []string{"v1", "v2alpha1"},
+			},
+		}
+		crdClient := apiextensionsfake.NewSimpleClientset(crd)
+		crds := crdClient.ApiextensionsV1().CustomResourceDefinitions()
+		require.NoError(t, patchCRDConversion(context.Background(), crds, "subscriptions.dapr.io", "dapr-system", caBundle))
+
+		crd, err := crds.Get(context.Background(), "subscriptions.dapr.io", metav1.GetOptions{})
+		require.NoError(t, err)
+		service := crd.Spec.Conversion.Webhook.ClientConfig.Service
+		assert.Equal(t, "dapr-system", service.Namespace)
+		assert.Equal(t, "my-webhook", service.Name)
+		assert.Equal(t, "/custom", *service.Path)
+		assert.Equal(t, []string{"v1", "v2alpha1"}, crd.Spec.Conversion.Webhook.ConversionReviewVersions)
+	})
+
+	t.Run("single version CRD not patched", func(t *testing.T) {
+		crd := newSubscriptionCRD("v2alpha1")
+		crd.Spec.Versions = crd.Spec.Versions[1:]
+		crdClient := apiextensionsfake.NewSimpleClientset(crd)
+		crds := crdClient.ApiextensionsV1().CustomResourceDefinitions()
+		require.NoError(t, patchCRDConversion(context.Background(), crds, "subscriptions.dapr.io", "dapr-system", caBundle))
+
+		for _, action := range crdClient.Actions() {
+			assert.NotEqual(t, "patch", action.GetVerb())
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dapr/dapr/pkg/operator/monitoring"
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+const (
+	// Suffix of the names of the services created for the apps with the Dapr sidecar.
+	daprServiceSuffix = "-dapr"
+
+	// Reasons of the events emitted on the resources to be deleted.
+	orphanedServiceEventReason = "DaprServiceOrphaned"
+	staleSecretEventReason     = "DaprSecretExpired"
+)
+
+var (
+	// Keys of the secrets with the certificates checked for expiration.
+	gcCertificateKeys = []string{corev1.TLSCertKey, "ca.crt", "issuer.crt", "caBundle"}
+
+	// Names of the secrets with the certificates of the control plane, in the namespace of the control plane.
+	// The trust bundle isn't one of them, as it's never removed.
+	gcSecretNames = []string{"dapr-webhook-cert", "dapr-webhook-ca", "dapr-sidecar-injector-cert"}
+)
+
+// GarbageCollector is a controller that periodically removes the resources leaked by the control plane:
+//   - the "-dapr" services whose deployment or statefulset was deleted;
+//   - the secrets of the control plane whose certificates expired longer than the grace period ago, which are created
+//     again with new certificates on the next upgrade of the control plane.
+//
+// The trust bundle is never removed. In dry-run mode, the resources are only reported with an event and a metric.
+// This controller only runs on the cluster's leader.
+type GarbageCollector struct {
+	interval          time.Duration
+	secretGracePeriod time.Duration
+	dryRun            bool
+	// Namespace of the control plane, whose secrets are collected.
+	namespace string
+
+	client   client.Client
+	recorder record.EventRecorder
+}
+
+// NeedLeaderElection makes it so the controller runs on the leader node only.
+// Implements sigs.k8s.io/controller-runtime/pkg/manager.LeaderElectionRunnable .
+func (gc *GarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start the controller. This method blocks until the context is canceled.
+// Implements sigs.k8s.io/controller-runtime/pkg/manager.Runnable .
+func (gc *GarbageCollector) Start(ctx context.Context) error {
+	if gc.interval <= 0 {
+		log.Infof("GarbageCollector is not enabled")
+		return nil
+	}
+
+	log.Infof("GarbageCollector worker started, collecting every %s", gc.interval)
+
+	t := time.NewTicker(gc.interval)
+	defer t.Stop()
+
+	for {
+		gc.collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Infof("GarbageCollector worker stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+func (gc *GarbageCollector) collect(ctx context.Context) {
+	if err := gc.collectServices(ctx); err != nil {
+		log.Errorf("Failed to collect the orphaned Dapr services. Error: %v", err)
+	}
+	if err := gc.collectSecrets(ctx, time.Now()); err != nil {
+		log.Errorf("Failed to collect the expired secrets. Error: %v", err)
+	}
+}
+
+func (gc *GarbageCollector) collectServices(ctx context.Context) error {
+	services := &corev1.ServiceList{}
+	err := gc.client.List(ctx, services, client.MatchingLabels{daprEnabledAnnotationKey: "true"})
+	if err != nil {
+		return err
+	}
+
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if !strings.HasSuffix(svc.Name, daprServiceSuffix) {
+			continue
+		}
+		orphaned, err := gc.isOrphaned(ctx, svc)
+		if err != nil {
+			log.Errorf("Failed to get the owner of service %s/%s. Error: %v", svc.Namespace, svc.Name, err)
+			continue
+		}
+		if !orphaned {
+			continue
+		}
+
+		gc.delete(ctx, svc, "service", orphanedServiceEventReason, "Service of a deleted app")
+	}
+	return nil
+}
+
+// isOrphaned returns true if the service is controlled by a deployment or a statefulset that doesn't exist anymore.
+// The services without such an owner weren't created by the operator and are kept.
+func (gc *GarbageCollector) isOrphaned(ctx context.Context, svc *corev1.Service) (bool, error) {
+	owner := metav1.GetControllerOf(svc)
+	if owner == nil || owner.APIVersion != appsv1.SchemeGroupVersion.String() {
+		return false, nil
+	}
+
+	var obj client.Object
+	switch owner.Kind {
+	case "Deployment":
+		obj = &appsv1.Deployment{}
+	case "StatefulSet":
+		obj = &appsv1.StatefulSet{}
+	default:
+		return false, nil
+	}
+
+	err := gc.client.Get(ctx, types.NamespacedName{Namespace: svc.Namespace, Name: owner.Name}, obj)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// A new owner with the same name isn't the owner of the service.
+	return obj.GetUID() != owner.UID, nil
+}
+
+func (gc *GarbageCollector) collectSecrets(ctx context.Context, now time.Time) error {
+	for _, name := range gcSecretNames {
+		secret := &corev1.Secret{}
+		err := gc.client.Get(ctx, types.NamespacedName{Namespace: gc.namespace, Name: name}, secret)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !isExpiredSecret(secret, now.Add(-gc.secretGracePeriod)) {
+			continue
+		}
+
+		gc.delete(ctx, secret, "secret", staleSecretEventReason, "Secret with expired certificates")
+	}
+	return nil
+}
+
+// isExpiredSecret returns true if the secret has certificates, all expired before the given time.
+func isExpiredSecret(secret *corev1.Secret, before time.Time) bool {
+	found := false
+	for _, key := range gcCertificateKeys {
+		data, ok := secret.Data[key]
+		if !ok {
+			continue
+		}
+		crts, err := certs.DecodePEMCertificates(data)
+		if err != nil {
+			// Secrets that can't be parsed are kept.
+			return false
+		}
+		for _, crt := range crts {
+			if crt.NotAfter.After(before) {
+				return false
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// delete removes the object, or only reports it in dry-run mode.
+func (gc *GarbageCollector) delete(ctx context.Context, obj client.Object, kind, reason, message string) {
+	logName := obj.GetNamespace() + "/" + obj.GetName()
+	monitoring.RecordGCOrphanedResourceCount(kind)
+
+	if gc.dryRun {
+		log.Warnf("Found %s %s to collect; not deleting it because dry-run mode is enabled", kind, logName)
+		gc.recordEvent(obj, reason, message)
+		return
+	}
+
+	log.Infof("Deleting %s %s", kind, logName)
+	gc.recordEvent(obj, reason, message+" will be deleted")
+	err := gc.client.Delete(ctx, obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Errorf("Failed to delete %s %s. Error: %v", kind, logName, err)
+		return
+	}
+	monitoring.RecordGCResourceDeletedCount(kind)
+}
+
+// recordEvent emits a warning event on the object, if an event recorder is set.
+func (gc *GarbageCollector) recordEvent(obj client.Object, reason, message string) {
+	if gc.recorder == nil {
+		return
+	}
+	gc.recorder.Event(obj, corev1.EventTypeWarning, reason, message)
+}
@@ -0,0 +1,176 @@
+package operator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+func newGarbageCollector(t *testing.T, dryRun bool, objs ...client.Object) *GarbageCollector {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	return &GarbageCollector{
+		interval:          time.Hour,
+		secretGracePeriod: time.Hour,
+		dryRun:            dryRun,
+		namespace:         "dapr-system",
+		client:            fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+		recorder:          record.NewFakeRecorder(10),
+	}
+}
+
+func newDaprService(name string, owner *metav1.OwnerReference) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{daprEnabledAnnotationKey: "true"},
+		},
+	}
+	if owner != nil {
+		svc.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return svc
+}
+
+func deploymentOwner(name string, uid types.UID) *metav1.OwnerReference {
+	controller := true
+	return &metav1.OwnerReference{
+		APIVersion: appsv1.SchemeGroupVersion.String(),
+		Kind:       "Deployment",
+		Name:       name,
+		UID:        uid,
+		Controller: &controller,
+	}
+}
+
+func newCertificateSecret(t *testing.T, name string, notAfter time.Time) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "dapr-system",
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		},
+	}
+}
+
+func exists(t *testing.T, c client.Client, obj client.Object) bool {
+	err := c.Get(context.Background(), client.ObjectKeyFromObject(obj), obj)
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestGarbageCollectorServices(t *testing.T) {
+	deployment := newDeployment("app", nil)
+	deployment.UID = "uid-app"
+	live := newDaprService("app-dapr", deploymentOwner("app", "uid-app"))
+	orphaned := newDaprService("deleted-dapr", deploymentOwner("deleted", "uid-deleted"))
+	replaced := newDaprService("other-dapr", deploymentOwner("app", "uid-old"))
+	unowned := newDaprService("manual-dapr", nil)
+	unrelated := newDaprService("unrelated", deploymentOwner("deleted", "uid-deleted"))
+	objs := func() []client.Object {
+		return []client.Object{deployment.DeepCopy(), live.DeepCopy(), orphaned.DeepCopy(), replaced.DeepCopy(), unowned.DeepCopy(), unrelated.DeepCopy()}
+	}
+
+	t.Run("delete the orphaned services", func(t *testing.T) {
+		gc := newGarbageCollector(t, false, objs()...)
+		require.NoError(t, gc.collectServices(context.Background()))
+
+		assert.True(t, exists(t, gc.client, live.DeepCopy()))
+		assert.False(t, exists(t, gc.client, orphaned.DeepCopy()))
+		assert.False(t, exists(t, gc.client, replaced.DeepCopy()))
+		assert.True(t, exists(t, gc.client, unowned.DeepCopy()))
+		assert.True(t, exists(t, gc.client, unrelated.DeepCopy()))
+	})
+
+	t.Run("dry-run", func(t *testing.T) {
+		gc := newGarbageCollector(t, true, objs()...)
+		require.NoError(t, gc.collectServices(context.Background()))
+
+		assert.True(t, exists(t, gc.client, orphaned.DeepCopy()))
+		assert.True(t, exists(t, gc.client, replaced.DeepCopy()))
+		assert.Len(t, gc.recorder.(*record.FakeRecorder).Events, 2)
+	})
+}
+
+func TestGarbageCollectorSecrets(t *testing.T) {
+	now := time.Now()
+	expired := newCertificateSecret(t, "dapr-webhook-cert", now.Add(-2*time.Hour))
+	expiredCA := newCertificateSecret(t, "dapr-webhook-ca", now.Add(-2*time.Hour))
+	expiredCA.Data = map[string][]byte{"caBundle": expiredCA.Data[corev1.TLSCertKey]}
+	inGracePeriod := newCertificateSecret(t, "dapr-sidecar-injector-cert", now.Add(-30*time.Minute))
+	trustBundle := newCertificateSecret(t, certs.KubeScrtName, now.Add(-2*time.Hour))
+	unrelated := newCertificateSecret(t, "app-cert", now.Add(-2*time.Hour))
+	otherNamespace := newCertificateSecret(t, "dapr-webhook-cert", now.Add(-2*time.Hour))
+	otherNamespace.Namespace = "default"
+	objs := func() []client.Object {
+		return []client.Object{expired.DeepCopy(), expiredCA.DeepCopy(), inGracePeriod.DeepCopy(), trustBundle.DeepCopy(), unrelated.DeepCopy(), otherNamespace.DeepCopy()}
+	}
+
+	t.Run("delete the expired secrets of the control plane", func(t *testing.T) {
+		gc := newGarbageCollector(t, false, objs()...)
+		require.NoError(t, gc.collectSecrets(context.Background(), now))
+
+		assert.False(t, exists(t, gc.client, expired.DeepCopy()))
+		assert.False(t, exists(t, gc.client, expiredCA.DeepCopy()))
+		assert.True(t, exists(t, gc.client, inGracePeriod.DeepCopy()))
+		assert.True(t, exists(t, gc.client, trustBundle.DeepCopy()))
+		assert.True(t, exists(t, gc.client, unrelated.DeepCopy()))
+		assert.True(t, exists(t, gc.client, otherNamespace.DeepCopy()))
+	})
+
+	t.Run("valid secrets are kept", func(t *testing.T) {
+		gc := newGarbageCollector(t, false, objs()...)
+		require.NoError(t, gc.collectSecrets(context.Background(), now.Add(-3*time.Hour)))
+
+		assert.True(t, exists(t, gc.client, expired.DeepCopy()))
+		assert.True(t, exists(t, gc.client, expiredCA.DeepCopy()))
+	})
+
+	t.Run("dry-run", func(t *testing.T) {
+		gc := newGarbageCollector(t, true, objs()...)
+		require.NoError(t, gc.collectSecrets(context.Background(), now))
+
+		assert.True(t, exists(t, gc.client, expired.DeepCopy()))
+		assert.True(t, exists(t, gc.client, expiredCA.DeepCopy()))
+		assert.Len(t, gc.recorder.(*record.FakeRecorder).Events, 2)
+	})
+}
@@ -25,6 +25,7 @@ import (
 
 const (
 	appID = "app_id"
+	kind  = "kind"
 )
 
 var (
@@ -40,24 +41,71 @@ var (
 		"operator/service_updated_total",
 		"The total number of dapr services updated.",
 		stats.UnitDimensionless)
+	watchdogSidecarMissingTotal = stats.Int64(
+		"operator/watchdog_sidecar_missing_total",
+		"The total number of pods found by the watchdog without the dapr sidecar.",
+		stats.UnitDimensionless)
+	watchdogPodDeletedTotal = stats.Int64(
+		"operator/watchdog_pod_deleted_total",
+		"The total number of pods without the dapr sidecar deleted by the watchdog.",
+		stats.UnitDimensionless)
+	deploymentRestartedTotal = stats.Int64(
+		"operator/deployment_restarted_total",
+		"The total number of deployments restarted to apply breaking changes of configurations and components.",
+		stats.UnitDimensionless)
+	gcOrphanedResourceTotal = stats.Int64(
+		"operator/gc_orphaned_resource_total",
+		"The total number of orphaned services and expired secrets found by the garbage collector.",
+		stats.UnitDimensionless)
+	gcResourceDeletedTotal = stats.Int64(
+		"operator/gc_resource_deleted_total",
+		"The total number of orphaned services and expired secrets deleted by the garbage collector.",
+		stats.UnitDimensionless)
 
 	// appIDKey is a tag key for App ID.
 	appIDKey = tag.MustNewKey(appID)
+	// kindKey is a tag key for the kind of resource.
+	kindKey = tag.MustNewKey(kind)
 )
 
 // RecordServiceCreatedCount records the number of dapr service created.
 func RecordServiceCreatedCount(appID string) {
-	stats.RecordWithTags(context.Background(), diagUtils.WithTags(appIDKey, appID), serviceCreatedTotal.M(1))
+	stats.RecordWithTags(context.Background(), diagUtils.WithTags(serviceCreatedTotal.Name(), appIDKey, appID), serviceCreatedTotal.M(1))
 }
 
 // RecordServiceDeletedCount records the number of dapr service deleted.
 func RecordServiceDeletedCount(appID string) {
-	stats.RecordWithTags(context.Background(), diagUtils.WithTags(appIDKey, appID), serviceDeletedTotal.M(1))
+	stats.RecordWithTags(context.Background(), diagUtils.WithTags(serviceDeletedTotal.Name(), appIDKey, appID), serviceDeletedTotal.M(1))
 }
 
 // RecordServiceUpdatedCount records the number of dapr service updated.
 func RecordServiceUpdatedCount(appID string) {
-	stats.RecordWithTags(context.Background(), diagUtils.WithTags(appIDKey, appID), serviceUpdatedTotal.M(1))
+	stats.RecordWithTags(context.Background(), diagUtils.WithTags(serviceUpdatedTotal.Name(), appIDKey, appID), serviceUpdatedTotal.M(1))
+}
+
+// RecordWatchdogSidecarMissingCount records the number of pods found without the dapr sidecar.
+func RecordWatchdogSidecarMissingCount(appID string) {
+	stats.RecordWithTags(context.Background(), diagUtils.WithTags(watchdogSidecarMissingTotal.Name(), appIDKey, appID), watchdogSidecarMissingTotal.M(1))
+}
+
+// RecordWatchdogPodDeletedCount records the number of pods without the dapr sidecar deleted.
+func RecordWatchdogPodDeletedCount(appID string) {
+	stats.RecordWithTags(context.Background(), diagUtils.WithTags(watchdogPodDeletedTotal.Name(), appIDKey, appID), watchdogPodDeletedTotal.M(1))
+}
+
+// RecordDeploymentRestartedCount records the number of deployments restarted to apply breaking changes.
+func RecordDeploymentRestartedCount(appID string) {
+	stats.RecordWithTags(context.Background(), diagUtils.WithTags(deploymentRestartedTotal.Name(), appIDKey, appID), deploymentRestartedTotal.M(1))
+}
+
+// RecordGCOrphanedResourceCount records the number of resources found by the garbage collector.
+func RecordGCOrphanedResourceCount(kind string) {
+	stats.RecordWithTags(context.Background(), diagUtils.WithTags(gcOrphanedResourceTotal.Name(), kindKey, kind), gcOrphanedResourceTotal.M(1))
+}
+
+// RecordGCResourceDeletedCount records the number of resources deleted by the garbage collector.
+func RecordGCResourceDeletedCount(kind string) {
+	stats.RecordWithTags(context.Background(), diagUtils.WithTags(gcResourceDeletedTotal.Name(), kindKey, kind), gcResourceDeletedTotal.M(1))
 }
 
 // InitMetrics initialize the operator service metrics.
@@ -66,6 +114,11 @@ func InitMetrics() error {
 		diagUtils.NewMeasureView(serviceCreatedTotal, []tag.Key{appIDKey}, view.Count()),
 		diagUtils.NewMeasureView(serviceDeletedTotal, []tag.Key{appIDKey}, view.Count()),
 		diagUtils.NewMeasureView(serviceUpdatedTotal, []tag.Key{appIDKey}, view.Count()),
+		diagUtils.NewMeasureView(watchdogSidecarMissingTotal, []tag.Key{appIDKey}, view.Count()),
+		diagUtils.NewMeasureView(watchdogPodDeletedTotal, []tag.Key{appIDKey}, view.Count()),
+		diagUtils.NewMeasureView(deploymentRestartedTotal, []tag.Key{appIDKey}, view.Count()),
+		diagUtils.NewMeasureView(gcOrphanedResourceTotal, []tag.Key{kindKey}, view.Count()),
+		diagUtils.NewMeasureView(gcResourceDeletedTotal, []tag.Key{kindKey}, view.Count()),
 	)
 
 	return err
@@ -34,6 +34,7 @@ import (
 	"github.com/dapr/dapr/pkg/health"
 	"github.com/dapr/dapr/pkg/operator/api"
 	"github.com/dapr/dapr/pkg/operator/handlers"
+	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
 	"github.com/dapr/kit/logger"
 )
 
@@ -56,6 +57,17 @@ type Options struct {
 	WatchdogEnabled           bool
 	WatchdogInterval          time.Duration
 	WatchdogMaxRestartsPerMin int
+	WatchdogDryRun            bool
+	BackupInterval            time.Duration
+	BackupKey                 []byte
+	BackupTargets             []string
+	RestartEnabled            bool
+	RestartWindow             *MaintenanceWindow
+	GCInterval                time.Duration
+	GCSecretGracePeriod       time.Duration
+	GCDryRun                  bool
+	WorkloadsAPIPort          int
+	SharedComponentsNamespace string
 }
 
 type operator struct {
@@ -104,12 +116,60 @@ func NewOperator(opts Options) Operator {
 		enabled:           opts.WatchdogEnabled,
 		interval:          opts.WatchdogInterval,
 		maxRestartsPerMin: opts.WatchdogMaxRestartsPerMin,
+		dryRun:            opts.WatchdogDryRun,
+		recorder:          mgr.GetEventRecorderFor("dapr-watchdog"),
 	}
 	err = mgr.Add(wd)
 	if err != nil {
 		log.Fatalf("unable to add watchdog controller, err: %s", err)
 	}
 
+	bc := &BackupController{
+		client:      mgrClient,
+		interval:    opts.BackupInterval,
+		key:         opts.BackupKey,
+		targets:     opts.BackupTargets,
+		namespace:   GetNamespace(),
+		credentials: credentials.NewTLSCredentials(opts.CertChainPath),
+	}
+	err = mgr.Add(bc)
+	if err != nil {
+		log.Fatalf("unable to add backup controller, err: %s", err)
+	}
+
+	rc := &RestartController{
+		client:   mgrClient,
+		enabled:  opts.RestartEnabled,
+		window:   opts.RestartWindow,
+		recorder: mgr.GetEventRecorderFor("dapr-restart"),
+	}
+	err = mgr.Add(rc)
+	if err != nil {
+		log.Fatalf("unable to add restart controller, err: %s", err)
+	}
+
+	gc := &GarbageCollector{
+		client:            mgrClient,
+		interval:          opts.GCInterval,
+		secretGracePeriod: opts.GCSecretGracePeriod,
+		dryRun:            opts.GCDryRun,
+		namespace:         GetNamespace(),
+		recorder:          mgr.GetEventRecorderFor("dapr-gc"),
+	}
+	err = mgr.Add(gc)
+	if err != nil {
+		log.Fatalf("unable to add garbage collector, err: %s", err)
+	}
+
+	ws := &WorkloadsServer{
+		client: mgrClient,
+		port:   opts.WorkloadsAPIPort,
+	}
+	err = mgr.Add(ws)
+	if err != nil {
+		log.Fatalf("unable to add workloads server, err: %s", err)
+	}
+
 	daprHandler := handlers.NewDaprHandler(mgr)
 	err = daprHandler.Init()
 	if err != nil {
@@ -123,7 +183,7 @@ func NewOperator(opts Options) Operator {
 		configName:    opts.Config,
 		certChainPath: opts.CertChainPath,
 	}
-	o.apiServer = api.NewAPIServer(o.client)
+	o.apiServer = api.NewAPIServer(o.client, mgr.GetEventRecorderFor("dapr-components"), opts.SharedComponentsNamespace)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	componentInformer, err := mgr.GetCache().GetInformer(ctx, &componentsapi.Component{})
@@ -132,13 +192,50 @@ func NewOperator(opts Options) Operator {
 		log.Fatalf("unable to get setup components informer, err: %s", err)
 	} else {
 		componentInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-			AddFunc: o.syncComponent,
+			AddFunc: func(obj interface{}) {
+				o.syncComponent(obj, operatorv1pb.ResourceEventType_CREATED)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.syncComponent(newObj, operatorv1pb.ResourceEventType_UPDATED)
+				rc.OnComponentUpdated(oldObj, newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				o.syncComponent(obj, operatorv1pb.ResourceEventType_DELETED)
+			},
+		})
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	subscriptionInformer, err := mgr.GetCache().GetInformer(ctx, &subscriptionsapiV2alpha1.Subscription{})
+	cancel()
+	if err != nil {
+		log.Fatalf("unable to get setup subscriptions informer, err: %s", err)
+	} else {
+		subscriptionInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.syncSubscription(obj, operatorv1pb.ResourceEventType_CREATED)
+			},
 			UpdateFunc: func(_, newObj interface{}) {
-				o.syncComponent(newObj)
+				o.syncSubscription(newObj, operatorv1pb.ResourceEventType_UPDATED)
+			},
+			DeleteFunc: func(obj interface{}) {
+				o.syncSubscription(obj, operatorv1pb.ResourceEventType_DELETED)
 			},
 		})
 	}
 
+	if opts.RestartEnabled {
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		configurationInformer, err := mgr.GetCache().GetInformer(ctx, &configurationapi.Configuration{})
+		cancel()
+		if err != nil {
+			log.Fatalf("unable to get setup configurations informer, err: %s", err)
+		}
+		configurationInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: rc.OnConfigurationUpdated,
+		})
+	}
+
 	return o
 }
 
@@ -151,12 +248,28 @@ func (o *operator) prepareConfig() {
 	o.config.Credentials = credentials.NewTLSCredentials(o.certChainPath)
 }
 
-func (o *operator) syncComponent(obj interface{}) {
-	c, ok := obj.(*componentsapi.Component)
+func (o *operator) syncComponent(obj interface{}, eventType operatorv1pb.ResourceEventType) {
+	c, ok := deletedFinalState(obj).(*componentsapi.Component)
+	if ok {
+		log.Debugf("observed component to be synced, %s/%s (%s)", c.Namespace, c.Name, eventType)
+		o.apiServer.OnComponentUpdated(c, eventType)
+	}
+}
+
+func (o *operator) syncSubscription(obj interface{}, eventType operatorv1pb.ResourceEventType) {
+	s, ok := deletedFinalState(obj).(*subscriptionsapiV2alpha1.Subscription)
 	if ok {
-		log.Debugf("observed component to be synced, %s/%s", c.Namespace, c.Name)
-		o.apiServer.OnComponentUpdated(c)
+		log.Debugf("observed subscription to be synced, %s/%s (%s)", s.Namespace, s.Name, eventType)
+		o.apiServer.OnSubscriptionUpdated(s, eventType)
+	}
+}
+
+// deletedFinalState unwraps the last known state of an object whose deletion was missed by the informer.
+func deletedFinalState(obj interface{}) interface{} {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return d.Obj
 	}
+	return obj
 }
 
 func (o *operator) loadCertChain(ctx context.Context) (certChain *credentials.CertChain) {
@@ -208,8 +321,16 @@ func (o *operator) Run(ctx context.Context) {
 	}
 	o.prepareConfig()
 
-	// load certs from disk
-	certChain := o.loadCertChain(ctx)
+	// load certs from disk, and reload them when they are rotated
+	certs, err := credentials.NewCertChainReloader(o.config.Credentials, o.loadCertChain(ctx))
+	if err != nil {
+		log.Fatalf("error loading tls certificates: %s", err)
+	}
+	go func() {
+		if err := certs.Watch(ctx); err != nil {
+			log.Errorf("error watching tls certificates, rotated certificates will not be reloaded: %s", err)
+		}
+	}()
 
 	// start healthz server
 	healthzServer := health.NewServer(log)
@@ -222,7 +343,7 @@ func (o *operator) Run(ctx context.Context) {
 	}()
 
 	// blocking call
-	o.apiServer.Run(ctx, certChain, func() {
+	o.apiServer.Run(ctx, certs, func() {
 		healthzServer.Ready()
 		log.Infof("Dapr Operator started")
 	})
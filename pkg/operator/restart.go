@@ -0,0 +1,381 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	"github.com/dapr/dapr/pkg/operator/monitoring"
+	"github.com/dapr/dapr/utils"
+)
+
+const (
+	daprConfigAnnotationKey = "dapr.io/config"
+	// Annotation set on the pod template of the deployments restarted by the operator, as `kubectl rollout restart` does.
+	restartedAtAnnotationKey = "dapr.io/restartedAt"
+
+	// Reason of the events emitted on the restarted deployments.
+	sidecarRestartEventReason = "DaprSidecarRestart"
+
+	restartCheckInterval  = 10 * time.Second
+	rolloutPollInterval   = 5 * time.Second
+	defaultRolloutTimeout = 10 * time.Minute
+)
+
+// MaintenanceWindow is a daily time window, in UTC, in which the deployments can be restarted.
+// The window wraps around midnight when the end is before the start.
+type MaintenanceWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseMaintenanceWindow parses a maintenance window in the format "HH:MM-HH:MM", in UTC.
+func ParseMaintenanceWindow(val string) (*MaintenanceWindow, error) {
+	startStr, endStr, ok := strings.Cut(val, "-")
+	if !ok {
+		return nil, errors.Errorf("invalid maintenance window %q: expected format is HH:MM-HH:MM", val)
+	}
+	start, err := parseTimeOfDay(startStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid maintenance window %q", val)
+	}
+	end, err := parseTimeOfDay(endStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid maintenance window %q", val)
+	}
+	if start == end {
+		return nil, errors.Errorf("invalid maintenance window %q: start and end must be different", val)
+	}
+	return &MaintenanceWindow{Start: start, End: end}, nil
+}
+
+func parseTimeOfDay(val string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(val))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains returns true if the time is in the maintenance window.
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	t = t.UTC()
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return tod >= w.Start && tod < w.End
+	}
+	return tod >= w.Start || tod < w.End
+}
+
+// RestartController is a controller that restarts the deployments with the Dapr sidecar after a change
+// of a Configuration or a Component that the sidecars can't hot-reload: updates of Configurations,
+// and updates of the type, version or scopes of Components.
+// Deployments are restarted one at a time, with a rolling restart that respects their rolling update strategy;
+// the restart of a deployment is deferred while a PodDisruptionBudget of its pods doesn't allow disruptions,
+// or outside of the maintenance window.
+// This controller only runs on the cluster's leader.
+type RestartController struct {
+	enabled        bool
+	window         *MaintenanceWindow
+	rolloutTimeout time.Duration
+
+	client   client.Client
+	recorder record.EventRecorder
+	clock    func() time.Time
+
+	lock sync.Mutex
+	// Deployments to restart, as namespace/name, with the reason of the restart.
+	pending map[string]string
+}
+
+// NeedLeaderElection makes it so the controller runs on the leader node only.
+// Implements sigs.k8s.io/controller-runtime/pkg/manager.LeaderElectionRunnable .
+func (rc *RestartController) NeedLeaderElection() bool {
+	return true
+}
+
+// Start the controller. This method blocks until the context is canceled.
+// Implements sigs.k8s.io/controller-runtime/pkg/manager.Runnable .
+func (rc *RestartController) Start(ctx context.Context) error {
+	if !rc.enabled {
+		log.Infof("RestartController is not enabled")
+		return nil
+	}
+
+	log.Infof("RestartController worker started")
+
+	t := time.NewTicker(restartCheckInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Infof("RestartController worker stopped")
+			return nil
+		case <-t.C:
+			rc.processPending(ctx)
+		}
+	}
+}
+
+// OnConfigurationUpdated queues the restart of the deployments using the configuration, if its spec changed.
+func (rc *RestartController) OnConfigurationUpdated(oldObj, newObj interface{}) {
+	if !rc.enabled {
+		return
+	}
+	oldConf, ok := oldObj.(*configurationapi.Configuration)
+	if !ok {
+		return
+	}
+	newConf, ok := newObj.(*configurationapi.Configuration)
+	if !ok || reflect.DeepEqual(oldConf.Spec, newConf.Spec) {
+		return
+	}
+
+	reason := fmt.Sprintf("Configuration %s was updated", newConf.Name)
+	rc.enqueueDeployments(newConf.Namespace, reason, func(annotations map[string]string) bool {
+		return annotations[daprConfigAnnotationKey] == newConf.Name
+	})
+}
+
+// OnComponentUpdated queues the restart of the deployments with access to the component,
+// if the change can't be hot-reloaded by the sidecars.
+func (rc *RestartController) OnComponentUpdated(oldObj, newObj interface{}) {
+	if !rc.enabled {
+		return
+	}
+	oldComp, ok := oldObj.(*componentsapi.Component)
+	if !ok {
+		return
+	}
+	newComp, ok := newObj.(*componentsapi.Component)
+	if !ok || !isBreakingComponentChange(oldComp, newComp) {
+		return
+	}
+
+	reason := fmt.Sprintf("Component %s was updated", newComp.Name)
+	rc.enqueueDeployments(newComp.Namespace, reason, func(annotations map[string]string) bool {
+		return componentInScope(oldComp, annotations[appIDAnnotationKey]) || componentInScope(newComp, annotations[appIDAnnotationKey])
+	})
+}
+
+// isBreakingComponentChange returns true if the sidecars need to be restarted to apply the change of the component.
+// Sidecars reload the metadata of a component, but a component of another type is loaded as a new component,
+// and the scopes are only applied when loading the components.
+func isBreakingComponentChange(oldComp, newComp *componentsapi.Component) bool {
+	return oldComp.Spec.Type != newComp.Spec.Type ||
+		oldComp.Spec.Version != newComp.Spec.Version ||
+		!reflect.DeepEqual(oldComp.Scopes, newComp.Scopes)
+}
+
+func componentInScope(comp *componentsapi.Component, appID string) bool {
+	if len(comp.Scopes) == 0 {
+		return true
+	}
+	for _, s := range comp.Scopes {
+		if s == appID {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueDeployments queues the restart of the deployments with the Dapr sidecar in the namespace,
+// whose pod template annotations match.
+func (rc *RestartController) enqueueDeployments(namespace string, reason string, match func(annotations map[string]string) bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	deployments := &appsv1.DeploymentList{}
+	if err := rc.client.List(ctx, deployments, client.InNamespace(namespace)); err != nil {
+		log.Errorf("Failed to list deployments in namespace %s. Error: %v", namespace, err)
+		return
+	}
+
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	if rc.pending == nil {
+		rc.pending = map[string]string{}
+	}
+	for _, d := range deployments.Items {
+		annotations := d.Spec.Template.Annotations
+		if !utils.IsTruthy(annotations[daprEnabledAnnotationKey]) || !match(annotations) {
+			continue
+		}
+		key := d.Namespace + "/" + d.Name
+		if _, ok := rc.pending[key]; !ok {
+			log.Infof("Queued the restart of deployment %s: %s", key, reason)
+			rc.pending[key] = reason
+		}
+	}
+}
+
+// processPending restarts the pending deployments, one at a time, while in the maintenance window.
+func (rc *RestartController) processPending(ctx context.Context) {
+	for _, key := range rc.pendingKeys() {
+		if !rc.window.Contains(rc.now()) {
+			log.Debugf("Outside of the maintenance window: deferring the restart of the deployments")
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		restarted, err := rc.restartDeployment(ctx, key)
+		if err != nil {
+			log.Errorf("Failed to restart deployment %s. Error: %v", key, err)
+			continue
+		}
+		if !restarted {
+			continue
+		}
+		if err = rc.waitForRollout(ctx, key); err != nil {
+			log.Errorf("Rollout of deployment %s did not complete. Error: %v", key, err)
+		}
+	}
+}
+
+func (rc *RestartController) pendingKeys() []string {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	keys := make([]string, 0, len(rc.pending))
+	for k := range rc.pending {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// restartDeployment triggers a rolling restart of the deployment, unless a disruption budget of its pods
+// doesn't allow it now; it returns true if the deployment was restarted.
+func (rc *RestartController) restartDeployment(ctx context.Context, key string) (bool, error) {
+	rc.lock.Lock()
+	reason := rc.pending[key]
+	rc.lock.Unlock()
+
+	namespace, name, _ := strings.Cut(key, "/")
+	d := &appsv1.Deployment{}
+	if err := rc.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, d); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			rc.removePending(key)
+			return false, nil
+		}
+		return false, err
+	}
+
+	allowed, err := rc.disruptionAllowed(ctx, d)
+	if err != nil {
+		return false, err
+	}
+	if !allowed {
+		log.Infof("Deferring the restart of deployment %s: a PodDisruptionBudget does not allow disruptions", key)
+		return false, nil
+	}
+
+	patch := client.MergeFrom(d.DeepCopy())
+	if d.Spec.Template.Annotations == nil {
+		d.Spec.Template.Annotations = map[string]string{}
+	}
+	d.Spec.Template.Annotations[restartedAtAnnotationKey] = rc.now().UTC().Format(time.RFC3339)
+	if err = rc.client.Patch(ctx, d, patch); err != nil {
+		return false, err
+	}
+	rc.removePending(key)
+
+	log.Infof("Restarted deployment %s: %s", key, reason)
+	monitoring.RecordDeploymentRestartedCount(d.Spec.Template.Annotations[appIDAnnotationKey])
+	if rc.recorder != nil {
+		rc.recorder.Event(d, corev1.EventTypeNormal, sidecarRestartEventReason, "Restarting the pods to apply the Dapr changes: "+reason)
+	}
+	return true, nil
+}
+
+func (rc *RestartController) removePending(key string) {
+	rc.lock.Lock()
+	delete(rc.pending, key)
+	rc.lock.Unlock()
+}
+
+// disruptionAllowed returns false if a disruption budget selecting the pods of the deployment doesn't allow disruptions.
+func (rc *RestartController) disruptionAllowed(ctx context.Context, d *appsv1.Deployment) (bool, error) {
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := rc.client.List(ctx, pdbs, client.InNamespace(d.Namespace)); err != nil {
+		return false, err
+	}
+	podLabels := labels.Set(d.Spec.Template.Labels)
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(podLabels) {
+			continue
+		}
+		if pdb.Status.DisruptionsAllowed < 1 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// waitForRollout waits until all the replicas of the deployment are updated and available.
+func (rc *RestartController) waitForRollout(ctx context.Context, key string) error {
+	timeout := rc.rolloutTimeout
+	if timeout <= 0 {
+		timeout = defaultRolloutTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	namespace, name, _ := strings.Cut(key, "/")
+	t := time.NewTicker(rolloutPollInterval)
+	defer t.Stop()
+	for {
+		d := &appsv1.Deployment{}
+		if err := rc.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, d); err != nil {
+			return err
+		}
+		if rolloutComplete(d) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func rolloutComplete(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.Replicas == replicas &&
+		d.Status.AvailableReplicas == replicas
+}
+
+func (rc *RestartController) now() time.Time {
+	if rc.clock != nil {
+		return rc.clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,203 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+)
+
+func newDeployment(name string, annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": name},
+					Annotations: annotations,
+				},
+			},
+		},
+	}
+}
+
+func newRestartController(t *testing.T, objs ...client.Object) *RestartController {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	return &RestartController{
+		enabled:  true,
+		client:   fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+		recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	t.Run("valid window", func(t *testing.T) {
+		w, err := ParseMaintenanceWindow("22:00-04:30")
+		require.NoError(t, err)
+		assert.Equal(t, 22*time.Hour, w.Start)
+		assert.Equal(t, 4*time.Hour+30*time.Minute, w.End)
+	})
+
+	t.Run("invalid windows", func(t *testing.T) {
+		for _, val := range []string{"", "22:00", "25:00-01:00", "10:00-10:00"} {
+			_, err := ParseMaintenanceWindow(val)
+			assert.Error(t, err, val)
+		}
+	})
+}
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	at := func(h, m int) time.Time {
+		return time.Date(2022, 1, 1, h, m, 0, 0, time.UTC)
+	}
+
+	day := &MaintenanceWindow{Start: 9 * time.Hour, End: 17 * time.Hour}
+	assert.True(t, day.Contains(at(9, 0)))
+	assert.True(t, day.Contains(at(16, 59)))
+	assert.False(t, day.Contains(at(17, 0)))
+	assert.False(t, day.Contains(at(3, 0)))
+
+	night := &MaintenanceWindow{Start: 22 * time.Hour, End: 4 * time.Hour}
+	assert.True(t, night.Contains(at(23, 0)))
+	assert.True(t, night.Contains(at(1, 0)))
+	assert.False(t, night.Contains(at(12, 0)))
+
+	var always *MaintenanceWindow
+	assert.True(t, always.Contains(at(12, 0)))
+}
+
+func TestRestartControllerOnConfigurationUpdated(t *testing.T) {
+	rc := newRestartController(t,
+		newDeployment("uses-config", map[string]string{daprEnabledAnnotationKey: "true", daprConfigAnnotationKey: "appconfig"}),
+		newDeployment("other-config", map[string]string{daprEnabledAnnotationKey: "true", daprConfigAnnotationKey: "other"}),
+		newDeployment("disabled", map[string]string{daprEnabledAnnotationKey: "false", daprConfigAnnotationKey: "appconfig"}),
+	)
+
+	oldConf := &configurationapi.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "appconfig", Namespace: "default"}}
+	newConf := oldConf.DeepCopy()
+
+	rc.OnConfigurationUpdated(oldConf, newConf)
+	assert.Empty(t, rc.pendingKeys(), "configuration without changes of the spec")
+
+	newConf.Spec.TracingSpec.SamplingRate = "1"
+	rc.OnConfigurationUpdated(oldConf, newConf)
+	assert.Equal(t, []string{"default/uses-config"}, rc.pendingKeys())
+}
+
+func TestRestartControllerOnComponentUpdated(t *testing.T) {
+	rc := newRestartController(t,
+		newDeployment("app1", map[string]string{daprEnabledAnnotationKey: "true", appIDAnnotationKey: "app1"}),
+		newDeployment("app2", map[string]string{daprEnabledAnnotationKey: "true", appIDAnnotationKey: "app2"}),
+	)
+
+	oldComp := &componentsapi.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "statestore", Namespace: "default"},
+		Spec:       componentsapi.ComponentSpec{Type: "state.redis", Version: "v1"},
+		Scopes:     []string{"app1"},
+	}
+
+	t.Run("metadata changes are hot-reloaded", func(t *testing.T) {
+		newComp := oldComp.DeepCopy()
+		newComp.Spec.Metadata = []componentsapi.MetadataItem{{Name: "redisHost"}}
+		rc.OnComponentUpdated(oldComp, newComp)
+		assert.Empty(t, rc.pendingKeys())
+	})
+
+	t.Run("type changes restart the apps in scope", func(t *testing.T) {
+		newComp := oldComp.DeepCopy()
+		newComp.Spec.Type = "state.etcd"
+		rc.OnComponentUpdated(oldComp, newComp)
+		assert.Equal(t, []string{"default/app1"}, rc.pendingKeys())
+	})
+
+	t.Run("scope changes restart the apps in the old and new scopes", func(t *testing.T) {
+		newComp := oldComp.DeepCopy()
+		newComp.Scopes = []string{"app2"}
+		rc.OnComponentUpdated(oldComp, newComp)
+		assert.Equal(t, []string{"default/app1", "default/app2"}, rc.pendingKeys())
+	})
+}
+
+func TestRestartControllerProcessPending(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("restarts the pending deployments", func(t *testing.T) {
+		rc := newRestartController(t, newDeployment("app1", map[string]string{daprEnabledAnnotationKey: "true"}))
+		rc.clock = func() time.Time { return now }
+		rc.rolloutTimeout = time.Millisecond
+		rc.pending = map[string]string{"default/app1": "test", "default/deleted": "test"}
+
+		rc.processPending(context.Background())
+
+		d := &appsv1.Deployment{}
+		require.NoError(t, rc.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app1"}, d))
+		assert.Equal(t, now.Format(time.RFC3339), d.Spec.Template.Annotations[restartedAtAnnotationKey])
+		assert.Empty(t, rc.pendingKeys())
+		assert.Len(t, rc.recorder.(*record.FakeRecorder).Events, 1)
+	})
+
+	t.Run("outside of the maintenance window", func(t *testing.T) {
+		rc := newRestartController(t, newDeployment("app1", map[string]string{daprEnabledAnnotationKey: "true"}))
+		rc.clock = func() time.Time { return now }
+		rc.window = &MaintenanceWindow{Start: 22 * time.Hour, End: 4 * time.Hour}
+		rc.pending = map[string]string{"default/app1": "test"}
+
+		rc.processPending(context.Background())
+
+		d := &appsv1.Deployment{}
+		require.NoError(t, rc.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app1"}, d))
+		assert.NotContains(t, d.Spec.Template.Annotations, restartedAtAnnotationKey)
+		assert.Equal(t, []string{"default/app1"}, rc.pendingKeys())
+	})
+
+	t.Run("disruption budget does not allow disruptions", func(t *testing.T) {
+		pdb := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "app1", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "app1"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+		}
+		rc := newRestartController(t, newDeployment("app1", map[string]string{daprEnabledAnnotationKey: "true"}), pdb)
+		rc.clock = func() time.Time { return now }
+		rc.pending = map[string]string{"default/app1": "test"}
+
+		rc.processPending(context.Background())
+
+		d := &appsv1.Deployment{}
+		require.NoError(t, rc.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app1"}, d))
+		assert.NotContains(t, d.Spec.Template.Annotations, restartedAtAnnotationKey)
+		assert.Equal(t, []string{"default/app1"}, rc.pendingKeys())
+	})
+}
+
+func TestRolloutComplete(t *testing.T) {
+	replicas := int32(2)
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2},
+	}
+	assert.False(t, rolloutComplete(d))
+
+	d.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	assert.True(t, rolloutComplete(d))
+
+	d.Generation = 3
+	assert.False(t, rolloutComplete(d))
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+)
+
+// kubernetesSecretStore is the name of the built-in Kubernetes secret store.
+const kubernetesSecretStore = "kubernetes"
+
+// componentCategories contains the prefixes of the supported component types.
+var componentCategories = []string{
+	"bindings",
+	"pubsub",
+	"secretstores",
+	"state",
+	"middleware",
+	"configuration",
+	"lock",
+	"crypto",
+}
+
+var componentVersionRegexp = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]+)?$`)
+
+// ComponentValidator validates Component resources.
+type ComponentValidator struct {
+	client          client.Reader
+	additionalTypes map[string]struct{}
+}
+
+var _ admission.CustomValidator = (*ComponentValidator)(nil)
+
+// NewComponentValidator returns a new ComponentValidator.
+// The client is used to check that secrets referenced by the component exist.
+// The additional types are the types of the components not built into daprd, such as pluggable components.
+func NewComponentValidator(client client.Reader, additionalTypes []string) *ComponentValidator {
+	v := &ComponentValidator{
+		client:          client,
+		additionalTypes: make(map[string]struct{}, len(additionalTypes)),
+	}
+	for _, t := range additionalTypes {
+		v.additionalTypes[t] = struct{}{}
+	}
+	return v
+}
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *ComponentValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(ctx, obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (v *ComponentValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return v.validate(ctx, newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *ComponentValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v *ComponentValidator) validate(ctx context.Context, obj runtime.Object) error {
+	comp, ok := obj.(*componentsapi.Component)
+	if !ok {
+		return fmt.Errorf("expected a Component but got %T", obj)
+	}
+
+	errs := errorList{}
+
+	_, builtin := builtinComponentTypes[comp.Spec.Type]
+	_, additional := v.additionalTypes[comp.Spec.Type]
+	if !isValidComponentType(comp.Spec.Type) {
+		errs.add("spec.type: unsupported component type %q; must be one of the categories %s followed by a name", comp.Spec.Type, strings.Join(componentCategories, ", "))
+	} else if !builtin && !additional {
+		errs.add("spec.type: unknown component type %q; the types of the components not built into daprd must be listed in the additional-component-types option of the operator", comp.Spec.Type)
+	}
+	if comp.Spec.Version == "" {
+		errs.add("spec.version: must not be empty")
+	} else if !componentVersionRegexp.MatchString(comp.Spec.Version) {
+		errs.add("spec.version: invalid version %q", comp.Spec.Version)
+	}
+	if comp.Spec.InitTimeout != "" {
+		if _, err := time.ParseDuration(comp.Spec.InitTimeout); err != nil {
+			errs.add("spec.initTimeout: %s", err)
+		}
+	}
+
+	names := make(map[string]struct{}, len(comp.Spec.Metadata))
+	for i, md := range comp.Spec.Metadata {
+		if md.Name == "" {
+			errs.add("spec.metadata[%d].name: must not be empty", i)
+			continue
+		}
+		if _, exists := names[md.Name]; exists {
+			errs.add("spec.metadata[%d].name: duplicate metadata name %q", i, md.Name)
+		}
+		names[md.Name] = struct{}{}
+
+		if md.SecretKeyRef.Name == "" {
+			continue
+		}
+		if len(md.Value.Raw) > 0 {
+			errs.add("spec.metadata[%d]: only one of value and secretKeyRef can be set", i)
+		}
+		if err := v.validateSecretKeyRef(ctx, comp, md.SecretKeyRef); err != nil {
+			errs.add("spec.metadata[%d].secretKeyRef: %s", i, err)
+		}
+	}
+
+	validateScopes(&errs, comp.Scopes)
+
+	return errs.toError("component", comp.Name)
+}
+
+// validateSecretKeyRef checks that a secret referenced from the built-in Kubernetes secret store exists.
+// References to other secret stores are resolved by the sidecar and can't be checked here.
+func (v *ComponentValidator) validateSecretKeyRef(ctx context.Context, comp *componentsapi.Component, ref componentsapi.SecretKeyRef) error {
+	if v.client == nil || (comp.Auth.SecretStore != "" && comp.Auth.SecretStore != kubernetesSecretStore) {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	err := v.client.Get(ctx, types.NamespacedName{Namespace: comp.Namespace, Name: ref.Name}, secret)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("secret %q not found in namespace %q", ref.Name, comp.Namespace)
+	} else if err != nil {
+		return fmt.Errorf("failed to retrieve secret %q: %w", ref.Name, err)
+	}
+
+	key := ref.Key
+	if key == "" {
+		key = ref.Name
+	}
+	if _, ok := secret.Data[key]; !ok {
+		return fmt.Errorf("key %q not found in secret %q", key, ref.Name)
+	}
+	return nil
+}
+
+func isValidComponentType(t string) bool {
+	for _, c := range componentCategories {
+		if strings.HasPrefix(t, c+".") && len(t) > len(c)+1 {
+			return true
+		}
+	}
+	return false
+}
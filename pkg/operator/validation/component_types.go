@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+// builtinComponentTypes are the types of the components registered in daprd, in cmd/daprd/components.
+// Their metadata isn't validated, as the components don't publish the schema of their metadata: the sidecar reports
+// the invalid metadata when it initializes them.
+// TestBuiltinComponentTypes checks that they match the registered components.
+var builtinComponentTypes = map[string]struct{}{
+	"bindings.alicloud.dubbo":                 {},
+	"bindings.alicloud.oss":                   {},
+	"bindings.alicloud.sls":                   {},
+	"bindings.alicloud.tablestore":            {},
+	"bindings.apns":                           {},
+	"bindings.aws.dynamodb":                   {},
+	"bindings.aws.kinesis":                    {},
+	"bindings.aws.s3":                         {},
+	"bindings.aws.ses":                        {},
+	"bindings.aws.sns":                        {},
+	"bindings.aws.sqs":                        {},
+	"bindings.azure.blobstorage":              {},
+	"bindings.azure.cosmosdb":                 {},
+	"bindings.azure.cosmosdb.gremlinapi":      {},
+	"bindings.azure.eventgrid":                {},
+	"bindings.azure.eventhubs":                {},
+	"bindings.azure.servicebusqueues":         {},
+	"bindings.azure.signalr":                  {},
+	"bindings.azure.storagequeues":            {},
+	"bindings.commercetools":                  {},
+	"bindings.cron":                           {},
+	"bindings.dingtalk.webhook":               {},
+	"bindings.gcp.bucket":                     {},
+	"bindings.gcp.pubsub":                     {},
+	"bindings.graphql":                        {},
+	"bindings.http":                           {},
+	"bindings.huawei.obs":                     {},
+	"bindings.influx":                         {},
+	"bindings.kafka":                          {},
+	"bindings.kubernetes":                     {},
+	"bindings.localstorage":                   {},
+	"bindings.mqtt":                           {},
+	"bindings.mysql":                          {},
+	"bindings.postgres":                       {},
+	"bindings.postmark":                       {},
+	"bindings.rabbitmq":                       {},
+	"bindings.redis":                          {},
+	"bindings.rethinkdb.statechange":          {},
+	"bindings.smtp":                           {},
+	"bindings.twilio.sendgrid":                {},
+	"bindings.twilio.sms":                     {},
+	"bindings.twitter":                        {},
+	"bindings.zeebe.command":                  {},
+	"bindings.zeebe.jobworker":                {},
+	"configuration.azure.appconfig":           {},
+	"configuration.kubernetes":                {},
+	"configuration.localfile":                 {},
+	"configuration.redis":                     {},
+	"crypto.azure.keyvault":                   {},
+	"crypto.jwks":                             {},
+	"crypto.rawkeys":                          {},
+	"lock.redis":                              {},
+	"middleware.http.bearer":                  {},
+	"middleware.http.oauth2":                  {},
+	"middleware.http.oauth2clientcredentials": {},
+	"middleware.http.opa":                     {},
+	"middleware.http.ratelimit":               {},
+	"middleware.http.routeralias":             {},
+	"middleware.http.routerchecker":           {},
+	"middleware.http.sentinel":                {},
+	"middleware.http.uppercase":               {},
+	"middleware.http.wasm":                    {},
+	"middleware.http.wasm.basic":              {},
+	"pubsub.aws.snssqs":                       {},
+	"pubsub.azure.eventhubs":                  {},
+	"pubsub.azure.servicebus":                 {},
+	"pubsub.gcp.pubsub":                       {},
+	"pubsub.hazelcast":                        {},
+	"pubsub.in-memory":                        {},
+	"pubsub.jetstream":                        {},
+	"pubsub.kafka":                            {},
+	"pubsub.mqtt":                             {},
+	"pubsub.natsstreaming":                    {},
+	"pubsub.pulsar":                           {},
+	"pubsub.rabbitmq":                         {},
+	"pubsub.redis":                            {},
+	"pubsub.rocketmq":                         {},
+	"pubsub.snssqs":                           {},
+	"secretstores.alicloud.parameterstore":    {},
+	"secretstores.aws.parameterstore":         {},
+	"secretstores.aws.secretmanager":          {},
+	"secretstores.azure.keyvault":             {},
+	"secretstores.gcp.secretmanager":          {},
+	"secretstores.hashicorp.vault":            {},
+	"secretstores.huaweicloud.csms":           {},
+	"secretstores.kubernetes":                 {},
+	"secretstores.local.env":                  {},
+	"secretstores.local.file":                 {},
+	"state.aerospike":                         {},
+	"state.aws.dynaodb":                       {},
+	"state.azure.blobstorage":                 {},
+	"state.azure.cosmosdb":                    {},
+	"state.azure.tablestorage":                {},
+	"state.cassandra":                         {},
+	"state.cockroachdb":                       {},
+	"state.consul":                            {},
+	"state.couchbase":                         {},
+	"state.gcp.firestore":                     {},
+	"state.hazelcast":                         {},
+	"state.in-memory":                         {},
+	"state.jetstream":                         {},
+	"state.memcached":                         {},
+	"state.mongodb":                           {},
+	"state.mysql":                             {},
+	"state.oci.objectstorage":                 {},
+	"state.oracledatabase":                    {},
+	"state.postgresql":                        {},
+	"state.redis":                             {},
+	"state.rethinkdb":                         {},
+	"state.sqlserver":                         {},
+	"state.zookeeper":                         {},
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// registryImportRegexp matches the import of the registry of the components in the files of cmd/daprd/components.
+	registryImportRegexp = regexp.MustCompile(`"github.com/dapr/dapr/pkg/components/([a-z/]+)"`)
+	// registeredNamesRegexp matches the names passed to the loaders, at the end of the registrations.
+	registeredNamesRegexp = regexp.MustCompile(`,\s*((?:"[^"]+"\s*,?\s*)+)\)\s*\n`)
+	quotedRegexp          = regexp.MustCompile(`"([^"]+)"`)
+)
+
+func TestBuiltinComponentTypes(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "cmd", "daprd", "components", "*.go"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	registered := map[string]struct{}{}
+	for _, f := range files {
+		src, err := os.ReadFile(f)
+		require.NoError(t, err)
+
+		m := registryImportRegexp.FindSubmatch(src)
+		require.NotNil(t, m, f)
+		category := strings.ReplaceAll(string(m[1]), "/", ".")
+		if category == "nameresolution" {
+			// Name resolution components aren't defined with Component resources.
+			continue
+		}
+		for _, names := range registeredNamesRegexp.FindAllSubmatch(src, -1) {
+			for _, name := range quotedRegexp.FindAllSubmatch(names[1], -1) {
+				registered[category+"."+string(name[1])] = struct{}{}
+			}
+		}
+	}
+
+	for typ := range registered {
+		assert.Contains(t, builtinComponentTypes, typ, "component type registered in daprd but missing from builtinComponentTypes")
+	}
+	for typ := range builtinComponentTypes {
+		assert.Contains(t, registered, typ, "component type in builtinComponentTypes but not registered in daprd")
+		assert.True(t, isValidComponentType(typ), typ)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	"github.com/dapr/dapr/pkg/config"
+)
+
+// ConfigurationValidator validates Configuration resources.
+type ConfigurationValidator struct{}
+
+var _ admission.CustomValidator = (*ConfigurationValidator)(nil)
+
+// NewConfigurationValidator returns a new ConfigurationValidator.
+func NewConfigurationValidator() *ConfigurationValidator {
+	return &ConfigurationValidator{}
+}
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *ConfigurationValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (v *ConfigurationValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *ConfigurationValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v *ConfigurationValidator) validate(obj runtime.Object) error {
+	conf, ok := obj.(*configurationapi.Configuration)
+	if !ok {
+		return fmt.Errorf("expected a Configuration but got %T", obj)
+	}
+
+	errs := errorList{}
+	spec := conf.Spec
+
+	for i, h := range spec.HTTPPipelineSpec.Handlers {
+		if h.Name == "" {
+			errs.add("spec.httpPipeline.handlers[%d].name: must not be empty", i)
+		}
+		if !strings.HasPrefix(h.Type, "middleware.http.") {
+			errs.add("spec.httpPipeline.handlers[%d].type: %q is not an HTTP middleware type", i, h.Type)
+		}
+	}
+
+	if spec.TracingSpec.SamplingRate != "" {
+		rate, err := strconv.ParseFloat(spec.TracingSpec.SamplingRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			errs.add("spec.tracing.samplingRate: must be a number between 0 and 1")
+		}
+	}
+
+	validateDuration(&errs, "spec.mtls.workloadCertTTL", spec.MTLSSpec.WorkloadCertTTL)
+	validateDuration(&errs, "spec.mtls.allowedClockSkew", spec.MTLSSpec.AllowedClockSkew)
+	for i, tv := range spec.MTLSSpec.TokenValidators {
+		if tv.Name == "" {
+			errs.add("spec.mtls.tokenValidators[%d].name: must not be empty", i)
+		}
+	}
+
+	for i, s := range spec.Secrets.Scopes {
+		if s.StoreName == "" {
+			errs.add("spec.secrets.scopes[%d].storeName: must not be empty", i)
+		}
+		validateAction(&errs, fmt.Sprintf("spec.secrets.scopes[%d].defaultAccess", i), s.DefaultAccess)
+	}
+
+	acl := spec.AccessControlSpec
+	validateAction(&errs, "spec.accessControl.defaultAction", acl.DefaultAction)
+	for i, p := range acl.AppPolicies {
+		if p.AppName == "" {
+			errs.add("spec.accessControl.policies[%d].appId: must not be empty", i)
+		}
+		validateAction(&errs, fmt.Sprintf("spec.accessControl.policies[%d].defaultAction", i), p.DefaultAction)
+		for j, op := range p.AppOperationActions {
+			if op.Operation == "" {
+				errs.add("spec.accessControl.policies[%d].operations[%d].name: must not be empty", i, j)
+			}
+			validateAction(&errs, fmt.Sprintf("spec.accessControl.policies[%d].operations[%d].action", i, j), op.Action)
+		}
+	}
+
+	for i, a := range spec.APISpec.Allowed {
+		if a.Name == "" {
+			errs.add("spec.api.allowed[%d].name: must not be empty", i)
+		}
+		if a.Protocol != "" && a.Protocol != config.HTTPProtocol && a.Protocol != config.GRPCProtocol {
+			errs.add("spec.api.allowed[%d].protocol: must be one of %q or %q", i, config.HTTPProtocol, config.GRPCProtocol)
+		}
+	}
+
+	for i, f := range spec.Features {
+		if f.Name == "" {
+			errs.add("spec.features[%d].name: must not be empty", i)
+		}
+	}
+
+	return errs.toError("configuration", conf.Name)
+}
+
+func validateDuration(errs *errorList, field, val string) {
+	if val == "" {
+		return
+	}
+	if _, err := time.ParseDuration(val); err != nil {
+		errs.add("%s: %s", field, err)
+	}
+}
+
+func validateAction(errs *errorList, field, val string) {
+	if val != "" && val != config.AllowAccess && val != config.DenyAccess {
+		errs.add("%s: must be one of %q or %q", field, config.AllowAccess, config.DenyAccess)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	subscriptionsapiV2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+	"github.com/dapr/dapr/pkg/expr"
+)
+
+// SubscriptionValidator validates Subscription resources.
+// It operates on the v2alpha1 (storage) version: objects in other versions are converted by the API server before being sent to the webhook.
+type SubscriptionValidator struct{}
+
+var _ admission.CustomValidator = (*SubscriptionValidator)(nil)
+
+// NewSubscriptionValidator returns a new SubscriptionValidator.
+func NewSubscriptionValidator() *SubscriptionValidator {
+	return &SubscriptionValidator{}
+}
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *SubscriptionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (v *SubscriptionValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *SubscriptionValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v *SubscriptionValidator) validate(obj runtime.Object) error {
+	sub, ok := obj.(*subscriptionsapiV2alpha1.Subscription)
+	if !ok {
+		return fmt.Errorf("expected a Subscription but got %T", obj)
+	}
+
+	errs := errorList{}
+
+	if sub.Spec.Pubsubname == "" {
+		errs.add("spec.pubsubname: must not be empty")
+	}
+	if sub.Spec.Topic == "" {
+		errs.add("spec.topic: must not be empty")
+	}
+	if sub.Spec.Routes.Default == "" && len(sub.Spec.Routes.Rules) == 0 {
+		errs.add("spec.routes: at least one rule or a default route must be set")
+	}
+	for i, r := range sub.Spec.Routes.Rules {
+		if r.Path == "" {
+			errs.add("spec.routes.rules[%d].path: must not be empty", i)
+		}
+		if r.Match == "" {
+			continue
+		}
+		var e expr.Expr
+		if err := e.DecodeString(r.Match); err != nil {
+			errs.add("spec.routes.rules[%d].match: invalid CEL expression: %s", i, err)
+		}
+	}
+	if sub.Spec.DeadLetterTopic != "" && sub.Spec.DeadLetterTopic == sub.Spec.Topic {
+		errs.add("spec.deadLetterTopic: must be different from the topic")
+	}
+
+	validateScopes(&errs, sub.Scopes)
+
+	return errs.toError("subscription", sub.Name)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation contains the validating admission webhooks for Dapr resources.
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dapr/dapr/pkg/validation"
+)
+
+// errorList collects validation errors for a resource.
+type errorList []string
+
+func (e *errorList) add(format string, args ...interface{}) {
+	*e = append(*e, fmt.Sprintf(format, args...))
+}
+
+// toError returns an error containing all the collected messages, or nil if the list is empty.
+func (e errorList) toError(kind, name string) error {
+	if len(e) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid %s %q: %s", kind, name, strings.Join(e, "; "))
+}
+
+// validateScopes checks that each scope is a valid app ID.
+func validateScopes(errs *errorList, scopes []string) {
+	for i, s := range scopes {
+		if err := validation.ValidateKubernetesAppID(s); err != nil {
+			errs.add("scopes[%d]: %s", i, err)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	subscriptionsapiV2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+)
+
+func TestComponentValidator(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	client := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysecret", Namespace: "default"},
+			Data:       map[string][]byte{"password": []byte("pwd")},
+		}).
+		Build()
+	v := NewComponentValidator(client, []string{"state.pluggable"})
+
+	newComponent := func(typ, version string, md ...componentsapi.MetadataItem) *componentsapi.Component {
+		return &componentsapi.Component{
+			ObjectMeta: metav1.ObjectMeta{Name: "mycomp", Namespace: "default"},
+			Spec: componentsapi.ComponentSpec{
+				Type:     typ,
+				Version:  version,
+				Metadata: md,
+			},
+		}
+	}
+	secretRef := func(name, key string) componentsapi.MetadataItem {
+		return componentsapi.MetadataItem{
+			Name:         "password",
+			SecretKeyRef: componentsapi.SecretKeyRef{Name: name, Key: key},
+		}
+	}
+
+	redisHost := componentsapi.MetadataItem{Name: "redisHost", Value: componentsapi.DynamicValue{JSON: apiextensionsV1.JSON{Raw: []byte(`"localhost:6379"`)}}}
+
+	t.Run("valid component", func(t *testing.T) {
+		comp := newComponent("state.redis", "v1", redisHost, secretRef("mysecret", "password"))
+		comp.Scopes = []string{"app1", "app2"}
+		assert.NoError(t, v.ValidateCreate(context.Background(), comp))
+		assert.NoError(t, v.ValidateUpdate(context.Background(), comp, comp))
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		err := v.ValidateCreate(context.Background(), newComponent("foo.redis", "v1"))
+		assert.ErrorContains(t, err, "spec.type")
+
+		err = v.ValidateCreate(context.Background(), newComponent("state.", "v1"))
+		assert.ErrorContains(t, err, "spec.type")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		err := v.ValidateCreate(context.Background(), newComponent("state.unknown", "v1"))
+		assert.ErrorContains(t, err, `unknown component type "state.unknown"`)
+	})
+
+	t.Run("additional type", func(t *testing.T) {
+		assert.NoError(t, v.ValidateCreate(context.Background(), newComponent("state.pluggable", "v1")))
+	})
+
+	t.Run("metadata of the component type is not checked", func(t *testing.T) {
+		assert.NoError(t, v.ValidateCreate(context.Background(), newComponent("state.redis", "v1")))
+		assert.NoError(t, v.ValidateCreate(context.Background(), newComponent("bindings.cron", "v1", redisHost)))
+	})
+
+	t.Run("value and secret", func(t *testing.T) {
+		md := secretRef("mysecret", "password")
+		md.Value = redisHost.Value
+		err := v.ValidateCreate(context.Background(), newComponent("state.redis", "v1", md))
+		assert.ErrorContains(t, err, "spec.metadata[0]: only one of value and secretKeyRef can be set")
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		err := v.ValidateCreate(context.Background(), newComponent("state.redis", ""))
+		assert.ErrorContains(t, err, "spec.version")
+
+		err = v.ValidateCreate(context.Background(), newComponent("state.redis", "1"))
+		assert.ErrorContains(t, err, "spec.version")
+	})
+
+	t.Run("duplicate and empty metadata names", func(t *testing.T) {
+		err := v.ValidateCreate(context.Background(), newComponent("state.redis", "v1",
+			componentsapi.MetadataItem{Name: "a"},
+			componentsapi.MetadataItem{Name: "a"},
+			componentsapi.MetadataItem{Name: ""},
+		))
+		assert.ErrorContains(t, err, "duplicate metadata name")
+		assert.ErrorContains(t, err, "spec.metadata[2].name")
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		err := v.ValidateCreate(context.Background(), newComponent("state.redis", "v1", secretRef("notfound", "password")))
+		assert.ErrorContains(t, err, `secret "notfound" not found`)
+	})
+
+	t.Run("missing secret key", func(t *testing.T) {
+		err := v.ValidateCreate(context.Background(), newComponent("state.redis", "v1", secretRef("mysecret", "nokey")))
+		assert.ErrorContains(t, err, `key "nokey" not found`)
+	})
+
+	t.Run("secret from another store is not checked", func(t *testing.T) {
+		comp := newComponent("state.redis", "v1", redisHost, secretRef("notfound", "password"))
+		comp.Auth.SecretStore = "vault"
+		assert.NoError(t, v.ValidateCreate(context.Background(), comp))
+	})
+
+	t.Run("invalid scopes", func(t *testing.T) {
+		comp := newComponent("state.redis", "v1")
+		comp.Scopes = []string{"app1", "Not_Valid"}
+		err := v.ValidateCreate(context.Background(), comp)
+		assert.ErrorContains(t, err, "scopes[1]")
+	})
+
+	t.Run("delete is always allowed", func(t *testing.T) {
+		assert.NoError(t, v.ValidateDelete(context.Background(), newComponent("", "")))
+	})
+}
+
+func TestConfigurationValidator(t *testing.T) {
+	v := NewConfigurationValidator()
+
+	t.Run("valid configuration", func(t *testing.T) {
+		conf := &configurationapi.Configuration{
+			ObjectMeta: metav1.ObjectMeta{Name: "myconfig"},
+			Spec: configurationapi.ConfigurationSpec{
+				HTTPPipelineSpec: configurationapi.PipelineSpec{
+					Handlers: []configurationapi.HandlerSpec{{Name: "uppercase", Type: "middleware.http.uppercase"}},
+				},
+				TracingSpec: configurationapi.TracingSpec{SamplingRate: "0.5"},
+				MTLSSpec:    configurationapi.MTLSSpec{WorkloadCertTTL: "1h", AllowedClockSkew: "15m"},
+				AccessControlSpec: configurationapi.AccessControlSpec{
+					DefaultAction: "deny",
+					AppPolicies: []configurationapi.AppPolicySpec{{
+						AppName:             "app1",
+						DefaultAction:       "allow",
+						AppOperationActions: []configurationapi.AppOperationAction{{Operation: "/op", Action: "allow"}},
+					}},
+				},
+			},
+		}
+		assert.NoError(t, v.ValidateCreate(context.Background(), conf))
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		conf := &configurationapi.Configuration{
+			ObjectMeta: metav1.ObjectMeta{Name: "myconfig"},
+			Spec: configurationapi.ConfigurationSpec{
+				HTTPPipelineSpec: configurationapi.PipelineSpec{
+					Handlers: []configurationapi.HandlerSpec{{Name: "uppercase", Type: "state.redis"}},
+				},
+				TracingSpec: configurationapi.TracingSpec{SamplingRate: "2"},
+				MTLSSpec:    configurationapi.MTLSSpec{WorkloadCertTTL: "1 hour"},
+				AccessControlSpec: configurationapi.AccessControlSpec{
+					DefaultAction: "maybe",
+				},
+				APISpec: configurationapi.APISpec{
+					Allowed: []configurationapi.APIAccessRule{{Name: "state", Protocol: "tcp"}},
+				},
+			},
+		}
+		err := v.ValidateUpdate(context.Background(), conf, conf)
+		assert.ErrorContains(t, err, "spec.httpPipeline.handlers[0].type")
+		assert.ErrorContains(t, err, "spec.tracing.samplingRate")
+		assert.ErrorContains(t, err, "spec.mtls.workloadCertTTL")
+		assert.ErrorContains(t, err, "spec.accessControl.defaultAction")
+		assert.ErrorContains(t, err, "spec.api.allowed[0].protocol")
+	})
+}
+
+func TestSubscriptionValidator(t *testing.T) {
+	v := NewSubscriptionValidator()
+
+	newSubscription := func(routes subscriptionsapiV2alpha1.Routes) *subscriptionsapiV2alpha1.Subscription {
+		return &subscriptionsapiV2alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysub"},
+			Spec: subscriptionsapiV2alpha1.SubscriptionSpec{
+				Pubsubname: "pubsub",
+				Topic:      "orders",
+				Routes:     routes,
+			},
+		}
+	}
+
+	t.Run("valid subscription", func(t *testing.T) {
+		sub := newSubscription(subscriptionsapiV2alpha1.Routes{
+			Rules:   []subscriptionsapiV2alpha1.Rule{{Match: `event.type == "order"`, Path: "/orders"}},
+			Default: "/default",
+		})
+		assert.NoError(t, v.ValidateCreate(context.Background(), sub))
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		sub := &subscriptionsapiV2alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "mysub"}}
+		err := v.ValidateCreate(context.Background(), sub)
+		assert.ErrorContains(t, err, "spec.pubsubname")
+		assert.ErrorContains(t, err, "spec.topic")
+		assert.ErrorContains(t, err, "spec.routes")
+	})
+
+	t.Run("invalid match expression", func(t *testing.T) {
+		sub := newSubscription(subscriptionsapiV2alpha1.Routes{
+			Rules: []subscriptionsapiV2alpha1.Rule{{Match: `event.type ==`, Path: "/orders"}},
+		})
+		err := v.ValidateCreate(context.Background(), sub)
+		assert.ErrorContains(t, err, "spec.routes.rules[0].match")
+	})
+
+	t.Run("wrong object type", func(t *testing.T) {
+		err := v.ValidateCreate(context.Background(), &componentsapi.Component{})
+		assert.Error(t, err)
+	})
+}
@@ -6,25 +6,34 @@ import (
 
 	"go.uber.org/ratelimit"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/dapr/dapr/pkg/operator/monitoring"
 	"github.com/dapr/dapr/utils"
 )
 
 const (
 	sidecarContainerName     = "daprd"
 	daprEnabledAnnotationKey = "dapr.io/enabled"
+	appIDAnnotationKey       = "dapr.io/app-id"
+
+	// Reason of the events emitted on pods without the sidecar.
+	sidecarMissingEventReason = "DaprSidecarMissing"
 )
 
 // DaprWatchdog is a controller that periodically polls all pods and ensures that they are in the correct state.
 // This controller only runs on the cluster's leader.
 // Currently, this ensures that the sidecar is injected in each pod, otherwise it kills the pod so it can be restarted.
+// In dry-run mode, pods without the sidecar are only reported with an event and a metric.
 type DaprWatchdog struct {
 	enabled           bool
 	interval          time.Duration
 	maxRestartsPerMin int
+	dryRun            bool
 
 	client         client.Client
+	recorder       record.EventRecorder
 	restartLimiter ratelimit.Limiter
 }
 
@@ -155,8 +164,18 @@ func (dw *DaprWatchdog) listPods(ctx context.Context) {
 			continue
 		}
 
+		appID := v.Annotations[appIDAnnotationKey]
+		monitoring.RecordWatchdogSidecarMissingCount(appID)
+
+		if dw.dryRun {
+			log.Warnf("Pod %s does not have the Dapr sidecar; not deleting it because dry-run mode is enabled", logName)
+			dw.recordEvent(&v, "Pod does not have the Dapr sidecar")
+			continue
+		}
+
 		// Pod doesn't have a sidecar, so we need to kill it so it can be restarted and have the sidecar injected
 		log.Warnf("Pod %s does not have the Dapr sidecar and will be deleted", logName)
+		dw.recordEvent(&v, "Pod does not have the Dapr sidecar and will be deleted")
 		//nolint:gosec
 		err = dw.client.Delete(ctx, &v)
 		if err != nil {
@@ -165,6 +184,7 @@ func (dw *DaprWatchdog) listPods(ctx context.Context) {
 		}
 
 		log.Infof("Deleted pod %s", logName)
+		monitoring.RecordWatchdogPodDeletedCount(appID)
 
 		log.Debugf("Taking a pod restart token")
 		before := time.Now()
@@ -174,3 +194,11 @@ func (dw *DaprWatchdog) listPods(ctx context.Context) {
 
 	log.Infof("DaprWatchdog completed checking pods")
 }
+
+// recordEvent emits a warning event on the pod, if an event recorder is set.
+func (dw *DaprWatchdog) recordEvent(pod *corev1.Pod, message string) {
+	if dw.recorder == nil {
+		return
+	}
+	dw.recorder.Event(pod, corev1.EventTypeWarning, sidecarMissingEventReason, message)
+}
@@ -0,0 +1,81 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/ratelimit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createPods() []client.Object {
+	newPod := func(name string, enabled string, containers ...string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					daprEnabledAnnotationKey: enabled,
+					appIDAnnotationKey:       name,
+				},
+			},
+		}
+		for _, c := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c})
+		}
+		return pod
+	}
+
+	return []client.Object{
+		newPod("injected", "true", "app", sidecarContainerName),
+		newPod("missing", "true", "app"),
+		newPod("disabled", "false", "app"),
+	}
+}
+
+func TestDaprWatchdogListPods(t *testing.T) {
+	t.Run("deletes pods without the sidecar", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		dw := &DaprWatchdog{
+			client:         fake.NewClientBuilder().WithObjects(createPods()...).Build(),
+			recorder:       recorder,
+			restartLimiter: ratelimit.NewUnlimited(),
+		}
+
+		dw.listPods(context.Background())
+
+		pods := &corev1.PodList{}
+		require.NoError(t, dw.client.List(context.Background(), pods))
+		names := []string{}
+		for _, p := range pods.Items {
+			names = append(names, p.Name)
+		}
+		assert.ElementsMatch(t, []string{"injected", "disabled"}, names)
+		assert.Len(t, recorder.Events, 1)
+	})
+
+	t.Run("dry-run only reports pods without the sidecar", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		dw := &DaprWatchdog{
+			client:         fake.NewClientBuilder().WithObjects(createPods()...).Build(),
+			recorder:       recorder,
+			restartLimiter: ratelimit.NewUnlimited(),
+			dryRun:         true,
+		}
+
+		dw.listPods(context.Background())
+
+		pods := &corev1.PodList{}
+		require.NoError(t, dw.client.List(context.Background(), pods))
+		assert.Len(t, pods.Items, 3)
+		if assert.Len(t, recorder.Events, 1) {
+			assert.Contains(t, <-recorder.Events, sidecarMissingEventReason)
+		}
+	})
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -16,13 +19,29 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
 	subscriptionsapiV1alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
 	subscriptionsapiV2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+	"github.com/dapr/dapr/pkg/operator/validation"
 )
 
-const webhookCAName = "dapr-webhook-ca"
+const (
+	webhookCAName = "dapr-webhook-ca"
 
-func RunWebhooks(ctx context.Context, enableLeaderElection bool) {
+	// webhookServiceName is the name of the service of the webhooks, used by the conversion webhooks of the CRDs.
+	webhookServiceName = "dapr-webhook"
+	// conversionWebhookPath is the path of the conversion webhook served by controller-runtime.
+	conversionWebhookPath = "/convert"
+
+	// validatingWebhookConfigurationName is the name of the ValidatingWebhookConfiguration for Dapr resources.
+	validatingWebhookConfigurationName = "dapr-validation-webhook"
+)
+
+// conversionCRDs are the CRDs converted by the webhooks when they're served in multiple versions.
+var conversionCRDs = []string{"subscriptions.dapr.io", "components.dapr.io"}
+
+func RunWebhooks(ctx context.Context, enableLeaderElection bool, additionalComponentTypes []string) {
 	conf, err := ctrl.GetConfig()
 	if err != nil {
 		log.Fatalf("unable to get controller runtime configuration, err: %s", err)
@@ -51,9 +70,22 @@ func RunWebhooks(ctx context.Context, enableLeaderElection bool) {
 		}
 		if err = ctrl.NewWebhookManagedBy(mgr).
 			For(&subscriptionsapiV2alpha1.Subscription{}).
+			WithValidator(validation.NewSubscriptionValidator()).
 			Complete(); err != nil {
 			log.Fatalf("unable to create webhook Subscriptions v2alpha1: %v", err)
 		}
+		if err = ctrl.NewWebhookManagedBy(mgr).
+			For(&componentsapi.Component{}).
+			WithValidator(validation.NewComponentValidator(mgr.GetAPIReader(), additionalComponentTypes)).
+			Complete(); err != nil {
+			log.Fatalf("unable to create webhook Components v1alpha1: %v", err)
+		}
+		if err = ctrl.NewWebhookManagedBy(mgr).
+			For(&configurationapi.Configuration{}).
+			WithValidator(validation.NewConfigurationValidator()).
+			Complete(); err != nil {
+			log.Fatalf("unable to create webhook Configurations v1alpha1: %v", err)
+		}
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -63,7 +95,16 @@ func RunWebhooks(ctx context.Context, enableLeaderElection bool) {
 		log.Fatalf("unable to set up ready check: %v", err)
 	}
 
-	go patchCRDs(ctx, conf, "subscriptions.dapr.io")
+	go patchCRDs(ctx, conf, conversionCRDs...)
+	go func() {
+		// Only the leader migrates the stored objects, once the conversion webhook is served.
+		select {
+		case <-mgr.Elected():
+			migrateCRDs(ctx, conf, conversionCRDs...)
+		case <-ctx.Done():
+		}
+	}()
+	go patchValidatingWebhookConfiguration(ctx, conf, validatingWebhookConfigurationName)
 
 	log.Info("starting webhooks")
 	if err := mgr.Start(ctx); err != nil {
@@ -86,11 +127,111 @@ func patchCRDs(ctx context.Context, conf *rest.Config, crdNames ...string) {
 	}
 
 	crdClient := clientSet.ApiextensionsV1().CustomResourceDefinitions()
-	namespace := os.Getenv("NAMESPACE")
+	namespace, caBundle, ok := getWebhookCABundle(ctx, client)
+	if !ok {
+		return
+	}
+
+	for _, crdName := range crdNames {
+		if err := patchCRDConversion(ctx, crdClient, crdName, namespace, caBundle); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// patchCRDConversion sets the conversion webhook of a CRD served in multiple versions to the webhook of the operator.
+// The conversion is added if the CRD doesn't have one, so that it doesn't depend on how the CRD was installed.
+func patchCRDConversion(ctx context.Context, crdClient apiextensionsv1client.CustomResourceDefinitionInterface, crdName, namespace string, caBundle []byte) error {
+	crd, err := crdClient.Get(ctx, crdName, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("could not get CRD %q: %w", crdName, err)
+	}
+
+	if len(crd.Spec.Versions) < 2 {
+		log.Debugf("CRD %q is served in a single version, no conversion webhook needed", crdName)
+
+		return nil
+	}
+
+	conversion := crd.Spec.Conversion
+	if conversion != nil &&
+		conversion.Strategy == apiextensionsv1.WebhookConverter &&
+		conversion.Webhook != nil &&
+		conversion.Webhook.ClientConfig != nil &&
+		conversion.Webhook.ClientConfig.Service != nil &&
+		conversion.Webhook.ClientConfig.Service.Namespace == namespace &&
+		bytes.Equal(conversion.Webhook.ClientConfig.CABundle, caBundle) {
+		log.Infof("Conversion webhook for %q is up to date", crdName)
+
+		return nil
+	}
+
+	// This code mimics:
+	// kubectl patch crd "subscriptions.dapr.io" --type='json' -p [{'op': 'add', 'path': '/spec/conversion', 'value': {'strategy': 'Webhook', 'webhook': ...}}]"
+	type patchValue struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}
+	payload := []patchValue{{
+		Op:    "add",
+		Path:  "/spec/conversion",
+		Value: conversionWebhook(conversion, namespace, caBundle),
+	}}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not marshal webhook spec: %w", err)
+	}
+	_, err = crdClient.Patch(ctx, crdName, types.JSONPatchType, payloadJSON, v1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch webhook in CRD %q: %w", crdName, err)
+	}
+
+	log.Infof("Successfully patched webhook in CRD %q", crdName)
+
+	return nil
+}
+
+// conversionWebhook returns the conversion of a CRD through the webhook of the operator,
+// keeping the service name, path and review versions of the existing conversion if any.
+func conversionWebhook(existing *apiextensionsv1.CustomResourceConversion, namespace string, caBundle []byte) *apiextensionsv1.CustomResourceConversion {
+	path := conversionWebhookPath
+	service := &apiextensionsv1.ServiceReference{
+		Namespace: namespace,
+		Name:      webhookServiceName,
+		Path:      &path,
+	}
+	reviewVersions := []string{"v1"}
+	if existing != nil && existing.Webhook != nil {
+		if cc := existing.Webhook.ClientConfig; cc != nil && cc.Service != nil {
+			service = cc.Service.DeepCopy()
+			service.Namespace = namespace
+		}
+		if len(existing.Webhook.ConversionReviewVersions) > 0 {
+			reviewVersions = existing.Webhook.ConversionReviewVersions
+		}
+	}
+
+	return &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service:  service,
+				CABundle: caBundle,
+			},
+			ConversionReviewVersions: reviewVersions,
+		},
+	}
+}
+
+// getWebhookCABundle returns the namespace Dapr is running in and the CA bundle of the webhooks' certificate.
+func getWebhookCABundle(ctx context.Context, client kubernetes.Interface) (namespace string, caBundle []byte, ok bool) {
+	namespace = os.Getenv("NAMESPACE")
 	if namespace == "" {
 		log.Error("Could not get dapr namespace")
 
-		return
+		return "", nil, false
 	}
 
 	si, err := client.CoreV1().Secrets(namespace).Get(ctx, webhookCAName, v1.GetOptions{})
@@ -98,72 +239,60 @@ func patchCRDs(ctx context.Context, conf *rest.Config, crdNames ...string) {
 		log.Debugf("Could not get webhook CA: %v", err)
 		log.Info("The webhook CA secret was not found. Assuming conversion webhook caBundles are managed manually.")
 
-		return
+		return "", nil, false
 	}
 
-	caBundle, ok := si.Data["caBundle"]
+	caBundle, ok = si.Data["caBundle"]
 	if !ok {
 		log.Error("Webhook CA secret did not contain 'caBundle'")
 
-		return
+		return "", nil, false
 	}
 
-	for _, crdName := range crdNames {
-		crd, err := crdClient.Get(ctx, crdName, v1.GetOptions{})
-		if err != nil {
-			log.Errorf("Could not get CRD %q: %v", crdName, err)
+	return namespace, caBundle, true
+}
 
-			continue
-		}
+// patchValidatingWebhookConfiguration sets the caBundle in all webhooks of the ValidatingWebhookConfiguration, if it exists.
+func patchValidatingWebhookConfiguration(ctx context.Context, conf *rest.Config, name string) {
+	client, err := kubernetes.NewForConfig(conf)
+	if err != nil {
+		log.Errorf("Could not get Kubernetes API client: %v", err)
 
-		if crd == nil ||
-			crd.Spec.Conversion == nil ||
-			crd.Spec.Conversion.Webhook == nil ||
-			crd.Spec.Conversion.Webhook.ClientConfig == nil {
-			log.Errorf("CRD %q does not have an existing webhook client config. Applying resources of this type will fail.", crdName)
+		return
+	}
 
-			continue
-		}
+	_, caBundle, ok := getWebhookCABundle(ctx, client)
+	if !ok {
+		return
+	}
 
-		if crd.Spec.Conversion.Webhook.ClientConfig.Service != nil &&
-			crd.Spec.Conversion.Webhook.ClientConfig.Service.Namespace == namespace &&
-			crd.Spec.Conversion.Webhook.ClientConfig.CABundle != nil &&
-			bytes.Equal(crd.Spec.Conversion.Webhook.ClientConfig.CABundle, caBundle) {
-			log.Infof("Conversion webhook for %q is up to date", crdName)
+	whClient := client.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	whConf, err := whClient.Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		log.Infof("ValidatingWebhookConfiguration %q not found; validation of Dapr resources is disabled", name)
 
-			continue
-		}
+		return
+	}
 
-		// This code mimics:
-		// kubectl patch crd "subscriptions.dapr.io" --type='json' -p [{'op': 'replace', 'path': '/spec/conversion/webhook/clientConfig/service/namespace', 'value':'${namespace}'},{'op': 'add', 'path': '/spec/conversion/webhook/clientConfig/caBundle', 'value':'${caBundle}'}]"
-		type patchValue struct {
-			Op    string      `json:"op"`
-			Path  string      `json:"path"`
-			Value interface{} `json:"value"`
+	changed := false
+	for i := range whConf.Webhooks {
+		if !bytes.Equal(whConf.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			whConf.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
 		}
-		payload := []patchValue{{
-			Op:    "replace",
-			Path:  "/spec/conversion/webhook/clientConfig/service/namespace",
-			Value: namespace,
-		}, {
-			Op:    "replace",
-			Path:  "/spec/conversion/webhook/clientConfig/caBundle",
-			Value: caBundle,
-		}}
-
-		payloadJSON, err := json.Marshal(payload)
-		if err != nil {
-			log.Errorf("Could not marshal webhook spec: %v", err)
-
-			continue
-		}
-		_, err = crdClient.Patch(ctx, crdName, types.JSONPatchType, payloadJSON, v1.PatchOptions{})
-		if err != nil {
-			log.Errorf("Failed to patch webhook in CRD %q: %v", crdName, err)
+	}
+	if !changed {
+		log.Infof("Validating webhook %q is up to date", name)
 
-			continue
-		}
+		return
+	}
 
-		log.Infof("Successfully patched webhook in CRD %q", crdName)
+	_, err = whClient.Update(ctx, whConf, v1.UpdateOptions{})
+	if err != nil {
+		log.Errorf("Failed to patch validating webhook %q: %v", name, err)
+
+		return
 	}
+
+	log.Infof("Successfully patched validating webhook %q", name)
 }
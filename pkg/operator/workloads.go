@@ -0,0 +1,242 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dapr/dapr/utils"
+)
+
+const (
+	// WorkloadsPath is the path of the API listing the workloads with Dapr annotations.
+	WorkloadsPath = "/v1.0/workloads"
+
+	// Prefix of the annotations of the Dapr sidecar.
+	daprAnnotationPrefix = "dapr.io/"
+)
+
+// Workload is a deployment or a statefulset with Dapr annotations in its pod template.
+type Workload struct {
+	Kind        string            `json:"kind"`
+	Namespace   string            `json:"namespace"`
+	Name        string            `json:"name"`
+	AppID       string            `json:"appID"`
+	Enabled     bool              `json:"enabled"`
+	Config      string            `json:"config,omitempty"`
+	Annotations map[string]string `json:"annotations"`
+	Created     time.Time         `json:"created"`
+	// Versions of the sidecars injected in the pods of the workload, sorted.
+	SidecarVersions []string  `json:"sidecarVersions"`
+	Pods            []Sidecar `json:"pods"`
+}
+
+// Sidecar is the state of a pod of a workload.
+type Sidecar struct {
+	Name string `json:"name"`
+	// Injected is false for the pods without the sidecar container.
+	Injected bool   `json:"injected"`
+	Image    string `json:"image,omitempty"`
+	Ready    bool   `json:"ready"`
+}
+
+// WorkloadsServer serves the read-only API listing the workloads with Dapr annotations, so that the dashboards and the CLI
+// don't need to list the pods and the deployments of the cluster themselves.
+// This server runs on all the replicas, and reads the objects from the cache of the manager.
+type WorkloadsServer struct {
+	port   int
+	client client.Client
+}
+
+// NeedLeaderElection makes it so the server runs on all the replicas.
+// Implements sigs.k8s.io/controller-runtime/pkg/manager.LeaderElectionRunnable .
+func (ws *WorkloadsServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start the server. This method blocks until the context is canceled.
+// Implements sigs.k8s.io/controller-runtime/pkg/manager.Runnable .
+func (ws *WorkloadsServer) Start(ctx context.Context) error {
+	if ws.port <= 0 {
+		log.Infof("WorkloadsServer is not enabled")
+		return nil
+	}
+
+	router := http.NewServeMux()
+	router.HandleFunc(WorkloadsPath, ws.onListWorkloads)
+	//nolint:gosec
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", ws.port),
+		Handler: router,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("Error while shutting down workloads server: %v", err)
+		}
+	}()
+
+	log.Infof("WorkloadsServer is listening on %s", srv.Addr)
+
+	err := srv.ListenAndServe()
+	if err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (ws *WorkloadsServer) onListWorkloads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	workloads, err := ws.listWorkloads(r.Context(), r.URL.Query().Get("namespace"))
+	if err != nil {
+		log.Errorf("Failed to list the workloads. Error: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(workloads)
+}
+
+// listWorkloads returns the workloads with Dapr annotations of the namespace, or of all the namespaces if empty,
+// sorted by namespace, name and kind.
+func (ws *WorkloadsServer) listWorkloads(ctx context.Context, namespace string) ([]Workload, error) {
+	deployments := &appsv1.DeploymentList{}
+	if err := ws.client.List(ctx, deployments, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := ws.client.List(ctx, statefulSets, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	pods := &corev1.PodList{}
+	if err := ws.client.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	workloads := []Workload{}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if wl, ok := newWorkload("Deployment", d.ObjectMeta, d.Spec.Selector, d.Spec.Template, pods.Items); ok {
+			workloads = append(workloads, wl)
+		}
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		if wl, ok := newWorkload("StatefulSet", s.ObjectMeta, s.Spec.Selector, s.Spec.Template, pods.Items); ok {
+			workloads = append(workloads, wl)
+		}
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Namespace != workloads[j].Namespace {
+			return workloads[i].Namespace < workloads[j].Namespace
+		}
+		if workloads[i].Name != workloads[j].Name {
+			return workloads[i].Name < workloads[j].Name
+		}
+		return workloads[i].Kind < workloads[j].Kind
+	})
+	return workloads, nil
+}
+
+// newWorkload returns the workload with its pods, or false if the pod template has no Dapr annotation.
+func newWorkload(kind string, meta metav1.ObjectMeta, selector *metav1.LabelSelector, template corev1.PodTemplateSpec, pods []corev1.Pod) (Workload, bool) {
+	annotations := map[string]string{}
+	for k, v := range template.Annotations {
+		if strings.HasPrefix(k, daprAnnotationPrefix) {
+			annotations[k] = v
+		}
+	}
+	if len(annotations) == 0 {
+		return Workload{}, false
+	}
+
+	wl := Workload{
+		Kind:            kind,
+		Namespace:       meta.Namespace,
+		Name:            meta.Name,
+		AppID:           annotations[appIDAnnotationKey],
+		Enabled:         utils.IsTruthy(annotations[daprEnabledAnnotationKey]),
+		Config:          annotations[daprConfigAnnotationKey],
+		Annotations:     annotations,
+		Created:         meta.CreationTimestamp.Time,
+		SidecarVersions: []string{},
+		Pods:            []Sidecar{},
+	}
+	if wl.AppID == "" {
+		wl.AppID = meta.Name
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || sel.Empty() {
+		return wl, true
+	}
+	versions := map[string]struct{}{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Namespace != meta.Namespace || !sel.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		sidecar := newSidecar(pod)
+		if sidecar.Injected {
+			versions[imageVersion(sidecar.Image)] = struct{}{}
+		}
+		wl.Pods = append(wl.Pods, sidecar)
+	}
+	for v := range versions {
+		wl.SidecarVersions = append(wl.SidecarVersions, v)
+	}
+	sort.Strings(wl.SidecarVersions)
+	sort.Slice(wl.Pods, func(i, j int) bool {
+		return wl.Pods[i].Name < wl.Pods[j].Name
+	})
+	return wl, true
+}
+
+func newSidecar(pod *corev1.Pod) Sidecar {
+	sidecar := Sidecar{Name: pod.Name}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == sidecarContainerName {
+			sidecar.Injected = true
+			sidecar.Image = c.Image
+			break
+		}
+	}
+	for _, s := range pod.Status.ContainerStatuses {
+		if s.Name == sidecarContainerName {
+			sidecar.Ready = s.Ready
+			break
+		}
+	}
+	return sidecar
+}
+
+// imageVersion returns the tag of the image, or "latest" if it has none.
+func imageVersion(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
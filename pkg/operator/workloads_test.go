@@ -0,0 +1,121 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newWorkloadsServer(t *testing.T, objs ...client.Object) *WorkloadsServer {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	return &WorkloadsServer{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+	}
+}
+
+func newWorkloadPod(name, app, image string, ready bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:1"}}},
+	}
+	if image != "" {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: sidecarContainerName, Image: image})
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: sidecarContainerName, Ready: ready}}
+	}
+	return pod
+}
+
+func TestListWorkloads(t *testing.T) {
+	deployment := newDeployment("app", map[string]string{
+		daprEnabledAnnotationKey: "true",
+		appIDAnnotationKey:       "myapp",
+		daprConfigAnnotationKey:  "appconfig",
+		"prometheus.io/scrape":   "true",
+	})
+	deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "app"}}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "other"},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{daprEnabledAnnotationKey: "false"}},
+			},
+		},
+	}
+	plain := newDeployment("plain", nil)
+
+	ws := newWorkloadsServer(t,
+		deployment, statefulSet, plain,
+		newWorkloadPod("app-2", "app", "daprio/daprd:1.9.0", false),
+		newWorkloadPod("app-1", "app", "docker.io:5000/daprio/daprd:1.8.4", true),
+		newWorkloadPod("app-3", "app", "", false),
+		newWorkloadPod("plain-1", "plain", "", false),
+	)
+
+	t.Run("all namespaces", func(t *testing.T) {
+		workloads, err := ws.listWorkloads(context.Background(), "")
+		require.NoError(t, err)
+		require.Len(t, workloads, 2)
+
+		app := workloads[0]
+		assert.Equal(t, "Deployment", app.Kind)
+		assert.Equal(t, "myapp", app.AppID)
+		assert.True(t, app.Enabled)
+		assert.Equal(t, "appconfig", app.Config)
+		assert.Equal(t, map[string]string{
+			daprEnabledAnnotationKey: "true",
+			appIDAnnotationKey:       "myapp",
+			daprConfigAnnotationKey:  "appconfig",
+		}, app.Annotations)
+		assert.Equal(t, []string{"1.8.4", "1.9.0"}, app.SidecarVersions)
+		assert.Equal(t, []Sidecar{
+			{Name: "app-1", Injected: true, Image: "docker.io:5000/daprio/daprd:1.8.4", Ready: true},
+			{Name: "app-2", Injected: true, Image: "daprio/daprd:1.9.0"},
+			{Name: "app-3"},
+		}, app.Pods)
+
+		db := workloads[1]
+		assert.Equal(t, "StatefulSet", db.Kind)
+		assert.Equal(t, "db", db.AppID)
+		assert.False(t, db.Enabled)
+		assert.Empty(t, db.Pods)
+	})
+
+	t.Run("one namespace over HTTP", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, WorkloadsPath+"?namespace=other", nil)
+		w := httptest.NewRecorder()
+		ws.onListWorkloads(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var workloads []Workload
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &workloads))
+		require.Len(t, workloads, 1)
+		assert.Equal(t, "db", workloads[0].Name)
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ws.onListWorkloads(w, httptest.NewRequest(http.MethodPost, WorkloadsPath, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestImageVersion(t *testing.T) {
+	assert.Equal(t, "1.9.0", imageVersion("daprio/daprd:1.9.0"))
+	assert.Equal(t, "1.9.0", imageVersion("localhost:5000/daprd:1.9.0"))
+	assert.Equal(t, "latest", imageVersion("localhost:5000/daprd"))
+	assert.Equal(t, "sha256:abc", imageVersion("daprio/daprd@sha256:abc"))
+}
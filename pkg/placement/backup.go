@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"bytes"
+
+	"github.com/dapr/dapr/pkg/backup"
+)
+
+// backupSource exports and imports the placement state for the control plane backups.
+type backupSource struct {
+	raftNode Backend
+}
+
+// NewBackupSource returns the source of the placement state for the control plane backups.
+func NewBackupSource(raftNode Backend) backup.Source {
+	return &backupSource{
+		raftNode: raftNode,
+	}
+}
+
+// Backup adds a snapshot of the placement state to the bundle.
+func (s *backupSource) Backup(b *backup.Bundle) error {
+	var buf bytes.Buffer
+	if err := s.raftNode.Backup(&buf); err != nil {
+		return err
+	}
+	b.Placement = buf.Bytes()
+	return nil
+}
+
+// Restore replaces the placement state with the snapshot in the bundle.
+// This only succeeds on the leader of the placement servers.
+func (s *backupSource) Restore(b *backup.Bundle) error {
+	if b.Placement == nil {
+		return backup.ErrNotInBundle
+	}
+	return s.raftNode.Restore(bytes.NewReader(b.Placement))
+}
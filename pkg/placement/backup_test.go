@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/backup"
+	"github.com/dapr/dapr/pkg/placement/raft"
+	daprtesting "github.com/dapr/dapr/pkg/testing"
+)
+
+func TestBackupSource(t *testing.T) {
+	ports, err := daprtesting.GetFreePorts(1)
+	require.NoError(t, err)
+
+	peers := []raft.PeerInfo{
+		{
+			ID:      "mynode-0",
+			Address: fmt.Sprintf("127.0.0.1:%d", ports[0]),
+		},
+	}
+	raftServer := createRaftServer(t, 0, peers)
+	defer raftServer.Shutdown()
+	findLeader(t, []*raft.Server{raftServer})
+
+	member := &raft.DaprHostMember{
+		Name:     "127.0.0.1:3031",
+		AppID:    "testmember1",
+		Entities: []string{"red"},
+	}
+	_, err = raftServer.ApplyCommand(raft.MemberUpsert, *member)
+	require.NoError(t, err)
+
+	source := NewBackupSource(raftServer)
+
+	b := backup.NewBundle()
+	require.NoError(t, source.Backup(b))
+	assert.NotEmpty(t, b.Placement)
+
+	t.Run("restore the state", func(t *testing.T) {
+		_, err = raftServer.ApplyCommand(raft.MemberRemove, *member)
+		require.NoError(t, err)
+		assert.Empty(t, raftServer.FSM().State().Members())
+
+		require.NoError(t, source.Restore(b))
+		retrieveValidState(t, raftServer, member)
+	})
+
+	t.Run("bundle without placement state", func(t *testing.T) {
+		err := source.Restore(backup.NewBundle())
+		assert.ErrorIs(t, err, backup.ErrNotInBundle)
+	})
+
+	t.Run("invalid snapshot", func(t *testing.T) {
+		err := source.Restore(&backup.Bundle{Placement: []byte("invalid")})
+		assert.Error(t, err)
+		retrieveValidState(t, raftServer, member)
+	})
+}
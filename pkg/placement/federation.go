@@ -0,0 +1,355 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	daprCredentials "github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/placement/hashing"
+	"github.com/dapr/dapr/pkg/placement/raft"
+	placementv1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
+	"github.com/dapr/dapr/pkg/runtime/security"
+)
+
+const (
+	// FederationLocalityAny places the actor types on the hosts of all the clusters.
+	FederationLocalityAny = "any"
+	// FederationLocalityLocal prefers the hosts of the local cluster for the routing of the calls.
+	// The placement tables are still built from the hosts of all the clusters,
+	// so that every cluster places an actor on the same host.
+	FederationLocalityLocal = "local"
+
+	// federationSyncInterval is the interval of the synchronization of the members of the federated clusters.
+	federationSyncInterval = 5 * time.Second
+	// federationSyncTimeout is the timeout of the calls to the placement services of the federated clusters.
+	federationSyncTimeout = 3 * time.Second
+	// federationStaleTimeout is the duration after which the members of a federated cluster that can't be reached are removed.
+	federationStaleTimeout = 30 * time.Second
+)
+
+// FederationOptions are the options of the federation of the placement tables with the placement services of other clusters.
+type FederationOptions struct {
+	// ClusterID is the ID of the cluster of the placement service.
+	ClusterID string
+	// Peers are the addresses of the placement services of the federated clusters.
+	Peers []string
+	// Locality is the routing preference for the hosts of the local cluster: FederationLocalityAny or FederationLocalityLocal.
+	// It doesn't change the placement tables, which are the same in all the clusters.
+	Locality string
+	// CertChain is used to authenticate with the placement services of the federated clusters.
+	// The federation requires mTLS, as the callers of GetClusterMembers are authorized by their certificate.
+	CertChain *daprCredentials.CertChain
+}
+
+// federation replicates the members of the placement services of other clusters,
+// so that the actors hosted in the other clusters are invoked through the internal gRPC channel.
+type federation struct {
+	opts FederationOptions
+
+	lock     sync.RWMutex
+	clusters map[string]*federatedCluster
+	// generation is increased whenever the members of the federated clusters change.
+	generation uint64
+
+	connsLock sync.Mutex
+	conns     map[string]*grpc.ClientConn
+}
+
+// federatedCluster holds the members of a federated cluster.
+type federatedCluster struct {
+	hosts     []*placementv1pb.Host
+	updatedAt time.Time
+}
+
+func newFederation(opts FederationOptions) (*federation, error) {
+	if opts.ClusterID == "" {
+		return nil, fmt.Errorf("the cluster ID is required for the federation")
+	}
+	switch opts.Locality {
+	case "":
+		opts.Locality = FederationLocalityAny
+	case FederationLocalityLocal, FederationLocalityAny:
+	default:
+		return nil, fmt.Errorf("invalid federation locality %q: must be %q or %q", opts.Locality, FederationLocalityLocal, FederationLocalityAny)
+	}
+	return &federation{
+		opts:     opts,
+		clusters: map[string]*federatedCluster{},
+		conns:    map[string]*grpc.ClientConn{},
+	}, nil
+}
+
+// EnableFederation federates the placement tables with the placement services of other clusters.
+// It must be called before the service is started.
+func (p *Service) EnableFederation(opts FederationOptions) error {
+	if opts.CertChain == nil {
+		return fmt.Errorf("the federation requires mTLS")
+	}
+	f, err := newFederation(opts)
+	if err != nil {
+		return err
+	}
+	p.federation = f
+	log.Infof("placement tables federated with %d peers as cluster %s, locality: %s", len(opts.Peers), opts.ClusterID, f.opts.Locality)
+	return nil
+}
+
+// GetClusterMembers returns the actor hosts of the local cluster to the placement services of the federated clusters.
+// The hosts of the federated clusters aren't returned, so that the members are only replicated from their own cluster.
+// Only the placement services are allowed to call it, the runtimes can't list the hosts of the cluster.
+func (p *Service) GetClusterMembers(ctx context.Context, req *placementv1pb.GetClusterMembersRequest) (*placementv1pb.ClusterMembers, error) {
+	if p.federation == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the federation is not enabled")
+	}
+	if !isPlacementPeer(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "only the placement services of the federated clusters can get the cluster members")
+	}
+	if !p.hasLeadership.Load() {
+		return nil, status.Error(codes.FailedPrecondition, "only leader can serve the request")
+	}
+
+	members := p.raftNode.FSM().State().Members()
+	resp := &placementv1pb.ClusterMembers{
+		ClusterId: p.federation.opts.ClusterID,
+		Hosts:     make([]*placementv1pb.Host, 0, len(members)),
+	}
+	for _, m := range members {
+		resp.Hosts = append(resp.Hosts, &placementv1pb.Host{
+			Name:      m.Name,
+			Id:        m.AppID,
+			Entities:  m.Entities,
+			ClusterId: p.federation.opts.ClusterID,
+		})
+	}
+	sort.Slice(resp.Hosts, func(i, j int) bool { return resp.Hosts[i].Name < resp.Hosts[j].Name })
+	log.Debugf("returning %d members to the federated cluster %s", len(resp.Hosts), req.ClusterId)
+	return resp, nil
+}
+
+// isPlacementPeer returns true if the caller authenticated with the certificate of a control plane service.
+// The runtimes authenticate with workload certificates, which have a SPIFFE ID,
+// while the control plane services use the issuer certificate, which is valid for the TLS server name.
+func isPlacementPeer(ctx context.Context) bool {
+	pr, ok := peer.FromContext(ctx)
+	if !ok || pr.AuthInfo == nil {
+		return false
+	}
+	tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return false
+	}
+	return daprCredentials.IsControlPlaneCertificate(tlsInfo.State.VerifiedChains[0][0], security.TLSServerName)
+}
+
+// placementTables returns the placement tables disseminated to the runtimes,
+// which include the hosts of the federated clusters if the federation is enabled.
+func (p *Service) placementTables() *placementv1pb.PlacementTables {
+	tables := p.raftNode.FSM().PlacementState()
+	if p.federation == nil {
+		return tables
+	}
+	return p.federation.placementTables(p.raftNode.FSM().State().Members(), tables.Version)
+}
+
+// federationWorker synchronizes the members of the federated clusters while the service is the leader.
+func (p *Service) federationWorker(stopCh chan struct{}) {
+	if p.federation == nil {
+		return
+	}
+
+	ticker := time.NewTicker(federationSyncInterval)
+	defer ticker.Stop()
+	defer p.federation.close()
+
+	for {
+		if p.hasLeadership.Load() && p.federation.sync() {
+			p.memberUpdateCount.Inc()
+			p.disseminateNextTime.Store(time.Now().Add(disseminateTimeout).UnixNano())
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-p.shutdownCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync fetches the members of the federated clusters and returns true if they changed.
+func (f *federation) sync() bool {
+	now := time.Now()
+	fetched := map[string][]*placementv1pb.Host{}
+	for _, peer := range f.opts.Peers {
+		resp, err := f.fetch(peer)
+		if err != nil {
+			// The followers of the federated clusters refuse the calls
+			log.Debugf("failed to get the members of the federated placement service %s: %s", peer, err)
+			continue
+		}
+		if resp.ClusterId == "" || resp.ClusterId == f.opts.ClusterID {
+			log.Warnf("ignoring the members of the federated placement service %s with the cluster ID %q", peer, resp.ClusterId)
+			continue
+		}
+		for _, h := range resp.Hosts {
+			h.ClusterId = resp.ClusterId
+		}
+		fetched[resp.ClusterId] = resp.Hosts
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	changed := false
+	for clusterID, hosts := range fetched {
+		c, ok := f.clusters[clusterID]
+		if !ok || !hostsEqual(c.hosts, hosts) {
+			log.Infof("members of the federated cluster %s updated: %d hosts", clusterID, len(hosts))
+			changed = true
+		}
+		f.clusters[clusterID] = &federatedCluster{hosts: hosts, updatedAt: now}
+	}
+	for clusterID, c := range f.clusters {
+		if now.Sub(c.updatedAt) > federationStaleTimeout {
+			log.Warnf("removing the members of the federated cluster %s, unreachable since %s", clusterID, c.updatedAt)
+			delete(f.clusters, clusterID)
+			changed = true
+		}
+	}
+	if changed {
+		f.generation++
+	}
+	return changed
+}
+
+func (f *federation) fetch(peer string) (*placementv1pb.ClusterMembers, error) {
+	conn, err := f.conn(peer)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), federationSyncTimeout)
+	defer cancel()
+	return placementv1pb.NewPlacementClient(conn).GetClusterMembers(ctx, &placementv1pb.GetClusterMembersRequest{
+		ClusterId: f.opts.ClusterID,
+	})
+}
+
+func (f *federation) conn(peer string) (*grpc.ClientConn, error) {
+	f.connsLock.Lock()
+	defer f.connsLock.Unlock()
+
+	if conn, ok := f.conns[peer]; ok {
+		return conn, nil
+	}
+	opts, err := daprCredentials.GetClientOptions(f.opts.CertChain, security.TLSServerName)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(peer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	f.conns[peer] = conn
+	return conn, nil
+}
+
+// close closes the connections to the federated placement services and forgets their members,
+// which are fetched again when the service gains the leadership.
+func (f *federation) close() {
+	f.connsLock.Lock()
+	for peer, conn := range f.conns {
+		conn.Close()
+		delete(f.conns, peer)
+	}
+	f.connsLock.Unlock()
+
+	f.lock.Lock()
+	f.clusters = map[string]*federatedCluster{}
+	f.lock.Unlock()
+}
+
+// placementTables returns the placement tables of the local members and the members of the federated clusters.
+func (f *federation) placementTables(localMembers map[string]*raft.DaprHostMember, localVersion string) *placementv1pb.PlacementTables {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	local := map[string][]*placementv1pb.Host{}
+	for _, m := range localMembers {
+		for _, e := range m.Entities {
+			local[e] = append(local[e], &placementv1pb.Host{Name: m.Name, Id: m.AppID, ClusterId: f.opts.ClusterID})
+		}
+	}
+	remote := map[string][]*placementv1pb.Host{}
+	for _, c := range f.clusters {
+		for _, h := range c.hosts {
+			for _, e := range h.Entities {
+				remote[e] = append(remote[e], h)
+			}
+		}
+	}
+
+	// The hosts of all the clusters are used for every actor type, whatever the locality,
+	// so that the tables of all the clusters place an actor on the same host.
+	hostsByType := make(map[string][]*placementv1pb.Host, len(local)+len(remote))
+	for e, hosts := range local {
+		hostsByType[e] = hosts
+	}
+	for e, hosts := range remote {
+		hostsByType[e] = append(hostsByType[e], hosts...)
+	}
+
+	tables := &placementv1pb.PlacementTables{
+		Version: fmt.Sprintf("%s.%d", localVersion, f.generation),
+		Entries: make(map[string]*placementv1pb.PlacementTable, len(hostsByType)),
+	}
+	for e, hosts := range hostsByType {
+		clusterIDs := make(map[string]string, len(hosts))
+		hashingTable := hashing.NewConsistentHash()
+		for _, h := range hosts {
+			hashingTable.Add(h.Name, h.Id, 0)
+			clusterIDs[h.Name] = h.ClusterId
+		}
+		table := raft.PlacementTable(hashingTable)
+		for name, h := range table.LoadMap {
+			h.ClusterId = clusterIDs[name]
+		}
+		tables.Entries[e] = table
+	}
+	return tables
+}
+
+func hostsEqual(a, b []*placementv1pb.Host) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	daprCredentials "github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/placement/raft"
+	v1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
+)
+
+type fakeFederatedPlacement struct {
+	v1pb.UnimplementedPlacementServer
+
+	members *v1pb.ClusterMembers
+}
+
+func (f *fakeFederatedPlacement) GetClusterMembers(ctx context.Context, req *v1pb.GetClusterMembersRequest) (*v1pb.ClusterMembers, error) {
+	if f.members == nil {
+		return nil, status.Error(codes.FailedPrecondition, "only leader can serve the request")
+	}
+	return f.members, nil
+}
+
+func newFakeFederatedPlacement(t *testing.T, members *v1pb.ClusterMembers) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	v1pb.RegisterPlacementServer(server, &fakeFederatedPlacement{members: members})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func TestNewFederation(t *testing.T) {
+	f, err := newFederation(FederationOptions{ClusterID: "west"})
+	require.NoError(t, err)
+	assert.Equal(t, FederationLocalityAny, f.opts.Locality)
+
+	_, err = newFederation(FederationOptions{})
+	assert.Error(t, err)
+
+	_, err = newFederation(FederationOptions{ClusterID: "west", Locality: "nearest"})
+	assert.Error(t, err)
+}
+
+func TestFederationSync(t *testing.T) {
+	east := newFakeFederatedPlacement(t, &v1pb.ClusterMembers{
+		ClusterId: "east",
+		Hosts:     []*v1pb.Host{{Name: "10.1.0.1:50002", Id: "cart", Entities: []string{"CartActor"}}},
+	})
+	follower := newFakeFederatedPlacement(t, nil)
+	self := newFakeFederatedPlacement(t, &v1pb.ClusterMembers{ClusterId: "west"})
+
+	f, err := newFederation(FederationOptions{ClusterID: "west", Peers: []string{east, follower, self}})
+	require.NoError(t, err)
+	defer f.close()
+
+	assert.True(t, f.sync())
+	require.Len(t, f.clusters, 1)
+	require.Len(t, f.clusters["east"].hosts, 1)
+	assert.Equal(t, "east", f.clusters["east"].hosts[0].ClusterId)
+
+	// The table generation is only increased when the members change
+	assert.False(t, f.sync())
+	assert.Equal(t, uint64(1), f.generation)
+
+	// The members of the clusters that can't be reached are eventually removed
+	f.clusters["east"].updatedAt = time.Now().Add(-2 * federationStaleTimeout)
+	f.opts.Peers = []string{follower}
+	assert.True(t, f.sync())
+	assert.Empty(t, f.clusters)
+}
+
+func TestFederationPlacementTables(t *testing.T) {
+	local := map[string]*raft.DaprHostMember{
+		"10.0.0.1:50002": {Name: "10.0.0.1:50002", AppID: "order", Entities: []string{"OrderActor"}},
+	}
+	remote := map[string]*federatedCluster{
+		"east": {hosts: []*v1pb.Host{
+			{Name: "10.1.0.1:50002", Id: "order", Entities: []string{"OrderActor"}, ClusterId: "east"},
+			{Name: "10.1.0.2:50002", Id: "cart", Entities: []string{"CartActor"}, ClusterId: "east"},
+		}},
+	}
+
+	for _, locality := range []string{FederationLocalityAny, FederationLocalityLocal} {
+		t.Run(locality+" locality", func(t *testing.T) {
+			f, err := newFederation(FederationOptions{ClusterID: "west", Locality: locality})
+			require.NoError(t, err)
+			f.clusters = remote
+			f.generation = 3
+
+			tables := f.placementTables(local, "7")
+			assert.Equal(t, "7.3", tables.Version)
+			require.Len(t, tables.Entries, 2)
+
+			// The actor types are placed on the hosts of all the clusters,
+			// so that the table is the same in every cluster
+			order := tables.Entries["OrderActor"]
+			require.Len(t, order.LoadMap, 2)
+			assert.Equal(t, "west", order.LoadMap["10.0.0.1:50002"].ClusterId)
+			assert.Equal(t, "east", order.LoadMap["10.1.0.1:50002"].ClusterId)
+
+			cart := tables.Entries["CartActor"]
+			require.Len(t, cart.LoadMap, 1)
+			assert.Equal(t, "east", cart.LoadMap["10.1.0.2:50002"].ClusterId)
+			assert.Equal(t, "cart", cart.LoadMap["10.1.0.2:50002"].Id)
+		})
+	}
+
+	t.Run("the tables of the federated clusters are the same", func(t *testing.T) {
+		west, err := newFederation(FederationOptions{ClusterID: "west"})
+		require.NoError(t, err)
+		west.clusters = remote
+
+		east, err := newFederation(FederationOptions{ClusterID: "east", Locality: FederationLocalityLocal})
+		require.NoError(t, err)
+		east.clusters = map[string]*federatedCluster{
+			"west": {hosts: []*v1pb.Host{
+				{Name: "10.0.0.1:50002", Id: "order", Entities: []string{"OrderActor"}, ClusterId: "west"},
+			}},
+		}
+		eastLocal := map[string]*raft.DaprHostMember{}
+		for _, h := range remote["east"].hosts {
+			eastLocal[h.Name] = &raft.DaprHostMember{Name: h.Name, AppID: h.Id, Entities: h.Entities}
+		}
+
+		westTables := west.placementTables(local, "1")
+		eastTables := east.placementTables(eastLocal, "1")
+		for e, table := range westTables.Entries {
+			assert.Equal(t, table.SortedSet, eastTables.Entries[e].SortedSet, e)
+			assert.Equal(t, table.Hosts, eastTables.Entries[e].Hosts, e)
+		}
+	})
+}
+
+// peerContext returns a context with the TLS info of a caller authenticated with the certificate.
+func peerContext(t *testing.T, tmpl *x509.Certificate) context.Context {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl.SerialNumber = big.NewInt(1)
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestGetClusterMembers(t *testing.T) {
+	testServer := NewPlacementService(testRaftServer)
+
+	_, err := testServer.GetClusterMembers(context.Background(), &v1pb.GetClusterMembersRequest{ClusterId: "east"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// The federation requires mTLS
+	assert.Error(t, testServer.EnableFederation(FederationOptions{ClusterID: "west", Peers: []string{"placement.east:50005"}}))
+
+	require.NoError(t, testServer.EnableFederation(FederationOptions{
+		ClusterID: "west",
+		Peers:     []string{"placement.east:50005"},
+		CertChain: &daprCredentials.CertChain{},
+	}))
+	placementCtx := peerContext(t, &x509.Certificate{DNSNames: []string{"cluster.local"}})
+	_, err = testServer.GetClusterMembers(placementCtx, &v1pb.GetClusterMembersRequest{ClusterId: "east"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	testServer.hasLeadership.Store(true)
+
+	// The runtimes and the unauthenticated callers aren't allowed to list the members
+	spiffeID, err := url.Parse("spiffe://cluster.local/ns/default/myapp")
+	require.NoError(t, err)
+	for _, ctx := range []context.Context{
+		context.Background(),
+		peerContext(t, &x509.Certificate{DNSNames: []string{"cluster.local"}, URIs: []*url.URL{spiffeID}}),
+		peerContext(t, &x509.Certificate{DNSNames: []string{"myapp.default.svc.cluster.local"}}),
+	} {
+		_, err = testServer.GetClusterMembers(ctx, &v1pb.GetClusterMembersRequest{ClusterId: "east"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+
+	resp, err := testServer.GetClusterMembers(placementCtx, &v1pb.GetClusterMembersRequest{ClusterId: "east"})
+	require.NoError(t, err)
+	assert.Equal(t, "west", resp.ClusterId)
+	for _, h := range resp.Hosts {
+		assert.Equal(t, "west", h.ClusterId)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hashing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MovedRange is a range of the ring whose keys would be owned by another host.
+// The range goes from Start, excluded, to End, included, and wraps around the ring when End is not greater than Start.
+type MovedRange struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Simulate returns a copy of the ring with the given hosts added and removed, leaving the ring unchanged.
+func (c *Consistent) Simulate(added, removed []string) *Consistent {
+	c.RLock()
+	defer c.RUnlock()
+
+	// The vnodes of the hosts are the ones received from the placement service,
+	// which may use another replication factor than this process.
+	vnodes := replicationFactor
+	if len(c.loadMap) > 0 {
+		vnodes = len(c.sortedSet) / len(c.loadMap)
+	}
+
+	removedHosts := make(map[string]struct{}, len(removed))
+	for _, host := range removed {
+		removedHosts[host] = struct{}{}
+	}
+
+	sim := NewConsistentHash()
+	for name, host := range c.loadMap {
+		if _, ok := removedHosts[name]; !ok {
+			h := *host
+			sim.loadMap[name] = &h
+			sim.totalLoad += h.Load
+		}
+	}
+	for h, host := range c.hosts {
+		if _, ok := removedHosts[host]; !ok {
+			sim.hosts[h] = host
+			sim.sortedSet = append(sim.sortedSet, h)
+		}
+	}
+	for _, host := range added {
+		if _, ok := sim.loadMap[host]; ok {
+			continue
+		}
+		sim.loadMap[host] = &Host{Name: host}
+		for i := 0; i < vnodes; i++ {
+			h := sim.hash(fmt.Sprintf("%s%d", host, i))
+			sim.hosts[h] = host
+			sim.sortedSet = append(sim.sortedSet, h)
+		}
+	}
+	sort.Slice(sim.sortedSet, func(i, j int) bool {
+		return sim.sortedSet[i] < sim.sortedSet[j]
+	})
+
+	return sim
+}
+
+// MovedRanges returns the ranges of the ring whose keys are owned by another host in the other ring.
+// Nothing moves when either ring has no hosts.
+func (c *Consistent) MovedRanges(other *Consistent) []MovedRange {
+	c.RLock()
+	defer c.RUnlock()
+	other.RLock()
+	defer other.RUnlock()
+
+	if len(c.sortedSet) == 0 || len(other.sortedSet) == 0 {
+		return nil
+	}
+
+	boundaries := make([]uint64, 0, len(c.sortedSet)+len(other.sortedSet))
+	boundaries = append(boundaries, c.sortedSet...)
+	boundaries = append(boundaries, other.sortedSet...)
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i] < boundaries[j]
+	})
+	unique := boundaries[:1]
+	for _, b := range boundaries[1:] {
+		if b != unique[len(unique)-1] {
+			unique = append(unique, b)
+		}
+	}
+	boundaries = unique
+
+	// The keys between two consecutive boundaries are all owned by the vnode of the upper boundary, in both rings.
+	var ranges []MovedRange
+	for i, end := range boundaries {
+		start := boundaries[(i+len(boundaries)-1)%len(boundaries)]
+		from := c.hosts[c.sortedSet[c.search(end)]]
+		to := other.hosts[other.sortedSet[other.search(end)]]
+		if from == to {
+			continue
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].End == start && ranges[n-1].From == from && ranges[n-1].To == to {
+			ranges[n-1].End = end
+			continue
+		}
+		ranges = append(ranges, MovedRange{Start: start, End: end, From: from, To: to})
+	}
+
+	// Merge the last range with the first one when they are contiguous across the end of the ring.
+	if n := len(ranges); n > 1 && ranges[n-1].End == ranges[0].Start && ranges[n-1].From == ranges[0].From && ranges[n-1].To == ranges[0].To {
+		ranges[0].Start = ranges[n-1].Start
+		ranges = ranges[:n-1]
+	}
+
+	return ranges
+}
+
+// RingFraction returns the fraction of the ring covered by the ranges, between 0 and 1.
+func RingFraction(ranges []MovedRange) float64 {
+	var fraction float64
+	for _, r := range ranges {
+		if r.Start == r.End {
+			// The range covers the whole ring.
+			return 1
+		}
+		fraction += float64(r.End-r.Start) / math.Exp2(64)
+	}
+	return math.Min(fraction, 1)
+}
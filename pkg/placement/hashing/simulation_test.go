@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hashing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulate(t *testing.T) {
+	SetReplicationFactor(100)
+
+	h := NewConsistentHash()
+	for _, n := range nodes[:4] {
+		h.Add(n, n, 1)
+	}
+
+	t.Run("adding a host", func(t *testing.T) {
+		sim := h.Simulate([]string{"node5"}, nil)
+		assert.Len(t, h.Hosts(), 4)
+		assert.Len(t, sim.Hosts(), 5)
+
+		ranges := h.MovedRanges(sim)
+		assert.NotEmpty(t, ranges)
+		for _, r := range ranges {
+			assert.Equal(t, "node5", r.To)
+			assert.NotEqual(t, "node5", r.From)
+		}
+		fraction := RingFraction(ranges)
+		assert.InDelta(t, 0.2, fraction, 0.1)
+
+		// The simulated ring matches the ring with the host actually added.
+		actual := NewConsistentHash()
+		for _, n := range nodes {
+			actual.Add(n, n, 1)
+		}
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprint(i)
+			expected, _ := actual.Get(key)
+			simulated, _ := sim.Get(key)
+			assert.Equal(t, expected, simulated)
+		}
+	})
+
+	t.Run("removing a host", func(t *testing.T) {
+		sim := h.Simulate(nil, []string{"node1"})
+		assert.Len(t, sim.Hosts(), 3)
+
+		ranges := h.MovedRanges(sim)
+		assert.NotEmpty(t, ranges)
+		for _, r := range ranges {
+			assert.Equal(t, "node1", r.From)
+		}
+		assert.InDelta(t, 0.25, RingFraction(ranges), 0.1)
+
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprint(i)
+			before, _ := h.Get(key)
+			after, _ := sim.Get(key)
+			assert.Equal(t, before == "node1", before != after)
+		}
+	})
+
+	t.Run("no change", func(t *testing.T) {
+		sim := h.Simulate([]string{"node1"}, []string{"unknown"})
+		assert.Empty(t, h.MovedRanges(sim))
+	})
+
+	t.Run("single host replaced", func(t *testing.T) {
+		SetReplicationFactor(1)
+		single := NewConsistentHash()
+		single.Add("node1", "node1", 1)
+
+		ranges := single.MovedRanges(single.Simulate([]string{"node2"}, []string{"node1"}))
+		assert.Len(t, ranges, 1)
+		assert.Equal(t, "node1", ranges[0].From)
+		assert.Equal(t, "node2", ranges[0].To)
+		assert.Equal(t, 1.0, RingFraction(ranges))
+		SetReplicationFactor(100)
+	})
+}
@@ -0,0 +1,435 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lease implements a placement backend for small clusters that doesn't need a raft quorum.
+// The leader is elected with a Kubernetes Lease, and the membership state is stored in a ConfigMap
+// so a new leader can resume from it.
+package lease
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/dapr/kit/logger"
+
+	"github.com/dapr/dapr/pkg/placement/raft"
+)
+
+var log = logger.NewLogger("dapr.placement.lease")
+
+const (
+	// DefaultLeaseName is the default name of the Lease used to elect the leader.
+	DefaultLeaseName = "dapr-placement-leader"
+	// DefaultConfigMapName is the default name of the ConfigMap storing the membership state.
+	DefaultConfigMapName = "dapr-placement-table"
+	// DefaultMaxMembers is the default maximum number of actor hosts in the placement table.
+	DefaultMaxMembers = 50
+
+	// stateKey is the key of the ConfigMap binary data holding the membership state.
+	stateKey = "state"
+
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+
+	// loadRetryInterval is the interval between the attempts to load the state after acquiring the lease.
+	loadRetryInterval = time.Second
+	// saveTimeout is the timeout of the write of the state to the ConfigMap.
+	saveTimeout = 5 * time.Second
+)
+
+// Options are the options of the lease backend.
+type Options struct {
+	// Identity of the placement server in the Lease, unique among the placement servers.
+	Identity string
+	// Namespace of the Lease and of the ConfigMap.
+	Namespace string
+	// LeaseName is the name of the Lease used to elect the leader.
+	LeaseName string
+	// ConfigMapName is the name of the ConfigMap storing the membership state.
+	ConfigMapName string
+	// MaxMembers is the maximum number of actor hosts in the placement table.
+	MaxMembers int
+
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// Server is a placement backend that elects the leader with a Kubernetes Lease, and stores the membership state in a ConfigMap.
+// The state is only kept in memory by the leader, which writes it to the ConfigMap whenever the hashing tables change.
+// As a ConfigMap is limited to 1MiB, the number of actor hosts is limited by Options.MaxMembers.
+type Server struct {
+	opts   Options
+	client kubernetes.Interface
+	fsm    *raft.FSM
+
+	leaderCh chan bool
+	// leaderLock protects isLeader and the notifications sent to leaderCh.
+	leaderLock sync.Mutex
+	isLeader   bool
+
+	// stateLock serializes the changes to the state, and protects version and savedVersion.
+	stateLock sync.Mutex
+	// version is incremented with each change of the state written to the ConfigMap,
+	// and savedVersion is the version of the state in the ConfigMap.
+	version      uint64
+	savedVersion uint64
+	// writeLock serializes the writes to the ConfigMap, which are done without holding stateLock,
+	// so the membership changes aren't blocked by the API server.
+	writeLock sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// New returns a new lease backend.
+func New(client kubernetes.Interface, opts Options) *Server {
+	if opts.LeaseName == "" {
+		opts.LeaseName = DefaultLeaseName
+	}
+	if opts.ConfigMapName == "" {
+		opts.ConfigMapName = DefaultConfigMapName
+	}
+	if opts.MaxMembers <= 0 {
+		opts.MaxMembers = DefaultMaxMembers
+	}
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = defaultLeaseDuration
+	}
+	if opts.RenewDeadline <= 0 {
+		opts.RenewDeadline = defaultRenewDeadline
+	}
+	if opts.RetryPeriod <= 0 {
+		opts.RetryPeriod = defaultRetryPeriod
+	}
+
+	return &Server{
+		opts:     opts,
+		client:   client,
+		fsm:      raft.NewFSM(),
+		leaderCh: make(chan bool, 1),
+	}
+}
+
+// Start starts campaigning for the Lease.
+// The server keeps campaigning after losing the leadership, until Shutdown is called.
+func (s *Server) Start() error {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      s.opts.LeaseName,
+			Namespace: s.opts.Namespace,
+		},
+		Client: s.client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: s.opts.Identity,
+		},
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            s.opts.LeaseName,
+		LeaseDuration:   s.opts.LeaseDuration,
+		RenewDeadline:   s.opts.RenewDeadline,
+		RetryPeriod:     s.opts.RetryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: s.onStartedLeading,
+			OnStoppedLeading: s.onStoppedLeading,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the leader elector")
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.doneCh = make(chan struct{})
+	go func() {
+		defer close(s.doneCh)
+		for s.ctx.Err() == nil {
+			elector.Run(s.ctx)
+		}
+	}()
+
+	log.Infof("campaigning for the lease %s/%s as %s", s.opts.Namespace, s.opts.LeaseName, s.opts.Identity)
+	return nil
+}
+
+func (s *Server) onStartedLeading(ctx context.Context) {
+	// The state must be loaded before serving the runtimes, so the hashing tables aren't reset by a new leader.
+	for {
+		err := s.loadState(ctx)
+		if err == nil {
+			break
+		}
+		log.Errorf("failed to load the placement state from the ConfigMap %s/%s: %v", s.opts.Namespace, s.opts.ConfigMapName, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(loadRetryInterval):
+		}
+	}
+
+	s.leaderLock.Lock()
+	defer s.leaderLock.Unlock()
+
+	if ctx.Err() != nil || s.isLeader {
+		return
+	}
+	s.isLeader = true
+	s.notifyLeadership(true)
+}
+
+func (s *Server) onStoppedLeading() {
+	s.leaderLock.Lock()
+	defer s.leaderLock.Unlock()
+
+	if !s.isLeader {
+		return
+	}
+	s.isLeader = false
+	s.notifyLeadership(false)
+}
+
+// notifyLeadership sends the leadership change to leaderCh, unless the server is shutting down and nobody reads it anymore.
+func (s *Server) notifyLeadership(isLeader bool) {
+	select {
+	case s.leaderCh <- isLeader:
+	case <-s.ctx.Done():
+	}
+}
+
+// FSM returns the state machine holding the membership state.
+func (s *Server) FSM() *raft.FSM {
+	return s.fsm
+}
+
+// LeaderCh returns the channel notified when the server gains or loses the leadership.
+func (s *Server) LeaderCh() <-chan bool {
+	return s.leaderCh
+}
+
+// IsLeader returns true if the server holds the Lease.
+func (s *Server) IsLeader() bool {
+	s.leaderLock.Lock()
+	defer s.leaderLock.Unlock()
+
+	return s.isLeader
+}
+
+// Barrier returns immediately, as the leader loads the state from the ConfigMap before announcing its leadership.
+func (s *Server) Barrier(timeout time.Duration) error {
+	if !s.IsLeader() {
+		return errors.New("this is not the leader node")
+	}
+	return nil
+}
+
+// ApplyCommand applies the membership change to the state, and writes the state to the ConfigMap if the hashing tables changed.
+func (s *Server) ApplyCommand(cmdType raft.CommandType, data raft.DaprHostMember) (bool, error) {
+	if !s.IsLeader() {
+		return false, errors.New("this is not the leader node")
+	}
+
+	updated, version, snapshot, err := s.applyCommand(cmdType, data)
+	if err != nil {
+		return false, err
+	}
+
+	if snapshot != nil {
+		// The state in memory is authoritative while the lease is held: a failed write is retried with the next change
+		if err = s.writeState(version, snapshot); err != nil {
+			log.Warnf("failed to write the placement state to the ConfigMap %s/%s: %v", s.opts.Namespace, s.opts.ConfigMapName, err)
+		}
+	}
+
+	return updated, nil
+}
+
+// applyCommand applies the membership change to the state.
+// It returns the snapshot of the state to write to the ConfigMap with its version, or nil if the ConfigMap is up to date.
+func (s *Server) applyCommand(cmdType raft.CommandType, data raft.DaprHostMember) (bool, uint64, []byte, error) {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	if cmdType == raft.MemberUpsert {
+		members := s.fsm.State().Members()
+		if _, ok := members[data.Name]; !ok && len(members) >= s.opts.MaxMembers {
+			return false, 0, nil, fmt.Errorf("cannot add the actor host %s: the placement table is limited to %d members in lease mode", data.Name, s.opts.MaxMembers)
+		}
+	}
+
+	updated, err := s.fsm.ApplyCommand(cmdType, data)
+	if err != nil {
+		return false, 0, nil, err
+	}
+	if !updated && s.savedVersion == s.version {
+		return updated, 0, nil, nil
+	}
+
+	if updated {
+		s.version++
+	}
+	snapshot, err := s.snapshotLocked()
+	if err != nil {
+		return updated, 0, nil, err
+	}
+	return updated, s.version, snapshot, nil
+}
+
+// Backup writes a snapshot of the placement state to w.
+func (s *Server) Backup(w io.Writer) error {
+	return s.fsm.Persist(w)
+}
+
+// Restore replaces the placement state with a snapshot written by Backup, and writes it to the ConfigMap.
+// This can only be run on the leader.
+func (s *Server) Restore(r io.Reader) error {
+	if !s.IsLeader() {
+		return errors.New("this is not the leader node")
+	}
+
+	s.stateLock.Lock()
+	if err := s.fsm.Restore(io.NopCloser(r)); err != nil {
+		s.stateLock.Unlock()
+		return errors.Wrap(err, "invalid placement snapshot")
+	}
+	s.version++
+	version := s.version
+	snapshot, err := s.snapshotLocked()
+	s.stateLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return s.writeState(version, snapshot)
+}
+
+// Shutdown stops campaigning and releases the Lease if it is held.
+func (s *Server) Shutdown() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.doneCh
+}
+
+// loadState replaces the state with the one stored in the ConfigMap, if any.
+func (s *Server) loadState(ctx context.Context) error {
+	var data []byte
+	cm, err := s.client.CoreV1().ConfigMaps(s.opts.Namespace).Get(ctx, s.opts.ConfigMapName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		log.Infof("the ConfigMap %s/%s doesn't exist: starting with an empty placement state", s.opts.Namespace, s.opts.ConfigMapName)
+	case err != nil:
+		return err
+	default:
+		data = cm.BinaryData[stateKey]
+	}
+	if data == nil {
+		data = emptyState()
+	}
+
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+	if err = s.fsm.Restore(io.NopCloser(bytes.NewReader(data))); err != nil {
+		return err
+	}
+	s.savedVersion = s.version
+
+	log.Infof("placement state loaded from the ConfigMap %s/%s, members: %d", s.opts.Namespace, s.opts.ConfigMapName, len(s.fsm.State().Members()))
+	return nil
+}
+
+// snapshotLocked returns the snapshot of the state. The caller must hold stateLock.
+func (s *Server) snapshotLocked() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := s.fsm.Persist(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeState writes the snapshot of the state with the version to the ConfigMap, unless a more recent version was
+// written since the snapshot was taken.
+func (s *Server) writeState(version uint64, snapshot []byte) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	// savedVersion is only changed while holding writeLock
+	s.stateLock.Lock()
+	saved := s.savedVersion
+	s.stateLock.Unlock()
+	if version <= saved {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, saveTimeout)
+	defer cancel()
+	if err := s.saveState(ctx, snapshot); err != nil {
+		return err
+	}
+
+	s.stateLock.Lock()
+	s.savedVersion = version
+	s.stateLock.Unlock()
+	return nil
+}
+
+// saveState writes the snapshot of the state to the ConfigMap, creating it if it doesn't exist.
+func (s *Server) saveState(ctx context.Context, snapshot []byte) error {
+	configMaps := s.client.CoreV1().ConfigMaps(s.opts.Namespace)
+	cm, err := configMaps.Get(ctx, s.opts.ConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.opts.ConfigMapName,
+				Namespace: s.opts.Namespace,
+			},
+			BinaryData: map[string][]byte{stateKey: snapshot},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if cm.BinaryData == nil {
+		cm.BinaryData = map[string][]byte{}
+	}
+	cm.BinaryData[stateKey] = snapshot
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// emptyState returns the snapshot of an empty membership state.
+func emptyState() []byte {
+	var buf bytes.Buffer
+	raft.NewFSM().Persist(&buf)
+	return buf.Bytes()
+}
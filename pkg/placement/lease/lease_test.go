@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lease
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/dapr/dapr/pkg/placement/raft"
+)
+
+const testNamespace = "dapr-system"
+
+func newTestServer(t *testing.T, client kubernetes.Interface, identity string) *Server {
+	s := New(client, Options{
+		Identity:      identity,
+		Namespace:     testNamespace,
+		MaxMembers:    2,
+		LeaseDuration: 600 * time.Millisecond,
+		RenewDeadline: 400 * time.Millisecond,
+		RetryPeriod:   100 * time.Millisecond,
+	})
+	require.NoError(t, s.Start())
+	return s
+}
+
+func waitForLeadership(t *testing.T, s *Server) {
+	select {
+	case isLeader := <-s.LeaderCh():
+		require.True(t, isLeader)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the lease was not acquired")
+	}
+}
+
+func testMember(name string) raft.DaprHostMember {
+	return raft.DaprHostMember{
+		Name:      name,
+		AppID:     "app-" + name,
+		Entities:  []string{"actorTypeOne"},
+		UpdatedAt: time.Now().UnixNano(),
+	}
+}
+
+func TestLeaseBackend(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	s1 := newTestServer(t, client, "placement-0")
+	waitForLeadership(t, s1)
+	assert.True(t, s1.IsLeader())
+	assert.NoError(t, s1.Barrier(time.Second))
+
+	t.Run("membership changes are written to the ConfigMap", func(t *testing.T) {
+		updated, err := s1.ApplyCommand(raft.MemberUpsert, testMember("host1"))
+		require.NoError(t, err)
+		assert.True(t, updated)
+
+		cm, err := client.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), DefaultConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, cm.BinaryData[stateKey])
+
+		// A heartbeat of the same member doesn't change the hashing tables
+		updated, err = s1.ApplyCommand(raft.MemberUpsert, testMember("host1"))
+		require.NoError(t, err)
+		assert.False(t, updated)
+	})
+
+	t.Run("the number of members is limited", func(t *testing.T) {
+		_, err := s1.ApplyCommand(raft.MemberUpsert, testMember("host2"))
+		require.NoError(t, err)
+
+		_, err = s1.ApplyCommand(raft.MemberUpsert, testMember("host3"))
+		assert.Error(t, err)
+		assert.Len(t, s1.FSM().State().Members(), 2)
+
+		updated, err := s1.ApplyCommand(raft.MemberRemove, raft.DaprHostMember{Name: "host2"})
+		require.NoError(t, err)
+		assert.True(t, updated)
+	})
+
+	s1.Shutdown()
+
+	t.Run("a new leader resumes from the ConfigMap", func(t *testing.T) {
+		s2 := newTestServer(t, client, "placement-1")
+		defer s2.Shutdown()
+		waitForLeadership(t, s2)
+
+		members := s2.FSM().State().Members()
+		require.Len(t, members, 1)
+		assert.Equal(t, "app-host1", members["host1"].AppID)
+		assert.Equal(t, s1.FSM().PlacementState().Version, s2.FSM().PlacementState().Version)
+	})
+}
+
+func TestLeaseBackendFailedWrite(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	failWrites := atomic.Bool{}
+	client.PrependReactor("create", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if failWrites.Load() {
+			return true, nil, errors.New("api server unavailable")
+		}
+		return false, nil, nil
+	})
+
+	s := newTestServer(t, client, "placement-0")
+	defer s.Shutdown()
+	waitForLeadership(t, s)
+
+	failWrites.Store(true)
+	updated, err := s.ApplyCommand(raft.MemberUpsert, testMember("host1"))
+	require.NoError(t, err)
+	assert.True(t, updated)
+	_, err = client.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), DefaultConfigMapName, metav1.GetOptions{})
+	require.Error(t, err)
+
+	// The state is written with the next command, even if it doesn't change the hashing tables
+	failWrites.Store(false)
+	updated, err = s.ApplyCommand(raft.MemberUpsert, testMember("host1"))
+	require.NoError(t, err)
+	assert.False(t, updated)
+	cm, err := client.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), DefaultConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, cm.BinaryData[stateKey])
+
+	// An older snapshot doesn't replace the state in the ConfigMap
+	require.NoError(t, s.writeState(1, []byte("stale")))
+	cm, err = client.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), DefaultConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, []byte("stale"), cm.BinaryData[stateKey])
+}
+
+func TestLeaseBackendNotLeader(t *testing.T) {
+	s := New(fake.NewSimpleClientset(), Options{Identity: "placement-0", Namespace: testNamespace})
+
+	assert.False(t, s.IsLeader())
+	assert.Error(t, s.Barrier(time.Second))
+
+	_, err := s.ApplyCommand(raft.MemberUpsert, testMember("host1"))
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Backup(&buf))
+	assert.Error(t, s.Restore(&buf))
+}
@@ -41,7 +41,7 @@ func (p *Service) MonitorLeadership() {
 	var weAreLeaderCh chan struct{}
 	var leaderLoop sync.WaitGroup
 
-	leaderCh := p.raftNode.Raft().LeaderCh()
+	leaderCh := p.raftNode.LeaderCh()
 
 	for {
 		select {
@@ -91,8 +91,7 @@ func (p *Service) leaderLoop(stopCh chan struct{}) {
 		default:
 		}
 
-		barrier := p.raftNode.Raft().Barrier(barrierWriteTimeout)
-		if err := barrier.Error(); err != nil {
+		if err := p.raftNode.Barrier(barrierWriteTimeout); err != nil {
 			log.Error("failed to wait for barrier", "error", err)
 			continue
 		}
@@ -141,6 +140,7 @@ func (p *Service) membershipChangeWorker(stopCh chan struct{}) {
 	p.memberUpdateCount.Store(0)
 
 	go p.processRaftStateCommand(stopCh)
+	go p.federationWorker(stopCh)
 
 	for {
 		select {
@@ -287,7 +287,7 @@ func (p *Service) performTableDissemination() {
 		p.disseminateLock.Lock()
 		defer p.disseminateLock.Unlock()
 
-		state := p.raftNode.FSM().PlacementState()
+		state := p.placementTables()
 		log.Infof(
 			"Start disseminating tables. memberUpdateCount: %d, streams: %d, targets: %d, table generation: %s",
 			cnt, nStreamConnPool, nTargetConns, state.Version)
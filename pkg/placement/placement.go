@@ -73,6 +73,23 @@ type hostMemberChange struct {
 	host    raft.DaprHostMember
 }
 
+// Backend stores the membership of the actor hosts and elects the leader of the placement servers.
+// It is implemented by the raft server, and by the Kubernetes lease backend for small clusters.
+type Backend interface {
+	// FSM returns the state machine holding the membership and the hashing tables.
+	FSM() *raft.FSM
+	// LeaderCh returns the channel notified when the current server gains or loses the leadership.
+	LeaderCh() <-chan bool
+	// Barrier blocks until the state reflects all the changes committed before the current server became the leader.
+	Barrier(timeout time.Duration) error
+	// ApplyCommand applies a membership change and returns true if the hashing tables were updated.
+	ApplyCommand(cmdType raft.CommandType, data raft.DaprHostMember) (bool, error)
+	// Backup writes a snapshot of the state to w.
+	Backup(w io.Writer) error
+	// Restore replaces the state with a snapshot written by Backup.
+	Restore(r io.Reader) error
+}
+
 // Service updates the Dapr runtimes with distributed hash tables for stateful entities.
 type Service struct {
 	// serverListener is the TCP listener for placement gRPC server.
@@ -86,8 +103,8 @@ type Service struct {
 	// streamConnPoolLock is the lock for streamConnPool change.
 	streamConnPoolLock *sync.RWMutex
 
-	// raftNode is the raft server instance.
-	raftNode *raft.Server
+	// raftNode is the state backend: the raft server, or the Kubernetes lease backend.
+	raftNode Backend
 
 	// lastHeartBeat represents the last time stamp when runtime sent heartbeat.
 	lastHeartBeat *sync.Map
@@ -115,10 +132,13 @@ type Service struct {
 	shutdownLock *sync.Mutex
 	// shutdownCh is the channel to be used for the graceful shutdown.
 	shutdownCh chan struct{}
+
+	// federation replicates the members of the federated clusters, nil if the federation is disabled.
+	federation *federation
 }
 
 // NewPlacementService returns a new placement service.
-func NewPlacementService(raftNode *raft.Server) *Service {
+func NewPlacementService(raftNode Backend) *Service {
 	return &Service{
 		disseminateLock:          &sync.Mutex{},
 		streamConnPool:           []placementGRPCStream{},
@@ -134,16 +154,17 @@ func NewPlacementService(raftNode *raft.Server) *Service {
 }
 
 // Run starts the placement service gRPC server.
-func (p *Service) Run(port string, certChain *daprCredentials.CertChain) {
+// The certs are nil when mTLS is disabled.
+func (p *Service) Run(port string, certs *daprCredentials.CertChainReloader) {
 	var err error
 	p.serverListener, err = net.Listen("tcp", fmt.Sprintf(":%s", port))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	opts, err := daprCredentials.GetServerOptions(certChain)
-	if err != nil {
-		log.Fatalf("error creating gRPC options: %s", err)
+	var opts []grpc.ServerOption
+	if certs != nil {
+		opts = certs.ServerOptions()
 	}
 	grpcServer := grpc.NewServer(opts...)
 	placementv1pb.RegisterPlacementServer(grpcServer, p)
@@ -203,7 +224,7 @@ func (p *Service) ReportDaprStatus(stream placementv1pb.Placement_ReportDaprStat
 				p.addStreamConn(stream)
 				// TODO: If each sidecar can report table version, then placement
 				// doesn't need to disseminate tables to each sidecar.
-				p.performTablesUpdate([]placementGRPCStream{stream}, p.raftNode.FSM().PlacementState())
+				p.performTablesUpdate([]placementGRPCStream{stream}, p.placementTables())
 				log.Debugf("Stream connection is established from %s", registeredMemberID)
 			}
 
@@ -50,7 +50,8 @@ type FSM struct {
 	state     *DaprHostMemberState
 }
 
-func newFSM() *FSM {
+// NewFSM returns a new FSM with an empty state.
+func NewFSM() *FSM {
 	return &FSM{
 		state: newDaprHostMemberState(),
 	}
@@ -79,33 +80,8 @@ func (c *FSM) PlacementState() *v1pb.PlacementTables {
 
 	entries := c.state.hashingTableMap()
 	for k, v := range entries {
-		var table v1pb.PlacementTable
-		v.ReadInternals(func(hosts map[uint64]string, sortedSet []uint64, loadMap map[string]*hashing.Host, totalLoad int64) {
-			table = v1pb.PlacementTable{
-				Hosts:     make(map[uint64]string),
-				SortedSet: make([]uint64, len(sortedSet)),
-				TotalLoad: totalLoad,
-				LoadMap:   make(map[string]*v1pb.Host),
-			}
-
-			for lk, lv := range hosts {
-				table.Hosts[lk] = lv
-			}
-
-			copy(table.SortedSet, sortedSet)
-
-			for lk, lv := range loadMap {
-				h := v1pb.Host{
-					Name: lv.Name,
-					Load: lv.Load,
-					Port: lv.Port,
-					Id:   lv.AppID,
-				}
-				table.LoadMap[lk] = &h
-			}
-		})
-
-		newTable.Entries[k] = &table
+		table := PlacementTable(v)
+		newTable.Entries[k] = table
 
 		totalHostSize += len(table.Hosts)
 		totalSortedSet += len(table.SortedSet)
@@ -117,6 +93,35 @@ func (c *FSM) PlacementState() *v1pb.PlacementTables {
 	return newTable
 }
 
+// PlacementTable returns the placement table disseminated to the runtimes for the consistent hashing table of an actor type.
+func PlacementTable(c *hashing.Consistent) *v1pb.PlacementTable {
+	var table *v1pb.PlacementTable
+	c.ReadInternals(func(hosts map[uint64]string, sortedSet []uint64, loadMap map[string]*hashing.Host, totalLoad int64) {
+		table = &v1pb.PlacementTable{
+			Hosts:     make(map[uint64]string),
+			SortedSet: make([]uint64, len(sortedSet)),
+			TotalLoad: totalLoad,
+			LoadMap:   make(map[string]*v1pb.Host),
+		}
+
+		for lk, lv := range hosts {
+			table.Hosts[lk] = lv
+		}
+
+		copy(table.SortedSet, sortedSet)
+
+		for lk, lv := range loadMap {
+			table.LoadMap[lk] = &v1pb.Host{
+				Name: lv.Name,
+				Load: lv.Load,
+				Port: lv.Port,
+				Id:   lv.AppID,
+			}
+		}
+	})
+	return table
+}
+
 func (c *FSM) upsertMember(cmdData []byte) (bool, error) {
 	var host DaprHostMember
 	if err := unmarshalMsgPack(cmdData, &host); err != nil {
@@ -141,6 +146,27 @@ func (c *FSM) removeMember(cmdData []byte) (bool, error) {
 	return c.state.removeMember(&host), nil
 }
 
+// ApplyCommand applies a membership change to the state directly, without a raft log entry.
+// This is used by the placement backends that don't replicate the state with raft.
+func (c *FSM) ApplyCommand(cmdType CommandType, data DaprHostMember) (bool, error) {
+	c.stateLock.RLock()
+	defer c.stateLock.RUnlock()
+
+	switch cmdType {
+	case MemberUpsert:
+		return c.state.upsertMember(&data), nil
+	case MemberRemove:
+		return c.state.removeMember(&data), nil
+	default:
+		return false, errors.New("unimplemented command")
+	}
+}
+
+// Persist writes a snapshot of the current state to w, in the format read by Restore.
+func (c *FSM) Persist(w io.Writer) error {
+	return c.State().clone().persist(w)
+}
+
 // Apply log is invoked once a log entry is committed.
 func (c *FSM) Apply(log *raft.Log) interface{} {
 	var (
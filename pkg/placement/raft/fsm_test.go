@@ -23,7 +23,7 @@ import (
 )
 
 func TestFSMApply(t *testing.T) {
-	fsm := newFSM()
+	fsm := NewFSM()
 
 	t.Run("upsertMember", func(t *testing.T) {
 		cmdLog, err := makeRaftLogCommand(MemberUpsert, DaprHostMember{
@@ -74,9 +74,37 @@ func TestFSMApply(t *testing.T) {
 	})
 }
 
+func TestFSMApplyCommand(t *testing.T) {
+	fsm := NewFSM()
+
+	updated, err := fsm.ApplyCommand(MemberUpsert, DaprHostMember{
+		Name:     "127.0.0.1:3030",
+		AppID:    "fakeAppID",
+		Entities: []string{"actorTypeOne"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, 1, len(fsm.State().Members()))
+
+	var buf bytes.Buffer
+	assert.NoError(t, fsm.Persist(&buf))
+	restored := NewFSM()
+	assert.NoError(t, restored.Restore(io.NopCloser(&buf)))
+	assert.Equal(t, fsm.PlacementState().Version, restored.PlacementState().Version)
+	assert.Equal(t, 1, len(restored.State().Members()))
+
+	updated, err = fsm.ApplyCommand(MemberRemove, DaprHostMember{Name: "127.0.0.1:3030"})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, 0, len(fsm.State().Members()))
+
+	_, err = fsm.ApplyCommand(TableDisseminate, DaprHostMember{})
+	assert.Error(t, err)
+}
+
 func TestRestore(t *testing.T) {
 	// arrange
-	fsm := newFSM()
+	fsm := NewFSM()
 
 	s := newDaprHostMemberState()
 	s.upsertMember(&DaprHostMember{
@@ -98,7 +126,7 @@ func TestRestore(t *testing.T) {
 }
 
 func TestPlacementState(t *testing.T) {
-	fsm := newFSM()
+	fsm := NewFSM()
 	m := DaprHostMember{
 		Name:     "127.0.0.1:3030",
 		AppID:    "fakeAppID",
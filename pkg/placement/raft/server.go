@@ -14,12 +14,12 @@ limitations under the License.
 package raft
 
 import (
+	"bytes"
+	"io"
 	"net"
-	"path/filepath"
 	"time"
 
 	"github.com/hashicorp/raft"
-	raftboltdb "github.com/hashicorp/raft-boltdb"
 	"github.com/pkg/errors"
 )
 
@@ -32,6 +32,7 @@ const (
 	raftLogCacheSize = 512
 
 	commandTimeout = 1 * time.Second
+	restoreTimeout = 30 * time.Second
 
 	nameResolveRetryInterval = 2 * time.Second
 	nameResolveMaxRetry      = 120
@@ -48,13 +49,13 @@ type Server struct {
 	id  string
 	fsm *FSM
 
-	inMem    bool
 	raftBind string
 	peers    []PeerInfo
+	storage  StorageOptions
 
 	config        *raft.Config
 	raft          *raft.Raft
-	raftStore     *raftboltdb.BoltStore
+	raftStores    *Stores
 	raftTransport *raft.NetworkTransport
 
 	logStore    raft.LogStore
@@ -66,6 +67,16 @@ type Server struct {
 
 // New creates Raft server node.
 func New(id string, inMem bool, peers []PeerInfo, logStorePath string) *Server {
+	logStore := LogStoreBoltDB
+	if inMem {
+		logStore = LogStoreInMem
+	}
+	return NewWithStorage(id, peers, logStorePath, DefaultStorageOptions(logStore))
+}
+
+// NewWithStorage creates Raft server node storing the logs and snapshots with the storage options.
+// The unset storage options have their default value.
+func NewWithStorage(id string, peers []PeerInfo, logStorePath string, storage StorageOptions) *Server {
 	raftBind := raftAddressForID(id, peers)
 	if raftBind == "" {
 		return nil
@@ -73,9 +84,9 @@ func New(id string, inMem bool, peers []PeerInfo, logStorePath string) *Server {
 
 	return &Server{
 		id:               id,
-		inMem:            inMem,
 		raftBind:         raftBind,
 		peers:            peers,
+		storage:          storage.withDefaults(),
 		raftLogStorePath: logStorePath,
 	}
 }
@@ -100,14 +111,14 @@ func tryResolveRaftAdvertiseAddr(bindAddr string) (*net.TCPAddr, error) {
 func (s *Server) StartRaft(config *raft.Config) error {
 	// If we have an unclean exit then attempt to close the Raft store.
 	defer func() {
-		if s.raft == nil && s.raftStore != nil {
-			if err := s.raftStore.Close(); err != nil {
+		if s.raft == nil && s.raftStores != nil && s.raftStores.Close != nil {
+			if err := s.raftStores.Close(); err != nil {
 				logging.Errorf("failed to close log storage: %v", err)
 			}
 		}
 	}()
 
-	s.fsm = newFSM()
+	s.fsm = NewFSM()
 
 	addr, err := tryResolveRaftAdvertiseAddr(s.raftBind)
 	if err != nil {
@@ -122,37 +133,14 @@ func (s *Server) StartRaft(config *raft.Config) error {
 
 	s.raftTransport = trans
 
-	// Build an all in-memory setup for dev mode, otherwise prepare a full
-	// disk-based setup.
-	if s.inMem {
-		raftInmem := raft.NewInmemStore()
-		s.stableStore = raftInmem
-		s.logStore = raftInmem
-		s.snapStore = raft.NewInmemSnapshotStore()
-	} else {
-		if err = ensureDir(s.raftStorePath()); err != nil {
-			return errors.Wrap(err, "failed to create log store directory")
-		}
-
-		// Create the backend raft store for logs and stable storage.
-		s.raftStore, err = raftboltdb.NewBoltStore(filepath.Join(s.raftStorePath(), "raft.db"))
-		if err != nil {
-			return err
-		}
-		s.stableStore = s.raftStore
-
-		// Wrap the store in a LogCache to improve performance.
-		s.logStore, err = raft.NewLogCache(raftLogCacheSize, s.raftStore)
-		if err != nil {
-			return err
-		}
-
-		// Create the snapshot store.
-		s.snapStore, err = raft.NewFileSnapshotStoreWithLogger(s.raftStorePath(), snapshotsRetained, loggerAdapter)
-		if err != nil {
-			return err
-		}
+	// Build the stores of the logs and snapshots: all in-memory for dev mode, otherwise on disk.
+	s.raftStores, err = newStores(s.raftStorePath(), s.storage, loggerAdapter)
+	if err != nil {
+		return err
 	}
+	s.logStore = s.raftStores.Log
+	s.stableStore = s.raftStores.Stable
+	s.snapStore = s.raftStores.Snapshot
 
 	// Setup Raft configuration.
 	if config == nil {
@@ -164,9 +152,9 @@ func (s *Server) StartRaft(config *raft.Config) error {
 			CommitTimeout:      50 * time.Millisecond,
 			MaxAppendEntries:   64,
 			ShutdownOnRemove:   true,
-			TrailingLogs:       10240,
-			SnapshotInterval:   120 * time.Second,
-			SnapshotThreshold:  8192,
+			TrailingLogs:       s.storage.TrailingLogs,
+			SnapshotInterval:   s.storage.SnapshotInterval,
+			SnapshotThreshold:  s.storage.SnapshotThreshold,
 			LeaderLeaseTimeout: 500 * time.Millisecond,
 		}
 	} else {
@@ -244,6 +232,16 @@ func (s *Server) Raft() *raft.Raft {
 	return s.raft
 }
 
+// LeaderCh returns the channel notified when the current node gains or loses the leadership.
+func (s *Server) LeaderCh() <-chan bool {
+	return s.raft.LeaderCh()
+}
+
+// Barrier blocks until all the log entries committed before the current node became the leader are applied to the FSM.
+func (s *Server) Barrier(timeout time.Duration) error {
+	return s.raft.Barrier(timeout).Error()
+}
+
 // IsLeader returns true if the current node is leader.
 func (s *Server) IsLeader() bool {
 	return s.raft.State() == raft.Leader
@@ -269,6 +267,35 @@ func (s *Server) ApplyCommand(cmdType CommandType, data DaprHostMember) (bool, e
 	return resp.(bool), nil
 }
 
+// Backup writes a snapshot of the placement state to w.
+func (s *Server) Backup(w io.Writer) error {
+	return s.fsm.Persist(w)
+}
+
+// Restore replaces the placement state with a snapshot written by Backup, and replicates it to the followers.
+// This can only be run on the leader, and is meant for disaster recovery into a fresh cluster.
+func (s *Server) Restore(r io.Reader) error {
+	if !s.IsLeader() {
+		return errors.New("this is not the leader node")
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	// Validate the snapshot before forcing raft to consume it
+	if err = newDaprHostMemberState().restore(bytes.NewReader(data)); err != nil {
+		return errors.Wrap(err, "invalid placement snapshot")
+	}
+
+	meta := &raft.SnapshotMeta{
+		Version: raft.SnapshotVersionMax,
+		Size:    int64(len(data)),
+	}
+	return s.raft.Restore(meta, bytes.NewReader(data), restoreTimeout)
+}
+
 // Shutdown shutdown raft server gracefully.
 func (s *Server) Shutdown() {
 	if s.raft != nil {
@@ -277,8 +304,8 @@ func (s *Server) Shutdown() {
 		if err := future.Error(); err != nil {
 			logging.Warnf("error shutting down raft: %v", err)
 		}
-		if s.raftStore != nil {
-			s.raftStore.Close()
+		if s.raftStores != nil && s.raftStores.Close != nil {
+			s.raftStores.Close()
 		}
 	}
 }
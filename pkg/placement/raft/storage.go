@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LogStoreInMem keeps the raft logs and snapshots in memory, and loses them when the server restarts.
+	LogStoreInMem = "inmem"
+	// LogStoreBoltDB stores the raft logs in a BoltDB database and the snapshots in files.
+	LogStoreBoltDB = "boltdb"
+
+	defaultSnapshotInterval  = 120 * time.Second
+	defaultSnapshotThreshold = 8192
+	defaultTrailingLogs      = 10240
+)
+
+// StorageOptions are the options of the storage of the raft logs and snapshots.
+type StorageOptions struct {
+	// LogStore is the name of the store of the raft logs: LogStoreInMem, LogStoreBoltDB, or a registered store.
+	LogStore string
+	// SnapshotsRetained is the number of snapshots kept on disk.
+	SnapshotsRetained int
+	// SnapshotInterval is the interval of the checks for the snapshots.
+	SnapshotInterval time.Duration
+	// SnapshotThreshold is the number of logs written since the last snapshot for a new snapshot to be taken.
+	SnapshotThreshold uint64
+	// TrailingLogs is the number of logs kept after a snapshot, so that the followers can catch up without the snapshot.
+	TrailingLogs uint64
+}
+
+// DefaultStorageOptions returns the default storage options for the log store.
+func DefaultStorageOptions(logStore string) StorageOptions {
+	return StorageOptions{
+		LogStore:          logStore,
+		SnapshotsRetained: snapshotsRetained,
+		SnapshotInterval:  defaultSnapshotInterval,
+		SnapshotThreshold: defaultSnapshotThreshold,
+		TrailingLogs:      defaultTrailingLogs,
+	}
+}
+
+// withDefaults returns the options with the default values for the unset options.
+func (o StorageOptions) withDefaults() StorageOptions {
+	defaults := DefaultStorageOptions(LogStoreBoltDB)
+	if o.LogStore == "" {
+		o.LogStore = defaults.LogStore
+	}
+	if o.SnapshotsRetained <= 0 {
+		o.SnapshotsRetained = defaults.SnapshotsRetained
+	}
+	if o.SnapshotInterval <= 0 {
+		o.SnapshotInterval = defaults.SnapshotInterval
+	}
+	if o.SnapshotThreshold == 0 {
+		o.SnapshotThreshold = defaults.SnapshotThreshold
+	}
+	if o.TrailingLogs == 0 {
+		o.TrailingLogs = defaults.TrailingLogs
+	}
+	return o
+}
+
+// Stores are the stores of the raft logs, of the raft configuration and of the snapshots.
+type Stores struct {
+	Log      raft.LogStore
+	Stable   raft.StableStore
+	Snapshot raft.SnapshotStore
+	// Close releases the stores, it can be nil.
+	Close func() error
+}
+
+// LogStoreFactory creates the stores of a raft server in the directory.
+type LogStoreFactory func(dir string, opts StorageOptions, logger hclog.Logger) (*Stores, error)
+
+var (
+	logStoresLock sync.RWMutex
+	logStores     = map[string]LogStoreFactory{
+		LogStoreInMem:  newInMemStores,
+		LogStoreBoltDB: newBoltDBStores,
+	}
+)
+
+// RegisterLogStore registers a store of the raft logs, which can be selected with StorageOptions.LogStore.
+func RegisterLogStore(name string, factory LogStoreFactory) {
+	logStoresLock.Lock()
+	defer logStoresLock.Unlock()
+	logStores[name] = factory
+}
+
+// LogStores returns the names of the registered stores of the raft logs.
+func LogStores() []string {
+	logStoresLock.RLock()
+	defer logStoresLock.RUnlock()
+	names := make([]string, 0, len(logStores))
+	for name := range logStores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newStores(dir string, opts StorageOptions, logger hclog.Logger) (*Stores, error) {
+	logStoresLock.RLock()
+	factory, ok := logStores[opts.LogStore]
+	logStoresLock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("unknown raft log store %q, must be one of %v", opts.LogStore, LogStores())
+	}
+	return factory(dir, opts, logger)
+}
+
+func newInMemStores(dir string, opts StorageOptions, logger hclog.Logger) (*Stores, error) {
+	raftInmem := raft.NewInmemStore()
+	return &Stores{
+		Log:      raftInmem,
+		Stable:   raftInmem,
+		Snapshot: raft.NewInmemSnapshotStore(),
+	}, nil
+}
+
+func newBoltDBStores(dir string, opts StorageOptions, logger hclog.Logger) (*Stores, error) {
+	if err := ensureDir(dir); err != nil {
+		return nil, errors.Wrap(err, "failed to create log store directory")
+	}
+
+	// Create the backend raft store for logs and stable storage.
+	boltStore, err := raftboltdb.NewBoltStore(filepath.Join(dir, "raft.db"))
+	if err != nil {
+		return nil, err
+	}
+	stores := &Stores{Stable: boltStore, Close: boltStore.Close}
+
+	// Wrap the store in a LogCache to improve performance.
+	stores.Log, err = raft.NewLogCache(raftLogCacheSize, boltStore)
+	if err != nil {
+		boltStore.Close()
+		return nil, err
+	}
+
+	// Create the snapshot store.
+	stores.Snapshot, err = raft.NewFileSnapshotStoreWithLogger(dir, opts.SnapshotsRetained, logger)
+	if err != nil {
+		boltStore.Close()
+		return nil, err
+	}
+	return stores, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageOptionsWithDefaults(t *testing.T) {
+	opts := StorageOptions{SnapshotInterval: time.Minute}.withDefaults()
+	assert.Equal(t, LogStoreBoltDB, opts.LogStore)
+	assert.Equal(t, snapshotsRetained, opts.SnapshotsRetained)
+	assert.Equal(t, time.Minute, opts.SnapshotInterval)
+	assert.Equal(t, uint64(defaultSnapshotThreshold), opts.SnapshotThreshold)
+	assert.Equal(t, uint64(defaultTrailingLogs), opts.TrailingLogs)
+}
+
+func TestNewStores(t *testing.T) {
+	t.Run("in-memory", func(t *testing.T) {
+		stores, err := newStores("", DefaultStorageOptions(LogStoreInMem), newLoggerAdapter())
+		require.NoError(t, err)
+		assert.IsType(t, &raft.InmemStore{}, stores.Log)
+		assert.Nil(t, stores.Close)
+	})
+
+	t.Run("boltdb", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "raft")
+		stores, err := newStores(dir, DefaultStorageOptions(LogStoreBoltDB), newLoggerAdapter())
+		require.NoError(t, err)
+		require.NotNil(t, stores.Close)
+		defer stores.Close()
+
+		_, err = os.Stat(filepath.Join(dir, "raft.db"))
+		assert.NoError(t, err)
+		assert.IsType(t, &raft.FileSnapshotStore{}, stores.Snapshot)
+	})
+
+	t.Run("unknown store", func(t *testing.T) {
+		_, err := newStores("", DefaultStorageOptions("etcd"), newLoggerAdapter())
+		assert.ErrorContains(t, err, "etcd")
+	})
+
+	t.Run("registered store", func(t *testing.T) {
+		RegisterLogStore("test", func(dir string, opts StorageOptions, logger hclog.Logger) (*Stores, error) {
+			return newInMemStores(dir, opts, logger)
+		})
+		defer func() {
+			logStoresLock.Lock()
+			delete(logStores, "test")
+			logStoresLock.Unlock()
+		}()
+
+		assert.Contains(t, LogStores(), "test")
+		_, err := newStores("", DefaultStorageOptions("test"), newLoggerAdapter())
+		assert.NoError(t, err)
+	})
+}
@@ -37,6 +37,12 @@ type OperatorClient interface {
 	ListResiliency(ctx context.Context, in *ListResiliencyRequest, opts ...grpc.CallOption) (*ListResiliencyResponse, error)
 	// Returns a list of pub/sub subscriptions, ListSubscriptionsRequest to expose pod info
 	ListSubscriptionsV2(ctx context.Context, in *ListSubscriptionsRequest, opts ...grpc.CallOption) (*ListSubscriptionsResponse, error)
+	// Sends events to Dapr sidecars upon subscription changes.
+	SubscriptionUpdate(ctx context.Context, in *SubscriptionUpdateRequest, opts ...grpc.CallOption) (Operator_SubscriptionUpdateClient, error)
+	// Reports the failed initialization of a component by a Dapr sidecar
+	ReportComponentStatus(ctx context.Context, in *ReportComponentStatusRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Reports the components and the configuration that are out of date in a Dapr sidecar
+	ReportDrift(ctx context.Context, in *ReportDriftRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type operatorClient struct {
@@ -133,6 +139,56 @@ func (c *operatorClient) ListSubscriptionsV2(ctx context.Context, in *ListSubscr
 	return out, nil
 }
 
+func (c *operatorClient) SubscriptionUpdate(ctx context.Context, in *SubscriptionUpdateRequest, opts ...grpc.CallOption) (Operator_SubscriptionUpdateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Operator_ServiceDesc.Streams[1], "/dapr.proto.operator.v1.Operator/SubscriptionUpdate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &operatorSubscriptionUpdateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Operator_SubscriptionUpdateClient interface {
+	Recv() (*SubscriptionUpdateEvent, error)
+	grpc.ClientStream
+}
+
+type operatorSubscriptionUpdateClient struct {
+	grpc.ClientStream
+}
+
+func (x *operatorSubscriptionUpdateClient) Recv() (*SubscriptionUpdateEvent, error) {
+	m := new(SubscriptionUpdateEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *operatorClient) ReportComponentStatus(ctx context.Context, in *ReportComponentStatusRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.operator.v1.Operator/ReportComponentStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *operatorClient) ReportDrift(ctx context.Context, in *ReportDriftRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.operator.v1.Operator/ReportDrift", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OperatorServer is the server API for Operator service.
 // All implementations should embed UnimplementedOperatorServer
 // for forward compatibility
@@ -151,6 +207,12 @@ type OperatorServer interface {
 	ListResiliency(context.Context, *ListResiliencyRequest) (*ListResiliencyResponse, error)
 	// Returns a list of pub/sub subscriptions, ListSubscriptionsRequest to expose pod info
 	ListSubscriptionsV2(context.Context, *ListSubscriptionsRequest) (*ListSubscriptionsResponse, error)
+	// Sends events to Dapr sidecars upon subscription changes.
+	SubscriptionUpdate(*SubscriptionUpdateRequest, Operator_SubscriptionUpdateServer) error
+	// Reports the failed initialization of a component by a Dapr sidecar
+	ReportComponentStatus(context.Context, *ReportComponentStatusRequest) (*emptypb.Empty, error)
+	// Reports the components and the configuration that are out of date in a Dapr sidecar
+	ReportDrift(context.Context, *ReportDriftRequest) (*emptypb.Empty, error)
 }
 
 // UnimplementedOperatorServer should be embedded to have forward compatible implementations.
@@ -178,6 +240,15 @@ func (UnimplementedOperatorServer) ListResiliency(context.Context, *ListResilien
 func (UnimplementedOperatorServer) ListSubscriptionsV2(context.Context, *ListSubscriptionsRequest) (*ListSubscriptionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptionsV2 not implemented")
 }
+func (UnimplementedOperatorServer) SubscriptionUpdate(*SubscriptionUpdateRequest, Operator_SubscriptionUpdateServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscriptionUpdate not implemented")
+}
+func (UnimplementedOperatorServer) ReportComponentStatus(context.Context, *ReportComponentStatusRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportComponentStatus not implemented")
+}
+func (UnimplementedOperatorServer) ReportDrift(context.Context, *ReportDriftRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportDrift not implemented")
+}
 
 // UnsafeOperatorServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to OperatorServer will
@@ -319,6 +390,63 @@ func _Operator_ListSubscriptionsV2_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Operator_SubscriptionUpdate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscriptionUpdateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OperatorServer).SubscriptionUpdate(m, &operatorSubscriptionUpdateServer{stream})
+}
+
+type Operator_SubscriptionUpdateServer interface {
+	Send(*SubscriptionUpdateEvent) error
+	grpc.ServerStream
+}
+
+type operatorSubscriptionUpdateServer struct {
+	grpc.ServerStream
+}
+
+func (x *operatorSubscriptionUpdateServer) Send(m *SubscriptionUpdateEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Operator_ReportComponentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportComponentStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OperatorServer).ReportComponentStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.operator.v1.Operator/ReportComponentStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OperatorServer).ReportComponentStatus(ctx, req.(*ReportComponentStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Operator_ReportDrift_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportDriftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OperatorServer).ReportDrift(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.operator.v1.Operator/ReportDrift",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OperatorServer).ReportDrift(ctx, req.(*ReportDriftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Operator_ServiceDesc is the grpc.ServiceDesc for Operator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -350,6 +478,14 @@ var Operator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListSubscriptionsV2",
 			Handler:    _Operator_ListSubscriptionsV2_Handler,
 		},
+		{
+			MethodName: "ReportComponentStatus",
+			Handler:    _Operator_ReportComponentStatus_Handler,
+		},
+		{
+			MethodName: "ReportDrift",
+			Handler:    _Operator_ReportDrift_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -357,6 +493,11 @@ var Operator_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Operator_ComponentUpdate_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscriptionUpdate",
+			Handler:       _Operator_SubscriptionUpdate_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "dapr/proto/operator/v1/operator.proto",
 }
@@ -23,6 +23,9 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PlacementClient interface {
 	ReportDaprStatus(ctx context.Context, opts ...grpc.CallOption) (Placement_ReportDaprStatusClient, error)
+	// GetClusterMembers returns the actor hosts of the cluster of the placement service
+	// to the federated placement services of the other clusters.
+	GetClusterMembers(ctx context.Context, in *GetClusterMembersRequest, opts ...grpc.CallOption) (*ClusterMembers, error)
 }
 
 type placementClient struct {
@@ -64,11 +67,23 @@ func (x *placementReportDaprStatusClient) Recv() (*PlacementOrder, error) {
 	return m, nil
 }
 
+func (c *placementClient) GetClusterMembers(ctx context.Context, in *GetClusterMembersRequest, opts ...grpc.CallOption) (*ClusterMembers, error) {
+	out := new(ClusterMembers)
+	err := c.cc.Invoke(ctx, "/dapr.proto.placement.v1.Placement/GetClusterMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PlacementServer is the server API for Placement service.
 // All implementations should embed UnimplementedPlacementServer
 // for forward compatibility
 type PlacementServer interface {
 	ReportDaprStatus(Placement_ReportDaprStatusServer) error
+	// GetClusterMembers returns the actor hosts of the cluster of the placement service
+	// to the federated placement services of the other clusters.
+	GetClusterMembers(context.Context, *GetClusterMembersRequest) (*ClusterMembers, error)
 }
 
 // UnimplementedPlacementServer should be embedded to have forward compatible implementations.
@@ -78,6 +93,9 @@ type UnimplementedPlacementServer struct {
 func (UnimplementedPlacementServer) ReportDaprStatus(Placement_ReportDaprStatusServer) error {
 	return status.Errorf(codes.Unimplemented, "method ReportDaprStatus not implemented")
 }
+func (UnimplementedPlacementServer) GetClusterMembers(context.Context, *GetClusterMembersRequest) (*ClusterMembers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClusterMembers not implemented")
+}
 
 // UnsafePlacementServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to PlacementServer will
@@ -116,13 +134,36 @@ func (x *placementReportDaprStatusServer) Recv() (*Host, error) {
 	return m, nil
 }
 
+func _Placement_GetClusterMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClusterMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlacementServer).GetClusterMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.placement.v1.Placement/GetClusterMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlacementServer).GetClusterMembers(ctx, req.(*GetClusterMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Placement_ServiceDesc is the grpc.ServiceDesc for Placement service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var Placement_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "dapr.proto.placement.v1.Placement",
 	HandlerType: (*PlacementServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetClusterMembers",
+			Handler:    _Placement_GetClusterMembers_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "ReportDaprStatus",
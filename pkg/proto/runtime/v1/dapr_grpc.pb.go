@@ -74,12 +74,42 @@ type DaprClient interface {
 	TryLockAlpha1(ctx context.Context, in *TryLockRequest, opts ...grpc.CallOption) (*TryLockResponse, error)
 	// UnlockAlpha1 unlocks a lock.
 	UnlockAlpha1(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error)
+	// Starts a new instance of a workflow
+	StartWorkflowAlpha1(ctx context.Context, in *StartWorkflowRequest, opts ...grpc.CallOption) (*StartWorkflowResponse, error)
+	// Gets details about a started workflow instance
+	GetWorkflowAlpha1(ctx context.Context, in *GetWorkflowRequest, opts ...grpc.CallOption) (*GetWorkflowResponse, error)
+	// Terminates a running workflow instance
+	TerminateWorkflowAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Raises an event to a running workflow instance
+	RaiseEventWorkflowAlpha1(ctx context.Context, in *RaiseEventWorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Pauses a running workflow instance
+	PauseWorkflowAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Resumes a paused workflow instance
+	ResumeWorkflowAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Purges the state of a completed workflow instance
+	PurgeWorkflowAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Lists the pending external events and timers of a workflow instance
+	GetWorkflowPendingAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*GetWorkflowPendingResponse, error)
+	// Cancels a pending timer of a workflow instance
+	CancelWorkflowTimerAlpha1(ctx context.Context, in *WorkflowTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Changes the time a pending timer of a workflow instance fires at
+	RescheduleWorkflowTimerAlpha1(ctx context.Context, in *RescheduleWorkflowTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Encrypts a stream of data with a crypto provider
+	EncryptAlpha1(ctx context.Context, opts ...grpc.CallOption) (Dapr_EncryptAlpha1Client, error)
+	// Decrypts a stream of data encrypted with EncryptAlpha1
+	DecryptAlpha1(ctx context.Context, opts ...grpc.CallOption) (Dapr_DecryptAlpha1Client, error)
+	// Signs a digest with a crypto provider
+	SignAlpha1(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error)
+	// Verifies the signature of a digest with a crypto provider
+	VerifyAlpha1(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
 	// Gets metadata of the sidecar
 	GetMetadata(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetMetadataResponse, error)
 	// Sets value in extended metadata of the sidecar
 	SetMetadata(ctx context.Context, in *SetMetadataRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Shutdown the sidecar
 	Shutdown(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Sets the log level of the sidecar and toggles the logging of the API calls, without restarting it
+	SetLogLevelAlpha1(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error)
 }
 
 type daprClient struct {
@@ -338,6 +368,176 @@ func (c *daprClient) UnlockAlpha1(ctx context.Context, in *UnlockRequest, opts .
 	return out, nil
 }
 
+func (c *daprClient) StartWorkflowAlpha1(ctx context.Context, in *StartWorkflowRequest, opts ...grpc.CallOption) (*StartWorkflowResponse, error) {
+	out := new(StartWorkflowResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/StartWorkflowAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) GetWorkflowAlpha1(ctx context.Context, in *GetWorkflowRequest, opts ...grpc.CallOption) (*GetWorkflowResponse, error) {
+	out := new(GetWorkflowResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/GetWorkflowAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) TerminateWorkflowAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/TerminateWorkflowAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) RaiseEventWorkflowAlpha1(ctx context.Context, in *RaiseEventWorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/RaiseEventWorkflowAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) PauseWorkflowAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/PauseWorkflowAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) ResumeWorkflowAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/ResumeWorkflowAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) PurgeWorkflowAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/PurgeWorkflowAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) GetWorkflowPendingAlpha1(ctx context.Context, in *WorkflowInstanceRequest, opts ...grpc.CallOption) (*GetWorkflowPendingResponse, error) {
+	out := new(GetWorkflowPendingResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/GetWorkflowPendingAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) CancelWorkflowTimerAlpha1(ctx context.Context, in *WorkflowTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/CancelWorkflowTimerAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) RescheduleWorkflowTimerAlpha1(ctx context.Context, in *RescheduleWorkflowTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/RescheduleWorkflowTimerAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) EncryptAlpha1(ctx context.Context, opts ...grpc.CallOption) (Dapr_EncryptAlpha1Client, error) {
+	stream, err := c.cc.NewStream(ctx, &Dapr_ServiceDesc.Streams[1], "/dapr.proto.runtime.v1.Dapr/EncryptAlpha1", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daprEncryptAlpha1Client{stream}
+	return x, nil
+}
+
+type Dapr_EncryptAlpha1Client interface {
+	Send(*EncryptRequest) error
+	Recv() (*EncryptResponse, error)
+	grpc.ClientStream
+}
+
+type daprEncryptAlpha1Client struct {
+	grpc.ClientStream
+}
+
+func (x *daprEncryptAlpha1Client) Send(m *EncryptRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *daprEncryptAlpha1Client) Recv() (*EncryptResponse, error) {
+	m := new(EncryptResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daprClient) DecryptAlpha1(ctx context.Context, opts ...grpc.CallOption) (Dapr_DecryptAlpha1Client, error) {
+	stream, err := c.cc.NewStream(ctx, &Dapr_ServiceDesc.Streams[2], "/dapr.proto.runtime.v1.Dapr/DecryptAlpha1", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daprDecryptAlpha1Client{stream}
+	return x, nil
+}
+
+type Dapr_DecryptAlpha1Client interface {
+	Send(*DecryptRequest) error
+	Recv() (*DecryptResponse, error)
+	grpc.ClientStream
+}
+
+type daprDecryptAlpha1Client struct {
+	grpc.ClientStream
+}
+
+func (x *daprDecryptAlpha1Client) Send(m *DecryptRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *daprDecryptAlpha1Client) Recv() (*DecryptResponse, error) {
+	m := new(DecryptResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daprClient) SignAlpha1(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error) {
+	out := new(SignResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/SignAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) VerifyAlpha1(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/VerifyAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daprClient) GetMetadata(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetMetadataResponse, error) {
 	out := new(GetMetadataResponse)
 	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/GetMetadata", in, out, opts...)
@@ -365,6 +565,15 @@ func (c *daprClient) Shutdown(ctx context.Context, in *emptypb.Empty, opts ...gr
 	return out, nil
 }
 
+func (c *daprClient) SetLogLevelAlpha1(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error) {
+	out := new(SetLogLevelResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/SetLogLevelAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DaprServer is the server API for Dapr service.
 // All implementations should embed UnimplementedDaprServer
 // for forward compatibility
@@ -419,12 +628,42 @@ type DaprServer interface {
 	TryLockAlpha1(context.Context, *TryLockRequest) (*TryLockResponse, error)
 	// UnlockAlpha1 unlocks a lock.
 	UnlockAlpha1(context.Context, *UnlockRequest) (*UnlockResponse, error)
+	// Starts a new instance of a workflow
+	StartWorkflowAlpha1(context.Context, *StartWorkflowRequest) (*StartWorkflowResponse, error)
+	// Gets details about a started workflow instance
+	GetWorkflowAlpha1(context.Context, *GetWorkflowRequest) (*GetWorkflowResponse, error)
+	// Terminates a running workflow instance
+	TerminateWorkflowAlpha1(context.Context, *WorkflowInstanceRequest) (*emptypb.Empty, error)
+	// Raises an event to a running workflow instance
+	RaiseEventWorkflowAlpha1(context.Context, *RaiseEventWorkflowRequest) (*emptypb.Empty, error)
+	// Pauses a running workflow instance
+	PauseWorkflowAlpha1(context.Context, *WorkflowInstanceRequest) (*emptypb.Empty, error)
+	// Resumes a paused workflow instance
+	ResumeWorkflowAlpha1(context.Context, *WorkflowInstanceRequest) (*emptypb.Empty, error)
+	// Purges the state of a completed workflow instance
+	PurgeWorkflowAlpha1(context.Context, *WorkflowInstanceRequest) (*emptypb.Empty, error)
+	// Lists the pending external events and timers of a workflow instance
+	GetWorkflowPendingAlpha1(context.Context, *WorkflowInstanceRequest) (*GetWorkflowPendingResponse, error)
+	// Cancels a pending timer of a workflow instance
+	CancelWorkflowTimerAlpha1(context.Context, *WorkflowTimerRequest) (*emptypb.Empty, error)
+	// Changes the time a pending timer of a workflow instance fires at
+	RescheduleWorkflowTimerAlpha1(context.Context, *RescheduleWorkflowTimerRequest) (*emptypb.Empty, error)
+	// Encrypts a stream of data with a crypto provider
+	EncryptAlpha1(Dapr_EncryptAlpha1Server) error
+	// Decrypts a stream of data encrypted with EncryptAlpha1
+	DecryptAlpha1(Dapr_DecryptAlpha1Server) error
+	// Signs a digest with a crypto provider
+	SignAlpha1(context.Context, *SignRequest) (*SignResponse, error)
+	// Verifies the signature of a digest with a crypto provider
+	VerifyAlpha1(context.Context, *VerifyRequest) (*VerifyResponse, error)
 	// Gets metadata of the sidecar
 	GetMetadata(context.Context, *emptypb.Empty) (*GetMetadataResponse, error)
 	// Sets value in extended metadata of the sidecar
 	SetMetadata(context.Context, *SetMetadataRequest) (*emptypb.Empty, error)
 	// Shutdown the sidecar
 	Shutdown(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// Sets the log level of the sidecar and toggles the logging of the API calls, without restarting it
+	SetLogLevelAlpha1(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error)
 }
 
 // UnimplementedDaprServer should be embedded to have forward compatible implementations.
@@ -506,6 +745,48 @@ func (UnimplementedDaprServer) TryLockAlpha1(context.Context, *TryLockRequest) (
 func (UnimplementedDaprServer) UnlockAlpha1(context.Context, *UnlockRequest) (*UnlockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UnlockAlpha1 not implemented")
 }
+func (UnimplementedDaprServer) StartWorkflowAlpha1(context.Context, *StartWorkflowRequest) (*StartWorkflowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartWorkflowAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) GetWorkflowAlpha1(context.Context, *GetWorkflowRequest) (*GetWorkflowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) TerminateWorkflowAlpha1(context.Context, *WorkflowInstanceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TerminateWorkflowAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) RaiseEventWorkflowAlpha1(context.Context, *RaiseEventWorkflowRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RaiseEventWorkflowAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) PauseWorkflowAlpha1(context.Context, *WorkflowInstanceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseWorkflowAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) ResumeWorkflowAlpha1(context.Context, *WorkflowInstanceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeWorkflowAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) PurgeWorkflowAlpha1(context.Context, *WorkflowInstanceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeWorkflowAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) GetWorkflowPendingAlpha1(context.Context, *WorkflowInstanceRequest) (*GetWorkflowPendingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowPendingAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) CancelWorkflowTimerAlpha1(context.Context, *WorkflowTimerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelWorkflowTimerAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) RescheduleWorkflowTimerAlpha1(context.Context, *RescheduleWorkflowTimerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RescheduleWorkflowTimerAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) EncryptAlpha1(Dapr_EncryptAlpha1Server) error {
+	return status.Errorf(codes.Unimplemented, "method EncryptAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) DecryptAlpha1(Dapr_DecryptAlpha1Server) error {
+	return status.Errorf(codes.Unimplemented, "method DecryptAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) SignAlpha1(context.Context, *SignRequest) (*SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) VerifyAlpha1(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyAlpha1 not implemented")
+}
 func (UnimplementedDaprServer) GetMetadata(context.Context, *emptypb.Empty) (*GetMetadataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
 }
@@ -515,6 +796,9 @@ func (UnimplementedDaprServer) SetMetadata(context.Context, *SetMetadataRequest)
 func (UnimplementedDaprServer) Shutdown(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
 }
+func (UnimplementedDaprServer) SetLogLevelAlpha1(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevelAlpha1 not implemented")
+}
 
 // UnsafeDaprServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to DaprServer will
@@ -980,6 +1264,274 @@ func _Dapr_UnlockAlpha1_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dapr_StartWorkflowAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).StartWorkflowAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/StartWorkflowAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).StartWorkflowAlpha1(ctx, req.(*StartWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_GetWorkflowAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).GetWorkflowAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/GetWorkflowAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).GetWorkflowAlpha1(ctx, req.(*GetWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_TerminateWorkflowAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).TerminateWorkflowAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/TerminateWorkflowAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).TerminateWorkflowAlpha1(ctx, req.(*WorkflowInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_RaiseEventWorkflowAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RaiseEventWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).RaiseEventWorkflowAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/RaiseEventWorkflowAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).RaiseEventWorkflowAlpha1(ctx, req.(*RaiseEventWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_PauseWorkflowAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).PauseWorkflowAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/PauseWorkflowAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).PauseWorkflowAlpha1(ctx, req.(*WorkflowInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_ResumeWorkflowAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).ResumeWorkflowAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/ResumeWorkflowAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).ResumeWorkflowAlpha1(ctx, req.(*WorkflowInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_PurgeWorkflowAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).PurgeWorkflowAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/PurgeWorkflowAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).PurgeWorkflowAlpha1(ctx, req.(*WorkflowInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_GetWorkflowPendingAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).GetWorkflowPendingAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/GetWorkflowPendingAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).GetWorkflowPendingAlpha1(ctx, req.(*WorkflowInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_CancelWorkflowTimerAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).CancelWorkflowTimerAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/CancelWorkflowTimerAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).CancelWorkflowTimerAlpha1(ctx, req.(*WorkflowTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_RescheduleWorkflowTimerAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescheduleWorkflowTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).RescheduleWorkflowTimerAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/RescheduleWorkflowTimerAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).RescheduleWorkflowTimerAlpha1(ctx, req.(*RescheduleWorkflowTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_EncryptAlpha1_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DaprServer).EncryptAlpha1(&daprEncryptAlpha1Server{stream})
+}
+
+type Dapr_EncryptAlpha1Server interface {
+	Send(*EncryptResponse) error
+	Recv() (*EncryptRequest, error)
+	grpc.ServerStream
+}
+
+type daprEncryptAlpha1Server struct {
+	grpc.ServerStream
+}
+
+func (x *daprEncryptAlpha1Server) Send(m *EncryptResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *daprEncryptAlpha1Server) Recv() (*EncryptRequest, error) {
+	m := new(EncryptRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Dapr_DecryptAlpha1_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DaprServer).DecryptAlpha1(&daprDecryptAlpha1Server{stream})
+}
+
+type Dapr_DecryptAlpha1Server interface {
+	Send(*DecryptResponse) error
+	Recv() (*DecryptRequest, error)
+	grpc.ServerStream
+}
+
+type daprDecryptAlpha1Server struct {
+	grpc.ServerStream
+}
+
+func (x *daprDecryptAlpha1Server) Send(m *DecryptResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *daprDecryptAlpha1Server) Recv() (*DecryptRequest, error) {
+	m := new(DecryptRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Dapr_SignAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).SignAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/SignAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).SignAlpha1(ctx, req.(*SignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_VerifyAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).VerifyAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/VerifyAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).VerifyAlpha1(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Dapr_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -1034,6 +1586,24 @@ func _Dapr_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dapr_SetLogLevelAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).SetLogLevelAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/SetLogLevelAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).SetLogLevelAlpha1(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Dapr_ServiceDesc is the grpc.ServiceDesc for Dapr service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1137,6 +1707,54 @@ var Dapr_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UnlockAlpha1",
 			Handler:    _Dapr_UnlockAlpha1_Handler,
 		},
+		{
+			MethodName: "StartWorkflowAlpha1",
+			Handler:    _Dapr_StartWorkflowAlpha1_Handler,
+		},
+		{
+			MethodName: "GetWorkflowAlpha1",
+			Handler:    _Dapr_GetWorkflowAlpha1_Handler,
+		},
+		{
+			MethodName: "TerminateWorkflowAlpha1",
+			Handler:    _Dapr_TerminateWorkflowAlpha1_Handler,
+		},
+		{
+			MethodName: "RaiseEventWorkflowAlpha1",
+			Handler:    _Dapr_RaiseEventWorkflowAlpha1_Handler,
+		},
+		{
+			MethodName: "PauseWorkflowAlpha1",
+			Handler:    _Dapr_PauseWorkflowAlpha1_Handler,
+		},
+		{
+			MethodName: "ResumeWorkflowAlpha1",
+			Handler:    _Dapr_ResumeWorkflowAlpha1_Handler,
+		},
+		{
+			MethodName: "PurgeWorkflowAlpha1",
+			Handler:    _Dapr_PurgeWorkflowAlpha1_Handler,
+		},
+		{
+			MethodName: "GetWorkflowPendingAlpha1",
+			Handler:    _Dapr_GetWorkflowPendingAlpha1_Handler,
+		},
+		{
+			MethodName: "CancelWorkflowTimerAlpha1",
+			Handler:    _Dapr_CancelWorkflowTimerAlpha1_Handler,
+		},
+		{
+			MethodName: "RescheduleWorkflowTimerAlpha1",
+			Handler:    _Dapr_RescheduleWorkflowTimerAlpha1_Handler,
+		},
+		{
+			MethodName: "SignAlpha1",
+			Handler:    _Dapr_SignAlpha1_Handler,
+		},
+		{
+			MethodName: "VerifyAlpha1",
+			Handler:    _Dapr_VerifyAlpha1_Handler,
+		},
 		{
 			MethodName: "GetMetadata",
 			Handler:    _Dapr_GetMetadata_Handler,
@@ -1149,6 +1767,10 @@ var Dapr_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Shutdown",
 			Handler:    _Dapr_Shutdown_Handler,
 		},
+		{
+			MethodName: "SetLogLevelAlpha1",
+			Handler:    _Dapr_SetLogLevelAlpha1_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1156,6 +1778,18 @@ var Dapr_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Dapr_SubscribeConfigurationAlpha1_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "EncryptAlpha1",
+			Handler:       _Dapr_EncryptAlpha1_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DecryptAlpha1",
+			Handler:       _Dapr_DecryptAlpha1_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "dapr/proto/runtime/v1/dapr.proto",
 }
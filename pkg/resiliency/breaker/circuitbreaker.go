@@ -120,3 +120,30 @@ func (c *CircuitBreaker) Execute(oper func() error) error {
 		return err //nolint:wrapcheck
 	}
 }
+
+// Status contains the current state and internal counts of a circuit breaker.
+type Status struct {
+	// State is "closed", "open" or "half-open".
+	State                string `json:"state"`
+	Requests             uint32 `json:"requests"`
+	TotalSuccesses       uint32 `json:"totalSuccesses"`
+	TotalFailures        uint32 `json:"totalFailures"`
+	ConsecutiveSuccesses uint32 `json:"consecutiveSuccesses"`
+	ConsecutiveFailures  uint32 `json:"consecutiveFailures"`
+}
+
+// Status returns the current state and internal counts of the circuit breaker.
+func (c *CircuitBreaker) Status() Status {
+	if c.breaker == nil {
+		return Status{State: gobreaker.StateClosed.String()}
+	}
+	counts := c.breaker.Counts()
+	return Status{
+		State:                c.breaker.State().String(),
+		Requests:             counts.Requests,
+		TotalSuccesses:       counts.TotalSuccesses,
+		TotalFailures:        counts.TotalFailures,
+		ConsecutiveSuccesses: counts.ConsecutiveSuccesses,
+		ConsecutiveFailures:  counts.ConsecutiveFailures,
+	}
+}
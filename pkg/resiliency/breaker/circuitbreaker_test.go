@@ -53,3 +53,28 @@ func TestCircuitBreaker(t *testing.T) {
 	})
 	assert.NoError(t, err)
 }
+
+func TestCircuitBreakerStatus(t *testing.T) {
+	var trip expr.Expr
+	err := trip.DecodeString("consecutiveFailures > 1")
+	require.NoError(t, err)
+	cb := breaker.CircuitBreaker{ //nolint:exhaustivestruct
+		Name:    "test",
+		Trip:    &trip,
+		Timeout: time.Minute,
+	}
+	assert.Equal(t, "closed", cb.Status().State)
+
+	cb.Initialize(logger.NewLogger("test"))
+	cb.Execute(func() error {
+		return errors.New("test")
+	})
+	status := cb.Status()
+	assert.Equal(t, "closed", status.State)
+	assert.Equal(t, uint32(1), status.ConsecutiveFailures)
+
+	cb.Execute(func() error {
+		return errors.New("test")
+	})
+	assert.Equal(t, "open", cb.Status().State)
+}
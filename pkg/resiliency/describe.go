@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"sort"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/dapr/dapr/pkg/resiliency/breaker"
+)
+
+type (
+	// Description describes the effective resiliency policies and the live state of the circuit breakers.
+	Description struct {
+		// Targets are the apps, actors and components with policies, including the default policies.
+		Targets []TargetDescription `json:"targets"`
+		// CircuitBreakers are the circuit breakers instantiated for the targets.
+		CircuitBreakers []CircuitBreakerDescription `json:"circuitBreakers"`
+	}
+
+	// TargetDescription contains the names of the policies applied to a target.
+	// The name of the target is empty for the default policies of the target type.
+	TargetDescription struct {
+		Type           PolicyTypeName `json:"type"`
+		Name           string         `json:"name,omitempty"`
+		ComponentType  ComponentType  `json:"componentType,omitempty"`
+		Direction      string         `json:"direction,omitempty"`
+		Timeout        string         `json:"timeout,omitempty"`
+		Retry          string         `json:"retry,omitempty"`
+		CircuitBreaker string         `json:"circuitBreaker,omitempty"`
+	}
+
+	// CircuitBreakerDescription contains the state of a circuit breaker instance.
+	CircuitBreakerDescription struct {
+		// Policy is the name of the circuit breaker policy.
+		Policy string         `json:"policy,omitempty"`
+		Target string         `json:"target"`
+		Type   PolicyTypeName `json:"type"`
+		// Instance is the endpoint, actor type or actor ID the circuit breaker applies to.
+		Instance string `json:"instance,omitempty"`
+		breaker.Status
+	}
+)
+
+var componentTypes = []ComponentType{Binding, Configuration, Lock, Pubsub, Secretstore, Statestore}
+
+// Describe returns the effective policies and the current state of the circuit breakers.
+func (r *Resiliency) Describe() *Description {
+	d := &Description{
+		Targets:         []TargetDescription{},
+		CircuitBreakers: []CircuitBreakerDescription{},
+	}
+	if r == nil {
+		return d
+	}
+
+	for name, p := range r.apps {
+		d.Targets = append(d.Targets, TargetDescription{
+			Type: Endpoint, Name: name,
+			Timeout: p.Timeout, Retry: p.Retry, CircuitBreaker: p.CircuitBreaker,
+		})
+	}
+	for name, p := range r.actors {
+		d.Targets = append(d.Targets, TargetDescription{
+			Type: Actor, Name: name,
+			Timeout: p.PostLockPolicies.Timeout, Retry: p.PreLockPolicies.Retry, CircuitBreaker: p.PreLockPolicies.CircuitBreaker,
+		})
+	}
+	for name, p := range r.components {
+		for _, dir := range []struct {
+			name     string
+			policies PolicyNames
+		}{{"Inbound", p.Inbound}, {"Outbound", p.Outbound}} {
+			if dir.policies == (PolicyNames{}) {
+				continue
+			}
+			d.Targets = append(d.Targets, TargetDescription{
+				Type: Component, Name: name, Direction: dir.name,
+				Timeout: dir.policies.Timeout, Retry: dir.policies.Retry, CircuitBreaker: dir.policies.CircuitBreaker,
+			})
+		}
+	}
+	d.Targets = append(d.Targets, r.describeDefaultPolicies()...)
+	sort.SliceStable(d.Targets, func(i, j int) bool {
+		a, b := d.Targets[i], d.Targets[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.ComponentType != b.ComponentType {
+			return a.ComponentType < b.ComponentType
+		}
+		return a.Direction < b.Direction
+	})
+
+	for app, cache := range r.serviceCBs {
+		d.CircuitBreakers = append(d.CircuitBreakers, describeCircuitBreakerCache(Endpoint, app, r.apps[app].CircuitBreaker, cache)...)
+	}
+	for actorType, cache := range r.actorCBCaches {
+		d.CircuitBreakers = append(d.CircuitBreakers, describeCircuitBreakerCache(Actor, actorType, r.actors[actorType].PreLockPolicies.CircuitBreaker, cache)...)
+	}
+	r.componentCBs.RLock()
+	for name, cb := range r.componentCBs.cbs {
+		policy := r.components[name].Outbound.CircuitBreaker
+		if policy == "" {
+			policy = r.components[name].Inbound.CircuitBreaker
+		}
+		d.CircuitBreakers = append(d.CircuitBreakers, CircuitBreakerDescription{
+			Policy: policy,
+			Target: name,
+			Type:   Component,
+			Status: cb.Status(),
+		})
+	}
+	r.componentCBs.RUnlock()
+	sort.SliceStable(d.CircuitBreakers, func(i, j int) bool {
+		a, b := d.CircuitBreakers[i], d.CircuitBreakers[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Instance < b.Instance
+	})
+
+	return d
+}
+
+// describeDefaultPolicies returns the default policies of the target types, for the targets without policies.
+func (r *Resiliency) describeDefaultPolicies() []TargetDescription {
+	res := []TargetDescription{}
+	add := func(target TargetDescription, policyType PolicyType) {
+		names, ok := r.getDefaultPolicy(policyType)
+		if !ok {
+			return
+		}
+		target.Timeout, target.Retry, target.CircuitBreaker = names.Timeout, names.Retry, names.CircuitBreaker
+		res = append(res, target)
+	}
+
+	add(TargetDescription{Type: Endpoint}, &EndpointPolicy{})
+	add(TargetDescription{Type: Actor}, &ActorPolicy{})
+	for _, componentType := range componentTypes {
+		for _, direction := range []string{"Inbound", "Outbound"} {
+			add(
+				TargetDescription{Type: Component, ComponentType: componentType, Direction: direction},
+				&ComponentPolicy{componentType: componentType, componentDirection: direction},
+			)
+		}
+	}
+	return res
+}
+
+func describeCircuitBreakerCache(policyType PolicyTypeName, target string, policy string, cache *lru.Cache) []CircuitBreakerDescription {
+	res := []CircuitBreakerDescription{}
+	for _, key := range cache.Keys() {
+		val, ok := cache.Peek(key)
+		if !ok {
+			continue
+		}
+		cb, ok := val.(*breaker.CircuitBreaker)
+		if !ok {
+			continue
+		}
+		instance, _ := key.(string)
+		res = append(res, CircuitBreakerDescription{
+			Policy:   policy,
+			Target:   target,
+			Type:     policyType,
+			Instance: instance,
+			Status:   cb.Status(),
+		})
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	resiliencyV1alpha "github.com/dapr/dapr/pkg/apis/resiliency/v1alpha1"
+)
+
+func TestDescribe(t *testing.T) {
+	config := &resiliencyV1alpha.Resiliency{
+		Spec: resiliencyV1alpha.ResiliencySpec{
+			Policies: resiliencyV1alpha.Policies{
+				Timeouts: map[string]string{
+					"fast": "100ms",
+					fmt.Sprintf(string(DefaultTimeoutTemplate), ""): "1s",
+				},
+				Retries: map[string]resiliencyV1alpha.Retry{
+					"noRetry": {Policy: "constant", Duration: "10ms", MaxRetries: 0},
+				},
+				CircuitBreakers: map[string]resiliencyV1alpha.CircuitBreaker{
+					"twoFailures": {Trip: "consecutiveFailures > 1", MaxRequests: 1, Timeout: "60s"},
+				},
+			},
+			Targets: resiliencyV1alpha.Targets{
+				Apps: map[string]resiliencyV1alpha.EndpointPolicyNames{
+					"appB": {Timeout: "fast", CircuitBreaker: "twoFailures"},
+				},
+				Components: map[string]resiliencyV1alpha.ComponentPolicyNames{
+					"statestore": {Outbound: resiliencyV1alpha.PolicyNames{Retry: "noRetry", CircuitBreaker: "twoFailures"}},
+					"pubsub":     {Inbound: resiliencyV1alpha.PolicyNames{CircuitBreaker: "twoFailures"}},
+				},
+			},
+		},
+	}
+	r := FromConfigurations(log, config)
+
+	t.Run("targets", func(t *testing.T) {
+		d := r.Describe()
+		assert.Contains(t, d.Targets, TargetDescription{Type: Endpoint, Name: "appB", Timeout: "fast", CircuitBreaker: "twoFailures"})
+		assert.Contains(t, d.Targets, TargetDescription{Type: Component, Name: "statestore", Direction: "Outbound", Retry: "noRetry", CircuitBreaker: "twoFailures"})
+		assert.NotContains(t, d.Targets, TargetDescription{Type: Component, Name: "statestore", Direction: "Inbound"})
+		// Default policies
+		assert.Contains(t, d.Targets, TargetDescription{Type: Endpoint, Timeout: "DefaultTimeoutPolicy"})
+		assert.Contains(t, d.Targets, TargetDescription{Type: Component, ComponentType: Pubsub, Direction: "Inbound", Timeout: "DefaultTimeoutPolicy"})
+		assert.Empty(t, d.CircuitBreakers)
+	})
+
+	t.Run("circuit breakers", func(t *testing.T) {
+		fail := func(ctx context.Context) error {
+			return errors.New("forced failure")
+		}
+		r.EndpointPolicy(context.Background(), "appB", "method")(fail)
+		for i := 0; i < 2; i++ {
+			r.ComponentInboundPolicy(context.Background(), "pubsub", Pubsub)(fail)
+		}
+
+		d := r.Describe()
+		require.Len(t, d.CircuitBreakers, 2)
+
+		assert.Equal(t, Endpoint, d.CircuitBreakers[0].Type)
+		assert.Equal(t, "appB", d.CircuitBreakers[0].Target)
+		assert.Equal(t, "method", d.CircuitBreakers[0].Instance)
+		assert.Equal(t, "closed", d.CircuitBreakers[0].State)
+		assert.Equal(t, uint32(1), d.CircuitBreakers[0].ConsecutiveFailures)
+
+		assert.Equal(t, Component, d.CircuitBreakers[1].Type)
+		assert.Equal(t, "pubsub", d.CircuitBreakers[1].Target)
+		assert.Equal(t, "twoFailures", d.CircuitBreakers[1].Policy)
+		assert.Equal(t, "open", d.CircuitBreakers[1].State)
+	})
+
+	t.Run("no resiliency", func(t *testing.T) {
+		var nilResiliency *Resiliency
+		assert.Empty(t, nilResiliency.Describe().Targets)
+		assert.Empty(t, (&NoOp{}).Describe().CircuitBreakers)
+	})
+}
@@ -74,3 +74,11 @@ func (*NoOp) BuiltInPolicy(ctx context.Context, name BuiltInPolicyName) Runner {
 func (*NoOp) GetPolicy(target string, policyType PolicyType) *PolicyDescription {
 	return &PolicyDescription{}
 }
+
+// Describe returns an empty description, as there are no policies.
+func (*NoOp) Describe() *Description {
+	return &Description{
+		Targets:         []TargetDescription{},
+		CircuitBreakers: []CircuitBreakerDescription{},
+	}
+}
@@ -100,6 +100,8 @@ type (
 		BuiltInPolicy(ctx context.Context, name BuiltInPolicyName) Runner
 		// GetPolicy returns the policy that applies to the target, or nil if there is none.
 		GetPolicy(target string, policyType PolicyType) *PolicyDescription
+		// Describe returns the effective policies and the current state of the circuit breakers.
+		Describe() *Description
 	}
 
 	// Resiliency encapsulates configuration for timeouts, retries, and circuit breakers.
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"go.uber.org/ratelimit"
+
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+)
+
+const (
+	// Metadata of the input bindings limiting the delivery of their events to the app.
+	bindingMaxConcurrencyKey    = "maxConcurrency"
+	bindingRequestsPerSecondKey = "requestsPerSecond"
+	bindingMaxInFlightKey       = "maxInFlight"
+)
+
+// errBindingOverloaded is returned to an input binding which has as many events in flight as it's allowed to,
+// so that the binding can retry the event later.
+var errBindingOverloaded = errors.New("too many events in flight for the input binding")
+
+// bindingLimiter enforces the concurrency and rate limits of an input binding,
+// so that a flood of events from a binding can't starve the app or the other bindings.
+// A nil bindingLimiter doesn't limit anything.
+type bindingLimiter struct {
+	// concurrency holds a token for each event being delivered to the app, nil if unlimited.
+	concurrency chan struct{}
+	// rate is nil if unlimited.
+	rate ratelimit.Limiter
+	// maxInFlight is the maximum number of events waiting to be delivered or being delivered, 0 if unlimited.
+	maxInFlight int64
+	inFlight    int64
+}
+
+// newBindingLimiter returns the limiter of the input binding with the given metadata, or nil if it has no limits.
+func newBindingLimiter(metadata []componentsV1alpha1.MetadataItem) (*bindingLimiter, error) {
+	limits := map[string]int{}
+	for _, item := range metadata {
+		switch item.Name {
+		case bindingMaxConcurrencyKey, bindingRequestsPerSecondKey, bindingMaxInFlightKey:
+			val, err := strconv.Atoi(item.Value.String())
+			if err != nil || val < 0 {
+				return nil, fmt.Errorf("invalid %s for the input binding: %q must be a non-negative integer", item.Name, item.Value.String())
+			}
+			limits[item.Name] = val
+		}
+	}
+
+	l := &bindingLimiter{}
+	if limits[bindingMaxConcurrencyKey] > 0 {
+		l.concurrency = make(chan struct{}, limits[bindingMaxConcurrencyKey])
+	}
+	if limits[bindingRequestsPerSecondKey] > 0 {
+		l.rate = ratelimit.New(limits[bindingRequestsPerSecondKey])
+	}
+	l.maxInFlight = int64(limits[bindingMaxInFlightKey])
+
+	if l.concurrency == nil && l.rate == nil && l.maxInFlight == 0 {
+		return nil, nil
+	}
+	return l, nil
+}
+
+// acquire waits until an event of the binding can be delivered to the app.
+// It fails at once with errBindingOverloaded if the binding has too many events in flight.
+// The returned function must be called when the delivery completes.
+func (l *bindingLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if n := atomic.AddInt64(&l.inFlight, 1); l.maxInFlight > 0 && n > l.maxInFlight {
+		atomic.AddInt64(&l.inFlight, -1)
+		return nil, errBindingOverloaded
+	}
+
+	if l.rate != nil {
+		l.rate.Take()
+	}
+
+	if l.concurrency != nil {
+		select {
+		case l.concurrency <- struct{}{}:
+		case <-ctx.Done():
+			atomic.AddInt64(&l.inFlight, -1)
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		if l.concurrency != nil {
+			<-l.concurrency
+		}
+		atomic.AddInt64(&l.inFlight, -1)
+	}, nil
+}
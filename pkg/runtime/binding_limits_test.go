@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+)
+
+func bindingLimitsMetadata(items map[string]string) []componentsV1alpha1.MetadataItem {
+	metadata := []componentsV1alpha1.MetadataItem{{Name: "route", Value: componentsV1alpha1.DynamicValue{JSON: v1.JSON{Raw: []byte("/orders")}}}}
+	for k, v := range items {
+		metadata = append(metadata, componentsV1alpha1.MetadataItem{Name: k, Value: componentsV1alpha1.DynamicValue{JSON: v1.JSON{Raw: []byte(v)}}})
+	}
+	return metadata
+}
+
+func TestNewBindingLimiter(t *testing.T) {
+	t.Run("no limits", func(t *testing.T) {
+		l, err := newBindingLimiter(bindingLimitsMetadata(nil))
+		require.NoError(t, err)
+		assert.Nil(t, l)
+
+		l, err = newBindingLimiter(bindingLimitsMetadata(map[string]string{bindingMaxConcurrencyKey: "0"}))
+		require.NoError(t, err)
+		assert.Nil(t, l)
+
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		l, err := newBindingLimiter(bindingLimitsMetadata(map[string]string{
+			bindingMaxConcurrencyKey:    "2",
+			bindingRequestsPerSecondKey: "100",
+			bindingMaxInFlightKey:       "5",
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		assert.Equal(t, 2, cap(l.concurrency))
+		assert.NotNil(t, l.rate)
+		assert.Equal(t, int64(5), l.maxInFlight)
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		for _, val := range []string{"-1", "many", "1.5"} {
+			_, err := newBindingLimiter(bindingLimitsMetadata(map[string]string{bindingRequestsPerSecondKey: val}))
+			assert.Error(t, err, val)
+		}
+	})
+}
+
+func TestBindingLimiterAcquire(t *testing.T) {
+	t.Run("max concurrency", func(t *testing.T) {
+		l, err := newBindingLimiter(bindingLimitsMetadata(map[string]string{bindingMaxConcurrencyKey: "1"}))
+		require.NoError(t, err)
+
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+
+		acquired := make(chan func())
+		go func() {
+			r, _ := l.acquire(context.Background())
+			acquired <- r
+		}()
+		select {
+		case <-acquired:
+			t.Fatal("acquired more than the max concurrency")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		release()
+		select {
+		case r := <-acquired:
+			r()
+		case <-time.After(time.Second):
+			t.Fatal("timeout while waiting for the concurrency to be released")
+		}
+		assert.Equal(t, int64(0), l.inFlight)
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		l, err := newBindingLimiter(bindingLimitsMetadata(map[string]string{bindingMaxConcurrencyKey: "1"}))
+		require.NoError(t, err)
+
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = l.acquire(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int64(1), l.inFlight)
+	})
+
+	t.Run("max in flight", func(t *testing.T) {
+		l, err := newBindingLimiter(bindingLimitsMetadata(map[string]string{bindingMaxInFlightKey: "2"}))
+		require.NoError(t, err)
+
+		release1, err := l.acquire(context.Background())
+		require.NoError(t, err)
+		release2, err := l.acquire(context.Background())
+		require.NoError(t, err)
+
+		_, err = l.acquire(context.Background())
+		assert.ErrorIs(t, err, errBindingOverloaded)
+
+		release1()
+		release3, err := l.acquire(context.Background())
+		require.NoError(t, err)
+		release2()
+		release3()
+		assert.Equal(t, int64(0), l.inFlight)
+	})
+
+	t.Run("requests per second", func(t *testing.T) {
+		l, err := newBindingLimiter(bindingLimitsMetadata(map[string]string{bindingRequestsPerSecondKey: "20"}))
+		require.NoError(t, err)
+
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			release, err := l.acquire(context.Background())
+			require.NoError(t, err)
+			release()
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	})
+}
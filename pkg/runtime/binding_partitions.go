@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dapr/components-contrib/bindings"
+
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+)
+
+const (
+	// bindingPartitionKeyKey is the metadata of the input bindings whose events with the same partition key
+	// are delivered to the app one at a time, in the order they're read.
+	// It's either "metadata.<key>", for a metadata key of the events, or "data.<path>", for a dot separated
+	// path in the JSON payload of the events.
+	bindingPartitionKeyKey = "partitionKey"
+
+	partitionKeyMetadataPrefix = "metadata."
+	partitionKeyDataPrefix     = "data."
+)
+
+// bindingPartitioner delivers the events of an input binding with the same partition key serially,
+// while the events with different keys, or without a key, are delivered concurrently.
+// A nil bindingPartitioner doesn't order anything.
+type bindingPartitioner struct {
+	// metadataKey is the metadata key of the events holding the partition key, empty if it's in the payload.
+	metadataKey string
+	// dataPath is the path of the partition key in the payload of the events.
+	dataPath []string
+
+	lock       sync.Mutex
+	partitions map[string]*bindingPartition
+}
+
+// bindingPartition is the queue of the events of a partition key.
+type bindingPartition struct {
+	// tail is closed when the last event queued for the key is delivered.
+	tail    chan struct{}
+	pending int
+}
+
+// newBindingPartitioner returns the partitioner of the input binding with the given metadata, or nil if it has no partition key.
+func newBindingPartitioner(metadata []componentsV1alpha1.MetadataItem) (*bindingPartitioner, error) {
+	var rule string
+	for _, item := range metadata {
+		if item.Name == bindingPartitionKeyKey {
+			rule = item.Value.String()
+		}
+	}
+	if rule == "" {
+		return nil, nil
+	}
+
+	p := &bindingPartitioner{partitions: map[string]*bindingPartition{}}
+	switch {
+	case strings.HasPrefix(rule, partitionKeyMetadataPrefix) && len(rule) > len(partitionKeyMetadataPrefix):
+		p.metadataKey = strings.TrimPrefix(rule, partitionKeyMetadataPrefix)
+	case strings.HasPrefix(rule, partitionKeyDataPrefix) && len(rule) > len(partitionKeyDataPrefix):
+		p.dataPath = strings.Split(strings.TrimPrefix(rule, partitionKeyDataPrefix), ".")
+	default:
+		return nil, fmt.Errorf("invalid %s for the input binding: %q must start with %q or %q", bindingPartitionKeyKey, rule, partitionKeyMetadataPrefix, partitionKeyDataPrefix)
+	}
+	return p, nil
+}
+
+// partitionKey returns the partition key of the event, empty if it has none.
+func (p *bindingPartitioner) partitionKey(resp *bindings.ReadResponse) string {
+	if p.metadataKey != "" {
+		return resp.Metadata[p.metadataKey]
+	}
+
+	var val interface{}
+	if err := json.Unmarshal(resp.Data, &val); err != nil {
+		return ""
+	}
+	for _, field := range p.dataPath {
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		val = obj[field]
+	}
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// acquire waits until the events read before the event, with the same partition key, are delivered.
+// The returned function must be called when the delivery of the event completes.
+func (p *bindingPartitioner) acquire(ctx context.Context, resp *bindings.ReadResponse) (func(), error) {
+	if p == nil {
+		return func() {}, nil
+	}
+	key := p.partitionKey(resp)
+	if key == "" {
+		return func() {}, nil
+	}
+
+	p.lock.Lock()
+	partition, ok := p.partitions[key]
+	if !ok {
+		partition = &bindingPartition{}
+		p.partitions[key] = partition
+	}
+	prev := partition.tail
+	done := make(chan struct{})
+	partition.tail = done
+	partition.pending++
+	p.lock.Unlock()
+
+	release := func() {
+		close(done)
+		p.lock.Lock()
+		partition.pending--
+		if partition.pending == 0 {
+			delete(p.partitions, key)
+		}
+		p.lock.Unlock()
+	}
+
+	if prev != nil {
+		select {
+		case <-prev:
+		case <-ctx.Done():
+			// Keep the order of the events queued after this one.
+			go func() {
+				<-prev
+				release()
+			}()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/bindings"
+)
+
+func TestNewBindingPartitioner(t *testing.T) {
+	t.Run("no partition key", func(t *testing.T) {
+		p, err := newBindingPartitioner(bindingLimitsMetadata(nil))
+		require.NoError(t, err)
+		assert.Nil(t, p)
+
+		release, err := p.acquire(context.Background(), &bindings.ReadResponse{})
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("invalid partition key", func(t *testing.T) {
+		for _, rule := range []string{"orderId", "metadata.", "data."} {
+			_, err := newBindingPartitioner(bindingLimitsMetadata(map[string]string{bindingPartitionKeyKey: rule}))
+			assert.Error(t, err, rule)
+		}
+	})
+}
+
+func TestBindingPartitionKey(t *testing.T) {
+	t.Run("metadata", func(t *testing.T) {
+		p, err := newBindingPartitioner(bindingLimitsMetadata(map[string]string{bindingPartitionKeyKey: "metadata.sessionId"}))
+		require.NoError(t, err)
+		assert.Equal(t, "s1", p.partitionKey(&bindings.ReadResponse{Metadata: map[string]string{"sessionId": "s1"}}))
+		assert.Equal(t, "", p.partitionKey(&bindings.ReadResponse{}))
+	})
+
+	t.Run("data", func(t *testing.T) {
+		p, err := newBindingPartitioner(bindingLimitsMetadata(map[string]string{bindingPartitionKeyKey: "data.order.customer"}))
+		require.NoError(t, err)
+		assert.Equal(t, "c1", p.partitionKey(&bindings.ReadResponse{Data: []byte(`{"order":{"customer":"c1"}}`)}))
+		assert.Equal(t, "42", p.partitionKey(&bindings.ReadResponse{Data: []byte(`{"order":{"customer":42}}`)}))
+		assert.Equal(t, "", p.partitionKey(&bindings.ReadResponse{Data: []byte(`{"order":"c1"}`)}))
+		assert.Equal(t, "", p.partitionKey(&bindings.ReadResponse{Data: []byte(`not json`)}))
+	})
+}
+
+func TestBindingPartitionerAcquire(t *testing.T) {
+	p, err := newBindingPartitioner(bindingLimitsMetadata(map[string]string{bindingPartitionKeyKey: "metadata.key"}))
+	require.NoError(t, err)
+	event := func(key string) *bindings.ReadResponse {
+		return &bindings.ReadResponse{Metadata: map[string]string{"key": key}}
+	}
+
+	t.Run("same key is serial", func(t *testing.T) {
+		release, err := p.acquire(context.Background(), event("a"))
+		require.NoError(t, err)
+
+		acquired := make(chan struct{})
+		go func() {
+			release2, err := p.acquire(context.Background(), event("a"))
+			assert.NoError(t, err)
+			release2()
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("event acquired before the previous event of the key was released")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		// Different keys and events without a key aren't blocked.
+		releaseB, err := p.acquire(context.Background(), event("b"))
+		require.NoError(t, err)
+		releaseB()
+		releaseNoKey, err := p.acquire(context.Background(), event(""))
+		require.NoError(t, err)
+		releaseNoKey()
+
+		release()
+		<-acquired
+	})
+
+	t.Run("in order", func(t *testing.T) {
+		release, err := p.acquire(context.Background(), event("a"))
+		require.NoError(t, err)
+
+		var (
+			lock  sync.Mutex
+			order []int
+			wg    sync.WaitGroup
+		)
+		pending := func() int {
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			return p.partitions["a"].pending
+		}
+		for i := 1; i <= 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				release, err := p.acquire(context.Background(), event("a"))
+				assert.NoError(t, err)
+				lock.Lock()
+				order = append(order, i)
+				lock.Unlock()
+				release()
+			}(i)
+			// Wait for the event to be queued before reading the next one.
+			require.Eventually(t, func() bool { return pending() == i+1 }, time.Second, time.Millisecond)
+		}
+		release()
+		wg.Wait()
+		assert.Equal(t, []int{1, 2, 3, 4}, order)
+	})
+
+	t.Run("cancelled wait keeps the order", func(t *testing.T) {
+		release, err := p.acquire(context.Background(), event("a"))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = p.acquire(ctx, event("a"))
+		require.ErrorIs(t, err, context.Canceled)
+
+		acquired := make(chan struct{})
+		go func() {
+			release3, err := p.acquire(context.Background(), event("a"))
+			assert.NoError(t, err)
+			release3()
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("event acquired before the previous event of the key was released")
+		case <-time.After(50 * time.Millisecond):
+		}
+		release()
+		<-acquired
+
+		assert.Eventually(t, func() bool {
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			return len(p.partitions) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
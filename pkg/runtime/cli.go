@@ -17,6 +17,7 @@ package runtime
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -27,11 +28,13 @@ import (
 	"github.com/dapr/kit/logger"
 
 	"github.com/dapr/dapr/pkg/acl"
+	"github.com/dapr/dapr/pkg/actors"
 	resiliencyV1alpha "github.com/dapr/dapr/pkg/apis/resiliency/v1alpha1"
 	"github.com/dapr/dapr/pkg/apphealth"
 	daprGlobalConfig "github.com/dapr/dapr/pkg/config"
 	env "github.com/dapr/dapr/pkg/config/env"
 	"github.com/dapr/dapr/pkg/cors"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
 	"github.com/dapr/dapr/pkg/grpc"
 	"github.com/dapr/dapr/pkg/metrics"
 	"github.com/dapr/dapr/pkg/modes"
@@ -49,19 +52,26 @@ func FromFlags() (*DaprRuntime, error) {
 	daprHTTPPort := flag.String("dapr-http-port", strconv.Itoa(DefaultDaprHTTPPort), "HTTP port for Dapr API to listen on")
 	daprAPIListenAddresses := flag.String("dapr-listen-addresses", DefaultAPIListenAddress, "One or more addresses for the Dapr API to listen on, CSV limited")
 	daprPublicPort := flag.String("dapr-public-port", "", "Public port for Dapr Health and Metadata to listen on")
+	daprPublicGRPCPort := flag.String("dapr-public-grpc-port", "", "Public port for the gRPC health service of Dapr to listen on, without TLS nor authentication; disabled if empty")
 	daprAPIGRPCPort := flag.String("dapr-grpc-port", strconv.Itoa(DefaultDaprAPIGRPCPort), "gRPC port for the Dapr API to listen on")
 	daprInternalGRPCPort := flag.String("dapr-internal-grpc-port", "", "gRPC port for the Dapr Internal API to listen on")
 	appPort := flag.String("app-port", "", "The port the application is listening on")
 	profilePort := flag.String("profile-port", strconv.Itoa(DefaultProfilePort), "The port for the profile server")
-	appProtocol := flag.String("app-protocol", string(HTTPProtocol), "Protocol for the application: grpc or http")
+	appProtocol := flag.String("app-protocol", string(HTTPProtocol), "Protocol for the application: grpc, http or h2c (HTTP/2 cleartext)")
 	componentsPath := flag.String("components-path", "", "Path for components directory. If empty, components will not be loaded. Self-hosted mode only")
-	resourcesPath := flag.String("resources-path", "", "Path for resources directory. If empty, resources will not be loaded. Self-hosted mode only")
-	config := flag.String("config", "", "Path to config file, or name of a configuration object")
+	var resourcesPath []string
+	flag.Func("resources-path", "Path for resources directory. Can be repeated: the resources of the later directories override the ones with the same name of the earlier directories. If empty, resources will not be loaded. Self-hosted mode only", func(path string) error {
+		resourcesPath = append(resourcesPath, path)
+		return nil
+	})
+	config := flag.String("config", "", "Path to config file, HTTP(S) URL of a config file in self-hosted mode, or name of a configuration object. A comma separated list of configurations is merged, the next ones overriding the tracing, metrics and features of the first one")
 	appID := flag.String("app-id", "", "A unique ID for Dapr. Used for Service Discovery and state")
 	controlPlaneAddress := flag.String("control-plane-address", "", "Address for a Dapr control plane")
 	sentryAddress := flag.String("sentry-address", "", "Address for the Sentry CA service")
 	placementServiceHostAddr := flag.String("placement-host-address", "", "Addresses for Dapr Actor Placement servers")
-	allowedOrigins := flag.String("allowed-origins", cors.DefaultAllowedOrigins, "Allowed HTTP origins")
+	placementTLSCAFile := flag.String("placement-tls-ca-file", "", "Path to the PEM bundle of the CAs of a placement service which isn't verified with the Dapr trust bundle, such as a managed placement service outside of the cluster; the system CAs are used if only placement-tls-server-name is set")
+	placementTLSServerName := flag.String("placement-tls-server-name", "", "Server name sent with SNI and verified in the certificate of a placement service which isn't verified with the Dapr trust bundle")
+	allowedOrigins := flag.String("allowed-origins", cors.DefaultAllowedOrigins, "Allowed HTTP origins. Ignored when the configuration defines a CORS policy")
 	enableProfiling := flag.Bool("enable-profiling", false, "Enable profiling")
 	runtimeVersion := flag.Bool("version", false, "Prints the runtime version")
 	buildInfo := flag.Bool("build-info", false, "Prints the build info")
@@ -69,8 +79,10 @@ func FromFlags() (*DaprRuntime, error) {
 	appMaxConcurrency := flag.Int("app-max-concurrency", -1, "Controls the concurrency level when forwarding requests to user code; set to -1 for no limits")
 	enableMTLS := flag.Bool("enable-mtls", false, "Enables automatic mTLS for daprd to daprd communication channels")
 	appSSL := flag.Bool("app-ssl", false, "Sets the URI scheme of the app to https and attempts an SSL connection")
+	appHTTP2Detection := flag.Bool("app-http2-detection", false, "Detects if the HTTP app supports HTTP/2, with ALPN when app-ssl is set and with the HTTP/2 connection preface otherwise, and invokes the app with HTTP/2 if it does")
 	daprHTTPMaxRequestSize := flag.Int("dapr-http-max-request-size", DefaultMaxRequestBodySize, "Increasing max size of request body in MB to handle uploading of big files")
 	unixDomainSocket := flag.String("unix-domain-socket", "", "Path to a unix domain socket dir mount. If specified, Dapr API servers will use Unix Domain Sockets")
+	appUnixDomainSocket := flag.String("app-unix-domain-socket", "", "Path to the unix domain socket the app listens on. If specified, Dapr connects to the app over it instead of the app port")
 	daprHTTPReadBufferSize := flag.Int("dapr-http-read-buffer-size", DefaultReadBufferSize, "Increasing max size of read buffer in KB to handle sending multi-KB headers")
 	daprGracefulShutdownSeconds := flag.Int("dapr-graceful-shutdown-seconds", int(DefaultGracefulShutdownDuration/time.Second), "Graceful shutdown time in seconds")
 	enableAPILogging := flag.Bool("enable-api-logging", false, "Enable API logging for API calls")
@@ -80,6 +92,18 @@ func FromFlags() (*DaprRuntime, error) {
 	appHealthProbeInterval := flag.Int("app-health-probe-interval", int(apphealth.DefaultProbeInterval/time.Second), "Interval to probe for the health of the app in seconds")
 	appHealthProbeTimeout := flag.Int("app-health-probe-timeout", int(apphealth.DefaultProbeTimeout/time.Millisecond), "Timeout for app health probes in milliseconds")
 	appHealthThreshold := flag.Int("app-health-threshold", int(apphealth.DefaultThreshold), "Number of consecutive failures for the app to be considered unhealthy")
+	appStartupProbeMethod := flag.String("app-startup-probe-method", "", "HTTP path or gRPC method invoked on the app until it succeeds before delivering pubsub messages and input bindings events; disabled if empty")
+	appStartupProbeInterval := flag.Int("app-startup-probe-interval", int(DefaultAppStartupProbeInterval/time.Second), "Interval between the startup probes of the app in seconds")
+	appStartupProbeTimeout := flag.Int("app-startup-probe-timeout", 0, "Maximum time to wait for the startup probe of the app to succeed in seconds, after which events are delivered anyway; 0 to wait indefinitely")
+	traceAttributes := flag.String("trace-attributes", "", "Comma separated list of key=value attributes added to all the spans, in addition to the ones of the tracing configuration")
+	enableDevConsole := flag.Bool("enable-dev-console", false, "Stream the API calls, pub/sub deliveries and actor invocations of the sidecar on the v1.0-alpha1/devconsole endpoint of the Dapr HTTP API; for development only, ignored in Kubernetes mode")
+	internalGRPCMaxConnectionAge := flag.Int("dapr-internal-grpc-max-connection-age", int(grpc.DefaultMaxConnectionAge/time.Second), "Age in seconds after which the internal gRPC server closes the connections of the other sidecars, so that they reconnect to the new instances")
+	internalGRPCKeepaliveTime := flag.Int("dapr-internal-grpc-keepalive-time", 0, "Interval in seconds of the keepalive pings of the internal gRPC connections without activity, at least 10; 0 to disable the pings")
+	internalGRPCKeepaliveTimeout := flag.Int("dapr-internal-grpc-keepalive-timeout", int(DefaultInternalGRPCKeepaliveTimeout/time.Second), "Time in seconds to wait for the acknowledgement of a keepalive ping before closing the internal gRPC connection")
+	internalGRPCConnsPerTarget := flag.Int("dapr-internal-grpc-connections-per-target", DefaultInternalGRPCConnectionsPerTarget, "Number of internal gRPC connections to each sidecar, between which the calls are spread")
+	internalGRPCMaxStreams := flag.Int("dapr-internal-grpc-max-concurrent-streams", 0, "Maximum number of concurrent streams of each connection accepted by the internal gRPC server; 0 for no limit")
+	internalGRPCIdleTimeout := flag.Int("dapr-internal-grpc-idle-timeout", 0, "Time in seconds after which the internal gRPC connections without calls are closed; 0 to keep them open")
+	publicAllowedCIDRs := flag.String("public-allowed-cidrs", "", "Comma separated list of CIDRs the public port accepts calls from, except for the health endpoints; any address if empty")
 
 	loggerOptions := logger.DefaultOptions()
 	loggerOptions.AttachCmdFlags(flag.StringVar, flag.BoolVar)
@@ -90,8 +114,8 @@ func FromFlags() (*DaprRuntime, error) {
 
 	flag.Parse()
 
-	if *resourcesPath != "" {
-		componentsPath = resourcesPath
+	if len(resourcesPath) == 0 && *componentsPath != "" {
+		resourcesPath = []string{*componentsPath}
 	}
 
 	if *runtimeVersion {
@@ -121,6 +145,7 @@ func FromFlags() (*DaprRuntime, error) {
 
 	log.Infof("starting Dapr Runtime -- version %s -- commit %s", version.Version(), version.Commit())
 	log.Infof("log level set to: %s", loggerOptions.OutputLevel)
+	diag.DefaultRuntimeLogging.Init(loggerOptions, *enableAPILogging)
 
 	// Initialize dapr metrics exporter
 	if err := metricsExporter.Init(); err != nil {
@@ -164,6 +189,15 @@ func FromFlags() (*DaprRuntime, error) {
 		publicPort = &port
 	}
 
+	var publicGRPCPort *int
+	if *daprPublicGRPCPort != "" {
+		port, cerr := strconv.Atoi(*daprPublicGRPCPort)
+		if cerr != nil {
+			return nil, errors.Wrap(cerr, "error parsing dapr-public-grpc-port")
+		}
+		publicGRPCPort = &port
+	}
+
 	var applicationPort int
 	if *appPort != "" {
 		applicationPort, err = strconv.Atoi(*appPort)
@@ -206,6 +240,11 @@ func FromFlags() (*DaprRuntime, error) {
 		placementAddresses = parsePlacementAddr(*placementServiceHostAddr)
 	}
 
+	placementTLS, err := getPlacementTLSConfig(*placementTLSCAFile, *placementTLSServerName, os.Getenv(security.PlacementTokenEnvVar))
+	if err != nil {
+		return nil, err
+	}
+
 	var concurrency int
 	if *appMaxConcurrency != -1 {
 		concurrency = *appMaxConcurrency
@@ -247,13 +286,34 @@ func FromFlags() (*DaprRuntime, error) {
 		healthThreshold = int32(*appHealthThreshold)
 	}
 
+	if *internalGRPCKeepaliveTime < 0 || *internalGRPCKeepaliveTimeout < 0 || *internalGRPCIdleTimeout < 0 || *internalGRPCMaxConnectionAge < 0 {
+		return nil, errors.New("the durations of the internal gRPC connections must not be negative")
+	}
+	if *internalGRPCConnsPerTarget < 1 {
+		return nil, errors.New("value for 'dapr-internal-grpc-connections-per-target' must be at least 1")
+	}
+	if *internalGRPCMaxStreams < 0 || int64(*internalGRPCMaxStreams) > math.MaxUint32 {
+		return nil, errors.New("value for 'dapr-internal-grpc-max-concurrent-streams' is out of range")
+	}
+
+	publicAllowlist, err := utils.ParseIPAllowlist(*publicAllowedCIDRs)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing public-allowed-cidrs")
+	}
+
+	spanAttributes, err := parseTraceAttributes(*traceAttributes)
+	if err != nil {
+		return nil, err
+	}
+
 	runtimeConfig := NewRuntimeConfig(NewRuntimeConfigOpts{
 		ID:                           *appID,
 		PlacementAddresses:           placementAddresses,
+		PlacementTLS:                 placementTLS,
 		controlPlaneAddress:          *controlPlaneAddress,
 		AllowedOrigins:               *allowedOrigins,
 		GlobalConfig:                 *config,
-		ComponentsPath:               *componentsPath,
+		ResourcesPath:                resourcesPath,
 		AppProtocol:                  appPrtcl,
 		Mode:                         *mode,
 		HTTPPort:                     daprHTTP,
@@ -261,6 +321,7 @@ func FromFlags() (*DaprRuntime, error) {
 		APIGRPCPort:                  daprAPIGRPC,
 		APIListenAddresses:           daprAPIListenAddressList,
 		PublicPort:                   publicPort,
+		PublicGRPCPort:               publicGRPCPort,
 		AppPort:                      applicationPort,
 		ProfilePort:                  profPort,
 		EnableProfiling:              *enableProfiling,
@@ -270,6 +331,8 @@ func FromFlags() (*DaprRuntime, error) {
 		AppSSL:                       *appSSL,
 		MaxRequestBodySize:           maxRequestBodySize,
 		UnixDomainSocket:             *unixDomainSocket,
+		AppUnixDomainSocket:          *appUnixDomainSocket,
+		AppHTTP2Detection:            *appHTTP2Detection,
 		ReadBufferSize:               readBufferSize,
 		GracefulShutdownDuration:     gracefulShutdownDuration,
 		EnableAPILogging:             *enableAPILogging,
@@ -279,6 +342,18 @@ func FromFlags() (*DaprRuntime, error) {
 		AppHealthProbeInterval:       healthProbeInterval,
 		AppHealthProbeTimeout:        healthProbeTimeout,
 		AppHealthThreshold:           healthThreshold,
+		AppStartupProbeMethod:        *appStartupProbeMethod,
+		AppStartupProbeInterval:      time.Duration(*appStartupProbeInterval) * time.Second,
+		AppStartupProbeTimeout:       time.Duration(*appStartupProbeTimeout) * time.Second,
+		TraceAttributes:              spanAttributes,
+		PublicAllowlist:              publicAllowlist,
+		EnableDevConsole:             *enableDevConsole,
+		InternalGRPCMaxConnectionAge: time.Duration(*internalGRPCMaxConnectionAge) * time.Second,
+		InternalGRPCKeepaliveTime:    time.Duration(*internalGRPCKeepaliveTime) * time.Second,
+		InternalGRPCKeepaliveTimeout: time.Duration(*internalGRPCKeepaliveTimeout) * time.Second,
+		InternalGRPCConnsPerTarget:   *internalGRPCConnsPerTarget,
+		InternalGRPCMaxStreams:       uint32(*internalGRPCMaxStreams),
+		InternalGRPCIdleTimeout:      time.Duration(*internalGRPCIdleTimeout) * time.Second,
 	})
 
 	// set environment variables
@@ -329,14 +404,29 @@ func FromFlags() (*DaprRuntime, error) {
 	var namespace string
 	var podName string
 
-	if *config != "" {
-		switch modes.DaprMode(*mode) {
-		case modes.KubernetesMode:
-			namespace = os.Getenv("NAMESPACE")
-			podName = os.Getenv("POD_NAME")
-			globalConfig, configErr = daprGlobalConfig.LoadKubernetesConfiguration(*config, namespace, podName, operatorClient)
-		case modes.StandaloneMode:
-			globalConfig, _, configErr = daprGlobalConfig.LoadStandaloneConfiguration(*config)
+	// The configuration may be a comma separated list of configurations, which are merged.
+	if configNames := daprGlobalConfig.SplitConfigurationNames(*config); len(configNames) > 0 {
+		configs := make([]*daprGlobalConfig.Configuration, 0, len(configNames))
+		for _, name := range configNames {
+			var conf *daprGlobalConfig.Configuration
+			switch modes.DaprMode(*mode) {
+			case modes.KubernetesMode:
+				namespace = os.Getenv("NAMESPACE")
+				podName = os.Getenv("POD_NAME")
+				conf, configErr = daprGlobalConfig.LoadKubernetesConfiguration(name, namespace, podName, operatorClient)
+			case modes.StandaloneMode:
+				conf, _, configErr = daprGlobalConfig.LoadStandaloneConfiguration(name)
+			}
+			if configErr != nil {
+				configErr = errors.Wrapf(configErr, "configuration %s", name)
+				break
+			}
+			if conf != nil {
+				configs = append(configs, conf)
+			}
+		}
+		if configErr == nil && len(configs) > 0 {
+			globalConfig = daprGlobalConfig.MergeConfigurations(configs...)
 		}
 	}
 
@@ -348,6 +438,13 @@ func FromFlags() (*DaprRuntime, error) {
 		globalConfig = daprGlobalConfig.LoadDefaultConfiguration()
 	}
 
+	if levels := globalConfig.Spec.LoggingSpec.Levels; len(levels) > 0 {
+		if err := diag.DefaultRuntimeLogging.SetScopeLevels(levels); err != nil {
+			return nil, err
+		}
+		log.Infof("log levels of scopes set to: %v", levels)
+	}
+
 	// TODO: Remove once AppHealthCheck feature is finalized
 	if !daprGlobalConfig.IsFeatureEnabled(globalConfig.Spec.Features, daprGlobalConfig.AppHealthCheck) && *enableAppHealthCheck {
 		log.Warnf("App health checks are a preview feature and require the %s feature flag to be enabled. See https://docs.dapr.io/operations/configuration/preview-features/ on how to enable preview features.", daprGlobalConfig.AppHealthCheck)
@@ -364,7 +461,9 @@ func FromFlags() (*DaprRuntime, error) {
 			namespace = os.Getenv("NAMESPACE")
 			resiliencyConfigs = resiliencyConfig.LoadKubernetesResiliency(log, *appID, namespace, operatorClient)
 		case modes.StandaloneMode:
-			resiliencyConfigs = resiliencyConfig.LoadStandaloneResiliency(log, *appID, *componentsPath)
+			for _, path := range resourcesPath {
+				resiliencyConfigs = append(resiliencyConfigs, resiliencyConfig.LoadStandaloneResiliency(log, *appID, path)...)
+			}
 		}
 		log.Debugf("Found %d resiliency configurations.", len(resiliencyConfigs))
 		resiliencyProvider = resiliencyConfig.FromConfigurations(log, resiliencyConfigs...)
@@ -389,3 +488,44 @@ func parsePlacementAddr(val string) []string {
 	}
 	return parsed
 }
+
+// getPlacementTLSConfig returns the TLS configuration of a placement service which isn't verified with the trust bundle,
+// or nil if neither the CA bundle nor the server name is set. The token is only sent over this TLS connection.
+func getPlacementTLSConfig(caFile, serverName, token string) (*actors.PlacementTLSConfig, error) {
+	if caFile == "" && serverName == "" {
+		if token != "" {
+			log.Warnf("%s is ignored without placement-tls-ca-file or placement-tls-server-name", security.PlacementTokenEnvVar)
+		}
+		return nil, nil
+	}
+
+	config := &actors.PlacementTLSConfig{
+		ServerName: serverName,
+		Token:      strings.TrimSpace(token),
+	}
+	if caFile != "" {
+		rootCA, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading placement-tls-ca-file")
+		}
+		config.RootCA = rootCA
+	}
+	return config, nil
+}
+
+// parseTraceAttributes parses a comma separated list of key=value span attributes.
+func parseTraceAttributes(val string) (map[string]string, error) {
+	if strings.TrimSpace(val) == "" {
+		return nil, nil
+	}
+	attributes := map[string]string{}
+	for _, attr := range strings.Split(val, ",") {
+		k, v, ok := strings.Cut(attr, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid trace attribute %q: expected key=value", strings.TrimSpace(attr))
+		}
+		attributes[k] = strings.TrimSpace(v)
+	}
+	return attributes, nil
+}
@@ -14,9 +14,12 @@ limitations under the License.
 package runtime
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParsePlacementAddr(t *testing.T) {
@@ -44,3 +47,45 @@ func TestParsePlacementAddr(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTraceAttributes(t *testing.T) {
+	attributes, err := parseTraceAttributes("team=payments, environment = staging,empty=")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "environment": "staging", "empty": ""}, attributes)
+
+	attributes, err = parseTraceAttributes("")
+	require.NoError(t, err)
+	assert.Nil(t, attributes)
+
+	_, err = parseTraceAttributes("team=payments,environment")
+	assert.EqualError(t, err, `invalid trace attribute "environment": expected key=value`)
+	_, err = parseTraceAttributes("=payments")
+	assert.Error(t, err)
+}
+
+func TestGetPlacementTLSConfig(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		config, err := getPlacementTLSConfig("", "", "secret")
+		require.NoError(t, err)
+		assert.Nil(t, config)
+	})
+
+	t.Run("server name and token", func(t *testing.T) {
+		config, err := getPlacementTLSConfig("", "placement.example.com", "secret\n")
+		require.NoError(t, err)
+		assert.Equal(t, "placement.example.com", config.ServerName)
+		assert.Equal(t, "secret", config.Token)
+		assert.Empty(t, config.RootCA)
+	})
+
+	t.Run("CA file", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(caFile, []byte("ca"), 0o600))
+		config, err := getPlacementTLSConfig(caFile, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("ca"), config.RootCA)
+
+		_, err = getPlacementTLSConfig(filepath.Join(t.TempDir(), "missing.pem"), "", "")
+		assert.Error(t, err)
+	})
+}
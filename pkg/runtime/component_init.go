@@ -0,0 +1,292 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/runtime/meta"
+	"github.com/dapr/kit/retry"
+)
+
+const (
+	defaultComponentInitRetryInitialInterval = time.Second
+	defaultComponentInitRetryMaxInterval     = time.Minute
+)
+
+// componentInitRetry is the background retry of the initialization of a component.
+type componentInitRetry struct {
+	cancel context.CancelFunc
+}
+
+// isOptionalComponent returns true if the component is optional: it's initialized without blocking the readiness of the
+// sidecar, and it's marked as degraded instead of stopping the sidecar if it fails to initialize.
+func (a *DaprRuntime) isOptionalComponent(comp componentsV1alpha1.Component) bool {
+	_, ok := a.optionalComponents[comp.Name]
+	return ok
+}
+
+// componentInitTimeout returns the initialization timeout of the component, or the default timeout of the configuration.
+func (a *DaprRuntime) componentInitTimeout(comp componentsV1alpha1.Component) time.Duration {
+	if timeout, err := time.ParseDuration(comp.Spec.InitTimeout); err == nil {
+		return timeout
+	}
+	if a.globalConfig != nil && a.globalConfig.Spec.ComponentsSpec.InitTimeout != "" {
+		timeout, err := time.ParseDuration(a.globalConfig.Spec.ComponentsSpec.InitTimeout)
+		if err == nil {
+			return timeout
+		}
+		log.Warnf("ignoring the invalid default component init timeout %q", a.globalConfig.Spec.ComponentsSpec.InitTimeout)
+	}
+	return defaultComponentInitTimeout
+}
+
+// initComponentsInParallel initializes the components: the secret stores first, since the other components may
+// reference their secrets, then the components of the other categories concurrently.
+// The components of a category are initialized in order, since they're registered in the same tables of the runtime.
+func (a *DaprRuntime) initComponentsInParallel(comps []componentsV1alpha1.Component) {
+	// The components queued before, such as the built-in secret store, are processed first.
+	a.flushOutstandingComponents()
+
+	a.componentInitLock.Lock()
+	defer a.componentInitLock.Unlock()
+
+	secretStores := make([]componentsV1alpha1.Component, 0, len(comps))
+	others := map[ComponentCategory][]componentsV1alpha1.Component{}
+	for _, comp := range comps {
+		category := a.extractComponentCategory(comp)
+		if category == secretStoreComponent {
+			secretStores = append(secretStores, comp)
+		} else {
+			others[category] = append(others[category], comp)
+		}
+	}
+
+	start := time.Now()
+	a.initComponentGroups(map[ComponentCategory][]componentsV1alpha1.Component{secretStoreComponent: secretStores})
+	a.initComponentGroups(others)
+	log.Infof("initialized %d components in parallel in %s", len(comps), time.Since(start))
+}
+
+// initComponentGroups initializes the groups of components concurrently, and waits for all of them.
+func (a *DaprRuntime) initComponentGroups(groups map[ComponentCategory][]componentsV1alpha1.Component) {
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group []componentsV1alpha1.Component) {
+			defer wg.Done()
+			for _, comp := range group {
+				if err := a.processComponentAndDependents(comp); err != nil {
+					a.onComponentError(comp, err)
+				}
+			}
+		}(group)
+	}
+	wg.Wait()
+}
+
+// processDeferredComponents initializes the optional components in the background after the other components were
+// initialized. The topic subscriptions and the input bindings of the optional components start once they're
+// initialized, if the others started already.
+func (a *DaprRuntime) processDeferredComponents() {
+	comps := a.deferredComponents
+	a.deferredComponents = nil
+	if len(comps) == 0 {
+		return
+	}
+
+	log.Infof("initializing %d optional components in the background", len(comps))
+	go func() {
+		for _, comp := range comps {
+			a.componentInitLock.Lock()
+			err := a.processComponentAndDependents(comp)
+			a.componentInitLock.Unlock()
+			if err != nil {
+				a.onComponentError(comp, err)
+				continue
+			}
+			a.startComponentInputs(comp)
+		}
+	}()
+}
+
+// componentInitBackoff returns the backoff of the retries of the component initializations, or nil if they're disabled.
+func (a *DaprRuntime) componentInitBackoff(ctx context.Context) backoff.BackOff {
+	if a.globalConfig == nil || !a.globalConfig.Spec.ComponentsSpec.InitRetry.Enabled {
+		return nil
+	}
+	spec := a.globalConfig.Spec.ComponentsSpec.InitRetry
+
+	config := retry.DefaultConfig()
+	config.Policy = retry.PolicyExponential
+	config.InitialInterval = defaultComponentInitRetryInitialInterval
+	config.MaxInterval = defaultComponentInitRetryMaxInterval
+	// The retries only stop after the maximum number of retries.
+	config.MaxElapsedTime = 0
+	if d, err := time.ParseDuration(spec.InitialInterval); err == nil && d > 0 {
+		config.InitialInterval = d
+	}
+	if d, err := time.ParseDuration(spec.MaxInterval); err == nil && d > 0 {
+		config.MaxInterval = d
+	}
+	if spec.MaxRetries > 0 {
+		config.MaxRetries = int64(spec.MaxRetries)
+	}
+	return config.NewBackOffWithContext(ctx)
+}
+
+// isRequiredComponent returns true if the process must exit when the component fails to initialize.
+func (a *DaprRuntime) isRequiredComponent(comp componentsV1alpha1.Component) bool {
+	return !comp.Spec.IgnoreErrors && !a.isOptionalComponent(comp)
+}
+
+// retryComponentInit retries the initialization of the component in the background with an exponential backoff,
+// until it succeeds or the maximum number of retries is reached. The component is reported as retrying meanwhile.
+// The process exits if the retries of a required component are exhausted.
+// It returns false if the retries are disabled.
+func (a *DaprRuntime) retryComponentInit(comp componentsV1alpha1.Component, err error) bool {
+	ctx, cancel := context.WithCancel(a.ctx)
+	b := a.componentInitBackoff(ctx)
+	if b == nil {
+		cancel()
+		return false
+	}
+
+	r := &componentInitRetry{cancel: cancel}
+	a.componentInitRetriesLock.Lock()
+	if a.componentInitRetries == nil {
+		a.componentInitRetries = map[string]*componentInitRetry{}
+	}
+	if prev, ok := a.componentInitRetries[comp.Name]; ok {
+		// The component was updated: only its latest version is retried.
+		prev.cancel()
+	}
+	a.componentInitRetries[comp.Name] = r
+	a.componentInitRetriesLock.Unlock()
+
+	go func() {
+		defer func() {
+			cancel()
+			a.componentInitRetriesLock.Lock()
+			if a.componentInitRetries[comp.Name] == r {
+				delete(a.componentInitRetries, comp.Name)
+			}
+			a.componentInitRetriesLock.Unlock()
+		}()
+
+		for attempt := 1; ; attempt++ {
+			delay := b.NextBackOff()
+			if delay == backoff.Stop {
+				// The component keeps the status of its last failed initialization.
+				if ctx.Err() != nil {
+					return
+				}
+				e := fmt.Sprintf("giving up the initialization of component %s after %d retries: %s", comp.Name, attempt-1, err)
+				if a.isRequiredComponent(comp) {
+					a.exitOnComponentError(e)
+				}
+				log.Errorf(e)
+				return
+			}
+			a.componentStatuses.SetRetrying(comp, err)
+			log.Infof("retrying the initialization of component %s in %s", comp.Name, delay)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			a.componentInitLock.Lock()
+			if ctx.Err() != nil {
+				a.componentInitLock.Unlock()
+				return
+			}
+			if status, ok := a.componentStatuses.Get(comp.Name); ok && status.Status == meta.ComponentStatusInitialized {
+				// The component was initialized by an update in the meantime.
+				a.componentInitLock.Unlock()
+				return
+			}
+			err = a.processComponentAndDependents(comp)
+			a.componentInitLock.Unlock()
+			if err == nil {
+				log.Infof("component %s initialized after %d retries", comp.Name, attempt)
+				a.startComponentInputs(comp)
+				return
+			}
+			log.Warnf("retry %d of the initialization of component %s failed: %s", attempt, comp.Name, err)
+		}
+	}()
+	return true
+}
+
+// startComponentInputs subscribes to the topics of a pubsub or reads from an input binding that was initialized after
+// the topic subscriptions and the input bindings started. Otherwise they're started with the others.
+func (a *DaprRuntime) startComponentInputs(comp componentsV1alpha1.Component) {
+	switch a.extractComponentCategory(comp) {
+	case pubsubComponent:
+		a.subsReloadLock.Lock()
+		defer a.subsReloadLock.Unlock()
+		// The component waiting for a dependency isn't initialized yet
+		if _, ok := a.pubSubs[comp.Name]; !ok || a.pubsubCtx == nil {
+			return
+		}
+		if err := a.beginPubSub(comp.Name); err != nil {
+			log.Errorf("error occurred while beginning pubsub %s: %s", comp.Name, err)
+		}
+	case bindingsComponent:
+		binding, ok := a.inputBindings[comp.Name]
+		if !ok || !a.isAppSubscribedToBinding(comp.Name) {
+			return
+		}
+		a.inputBindingsLock.Lock()
+		defer a.inputBindingsLock.Unlock()
+		if a.inputBindingsCtx == nil {
+			return
+		}
+		if _, started := a.inputBindingCancels[comp.Name]; started {
+			return
+		}
+		if _, paused := a.pausedInputBindings[comp.Name]; paused {
+			a.inputBindingCancels[comp.Name] = nil
+			return
+		}
+		if err := a.startReadingFromBinding(comp.Name, binding); err != nil {
+			log.Errorf("error reading from input binding %s: %s", comp.Name, err)
+		}
+	}
+}
+
+// cancelComponentInitRetry stops retrying the initialization of the component, and waits for an ongoing retry.
+func (a *DaprRuntime) cancelComponentInitRetry(name string) {
+	a.componentInitRetriesLock.Lock()
+	r, ok := a.componentInitRetries[name]
+	delete(a.componentInitRetries, name)
+	a.componentInitRetriesLock.Unlock()
+	if !ok {
+		return
+	}
+
+	r.cancel()
+	// An ongoing retry holds the lock; the next ones see that the context is canceled.
+	a.componentInitLock.Lock()
+	//nolint:staticcheck
+	a.componentInitLock.Unlock()
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/components-contrib/secretstores"
+	"github.com/dapr/components-contrib/state"
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/modes"
+	"github.com/dapr/dapr/pkg/runtime/meta"
+	daprt "github.com/dapr/dapr/pkg/testing"
+	"github.com/dapr/kit/logger"
+)
+
+func TestComponentInitTimeout(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+
+	comp := componentsV1alpha1.Component{}
+	assert.Equal(t, defaultComponentInitTimeout, rt.componentInitTimeout(comp))
+
+	rt.globalConfig.Spec.ComponentsSpec.InitTimeout = "30s"
+	assert.Equal(t, 30*time.Second, rt.componentInitTimeout(comp))
+
+	comp.Spec.InitTimeout = "1m"
+	assert.Equal(t, time.Minute, rt.componentInitTimeout(comp))
+
+	rt.globalConfig.Spec.ComponentsSpec.InitTimeout = "invalid"
+	comp.Spec.InitTimeout = ""
+	assert.Equal(t, defaultComponentInitTimeout, rt.componentInitTimeout(comp))
+}
+
+func TestInitComponentsInParallel(t *testing.T) {
+	const initDuration = 300 * time.Millisecond
+
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	go rt.processComponents()
+
+	var (
+		lock           sync.Mutex
+		secretStoreEnd time.Time
+		starts         []time.Time
+	)
+	onInit := func() {
+		lock.Lock()
+		starts = append(starts, time.Now())
+		lock.Unlock()
+		time.Sleep(initDuration)
+	}
+
+	rt.secretStoresRegistry.RegisterComponent(func(_ logger.Logger) secretstores.SecretStore {
+		return NewMockKubernetesStoreWithInitCallback(func() {
+			time.Sleep(100 * time.Millisecond)
+			lock.Lock()
+			secretStoreEnd = time.Now()
+			lock.Unlock()
+		})
+	}, "kubernetesMock")
+	mockPubSub := new(daprt.MockPubSub)
+	mockPubSub.On("Init", mock.Anything).Run(func(mock.Arguments) { onInit() }).Return(nil)
+	rt.pubSubRegistry.RegisterComponent(func(_ logger.Logger) pubsub.PubSub { return mockPubSub }, "mockPubSub")
+	mockStateStore := new(daprt.MockStateStore)
+	mockStateStore.On("Init", mock.Anything).Run(func(mock.Arguments) { onInit() }).Return(nil)
+	rt.stateStoreRegistry.RegisterComponent(func(_ logger.Logger) state.Store { return mockStateStore }, "mockState")
+
+	newComponent := func(name, componentType string) componentsV1alpha1.Component {
+		return componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Spec:       componentsV1alpha1.ComponentSpec{Type: componentType, Version: "v1"},
+		}
+	}
+
+	start := time.Now()
+	rt.initComponentsInParallel([]componentsV1alpha1.Component{
+		newComponent("mystate", "state.mockState"),
+		newComponent("mypubsub", "pubsub.mockPubSub"),
+		newComponent("mysecrets", "secretstores.kubernetesMock"),
+	})
+	elapsed := time.Since(start)
+
+	assert.Contains(t, rt.stateStores, "mystate")
+	assert.Contains(t, rt.pubSubs, "mypubsub")
+	assert.Contains(t, rt.secretStores, "mysecrets")
+	require.Len(t, starts, 2)
+	for _, s := range starts {
+		assert.True(t, s.After(secretStoreEnd), "the secret stores must be initialized first")
+	}
+	assert.Less(t, elapsed, 2*initDuration, "the components of different categories must be initialized concurrently")
+}
+
+func TestOptionalComponents(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.optionalComponents = map[string]struct{}{"mypubsub": {}}
+	go rt.processComponents()
+
+	mockPubSub := new(daprt.MockPubSub)
+	mockPubSub.On("Init", mock.Anything).Return(assert.AnError)
+	rt.pubSubRegistry.RegisterComponent(func(_ logger.Logger) pubsub.PubSub { return mockPubSub }, "mockPubSub")
+
+	comp := componentsV1alpha1.Component{
+		ObjectMeta: metaV1.ObjectMeta{Name: "mypubsub"},
+		Spec:       componentsV1alpha1.ComponentSpec{Type: "pubsub.mockPubSub", Version: "v1"},
+	}
+	assert.True(t, rt.isOptionalComponent(comp))
+
+	// The optional components are processed once the sidecar is ready, and their failures don't stop the sidecar.
+	rt.deferredComponents = []componentsV1alpha1.Component{comp}
+	rt.processDeferredComponents()
+	assert.Empty(t, rt.deferredComponents)
+
+	assert.Eventually(t, func() bool {
+		statuses := rt.componentStatuses.List()
+		return len(statuses) == 1 && statuses[0].Status == meta.ComponentStatusDegraded
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, assert.AnError.Error(), rt.componentStatuses.List()[0].LastError)
+	assert.NotContains(t, rt.pubSubs, "mypubsub")
+}
+
+func TestDeferredComponentsSubscriptions(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.optionalComponents = map[string]struct{}{"mypubsub": {}}
+
+	mockPubSub := new(daprt.MockPubSub)
+	mockPubSub.On("Init", mock.Anything).Return(nil)
+	mockPubSub.On("Subscribe", mock.Anything, mock.Anything).Return(nil)
+	rt.pubSubRegistry.RegisterComponent(func(_ logger.Logger) pubsub.PubSub { return mockPubSub }, "mockPubSub")
+
+	// The subscriptions of the other components started before the optional components are initialized
+	rt.topicRoutes = map[string]TopicRoutes{"mypubsub": {"topic1": TopicRouteElem{}}}
+	rt.startSubscriptions()
+
+	rt.deferredComponents = []componentsV1alpha1.Component{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "mypubsub"},
+		Spec:       componentsV1alpha1.ComponentSpec{Type: "pubsub.mockPubSub", Version: "v1"},
+	}}
+	rt.processDeferredComponents()
+
+	assert.Eventually(t, func() bool {
+		rt.topicsLock.Lock()
+		defer rt.topicsLock.Unlock()
+		_, ok := rt.topicCtxCancels[pubsubTopicKey("mypubsub", "topic1")]
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	mockPubSub.AssertCalled(t, "Subscribe", pubsub.SubscribeRequest{Topic: "topic1"}, mock.Anything)
+}
+
+func TestRetryComponentInit(t *testing.T) {
+	newPubSubComponent := func(name string) componentsV1alpha1.Component {
+		return componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Spec:       componentsV1alpha1.ComponentSpec{Type: "pubsub.mockPubSub", Version: "v1", IgnoreErrors: true},
+		}
+	}
+	newRuntime := func(mockPubSub *daprt.MockPubSub, maxRetries int) *DaprRuntime {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		rt.globalConfig.Spec.ComponentsSpec.InitRetry = config.ComponentInitRetrySpec{
+			Enabled:         true,
+			InitialInterval: "10ms",
+			MaxInterval:     "20ms",
+			MaxRetries:      maxRetries,
+		}
+		rt.pubSubRegistry.RegisterComponent(func(_ logger.Logger) pubsub.PubSub { return mockPubSub }, "mockPubSub")
+		return rt
+	}
+
+	t.Run("initialized after retries", func(t *testing.T) {
+		mockPubSub := new(daprt.MockPubSub)
+		mockPubSub.On("Init", mock.Anything).Return(assert.AnError).Twice()
+		mockPubSub.On("Init", mock.Anything).Return(nil)
+		rt := newRuntime(mockPubSub, 0)
+		defer stopRuntime(t, rt)
+
+		comp := newPubSubComponent("mypubsub")
+		err := rt.processComponentAndDependents(comp)
+		require.Error(t, err)
+		rt.onComponentError(comp, err)
+
+		assert.Eventually(t, func() bool {
+			status, ok := rt.componentStatuses.Get("mypubsub")
+			return ok && status.Status == meta.ComponentStatusInitialized
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Contains(t, rt.pubSubs, "mypubsub")
+		mockPubSub.AssertNumberOfCalls(t, "Init", 3)
+	})
+
+	t.Run("required component is retried", func(t *testing.T) {
+		mockPubSub := new(daprt.MockPubSub)
+		mockPubSub.On("Init", mock.Anything).Return(assert.AnError).Once()
+		mockPubSub.On("Init", mock.Anything).Return(nil)
+		rt := newRuntime(mockPubSub, 0)
+		defer stopRuntime(t, rt)
+
+		comp := newPubSubComponent("mypubsub")
+		comp.Spec.IgnoreErrors = false
+		require.True(t, rt.isRequiredComponent(comp))
+		err := rt.processComponentAndDependents(comp)
+		require.Error(t, err)
+		// The process would exit if the component weren't retried
+		rt.onComponentError(comp, err)
+
+		assert.Eventually(t, func() bool {
+			status, ok := rt.componentStatuses.Get("mypubsub")
+			return ok && status.Status == meta.ComponentStatusInitialized
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("subscriptions started after the retries", func(t *testing.T) {
+		mockPubSub := new(daprt.MockPubSub)
+		mockPubSub.On("Init", mock.Anything).Return(assert.AnError).Once()
+		mockPubSub.On("Init", mock.Anything).Return(nil)
+		mockPubSub.On("Subscribe", mock.Anything, mock.Anything).Return(nil)
+		rt := newRuntime(mockPubSub, 0)
+		defer stopRuntime(t, rt)
+
+		// The subscriptions of the other components already started
+		rt.topicRoutes = map[string]TopicRoutes{"mypubsub": {"topic1": TopicRouteElem{}}}
+		rt.startSubscriptions()
+
+		comp := newPubSubComponent("mypubsub")
+		err := rt.processComponentAndDependents(comp)
+		require.Error(t, err)
+		rt.onComponentError(comp, err)
+
+		assert.Eventually(t, func() bool {
+			rt.topicsLock.Lock()
+			defer rt.topicsLock.Unlock()
+			_, ok := rt.topicCtxCancels[pubsubTopicKey("mypubsub", "topic1")]
+			return ok
+		}, 5*time.Second, 10*time.Millisecond)
+		mockPubSub.AssertCalled(t, "Subscribe", pubsub.SubscribeRequest{Topic: "topic1"}, mock.Anything)
+	})
+
+	t.Run("maximum number of retries", func(t *testing.T) {
+		mockPubSub := new(daprt.MockPubSub)
+		mockPubSub.On("Init", mock.Anything).Return(assert.AnError)
+		rt := newRuntime(mockPubSub, 2)
+		defer stopRuntime(t, rt)
+
+		comp := newPubSubComponent("mypubsub")
+		err := rt.processComponentAndDependents(comp)
+		require.Error(t, err)
+		rt.onComponentError(comp, err)
+
+		assert.Eventually(t, func() bool {
+			rt.componentInitRetriesLock.Lock()
+			defer rt.componentInitRetriesLock.Unlock()
+			return len(rt.componentInitRetries) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		status, _ := rt.componentStatuses.Get("mypubsub")
+		assert.Equal(t, meta.ComponentStatusFailed, status.Status)
+		assert.NotContains(t, rt.pubSubs, "mypubsub")
+		mockPubSub.AssertNumberOfCalls(t, "Init", 3)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		mockPubSub := new(daprt.MockPubSub)
+		mockPubSub.On("Init", mock.Anything).Return(assert.AnError)
+		rt := newRuntime(mockPubSub, 0)
+		rt.globalConfig.Spec.ComponentsSpec.InitRetry.InitialInterval = "1h"
+		defer stopRuntime(t, rt)
+
+		comp := newPubSubComponent("mypubsub")
+		rt.retryComponentInit(comp, assert.AnError)
+		assert.Eventually(t, func() bool {
+			status, ok := rt.componentStatuses.Get("mypubsub")
+			return ok && status.Status == meta.ComponentStatusRetrying
+		}, 5*time.Second, 10*time.Millisecond)
+
+		rt.cancelComponentInitRetry("mypubsub")
+		assert.Empty(t, rt.componentInitRetries)
+		mockPubSub.AssertNotCalled(t, "Init", mock.Anything)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		assert.False(t, rt.retryComponentInit(newPubSubComponent("mypubsub"), assert.AnError))
+		assert.Empty(t, rt.componentInitRetries)
+	})
+}
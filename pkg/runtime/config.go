@@ -16,10 +16,14 @@ package runtime
 import (
 	"time"
 
+	"github.com/dapr/dapr/pkg/actors"
 	"github.com/dapr/dapr/pkg/apphealth"
+	httpChannel "github.com/dapr/dapr/pkg/channel/http"
 	config "github.com/dapr/dapr/pkg/config/modes"
 	"github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/grpc"
 	"github.com/dapr/dapr/pkg/modes"
+	"github.com/dapr/dapr/utils"
 )
 
 // Protocol is a communications protocol.
@@ -30,6 +34,9 @@ const (
 	GRPCProtocol Protocol = "grpc"
 	// HTTPProtocol is a HTTP communication protocol.
 	HTTPProtocol Protocol = "http"
+	// H2CProtocol is a HTTP/2 cleartext communication protocol.
+	// The app is invoked with HTTP/2 without TLS, and is otherwise handled as a HTTP app.
+	H2CProtocol Protocol = "h2c"
 	// DefaultDaprHTTPPort is the default http port for Dapr.
 	DefaultDaprHTTPPort = 3500
 	// DefaultDaprPublicPort is the default http port for Dapr.
@@ -50,6 +57,12 @@ const (
 	DefaultGracefulShutdownDuration = time.Second * 5
 	// DefaultAppHealthCheckPath is the default path for HTTP health checks.
 	DefaultAppHealthCheckPath = "/health"
+	// DefaultAppStartupProbeInterval is the default interval between the startup probes of the app.
+	DefaultAppStartupProbeInterval = time.Second
+	// DefaultInternalGRPCKeepaliveTimeout is the default time to wait for the acknowledgement of the keepalive pings of the internal gRPC connections.
+	DefaultInternalGRPCKeepaliveTimeout = 20 * time.Second
+	// DefaultInternalGRPCConnectionsPerTarget is the default number of internal gRPC connections to each sidecar.
+	DefaultInternalGRPCConnectionsPerTarget = 1
 )
 
 // Config holds the Dapr Runtime configuration.
@@ -57,6 +70,7 @@ type Config struct {
 	ID                           string
 	HTTPPort                     int
 	PublicPort                   *int
+	PublicGRPCPort               *int
 	ProfilePort                  int
 	EnableProfiling              bool
 	APIGRPCPort                  int
@@ -66,6 +80,7 @@ type Config struct {
 	ApplicationProtocol          Protocol
 	Mode                         modes.DaprMode
 	PlacementAddresses           []string
+	PlacementTLS                 *actors.PlacementTLSConfig
 	GlobalConfig                 string
 	AllowedOrigins               string
 	Standalone                   config.StandaloneConfig
@@ -75,24 +90,43 @@ type Config struct {
 	SentryServiceAddress         string
 	CertChain                    *credentials.CertChain
 	AppSSL                       bool
+	AppHTTP2                     httpChannel.HTTP2Mode
 	MaxRequestBodySize           int
 	UnixDomainSocket             string
+	AppUnixDomainSocket          string
 	ReadBufferSize               int
 	GracefulShutdownDuration     time.Duration
 	EnableAPILogging             bool
 	DisableBuiltinK8sSecretStore bool
 	AppHealthCheck               *apphealth.Config
 	AppHealthCheckHTTPPath       string
+	AppStartupProbe              *AppStartupProbeConfig
+	TraceAttributes              map[string]string
+	PublicAllowlist              utils.IPAllowlist
+	EnableDevConsole             bool
+	InternalGRPCConnection       grpc.ConnectionConfig
+}
+
+// AppStartupProbeConfig is the configuration of the probe of the app readiness,
+// which delays the delivery of pubsub messages and input bindings events until the app is ready.
+type AppStartupProbeConfig struct {
+	// Method invoked on the app: an HTTP path for HTTP apps, or the name of the method invoked with OnInvoke for gRPC apps.
+	Method string
+	// Interval between the probes. It is also the timeout of each probe.
+	Interval time.Duration
+	// Maximum time to wait for the app to be ready, after which events are delivered anyway. No limit if 0.
+	Timeout time.Duration
 }
 
 // NewRuntimeConfigOpts contains options for NewRuntimeConfig.
 type NewRuntimeConfigOpts struct {
 	ID                           string
 	PlacementAddresses           []string
+	PlacementTLS                 *actors.PlacementTLSConfig
 	controlPlaneAddress          string
 	AllowedOrigins               string
 	GlobalConfig                 string
-	ComponentsPath               string
+	ResourcesPath                []string
 	AppProtocol                  string
 	Mode                         string
 	HTTPPort                     int
@@ -100,6 +134,7 @@ type NewRuntimeConfigOpts struct {
 	APIGRPCPort                  int
 	APIListenAddresses           []string
 	PublicPort                   *int
+	PublicGRPCPort               *int
 	AppPort                      int
 	ProfilePort                  int
 	EnableProfiling              bool
@@ -107,8 +142,10 @@ type NewRuntimeConfigOpts struct {
 	MTLSEnabled                  bool
 	SentryAddress                string
 	AppSSL                       bool
+	AppHTTP2Detection            bool
 	MaxRequestBodySize           int
 	UnixDomainSocket             string
+	AppUnixDomainSocket          string
 	ReadBufferSize               int
 	GracefulShutdownDuration     time.Duration
 	EnableAPILogging             bool
@@ -118,6 +155,18 @@ type NewRuntimeConfigOpts struct {
 	AppHealthProbeInterval       time.Duration
 	AppHealthProbeTimeout        time.Duration
 	AppHealthThreshold           int32
+	AppStartupProbeMethod        string
+	AppStartupProbeInterval      time.Duration
+	AppStartupProbeTimeout       time.Duration
+	TraceAttributes              map[string]string
+	PublicAllowlist              utils.IPAllowlist
+	EnableDevConsole             bool
+	InternalGRPCMaxConnectionAge time.Duration
+	InternalGRPCKeepaliveTime    time.Duration
+	InternalGRPCKeepaliveTimeout time.Duration
+	InternalGRPCConnsPerTarget   int
+	InternalGRPCMaxStreams       uint32
+	InternalGRPCIdleTimeout      time.Duration
 }
 
 // NewRuntimeConfig returns a new runtime config.
@@ -132,22 +181,46 @@ func NewRuntimeConfig(opts NewRuntimeConfigOpts) *Config {
 		}
 	}
 
+	var appStartupProbe *AppStartupProbeConfig
+	if opts.AppStartupProbeMethod != "" {
+		appStartupProbe = &AppStartupProbeConfig{
+			Method:   opts.AppStartupProbeMethod,
+			Interval: opts.AppStartupProbeInterval,
+			Timeout:  opts.AppStartupProbeTimeout,
+		}
+		if appStartupProbe.Interval <= 0 {
+			appStartupProbe.Interval = DefaultAppStartupProbeInterval
+		}
+	}
+
+	appProtocol := Protocol(opts.AppProtocol)
+	var appHTTP2 httpChannel.HTTP2Mode
+	switch {
+	case appProtocol == H2CProtocol:
+		appProtocol = HTTPProtocol
+		appHTTP2 = httpChannel.HTTP2Enabled
+	case appProtocol == HTTPProtocol && opts.AppHTTP2Detection:
+		appHTTP2 = httpChannel.HTTP2Auto
+	}
+
 	return &Config{
 		ID:                  opts.ID,
 		HTTPPort:            opts.HTTPPort,
 		PublicPort:          opts.PublicPort,
+		PublicGRPCPort:      opts.PublicGRPCPort,
 		InternalGRPCPort:    opts.InternalGRPCPort,
 		APIGRPCPort:         opts.APIGRPCPort,
 		ApplicationPort:     opts.AppPort,
 		ProfilePort:         opts.ProfilePort,
 		APIListenAddresses:  opts.APIListenAddresses,
-		ApplicationProtocol: Protocol(opts.AppProtocol),
+		ApplicationProtocol: appProtocol,
 		Mode:                modes.DaprMode(opts.Mode),
 		PlacementAddresses:  opts.PlacementAddresses,
+		PlacementTLS:        opts.PlacementTLS,
 		GlobalConfig:        opts.GlobalConfig,
 		AllowedOrigins:      opts.AllowedOrigins,
 		Standalone: config.StandaloneConfig{
-			ComponentsPath: opts.ComponentsPath,
+			ResourcesPath: opts.ResourcesPath,
 		},
 		Kubernetes: config.KubernetesConfig{
 			ControlPlaneAddress: opts.controlPlaneAddress,
@@ -157,13 +230,27 @@ func NewRuntimeConfig(opts NewRuntimeConfigOpts) *Config {
 		mtlsEnabled:                  opts.MTLSEnabled,
 		SentryServiceAddress:         opts.SentryAddress,
 		AppSSL:                       opts.AppSSL,
+		AppHTTP2:                     appHTTP2,
 		MaxRequestBodySize:           opts.MaxRequestBodySize,
 		UnixDomainSocket:             opts.UnixDomainSocket,
+		AppUnixDomainSocket:          opts.AppUnixDomainSocket,
 		ReadBufferSize:               opts.ReadBufferSize,
 		GracefulShutdownDuration:     opts.GracefulShutdownDuration,
 		EnableAPILogging:             opts.EnableAPILogging,
 		DisableBuiltinK8sSecretStore: opts.DisableBuiltinK8sSecretStore,
 		AppHealthCheck:               appHealthCheck,
 		AppHealthCheckHTTPPath:       opts.AppHealthCheckPath,
+		AppStartupProbe:              appStartupProbe,
+		TraceAttributes:              opts.TraceAttributes,
+		PublicAllowlist:              opts.PublicAllowlist,
+		EnableDevConsole:             opts.EnableDevConsole,
+		InternalGRPCConnection: grpc.ConnectionConfig{
+			MaxConnectionAge:     opts.InternalGRPCMaxConnectionAge,
+			KeepaliveTime:        opts.InternalGRPCKeepaliveTime,
+			KeepaliveTimeout:     opts.InternalGRPCKeepaliveTimeout,
+			ConnectionsPerTarget: opts.InternalGRPCConnsPerTarget,
+			MaxConcurrentStreams: opts.InternalGRPCMaxStreams,
+			IdleTimeout:          opts.InternalGRPCIdleTimeout,
+		},
 	}
 }
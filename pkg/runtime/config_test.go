@@ -18,6 +18,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	httpChannel "github.com/dapr/dapr/pkg/channel/http"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -28,7 +30,7 @@ func TestNewConfig(t *testing.T) {
 		controlPlaneAddress:          "localhost:5051",
 		AllowedOrigins:               "*",
 		GlobalConfig:                 "config",
-		ComponentsPath:               "components",
+		ResourcesPath:                []string{"components"},
 		AppProtocol:                  "http",
 		Mode:                         "kubernetes",
 		HTTPPort:                     3500,
@@ -49,6 +51,8 @@ func TestNewConfig(t *testing.T) {
 		GracefulShutdownDuration:     time.Second,
 		EnableAPILogging:             true,
 		DisableBuiltinK8sSecretStore: true,
+		InternalGRPCKeepaliveTime:    time.Minute,
+		InternalGRPCConnsPerTarget:   2,
 	})
 
 	assert.Equal(t, "app1", c.ID)
@@ -56,7 +60,7 @@ func TestNewConfig(t *testing.T) {
 	assert.Equal(t, "localhost:5051", c.Kubernetes.ControlPlaneAddress)
 	assert.Equal(t, "*", c.AllowedOrigins)
 	assert.Equal(t, "config", c.GlobalConfig)
-	assert.Equal(t, "components", c.Standalone.ComponentsPath)
+	assert.Equal(t, []string{"components"}, c.Standalone.ResourcesPath)
 	assert.Equal(t, "http", string(c.ApplicationProtocol))
 	assert.Equal(t, "kubernetes", string(c.Mode))
 	assert.Equal(t, 3500, c.HTTPPort)
@@ -77,4 +81,20 @@ func TestNewConfig(t *testing.T) {
 	assert.Equal(t, time.Second, c.GracefulShutdownDuration)
 	assert.Equal(t, true, c.EnableAPILogging)
 	assert.Equal(t, true, c.DisableBuiltinK8sSecretStore)
+	assert.Equal(t, time.Minute, c.InternalGRPCConnection.KeepaliveTime)
+	assert.Equal(t, 2, c.InternalGRPCConnection.ConnectionsPerTarget)
+}
+
+func TestNewConfigAppHTTP2(t *testing.T) {
+	c := NewRuntimeConfig(NewRuntimeConfigOpts{AppProtocol: "h2c"})
+	assert.Equal(t, HTTPProtocol, c.ApplicationProtocol)
+	assert.Equal(t, httpChannel.HTTP2Enabled, c.AppHTTP2)
+
+	c = NewRuntimeConfig(NewRuntimeConfigOpts{AppProtocol: "http", AppHTTP2Detection: true})
+	assert.Equal(t, HTTPProtocol, c.ApplicationProtocol)
+	assert.Equal(t, httpChannel.HTTP2Auto, c.AppHTTP2)
+
+	c = NewRuntimeConfig(NewRuntimeConfigOpts{AppProtocol: "grpc", AppHTTP2Detection: true})
+	assert.Equal(t, GRPCProtocol, c.ApplicationProtocol)
+	assert.Equal(t, httpChannel.HTTP2Disabled, c.AppHTTP2)
 }
@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dapr/components-contrib/configuration"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// ResyncIntervalMetadataKey is the request metadata key for the interval of the resync of a subscription with the store.
+	// It is removed from the metadata sent to the configuration store.
+	ResyncIntervalMetadataKey = "resyncInterval"
+
+	minResyncInterval = time.Second
+)
+
+var log = logger.NewLogger("dapr.runtime.configuration")
+
+// SubscribeOptions contains the options for Subscribe.
+type SubscribeOptions struct {
+	Store     configuration.Store
+	StoreName string
+	Keys      []string
+	Metadata  map[string]string
+	// Interval for re-reading the subscribed keys from the store, to deliver the updates that were missed. Disabled if 0.
+	ResyncInterval time.Duration
+	// Handler invoked with the updated keys.
+	Handler configuration.UpdateHandler
+}
+
+// Subscription is a subscription to a configuration store that keeps the last value of each key in memory.
+// The updates pushed by the store are delivered as they are; the resync only delivers the keys whose value or version changed.
+type Subscription struct {
+	id      string
+	opts    SubscribeOptions
+	items   map[string]*configuration.Item
+	lock    sync.Mutex
+	stopCh  chan struct{}
+	stopped bool
+}
+
+// SubscribeRequestOptions returns the resync interval from the request metadata, and the metadata to pass to the store.
+func SubscribeRequestOptions(md map[string]string) (time.Duration, map[string]string, error) {
+	v, ok := md[ResyncIntervalMetadataKey]
+	if !ok {
+		return 0, md, nil
+	}
+
+	storeMd := make(map[string]string, len(md))
+	for k, val := range md {
+		if k != ResyncIntervalMetadataKey {
+			storeMd[k] = val
+		}
+	}
+
+	interval, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, storeMd, err
+	}
+	if interval > 0 && interval < minResyncInterval {
+		interval = minResyncInterval
+	}
+	return interval, storeMd, nil
+}
+
+// Subscribe subscribes to the configuration store.
+func Subscribe(ctx context.Context, opts SubscribeOptions) (*Subscription, error) {
+	s := &Subscription{
+		opts:   opts,
+		items:  map[string]*configuration.Item{},
+		stopCh: make(chan struct{}),
+	}
+
+	id, err := opts.Store.Subscribe(ctx, &configuration.SubscribeRequest{
+		Keys:     opts.Keys,
+		Metadata: opts.Metadata,
+	}, s.onUpdate)
+	if err != nil {
+		return nil, err
+	}
+	s.lock.Lock()
+	s.id = id
+	s.lock.Unlock()
+
+	if opts.ResyncInterval > 0 {
+		go s.resyncLoop()
+	}
+
+	return s, nil
+}
+
+// ID returns the ID of the subscription in the configuration store.
+func (s *Subscription) ID() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.id
+}
+
+// Items returns a copy of the last known value of the subscribed keys.
+func (s *Subscription) Items() map[string]*configuration.Item {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	items := make(map[string]*configuration.Item, len(s.items))
+	for k, v := range s.items {
+		items[k] = v
+	}
+	return items
+}
+
+// Load reads the subscribed keys from the store, and returns the last known value of the subscribed keys.
+// The values are not delivered to the handler; the keys updated by the store in the meantime keep their updated value.
+func (s *Subscription) Load(ctx context.Context) (map[string]*configuration.Item, error) {
+	resp, err := s.opts.Store.Get(ctx, &configuration.GetRequest{
+		Keys:     s.opts.Keys,
+		Metadata: s.opts.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp != nil {
+		s.lock.Lock()
+		for k, v := range resp.Items {
+			if _, ok := s.items[k]; !ok && v != nil {
+				s.items[k] = v
+			}
+		}
+		s.lock.Unlock()
+	}
+	return s.Items(), nil
+}
+
+// Stop stops the resync and the delivery of updates, without unsubscribing from the store.
+// It returns false if the subscription was already stopped.
+func (s *Subscription) Stop() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped {
+		return false
+	}
+	s.stopped = true
+	close(s.stopCh)
+	return true
+}
+
+// Close stops the subscription and unsubscribes from the store.
+func (s *Subscription) Close(ctx context.Context) error {
+	if !s.Stop() {
+		return nil
+	}
+
+	return s.opts.Store.Unsubscribe(ctx, &configuration.UnsubscribeRequest{
+		ID: s.ID(),
+	})
+}
+
+func (s *Subscription) onUpdate(ctx context.Context, e *configuration.UpdateEvent) error {
+	return s.deliver(ctx, e, false)
+}
+
+func (s *Subscription) deliver(ctx context.Context, e *configuration.UpdateEvent, onlyChanged bool) error {
+	changed := s.updateItems(e.Items, onlyChanged)
+	if len(changed) == 0 {
+		return nil
+	}
+	return s.opts.Handler(ctx, &configuration.UpdateEvent{
+		ID:    e.ID,
+		Items: changed,
+	})
+}
+
+// updateItems stores the items and returns the ones to deliver.
+func (s *Subscription) updateItems(items map[string]*configuration.Item, onlyChanged bool) map[string]*configuration.Item {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped {
+		return nil
+	}
+
+	changed := make(map[string]*configuration.Item, len(items))
+	for k, v := range items {
+		if v == nil {
+			continue
+		}
+		old, ok := s.items[k]
+		if onlyChanged && ok && old.Value == v.Value && old.Version == v.Version {
+			continue
+		}
+		s.items[k] = v
+		changed[k] = v
+	}
+	return changed
+}
+
+func (s *Subscription) resyncLoop() {
+	t := time.NewTicker(s.opts.ResyncInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-t.C:
+			s.resync()
+		}
+	}
+}
+
+func (s *Subscription) resync() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.ResyncInterval)
+	defer cancel()
+
+	resp, err := s.opts.Store.Get(ctx, &configuration.GetRequest{
+		Keys:     s.opts.Keys,
+		Metadata: s.opts.Metadata,
+	})
+	if err != nil {
+		log.Warnf("error resyncing configuration subscription %s on store %s: %s", s.ID(), s.opts.StoreName, err)
+		return
+	}
+	if resp == nil {
+		return
+	}
+
+	if err = s.deliver(ctx, &configuration.UpdateEvent{
+		ID:    s.ID(),
+		Items: resp.Items,
+	}, true); err != nil {
+		log.Warnf("error delivering resynced configuration for subscription %s on store %s: %s", s.ID(), s.opts.StoreName, err)
+	}
+}
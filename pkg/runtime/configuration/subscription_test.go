@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/configuration"
+	daprt "github.com/dapr/dapr/pkg/testing"
+)
+
+type eventRecorder struct {
+	lock   sync.Mutex
+	events []map[string]*configuration.Item
+}
+
+func (r *eventRecorder) handler(_ context.Context, e *configuration.UpdateEvent) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, e.Items)
+	return nil
+}
+
+func (r *eventRecorder) get() []map[string]*configuration.Item {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]map[string]*configuration.Item{}, r.events...)
+}
+
+func TestSubscribeRequestOptions(t *testing.T) {
+	t.Run("no resync interval", func(t *testing.T) {
+		md := map[string]string{"foo": "bar"}
+		interval, storeMd, err := SubscribeRequestOptions(md)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), interval)
+		assert.Equal(t, md, storeMd)
+	})
+
+	t.Run("resync interval removed from metadata", func(t *testing.T) {
+		md := map[string]string{"foo": "bar", ResyncIntervalMetadataKey: "30s"}
+		interval, storeMd, err := SubscribeRequestOptions(md)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, interval)
+		assert.Equal(t, map[string]string{"foo": "bar"}, storeMd)
+		assert.Len(t, md, 2)
+	})
+
+	t.Run("resync interval below minimum", func(t *testing.T) {
+		interval, _, err := SubscribeRequestOptions(map[string]string{ResyncIntervalMetadataKey: "10ms"})
+		require.NoError(t, err)
+		assert.Equal(t, minResyncInterval, interval)
+	})
+
+	t.Run("invalid resync interval", func(t *testing.T) {
+		_, _, err := SubscribeRequestOptions(map[string]string{ResyncIntervalMetadataKey: "foo"})
+		assert.Error(t, err)
+	})
+}
+
+func TestSubscription(t *testing.T) {
+	t.Run("updates from the store are delivered and cached", func(t *testing.T) {
+		store := &daprt.MockConfigurationStore{}
+		var storeHandler configuration.UpdateHandler
+		store.On("Subscribe", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				storeHandler = args.Get(2).(configuration.UpdateHandler)
+			}).
+			Return("sub1", nil)
+		store.On("Unsubscribe", mock.Anything, mock.MatchedBy(func(req *configuration.UnsubscribeRequest) bool {
+			return req.ID == "sub1"
+		})).Return(nil)
+
+		r := &eventRecorder{}
+		sub, err := Subscribe(context.Background(), SubscribeOptions{
+			Store:   store,
+			Keys:    []string{"key1"},
+			Handler: r.handler,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "sub1", sub.ID())
+
+		item := &configuration.Item{Value: "v1", Version: "1"}
+		require.NoError(t, storeHandler(context.Background(), &configuration.UpdateEvent{
+			ID:    "sub1",
+			Items: map[string]*configuration.Item{"key1": item},
+		}))
+		assert.Equal(t, []map[string]*configuration.Item{{"key1": item}}, r.get())
+		assert.Equal(t, map[string]*configuration.Item{"key1": item}, sub.Items())
+
+		require.NoError(t, sub.Close(context.Background()))
+		store.AssertNumberOfCalls(t, "Unsubscribe", 1)
+
+		// No delivery after the subscription is closed
+		require.NoError(t, storeHandler(context.Background(), &configuration.UpdateEvent{
+			ID:    "sub1",
+			Items: map[string]*configuration.Item{"key1": {Value: "v2", Version: "2"}},
+		}))
+		assert.Len(t, r.get(), 1)
+
+		// Closing twice is a no-op
+		require.NoError(t, sub.Close(context.Background()))
+		store.AssertNumberOfCalls(t, "Unsubscribe", 1)
+	})
+
+	t.Run("resync delivers only changed keys", func(t *testing.T) {
+		store := &daprt.MockConfigurationStore{}
+		var storeHandler configuration.UpdateHandler
+		store.On("Subscribe", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				storeHandler = args.Get(2).(configuration.UpdateHandler)
+			}).
+			Return("sub1", nil)
+		store.On("Get", mock.Anything, mock.Anything).Return(&configuration.GetResponse{
+			Items: map[string]*configuration.Item{
+				"key1": {Value: "v1", Version: "1"},
+				"key2": {Value: "v2", Version: "2"},
+			},
+		}, nil)
+
+		r := &eventRecorder{}
+		sub, err := Subscribe(context.Background(), SubscribeOptions{
+			Store:   store,
+			Keys:    []string{"key1", "key2"},
+			Handler: r.handler,
+		})
+		require.NoError(t, err)
+		defer sub.Stop()
+
+		require.NoError(t, storeHandler(context.Background(), &configuration.UpdateEvent{
+			ID:    "sub1",
+			Items: map[string]*configuration.Item{"key1": {Value: "v1", Version: "1"}},
+		}))
+
+		sub.resync()
+		events := r.get()
+		require.Len(t, events, 2)
+		assert.Equal(t, map[string]*configuration.Item{"key2": {Value: "v2", Version: "2"}}, events[1])
+
+		// Nothing changed
+		sub.resync()
+		assert.Len(t, r.get(), 2)
+	})
+}
+
+func TestSubscriptionLoad(t *testing.T) {
+	store := &daprt.MockConfigurationStore{}
+	var storeHandler configuration.UpdateHandler
+	store.On("Subscribe", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			storeHandler = args.Get(2).(configuration.UpdateHandler)
+		}).
+		Return("sub1", nil)
+	store.On("Get", mock.Anything, mock.MatchedBy(func(req *configuration.GetRequest) bool {
+		return len(req.Keys) == 2 && req.Keys[0] == "key1" && req.Keys[1] == "key2"
+	})).Return(&configuration.GetResponse{
+		Items: map[string]*configuration.Item{
+			"key1": {Value: "v1", Version: "1"},
+			"key2": {Value: "v2", Version: "2"},
+		},
+	}, nil)
+
+	r := &eventRecorder{}
+	sub, err := Subscribe(context.Background(), SubscribeOptions{
+		Store:   store,
+		Keys:    []string{"key1", "key2"},
+		Handler: r.handler,
+	})
+	require.NoError(t, err)
+	defer sub.Stop()
+
+	// The store updated key2 before the values are loaded
+	updated := &configuration.Item{Value: "v3", Version: "3"}
+	require.NoError(t, storeHandler(context.Background(), &configuration.UpdateEvent{
+		ID:    "sub1",
+		Items: map[string]*configuration.Item{"key2": updated},
+	}))
+
+	items, err := sub.Load(context.Background())
+	require.NoError(t, err)
+	expected := map[string]*configuration.Item{
+		"key1": {Value: "v1", Version: "1"},
+		"key2": updated,
+	}
+	assert.Equal(t, expected, items)
+	assert.Equal(t, expected, sub.Items())
+
+	// The loaded values are not delivered to the handler
+	assert.Len(t, r.get(), 1)
+}
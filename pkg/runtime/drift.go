@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	secretstoresLoader "github.com/dapr/dapr/pkg/components/secretstores"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+	"github.com/dapr/dapr/pkg/modes"
+	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
+	"github.com/dapr/dapr/pkg/runtime/meta"
+)
+
+const (
+	driftCheckInterval = 5 * time.Minute
+	driftCheckTimeout  = 30 * time.Second
+
+	driftKindComponent     = "component"
+	driftKindConfiguration = "configuration"
+)
+
+// recordComponentVersion records the version of the component received from the operator.
+func (a *DaprRuntime) recordComponentVersion(component componentsV1alpha1.Component) {
+	a.driftLock.Lock()
+	a.componentVersions[component.Name] = component.ResourceVersion
+	a.driftLock.Unlock()
+}
+
+// forgetComponentVersion removes a deleted component from the versions received from the operator.
+func (a *DaprRuntime) forgetComponentVersion(name string) {
+	a.driftLock.Lock()
+	delete(a.componentVersions, name)
+	a.driftLock.Unlock()
+}
+
+// startDriftDetection periodically compares the versions of the components and of the configuration loaded by the sidecar
+// with the current ones in the operator, so that the sidecars that missed updates are detected.
+func (a *DaprRuntime) startDriftDetection() {
+	if a.runtimeConfig.Mode != modes.KubernetesMode || a.operatorClient == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(driftCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := a.checkDrift(a.ctx); err != nil {
+					log.Warnf("failed to check the drift of the components and the configuration: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// checkDrift compares the loaded resources with the current ones in the operator and records the drift.
+func (a *DaprRuntime) checkDrift(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, driftCheckTimeout)
+	defer cancel()
+
+	resp, err := a.operatorClient.ListComponents(ctx, &operatorv1pb.ListComponentsRequest{
+		Namespace: a.namespace,
+		PodName:   a.podName,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error listing components")
+	}
+	drift := meta.Drift{
+		LastChecked: time.Now(),
+	}
+	drift.Components, err = a.driftedComponents(resp.GetComponents())
+	if err != nil {
+		return err
+	}
+
+	if a.runtimeConfig.GlobalConfig != "" {
+		drift.Configuration, err = a.configurationDrifted(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	a.setDrift(drift)
+	return nil
+}
+
+// driftedComponents returns the names of the components whose version differs from the one in the operator,
+// including the components that the sidecar never received and the ones deleted since they were loaded.
+func (a *DaprRuntime) driftedComponents(compsRaw [][]byte) ([]string, error) {
+	a.driftLock.RLock()
+	loaded := make(map[string]string, len(a.componentVersions))
+	for name, version := range a.componentVersions {
+		loaded[name] = version
+	}
+	a.driftLock.RUnlock()
+
+	drifted := []string{}
+	for _, raw := range compsRaw {
+		var component componentsV1alpha1.Component
+		if err := json.Unmarshal(raw, &component); err != nil {
+			return nil, errors.Wrap(err, "error deserializing component")
+		}
+		if !a.isComponentAuthorized(component) {
+			continue
+		}
+
+		if version, ok := loaded[component.Name]; !ok || version != component.ResourceVersion {
+			drifted = append(drifted, component.Name)
+		}
+		delete(loaded, component.Name)
+	}
+	for name := range loaded {
+		// The built-in secret store is not managed by the operator
+		if name != secretstoresLoader.BuiltinKubernetesSecretStore {
+			drifted = append(drifted, name)
+		}
+	}
+
+	sort.Strings(drifted)
+	return drifted, nil
+}
+
+// configurationDrifted returns true if the configuration was updated since the sidecar loaded it.
+func (a *DaprRuntime) configurationDrifted(ctx context.Context) (bool, error) {
+	resp, err := a.operatorClient.GetConfiguration(ctx, &operatorv1pb.GetConfigurationRequest{
+		Name:      a.runtimeConfig.GlobalConfig,
+		Namespace: a.namespace,
+		PodName:   a.podName,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "error getting configuration")
+	}
+
+	var current metav1.PartialObjectMetadata
+	if err = json.Unmarshal(resp.GetConfiguration(), &current); err != nil {
+		return false, errors.Wrap(err, "error deserializing configuration")
+	}
+	return current.ResourceVersion != a.globalConfig.ResourceVersion, nil
+}
+
+// setDrift records the drift, updates the metrics and reports the out of date resources to the operator when they change.
+func (a *DaprRuntime) setDrift(drift meta.Drift) {
+	a.driftLock.Lock()
+	prev := a.drift
+	a.drift = &drift
+	a.driftLock.Unlock()
+
+	var prevDrift meta.Drift
+	if prev != nil {
+		prevDrift = *prev
+	}
+	diag.DefaultMonitoring.DriftedResourcesChanged(driftKindComponent, len(drift.Components)-len(prevDrift.Components))
+	diag.DefaultMonitoring.DriftedResourcesChanged(driftKindConfiguration, boolToInt(drift.Configuration)-boolToInt(prevDrift.Configuration))
+
+	if prevDrift.Configuration == drift.Configuration && stringSlicesEqual(prevDrift.Components, drift.Components) {
+		return
+	}
+	if !drift.Detected() {
+		log.Info("the components and the configuration are up to date")
+		return
+	}
+
+	log.Warnf("the sidecar is running out of date resources. components: %v, configuration: %t", drift.Components, drift.Configuration)
+	req := &operatorv1pb.ReportDriftRequest{
+		Namespace:  a.namespace,
+		PodName:    a.podName,
+		Components: drift.Components,
+	}
+	if drift.Configuration {
+		req.Configuration = a.runtimeConfig.GlobalConfig
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+		defer cancel()
+		if _, err := a.operatorClient.ReportDrift(ctx, req); err != nil {
+			log.Debugf("failed to report the drift to the operator: %s", err)
+		}
+	}()
+}
+
+// getDrift returns the last drift recorded, or nil if it wasn't checked yet.
+func (a *DaprRuntime) getDrift() *meta.Drift {
+	a.driftLock.RLock()
+	defer a.driftLock.RUnlock()
+	if a.drift == nil {
+		return nil
+	}
+	drift := *a.drift
+	return &drift
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
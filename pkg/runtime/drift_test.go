@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/modes"
+	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
+)
+
+// driftOperatorClient is a mock operator client that serves the configuration and records the reported drifts.
+type driftOperatorClient struct {
+	*mockOperatorClient
+
+	configVersion string
+	reports       chan *operatorv1pb.ReportDriftRequest
+}
+
+func (c *driftOperatorClient) GetConfiguration(ctx context.Context, in *operatorv1pb.GetConfigurationRequest, opts ...grpc.CallOption) (*operatorv1pb.GetConfigurationResponse, error) {
+	b, err := json.Marshal(metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{Name: in.Name, ResourceVersion: c.configVersion},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &operatorv1pb.GetConfigurationResponse{Configuration: b}, nil
+}
+
+func (c *driftOperatorClient) ReportDrift(ctx context.Context, in *operatorv1pb.ReportDriftRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	c.reports <- in
+	return &emptypb.Empty{}, nil
+}
+
+func TestCheckDrift(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.KubernetesMode)
+	defer stopRuntime(t, rt)
+
+	opCli := &driftOperatorClient{
+		mockOperatorClient: newMockOperatorClient(),
+		configVersion:      "10",
+		reports:            make(chan *operatorv1pb.ReportDriftRequest, 2),
+	}
+	rt.operatorClient = opCli
+	rt.runtimeConfig.GlobalConfig = "appconfig"
+	rt.globalConfig.ResourceVersion = "10"
+
+	component := func(name, version string) *componentsV1alpha1.Component {
+		comp := &componentsV1alpha1.Component{}
+		comp.Name = name
+		comp.ResourceVersion = version
+		comp.Spec.Type = "state.mockState"
+		return comp
+	}
+	rt.recordComponentVersion(*component("uptodate", "1"))
+	rt.recordComponentVersion(*component("updated", "1"))
+	rt.recordComponentVersion(*component("deleted", "1"))
+	opCli.compsByName["uptodate"] = component("uptodate", "1")
+	opCli.compsByName["updated"] = component("updated", "2")
+	opCli.compsByName["created"] = component("created", "1")
+
+	assert.Nil(t, rt.getDrift())
+
+	t.Run("the out of date components are detected", func(t *testing.T) {
+		require.NoError(t, rt.checkDrift(context.Background()))
+		drift := rt.getDrift()
+		require.NotNil(t, drift)
+		assert.True(t, drift.Detected())
+		assert.Equal(t, []string{"created", "deleted", "updated"}, drift.Components)
+		assert.False(t, drift.Configuration)
+
+		select {
+		case report := <-opCli.reports:
+			assert.Equal(t, []string{"created", "deleted", "updated"}, report.Components)
+			assert.Empty(t, report.Configuration)
+		case <-time.After(time.Second):
+			t.Fatal("the drift was not reported to the operator")
+		}
+	})
+
+	t.Run("the out of date configuration is detected", func(t *testing.T) {
+		opCli.configVersion = "11"
+		require.NoError(t, rt.checkDrift(context.Background()))
+		assert.True(t, rt.getDrift().Configuration)
+
+		select {
+		case report := <-opCli.reports:
+			assert.Equal(t, "appconfig", report.Configuration)
+		case <-time.After(time.Second):
+			t.Fatal("the drift was not reported to the operator")
+		}
+	})
+
+	t.Run("an unchanged drift is not reported again", func(t *testing.T) {
+		require.NoError(t, rt.checkDrift(context.Background()))
+		select {
+		case <-opCli.reports:
+			t.Fatal("the drift was reported again")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("the components received from the operator are up to date", func(t *testing.T) {
+		opCli.configVersion = "10"
+		rt.recordComponentVersion(*component("updated", "2"))
+		rt.recordComponentVersion(*component("created", "1"))
+		rt.forgetComponentVersion("deleted")
+
+		require.NoError(t, rt.checkDrift(context.Background()))
+		drift := rt.getDrift()
+		assert.False(t, drift.Detected())
+		assert.Empty(t, drift.Components)
+	})
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dapr/components-contrib/secretstores"
+	"golang.org/x/net/http/httpproxy"
+
+	secretstoresLoader "github.com/dapr/dapr/pkg/components/secretstores"
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/modes"
+	"github.com/dapr/dapr/utils"
+)
+
+const (
+	defaultProxyUsernameKey = "username"
+	defaultProxyPasswordKey = "password"
+)
+
+// egressProxy selects the proxy of the outbound HTTP(S) requests configured in the proxy spec.
+type egressProxy struct {
+	spec  config.ProxySpec
+	proxy func(*url.URL) (*url.URL, error)
+	// overrides are the proxies of the hosts of the components, which take precedence over the global proxies.
+	overrides []proxyOverride
+
+	lock sync.RWMutex
+	// user are the credentials read from the secret store, nil until they are.
+	user *url.Userinfo
+}
+
+// proxyOverride is the proxy of the requests of a component to its hosts.
+type proxyOverride struct {
+	// match returns true if the URL is one of the hosts of the component.
+	match func(*url.URL) bool
+	proxy func(*url.URL) (*url.URL, error)
+}
+
+// newEgressProxy returns the proxy of the spec, or nil if no proxy is configured.
+func newEgressProxy(spec config.ProxySpec) (*egressProxy, error) {
+	if spec.HTTPProxy == "" && spec.HTTPSProxy == "" && len(spec.Components) == 0 {
+		return nil, nil
+	}
+	if err := validateProxyURLs(spec.HTTPProxy, spec.HTTPSProxy); err != nil {
+		return nil, err
+	}
+
+	proxyConfig := httpproxy.Config{
+		HTTPProxy:  spec.HTTPProxy,
+		HTTPSProxy: spec.HTTPSProxy,
+		NoProxy:    strings.Join(spec.NoProxy, ","),
+	}
+	p := &egressProxy{
+		spec:      spec,
+		proxy:     proxyConfig.ProxyFunc(),
+		overrides: make([]proxyOverride, len(spec.Components)),
+	}
+	for i, c := range spec.Components {
+		if c.Name == "" || len(c.Hosts) == 0 {
+			return nil, fmt.Errorf("invalid proxy of component %q: the name and the hosts of the component are required", c.Name)
+		}
+		if err := validateProxyURLs(c.HTTPProxy, c.HTTPSProxy); err != nil {
+			return nil, fmt.Errorf("invalid proxy of component %s: %w", c.Name, err)
+		}
+
+		// The hosts are matched with the rules of NoProxy: their requests are the ones a proxy excluding them doesn't get
+		matchConfig := httpproxy.Config{
+			HTTPProxy:  "http://match",
+			HTTPSProxy: "http://match",
+			NoProxy:    strings.Join(c.Hosts, ","),
+		}
+		excluded := matchConfig.ProxyFunc()
+		overrideConfig := httpproxy.Config{
+			HTTPProxy:  c.HTTPProxy,
+			HTTPSProxy: c.HTTPSProxy,
+		}
+		p.overrides[i] = proxyOverride{
+			match: func(u *url.URL) bool {
+				proxyURL, err := excluded(u)
+				return err == nil && proxyURL == nil
+			},
+			proxy: overrideConfig.ProxyFunc(),
+		}
+	}
+	return p, nil
+}
+
+func validateProxyURLs(proxyURLs ...string) error {
+	for _, proxyURL := range proxyURLs {
+		if proxyURL == "" {
+			continue
+		}
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q", proxyURL)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("invalid proxy URL %q: unsupported scheme %q", proxyURL, u.Scheme)
+		}
+	}
+	return nil
+}
+
+// Proxy returns the proxy of the request, or nil if the request isn't proxied.
+// It has the signature of http.Transport.Proxy.
+func (p *egressProxy) Proxy(req *http.Request) (*url.URL, error) {
+	proxy := p.proxy
+	for _, o := range p.overrides {
+		if o.match(req.URL) {
+			proxy = o.proxy
+			break
+		}
+	}
+
+	u, err := proxy(req.URL)
+	if err != nil || u == nil || u.User != nil {
+		return u, err
+	}
+
+	p.lock.RLock()
+	user := p.user
+	p.lock.RUnlock()
+	if user == nil {
+		return u, nil
+	}
+	withUser := *u
+	withUser.User = user
+	return &withUser, nil
+}
+
+// setCredentials sets the credentials sent to the proxies whose URL has none.
+func (p *egressProxy) setCredentials(username, password string) {
+	p.lock.Lock()
+	p.user = url.UserPassword(username, password)
+	p.lock.Unlock()
+}
+
+// environment returns the proxy environment variables, honored by the clients which don't use the default HTTP transport.
+// They only have the global proxies: the overrides of the components only apply to the default HTTP transport.
+func (p *egressProxy) environment() map[string]string {
+	p.lock.RLock()
+	user := p.user
+	p.lock.RUnlock()
+
+	withUser := func(proxyURL string) string {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.User != nil || user == nil {
+			return proxyURL
+		}
+		u.User = user
+		return u.String()
+	}
+
+	env := map[string]string{}
+	if p.spec.HTTPProxy != "" {
+		env["HTTP_PROXY"] = withUser(p.spec.HTTPProxy)
+	}
+	if p.spec.HTTPSProxy != "" {
+		env["HTTPS_PROXY"] = withUser(p.spec.HTTPSProxy)
+	}
+	if len(p.spec.NoProxy) > 0 {
+		env["NO_PROXY"] = strings.Join(p.spec.NoProxy, ",")
+	}
+	return env
+}
+
+// secretStore returns the name of the secret store holding the credentials of the proxies, empty if they have none.
+func (p *egressProxy) secretStore() string {
+	if p.spec.Auth.SecretName == "" {
+		return ""
+	}
+	if p.spec.Auth.SecretStore == "" {
+		return secretstoresLoader.BuiltinKubernetesSecretStore
+	}
+	return p.spec.Auth.SecretStore
+}
+
+// initEgressProxy routes the outbound HTTP(S) requests through the proxies of the configuration.
+// The requests of the default HTTP transport, and of its clones, use the proxies, and the clients of
+// the components reading the proxy environment variables when they're created do as well.
+func (a *DaprRuntime) initEgressProxy() error {
+	spec := a.globalConfig.Spec.ProxySpec
+	if spec.HTTPProxy != "" || spec.HTTPSProxy != "" {
+		spec.NoProxy = append(a.defaultNoProxy(), spec.NoProxy...)
+	}
+	p, err := newEgressProxy(spec)
+	if err != nil || p == nil {
+		return err
+	}
+	a.egressProxy = p
+
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.Proxy = p.Proxy
+	}
+	a.setEgressProxyEnvironment()
+	log.Infof("outbound HTTP requests go through the proxies of configuration %s", a.runtimeConfig.GlobalConfig)
+	return nil
+}
+
+// defaultNoProxy returns the hosts which are never proxied: in Kubernetes, the services of the cluster and the API
+// server, so the requests to the control plane and to the other apps stay in the cluster.
+func (a *DaprRuntime) defaultNoProxy() []string {
+	if a.runtimeConfig.Mode != modes.KubernetesMode {
+		return nil
+	}
+
+	clusterDomain, err := utils.GetKubeClusterDomain()
+	if err != nil {
+		clusterDomain = utils.DefaultKubeClusterDomain
+	}
+	noProxy := []string{".svc", "." + clusterDomain}
+	if host := os.Getenv("KUBERNETES_SERVICE_HOST"); host != "" {
+		noProxy = append(noProxy, host)
+	}
+	return noProxy
+}
+
+func (a *DaprRuntime) setEgressProxyEnvironment() {
+	for k, v := range a.egressProxy.environment() {
+		if err := os.Setenv(k, v); err != nil {
+			log.Warnf("failed to set the proxy environment variable %s: %s", k, err)
+		}
+	}
+}
+
+// loadEgressProxyCredentials reads the credentials of the proxies once their secret store is initialized,
+// so that the components initialized after it get them.
+func (a *DaprRuntime) loadEgressProxyCredentials(storeName string) {
+	if a.egressProxy == nil || a.egressProxy.secretStore() != storeName {
+		return
+	}
+
+	auth := a.egressProxy.spec.Auth
+	resp, err := a.getSecretStore(storeName).GetSecret(secretstores.GetSecretRequest{
+		Name: auth.SecretName,
+		Metadata: map[string]string{
+			"namespace": a.namespace,
+		},
+	})
+	if err != nil {
+		log.Errorf("failed to get the proxy credentials from secret %s of secret store %s: %s", auth.SecretName, storeName, err)
+		return
+	}
+
+	usernameKey, passwordKey := auth.UsernameKey, auth.PasswordKey
+	if usernameKey == "" {
+		usernameKey = defaultProxyUsernameKey
+	}
+	if passwordKey == "" {
+		passwordKey = defaultProxyPasswordKey
+	}
+	username, ok := resp.Data[usernameKey]
+	if !ok {
+		log.Errorf("the proxy credentials secret %s of secret store %s has no key %s", auth.SecretName, storeName, usernameKey)
+		return
+	}
+	a.egressProxy.setCredentials(username, resp.Data[passwordKey])
+	a.setEgressProxyEnvironment()
+	log.Infof("loaded the proxy credentials from secret store %s", storeName)
+}
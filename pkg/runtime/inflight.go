@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	"github.com/dapr/dapr/pkg/channel"
+	"github.com/dapr/dapr/pkg/messaging"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+)
+
+// inflightCalls counts the calls in progress, so that they can be drained before the sidecar shuts down.
+type inflightCalls struct {
+	lock    sync.Mutex
+	count   int
+	drained chan struct{}
+}
+
+func (c *inflightCalls) start() {
+	c.lock.Lock()
+	c.count++
+	c.lock.Unlock()
+}
+
+func (c *inflightCalls) end() {
+	c.lock.Lock()
+	c.count--
+	if c.count == 0 && c.drained != nil {
+		close(c.drained)
+		c.drained = nil
+	}
+	c.lock.Unlock()
+}
+
+// wait blocks until no calls are in progress or the context is done.
+// It returns the number of calls still in progress.
+func (c *inflightCalls) wait(ctx context.Context) int {
+	c.lock.Lock()
+	if c.count == 0 {
+		c.lock.Unlock()
+		return 0
+	}
+	if c.drained == nil {
+		c.drained = make(chan struct{})
+	}
+	drained := c.drained
+	c.lock.Unlock()
+
+	select {
+	case <-drained:
+		return 0
+	case <-ctx.Done():
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		return c.count
+	}
+}
+
+// inflightAppChannel is an app channel recording the calls to the app in progress:
+// the service invocations, the actor calls and the delivery of events.
+type inflightAppChannel struct {
+	channel.AppChannel
+
+	calls *inflightCalls
+}
+
+func (c *inflightAppChannel) InvokeMethod(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	c.calls.start()
+	defer c.calls.end()
+	return c.AppChannel.InvokeMethod(ctx, req)
+}
+
+// inflightClientConn is a connection to the app recording the unary calls in progress:
+// the delivery of events and the calls of the app callbacks with the gRPC protocol.
+type inflightClientConn struct {
+	grpc.ClientConnInterface
+
+	calls *inflightCalls
+}
+
+func (c *inflightClientConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	c.calls.start()
+	defer c.calls.end()
+	return c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+}
+
+// inflightProxy is a gRPC proxy recording the proxied streams in progress.
+type inflightProxy struct {
+	messaging.Proxy
+
+	calls *inflightCalls
+}
+
+func (p *inflightProxy) Handler() grpc.StreamHandler {
+	handler := p.Proxy.Handler()
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		p.calls.start()
+		defer p.calls.end()
+		return handler(srv, stream)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/dapr/dapr/pkg/channel"
+	"github.com/dapr/dapr/pkg/messaging"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	"github.com/dapr/dapr/pkg/modes"
+)
+
+// slowAppChannel is an app channel whose calls take a fixed time to complete.
+type slowAppChannel struct {
+	channel.AppChannel
+
+	delay     time.Duration
+	started   chan struct{}
+	completed chan struct{}
+}
+
+func (c *slowAppChannel) InvokeMethod(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	close(c.started)
+	time.Sleep(c.delay)
+	c.completed <- struct{}{}
+	return invokev1.NewInvokeMethodResponse(200, "OK", nil), nil
+}
+
+func TestInflightCalls(t *testing.T) {
+	calls := &inflightCalls{}
+	assert.Equal(t, 0, calls.wait(context.Background()))
+
+	calls.start()
+	calls.start()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 2, calls.wait(ctx))
+
+	go func() {
+		calls.end()
+		calls.end()
+	}()
+	assert.Equal(t, 0, calls.wait(context.Background()))
+}
+
+// countingClientConn is a connection to the app recording the number of calls in progress during its calls.
+type countingClientConn struct {
+	grpc.ClientConnInterface
+
+	calls   *inflightCalls
+	pending int
+}
+
+func (c *countingClientConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	c.calls.lock.Lock()
+	c.pending = c.calls.count
+	c.calls.lock.Unlock()
+	return nil
+}
+
+// countingProxy is a gRPC proxy recording the number of calls in progress during its streams.
+type countingProxy struct {
+	messaging.Proxy
+
+	calls   *inflightCalls
+	pending int
+}
+
+func (p *countingProxy) Handler() grpc.StreamHandler {
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		p.calls.lock.Lock()
+		p.pending = p.calls.count
+		p.calls.lock.Unlock()
+		return nil
+	}
+}
+
+func TestInflightGRPCCalls(t *testing.T) {
+	t.Run("calls of the app callbacks", func(t *testing.T) {
+		calls := &inflightCalls{}
+		conn := &countingClientConn{calls: calls}
+		c := &inflightClientConn{ClientConnInterface: conn, calls: calls}
+
+		require.NoError(t, c.Invoke(context.Background(), "/dapr.proto.runtime.v1.AppCallback/OnTopicEvent", nil, nil))
+		assert.Equal(t, 1, conn.pending)
+		assert.Equal(t, 0, calls.wait(context.Background()))
+	})
+
+	t.Run("proxied streams", func(t *testing.T) {
+		calls := &inflightCalls{}
+		proxy := &countingProxy{calls: calls}
+		p := &inflightProxy{Proxy: proxy, calls: calls}
+
+		require.NoError(t, p.Handler()(nil, nil))
+		assert.Equal(t, 1, proxy.pending)
+		assert.Equal(t, 0, calls.wait(context.Background()))
+	})
+}
+
+func TestShutdownDrainsAppCalls(t *testing.T) {
+	startCall := func(rt *DaprRuntime, delay time.Duration) chan struct{} {
+		ch := &slowAppChannel{delay: delay, started: make(chan struct{}), completed: make(chan struct{}, 1)}
+		rt.appChannel = &inflightAppChannel{AppChannel: ch, calls: &rt.appCalls}
+		go rt.appChannel.InvokeMethod(context.Background(), invokev1.NewInvokeMethodRequest("method"))
+		<-ch.started
+		return ch.completed
+	}
+
+	t.Run("the calls in progress complete before the shutdown", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		completed := startCall(rt, 200*time.Millisecond)
+
+		start := time.Now()
+		rt.Shutdown(5 * time.Second)
+		select {
+		case <-completed:
+		default:
+			t.Fatal("the shutdown completed before the call in progress")
+		}
+		assert.Less(t, time.Since(start), 5*time.Second)
+		require.NoError(t, rt.WaitUntilShutdown())
+	})
+
+	t.Run("the shutdown waits for the minimum duration", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+
+		start := time.Now()
+		rt.Shutdown(5 * time.Second)
+		assert.GreaterOrEqual(t, time.Since(start), minShutdownDrainDuration)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		require.NoError(t, rt.WaitUntilShutdown())
+	})
+
+	t.Run("the shutdown doesn't wait longer than the graceful period", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		startCall(rt, 2*time.Second)
+
+		start := time.Now()
+		rt.Shutdown(100 * time.Millisecond)
+		assert.Less(t, time.Since(start), time.Second)
+		require.NoError(t, rt.WaitUntilShutdown())
+	})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+)
+
+// MaxAttributes is the maximum number of metadata attributes the app can set.
+const MaxAttributes = 64
+
+// Attribute is a metadata attribute set by the app.
+type Attribute struct {
+	Key    string
+	Value  string
+	Labels map[string]string
+	// ExpiresAt is zero when the attribute doesn't expire.
+	ExpiresAt time.Time
+}
+
+func (a Attribute) expired(now time.Time) bool {
+	return !a.ExpiresAt.IsZero() && !now.Before(a.ExpiresAt)
+}
+
+// AttributeStore holds the metadata attributes set by the app.
+// The attributes are reported by the metadata API, and are added to the metrics and to the spans of the sidecar.
+// The attributes with a TTL are removed by a timer once they expire.
+type AttributeStore struct {
+	lock   sync.RWMutex
+	attrs  map[string]Attribute
+	timers map[string]*time.Timer
+	now    func() time.Time
+}
+
+// NewAttributeStore returns a new, empty AttributeStore.
+func NewAttributeStore() *AttributeStore {
+	return &AttributeStore{
+		attrs:  map[string]Attribute{},
+		timers: map[string]*time.Timer{},
+		now:    time.Now,
+	}
+}
+
+// Set sets the value and the labels of the attribute, replacing the existing ones.
+// The attribute expires after ttl, or never if ttl is zero.
+func (s *AttributeStore) Set(key, value string, ttl time.Duration, labels map[string]string) error {
+	if key == "" {
+		return fmt.Errorf("the attribute key is required")
+	}
+	if ttl < 0 {
+		return fmt.Errorf("the TTL of attribute %s must not be negative", key)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	s.purgeLocked(now)
+
+	_, ok := s.attrs[key]
+	if !ok && len(s.attrs) >= MaxAttributes {
+		return fmt.Errorf("cannot set attribute %s: the number of attributes is limited to %d", key, MaxAttributes)
+	}
+	s.stopTimerLocked(key)
+
+	attr := Attribute{
+		Key:    key,
+		Value:  value,
+		Labels: labels,
+	}
+	if ttl > 0 {
+		attr.ExpiresAt = now.Add(ttl)
+		s.timers[key] = time.AfterFunc(ttl, s.purge)
+	}
+	s.attrs[key] = attr
+	if !ok {
+		diag.DefaultMonitoring.MetadataAttributeSet(key)
+	}
+	return nil
+}
+
+// List returns the attributes that haven't expired, sorted by key.
+func (s *AttributeStore) List() []Attribute {
+	now := s.now()
+
+	s.lock.RLock()
+	res := make([]Attribute, 0, len(s.attrs))
+	hasExpired := false
+	for _, attr := range s.attrs {
+		if attr.expired(now) {
+			hasExpired = true
+			continue
+		}
+		res = append(res, attr)
+	}
+	s.lock.RUnlock()
+
+	if hasExpired {
+		s.lock.Lock()
+		s.purgeLocked(now)
+		s.lock.Unlock()
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Key < res[j].Key
+	})
+	return res
+}
+
+// purge removes the expired attributes.
+func (s *AttributeStore) purge() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.purgeLocked(s.now())
+}
+
+// purgeLocked removes the expired attributes. The lock must be held.
+func (s *AttributeStore) purgeLocked(now time.Time) {
+	for key, attr := range s.attrs {
+		if attr.expired(now) {
+			delete(s.attrs, key)
+			s.stopTimerLocked(key)
+			diag.DefaultMonitoring.MetadataAttributeRemoved(key)
+		}
+	}
+}
+
+// stopTimerLocked stops the expiration timer of the attribute, if any. The lock must be held.
+func (s *AttributeStore) stopTimerLocked(key string) {
+	if timer, ok := s.timers[key]; ok {
+		timer.Stop()
+		delete(s.timers, key)
+	}
+}
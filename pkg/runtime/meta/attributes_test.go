@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeStore(t *testing.T) {
+	now := time.Now()
+	store := NewAttributeStore()
+	store.now = func() time.Time {
+		return now
+	}
+
+	require.NoError(t, store.Set("tenant", "contoso", 0, map[string]string{"team": "billing"}))
+	require.NoError(t, store.Set("build", "abc123", time.Minute, nil))
+
+	attrs := store.List()
+	require.Len(t, attrs, 2)
+	assert.Equal(t, "build", attrs[0].Key)
+	assert.Equal(t, now.Add(time.Minute), attrs[0].ExpiresAt)
+	assert.Equal(t, "tenant", attrs[1].Key)
+	assert.Equal(t, "contoso", attrs[1].Value)
+	assert.Equal(t, map[string]string{"team": "billing"}, attrs[1].Labels)
+	assert.True(t, attrs[1].ExpiresAt.IsZero())
+
+	t.Run("attributes expire after their TTL", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		attrs := store.List()
+		require.Len(t, attrs, 1)
+		assert.Equal(t, "tenant", attrs[0].Key)
+	})
+
+	t.Run("setting an attribute replaces it", func(t *testing.T) {
+		require.NoError(t, store.Set("tenant", "fabrikam", 0, nil))
+		attrs := store.List()
+		require.Len(t, attrs, 1)
+		assert.Equal(t, "fabrikam", attrs[0].Value)
+		assert.Empty(t, attrs[0].Labels)
+	})
+
+	t.Run("invalid attributes", func(t *testing.T) {
+		assert.Error(t, store.Set("", "value", 0, nil))
+		assert.Error(t, store.Set("key", "value", -time.Second, nil))
+	})
+
+	t.Run("the number of attributes is limited", func(t *testing.T) {
+		for i := len(store.List()); i < MaxAttributes; i++ {
+			require.NoError(t, store.Set(fmt.Sprintf("key%d", i), "value", 0, nil))
+		}
+		assert.Error(t, store.Set("onemore", "value", 0, nil))
+		// Existing attributes can still be replaced
+		assert.NoError(t, store.Set("tenant", "contoso", 0, nil))
+	})
+}
+
+func TestAttributeStoreExpirationTimer(t *testing.T) {
+	store := NewAttributeStore()
+	require.NoError(t, store.Set("build", "abc123", 10*time.Millisecond, nil))
+	require.NoError(t, store.Set("tenant", "contoso", 0, nil))
+
+	// The expired attributes are removed without being listed
+	assert.Eventually(t, func() bool {
+		store.lock.RLock()
+		defer store.lock.RUnlock()
+		_, ok := store.attrs["build"]
+		return !ok && len(store.timers) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, store.List(), 1)
+
+	t.Run("setting an attribute again resets its timer", func(t *testing.T) {
+		require.NoError(t, store.Set("tenant", "contoso", time.Hour, nil))
+		require.NoError(t, store.Set("tenant", "fabrikam", 0, nil))
+		store.lock.RLock()
+		defer store.lock.RUnlock()
+		assert.Empty(t, store.timers)
+	})
+}
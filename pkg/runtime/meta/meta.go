@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package meta contains the information about the runtime that is reported by the metadata API.
+package meta
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/components/validation"
+	"github.com/dapr/dapr/pkg/config"
+	runtimePubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+)
+
+// Initialization statuses of a component.
+const (
+	ComponentStatusInitialized = "INITIALIZED"
+	ComponentStatusFailed      = "FAILED"
+	// ComponentStatusDegraded is the status of an optional component that failed to initialize.
+	ComponentStatusDegraded = "DEGRADED"
+	// ComponentStatusRetrying is the status of a component that failed to initialize and whose initialization is retried.
+	ComponentStatusRetrying = "RETRYING"
+)
+
+// Health statuses of the app.
+const (
+	AppStatusHealthy   = "HEALTHY"
+	AppStatusUnhealthy = "UNHEALTHY"
+)
+
+// Info is the information about the runtime reported by the metadata API.
+type Info struct {
+	ComponentStatuses []ComponentStatus
+	Subscriptions     []runtimePubsub.Subscription
+	ActorTypes        []string
+	AppConnection     AppConnection
+	EnabledFeatures   []string
+	Features          []config.FeatureStatus
+	// Nil when the drift detection is disabled.
+	Drift *Drift
+	// Nil when the calls from other namespaces aren't restricted.
+	CrossNamespace *config.CrossNamespaceSpec
+}
+
+// Drift is the difference between the resources loaded by the sidecar and the current ones in the operator.
+type Drift struct {
+	// Components are the names of the components that were updated, created or deleted since the sidecar loaded them.
+	Components []string
+	// Configuration is true when the configuration was updated since the sidecar loaded it.
+	Configuration bool
+	LastChecked   time.Time
+}
+
+// Detected returns true if any resource is out of date.
+func (d Drift) Detected() bool {
+	return len(d.Components) > 0 || d.Configuration
+}
+
+// ComponentStatus is the initialization status of a component.
+type ComponentStatus struct {
+	Name      string
+	Type      string
+	Version   string
+	Status    string
+	LastError string
+	// ErrorClass is the cause of the last error, empty if the component was initialized.
+	ErrorClass  validation.ErrorClass
+	LastUpdated time.Time
+}
+
+// AppConnection describes the connection between Dapr and the app.
+type AppConnection struct {
+	Port           int
+	Protocol       string
+	MaxConcurrency int
+	// Nil when the app health checks are disabled.
+	Health *AppHealth
+}
+
+// AppHealth describes the health checks of the app.
+type AppHealth struct {
+	HealthCheckPath     string
+	HealthProbeInterval time.Duration
+	HealthProbeTimeout  time.Duration
+	HealthThreshold     int32
+	Status              string
+}
+
+// ComponentStatusStore records the initialization status of the components.
+type ComponentStatusStore struct {
+	lock     sync.RWMutex
+	statuses map[string]ComponentStatus
+}
+
+// NewComponentStatusStore returns a new, empty ComponentStatusStore.
+func NewComponentStatusStore() *ComponentStatusStore {
+	return &ComponentStatusStore{
+		statuses: map[string]ComponentStatus{},
+	}
+}
+
+// SetInitialized records the successful initialization of the component.
+func (s *ComponentStatusStore) SetInitialized(comp componentsV1alpha1.Component) {
+	s.set(comp, ComponentStatusInitialized, "", "")
+}
+
+// SetFailed records the failed initialization of the component.
+func (s *ComponentStatusStore) SetFailed(comp componentsV1alpha1.Component, err error) {
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+	s.set(comp, ComponentStatusFailed, lastError, validation.Classify(err))
+}
+
+// SetDegraded records the failed initialization of an optional component.
+func (s *ComponentStatusStore) SetDegraded(comp componentsV1alpha1.Component, err error) {
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+	s.set(comp, ComponentStatusDegraded, lastError, validation.Classify(err))
+}
+
+// SetRetrying records the failed initialization of a component whose initialization is retried.
+func (s *ComponentStatusStore) SetRetrying(comp componentsV1alpha1.Component, err error) {
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+	s.set(comp, ComponentStatusRetrying, lastError, validation.Classify(err))
+}
+
+func (s *ComponentStatusStore) set(comp componentsV1alpha1.Component, status string, lastError string, errorClass validation.ErrorClass) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.statuses[comp.Name] = ComponentStatus{
+		Name:        comp.Name,
+		Type:        comp.Spec.Type,
+		Version:     comp.Spec.Version,
+		Status:      status,
+		LastError:   lastError,
+		ErrorClass:  errorClass,
+		LastUpdated: time.Now(),
+	}
+}
+
+// Get returns the status of the component.
+func (s *ComponentStatusStore) Get(name string) (ComponentStatus, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	status, ok := s.statuses[name]
+	return status, ok
+}
+
+// Delete forgets the status of the component.
+func (s *ComponentStatusStore) Delete(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.statuses, name)
+}
+
+// List returns the status of all the components, sorted by name.
+func (s *ComponentStatusStore) List() []ComponentStatus {
+	s.lock.RLock()
+	res := make([]ComponentStatus, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		res = append(res, status)
+	}
+	s.lock.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
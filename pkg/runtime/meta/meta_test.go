@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/components/validation"
+)
+
+func TestComponentStatusStore(t *testing.T) {
+	store := NewComponentStatusStore()
+
+	comp1 := componentsV1alpha1.Component{}
+	comp1.Name = "b"
+	comp1.Spec.Type = "state.redis"
+	comp1.Spec.Version = "v1"
+	comp2 := componentsV1alpha1.Component{}
+	comp2.Name = "a"
+	comp2.Spec.Type = "pubsub.redis"
+
+	store.SetFailed(comp1, errors.New("init error"))
+	store.SetInitialized(comp2)
+
+	statuses := store.List()
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "a", statuses[0].Name)
+	assert.Equal(t, ComponentStatusInitialized, statuses[0].Status)
+	assert.Empty(t, statuses[0].LastError)
+	assert.Equal(t, "b", statuses[1].Name)
+	assert.Equal(t, "state.redis", statuses[1].Type)
+	assert.Equal(t, "v1", statuses[1].Version)
+	assert.Equal(t, ComponentStatusFailed, statuses[1].Status)
+	assert.Equal(t, "init error", statuses[1].LastError)
+	assert.Equal(t, validation.ClassUnknown, statuses[1].ErrorClass)
+	assert.False(t, statuses[1].LastUpdated.IsZero())
+
+	// A successful initialization clears the error
+	store.SetInitialized(comp1)
+	statuses = store.List()
+	assert.Equal(t, ComponentStatusInitialized, statuses[1].Status)
+	assert.Empty(t, statuses[1].LastError)
+	assert.Empty(t, statuses[1].ErrorClass)
+
+	store.SetDegraded(comp2, errors.New("optional"))
+	statuses = store.List()
+	assert.Equal(t, ComponentStatusDegraded, statuses[0].Status)
+	assert.Equal(t, "optional", statuses[0].LastError)
+
+	store.SetRetrying(comp2, errors.New("retry"))
+	status, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, ComponentStatusRetrying, status.Status)
+	assert.Equal(t, "retry", status.LastError)
+
+	store.Delete("a")
+	statuses = store.List()
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "b", statuses[0].Name)
+}
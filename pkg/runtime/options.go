@@ -1,14 +1,20 @@
 package runtime
 
 import (
+	"net"
+
+	"google.golang.org/grpc"
+
 	bindingsLoader "github.com/dapr/dapr/pkg/components/bindings"
 	configurationLoader "github.com/dapr/dapr/pkg/components/configuration"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
 	lockLoader "github.com/dapr/dapr/pkg/components/lock"
 	httpMiddlewareLoader "github.com/dapr/dapr/pkg/components/middleware/http"
 	nrLoader "github.com/dapr/dapr/pkg/components/nameresolution"
 	pubsubLoader "github.com/dapr/dapr/pkg/components/pubsub"
 	secretstoresLoader "github.com/dapr/dapr/pkg/components/secretstores"
 	stateLoader "github.com/dapr/dapr/pkg/components/state"
+	"github.com/dapr/dapr/pkg/runtime/workflow"
 )
 
 type (
@@ -18,11 +24,15 @@ type (
 		stateRegistry          *stateLoader.Registry
 		configurationRegistry  *configurationLoader.Registry
 		lockRegistry           *lockLoader.Registry
+		cryptoRegistry         *cryptoLoader.Registry
 		pubsubRegistry         *pubsubLoader.Registry
 		nameResolutionRegistry *nrLoader.Registry
 		bindingRegistry        *bindingsLoader.Registry
 		httpMiddlewareRegistry *httpMiddlewareLoader.Registry
 		componentsCallback     ComponentsCallback
+		appConnection          *grpc.ClientConn
+		apiListeners           []net.Listener
+		workflowRegistry       *workflow.Registry
 	}
 
 	// Option is a function that customizes the runtime.
@@ -56,6 +66,13 @@ func WithLocks(registry *lockLoader.Registry) Option {
 	}
 }
 
+// WithCryptoProviders adds crypto components to the runtime.
+func WithCryptoProviders(registry *cryptoLoader.Registry) Option {
+	return func(o *runtimeOpts) {
+		o.cryptoRegistry = registry
+	}
+}
+
 // WithPubSubs adds pubsub store components to the runtime.
 func WithPubSubs(registry *pubsubLoader.Registry) Option {
 	return func(o *runtimeOpts) {
@@ -90,3 +107,25 @@ func WithComponentsCallback(componentsCallback ComponentsCallback) Option {
 		o.componentsCallback = componentsCallback
 	}
 }
+
+// WithAppConnection sets the connection to the gRPC server of applications that embed Dapr.
+// It is used instead of connecting to the app port.
+func WithAppConnection(conn *grpc.ClientConn) Option {
+	return func(o *runtimeOpts) {
+		o.appConnection = conn
+	}
+}
+
+// WithAPIListener adds a listener for the Dapr gRPC API, such as an in-memory listener for applications that embed Dapr.
+func WithAPIListener(l net.Listener) Option {
+	return func(o *runtimeOpts) {
+		o.apiListeners = append(o.apiListeners, l)
+	}
+}
+
+// WithWorkflows adds workflows and activities to the runtime, which are executed by the workflow engine when actors are enabled.
+func WithWorkflows(registry *workflow.Registry) Option {
+	return func(o *runtimeOpts) {
+		o.workflowRegistry = registry
+	}
+}
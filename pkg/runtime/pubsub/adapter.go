@@ -14,11 +14,32 @@ limitations under the License.
 package pubsub
 
 import (
+	"time"
+
 	contribPubsub "github.com/dapr/components-contrib/pubsub"
 )
 
+// ShadowTopicMetadataKey is the metadata key of the publish requests naming a topic the message is mirrored to.
+// The message is published to the shadow topic after it was published to its topic, and the failures to publish
+// it to the shadow topic don't affect the primary delivery. It lets new consumers be tested against the real traffic.
+const ShadowTopicMetadataKey = "shadowTopic"
+
 // Adapter is the interface for message buses.
 type Adapter interface {
 	GetPubSub(pubsubName string) contribPubsub.PubSub
 	Publish(req *contribPubsub.PublishRequest) error
 }
+
+// SubscriptionPauser is implemented by adapters that allow the app to temporarily pause delivery of messages for a topic.
+type SubscriptionPauser interface {
+	PauseSubscription(pubsubName, topic string, duration time.Duration) error
+	ResumeSubscription(pubsubName, topic string) error
+	PausedSubscriptions() []PausedSubscription
+}
+
+// PausedSubscription describes a subscription whose delivery is currently paused.
+type PausedSubscription struct {
+	PubsubName  string    `json:"pubsubname"`
+	Topic       string    `json:"topic"`
+	PausedUntil time.Time `json:"pausedUntil"`
+}
@@ -14,12 +14,39 @@ limitations under the License.
 package pubsub
 
 import (
+	"encoding/json"
+	"strings"
+
 	"github.com/google/uuid"
 
 	contribContenttype "github.com/dapr/components-contrib/contenttype"
+	contribMetadata "github.com/dapr/components-contrib/metadata"
 	contribPubsub "github.com/dapr/components-contrib/pubsub"
 )
 
+const (
+	// DataSchemaField is the attribute of the cloudevents identifying the schema of their data.
+	DataSchemaField = "dataschema"
+	// BaggageField is the attribute of the cloudevents holding the W3C baggage of the publisher.
+	BaggageField = "baggage"
+
+	// cloudEventOverridePrefix is the prefix of the publish metadata overriding the fields of the cloudevent envelope.
+	cloudEventOverridePrefix = "cloudevent."
+	// RawPayloadAuto is the value of the rawPayload subscription metadata letting the runtime detect,
+	// for each message, whether it is a cloudevent or a raw payload.
+	RawPayloadAuto = "auto"
+)
+
+// cloudEventOverrides are the fields of the cloudevent envelope which publishers can override with the
+// cloudevent.<field> metadata, for example to interoperate with consumers expecting a given source or type.
+var cloudEventOverrides = []string{
+	contribPubsub.SourceField,
+	contribPubsub.TypeField,
+	contribPubsub.DataContentTypeField,
+	contribPubsub.TraceParentField,
+	contribPubsub.TraceStateField,
+}
+
 // CloudEvent is a request object to create a Dapr compliant cloudevent.
 type CloudEvent struct {
 	ID              string
@@ -29,13 +56,68 @@ type CloudEvent struct {
 	DataContentType string
 	TraceID         string
 	TraceState      string
+	Baggage         string
 }
 
 // NewCloudEvent encapsulates the creation of a Dapr cloudevent from an existing cloudevent or a raw payload.
 func NewCloudEvent(req *CloudEvent) (map[string]interface{}, error) {
+	var envelope map[string]interface{}
 	if contribContenttype.IsCloudEventContentType(req.DataContentType) {
-		return contribPubsub.FromCloudEvent(req.Data, req.Topic, req.Pubsub, req.TraceID, req.TraceState)
+		var err error
+		envelope, err = contribPubsub.FromCloudEvent(req.Data, req.Topic, req.Pubsub, req.TraceID, req.TraceState)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		envelope = contribPubsub.NewCloudEventsEnvelope(uuid.New().String(), req.ID, contribPubsub.DefaultCloudEventType,
+			"", req.Topic, req.Pubsub, req.DataContentType, req.Data, req.TraceID, req.TraceState)
+	}
+	if req.Baggage != "" {
+		envelope[BaggageField] = req.Baggage
+	}
+	return envelope, nil
+}
+
+// ApplyCloudEventOverrides sets the fields of the cloudevent overridden by the publish metadata.
+// The traceparent is passed through as is, replacing the one of the Dapr span.
+func ApplyCloudEventOverrides(cloudEvent map[string]interface{}, metadata map[string]string) {
+	for _, field := range cloudEventOverrides {
+		val, ok := metadata[cloudEventOverridePrefix+field]
+		if !ok || val == "" {
+			continue
+		}
+		cloudEvent[field] = val
+		if field == contribPubsub.TraceParentField {
+			cloudEvent[contribPubsub.TraceIDField] = val
+		}
 	}
-	return contribPubsub.NewCloudEventsEnvelope(uuid.New().String(), req.ID, contribPubsub.DefaultCloudEventType,
-		"", req.Topic, req.Pubsub, req.DataContentType, req.Data, req.TraceID, req.TraceState), nil
+}
+
+// IsRawPayload reports whether the data of a message received by a subscription is a raw payload rather than a cloudevent.
+// When the rawPayload metadata of the subscription is "auto", the messages which aren't JSON cloudevents are raw payloads,
+// so that the subscription can receive messages from Dapr and non-Dapr publishers.
+func IsRawPayload(subscriptionMetadata map[string]string, data []byte) (bool, error) {
+	if strings.EqualFold(subscriptionMetadata[contribMetadata.RawPayloadKey], RawPayloadAuto) {
+		return !isCloudEvent(data), nil
+	}
+	return contribMetadata.IsRawPayload(subscriptionMetadata)
+}
+
+func isCloudEvent(data []byte) bool {
+	var envelope struct {
+		SpecVersion string `json:"specversion"`
+	}
+	return json.Unmarshal(data, &envelope) == nil && envelope.SpecVersion != ""
+}
+
+// DataSchema returns the identifier of the schema of the cloudevent data, or an empty string if it is not set.
+func DataSchema(cloudEvent map[string]interface{}) string {
+	schema, _ := cloudEvent[DataSchemaField].(string)
+	return schema
+}
+
+// Baggage returns the W3C baggage of the publisher of the cloudevent, or an empty string if it is not set.
+func Baggage(cloudEvent map[string]interface{}) string {
+	baggage, _ := cloudEvent[BaggageField].(string)
+	return baggage
 }
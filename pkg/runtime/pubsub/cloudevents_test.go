@@ -77,4 +77,87 @@ func TestNewCloudEvent(t *testing.T) {
 		assert.Equal(t, "trace1", ce["traceid"].(string))
 		assert.Equal(t, "pubsub", ce["pubsubname"].(string))
 	})
+
+	t.Run("baggage", func(t *testing.T) {
+		ce, err := NewCloudEvent(&CloudEvent{
+			ID:      "a",
+			Topic:   "b",
+			Data:    []byte("hello"),
+			Pubsub:  "c",
+			Baggage: "team=payments,environment=staging",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "team=payments,environment=staging", Baggage(ce))
+
+		ce, err = NewCloudEvent(&CloudEvent{ID: "a", Topic: "b", Pubsub: "c"})
+		assert.NoError(t, err)
+		assert.NotContains(t, ce, BaggageField)
+		assert.Empty(t, Baggage(ce))
+	})
+}
+
+func TestApplyCloudEventOverrides(t *testing.T) {
+	ce, err := NewCloudEvent(&CloudEvent{
+		ID:      "a",
+		Topic:   "b",
+		Data:    []byte("hello"),
+		Pubsub:  "c",
+		TraceID: "d",
+	})
+	assert.NoError(t, err)
+
+	ApplyCloudEventOverrides(ce, map[string]string{
+		"cloudevent.source":          "legacy-orders",
+		"cloudevent.type":            "com.example.order.created",
+		"cloudevent.datacontenttype": "application/xml",
+		"cloudevent.traceparent":     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"cloudevent.tracestate":      "",
+		"cloudevent.id":              "ignored",
+		"ttlInSeconds":               "10",
+	})
+	assert.Equal(t, "legacy-orders", ce["source"])
+	assert.Equal(t, "com.example.order.created", ce["type"])
+	assert.Equal(t, "application/xml", ce["datacontenttype"])
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ce["traceparent"])
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ce["traceid"])
+	assert.Equal(t, "", ce["tracestate"])
+	assert.NotEqual(t, "ignored", ce["id"])
+	assert.Equal(t, "hello", ce["data"])
+}
+
+func TestIsRawPayload(t *testing.T) {
+	cloudEvent := []byte(`{"specversion":"1.0","id":"event","data":"hello"}`)
+
+	t.Run("subscription metadata", func(t *testing.T) {
+		raw, err := IsRawPayload(map[string]string{"rawPayload": "true"}, cloudEvent)
+		assert.NoError(t, err)
+		assert.True(t, raw)
+
+		raw, err = IsRawPayload(nil, []byte("hello"))
+		assert.NoError(t, err)
+		assert.False(t, raw)
+
+		_, err = IsRawPayload(map[string]string{"rawPayload": "sometimes"}, cloudEvent)
+		assert.Error(t, err)
+	})
+
+	t.Run("auto", func(t *testing.T) {
+		metadata := map[string]string{"rawPayload": "auto"}
+
+		raw, err := IsRawPayload(metadata, cloudEvent)
+		assert.NoError(t, err)
+		assert.False(t, raw)
+
+		for _, data := range []string{"hello", `{"id":"order1"}`, `["a"]`, ""} {
+			raw, err = IsRawPayload(metadata, []byte(data))
+			assert.NoError(t, err)
+			assert.True(t, raw, data)
+		}
+	})
+}
+
+func TestDataSchema(t *testing.T) {
+	assert.Equal(t, "https://example.com/order.json", DataSchema(map[string]interface{}{DataSchemaField: "https://example.com/order.json"}))
+	assert.Empty(t, DataSchema(map[string]interface{}{"id": "event"}))
+	assert.Empty(t, DataSchema(nil))
 }
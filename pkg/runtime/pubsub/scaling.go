@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// processingRateWindow is the period over which the processing rate of the subscriptions is computed.
+const processingRateWindow = time.Minute
+
+// LagReporter is implemented by the pub/sub components which can report the number of messages of a topic
+// that were not delivered to the subscriber yet.
+type LagReporter interface {
+	Lag(ctx context.Context, topic string) (int64, error)
+}
+
+// ScalingSignals are the signals of a subscription used to autoscale the app, for example by KEDA scalers.
+type ScalingSignals struct {
+	PubsubName string
+	Topic      string
+	// PendingDeliveries is the number of messages received from the broker which the app hasn't processed yet.
+	PendingDeliveries int64
+	// ProcessingRate is the number of messages processed by the app per second, over the last minute.
+	ProcessingRate float64
+	// Lag is nil when the component can't report it.
+	Lag *int64
+}
+
+// SubscriptionStats tracks the deliveries of the messages of the subscriptions to the app.
+type SubscriptionStats struct {
+	lock sync.Mutex
+	subs map[string]*subscriptionStats
+	now  func() time.Time
+}
+
+type subscriptionStats struct {
+	pubsubName string
+	topic      string
+	pending    int64
+	// The number of processed messages for each second of the window.
+	processed [int(processingRateWindow / time.Second)]int64
+	// The second of each count of processed messages.
+	seconds [int(processingRateWindow / time.Second)]int64
+}
+
+// NewSubscriptionStats returns an empty SubscriptionStats.
+func NewSubscriptionStats() *SubscriptionStats {
+	return &SubscriptionStats{
+		subs: map[string]*subscriptionStats{},
+		now:  time.Now,
+	}
+}
+
+// DeliveryStarted records a message of the subscription being delivered to the app.
+// It returns the number of pending deliveries of the subscription.
+func (s *SubscriptionStats) DeliveryStarted(pubsubName, topic string) int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sub := s.get(pubsubName, topic)
+	sub.pending++
+	return sub.pending
+}
+
+// DeliveryCompleted records the end of the delivery of a message of the subscription, whatever its result.
+// It returns the number of pending deliveries of the subscription.
+func (s *SubscriptionStats) DeliveryCompleted(pubsubName, topic string) int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sub := s.get(pubsubName, topic)
+	if sub.pending > 0 {
+		sub.pending--
+	}
+
+	second := s.now().Unix()
+	i := int(second % int64(len(sub.seconds)))
+	if sub.seconds[i] != second {
+		sub.seconds[i] = second
+		sub.processed[i] = 0
+	}
+	sub.processed[i]++
+	return sub.pending
+}
+
+// Signals returns the scaling signals of the subscriptions which received messages, sorted by pub/sub and topic.
+func (s *SubscriptionStats) Signals() []ScalingSignals {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now().Unix()
+	signals := make([]ScalingSignals, 0, len(s.subs))
+	for _, sub := range s.subs {
+		var processed int64
+		for i, second := range sub.seconds {
+			if second > now-int64(len(sub.seconds)) && second <= now {
+				processed += sub.processed[i]
+			}
+		}
+		signals = append(signals, ScalingSignals{
+			PubsubName:        sub.pubsubName,
+			Topic:             sub.topic,
+			PendingDeliveries: sub.pending,
+			ProcessingRate:    float64(processed) / processingRateWindow.Seconds(),
+		})
+	}
+	sort.Slice(signals, func(i, j int) bool {
+		if signals[i].PubsubName != signals[j].PubsubName {
+			return signals[i].PubsubName < signals[j].PubsubName
+		}
+		return signals[i].Topic < signals[j].Topic
+	})
+	return signals
+}
+
+func (s *SubscriptionStats) get(pubsubName, topic string) *subscriptionStats {
+	key := pubsubName + "||" + topic
+	sub, ok := s.subs[key]
+	if !ok {
+		sub = &subscriptionStats{
+			pubsubName: pubsubName,
+			topic:      topic,
+		}
+		s.subs[key] = sub
+	}
+	return sub
+}
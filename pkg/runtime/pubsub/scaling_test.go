@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionStats(t *testing.T) {
+	now := time.Unix(1000, 0)
+	stats := NewSubscriptionStats()
+	stats.now = func() time.Time { return now }
+
+	assert.Empty(t, stats.Signals())
+
+	assert.Equal(t, int64(1), stats.DeliveryStarted("pubsub", "orders"))
+	assert.Equal(t, int64(2), stats.DeliveryStarted("pubsub", "orders"))
+	stats.DeliveryStarted("kafka", "payments")
+	assert.Equal(t, int64(1), stats.DeliveryCompleted("pubsub", "orders"))
+	now = now.Add(10 * time.Second)
+	stats.DeliveryCompleted("kafka", "payments")
+	stats.DeliveryStarted("kafka", "payments")
+	stats.DeliveryCompleted("kafka", "payments")
+
+	assert.Equal(t, []ScalingSignals{
+		{PubsubName: "kafka", Topic: "payments", PendingDeliveries: 0, ProcessingRate: 2.0 / 60},
+		{PubsubName: "pubsub", Topic: "orders", PendingDeliveries: 1, ProcessingRate: 1.0 / 60},
+	}, stats.Signals())
+
+	t.Run("the processing rate only counts the last minute", func(t *testing.T) {
+		now = now.Add(55 * time.Second)
+		signals := stats.Signals()
+		assert.Equal(t, 2.0/60, signals[0].ProcessingRate)
+		assert.Equal(t, 0.0, signals[1].ProcessingRate)
+
+		now = now.Add(2 * time.Minute)
+		stats.DeliveryCompleted("pubsub", "orders")
+		signals = stats.Signals()
+		assert.Equal(t, 0.0, signals[0].ProcessingRate)
+		assert.Equal(t, int64(0), signals[1].PendingDeliveries)
+		assert.Equal(t, 1.0/60, signals[1].ProcessingRate)
+	})
+}
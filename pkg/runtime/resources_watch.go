@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"github.com/dapr/dapr/pkg/components"
+	"github.com/dapr/dapr/pkg/fswatcher"
+)
+
+// watchResourcesPath watches each resources directory in self-hosted mode,
+// reloading the components and the subscriptions when a file is created or written.
+func (a *DaprRuntime) watchResourcesPath() {
+	eventCh := make(chan struct{})
+	for _, path := range a.runtimeConfig.Standalone.ResourcesPath {
+		go func(path string) {
+			log.Debugf("starting to watch the resources in %s", path)
+			if err := fswatcher.Watch(a.ctx, path, eventCh); err != nil {
+				log.Warnf("failed to watch the resources in %s: %s", path, err)
+			}
+		}(path)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-eventCh:
+				a.onResourcesPathUpdated()
+			case <-a.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// onResourcesPathUpdated loads the components of all the resources directories again, with their precedence,
+// and updates the ones that changed. Components are not deleted when their files are removed.
+func (a *DaprRuntime) onResourcesPathUpdated() {
+	comps, err := components.NewStandaloneComponents(a.runtimeConfig.Standalone).LoadComponents()
+	if err != nil {
+		log.Warnf("failed to reload components: %s", err)
+		return
+	}
+
+	for _, comp := range a.getAuthorizedComponents(comps) {
+		if a.onComponentUpdated(comp) {
+			log.Infof("component %s updated", comp.Name)
+		}
+	}
+	a.onSubscriptionsUpdated()
+}
@@ -23,6 +23,7 @@ import (
 	nethttp "net/http"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,6 +33,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	otlptracegrpc "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	otlptracehttp "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
@@ -40,18 +42,25 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
 	"go.opentelemetry.io/otel/trace"
+	grpcGo "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthv1pb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcMetadata "google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/dapr/dapr/pkg/acl"
 	"github.com/dapr/dapr/pkg/actors"
 	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	"github.com/dapr/dapr/pkg/apphealth"
 	"github.com/dapr/dapr/pkg/channel"
 	httpChannel "github.com/dapr/dapr/pkg/channel/http"
 	"github.com/dapr/dapr/pkg/components"
+	"github.com/dapr/dapr/pkg/compression"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diagUtils "github.com/dapr/dapr/pkg/diagnostics/utils"
@@ -64,8 +73,10 @@ import (
 	"github.com/dapr/dapr/pkg/modes"
 	"github.com/dapr/dapr/pkg/operator/client"
 	"github.com/dapr/dapr/pkg/resiliency"
+	"github.com/dapr/dapr/pkg/runtime/meta"
 	runtimePubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 	"github.com/dapr/dapr/pkg/runtime/security"
+	"github.com/dapr/dapr/pkg/runtime/workflow"
 	"github.com/dapr/dapr/pkg/scopes"
 	"github.com/dapr/dapr/utils"
 	"github.com/dapr/kit/logger"
@@ -75,12 +86,15 @@ import (
 
 	bindingsLoader "github.com/dapr/dapr/pkg/components/bindings"
 	configurationLoader "github.com/dapr/dapr/pkg/components/configuration"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
 	lockLoader "github.com/dapr/dapr/pkg/components/lock"
 	httpMiddlewareLoader "github.com/dapr/dapr/pkg/components/middleware/http"
 	nrLoader "github.com/dapr/dapr/pkg/components/nameresolution"
 	pubsubLoader "github.com/dapr/dapr/pkg/components/pubsub"
 	secretstoresLoader "github.com/dapr/dapr/pkg/components/secretstores"
 	stateLoader "github.com/dapr/dapr/pkg/components/state"
+	stateRouter "github.com/dapr/dapr/pkg/components/state/router"
+	"github.com/dapr/dapr/pkg/components/validation"
 
 	"github.com/dapr/components-contrib/bindings"
 	"github.com/dapr/components-contrib/configuration"
@@ -106,6 +120,15 @@ const (
 	// setting this environment variable restores the
 	// partial hot reloading support for k8s.
 	hotReloadingEnvVar = "DAPR_ENABLE_HOT_RELOADING"
+
+	// header (or gRPC metadata key) the app can set when responding to a pub/sub event to pause delivery for the topic.
+	pubsubPauseHeader = "dapr-pubsub-pause"
+	// maximum duration an app can pause delivery for a topic.
+	maxSubscriptionPause = time.Hour
+
+	// default topic of the actor lifecycle events, and prefix of their cloudevent type.
+	defaultActorLifecycleEventsTopic = "dapr-actor-lifecycle"
+	actorLifecycleEventTypePrefix    = "io.dapr.actor."
 )
 
 type ComponentCategory string
@@ -118,8 +141,14 @@ const (
 	middlewareComponent    ComponentCategory = "middleware"
 	configurationComponent ComponentCategory = "configuration"
 	lockComponent          ComponentCategory = "lock"
+	cryptoComponent        ComponentCategory = "crypto"
 
 	defaultComponentInitTimeout = time.Second * 5
+	subscriptionLagTimeout      = time.Second * 2
+
+	// minShutdownDrainDuration is the minimum time the APIs are kept open when the sidecar shuts down, as the app,
+	// which usually receives the termination signal at the same time, may still call them while it shuts down.
+	minShutdownDrainDuration = time.Second * 2
 )
 
 var componentCategoriesNeedProcess = []ComponentCategory{
@@ -130,6 +159,7 @@ var componentCategoriesNeedProcess = []ComponentCategory{
 	middlewareComponent,
 	configurationComponent,
 	lockComponent,
+	cryptoComponent,
 }
 
 var log = logger.NewLogger("dapr.runtime")
@@ -146,6 +176,12 @@ type TopicRouteElem struct {
 	deadLetterTopic string
 }
 
+// topicPause holds the state of a subscription that was paused by the app.
+type topicPause struct {
+	until time.Time
+	timer *time.Timer
+}
+
 // Type of function that determines if a component is authorized.
 // The function receives the component and must return true if the component is authorized.
 type ComponentAuthorizer func(component componentsV1alpha1.Component) bool
@@ -157,6 +193,8 @@ type DaprRuntime struct {
 	runtimeConfig          *Config
 	globalConfig           *config.Configuration
 	accessControlList      *config.AccessControlList
+	crossNamespacePolicy   *acl.CrossNamespacePolicy
+	outboundPolicy         *acl.OutboundPolicy
 	componentsLock         *sync.RWMutex
 	components             []componentsV1alpha1.Component
 	grpc                   *grpc.Manager
@@ -168,15 +206,21 @@ type DaprRuntime struct {
 	nameResolutionRegistry *nrLoader.Registry
 	stateStores            map[string]state.Store
 	actor                  actors.Actors
+	workflowRegistry       *workflow.Registry
+	workflowEngine         *workflow.Engine
 	bindingsRegistry       *bindingsLoader.Registry
 	subscribeBindingList   []string
 	inputBindings          map[string]bindings.InputBinding
 	outputBindings         map[string]bindings.OutputBinding
 	inputBindingsCtx       context.Context
 	inputBindingsCancel    context.CancelFunc
+	inputBindingsLock      *sync.Mutex
+	inputBindingCancels    map[string]context.CancelFunc // Key is the name of the input binding, the value is nil while it's paused
+	pausedInputBindings    map[string]struct{}           // Key is the name of the input binding
 	secretStores           map[string]secretstores.SecretStore
 	pubSubRegistry         *pubsubLoader.Registry
 	pubSubs                map[string]pubsubItem // Key is "componentName"
+	subscriptionStats      *runtimePubsub.SubscriptionStats
 	nameResolver           nr.Resolver
 	httpMiddlewareRegistry *httpMiddlewareLoader.Registry
 	hostAddress            string
@@ -190,13 +234,28 @@ type DaprRuntime struct {
 	pubsubCtx              context.Context
 	pubsubCancel           context.CancelFunc
 	topicsLock             *sync.Mutex
+	subsReloadLock         *sync.Mutex
 	topicRoutes            map[string]TopicRoutes        // Key is "componentName"
 	topicCtxCancels        map[string]context.CancelFunc // Key is "componentName||topicName"
+	topicPauses            map[string]*topicPause        // Key is "componentName||topicName"
+	subscriptions          []runtimePubsub.Subscription
+	subscriptionsLock      *sync.RWMutex
+	componentStatuses      *meta.ComponentStatusStore
+	metadataAttributes     *meta.AttributeStore
+	driftLock              *sync.RWMutex
+	componentVersions      map[string]string // Key is the name of the component
+	drift                  *meta.Drift
 	inputBindingRoutes     map[string]string
+	inputBindingLimiters   map[string]*bindingLimiter
+	inputBindingPartitions map[string]*bindingPartitioner
 	shutdownC              chan error
 	apiClosers             []io.Closer
+	grpcHealth             *health.Server
+	appCalls               inflightCalls
 	componentAuthorizers   []ComponentAuthorizer
 	appHealth              *apphealth.AppHealth
+	appConnection          *grpcGo.ClientConn
+	apiListeners           []net.Listener
 
 	secretsConfiguration map[string]config.SecretsScope
 
@@ -206,14 +265,31 @@ type DaprRuntime struct {
 	lockStoreRegistry *lockLoader.Registry
 	lockStores        map[string]lock.Store
 
-	pendingComponents          chan componentsV1alpha1.Component
-	pendingComponentDependents map[string][]componentsV1alpha1.Component
+	cryptoProviderRegistry *cryptoLoader.Registry
+	cryptoProviders        map[string]cryptoLoader.SubtleCrypto
+
+	pendingComponents              chan componentsV1alpha1.Component
+	pendingComponentDependents     map[string][]componentsV1alpha1.Component
+	pendingComponentDependentsLock sync.Mutex
+	// Held while components are processed, so the parallel initialization at startup excludes the updates.
+	componentInitLock sync.Mutex
+	// Names of the optional components.
+	optionalComponents map[string]struct{}
+	// Optional components processed once the sidecar is ready.
+	deferredComponents []componentsV1alpha1.Component
+	// Retries of the failed component initializations, by component name.
+	componentInitRetries     map[string]*componentInitRetry
+	componentInitRetriesLock sync.Mutex
 
 	proxy messaging.Proxy
 
 	resiliency resiliency.Provider
 
-	tracerProvider *sdktrace.TracerProvider
+	tracerProvider  *sdktrace.TracerProvider
+	metricsExporter *diag.OTLPMetricsExporter
+	auditor         *diag.Auditor
+	devConsole      *diag.DevConsole
+	egressProxy     *egressProxy
 }
 
 type ComponentsCallback func(components ComponentRegistry) error
@@ -246,6 +322,7 @@ type pubsubItem struct {
 	scopedSubscriptions []string
 	scopedPublishings   []string
 	allowedTopics       []string
+	topicAliases        map[string]string
 }
 
 // NewDaprRuntime returns a new runtime with the given runtime config and global config.
@@ -263,26 +340,48 @@ func NewDaprRuntime(runtimeConfig *Config, globalConfig *config.Configuration, a
 		grpc:                       grpc.NewGRPCManager(runtimeConfig.Mode),
 		inputBindings:              map[string]bindings.InputBinding{},
 		outputBindings:             map[string]bindings.OutputBinding{},
+		inputBindingsLock:          &sync.Mutex{},
+		pausedInputBindings:        map[string]struct{}{},
 		secretStores:               map[string]secretstores.SecretStore{},
 		stateStores:                map[string]state.Store{},
 		pubSubs:                    map[string]pubsubItem{},
 		topicsLock:                 &sync.Mutex{},
+		subscriptionsLock:          &sync.RWMutex{},
+		componentStatuses:          meta.NewComponentStatusStore(),
+		metadataAttributes:         meta.NewAttributeStore(),
+		driftLock:                  &sync.RWMutex{},
+		componentVersions:          map[string]string{},
+		subsReloadLock:             &sync.Mutex{},
+		topicPauses:                map[string]*topicPause{},
+		subscriptionStats:          runtimePubsub.NewSubscriptionStats(),
 		inputBindingRoutes:         map[string]string{},
+		inputBindingLimiters:       map[string]*bindingLimiter{},
+		inputBindingPartitions:     map[string]*bindingPartitioner{},
 		secretsConfiguration:       map[string]config.SecretsScope{},
 		configurationStores:        map[string]configuration.Store{},
 		lockStores:                 map[string]lock.Store{},
+		cryptoProviders:            map[string]cryptoLoader.SubtleCrypto{},
 		pendingComponents:          make(chan componentsV1alpha1.Component),
 		pendingComponentDependents: map[string][]componentsV1alpha1.Component{},
 		shutdownC:                  make(chan error, 1),
+		grpcHealth:                 newGRPCHealth(),
 		tracerProvider:             nil,
 		resiliency:                 resiliencyProvider,
 	}
 
+	rt.grpc.SetConnectionConfig(runtimeConfig.InternalGRPCConnection)
+
 	rt.componentAuthorizers = []ComponentAuthorizer{rt.namespaceComponentAuthorizer}
 	if globalConfig != nil && len(globalConfig.Spec.ComponentsSpec.Deny) > 0 {
 		dl := newComponentDenyList(globalConfig.Spec.ComponentsSpec.Deny)
 		rt.componentAuthorizers = append(rt.componentAuthorizers, dl.IsAllowed)
 	}
+	if globalConfig != nil && len(globalConfig.Spec.ComponentsSpec.Optional) > 0 {
+		rt.optionalComponents = make(map[string]struct{}, len(globalConfig.Spec.ComponentsSpec.Optional))
+		for _, name := range globalConfig.Spec.ComponentsSpec.Optional {
+			rt.optionalComponents[name] = struct{}{}
+		}
+	}
 
 	return rt
 }
@@ -310,10 +409,18 @@ func (a *DaprRuntime) Run(opts ...Option) error {
 		// gRPC server start failure is logged as Fatal in initRuntime method. Setting the status only when runtime is initialized.
 		a.daprHTTPAPI.MarkStatusAsReady()
 	}
+	a.grpcHealth.SetServingStatus("", healthv1pb.HealthCheckResponse_SERVING)
 
 	return nil
 }
 
+// newGRPCHealth returns the gRPC health service of the sidecar, which is not serving until the runtime is initialized.
+func newGRPCHealth() *health.Server {
+	h := health.NewServer()
+	h.SetServingStatus("", healthv1pb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
 func (a *DaprRuntime) getNamespace() string {
 	return os.Getenv("NAMESPACE")
 }
@@ -336,6 +443,10 @@ func (a *DaprRuntime) getOperatorClient() (operatorv1pb.OperatorClient, error) {
 // setupTracing set up the trace exporters. Technically we don't need to pass `hostAddress` in,
 // but we do so here to explicitly call out the dependency on having `hostAddress` computed.
 func (a *DaprRuntime) setupTracing(hostAddress string, tpStore tracerProviderStore) error {
+	if err := diag.SetTracePropagators(a.globalConfig.Spec.TracingSpec.Propagators); err != nil {
+		return err
+	}
+
 	// Register stdout trace exporter if user wants to debug requests or log as Info level.
 	if a.globalConfig.Spec.TracingSpec.Stdout {
 		tpStore.RegisterExporter(diagUtils.NewStdOutExporter())
@@ -358,18 +469,33 @@ func (a *DaprRuntime) setupTracing(hostAddress string, tpStore tracerProviderSto
 			return fmt.Errorf("invalid protocol %v provided for Otel endpoint", protocol)
 		}
 		isSecure := a.globalConfig.Spec.TracingSpec.Otel.IsSecure
+		headers := a.globalConfig.Spec.TracingSpec.Otel.Headers
+		tlsConfig, err := a.globalConfig.Spec.TracingSpec.Otel.GetTLSConfig()
+		if err != nil {
+			return err
+		}
 
 		var client otlptrace.Client
 		if protocol == "http" {
 			clientOptions := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
 			if !isSecure {
 				clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+			} else if tlsConfig != nil {
+				clientOptions = append(clientOptions, otlptracehttp.WithTLSClientConfig(tlsConfig))
+			}
+			if len(headers) > 0 {
+				clientOptions = append(clientOptions, otlptracehttp.WithHeaders(headers))
 			}
 			client = otlptracehttp.NewClient(clientOptions...)
 		} else {
 			clientOptions := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
 			if !isSecure {
 				clientOptions = append(clientOptions, otlptracegrpc.WithInsecure())
+			} else if tlsConfig != nil {
+				clientOptions = append(clientOptions, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+			}
+			if len(headers) > 0 {
+				clientOptions = append(clientOptions, otlptracegrpc.WithHeaders(headers))
 			}
 			client = otlptracegrpc.NewClient(clientOptions...)
 		}
@@ -380,33 +506,105 @@ func (a *DaprRuntime) setupTracing(hostAddress string, tpStore tracerProviderSto
 		tpStore.RegisterExporter(otelExporter)
 	}
 
-	// Register a resource
-	r := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceNameKey.String(a.runtimeConfig.ID),
-	)
+	// Register a resource, with the static attributes of the configuration overridden by the ones of the sidecar
+	attrs := []attribute.KeyValue{semconv.ServiceNameKey.String(a.runtimeConfig.ID)}
+	for k, v := range a.globalConfig.Spec.TracingSpec.Attributes {
+		if _, ok := a.runtimeConfig.TraceAttributes[k]; !ok {
+			attrs = append(attrs, attribute.String(k, v))
+		}
+	}
+	for k, v := range a.runtimeConfig.TraceAttributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	r := resource.NewWithAttributes(semconv.SchemaURL, attrs...)
 
 	tpStore.RegisterResource(r)
 
-	// Register a trace sampler based on Sampling settings
-	tpStore.RegisterSampler(diagUtils.TraceSampler(a.globalConfig.Spec.TracingSpec.SamplingRate))
+	// The metadata attributes set by the app are added to all the spans, as the resource can't change after it's registered
+	tpStore.RegisterSpanProcessor(newMetadataAttributesSpanProcessor(a.metadataAttributes))
+
+	// Register a trace sampler based on Sampling settings, which samples all the requests to the components with detailed tracing enabled
+	tpStore.RegisterSampler(diag.NewComponentTracingSampler(diagUtils.TraceSampler(a.globalConfig.Spec.TracingSpec.SamplingRate)))
 
 	tpStore.RegisterTracerProvider()
 
 	return nil
 }
 
+// setupMetricsExporter starts exporting the metrics to the OpenTelemetry collector, if one is configured.
+func (a *DaprRuntime) setupMetricsExporter() error {
+	otelSpec := a.globalConfig.Spec.MetricSpec.Otel
+	if otelSpec == nil || otelSpec.EndpointAddress == "" {
+		return nil
+	}
+
+	tlsConfig, err := otelSpec.GetTLSConfig()
+	if err != nil {
+		return err
+	}
+	exporter, err := diag.NewOTLPMetricsExporter(diag.OTLPMetricsExporterOptions{
+		AppID:     a.runtimeConfig.ID,
+		Protocol:  otelSpec.Protocol,
+		Endpoint:  otelSpec.EndpointAddress,
+		Insecure:  !otelSpec.IsSecure,
+		TLSConfig: tlsConfig,
+		Headers:   otelSpec.Headers,
+	})
+	if err != nil {
+		return err
+	}
+	if err = exporter.Start(); err != nil {
+		exporter.Close()
+		return err
+	}
+	a.metricsExporter = exporter
+	log.Infof("exporting metrics to the Otel collector at %s", otelSpec.EndpointAddress)
+	return nil
+}
+
 func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	a.namespace = a.getNamespace()
 
+	crossNamespacePolicy, err := acl.ParseCrossNamespaceSpec(a.globalConfig.Spec.AccessControlSpec.CrossNamespace, a.namespace, a.getTrustDomain())
+	if err != nil {
+		return err
+	}
+	a.crossNamespacePolicy = crossNamespacePolicy
+	outboundPolicy, err := acl.ParseOutboundSpec(a.globalConfig.Spec.AccessControlSpec.Outbound, a.namespace)
+	if err != nil {
+		return err
+	}
+	a.outboundPolicy = outboundPolicy
+
 	// Initialize metrics only if MetricSpec is enabled.
 	if a.globalConfig.Spec.MetricSpec.Enabled {
-		if err := diag.InitMetrics(a.runtimeConfig.ID, a.namespace); err != nil {
+		if err := diag.InitMetrics(a.runtimeConfig.ID, a.namespace, a.globalConfig.Spec.MetricSpec.GetPathTemplates(), a.globalConfig.Spec.MetricSpec.Rules, a.globalConfig.Spec.MetricSpec.PayloadSchema); err != nil {
 			log.Errorf("failed to initialize metrics: %v", err)
 		}
+		if err := a.setupMetricsExporter(); err != nil {
+			return errors.Wrap(err, "failed to setup the metrics exporter")
+		}
+	}
+
+	if auditSpec := a.globalConfig.Spec.AuditSpec; auditSpec.Enabled {
+		auditor, err := diag.NewAuditor(a.runtimeConfig.ID, auditSpec)
+		if err != nil {
+			return errors.Wrap(err, "failed to setup the audit log")
+		}
+		a.auditor = auditor
+		log.Info("audit log enabled")
+	}
+
+	if a.runtimeConfig.EnableDevConsole {
+		if a.runtimeConfig.Mode == modes.KubernetesMode {
+			log.Warn("the developer console is not available in Kubernetes mode")
+		} else {
+			a.devConsole = diag.NewDevConsole(a.runtimeConfig.ID)
+			log.Warn("developer console enabled: do not use it in production")
+		}
 	}
 
-	err := a.establishSecurity(a.runtimeConfig.SentryServiceAddress)
+	err = a.establishSecurity(a.runtimeConfig.SentryServiceAddress)
 	if err != nil {
 		return err
 	}
@@ -428,6 +626,10 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	if err != nil {
 		log.Warnf("failed to init name resolution: %s", err)
 	}
+	if err = a.grpc.SetLoadBalancing(a.globalConfig.Spec.NameResolutionSpec.LoadBalancing, a.hostAddress); err != nil {
+		return errors.Wrap(err, "failed to set load balancing")
+	}
+	go a.grpc.CloseIdleConnections(a.ctx)
 
 	a.pubSubRegistry = opts.pubsubRegistry
 	a.secretStoresRegistry = opts.secretStoreRegistry
@@ -436,6 +638,14 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	a.bindingsRegistry = opts.bindingRegistry
 	a.httpMiddlewareRegistry = opts.httpMiddlewareRegistry
 	a.lockStoreRegistry = opts.lockRegistry
+	a.cryptoProviderRegistry = opts.cryptoRegistry
+	a.workflowRegistry = opts.workflowRegistry
+	a.appConnection = opts.appConnection
+	a.apiListeners = opts.apiListeners
+
+	if err = a.initEgressProxy(); err != nil {
+		return errors.Wrap(err, "failed to setup the outbound proxy")
+	}
 
 	go a.processComponents()
 
@@ -445,7 +655,13 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 		if err != nil {
 			log.Warnf("failed to watch component updates: %s", err)
 		}
+		log.Debug("starting to watch subscription updates")
+		err = a.beginSubscriptionsUpdates()
+		if err != nil {
+			log.Warnf("failed to watch subscription updates: %s", err)
+		}
 	}
+	a.startDriftDetection()
 
 	a.appendBuiltinSecretStore()
 	err = a.loadComponents(opts)
@@ -454,6 +670,7 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	}
 
 	a.flushOutstandingComponents()
+	a.processDeferredComponents()
 
 	pipeline, err := a.buildHTTPPipeline()
 	if err != nil {
@@ -497,9 +714,21 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	}
 	log.Infof("internal gRPC server is running on port %v", a.runtimeConfig.InternalGRPCPort)
 
+	if a.runtimeConfig.PublicGRPCPort != nil {
+		err = a.startGRPCHealthServer(*a.runtimeConfig.PublicGRPCPort)
+		if err != nil {
+			log.Fatalf("failed to start public gRPC health server: %s", err)
+		}
+		log.Infof("public gRPC health server is running on port %v", *a.runtimeConfig.PublicGRPCPort)
+	}
+
 	if a.daprHTTPAPI != nil {
+		a.daprHTTPAPI.SetRuntimeMetadataFn(a.getRuntimeMetadata)
+		a.daprHTTPAPI.SetSubscriptionScalingFn(a.getSubscriptionScalingSignals)
+		a.daprHTTPAPI.SetInputBindingPauser(a)
 		a.daprHTTPAPI.MarkStatusAsOutboundReady()
 	}
+	grpcAPI.SetRuntimeMetadataFn(a.getRuntimeMetadata)
 
 	a.blockUntilAppIsReady()
 
@@ -524,6 +753,10 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 		} else {
 			a.daprHTTPAPI.SetActorRuntime(a.actor)
 			grpcAPI.SetActorRuntime(a.actor)
+			if a.workflowEngine != nil {
+				a.daprHTTPAPI.SetWorkflowEngine(a.workflowEngine)
+				grpcAPI.SetWorkflowEngine(a.workflowEngine)
+			}
 		}
 	}
 
@@ -545,6 +778,22 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 		}
 	}
 
+	if a.runtimeConfig.AppStartupProbe != nil && a.appChannel != nil {
+		// Wait for the app to be ready in background, so the app can use the Dapr APIs while it's warming up
+		go func() {
+			if a.waitForAppStartup(a.ctx) {
+				a.initAppHealth()
+			}
+		}()
+	} else {
+		a.initAppHealth()
+	}
+
+	return nil
+}
+
+// initAppHealth starts the health checks of the app, which start the input components once the app is healthy.
+func (a *DaprRuntime) initAppHealth() {
 	if a.runtimeConfig.AppHealthCheck != nil && a.appChannel != nil {
 		a.appHealth = apphealth.NewAppHealth(a.runtimeConfig.AppHealthCheck, a.appChannel.HealthProbe)
 		a.appHealth.OnHealthChange(a.appHealthChanged)
@@ -560,8 +809,57 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 		// If there's no health check, mark the app as healthy right away so subscriptions can start
 		a.appHealthChanged(apphealth.AppStatusHealthy)
 	}
+}
+
+// waitForAppStartup probes the app until it reports that it is ready to receive events, or the startup probe times out.
+// It returns false if the runtime is shutting down.
+func (a *DaprRuntime) waitForAppStartup(ctx context.Context) bool {
+	probe := a.runtimeConfig.AppStartupProbe
+	if probe.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, probe.Timeout)
+		defer cancel()
+	}
 
-	return nil
+	log.Infof("waiting for the app to be ready with the startup probe %s before delivering events", probe.Method)
+
+	ticker := time.NewTicker(probe.Interval)
+	defer ticker.Stop()
+	for {
+		if a.probeAppStartup(ctx, probe) {
+			log.Info("app is ready: starting the delivery of events")
+			return true
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if a.ctx.Err() != nil {
+				return false
+			}
+			log.Warnf("app is not ready after %s: starting the delivery of events anyway", probe.Timeout)
+			return true
+		}
+	}
+}
+
+func (a *DaprRuntime) probeAppStartup(ctx context.Context, probe *AppStartupProbeConfig) bool {
+	ctx, cancel := context.WithTimeout(ctx, probe.Interval)
+	defer cancel()
+
+	req := invokev1.NewInvokeMethodRequest(strings.TrimPrefix(probe.Method, "/")).
+		WithHTTPExtension(nethttp.MethodGet, "")
+	resp, err := a.appChannel.InvokeMethod(ctx, req)
+	if err != nil {
+		log.Debugf("startup probe of the app failed: %s", err)
+		return false
+	}
+
+	code := resp.Status().Code
+	if resp.IsHTTPResponse() {
+		return code >= 200 && code < 300
+	}
+	return code == int32(codes.OK)
 }
 
 // Sets the status of the app to healthy or un-healthy
@@ -589,27 +887,46 @@ func (a *DaprRuntime) populateSecretsConfiguration() {
 	}
 }
 
+// Names of the HTTP pipelines, used in the logs, spans and metrics of their middleware.
+const (
+	httpPipelineName    = "http"
+	appHTTPPipelineName = "app"
+)
+
 func (a *DaprRuntime) buildHTTPPipeline() (httpMiddleware.Pipeline, error) {
+	if a.globalConfig == nil {
+		return httpMiddleware.Pipeline{}, nil
+	}
+	return a.buildHTTPPipelineForSpec(a.globalConfig.Spec.HTTPPipelineSpec, httpPipelineName)
+}
+
+// buildAppHTTPPipeline builds the pipeline applied by the app channel to the requests sent to the app.
+func (a *DaprRuntime) buildAppHTTPPipeline() (httpMiddleware.Pipeline, error) {
+	if a.globalConfig == nil {
+		return httpMiddleware.Pipeline{}, nil
+	}
+	return a.buildHTTPPipelineForSpec(a.globalConfig.Spec.AppHTTPPipelineSpec, appHTTPPipelineName)
+}
+
+func (a *DaprRuntime) buildHTTPPipelineForSpec(spec config.PipelineSpec, targetPipeline string) (httpMiddleware.Pipeline, error) {
 	var handlers []httpMiddleware.Middleware
 
-	if a.globalConfig != nil {
-		for i := 0; i < len(a.globalConfig.Spec.HTTPPipelineSpec.Handlers); i++ {
-			middlewareSpec := a.globalConfig.Spec.HTTPPipelineSpec.Handlers[i]
-			component, exists := a.getComponent(middlewareSpec.Type, middlewareSpec.Name)
-			if !exists {
-				return httpMiddleware.Pipeline{}, errors.Errorf("couldn't find middleware component with name %s and type %s/%s",
-					middlewareSpec.Name,
-					middlewareSpec.Type,
-					middlewareSpec.Version)
-			}
-			handler, err := a.httpMiddlewareRegistry.Create(middlewareSpec.Type, middlewareSpec.Version,
-				middleware.Metadata{Base: contribMetadata.Base{Properties: a.convertMetadataItemsToProperties(component.Spec.Metadata)}})
-			if err != nil {
-				return httpMiddleware.Pipeline{}, err
-			}
-			log.Infof("enabled %s/%s http middleware", middlewareSpec.Type, middlewareSpec.Version)
-			handlers = append(handlers, handler)
+	for i := 0; i < len(spec.Handlers); i++ {
+		middlewareSpec := spec.Handlers[i]
+		component, exists := a.getComponent(middlewareSpec.Type, middlewareSpec.Name)
+		if !exists {
+			return httpMiddleware.Pipeline{}, errors.Errorf("couldn't find middleware component with name %s and type %s/%s",
+				middlewareSpec.Name,
+				middlewareSpec.Type,
+				middlewareSpec.Version)
+		}
+		handler, err := a.httpMiddlewareRegistry.Create(middlewareSpec.Type, middlewareSpec.Version,
+			middleware.Metadata{Base: contribMetadata.Base{Properties: a.convertMetadataItemsToProperties(component.Spec.Metadata)}})
+		if err != nil {
+			return httpMiddleware.Pipeline{}, err
 		}
+		log.Infof("enabled %s/%s %s middleware", middlewareSpec.Type, middlewareSpec.Version, targetPipeline)
+		handlers = append(handlers, diag.InstrumentHTTPMiddleware(middlewareSpec.Name, middlewareSpec.Type, targetPipeline, handler))
 	}
 	return httpMiddleware.Pipeline{Handlers: handlers}, nil
 }
@@ -659,7 +976,12 @@ func (a *DaprRuntime) subscribeTopic(parentCtx context.Context, name string, top
 	a.topicsLock.Lock()
 	defer a.topicsLock.Unlock()
 
-	log.Debugf("subscribing to topic='%s' on pubsub='%s'", topic, name)
+	physicalTopic := a.pubSubs[name].physicalTopic(topic)
+	if physicalTopic != topic {
+		log.Debugf("subscribing to topic='%s' (alias of topic='%s') on pubsub='%s'", physicalTopic, topic, name)
+	} else {
+		log.Debugf("subscribing to topic='%s' on pubsub='%s'", topic, name)
+	}
 
 	if _, ok := a.topicCtxCancels[subKey]; ok {
 		return fmt.Errorf("cannot subscribe to topic '%s' on pubsub '%s': the subscription already exists", topic, name)
@@ -668,7 +990,7 @@ func (a *DaprRuntime) subscribeTopic(parentCtx context.Context, name string, top
 	ctx, cancel := context.WithCancel(parentCtx)
 	policy := a.resiliency.ComponentInboundPolicy(ctx, name, resiliency.Pubsub)
 	err := a.pubSubs[name].component.Subscribe(ctx, pubsub.SubscribeRequest{
-		Topic:    topic,
+		Topic:    physicalTopic,
 		Metadata: route.metadata,
 	}, func(ctx context.Context, msg *pubsub.NewMessage) error {
 		if msg.Metadata == nil {
@@ -677,7 +999,13 @@ func (a *DaprRuntime) subscribeTopic(parentCtx context.Context, name string, top
 
 		msg.Metadata[pubsubName] = name
 
-		rawPayload, err := contribMetadata.IsRawPayload(route.metadata)
+		// The app receives the events with the logical topic name it subscribed to
+		eventTopic := msg.Topic
+		if physicalTopic != topic {
+			eventTopic = topic
+		}
+
+		rawPayload, err := runtimePubsub.IsRawPayload(route.metadata, msg.Data)
 		if err != nil {
 			log.Errorf("error deserializing pubsub metadata: %s", err)
 			if route.deadLetterTopic != "" {
@@ -694,7 +1022,7 @@ func (a *DaprRuntime) subscribeTopic(parentCtx context.Context, name string, top
 		var cloudEvent map[string]interface{}
 		data := msg.Data
 		if rawPayload {
-			cloudEvent = pubsub.FromRawPayload(msg.Data, msg.Topic, name)
+			cloudEvent = pubsub.FromRawPayload(msg.Data, eventTopic, name)
 			data, err = json.Marshal(cloudEvent)
 			if err != nil {
 				log.Errorf("error serializing cloud event in pubsub %s and topic %s: %s", name, msg.Topic, err)
@@ -712,6 +1040,7 @@ func (a *DaprRuntime) subscribeTopic(parentCtx context.Context, name string, top
 			err = json.Unmarshal(msg.Data, &cloudEvent)
 			if err != nil {
 				log.Errorf("error deserializing cloud event in pubsub %s and topic %s: %s", name, msg.Topic, err)
+				diag.DefaultComponentMonitoring.PubsubIngressPayload(ctx, name, msg.Topic, diag.MalformedPayloadSchema, len(msg.Data))
 				if route.deadLetterTopic != "" {
 					if dlqErr := a.sendToDeadLetter(name, msg, route.deadLetterTopic); dlqErr == nil {
 						// dlq has been configured and message is successfully sent to dlq.
@@ -724,6 +1053,8 @@ func (a *DaprRuntime) subscribeTopic(parentCtx context.Context, name string, top
 			}
 		}
 
+		diag.DefaultComponentMonitoring.PubsubIngressPayload(ctx, name, msg.Topic, runtimePubsub.DataSchema(cloudEvent), len(msg.Data))
+
 		if pubsub.HasExpired(cloudEvent) {
 			log.Warnf("dropping expired pub/sub event %v as of %v", cloudEvent[pubsub.IDField], cloudEvent[pubsub.ExpirationField])
 			diag.DefaultComponentMonitoring.PubsubIngressEvent(ctx, pubsubName, strings.ToLower(string(pubsub.Drop)), msg.Topic, 0)
@@ -734,7 +1065,7 @@ func (a *DaprRuntime) subscribeTopic(parentCtx context.Context, name string, top
 			return nil
 		}
 
-		routePath, shouldProcess, err := findMatchingRoute(route.rules, cloudEvent)
+		routePath, shouldProcess, err := findMatchingRoute(route.rules, cloudEvent, msg.Metadata)
 		if err != nil {
 			log.Errorf("error finding matching route for event %v in pubsub %s and topic %s: %s", cloudEvent[pubsub.IDField], name, msg.Topic, err)
 			if route.deadLetterTopic != "" {
@@ -757,23 +1088,24 @@ func (a *DaprRuntime) subscribeTopic(parentCtx context.Context, name string, top
 			return nil
 		}
 
+		diag.DefaultComponentMonitoring.PubsubIngressPending(ctx, name, topic, a.subscriptionStats.DeliveryStarted(name, topic))
+		defer func() {
+			diag.DefaultComponentMonitoring.PubsubIngressPending(ctx, name, topic, a.subscriptionStats.DeliveryCompleted(name, topic))
+		}()
+
 		err = policy(func(ctx context.Context) error {
 			psm := &pubsubSubscribedMessage{
 				cloudEvent: cloudEvent,
 				data:       data,
-				topic:      msg.Topic,
+				topic:      eventTopic,
 				metadata:   msg.Metadata,
 				path:       routePath,
 				pubsub:     name,
 			}
-			switch a.runtimeConfig.ApplicationProtocol {
-			case HTTPProtocol:
-				return a.publishMessageHTTP(ctx, psm)
-			case GRPCProtocol:
-				return a.publishMessageGRPC(ctx, psm)
-			default:
-				return backoff.Permanent(errors.New("invalid application protocol"))
-			}
+			start := time.Now()
+			err := a.sendPubSubMessageToApp(ctx, psm)
+			a.recordPubSubDelivery(psm, start, err)
+			return err
 		})
 		if err != nil && err != context.Canceled {
 			// Sending msg to dead letter queue.
@@ -813,6 +1145,136 @@ func (a *DaprRuntime) unsubscribeTopic(name string, topic string) error {
 	return nil
 }
 
+// PauseSubscription stops delivering messages for the topic until the duration elapses or ResumeSubscription is called.
+// Pausing a topic that is already paused resets the pause to the new duration.
+func (a *DaprRuntime) PauseSubscription(pubsubName, topic string, duration time.Duration) error {
+	if duration <= 0 {
+		return errors.Errorf("invalid pause duration for topic '%s' on pubsub '%s': %v", topic, pubsubName, duration)
+	}
+	if duration > maxSubscriptionPause {
+		duration = maxSubscriptionPause
+	}
+
+	a.topicsLock.Lock()
+	defer a.topicsLock.Unlock()
+
+	subKey := pubsubTopicKey(pubsubName, topic)
+	if p, ok := a.topicPauses[subKey]; ok {
+		p.timer.Stop()
+	} else {
+		cancel, ok := a.topicCtxCancels[subKey]
+		if !ok {
+			return fmt.Errorf("cannot pause topic '%s' on pubsub '%s': the subscription does not exist", topic, pubsubName)
+		}
+		if cancel != nil {
+			cancel()
+		}
+		delete(a.topicCtxCancels, subKey)
+	}
+
+	p := &topicPause{
+		until: time.Now().Add(duration),
+	}
+	p.timer = time.AfterFunc(duration, func() {
+		if err := a.resumeSubscription(pubsubName, topic, p); err != nil {
+			log.Errorf("error resuming paused subscription: %s", err)
+		}
+	})
+	a.topicPauses[subKey] = p
+
+	log.Infof("paused delivery for topic '%s' on pubsub '%s' for %v", topic, pubsubName, duration)
+	return nil
+}
+
+// ResumeSubscription resumes delivering messages for a topic that was paused with PauseSubscription.
+func (a *DaprRuntime) ResumeSubscription(pubsubName, topic string) error {
+	return a.resumeSubscription(pubsubName, topic, nil)
+}
+
+// resumeSubscription re-subscribes to a paused topic.
+// If expected is not nil, the topic is resumed only if it's still paused by that same pause.
+func (a *DaprRuntime) resumeSubscription(pubsubName, topic string, expected *topicPause) error {
+	subKey := pubsubTopicKey(pubsubName, topic)
+
+	a.topicsLock.Lock()
+	p, ok := a.topicPauses[subKey]
+	if !ok || (expected != nil && p != expected) {
+		a.topicsLock.Unlock()
+		if expected != nil {
+			return nil
+		}
+		return fmt.Errorf("cannot resume topic '%s' on pubsub '%s': the subscription is not paused", topic, pubsubName)
+	}
+	p.timer.Stop()
+	delete(a.topicPauses, subKey)
+	ctx := a.pubsubCtx
+	route, hasRoute := a.topicRoutes[pubsubName][topic]
+	a.topicsLock.Unlock()
+
+	if ctx == nil || !hasRoute {
+		// Subscriptions have been stopped in the meanwhile
+		return nil
+	}
+
+	log.Infof("resuming delivery for topic '%s' on pubsub '%s'", topic, pubsubName)
+	return a.subscribeTopic(ctx, pubsubName, topic, route)
+}
+
+// PausedSubscriptions returns the list of subscriptions that are currently paused.
+func (a *DaprRuntime) PausedSubscriptions() []runtimePubsub.PausedSubscription {
+	a.topicsLock.Lock()
+	defer a.topicsLock.Unlock()
+
+	res := make([]runtimePubsub.PausedSubscription, 0, len(a.topicPauses))
+	for subKey, p := range a.topicPauses {
+		pubsubName, topic, _ := strings.Cut(subKey, "||")
+		res = append(res, runtimePubsub.PausedSubscription{
+			PubsubName:  pubsubName,
+			Topic:       topic,
+			PausedUntil: p.until,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].PubsubName != res[j].PubsubName {
+			return res[i].PubsubName < res[j].PubsubName
+		}
+		return res[i].Topic < res[j].Topic
+	})
+	return res
+}
+
+// clearTopicPauses stops the timers of all paused subscriptions and forgets them.
+func (a *DaprRuntime) clearTopicPauses() {
+	a.topicsLock.Lock()
+	defer a.topicsLock.Unlock()
+
+	for _, p := range a.topicPauses {
+		p.timer.Stop()
+	}
+	a.topicPauses = map[string]*topicPause{}
+}
+
+// handlePauseRequest pauses the subscription if the app asked for it while responding to a message.
+// The value is the duration of the pause, for example "30s".
+func (a *DaprRuntime) handlePauseRequest(pubsubName, topic, value string) {
+	if value == "" {
+		return
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Warnf("ignoring invalid value for %s returned by the app for topic '%s' on pubsub '%s': %s", pubsubPauseHeader, topic, pubsubName, err)
+		return
+	}
+
+	// Pause asynchronously, as this cancels the context of the subscription the message is delivered on
+	go func() {
+		if err := a.PauseSubscription(pubsubName, topic, duration); err != nil {
+			log.Warnf("error pausing subscription requested by the app: %s", err)
+		}
+	}()
+}
+
 func (a *DaprRuntime) beginPubSub(name string) error {
 	topicRoutes, err := a.getTopicRoutes()
 	if err != nil {
@@ -837,11 +1299,17 @@ func (a *DaprRuntime) beginPubSub(name string) error {
 
 // findMatchingRoute selects the path based on routing rules. If there are
 // no matching rules, the route-level path is used.
-func findMatchingRoute(rules []*runtimePubsub.Rule, cloudEvent interface{}) (path string, shouldProcess bool, err error) {
+// findMatchingRoute returns the path of the first rule matching the message.
+// The expressions of the rules can use the cloud event as `event` and the metadata of the message as `metadata`.
+func findMatchingRoute(rules []*runtimePubsub.Rule, cloudEvent interface{}, metadata map[string]string) (path string, shouldProcess bool, err error) {
 	hasRules := len(rules) > 0
 	if hasRules {
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
 		data := map[string]interface{}{
-			"event": cloudEvent,
+			"event":    cloudEvent,
+			"metadata": metadata,
 		}
 		rule, err := matchRoutingRule(rules, data)
 		if err != nil {
@@ -891,24 +1359,42 @@ func (a *DaprRuntime) initDirectMessaging(resolver nr.Resolver) {
 		ReadBufferSize:      a.runtimeConfig.ReadBufferSize,
 		Resiliency:          a.resiliency,
 		IsResiliencyEnabled: config.IsFeatureEnabled(a.globalConfig.Spec.Features, config.Resiliency),
+		OutboundPolicy:      a.outboundPolicy,
+		Clusters:            a.globalConfig.Spec.NameResolutionSpec.Clusters,
 	})
 }
 
 func (a *DaprRuntime) initProxy() {
-	a.proxy = messaging.NewProxy(a.grpc.GetGRPCConnection, a.runtimeConfig.ID,
-		fmt.Sprintf("%s:%d", channel.DefaultChannelAddress, a.runtimeConfig.ApplicationPort), a.runtimeConfig.InternalGRPCPort, a.accessControlList, a.runtimeConfig.AppSSL, a.resiliency)
+	proxy := messaging.NewProxy(a.grpc.GetGRPCConnection, a.runtimeConfig.ID,
+		a.getLocalAppAddress(), a.runtimeConfig.InternalGRPCPort, a.accessControlList, a.runtimeConfig.AppSSL, a.resiliency)
+	proxy.SetMetadataPolicy(a.globalConfig.Spec.GRPCProxySpec.Metadata)
+	proxy.SetMaxMetadataSize(a.globalConfig.Spec.GRPCProxySpec.MaxMetadataSize)
+	proxy.SetCrossNamespacePolicy(a.crossNamespacePolicy)
+	a.proxy = &inflightProxy{Proxy: proxy, calls: &a.appCalls}
 
 	log.Info("gRPC proxy enabled")
 }
 
+// getLocalAppAddress returns the address at which the gRPC proxy dials the app, over its Unix domain socket if set.
+func (a *DaprRuntime) getLocalAppAddress() string {
+	if socket := a.runtimeConfig.AppUnixDomainSocket; socket != "" {
+		return grpc.UnixDomainSocketScheme + socket
+	}
+	return fmt.Sprintf("%s:%d", channel.DefaultChannelAddress, a.runtimeConfig.ApplicationPort)
+}
+
 // begin components updates for kubernetes mode.
 func (a *DaprRuntime) beginComponentsUpdates() error {
+	if a.runtimeConfig.Mode == modes.StandaloneMode {
+		a.watchResourcesPath()
+		return nil
+	}
 	if a.runtimeConfig.Mode != modes.KubernetesMode {
 		return nil
 	}
 
 	go func() {
-		parseAndUpdate := func(compRaw []byte) {
+		parseAndUpdate := func(compRaw []byte, eventType operatorv1pb.ResourceEventType) {
 			var component componentsV1alpha1.Component
 			if err := json.Unmarshal(compRaw, &component); err != nil {
 				log.Warnf("error deserializing component: %s", err)
@@ -920,6 +1406,14 @@ func (a *DaprRuntime) beginComponentsUpdates() error {
 				return
 			}
 
+			if eventType == operatorv1pb.ResourceEventType_DELETED {
+				log.Debugf("received component deletion. name: %s, type: %s/%s", component.ObjectMeta.Name, component.Spec.Type, component.Spec.Version)
+				a.forgetComponentVersion(component.Name)
+				a.onComponentDeleted(component)
+				return
+			}
+			a.recordComponentVersion(component)
+
 			log.Debugf("received component update. name: %s, type: %s/%s", component.ObjectMeta.Name, component.Spec.Type, component.Spec.Version)
 			updated := a.onComponentUpdated(component)
 			if !updated {
@@ -960,10 +1454,13 @@ func (a *DaprRuntime) beginComponentsUpdates() error {
 					for i := 0; i < len(comps); i++ {
 						// avoid missing any updates during the init component time.
 						go func(comp []byte) {
-							parseAndUpdate(comp)
+							parseAndUpdate(comp, operatorv1pb.ResourceEventType_UPDATED)
 						}(comps[i])
 					}
 
+					// Remove the components that were deleted during the failure time.
+					a.removeUnlistedComponents(comps)
+
 					return nil
 				}, backoff.NewExponentialBackOff())
 			}
@@ -977,13 +1474,76 @@ func (a *DaprRuntime) beginComponentsUpdates() error {
 					break
 				}
 
-				parseAndUpdate(c.GetComponent())
+				parseAndUpdate(c.GetComponent(), c.GetType())
+			}
+		}
+	}()
+	return nil
+}
+
+// begin subscriptions updates for kubernetes mode.
+func (a *DaprRuntime) beginSubscriptionsUpdates() error {
+	if a.runtimeConfig.Mode != modes.KubernetesMode {
+		return nil
+	}
+
+	go func() {
+		needReload := false
+		for {
+			var stream operatorv1pb.Operator_SubscriptionUpdateClient //nolint:nosnakecase
+
+			// Retry on stream error.
+			backoff.Retry(func() error {
+				var err error
+				stream, err = a.operatorClient.SubscriptionUpdate(context.Background(), &operatorv1pb.SubscriptionUpdateRequest{
+					Namespace: a.namespace,
+					PodName:   a.podName,
+				})
+				if err != nil {
+					log.Errorf("error from operator stream: %s", err)
+					return err
+				}
+				return nil
+			}, backoff.NewExponentialBackOff())
+
+			if needReload {
+				// Subscriptions may have changed during the failure time.
+				a.onSubscriptionsUpdated()
+			}
+
+			for {
+				s, err := stream.Recv()
+				if err != nil {
+					// Retry on stream error.
+					needReload = true
+					log.Errorf("error from operator stream: %s", err)
+					break
+				}
+
+				log.Debugf("received subscription update (%s)", s.GetType())
+				a.onSubscriptionsUpdated()
 			}
 		}
 	}()
 	return nil
 }
 
+// onSubscriptionsUpdated reloads the declarative subscriptions, restarting the topic subscriptions if they are active.
+func (a *DaprRuntime) onSubscriptionsUpdated() {
+	a.subsReloadLock.Lock()
+	defer a.subsReloadLock.Unlock()
+
+	if a.pubsubCtx == nil {
+		// Subscriptions are not active: only drop the cached routes so they are loaded again when subscriptions start
+		a.topicRoutes = nil
+		return
+	}
+
+	log.Info("reloading topic subscriptions")
+	a.stopSubscriptions()
+	a.startSubscriptions()
+}
+
 func (a *DaprRuntime) onComponentUpdated(component componentsV1alpha1.Component) bool {
 	oldComp, exists := a.getComponent(component.Spec.Type, component.Name)
 	newComp, _ := a.processComponentSecrets(component)
@@ -996,34 +1556,157 @@ func (a *DaprRuntime) onComponentUpdated(component componentsV1alpha1.Component)
 	return true
 }
 
-func (a *DaprRuntime) sendBatchOutputBindingsParallel(to []string, data []byte) {
-	for _, dst := range to {
-		go func(name string) {
-			_, err := a.sendToOutputBinding(name, &bindings.InvokeRequest{
-				Data:      data,
-				Operation: bindings.CreateOperation,
-			})
-			if err != nil {
-				log.Error(err)
-			}
-		}(dst)
+// onComponentDeleted closes the component and removes it from the runtime.
+func (a *DaprRuntime) onComponentDeleted(component componentsV1alpha1.Component) {
+	// Wait for the pending updates to be processed, so a deleted component isn't re-created afterwards
+	a.pendingComponents <- componentsV1alpha1.Component{}
+	a.cancelComponentInitRetry(component.Name)
+	a.componentStatuses.Delete(component.Name)
+
+	if _, exists := a.getComponent(component.Spec.Type, component.Name); !exists {
+		return
+	}
+
+	err := a.closeComponent(a.extractComponentCategory(component), component.Name)
+	if err != nil {
+		log.Warnf("error closing deleted component %s (%s): %s", component.Name, component.Spec.Type, err)
 	}
+
+	a.removeComponent(component)
+	log.Infof("component unloaded. name: %s, type: %s/%s", component.ObjectMeta.Name, component.Spec.Type, component.Spec.Version)
 }
 
-func (a *DaprRuntime) sendBatchOutputBindingsSequential(to []string, data []byte) error {
-	for _, dst := range to {
-		_, err := a.sendToOutputBinding(dst, &bindings.InvokeRequest{
-			Data:      data,
-			Operation: bindings.CreateOperation,
-		})
-		if err != nil {
-			return err
+// removeUnlistedComponents deletes the loaded components that are not included in the list returned by the operator.
+func (a *DaprRuntime) removeUnlistedComponents(compsRaw [][]byte) {
+	listed := make(map[string]struct{}, len(compsRaw))
+	for _, raw := range compsRaw {
+		var component componentsV1alpha1.Component
+		if err := json.Unmarshal(raw, &component); err != nil {
+			// Don't remove anything if the list can't be trusted
+			log.Warnf("error deserializing component: %s", err)
+			return
 		}
+		listed[component.Spec.Type+"/"+component.Name] = struct{}{}
 	}
-	return nil
-}
 
-func (a *DaprRuntime) sendToOutputBinding(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	for _, comp := range a.getComponents() {
+		// The built-in secret store is not managed by the operator
+		if comp.Name == secretstoresLoader.BuiltinKubernetesSecretStore {
+			continue
+		}
+		if _, ok := listed[comp.Spec.Type+"/"+comp.Name]; !ok {
+			go a.onComponentDeleted(comp)
+		}
+	}
+}
+
+func (a *DaprRuntime) removeComponent(component componentsV1alpha1.Component) {
+	a.componentsLock.Lock()
+	defer a.componentsLock.Unlock()
+
+	for i, c := range a.components {
+		if c.Spec.Type == component.Spec.Type && c.ObjectMeta.Name == component.Name {
+			a.components = append(a.components[:i], a.components[i+1:]...)
+			return
+		}
+	}
+}
+
+// closeComponent closes the instance of the component with the given name and stops using it.
+func (a *DaprRuntime) closeComponent(category ComponentCategory, name string) error {
+	var instance interface{}
+	switch category {
+	case bindingsComponent:
+		if binding, ok := a.inputBindings[name]; ok {
+			instance = binding
+			delete(a.inputBindings, name)
+			delete(a.inputBindingLimiters, name)
+			delete(a.inputBindingPartitions, name)
+			a.inputBindingsLock.Lock()
+			if cancel := a.inputBindingCancels[name]; cancel != nil {
+				cancel()
+			}
+			delete(a.inputBindingCancels, name)
+			delete(a.pausedInputBindings, name)
+			a.inputBindingsLock.Unlock()
+		}
+		if binding, ok := a.outputBindings[name]; ok {
+			instance = binding
+			delete(a.outputBindings, name)
+		}
+	case pubsubComponent:
+		if ps, ok := a.pubSubs[name]; ok {
+			a.unsubscribePubSub(name)
+			instance = ps.component
+			delete(a.pubSubs, name)
+		}
+	case secretStoreComponent:
+		instance = a.secretStores[name]
+		delete(a.secretStores, name)
+	case stateComponent:
+		instance = a.stateStores[name]
+		delete(a.stateStores, name)
+	case configurationComponent:
+		instance = a.configurationStores[name]
+		delete(a.configurationStores, name)
+	case lockComponent:
+		instance = a.lockStores[name]
+		delete(a.lockStores, name)
+	case cryptoComponent:
+		instance = a.cryptoProviders[name]
+		delete(a.cryptoProviders, name)
+	}
+
+	if closer, ok := instance.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// unsubscribePubSub stops all the topic subscriptions of the pubsub component.
+func (a *DaprRuntime) unsubscribePubSub(name string) {
+	a.topicsLock.Lock()
+	defer a.topicsLock.Unlock()
+
+	prefix := pubsubTopicKey(name, "")
+	for key, cancel := range a.topicCtxCancels {
+		if strings.HasPrefix(key, prefix) {
+			if cancel != nil {
+				cancel()
+			}
+			delete(a.topicCtxCancels, key)
+		}
+	}
+}
+
+func (a *DaprRuntime) sendBatchOutputBindingsParallel(to []string, data []byte) {
+	for _, dst := range to {
+		go func(name string) {
+			_, err := a.sendToOutputBinding(name, &bindings.InvokeRequest{
+				Data:      data,
+				Operation: bindings.CreateOperation,
+			})
+			if err != nil {
+				log.Error(err)
+			}
+		}(dst)
+	}
+}
+
+func (a *DaprRuntime) sendBatchOutputBindingsSequential(to []string, data []byte) error {
+	for _, dst := range to {
+		_, err := a.sendToOutputBinding(dst, &bindings.InvokeRequest{
+			Data:      data,
+			Operation: bindings.CreateOperation,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *DaprRuntime) sendToOutputBinding(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
 	if req.Operation == "" {
 		return nil, errors.New("operation field is missing from request")
 	}
@@ -1094,7 +1777,7 @@ func (a *DaprRuntime) sendBindingEventToApp(bindingName string, data []byte, met
 
 	if a.runtimeConfig.ApplicationProtocol == GRPCProtocol {
 		ctx = diag.SpanContextToGRPCMetadata(ctx, span.SpanContext())
-		client := runtimev1pb.NewAppCallbackClient(a.grpc.AppClient)
+		client := a.getAppCallbackClient()
 		req := &runtimev1pb.BindingEventRequest{
 			Name:     bindingName,
 			Data:     data,
@@ -1210,11 +1893,29 @@ func (a *DaprRuntime) sendBindingEventToApp(bindingName string, data []byte, met
 }
 
 func (a *DaprRuntime) readFromBinding(readCtx context.Context, name string, binding bindings.InputBinding) error {
+	limiter := a.inputBindingLimiters[name]
+	partitioner := a.inputBindingPartitions[name]
 	return binding.Read(readCtx, func(ctx context.Context, resp *bindings.ReadResponse) ([]byte, error) {
 		if resp == nil {
 			return nil, nil
 		}
 
+		releasePartition, err := partitioner.acquire(readCtx, resp)
+		if err != nil {
+			log.Debugf("not delivering event from binding [%s] to the app: %s", name, err)
+			return nil, err
+		}
+		defer releasePartition()
+
+		release, err := limiter.acquire(readCtx)
+		if err != nil {
+			log.Debugf("not delivering event from binding [%s] to the app: %s", name, err)
+			return nil, err
+		}
+		defer release()
+
+		diag.DefaultComponentMonitoring.InputBindingPayload(context.Background(), name, resp.Metadata[diag.PayloadSchemaMetadataKey], len(resp.Data))
+
 		start := time.Now()
 		b, err := a.sendBindingEventToApp(name, resp.Data, resp.Metadata)
 		elapsed := diag.ElapsedSince(start)
@@ -1237,6 +1938,7 @@ func (a *DaprRuntime) startHTTPServer(port int, publicPort *int, profilePort int
 		a.resiliency,
 		a.stateStores,
 		a.lockStores,
+		a.cryptoProviders,
 		a.secretStores,
 		a.secretsConfiguration,
 		a.configurationStores,
@@ -1247,6 +1949,7 @@ func (a *DaprRuntime) startHTTPServer(port int, publicPort *int, profilePort int
 		a.ShutdownWithWait,
 		a.getComponentsCapabilitesMap,
 	)
+	a.daprHTTPAPI.SetMetadataAttributes(a.metadataAttributes)
 
 	serverConf := http.ServerConfig{
 		AppID:              a.runtimeConfig.ID,
@@ -1254,6 +1957,7 @@ func (a *DaprRuntime) startHTTPServer(port int, publicPort *int, profilePort int
 		Port:               port,
 		APIListenAddresses: a.runtimeConfig.APIListenAddresses,
 		PublicPort:         publicPort,
+		PublicAllowlist:    a.runtimeConfig.PublicAllowlist,
 		ProfilePort:        profilePort,
 		AllowedOrigins:     allowedOrigins,
 		EnableProfiling:    a.runtimeConfig.EnableProfiling,
@@ -1264,12 +1968,17 @@ func (a *DaprRuntime) startHTTPServer(port int, publicPort *int, profilePort int
 	}
 
 	server := http.NewServer(http.NewServerOpts{
-		API:         a.daprHTTPAPI,
-		Config:      serverConf,
-		TracingSpec: a.globalConfig.Spec.TracingSpec,
-		MetricSpec:  a.globalConfig.Spec.MetricSpec,
-		Pipeline:    pipeline,
-		APISpec:     a.globalConfig.Spec.APISpec,
+		API:             a.daprHTTPAPI,
+		Config:          serverConf,
+		TracingSpec:     a.globalConfig.Spec.TracingSpec,
+		MetricSpec:      a.globalConfig.Spec.MetricSpec,
+		Pipeline:        pipeline,
+		APISpec:         a.globalConfig.Spec.GetAPISpec(),
+		CORSSpec:        a.globalConfig.Spec.CORSSpec,
+		CompressionSpec: a.globalConfig.Spec.CompressionSpec,
+		ProfilingSpec:   a.globalConfig.Spec.ProfilingSpec,
+		Auditor:         a.auditor,
+		DevConsole:      a.devConsole,
 	})
 	if err := server.StartNonBlocking(); err != nil {
 		return err
@@ -1282,7 +1991,8 @@ func (a *DaprRuntime) startHTTPServer(port int, publicPort *int, profilePort int
 func (a *DaprRuntime) startGRPCInternalServer(api grpc.API, port int) error {
 	// Since GRPCInteralServer is encrypted & authenticated, it is safe to listen on *
 	serverConf := a.getNewServerConfig([]string{""}, port)
-	server := grpc.NewInternalServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, a.authenticator, a.proxy)
+	serverConf.Connection = a.runtimeConfig.InternalGRPCConnection
+	server := grpc.NewInternalServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, a.authenticator, a.proxy, a.auditor, a.devConsole)
 	if err := server.StartNonBlocking(); err != nil {
 		return err
 	}
@@ -1293,7 +2003,8 @@ func (a *DaprRuntime) startGRPCInternalServer(api grpc.API, port int) error {
 
 func (a *DaprRuntime) startGRPCAPIServer(api grpc.API, port int) error {
 	serverConf := a.getNewServerConfig(a.runtimeConfig.APIListenAddresses, port)
-	server := grpc.NewAPIServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, a.globalConfig.Spec.APISpec, a.proxy)
+	serverConf.Listeners = a.apiListeners
+	server := grpc.NewAPIServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, a.globalConfig.Spec.GetAPISpec(), a.proxy, a.auditor, a.devConsole)
 	if err := server.StartNonBlocking(); err != nil {
 		return err
 	}
@@ -1302,18 +2013,35 @@ func (a *DaprRuntime) startGRPCAPIServer(api grpc.API, port int) error {
 	return nil
 }
 
-func (a *DaprRuntime) getNewServerConfig(apiListenAddresses []string, port int) grpc.ServerConfig {
+func (a *DaprRuntime) startGRPCHealthServer(port int) error {
+	// Like the public HTTP port, the health server listens on all interfaces for the kubelet
+	server := grpc.NewHealthServer(a.getNewServerConfig([]string{""}, port))
+	if err := server.StartNonBlocking(); err != nil {
+		return err
+	}
+	a.apiClosers = append(a.apiClosers, server)
+
+	return nil
+}
+
+// getTrustDomain returns the trust domain of the certificate of the sidecar.
+func (a *DaprRuntime) getTrustDomain() string {
 	// Use the trust domain value from the access control policy spec to generate the cert
 	// If no access control policy has been specified, use a default value
-	trustDomain := config.DefaultTrustDomain
 	if a.accessControlList != nil {
-		trustDomain = a.accessControlList.TrustDomain
+		return a.accessControlList.TrustDomain
 	}
-	return grpc.NewServerConfig(a.runtimeConfig.ID, a.hostAddress, port, apiListenAddresses, a.namespace, trustDomain, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.UnixDomainSocket, a.runtimeConfig.ReadBufferSize, a.runtimeConfig.EnableAPILogging)
+	return config.DefaultTrustDomain
+}
+
+func (a *DaprRuntime) getNewServerConfig(apiListenAddresses []string, port int) grpc.ServerConfig {
+	serverConf := grpc.NewServerConfig(a.runtimeConfig.ID, a.hostAddress, port, apiListenAddresses, a.namespace, a.getTrustDomain(), a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.UnixDomainSocket, a.runtimeConfig.ReadBufferSize, a.runtimeConfig.EnableAPILogging)
+	serverConf.Health = a.grpcHealth
+	return serverConf
 }
 
 func (a *DaprRuntime) getGRPCAPI() grpc.API {
-	return grpc.NewAPI(a.runtimeConfig.ID,
+	api := grpc.NewAPI(a.runtimeConfig.ID,
 		a.appChannel,
 		a.resiliency,
 		a.stateStores,
@@ -1321,6 +2049,7 @@ func (a *DaprRuntime) getGRPCAPI() grpc.API {
 		a.secretsConfiguration,
 		a.configurationStores,
 		a.lockStores,
+		a.cryptoProviders,
 		a.getPublishAdapter(),
 		a.directMessaging,
 		a.actor,
@@ -1332,6 +2061,10 @@ func (a *DaprRuntime) getGRPCAPI() grpc.API {
 		a.ShutdownWithWait,
 		a.getComponentsCapabilitesMap,
 	)
+	// The metadata attributes are shared by the HTTP and gRPC APIs
+	api.SetMetadataAttributes(a.metadataAttributes)
+	api.SetCrossNamespacePolicy(a.crossNamespacePolicy)
+	return api
 }
 
 func (a *DaprRuntime) getPublishAdapter() runtimePubsub.Adapter {
@@ -1343,7 +2076,7 @@ func (a *DaprRuntime) getPublishAdapter() runtimePubsub.Adapter {
 }
 
 func (a *DaprRuntime) getSubscribedBindingsGRPC() []string {
-	client := runtimev1pb.NewAppCallbackClient(a.grpc.AppClient)
+	client := a.getAppCallbackClient()
 	resp, err := client.ListInputBindings(context.Background(), &emptypb.Empty{})
 	bindings := []string{}
 
@@ -1396,6 +2129,21 @@ func (a *DaprRuntime) initInputBinding(c componentsV1alpha1.Component) error {
 		Name:       c.ObjectMeta.Name,
 	}})
 	if err != nil {
+		err = validation.Wrap(c.ObjectMeta.Name, err)
+		log.Errorf("failed to init input binding %s (%s/%s): %s", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version, err)
+		diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
+		return err
+	}
+	if err = a.validateComponentCredentials(c, binding); err != nil {
+		return err
+	}
+	limiter, err := newBindingLimiter(c.Spec.Metadata)
+	var partitioner *bindingPartitioner
+	if err == nil {
+		partitioner, err = newBindingPartitioner(c.Spec.Metadata)
+	}
+	if err != nil {
+		err = validation.Wrap(c.ObjectMeta.Name, err)
 		log.Errorf("failed to init input binding %s (%s/%s): %s", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version, err)
 		diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
 		return err
@@ -1409,6 +2157,8 @@ func (a *DaprRuntime) initInputBinding(c componentsV1alpha1.Component) error {
 		}
 	}
 	a.inputBindings[c.Name] = binding
+	a.inputBindingLimiters[c.Name] = limiter
+	a.inputBindingPartitions[c.Name] = partitioner
 	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
 	return nil
 }
@@ -1427,10 +2177,14 @@ func (a *DaprRuntime) initOutputBinding(c componentsV1alpha1.Component) error {
 			Name:       c.ObjectMeta.Name,
 		}})
 		if err != nil {
+			err = validation.Wrap(c.ObjectMeta.Name, err)
 			log.Errorf("failed to init output binding %s (%s/%s): %s", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version, err)
 			diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
 			return err
 		}
+		if err = a.validateComponentCredentials(c, binding); err != nil {
+			return err
+		}
 		log.Infof("successful init for output binding %s (%s/%s)", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version)
 		a.outputBindings[c.ObjectMeta.Name] = binding
 		diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
@@ -1438,6 +2192,24 @@ func (a *DaprRuntime) initOutputBinding(c componentsV1alpha1.Component) error {
 	return nil
 }
 
+// validateComponentCredentials runs the credentials validation of the initialized component, if it supports it.
+// The component is closed if its credentials are rejected.
+func (a *DaprRuntime) validateComponentCredentials(c componentsV1alpha1.Component, component any) error {
+	err := validation.Validate(a.ctx, c.ObjectMeta.Name, component)
+	if err == nil {
+		return nil
+	}
+
+	log.Errorf("credentials validation failed for component %s (%s/%s): %s", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version, err)
+	diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "validation")
+	if closer, ok := component.(io.Closer); ok {
+		if closeErr := closer.Close(); closeErr != nil {
+			log.Warnf("error closing component %s: %s", c.ObjectMeta.Name, closeErr)
+		}
+	}
+	return err
+}
+
 func (a *DaprRuntime) initConfiguration(s componentsV1alpha1.Component) error {
 	store, err := a.configurationStoreRegistry.Create(s.Spec.Type, s.Spec.Version)
 	if err != nil {
@@ -1497,9 +2269,44 @@ func (a *DaprRuntime) initLock(s componentsV1alpha1.Component) error {
 	return nil
 }
 
+func (a *DaprRuntime) initCryptoProvider(c componentsV1alpha1.Component) error {
+	// create the component
+	provider, err := a.cryptoProviderRegistry.Create(c.Spec.Type, c.Spec.Version)
+	if err != nil {
+		log.Warnf("error creating crypto provider %s (%s/%s): %s", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version, err)
+		diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "creation")
+		return err
+	}
+	if provider == nil {
+		return nil
+	}
+	// initialization
+	err = provider.Init(cryptoLoader.Metadata{Base: contribMetadata.Base{
+		Properties: a.convertMetadataItemsToProperties(c.Spec.Metadata),
+	}})
+	if err != nil {
+		diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
+		log.Warnf("error initializing crypto provider %s (%s/%s): %s", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version, err)
+		return err
+	}
+
+	a.cryptoProviders[c.ObjectMeta.Name] = provider
+	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
+	return nil
+}
+
 // Refer for state store api decision  https://github.com/dapr/dapr/blob/master/docs/decision_records/api/API-008-multi-state-store-api-design.md
 func (a *DaprRuntime) initState(s componentsV1alpha1.Component) error {
-	store, err := a.stateStoreRegistry.Create(s.Spec.Type, s.Spec.Version)
+	var store state.Store
+	var err error
+	if strings.EqualFold(s.Spec.Type, stateRouter.ComponentType) {
+		store = stateRouter.NewRouter(log, func(name string) (state.Store, bool) {
+			store, ok := a.stateStores[name]
+			return store, ok
+		})
+	} else {
+		store, err = a.stateStoreRegistry.Create(s.Spec.Type, s.Spec.Version)
+	}
 	if err != nil {
 		log.Warnf("error creating state store %s (%s/%s): %s", s.ObjectMeta.Name, s.Spec.Type, s.Spec.Version, err)
 		diag.DefaultMonitoring.ComponentInitFailed(s.Spec.Type, "creation")
@@ -1532,6 +2339,9 @@ func (a *DaprRuntime) initState(s componentsV1alpha1.Component) error {
 			log.Warnf("error initializing state store %s (%s/%s): %s", s.ObjectMeta.Name, s.Spec.Type, s.Spec.Version, err)
 			return err
 		}
+		if err = a.validateComponentCredentials(s, store); err != nil {
+			return err
+		}
 
 		a.stateStores[s.ObjectMeta.Name] = store
 		err = stateLoader.SaveStateConfiguration(s.ObjectMeta.Name, props)
@@ -1569,7 +2379,9 @@ func (a *DaprRuntime) getDeclarativeSubscriptions() []runtimePubsub.Subscription
 	case modes.KubernetesMode:
 		subs = runtimePubsub.DeclarativeKubernetes(a.operatorClient, a.podName, a.namespace, log)
 	case modes.StandaloneMode:
-		subs = runtimePubsub.DeclarativeSelfHosted(a.runtimeConfig.Standalone.ComponentsPath, log)
+		for _, path := range a.runtimeConfig.Standalone.ResourcesPath {
+			subs = append(subs, runtimePubsub.DeclarativeSelfHosted(path, log)...)
+		}
 	}
 
 	// only return valid subscriptions for this app id
@@ -1617,7 +2429,7 @@ func (a *DaprRuntime) getTopicRoutes() (map[string]TopicRoutes, error) {
 	if a.runtimeConfig.ApplicationProtocol == HTTPProtocol {
 		subscriptions, err = runtimePubsub.GetSubscriptionsHTTP(a.appChannel, log, a.resiliency, resiliencyEnabled)
 	} else if a.runtimeConfig.ApplicationProtocol == GRPCProtocol {
-		client := runtimev1pb.NewAppCallbackClient(a.grpc.AppClient)
+		client := a.getAppCallbackClient()
 		subscriptions, err = runtimePubsub.GetSubscriptionsGRPC(client, log, a.resiliency, resiliencyEnabled)
 	}
 	if err != nil {
@@ -1670,6 +2482,11 @@ func (a *DaprRuntime) getTopicRoutes() (map[string]TopicRoutes, error) {
 		}
 	}
 	a.topicRoutes = topicRoutes
+
+	a.subscriptionsLock.Lock()
+	a.subscriptions = subscriptions
+	a.subscriptionsLock.Unlock()
+
 	return topicRoutes, nil
 }
 
@@ -1692,10 +2509,14 @@ func (a *DaprRuntime) initPubSub(c componentsV1alpha1.Component) error {
 		Properties: properties,
 	}})
 	if err != nil {
+		err = validation.Wrap(c.ObjectMeta.Name, err)
 		log.Warnf("error initializing pub sub %s/%s: %s", c.Spec.Type, c.Spec.Version, err)
 		diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
 		return err
 	}
+	if err = a.validateComponentCredentials(c, pubSub); err != nil {
+		return err
+	}
 
 	pubsubName := c.ObjectMeta.Name
 
@@ -1704,6 +2525,7 @@ func (a *DaprRuntime) initPubSub(c componentsV1alpha1.Component) error {
 		scopedSubscriptions: scopes.GetScopedTopics(scopes.SubscriptionScopes, a.runtimeConfig.ID, properties),
 		scopedPublishings:   scopes.GetScopedTopics(scopes.PublishingScopes, a.runtimeConfig.ID, properties),
 		allowedTopics:       scopes.GetAllowedTopics(properties),
+		topicAliases:        scopes.GetTopicAliases(properties),
 	}
 	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
 
@@ -1719,16 +2541,74 @@ func (a *DaprRuntime) Publish(req *pubsub.PublishRequest) error {
 		return runtimePubsub.NotFoundError{PubsubName: req.PubsubName}
 	}
 
+	shadowTopic := req.Metadata[runtimePubsub.ShadowTopicMetadataKey]
+	if shadowTopic != "" {
+		// The option is handled by the runtime, so it isn't passed to the component
+		req = withoutShadowTopic(req)
+	}
+
+	if err := a.publishToTopic(ps, req); err != nil {
+		return err
+	}
+
+	if shadowTopic != "" && shadowTopic != req.Topic {
+		a.shadowPublish(ps, req, shadowTopic)
+	}
+	return nil
+}
+
+func (a *DaprRuntime) publishToTopic(ps pubsubItem, req *pubsub.PublishRequest) error {
 	if allowed := a.isPubSubOperationAllowed(req.PubsubName, req.Topic, ps.scopedPublishings); !allowed {
 		return runtimePubsub.NotAllowedError{Topic: req.Topic, ID: a.runtimeConfig.ID}
 	}
 
+	// Scopes apply to the logical topic names, the component uses the physical ones
+	if physical := ps.physicalTopic(req.Topic); physical != req.Topic {
+		aliased := *req
+		aliased.Topic = physical
+		req = &aliased
+	}
+
 	policy := a.resiliency.ComponentOutboundPolicy(a.ctx, req.PubsubName, resiliency.Pubsub)
 	return policy(func(ctx context.Context) (err error) {
 		return ps.component.Publish(req)
 	})
 }
 
+// shadowPublish mirrors a published message to a shadow topic in the background.
+// The failures are only logged, as they must not affect the delivery of the message to its topic.
+func (a *DaprRuntime) shadowPublish(ps pubsubItem, req *pubsub.PublishRequest, topic string) {
+	shadow := *req
+	shadow.Topic = topic
+	// The APIs can reuse the buffer of the data once the message is published
+	shadow.Data = make([]byte, len(req.Data))
+	copy(shadow.Data, req.Data)
+
+	go func() {
+		start := time.Now()
+		err := a.publishToTopic(ps, &shadow)
+		elapsed := diag.ElapsedSince(start)
+
+		diag.DefaultComponentMonitoring.PubsubEgressEvent(context.Background(), shadow.PubsubName, topic, err == nil, elapsed)
+		if err != nil {
+			log.Warnf("error publishing to the shadow topic %s of topic %s in pubsub %s: %s", topic, req.Topic, req.PubsubName, err)
+		}
+	}()
+}
+
+// withoutShadowTopic returns a copy of the publish request without the shadow topic option in its metadata.
+func withoutShadowTopic(req *pubsub.PublishRequest) *pubsub.PublishRequest {
+	md := make(map[string]string, len(req.Metadata))
+	for k, v := range req.Metadata {
+		if k != runtimePubsub.ShadowTopicMetadataKey {
+			md[k] = v
+		}
+	}
+	stripped := *req
+	stripped.Metadata = md
+	return &stripped
+}
+
 // Subscribe is used by APIs to start a subscription to a topic.
 func (a *DaprRuntime) Subscribe(ctx context.Context, name string, routes map[string]TopicRouteElem) (err error) {
 	_, ok := a.pubSubs[name]
@@ -1755,6 +2635,14 @@ func (a *DaprRuntime) GetPubSub(pubsubName string) pubsub.PubSub {
 	return ps.component
 }
 
+// physicalTopic returns the name of the topic in the component for a logical topic name used by the app.
+func (p pubsubItem) physicalTopic(topic string) string {
+	if physical, ok := p.topicAliases[topic]; ok {
+		return physical
+	}
+	return topic
+}
+
 func (a *DaprRuntime) isPubSubOperationAllowed(pubsubName string, topic string, scopedTopics []string) bool {
 	inAllowedTopics := false
 
@@ -1838,6 +2726,38 @@ func (a *DaprRuntime) initNameResolution() error {
 	return nil
 }
 
+// sendPubSubMessageToApp delivers the message to the app with the application protocol.
+func (a *DaprRuntime) sendPubSubMessageToApp(ctx context.Context, msg *pubsubSubscribedMessage) error {
+	switch a.runtimeConfig.ApplicationProtocol {
+	case HTTPProtocol:
+		return a.publishMessageHTTP(ctx, msg)
+	case GRPCProtocol:
+		return a.publishMessageGRPC(ctx, msg)
+	default:
+		return backoff.Permanent(errors.New("invalid application protocol"))
+	}
+}
+
+// recordPubSubDelivery records the delivery of the message to the app in the developer console, if enabled.
+func (a *DaprRuntime) recordPubSubDelivery(msg *pubsubSubscribedMessage, start time.Time, err error) {
+	if a.devConsole == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = err.Error()
+	}
+	a.devConsole.Record(diag.DevConsoleEvent{
+		Time:       start,
+		Kind:       diag.DevConsoleKindPubSub,
+		Protocol:   string(a.runtimeConfig.ApplicationProtocol),
+		Method:     msg.path,
+		Target:     msg.pubsub + "/" + msg.topic,
+		Result:     result,
+		DurationMs: float64(time.Since(start)) / float64(time.Millisecond),
+	})
+}
+
 func (a *DaprRuntime) publishMessageHTTP(ctx context.Context, msg *pubsubSubscribedMessage) error {
 	cloudEvent := msg.cloudEvent
 
@@ -1847,6 +2767,9 @@ func (a *DaprRuntime) publishMessageHTTP(ctx context.Context, msg *pubsubSubscri
 	req.WithHTTPExtension(nethttp.MethodPost, "")
 	req.WithRawData(msg.data, contenttype.CloudEventContentType)
 	req.WithCustomHTTPMetadata(msg.metadata)
+	if baggage := runtimePubsub.Baggage(cloudEvent); baggage != "" {
+		req.WithCustomHTTPMetadata(map[string]string{runtimePubsub.BaggageField: baggage})
+	}
 
 	if cloudEvent[pubsub.TraceIDField] != nil {
 		traceID := cloudEvent[pubsub.TraceIDField].(string)
@@ -1877,6 +2800,8 @@ func (a *DaprRuntime) publishMessageHTTP(ctx context.Context, msg *pubsubSubscri
 
 	if (statusCode >= 200) && (statusCode <= 299) {
 		// Any 2xx is considered a success.
+		a.handlePauseRequest(msg.pubsub, msg.topic, getMetadataValue(resp.Headers(), pubsubPauseHeader))
+
 		var appResponse pubsub.AppResponse
 		err := json.Unmarshal(body, &appResponse)
 		if err != nil {
@@ -1982,11 +2907,15 @@ func (a *DaprRuntime) publishMessageGRPC(ctx context.Context, msg *pubsubSubscri
 	}
 
 	ctx = invokev1.WithCustomGRPCMetadata(ctx, msg.metadata)
+	if baggage := runtimePubsub.Baggage(cloudEvent); baggage != "" {
+		ctx = grpcMetadata.AppendToOutgoingContext(ctx, runtimePubsub.BaggageField, baggage)
+	}
 
-	clientV1 := runtimev1pb.NewAppCallbackClient(a.grpc.AppClient)
+	clientV1 := a.getAppCallbackClient()
 
+	var header grpcMetadata.MD
 	start := time.Now()
-	res, err := clientV1.OnTopicEvent(ctx, envelope)
+	res, err := clientV1.OnTopicEvent(ctx, envelope, grpcGo.Header(&header))
 	elapsed := diag.ElapsedSince(start)
 
 	if span != nil {
@@ -2014,6 +2943,10 @@ func (a *DaprRuntime) publishMessageGRPC(ctx context.Context, msg *pubsubSubscri
 		return err
 	}
 
+	if values := header.Get(pubsubPauseHeader); len(values) > 0 {
+		a.handlePauseRequest(msg.pubsub, msg.topic, values[0])
+	}
+
 	switch res.GetStatus() {
 	case runtimev1pb.TopicEventResponse_SUCCESS: //nolint:nosnakecase
 		// on uninitialized status, this is the case it defaults to as an uninitialized status defaults to 0 which is
@@ -2062,16 +2995,86 @@ func (a *DaprRuntime) initActors() error {
 	actorConfig := actors.NewConfig(a.hostAddress, a.runtimeConfig.ID,
 		a.runtimeConfig.PlacementAddresses, a.runtimeConfig.InternalGRPCPort,
 		a.namespace, a.appConfig)
+	actorConfig.PlacementTLS = a.runtimeConfig.PlacementTLS
+	if a.globalConfig.Spec.ActorsSpec.LifecycleEvents.PubsubName != "" {
+		actorConfig.LifecycleEventPublisher = a.publishActorLifecycleEvent
+	}
+	// The workflow instances are internal actors, checkpointed in the actor state store
+	var engine *workflow.Engine
+	if a.actorStateStoreName != "" {
+		engine = a.newWorkflowEngine()
+		actorConfig.InternalActors = map[string]actors.InternalActor{engine.ActorType(): engine}
+	}
 	act := actors.NewActors(a.stateStores[a.actorStateStoreName], a.appChannel, a.grpc.GetGRPCConnection, actorConfig,
 		a.runtimeConfig.CertChain, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.Features,
 		a.resiliency, a.actorStateStoreName)
+	if engine != nil {
+		engine.SetActorRuntime(act)
+	}
 	err = act.Init()
 	if err == nil {
 		a.actor = act
+		a.workflowEngine = engine
 	}
 	return err
 }
 
+// newWorkflowEngine returns the workflow engine of the workflows of the registry and the built-in workflows.
+func (a *DaprRuntime) newWorkflowEngine() *workflow.Engine {
+	registry := a.workflowRegistry
+	if registry == nil {
+		registry = workflow.NewRegistry()
+	}
+	workflow.RegisterBuiltins(registry, a.invokeFromWorkflow)
+	return workflow.NewEngine(registry, a.namespace, a.runtimeConfig.ID)
+}
+
+// invokeFromWorkflow invokes a method of an app with service invocation, for the built-in workflow activities.
+func (a *DaprRuntime) invokeFromWorkflow(ctx context.Context, appID, method string, data []byte) ([]byte, error) {
+	if a.directMessaging == nil {
+		return nil, errors.New("service invocation is not initialized")
+	}
+	req := invokev1.NewInvokeMethodRequest(method).
+		WithHTTPExtension(nethttp.MethodPost, "").
+		WithRawData(data, invokev1.JSONContentType)
+	resp, err := a.directMessaging.Invoke(ctx, appID, req)
+	if err != nil {
+		return nil, err
+	}
+	_, body := resp.RawData()
+	if code := resp.Status().Code; (resp.IsHTTPResponse() && (code < 200 || code > 299)) || (!resp.IsHTTPResponse() && code != 0) {
+		return nil, errors.Errorf("method %s of app %s returned status code %d: %s", method, appID, code, string(body))
+	}
+	return body, nil
+}
+
+// publishActorLifecycleEvent publishes an actor lifecycle event as a cloudevent to the topic of the configuration.
+func (a *DaprRuntime) publishActorLifecycleEvent(event actors.LifecycleEvent) error {
+	spec := a.globalConfig.Spec.ActorsSpec.LifecycleEvents
+	topic := spec.Topic
+	if topic == "" {
+		topic = defaultActorLifecycleEventsTopic
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	envelope := pubsub.NewCloudEventsEnvelope(uuid.New().String(), a.runtimeConfig.ID, actorLifecycleEventTypePrefix+string(event.Type),
+		event.ActorType+"/"+event.ActorID, topic, spec.PubsubName, contenttype.JSONContentType, data, "", "")
+	b, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return a.Publish(&pubsub.PublishRequest{
+		PubsubName: spec.PubsubName,
+		Topic:      topic,
+		Data:       b,
+		Metadata:   map[string]string{},
+	})
+}
+
 func (a *DaprRuntime) getAuthorizedComponents(components []componentsV1alpha1.Component) []componentsV1alpha1.Component {
 	authorized := make([]componentsV1alpha1.Component, len(components))
 
@@ -2138,7 +3141,21 @@ func (a *DaprRuntime) loadComponents(opts *runtimeOpts) error {
 	a.components = authorizedComps
 	a.componentsLock.Unlock()
 
+	required := make([]componentsV1alpha1.Component, 0, len(authorizedComps))
 	for _, comp := range authorizedComps {
+		a.recordComponentVersion(comp)
+		if a.isOptionalComponent(comp) {
+			a.deferredComponents = append(a.deferredComponents, comp)
+			continue
+		}
+		required = append(required, comp)
+	}
+
+	if a.globalConfig != nil && a.globalConfig.Spec.ComponentsSpec.ParallelInit {
+		a.initComponentsInParallel(required)
+		return nil
+	}
+	for _, comp := range required {
 		a.pendingComponents <- comp
 	}
 
@@ -2178,19 +3195,33 @@ func (a *DaprRuntime) processComponents() {
 			continue
 		}
 
+		a.componentInitLock.Lock()
 		err := a.processComponentAndDependents(comp)
+		a.componentInitLock.Unlock()
 		if err != nil {
-			e := fmt.Sprintf("process component %s error: %s", comp.Name, err.Error())
-			if !comp.Spec.IgnoreErrors {
-				log.Warnf("error processing component, daprd process will exit gracefully")
-				a.Shutdown(a.runtimeConfig.GracefulShutdownDuration)
-				log.Fatalf(e)
-			}
-			log.Errorf(e)
+			a.onComponentError(comp, err)
 		}
 	}
 }
 
+// onComponentError retries the initialization of a component that failed to initialize in the background, if the retries
+// are enabled. Otherwise it exits the process, unless the errors of the component are ignored or it's optional.
+func (a *DaprRuntime) onComponentError(comp componentsV1alpha1.Component, err error) {
+	e := fmt.Sprintf("process component %s error: %s", comp.Name, err.Error())
+	if a.retryComponentInit(comp, err) || !a.isRequiredComponent(comp) {
+		log.Errorf(e)
+		return
+	}
+	a.exitOnComponentError(e)
+}
+
+// exitOnComponentError shuts the runtime down and exits the process after a required component failed to initialize.
+func (a *DaprRuntime) exitOnComponentError(e string) {
+	log.Warnf("error processing component, daprd process will exit gracefully")
+	a.Shutdown(a.runtimeConfig.GracefulShutdownDuration)
+	log.Fatalf(e)
+}
+
 func (a *DaprRuntime) flushOutstandingComponents() {
 	log.Info("waiting for all outstanding components to be processed")
 	// We flush by sending a no-op component. Since the processComponents goroutine only reads one component at a time,
@@ -2199,11 +3230,45 @@ func (a *DaprRuntime) flushOutstandingComponents() {
 	log.Info("all outstanding components processed")
 }
 
+// setComponentFailed records the failed initialization of the component, and reports it to the operator in Kubernetes mode
+// so it's surfaced as an event of the component.
+// Optional components are recorded as degraded.
+func (a *DaprRuntime) setComponentFailed(comp componentsV1alpha1.Component, err error) {
+	compStatus := meta.ComponentStatusFailed
+	if a.isOptionalComponent(comp) {
+		compStatus = meta.ComponentStatusDegraded
+		a.componentStatuses.SetDegraded(comp, err)
+	} else {
+		a.componentStatuses.SetFailed(comp, err)
+	}
+
+	if a.runtimeConfig.Mode != modes.KubernetesMode || a.operatorClient == nil {
+		return
+	}
+	req := &operatorv1pb.ReportComponentStatusRequest{
+		ComponentName: comp.Name,
+		Namespace:     a.namespace,
+		PodName:       a.podName,
+		Status:        compStatus,
+		Error:         err.Error(),
+		ErrorClass:    string(validation.Classify(err)),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+		defer cancel()
+		if _, reportErr := a.operatorClient.ReportComponentStatus(ctx, req); reportErr != nil {
+			log.Debugf("failed to report the status of component %s to the operator: %s", comp.Name, reportErr)
+		}
+	}()
+}
+
 func (a *DaprRuntime) processComponentAndDependents(comp componentsV1alpha1.Component) error {
 	log.Debugf("loading component. name: %s, type: %s/%s", comp.ObjectMeta.Name, comp.Spec.Type, comp.Spec.Version)
 	res := a.preprocessOneComponent(&comp)
 	if res.unreadyDependency != "" {
+		a.pendingComponentDependentsLock.Lock()
 		a.pendingComponentDependents[res.unreadyDependency] = append(a.pendingComponentDependents[res.unreadyDependency], comp)
+		a.pendingComponentDependentsLock.Unlock()
 		return nil
 	}
 
@@ -2214,11 +3279,7 @@ func (a *DaprRuntime) processComponentAndDependents(comp componentsV1alpha1.Comp
 	}
 
 	ch := make(chan error, 1)
-
-	timeout, err := time.ParseDuration(comp.Spec.InitTimeout)
-	if err != nil {
-		timeout = defaultComponentInitTimeout
-	}
+	timeout := a.componentInitTimeout(comp)
 
 	go func() {
 		ch <- a.doProcessOneComponent(compCategory, comp)
@@ -2227,19 +3288,26 @@ func (a *DaprRuntime) processComponentAndDependents(comp componentsV1alpha1.Comp
 	select {
 	case err := <-ch:
 		if err != nil {
+			a.setComponentFailed(comp, err)
 			return err
 		}
 	case <-time.After(timeout):
-		return fmt.Errorf("init timeout for component %s exceeded after %s", comp.Name, timeout.String())
+		err := fmt.Errorf("init timeout for component %s exceeded after %s", comp.Name, timeout.String())
+		a.setComponentFailed(comp, err)
+		return err
 	}
 
 	log.Infof("component loaded. name: %s, type: %s/%s", comp.ObjectMeta.Name, comp.Spec.Type, comp.Spec.Version)
 	a.appendOrReplaceComponents(comp)
+	a.componentStatuses.SetInitialized(comp)
 	diag.DefaultMonitoring.ComponentLoaded()
 
 	dependency := componentDependency(compCategory, comp.Name)
-	if deps, ok := a.pendingComponentDependents[dependency]; ok {
-		delete(a.pendingComponentDependents, dependency)
+	a.pendingComponentDependentsLock.Lock()
+	deps, ok := a.pendingComponentDependents[dependency]
+	delete(a.pendingComponentDependents, dependency)
+	a.pendingComponentDependentsLock.Unlock()
+	if ok {
 		for _, dependent := range deps {
 			if err := a.processComponentAndDependents(dependent); err != nil {
 				return err
@@ -2264,6 +3332,8 @@ func (a *DaprRuntime) doProcessOneComponent(category ComponentCategory, comp com
 		return a.initConfiguration(comp)
 	case lockComponent:
 		return a.initLock(comp)
+	case cryptoComponent:
+		return a.initCryptoProvider(comp)
 	}
 	return nil
 }
@@ -2276,10 +3346,26 @@ func (a *DaprRuntime) preprocessOneComponent(comp *componentsV1alpha1.Component)
 			unreadyDependency: componentDependency(secretStoreComponent, unreadySecretsStore),
 		}
 	}
+	// The state routers are initialized after the state stores they route to.
+	if strings.EqualFold(comp.Spec.Type, stateRouter.ComponentType) {
+		if routerConfig, err := stateRouter.ParseConfig(a.convertMetadataItemsToProperties(comp.Spec.Metadata)); err == nil {
+			for _, store := range routerConfig.Stores() {
+				if _, ok := a.stateStores[store]; !ok {
+					return componentPreprocessRes{
+						unreadyDependency: componentDependency(stateComponent, store),
+					}
+				}
+			}
+		}
+	}
 	return componentPreprocessRes{}
 }
 
 func (a *DaprRuntime) stopActor() {
+	if a.workflowEngine != nil {
+		log.Info("Shutting down workflow engine")
+		a.workflowEngine.Close()
+	}
 	if a.actor != nil {
 		log.Info("Shutting down actor")
 		a.actor.Stop()
@@ -2288,6 +3374,7 @@ func (a *DaprRuntime) stopActor() {
 
 // shutdownOutputComponents allows for a graceful shutdown of all runtime internal operations of components that are not source of more work.
 // These are all components except input bindings and pubsub.
+// The components are closed in dependency order: the secret stores, which the other components get their secrets from, are closed last.
 func (a *DaprRuntime) shutdownOutputComponents() error {
 	log.Info("Shutting down all remaining components")
 	var merr error
@@ -2303,24 +3390,6 @@ func (a *DaprRuntime) shutdownOutputComponents() error {
 			}
 		}
 	}
-	for name, secretstore := range a.secretStores {
-		if closer, ok := secretstore.(io.Closer); ok {
-			if err := closer.Close(); err != nil {
-				err = fmt.Errorf("error closing secret store %s: %w", name, err)
-				merr = multierror.Append(merr, err)
-				log.Warn(err)
-			}
-		}
-	}
-	for name, stateStore := range a.stateStores {
-		if closer, ok := stateStore.(io.Closer); ok {
-			if err := closer.Close(); err != nil {
-				err = fmt.Errorf("error closing state store %s: %w", name, err)
-				merr = multierror.Append(merr, err)
-				log.Warn(err)
-			}
-		}
-	}
 	// Close pubsub publisher
 	// The subscriber part is closed when a.ctx is canceled
 	for name, pubSub := range a.pubSubs {
@@ -2333,6 +3402,15 @@ func (a *DaprRuntime) shutdownOutputComponents() error {
 			log.Warn(err)
 		}
 	}
+	for name, stateStore := range a.stateStores {
+		if closer, ok := stateStore.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				err = fmt.Errorf("error closing state store %s: %w", name, err)
+				merr = multierror.Append(merr, err)
+				log.Warn(err)
+			}
+		}
+	}
 	if closer, ok := a.nameResolver.(io.Closer); ok {
 		if err := closer.Close(); err != nil {
 			err = fmt.Errorf("error closing name resolver: %w", err)
@@ -2340,6 +3418,15 @@ func (a *DaprRuntime) shutdownOutputComponents() error {
 			log.Warn(err)
 		}
 	}
+	for name, secretstore := range a.secretStores {
+		if closer, ok := secretstore.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				err = fmt.Errorf("error closing secret store %s: %w", name, err)
+				merr = multierror.Append(merr, err)
+				log.Warn(err)
+			}
+		}
+	}
 
 	return merr
 }
@@ -2358,15 +3445,25 @@ func (a *DaprRuntime) cleanSocket() {
 	}
 }
 
+// Shutdown stops the runtime in sequence: the sidecar reports unready and stops accepting new events,
+// the calls in progress are drained within the duration, then the APIs and the components are closed.
 func (a *DaprRuntime) Shutdown(duration time.Duration) {
 	// Ensure the Unix socket file is removed if a panic occurs.
 	defer a.cleanSocket()
 
 	log.Info("dapr shutting down.")
+	if a.daprHTTPAPI != nil {
+		a.daprHTTPAPI.MarkStatusAsNotReady()
+	}
+	a.grpcHealth.Shutdown()
 
 	log.Info("Stopping PubSub subscribers and input bindings")
 	a.stopSubscriptions()
 	a.stopReadingFromBindings()
+
+	log.Infof("Waiting up to %s for the calls in progress to complete", duration)
+	a.drainAppCalls(duration)
+
 	a.cancel()
 	a.stopActor()
 	log.Info("Stopping Dapr APIs")
@@ -2375,95 +3472,153 @@ func (a *DaprRuntime) Shutdown(duration time.Duration) {
 			log.Warnf("error closing API: %v", err)
 		}
 	}
+	a.shutdownOutputComponents()
+	if a.auditor != nil {
+		if err := a.auditor.Close(); err != nil {
+			log.Warnf("error closing the audit log: %v", err)
+		}
+	}
 	if a.tracerProvider != nil {
 		a.tracerProvider.Shutdown(context.Background())
 	}
-	log.Infof("Waiting %s to finish outstanding operations", duration)
-	<-time.After(duration)
-	a.shutdownOutputComponents()
+	if a.metricsExporter != nil {
+		if err := a.metricsExporter.Close(); err != nil {
+			log.Warnf("error closing the metrics exporter: %v", err)
+		}
+	}
 	a.shutdownC <- nil
 }
 
+// drainAppCalls waits for the service invocations, the actor calls, the proxied gRPC calls and the events delivered to
+// the app to complete, and for at least minShutdownDrainDuration, capped at the duration.
+func (a *DaprRuntime) drainAppCalls(duration time.Duration) {
+	minDuration := minShutdownDrainDuration
+	if minDuration > duration {
+		minDuration = duration
+	}
+	minWait := time.NewTimer(minDuration)
+	defer minWait.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+	if pending := a.appCalls.wait(ctx); pending > 0 {
+		log.Warnf("%d calls to the app still in progress after %s", pending, duration)
+	}
+	<-minWait.C
+}
+
+// getAppCallbackClient returns the client of the callbacks of the app using the gRPC protocol, whose calls are drained
+// when the sidecar shuts down.
+func (a *DaprRuntime) getAppCallbackClient() runtimev1pb.AppCallbackClient {
+	return runtimev1pb.NewAppCallbackClient(&inflightClientConn{ClientConnInterface: a.grpc.AppClient, calls: &a.appCalls})
+}
+
 func (a *DaprRuntime) WaitUntilShutdown() error {
 	return <-a.shutdownC
 }
 
+// processComponentSecrets resolves the secret references of the component from the first secret store of its chain having the secret.
+// It returns the name of the first secret store of the chain that isn't loaded yet, if any.
 func (a *DaprRuntime) processComponentSecrets(component componentsV1alpha1.Component) (componentsV1alpha1.Component, string) {
-	cache := map[string]secretstores.GetSecretResponse{}
+	// The secrets got from each secret store, by secret store and secret name.
+	cache := map[string]map[string]secretstores.GetSecretResponse{}
+	chain := a.secretStoresChain(component)
 
 	for i, m := range component.Spec.Metadata {
 		if m.SecretKeyRef.Name == "" {
 			continue
 		}
 
-		secretStoreName := a.authSecretStoreOrDefault(component)
-		secretStore := a.getSecretStore(secretStoreName)
-		if secretStore == nil {
-			log.Warnf("component %s references a secret store that isn't loaded: %s", component.Name, secretStoreName)
-			return component, secretStoreName
+		// Use the SecretKeyRef.Name key if SecretKeyRef.Key is not given
+		secretKeyName := m.SecretKeyRef.Key
+		if secretKeyName == "" {
+			secretKeyName = m.SecretKeyRef.Name
 		}
 
-		// If running in Kubernetes, do not fetch secrets from the Kubernetes secret store as they will be populated by the operator.
-		// Instead, base64 decode the secret values into their real self.
-		if a.runtimeConfig.Mode == modes.KubernetesMode && secretStoreName == secretstoresLoader.BuiltinKubernetesSecretStore {
-			var jsonVal string
-			err := json.Unmarshal(m.Value.Raw, &jsonVal)
-			if err != nil {
-				log.Errorf("error decoding secret: %s", err)
-				continue
+		for _, secretStoreName := range chain {
+			secretStore := a.getSecretStore(secretStoreName)
+			if secretStore == nil {
+				if secretStoreName == secretstoresLoader.BuiltinKubernetesSecretStore && a.builtinK8sSecretStoreDisabledByPolicy() {
+					log.Warnf("component %s references the built-in Kubernetes secret store, which is disabled by configuration %s", component.Name, a.runtimeConfig.GlobalConfig)
+					diag.DefaultMonitoring.ComponentInitFailed(component.Spec.Type, "secret_store_disabled")
+				} else {
+					log.Warnf("component %s references a secret store that isn't loaded: %s", component.Name, secretStoreName)
+				}
+				return component, secretStoreName
 			}
 
-			dec, err := base64.StdEncoding.DecodeString(jsonVal)
-			if err != nil {
-				log.Errorf("error decoding secret: %s", err)
-				continue
-			}
+			// If running in Kubernetes, do not fetch secrets from the Kubernetes secret store as they will be populated by the operator.
+			// Instead, base64 decode the secret values into their real self.
+			if a.runtimeConfig.Mode == modes.KubernetesMode && secretStoreName == secretstoresLoader.BuiltinKubernetesSecretStore {
+				// The operator leaves the value empty when the secret isn't in Kubernetes.
+				if len(m.Value.Raw) == 0 {
+					continue
+				}
 
-			m.Value = componentsV1alpha1.DynamicValue{
-				JSON: v1.JSON{
-					Raw: dec,
-				},
-			}
+				var jsonVal string
+				err := json.Unmarshal(m.Value.Raw, &jsonVal)
+				if err != nil {
+					log.Errorf("error decoding secret: %s", err)
+					break
+				}
 
-			component.Spec.Metadata[i] = m
-			continue
-		}
+				dec, err := base64.StdEncoding.DecodeString(jsonVal)
+				if err != nil {
+					log.Errorf("error decoding secret: %s", err)
+					break
+				}
 
-		resp, ok := cache[m.SecretKeyRef.Name]
-		if !ok {
-			r, err := secretStore.GetSecret(secretstores.GetSecretRequest{
-				Name: m.SecretKeyRef.Name,
-				Metadata: map[string]string{
-					"namespace": component.ObjectMeta.Namespace,
-				},
-			})
-			if err != nil {
-				log.Errorf("error getting secret: %s", err)
-				continue
+				m.Value = componentsV1alpha1.DynamicValue{
+					JSON: v1.JSON{
+						Raw: dec,
+					},
+				}
+
+				component.Spec.Metadata[i] = m
+				break
 			}
-			resp = r
-		}
 
-		// Use the SecretKeyRef.Name key if SecretKeyRef.Key is not given
-		secretKeyName := m.SecretKeyRef.Key
-		if secretKeyName == "" {
-			secretKeyName = m.SecretKeyRef.Name
-		}
+			if cache[secretStoreName] == nil {
+				cache[secretStoreName] = map[string]secretstores.GetSecretResponse{}
+			}
+			resp, ok := cache[secretStoreName][m.SecretKeyRef.Name]
+			if !ok {
+				r, err := secretStore.GetSecret(secretstores.GetSecretRequest{
+					Name: m.SecretKeyRef.Name,
+					Metadata: map[string]string{
+						"namespace": component.ObjectMeta.Namespace,
+					},
+				})
+				if err != nil {
+					log.Errorf("error getting secret: %s", err)
+				}
+				// Failed lookups are cached too, so that the other keys of the secret are got from the next secret stores right away.
+				resp = r
+				cache[secretStoreName][m.SecretKeyRef.Name] = resp
+			}
 
-		val, ok := resp.Data[secretKeyName]
-		if ok {
-			component.Spec.Metadata[i].Value = componentsV1alpha1.DynamicValue{
-				JSON: v1.JSON{
-					Raw: []byte(val),
-				},
+			if val, ok := resp.Data[secretKeyName]; ok {
+				component.Spec.Metadata[i].Value = componentsV1alpha1.DynamicValue{
+					JSON: v1.JSON{
+						Raw: []byte(val),
+					},
+				}
+				break
 			}
 		}
-
-		cache[m.SecretKeyRef.Name] = resp
 	}
 	return component, ""
 }
 
+// secretStoresChain returns the secret stores resolving the secret references of the component:
+// its own secret store if it names one, otherwise the resolution chain of the configuration, if any, or the default secret store.
+func (a *DaprRuntime) secretStoresChain(comp componentsV1alpha1.Component) []string {
+	if comp.SecretStore == "" && len(a.globalConfig.Spec.Secrets.ResolutionChain) > 0 {
+		return a.globalConfig.Spec.Secrets.ResolutionChain
+	}
+	return []string{a.authSecretStoreOrDefault(comp)}
+}
+
 func (a *DaprRuntime) authSecretStoreOrDefault(comp componentsV1alpha1.Component) string {
 	if comp.SecretStore == "" {
 		switch a.runtimeConfig.Mode {
@@ -2482,6 +3637,21 @@ func (a *DaprRuntime) getSecretStore(storeName string) secretstores.SecretStore
 }
 
 func (a *DaprRuntime) blockUntilAppIsReady() {
+	if socket := a.runtimeConfig.AppUnixDomainSocket; socket != "" {
+		log.Infof("application protocol: %s. waiting on unix domain socket %s.  This will block until the app is listening on that socket.", string(a.runtimeConfig.ApplicationProtocol), socket)
+		for {
+			conn, _ := net.DialTimeout("unix", socket, time.Millisecond*500)
+			if conn != nil {
+				conn.Close()
+				break
+			}
+			// prevents overwhelming the OS with open connections
+			time.Sleep(time.Millisecond * 50)
+		}
+		log.Infof("application discovered on unix domain socket %s", socket)
+		return
+	}
+
 	if a.runtimeConfig.ApplicationPort <= 0 {
 		return
 	}
@@ -2518,24 +3688,66 @@ func (a *DaprRuntime) loadAppConfiguration() {
 }
 
 func (a *DaprRuntime) createAppChannel() (err error) {
-	if a.runtimeConfig.ApplicationPort == 0 {
+	if a.appConnection != nil {
+		log.Info("using the connection to the embedding app as app channel")
+		ch := a.grpc.CreateLocalChannelWithConnection(a.appConnection, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize)
+		a.appChannel = &inflightAppChannel{AppChannel: ch, calls: &a.appCalls}
+		return nil
+	}
+
+	socket := a.runtimeConfig.AppUnixDomainSocket
+	if a.runtimeConfig.ApplicationPort == 0 && socket == "" {
 		log.Warn("App channel is not initialized. Did you configure an app-port?")
 		return nil
 	}
+	if socket != "" {
+		log.Infof("connecting to the app over unix domain socket %s", socket)
+	}
 
 	var ch channel.AppChannel
 	switch a.runtimeConfig.ApplicationProtocol {
 	case GRPCProtocol:
-		ch, err = a.grpc.CreateLocalChannel(a.runtimeConfig.ApplicationPort, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.AppSSL, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize)
+		if socket != "" {
+			ch, err = a.grpc.CreateLocalChannelOverUnixDomainSocket(socket, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize)
+		} else {
+			ch, err = a.grpc.CreateLocalChannel(a.runtimeConfig.ApplicationPort, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.AppSSL, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize)
+		}
 		if err != nil {
 			return err
 		}
 	case HTTPProtocol:
-		ch, err = httpChannel.CreateLocalChannel(a.runtimeConfig.ApplicationPort, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.AppSSL, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize)
+		if socket != "" {
+			ch, err = httpChannel.CreateLocalChannelOverUnixDomainSocket(socket, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize)
+		} else {
+			ch, err = httpChannel.CreateLocalChannel(a.runtimeConfig.ApplicationPort, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.AppSSL, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize)
+		}
 		if err != nil {
 			return err
 		}
 		ch.(*httpChannel.Channel).SetAppHealthCheckPath(a.runtimeConfig.AppHealthCheckHTTPPath)
+		if a.runtimeConfig.AppHTTP2 != httpChannel.HTTP2Disabled {
+			http2, http2Err := ch.(*httpChannel.Channel).SetHTTP2(a.runtimeConfig.AppHTTP2)
+			if http2Err != nil {
+				log.Warnf("failed to detect HTTP/2 support of the app, using HTTP/1.1: %s", http2Err)
+			} else if http2 {
+				log.Info("invoking the app with HTTP/2")
+			}
+		}
+
+		// The requests to the app must not bypass the middlewares of the app channel
+		pipeline, pipelineErr := a.buildAppHTTPPipeline()
+		if pipelineErr != nil {
+			return errors.Wrap(pipelineErr, "failed to build app HTTP pipeline")
+		}
+		ch.(*httpChannel.Channel).SetPipeline(pipeline)
+
+		compressionPolicy, compressionErr := compression.NewPolicy(a.globalConfig.Spec.CompressionSpec.AppChannel)
+		if compressionErr != nil {
+			log.Warnf("compression of the app channel is disabled: %s", compressionErr)
+		} else if compressionPolicy != nil {
+			log.Infof("requesting the app responses compressed with the encodings %v", compressionPolicy.Encodings)
+			ch.(*httpChannel.Channel).SetCompression(compressionPolicy)
+		}
 	default:
 		return errors.Errorf("cannot create app channel for protocol %s", string(a.runtimeConfig.ApplicationProtocol))
 	}
@@ -2549,15 +3761,25 @@ func (a *DaprRuntime) createAppChannel() (err error) {
 		log.Warn("[DEPRECATION NOTICE] Adding a default content type to incoming service invocation requests is deprecated and will be removed in the future. See https://docs.dapr.io/operations/support/support-preview-features/ for more details. You can opt into the new behavior today by setting the configuration option `ServiceInvocation.NoDefaultContentType` to true.")
 	}
 
-	a.appChannel = ch
+	// The calls to the app are recorded to be drained on shutdown
+	a.appChannel = &inflightAppChannel{AppChannel: ch, calls: &a.appCalls}
 
 	return nil
 }
 
+// builtinK8sSecretStoreDisabledByPolicy returns true if the configuration disables the built-in Kubernetes secret store.
+func (a *DaprRuntime) builtinK8sSecretStoreDisabledByPolicy() bool {
+	return a.runtimeConfig.Mode == modes.KubernetesMode && a.globalConfig.Spec.Secrets.DisableBuiltinK8sSecretStore
+}
+
 func (a *DaprRuntime) appendBuiltinSecretStore() {
 	if a.runtimeConfig.DisableBuiltinK8sSecretStore {
 		return
 	}
+	if a.builtinK8sSecretStoreDisabledByPolicy() {
+		log.Infof("built-in Kubernetes secret store disabled by configuration %s", a.runtimeConfig.GlobalConfig)
+		return
+	}
 
 	switch a.runtimeConfig.Mode {
 	case modes.KubernetesMode:
@@ -2575,6 +3797,12 @@ func (a *DaprRuntime) appendBuiltinSecretStore() {
 }
 
 func (a *DaprRuntime) initSecretStore(c componentsV1alpha1.Component) error {
+	// Don't let a component bring the Kubernetes secret store back when it's disabled by the configuration.
+	if c.Name == secretstoresLoader.BuiltinKubernetesSecretStore && a.builtinK8sSecretStoreDisabledByPolicy() {
+		log.Warnf("refusing to load secret store %s: the built-in Kubernetes secret store is disabled by configuration %s", c.Name, a.runtimeConfig.GlobalConfig)
+		diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "secret_store_disabled")
+		return errors.Errorf("secret store %s is disabled by configuration", c.Name)
+	}
 	secretStore, err := a.secretStoresRegistry.Create(c.Spec.Type, c.Spec.Version)
 	if err != nil {
 		log.Warnf("failed to create secret store %s/%s: %s", c.Spec.Type, c.Spec.Version, err)
@@ -2582,8 +3810,16 @@ func (a *DaprRuntime) initSecretStore(c componentsV1alpha1.Component) error {
 		return err
 	}
 
+	properties := a.convertMetadataItemsToProperties(c.Spec.Metadata)
+	cacheTTL, err := secretstoresLoader.GetCacheTTL(properties)
+	if err != nil {
+		log.Warnf("failed to init secret store %s/%s named %s: %s", c.Spec.Type, c.Spec.Version, c.ObjectMeta.Name, err)
+		diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
+		return err
+	}
+
 	err = secretStore.Init(secretstores.Metadata{Base: contribMetadata.Base{
-		Properties: a.convertMetadataItemsToProperties(c.Spec.Metadata),
+		Properties: properties,
 	}})
 	if err != nil {
 		log.Warnf("failed to init secret store %s/%s named %s: %s", c.Spec.Type, c.Spec.Version, c.ObjectMeta.Name, err)
@@ -2591,8 +3827,13 @@ func (a *DaprRuntime) initSecretStore(c componentsV1alpha1.Component) error {
 		return err
 	}
 
+	if cacheTTL > 0 {
+		log.Infof("caching the secrets of secret store %s for %s", c.ObjectMeta.Name, cacheTTL)
+		secretStore = secretstoresLoader.NewCachedSecretStore(secretStore, cacheTTL)
+	}
 	a.secretStores[c.ObjectMeta.Name] = secretStore
 	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
+	a.loadEgressProxyCredentials(c.ObjectMeta.Name)
 	return nil
 }
 
@@ -2635,6 +3876,77 @@ func (a *DaprRuntime) getComponents() []componentsV1alpha1.Component {
 	return comps
 }
 
+// getRuntimeMetadata returns the information about the runtime reported by the metadata API.
+func (a *DaprRuntime) getRuntimeMetadata() meta.Info {
+	a.subscriptionsLock.RLock()
+	subscriptions := make([]runtimePubsub.Subscription, len(a.subscriptions))
+	copy(subscriptions, a.subscriptions)
+	a.subscriptionsLock.RUnlock()
+
+	var actorTypes []string
+	if a.actor != nil {
+		actorTypes = a.appConfig.Entities
+	}
+
+	appConnection := meta.AppConnection{
+		Port:           a.runtimeConfig.ApplicationPort,
+		Protocol:       string(a.runtimeConfig.ApplicationProtocol),
+		MaxConcurrency: a.runtimeConfig.MaxConcurrency,
+	}
+	if a.runtimeConfig.AppHealthCheck != nil {
+		appConnection.Health = &meta.AppHealth{
+			HealthCheckPath:     a.runtimeConfig.AppHealthCheckHTTPPath,
+			HealthProbeInterval: a.runtimeConfig.AppHealthCheck.ProbeInterval,
+			HealthProbeTimeout:  a.runtimeConfig.AppHealthCheck.ProbeTimeout,
+			HealthThreshold:     a.runtimeConfig.AppHealthCheck.Threshold,
+		}
+		if a.appHealth != nil {
+			if a.appHealth.GetStatus() == apphealth.AppStatusHealthy {
+				appConnection.Health.Status = meta.AppStatusHealthy
+			} else {
+				appConnection.Health.Status = meta.AppStatusUnhealthy
+			}
+		}
+	}
+
+	return meta.Info{
+		ComponentStatuses: a.componentStatuses.List(),
+		Subscriptions:     subscriptions,
+		ActorTypes:        actorTypes,
+		AppConnection:     appConnection,
+		EnabledFeatures:   config.GetEnabledFeatures(a.globalConfig.Spec.Features),
+		Features:          config.GetFeatureStatuses(a.globalConfig.Spec.Features),
+		Drift:             a.getDrift(),
+		CrossNamespace:    a.globalConfig.Spec.AccessControlSpec.CrossNamespace,
+	}
+}
+
+// getSubscriptionScalingSignals returns the scaling signals of the subscriptions,
+// with the lag of the topics when the pub/sub component can report it.
+func (a *DaprRuntime) getSubscriptionScalingSignals() []runtimePubsub.ScalingSignals {
+	signals := a.subscriptionStats.Signals()
+	for i := range signals {
+		ps, ok := a.pubSubs[signals[i].PubsubName]
+		if !ok {
+			continue
+		}
+		reporter, ok := ps.component.(runtimePubsub.LagReporter)
+		if !ok {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(a.ctx, subscriptionLagTimeout)
+		lag, err := reporter.Lag(ctx, ps.physicalTopic(signals[i].Topic))
+		cancel()
+		if err != nil {
+			log.Debugf("failed to get the lag of topic %s on pubsub %s: %s", signals[i].Topic, signals[i].PubsubName, err)
+			continue
+		}
+		signals[i].Lag = &lag
+	}
+	return signals
+}
+
 func (a *DaprRuntime) getComponentsCapabilitesMap() map[string][]string {
 	capabilities := make(map[string][]string)
 	for key, store := range a.stateStores {
@@ -2707,6 +4019,7 @@ func (a *DaprRuntime) startSubscriptions() {
 	// PubSub subscribers are stopped via cancellation of the main runtime's context
 	a.pubsubCtx, a.pubsubCancel = context.WithCancel(a.ctx)
 	a.topicCtxCancels = map[string]context.CancelFunc{}
+	a.clearTopicPauses()
 	for pubsubName := range a.pubSubs {
 		if err := a.beginPubSub(pubsubName); err != nil {
 			log.Errorf("error occurred while beginning pubsub %s: %s", pubsubName, err)
@@ -2725,6 +4038,7 @@ func (a *DaprRuntime) stopSubscriptions() {
 
 	// Remove all contexts that are specific to each component (which have been canceled already by canceling pubsubCtx)
 	a.topicCtxCancels = nil
+	a.clearTopicPauses()
 
 	// Delete the cached topics and routes
 	a.topicRoutes = nil
@@ -2741,7 +4055,10 @@ func (a *DaprRuntime) startReadingFromBindings() (err error) {
 	}
 
 	// Input bindings are stopped via cancellation of the main runtime's context
+	a.inputBindingsLock.Lock()
 	a.inputBindingsCtx, a.inputBindingsCancel = context.WithCancel(a.ctx)
+	a.inputBindingCancels = make(map[string]context.CancelFunc, len(a.inputBindings))
+	a.inputBindingsLock.Unlock()
 
 	for name, binding := range a.inputBindings {
 		if !a.isAppSubscribedToBinding(name) {
@@ -2749,7 +4066,15 @@ func (a *DaprRuntime) startReadingFromBindings() (err error) {
 			continue
 		}
 
-		err = a.readFromBinding(a.inputBindingsCtx, name, binding)
+		a.inputBindingsLock.Lock()
+		if _, paused := a.pausedInputBindings[name]; paused {
+			log.Infof("input binding %s is paused.", name)
+			a.inputBindingCancels[name] = nil
+			a.inputBindingsLock.Unlock()
+			continue
+		}
+		err = a.startReadingFromBinding(name, binding)
+		a.inputBindingsLock.Unlock()
 		if err != nil {
 			log.Errorf("error reading from input binding %s: %s", name, err)
 			continue
@@ -2758,16 +4083,103 @@ func (a *DaprRuntime) startReadingFromBindings() (err error) {
 	return nil
 }
 
+// startReadingFromBinding reads from the input binding until the input bindings are stopped or the binding is paused.
+// It must be called with inputBindingsLock locked.
+func (a *DaprRuntime) startReadingFromBinding(name string, binding bindings.InputBinding) error {
+	ctx, cancel := context.WithCancel(a.inputBindingsCtx)
+	err := a.readFromBinding(ctx, name, binding)
+	if err != nil {
+		cancel()
+		return err
+	}
+	a.inputBindingCancels[name] = cancel
+	return nil
+}
+
 func (a *DaprRuntime) stopReadingFromBindings() {
+	a.inputBindingsLock.Lock()
+	defer a.inputBindingsLock.Unlock()
+
 	if a.inputBindingsCancel != nil {
 		a.inputBindingsCancel()
 	}
 
 	a.inputBindingsCtx = nil
 	a.inputBindingsCancel = nil
+	a.inputBindingCancels = nil
+}
+
+// PauseInputBinding stops reading from the input binding until ResumeInputBinding is called.
+// The pause is kept when the input bindings are restarted, for example when the app becomes healthy again.
+func (a *DaprRuntime) PauseInputBinding(name string) error {
+	a.inputBindingsLock.Lock()
+	defer a.inputBindingsLock.Unlock()
+
+	if _, ok := a.inputBindings[name]; !ok {
+		return fmt.Errorf("cannot pause input binding '%s': the input binding does not exist", name)
+	}
+	if _, paused := a.pausedInputBindings[name]; paused {
+		return nil
+	}
+
+	if cancel, ok := a.inputBindingCancels[name]; ok && cancel != nil {
+		cancel()
+		a.inputBindingCancels[name] = nil
+	}
+	if a.pausedInputBindings == nil {
+		a.pausedInputBindings = map[string]struct{}{}
+	}
+	a.pausedInputBindings[name] = struct{}{}
+
+	log.Infof("paused reading from input binding '%s'", name)
+	return nil
+}
+
+// ResumeInputBinding starts reading again from an input binding that was paused with PauseInputBinding.
+func (a *DaprRuntime) ResumeInputBinding(name string) error {
+	a.inputBindingsLock.Lock()
+	defer a.inputBindingsLock.Unlock()
+
+	if _, paused := a.pausedInputBindings[name]; !paused {
+		return fmt.Errorf("cannot resume input binding '%s': the input binding is not paused", name)
+	}
+	delete(a.pausedInputBindings, name)
+
+	binding, ok := a.inputBindings[name]
+	if _, subscribed := a.inputBindingCancels[name]; !ok || !subscribed || a.inputBindingsCtx == nil {
+		// The app isn't subscribed to the binding or the input bindings are stopped,
+		// they'll read from the binding when restarted
+		return nil
+	}
+
+	log.Infof("resuming reading from input binding '%s'", name)
+	return a.startReadingFromBinding(name, binding)
+}
+
+// PausedInputBindings returns the names of the input bindings that are currently paused, sorted by name.
+func (a *DaprRuntime) PausedInputBindings() []string {
+	a.inputBindingsLock.Lock()
+	defer a.inputBindingsLock.Unlock()
+
+	res := make([]string, 0, len(a.pausedInputBindings))
+	for name := range a.pausedInputBindings {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
 }
 
 // Returns "componentName||topicName", which is used as key for some maps
 func pubsubTopicKey(componentName, topicName string) string {
 	return componentName + "||" + topicName
 }
+
+// Returns the first value of the key in the metadata, looking up the key case-insensitively.
+func getMetadataValue(md invokev1.DaprInternalMetadata, key string) string {
+	for k, v := range md {
+		if strings.EqualFold(k, key) && len(v.GetValues()) > 0 {
+			return v.GetValues()[0]
+		}
+	}
+	return ""
+}
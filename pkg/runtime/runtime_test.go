@@ -40,10 +40,13 @@ import (
 	"github.com/dapr/components-contrib/lock"
 	bindingsLoader "github.com/dapr/dapr/pkg/components/bindings"
 	configurationLoader "github.com/dapr/dapr/pkg/components/configuration"
+	cryptoLoader "github.com/dapr/dapr/pkg/components/crypto"
+	"github.com/dapr/dapr/pkg/components/crypto/localkeys"
 	lockLoader "github.com/dapr/dapr/pkg/components/lock"
 	httpMiddlewareLoader "github.com/dapr/dapr/pkg/components/middleware/http"
 	pubsubLoader "github.com/dapr/dapr/pkg/components/pubsub"
 	stateLoader "github.com/dapr/dapr/pkg/components/state"
+	"github.com/dapr/dapr/pkg/components/validation"
 
 	"github.com/ghodss/yaml"
 	"github.com/google/uuid"
@@ -53,9 +56,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/zipkin"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -74,6 +79,7 @@ import (
 
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/actors"
 	componentsV1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	"github.com/dapr/dapr/pkg/apis/resiliency/v1alpha1"
 	subscriptionsapi "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
@@ -91,6 +97,7 @@ import (
 	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	"github.com/dapr/dapr/pkg/resiliency"
+	"github.com/dapr/dapr/pkg/runtime/meta"
 	runtimePubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 	"github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/dapr/dapr/pkg/scopes"
@@ -303,6 +310,41 @@ func TestProcessComponentsAndDependents(t *testing.T) {
 	})
 }
 
+func TestInitCryptoProvider(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.cryptoProviderRegistry.Logger = logger.NewLogger("crypto.test")
+	rt.cryptoProviderRegistry.RegisterComponent(localkeys.NewRawKeys, "rawkeys")
+
+	t.Run("init crypto provider", func(t *testing.T) {
+		err := rt.doProcessOneComponent(cryptoComponent, componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{Name: "mycrypto"},
+			Spec: componentsV1alpha1.ComponentSpec{
+				Type:    "crypto.rawkeys",
+				Version: "v1",
+				Metadata: []componentsV1alpha1.MetadataItem{{
+					Name:  "mykey",
+					Value: componentsV1alpha1.DynamicValue{JSON: v1.JSON{Raw: []byte("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")}},
+				}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, rt.cryptoProviders, "mycrypto")
+	})
+
+	t.Run("error on crypto provider init", func(t *testing.T) {
+		err := rt.doProcessOneComponent(cryptoComponent, componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{Name: "badcrypto"},
+			Spec: componentsV1alpha1.ComponentSpec{
+				Type:    "crypto.rawkeys",
+				Version: "v1",
+			},
+		})
+		assert.Error(t, err)
+		assert.NotContains(t, rt.cryptoProviders, "badcrypto")
+	})
+}
+
 func TestDoProcessComponent(t *testing.T) {
 	rt := NewTestDaprRuntime(modes.StandaloneMode)
 	defer stopRuntime(t, rt)
@@ -792,6 +834,68 @@ func TestInitState(t *testing.T) {
 	})
 }
 
+func TestInitStateRouter(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+
+	mockStateStore := new(daprt.MockStateStore)
+	mockStateStore.On("Init", mock.Anything).Return(nil)
+	rt.stateStoreRegistry.RegisterComponent(
+		func(_ logger.Logger) state.Store {
+			return mockStateStore
+		},
+		"mockState",
+	)
+
+	routerComponent := componentsV1alpha1.Component{
+		ObjectMeta: metaV1.ObjectMeta{Name: "router"},
+		Spec: componentsV1alpha1.ComponentSpec{
+			Type:    "state.router",
+			Version: "v1",
+			Metadata: []componentsV1alpha1.MetadataItem{
+				{Name: "routes", Value: componentsV1alpha1.DynamicValue{JSON: v1.JSON{Raw: []byte("hot-=hotStore")}}},
+				{Name: "defaultStore", Value: componentsV1alpha1.DynamicValue{JSON: v1.JSON{Raw: []byte("coldStore")}}},
+			},
+		},
+	}
+	stateComponent := func(name string) componentsV1alpha1.Component {
+		return componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Spec:       componentsV1alpha1.ComponentSpec{Type: "state.mockState", Version: "v1"},
+		}
+	}
+
+	// The router waits for the state stores it routes to.
+	require.NoError(t, rt.processComponentAndDependents(routerComponent))
+	assert.NotContains(t, rt.stateStores, "router")
+	require.NoError(t, rt.processComponentAndDependents(stateComponent("coldStore")))
+	assert.NotContains(t, rt.stateStores, "router")
+	require.NoError(t, rt.processComponentAndDependents(stateComponent("hotStore")))
+	assert.Contains(t, rt.stateStores, "router")
+
+	mockStateStore.On("Set", mock.Anything).Return(nil)
+	require.NoError(t, rt.stateStores["router"].Set(&state.SetRequest{Key: "app1||hot-1"}))
+	mockStateStore.AssertCalled(t, "Set", &state.SetRequest{Key: "app1||hot-1"})
+}
+
+func TestGetLocalAppAddress(t *testing.T) {
+	t.Run("app port", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.runtimeConfig.ApplicationPort = 3000
+
+		assert.Equal(t, "127.0.0.1:3000", rt.getLocalAppAddress())
+	})
+
+	t.Run("app unix domain socket", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.runtimeConfig.AppUnixDomainSocket = "/tmp/app.socket"
+
+		assert.Equal(t, "unix:/tmp/app.socket", rt.getLocalAppAddress())
+	})
+}
+
 func TestInitNameResolution(t *testing.T) {
 	initMockResolverForRuntime := func(rt *DaprRuntime, resolverName string, e error) *daprt.MockResolver {
 		mockResolver := new(daprt.MockResolver)
@@ -926,6 +1030,29 @@ func TestSetupTracing(t *testing.T) {
 			},
 		},
 		expectedExporters: []sdktrace.SpanExporter{&otlptrace.Exporter{}},
+	}, {
+		name: "otel trace grpc exporter with headers and TLS",
+		tracingConfig: config.TracingSpec{
+			Otel: config.OtelSpec{
+				EndpointAddress: "foo.bar:4317",
+				IsSecure:        true,
+				Protocol:        "grpc",
+				Headers:         map[string]string{"api-key": "secret"},
+				TLS:             &config.OtelTLSSpec{InsecureSkipVerify: true},
+			},
+		},
+		expectedExporters: []sdktrace.SpanExporter{&otlptrace.Exporter{}},
+	}, {
+		name: "otel trace exporter with invalid TLS configuration",
+		tracingConfig: config.TracingSpec{
+			Otel: config.OtelSpec{
+				EndpointAddress: "foo.bar:4317",
+				IsSecure:        true,
+				Protocol:        "grpc",
+				TLS:             &config.OtelTLSSpec{CAFile: "/not/found/ca.pem"},
+			},
+		},
+		expectedErr: "failed to read the CA certificates of the Otel collector",
 	}, {
 		name: "invalid otel trace exporter protocol",
 		tracingConfig: config.TracingSpec{
@@ -936,6 +1063,12 @@ func TestSetupTracing(t *testing.T) {
 			},
 		},
 		expectedErr: "invalid protocol tcp provided for Otel endpoint",
+	}, {
+		name: "invalid trace propagator",
+		tracingConfig: config.TracingSpec{
+			Propagators: []string{"b3", "xray"},
+		},
+		expectedErr: "invalid trace propagator xray",
 	}, {
 		name: "stdout trace exporter",
 		tracingConfig: config.TracingSpec{
@@ -989,6 +1122,40 @@ func TestSetupTracing(t *testing.T) {
 	}
 }
 
+func TestSetupTracingAttributes(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.globalConfig.Spec.TracingSpec.Attributes = map[string]string{"team": "payments", "environment": "staging"}
+	rt.runtimeConfig.TraceAttributes = map[string]string{"environment": "production"}
+
+	tpStore := newFakeTracerProviderStore()
+	require.NoError(t, rt.setupTracing(rt.hostAddress, tpStore))
+	require.NotNil(t, tpStore.res)
+	attrs := tpStore.res.Attributes()
+	assert.Contains(t, attrs, attribute.String("service.name", rt.runtimeConfig.ID))
+	assert.Contains(t, attrs, attribute.String("team", "payments"))
+	// The attributes of the sidecar override the ones of the configuration
+	assert.Contains(t, attrs, attribute.String("environment", "production"))
+	assert.NotContains(t, attrs, attribute.String("environment", "staging"))
+}
+
+func TestMetadataAttributesSpanProcessor(t *testing.T) {
+	attributes := meta.NewAttributeStore()
+	require.NoError(t, attributes.Set("tenant", "contoso", 0, nil))
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(newMetadataAttributesSpanProcessor(attributes)),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("dapr.metadata.tenant", "contoso"))
+}
+
 func TestMetadataUUID(t *testing.T) {
 	pubsubComponent := componentsV1alpha1.Component{
 		ObjectMeta: metaV1.ObjectMeta{
@@ -1198,6 +1365,64 @@ func TestOnComponentUpdated(t *testing.T) {
 	})
 }
 
+func TestOnComponentDeleted(t *testing.T) {
+	comp := componentsV1alpha1.Component{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "mockStateStore",
+		},
+		Spec: componentsV1alpha1.ComponentSpec{
+			Type:    "state.mockState",
+			Version: "v1",
+		},
+	}
+
+	t.Run("deleted component is closed and removed", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.KubernetesMode)
+		defer stopRuntime(t, rt)
+		go rt.processComponents()
+
+		rt.stateStores["mockStateStore"] = new(daprt.MockStateStore)
+		rt.components = append(rt.components, comp)
+
+		rt.onComponentDeleted(comp)
+
+		assert.NotContains(t, rt.stateStores, "mockStateStore")
+		_, exists := rt.getComponent("state.mockState", "mockStateStore")
+		assert.False(t, exists)
+	})
+
+	t.Run("unknown component is ignored", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.KubernetesMode)
+		defer stopRuntime(t, rt)
+		go rt.processComponents()
+
+		rt.onComponentDeleted(comp)
+
+		assert.Len(t, rt.getComponents(), 0)
+	})
+
+	t.Run("components missing from the operator list are removed", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.KubernetesMode)
+		defer stopRuntime(t, rt)
+		go rt.processComponents()
+
+		kept := comp
+		kept.Name = "kept"
+		rt.components = append(rt.components, comp, kept)
+		rt.stateStores["mockStateStore"] = new(daprt.MockStateStore)
+		keptRaw, _ := json.Marshal(kept)
+
+		rt.removeUnlistedComponents([][]byte{keptRaw})
+
+		assert.Eventually(t, func() bool {
+			_, exists := rt.getComponent("state.mockState", "mockStateStore")
+			return !exists
+		}, time.Second, 10*time.Millisecond)
+		_, exists := rt.getComponent("state.mockState", "kept")
+		assert.True(t, exists)
+	})
+}
+
 func TestConsumerID(t *testing.T) {
 	metadata := []componentsV1alpha1.MetadataItem{
 		{
@@ -1447,7 +1672,7 @@ func TestInitPubSub(t *testing.T) {
 		filePath := "./components/sub.yaml"
 		writeSubscriptionToDisk(s, filePath)
 
-		rts.runtimeConfig.Standalone.ComponentsPath = dir
+		rts.runtimeConfig.Standalone.ResourcesPath = []string{dir}
 		subs := rts.getDeclarativeSubscriptions()
 		if assert.Len(t, subs, 1) {
 			assert.Equal(t, "topic1", subs[0].Topic)
@@ -1473,7 +1698,7 @@ func TestInitPubSub(t *testing.T) {
 		filePath := "./components/sub.yaml"
 		writeSubscriptionToDisk(s, filePath)
 
-		rts.runtimeConfig.Standalone.ComponentsPath = dir
+		rts.runtimeConfig.Standalone.ResourcesPath = []string{dir}
 		subs := rts.getDeclarativeSubscriptions()
 		if assert.Len(t, subs, 1) {
 			assert.Equal(t, "topic1", subs[0].Topic)
@@ -1500,7 +1725,7 @@ func TestInitPubSub(t *testing.T) {
 		filePath := "./components/sub.yaml"
 		writeSubscriptionToDisk(s, filePath)
 
-		rts.runtimeConfig.Standalone.ComponentsPath = dir
+		rts.runtimeConfig.Standalone.ResourcesPath = []string{dir}
 		subs := rts.getDeclarativeSubscriptions()
 		assert.Len(t, subs, 0)
 	})
@@ -1855,6 +2080,49 @@ func TestInitSecretStores(t *testing.T) {
 		s := rt.getSecretStore("kubernetesMock")
 		assert.NotNil(t, s)
 	})
+
+	t.Run("secret store with cache", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		m := NewMockKubernetesStore()
+		rt.secretStoresRegistry.RegisterComponent(
+			func(_ logger.Logger) secretstores.SecretStore {
+				return m
+			},
+			"kubernetesMock",
+		)
+
+		cacheTTL := []componentsV1alpha1.MetadataItem{{
+			Name:  "cacheTTL",
+			Value: componentsV1alpha1.DynamicValue{JSON: v1.JSON{Raw: []byte("1m")}},
+		}}
+		err := rt.processComponentAndDependents(componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: "kubernetesMock",
+			},
+			Spec: componentsV1alpha1.ComponentSpec{
+				Type:     "secretstores.kubernetesMock",
+				Version:  "v1",
+				Metadata: cacheTTL,
+			},
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, rt.secretStores["kubernetesMock"])
+		assert.NotEqual(t, m, rt.secretStores["kubernetesMock"])
+
+		cacheTTL[0].Value = componentsV1alpha1.DynamicValue{JSON: v1.JSON{Raw: []byte("never")}}
+		err = rt.processComponentAndDependents(componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: "invalidCache",
+			},
+			Spec: componentsV1alpha1.ComponentSpec{
+				Type:     "secretstores.kubernetesMock",
+				Version:  "v1",
+				Metadata: cacheTTL,
+			},
+		})
+		assert.Error(t, err)
+	})
 }
 
 func TestMetadataItemsToPropertiesConversion(t *testing.T) {
@@ -2088,6 +2356,61 @@ func TestProcessComponentSecrets(t *testing.T) {
 		assert.Equal(t, "value1", mod.Spec.Metadata[0].Value.String())
 		assert.Empty(t, unready)
 	})
+
+	t.Run("Resolution chain", func(t *testing.T) {
+		chainBinding := func(secretStore string) componentsV1alpha1.Component {
+			return componentsV1alpha1.Component{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name: "chainBinding",
+				},
+				Spec: componentsV1alpha1.ComponentSpec{
+					Type:    "bindings.mock",
+					Version: "v1",
+					Metadata: []componentsV1alpha1.MetadataItem{
+						{Name: "a", SecretKeyRef: componentsV1alpha1.SecretKeyRef{Name: "name1", Key: "key1"}},
+						{Name: "b", SecretKeyRef: componentsV1alpha1.SecretKeyRef{Name: "name1", Key: "_value"}},
+					},
+				},
+				Auth: componentsV1alpha1.Auth{SecretStore: secretStore},
+			}
+		}
+
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.globalConfig.Spec.Secrets.ResolutionChain = []string{"vault", "mock"}
+
+		_, unready := rt.processComponentSecrets(chainBinding(""))
+		assert.Equal(t, "vault", unready)
+
+		vault := &notFoundSecretStore{}
+		rt.secretStores["vault"] = vault
+		_, unready = rt.processComponentSecrets(chainBinding(""))
+		assert.Equal(t, "mock", unready)
+
+		rt.secretStores["mock"] = &mockSecretStore{}
+		mod, unready := rt.processComponentSecrets(chainBinding(""))
+		assert.Empty(t, unready)
+		assert.Equal(t, "value1", mod.Spec.Metadata[0].Value.String())
+		assert.Equal(t, "_value_data", mod.Spec.Metadata[1].Value.String())
+		// The failed lookup of the secret is cached for the other keys.
+		assert.Equal(t, 2, vault.calls)
+
+		// The secret store of the component overrides the chain.
+		mod, unready = rt.processComponentSecrets(chainBinding("vault"))
+		assert.Empty(t, unready)
+		assert.Equal(t, "", mod.Spec.Metadata[0].Value.String())
+	})
+}
+
+// notFoundSecretStore is a secret store without secrets.
+type notFoundSecretStore struct {
+	secretstores.SecretStore
+	calls int
+}
+
+func (s *notFoundSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	s.calls++
+	return secretstores.GetSecretResponse{}, fmt.Errorf("secret %s not found", req.Name)
 }
 
 func TestExtractComponentCategory(t *testing.T) {
@@ -2306,6 +2629,58 @@ func TestInitSecretStoresInKubernetesMode(t *testing.T) {
 		}
 	})
 
+	t.Run("built-in secret store disabled by configuration", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.KubernetesMode)
+		defer stopRuntime(t, rt)
+		rt.globalConfig.Spec.Secrets.DisableBuiltinK8sSecretStore = true
+
+		testOk := make(chan struct{})
+		defer close(testOk)
+		go func() {
+			// If the test fails, this call blocks forever, eventually causing a timeout
+			rt.appendBuiltinSecretStore()
+			testOk <- struct{}{}
+		}()
+		select {
+		case <-testOk:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("test failed")
+		}
+
+		// A component can't replace the disabled built-in secret store
+		err := rt.initSecretStore(componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: secretstoresLoader.BuiltinKubernetesSecretStore,
+			},
+			Spec: componentsV1alpha1.ComponentSpec{
+				Type:    "secretstores.kubernetes",
+				Version: "v1",
+			},
+		})
+		assert.Error(t, err)
+		assert.Nil(t, rt.getSecretStore(secretstoresLoader.BuiltinKubernetesSecretStore))
+
+		// Components referencing the built-in secret store are not loaded
+		_, unready := rt.processComponentSecrets(componentsV1alpha1.Component{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: "mockPubSub",
+			},
+			Spec: componentsV1alpha1.ComponentSpec{
+				Type: "pubsub.mockPubSub",
+				Metadata: []componentsV1alpha1.MetadataItem{
+					{
+						Name: "redisPassword",
+						SecretKeyRef: componentsV1alpha1.SecretKeyRef{
+							Name: "redis-secret",
+							Key:  "redis-password",
+						},
+					},
+				},
+			},
+		})
+		assert.Equal(t, secretstoresLoader.BuiltinKubernetesSecretStore, unready)
+	})
+
 	t.Run("built-in secret store bypasses authorizers", func(t *testing.T) {
 		rt := NewTestDaprRuntime(modes.KubernetesMode)
 		defer stopRuntime(t, rt)
@@ -3383,6 +3758,103 @@ func TestPubsubWithResiliency(t *testing.T) {
 	})
 }
 
+func TestPauseSubscription(t *testing.T) {
+	setup := func(t *testing.T) (*DaprRuntime, *mockSubscribePubSub) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		t.Cleanup(func() { stopRuntime(t, rt) })
+
+		ps := &mockSubscribePubSub{}
+		require.NoError(t, ps.Init(pubsub.Metadata{}))
+		rt.pubSubs = map[string]pubsubItem{TestPubsubName: {component: ps}}
+		rt.topicRoutes = map[string]TopicRoutes{
+			TestPubsubName: {
+				"topic1": TopicRouteElem{
+					rules: []*runtimePubsub.Rule{{Path: "topic1"}},
+				},
+			},
+		}
+		rt.startSubscriptions()
+		require.Contains(t, rt.topicCtxCancels, pubsubTopicKey(TestPubsubName, "topic1"))
+		return rt, ps
+	}
+
+	t.Run("pause and resume", func(t *testing.T) {
+		rt, ps := setup(t)
+		subKey := pubsubTopicKey(TestPubsubName, "topic1")
+
+		require.NoError(t, rt.PauseSubscription(TestPubsubName, "topic1", time.Minute))
+		assert.NotContains(t, rt.topicCtxCancels, subKey)
+		paused := rt.PausedSubscriptions()
+		require.Len(t, paused, 1)
+		assert.Equal(t, TestPubsubName, paused[0].PubsubName)
+		assert.Equal(t, "topic1", paused[0].Topic)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), paused[0].PausedUntil, 5*time.Second)
+
+		// Pausing again extends the pause
+		require.NoError(t, rt.PauseSubscription(TestPubsubName, "topic1", 2*time.Minute))
+		assert.Len(t, rt.PausedSubscriptions(), 1)
+
+		delete(ps.handlers, "topic1")
+		require.NoError(t, rt.ResumeSubscription(TestPubsubName, "topic1"))
+		assert.Contains(t, rt.topicCtxCancels, subKey)
+		assert.Contains(t, ps.handlers, "topic1")
+		assert.Empty(t, rt.PausedSubscriptions())
+	})
+
+	t.Run("resumes after the duration elapses", func(t *testing.T) {
+		rt, _ := setup(t)
+
+		require.NoError(t, rt.PauseSubscription(TestPubsubName, "topic1", 50*time.Millisecond))
+		assert.Eventually(t, func() bool {
+			return len(rt.PausedSubscriptions()) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+
+		rt.topicsLock.Lock()
+		defer rt.topicsLock.Unlock()
+		assert.Contains(t, rt.topicCtxCancels, pubsubTopicKey(TestPubsubName, "topic1"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		rt, _ := setup(t)
+
+		assert.Error(t, rt.PauseSubscription(TestPubsubName, "topic1", 0))
+		assert.Error(t, rt.PauseSubscription(TestPubsubName, "notsubscribed", time.Minute))
+		assert.Error(t, rt.ResumeSubscription(TestPubsubName, "topic1"))
+	})
+
+	t.Run("app requests pause in http response", func(t *testing.T) {
+		rt, _ := setup(t)
+
+		mockAppChannel := new(channelt.MockAppChannel)
+		rt.appChannel = mockAppChannel
+		fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		fakeResp.WithHeaders(metadata.MD{"Dapr-Pubsub-Pause": []string{"1m"}})
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).Return(fakeResp, nil)
+
+		err := rt.publishMessageHTTP(context.Background(), &pubsubSubscribedMessage{
+			cloudEvent: map[string]interface{}{},
+			topic:      "topic1",
+			data:       []byte("testing"),
+			metadata:   map[string]string{pubsubName: TestPubsubName},
+			path:       "topic1",
+			pubsub:     TestPubsubName,
+		})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return len(rt.PausedSubscriptions()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("stopping subscriptions clears pauses", func(t *testing.T) {
+		rt, _ := setup(t)
+
+		require.NoError(t, rt.PauseSubscription(TestPubsubName, "topic1", time.Minute))
+		rt.stopSubscriptions()
+		assert.Empty(t, rt.PausedSubscriptions())
+	})
+}
+
 // mockSubscribePubSub is an in-memory pubsub component.
 type mockSubscribePubSub struct {
 	handlers map[string]pubsub.Handler
@@ -3527,6 +3999,198 @@ func TestPubSubDeadLetter(t *testing.T) {
 	})
 }
 
+func TestPubSubTopicAliases(t *testing.T) {
+	pubsubComponent := componentsV1alpha1.Component{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: TestPubsubName,
+		},
+		Spec: componentsV1alpha1.ComponentSpec{
+			Type:    "pubsub.mockPubSub",
+			Version: "v1",
+			Metadata: []componentsV1alpha1.MetadataItem{
+				{
+					Name: "topicAliases",
+					Value: componentsV1alpha1.DynamicValue{
+						JSON: v1.JSON{Raw: []byte("orders=orders-v2")},
+					},
+				},
+			},
+		},
+	}
+
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.pubSubRegistry.RegisterComponent(
+		func(_ logger.Logger) pubsub.PubSub {
+			return &mockSubscribePubSub{}
+		},
+		"mockPubSub",
+	)
+	req := invokev1.NewInvokeMethodRequest("dapr/subscribe")
+	req.WithHTTPExtension(http.MethodGet, "")
+	req.WithRawData(nil, invokev1.JSONContentType)
+
+	subscriptionItems := []runtimePubsub.SubscriptionJSON{
+		{PubsubName: TestPubsubName, Topic: "orders", Route: "orders"},
+	}
+	sub, _ := json.Marshal(subscriptionItems)
+	fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+	fakeResp.WithRawData(sub, "application/json")
+
+	mockAppChannel := new(channelt.MockAppChannel)
+	rt.appChannel = mockAppChannel
+	mockAppChannel.On("InvokeMethod", mock.MatchedBy(matchContextInterface), req).Return(fakeResp, nil)
+	mockAppChannel.On("InvokeMethod", mock.MatchedBy(matchContextInterface), mock.MatchedBy(func(req *invokev1.InvokeMethodRequest) bool {
+		return req.Message().Method == "orders"
+	})).Return(invokev1.NewInvokeMethodResponse(200, "OK", nil), nil)
+
+	require.NoError(t, rt.initPubSub(pubsubComponent))
+	rt.startSubscriptions()
+
+	pubsubIns := rt.pubSubs[TestPubsubName].component.(*mockSubscribePubSub)
+	assert.Contains(t, pubsubIns.handlers, "orders-v2")
+	assert.NotContains(t, pubsubIns.handlers, "orders")
+
+	err := rt.Publish(&pubsub.PublishRequest{
+		PubsubName: TestPubsubName,
+		Topic:      "orders",
+		Data:       []byte(`{"id":"1"}`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pubsubIns.pubCount["orders-v2"])
+	assert.Equal(t, 0, pubsubIns.pubCount["orders"])
+	mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 2)
+
+	t.Run("topics without alias are not remapped", func(t *testing.T) {
+		err := rt.Publish(&pubsub.PublishRequest{
+			PubsubName: TestPubsubName,
+			Topic:      "payments",
+			Data:       []byte(`{"id":"1"}`),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, pubsubIns.pubCount["payments"])
+	})
+}
+
+// recordingPubSub is a pubsub component sending the published messages to a channel.
+type recordingPubSub struct {
+	mockPublishPubSub
+
+	published chan *pubsub.PublishRequest
+	failTopic string
+}
+
+func (m *recordingPubSub) Publish(req *pubsub.PublishRequest) error {
+	if req.Topic == m.failTopic {
+		return errors.New("publish failed")
+	}
+	m.published <- req
+	return nil
+}
+
+func TestShadowPublish(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+
+	component := &recordingPubSub{
+		published: make(chan *pubsub.PublishRequest, 2),
+		failTopic: "failing-shadow",
+	}
+	rt.pubSubs[TestPubsubName] = pubsubItem{
+		component:    component,
+		topicAliases: map[string]string{"orders-shadow": "orders-v2"},
+	}
+
+	receive := func(t *testing.T) *pubsub.PublishRequest {
+		t.Helper()
+		select {
+		case req := <-component.published:
+			return req
+		case <-time.After(time.Second):
+			t.Fatal("the message was not published")
+			return nil
+		}
+	}
+
+	t.Run("the message is mirrored to the shadow topic", func(t *testing.T) {
+		data := []byte(`{"id":"1"}`)
+		err := rt.Publish(&pubsub.PublishRequest{
+			PubsubName: TestPubsubName,
+			Topic:      "orders",
+			Data:       data,
+			Metadata:   map[string]string{runtimePubsub.ShadowTopicMetadataKey: "orders-shadow", "ttlInSeconds": "10"},
+		})
+		require.NoError(t, err)
+		// The API servers can reuse the buffer of the data
+		copy(data, "xxxxxxxxxx")
+
+		primary := receive(t)
+		assert.Equal(t, "orders", primary.Topic)
+		assert.Equal(t, map[string]string{"ttlInSeconds": "10"}, primary.Metadata)
+
+		shadow := receive(t)
+		assert.Equal(t, "orders-v2", shadow.Topic)
+		assert.Equal(t, `{"id":"1"}`, string(shadow.Data))
+		assert.Equal(t, map[string]string{"ttlInSeconds": "10"}, shadow.Metadata)
+	})
+
+	t.Run("the failures of the shadow topic don't affect the primary delivery", func(t *testing.T) {
+		err := rt.Publish(&pubsub.PublishRequest{
+			PubsubName: TestPubsubName,
+			Topic:      "orders",
+			Metadata:   map[string]string{runtimePubsub.ShadowTopicMetadataKey: "failing-shadow"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "orders", receive(t).Topic)
+	})
+
+	t.Run("the message isn't mirrored when the primary publish fails", func(t *testing.T) {
+		err := rt.Publish(&pubsub.PublishRequest{
+			PubsubName: TestPubsubName,
+			Topic:      "failing-shadow",
+			Metadata:   map[string]string{runtimePubsub.ShadowTopicMetadataKey: "orders-shadow"},
+		})
+		require.Error(t, err)
+		select {
+		case <-component.published:
+			t.Fatal("the message was mirrored")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+func TestPublishActorLifecycleEvent(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.globalConfig.Spec.ActorsSpec.LifecycleEvents = config.ActorLifecycleEventsSpec{PubsubName: TestPubsubName}
+
+	var published *pubsub.PublishRequest
+	mockPubSub := new(daprt.MockPubSub)
+	mockPubSub.On("Publish", mock.MatchedBy(func(req *pubsub.PublishRequest) bool {
+		published = req
+		return true
+	})).Return(nil)
+	rt.pubSubs[TestPubsubName] = pubsubItem{component: mockPubSub}
+
+	err := rt.publishActorLifecycleEvent(actors.LifecycleEvent{
+		Type:      actors.LifecycleEventDeactivated,
+		ActorType: "cat",
+		ActorID:   "1",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, published)
+	assert.Equal(t, defaultActorLifecycleEventsTopic, published.Topic)
+
+	var envelope map[string]interface{}
+	require.NoError(t, json.Unmarshal(published.Data, &envelope))
+	assert.Equal(t, "io.dapr.actor.deactivated", envelope[pubsub.TypeField])
+	assert.Equal(t, "cat/1", envelope[pubsub.SubjectField])
+	assert.Equal(t, TestRuntimeConfigID, envelope[pubsub.SourceField])
+	data, ok := envelope[pubsub.DataField].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "1", data["actorId"])
+}
+
 func TestGetSubscribedBindingsGRPC(t *testing.T) {
 	testCases := []struct {
 		name             string
@@ -3657,6 +4321,7 @@ func NewTestDaprRuntimeWithProtocol(mode modes.DaprMode, protocol string, appPor
 	rt.httpMiddlewareRegistry = httpMiddlewareLoader.NewRegistry()
 	rt.configurationStoreRegistry = configurationLoader.NewRegistry()
 	rt.lockStoreRegistry = lockLoader.NewRegistry()
+	rt.cryptoProviderRegistry = cryptoLoader.NewRegistry()
 
 	return rt
 }
@@ -3668,7 +4333,7 @@ func NewTestDaprRuntimeConfig(mode modes.DaprMode, protocol string, appPort int)
 		controlPlaneAddress:          "10.10.10.11",
 		AllowedOrigins:               cors.DefaultAllowedOrigins,
 		GlobalConfig:                 "globalConfig",
-		ComponentsPath:               "",
+		ResourcesPath:                nil,
 		AppProtocol:                  protocol,
 		Mode:                         string(mode),
 		HTTPPort:                     DefaultDaprHTTPPort,
@@ -3776,6 +4441,22 @@ func (b *mockBinding) Close() error {
 	return b.closeErr
 }
 
+// mockValidatingBinding is a binding validating its credentials after the initialization.
+type mockValidatingBinding struct {
+	mockBinding
+	validateErr error
+	closed      bool
+}
+
+func (b *mockValidatingBinding) ValidateCredentials(ctx context.Context) error {
+	return b.validateErr
+}
+
+func (b *mockValidatingBinding) Close() error {
+	b.closed = true
+	return nil
+}
+
 func TestInvokeOutputBindings(t *testing.T) {
 	t.Run("output binding missing operation", func(t *testing.T) {
 		rt := NewTestDaprRuntime(modes.StandaloneMode)
@@ -3814,6 +4495,71 @@ func TestInvokeOutputBindings(t *testing.T) {
 	})
 }
 
+func TestWaitForAppStartup(t *testing.T) {
+	newRuntime := func(timeout time.Duration) (*DaprRuntime, *channelt.MockAppChannel) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		rt.runtimeConfig.AppStartupProbe = &AppStartupProbeConfig{
+			Method:   "/ready",
+			Interval: 10 * time.Millisecond,
+			Timeout:  timeout,
+		}
+		mockAppChannel := new(channelt.MockAppChannel)
+		rt.appChannel = mockAppChannel
+		return rt, mockAppChannel
+	}
+
+	t.Run("app becomes ready", func(t *testing.T) {
+		rt, mockAppChannel := newRuntime(0)
+		defer stopRuntime(t, rt)
+
+		probeReq := invokev1.NewInvokeMethodRequest("ready").WithHTTPExtension(http.MethodGet, "")
+		mockAppChannel.On("InvokeMethod", mock.Anything, probeReq).
+			Return(invokev1.NewInvokeMethodResponse(503, "Service Unavailable", nil), nil).Twice()
+		mockAppChannel.On("InvokeMethod", mock.Anything, probeReq).
+			Return(invokev1.NewInvokeMethodResponse(200, "OK", nil), nil).Once()
+
+		assert.True(t, rt.waitForAppStartup(rt.ctx))
+		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 3)
+	})
+
+	t.Run("gRPC app becomes ready", func(t *testing.T) {
+		rt, mockAppChannel := newRuntime(0)
+		defer stopRuntime(t, rt)
+
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).
+			Return(nil, errors.New("connection refused")).Once()
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).
+			Return(invokev1.NewInvokeMethodResponse(int32(codes.OK), "", nil), nil).Once()
+
+		assert.True(t, rt.waitForAppStartup(rt.ctx))
+		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 2)
+	})
+
+	t.Run("events are delivered after the timeout", func(t *testing.T) {
+		rt, mockAppChannel := newRuntime(50 * time.Millisecond)
+		defer stopRuntime(t, rt)
+
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).
+			Return(invokev1.NewInvokeMethodResponse(503, "Service Unavailable", nil), nil)
+
+		assert.True(t, rt.waitForAppStartup(rt.ctx))
+	})
+
+	t.Run("runtime is shutting down", func(t *testing.T) {
+		rt, mockAppChannel := newRuntime(0)
+		defer stopRuntime(t, rt)
+
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).
+			Return(invokev1.NewInvokeMethodResponse(503, "Service Unavailable", nil), nil)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			rt.cancel()
+		}()
+		assert.False(t, rt.waitForAppStartup(rt.ctx))
+	})
+}
+
 func TestReadInputBindings(t *testing.T) {
 	const testInputBindingName = "inputbinding"
 	const testInputBindingMethod = "inputbinding"
@@ -3953,6 +4699,56 @@ func TestReadInputBindings(t *testing.T) {
 	})
 }
 
+func TestPauseInputBinding(t *testing.T) {
+	const testInputBindingName = "inputbinding"
+
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.appChannel = new(channelt.MockAppChannel)
+	rt.runtimeConfig.ApplicationProtocol = GRPCProtocol
+	rt.subscribeBindingList = []string{testInputBindingName}
+
+	closeCh := make(chan struct{}, 1)
+	b := &daprt.MockBinding{}
+	b.SetOnReadCloseCh(closeCh)
+	b.On("Read", mock.MatchedBy(matchContextInterface), mock.Anything).Return(nil)
+	rt.inputBindings[testInputBindingName] = b
+
+	require.NoError(t, rt.startReadingFromBindings())
+	b.AssertNumberOfCalls(t, "Read", 1)
+	assert.Empty(t, rt.PausedInputBindings())
+
+	t.Run("pause stops reading from the binding", func(t *testing.T) {
+		require.NoError(t, rt.PauseInputBinding(testInputBindingName))
+		select {
+		case <-closeCh:
+			// All good
+		case <-time.After(time.Second):
+			t.Fatal("timeout while waiting for binding to stop reading")
+		}
+		assert.Equal(t, []string{testInputBindingName}, rt.PausedInputBindings())
+
+		// Pausing twice is a no-op
+		require.NoError(t, rt.PauseInputBinding(testInputBindingName))
+		assert.Error(t, rt.PauseInputBinding("unknown"))
+	})
+
+	t.Run("the pause is kept when restarting the bindings", func(t *testing.T) {
+		rt.stopReadingFromBindings()
+		require.NoError(t, rt.startReadingFromBindings())
+		b.AssertNumberOfCalls(t, "Read", 1)
+		assert.Equal(t, []string{testInputBindingName}, rt.PausedInputBindings())
+	})
+
+	t.Run("resume reads from the binding again", func(t *testing.T) {
+		require.NoError(t, rt.ResumeInputBinding(testInputBindingName))
+		b.AssertNumberOfCalls(t, "Read", 2)
+		assert.Empty(t, rt.PausedInputBindings())
+
+		assert.Error(t, rt.ResumeInputBinding(testInputBindingName))
+	})
+}
+
 func TestNamespace(t *testing.T) {
 	t.Run("empty namespace", func(t *testing.T) {
 		rt := NewTestDaprRuntime(modes.StandaloneMode)
@@ -4285,6 +5081,48 @@ func TestInitBindings(t *testing.T) {
 		err = r.initBinding(output)
 		assert.NoError(t, err)
 	})
+
+	t.Run("output binding with rejected credentials", func(t *testing.T) {
+		r := NewDaprRuntime(&Config{}, &config.Configuration{}, &config.AccessControlList{}, resiliency.New(logger.NewLogger("test")))
+		r.bindingsRegistry = bindingsLoader.NewRegistry()
+		defer stopRuntime(t, r)
+		b := &mockValidatingBinding{validateErr: errors.New("NOAUTH Authentication required")}
+		r.bindingsRegistry.RegisterOutputBinding(
+			func(_ logger.Logger) bindings.OutputBinding {
+				return b
+			},
+			"testoutput",
+		)
+
+		c := componentsV1alpha1.Component{}
+		c.ObjectMeta.Name = "testoutput"
+		c.Spec.Type = "bindings.testoutput"
+		err := r.initBinding(c)
+		require.Error(t, err)
+		assert.Equal(t, validation.ClassCredentials, validation.Classify(err))
+		assert.True(t, b.closed)
+		assert.NotContains(t, r.outputBindings, "testoutput")
+	})
+
+	t.Run("output binding with valid credentials", func(t *testing.T) {
+		r := NewDaprRuntime(&Config{}, &config.Configuration{}, &config.AccessControlList{}, resiliency.New(logger.NewLogger("test")))
+		r.bindingsRegistry = bindingsLoader.NewRegistry()
+		defer stopRuntime(t, r)
+		b := &mockValidatingBinding{}
+		r.bindingsRegistry.RegisterOutputBinding(
+			func(_ logger.Logger) bindings.OutputBinding {
+				return b
+			},
+			"testoutput",
+		)
+
+		c := componentsV1alpha1.Component{}
+		c.ObjectMeta.Name = "testoutput"
+		c.Spec.Type = "bindings.testoutput"
+		assert.NoError(t, r.initBinding(c))
+		assert.False(t, b.closed)
+		assert.Contains(t, r.outputBindings, "testoutput")
+	})
 }
 
 func TestBindingResiliency(t *testing.T) {
@@ -4647,10 +5485,27 @@ func TestFindMatchingRoute(t *testing.T) {
 	rules := []*runtimePubsub.Rule{r}
 	path, shouldProcess, err := findMatchingRoute(rules, map[string]interface{}{
 		"type": "MyEventType",
-	})
+	}, nil)
 	require.NoError(t, err)
 	assert.Equal(t, "mypath", path)
 	assert.True(t, shouldProcess)
+
+	t.Run("match on the metadata of the message", func(t *testing.T) {
+		r, err := createRoutingRule(`has(metadata.priority) && metadata.priority == "high"`, "urgent")
+		require.NoError(t, err)
+		rules := []*runtimePubsub.Rule{r, {Path: "default"}}
+		cloudEvent := map[string]interface{}{"type": "MyEventType"}
+
+		path, shouldProcess, err := findMatchingRoute(rules, cloudEvent, map[string]string{"priority": "high"})
+		require.NoError(t, err)
+		assert.Equal(t, "urgent", path)
+		assert.True(t, shouldProcess)
+
+		path, shouldProcess, err = findMatchingRoute(rules, cloudEvent, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "default", path)
+		assert.True(t, shouldProcess)
+	})
 }
 
 func createRoutingRule(match, path string) (*runtimePubsub.Rule, error) {
@@ -4838,6 +5693,93 @@ func TestGetComponentsCapabilitiesMap(t *testing.T) {
 	assert.Equal(t, 3, len(capabilities))
 }
 
+func TestGetRuntimeMetadata(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+
+	rt.globalConfig.Spec.Features = []config.FeatureSpec{
+		{Name: "Feature1", Enabled: true},
+		{Name: "Feature2", Enabled: false},
+	}
+
+	mockStateStore := new(daprt.MockStateStore)
+	mockStateStore.On("Init", mock.Anything).Return(nil)
+	rt.stateStoreRegistry.RegisterComponent(
+		func(_ logger.Logger) state.Store {
+			return mockStateStore
+		},
+		"mockState",
+	)
+	failingStateStore := new(daprt.MockStateStore)
+	failingStateStore.On("Init", mock.Anything).Return(assert.AnError)
+	rt.stateStoreRegistry.RegisterComponent(
+		func(_ logger.Logger) state.Store {
+			return failingStateStore
+		},
+		"failingState",
+	)
+
+	cStateStore := componentsV1alpha1.Component{}
+	cStateStore.ObjectMeta.Name = "testStateStore"
+	cStateStore.Spec.Type = "state.mockState"
+	cFailing := componentsV1alpha1.Component{}
+	cFailing.ObjectMeta.Name = "failingStateStore"
+	cFailing.Spec.Type = "state.failingState"
+
+	require.NoError(t, rt.processComponentAndDependents(cStateStore))
+	require.Error(t, rt.processComponentAndDependents(cFailing))
+
+	info := rt.getRuntimeMetadata()
+	require.Len(t, info.ComponentStatuses, 2)
+	assert.Equal(t, "failingStateStore", info.ComponentStatuses[0].Name)
+	assert.Equal(t, meta.ComponentStatusFailed, info.ComponentStatuses[0].Status)
+	assert.Contains(t, info.ComponentStatuses[0].LastError, assert.AnError.Error())
+	assert.Equal(t, "testStateStore", info.ComponentStatuses[1].Name)
+	assert.Equal(t, meta.ComponentStatusInitialized, info.ComponentStatuses[1].Status)
+	assert.Equal(t, []string{"Feature1"}, info.EnabledFeatures)
+	assert.Contains(t, info.Features, config.FeatureStatus{Name: "Feature1", Enabled: true})
+	assert.Contains(t, info.Features, config.FeatureStatus{Name: config.Resiliency, Registered: true})
+	assert.Equal(t, rt.runtimeConfig.ApplicationPort, info.AppConnection.Port)
+	assert.Equal(t, string(rt.runtimeConfig.ApplicationProtocol), info.AppConnection.Protocol)
+	assert.Empty(t, info.ActorTypes)
+}
+
+// lagPubSub is a pub/sub component reporting the lag of its topics.
+type lagPubSub struct {
+	daprt.MockPubSub
+}
+
+func (p *lagPubSub) Lag(ctx context.Context, topic string) (int64, error) {
+	if topic == "physical-orders" {
+		return 7, nil
+	}
+	return 0, errors.New("unknown topic")
+}
+
+func TestGetSubscriptionScalingSignals(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+
+	rt.pubSubs["kafka"] = pubsubItem{
+		component:    &lagPubSub{},
+		topicAliases: map[string]string{"orders": "physical-orders"},
+	}
+	rt.pubSubs["redis"] = pubsubItem{component: &daprt.MockPubSub{}}
+	rt.subscriptionStats.DeliveryStarted("kafka", "orders")
+	rt.subscriptionStats.DeliveryStarted("kafka", "payments")
+	rt.subscriptionStats.DeliveryStarted("redis", "orders")
+
+	signals := rt.getSubscriptionScalingSignals()
+	require.Len(t, signals, 3)
+	assert.Equal(t, "orders", signals[0].Topic)
+	assert.Equal(t, int64(1), signals[0].PendingDeliveries)
+	if assert.NotNil(t, signals[0].Lag) {
+		assert.Equal(t, int64(7), *signals[0].Lag)
+	}
+	assert.Nil(t, signals[1].Lag)
+	assert.Nil(t, signals[2].Lag)
+}
+
 func runGRPCApp(port int) (func(), error) {
 	serverListener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
 	if err != nil {
@@ -4898,3 +5840,17 @@ func matchContextInterface(v any) bool {
 	_, ok := v.(context.Context)
 	return ok
 }
+
+func TestCreateAppChannelInvalidPipeline(t *testing.T) {
+	port, _ := freeport.GetFreePort()
+	rt := NewTestDaprRuntimeWithProtocol(modes.StandaloneMode, string(HTTPProtocol), port)
+	defer stopRuntime(t, rt)
+	rt.globalConfig.Spec.AppHTTPPipelineSpec = config.PipelineSpec{
+		Handlers: []config.HandlerSpec{{Name: "notfound", Type: "middleware.http.uppercase", Version: "v1"}},
+	}
+
+	// The requests must not be sent to the app without the middlewares of the pipeline
+	err := rt.createAppChannel()
+	assert.ErrorContains(t, err, "failed to build app HTTP pipeline")
+	assert.Nil(t, rt.appChannel)
+}
@@ -17,6 +17,7 @@ import (
 	daprCredentials "github.com/dapr/dapr/pkg/credentials"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	sentryv1pb "github.com/dapr/dapr/pkg/proto/sentry/v1"
+	"github.com/dapr/dapr/pkg/sentry/certs"
 )
 
 const (
@@ -63,6 +64,25 @@ func newAuthenticator(sentryAddress string, trustAnchors *x509.CertPool, certCha
 
 // GetTrustAnchors returns the extracted root cert that serves as the trust anchor.
 func (a *authenticator) GetTrustAnchors() *x509.CertPool {
+	a.certMutex.RLock()
+	defer a.certMutex.RUnlock()
+	return a.trustAnchors
+}
+
+// reloadTrustAnchors reloads the trust anchors from the mounted trust bundle, which holds the previous and the new
+// root certs during a rotation, so that Sentry is trusted after its root cert changes.
+// The current trust anchors are kept if the trust bundle can't be read.
+func (a *authenticator) reloadTrustAnchors() *x509.CertPool {
+	a.certMutex.Lock()
+	defer a.certMutex.Unlock()
+
+	if path := os.Getenv(certs.TrustAnchorsFileEnvVar); path != "" {
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			if trustAnchors, err := CertPool(data); err == nil {
+				a.trustAnchors = trustAnchors
+			}
+		}
+	}
 	return a.trustAnchors
 }
 
@@ -82,7 +102,7 @@ func (a *authenticator) CreateSignedWorkloadCert(id, namespace, trustDomain stri
 	}
 	certPem := pem.EncodeToMemory(&pem.Block{Type: certType, Bytes: csrb})
 
-	config, err := daprCredentials.TLSConfigFromCertAndKey(a.certChainPem, a.keyPem, TLSServerName, a.trustAnchors)
+	config, err := daprCredentials.TLSConfigFromCertAndKey(a.certChainPem, a.keyPem, TLSServerName, a.reloadTrustAnchors())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create tls config from cert and key")
 	}
@@ -2,9 +2,13 @@ package security
 
 import (
 	"crypto/x509"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
 )
 
 func mockGenCSR(id string) ([]byte, []byte, error) {
@@ -21,6 +25,26 @@ func TestGetTrustAuthAnchors(t *testing.T) {
 	assert.NotNil(t, ta)
 }
 
+func TestReloadTrustAnchors(t *testing.T) {
+	a := getTestAuthenticator().(*authenticator)
+	initial := a.GetTrustAnchors()
+
+	t.Run("unreadable trust bundle keeps the trust anchors", func(t *testing.T) {
+		t.Setenv(certs.TrustAnchorsFileEnvVar, filepath.Join(t.TempDir(), "missing.crt"))
+		assert.Same(t, initial, a.reloadTrustAnchors())
+	})
+
+	t.Run("trust anchors reloaded from the trust bundle", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ca.crt")
+		assert.NoError(t, os.WriteFile(path, []byte(testRootCert), 0o600))
+		t.Setenv(certs.TrustAnchorsFileEnvVar, path)
+
+		reloaded := a.reloadTrustAnchors()
+		assert.NotSame(t, initial, reloaded)
+		assert.Same(t, reloaded, a.GetTrustAnchors())
+	})
+}
+
 func TestGetCurrentSignedCert(t *testing.T) {
 	a := getTestAuthenticator()
 	a.(*authenticator).currentSignedCert = &SignedCertificate{}
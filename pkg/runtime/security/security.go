@@ -6,6 +6,7 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"os"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -32,9 +33,9 @@ func CertPool(certPem []byte) (*x509.CertPool, error) {
 }
 
 func GetCertChain() (*credentials.CertChain, error) {
-	trustAnchors := os.Getenv(certs.TrustAnchorsEnvVar)
-	if trustAnchors == "" {
-		return nil, errors.Errorf("couldn't find trust anchors in environment variable %s", certs.TrustAnchorsEnvVar)
+	trustAnchors, err := getTrustAnchors()
+	if err != nil {
+		return nil, err
 	}
 	cert := os.Getenv(certs.CertChainEnvVar)
 	if cert == "" {
@@ -51,6 +52,24 @@ func GetCertChain() (*credentials.CertChain, error) {
 	}, nil
 }
 
+// getTrustAnchors returns the trust anchors from the file set in the environment, which is a mounted trust bundle ConfigMap in Kubernetes,
+// falling back to the trust anchors embedded in the environment.
+func getTrustAnchors() (string, error) {
+	if path := os.Getenv(certs.TrustAnchorsFileEnvVar); path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil && len(data) > 0 {
+			return string(data), nil
+		}
+		log.Warnf("couldn't read trust anchors from file %s, falling back to environment variable %s: %v", path, certs.TrustAnchorsEnvVar, err)
+	}
+
+	trustAnchors := os.Getenv(certs.TrustAnchorsEnvVar)
+	if trustAnchors == "" {
+		return "", errors.Errorf("couldn't find trust anchors in environment variable %s", certs.TrustAnchorsEnvVar)
+	}
+	return trustAnchors, nil
+}
+
 // GetSidecarAuthenticator returns a new authenticator with the extracted trust anchors.
 func GetSidecarAuthenticator(sentryAddress string, certChain *credentials.CertChain) (Authenticator, error) {
 	trustAnchors, err := CertPool(certChain.RootCA)
@@ -62,6 +81,17 @@ func GetSidecarAuthenticator(sentryAddress string, certChain *credentials.CertCh
 	return newAuthenticator(sentryAddress, trustAnchors, certChain.Cert, certChain.Key, generateCSRAndPrivateKey), nil
 }
 
+// certDNSNames returns the DNS names requested on the certificate in addition to the id, which must be allowed by Sentry.
+func certDNSNames() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv(certs.CertDNSNamesEnvVar), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 func generateCSRAndPrivateKey(id string) ([]byte, []byte, error) {
 	if id == "" {
 		return nil, nil, errors.New("id must not be empty")
@@ -82,7 +112,7 @@ func generateCSRAndPrivateKey(id string) ([]byte, []byte, error) {
 
 	csr := x509.CertificateRequest{
 		Subject:  pkix.Name{CommonName: id},
-		DNSNames: []string{id},
+		DNSNames: append([]string{id}, certDNSNames()...),
 	}
 	csrb, err := x509.CreateCertificateRequest(rand.Reader, &csr, key)
 	if err != nil {
@@ -1,10 +1,14 @@
 package security
 
 import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dapr/dapr/pkg/sentry/certs"
 )
@@ -44,6 +48,30 @@ func TestGetTrustAnchors(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, caPool)
 	})
+
+	t.Run("root cert from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ca.crt")
+		assert.NoError(t, os.WriteFile(path, []byte(testRootCert), 0o600))
+		t.Setenv(certs.TrustAnchorsFileEnvVar, path)
+		t.Setenv(certs.TrustAnchorsEnvVar, "")
+		t.Setenv(certs.CertChainEnvVar, "111")
+		t.Setenv(certs.CertKeyEnvVar, "111")
+
+		certChain, err := GetCertChain()
+		assert.Nil(t, err)
+		assert.Equal(t, testRootCert, string(certChain.RootCA))
+	})
+
+	t.Run("missing file falls back to env var", func(t *testing.T) {
+		t.Setenv(certs.TrustAnchorsFileEnvVar, filepath.Join(t.TempDir(), "missing.crt"))
+		t.Setenv(certs.TrustAnchorsEnvVar, testRootCert)
+		t.Setenv(certs.CertChainEnvVar, "111")
+		t.Setenv(certs.CertKeyEnvVar, "111")
+
+		certChain, err := GetCertChain()
+		assert.Nil(t, err)
+		assert.Equal(t, testRootCert, string(certChain.RootCA))
+	})
 }
 
 func TestGenerateSidecarCSR(t *testing.T) {
@@ -63,6 +91,15 @@ func TestGenerateSidecarCSR(t *testing.T) {
 		assert.True(t, len(csr) > 0)
 		assert.True(t, len(pk) > 0)
 	})
+
+	t.Run("with dns names", func(t *testing.T) {
+		t.Setenv(certs.CertDNSNamesEnvVar, "orders.example.com, orders.contoso.com,")
+		csrb, _, err := generateCSRAndPrivateKey("test")
+		require.NoError(t, err)
+		csr, err := x509.ParseCertificateRequest(csrb)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"test", "orders.example.com", "orders.contoso.com"}, csr.DNSNames)
+	})
 }
 
 func TestInitSidecarAuthenticator(t *testing.T) {
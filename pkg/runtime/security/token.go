@@ -10,6 +10,10 @@ const (
 	// APITokenEnvVar is the environment variable for the api token.
 	APITokenEnvVar    = "DAPR_API_TOKEN"
 	AppAPITokenEnvVar = "APP_API_TOKEN"
+	// PublicAPITokenEnvVar is the environment variable for the token of the calls to the public port.
+	PublicAPITokenEnvVar = "DAPR_PUBLIC_API_TOKEN"
+	// PlacementTokenEnvVar is the environment variable for the bearer token sent to a placement service outside of the cluster.
+	PlacementTokenEnvVar = "DAPR_PLACEMENT_TOKEN"
 	// APITokenHeader is header name for http/gRPC calls to hold the token.
 	APITokenHeader = "dapr-api-token"
 )
@@ -26,6 +30,11 @@ func GetAppToken() string {
 	return os.Getenv(AppAPITokenEnvVar)
 }
 
+// GetPublicAPIToken returns the value of the public api token from an environment variable.
+func GetPublicAPIToken() string {
+	return os.Getenv(PublicAPITokenEnvVar)
+}
+
 // ExcludedRoute returns whether a given route should be excluded from a token check.
 func ExcludedRoute(route string) bool {
 	for _, r := range excludedRoutes {
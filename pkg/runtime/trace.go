@@ -14,11 +14,19 @@ limitations under the License.
 package runtime
 
 import (
+	"context"
+
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/dapr/dapr/pkg/runtime/meta"
 )
 
+// metadataAttributePrefix is the prefix of the span attributes holding the metadata attributes set by the app.
+const metadataAttributePrefix = "dapr.metadata."
+
 // tracerProviderStore allows us to capture the trace provider options
 // and set a trace provider as per those settings
 //
@@ -29,20 +37,22 @@ type tracerProviderStore interface {
 	RegisterExporter(exporter sdktrace.SpanExporter)
 	RegisterResource(res *resource.Resource)
 	RegisterSampler(sampler sdktrace.Sampler)
+	RegisterSpanProcessor(processor sdktrace.SpanProcessor)
 	RegisterTracerProvider()
 }
 
 // newOpentelemetryTracerProviderStore returns an opentelemetryOptionsStore
 func newOpentelemetryTracerProviderStore() *opentelemetryTracerProviderStore {
 	exps := []sdktrace.SpanExporter{}
-	return &opentelemetryTracerProviderStore{exps, nil, nil}
+	return &opentelemetryTracerProviderStore{exps, nil, nil, nil}
 }
 
 // opentelemetryOptionsStore is an implementation of traceOptionsStore
 type opentelemetryTracerProviderStore struct {
-	exporters []sdktrace.SpanExporter
-	res       *resource.Resource
-	sampler   sdktrace.Sampler
+	exporters  []sdktrace.SpanExporter
+	res        *resource.Resource
+	sampler    sdktrace.Sampler
+	processors []sdktrace.SpanProcessor
 }
 
 // RegisterExporter adds a Span Exporter for registration with open telemetry global trace provider
@@ -61,10 +71,19 @@ func (s *opentelemetryTracerProviderStore) RegisterSampler(sampler sdktrace.Samp
 	s.sampler = sampler
 }
 
+// RegisterSpanProcessor adds a span processor for registration with open telemetry global trace provider
+func (s *opentelemetryTracerProviderStore) RegisterSpanProcessor(processor sdktrace.SpanProcessor) {
+	s.processors = append(s.processors, processor)
+}
+
 // RegisterTraceProvider registers a trace provider as per the tracer options in the store
 func (s *opentelemetryTracerProviderStore) RegisterTracerProvider() {
 	if len(s.exporters) != 0 {
 		tracerOptions := []sdktrace.TracerProviderOption{}
+		// The span processors must run before the exporters to add attributes to the spans
+		for _, processor := range s.processors {
+			tracerOptions = append(tracerOptions, sdktrace.WithSpanProcessor(processor))
+		}
 		for _, exporter := range s.exporters {
 			tracerOptions = append(tracerOptions, sdktrace.WithBatcher(exporter))
 		}
@@ -88,15 +107,16 @@ func (s *opentelemetryTracerProviderStore) RegisterTracerProvider() {
 //
 // This is only for use in unit tests.
 type fakeTracerProviderStore struct {
-	exporters []sdktrace.SpanExporter
-	res       *resource.Resource
-	sampler   sdktrace.Sampler
+	exporters  []sdktrace.SpanExporter
+	res        *resource.Resource
+	sampler    sdktrace.Sampler
+	processors []sdktrace.SpanProcessor
 }
 
 // newFakeTracerProviderStore returns an opentelemetryOptionsStore
 func newFakeTracerProviderStore() *fakeTracerProviderStore {
 	exps := []sdktrace.SpanExporter{}
-	return &fakeTracerProviderStore{exps, nil, nil}
+	return &fakeTracerProviderStore{exps, nil, nil, nil}
 }
 
 // RegisterExporter adds a Span Exporter for registration with open telemetry global trace provider
@@ -115,5 +135,39 @@ func (s *fakeTracerProviderStore) RegisterSampler(sampler sdktrace.Sampler) {
 	s.sampler = sampler
 }
 
+// RegisterSpanProcessor adds a span processor for registration with open telemetry global trace provider
+func (s *fakeTracerProviderStore) RegisterSpanProcessor(processor sdktrace.SpanProcessor) {
+	s.processors = append(s.processors, processor)
+}
+
 // RegisterTraceProvider does nothing
 func (s *fakeTracerProviderStore) RegisterTracerProvider() {}
+
+// metadataAttributesSpanProcessor adds the metadata attributes set by the app to the spans when they start.
+type metadataAttributesSpanProcessor struct {
+	attributes *meta.AttributeStore
+}
+
+func newMetadataAttributesSpanProcessor(attributes *meta.AttributeStore) *metadataAttributesSpanProcessor {
+	return &metadataAttributesSpanProcessor{attributes: attributes}
+}
+
+// OnStart adds the metadata attributes to the span.
+func (p *metadataAttributesSpanProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	for _, attr := range p.attributes.List() {
+		s.SetAttributes(attribute.String(metadataAttributePrefix+attr.Key, attr.Value))
+	}
+}
+
+// OnEnd does nothing.
+func (p *metadataAttributesSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+// Shutdown does nothing.
+func (p *metadataAttributesSpanProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush does nothing.
+func (p *metadataAttributesSpanProcessor) ForceFlush(context.Context) error {
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"hash/fnv"
+	nethttp "net/http"
+	"sync"
+
+	"github.com/dapr/dapr/pkg/actors"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+)
+
+// MockActors is an in-memory actor runtime for tests, which places the workflow instances on the engines added as
+// hosts, and serializes the calls to each instance.
+type MockActors struct {
+	data      map[string][]byte
+	reminders map[string]actors.CreateReminderRequest
+	hosts     []*Engine
+	locks     map[string]*sync.Mutex
+	lock      sync.Mutex
+}
+
+// NewMockActors returns a new MockActors without hosts.
+func NewMockActors() *MockActors {
+	return &MockActors{
+		data:      map[string][]byte{},
+		reminders: map[string]actors.CreateReminderRequest{},
+		locks:     map[string]*sync.Mutex{},
+	}
+}
+
+// AddHost sets the actor runtime of the engine, and places workflow instances on it.
+func (m *MockActors) AddHost(e *Engine) {
+	e.SetActorRuntime(m)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.hosts = append(m.hosts, e)
+}
+
+// RemoveHost deactivates the instances of the engine, and places them on the other hosts.
+func (m *MockActors) RemoveHost(e *Engine) {
+	m.lock.Lock()
+	for i, h := range m.hosts {
+		if h == e {
+			m.hosts = append(m.hosts[:i:i], m.hosts[i+1:]...)
+			break
+		}
+	}
+	m.lock.Unlock()
+
+	e.lock.Lock()
+	ids := make([]string, 0, len(e.instances))
+	for id := range e.instances {
+		ids = append(ids, id)
+	}
+	e.lock.Unlock()
+	for _, id := range ids {
+		e.DeactivateActor(context.Background(), id)
+	}
+}
+
+// Host returns the engine the instance is placed on.
+func (m *MockActors) Host(instanceID string) *Engine {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	h := fnv.New32a()
+	h.Write([]byte(instanceID))
+	return m.hosts[int(h.Sum32())%len(m.hosts)]
+}
+
+// FireReminders invokes the reminders of the instances on their hosts.
+func (m *MockActors) FireReminders() {
+	m.lock.Lock()
+	reminders := make([]actors.CreateReminderRequest, 0, len(m.reminders))
+	for _, r := range m.reminders {
+		reminders = append(reminders, r)
+	}
+	m.lock.Unlock()
+
+	for _, r := range reminders {
+		req := invokev1.NewInvokeMethodRequest("remind/"+r.Name).WithActor(r.ActorType, r.ActorID)
+		m.Call(context.Background(), req)
+	}
+}
+
+// HasReminder returns true if the instance has a reminder.
+func (m *MockActors) HasReminder(instanceID string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, r := range m.reminders {
+		if r.ActorID == instanceID {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of keys in the state store.
+func (m *MockActors) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.data)
+}
+
+func (m *MockActors) Call(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	actorID := req.Actor().GetActorId()
+	m.lock.Lock()
+	l, ok := m.locks[actorID]
+	if !ok {
+		l = &sync.Mutex{}
+		m.locks[actorID] = l
+	}
+	m.lock.Unlock()
+
+	l.Lock()
+	defer l.Unlock()
+	_, data := req.RawData()
+	respData, err := m.Host(actorID).InvokeMethod(ctx, actorID, req.Message().Method, data)
+	if err != nil {
+		return nil, err
+	}
+	return invokev1.NewInvokeMethodResponse(nethttp.StatusOK, "OK", nil).WithRawData(respData, invokev1.JSONContentType), nil
+}
+
+func (m *MockActors) GetState(ctx context.Context, req *actors.GetStateRequest) (*actors.StateResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return &actors.StateResponse{Data: m.data[req.ActorType+"||"+req.ActorID+"||"+req.Key]}, nil
+}
+
+func (m *MockActors) TransactionalStateOperation(ctx context.Context, req *actors.TransactionalRequest) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, o := range req.Operations {
+		r := o.Request.(map[string]interface{})
+		key := req.ActorType + "||" + req.ActorID + "||" + r["key"].(string)
+		switch o.Operation {
+		case actors.Upsert:
+			m.data[key] = r["value"].([]byte)
+		case actors.Delete:
+			delete(m.data, key)
+		}
+	}
+	return nil
+}
+
+func (m *MockActors) CreateReminder(ctx context.Context, req *actors.CreateReminderRequest) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.reminders[req.ActorType+"||"+req.ActorID+"||"+req.Name] = *req
+	return nil
+}
+
+func (m *MockActors) DeleteReminder(ctx context.Context, req *actors.DeleteReminderRequest) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.reminders, req.ActorType+"||"+req.ActorID+"||"+req.Name)
+	return nil
+}
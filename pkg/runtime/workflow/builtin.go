@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// InvokeActivity invokes a method of an app with service invocation, and returns its response.
+	InvokeActivity = "dapr.invoke"
+	// SequenceWorkflow executes its steps in order, and returns their results.
+	SequenceWorkflow = "dapr.sequence"
+)
+
+// InvokeRequest is the input of InvokeActivity.
+type InvokeRequest struct {
+	AppID  string          `json:"appId"`
+	Method string          `json:"method"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// SequenceStep is a step of SequenceWorkflow, which invokes a method of an app, waits for an event, or sleeps
+// for a duration with a durable timer. Its result is the response of the method, or the data of the event.
+type SequenceStep struct {
+	Invoke       *InvokeRequest `json:"invoke,omitempty"`
+	WaitForEvent string         `json:"waitForEvent,omitempty"`
+	// Sleep is a duration, such as "10m".
+	Sleep string `json:"sleep,omitempty"`
+}
+
+// SequenceInput is the input of SequenceWorkflow.
+type SequenceInput struct {
+	Steps []SequenceStep `json:"steps"`
+}
+
+// Invoker invokes a method of an app, and returns its response.
+type Invoker func(ctx context.Context, appID, method string, data []byte) ([]byte, error)
+
+// RegisterBuiltins registers the workflows and the activities built into the runtime, which invoke the apps with the
+// invoker. They let the apps run workflows without registering their own.
+func RegisterBuiltins(r *Registry, invoke Invoker) {
+	r.RegisterActivity(InvokeActivity, func(ctx context.Context, input []byte) (any, error) {
+		var req InvokeRequest
+		if err := json.Unmarshal(input, &req); err != nil {
+			return nil, errors.Wrap(err, "invalid invocation")
+		}
+		if req.AppID == "" || req.Method == "" {
+			return nil, errors.New("invalid invocation: the app id and the method are required")
+		}
+		resp, err := invoke(ctx, req.AppID, req.Method, req.Data)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp) == 0 {
+			return nil, nil
+		}
+		if !json.Valid(resp) {
+			return string(resp), nil
+		}
+		return json.RawMessage(resp), nil
+	})
+
+	r.RegisterWorkflow(SequenceWorkflow, func(ctx *Context) (any, error) {
+		var in SequenceInput
+		if err := ctx.GetInput(&in); err != nil {
+			return nil, errors.Wrap(err, "invalid input")
+		}
+
+		results := make([]json.RawMessage, len(in.Steps))
+		for i, step := range in.Steps {
+			var err error
+			switch {
+			case step.Invoke != nil:
+				err = ctx.CallActivity(InvokeActivity, step.Invoke, &results[i])
+			case step.WaitForEvent != "":
+				err = ctx.WaitForEvent(step.WaitForEvent, &results[i])
+			case step.Sleep != "":
+				var d time.Duration
+				if d, err = time.ParseDuration(step.Sleep); err == nil {
+					err = ctx.CreateTimer(fmt.Sprintf("step-%d", i), d)
+				}
+			default:
+				err = errors.New("the step must invoke a method, wait for an event or sleep")
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "step %d", i)
+			}
+		}
+		return results, nil
+	})
+}
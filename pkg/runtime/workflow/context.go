@@ -0,0 +1,348 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+)
+
+// errAborted stops the execution of a workflow when the instance is terminated or the engine is closed.
+var errAborted = errors.New("workflow execution aborted")
+
+// nonDeterminismError is raised when the steps of a replayed workflow don't match its history.
+type nonDeterminismError struct {
+	expected, actual string
+}
+
+func (e nonDeterminismError) Error() string {
+	return fmt.Sprintf("non-deterministic workflow: expected %s from the history, got %s", e.expected, e.actual)
+}
+
+// Context is passed to the workflows to execute their steps.
+type Context struct {
+	ctx    context.Context
+	engine *Engine
+	inst   *instance
+	// cursor is the position of the next step in the history of the instance.
+	cursor int
+}
+
+// InstanceID returns the ID of the workflow instance.
+func (c *Context) InstanceID() string {
+	c.inst.lock.Lock()
+	defer c.inst.lock.Unlock()
+	return c.inst.state.InstanceID
+}
+
+// Input returns the raw input of the workflow instance.
+func (c *Context) Input() []byte {
+	c.inst.lock.Lock()
+	defer c.inst.lock.Unlock()
+	return c.inst.state.Input
+}
+
+// GetInput decodes the JSON input of the workflow instance.
+func (c *Context) GetInput(v any) error {
+	return json.Unmarshal(c.Input(), v)
+}
+
+// IsReplaying returns true while the workflow is replaying its history.
+func (c *Context) IsReplaying() bool {
+	c.inst.lock.Lock()
+	defer c.inst.lock.Unlock()
+	return c.cursor < len(c.inst.state.History)
+}
+
+// SetCustomStatus sets a custom status on the workflow instance, which is saved with its next checkpoint.
+func (c *Context) SetCustomStatus(status string) {
+	c.inst.lock.Lock()
+	defer c.inst.lock.Unlock()
+	c.inst.state.CustomStatus = status
+}
+
+// CallActivity executes the activity with the JSON encoded input, and decodes its result in the output if not nil.
+// The result is checkpointed, so the activity is not executed again when the workflow is replayed.
+func (c *Context) CallActivity(name string, input any, output any) error {
+	if ev, ok := c.replay(EventActivityCompleted, name); ok {
+		return decodeEvent(ev, output)
+	}
+
+	c.await(nil)
+	workflowName := c.inst.state.WorkflowName
+	c.inst.lock.Unlock()
+
+	ev := HistoryEvent{
+		Type: EventActivityCompleted,
+		Name: name,
+	}
+	start := time.Now()
+	result, err := c.executeActivity(name, input)
+	if err != nil {
+		ev.Error = err.Error()
+	} else if result != nil {
+		ev.Data, err = json.Marshal(result)
+		if err != nil {
+			ev.Error = fmt.Sprintf("failed to serialize activity result: %s", err)
+		}
+	}
+	ev.Timestamp = time.Now().UTC()
+	elapsed := float64(time.Since(start) / time.Millisecond)
+	diag.DefaultWorkflowMonitoring.ActivityExecuted(workflowName, name, ev.Error == "", elapsed)
+
+	c.checkpoint(func(s *State) {
+		s.History = append(s.History, ev)
+	})
+	return decodeEvent(&ev, output)
+}
+
+// WaitForEvent blocks until an external event with the name is raised on the workflow instance,
+// and decodes its JSON data in the output if not nil.
+func (c *Context) WaitForEvent(name string, output any) error {
+	if ev, ok := c.replay(EventReceived, name); ok {
+		return decodeEvent(ev, output)
+	}
+
+	c.inst.lock.Lock()
+	c.inst.state.WaitingForEvent = name
+	c.inst.lock.Unlock()
+
+	c.await(func(s *State) bool {
+		for _, pe := range s.PendingEvents {
+			if pe.Name == name {
+				return true
+			}
+		}
+		return false
+	})
+	c.inst.lock.Unlock()
+
+	var ev HistoryEvent
+	c.checkpoint(func(s *State) {
+		s.WaitingForEvent = ""
+		for i, pe := range s.PendingEvents {
+			if pe.Name == name {
+				s.PendingEvents = append(s.PendingEvents[:i:i], s.PendingEvents[i+1:]...)
+				ev = HistoryEvent{
+					Type:      EventReceived,
+					Name:      name,
+					Data:      pe.Data,
+					Timestamp: time.Now().UTC(),
+				}
+				break
+			}
+		}
+		s.History = append(s.History, ev)
+	})
+	return decodeEvent(&ev, output)
+}
+
+// CreateTimer blocks until the durable timer with the name fires, after the delay.
+// The timer is checkpointed: it fires at the same time when the workflow is recovered, and it can be canceled or
+// rescheduled with the APIs. It returns ErrTimerCanceled if the timer is canceled.
+func (c *Context) CreateTimer(name string, delay time.Duration) error {
+	if ev, ok := c.replay(EventTimerFired, name); ok {
+		return decodeTimerEvent(ev)
+	}
+
+	id := c.createTimer(name, delay)
+	for {
+		c.await(nil)
+		t := findTimer(c.inst.state, id)
+		if t == nil || t.Canceled || !time.Now().Before(t.FireAt) {
+			break
+		}
+		changed := c.inst.changed
+		c.inst.lock.Unlock()
+
+		// The timer is rescheduled or canceled when the instance changes
+		timer := time.NewTimer(time.Until(t.FireAt))
+		select {
+		case <-timer.C:
+		case <-changed:
+		case <-c.ctx.Done():
+		}
+		timer.Stop()
+	}
+	defer c.inst.lock.Unlock()
+
+	ev := HistoryEvent{
+		Type:      EventTimerFired,
+		Name:      name,
+		Timestamp: time.Now().UTC(),
+	}
+	c.saveLocked(func(s *State) {
+		for i, t := range s.Timers {
+			if t.ID == id {
+				if t.Canceled {
+					ev.Error = "timer canceled"
+				}
+				s.Timers = append(s.Timers[:i:i], s.Timers[i+1:]...)
+				break
+			}
+		}
+		s.History = append(s.History, ev)
+	})
+	c.cursor++
+	return decodeTimerEvent(&ev)
+}
+
+// createTimer saves the timer of the current step, unless it was saved before the workflow was recovered,
+// and returns its ID.
+func (c *Context) createTimer(name string, delay time.Duration) string {
+	c.inst.lock.Lock()
+	defer c.inst.lock.Unlock()
+
+	// The timers are identified by their position in the history, so the workflow finds its timer when it's replayed
+	id := fmt.Sprintf("timer-%d", c.cursor)
+	if findTimer(c.inst.state, id) != nil {
+		return id
+	}
+	now := time.Now().UTC()
+	c.saveLocked(func(s *State) {
+		s.Timers = append(s.Timers, Timer{
+			ID:        id,
+			Name:      name,
+			FireAt:    now.Add(delay),
+			CreatedAt: now,
+		})
+	})
+	return id
+}
+
+func (c *Context) executeActivity(name string, input any) (result any, err error) {
+	activity, ok := c.engine.registry.getActivity(name)
+	if !ok {
+		return nil, errors.Errorf("activity %s is not registered", name)
+	}
+
+	var data []byte
+	if input != nil {
+		data, err = json.Marshal(input)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to serialize activity input")
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("activity panicked: %v", r)
+		}
+	}()
+	return activity(c.ctx, data)
+}
+
+// replay returns the next event of the history while the workflow is replayed.
+func (c *Context) replay(typ EventType, name string) (*HistoryEvent, bool) {
+	c.inst.lock.Lock()
+	defer c.inst.lock.Unlock()
+
+	if c.cursor >= len(c.inst.state.History) {
+		return nil, false
+	}
+	ev := c.inst.state.History[c.cursor]
+	if ev.Type != typ || ev.Name != name {
+		panic(nonDeterminismError{
+			expected: fmt.Sprintf("%s %s", ev.Type, ev.Name),
+			actual:   fmt.Sprintf("%s %s", typ, name),
+		})
+	}
+	c.cursor++
+	return &ev, true
+}
+
+// await blocks until the instance is running and ready returns true, and returns with the lock of the instance held.
+// It aborts the workflow if the instance is terminated or the engine is closed.
+func (c *Context) await(ready func(s *State) bool) {
+	for {
+		c.inst.lock.Lock()
+		if c.inst.state.Status == StatusTerminated || c.ctx.Err() != nil {
+			c.inst.lock.Unlock()
+			panic(errAborted)
+		}
+		if c.inst.state.Status == StatusRunning && (ready == nil || ready(c.inst.state)) {
+			return
+		}
+		changed := c.inst.changed
+		c.inst.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-c.ctx.Done():
+		}
+	}
+}
+
+// checkpoint appends a step to the state of the instance and saves it.
+// It aborts the workflow if the instance was terminated in the meantime, or if the state cannot be saved:
+// the instance is then recovered from its last checkpoint when it's loaded again.
+func (c *Context) checkpoint(fn func(s *State)) {
+	c.inst.lock.Lock()
+	defer c.inst.lock.Unlock()
+
+	c.saveLocked(fn)
+	c.cursor++
+}
+
+// saveLocked changes the state of the instance with the function and saves it, without adding a step to its history.
+// It must be called with the lock of the instance held.
+func (c *Context) saveLocked(fn func(s *State)) {
+	err := c.engine.updateLocked(c.ctx, c.inst, func(s *State) error {
+		if s.Status == StatusTerminated {
+			return errAborted
+		}
+		fn(s)
+		return nil
+	}, false)
+	if err == errAborted {
+		panic(errAborted)
+	} else if err != nil {
+		// The instance is recovered from its last checkpoint by the recovery reminder
+		log.Errorf("failed to checkpoint workflow instance %s: %s", c.inst.state.InstanceID, err)
+		panic(errAborted)
+	}
+}
+
+func decodeEvent(ev *HistoryEvent, output any) error {
+	if ev.Error != "" {
+		return errors.Errorf("activity %s failed: %s", ev.Name, ev.Error)
+	}
+	if output == nil || len(ev.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(ev.Data, output)
+}
+
+func decodeTimerEvent(ev *HistoryEvent) error {
+	if ev.Error != "" {
+		return errors.Wrapf(ErrTimerCanceled, "timer %s", ev.Name)
+	}
+	return nil
+}
+
+// findTimer returns the timer of the instance with the ID, or nil if it has no such timer.
+func findTimer(s *State, id string) *Timer {
+	for i := range s.Timers {
+		if s.Timers[i].ID == id {
+			return &s.Timers[i]
+		}
+	}
+	return nil
+}
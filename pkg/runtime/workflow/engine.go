@@ -0,0 +1,700 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workflow implements an embedded workflow engine hosted on the actor runtime.
+// The workflow instances are internal actors, so each of them is executed by a single host.
+// Their state is checkpointed in the actor state store after each step, and the instances are recovered on their
+// host by replaying their history.
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/actors"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// actorTypeSuffix is the suffix of the type of the internal actors of the workflow instances of an app.
+	actorTypeSuffix = ".workflow"
+
+	stateKey = "state"
+	// recoveryReminder keeps the running instances active on their host, and recovers them on their new host
+	// after they are moved, or after their host is restarted.
+	recoveryReminder       = "recovery"
+	recoveryReminderPeriod = "1m"
+)
+
+var log = logger.NewLogger("dapr.runtime.workflow")
+
+var (
+	ErrWorkflowNotFound    = errors.New("workflow is not registered")
+	ErrInstanceNotFound    = errors.New("workflow instance not found")
+	ErrInstanceExists      = errors.New("workflow instance already exists")
+	ErrInstanceCompleted   = errors.New("workflow instance is completed")
+	ErrInstanceNotComplete = errors.New("workflow instance is not completed")
+	ErrInstanceNotPaused   = errors.New("workflow instance is not paused")
+	ErrTimerNotFound       = errors.New("workflow timer not found")
+	ErrTimerCanceled       = errors.New("workflow timer was canceled")
+)
+
+// errorCodes identify the errors returned by the host of an instance to the engine which called it.
+var errorCodes = map[string]error{
+	"WorkflowNotFound":    ErrWorkflowNotFound,
+	"InstanceNotFound":    ErrInstanceNotFound,
+	"InstanceExists":      ErrInstanceExists,
+	"InstanceCompleted":   ErrInstanceCompleted,
+	"InstanceNotComplete": ErrInstanceNotComplete,
+	"InstanceNotPaused":   ErrInstanceNotPaused,
+	"TimerNotFound":       ErrTimerNotFound,
+}
+
+// Actors is the part of the actor runtime used by the engine. The workflow instances are internal actors: each of them
+// is executed by the host it's placed on, and checkpointed in the actor state store.
+type Actors interface {
+	Call(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error)
+	GetState(ctx context.Context, req *actors.GetStateRequest) (*actors.StateResponse, error)
+	TransactionalStateOperation(ctx context.Context, req *actors.TransactionalRequest) error
+	CreateReminder(ctx context.Context, req *actors.CreateReminderRequest) error
+	DeleteReminder(ctx context.Context, req *actors.DeleteReminderRequest) error
+}
+
+// Engine executes the workflows of a registry.
+type Engine struct {
+	registry  *Registry
+	actorType string
+	actors    Actors
+	// instances are the running instances hosted by the engine.
+	instances map[string]*instance
+	lock      sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// instance is a workflow instance loaded by the engine.
+type instance struct {
+	state *State
+	lock  sync.Mutex
+	// changed is closed and replaced when the instance is changed with the APIs, to wake up the workflow.
+	changed chan struct{}
+
+	// cancel stops the execution of the instance, and done is closed once it's stopped.
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ actors.InternalActor = (*Engine)(nil)
+
+// NewEngine returns a new workflow engine for the workflow instances of the app.
+// The engine must be added to the internal actors of the actor runtime, and the actor runtime set with SetActorRuntime
+// before the actor runtime is initialized.
+func NewEngine(registry *Registry, namespace, appID string) *Engine {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Engine{
+		registry:  registry,
+		actorType: actors.InternalActorTypePrefix + namespace + "." + appID + actorTypeSuffix,
+		instances: map[string]*instance{},
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// ActorType returns the type of the internal actors of the workflow instances.
+func (e *Engine) ActorType() string {
+	return e.actorType
+}
+
+// SetActorRuntime sets the actor runtime which hosts the workflow instances.
+func (e *Engine) SetActorRuntime(actors Actors) {
+	e.actors = actors
+}
+
+// startRequest is the request to start a workflow instance.
+type startRequest struct {
+	WorkflowName string `json:"workflowName"`
+	Input        []byte `json:"input,omitempty"`
+}
+
+// raiseEventRequest is the request to raise an event on a workflow instance.
+type raiseEventRequest struct {
+	EventName string `json:"eventName"`
+	Data      []byte `json:"data,omitempty"`
+}
+
+// timerRequest is the request to cancel or reschedule a timer of a workflow instance.
+type timerRequest struct {
+	TimerID string    `json:"timerID"`
+	FireAt  time.Time `json:"fireAt,omitempty"`
+}
+
+// actorResponse is the response of the host of a workflow instance.
+type actorResponse struct {
+	State     *State `json:"state,omitempty"`
+	Error     string `json:"error,omitempty"`
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+// hostError is an error returned by the host of a workflow instance.
+type hostError struct {
+	msg string
+	err error
+}
+
+func (e *hostError) Error() string {
+	return e.msg
+}
+
+func (e *hostError) Unwrap() error {
+	return e.err
+}
+
+// Start starts a new instance of the workflow with the input.
+func (e *Engine) Start(ctx context.Context, workflowName, instanceID string, input []byte) error {
+	if _, ok := e.registry.getWorkflow(workflowName); !ok {
+		return errors.Wrapf(ErrWorkflowNotFound, "workflow %s", workflowName)
+	}
+	_, err := e.call(ctx, instanceID, "start", startRequest{WorkflowName: workflowName, Input: input})
+	return err
+}
+
+// Get returns the state of the workflow instance.
+func (e *Engine) Get(ctx context.Context, instanceID string) (*State, error) {
+	return e.call(ctx, instanceID, "get", nil)
+}
+
+// Terminate stops the execution of the workflow instance.
+func (e *Engine) Terminate(ctx context.Context, instanceID string) error {
+	_, err := e.call(ctx, instanceID, "terminate", nil)
+	return err
+}
+
+// RaiseEvent sends an external event to the workflow instance, which can wait for it with Context.WaitForEvent.
+func (e *Engine) RaiseEvent(ctx context.Context, instanceID, eventName string, data []byte) error {
+	_, err := e.call(ctx, instanceID, "raiseEvent", raiseEventRequest{EventName: eventName, Data: data})
+	return err
+}
+
+// Pause suspends the execution of the workflow instance before its next step.
+func (e *Engine) Pause(ctx context.Context, instanceID string) error {
+	_, err := e.call(ctx, instanceID, "pause", nil)
+	return err
+}
+
+// Resume resumes the execution of a paused workflow instance.
+func (e *Engine) Resume(ctx context.Context, instanceID string) error {
+	_, err := e.call(ctx, instanceID, "resume", nil)
+	return err
+}
+
+// Purge deletes the state of a completed workflow instance.
+func (e *Engine) Purge(ctx context.Context, instanceID string) error {
+	_, err := e.call(ctx, instanceID, "purge", nil)
+	return err
+}
+
+// CancelTimer cancels a pending timer of the workflow instance: Context.CreateTimer returns ErrTimerCanceled.
+func (e *Engine) CancelTimer(ctx context.Context, instanceID, timerID string) error {
+	_, err := e.call(ctx, instanceID, "cancelTimer", timerRequest{TimerID: timerID})
+	return err
+}
+
+// RescheduleTimer changes the time a pending timer of the workflow instance fires at.
+// The timer fires immediately if the time is in the past.
+func (e *Engine) RescheduleTimer(ctx context.Context, instanceID, timerID string, fireAt time.Time) error {
+	_, err := e.call(ctx, instanceID, "rescheduleTimer", timerRequest{TimerID: timerID, FireAt: fireAt})
+	return err
+}
+
+// Close stops the execution of the workflow instances, which are recovered from their last checkpoint
+// when they are loaded again.
+func (e *Engine) Close() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+// call invokes the method on the actor of the instance, on the host the instance is placed on.
+func (e *Engine) call(ctx context.Context, instanceID, method string, body any) (*State, error) {
+	if e.actors == nil {
+		return nil, errors.New("the actor runtime of the workflow engine is not set")
+	}
+
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	req := invokev1.NewInvokeMethodRequest(method).
+		WithActor(e.actorType, instanceID).
+		WithRawData(data, invokev1.JSONContentType)
+	resp, err := e.actors.Call(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call the host of the workflow instance")
+	}
+
+	var res actorResponse
+	_, respData := resp.RawData()
+	if err = json.Unmarshal(respData, &res); err != nil {
+		return nil, errors.Wrap(err, "invalid response from the host of the workflow instance")
+	}
+	if res.Error != "" {
+		return nil, &hostError{msg: res.Error, err: errorCodes[res.ErrorCode]}
+	}
+	return res.State, nil
+}
+
+// InvokeMethod implements actors.InternalActor: it executes the calls on the instances hosted by the engine.
+// The actor runtime serializes the calls to an instance.
+func (e *Engine) InvokeMethod(ctx context.Context, instanceID, method string, data []byte) ([]byte, error) {
+	state, err := e.invoke(ctx, instanceID, method, data)
+
+	res := actorResponse{State: state}
+	if err != nil {
+		res.Error = err.Error()
+		for code, codeErr := range errorCodes {
+			if errors.Is(err, codeErr) {
+				res.ErrorCode = code
+				break
+			}
+		}
+	}
+	return json.Marshal(res)
+}
+
+func (e *Engine) invoke(ctx context.Context, instanceID, method string, data []byte) (*State, error) {
+	switch method {
+	case "start":
+		var req startRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		return nil, e.start(ctx, req.WorkflowName, instanceID, req.Input)
+	case "get":
+		inst, err := e.loadInstance(ctx, instanceID)
+		if err != nil {
+			return nil, err
+		}
+		inst.lock.Lock()
+		defer inst.lock.Unlock()
+		return inst.state.clone(), nil
+	case "terminate":
+		return nil, e.update(ctx, instanceID, func(s *State) error {
+			if s.Status.IsFinal() {
+				return errors.Wrapf(ErrInstanceCompleted, "instance %s", instanceID)
+			}
+			s.Status = StatusTerminated
+			return nil
+		})
+	case "raiseEvent":
+		var req raiseEventRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		return nil, e.update(ctx, instanceID, func(s *State) error {
+			if s.Status.IsFinal() {
+				return errors.Wrapf(ErrInstanceCompleted, "instance %s", instanceID)
+			}
+			s.PendingEvents = append(s.PendingEvents, RaisedEvent{
+				Name:      req.EventName,
+				Data:      req.Data,
+				Timestamp: time.Now().UTC(),
+			})
+			return nil
+		})
+	case "pause":
+		return nil, e.update(ctx, instanceID, func(s *State) error {
+			if s.Status.IsFinal() {
+				return errors.Wrapf(ErrInstanceCompleted, "instance %s", instanceID)
+			}
+			s.Status = StatusPaused
+			return nil
+		})
+	case "resume":
+		return nil, e.update(ctx, instanceID, func(s *State) error {
+			if s.Status != StatusPaused {
+				return errors.Wrapf(ErrInstanceNotPaused, "instance %s", instanceID)
+			}
+			s.Status = StatusRunning
+			return nil
+		})
+	case "cancelTimer":
+		var req timerRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		return nil, e.updateTimer(ctx, instanceID, req.TimerID, func(t *Timer) {
+			t.Canceled = true
+		})
+	case "rescheduleTimer":
+		var req timerRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		return nil, e.updateTimer(ctx, instanceID, req.TimerID, func(t *Timer) {
+			t.FireAt = req.FireAt.UTC()
+		})
+	case "purge":
+		return nil, e.purge(ctx, instanceID)
+	case "remind/" + recoveryReminder:
+		// Loading the instance recovers it if it's not running on this host yet
+		inst, err := e.loadInstance(ctx, instanceID)
+		if errors.Is(err, ErrInstanceNotFound) {
+			return nil, e.deleteRecoveryReminder(ctx, instanceID)
+		} else if err != nil {
+			return nil, err
+		}
+		inst.lock.Lock()
+		final := inst.state.Status.IsFinal()
+		inst.lock.Unlock()
+		if final {
+			return nil, e.deleteRecoveryReminder(ctx, instanceID)
+		}
+		return nil, nil
+	default:
+		return nil, errors.Errorf("unknown workflow instance method %s", method)
+	}
+}
+
+// DeactivateActor implements actors.InternalActor: it stops the execution of the instance on this host, when it's
+// moved to another host or when it's idle. Its execution is resumed from its last checkpoint when it's loaded again.
+func (e *Engine) DeactivateActor(ctx context.Context, instanceID string) error {
+	e.lock.Lock()
+	inst, ok := e.instances[instanceID]
+	delete(e.instances, instanceID)
+	e.lock.Unlock()
+
+	if ok {
+		inst.cancel()
+		<-inst.done
+	}
+	return nil
+}
+
+func (e *Engine) start(ctx context.Context, workflowName, instanceID string, input []byte) error {
+	if _, ok := e.registry.getWorkflow(workflowName); !ok {
+		return errors.Wrapf(ErrWorkflowNotFound, "workflow %s", workflowName)
+	}
+
+	existing, err := e.loadInstance(ctx, instanceID)
+	if err != nil && !errors.Is(err, ErrInstanceNotFound) {
+		return err
+	}
+	if existing != nil {
+		return errors.Wrapf(ErrInstanceExists, "instance %s", instanceID)
+	}
+
+	now := time.Now().UTC()
+	state := &State{
+		InstanceID:    instanceID,
+		WorkflowName:  workflowName,
+		Status:        StatusRunning,
+		Input:         input,
+		CreatedAt:     now,
+		LastUpdatedAt: now,
+	}
+	if err = e.save(ctx, state); err != nil {
+		return err
+	}
+	err = e.actors.CreateReminder(ctx, &actors.CreateReminderRequest{
+		Name:      recoveryReminder,
+		ActorType: e.actorType,
+		ActorID:   instanceID,
+		DueTime:   recoveryReminderPeriod,
+		Period:    recoveryReminderPeriod,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the recovery reminder of the workflow instance")
+	}
+
+	diag.DefaultWorkflowMonitoring.WorkflowStarted(workflowName)
+	e.run(e.newInstance(state))
+	return nil
+}
+
+func (e *Engine) purge(ctx context.Context, instanceID string) error {
+	inst, err := e.loadInstance(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	inst.lock.Lock()
+	final := inst.state.Status.IsFinal()
+	inst.lock.Unlock()
+	if !final {
+		return errors.Wrapf(ErrInstanceNotComplete, "instance %s", instanceID)
+	}
+
+	err = e.actors.TransactionalStateOperation(ctx, &actors.TransactionalRequest{
+		ActorType: e.actorType,
+		ActorID:   instanceID,
+		Operations: []actors.TransactionalOperation{{
+			Operation: actors.Delete,
+			Request:   map[string]interface{}{"key": stateKey},
+		}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete workflow instance state")
+	}
+	return nil
+}
+
+func (e *Engine) newInstance(state *State) *instance {
+	return &instance{
+		state:   state,
+		changed: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// loadInstance returns the instance running on this host, or loads it from the state store.
+// The loaded instances that are not completed are recovered and executed again, while the completed instances are
+// not kept in memory.
+// It must be called by the actor runtime, which makes sure the instance is only hosted by this host.
+func (e *Engine) loadInstance(ctx context.Context, instanceID string) (*instance, error) {
+	e.lock.Lock()
+	inst, ok := e.instances[instanceID]
+	e.lock.Unlock()
+	if ok {
+		return inst, nil
+	}
+
+	resp, err := e.actors.GetState(ctx, &actors.GetStateRequest{
+		ActorType: e.actorType,
+		ActorID:   instanceID,
+		Key:       stateKey,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get workflow instance state")
+	}
+	if resp == nil || len(resp.Data) == 0 {
+		return nil, errors.Wrapf(ErrInstanceNotFound, "instance %s", instanceID)
+	}
+
+	var state State
+	if err = json.Unmarshal(resp.Data, &state); err != nil {
+		return nil, errors.Wrap(err, "invalid workflow instance state")
+	}
+
+	inst = e.newInstance(&state)
+	if !state.Status.IsFinal() {
+		if _, ok := e.registry.getWorkflow(state.WorkflowName); ok {
+			log.Infof("recovering workflow instance %s of workflow %s", instanceID, state.WorkflowName)
+			e.run(inst)
+		} else {
+			log.Warnf("cannot recover workflow instance %s: workflow %s is not registered", instanceID, state.WorkflowName)
+		}
+	}
+	return inst, nil
+}
+
+// update changes the state of the instance with the function, checkpoints it, and wakes up the workflow.
+func (e *Engine) update(ctx context.Context, instanceID string, fn func(s *State) error) error {
+	inst, err := e.loadInstance(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	inst.lock.Lock()
+	defer inst.lock.Unlock()
+	return e.updateLocked(ctx, inst, fn, true)
+}
+
+// updateTimer changes a pending timer of the instance with the function, and wakes up the workflow.
+func (e *Engine) updateTimer(ctx context.Context, instanceID, timerID string, fn func(t *Timer)) error {
+	return e.update(ctx, instanceID, func(s *State) error {
+		if s.Status.IsFinal() {
+			return errors.Wrapf(ErrInstanceCompleted, "instance %s", instanceID)
+		}
+		t := findTimer(s, timerID)
+		if t == nil || t.Canceled {
+			return errors.Wrapf(ErrTimerNotFound, "timer %s of instance %s", timerID, instanceID)
+		}
+		fn(t)
+		return nil
+	})
+}
+
+// updateLocked changes the state of the instance with the function, and checkpoints it.
+// The in-memory state is only changed if the checkpoint succeeds.
+// It must be called with the lock of the instance held.
+func (e *Engine) updateLocked(ctx context.Context, inst *instance, fn func(s *State) error, notify bool) error {
+	next := inst.state.clone()
+	if err := fn(next); err != nil {
+		return err
+	}
+	next.LastUpdatedAt = time.Now().UTC()
+	if err := e.save(ctx, next); err != nil {
+		return err
+	}
+
+	inst.state = next
+	if notify {
+		close(inst.changed)
+		inst.changed = make(chan struct{})
+	}
+	return nil
+}
+
+func (e *Engine) save(ctx context.Context, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	err = e.actors.TransactionalStateOperation(ctx, &actors.TransactionalRequest{
+		ActorType: e.actorType,
+		ActorID:   state.InstanceID,
+		Operations: []actors.TransactionalOperation{{
+			Operation: actors.Upsert,
+			Request: map[string]interface{}{
+				"key":   stateKey,
+				"value": data,
+			},
+		}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to save workflow instance state")
+	}
+	return nil
+}
+
+func (e *Engine) deleteRecoveryReminder(ctx context.Context, instanceID string) error {
+	err := e.actors.DeleteReminder(ctx, &actors.DeleteReminderRequest{
+		Name:      recoveryReminder,
+		ActorType: e.actorType,
+		ActorID:   instanceID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete the recovery reminder of the workflow instance")
+	}
+	return nil
+}
+
+// run executes the workflow of the instance in background, replaying its history first.
+// The instance is kept in memory until it's completed, or until its actor is deactivated.
+func (e *Engine) run(inst *instance) {
+	w, _ := e.registry.getWorkflow(inst.state.WorkflowName)
+	instanceID := inst.state.InstanceID
+
+	ctx, cancel := context.WithCancel(e.ctx)
+	inst.cancel = cancel
+	e.lock.Lock()
+	e.instances[instanceID] = inst
+	e.lock.Unlock()
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		defer close(inst.done)
+		defer cancel()
+
+		final := e.execute(ctx, w, inst)
+		e.forget(instanceID, inst)
+		if final {
+			if err := e.deleteRecoveryReminder(ctx, instanceID); err != nil {
+				log.Warnf("workflow instance %s: %s", instanceID, err)
+			}
+		}
+	}()
+}
+
+// execute executes the workflow of the instance, and returns true if the instance is completed.
+func (e *Engine) execute(ctx context.Context, w Workflow, inst *instance) bool {
+	wctx := &Context{
+		ctx:    ctx,
+		engine: e,
+		inst:   inst,
+	}
+	output, err, aborted := execute(w, wctx)
+	if aborted {
+		inst.lock.Lock()
+		defer inst.lock.Unlock()
+		return inst.state.Status.IsFinal()
+	}
+
+	inst.lock.Lock()
+	defer inst.lock.Unlock()
+
+	updateErr := e.updateLocked(ctx, inst, func(s *State) error {
+		if s.Status.IsFinal() {
+			return errAborted
+		}
+		if err != nil {
+			s.Status = StatusFailed
+			s.FailureMessage = err.Error()
+			return nil
+		}
+		s.Status = StatusCompleted
+		if output != nil {
+			data, marshalErr := json.Marshal(output)
+			if marshalErr != nil {
+				s.Status = StatusFailed
+				s.FailureMessage = fmt.Sprintf("failed to serialize workflow output: %s", marshalErr)
+				return nil
+			}
+			s.Output = data
+		}
+		return nil
+	}, true)
+	if updateErr == errAborted {
+		return true
+	} else if updateErr != nil {
+		// The instance is recovered from its last checkpoint by the recovery reminder
+		log.Errorf("failed to complete workflow instance %s: %s", inst.state.InstanceID, updateErr)
+		return false
+	}
+
+	elapsed := float64(time.Since(inst.state.CreatedAt) / time.Millisecond)
+	diag.DefaultWorkflowMonitoring.WorkflowCompleted(inst.state.WorkflowName, string(inst.state.Status), elapsed)
+	return true
+}
+
+// forget removes the instance from memory, so it's loaded from its last checkpoint when it's called again.
+func (e *Engine) forget(instanceID string, inst *instance) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.instances[instanceID] == inst {
+		delete(e.instances, instanceID)
+	}
+}
+
+// execute runs the workflow, and reports whether the execution was aborted.
+func execute(w Workflow, wctx *Context) (output any, err error, aborted bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if r == errAborted {
+				aborted = true
+				return
+			}
+			if nd, ok := r.(nonDeterminismError); ok {
+				err = nd
+				return
+			}
+			err = errors.Errorf("workflow panicked: %v", r)
+		}
+	}()
+
+	output, err = w(wctx)
+	return output, err, false
+}
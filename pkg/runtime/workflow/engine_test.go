@@ -0,0 +1,418 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderInput struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+}
+
+// newTestRegistry returns a registry with an order workflow, which reserves the items,
+// waits for the approval, and returns the number of reserved items.
+func newTestRegistry(reservations *int32) *Registry {
+	r := NewRegistry()
+	r.RegisterActivity("reserve", func(ctx context.Context, input []byte) (any, error) {
+		atomic.AddInt32(reservations, 1)
+		return string(input), nil
+	})
+	r.RegisterActivity("fail", func(ctx context.Context, input []byte) (any, error) {
+		return nil, errors.New("out of stock")
+	})
+	r.RegisterWorkflow("order", func(ctx *Context) (any, error) {
+		var in orderInput
+		if err := ctx.GetInput(&in); err != nil {
+			return nil, err
+		}
+
+		var reserved string
+		if err := ctx.CallActivity("reserve", in, &reserved); err != nil {
+			return nil, err
+		}
+		ctx.SetCustomStatus("waiting for approval")
+
+		var approved bool
+		if err := ctx.WaitForEvent("approval", &approved); err != nil {
+			return nil, err
+		}
+		if !approved {
+			return nil, errors.New("order rejected")
+		}
+		return in.Quantity, nil
+	})
+	r.RegisterWorkflow("failing", func(ctx *Context) (any, error) {
+		return nil, ctx.CallActivity("fail", nil, nil)
+	})
+	r.RegisterWorkflow("deadline", func(ctx *Context) (any, error) {
+		err := ctx.CreateTimer("deadline", time.Hour)
+		if errors.Is(err, ErrTimerCanceled) {
+			return "canceled", nil
+		} else if err != nil {
+			return nil, err
+		}
+		return "fired", nil
+	})
+	return r
+}
+
+// newTestEngine returns an engine hosting the instances placed on it by the actor runtime.
+func newTestEngine(r *Registry, m *MockActors) *Engine {
+	e := NewEngine(r, "default", "myapp")
+	m.AddHost(e)
+	return e
+}
+
+// running returns the number of instances running on the engine.
+func running(e *Engine) int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return len(e.instances)
+}
+
+func waitForStatus(t *testing.T, e *Engine, instanceID string, status Status) *State {
+	t.Helper()
+	var state *State
+	require.Eventually(t, func() bool {
+		var err error
+		state, err = e.Get(context.Background(), instanceID)
+		require.NoError(t, err)
+		return state.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return state
+}
+
+func waitForPendingEvent(t *testing.T, e *Engine, instanceID string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		state, err := e.Get(context.Background(), instanceID)
+		require.NoError(t, err)
+		return state.CustomStatus == "waiting for approval" && len(state.History) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// waitForTimer waits for the instance to have a pending timer, and returns it.
+func waitForTimer(t *testing.T, e *Engine, instanceID string) Timer {
+	t.Helper()
+	var timers []Timer
+	require.Eventually(t, func() bool {
+		state, err := e.Get(context.Background(), instanceID)
+		require.NoError(t, err)
+		timers = state.Pending().Timers
+		return len(timers) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	return timers[0]
+}
+
+func TestEngine(t *testing.T) {
+	ctx := context.Background()
+	input := []byte(`{"item":"shoes","quantity":2}`)
+
+	t.Run("workflow completes", func(t *testing.T) {
+		var reservations int32
+		e := newTestEngine(newTestRegistry(&reservations), NewMockActors())
+		defer e.Close()
+
+		require.NoError(t, e.Start(ctx, "order", "i1", input))
+		waitForPendingEvent(t, e, "i1")
+		require.NoError(t, e.RaiseEvent(ctx, "i1", "approval", []byte("true")))
+
+		state := waitForStatus(t, e, "i1", StatusCompleted)
+		assert.Equal(t, "2", string(state.Output))
+		assert.Equal(t, "order", state.WorkflowName)
+		assert.Len(t, state.History, 2)
+		assert.Empty(t, state.PendingEvents)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&reservations))
+	})
+
+	t.Run("workflow fails", func(t *testing.T) {
+		var reservations int32
+		e := newTestEngine(newTestRegistry(&reservations), NewMockActors())
+		defer e.Close()
+
+		require.NoError(t, e.Start(ctx, "failing", "i1", nil))
+		state := waitForStatus(t, e, "i1", StatusFailed)
+		assert.Contains(t, state.FailureMessage, "out of stock")
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		var reservations int32
+		e := newTestEngine(newTestRegistry(&reservations), NewMockActors())
+		defer e.Close()
+
+		assert.ErrorIs(t, e.Start(ctx, "unknown", "i1", nil), ErrWorkflowNotFound)
+		_, err := e.Get(ctx, "i1")
+		assert.ErrorIs(t, err, ErrInstanceNotFound)
+		assert.ErrorIs(t, e.RaiseEvent(ctx, "i1", "approval", nil), ErrInstanceNotFound)
+
+		require.NoError(t, e.Start(ctx, "order", "i1", input))
+		assert.ErrorIs(t, e.Start(ctx, "order", "i1", input), ErrInstanceExists)
+		assert.ErrorIs(t, e.Resume(ctx, "i1"), ErrInstanceNotPaused)
+		assert.ErrorIs(t, e.Purge(ctx, "i1"), ErrInstanceNotComplete)
+	})
+
+	t.Run("pause and resume", func(t *testing.T) {
+		var reservations int32
+		e := newTestEngine(newTestRegistry(&reservations), NewMockActors())
+		defer e.Close()
+
+		require.NoError(t, e.Start(ctx, "order", "i1", input))
+		waitForPendingEvent(t, e, "i1")
+		require.NoError(t, e.Pause(ctx, "i1"))
+		require.NoError(t, e.RaiseEvent(ctx, "i1", "approval", []byte("true")))
+
+		// The event is not consumed while the instance is paused
+		time.Sleep(50 * time.Millisecond)
+		state, err := e.Get(ctx, "i1")
+		require.NoError(t, err)
+		assert.Equal(t, StatusPaused, state.Status)
+		assert.Len(t, state.PendingEvents, 1)
+
+		require.NoError(t, e.Resume(ctx, "i1"))
+		waitForStatus(t, e, "i1", StatusCompleted)
+	})
+
+	t.Run("terminate and purge", func(t *testing.T) {
+		var reservations int32
+		store := NewMockActors()
+		e := newTestEngine(newTestRegistry(&reservations), store)
+		defer e.Close()
+
+		require.NoError(t, e.Start(ctx, "order", "i1", input))
+		waitForPendingEvent(t, e, "i1")
+		require.NoError(t, e.Terminate(ctx, "i1"))
+		waitForStatus(t, e, "i1", StatusTerminated)
+		assert.ErrorIs(t, e.RaiseEvent(ctx, "i1", "approval", []byte("true")), ErrInstanceCompleted)
+		assert.ErrorIs(t, e.Terminate(ctx, "i1"), ErrInstanceCompleted)
+
+		require.NoError(t, e.Purge(ctx, "i1"))
+		assert.Equal(t, 0, store.Len())
+		_, err := e.Get(ctx, "i1")
+		assert.ErrorIs(t, err, ErrInstanceNotFound)
+	})
+
+	t.Run("instances are recovered from their checkpoints", func(t *testing.T) {
+		var reservations int32
+		store := NewMockActors()
+
+		e1 := newTestEngine(newTestRegistry(&reservations), store)
+		require.NoError(t, e1.Start(ctx, "order", "i1", input))
+		waitForPendingEvent(t, e1, "i1")
+		e1.Close()
+		store.RemoveHost(e1)
+
+		e2 := newTestEngine(newTestRegistry(&reservations), store)
+		defer e2.Close()
+		require.NoError(t, e2.RaiseEvent(ctx, "i1", "approval", []byte("true")))
+
+		state := waitForStatus(t, e2, "i1", StatusCompleted)
+		assert.Equal(t, "2", string(state.Output))
+		// The activity is replayed from the history, and not executed again
+		assert.Equal(t, int32(1), atomic.LoadInt32(&reservations))
+	})
+
+	t.Run("non-deterministic workflows fail", func(t *testing.T) {
+		var reservations int32
+		store := NewMockActors()
+
+		e1 := newTestEngine(newTestRegistry(&reservations), store)
+		require.NoError(t, e1.Start(ctx, "order", "i1", input))
+		waitForPendingEvent(t, e1, "i1")
+		e1.Close()
+		store.RemoveHost(e1)
+
+		r := newTestRegistry(&reservations)
+		r.RegisterWorkflow("order", func(ctx *Context) (any, error) {
+			return nil, ctx.WaitForEvent("approval", nil)
+		})
+		e2 := newTestEngine(r, store)
+		defer e2.Close()
+
+		state := waitForStatus(t, e2, "i1", StatusFailed)
+		assert.Contains(t, state.FailureMessage, "non-deterministic")
+	})
+
+	t.Run("instances are executed by their host", func(t *testing.T) {
+		var reservations int32
+		store := NewMockActors()
+		e1 := newTestEngine(newTestRegistry(&reservations), store)
+		defer e1.Close()
+		e2 := newTestEngine(newTestRegistry(&reservations), store)
+		defer e2.Close()
+
+		ids := []string{"i1", "i2", "i3", "i4", "i5", "i6"}
+		for _, id := range ids {
+			// The instances are started through the first engine, whatever their host
+			require.NoError(t, e1.Start(ctx, "order", id, input))
+			waitForPendingEvent(t, e1, id)
+		}
+		assert.Equal(t, len(ids), running(e1)+running(e2))
+		for _, id := range ids {
+			host := store.Host(id)
+			host.lock.Lock()
+			_, ok := host.instances[id]
+			host.lock.Unlock()
+			assert.True(t, ok, "instance %s must run on its host", id)
+		}
+		// Each instance is executed once
+		assert.Equal(t, int32(len(ids)), atomic.LoadInt32(&reservations))
+	})
+
+	t.Run("completed instances are evicted", func(t *testing.T) {
+		var reservations int32
+		store := NewMockActors()
+		e := newTestEngine(newTestRegistry(&reservations), store)
+		defer e.Close()
+
+		require.NoError(t, e.Start(ctx, "order", "i1", input))
+		waitForPendingEvent(t, e, "i1")
+		assert.Equal(t, 1, running(e))
+		assert.True(t, store.HasReminder("i1"))
+
+		require.NoError(t, e.RaiseEvent(ctx, "i1", "approval", []byte("true")))
+		waitForStatus(t, e, "i1", StatusCompleted)
+		require.Eventually(t, func() bool {
+			return running(e) == 0 && !store.HasReminder("i1")
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// The state of the completed instance is still returned
+		state, err := e.Get(ctx, "i1")
+		require.NoError(t, err)
+		assert.Equal(t, StatusCompleted, state.Status)
+		assert.Equal(t, 0, running(e))
+	})
+
+	t.Run("instances are recovered on their new host by the reminder", func(t *testing.T) {
+		var reservations int32
+		store := NewMockActors()
+		e1 := newTestEngine(newTestRegistry(&reservations), store)
+		defer e1.Close()
+		e2 := newTestEngine(newTestRegistry(&reservations), store)
+		defer e2.Close()
+
+		require.NoError(t, e1.Start(ctx, "order", "i1", input))
+		waitForPendingEvent(t, e1, "i1")
+		host := store.Host("i1")
+		other := e1
+		if host == e1 {
+			other = e2
+		}
+
+		store.RemoveHost(host)
+		assert.Equal(t, 0, running(host))
+		assert.Equal(t, 0, running(other))
+
+		store.FireReminders()
+		assert.Equal(t, 1, running(other))
+
+		require.NoError(t, other.RaiseEvent(ctx, "i1", "approval", []byte("true")))
+		waitForStatus(t, other, "i1", StatusCompleted)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&reservations))
+	})
+	t.Run("pending events", func(t *testing.T) {
+		var reservations int32
+		e := newTestEngine(newTestRegistry(&reservations), NewMockActors())
+		defer e.Close()
+
+		require.NoError(t, e.Start(ctx, "order", "i1", input))
+		waitForPendingEvent(t, e, "i1")
+		require.NoError(t, e.Pause(ctx, "i1"))
+		require.NoError(t, e.RaiseEvent(ctx, "i1", "approval", []byte("true")))
+
+		state, err := e.Get(ctx, "i1")
+		require.NoError(t, err)
+		pending := state.Pending()
+		assert.Equal(t, "approval", pending.WaitingForEvent)
+		require.Len(t, pending.Events, 1)
+		assert.Equal(t, "approval", pending.Events[0].Name)
+		assert.Empty(t, pending.Timers)
+
+		require.NoError(t, e.Resume(ctx, "i1"))
+		state = waitForStatus(t, e, "i1", StatusCompleted)
+		pending = state.Pending()
+		assert.Empty(t, pending.WaitingForEvent)
+		assert.Empty(t, pending.Events)
+	})
+
+	t.Run("timers are rescheduled", func(t *testing.T) {
+		var reservations int32
+		e := newTestEngine(newTestRegistry(&reservations), NewMockActors())
+		defer e.Close()
+
+		start := time.Now()
+		require.NoError(t, e.Start(ctx, "deadline", "i1", nil))
+		timer := waitForTimer(t, e, "i1")
+		assert.Equal(t, "deadline", timer.Name)
+		assert.False(t, timer.Canceled)
+		assert.WithinDuration(t, start.Add(time.Hour), timer.FireAt, time.Minute)
+
+		assert.ErrorIs(t, e.RescheduleTimer(ctx, "i1", "unknown", time.Now()), ErrTimerNotFound)
+		require.NoError(t, e.RescheduleTimer(ctx, "i1", timer.ID, time.Now()))
+
+		state := waitForStatus(t, e, "i1", StatusCompleted)
+		assert.Equal(t, `"fired"`, string(state.Output))
+		assert.Empty(t, state.Timers)
+		require.Len(t, state.History, 1)
+		assert.Equal(t, EventTimerFired, state.History[0].Type)
+		assert.ErrorIs(t, e.CancelTimer(ctx, "i1", timer.ID), ErrInstanceCompleted)
+	})
+
+	t.Run("timers are canceled", func(t *testing.T) {
+		var reservations int32
+		e := newTestEngine(newTestRegistry(&reservations), NewMockActors())
+		defer e.Close()
+
+		require.NoError(t, e.Start(ctx, "deadline", "i1", nil))
+		timer := waitForTimer(t, e, "i1")
+		assert.ErrorIs(t, e.CancelTimer(ctx, "i1", "unknown"), ErrTimerNotFound)
+		require.NoError(t, e.CancelTimer(ctx, "i1", timer.ID))
+
+		state := waitForStatus(t, e, "i1", StatusCompleted)
+		assert.Equal(t, `"canceled"`, string(state.Output))
+		assert.Empty(t, state.Timers)
+	})
+
+	t.Run("timers are recovered from their checkpoints", func(t *testing.T) {
+		var reservations int32
+		store := NewMockActors()
+
+		e1 := newTestEngine(newTestRegistry(&reservations), store)
+		require.NoError(t, e1.Start(ctx, "deadline", "i1", nil))
+		timer := waitForTimer(t, e1, "i1")
+		e1.Close()
+		store.RemoveHost(e1)
+
+		e2 := newTestEngine(newTestRegistry(&reservations), store)
+		defer e2.Close()
+		state, err := e2.Get(ctx, "i1")
+		require.NoError(t, err)
+		// The replayed workflow finds its timer, and doesn't create another one
+		require.Len(t, state.Timers, 1)
+		assert.Equal(t, timer.ID, state.Timers[0].ID)
+		assert.True(t, timer.FireAt.Equal(state.Timers[0].FireAt))
+
+		require.NoError(t, e2.RescheduleTimer(ctx, "i1", timer.ID, time.Now()))
+		state = waitForStatus(t, e2, "i1", StatusCompleted)
+		assert.Equal(t, `"fired"`, string(state.Output))
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"sync"
+)
+
+// Workflow orchestrates the activities of a workflow instance, and returns its output.
+// Workflows are replayed from their history when they are recovered after a restart, so they must be deterministic:
+// all the side effects and non-deterministic operations must be done in activities.
+type Workflow func(ctx *Context) (any, error)
+
+// Activity is a step of a workflow. It receives the JSON encoded input passed to Context.CallActivity.
+// Its result is checkpointed in the history of the workflow instance, so it's only executed once.
+type Activity func(ctx context.Context, input []byte) (any, error)
+
+// Registry contains the workflows and the activities that can be executed by the engine.
+type Registry struct {
+	workflows  map[string]Workflow
+	activities map[string]Activity
+	lock       sync.RWMutex
+}
+
+// DefaultRegistry is the registry of the workflows and the activities built into daprd.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		workflows:  map[string]Workflow{},
+		activities: map[string]Activity{},
+	}
+}
+
+// RegisterWorkflow registers a workflow with the name.
+func (r *Registry) RegisterWorkflow(name string, w Workflow) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.workflows[name] = w
+}
+
+// RegisterActivity registers an activity with the name.
+func (r *Registry) RegisterActivity(name string, a Activity) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.activities[name] = a
+}
+
+func (r *Registry) getWorkflow(name string) (Workflow, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	w, ok := r.workflows[name]
+	return w, ok
+}
+
+func (r *Registry) getActivity(name string) (Activity, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	a, ok := r.activities[name]
+	return a, ok
+}
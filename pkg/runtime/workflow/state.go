@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"time"
+)
+
+// Status is the runtime status of a workflow instance.
+type Status string
+
+const (
+	StatusRunning    Status = "RUNNING"
+	StatusPaused     Status = "PAUSED"
+	StatusCompleted  Status = "COMPLETED"
+	StatusFailed     Status = "FAILED"
+	StatusTerminated Status = "TERMINATED"
+)
+
+// IsFinal returns true if the workflow instance is not executed anymore.
+func (s Status) IsFinal() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusTerminated
+}
+
+// EventType is the type of the events of the history of a workflow instance.
+type EventType string
+
+const (
+	// EventActivityCompleted records the result of an activity.
+	EventActivityCompleted EventType = "ActivityCompleted"
+	// EventReceived records an external event consumed by the workflow.
+	EventReceived EventType = "EventReceived"
+	// EventTimerFired records a durable timer which fired, or which was canceled.
+	EventTimerFired EventType = "TimerFired"
+)
+
+// HistoryEvent is a step of a workflow instance, replayed when the instance is recovered.
+type HistoryEvent struct {
+	Type      EventType `json:"type"`
+	Name      string    `json:"name"`
+	Data      []byte    `json:"data,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// RaisedEvent is an external event raised on a workflow instance, which was not consumed yet.
+type RaisedEvent struct {
+	Name      string    `json:"name"`
+	Data      []byte    `json:"data,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Timer is a durable timer of a workflow instance, which did not fire yet.
+// It is checkpointed, so it fires at the same time when the instance is recovered.
+type Timer struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	FireAt    time.Time `json:"fireAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Canceled is true if the timer was canceled with the APIs, before the workflow was notified.
+	Canceled bool `json:"canceled,omitempty"`
+}
+
+// Pending is what a workflow instance is waiting for.
+type Pending struct {
+	// WaitingForEvent is the name of the external event the workflow is waiting for, if any.
+	WaitingForEvent string `json:"waitingForEvent,omitempty"`
+	// Events are the external events raised on the instance, which were not consumed by the workflow yet.
+	Events []RaisedEvent `json:"events"`
+	// Timers are the durable timers of the instance, which did not fire yet.
+	Timers []Timer `json:"timers"`
+}
+
+// State is the state of a workflow instance, checkpointed in the actor state store after each step.
+type State struct {
+	InstanceID     string         `json:"instanceID"`
+	WorkflowName   string         `json:"workflowName"`
+	Status         Status         `json:"status"`
+	Input          []byte         `json:"input,omitempty"`
+	Output         []byte         `json:"output,omitempty"`
+	FailureMessage string         `json:"failureMessage,omitempty"`
+	CustomStatus   string         `json:"customStatus,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	LastUpdatedAt  time.Time      `json:"lastUpdatedAt"`
+	History        []HistoryEvent `json:"history,omitempty"`
+	PendingEvents  []RaisedEvent  `json:"pendingEvents,omitempty"`
+	Timers         []Timer        `json:"timers,omitempty"`
+	// WaitingForEvent is the name of the external event the workflow is waiting for, if any.
+	WaitingForEvent string `json:"waitingForEvent,omitempty"`
+}
+
+// Pending returns the pending external events and timers of the workflow instance.
+func (s *State) Pending() *Pending {
+	return &Pending{
+		WaitingForEvent: s.WaitingForEvent,
+		Events:          append([]RaisedEvent{}, s.PendingEvents...),
+		Timers:          append([]Timer{}, s.Timers...),
+	}
+}
+
+func (s *State) clone() *State {
+	c := *s
+	c.History = append([]HistoryEvent(nil), s.History...)
+	c.PendingEvents = append([]RaisedEvent(nil), s.PendingEvents...)
+	c.Timers = append([]Timer(nil), s.Timers...)
+	return &c
+}
@@ -8,6 +8,7 @@ const (
 	SubscriptionScopes = "subscriptionScopes"
 	PublishingScopes   = "publishingScopes"
 	AllowedTopics      = "allowedTopics"
+	TopicAliases       = "topicAliases"
 	appsSeparator      = ";"
 	appSeparator       = "="
 	topicSeparator     = ","
@@ -66,3 +67,21 @@ func GetAllowedTopics(metadata map[string]string) []string {
 	}
 	return topics
 }
+
+// GetTopicAliases returns the map of logical topic names to physical topic names of the params topicAliases.
+// Aliases are separated by semicolons, in the format logicalName=physicalName.
+func GetTopicAliases(metadata map[string]string) map[string]string {
+	aliases := map[string]string{}
+
+	if val, ok := metadata[TopicAliases]; ok && val != "" {
+		val = strings.ReplaceAll(val, " ", "")
+		for _, alias := range strings.Split(val, appsSeparator) {
+			logical, physical, ok := strings.Cut(alias, appSeparator)
+			if !ok || logical == "" || physical == "" {
+				continue
+			}
+			aliases[logical] = physical
+		}
+	}
+	return aliases
+}
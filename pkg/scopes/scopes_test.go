@@ -154,3 +154,41 @@ func TestGetScopedTopics(t *testing.T) {
 			item.Target)
 	}
 }
+
+func TestGetTopicAliases(t *testing.T) {
+	aliasTests := []struct {
+		Metadata map[string]string
+		Target   map[string]string
+		Msg      string
+	}{
+		{
+			Metadata: map[string]string{},
+			Target:   map[string]string{},
+			Msg:      "pass",
+		},
+		{
+			Metadata: map[string]string{
+				"topicAliases": "orders=orders-v2",
+			},
+			Target: map[string]string{"orders": "orders-v2"},
+			Msg:    "pass",
+		},
+		{
+			Metadata: map[string]string{
+				"topicAliases": "orders=orders-v2; payments = prod.payments",
+			},
+			Target: map[string]string{"orders": "orders-v2", "payments": "prod.payments"},
+			Msg:    "pass",
+		},
+		{
+			Metadata: map[string]string{
+				"topicAliases": "orders;=topic1;payments=",
+			},
+			Target: map[string]string{},
+			Msg:    "pass",
+		},
+	}
+	for _, item := range aliasTests {
+		assert.Equal(t, item.Target, GetTopicAliases(item.Metadata), item.Msg)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"crypto/x509"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/backup"
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/dapr/pkg/sentry/config"
+)
+
+// backupSource exports and imports the trust anchors and issuer credentials for the control plane backups.
+type backupSource struct {
+	config config.SentryConfig
+}
+
+// NewBackupSource returns the source of the issuer material for the control plane backups.
+func NewBackupSource(conf config.SentryConfig) backup.Source {
+	return &backupSource{
+		config: conf,
+	}
+}
+
+// Backup adds the trust anchors and the issuer credentials to the bundle.
+func (s *backupSource) Backup(b *backup.Bundle) error {
+	var (
+		material backup.IssuerMaterial
+		err      error
+	)
+	if material.RootCert, err = os.ReadFile(s.config.RootCertPath); err != nil {
+		return err
+	}
+	if material.IssuerCert, err = os.ReadFile(s.config.IssuerCertPath); err != nil {
+		return err
+	}
+	if material.IssuerKey, err = os.ReadFile(s.config.IssuerKeyPath); err != nil {
+		return err
+	}
+	b.Sentry = &material
+	return nil
+}
+
+// Restore validates and stores the issuer material of the bundle.
+// Sentry reloads the trust bundle when it detects the change of the credentials.
+func (s *backupSource) Restore(b *backup.Bundle) error {
+	if b.Sentry == nil {
+		return backup.ErrNotInBundle
+	}
+
+	if err := validateIssuerMaterial(b.Sentry); err != nil {
+		return errors.Wrap(err, "invalid issuer material")
+	}
+	return certs.StoreCredentials(s.config, b.Sentry.RootCert, b.Sentry.IssuerCert, b.Sentry.IssuerKey)
+}
+
+// validateIssuerMaterial checks that the issuer key matches the issuer certificate, and that the latter is signed by the trust anchors.
+func validateIssuerMaterial(m *backup.IssuerMaterial) error {
+	issuerCreds, err := certs.PEMCredentialsFromFiles(m.IssuerCert, m.IssuerKey)
+	if err != nil {
+		return err
+	}
+
+	trustAnchors, err := certs.CertPoolFromPEM(m.RootCert)
+	if err != nil {
+		return err
+	}
+
+	_, err = issuerCreds.Certificate.Verify(x509.VerifyOptions{
+		Roots:     trustAnchors,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/backup"
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/dapr/pkg/sentry/config"
+)
+
+func TestBackupSource(t *testing.T) {
+	dir := t.TempDir()
+	conf := config.SentryConfig{
+		RootCertPath:   filepath.Join(dir, "ca.crt"),
+		IssuerCertPath: filepath.Join(dir, "issuer.crt"),
+		IssuerKeyPath:  filepath.Join(dir, "issuer.key"),
+	}
+
+	newMaterial := func(t *testing.T) *backup.IssuerMaterial {
+		rootKey, err := certs.GenerateECPrivateKey()
+		require.NoError(t, err)
+		_, rootCertPem, issuerCertPem, issuerKeyPem, err := GetNewSelfSignedCertificates(rootKey, time.Hour, time.Minute)
+		require.NoError(t, err)
+		return &backup.IssuerMaterial{
+			RootCert:   rootCertPem,
+			IssuerCert: issuerCertPem,
+			IssuerKey:  issuerKeyPem,
+		}
+	}
+
+	original := newMaterial(t)
+	require.NoError(t, certs.StoreCredentials(conf, original.RootCert, original.IssuerCert, original.IssuerKey))
+
+	source := NewBackupSource(conf)
+
+	t.Run("backup", func(t *testing.T) {
+		b := backup.NewBundle()
+		require.NoError(t, source.Backup(b))
+		assert.Equal(t, original, b.Sentry)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		restored := newMaterial(t)
+		require.NoError(t, source.Restore(&backup.Bundle{Sentry: restored}))
+
+		issuerCert, err := os.ReadFile(conf.IssuerCertPath)
+		require.NoError(t, err)
+		assert.Equal(t, restored.IssuerCert, issuerCert)
+	})
+
+	t.Run("bundle without issuer material", func(t *testing.T) {
+		err := source.Restore(backup.NewBundle())
+		assert.ErrorIs(t, err, backup.ErrNotInBundle)
+	})
+
+	t.Run("issuer not signed by the trust anchors", func(t *testing.T) {
+		m1 := newMaterial(t)
+		m2 := newMaterial(t)
+		m1.RootCert = m2.RootCert
+		assert.Error(t, source.Restore(&backup.Bundle{Sentry: m1}))
+	})
+
+	t.Run("issuer key not matching the certificate", func(t *testing.T) {
+		m1 := newMaterial(t)
+		m2 := newMaterial(t)
+		m1.IssuerKey = m2.IssuerKey
+		assert.Error(t, source.Restore(&backup.Bundle{Sentry: m1}))
+	})
+}
@@ -36,6 +36,7 @@ var log = logger.NewLogger("dapr.sentry.ca")
 type CertificateAuthority interface {
 	LoadOrStoreTrustBundle() error
 	GetCACertBundle() TrustRootBundler
+	AddTrustAnchors(rootCertPem []byte) error
 	SignCSR(csrPem []byte, subject string, identity *identity.Bundle, ttl time.Duration, isCA bool) (*SignedCertificate, error)
 	ValidateCSR(csr *x509.CertificateRequest) error
 }
@@ -80,6 +81,53 @@ func (c *defaultCA) GetCACertBundle() TrustRootBundler {
 	return c.bundle
 }
 
+// AddTrustAnchors adds the root certs to the trust anchors of the loaded trust bundle, so that the certificates
+// chained to previous roots are still trusted during a rotation. Expired certs and the certs already in the bundle are skipped.
+func (c *defaultCA) AddTrustAnchors(rootCertPem []byte) error {
+	if c.bundle == nil {
+		return errors.New("trust bundle is not loaded")
+	}
+	added, err := certs.DecodePEMCertificates(rootCertPem)
+	if err != nil {
+		return errors.Wrap(err, "error decoding root certs")
+	}
+	existing, err := certs.DecodePEMCertificates(c.bundle.rootCertPem)
+	if err != nil {
+		return errors.Wrap(err, "error decoding trust anchors")
+	}
+
+	c.issuerLock.Lock()
+	defer c.issuerLock.Unlock()
+
+	now := time.Now()
+	rootCertPemWithAdded := append([]byte{}, c.bundle.rootCertPem...)
+	if len(rootCertPemWithAdded) > 0 && rootCertPemWithAdded[len(rootCertPemWithAdded)-1] != '\n' {
+		rootCertPemWithAdded = append(rootCertPemWithAdded, '\n')
+	}
+	for _, cert := range added {
+		if cert.NotAfter.Before(now) || containsCertificate(existing, cert) {
+			continue
+		}
+		existing = append(existing, cert)
+		rootCertPemWithAdded = append(rootCertPemWithAdded, pem.EncodeToMemory(&pem.Block{
+			Type:  certs.BlockTypeCertificate,
+			Bytes: cert.Raw,
+		})...)
+		c.bundle.trustAnchors.AddCert(cert)
+	}
+	c.bundle.rootCertPem = rootCertPemWithAdded
+	return nil
+}
+
+func containsCertificate(certificates []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certificates {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+	return false
+}
+
 // SignCSR signs a request with a PEM encoded CSR cert and duration.
 // If isCA is set to true, a CA cert will be issued. If isCA is set to false, a workload
 // Certificate will be issued instead.
@@ -102,6 +150,12 @@ func (c *defaultCA) SignCSR(csrPem []byte, subject string, identity *identity.Bu
 		return nil, errors.Wrap(err, "error parsing csr pem")
 	}
 
+	if identity != nil && c.config.SPIFFEIDTemplate != "" {
+		withTemplate := *identity
+		withTemplate.SPIFFEIDTemplate = c.config.SPIFFEIDTemplate
+		identity = &withTemplate
+	}
+
 	crtb, err := csr.GenerateCSRCertificate(cert, subject, identity, signingCert, cert.PublicKey, signingKey, certLifetime, c.config.AllowedClockSkew, isCA)
 	if err != nil {
 		return nil, errors.Wrap(err, "error signing csr")
@@ -211,6 +211,31 @@ func TestSignCSR(t *testing.T) {
 			t.Error("SAN extension not found in certificate")
 		}
 	})
+
+	t.Run("identity with dns names and spiffe id template", func(t *testing.T) {
+		writeTestCredentialsToDisk()
+		defer cleanupCredentials()
+
+		csr := getTestCSR("test.a.com")
+		pk, _ := getECDSAPrivateKey()
+		csrb, _ := x509.CreateCertificateRequest(rand.Reader, csr, pk)
+		certPem := pem.EncodeToMemory(&pem.Block{Type: certs.BlockTypeCertificate, Bytes: csrb})
+
+		certAuth := getTestCertAuth()
+		certAuth.(*defaultCA).config.SPIFFEIDTemplate = "spiffe://{trustDomain}/cluster-a/{namespace}/{appID}"
+		certAuth.LoadOrStoreTrustBundle()
+
+		bundle := identity.NewBundle("app", "default", "public")
+		bundle.DNSNames = []string{"app.example.com"}
+		resp, err := certAuth.SignCSR(certPem, "test-subject", bundle, time.Hour*24, false)
+		assert.NoError(t, err)
+		if assert.Len(t, resp.Certificate.URIs, 1) {
+			assert.Equal(t, "spiffe://public/cluster-a/default/app", resp.Certificate.URIs[0].String())
+		}
+		assert.Equal(t, []string{"test-subject.default.svc.cluster.local", "app.example.com"}, resp.Certificate.DNSNames)
+		// The bundle of the caller is left untouched
+		assert.Empty(t, bundle.SPIFFEIDTemplate)
+	})
 }
 
 func TestCACertsGeneration(t *testing.T) {
@@ -224,6 +249,36 @@ func TestCACertsGeneration(t *testing.T) {
 	assert.True(t, len(ca.GetCACertBundle().GetIssuerCertPem()) > 0)
 }
 
+func TestAddTrustAnchors(t *testing.T) {
+	writeTestCredentialsToDisk()
+	defer cleanupCredentials()
+
+	certAuth := getTestCertAuth()
+	assert.NoError(t, certAuth.LoadOrStoreTrustBundle())
+
+	rootKey, err := certs.GenerateECPrivateKey()
+	assert.NoError(t, err)
+	_, previousRootPem, _, _, err := GetNewSelfSignedCertificates(rootKey, time.Hour, allowedClockSkew)
+	assert.NoError(t, err)
+	previousRoots, err := certs.DecodePEMCertificates(previousRootPem)
+	assert.NoError(t, err)
+
+	// The expired certs and the certs already trusted are skipped
+	assert.NoError(t, certAuth.AddTrustAnchors(previousRootPem))
+	assert.NoError(t, certAuth.AddTrustAnchors([]byte(rootCert+"\n"+issuerCert)))
+	assert.NoError(t, certAuth.AddTrustAnchors(previousRootPem))
+
+	roots, err := certs.DecodePEMCertificates(certAuth.GetCACertBundle().GetRootCertPem())
+	assert.NoError(t, err)
+	if assert.Len(t, roots, 2) {
+		assert.True(t, roots[1].Equal(previousRoots[0]))
+	}
+	_, err = previousRoots[0].Verify(x509.VerifyOptions{Roots: certAuth.GetCACertBundle().GetTrustAnchors()})
+	assert.NoError(t, err)
+
+	assert.Error(t, certAuth.AddTrustAnchors([]byte("not a cert")))
+}
+
 func TestShouldCreateCerts(t *testing.T) {
 	t.Run("certs exist, should not create", func(t *testing.T) {
 		writeTestCredentialsToDisk()
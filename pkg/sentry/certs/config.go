@@ -7,4 +7,14 @@ const (
 	TrustAnchorsEnvVar = "DAPR_TRUST_ANCHORS"
 	CertChainEnvVar    = "DAPR_CERT_CHAIN"
 	CertKeyEnvVar      = "DAPR_CERT_KEY"
+	// TrustAnchorsFileEnvVar is the environment variable name for the path of a file containing the trust anchors in the sidecar.
+	// When set, it takes precedence over TrustAnchorsEnvVar.
+	TrustAnchorsFileEnvVar = "DAPR_TRUST_ANCHORS_FILE"
+	// CertDNSNamesEnvVar is the environment variable name for the comma separated DNS names requested on the certificate of the sidecar.
+	CertDNSNamesEnvVar = "DAPR_CERT_DNS_NAMES"
+
+	// TrustBundleConfigMapName is the name of the ConfigMap that Sentry publishes the trust anchors to, in every namespace.
+	TrustBundleConfigMapName = "dapr-root-ca.crt"
+	// TrustBundleConfigMapKey is the key in the trust bundle ConfigMap that contains the trust anchors.
+	TrustBundleConfigMapKey = "ca.crt"
 )
@@ -12,6 +12,7 @@ import (
 
 	scheme "github.com/dapr/dapr/pkg/client/clientset/versioned"
 	daprDaprConfig "github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/sentry/identity"
 	"github.com/dapr/dapr/utils"
 )
 
@@ -23,6 +24,11 @@ const (
 	defaultWorkloadCertTTL      = time.Hour * 24
 	defaultAllowedClockSkew     = time.Minute * 15
 
+	// DefaultCertExpiryWarningThreshold is the default time before the expiration of the root or issuer certs when warnings are emitted.
+	DefaultCertExpiryWarningThreshold = time.Hour * 24 * 30
+	// DefaultRootRotationWindow is the default time during which the previous root certs are still trusted after a rotation.
+	DefaultRootRotationWindow = time.Hour * 24
+
 	// defaultDaprSystemConfigName is the default resource object name for Dapr System Config.
 	defaultDaprSystemConfigName = "daprsystem"
 )
@@ -39,6 +45,19 @@ type SentryConfig struct {
 	RootCertPath     string
 	IssuerCertPath   string
 	IssuerKeyPath    string
+	TokenValidators  []daprDaprConfig.ValidatorSpec
+	// AllowedDNSNames are the patterns of the DNS names that the sidecars of each namespace can request on their
+	// workload certificates.
+	AllowedDNSNames []daprDaprConfig.AllowedDNSNamesSpec
+	// SPIFFEIDTemplate is the template of the SPIFFE IDs of the workload certificates.
+	SPIFFEIDTemplate string
+	// CSRRateLimit limits the certificate signing requests of each app ID and namespace, if set.
+	CSRRateLimit *daprDaprConfig.CSRRateLimitSpec
+
+	CertExpiryWarningThreshold time.Duration
+	// RootRotationWindow is the time during which the previous root certs are still trusted after the root cert changes,
+	// so that the workloads holding certificates chained to them keep working until they are renewed.
+	RootRotationWindow time.Duration
 }
 
 var configGetters = map[string]func(string) (SentryConfig, error){
@@ -86,6 +105,9 @@ func getDefaultConfig() SentryConfig {
 		Port:             defaultPort,
 		WorkloadCertTTL:  defaultWorkloadCertTTL,
 		AllowedClockSkew: defaultAllowedClockSkew,
+
+		CertExpiryWarningThreshold: DefaultCertExpiryWarningThreshold,
+		RootRotationWindow:         DefaultRootRotationWindow,
 	}
 }
 
@@ -155,5 +177,39 @@ func parseConfiguration(conf SentryConfig, daprConfig *daprDaprConfig.Configurat
 		conf.AllowedClockSkew = d
 	}
 
+	conf.TokenValidators = daprConfig.Spec.MTLSSpec.TokenValidators
+
+	for _, allowed := range daprConfig.Spec.MTLSSpec.AllowedDNSNames {
+		if allowed.Namespace == "" {
+			return conf, errors.New("the namespace of the allowed dns names is required")
+		}
+		if len(allowed.AppIDs) == 0 {
+			return conf, errors.Errorf("the app ids of the allowed dns names of namespace %s are required", allowed.Namespace)
+		}
+		if err := identity.ValidateDNSNamePatterns(allowed.Names); err != nil {
+			return conf, err
+		}
+	}
+	conf.AllowedDNSNames = daprConfig.Spec.MTLSSpec.AllowedDNSNames
+
+	if daprConfig.Spec.MTLSSpec.SPIFFEIDTemplate != "" {
+		if err := identity.ValidateSPIFFEIDTemplate(daprConfig.Spec.MTLSSpec.SPIFFEIDTemplate); err != nil {
+			return conf, err
+		}
+		conf.SPIFFEIDTemplate = daprConfig.Spec.MTLSSpec.SPIFFEIDTemplate
+	}
+
+	if limit := daprConfig.Spec.MTLSSpec.CSRRateLimit; limit != nil {
+		if limit.AppIDRequestsPerMinute < 0 || limit.NamespaceRequestsPerMinute < 0 || limit.Burst < 0 {
+			return conf, errors.New("the CSR rate limits must not be negative")
+		}
+		switch limit.Enforcement {
+		case "", daprDaprConfig.CSRRateLimitWarn, daprDaprConfig.CSRRateLimitReject:
+		default:
+			return conf, errors.Errorf("invalid CSR rate limit enforcement %q: expected warn or reject", limit.Enforcement)
+		}
+		conf.CSRRateLimit = limit
+	}
+
 	return conf, nil
 }
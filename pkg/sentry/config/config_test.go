@@ -30,6 +30,9 @@ func TestConfig(t *testing.T) {
 					Enabled:          true,
 					WorkloadCertTTL:  "5s",
 					AllowedClockSkew: "1h",
+					TokenValidators: []daprDaprConfig.ValidatorSpec{
+						{Name: "jwks", Options: map[string]string{"jwksURL": "https://localhost/jwks.json"}},
+					},
 				},
 			},
 		}
@@ -39,5 +42,72 @@ func TestConfig(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, "5s", conf.WorkloadCertTTL.String())
 		assert.Equal(t, "1h0m0s", conf.AllowedClockSkew.String())
+		assert.Len(t, conf.TokenValidators, 1)
+		assert.Equal(t, "jwks", conf.TokenValidators[0].Name)
+	})
+
+	t.Run("parse certificate names", func(t *testing.T) {
+		daprConfig := daprDaprConfig.Configuration{
+			Spec: daprDaprConfig.ConfigurationSpec{
+				MTLSSpec: daprDaprConfig.MTLSSpec{
+					AllowedDNSNames: []daprDaprConfig.AllowedDNSNamesSpec{
+						{Namespace: "default", AppIDs: []string{"app1"}, Names: []string{"*.example.com", "api.contoso.com"}},
+					},
+					SPIFFEIDTemplate: "spiffe://{trustDomain}/cluster-a/{namespace}/{appID}",
+				},
+			},
+		}
+
+		conf, err := parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, []daprDaprConfig.AllowedDNSNamesSpec{
+			{Namespace: "default", AppIDs: []string{"app1"}, Names: []string{"*.example.com", "api.contoso.com"}},
+		}, conf.AllowedDNSNames)
+		assert.Equal(t, "spiffe://{trustDomain}/cluster-a/{namespace}/{appID}", conf.SPIFFEIDTemplate)
+
+		daprConfig.Spec.MTLSSpec.AllowedDNSNames = []daprDaprConfig.AllowedDNSNamesSpec{
+			{Namespace: "default", AppIDs: []string{"app1"}, Names: []string{"*.Invalid_Name"}},
+		}
+		_, err = parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.Error(t, err)
+
+		daprConfig.Spec.MTLSSpec.AllowedDNSNames = []daprDaprConfig.AllowedDNSNamesSpec{
+			{AppIDs: []string{"app1"}, Names: []string{"*.example.com"}},
+		}
+		_, err = parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.Error(t, err)
+
+		daprConfig.Spec.MTLSSpec.AllowedDNSNames = []daprDaprConfig.AllowedDNSNamesSpec{
+			{Namespace: "default", Names: []string{"*.example.com"}},
+		}
+		_, err = parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.ErrorContains(t, err, "app ids")
+
+		daprConfig.Spec.MTLSSpec.AllowedDNSNames = nil
+		daprConfig.Spec.MTLSSpec.SPIFFEIDTemplate = "spiffe://{trustDomain}/{appID}"
+		_, err = parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.Error(t, err)
+	})
+
+	t.Run("parse CSR rate limit", func(t *testing.T) {
+		limit := &daprDaprConfig.CSRRateLimitSpec{AppIDRequestsPerMinute: 10, Enforcement: daprDaprConfig.CSRRateLimitReject}
+		daprConfig := daprDaprConfig.Configuration{
+			Spec: daprDaprConfig.ConfigurationSpec{
+				MTLSSpec: daprDaprConfig.MTLSSpec{CSRRateLimit: limit},
+			},
+		}
+
+		conf, err := parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, limit, conf.CSRRateLimit)
+
+		limit.Enforcement = "block"
+		_, err = parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.Error(t, err)
+
+		limit.Enforcement = ""
+		limit.Burst = -1
+		_, err = parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.Error(t, err)
 	})
 }
@@ -145,7 +145,7 @@ func GenerateCSRCertificate(csr *x509.CertificateRequest, subject string, identi
 	cert.SignatureAlgorithm = csr.SignatureAlgorithm
 
 	if identityBundle != nil {
-		spiffeID, err := identity.CreateSPIFFEID(identityBundle.TrustDomain, identityBundle.Namespace, identityBundle.ID)
+		spiffeID, err := identity.CreateSPIFFEIDFromTemplate(identityBundle.SPIFFEIDTemplate, identityBundle.TrustDomain, identityBundle.Namespace, identityBundle.ID)
 		if err != nil {
 			return nil, errors.Wrap(err, "error generating spiffe id")
 		}
@@ -162,6 +162,13 @@ func GenerateCSRCertificate(csr *x509.CertificateRequest, subject string, identi
 				Tag:   2,
 			},
 		}
+		for _, name := range identityBundle.DNSNames {
+			rv = append(rv, asn1.RawValue{
+				Bytes: []byte(name),
+				Class: asn1.ClassContextSpecific,
+				Tag:   2,
+			})
+		}
 
 		b, err := asn1.Marshal(rv)
 		if err != nil {
@@ -0,0 +1,36 @@
+package identity
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NewChainValidator returns a Validator that accepts a request if any of the given validators accepts it.
+// Validators are invoked in the order they are passed.
+func NewChainValidator(validators ...Validator) Validator {
+	return &chainValidator{
+		validators: validators,
+	}
+}
+
+type chainValidator struct {
+	validators []Validator
+}
+
+func (c *chainValidator) Validate(id, token, namespace string) error {
+	if len(c.validators) == 0 {
+		return errors.New("csr validation failed: no validators configured")
+	}
+
+	errs := make([]string, 0, len(c.validators))
+	for _, v := range c.validators {
+		err := v.Validate(id, token, namespace)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err.Error())
+	}
+
+	return errors.New(strings.Join(errs, "; "))
+}
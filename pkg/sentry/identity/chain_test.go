@@ -0,0 +1,52 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockValidator struct {
+	err   error
+	calls int
+}
+
+func (m *mockValidator) Validate(id, token, namespace string) error {
+	m.calls++
+	return m.err
+}
+
+func TestChainValidator(t *testing.T) {
+	t.Run("no validators", func(t *testing.T) {
+		v := NewChainValidator()
+		assert.Error(t, v.Validate("id", "token", "ns"))
+	})
+
+	t.Run("first validator succeeds", func(t *testing.T) {
+		v1 := &mockValidator{}
+		v2 := &mockValidator{err: errors.New("v2")}
+		v := NewChainValidator(v1, v2)
+		assert.NoError(t, v.Validate("id", "token", "ns"))
+		assert.Equal(t, 1, v1.calls)
+		assert.Equal(t, 0, v2.calls)
+	})
+
+	t.Run("second validator succeeds", func(t *testing.T) {
+		v1 := &mockValidator{err: errors.New("v1")}
+		v2 := &mockValidator{}
+		v := NewChainValidator(v1, v2)
+		assert.NoError(t, v.Validate("id", "token", "ns"))
+		assert.Equal(t, 1, v1.calls)
+		assert.Equal(t, 1, v2.calls)
+	})
+
+	t.Run("all validators fail", func(t *testing.T) {
+		v := NewChainValidator(
+			&mockValidator{err: errors.New("v1")},
+			&mockValidator{err: errors.New("v2")},
+		)
+		err := v.Validate("id", "token", "ns")
+		assert.EqualError(t, err, "v1; v2")
+	})
+}
@@ -0,0 +1,39 @@
+package identity
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateDNSNamePatterns returns an error if a pattern of the DNS names allowed on the workload certificates is invalid.
+// A pattern is a DNS name, or a DNS name prefixed with "*." to allow the names with one more label.
+func ValidateDNSNamePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		name := strings.TrimPrefix(pattern, "*.")
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return errors.Errorf("invalid allowed dns name %q: %s", pattern, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// IsDNSNameAllowed returns true if the DNS name matches one of the patterns.
+func IsDNSNameAllowed(patterns []string, name string) bool {
+	if len(validation.IsDNS1123Subdomain(name)) > 0 {
+		return false
+	}
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if strings.HasPrefix(pattern, "*.") {
+			i := strings.IndexByte(name, '.')
+			if i > 0 && name[i+1:] == pattern[2:] {
+				return true
+			}
+		}
+	}
+	return false
+}
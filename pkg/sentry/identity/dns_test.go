@@ -0,0 +1,28 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDNSNamePatterns(t *testing.T) {
+	assert.NoError(t, ValidateDNSNamePatterns(nil))
+	assert.NoError(t, ValidateDNSNamePatterns([]string{"*.example.com", "api.example.com"}))
+	assert.Error(t, ValidateDNSNamePatterns([]string{"*"}))
+	assert.Error(t, ValidateDNSNamePatterns([]string{"api.*.example.com"}))
+	assert.Error(t, ValidateDNSNamePatterns([]string{"Invalid_Name"}))
+}
+
+func TestIsDNSNameAllowed(t *testing.T) {
+	patterns := []string{"*.example.com", "api.contoso.com"}
+
+	assert.True(t, IsDNSNameAllowed(patterns, "orders.example.com"))
+	assert.True(t, IsDNSNameAllowed(patterns, "api.contoso.com"))
+
+	assert.False(t, IsDNSNameAllowed(patterns, "example.com"))
+	assert.False(t, IsDNSNameAllowed(patterns, "a.orders.example.com"))
+	assert.False(t, IsDNSNameAllowed(patterns, "web.contoso.com"))
+	assert.False(t, IsDNSNameAllowed(patterns, "*.example.com"))
+	assert.False(t, IsDNSNameAllowed(nil, "orders.example.com"))
+}
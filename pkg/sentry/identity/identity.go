@@ -5,6 +5,10 @@ type Bundle struct {
 	ID          string
 	Namespace   string
 	TrustDomain string
+	// DNSNames are the DNS names requested on the certificate in addition to the default one.
+	DNSNames []string
+	// SPIFFEIDTemplate is the template of the SPIFFE ID, DefaultSPIFFEIDTemplate is used if empty.
+	SPIFFEIDTemplate string
 }
 
 // NewBundle returns a new identity bundle.
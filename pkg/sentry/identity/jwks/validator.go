@@ -0,0 +1,264 @@
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/dapr/dapr/pkg/sentry/identity"
+)
+
+const (
+	errPrefix = "csr validation failed"
+
+	// Name of the validator, used in the Configuration.
+	Name = "jwks"
+
+	defaultAudience        = "dapr.io/sentry"
+	defaultNamespaceClaim  = "namespace"
+	defaultRefreshInterval = time.Hour
+	minRefreshInterval     = time.Minute
+	fetchTimeout           = 10 * time.Second
+)
+
+// Options for the JWKS validator.
+type Options struct {
+	// URL of the JWKS document; for example, an OIDC provider's "jwks_uri".
+	JWKSURL string
+	// Inline JWKS document, as JSON. Used instead of JWKSURL when set.
+	JWKS string
+	// Expected value of the "iss" claim. Optional.
+	Issuer string
+	// Expected value of the "aud" claim. Defaults to "dapr.io/sentry".
+	Audience string
+	// Name of a claim that must match the namespace of the requester, which is then required. Defaults to "namespace".
+	NamespaceClaim string
+	// Accept the tokens for the requesters of any namespace, which aren't bound to a namespace by a claim.
+	// Only for the issuers that can't add a namespace claim to the tokens, as the token of an app is then valid in every namespace.
+	AllowAnyNamespace bool
+	// Interval for re-fetching the JWKS from JWKSURL.
+	RefreshInterval time.Duration
+	// HTTP client used to fetch the JWKS. Optional.
+	Client *http.Client
+}
+
+// OptionsFromMap parses the options from the map included in the Configuration.
+func OptionsFromMap(m map[string]string) (Options, error) {
+	opts := Options{
+		JWKSURL:        m["jwksURL"],
+		JWKS:           m["jwks"],
+		Issuer:         m["issuer"],
+		Audience:       m["audience"],
+		NamespaceClaim: m["namespaceClaim"],
+	}
+	if v := m["refreshInterval"]; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "invalid value for option refreshInterval")
+		}
+		opts.RefreshInterval = d
+	}
+	if v := m["allowAnyNamespace"]; v != "" {
+		allow, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "invalid value for option allowAnyNamespace")
+		}
+		opts.AllowAnyNamespace = allow
+	}
+	return opts, nil
+}
+
+// NewValidator returns a Validator that authenticates requesters with a JWT signed by a key from a JWKS.
+// The "sub" claim of the token must match the ID of the requester, and the token must have an "exp" claim.
+// The namespace claim must match the namespace of the requester, unless AllowAnyNamespace is set.
+func NewValidator(opts Options) (identity.Validator, error) {
+	v := &validator{
+		opts: opts,
+	}
+
+	if v.opts.Audience == "" {
+		v.opts.Audience = defaultAudience
+	}
+	if v.opts.NamespaceClaim == "" && !v.opts.AllowAnyNamespace {
+		v.opts.NamespaceClaim = defaultNamespaceClaim
+	}
+	if v.opts.RefreshInterval == 0 {
+		v.opts.RefreshInterval = defaultRefreshInterval
+	} else if v.opts.RefreshInterval < minRefreshInterval {
+		v.opts.RefreshInterval = minRefreshInterval
+	}
+	if v.opts.Client == nil {
+		v.opts.Client = &http.Client{Timeout: fetchTimeout}
+	}
+
+	switch {
+	case v.opts.JWKS != "":
+		keys, err := parseJWKS([]byte(v.opts.JWKS))
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+	case v.opts.JWKSURL != "":
+		// Keys are fetched lazily on the first request
+	default:
+		return nil, errors.New("one of the options jwks or jwksURL is required")
+	}
+
+	return v, nil
+}
+
+type validator struct {
+	opts      Options
+	keys      *jose.JSONWebKeySet
+	fetchedAt time.Time
+	lock      sync.Mutex
+	// fetchLock is held while the JWKS is fetched, so that a single request fetches it at a time.
+	fetchLock sync.Mutex
+}
+
+func (v *validator) Validate(id, token, namespace string) error {
+	if id == "" {
+		return errors.Errorf("%s: id field in request must not be empty", errPrefix)
+	}
+	if token == "" {
+		return errors.Errorf("%s: token field in request must not be empty", errPrefix)
+	}
+
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return errors.Errorf("%s: failed to parse token: %v", errPrefix, err)
+	}
+
+	keys, err := v.getKeys()
+	if err != nil {
+		return errors.Errorf("%s: failed to retrieve JWKS: %v", errPrefix, err)
+	}
+
+	claims := jwt.Claims{}
+	extra := map[string]interface{}{}
+	err = tok.Claims(keys, &claims, &extra)
+	if err != nil {
+		return errors.Errorf("%s: invalid token signature: %v", errPrefix, err)
+	}
+
+	err = claims.Validate(jwt.Expected{
+		Issuer:   v.opts.Issuer,
+		Audience: jwt.Audience{v.opts.Audience},
+		Time:     time.Now(),
+	})
+	if err != nil {
+		return errors.Errorf("%s: invalid token: %v", errPrefix, err)
+	}
+	// The tokens without an expiration would stay valid forever.
+	if claims.Expiry == nil {
+		return errors.Errorf("%s: invalid token: missing exp claim", errPrefix)
+	}
+
+	if claims.Subject != id {
+		return errors.Errorf("%s: token/id mismatch. received id: %s", errPrefix, id)
+	}
+
+	if v.opts.NamespaceClaim != "" {
+		if namespace == "" {
+			return errors.Errorf("%s: namespace field in request must not be empty", errPrefix)
+		}
+		ns, _ := extra[v.opts.NamespaceClaim].(string)
+		if ns != namespace {
+			return errors.Errorf("%s: namespace mismatch. received namespace: %s", errPrefix, namespace)
+		}
+	}
+
+	return nil
+}
+
+// getKeys returns the JWKS, fetching it from the remote URL if the cached copy is stale.
+// The keys are fetched without holding the lock, so that the requests keep using the cached keys during a refresh.
+func (v *validator) getKeys() (*jose.JSONWebKeySet, error) {
+	keys, fresh := v.cachedKeys()
+	if fresh {
+		return keys, nil
+	}
+	if keys != nil {
+		// Another request is already refreshing the keys
+		if !v.fetchLock.TryLock() {
+			return keys, nil
+		}
+	} else {
+		v.fetchLock.Lock()
+	}
+	defer v.fetchLock.Unlock()
+
+	// The keys may have been fetched while waiting for the lock
+	keys, fresh = v.cachedKeys()
+	if fresh {
+		return keys, nil
+	}
+
+	fetched, err := v.fetchKeys()
+	if err != nil {
+		// Keep using the cached keys, if any, if the refresh fails
+		if keys != nil {
+			return keys, nil
+		}
+		return nil, err
+	}
+
+	v.lock.Lock()
+	v.keys = fetched
+	v.fetchedAt = time.Now()
+	v.lock.Unlock()
+	return fetched, nil
+}
+
+// cachedKeys returns the cached JWKS, and whether it doesn't need to be refreshed.
+func (v *validator) cachedKeys() (*jose.JSONWebKeySet, bool) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	return v.keys, v.opts.JWKS != "" || (v.keys != nil && time.Since(v.fetchedAt) < v.opts.RefreshInterval)
+}
+
+func (v *validator) fetchKeys() (*jose.JSONWebKeySet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.opts.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := v.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected response status code: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseJWKS(body)
+}
+
+func parseJWKS(data []byte) (*jose.JSONWebKeySet, error) {
+	keys := &jose.JSONWebKeySet{}
+	err := json.Unmarshal(data, keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse JWKS")
+	}
+	if len(keys.Keys) == 0 {
+		return nil, errors.New("JWKS does not contain any key")
+	}
+	return keys, nil
+}
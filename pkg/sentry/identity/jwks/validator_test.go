@@ -0,0 +1,266 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func generateKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks := jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{
+			{Key: key.Public(), KeyID: "mykey", Algorithm: string(jose.ES256), Use: "sig"},
+		},
+	}
+	enc, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	return key, string(enc)
+}
+
+func signToken(t *testing.T, key *ecdsa.PrivateKey, claims jwt.Claims, extra map[string]interface{}) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.ES256, Key: key},
+		(&jose.SignerOptions{}).WithHeader("kid", "mykey").WithType("JWT"),
+	)
+	require.NoError(t, err)
+
+	builder := jwt.Signed(signer).Claims(claims)
+	if extra != nil {
+		builder = builder.Claims(extra)
+	}
+	token, err := builder.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestNewValidator(t *testing.T) {
+	t.Run("missing JWKS", func(t *testing.T) {
+		_, err := NewValidator(Options{})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid inline JWKS", func(t *testing.T) {
+		_, err := NewValidator(Options{JWKS: "not-json"})
+		assert.Error(t, err)
+	})
+
+	t.Run("options from map", func(t *testing.T) {
+		opts, err := OptionsFromMap(map[string]string{
+			"jwksURL":           "https://example.com/jwks.json",
+			"issuer":            "myissuer",
+			"refreshInterval":   "10m",
+			"allowAnyNamespace": "true",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/jwks.json", opts.JWKSURL)
+		assert.Equal(t, "myissuer", opts.Issuer)
+		assert.Equal(t, 10*time.Minute, opts.RefreshInterval)
+		assert.True(t, opts.AllowAnyNamespace)
+
+		_, err = OptionsFromMap(map[string]string{"refreshInterval": "bad"})
+		assert.Error(t, err)
+		_, err = OptionsFromMap(map[string]string{"allowAnyNamespace": "bad"})
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateNamespaceBinding(t *testing.T) {
+	key, jwksDoc := generateKey(t)
+	claims := jwt.Claims{
+		Subject:  "myapp",
+		Audience: jwt.Audience{defaultAudience},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	t.Run("the namespace claim is required by default", func(t *testing.T) {
+		v, err := NewValidator(Options{JWKS: jwksDoc})
+		require.NoError(t, err)
+
+		token := signToken(t, key, claims, nil)
+		assert.ErrorContains(t, v.Validate("myapp", token, "ns1"), "namespace mismatch")
+		assert.ErrorContains(t, v.Validate("myapp", token, ""), "namespace field in request must not be empty")
+
+		token = signToken(t, key, claims, map[string]interface{}{defaultNamespaceClaim: "ns1"})
+		assert.NoError(t, v.Validate("myapp", token, "ns1"))
+	})
+
+	t.Run("any namespace is accepted when allowed", func(t *testing.T) {
+		v, err := NewValidator(Options{JWKS: jwksDoc, AllowAnyNamespace: true})
+		require.NoError(t, err)
+
+		token := signToken(t, key, claims, nil)
+		assert.NoError(t, v.Validate("myapp", token, "ns1"))
+	})
+}
+
+func TestValidate(t *testing.T) {
+	key, jwksDoc := generateKey(t)
+	now := time.Now()
+
+	v, err := NewValidator(Options{
+		JWKS:           jwksDoc,
+		Issuer:         "myissuer",
+		NamespaceClaim: "namespace",
+	})
+	require.NoError(t, err)
+
+	validClaims := jwt.Claims{
+		Issuer:   "myissuer",
+		Subject:  "myapp",
+		Audience: jwt.Audience{defaultAudience},
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		IssuedAt: jwt.NewNumericDate(now),
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		token := signToken(t, key, validClaims, map[string]interface{}{"namespace": "ns1"})
+		assert.NoError(t, v.Validate("myapp", token, "ns1"))
+	})
+
+	t.Run("empty id", func(t *testing.T) {
+		assert.Error(t, v.Validate("", "token", "ns1"))
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		assert.Error(t, v.Validate("myapp", "", "ns1"))
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		assert.Error(t, v.Validate("myapp", "not-a-jwt", "ns1"))
+	})
+
+	t.Run("id mismatch", func(t *testing.T) {
+		token := signToken(t, key, validClaims, map[string]interface{}{"namespace": "ns1"})
+		err := v.Validate("otherapp", token, "ns1")
+		assert.ErrorContains(t, err, "token/id mismatch")
+	})
+
+	t.Run("namespace mismatch", func(t *testing.T) {
+		token := signToken(t, key, validClaims, map[string]interface{}{"namespace": "ns1"})
+		err := v.Validate("myapp", token, "ns2")
+		assert.ErrorContains(t, err, "namespace mismatch")
+	})
+
+	t.Run("empty namespace", func(t *testing.T) {
+		token := signToken(t, key, validClaims, map[string]interface{}{"namespace": "ns1"})
+		err := v.Validate("myapp", token, "")
+		assert.ErrorContains(t, err, "namespace field in request must not be empty")
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		claims := validClaims
+		claims.Issuer = "other"
+		token := signToken(t, key, claims, nil)
+		assert.Error(t, v.Validate("myapp", token, ""))
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		claims := validClaims
+		claims.Audience = jwt.Audience{"other"}
+		token := signToken(t, key, claims, nil)
+		assert.Error(t, v.Validate("myapp", token, ""))
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		claims := validClaims
+		claims.Expiry = jwt.NewNumericDate(now.Add(-time.Hour))
+		token := signToken(t, key, claims, nil)
+		assert.Error(t, v.Validate("myapp", token, ""))
+	})
+
+	t.Run("token without expiration", func(t *testing.T) {
+		claims := validClaims
+		claims.Expiry = nil
+		token := signToken(t, key, claims, map[string]interface{}{"namespace": "ns1"})
+		assert.ErrorContains(t, v.Validate("myapp", token, "ns1"), "missing exp claim")
+	})
+
+	t.Run("signed with unknown key", func(t *testing.T) {
+		otherKey, _ := generateKey(t)
+		token := signToken(t, otherKey, validClaims, nil)
+		assert.ErrorContains(t, v.Validate("myapp", token, ""), "invalid token signature")
+	})
+}
+
+func TestValidateRemoteJWKS(t *testing.T) {
+	key, jwksDoc := generateKey(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(jwksDoc))
+	}))
+	defer server.Close()
+
+	v, err := NewValidator(Options{JWKSURL: server.URL})
+	require.NoError(t, err)
+
+	token := signToken(t, key, jwt.Claims{
+		Subject:  "myapp",
+		Audience: jwt.Audience{defaultAudience},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}, map[string]interface{}{"namespace": "ns1"})
+
+	assert.NoError(t, v.Validate("myapp", token, "ns1"))
+	assert.NoError(t, v.Validate("myapp", token, "ns1"))
+	// JWKS is cached between requests
+	assert.Equal(t, 1, requests)
+}
+
+func TestValidateDuringRefresh(t *testing.T) {
+	key, jwksDoc := generateKey(t)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(jwksDoc))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	val, err := NewValidator(Options{JWKSURL: server.URL})
+	require.NoError(t, err)
+	v := val.(*validator)
+	keys, err := parseJWKS([]byte(jwksDoc))
+	require.NoError(t, err)
+	// The cached keys are stale
+	v.keys = keys
+	v.fetchedAt = time.Now().Add(-2 * v.opts.RefreshInterval)
+
+	token := signToken(t, key, jwt.Claims{
+		Subject:  "myapp",
+		Audience: jwt.Audience{defaultAudience},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}, map[string]interface{}{"namespace": "ns1"})
+
+	// The first request refreshes the keys, which hangs until released
+	go v.Validate("myapp", token, "ns1")
+	<-started
+
+	// The other requests use the cached keys meanwhile
+	done := make(chan error)
+	go func() { done <- v.Validate("myapp", token, "ns1") }()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("validation blocked by the refresh of the keys")
+	}
+}
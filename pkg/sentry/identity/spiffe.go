@@ -1,14 +1,58 @@
 package identity
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
 )
 
+const (
+	// DefaultSPIFFEIDTemplate is the template of the SPIFFE IDs of the workloads.
+	DefaultSPIFFEIDTemplate = "spiffe://{trustDomain}/ns/{namespace}/{appID}"
+
+	trustDomainPlaceholder = "{trustDomain}"
+	namespacePlaceholder   = "{namespace}"
+	appIDPlaceholder       = "{appID}"
+)
+
 // CreateSPIFFEID returns a SPIFFE standard unique id for the given trust domain, namespace and appID.
 func CreateSPIFFEID(trustDomain, namespace, appID string) (string, error) {
+	return CreateSPIFFEIDFromTemplate(DefaultSPIFFEIDTemplate, trustDomain, namespace, appID)
+}
+
+// ValidateSPIFFEIDTemplate returns an error if the template doesn't produce unique SPIFFE IDs in the trust domain.
+// The sidecars and the operator read the namespace and the app ID of the callers from the third and fourth segments
+// of their SPIFFE IDs, so the template must be spiffe://{trustDomain}/<segment>/{namespace}/{appID}, optionally
+// followed by more segments; only the segments without a placeholder can be changed.
+func ValidateSPIFFEIDTemplate(template string) error {
+	prefix := "spiffe://" + trustDomainPlaceholder + "/"
+	if !strings.HasPrefix(template, prefix) {
+		return errors.Errorf("spiffe id template %q must start with %s", template, prefix)
+	}
+	if strings.ContainsAny(template, "?#") {
+		return errors.Errorf("spiffe id template %q is not a valid spiffe id path", template)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(template, prefix), "/")
+	if len(segments) < 3 || segments[1] != namespacePlaceholder || segments[2] != appIDPlaceholder {
+		return errors.Errorf("spiffe id template %q must be of the format spiffe://%s/<segment>/%s/%s[/<segment>...]", template, trustDomainPlaceholder, namespacePlaceholder, appIDPlaceholder)
+	}
+	for i, segment := range segments {
+		if i == 1 || i == 2 {
+			continue
+		}
+		if segment == "" {
+			return errors.Errorf("spiffe id template %q is not a valid spiffe id path", template)
+		}
+		if strings.ContainsAny(segment, "{}") {
+			return errors.Errorf("spiffe id template %q contains placeholders out of place", template)
+		}
+	}
+	return nil
+}
+
+// CreateSPIFFEIDFromTemplate returns a SPIFFE unique id for the given trust domain, namespace and appID from the template.
+func CreateSPIFFEIDFromTemplate(template, trustDomain, namespace, appID string) (string, error) {
 	if trustDomain == "" {
 		return "", errors.New("can't create spiffe id: trust domain is empty")
 	}
@@ -27,7 +71,14 @@ func CreateSPIFFEID(trustDomain, namespace, appID string) (string, error) {
 		return "", errors.New("trust domain cannot exceed 255 bytes")
 	}
 
-	id := fmt.Sprintf("spiffe://%s/ns/%s/%s", trustDomain, namespace, appID)
+	if template == "" {
+		template = DefaultSPIFFEIDTemplate
+	}
+	id := strings.NewReplacer(
+		trustDomainPlaceholder, trustDomain,
+		namespacePlaceholder, namespace,
+		appIDPlaceholder, appID,
+	).Replace(template)
 	if len([]byte(id)) > 2048 {
 		return "", errors.New("spiffe id cannot exceed 2048 bytes")
 	}
@@ -60,3 +60,37 @@ func TestSPIFFEID(t *testing.T) {
 		assert.Empty(t, id)
 	})
 }
+
+func TestSPIFFEIDTemplate(t *testing.T) {
+	t.Run("custom template", func(t *testing.T) {
+		id, err := CreateSPIFFEIDFromTemplate("spiffe://{trustDomain}/cluster-a/{namespace}/{appID}/dapr", "td1", "ns1", "app1")
+		assert.NoError(t, err)
+		assert.Equal(t, "spiffe://td1/cluster-a/ns1/app1/dapr", id)
+	})
+
+	t.Run("empty template", func(t *testing.T) {
+		id, err := CreateSPIFFEIDFromTemplate("", "td1", "ns1", "app1")
+		assert.NoError(t, err)
+		assert.Equal(t, "spiffe://td1/ns/ns1/app1", id)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		assert.NoError(t, ValidateSPIFFEIDTemplate(DefaultSPIFFEIDTemplate))
+		assert.NoError(t, ValidateSPIFFEIDTemplate("spiffe://{trustDomain}/cluster-a/{namespace}/{appID}"))
+		assert.NoError(t, ValidateSPIFFEIDTemplate("spiffe://{trustDomain}/ns/{namespace}/{appID}/dapr"))
+
+		for _, template := range []string{
+			"spiffe://example.org/ns/{namespace}/{appID}",
+			"spiffe://{trustDomain}/ns/{namespace}",
+			"spiffe://{trustDomain}/{appID}",
+			"spiffe://{trustDomain}/{namespace}/sa/{appID}",
+			"spiffe://{trustDomain}/ns/{appID}/{namespace}",
+			"spiffe://{trustDomain}/{namespace}/ns/{namespace}/{appID}",
+			"spiffe://{trustDomain}/ns/{namespace}/{appID}/{podName}",
+			"spiffe://{trustDomain}/ns/{namespace}//{appID}",
+			"spiffe://{trustDomain}/ns/{namespace}/{appID}?v=1",
+		} {
+			assert.Error(t, ValidateSPIFFEIDTemplate(template), template)
+		}
+	})
+}
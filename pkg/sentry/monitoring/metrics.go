@@ -25,6 +25,10 @@ var (
 		"sentry/cert/sign/failure_total",
 		"The number of errors occurred when signing the CSR.",
 		stats.UnitDimensionless)
+	csrAnomalyTotal = stats.Int64(
+		"sentry/cert/sign/anomaly_total",
+		"The number of CSRs above the rate limits or with an identity that failed the validation.",
+		stats.UnitDimensionless)
 	serverTLSCertIssueFailedTotal = stats.Int64(
 		"sentry/servercert/issue_failed_total",
 		"The number of server TLS certificate issuance failures.",
@@ -37,10 +41,37 @@ var (
 		"sentry/issuercert/expiry_timestamp",
 		"The unix timestamp, in seconds, when issuer/root cert will expire.",
 		stats.UnitDimensionless)
+	rootCertExpiryTimestamp = stats.Int64(
+		"sentry/rootcert/expiry_timestamp",
+		"The unix timestamp, in seconds, when root cert will expire.",
+		stats.UnitDimensionless)
+	certTimeToExpiry = stats.Int64(
+		"sentry/cert/time_to_expiry_seconds",
+		"The number of seconds until the issuer or root cert expires.",
+		stats.UnitSeconds)
+	certExpiryWarningTotal = stats.Int64(
+		"sentry/cert/expiry_warning_total",
+		"The number of warnings emitted because the issuer or root cert is nearing its expiration or has expired.",
+		stats.UnitDimensionless)
+	certSignLatency = stats.Float64(
+		"sentry/cert/sign/latency",
+		"The latency of handling a CSR, from the moment it's received until the certificate is issued.",
+		stats.UnitMilliseconds)
 
 	// Metrics Tags.
 	failedReasonKey = tag.MustNewKey("reason")
+	certTypeKey     = tag.MustNewKey("cert")
 	noKeys          = []tag.Key{}
+
+	// Metrics Distribution.
+	certSignLatencyDistribution = view.Distribution(1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000)
+)
+
+const (
+	// CertTypeRoot is the value of the "cert" tag for the root certificate.
+	CertTypeRoot = "root"
+	// CertTypeIssuer is the value of the "cert" tag for the issuer certificate.
+	CertTypeIssuer = "issuer"
 )
 
 // CertSignRequestReceived counts when CSR received.
@@ -53,19 +84,54 @@ func CertSignSucceed() {
 	stats.Record(context.Background(), certSignSuccessTotal.M(1))
 }
 
-// CertSignFailed counts succeeded cert issuance.
+// CertSignLatency records the time spent handling a CSR that resulted in a certificate being issued.
+func CertSignLatency(start time.Time) {
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	stats.Record(context.Background(), certSignLatency.M(elapsed))
+}
+
+// CertSignFailed counts failed cert issuance.
 func CertSignFailed(reason string) {
 	stats.RecordWithTags(
 		context.Background(),
-		diagUtils.WithTags(failedReasonKey, reason),
+		diagUtils.WithTags(certSignFailedTotal.Name(), failedReasonKey, reason),
 		certSignFailedTotal.M(1))
 }
 
+// CSRAnomaly counts the CSRs above the rate limits or with an invalid identity.
+func CSRAnomaly(reason string) {
+	stats.RecordWithTags(
+		context.Background(),
+		diagUtils.WithTags(csrAnomalyTotal.Name(), failedReasonKey, reason),
+		csrAnomalyTotal.M(1))
+}
+
 // IssuerCertExpiry records root cert expiry.
 func IssuerCertExpiry(expiry *time.Time) {
 	stats.Record(context.Background(), issuerCertExpiryTimestamp.M(expiry.Unix()))
 }
 
+// RootCertExpiry records root cert expiry.
+func RootCertExpiry(expiry time.Time) {
+	stats.Record(context.Background(), rootCertExpiryTimestamp.M(expiry.Unix()))
+}
+
+// CertTimeToExpiry records the time remaining until the root or issuer cert expires.
+func CertTimeToExpiry(certType string, remaining time.Duration) {
+	stats.RecordWithTags(
+		context.Background(),
+		diagUtils.WithTags(certTimeToExpiry.Name(), certTypeKey, certType),
+		certTimeToExpiry.M(int64(remaining.Seconds())))
+}
+
+// CertExpiryWarning counts warnings about the root or issuer cert nearing its expiration.
+func CertExpiryWarning(certType string) {
+	stats.RecordWithTags(
+		context.Background(),
+		diagUtils.WithTags(certExpiryWarningTotal.Name(), certTypeKey, certType),
+		certExpiryWarningTotal.M(1))
+}
+
 // ServerCertIssueFailed records server cert issue failure.
 func ServerCertIssueFailed(reason string) {
 	stats.Record(context.Background(), serverTLSCertIssueFailedTotal.M(1))
@@ -82,8 +148,13 @@ func InitMetrics() error {
 		diagUtils.NewMeasureView(csrReceivedTotal, noKeys, view.Count()),
 		diagUtils.NewMeasureView(certSignSuccessTotal, noKeys, view.Count()),
 		diagUtils.NewMeasureView(certSignFailedTotal, []tag.Key{failedReasonKey}, view.Count()),
+		diagUtils.NewMeasureView(csrAnomalyTotal, []tag.Key{failedReasonKey}, view.Count()),
 		diagUtils.NewMeasureView(serverTLSCertIssueFailedTotal, []tag.Key{failedReasonKey}, view.Count()),
 		diagUtils.NewMeasureView(issuerCertChangedTotal, noKeys, view.Count()),
 		diagUtils.NewMeasureView(issuerCertExpiryTimestamp, noKeys, view.LastValue()),
+		diagUtils.NewMeasureView(rootCertExpiryTimestamp, noKeys, view.LastValue()),
+		diagUtils.NewMeasureView(certTimeToExpiry, []tag.Key{certTypeKey}, view.LastValue()),
+		diagUtils.NewMeasureView(certExpiryWarningTotal, []tag.Key{certTypeKey}, view.Count()),
+		diagUtils.NewMeasureView(certSignLatency, noKeys, certSignLatencyDistribution),
 	)
 }
@@ -1,6 +1,7 @@
 package sentry
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/pem"
@@ -9,14 +10,17 @@ import (
 
 	"github.com/pkg/errors"
 
+	daprGlobalConfig "github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/sentry/ca"
 	"github.com/dapr/dapr/pkg/sentry/config"
 	"github.com/dapr/dapr/pkg/sentry/identity"
+	"github.com/dapr/dapr/pkg/sentry/identity/jwks"
 	"github.com/dapr/dapr/pkg/sentry/identity/kubernetes"
 	"github.com/dapr/dapr/pkg/sentry/identity/selfhosted"
 	k8s "github.com/dapr/dapr/pkg/sentry/kubernetes"
 	"github.com/dapr/dapr/pkg/sentry/monitoring"
 	"github.com/dapr/dapr/pkg/sentry/server"
+	"github.com/dapr/dapr/pkg/sentry/trustbundle"
 	"github.com/dapr/kit/logger"
 )
 
@@ -36,6 +40,16 @@ type sentry struct {
 	restartLock sync.Mutex
 	running     chan bool
 	stopping    chan bool
+
+	// rootCertPem is the root cert loaded from the issuer credentials, without the previous roots.
+	rootCertPem []byte
+	// previousRoots are the root certs replaced during a rotation, which are trusted until the end of the rotation window.
+	previousRoots []previousRoot
+}
+
+type previousRoot struct {
+	certPem      []byte
+	trustedUntil time.Time
 }
 
 // NewSentryCA returns a new Sentry Certificate Authority instance.
@@ -62,6 +76,11 @@ func (s *sentry) Start(ctx context.Context, conf config.SentryConfig) error {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	go s.run(certAuth, v)
 
+	// Restart at the end of the rotation window to stop trusting the previous roots
+	if until, ok := s.rootRotationWindowEnd(); ok {
+		go s.endRootRotationWindow(s.ctx, ctx, until)
+	}
+
 	// Wait 100ms to ensure a clean startup
 	time.Sleep(100 * time.Millisecond)
 
@@ -90,9 +109,10 @@ func (s *sentry) createCAServer() (ca.CertificateAuthority, identity.Validator)
 		log.Infof("trust root bundle loaded. issuer cert expiry: %s", certExpiry.String())
 	}
 	monitoring.IssuerCertExpiry(certExpiry)
+	s.trustPreviousRoots(certAuth)
 
 	// Create identity validator
-	v, validatorErr := createValidator()
+	v, validatorErr := createValidator(s.conf)
 	if validatorErr != nil {
 		log.Fatalf("error creating validator: %s", validatorErr)
 	}
@@ -104,10 +124,15 @@ func (s *sentry) createCAServer() (ca.CertificateAuthority, identity.Validator)
 // Runs the CA server.
 // This method blocks until the server is shut down.
 func (s *sentry) run(certAuth ca.CertificateAuthority, v identity.Validator) {
-	s.server = server.NewCAServer(certAuth, v)
+	s.server = server.NewCAServer(certAuth, v, s.conf.AllowedDNSNames, s.conf.CSRRateLimit)
 
 	// In background, watch for the root certificate's expiration
-	go watchCertExpiry(s.ctx, certAuth)
+	go watchCertExpiry(s.ctx, certAuth, s.conf.CertExpiryWarningThreshold)
+
+	// In Kubernetes, publish the trust bundle to every namespace so sidecars can consume it
+	if config.IsKubernetesHosted() {
+		go publishTrustBundle(s.ctx, certAuth)
+	}
 
 	// Watch for context cancelation to stop the server
 	go func() {
@@ -128,6 +153,60 @@ func (s *sentry) run(certAuth ca.CertificateAuthority, v identity.Validator) {
 	}
 }
 
+// trustPreviousRoots adds the previous root certs to the trust anchors of the CA during the rotation window,
+// so that the certificates chained to them are trusted until the workloads renew them.
+// The trust bundle published to the sidecars and the trust chain of the signed certificates include them too.
+func (s *sentry) trustPreviousRoots(certAuth ca.CertificateAuthority) {
+	now := time.Now()
+	rootCertPem := certAuth.GetCACertBundle().GetRootCertPem()
+	if s.rootCertPem != nil && !bytes.Equal(s.rootCertPem, rootCertPem) && s.conf.RootRotationWindow > 0 {
+		trustedUntil := now.Add(s.conf.RootRotationWindow)
+		log.Infof("root certificate changed; the previous root certificate is trusted until %s", trustedUntil.String())
+		s.previousRoots = append(s.previousRoots, previousRoot{certPem: s.rootCertPem, trustedUntil: trustedUntil})
+	}
+	s.rootCertPem = rootCertPem
+
+	roots := make([]previousRoot, 0, len(s.previousRoots))
+	for _, root := range s.previousRoots {
+		if !root.trustedUntil.After(now) {
+			continue
+		}
+		if err := certAuth.AddTrustAnchors(root.certPem); err != nil {
+			log.Warnf("could not trust the previous root certificate: %s", err)
+			continue
+		}
+		roots = append(roots, root)
+	}
+	s.previousRoots = roots
+}
+
+// rootRotationWindowEnd returns the time when the first of the previous roots stops being trusted.
+func (s *sentry) rootRotationWindowEnd() (time.Time, bool) {
+	var end time.Time
+	for _, root := range s.previousRoots {
+		if end.IsZero() || root.trustedUntil.Before(end) {
+			end = root.trustedUntil
+		}
+	}
+	return end, !end.IsZero()
+}
+
+// endRootRotationWindow restarts the server at the end of the rotation window, unless it is stopped before.
+// This is a blocking method that should be run in its own goroutine.
+func (s *sentry) endRootRotationWindow(ctx, parentCtx context.Context, until time.Time) {
+	timer := time.NewTimer(time.Until(until))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		log.Info("root rotation window ended; reloading")
+		if err := s.Restart(parentCtx, s.conf); err != nil {
+			log.Errorf("failed to restart sentry server: %s", err)
+		}
+	case <-ctx.Done():
+	}
+}
+
 // Stop the server.
 func (s *sentry) Stop() {
 	log.Info("sentry certificate authority is shutting down")
@@ -141,48 +220,114 @@ func (s *sentry) Stop() {
 
 // Watches certificates' expiry and shows an error message when they're nearing expiration time.
 // This is a blocking method that should be run in its own goroutine.
-func watchCertExpiry(ctx context.Context, certAuth ca.CertificateAuthority) {
+func watchCertExpiry(ctx context.Context, certAuth ca.CertificateAuthority, warningThreshold time.Duration) {
 	log.Debug("starting root certificate expiration watcher")
 	certExpiryCheckTicker := time.NewTicker(time.Hour)
+	defer certExpiryCheckTicker.Stop()
+
+	checkCertsExpiry(certAuth, warningThreshold)
 	for {
 		select {
 		case <-certExpiryCheckTicker.C:
-			caCrt := certAuth.GetCACertBundle().GetRootCertPem()
-			block, _ := pem.Decode(caCrt)
-			cert, certParseErr := x509.ParseCertificate(block.Bytes)
-			if certParseErr != nil {
-				log.Warn("could not determine Dapr root certificate expiration time")
-				break
-			}
-			if cert.NotAfter.Before(time.Now().UTC()) {
-				log.Warn("Dapr root certificate expiration warning: certificate has expired.")
-				break
-			}
-			if (cert.NotAfter.Add(-30 * 24 * time.Hour)).Before(time.Now().UTC()) {
-				expiryDurationHours := int(cert.NotAfter.Sub(time.Now().UTC()).Hours())
-				log.Warnf("Dapr root certificate expiration warning: certificate expires in %d days and %d hours", expiryDurationHours/24, expiryDurationHours%24)
-			} else {
-				validity := cert.NotAfter.Sub(time.Now().UTC())
-				log.Debugf("Dapr root certificate is still valid for %s", validity.String())
-			}
+			checkCertsExpiry(certAuth, warningThreshold)
 		case <-ctx.Done():
 			log.Debug("terminating root certificate expiration watcher")
-			certExpiryCheckTicker.Stop()
 			return
 		}
 	}
 }
 
-func createValidator() (identity.Validator, error) {
+// Checks the expiry of the root and issuer certificates, recording metrics and logging warnings as needed.
+func checkCertsExpiry(certAuth ca.CertificateAuthority, warningThreshold time.Duration) {
+	bundle := certAuth.GetCACertBundle()
+	checkCertExpiry(monitoring.CertTypeRoot, bundle.GetRootCertPem(), warningThreshold)
+	checkCertExpiry(monitoring.CertTypeIssuer, bundle.GetIssuerCertPem(), warningThreshold)
+}
+
+func checkCertExpiry(certType string, certPem []byte, warningThreshold time.Duration) {
+	block, _ := pem.Decode(certPem)
+	if block == nil {
+		log.Warnf("could not determine Dapr %s certificate expiration time", certType)
+		return
+	}
+	cert, certParseErr := x509.ParseCertificate(block.Bytes)
+	if certParseErr != nil {
+		log.Warnf("could not determine Dapr %s certificate expiration time", certType)
+		return
+	}
+
+	now := time.Now().UTC()
+	validity := cert.NotAfter.Sub(now)
+	monitoring.CertTimeToExpiry(certType, validity)
+	if certType == monitoring.CertTypeRoot {
+		monitoring.RootCertExpiry(cert.NotAfter)
+	}
+
+	switch {
+	case cert.NotAfter.Before(now):
+		monitoring.CertExpiryWarning(certType)
+		log.Warnf("Dapr %s certificate expiration warning: certificate has expired.", certType)
+	case cert.NotAfter.Add(-warningThreshold).Before(now):
+		monitoring.CertExpiryWarning(certType)
+		expiryDurationHours := int(validity.Hours())
+		log.Warnf("Dapr %s certificate expiration warning: certificate expires in %d days and %d hours", certType, expiryDurationHours/24, expiryDurationHours%24)
+	default:
+		log.Debugf("Dapr %s certificate is still valid for %s", certType, validity.String())
+	}
+}
+
+// Publishes the trust anchors to a ConfigMap in every namespace and keeps them in sync.
+// This is a blocking method that should be run in its own goroutine.
+func publishTrustBundle(ctx context.Context, certAuth ca.CertificateAuthority) {
+	kubeClient, err := k8s.GetClient()
+	if err != nil {
+		log.Errorf("failed to create kubernetes client, trust bundle will not be published: %s", err)
+		return
+	}
+	trustbundle.NewPublisher(kubeClient, certAuth.GetCACertBundle().GetRootCertPem()).Run(ctx)
+}
+
+func createValidator(conf config.SentryConfig) (identity.Validator, error) {
+	validators := make([]identity.Validator, 0, len(conf.TokenValidators)+1)
+
 	if config.IsKubernetesHosted() {
 		// we're in Kubernetes, create client and init a new serviceaccount token validator
 		kubeClient, err := k8s.GetClient()
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create kubernetes client")
 		}
-		return kubernetes.NewValidator(kubeClient), nil
+		validators = append(validators, kubernetes.NewValidator(kubeClient))
+	} else if len(conf.TokenValidators) == 0 {
+		// in self-hosted mode, the insecure validator is used only if no other validator is configured
+		return selfhosted.NewValidator(), nil
+	}
+
+	for _, spec := range conf.TokenValidators {
+		v, err := createTokenValidator(spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create validator '%s'", spec.Name)
+		}
+		validators = append(validators, v)
+		log.Infof("token validator '%s' enabled", spec.Name)
+	}
+
+	if len(validators) == 1 {
+		return validators[0], nil
+	}
+	return identity.NewChainValidator(validators...), nil
+}
+
+func createTokenValidator(spec daprGlobalConfig.ValidatorSpec) (identity.Validator, error) {
+	switch spec.Name {
+	case jwks.Name:
+		opts, err := jwks.OptionsFromMap(spec.Options)
+		if err != nil {
+			return nil, err
+		}
+		return jwks.NewValidator(opts)
+	default:
+		return nil, errors.Errorf("validator type '%s' is not supported", spec.Name)
 	}
-	return selfhosted.NewValidator(), nil
 }
 
 func (s *sentry) Restart(ctx context.Context, conf config.SentryConfig) error {
@@ -0,0 +1,120 @@
+package sentry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+
+	"github.com/dapr/dapr/pkg/sentry/monitoring"
+)
+
+func generateCertPem(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cluster.local"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// certMetricRow returns the row of the view with the cert tag value, or nil if it has none.
+func certMetricRow(t *testing.T, name, certType string) *view.Row {
+	t.Helper()
+
+	rows, err := view.RetrieveData(name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key.Name() == "cert" && tag.Value == certType {
+				return row
+			}
+		}
+	}
+	return nil
+}
+
+func TestCheckCertExpiry(t *testing.T) {
+	require.NoError(t, monitoring.InitMetrics())
+
+	const warningThreshold = 30 * 24 * time.Hour
+
+	testCases := []struct {
+		name     string
+		certType string
+		certPem  func(t *testing.T) []byte
+		warning  bool
+		// the expected time to expiry, if recorded
+		timeToExpiry *time.Duration
+	}{
+		{
+			name:         "expired",
+			certType:     "expired",
+			certPem:      func(t *testing.T) []byte { return generateCertPem(t, time.Now().Add(-time.Hour)) },
+			warning:      true,
+			timeToExpiry: durationPtr(-time.Hour),
+		},
+		{
+			name:         "near expiry",
+			certType:     "near-expiry",
+			certPem:      func(t *testing.T) []byte { return generateCertPem(t, time.Now().Add(10*24*time.Hour)) },
+			warning:      true,
+			timeToExpiry: durationPtr(10 * 24 * time.Hour),
+		},
+		{
+			name:         "healthy",
+			certType:     "healthy",
+			certPem:      func(t *testing.T) []byte { return generateCertPem(t, time.Now().Add(90*24*time.Hour)) },
+			warning:      false,
+			timeToExpiry: durationPtr(90 * 24 * time.Hour),
+		},
+		{
+			name:     "invalid pem",
+			certType: "invalid",
+			certPem:  func(t *testing.T) []byte { return []byte("not a certificate") },
+			warning:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkCertExpiry(tc.certType, tc.certPem(t), warningThreshold)
+
+			warnings := certMetricRow(t, "sentry/cert/expiry_warning_total", tc.certType)
+			if tc.warning {
+				require.NotNil(t, warnings)
+				assert.Equal(t, int64(1), warnings.Data.(*view.CountData).Value)
+			} else {
+				assert.Nil(t, warnings)
+			}
+
+			remaining := certMetricRow(t, "sentry/cert/time_to_expiry_seconds", tc.certType)
+			if tc.timeToExpiry == nil {
+				assert.Nil(t, remaining)
+				return
+			}
+			require.NotNil(t, remaining)
+			assert.InDelta(t, tc.timeToExpiry.Seconds(), remaining.Data.(*view.LastValueData).Value, 60)
+		})
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+// Reasons of the CSR anomalies.
+const (
+	anomalyAppIDRateLimit     = "app_id_rate_limit"
+	anomalyNamespaceRateLimit = "namespace_rate_limit"
+	anomalyIdentityMismatch   = "identity_mismatch"
+
+	// maxIdleBuckets is the number of buckets above which the full buckets are forgotten.
+	maxIdleBuckets = 10000
+)
+
+// tokenBucket is the state of the rate limit of an app ID or a namespace.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	// limited is true while the requests are above the limit, so that the anomaly is logged once.
+	limited bool
+}
+
+type rateLimit struct {
+	// rate is the number of tokens added per second.
+	rate  float64
+	burst float64
+}
+
+// csrLimiter limits the CSRs of each app ID and namespace with token buckets.
+type csrLimiter struct {
+	appID     *rateLimit
+	namespace *rateLimit
+	reject    bool
+
+	lock    sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// newCSRLimiter returns the limiter of the spec, or nil if the spec has no limit.
+func newCSRLimiter(spec *config.CSRRateLimitSpec) *csrLimiter {
+	if spec == nil || (spec.AppIDRequestsPerMinute <= 0 && spec.NamespaceRequestsPerMinute <= 0) {
+		return nil
+	}
+
+	newRateLimit := func(perMinute int) *rateLimit {
+		if perMinute <= 0 {
+			return nil
+		}
+		burst := spec.Burst
+		if burst <= 0 {
+			burst = perMinute
+		}
+		return &rateLimit{rate: float64(perMinute) / 60, burst: float64(burst)}
+	}
+	return &csrLimiter{
+		appID:     newRateLimit(spec.AppIDRequestsPerMinute),
+		namespace: newRateLimit(spec.NamespaceRequestsPerMinute),
+		reject:    spec.Enforcement == config.CSRRateLimitReject,
+		buckets:   map[string]*tokenBucket{},
+		now:       time.Now,
+	}
+}
+
+// allow takes a token of the buckets of the app ID and of the namespace. It returns the reason of the anomaly
+// if a bucket is empty, and whether the anomaly must be logged as it is the first request above the limit.
+func (l *csrLimiter) allow(appID, namespace string) (anomaly string, first bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if len(l.buckets) > maxIdleBuckets {
+		l.forgetFullBuckets(now)
+	}
+
+	// Both buckets are checked before taking the tokens, so that a rejected request doesn't consume any.
+	var appIDBucket, namespaceBucket *tokenBucket
+	if l.appID != nil {
+		appIDBucket = l.refill("app/"+namespace+"/"+appID, l.appID, now)
+	}
+	if l.namespace != nil {
+		namespaceBucket = l.refill("ns/"+namespace, l.namespace, now)
+	}
+
+	switch {
+	case appIDBucket != nil && appIDBucket.tokens < 1:
+		first = !appIDBucket.limited
+		appIDBucket.limited = true
+		return anomalyAppIDRateLimit, first
+	case namespaceBucket != nil && namespaceBucket.tokens < 1:
+		first = !namespaceBucket.limited
+		namespaceBucket.limited = true
+		return anomalyNamespaceRateLimit, first
+	}
+
+	for _, b := range []*tokenBucket{appIDBucket, namespaceBucket} {
+		if b != nil {
+			b.tokens--
+			b.limited = false
+		}
+	}
+	return "", false
+}
+
+func (l *csrLimiter) refill(key string, limit *rateLimit, now time.Time) *tokenBucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: limit.burst, last: now}
+		l.buckets[key] = b
+		return b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * limit.rate
+	if b.tokens > limit.burst {
+		b.tokens = limit.burst
+	}
+	b.last = now
+	return b
+}
+
+// forgetFullBuckets removes the buckets which would be full by now, as they are the same as new ones.
+func (l *csrLimiter) forgetFullBuckets(now time.Time) {
+	for key, b := range l.buckets {
+		limit := l.appID
+		if strings.HasPrefix(key, "ns/") {
+			limit = l.namespace
+		}
+		if b.tokens+now.Sub(b.last).Seconds()*limit.rate >= limit.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dapr/dapr/pkg/config"
+	sentryv1pb "github.com/dapr/dapr/pkg/proto/sentry/v1"
+	"github.com/dapr/dapr/pkg/sentry/ca"
+	"github.com/dapr/dapr/pkg/sentry/csr"
+	"github.com/dapr/dapr/pkg/sentry/identity"
+)
+
+func newTestLimiter(spec *config.CSRRateLimitSpec) (*csrLimiter, *time.Time) {
+	now := time.Unix(1000, 0)
+	l := newCSRLimiter(spec)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestCSRLimiter(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
+		assert.Nil(t, newCSRLimiter(nil))
+		assert.Nil(t, newCSRLimiter(&config.CSRRateLimitSpec{Enforcement: config.CSRRateLimitReject}))
+	})
+
+	t.Run("app ID limit", func(t *testing.T) {
+		l, now := newTestLimiter(&config.CSRRateLimitSpec{AppIDRequestsPerMinute: 60, Burst: 2})
+
+		for i := 0; i < 2; i++ {
+			anomaly, _ := l.allow("app1", "default")
+			assert.Empty(t, anomaly)
+		}
+		anomaly, first := l.allow("app1", "default")
+		assert.Equal(t, anomalyAppIDRateLimit, anomaly)
+		assert.True(t, first)
+		anomaly, first = l.allow("app1", "default")
+		assert.Equal(t, anomalyAppIDRateLimit, anomaly)
+		assert.False(t, first)
+
+		// The other apps and namespaces have their own buckets.
+		anomaly, _ = l.allow("app2", "default")
+		assert.Empty(t, anomaly)
+		anomaly, _ = l.allow("app1", "other")
+		assert.Empty(t, anomaly)
+
+		// A token is added every second.
+		*now = now.Add(time.Second)
+		anomaly, _ = l.allow("app1", "default")
+		assert.Empty(t, anomaly)
+		anomaly, first = l.allow("app1", "default")
+		assert.Equal(t, anomalyAppIDRateLimit, anomaly)
+		assert.True(t, first)
+	})
+
+	t.Run("namespace limit", func(t *testing.T) {
+		l, _ := newTestLimiter(&config.CSRRateLimitSpec{AppIDRequestsPerMinute: 10, NamespaceRequestsPerMinute: 2})
+
+		anomaly, _ := l.allow("app1", "default")
+		assert.Empty(t, anomaly)
+		anomaly, _ = l.allow("app2", "default")
+		assert.Empty(t, anomaly)
+		anomaly, _ = l.allow("app3", "default")
+		assert.Equal(t, anomalyNamespaceRateLimit, anomaly)
+
+		// The rejected request didn't take a token of its app ID.
+		assert.Equal(t, float64(10), l.buckets["app/default/app3"].tokens)
+	})
+}
+
+func TestCheckRateLimit(t *testing.T) {
+	spec := &config.CSRRateLimitSpec{AppIDRequestsPerMinute: 1}
+
+	s := &server{limiter: newCSRLimiter(spec)}
+	assert.NoError(t, s.checkRateLimit("app1", "default"))
+	assert.NoError(t, s.checkRateLimit("app1", "default"), "requests above the limit are only reported by default")
+
+	spec.Enforcement = config.CSRRateLimitReject
+	s = &server{limiter: newCSRLimiter(spec)}
+	assert.NoError(t, s.checkRateLimit("app1", "default"))
+	assert.Error(t, s.checkRateLimit("app1", "default"))
+
+	assert.NoError(t, (&server{}).checkRateLimit("app1", "default"))
+}
+
+type fakeCA struct {
+	ca.CertificateAuthority
+}
+
+func (fakeCA) ValidateCSR(*x509.CertificateRequest) error {
+	return nil
+}
+
+func (fakeCA) SignCSR([]byte, string, *identity.Bundle, time.Duration, bool) (*ca.SignedCertificate, error) {
+	return nil, errors.New("not signed")
+}
+
+type fakeValidator struct {
+	ids map[string]string
+}
+
+func (v fakeValidator) Validate(id, token, namespace string) error {
+	if v.ids[id] != token {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+func TestSignCertificateRateLimit(t *testing.T) {
+	s := &server{
+		certAuth:  fakeCA{},
+		validator: fakeValidator{ids: map[string]string{"default:orders": "token"}},
+		limiter: newCSRLimiter(&config.CSRRateLimitSpec{
+			AppIDRequestsPerMinute: 1,
+			Enforcement:            config.CSRRateLimitReject,
+		}),
+	}
+	csrPem, _, err := csr.GenerateCSR("", false)
+	require.NoError(t, err)
+
+	// The requests that fail the validation don't take the tokens of the app
+	for i := 0; i < 3; i++ {
+		_, err = s.SignCertificate(context.Background(), &sentryv1pb.SignCertificateRequest{
+			CertificateSigningRequest: csrPem,
+			Id:                        "default:orders",
+			Token:                     "forged",
+			Namespace:                 "default",
+		})
+		require.Error(t, err)
+		assert.NotEqual(t, codes.ResourceExhausted, status.Code(err))
+	}
+	assert.Empty(t, s.limiter.buckets)
+
+	// The buckets are keyed on the verified ID, not on the common name of the CSR
+	req := &sentryv1pb.SignCertificateRequest{
+		CertificateSigningRequest: csrPem,
+		Id:                        "default:orders",
+		Token:                     "token",
+		Namespace:                 "default",
+	}
+	_, err = s.SignCertificate(context.Background(), req)
+	assert.ErrorContains(t, err, "not signed")
+	_, err = s.SignCertificate(context.Background(), req)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Len(t, s.limiter.buckets, 1)
+	for key := range s.limiter.buckets {
+		assert.Contains(t, key, "default:orders")
+	}
+}
@@ -3,23 +3,28 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/config"
 	sentryv1pb "github.com/dapr/dapr/pkg/proto/sentry/v1"
 	"github.com/dapr/dapr/pkg/sentry/ca"
 	"github.com/dapr/dapr/pkg/sentry/certs"
 	"github.com/dapr/dapr/pkg/sentry/csr"
 	"github.com/dapr/dapr/pkg/sentry/identity"
 	"github.com/dapr/dapr/pkg/sentry/monitoring"
+	"github.com/dapr/dapr/utils"
 )
 
 const (
@@ -39,13 +44,20 @@ type server struct {
 	certAuth    ca.CertificateAuthority
 	srv         *grpc.Server
 	validator   identity.Validator
+	// allowedDNSNames are the patterns of the DNS names that the sidecars of each app ID can request on their certificates.
+	allowedDNSNames []config.AllowedDNSNamesSpec
+	// limiter is the rate limiter of the CSRs, nil if they aren't limited.
+	limiter *csrLimiter
 }
 
 // NewCAServer returns a new CA Server running a gRPC server.
-func NewCAServer(ca ca.CertificateAuthority, validator identity.Validator) CAServer {
+// The CSRs of each verified requester ID and namespace are limited by csrRateLimit, if set.
+func NewCAServer(ca ca.CertificateAuthority, validator identity.Validator, allowedDNSNames []config.AllowedDNSNamesSpec, csrRateLimit *config.CSRRateLimitSpec) CAServer {
 	return &server{
-		certAuth:  ca,
-		validator: validator,
+		certAuth:        ca,
+		validator:       validator,
+		allowedDNSNames: allowedDNSNames,
+		limiter:         newCSRLimiter(csrRateLimit),
 	}
 }
 
@@ -128,6 +140,7 @@ func (s *server) getServerCertificate() (*tls.Certificate, error) {
 // The method receives a request with an identity and initial cert and returns
 // A signed certificate including the trust chain to the caller along with an expiry date.
 func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertificateRequest) (*sentryv1pb.SignCertificateResponse, error) {
+	start := time.Now()
 	monitoring.CertSignRequestReceived()
 
 	csrPem := req.GetCertificateSigningRequest()
@@ -150,13 +163,32 @@ func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertif
 
 	err = s.validator.Validate(req.GetId(), req.GetToken(), req.GetNamespace())
 	if err != nil {
+		monitoring.CSRAnomaly(anomalyIdentityMismatch)
+		log.Warnf("certificate signing request anomaly %s: id %s in namespace %s for app %s", anomalyIdentityMismatch, req.GetId(), req.GetNamespace(), csr.Subject.CommonName)
 		err = errors.Wrap(err, "error validating requester identity")
 		log.Error(err)
 		monitoring.CertSignFailed("req_id_validation")
 		return nil, err
 	}
 
+	// The requests are limited after the validation of the identity, so that the buckets are keyed on the verified
+	// ID and namespace: an unauthenticated caller can't exhaust the buckets of another app with forged CSRs.
+	if err = s.checkRateLimit(req.GetId(), req.GetNamespace()); err != nil {
+		monitoring.CertSignFailed("rate_limited")
+		return nil, err
+	}
+
+	dnsNames, err := s.requestedDNSNames(csr, req.GetId(), req.GetNamespace())
+	if err != nil {
+		log.Error(err)
+		monitoring.CertSignFailed("dns_name_validation")
+		return nil, err
+	}
+
 	identity := identity.NewBundle(csr.Subject.CommonName, req.GetNamespace(), req.GetTrustDomain())
+	if identity != nil {
+		identity.DNSNames = dnsNames
+	}
 	signed, err := s.certAuth.SignCSR(csrPem, csr.Subject.CommonName, identity, -1, false)
 	if err != nil {
 		err = errors.Wrap(err, "error signing csr")
@@ -193,10 +225,65 @@ func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertif
 	}
 
 	monitoring.CertSignSucceed()
+	monitoring.CertSignLatency(start)
 
 	return resp, nil
 }
 
+// checkRateLimit reports the CSRs above the rate limits of the verified ID and namespace of their requester,
+// and returns an error if they must be rejected.
+func (s *server) checkRateLimit(id, namespace string) error {
+	if s.limiter == nil {
+		return nil
+	}
+
+	anomaly, first := s.limiter.allow(id, namespace)
+	if anomaly == "" {
+		return nil
+	}
+	monitoring.CSRAnomaly(anomaly)
+	if first {
+		log.Warnf("certificate signing request anomaly %s: requester %s in namespace %s is above the rate limit", anomaly, id, namespace)
+	}
+	if !s.limiter.reject {
+		return nil
+	}
+	return status.Errorf(codes.ResourceExhausted, "too many certificate signing requests for %s in namespace %s", id, namespace)
+}
+
+// requestedDNSNames returns the DNS names of the CSR in addition to its common name,
+// or an error if one of them isn't allowed for the app ID and the namespace by the configuration.
+// The app ID is the verified ID of the requester, and not the common name of the CSR, which the requester chooses.
+func (s *server) requestedDNSNames(csr *x509.CertificateRequest, appID, namespace string) ([]string, error) {
+	var names []string
+	patterns := s.allowedDNSNamePatterns(appID, namespace)
+	for _, name := range csr.DNSNames {
+		if name == csr.Subject.CommonName {
+			continue
+		}
+		if !identity.IsDNSNameAllowed(patterns, name) {
+			return nil, errors.Errorf("dns name %q is not allowed on the certificate of %s in namespace %s", name, appID, namespace)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// allowedDNSNamePatterns returns the patterns of the DNS names that the app can request in the namespace.
+func (s *server) allowedDNSNamePatterns(appID, namespace string) []string {
+	var patterns []string
+	for _, allowed := range s.allowedDNSNames {
+		if allowed.Namespace != namespace {
+			continue
+		}
+		if !utils.Contains(allowed.AppIDs, appID) {
+			continue
+		}
+		patterns = append(patterns, allowed.Names...)
+	}
+	return patterns
+}
+
 func (s *server) Shutdown() {
 	if s.srv != nil {
 		s.srv.GracefulStop()
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestRequestedDNSNames(t *testing.T) {
+	s := &server{
+		allowedDNSNames: []config.AllowedDNSNamesSpec{
+			{Namespace: "default", AppIDs: []string{"orders"}, Names: []string{"*.orders.example.com"}},
+			{Namespace: "default", AppIDs: []string{"orders", "payments"}, Names: []string{"api.example.com"}},
+			{Namespace: "other", AppIDs: []string{"orders"}, Names: []string{"*.example.com"}},
+		},
+	}
+	newCSR := func(appID string, names ...string) *x509.CertificateRequest {
+		return &x509.CertificateRequest{
+			Subject:  pkix.Name{CommonName: appID},
+			DNSNames: append([]string{appID}, names...),
+		}
+	}
+
+	t.Run("names of the app and the namespace", func(t *testing.T) {
+		names, err := s.requestedDNSNames(newCSR("orders", "v1.orders.example.com", "api.example.com"), "orders", "default")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1.orders.example.com", "api.example.com"}, names)
+	})
+
+	t.Run("names of another app", func(t *testing.T) {
+		names, err := s.requestedDNSNames(newCSR("payments", "api.example.com"), "payments", "default")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"api.example.com"}, names)
+
+		_, err = s.requestedDNSNames(newCSR("payments", "v1.orders.example.com"), "payments", "default")
+		assert.Error(t, err)
+
+		_, err = s.requestedDNSNames(newCSR("web", "api.example.com"), "web", "default")
+		assert.Error(t, err)
+	})
+
+	t.Run("names of the common name of another app", func(t *testing.T) {
+		_, err := s.requestedDNSNames(newCSR("orders", "v1.orders.example.com"), "payments", "default")
+		assert.Error(t, err)
+	})
+
+	t.Run("names of another namespace", func(t *testing.T) {
+		_, err := s.requestedDNSNames(newCSR("orders", "web.example.com"), "orders", "default")
+		assert.Error(t, err)
+
+		names, err := s.requestedDNSNames(newCSR("orders", "web.example.com"), "orders", "other")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"web.example.com"}, names)
+
+		_, err = s.requestedDNSNames(newCSR("orders", "api.example.com"), "orders", "unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("no additional names", func(t *testing.T) {
+		names, err := s.requestedDNSNames(newCSR("orders"), "orders", "unknown")
+		assert.NoError(t, err)
+		assert.Empty(t, names)
+	})
+}
@@ -0,0 +1,113 @@
+package trustbundle
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.sentry.trustbundle")
+
+// resyncPeriod is the interval at which the ConfigMaps in all namespaces are re-checked and restored if modified.
+const resyncPeriod = 10 * time.Minute
+
+// Publisher publishes the trust anchors to a well-known ConfigMap in every namespace of the cluster.
+type Publisher struct {
+	client      kubernetes.Interface
+	rootCertPem []byte
+}
+
+// NewPublisher returns a new Publisher for the given trust anchors.
+func NewPublisher(client kubernetes.Interface, rootCertPem []byte) *Publisher {
+	return &Publisher{
+		client:      client,
+		rootCertPem: rootCertPem,
+	}
+}
+
+// Run publishes the trust anchors to all existing namespaces, and to new namespaces as they are created.
+// This method blocks until the context is canceled.
+func (p *Publisher) Run(ctx context.Context) {
+	factory := informers.NewSharedInformerFactory(p.client, resyncPeriod)
+	informer := factory.Core().V1().Namespaces().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			p.syncNamespace(ctx, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			p.syncNamespace(ctx, newObj)
+		},
+	})
+
+	log.Infof("publishing trust bundle to ConfigMap %s in all namespaces", certs.TrustBundleConfigMapName)
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	log.Debug("terminating trust bundle publisher")
+}
+
+func (p *Publisher) syncNamespace(ctx context.Context, obj interface{}) {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok || ns.Status.Phase == corev1.NamespaceTerminating {
+		return
+	}
+
+	err := p.Sync(ctx, ns.Name)
+	if err != nil {
+		log.Errorf("failed to publish trust bundle to namespace %s: %v", ns.Name, err)
+	}
+}
+
+// Sync creates or updates the trust bundle ConfigMap in the given namespace.
+func (p *Publisher) Sync(ctx context.Context, namespace string) error {
+	cmClient := p.client.CoreV1().ConfigMaps(namespace)
+
+	cm, err := cmClient.Get(ctx, certs.TrustBundleConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = cmClient.Create(ctx, p.configMap(namespace), metav1.CreateOptions{})
+		if err == nil {
+			log.Debugf("created trust bundle ConfigMap in namespace %s", namespace)
+		}
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if bytes.Equal([]byte(cm.Data[certs.TrustBundleConfigMapKey]), p.rootCertPem) {
+		return nil
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[certs.TrustBundleConfigMapKey] = string(p.rootCertPem)
+	_, err = cmClient.Update(ctx, cm, metav1.UpdateOptions{})
+	if err == nil {
+		log.Infof("updated trust bundle ConfigMap in namespace %s", namespace)
+	}
+	return err
+}
+
+func (p *Publisher) configMap(namespace string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      certs.TrustBundleConfigMapName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "dapr-sentry",
+			},
+		},
+		Data: map[string]string{
+			certs.TrustBundleConfigMapKey: string(p.rootCertPem),
+		},
+	}
+}
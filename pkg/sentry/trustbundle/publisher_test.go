@@ -0,0 +1,64 @@
+package trustbundle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+func TestSync(t *testing.T) {
+	rootCert := []byte("-----BEGIN CERTIFICATE-----\nroot\n-----END CERTIFICATE-----\n")
+
+	t.Run("creates the ConfigMap", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		p := NewPublisher(client, rootCert)
+
+		require.NoError(t, p.Sync(context.Background(), "ns1"))
+
+		cm, err := client.CoreV1().ConfigMaps("ns1").Get(context.Background(), certs.TrustBundleConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, string(rootCert), cm.Data[certs.TrustBundleConfigMapKey])
+	})
+
+	t.Run("updates an outdated ConfigMap", func(t *testing.T) {
+		client := fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: certs.TrustBundleConfigMapName, Namespace: "ns1"},
+			Data:       map[string]string{certs.TrustBundleConfigMapKey: "old"},
+		})
+		p := NewPublisher(client, rootCert)
+
+		require.NoError(t, p.Sync(context.Background(), "ns1"))
+
+		cm, err := client.CoreV1().ConfigMaps("ns1").Get(context.Background(), certs.TrustBundleConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, string(rootCert), cm.Data[certs.TrustBundleConfigMapKey])
+	})
+}
+
+func TestRun(t *testing.T) {
+	rootCert := []byte("root")
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}},
+	)
+	p := NewPublisher(client, rootCert)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	for _, ns := range []string{"ns1", "ns2"} {
+		assert.Eventually(t, func() bool {
+			cm, err := client.CoreV1().ConfigMaps(ns).Get(ctx, certs.TrustBundleConfigMapName, metav1.GetOptions{})
+			return err == nil && cm.Data[certs.TrustBundleConfigMapKey] == "root"
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statemerge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"strconv"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+
+	"github.com/dapr/dapr/pkg/channel"
+	stateLoader "github.com/dapr/dapr/pkg/components/state"
+	"github.com/dapr/dapr/pkg/encryption"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+)
+
+const (
+	// MetadataKey is the request metadata opting a save state request in the merge of the conflicting values by the app.
+	MetadataKey = "mergeOnConflict"
+
+	// maxAttempts is the number of times the merged value is saved before giving up, as the value can also be
+	// concurrently modified during the merge.
+	maxAttempts = 3
+
+	mergeMethodFormat = "dapr/state/%s/merge"
+)
+
+var log = logger.NewLogger("dapr.runtime.statemerge")
+
+// Version is a version of a state value.
+type Version struct {
+	Value json.RawMessage `json:"value,omitempty"`
+	ETag  *string         `json:"etag,omitempty"`
+}
+
+// MergeRequest is sent to the app with a POST request to dapr/state/<storeName>/merge when the value it saves conflicts
+// with the stored value.
+type MergeRequest struct {
+	Key      string  `json:"key"`
+	Current  Version `json:"current"`
+	Proposed Version `json:"proposed"`
+}
+
+// MergeResponse is returned by the app with the merged value, which is saved instead of the proposed value.
+type MergeResponse struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// BulkSet saves the requests with set. The requests opting in with the MetadataKey metadata are saved one by one
+// and, on ETag mismatch, their value is merged with the stored value by the app.
+func BulkSet(ctx context.Context, store state.Store, appChannel channel.AppChannel, storeName string, reqs []state.SetRequest, set func(reqs []state.SetRequest) error) error {
+	// The values of the encrypted state stores can't be merged by the app.
+	mergeable := appChannel != nil && !encryption.EncryptedStateStore(storeName)
+
+	var plain []state.SetRequest
+	var merged []state.SetRequest
+	for _, req := range reqs {
+		if requested(&req) && mergeable {
+			merged = append(merged, req)
+		} else {
+			plain = append(plain, req)
+		}
+	}
+
+	if len(plain) > 0 {
+		if err := set(plain); err != nil {
+			return err
+		}
+	}
+	for i := range merged {
+		if err := setWithMerge(ctx, store, appChannel, storeName, &merged[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requested returns true if the request opts in the merge, and removes the metadata not meant for the store.
+func requested(req *state.SetRequest) bool {
+	val, ok := req.Metadata[MetadataKey]
+	if !ok {
+		return false
+	}
+	metadata := make(map[string]string, len(req.Metadata)-1)
+	for k, v := range req.Metadata {
+		if k != MetadataKey {
+			metadata[k] = v
+		}
+	}
+	req.Metadata = metadata
+	enabled, _ := strconv.ParseBool(val)
+	return enabled
+}
+
+func setWithMerge(ctx context.Context, store state.Store, appChannel channel.AppChannel, storeName string, req *state.SetRequest) error {
+	err := store.Set(req)
+	for attempt := 1; attempt <= maxAttempts && isETagMismatch(err); attempt++ {
+		current, getErr := store.Get(&state.GetRequest{Key: req.Key, Metadata: req.Metadata})
+		if getErr != nil {
+			return getErr
+		}
+
+		value, mergeErr := merge(ctx, appChannel, storeName, req, current)
+		if mergeErr != nil {
+			log.Debugf("failed to merge the value of key %s: %s", req.Key, mergeErr)
+			return err
+		}
+
+		req.Value = value
+		req.ETag = current.ETag
+		err = store.Set(req)
+	}
+	return err
+}
+
+func isETagMismatch(err error) bool {
+	var etagErr *state.ETagError
+	return errors.As(err, &etagErr) && etagErr.Kind() == state.ETagMismatch
+}
+
+// merge asks the app to merge the proposed value of the request with the current value and returns the merged value.
+func merge(ctx context.Context, appChannel channel.AppChannel, storeName string, req *state.SetRequest, current *state.GetResponse) ([]byte, error) {
+	proposed, err := proposedValue(req.Value)
+	if err != nil {
+		return nil, err
+	}
+	mergeReq := MergeRequest{
+		Key:      stateLoader.GetOriginalStateKey(req.Key),
+		Proposed: Version{Value: proposed, ETag: req.ETag},
+	}
+	if current != nil {
+		mergeReq.Current = Version{Value: rawValue(current.Data), ETag: current.ETag}
+	}
+	body, err := json.Marshal(mergeReq)
+	if err != nil {
+		return nil, err
+	}
+
+	invokeReq := invokev1.NewInvokeMethodRequest(fmt.Sprintf(mergeMethodFormat, storeName))
+	invokeReq.WithHTTPExtension(nethttp.MethodPost, "")
+	invokeReq.WithRawData(body, invokev1.JSONContentType)
+
+	resp, err := appChannel.InvokeMethod(ctx, invokeReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status().Code != nethttp.StatusOK {
+		return nil, fmt.Errorf("the app returned status %d", resp.Status().Code)
+	}
+
+	_, data := resp.RawData()
+	var mergeResp MergeResponse
+	if err = json.Unmarshal(data, &mergeResp); err != nil {
+		return nil, err
+	}
+	if len(mergeResp.Value) == 0 {
+		return nil, errors.New("the app returned no value")
+	}
+	return mergeResp.Value, nil
+}
+
+func proposedValue(value interface{}) (json.RawMessage, error) {
+	if b, ok := value.([]byte); ok {
+		return rawValue(b), nil
+	}
+	return json.Marshal(value)
+}
+
+// rawValue returns the value as-is when it's JSON, and as a JSON string otherwise.
+func rawValue(value []byte) json.RawMessage {
+	if len(value) == 0 {
+		return nil
+	}
+	if json.Valid(value) {
+		return value
+	}
+	s, _ := json.Marshal(string(value))
+	return s
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statemerge
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/state"
+
+	channelt "github.com/dapr/dapr/pkg/channel/testing"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	daprt "github.com/dapr/dapr/pkg/testing"
+)
+
+// etagStore is a state store checking the ETags of the saved values.
+type etagStore struct {
+	daprt.MockStateStore
+	values  map[string][]byte
+	version int
+	// onSet is called before a value is saved.
+	onSet func(req *state.SetRequest)
+}
+
+func (s *etagStore) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	etag := strconv.Itoa(s.version)
+	return &state.GetResponse{Data: s.values[req.Key], ETag: &etag}, nil
+}
+
+func (s *etagStore) Set(req *state.SetRequest) error {
+	if s.onSet != nil {
+		s.onSet(req)
+	}
+	if req.ETag != nil && *req.ETag != strconv.Itoa(s.version) {
+		return state.NewETagError(state.ETagMismatch, nil)
+	}
+	var err error
+	if b, ok := req.Value.([]byte); ok {
+		s.values[req.Key] = b
+	} else {
+		s.values[req.Key], err = json.Marshal(req.Value)
+	}
+	s.version++
+	return err
+}
+
+func (s *etagStore) BulkSet(reqs []state.SetRequest) error {
+	for i := range reqs {
+		if err := s.Set(&reqs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mergeResponse(value string) *invokev1.InvokeMethodResponse {
+	resp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+	resp.WithRawData([]byte(`{"value":`+value+`}`), invokev1.JSONContentType)
+	return resp
+}
+
+func TestBulkSet(t *testing.T) {
+	staleETag := "0"
+
+	t.Run("conflicting value merged by the app", func(t *testing.T) {
+		store := &etagStore{values: map[string][]byte{"app||key": []byte(`{"count":1}`)}, version: 1}
+		appChannel := new(channelt.MockAppChannel)
+		appChannel.On("InvokeMethod", mock.Anything, mock.MatchedBy(func(req *invokev1.InvokeMethodRequest) bool {
+			if req.Message().Method != "dapr/state/store/merge" {
+				return false
+			}
+			var mergeReq MergeRequest
+			_, data := req.RawData()
+			require.NoError(t, json.Unmarshal(data, &mergeReq))
+			return mergeReq.Key == "key" &&
+				string(mergeReq.Current.Value) == `{"count":1}` && *mergeReq.Current.ETag == "1" &&
+				string(mergeReq.Proposed.Value) == `{"count":2}`
+		})).Return(mergeResponse(`{"count":3}`), nil).Once()
+
+		err := BulkSet(context.Background(), store, appChannel, "store", []state.SetRequest{{
+			Key:      "app||key",
+			Value:    map[string]int{"count": 2},
+			ETag:     &staleETag,
+			Metadata: map[string]string{MetadataKey: "true"},
+		}}, store.BulkSet)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"count":3}`, string(store.values["app||key"]))
+		appChannel.AssertExpectations(t)
+	})
+
+	t.Run("merge metadata is not passed to the store", func(t *testing.T) {
+		store := &etagStore{values: map[string][]byte{}}
+		store.onSet = func(req *state.SetRequest) {
+			assert.NotContains(t, req.Metadata, MetadataKey)
+			assert.Equal(t, "1", req.Metadata["ttlInSeconds"])
+		}
+		err := BulkSet(context.Background(), store, new(channelt.MockAppChannel), "store", []state.SetRequest{{
+			Key:      "key",
+			Value:    "value",
+			Metadata: map[string]string{MetadataKey: "true", "ttlInSeconds": "1"},
+		}}, store.BulkSet)
+		assert.NoError(t, err)
+	})
+
+	t.Run("conflict when not opted in", func(t *testing.T) {
+		store := &etagStore{values: map[string][]byte{}, version: 1}
+		err := BulkSet(context.Background(), store, new(channelt.MockAppChannel), "store", []state.SetRequest{{
+			Key:   "key",
+			Value: "value",
+			ETag:  &staleETag,
+		}}, store.BulkSet)
+		assert.True(t, isETagMismatch(err))
+	})
+
+	t.Run("conflict when the app fails to merge", func(t *testing.T) {
+		store := &etagStore{values: map[string][]byte{}, version: 1}
+		appChannel := new(channelt.MockAppChannel)
+		appChannel.On("InvokeMethod", mock.Anything, mock.Anything).
+			Return(invokev1.NewInvokeMethodResponse(404, "Not Found", nil), nil).Once()
+
+		err := BulkSet(context.Background(), store, appChannel, "store", []state.SetRequest{{
+			Key:      "key",
+			Value:    "value",
+			ETag:     &staleETag,
+			Metadata: map[string]string{MetadataKey: "true"},
+		}}, store.BulkSet)
+		assert.True(t, isETagMismatch(err))
+		appChannel.AssertExpectations(t)
+	})
+
+	t.Run("merge attempts are bounded", func(t *testing.T) {
+		store := &etagStore{values: map[string][]byte{}, version: 1}
+		// The value keeps being modified concurrently
+		store.onSet = func(req *state.SetRequest) {
+			store.version++
+		}
+		appChannel := new(channelt.MockAppChannel)
+		appChannel.On("InvokeMethod", mock.Anything, mock.Anything).Return(mergeResponse(`"merged"`), nil)
+
+		err := BulkSet(context.Background(), store, appChannel, "store", []state.SetRequest{{
+			Key:      "key",
+			Value:    "value",
+			ETag:     &staleETag,
+			Metadata: map[string]string{MetadataKey: "true"},
+		}}, store.BulkSet)
+		assert.True(t, isETagMismatch(err))
+		appChannel.AssertNumberOfCalls(t, "InvokeMethod", maxAttempts)
+	})
+}
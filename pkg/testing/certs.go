@@ -0,0 +1,81 @@
+package testing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dapr/dapr/pkg/credentials"
+)
+
+// GenerateCertChains returns the cert chains of a control plane service and of a sidecar, signed by the same root.
+// The certificate of the control plane service is valid for serverName, the one of the sidecar has the SPIFFE ID.
+func GenerateCertChains(t *testing.T, serverName, spiffeID string) (controlPlane, workload *credentials.CertChain) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: serverName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, root, root, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER})
+
+	newChain := func(serial int64, tmpl *x509.Certificate) *credentials.CertChain {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl.SerialNumber = big.NewInt(serial)
+		tmpl.NotBefore = root.NotBefore
+		tmpl.NotAfter = root.NotAfter
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, root, &key.PublicKey, rootKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &credentials.CertChain{
+			RootCA: rootPem,
+			Cert:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			Key:    pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		}
+	}
+
+	id, err := url.Parse(spiffeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	controlPlane = newChain(2, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: serverName},
+		DNSNames: []string{serverName},
+	})
+	workload = newChain(3, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "app"},
+		DNSNames: []string{serverName},
+		URIs:     []*url.URL{id},
+	})
+	return controlPlane, workload
+}
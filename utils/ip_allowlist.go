@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IPAllowlist is a list of CIDRs the clients must connect from. An empty list allows all the clients.
+type IPAllowlist []*net.IPNet
+
+// ParseIPAllowlist parses a comma separated list of CIDRs or IP addresses.
+func ParseIPAllowlist(val string) (IPAllowlist, error) {
+	var allowlist IPAllowlist
+	for _, entry := range strings.Split(val, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, errors.Errorf("invalid IP address %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			allowlist = append(allowlist, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, errors.Errorf("invalid CIDR %q", entry)
+		}
+		allowlist = append(allowlist, ipNet)
+	}
+	return allowlist, nil
+}
+
+// Allows returns true if the client at the address, with or without a port, is allowed.
+// The loopback addresses are always allowed.
+func (l IPAllowlist) Allows(addr string) bool {
+	if len(l) == 0 {
+		return true
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, ipNet := range l {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
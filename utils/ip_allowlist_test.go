@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAllowlist(t *testing.T) {
+	t.Run("empty allowlist allows all", func(t *testing.T) {
+		l, err := ParseIPAllowlist("")
+		require.NoError(t, err)
+		assert.True(t, l.Allows("203.0.113.1:1234"))
+	})
+
+	t.Run("CIDRs and addresses", func(t *testing.T) {
+		l, err := ParseIPAllowlist("10.0.0.0/8, 192.168.1.10,fd00::/8")
+		require.NoError(t, err)
+		assert.Len(t, l, 3)
+
+		assert.True(t, l.Allows("10.1.2.3:9090"))
+		assert.True(t, l.Allows("192.168.1.10"))
+		assert.False(t, l.Allows("192.168.1.11:9090"))
+		assert.True(t, l.Allows("[fd00::1]:9090"))
+		assert.False(t, l.Allows("203.0.113.1:9090"))
+		assert.True(t, l.Allows("127.0.0.1:9090"))
+		assert.True(t, l.Allows("[::1]:9090"))
+		assert.False(t, l.Allows("not-an-ip"))
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		for _, val := range []string{"10.0.0.0/33", "10.0.0", "localhost"} {
+			_, err := ParseIPAllowlist(val)
+			assert.Error(t, err, val)
+		}
+	})
+}